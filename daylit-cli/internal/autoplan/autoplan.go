@@ -0,0 +1,214 @@
+// Package autoplan implements the morning plan check shared by the notify
+// daemon and the TUI.
+package autoplan
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/hooks"
+	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/optimizer"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+// Enabled reports whether the morning plan setting is on
+func Enabled(settings models.Settings) bool {
+	return settings.MorningPlan == constants.MorningPlanPrompt ||
+		settings.MorningPlan == constants.MorningPlanHandsFree
+}
+
+// ValidMode reports whether mode is a known morning plan mode
+func ValidMode(mode string) bool {
+	switch mode {
+	case constants.MorningPlanOff, constants.MorningPlanPrompt, constants.MorningPlanHandsFree:
+		return true
+	}
+	return false
+}
+
+// Due reports whether the morning plan check should act at now: the setting
+// is on, the day has started, today isn't a vacation day and today has no
+// accepted plan
+func Due(store storage.Provider, settings models.Settings, now time.Time) bool {
+	if !Enabled(settings) {
+		return false
+	}
+
+	dayStart, err := utils.ParseTimeToMinutes(settings.DayStart)
+	if err != nil {
+		return false
+	}
+	if now.Hour()*60+now.Minute() < dayStart {
+		return false
+	}
+
+	today := now.Format(constants.DateFormat)
+	if vacations, err := store.GetVacations(today, today); err == nil && len(vacations) > 0 {
+		return false
+	}
+
+	plan, err := store.GetPlan(today)
+	if err != nil {
+		// No plan for today
+		return true
+	}
+	return plan.AcceptedAt == nil
+}
+
+// Generate schedules the tasks in the active context for date and saves the
+// plan, marking it accepted at now when accept is set
+func Generate(store storage.Provider, sched *scheduler.Scheduler, settings models.Settings, date string, accept bool, now time.Time) (models.DayPlan, error) {
+	tasks, err := store.GetAllTasks()
+	if err != nil {
+		return models.DayPlan{}, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	candidates, err := Candidates(store, tasks, settings, date)
+	if err != nil {
+		return models.DayPlan{}, err
+	}
+
+	opts := LockOptions(store, settings, date)
+	opts.Preferred = Preferences(store, settings, date)
+	opts.Aging = Aging(store, settings, candidates, date)
+	opts.Recovery = settings.Recovery()
+	plan, err := sched.GeneratePlanWithOptions(date, candidates, settings.DayStart, settings.DayEnd, opts)
+	if err != nil {
+		return models.DayPlan{}, err
+	}
+
+	// Revision 0 lets SavePlan assign the revision and keep accepted plans intact
+	plan.Revision = 0
+	if accept {
+		// Locked slots keep their status, such as done
+		for i := range plan.Slots {
+			if plan.Slots[i].Status == constants.SlotStatusPlanned {
+				plan.Slots[i].Status = constants.SlotStatusAccepted
+			}
+		}
+		acceptedAt := now.UTC().Format(time.RFC3339)
+		plan.AcceptedAt = &acceptedAt
+	}
+
+	if err := store.SavePlan(plan); err != nil {
+		return models.DayPlan{}, fmt.Errorf("failed to save plan: %w", err)
+	}
+
+	// Reload to pick up the assigned revision
+	saved, err := store.GetPlan(date)
+	if err != nil {
+		saved = plan
+	}
+	if accept {
+		hooks.Fire(hooks.PlanAccepted, hooks.Plan(store, saved))
+	}
+	return saved, nil
+}
+
+// LockOptions returns plan options that keep the locked part of date's
+// current plan, so regenerating the day leaves it in place. Without a locked
+// plan the options plan the whole day.
+func LockOptions(store storage.Provider, settings models.Settings, date string) scheduler.PlanOptions {
+	plan, err := store.GetPlan(date)
+	if err != nil || plan.LockedUntil == "" {
+		return scheduler.PlanOptions{}
+	}
+	window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+	if err != nil {
+		return scheduler.PlanOptions{}
+	}
+	return scheduler.PlanOptions{LockedUntil: plan.LockedUntil, LockedSlots: plan.LockedSlots(window)}
+}
+
+// PlacementLookbackDays is how many days of plans adaptive placement learns
+// from
+const PlacementLookbackDays = 60
+
+// Preferences returns the time of day each task has been done most reliably
+// in the plans before date, for the scheduler to place it there. It returns
+// nil when adaptive placement is off.
+func Preferences(store storage.Provider, settings models.Settings, date string) map[string]scheduler.Preference {
+	if !settings.AdaptivePlacement {
+		return nil
+	}
+	day, err := time.Parse(constants.DateFormat, date)
+	if err != nil {
+		return nil
+	}
+	prefs, err := optimizer.NewFeedbackAnalyzer(store).AnalyzePlacement(
+		day.AddDate(0, 0, -PlacementLookbackDays).Format(constants.DateFormat),
+		day.AddDate(0, 0, -1).Format(constants.DateFormat),
+	)
+	if err != nil {
+		// Plans still work without the learned times
+		logger.Warn("Failed to learn task placement", "error", err)
+		return nil
+	}
+
+	preferred := make(map[string]scheduler.Preference, len(prefs))
+	for _, pref := range prefs {
+		preferred[pref.TaskID] = scheduler.Preference{Start: pref.Start, End: pref.End}
+	}
+	return preferred
+}
+
+// Aging returns how far each of tasks has its priority raised on date for
+// being left out of the plans before it. It returns nil when priority aging
+// is off.
+func Aging(store storage.Provider, settings models.Settings, tasks []models.Task, date string) map[string]scheduler.Aging {
+	if settings.PriorityAgingDays <= 0 {
+		return nil
+	}
+	day, err := time.Parse(constants.DateFormat, date)
+	if err != nil {
+		return nil
+	}
+	plans, err := store.GetPlansRange(
+		day.AddDate(0, 0, -scheduler.AgingLookbackDays).Format(constants.DateFormat),
+		day.AddDate(0, 0, -1).Format(constants.DateFormat),
+	)
+	if err != nil {
+		// Plans still work without the raised priorities
+		logger.Warn("Failed to get plans for priority aging", "error", err)
+		return nil
+	}
+	return scheduler.AgeTasks(tasks, plans, settings.PriorityAgingDays)
+}
+
+// Candidates returns the tasks to schedule on date: those in the active
+// context, leaving out the recurring ones on vacation days, all but one
+// member of each task pool and shared tasks that are another user's turn
+func Candidates(store storage.Provider, tasks []models.Task, settings models.Settings, date string) ([]models.Task, error) {
+	day, err := time.Parse(constants.DateFormat, date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format: %w", err)
+	}
+	vacations, err := store.GetVacations(date, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vacations: %w", err)
+	}
+	pools, err := store.GetAllPools()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task pools: %w", err)
+	}
+	candidates := models.TasksOffVacation(models.TasksInContext(tasks, settings.ActiveContext), vacations, date)
+	return SharedTurns(store, scheduler.ChoosePoolMembers(candidates, pools, day))
+}
+
+// SharedTurns leaves out the shared household tasks that are another user's
+// turn. Providers without users keep every task.
+func SharedTurns(store storage.Provider, tasks []models.Task) ([]models.Task, error) {
+	household, ok := storage.Unwrap(store).(storage.Household)
+	if !ok {
+		return tasks, nil
+	}
+	shared, err := household.GetSharedTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shared tasks: %w", err)
+	}
+	return scheduler.ChooseSharedTurns(tasks, shared, household.CurrentUser()), nil
+}
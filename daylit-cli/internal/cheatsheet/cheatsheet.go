@@ -0,0 +1,172 @@
+// Package cheatsheet builds a quick reference of the CLI from the kong
+// command tree, so it always matches the commands and flags that exist
+package cheatsheet
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// Option is a flag or positional argument of a command
+type Option struct {
+	Usage   string `json:"usage"`             // e.g. "-o, --output=OUTPUT" or "<date>"
+	Help    string `json:"help,omitempty"`    // Help text from the command definition
+	Default string `json:"default,omitempty"` // Value used when the option is not given
+}
+
+// Entry is one runnable command of the cheat sheet
+type Entry struct {
+	Path    string   `json:"path"`    // Command words after the app name, e.g. "task add"
+	Help    string   `json:"help"`    // One-line description
+	Example string   `json:"example"` // Shortest valid invocation, e.g. "daylit task add <name> --duration=DURATION"
+	Args    []Option `json:"args,omitempty"`
+	Flags   []Option `json:"flags,omitempty"`
+}
+
+// Build returns an entry for every visible runnable command under root, in
+// the order the commands are declared
+func Build(root *kong.Node) []Entry {
+	var entries []Entry
+	var walk func(n *kong.Node)
+	walk = func(n *kong.Node) {
+		for _, child := range n.Children {
+			if child.Hidden {
+				continue
+			}
+			if child.Leaf() {
+				entries = append(entries, entry(root.Name, child))
+				continue
+			}
+			walk(child)
+		}
+	}
+	walk(root)
+	return entries
+}
+
+func entry(app string, n *kong.Node) Entry {
+	e := Entry{Path: commandPath(n), Help: n.Help}
+	example := []string{app, e.Path}
+
+	for _, arg := range n.Positional {
+		usage := "<" + arg.Name + ">"
+		if arg.Required {
+			example = append(example, usage)
+		}
+		e.Args = append(e.Args, Option{Usage: usage, Help: arg.Help, Default: defaultOf(arg)})
+	}
+
+	for _, f := range n.Flags {
+		if f.Hidden {
+			continue
+		}
+		usage := "--" + f.Name
+		if !f.IsBool() && !f.IsCounter() {
+			usage += "=" + placeholder(f)
+		}
+		if f.Required {
+			example = append(example, usage)
+		}
+		if f.Short != 0 {
+			usage = fmt.Sprintf("-%c, %s", f.Short, usage)
+		}
+		help := f.Help
+		if f.Enum != "" {
+			help = strings.TrimSpace(fmt.Sprintf("%s (one of: %s)", help, f.Enum))
+		}
+		e.Flags = append(e.Flags, Option{Usage: usage, Help: help, Default: defaultOf(f.Value)})
+	}
+
+	e.Example = strings.Join(example, " ")
+	return e
+}
+
+// commandPath is the node's path without the aliases kong adds to it
+func commandPath(n *kong.Node) string {
+	var words []string
+	for ; n != nil && n.Parent != nil; n = n.Parent {
+		name := n.Name
+		if n.Type == kong.ArgumentNode {
+			name = "<" + name + ">"
+		}
+		words = append([]string{name}, words...)
+	}
+	return strings.Join(words, " ")
+}
+
+func placeholder(f *kong.Flag) string {
+	if f.PlaceHolder != "" {
+		return f.PlaceHolder
+	}
+	return strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+}
+
+func defaultOf(v *kong.Value) string {
+	if !v.HasDefault {
+		return ""
+	}
+	return v.Default
+}
+
+// Filter returns the entries whose command path starts with the given
+// words, e.g. "task" or "plan generate". No words keeps every entry.
+func Filter(entries []Entry, words ...string) []Entry {
+	if len(words) == 0 {
+		return entries
+	}
+	var out []Entry
+	for _, e := range entries {
+		if hasPrefix(e.Path, words) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func hasPrefix(path string, words []string) bool {
+	fields := strings.Fields(path)
+	if len(words) > len(fields) {
+		return false
+	}
+	for i, w := range words {
+		if fields[i] != w {
+			return false
+		}
+	}
+	return true
+}
+
+// Write prints the entries as a cheat sheet. With options, each entry is
+// followed by its arguments and flags with their defaults.
+func Write(w io.Writer, entries []Entry, options bool) {
+	width := 0
+	for _, e := range entries {
+		width = max(width, len(e.Example))
+	}
+	for i, e := range entries {
+		if !options {
+			fmt.Fprintf(w, "%-*s  %s\n", width, e.Example, e.Help)
+			continue
+		}
+
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s\n  %s\n", e.Example, e.Help)
+		opts := append(append([]Option{}, e.Args...), e.Flags...)
+		optWidth := 0
+		for _, o := range opts {
+			optWidth = max(optWidth, len(o.Usage))
+		}
+		for _, o := range opts {
+			help := o.Help
+			if o.Default != "" {
+				help = strings.TrimSpace(fmt.Sprintf("%s (default: %s)", help, o.Default))
+			}
+			fmt.Fprintf(w, "    %-*s  %s\n", optWidth, o.Usage, help)
+		}
+	}
+}
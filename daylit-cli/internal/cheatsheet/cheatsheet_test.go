@@ -0,0 +1,111 @@
+package cheatsheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kong"
+)
+
+type testCLI struct {
+	Debug bool `help:"Enable debug logging."`
+
+	Task struct {
+		Add struct {
+			Name     string `arg:"" help:"Task name."`
+			Duration int    `short:"d" required:"" help:"Duration in minutes."`
+			Priority int    `help:"Priority." default:"3"`
+			Leisure  bool   `help:"Count as free time."`
+			Secret   string `hidden:""`
+		} `cmd:"" help:"Add a task."`
+		List struct {
+			Date string `arg:"" optional:"" help:"Day to list."`
+		} `cmd:"" help:"List tasks."`
+	} `cmd:"" help:"Manage tasks."`
+	Now struct{} `cmd:"" help:"Show current task."`
+	Old struct{} `cmd:"" hidden:"" help:"Removed."`
+}
+
+func build(t *testing.T) []Entry {
+	t.Helper()
+	parser, err := kong.New(&testCLI{}, kong.Name("daylit"))
+	if err != nil {
+		t.Fatalf("failed to build parser: %v", err)
+	}
+	return Build(parser.Model.Node)
+}
+
+func TestBuild(t *testing.T) {
+	entries := build(t)
+
+	var paths []string
+	for _, e := range entries {
+		paths = append(paths, e.Path)
+	}
+	if got := strings.Join(paths, ","); got != "task add,task list,now" {
+		t.Fatalf("expected visible leaf commands in order, got %s", got)
+	}
+
+	add := entries[0]
+	if add.Example != "daylit task add <name> --duration=DURATION" {
+		t.Errorf("unexpected example: %q", add.Example)
+	}
+	if len(add.Args) != 1 || add.Args[0].Usage != "<name>" {
+		t.Errorf("expected the name argument, got %+v", add.Args)
+	}
+
+	flags := make(map[string]Option)
+	for _, f := range add.Flags {
+		flags[f.Usage] = f
+	}
+	if _, ok := flags["-d, --duration=DURATION"]; !ok {
+		t.Errorf("expected the duration flag with its short name, got %+v", add.Flags)
+	}
+	if f, ok := flags["--priority=PRIORITY"]; !ok || f.Default != "3" {
+		t.Errorf("expected the priority flag with default 3, got %+v", add.Flags)
+	}
+	if _, ok := flags["--leisure"]; !ok {
+		t.Errorf("expected the boolean leisure flag without a value, got %+v", add.Flags)
+	}
+	if len(add.Flags) != 3 {
+		t.Errorf("expected the hidden flag to be left out, got %+v", add.Flags)
+	}
+
+	if entries[1].Example != "daylit task list" {
+		t.Errorf("expected optional arguments left out of the example, got %q", entries[1].Example)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	entries := build(t)
+
+	if got := Filter(entries, "task"); len(got) != 2 {
+		t.Errorf("expected 2 task commands, got %d", len(got))
+	}
+	if got := Filter(entries, "task", "add"); len(got) != 1 || got[0].Path != "task add" {
+		t.Errorf("expected only task add, got %+v", got)
+	}
+	if got := Filter(entries, "ta"); len(got) != 0 {
+		t.Errorf("expected whole words to be matched, got %+v", got)
+	}
+	if got := Filter(entries); len(got) != len(entries) {
+		t.Errorf("expected no words to keep every entry, got %d", len(got))
+	}
+}
+
+func TestWrite(t *testing.T) {
+	entries := Filter(build(t), "task", "add")
+
+	var compact bytes.Buffer
+	Write(&compact, entries, false)
+	if got := compact.String(); got != "daylit task add <name> --duration=DURATION  Add a task.\n" {
+		t.Errorf("unexpected compact output: %q", got)
+	}
+
+	var detail bytes.Buffer
+	Write(&detail, entries, true)
+	if !strings.Contains(detail.String(), "Priority. (default: 3)") {
+		t.Errorf("expected defaults in the detailed output, got:\n%s", detail.String())
+	}
+}
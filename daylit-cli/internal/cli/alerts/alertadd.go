@@ -13,16 +13,59 @@ import (
 )
 
 type AlertAddCmd struct {
-	Message    string `arg:"" help:"Alert message."`
-	Time       string `help:"Time for alert (HH:MM)." required:""`
+	Message    string `arg:"" optional:"" help:"Alert message. Optional for leave-by alerts."`
+	Time       string `help:"Time for alert (HH:MM). Required unless --cron is given."`
 	Date       string `help:"Date for one-time alert (YYYY-MM-DD)."`
-	Recurrence string `help:"Recurrence type (daily|weekly|n_days). Required if --date not set."`
+	Recurrence string `help:"Recurrence type (daily|weekly|n_days|monthly_date|yearly). Required if --date or --cron not set."`
 	Interval   int    `help:"Interval for n_days recurrence." default:"1"`
 	Weekdays   string `help:"Comma-separated weekdays for weekly recurrence (e.g., mon,wed,fri)."`
+	MonthDay   int    `help:"Day of month (1-31) for monthly_date or yearly recurrence."`
+	Month      int    `help:"Month (1-12) for yearly recurrence."`
+	Cron       string `help:"Cron expression giving the alert's times, e.g. '0 9 * * 1-5' (minute hour day month weekday)."`
+	LeaveFor   string `help:"Appointment task (ID or name) to leave for; the alert goes off --travel minutes before it starts." name:"leave-for"`
+	Travel     int    `help:"Travel time in minutes for --leave-for."`
+	Urgency    string `help:"Notification urgency (low|normal|critical)."`
+	Sound      string `help:"Notification sound name; empty uses the default."`
 }
 
+// defaultLeaveByMessage is the message of leave-by alerts added without one
+const defaultLeaveByMessage = "Time to go"
+
 func (c *AlertAddCmd) Validate() error {
+	if err := models.ValidateUrgency(c.Urgency); err != nil {
+		return err
+	}
+
+	// Leave-by alerts take their times from the appointment
+	if c.LeaveFor != "" {
+		if c.Time != "" || c.Date != "" || c.Recurrence != "" || c.Cron != "" {
+			return fmt.Errorf("--leave-for can't be combined with --time, --date, --recurrence or --cron")
+		}
+		if c.Travel < 0 {
+			return fmt.Errorf("--travel can't be negative")
+		}
+		return nil
+	}
+	if c.Message == "" {
+		return fmt.Errorf("alert message is required")
+	}
+
+	// Cron alerts take their times and days from the expression
+	if c.Cron != "" {
+		if c.Time != "" || c.Date != "" || (c.Recurrence != "" && c.Recurrence != string(constants.RecurrenceCron)) {
+			return fmt.Errorf("--cron can't be combined with --time, --date or --recurrence")
+		}
+		_, err := models.ParseCron(c.Cron)
+		return err
+	}
+	if c.Recurrence == string(constants.RecurrenceCron) {
+		return fmt.Errorf("cron recurrence needs a --cron expression")
+	}
+
 	// Validate time format
+	if c.Time == "" {
+		return fmt.Errorf("--time is required")
+	}
 	if _, err := utils.ParseTime(c.Time); err != nil {
 		return fmt.Errorf("invalid time format (expected HH:MM): %w", err)
 	}
@@ -46,12 +89,14 @@ func (c *AlertAddCmd) Validate() error {
 
 	// Validate recurrence type
 	validRecurrence := map[string]bool{
-		"daily":  true,
-		"weekly": true,
-		"n_days": true,
+		"daily":        true,
+		"weekly":       true,
+		"n_days":       true,
+		"monthly_date": true,
+		"yearly":       true,
 	}
 	if !validRecurrence[c.Recurrence] {
-		return fmt.Errorf("invalid recurrence type: %s (must be daily, weekly, or n_days)", c.Recurrence)
+		return fmt.Errorf("invalid recurrence type: %s (must be daily, weekly, n_days, monthly_date, or yearly)", c.Recurrence)
 	}
 
 	// Validate weekly recurrence has weekdays
@@ -64,6 +109,14 @@ func (c *AlertAddCmd) Validate() error {
 		return fmt.Errorf("interval must be at least 1 for n_days recurrence")
 	}
 
+	// Monthly and yearly alerts need the day they fall on
+	if (c.Recurrence == "monthly_date" || c.Recurrence == "yearly") && c.MonthDay == 0 {
+		return fmt.Errorf("--month-day must be specified for %s recurrence", c.Recurrence)
+	}
+	if c.Recurrence == "yearly" && c.Month == 0 {
+		return fmt.Errorf("--month must be specified for yearly recurrence")
+	}
+
 	return nil
 }
 
@@ -81,14 +134,44 @@ func (c *AlertAddCmd) Run(ctx *cli.Context) error {
 		Message:   c.Message,
 		Time:      c.Time,
 		Date:      c.Date,
+		Urgency:   c.Urgency,
+		Sound:     c.Sound,
 		Active:    true,
-		CreatedAt: time.Now(),
+		CreatedAt: ctx.Now(),
 	}
 
 	// Set recurrence if not one-time
-	if c.Date == "" {
+	if c.LeaveFor != "" {
+		task, err := ctx.ResolveTask(c.LeaveFor, cli.LiveTasks)
+		if err != nil {
+			return err
+		}
+		if task.Kind != constants.TaskKindAppointment || task.FixedStart == "" {
+			return fmt.Errorf("%s isn't an appointment with a fixed start (see 'daylit task add --help')", task.Name)
+		}
+		alert.TaskID = task.ID
+		alert.TravelMin = c.Travel
+		if alert.Message == "" {
+			alert.Message = defaultLeaveByMessage
+		}
+		leave, err := alert.LeaveBy(task)
+		if err != nil {
+			return err
+		}
+		if err := ctx.Store.AddAlert(alert); err != nil {
+			return fmt.Errorf("failed to add alert: %w", err)
+		}
+		fmt.Printf("✓ Alert added: %s — leave by %02d:%02d for %s at %s (follows the appointment's start)\n",
+			alert.Message, leave/60, leave%60, task.Name, task.FixedStart)
+		return nil
+	} else if c.Cron != "" {
+		alert.Recurrence.Type = constants.RecurrenceCron
+		alert.Cron = c.Cron
+	} else if c.Date == "" {
 		alert.Recurrence.Type = constants.RecurrenceType(c.Recurrence)
 		alert.Recurrence.IntervalDays = c.Interval
+		alert.Recurrence.MonthDay = c.MonthDay
+		alert.Recurrence.Month = c.Month
 
 		// Parse weekdays for weekly recurrence
 		if c.Recurrence == "weekly" {
@@ -104,7 +187,10 @@ func (c *AlertAddCmd) Run(ctx *cli.Context) error {
 		return fmt.Errorf("failed to add alert: %w", err)
 	}
 
-	fmt.Printf("✓ Alert added: %s at %s", alert.Message, alert.Time)
+	fmt.Printf("✓ Alert added: %s", alert.Message)
+	if !alert.IsCron() {
+		fmt.Printf(" at %s", alert.Time)
+	}
 	if alert.Date != "" {
 		fmt.Printf(" on %s", alert.Date)
 	} else {
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
 )
 
 type AlertListCmd struct{}
@@ -24,8 +25,8 @@ func (c *AlertListCmd) Run(ctx *cli.Context) error {
 		return nil
 	}
 
-	fmt.Printf("%-36s %-30s %-8s %-20s %-8s\n", "ID", "Message", "Time", "Recurrence", "Active")
-	fmt.Println(strings.Repeat("-", 110))
+	fmt.Printf("%-36s %-30s %-8s %-20s %-8s %-8s\n", "ID", "Message", "Time", "Recurrence", "Active", "Urgency")
+	fmt.Println(strings.Repeat("-", 119))
 
 	for _, alert := range alerts {
 		message := alert.Message
@@ -38,13 +39,28 @@ func (c *AlertListCmd) Run(ctx *cli.Context) error {
 			recurrence = recurrence[:15] + "..."
 		}
 
+		// Leave-by alerts show when to leave for the appointment today
+		timeStr := alert.FormatTime()
+		if alert.IsLeaveBy() {
+			if task, err := ctx.Store.GetTask(alert.TaskID); err == nil {
+				if leave, err := alert.LeaveBy(task); err == nil {
+					timeStr = fmt.Sprintf("%02d:%02d", leave/60, leave%60)
+				}
+			}
+		}
+
 		activeStr := "Yes"
 		if !alert.Active {
 			activeStr = "No"
 		}
 
-		fmt.Printf("%-36s %-30s %-8s %-20s %-8s\n",
-			alert.ID, message, alert.Time, recurrence, activeStr)
+		urgency := alert.Urgency
+		if urgency == "" {
+			urgency = constants.NotificationUrgencyNormal
+		}
+
+		fmt.Printf("%-36s %-30s %-8s %-20s %-8s %-8s\n",
+			alert.ID, message, timeStr, recurrence, activeStr, urgency)
 	}
 
 	return nil
@@ -1,11 +1,9 @@
 package backups
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/backup"
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
@@ -96,16 +94,11 @@ func (c *BackupRestoreCmd) Run(ctx *cli.Context) error {
 	fmt.Println("             Concurrent access during restore can cause data corruption.")
 	fmt.Println("A backup of your current database will be created before restoring.")
 	fmt.Printf("\nRestore from: %s\n", backupPath)
-	fmt.Print("Continue? [y/N]: ")
-
-	reader := bufio.NewReader(os.Stdin)
-	response, err := reader.ReadString('\n')
+	ok, err := ctx.Confirm("Continue?")
 	if err != nil {
 		return err
 	}
-	response = strings.TrimSpace(strings.ToLower(response))
-
-	if response != "y" && response != "yes" {
+	if !ok {
 		fmt.Println("Restore cancelled.")
 		return nil
 	}
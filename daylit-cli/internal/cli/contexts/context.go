@@ -0,0 +1,98 @@
+package contexts
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+type ContextCmd struct {
+	Show  ContextShowCmd  `cmd:"" help:"Show the active context and the contexts used by tasks." default:"1"`
+	Set   ContextSetCmd   `cmd:"" help:"Set the active context used for plan generation."`
+	Clear ContextClearCmd `cmd:"" help:"Clear the active context so tasks from every context are planned."`
+}
+
+type ContextShowCmd struct{}
+
+func (c *ContextShowCmd) Run(ctx *cli.Context) error {
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	if settings.ActiveContext == "" {
+		fmt.Println("Active context: (none, all tasks are planned)")
+	} else {
+		fmt.Printf("Active context: %s\n", settings.ActiveContext)
+	}
+
+	counts := make(map[string]int)
+	for _, t := range tasks {
+		if t.Context != "" {
+			counts[t.Context]++
+		}
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("\nContexts:")
+	for _, name := range names {
+		marker := " "
+		if name == settings.ActiveContext {
+			marker = "*"
+		}
+		fmt.Printf("  %s %s (%d task(s))\n", marker, name, counts[name])
+	}
+	return nil
+}
+
+type ContextSetCmd struct {
+	Name string `arg:"" help:"Context name, e.g. 'home' or 'office'."`
+}
+
+func (c *ContextSetCmd) Run(ctx *cli.Context) error {
+	name := models.NormalizeContext(c.Name)
+	if name == "" {
+		return fmt.Errorf("context name cannot be empty")
+	}
+	if err := saveActiveContext(ctx, name); err != nil {
+		return err
+	}
+	fmt.Printf("Active context: %s\n", name)
+	return nil
+}
+
+type ContextClearCmd struct{}
+
+func (c *ContextClearCmd) Run(ctx *cli.Context) error {
+	if err := saveActiveContext(ctx, ""); err != nil {
+		return err
+	}
+	fmt.Println("Active context cleared")
+	return nil
+}
+
+func saveActiveContext(ctx *cli.Context, name string) error {
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+	settings.ActiveContext = name
+	if err := ctx.Store.SaveSettings(settings); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package contexts
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+)
+
+func setupTestDB(t *testing.T) (*cli.Context, func()) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "test.db")
+
+	store := sqlite.NewStore(dbPath)
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+
+	ctx := &cli.Context{
+		Store:     store,
+		Scheduler: scheduler.New(),
+	}
+
+	cleanup := func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("failed to close store: %v", err)
+		}
+	}
+
+	return ctx, cleanup
+}
+
+func TestContextSetAndClear(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := (&ContextSetCmd{Name: " Office "}).Run(ctx); err != nil {
+		t.Fatalf("context set failed: %v", err)
+	}
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if settings.ActiveContext != "office" {
+		t.Errorf("active context = %q, want %q", settings.ActiveContext, "office")
+	}
+
+	if err := (&ContextShowCmd{}).Run(ctx); err != nil {
+		t.Errorf("context show failed: %v", err)
+	}
+
+	if err := (&ContextClearCmd{}).Run(ctx); err != nil {
+		t.Fatalf("context clear failed: %v", err)
+	}
+	settings, err = ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if settings.ActiveContext != "" {
+		t.Errorf("active context = %q after clear, want empty", settings.ActiveContext)
+	}
+
+	if err := (&ContextSetCmd{Name: "  "}).Run(ctx); err == nil {
+		t.Error("expected error for an empty context name")
+	}
+}
+
+func TestPlanSkipsTasksFromOtherContexts(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	tasks := []models.Task{
+		{ID: "plants", Name: "Water the plants", Kind: constants.TaskKindFlexible, DurationMin: 15, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 2, Active: true, Context: "home"},
+		{ID: "standup", Name: "Standup notes", Kind: constants.TaskKindFlexible, DurationMin: 15, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 2, Active: true, Context: "office"},
+		{ID: "read", Name: "Read", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 3, Active: true},
+	}
+	for _, task := range tasks {
+		if err := ctx.Store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+	}
+
+	if err := (&ContextSetCmd{Name: "office"}).Run(ctx); err != nil {
+		t.Fatalf("context set failed: %v", err)
+	}
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+
+	stored, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		t.Fatalf("failed to get tasks: %v", err)
+	}
+	plan, err := ctx.Scheduler.GeneratePlan("2024-05-06", models.TasksInContext(stored, settings.ActiveContext), "08:00", "18:00")
+	if err != nil {
+		t.Fatalf("GeneratePlan failed: %v", err)
+	}
+
+	scheduled := map[string]bool{}
+	for _, slot := range plan.Slots {
+		scheduled[slot.TaskID] = true
+	}
+	if scheduled["plants"] {
+		t.Error("home task was scheduled on an office day")
+	}
+	if !scheduled["standup"] || !scheduled["read"] {
+		t.Errorf("expected office and context-free tasks to be scheduled, got %v", scheduled)
+	}
+}
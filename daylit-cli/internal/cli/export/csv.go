@@ -0,0 +1,81 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// csvHeader lists the exported columns in order
+var csvHeader = []string{"date", "start", "end", "task", "status", "rating", "note"}
+
+type ExportCSVCmd struct {
+	From   string `help:"First date to export (YYYY-MM-DD or 'today'). Defaults to the earliest plan."`
+	To     string `help:"Last date to export (YYYY-MM-DD or 'today')." default:"today"`
+	Output string `short:"o" help:"File to write instead of stdout." type:"path"`
+}
+
+func (c *ExportCSVCmd) Run(ctx *cli.Context) error {
+	// Dates are YYYY-MM-DD strings, so the zero date sorts before every plan
+	from := "0000-01-01"
+	if c.From != "" {
+		var err error
+		if from, err = parseDate(c.From, ctx.Now()); err != nil {
+			return err
+		}
+	}
+	to, err := parseDate(c.To, ctx.Now())
+	if err != nil {
+		return err
+	}
+	if from > to {
+		return fmt.Errorf("--from (%s) is after --to (%s)", from, to)
+	}
+
+	if c.Output == "" {
+		_, err := writeCSV(ctx, os.Stdout, from, to)
+		return err
+	}
+
+	f, err := os.Create(c.Output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", c.Output, err)
+	}
+	count, err := writeCSV(ctx, f, from, to)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close %s: %w", c.Output, closeErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d slot(s) to %s\n", count, c.Output)
+	return nil
+}
+
+// writeCSV streams the slots in the date range to w and returns the number of rows written
+func writeCSV(ctx *cli.Context, w io.Writer, from, to string) (int, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return 0, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	count := 0
+	err := ctx.Store.EachSlot(from, to, func(r models.SlotRecord) error {
+		count++
+		return cw.Write([]string{r.Date, r.Start, r.End, r.TaskName, string(r.Status), string(r.Rating), r.Note})
+	})
+	if err != nil {
+		return count, fmt.Errorf("failed to export slots: %w", err)
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return count, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return count, nil
+}
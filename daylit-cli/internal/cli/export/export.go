@@ -0,0 +1,24 @@
+package export
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+type ExportCmd struct {
+	CSV      ExportCSVCmd      `cmd:"" name:"csv" help:"Export slots and feedback as CSV."`
+	Markdown ExportMarkdownCmd `cmd:"" name:"md" help:"Export a day's plan, feedback, habits, and OT as a Markdown note."`
+}
+
+// parseDate accepts YYYY-MM-DD or 'today' and returns the date in YYYY-MM-DD format
+func parseDate(value string, now time.Time) (string, error) {
+	if value == "today" {
+		return now.Format(constants.DateFormat), nil
+	}
+	if _, err := time.Parse(constants.DateFormat, value); err != nil {
+		return "", fmt.Errorf("invalid date %q, use YYYY-MM-DD or 'today'", value)
+	}
+	return value, nil
+}
@@ -0,0 +1,233 @@
+package export
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+)
+
+func setupTestDB(t *testing.T) (*cli.Context, func()) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "test.db")
+
+	store := sqlite.NewStore(dbPath)
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+
+	ctx := &cli.Context{
+		Store:     store,
+		Scheduler: scheduler.New(),
+	}
+
+	cleanup := func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("failed to close store: %v", err)
+		}
+	}
+
+	return ctx, cleanup
+}
+
+func TestExportCSVCmd(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	task := models.Task{
+		ID:          "task-1",
+		Name:        "Review, then plan",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+		Priority:    3,
+		Active:      true,
+	}
+	if err := ctx.Store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	for _, plan := range []models.DayPlan{
+		{Date: "2024-05-01", Slots: []models.Slot{
+			{Start: "09:00", End: "09:30", TaskID: task.ID, Status: constants.SlotStatusDone, Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack, Note: "said \"done\"\nearly"}},
+		}},
+		{Date: "2024-06-01", Slots: []models.Slot{
+			{Start: "09:00", End: "09:30", TaskID: task.ID, Status: constants.SlotStatusPlanned},
+		}},
+	} {
+		if err := ctx.Store.SavePlan(plan); err != nil {
+			t.Fatalf("failed to save plan: %v", err)
+		}
+	}
+
+	out := filepath.Join(t.TempDir(), "slots.csv")
+	cmd := &ExportCSVCmd{From: "2024-05-01", To: "2024-05-31", Output: out}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatalf("failed to open export: %v", err)
+	}
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected header and 1 row, got %d rows: %v", len(rows), rows)
+	}
+	want := []string{"2024-05-01", "09:00", "09:30", task.Name, "done", "on_track", "said \"done\"\nearly"}
+	for i, v := range want {
+		if rows[1][i] != v {
+			t.Errorf("column %s = %q, want %q", rows[0][i], rows[1][i], v)
+		}
+	}
+}
+
+func TestExportCSVCmd_InvalidRange(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	for _, cmd := range []*ExportCSVCmd{
+		{From: "2024-06-01", To: "2024-05-01"},
+		{From: "yesterday", To: "today"},
+		{To: "2024-13-01"},
+	} {
+		if err := cmd.Run(ctx); err == nil {
+			t.Errorf("expected error for %+v", cmd)
+		}
+	}
+}
+
+func TestExportMarkdownCmd(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	task := models.Task{
+		ID:          "task-1",
+		Name:        "Write",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 60,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+		Priority:    2,
+		Active:      true,
+	}
+	if err := ctx.Store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	actualStart, actualEnd := "09:05", "10:20"
+	plan := models.DayPlan{Date: "2024-05-01", Slots: []models.Slot{
+		{Start: "09:00", End: "10:00", TaskID: task.ID, Status: constants.SlotStatusDone, Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack, Note: "two pages"}, ActualStart: &actualStart, ActualEnd: &actualEnd},
+		{Start: "14:00", End: "15:00", TaskID: task.ID, Status: constants.SlotStatusPlanned},
+	}, Note: "Power cut after lunch"}
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	for _, name := range []string{"Stretch", "Read"} {
+		habit := models.Habit{ID: uuid.New().String(), Name: name, CreatedAt: time.Now()}
+		if err := ctx.Store.AddHabit(habit); err != nil {
+			t.Fatalf("failed to add habit: %v", err)
+		}
+		if name == "Stretch" {
+			entry := models.HabitEntry{ID: uuid.New().String(), HabitID: habit.ID, Day: plan.Date, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+			if err := ctx.Store.AddHabitEntry(entry); err != nil {
+				t.Fatalf("failed to add habit entry: %v", err)
+			}
+		}
+	}
+
+	ot := models.OTEntry{ID: uuid.New().String(), Day: plan.Date, Title: "Ship draft", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := ctx.Store.AddOTEntry(ot); err != nil {
+		t.Fatalf("failed to add OT entry: %v", err)
+	}
+
+	out := filepath.Join(t.TempDir(), "note.md")
+	cmd := &ExportMarkdownCmd{Date: plan.Date, Output: out}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+	want := `# 2024-05-01 (Wednesday)
+
+## One Thing
+
+**Ship draft**
+
+## Plan
+
+- [x] 09:00-10:00 Write (on_track): two pages
+- [ ] 14:00-15:00 Write
+
+Total drift: 20m across 1 tracked slot(s)
+
+## Notes
+
+Power cut after lunch
+
+## Habits
+
+- [x] Stretch
+- [ ] Read
+`
+	if string(got) != want {
+		t.Errorf("unexpected note:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExportMarkdownCmd_TemplateAndExportDir(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	dir := filepath.Join(t.TempDir(), "notes")
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	settings.MarkdownExportDir = dir
+	if err := ctx.Store.SaveSettings(settings); err != nil {
+		t.Fatalf("failed to save settings: %v", err)
+	}
+
+	tmplPath := filepath.Join(t.TempDir(), "note.tmpl")
+	if err := os.WriteFile(tmplPath, []byte("{{.Weekday}} {{len .Slots}} {{.HasPlan}}"), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	cmd := &ExportMarkdownCmd{Date: "2024-05-04", Template: tmplPath}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "2024-05-04.md"))
+	if err != nil {
+		t.Fatalf("note was not written to the export directory: %v", err)
+	}
+	if string(got) != "Saturday 0 false" {
+		t.Errorf("rendered template = %q", got)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.tmpl")
+	if err := os.WriteFile(bad, []byte("{{.Missing"), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	if err := (&ExportMarkdownCmd{Date: "today", Template: bad, Stdout: true}).Run(ctx); err == nil {
+		t.Error("expected error for invalid template")
+	}
+}
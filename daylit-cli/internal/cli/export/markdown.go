@@ -0,0 +1,240 @@
+package export
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// defaultNoteTemplate renders a daily note with the OT, plan, and habits
+const defaultNoteTemplate = `# {{.Date}} ({{.Weekday}})
+{{with .OT}}
+## One Thing
+
+**{{.Title}}**
+{{- if .Note}}
+
+{{.Note}}
+{{- end}}
+{{end}}
+## Plan
+{{if .Slots}}
+{{range .Slots}}- [{{if .Done}}x{{else}} {{end}}] {{.Start}}-{{.End}} {{.Task}}{{if .Rating}} ({{.Rating}}){{end}}{{if .Note}}: {{.Note}}{{end}}
+{{end}}{{if .TrackedSlots}}
+Total drift: {{.DriftMinutes}}m across {{.TrackedSlots}} tracked slot(s)
+{{end}}{{else}}
+No plan for this day.
+{{end}}{{with .Notes}}
+## Notes
+
+{{.}}
+{{end}}
+## Habits
+{{if .Habits}}
+{{range .Habits}}- [{{if .Done}}x{{else}} {{end}}] {{.Name}}{{if .Note}}: {{.Note}}{{end}}
+{{end}}{{else}}
+No habits tracked.
+{{end}}`
+
+// DailyNote is the data passed to Markdown note templates
+type DailyNote struct {
+	Date     string          // YYYY-MM-DD
+	Weekday  string          // e.g. "Monday"
+	HasPlan  bool            // Whether a plan exists for the day
+	Accepted bool            // Whether the latest plan revision was accepted
+	Slots    []NoteSlot      // Slots of the latest plan revision, by start time
+	Notes    string          // Free-text notes on the latest plan revision
+	Habits   []NoteHabit     // Active habits and whether they were done
+	OT       *models.OTEntry // The day's OT entry, or nil
+
+	TrackedSlots int // Slots with a tracked start or stop time
+	DriftMinutes int // Total minutes the tracked slots drifted from the plan
+}
+
+// NoteSlot is a plan slot in a daily note
+type NoteSlot struct {
+	Start  string // HH:MM
+	End    string // HH:MM
+	Task   string
+	Status string
+	Done   bool
+	Rating string
+	Note   string
+}
+
+// NoteHabit is a habit in a daily note
+type NoteHabit struct {
+	Name string
+	Done bool
+	Note string
+}
+
+type ExportMarkdownCmd struct {
+	Date     string `arg:"" help:"Date to export (YYYY-MM-DD or 'today')." default:"today"`
+	Template string `help:"Go text/template file to render instead of the built-in note." type:"existingfile"`
+	Output   string `short:"o" help:"File to write. Defaults to <date>.md in the markdown export directory setting, or stdout if it is unset." type:"path"`
+	Stdout   bool   `help:"Write to stdout even if a markdown export directory is set."`
+}
+
+func (c *ExportMarkdownCmd) Run(ctx *cli.Context) error {
+	date, err := parseDate(c.Date, ctx.Now())
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := loadNoteTemplate(c.Template)
+	if err != nil {
+		return err
+	}
+
+	note, err := buildDailyNote(ctx, date)
+	if err != nil {
+		return err
+	}
+
+	output := c.Output
+	if output == "" && !c.Stdout {
+		settings, err := ctx.Store.GetSettings()
+		if err != nil {
+			return fmt.Errorf("failed to get settings: %w", err)
+		}
+		if settings.MarkdownExportDir != "" {
+			if err := os.MkdirAll(settings.MarkdownExportDir, 0o755); err != nil {
+				return fmt.Errorf("failed to create export directory: %w", err)
+			}
+			output = filepath.Join(settings.MarkdownExportDir, date+".md")
+		}
+	}
+
+	if output == "" {
+		return renderNote(os.Stdout, tmpl, note)
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	err = renderNote(f, tmpl, note)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close %s: %w", output, closeErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %s to %s\n", date, output)
+	return nil
+}
+
+// loadNoteTemplate parses the template file at path, or the built-in template if path is empty
+func loadNoteTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return template.Must(template.New("note").Parse(defaultNoteTemplate)), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func renderNote(w io.Writer, tmpl *template.Template, note DailyNote) error {
+	if err := tmpl.Execute(w, note); err != nil {
+		return fmt.Errorf("failed to render note: %w", err)
+	}
+	return nil
+}
+
+// buildDailyNote gathers the plan, habits, and OT entry for date
+func buildDailyNote(ctx *cli.Context, date string) (DailyNote, error) {
+	day, err := time.Parse(constants.DateFormat, date)
+	if err != nil {
+		return DailyNote{}, err
+	}
+	note := DailyNote{Date: date, Weekday: day.Weekday().String()}
+
+	summaries, err := ctx.Store.GetDaySummaries(date, date)
+	if err != nil {
+		return DailyNote{}, fmt.Errorf("failed to get day summary: %w", err)
+	}
+	if len(summaries) > 0 {
+		note.HasPlan = summaries[0].HasPlan
+		note.Accepted = summaries[0].Accepted
+	}
+
+	if plan, err := ctx.Store.GetPlan(date); err == nil {
+		note.Notes = plan.Note
+
+		settings, err := ctx.Store.GetSettings()
+		if err != nil {
+			return DailyNote{}, fmt.Errorf("failed to get settings: %w", err)
+		}
+		window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+		if err != nil {
+			return DailyNote{}, err
+		}
+		for _, slot := range plan.Slots {
+			if window.Drift(slot).Tracked() {
+				note.TrackedSlots++
+			}
+		}
+		note.DriftMinutes = window.TotalDrift(plan.Slots)
+	}
+
+	err = ctx.Store.EachSlot(date, date, func(r models.SlotRecord) error {
+		note.Slots = append(note.Slots, NoteSlot{
+			Start:  r.Start,
+			End:    r.End,
+			Task:   r.TaskName,
+			Status: string(r.Status),
+			Done:   r.Status == constants.SlotStatusDone,
+			Rating: string(r.Rating),
+			Note:   r.Note,
+		})
+		return nil
+	})
+	if err != nil {
+		return DailyNote{}, fmt.Errorf("failed to get slots: %w", err)
+	}
+
+	habits, err := ctx.Store.GetAllHabits(false, false)
+	if err != nil {
+		return DailyNote{}, fmt.Errorf("failed to get habits: %w", err)
+	}
+	entries, err := ctx.Store.GetHabitEntriesForDay(date)
+	if err != nil {
+		return DailyNote{}, fmt.Errorf("failed to get habit entries: %w", err)
+	}
+	entryByHabit := make(map[string]models.HabitEntry, len(entries))
+	for _, e := range entries {
+		entryByHabit[e.HabitID] = e
+	}
+	for _, h := range habits {
+		e, done := entryByHabit[h.ID]
+		note.Habits = append(note.Habits, NoteHabit{Name: h.Name, Done: done, Note: e.Note})
+	}
+
+	ot, err := ctx.Store.GetOTEntry(date)
+	switch {
+	case err == nil:
+		note.OT = &ot
+	case !errors.Is(err, sql.ErrNoRows):
+		return DailyNote{}, fmt.Errorf("failed to get OT entry: %w", err)
+	}
+
+	return note, nil
+}
@@ -9,24 +9,33 @@ import (
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/hooks"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
 )
 
 type HabitCmd struct {
-	Add     HabitAddCmd     `cmd:"" help:"Add a new habit."`
-	List    HabitListCmd    `cmd:"" help:"List habits."`
-	Mark    HabitMarkCmd    `cmd:"" help:"Mark a habit as done for a day."`
-	Today   HabitTodayCmd   `cmd:"" help:"Show today's habit status."`
-	Log     HabitLogCmd     `cmd:"" help:"Show habit log (ASCII history)."`
-	Archive HabitArchiveCmd `cmd:"" help:"Archive a habit."`
-	Delete  HabitDeleteCmd  `cmd:"" help:"Delete a habit (soft delete)."`
-	Restore HabitRestoreCmd `cmd:"" help:"Restore a deleted habit."`
+	Add      HabitAddCmd      `cmd:"" help:"Add a new habit."`
+	List     HabitListCmd     `cmd:"" help:"List habits."`
+	Mark     HabitMarkCmd     `cmd:"" help:"Mark a habit as done for a day."`
+	Today    HabitTodayCmd    `cmd:"" help:"Show today's habit status."`
+	Log      HabitLogCmd      `cmd:"" help:"Show habit log (ASCII history)."`
+	Archive  HabitArchiveCmd  `cmd:"" help:"Archive a habit."`
+	Delete   HabitDeleteCmd   `cmd:"" help:"Delete a habit (soft delete)."`
+	Restore  HabitRestoreCmd  `cmd:"" help:"Restore a deleted habit."`
+	Remind   HabitRemindCmd   `cmd:"" help:"Set, show or turn off a habit's reminder."`
+	Category HabitCategoryCmd `cmd:"" help:"Set, show or clear a habit's category."`
+	Pause    HabitPauseCmd    `cmd:"" help:"Pause a habit until a date without breaking its streak."`
+	Resume   HabitResumeCmd   `cmd:"" help:"End a habit's pause early."`
 }
 
 type HabitAddCmd struct {
-	Name string `arg:"" help:"Habit name."`
+	Name       string `arg:"" help:"Habit name."`
+	Category   string `help:"Category to group the habit under (e.g., health, chores, learning)."`
+	RemindAt   string `help:"Remind about the habit at this time (HH:MM) while it is unmarked."`
+	RemindDays string `help:"Comma-separated weekdays to remind on (e.g., mon,wed,fri). Default: every day."`
 }
 
 func (c *HabitAddCmd) Run(ctx *cli.Context) error {
@@ -39,7 +48,13 @@ func (c *HabitAddCmd) Run(ctx *cli.Context) error {
 	habit := models.Habit{
 		ID:        uuid.New().String(),
 		Name:      c.Name,
-		CreatedAt: time.Now(),
+		Category:  models.NormalizeHabitCategory(c.Category),
+		CreatedAt: ctx.Now(),
+	}
+	if c.RemindAt != "" || c.RemindDays != "" {
+		if err := setReminder(&habit, c.RemindAt, c.RemindDays); err != nil {
+			return err
+		}
 	}
 
 	if err := ctx.Store.AddHabit(habit); err != nil {
@@ -47,12 +62,16 @@ func (c *HabitAddCmd) Run(ctx *cli.Context) error {
 	}
 
 	fmt.Printf("Added habit: %s\n", c.Name)
+	if habit.ReminderEnabled {
+		fmt.Printf("Reminder: %s\n", habit.ReminderSummary())
+	}
 	return nil
 }
 
 type HabitListCmd struct {
-	Archived bool `help:"Include archived habits."`
-	Deleted  bool `help:"Include deleted habits."`
+	Archived bool   `help:"Include archived habits."`
+	Deleted  bool   `help:"Include deleted habits."`
+	Category string `help:"Only list habits in this category."`
 }
 
 func (c *HabitListCmd) Run(ctx *cli.Context) error {
@@ -61,24 +80,70 @@ func (c *HabitListCmd) Run(ctx *cli.Context) error {
 		return err
 	}
 
+	if c.Category != "" {
+		category := models.NormalizeHabitCategory(c.Category)
+		var filtered []models.Habit
+		for _, h := range habits {
+			if h.Category == category {
+				filtered = append(filtered, h)
+			}
+		}
+		habits = filtered
+	}
+
 	if len(habits) == 0 {
 		fmt.Println("No habits found.")
 		return nil
 	}
 
-	for _, habit := range habits {
+	printGrouped(habits, func(habit models.Habit) string {
 		status := ""
 		if habit.DeletedAt != nil {
 			status = " [DELETED]"
 		} else if habit.ArchivedAt != nil {
 			status = " [ARCHIVED]"
 		}
-		fmt.Printf("%s%s\n", habit.Name, status)
-	}
+		if today := ctx.Now().Format(constants.DateFormat); habit.PausedOn(today) || habit.PausedFrom > today {
+			status += fmt.Sprintf(" (paused %s to %s)", habit.PausedFrom, habit.PausedUntil)
+		}
+		if habit.ReminderTime != "" {
+			status += fmt.Sprintf(" (reminder %s)", habit.ReminderSummary())
+		}
+		return habit.Name + status
+	})
 
 	return nil
 }
 
+// printGrouped prints a line per habit, under a heading per category when
+// any habit has one
+func printGrouped(habits []models.Habit, line func(models.Habit) string) {
+	groups := models.GroupHabits(habits)
+	if len(groups) == 1 && groups[0].Category == "" {
+		for _, h := range habits {
+			fmt.Println(line(h))
+		}
+		return
+	}
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s:\n", categoryName(group.Category))
+		for _, h := range group.Habits {
+			fmt.Printf("  %s\n", line(h))
+		}
+	}
+}
+
+// categoryName is the heading for a category
+func categoryName(category string) string {
+	if category == "" {
+		return "uncategorized"
+	}
+	return category
+}
+
 type HabitMarkCmd struct {
 	Name string `arg:"" help:"Habit name."`
 	Date string `help:"Date in YYYY-MM-DD format (default: today)." default:""`
@@ -92,15 +157,10 @@ func (c *HabitMarkCmd) Run(ctx *cli.Context) error {
 		return fmt.Errorf("habit %q not found", c.Name)
 	}
 
-	// Determine the date
-	day := c.Date
-	if day == "" {
-		day = time.Now().Format(constants.DateFormat)
-	} else {
-		// Validate date format
-		if _, err := time.Parse(constants.DateFormat, day); err != nil {
-			return fmt.Errorf("invalid date format: %s (expected YYYY-MM-DD)", day)
-		}
+	// Determine the date; past days can be backfilled
+	day, err := ctx.PastDate(c.Date)
+	if err != nil {
+		return err
 	}
 
 	// Check if entry already exists
@@ -120,8 +180,8 @@ func (c *HabitMarkCmd) Run(ctx *cli.Context) error {
 		HabitID:   habit.ID,
 		Day:       day,
 		Note:      c.Note,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CreatedAt: ctx.Now(),
+		UpdatedAt: ctx.Now(),
 	}
 
 	if err := ctx.Store.AddHabitEntry(entry); err != nil {
@@ -129,6 +189,7 @@ func (c *HabitMarkCmd) Run(ctx *cli.Context) error {
 	}
 
 	fmt.Printf("Marked habit %q for %s\n", c.Name, day)
+	hooks.Fire(hooks.HabitMarked, hooks.HabitData{HabitID: habit.ID, Name: habit.Name, Day: day, Note: entry.Note})
 	return nil
 }
 
@@ -145,7 +206,7 @@ func (c *HabitTodayCmd) Run(ctx *cli.Context) error {
 		return nil
 	}
 
-	today := time.Now().Format("2006-01-02")
+	today := ctx.Now().Format("2006-01-02")
 	entries, err := ctx.Store.GetHabitEntriesForDay(today)
 	if err != nil {
 		return err
@@ -159,17 +220,25 @@ func (c *HabitTodayCmd) Run(ctx *cli.Context) error {
 
 	fmt.Printf("Habits for %s:\n\n", today)
 	recorded := 0
+	var active []models.Habit
 	for _, habit := range habits {
 		if habit.ArchivedAt != nil {
 			continue
 		}
-		status := "[ ]"
+		active = append(active, habit)
 		if entryMap[habit.ID] {
-			status = "[x]"
 			recorded++
 		}
-		fmt.Printf("%s %s\n", status, habit.Name)
 	}
+	printGrouped(active, func(habit models.Habit) string {
+		switch {
+		case entryMap[habit.ID]:
+			return "[x] " + habit.Name
+		case habit.PausedOn(today):
+			return fmt.Sprintf("[-] %s (paused until %s)", habit.Name, habit.PausedUntil)
+		}
+		return "[ ] " + habit.Name
+	})
 
 	activeCount := 0
 	for _, habit := range habits {
@@ -179,6 +248,14 @@ func (c *HabitTodayCmd) Run(ctx *cli.Context) error {
 	}
 
 	fmt.Printf("\nRecorded: %d/%d\n", recorded, activeCount)
+
+	vacations, err := ctx.Store.GetVacations(today, today)
+	if err != nil {
+		return err
+	}
+	if v, away := models.VacationOn(vacations, today); away {
+		fmt.Printf("On vacation until %s; missed habits don't count against you.\n", v.End)
+	}
 	return nil
 }
 
@@ -220,9 +297,15 @@ func (c *HabitLogCmd) Run(ctx *cli.Context) error {
 	}
 
 	// Calculate date range
-	endDay := time.Now()
+	endDay := ctx.Now()
 	startDay := endDay.AddDate(0, 0, -(c.Days - 1))
 
+	// Days away are shown apart from missed days
+	vacations, err := ctx.Store.GetVacations(startDay.Format("2006-01-02"), endDay.Format("2006-01-02"))
+	if err != nil {
+		return err
+	}
+
 	// Get entries for each habit
 	fmt.Printf("Habit log (last %d days):\n\n", c.Days)
 
@@ -243,6 +326,7 @@ func (c *HabitLogCmd) Run(ctx *cli.Context) error {
 	fmt.Println()
 
 	// Print each habit's log
+	anyPaused := false
 	for _, habit := range selectedHabits {
 		// Truncate or pad habit name
 		name := habit.Name
@@ -281,6 +365,11 @@ func (c *HabitLogCmd) Run(ctx *cli.Context) error {
 			dayStr := day.Format("2006-01-02")
 			if entryMap[dayStr] {
 				fmt.Print("  x   ")
+			} else if habit.PausedOn(dayStr) {
+				fmt.Print("  -   ")
+				anyPaused = true
+			} else if _, away := models.VacationOn(vacations, dayStr); away {
+				fmt.Print("  ~   ")
 			} else {
 				fmt.Print("  .   ")
 			}
@@ -288,6 +377,16 @@ func (c *HabitLogCmd) Run(ctx *cli.Context) error {
 		fmt.Println()
 	}
 
+	if len(vacations) > 0 || anyPaused {
+		fmt.Println()
+	}
+	if len(vacations) > 0 {
+		fmt.Println("~ on vacation")
+	}
+	if anyPaused {
+		fmt.Println("- paused")
+	}
+
 	return nil
 }
 
@@ -327,11 +426,18 @@ func (c *HabitDeleteCmd) Run(ctx *cli.Context) error {
 		return fmt.Errorf("habit %q not found", c.Name)
 	}
 
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
 	if err := ctx.Store.DeleteHabit(habit.ID); err != nil {
 		return err
 	}
 
 	fmt.Printf("Deleted habit: %s\n", c.Name)
+	if settings.HabitDeleteEntries == constants.HabitDeleteEntriesDelete {
+		fmt.Println("Its entries were deleted with it.")
+	}
 	fmt.Println("(This is a soft delete. Use 'daylit habit restore' to undo)")
 	return nil
 }
@@ -367,9 +473,179 @@ func (c *HabitRestoreCmd) Run(ctx *cli.Context) error {
 	return nil
 }
 
+type HabitRemindCmd struct {
+	Name string `arg:"" help:"Habit name."`
+	At   string `help:"Reminder time (HH:MM)."`
+	Days string `help:"Comma-separated weekdays to remind on (e.g., mon,wed,fri), or 'all' for every day."`
+	Off  bool   `help:"Turn the reminder off, keeping its time and days."`
+	On   bool   `help:"Turn a reminder that was turned off back on."`
+}
+
+func (c *HabitRemindCmd) Run(ctx *cli.Context) error {
+	if c.On && c.Off {
+		return fmt.Errorf("--on and --off can't be used together")
+	}
+
+	habit, err := ctx.Store.GetHabitByName(c.Name)
+	if err != nil {
+		return fmt.Errorf("habit %q not found", c.Name)
+	}
+
+	if c.At == "" && c.Days == "" && !c.On && !c.Off {
+		fmt.Printf("Reminder for %s: %s\n", habit.Name, habit.ReminderSummary())
+		return nil
+	}
+
+	if c.At != "" || c.Days != "" {
+		if err := setReminder(&habit, c.At, c.Days); err != nil {
+			return err
+		}
+	}
+	switch {
+	case c.Off:
+		habit.ReminderEnabled = false
+	case c.On:
+		if habit.ReminderTime == "" {
+			return fmt.Errorf("habit %q has no reminder time; set one with --at", c.Name)
+		}
+		habit.ReminderEnabled = true
+	}
+
+	if err := ctx.Store.UpdateHabit(habit); err != nil {
+		return err
+	}
+
+	fmt.Printf("Reminder for %s: %s\n", habit.Name, habit.ReminderSummary())
+	return nil
+}
+
+type HabitCategoryCmd struct {
+	Name     string `arg:"" help:"Habit name."`
+	Category string `arg:"" optional:"" help:"Category to group the habit under (e.g., health, chores, learning)."`
+	Clear    bool   `help:"Remove the habit's category."`
+}
+
+func (c *HabitCategoryCmd) Run(ctx *cli.Context) error {
+	if c.Clear && c.Category != "" {
+		return fmt.Errorf("give a category or --clear, not both")
+	}
+
+	habit, err := ctx.Store.GetHabitByName(c.Name)
+	if err != nil {
+		return fmt.Errorf("habit %q not found", c.Name)
+	}
+
+	if !c.Clear && c.Category == "" {
+		fmt.Printf("Category for %s: %s\n", habit.Name, categoryName(habit.Category))
+		return nil
+	}
+
+	habit.Category = models.NormalizeHabitCategory(c.Category)
+	if err := ctx.Store.UpdateHabit(habit); err != nil {
+		return err
+	}
+
+	fmt.Printf("Category for %s: %s\n", habit.Name, categoryName(habit.Category))
+	return nil
+}
+
+type HabitPauseCmd struct {
+	Name  string `arg:"" help:"Habit name."`
+	Until string `required:"" help:"Last paused day (YYYY-MM-DD)."`
+	From  string `help:"First paused day (YYYY-MM-DD). Default: today."`
+}
+
+func (c *HabitPauseCmd) Run(ctx *cli.Context) error {
+	habit, err := ctx.Store.GetHabitByName(c.Name)
+	if err != nil {
+		return fmt.Errorf("habit %q not found", c.Name)
+	}
+
+	from := c.From
+	if from == "" {
+		from = ctx.Now().Format(constants.DateFormat)
+	} else if _, err := time.Parse(constants.DateFormat, from); err != nil {
+		return fmt.Errorf("invalid date format: %s (expected YYYY-MM-DD)", from)
+	}
+	if _, err := time.Parse(constants.DateFormat, c.Until); err != nil {
+		return fmt.Errorf("invalid date format: %s (expected YYYY-MM-DD)", c.Until)
+	}
+	if c.Until < from {
+		return fmt.Errorf("--until %s is before the pause starts on %s", c.Until, from)
+	}
+
+	// A habit keeps one pause; a new one replaces it
+	habit.PausedFrom = from
+	habit.PausedUntil = c.Until
+	if err := ctx.Store.UpdateHabit(habit); err != nil {
+		return err
+	}
+
+	fmt.Printf("Paused habit %q from %s until %s\n", c.Name, from, c.Until)
+	return nil
+}
+
+type HabitResumeCmd struct {
+	Name string `arg:"" help:"Habit name."`
+}
+
+func (c *HabitResumeCmd) Run(ctx *cli.Context) error {
+	habit, err := ctx.Store.GetHabitByName(c.Name)
+	if err != nil {
+		return fmt.Errorf("habit %q not found", c.Name)
+	}
+
+	today := ctx.Now().Format(constants.DateFormat)
+	if habit.PausedFrom == "" || habit.PausedUntil < today {
+		return fmt.Errorf("habit %q isn't paused", c.Name)
+	}
+
+	// The days already paused stay neutral; a pause that hasn't started yet
+	// is dropped
+	yesterday := ctx.Now().AddDate(0, 0, -1).Format(constants.DateFormat)
+	if habit.PausedFrom <= yesterday {
+		habit.PausedUntil = yesterday
+	} else {
+		habit.PausedFrom, habit.PausedUntil = "", ""
+	}
+	if err := ctx.Store.UpdateHabit(habit); err != nil {
+		return err
+	}
+
+	fmt.Printf("Resumed habit %q\n", c.Name)
+	return nil
+}
+
+// setReminder sets and turns on a habit's reminder. An empty at keeps the
+// current time, and days "all" reminds every day.
+func setReminder(habit *models.Habit, at, days string) error {
+	if at != "" {
+		if !utils.ValidateTimeFormat(at) {
+			return fmt.Errorf("invalid reminder time: %s (expected HH:MM)", at)
+		}
+		habit.ReminderTime = at
+	}
+	if habit.ReminderTime == "" {
+		return fmt.Errorf("a reminder needs a time (HH:MM)")
+	}
+	switch strings.ToLower(strings.TrimSpace(days)) {
+	case "":
+	case "all":
+		habit.ReminderDays = nil
+	default:
+		weekdays, err := cli.ParseWeekdays(days)
+		if err != nil {
+			return err
+		}
+		habit.ReminderDays = weekdays
+	}
+	habit.ReminderEnabled = true
+	return nil
+}
+
 // Helper function to check if storage is SQLite
 func isSQLiteStore(store storage.Provider) bool {
-	_, ok := store.(*sqlite.Store)
+	_, ok := storage.Unwrap(store).(*sqlite.Store)
 	return ok
 }
 
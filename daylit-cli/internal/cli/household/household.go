@@ -0,0 +1,156 @@
+package household
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+type HouseholdCmd struct {
+	Share   HouseholdShareCmd   `cmd:"" help:"Share a task with other users, who take turns at it."`
+	List    HouseholdListCmd    `cmd:"" help:"List shared tasks, who did each last and whose turn is next." default:"1"`
+	Unshare HouseholdUnshareCmd `cmd:"" help:"Stop sharing a task; every member keeps their copy."`
+}
+
+// household returns the store's shared tasks support, which only a shared
+// PostgreSQL database has
+func household(ctx *cli.Context) (storage.Household, error) {
+	h, ok := storage.Unwrap(ctx.Store).(storage.Household)
+	if !ok {
+		return nil, fmt.Errorf("this database holds a single user; shared tasks need a shared PostgreSQL database")
+	}
+	return h, nil
+}
+
+type HouseholdShareCmd struct {
+	Task string   `arg:"" help:"Task (ID or name) to share."`
+	With []string `required:"" sep:"," help:"Users to share the task with, in turn order after you. 'default' is the default user."`
+}
+
+func (c *HouseholdShareCmd) Run(ctx *cli.Context) error {
+	h, err := household(ctx)
+	if err != nil {
+		return err
+	}
+	task, err := ctx.ResolveTask(c.Task, cli.LiveTasks)
+	if err != nil {
+		return err
+	}
+
+	members := []string{h.CurrentUser()}
+	for _, name := range c.With {
+		name = strings.TrimSpace(name)
+		if name == models.DefaultUserName {
+			name = ""
+		}
+		members = append(members, name)
+	}
+	if err := h.ShareTask(task.ID, members, ctx.Now()); err != nil {
+		return fmt.Errorf("failed to share %s: %w", task.Name, err)
+	}
+
+	labels := make([]string, len(members))
+	for i, user := range members {
+		labels[i] = models.UserLabel(user)
+	}
+	fmt.Printf("Shared %s. Turn order: %s\n", task.Name, strings.Join(labels, " → "))
+	return nil
+}
+
+// sharedTaskJSON is a shared task in 'daylit household list --json'
+type sharedTaskJSON struct {
+	models.SharedTask
+	Name       string `json:"name"`
+	LastDoneBy string `json:"last_done_by,omitempty"`
+	Turn       string `json:"turn"`
+}
+
+type HouseholdListCmd struct {
+	JSON bool `help:"Output as JSON."`
+}
+
+func (c *HouseholdListCmd) Run(ctx *cli.Context) error {
+	h, err := household(ctx)
+	if err != nil {
+		return err
+	}
+	shared, err := h.GetSharedTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get shared tasks: %w", err)
+	}
+
+	list := make([]sharedTaskJSON, 0, len(shared))
+	for _, st := range shared {
+		item := sharedTaskJSON{SharedTask: st, Name: "(deleted task)", Turn: models.UserLabel(st.Turn())}
+		if task, err := ctx.Store.GetTask(st.TaskID); err == nil {
+			item.Name = task.Name
+		}
+		if last, ok := st.LastDoneBy(); ok {
+			item.LastDoneBy = models.UserLabel(last.User)
+		}
+		list = append(list, item)
+	}
+
+	if c.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No shared tasks found")
+		fmt.Println("Share one with 'daylit household share TASK --with USER'.")
+		return nil
+	}
+
+	me := h.CurrentUser()
+	fmt.Println("Shared tasks:")
+	for _, item := range list {
+		labels := make([]string, len(item.Members))
+		for i, m := range item.Members {
+			labels[i] = models.UserLabel(m.User)
+		}
+		fmt.Printf("  %s (%s)\n", item.Name, strings.Join(labels, " → "))
+		if item.LastDoneBy != "" {
+			last, _ := item.SharedTask.LastDoneBy()
+			fmt.Printf("      Last done: %s by %s\n", last.LastDone, item.LastDoneBy)
+		} else {
+			fmt.Println("      Last done: never")
+		}
+		turn := item.Turn
+		if item.SharedTask.Turn() == me {
+			turn += " (you)"
+		}
+		fmt.Printf("      Next turn: %s\n", turn)
+	}
+	return nil
+}
+
+type HouseholdUnshareCmd struct {
+	Task string `arg:"" help:"Task (ID or name) to stop sharing."`
+}
+
+func (c *HouseholdUnshareCmd) Run(ctx *cli.Context) error {
+	h, err := household(ctx)
+	if err != nil {
+		return err
+	}
+	task, err := ctx.ResolveTask(c.Task, cli.LiveTasks)
+	if err != nil {
+		return err
+	}
+	if err := h.UnshareTask(task.ID); errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s is not shared", task.Name)
+	} else if err != nil {
+		return fmt.Errorf("failed to stop sharing %s: %w", task.Name, err)
+	}
+	fmt.Printf("Stopped sharing %s; every member keeps their copy.\n", task.Name)
+	return nil
+}
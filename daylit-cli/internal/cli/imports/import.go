@@ -0,0 +1,159 @@
+package imports
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/importer"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+type ImportCmd struct {
+	Taskwarrior ImportTaskwarriorCmd `cmd:"" help:"Import pending tasks from a Taskwarrior JSON export ('task export')."`
+	TodoTxt     ImportTodoTxtCmd     `cmd:"" name:"todotxt" help:"Import open tasks from a todo.txt file."`
+}
+
+// ImportFlags are shared by every import source
+type ImportFlags struct {
+	File     string `arg:"" optional:"" help:"File to import, or '-' for stdin." default:"-"`
+	Duration int    `short:"d" help:"Duration in minutes given to imported tasks." default:"30"`
+	Priority int    `short:"p" help:"Priority (1-5) for tasks without one." default:"3"`
+	Yes      bool   `short:"y" help:"Import without asking for confirmation."`
+}
+
+func (f *ImportFlags) Validate() error {
+	if f.Duration <= 0 {
+		return fmt.Errorf("duration must be greater than zero")
+	}
+	if f.Priority < 1 || f.Priority > 5 {
+		return fmt.Errorf("priority must be between 1 and 5")
+	}
+	return nil
+}
+
+type ImportTaskwarriorCmd struct {
+	ImportFlags `embed:""`
+}
+
+func (c *ImportTaskwarriorCmd) Run(ctx *cli.Context) error {
+	return runImport(ctx, c.ImportFlags, importer.ParseTaskwarrior)
+}
+
+type ImportTodoTxtCmd struct {
+	ImportFlags `embed:""`
+}
+
+func (c *ImportTodoTxtCmd) Run(ctx *cli.Context) error {
+	return runImport(ctx, c.ImportFlags, importer.ParseTodoTxt)
+}
+
+type parseFunc func(io.Reader, importer.Options) ([]models.Task, error)
+
+func runImport(ctx *cli.Context, flags ImportFlags, parse parseFunc) error {
+	// Turning confirm off in config.toml works like --yes
+	yes := flags.Yes || !ctx.Config.Confirms()
+
+	fromStdin := flags.File == "" || flags.File == "-"
+	if fromStdin && !yes {
+		// stdin carries the import data, so it can't also answer the prompt
+		return fmt.Errorf("--yes is required when reading from stdin")
+	}
+
+	in := io.Reader(os.Stdin)
+	if !fromStdin {
+		f, err := os.Open(flags.File)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", flags.File, err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	opts := importer.Options{
+		DurationMin: flags.Duration,
+		Priority:    flags.Priority,
+		Today:       ctx.Now(),
+	}
+	tasks, err := parse(in, opts)
+	if err != nil {
+		return err
+	}
+
+	return importTasks(ctx, tasks, yes, os.Stdin)
+}
+
+// importTasks previews the tasks, drops those whose names already exist, asks
+// for confirmation on confirm unless yes is set, and saves the rest
+func importTasks(ctx *cli.Context, tasks []models.Task, yes bool, confirm io.Reader) error {
+	existing, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		seen[nameKey(t.Name)] = true
+	}
+
+	var toAdd []models.Task
+	skipped := 0
+	for _, t := range tasks {
+		key := nameKey(t.Name)
+		if seen[key] {
+			skipped++
+			continue
+		}
+		seen[key] = true
+		toAdd = append(toAdd, t)
+	}
+
+	for _, t := range toAdd {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("invalid task %q: %w", t.Name, err)
+		}
+	}
+
+	if len(toAdd) == 0 {
+		fmt.Printf("Nothing to import (%d duplicate task(s) skipped)\n", skipped)
+		return nil
+	}
+
+	fmt.Println("Tasks to import:")
+	for _, t := range toAdd {
+		fmt.Printf("  %s - %dm (%s, priority %d)\n",
+			t.Name, t.DurationMin, cli.FormatRecurrence(t.Recurrence), t.Priority)
+	}
+	if skipped > 0 {
+		fmt.Printf("Skipping %d task(s) that already exist\n", skipped)
+	}
+
+	if !yes {
+		fmt.Printf("\nImport %d task(s)? [y/N]: ", len(toAdd))
+		response, err := bufio.NewReader(confirm).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Import cancelled.")
+			return nil
+		}
+	}
+
+	for _, t := range toAdd {
+		if err := ctx.Store.AddTask(t); err != nil {
+			return fmt.Errorf("failed to add task %q: %w", t.Name, err)
+		}
+	}
+
+	fmt.Printf("Imported %d task(s)\n", len(toAdd))
+	return nil
+}
+
+// nameKey normalizes a task name for duplicate detection
+func nameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,119 @@
+package imports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/importer"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+)
+
+func setupTestDB(t *testing.T) (*cli.Context, func()) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "test.db")
+
+	store := sqlite.NewStore(dbPath)
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+
+	ctx := &cli.Context{
+		Store:     store,
+		Scheduler: scheduler.New(),
+	}
+
+	cleanup := func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("failed to close store: %v", err)
+		}
+	}
+
+	return ctx, cleanup
+}
+
+func TestImportTasks_DedupesAndConfirms(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	opts := importer.Options{DurationMin: 30, Priority: 3}
+	first, err := importer.ParseTodoTxt(strings.NewReader("Call dentist\nWater plants rec:daily\n"), opts)
+	if err != nil {
+		t.Fatalf("ParseTodoTxt failed: %v", err)
+	}
+	if err := importTasks(ctx, first, true, nil); err != nil {
+		t.Fatalf("importTasks failed: %v", err)
+	}
+
+	// Declining the prompt saves nothing
+	second, err := importer.ParseTodoTxt(strings.NewReader("call dentist \nStretch\nStretch\n"), opts)
+	if err != nil {
+		t.Fatalf("ParseTodoTxt failed: %v", err)
+	}
+	if err := importTasks(ctx, second, false, strings.NewReader("n\n")); err != nil {
+		t.Fatalf("importTasks failed: %v", err)
+	}
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		t.Fatalf("failed to get tasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("got %d tasks after declining, want 2", len(tasks))
+	}
+
+	// Confirming imports only the new name, once
+	if err := importTasks(ctx, second, false, strings.NewReader("y\n")); err != nil {
+		t.Fatalf("importTasks failed: %v", err)
+	}
+	tasks, err = ctx.Store.GetAllTasks()
+	if err != nil {
+		t.Fatalf("failed to get tasks: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("got %d tasks after import, want 3", len(tasks))
+	}
+	names := map[string]bool{}
+	for _, task := range tasks {
+		names[task.Name] = true
+	}
+	if !names["Stretch"] {
+		t.Errorf("expected Stretch to be imported, got %v", names)
+	}
+}
+
+func TestImportTaskwarriorCmd(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	export := `[{"description":"Write report","status":"pending","priority":"H"}]`
+	if err := os.WriteFile(path, []byte(export), 0o644); err != nil {
+		t.Fatalf("failed to write export: %v", err)
+	}
+
+	cmd := &ImportTaskwarriorCmd{ImportFlags{File: path, Duration: 45, Priority: 3, Yes: true}}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		t.Fatalf("failed to get tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Name != "Write report" || tasks[0].DurationMin != 45 || tasks[0].Priority != 1 {
+		t.Errorf("imported tasks = %+v", tasks)
+	}
+}
+
+func TestImportCmd_StdinRequiresYes(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	cmd := &ImportTodoTxtCmd{ImportFlags{File: "-", Duration: 30, Priority: 3}}
+	if err := cmd.Run(ctx); err == nil {
+		t.Error("expected error when reading stdin without --yes")
+	}
+}
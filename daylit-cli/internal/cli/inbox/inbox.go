@@ -0,0 +1,98 @@
+package inbox
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+type CaptureCmd struct {
+	Text []string `arg:"" help:"Text to capture; quotes are optional."`
+}
+
+func (c *CaptureCmd) Run(ctx *cli.Context) error {
+	item := models.InboxItem{
+		ID:        uuid.New().String(),
+		Text:      strings.TrimSpace(strings.Join(c.Text, " ")),
+		CreatedAt: ctx.Now(),
+	}
+	if err := item.Validate(); err != nil {
+		return err
+	}
+	if err := ctx.Store.AddInboxItem(item); err != nil {
+		return fmt.Errorf("failed to capture: %w", err)
+	}
+	fmt.Printf("Captured: %s\n", item.Text)
+	return nil
+}
+
+type InboxCmd struct {
+	List InboxListCmd `cmd:"" default:"1" help:"List captured items, oldest first."`
+	Drop InboxDropCmd `cmd:"" help:"Remove captured items by their number in the list."`
+}
+
+type InboxListCmd struct{}
+
+func (c *InboxListCmd) Run(ctx *cli.Context) error {
+	items, err := ctx.Store.GetInboxItems()
+	if err != nil {
+		return fmt.Errorf("failed to get inbox: %w", err)
+	}
+	if len(items) == 0 {
+		fmt.Println("Inbox is empty")
+		return nil
+	}
+
+	fmt.Printf("Inbox (%d):\n", len(items))
+	for i, item := range items {
+		fmt.Printf("  %2d. %s  (%s)\n", i+1, item.Text, capturedAt(item.CreatedAt, ctx.Now()))
+	}
+	fmt.Println("\nTriage them in the TUI's Inbox tab, or remove them with 'daylit inbox drop N'.")
+	return nil
+}
+
+type InboxDropCmd struct {
+	Numbers []int `arg:"" help:"Numbers of the items to remove, as shown by 'daylit inbox'."`
+}
+
+func (c *InboxDropCmd) Run(ctx *cli.Context) error {
+	items, err := ctx.Store.GetInboxItems()
+	if err != nil {
+		return fmt.Errorf("failed to get inbox: %w", err)
+	}
+
+	// Check every number before removing anything
+	for _, n := range c.Numbers {
+		if n < 1 || n > len(items) {
+			return fmt.Errorf("no inbox item %d (the inbox has %d)", n, len(items))
+		}
+	}
+	dropped := make(map[int]bool)
+	for _, n := range c.Numbers {
+		if dropped[n] {
+			continue
+		}
+		dropped[n] = true
+		if err := ctx.Store.DeleteInboxItem(items[n-1].ID); err != nil {
+			return err
+		}
+		fmt.Printf("Removed: %s\n", items[n-1].Text)
+	}
+	return nil
+}
+
+// capturedAt describes when an item was captured, leaving out the date for
+// today's items
+func capturedAt(t, now time.Time) string {
+	t = t.Local()
+	if t.Format(constants.DateFormat) == now.Format(constants.DateFormat) {
+		return "today " + t.Format("15:04")
+	}
+	return t.Format(constants.DateFormat + " 15:04")
+}
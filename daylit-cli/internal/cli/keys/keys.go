@@ -0,0 +1,98 @@
+package keys
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+type KeysCmd struct {
+	List  KeysListCmd  `cmd:"" help:"List the active TUI key bindings." default:"1"`
+	Set   KeysSetCmd   `cmd:"" help:"Remap a TUI action to one or more keys."`
+	Reset KeysResetCmd `cmd:"" help:"Restore the default keys for a TUI action."`
+}
+
+type KeysListCmd struct{}
+
+func (c *KeysListCmd) Run(ctx *cli.Context) error {
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	km, err := state.NewKeyMap(settings.Keys)
+	if err != nil {
+		fmt.Printf("Warning: invalid key bindings (%v); the TUI uses the defaults.\n\n", err)
+		km = state.DefaultKeyMap()
+	}
+
+	fmt.Printf("%-10s %-18s %s\n", "ACTION", "KEYS", "DESCRIPTION")
+	for _, a := range state.KeyActions {
+		b, _ := km.Binding(a.Name)
+		desc := b.Help().Desc
+		if len(state.ParseKeys(settings.Keys[a.Name])) > 0 {
+			desc += " (custom)"
+		}
+		fmt.Printf("%-10s %-18s %s\n", a.Name, strings.Join(b.Keys(), ","), desc)
+	}
+	return nil
+}
+
+type KeysSetCmd struct {
+	Action string `arg:"" help:"Action to remap (see 'daylit keys list')."`
+	Keys   string `arg:"" help:"Comma-separated keys, e.g. 'q,ctrl+c'."`
+}
+
+func (c *KeysSetCmd) Run(ctx *cli.Context) error {
+	if _, ok := state.LookupKeyAction(c.Action); !ok {
+		return fmt.Errorf("unknown action: %s", c.Action)
+	}
+	keys := state.ParseKeys(c.Keys)
+	if len(keys) == 0 {
+		return fmt.Errorf("at least one key is required")
+	}
+	return saveOverride(ctx, c.Action, strings.Join(keys, ","))
+}
+
+type KeysResetCmd struct {
+	Action string `arg:"" help:"Action to reset (see 'daylit keys list')."`
+}
+
+func (c *KeysResetCmd) Run(ctx *cli.Context) error {
+	if _, ok := state.LookupKeyAction(c.Action); !ok {
+		return fmt.Errorf("unknown action: %s", c.Action)
+	}
+	return saveOverride(ctx, c.Action, "")
+}
+
+// saveOverride stores the keys for an action after checking that the
+// resulting key map has no conflicts. An empty value restores the default.
+func saveOverride(ctx *cli.Context, action, value string) error {
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	overrides := maps.Clone(settings.Keys)
+	if overrides == nil {
+		overrides = make(map[string]string)
+	}
+	overrides[action] = value
+
+	km, err := state.NewKeyMap(overrides)
+	if err != nil {
+		return err
+	}
+
+	settings.Keys = overrides
+	if err := ctx.Store.SaveSettings(settings); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	b, _ := km.Binding(action)
+	fmt.Printf("%s: %s\n", action, strings.Join(b.Keys(), ","))
+	return nil
+}
@@ -0,0 +1,115 @@
+package keys
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+func setupTestDB(t *testing.T) (*cli.Context, func()) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "test.db")
+
+	store := sqlite.NewStore(dbPath)
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+
+	ctx := &cli.Context{
+		Store:     store,
+		Scheduler: scheduler.New(),
+	}
+
+	cleanup := func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("failed to close store: %v", err)
+		}
+	}
+
+	return ctx, cleanup
+}
+
+func TestKeysCmd_List(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	cmd := &KeysListCmd{}
+	if err := cmd.Run(ctx); err != nil {
+		t.Errorf("keys list failed: %v", err)
+	}
+}
+
+func TestKeysCmd_SetAndReset(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	set := &KeysSetCmd{Action: "quit", Keys: "x, ctrl+q"}
+	if err := set.Run(ctx); err != nil {
+		t.Fatalf("keys set failed: %v", err)
+	}
+
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if got := settings.Keys["quit"]; got != "x,ctrl+q" {
+		t.Errorf("stored quit keys = %q, want %q", got, "x,ctrl+q")
+	}
+
+	km, err := state.NewKeyMap(settings.Keys)
+	if err != nil {
+		t.Fatalf("NewKeyMap failed: %v", err)
+	}
+	if keys := km.Quit.Keys(); len(keys) != 2 || keys[0] != "x" || keys[1] != "ctrl+q" {
+		t.Errorf("quit binding keys = %v, want [x ctrl+q]", keys)
+	}
+
+	reset := &KeysResetCmd{Action: "quit"}
+	if err := reset.Run(ctx); err != nil {
+		t.Fatalf("keys reset failed: %v", err)
+	}
+
+	settings, err = ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	km, err = state.NewKeyMap(settings.Keys)
+	if err != nil {
+		t.Fatalf("NewKeyMap failed: %v", err)
+	}
+	if keys := km.Quit.Keys(); len(keys) != 2 || keys[0] != "q" {
+		t.Errorf("quit binding keys after reset = %v, want defaults", keys)
+	}
+}
+
+func TestKeysCmd_SetRejectsConflicts(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	cmd := &KeysSetCmd{Action: "add", Keys: "q"}
+	if err := cmd.Run(ctx); err == nil {
+		t.Error("expected error when binding a key already used by another action")
+	}
+
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if _, ok := settings.Keys["add"]; ok {
+		t.Error("conflicting binding should not be saved")
+	}
+}
+
+func TestKeysCmd_SetRejectsUnknownAction(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	cmd := &KeysSetCmd{Action: "teleport", Keys: "t"}
+	if err := cmd.Run(ctx); err == nil {
+		t.Error("expected error for unknown action")
+	}
+}
@@ -0,0 +1,115 @@
+package optimize
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/optimizer"
+)
+
+type OptimizeDriftCmd struct {
+	FeedbackLimit int  `help:"Number of recent feedback entries to compare per task." default:"10"`
+	Threshold     int  `help:"Only suggest a new duration when the estimate is off by at least this percentage." default:"15"`
+	Apply         bool `help:"Apply the suggested durations without asking."`
+}
+
+func (c *OptimizeDriftCmd) Run(ctx *cli.Context) error {
+	if c.Threshold < 0 {
+		return fmt.Errorf("threshold can't be negative")
+	}
+
+	analyzer := optimizer.NewFeedbackAnalyzer(ctx.Store)
+	drifts, err := analyzer.AnalyzeDrift(c.FeedbackLimit)
+	if err != nil {
+		return fmt.Errorf("failed to analyze tasks: %w", err)
+	}
+	if len(drifts) == 0 {
+		fmt.Println("No feedback with durations yet. Give feedback on finished slots with 'daylit feedback'.")
+		return nil
+	}
+
+	fmt.Printf("Estimate drift over the last %d feedback entries per task:\n\n", c.FeedbackLimit)
+	fmt.Printf("  %-24s %6s %7s %7s  %-28s %s\n", "Task", "Set", "Actual", "Error", "Feedback", "Suggested")
+	var suggestions []optimizer.DurationDrift
+	for _, d := range drifts {
+		suggested := "-"
+		if needsChange(d, c.Threshold) {
+			suggested = fmt.Sprintf("%dm", d.SuggestedMin)
+			suggestions = append(suggestions, d)
+		}
+		fmt.Printf("  %-24s %5dm %6.0fm %+6.0f%%  %-28s %s\n",
+			truncate(d.TaskName, 24), d.DurationMin, d.ActualAvgMin, d.ErrorPercent(), formatRatings(d), suggested)
+	}
+
+	if len(suggestions) == 0 {
+		fmt.Printf("\n✅ No estimate is off by %d%% or more.\n", c.Threshold)
+		return nil
+	}
+
+	fmt.Println()
+	apply := c.Apply
+	if !apply {
+		apply, err = ctx.Confirm(fmt.Sprintf("Apply the %d suggested duration(s)?", len(suggestions)))
+		if err != nil {
+			return err
+		}
+	}
+	if !apply {
+		fmt.Println("No changes made.")
+		return nil
+	}
+
+	applied := 0
+	for _, d := range suggestions {
+		if err := applyDrift(ctx, d); err != nil {
+			fmt.Printf("  ❌ Failed to update %s: %v\n", d.TaskName, err)
+			continue
+		}
+		applied++
+		fmt.Printf("  ✅ %s: %dm → %dm\n", d.TaskName, d.DurationMin, d.SuggestedMin)
+	}
+	fmt.Printf("\n✨ Updated %d/%d task durations.\n", applied, len(suggestions))
+	return nil
+}
+
+// needsChange reports whether a task's estimate is off by at least threshold
+// percent and the suggestion actually changes it
+func needsChange(d optimizer.DurationDrift, threshold int) bool {
+	return d.SuggestedMin != d.DurationMin && math.Abs(d.ErrorPercent()) >= float64(threshold)
+}
+
+func formatRatings(d optimizer.DurationDrift) string {
+	var parts []string
+	if d.OnTrack > 0 {
+		parts = append(parts, fmt.Sprintf("%d on track", d.OnTrack))
+	}
+	if d.TooMuch > 0 {
+		parts = append(parts, fmt.Sprintf("%d too much", d.TooMuch))
+	}
+	if d.Unnecessary > 0 {
+		parts = append(parts, fmt.Sprintf("%d unnecessary", d.Unnecessary))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+func applyDrift(ctx *cli.Context, d optimizer.DurationDrift) error {
+	task, err := ctx.Store.GetTask(d.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	task.DurationMin = d.SuggestedMin
+	if err := task.Validate(); err != nil {
+		return fmt.Errorf("task validation failed: %w", err)
+	}
+	return ctx.Store.UpdateTask(task)
+}
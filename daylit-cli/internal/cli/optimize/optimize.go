@@ -13,12 +13,22 @@ import (
 )
 
 type OptimizeCmd struct {
+	Suggest   OptimizeSuggestCmd   `cmd:"" default:"withargs" help:"Suggest task changes from feedback ratings."`
+	Drift     OptimizeDriftCmd     `cmd:"" help:"Rank tasks by how far their durations are from how long they actually take."`
+	Placement OptimizePlacementCmd `cmd:"" help:"Show the time of day each task gets done most reliably, as used by adaptive placement."`
+}
+
+// scheduleLookbackDays is how many days of metrics the advice about the
+// plans as a whole looks at
+const scheduleLookbackDays = 30
+
+type OptimizeSuggestCmd struct {
 	FeedbackLimit int  `help:"Number of recent feedback entries to analyze per task." default:"10"`
 	Interactive   bool `help:"Interactively review and apply optimizations." default:"false"`
 	AutoApply     bool `help:"Automatically apply all optimizations without confirmation." default:"false"`
 }
 
-func (c *OptimizeCmd) Run(ctx *cli.Context) error {
+func (c *OptimizeSuggestCmd) Run(ctx *cli.Context) error {
 	if err := ctx.Store.Load(); err != nil {
 		return err
 	}
@@ -33,6 +43,21 @@ func (c *OptimizeCmd) Run(ctx *cli.Context) error {
 		return fmt.Errorf("failed to analyze tasks: %w", err)
 	}
 
+	now := ctx.Now()
+	insights, err := analyzer.AnalyzeSchedule(
+		now.AddDate(0, 0, -scheduleLookbackDays).Format(constants.DateFormat),
+		now.AddDate(0, 0, -1).Format(constants.DateFormat),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to analyze schedule: %w", err)
+	}
+	if len(insights) > 0 {
+		fmt.Printf("\n📈 Scheduling metrics for the past %d days:\n\n", scheduleLookbackDays)
+		for _, insight := range insights {
+			fmt.Printf("   - %s: %s\n", insight.Reason, insight.Advice)
+		}
+	}
+
 	if len(optimizations) == 0 {
 		fmt.Println("✅ No optimizations needed. All tasks are performing well based on feedback!")
 		return nil
@@ -47,14 +72,22 @@ func (c *OptimizeCmd) Run(ctx *cli.Context) error {
 	// Auto-apply mode
 	if c.AutoApply {
 		fmt.Println("\n🚀 Applying all optimizations...")
+		// The optimizations that apply are saved in one transaction, so an
+		// interrupted run leaves none of them half done
 		applied := 0
-		for _, opt := range optimizations {
-			if err := applyOptimization(ctx, opt); err != nil {
-				fmt.Printf("  ❌ Failed to apply optimization for %s: %v\n", opt.TaskName, err)
-			} else {
-				applied++
-				fmt.Printf("  ✅ Applied optimization for %s\n", opt.TaskName)
+		err := ctx.InTx(func() error {
+			for _, opt := range optimizations {
+				if err := applyOptimization(ctx, opt); err != nil {
+					fmt.Printf("  ❌ Failed to apply optimization for %s: %v\n", opt.TaskName, err)
+				} else {
+					applied++
+					fmt.Printf("  ✅ Applied optimization for %s\n", opt.TaskName)
+				}
 			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save optimizations: %w", err)
 		}
 		fmt.Printf("\n✨ Successfully applied %d/%d optimizations.\n", applied, len(optimizations))
 		return nil
@@ -73,7 +106,7 @@ func (c *OptimizeCmd) Run(ctx *cli.Context) error {
 	return nil
 }
 
-func (c *OptimizeCmd) runInteractive(ctx *cli.Context, optimizations []optimizer.Optimization) error {
+func (c *OptimizeSuggestCmd) runInteractive(ctx *cli.Context, optimizations []optimizer.Optimization) error {
 	fmt.Println("\n🎯 Interactive optimization mode")
 	fmt.Println("Review each suggestion and choose whether to apply it.")
 
@@ -0,0 +1,52 @@
+package optimize
+
+import (
+	"fmt"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/autoplan"
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/optimizer"
+)
+
+type OptimizePlacementCmd struct {
+	Days int `help:"Number of past days of plans to learn from." default:"60"`
+}
+
+func (c *OptimizePlacementCmd) Run(ctx *cli.Context) error {
+	if c.Days <= 0 {
+		return fmt.Errorf("days must be positive")
+	}
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	now := ctx.Now()
+	prefs, err := optimizer.NewFeedbackAnalyzer(ctx.Store).AnalyzePlacement(
+		now.AddDate(0, 0, -c.Days).Format(constants.DateFormat),
+		now.AddDate(0, 0, -1).Format(constants.DateFormat),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to analyze placement: %w", err)
+	}
+	if len(prefs) == 0 {
+		fmt.Printf("No task has a time of day it clearly gets done best at in the past %d days.\n", c.Days)
+		fmt.Println("A time is learned once a task has several accepted slots and is done noticeably more often at one time of day than at the others.")
+		return nil
+	}
+
+	fmt.Printf("Best times of day over the past %d days of accepted plans:\n\n", c.Days)
+	for _, p := range prefs {
+		fmt.Printf("  %s: done %.0f%% of the time %s–%s (%d/%d), %.0f%% at other times (%d/%d)\n",
+			p.TaskName, p.Rate()*100, p.Start, p.End, p.Done, p.Samples, p.OtherRate()*100, p.OtherDone, p.OtherSamples)
+	}
+
+	fmt.Println()
+	if settings.AdaptivePlacement {
+		fmt.Printf("✅ Adaptive placement is on: new plans place these tasks at these times when they fit, learning from the past %d days.\n", autoplan.PlacementLookbackDays)
+	} else {
+		fmt.Println("Adaptive placement is off. Turn it on with 'daylit settings --adaptive-placement=true' to place these tasks at these times in new plans.")
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
 )
 
@@ -107,7 +108,7 @@ func (c *OTSetCmd) Run(ctx *cli.Context) error {
 	// Determine the date
 	day := c.Day
 	if day == "" {
-		day = time.Now().Format("2006-01-02")
+		day = ctx.Now().Format("2006-01-02")
 	} else {
 		// Validate date format
 		if _, err := time.Parse("2006-01-02", day); err != nil {
@@ -121,7 +122,7 @@ func (c *OTSetCmd) Run(ctx *cli.Context) error {
 		// Update existing entry
 		existingEntry.Title = c.Title
 		existingEntry.Note = c.Note
-		existingEntry.UpdatedAt = time.Now()
+		existingEntry.UpdatedAt = ctx.Now()
 		if err := ctx.Store.UpdateOTEntry(existingEntry); err != nil {
 			return err
 		}
@@ -135,8 +136,8 @@ func (c *OTSetCmd) Run(ctx *cli.Context) error {
 		Day:       day,
 		Title:     c.Title,
 		Note:      c.Note,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CreatedAt: ctx.Now(),
+		UpdatedAt: ctx.Now(),
 	}
 
 	if err := ctx.Store.AddOTEntry(entry); err != nil {
@@ -156,7 +157,7 @@ type OTShowCmd struct {
 func (c *OTShowCmd) Run(ctx *cli.Context) error {
 	if c.Days > 0 {
 		// Show last N days
-		endDay := time.Now()
+		endDay := ctx.Now()
 		if c.Day != "" {
 			var err error
 			endDay, err = time.Parse("2006-01-02", c.Day)
@@ -199,7 +200,7 @@ func (c *OTShowCmd) Run(ctx *cli.Context) error {
 	// Show single day
 	day := c.Day
 	if day == "" {
-		day = time.Now().Format("2006-01-02")
+		day = ctx.Now().Format("2006-01-02")
 	} else {
 		// Validate date format
 		if _, err := time.Parse("2006-01-02", day); err != nil {
@@ -228,7 +229,7 @@ func (c *OTShowCmd) Run(ctx *cli.Context) error {
 type OTNudgeCmd struct{}
 
 func (c *OTNudgeCmd) Run(ctx *cli.Context) error {
-	today := time.Now().Format("2006-01-02")
+	today := ctx.Now().Format("2006-01-02")
 	entry, err := ctx.Store.GetOTEntry(today)
 	if err == nil {
 		// OT exists for today
@@ -274,7 +275,7 @@ func (c *OTDoctorCmd) Run(ctx *cli.Context) error {
 	}
 
 	// Check 2: Check for invalid dates
-	sqliteStore, ok := ctx.Store.(*sqlite.Store)
+	sqliteStore, ok := storage.Unwrap(ctx.Store).(*sqlite.Store)
 	if !ok {
 		fmt.Println("⊘ Date validation: SKIPPED (not SQLite)")
 	} else {
@@ -298,7 +299,7 @@ func (c *OTDoctorCmd) Run(ctx *cli.Context) error {
 	}
 
 	// Check 3: Check for duplicate days
-	if sqliteStore, ok := ctx.Store.(*sqlite.Store); ok {
+	if sqliteStore, ok := storage.Unwrap(ctx.Store).(*sqlite.Store); ok {
 		db := sqliteStore.GetDB()
 		var duplicateCount int
 		err := db.QueryRow(`
@@ -325,7 +326,7 @@ func (c *OTDoctorCmd) Run(ctx *cli.Context) error {
 	}
 
 	// Check 4: Check for corrupted timestamps
-	if sqliteStore, ok := ctx.Store.(*sqlite.Store); ok {
+	if sqliteStore, ok := storage.Unwrap(ctx.Store).(*sqlite.Store); ok {
 		db := sqliteStore.GetDB()
 		var corruptedCount int
 		err := db.QueryRow(`
@@ -362,7 +363,7 @@ type OTDeleteCmd struct {
 func (c *OTDeleteCmd) Run(ctx *cli.Context) error {
 	day := c.Day
 	if day == "" {
-		day = time.Now().Format("2006-01-02")
+		day = ctx.Now().Format("2006-01-02")
 	} else {
 		// Validate date format
 		if _, err := time.Parse("2006-01-02", day); err != nil {
@@ -386,7 +387,7 @@ type OTRestoreCmd struct {
 func (c *OTRestoreCmd) Run(ctx *cli.Context) error {
 	day := c.Day
 	if day == "" {
-		day = time.Now().Format("2006-01-02")
+		day = ctx.Now().Format("2006-01-02")
 	} else {
 		// Validate date format
 		if _, err := time.Parse("2006-01-02", day); err != nil {
@@ -404,7 +405,7 @@ func (c *OTRestoreCmd) Run(ctx *cli.Context) error {
 
 // Ensure storage is SQLite for OT commands
 func ensureSQLiteStoreOT(ctx *cli.Context) error {
-	if _, ok := ctx.Store.(*sqlite.Store); !ok {
+	if _, ok := storage.Unwrap(ctx.Store).(*sqlite.Store); !ok {
 		return fmt.Errorf("OT is only supported with SQLite storage (not JSON)")
 	}
 	return nil
@@ -0,0 +1,181 @@
+package plans
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+// SlotLine is the data --format templates of 'next' and 'agenda' are
+// rendered with
+type SlotLine struct {
+	Start    string // Planned start, HH:MM
+	End      string // Planned end, HH:MM
+	Name     string // Task name
+	Status   string // planned, accepted, done or skipped
+	Duration string // Planned length, e.g. "45m" or "1h30m"
+	In       string // Time until the slot starts; empty once it has started
+	Left     string // Time until the slot ends; empty once it has ended
+	Current  bool   // The slot covers the current time
+}
+
+type NextCmd struct {
+	Format string `short:"f" help:"Go template for the output; fields: Start, End, Name, Status, Duration, In, Left." default:"{{.Start}} {{.Name}} in {{.In}}"`
+	Empty  string `help:"Text to print when no slot is left today."`
+}
+
+func (c *NextCmd) Run(ctx *cli.Context) error {
+	tmpl, err := parseLineFormat(c.Format)
+	if err != nil {
+		return err
+	}
+	now := ctx.Now()
+	window, err := dayWindow(ctx)
+	if err != nil {
+		return err
+	}
+
+	plan, _, hasPlan := currentSlot(ctx, now, window)
+	i := -1
+	minutes := 0
+	if hasPlan {
+		minutes = minutesOnPlan(plan, now)
+		i = nextSlot(plan, minutes, window)
+	}
+	if i < 0 {
+		if c.Empty != "" {
+			fmt.Println(c.Empty)
+		}
+		return nil
+	}
+
+	names, err := taskNames(ctx)
+	if err != nil {
+		return err
+	}
+	line, err := renderLine(tmpl, slotLine(plan.Slots[i], minutes, window, names))
+	if err != nil {
+		return err
+	}
+	fmt.Println(line)
+	return nil
+}
+
+type AgendaCmd struct {
+	Format    string `short:"f" help:"Go template for each slot; fields: Start, End, Name, Status, Duration, In, Left, Current." default:"{{.Start}}-{{.End}} {{.Name}} [{{.Status}}]"`
+	Separator string `short:"s" help:"Text between slots, e.g. ' | ' for a single line (default: a new line)."`
+	All       bool   `short:"a" help:"Include the slots that have already ended."`
+	Empty     string `help:"Text to print when no slot is left today."`
+}
+
+func (c *AgendaCmd) Run(ctx *cli.Context) error {
+	tmpl, err := parseLineFormat(c.Format)
+	if err != nil {
+		return err
+	}
+	now := ctx.Now()
+	window, err := dayWindow(ctx)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	plan, _, hasPlan := currentSlot(ctx, now, window)
+	if hasPlan {
+		names, err := taskNames(ctx)
+		if err != nil {
+			return err
+		}
+		minutes := minutesOnPlan(plan, now)
+		slots := make([]models.Slot, 0, len(plan.Slots))
+		for _, slot := range plan.Slots {
+			if slot.DeletedAt == nil {
+				slots = append(slots, slot)
+			}
+		}
+		window.SortSlots(slots)
+		for _, slot := range slots {
+			data := slotLine(slot, minutes, window, names)
+			if !c.All && data.Left == "" {
+				continue
+			}
+			line, err := renderLine(tmpl, data)
+			if err != nil {
+				return err
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) == 0 {
+		if c.Empty != "" {
+			fmt.Println(c.Empty)
+		}
+		return nil
+	}
+	separator := c.Separator
+	if separator == "" {
+		separator = "\n"
+	}
+	fmt.Println(strings.Join(lines, separator))
+	return nil
+}
+
+func parseLineFormat(format string) (*template.Template, error) {
+	tmpl, err := template.New("format").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format: %w", err)
+	}
+	return tmpl, nil
+}
+
+func renderLine(tmpl *template.Template, data SlotLine) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("invalid format: %w", err)
+	}
+	return b.String(), nil
+}
+
+// slotLine describes slot as seen at minute minutes of the plan day
+func slotLine(slot models.Slot, minutes int, window models.DayWindow, names map[string]string) SlotLine {
+	line := SlotLine{
+		Start:  slot.Start,
+		End:    slot.End,
+		Name:   names[slot.TaskID],
+		Status: string(slot.Status),
+	}
+	if line.Name == "" {
+		line.Name = "Unknown task"
+	}
+	start, end, err := window.Range(slot.Start, slot.End)
+	if err != nil {
+		return line
+	}
+	line.Duration = utils.FormatMinutes(end - start)
+	if start > minutes {
+		line.In = utils.FormatMinutes(start - minutes)
+	}
+	if end > minutes {
+		line.Left = utils.FormatMinutes(end - minutes)
+	}
+	line.Current = start <= minutes && minutes < end
+	return line
+}
+
+// taskNames maps task IDs to names, deleted tasks included
+func taskNames(ctx *cli.Context) (map[string]string, error) {
+	tasks, err := ctx.Store.GetAllTasksIncludingDeleted()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	names := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		names[t.ID] = t.Name
+	}
+	return names, nil
+}
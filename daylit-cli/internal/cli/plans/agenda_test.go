@@ -0,0 +1,58 @@
+package plans
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestSlotLine(t *testing.T) {
+	names := map[string]string{"write": "Write"}
+	slot := models.Slot{Start: "09:00", End: "10:30", TaskID: "write", Status: constants.SlotStatusAccepted}
+
+	tests := []struct {
+		name    string
+		minutes int
+		want    SlotLine
+	}{
+		{name: "upcoming", minutes: 8*60 + 15, want: SlotLine{Start: "09:00", End: "10:30", Name: "Write", Status: "accepted", Duration: "1h30m", In: "45m", Left: "2h15m"}},
+		{name: "current", minutes: 9*60 + 5, want: SlotLine{Start: "09:00", End: "10:30", Name: "Write", Status: "accepted", Duration: "1h30m", Left: "1h25m", Current: true}},
+		{name: "ended", minutes: 11 * 60, want: SlotLine{Start: "09:00", End: "10:30", Name: "Write", Status: "accepted", Duration: "1h30m"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slotLine(slot, tt.minutes, models.DayWindow{}, names); got != tt.want {
+				t.Errorf("slotLine() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	unknown := slotLine(models.Slot{Start: "09:00", End: "10:00", TaskID: "gone"}, 0, models.DayWindow{}, names)
+	if unknown.Name != "Unknown task" {
+		t.Errorf("Name = %q, want %q", unknown.Name, "Unknown task")
+	}
+}
+
+func TestRenderLine(t *testing.T) {
+	data := SlotLine{Start: "09:00", End: "10:00", Name: "Write", Status: "accepted", In: "55m"}
+
+	tmpl, err := parseLineFormat("{{.Start}} {{.Name}} in {{.In}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := renderLine(tmpl, data); got != "09:00 Write in 55m" {
+		t.Errorf("renderLine() = %q", got)
+	}
+
+	if _, err := parseLineFormat("{{.Start"); err == nil {
+		t.Error("expected an error for an unterminated action")
+	}
+	tmpl, err = parseLineFormat("{{.Missing}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := renderLine(tmpl, data); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
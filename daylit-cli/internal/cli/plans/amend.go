@@ -0,0 +1,188 @@
+package plans
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+type AmendCmd struct {
+	Add    AmendAddCmd    `cmd:"" help:"Add a slot to an accepted plan."`
+	Move   AmendMoveCmd   `cmd:"" help:"Move a slot of an accepted plan to a new time."`
+	Remove AmendRemoveCmd `cmd:"" help:"Remove a slot from an accepted plan."`
+	Log    AmendLogCmd    `cmd:"" help:"Show the amendments made to a day's plan." default:"1"`
+}
+
+type AmendAddCmd struct {
+	Task   string `arg:"" help:"Task ID or name."`
+	Start  string `arg:"" help:"Slot start time (HH:MM)."`
+	End    string `arg:"" help:"Slot end time (HH:MM)."`
+	Date   string `help:"Date of the plan (YYYY-MM-DD or 'today')." default:"today"`
+	Reason string `short:"r" help:"Why the plan changed."`
+}
+
+func (c *AmendAddCmd) Run(ctx *cli.Context) error {
+	plan, err := acceptedPlan(ctx, c.Date)
+	if err != nil {
+		return err
+	}
+	task, err := ctx.ResolveTask(c.Task, cli.LiveTasks)
+	if err != nil {
+		return err
+	}
+	return amend(ctx, task.Name, models.PlanAmendment{
+		Date:     plan.Date,
+		Revision: plan.Revision,
+		Kind:     models.AmendmentAdd,
+		TaskID:   task.ID,
+		ToStart:  c.Start,
+		ToEnd:    c.End,
+		Reason:   strings.TrimSpace(c.Reason),
+	})
+}
+
+type AmendMoveCmd struct {
+	Slot   string `arg:"" help:"Slot start time (HH:MM) or task name."`
+	To     string `arg:"" help:"New start time (HH:MM)."`
+	End    string `help:"New end time (HH:MM). Defaults to keeping the slot's length."`
+	Date   string `help:"Date of the plan (YYYY-MM-DD or 'today')." default:"today"`
+	Reason string `short:"r" help:"Why the plan changed."`
+}
+
+func (c *AmendMoveCmd) Run(ctx *cli.Context) error {
+	plan, err := acceptedPlan(ctx, c.Date)
+	if err != nil {
+		return err
+	}
+	i, err := findSlot(ctx, plan, c.Slot)
+	if err != nil {
+		return err
+	}
+	slot := plan.Slots[i]
+
+	end := c.End
+	if end == "" {
+		if end, err = keepLength(slot, c.To); err != nil {
+			return err
+		}
+	}
+	return amend(ctx, taskName(ctx, slot.TaskID), models.PlanAmendment{
+		Date:      plan.Date,
+		Revision:  plan.Revision,
+		Kind:      models.AmendmentMove,
+		TaskID:    slot.TaskID,
+		FromStart: slot.Start,
+		ToStart:   c.To,
+		ToEnd:     end,
+		Reason:    strings.TrimSpace(c.Reason),
+	})
+}
+
+type AmendRemoveCmd struct {
+	Slot   string `arg:"" help:"Slot start time (HH:MM) or task name."`
+	Date   string `help:"Date of the plan (YYYY-MM-DD or 'today')." default:"today"`
+	Reason string `short:"r" help:"Why the plan changed."`
+}
+
+func (c *AmendRemoveCmd) Run(ctx *cli.Context) error {
+	plan, err := acceptedPlan(ctx, c.Date)
+	if err != nil {
+		return err
+	}
+	i, err := findSlot(ctx, plan, c.Slot)
+	if err != nil {
+		return err
+	}
+	slot := plan.Slots[i]
+
+	return amend(ctx, taskName(ctx, slot.TaskID), models.PlanAmendment{
+		Date:      plan.Date,
+		Revision:  plan.Revision,
+		Kind:      models.AmendmentRemove,
+		TaskID:    slot.TaskID,
+		FromStart: slot.Start,
+		Reason:    strings.TrimSpace(c.Reason),
+	})
+}
+
+type AmendLogCmd struct {
+	Date string `help:"Date of the plan (YYYY-MM-DD or 'today')." default:"today"`
+}
+
+func (c *AmendLogCmd) Run(ctx *cli.Context) error {
+	date, err := parsePlanDate(c.Date, ctx.Now())
+	if err != nil {
+		return err
+	}
+	amendments, err := ctx.Store.GetPlanAmendments(date)
+	if err != nil {
+		return fmt.Errorf("failed to get amendments: %w", err)
+	}
+	if len(amendments) == 0 {
+		fmt.Printf("No amendments to the plan for %s.\n", date)
+		return nil
+	}
+
+	fmt.Printf("Amendments to the plan for %s:\n", date)
+	for _, a := range amendments {
+		line := fmt.Sprintf("  %s  rev %d  %s", a.CreatedAt.Local().Format("15:04"), a.Revision, a.Describe(taskName(ctx, a.TaskID)))
+		if a.Reason != "" {
+			line += " — " + a.Reason
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// acceptedPlan returns the latest plan for the date, which must be accepted
+// to be amended
+func acceptedPlan(ctx *cli.Context, value string) (models.DayPlan, error) {
+	date, err := parsePlanDate(value, ctx.Now())
+	if err != nil {
+		return models.DayPlan{}, err
+	}
+	plan, err := ctx.Store.GetPlan(date)
+	if err != nil {
+		return models.DayPlan{}, fmt.Errorf("no plan found for %s", date)
+	}
+	if plan.AcceptedAt == nil {
+		return models.DayPlan{}, fmt.Errorf("the plan for %s isn't accepted yet; regenerate it with 'daylit plan %s' instead", date, date)
+	}
+	return plan, nil
+}
+
+func amend(ctx *cli.Context, name string, a models.PlanAmendment) error {
+	a.CreatedAt = ctx.Now()
+	a, err := ctx.Store.AmendPlan(a)
+	if err != nil {
+		return fmt.Errorf("failed to amend plan: %w", err)
+	}
+	fmt.Printf("Amended the plan for %s: %s\n", a.Date, a.Describe(name))
+	return nil
+}
+
+// keepLength returns the end time for the slot when it starts at start
+// instead, keeping its length
+func keepLength(slot models.Slot, start string) (string, error) {
+	from, err := time.Parse(constants.TimeFormat, slot.Start)
+	if err != nil {
+		return "", err
+	}
+	until, err := time.Parse(constants.TimeFormat, slot.End)
+	if err != nil {
+		return "", err
+	}
+	to, err := time.Parse(constants.TimeFormat, start)
+	if err != nil {
+		return "", fmt.Errorf("invalid time %q (expected HH:MM)", start)
+	}
+	end := to.Add(until.Sub(from))
+	if end.Day() != to.Day() {
+		return "", fmt.Errorf("slot would run past midnight; give --end instead")
+	}
+	return end.Format(constants.TimeFormat), nil
+}
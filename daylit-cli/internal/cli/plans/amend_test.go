@@ -0,0 +1,60 @@
+package plans
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestAmendCmds(t *testing.T) {
+	ctx := setupDoneTest(t, false)
+	const date = "2025-06-02"
+
+	if err := (&AmendMoveCmd{Slot: "Email", To: "14:00", Date: date, Reason: "call ran over"}).Run(ctx); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	if err := (&AmendMoveCmd{Slot: "12:00", To: "15:00", End: "16:00", Date: date}).Run(ctx); err != nil {
+		t.Fatalf("move with end failed: %v", err)
+	}
+	if err := (&AmendAddCmd{Task: "Email", Start: "16:00", End: "16:15", Date: date}).Run(ctx); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if err := (&AmendRemoveCmd{Slot: "Read", Date: date}).Run(ctx); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+
+	want := "09:00-10:00 write, 11:00-12:00 lunch, 14:00-14:30 email, 15:00-16:00 walk, 16:00-16:15 email"
+	if got := planSummary(t, ctx); got != want {
+		t.Errorf("slots = %s\nwant    %s", got, want)
+	}
+	if plan, _ := ctx.Store.GetPlan(date); plan.Revision != 1 || plan.AcceptedAt == nil {
+		t.Errorf("expected accepted revision 1 to be amended in place, got revision %d", plan.Revision)
+	}
+
+	amendments, err := ctx.Store.GetPlanAmendments(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(amendments) != 4 || amendments[0].Kind != models.AmendmentMove || amendments[0].Reason != "call ran over" {
+		t.Fatalf("unexpected amendments: %+v", amendments)
+	}
+	if err := (&AmendLogCmd{Date: date}).Run(ctx); err != nil {
+		t.Errorf("log failed: %v", err)
+	}
+
+	// Email now has two slots, so it must be named by start time
+	if err := (&AmendRemoveCmd{Slot: "Email", Date: date}).Run(ctx); err == nil {
+		t.Error("expected an ambiguous slot to be refused")
+	}
+	if err := (&AmendMoveCmd{Slot: "Lunch", To: "23:30", Date: date}).Run(ctx); err == nil {
+		t.Error("expected a move past midnight to be refused")
+	}
+
+	// Unaccepted plans are regenerated, not amended
+	if err := ctx.Store.SavePlan(models.DayPlan{Date: "2025-06-03", Slots: []models.Slot{{Start: "09:00", End: "10:00", TaskID: "write"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&AmendRemoveCmd{Slot: "09:00", Date: "2025-06-03"}).Run(ctx); err == nil {
+		t.Error("expected amending an unaccepted plan to fail")
+	}
+}
@@ -2,21 +2,32 @@ package plans
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/julianstephens/daylit/daylit-cli/internal/autoplan"
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/i18n"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
 )
 
 type DayCmd struct {
-	Date string `arg:"" help:"Date to show (YYYY-MM-DD or 'today')." default:"today"`
+	Show DayShowCmd `cmd:"" default:"withargs" help:"Show the plan for a day."`
+	Note DayNoteCmd `cmd:"" help:"Show or set the notes for a day's plan."`
 }
 
-func (c *DayCmd) Run(ctx *cli.Context) error {
+type DayShowCmd struct {
+	Date    string `arg:"" help:"Date to show (YYYY-MM-DD or 'today')." default:"today"`
+	Actuals bool   `help:"Show the planned times next to the tracked start and stop times, with drift."`
+}
+
+func (c *DayShowCmd) Run(ctx *cli.Context) error {
 	// Parse date
 	var planDate time.Time
 	if c.Date == "today" {
-		planDate = time.Now()
+		planDate = ctx.Now()
 	} else {
 		var err error
 		planDate, err = time.Parse("2006-01-02", c.Date)
@@ -32,42 +43,198 @@ func (c *DayCmd) Run(ctx *cli.Context) error {
 		return fmt.Errorf("no plan found for %s", dateStr)
 	}
 
-	fmt.Printf("Plan for %s (Rev %d):\n\n", dateStr, plan.Revision)
+	fmt.Printf("%s\n\n", i18n.T("day.header", dateStr, plan.Revision))
+	if plan.LockedUntil != "" {
+		fmt.Printf("%s\n\n", i18n.T("day.locked", plan.LockedUntil))
+	}
+	if plan.Note != "" {
+		fmt.Printf("%s\n%s\n\n", i18n.T("day.notes"), indent(plan.Note, "  "))
+	}
 
 	if len(plan.Slots) == 0 {
-		fmt.Println("  No slots scheduled")
-		return nil
+		fmt.Println("  " + i18n.T("day.no_slots"))
+	}
+	if c.Actuals {
+		return printActuals(ctx, plan)
 	}
 
 	for _, slot := range plan.Slots {
 		task, err := ctx.Store.GetTask(slot.TaskID)
-		taskName := "unknown task"
+		taskName := i18n.T("day.unknown_task")
 		if err == nil {
 			taskName = task.Name
 		}
 
 		statusStr := ""
 		switch slot.Status {
-		case constants.SlotStatusPlanned:
-			statusStr = "[planned]"
-		case constants.SlotStatusAccepted:
-			statusStr = "[accepted]"
+		case constants.SlotStatusPlanned, constants.SlotStatusAccepted, constants.SlotStatusSkipped:
+			statusStr = fmt.Sprintf("[%s]", i18n.T("status."+string(slot.Status)))
 		case constants.SlotStatusDone:
 			if slot.Feedback != nil {
-				statusStr = fmt.Sprintf("[done, %s]", slot.Feedback.Rating)
+				statusStr = fmt.Sprintf("[%s, %s]", i18n.T("status.done"), i18n.T("rating."+string(slot.Feedback.Rating)))
 			} else {
-				statusStr = "[done]"
+				statusStr = fmt.Sprintf("[%s]", i18n.T("status.done"))
 			}
-		case constants.SlotStatusSkipped:
-			statusStr = "[skipped]"
 		}
 
 		fmt.Printf("%s–%s  %-30s  %s\n", slot.Start, slot.End, taskName, statusStr)
 
 		if slot.Feedback != nil && slot.Feedback.Note != "" {
-			fmt.Printf("            Note: %s\n", slot.Feedback.Note)
+			fmt.Printf("            %s\n", i18n.T("day.note", slot.Feedback.Note))
+		}
+	}
+
+	return printOverflow(ctx, plan, planDate)
+}
+
+// printActuals lists the plan's slots with their tracked start and stop times
+// and how far each drifted from the plan, followed by the day's total drift
+func printActuals(ctx *cli.Context, plan models.DayPlan) error {
+	if len(plan.Slots) == 0 {
+		return nil
+	}
+	window, err := dayWindow(ctx)
+	if err != nil {
+		return err
+	}
+
+	tracked := 0
+	fmt.Printf("%-11s  %-11s  %-30s  %s\n", "Planned", "Actual", "Task", "Drift")
+	for _, slot := range plan.Slots {
+		taskName := "unknown task"
+		if task, err := ctx.Store.GetTask(slot.TaskID); err == nil {
+			taskName = task.Name
+		}
+		drift := window.Drift(slot)
+		if drift.Tracked() {
+			tracked++
+		}
+		fmt.Printf("%s–%s  %-11s  %-30s  %s\n", slot.Start, slot.End, actualRange(slot), taskName, actualDriftText(slot, drift))
+	}
+
+	if tracked == 0 {
+		fmt.Println("\nNo slots were tracked; use 'daylit start' and 'daylit stop' to record actual times.")
+		return nil
+	}
+	fmt.Printf("\nTotal drift: %dm across %d tracked slot(s)\n", window.TotalDrift(plan.Slots), tracked)
+	return nil
+}
+
+// actualRange formats the tracked start and end of slot, with "?" for a
+// missing start and "…" for a slot that is still running
+func actualRange(slot models.Slot) string {
+	start, end := "?", "…"
+	if slot.ActualStart != nil {
+		start = *slot.ActualStart
+	}
+	if slot.ActualEnd != nil {
+		end = *slot.ActualEnd
+	}
+	if slot.ActualStart == nil && slot.ActualEnd == nil {
+		return "-"
+	}
+	return start + "–" + end
+}
+
+// actualDriftText describes how far slot's tracked times drifted from its plan
+func actualDriftText(slot models.Slot, drift models.SlotDrift) string {
+	var parts []string
+	if drift.HasStart {
+		parts = append(parts, "started "+driftText(drift.Start))
+	}
+	if drift.HasEnd {
+		parts = append(parts, "ended "+driftText(drift.End))
+	}
+	if len(parts) == 0 {
+		if slot.Status == constants.SlotStatusSkipped {
+			return "skipped"
+		}
+		return "not tracked"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printOverflow lists the nice-to-have tasks due on date that the plan left
+// out, if there are any
+func printOverflow(ctx *cli.Context, plan models.DayPlan, date time.Time) error {
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	candidates, err := autoplan.Candidates(ctx.Store, tasks, settings, date.Format(constants.DateFormat))
+	if err != nil {
+		return err
+	}
+
+	overflow := scheduler.Overflow(plan, candidates, date)
+	if len(overflow) == 0 {
+		return nil
+	}
+	fmt.Println("\nDidn't make the cut (nice to have):")
+	for _, task := range overflow {
+		fmt.Printf("  - %s (%dm)\n", task.Name, task.DurationMin)
+	}
+	return nil
+}
+
+type DayNoteCmd struct {
+	Text   string `arg:"" optional:"" help:"Note text. Omit to show the current note."`
+	Date   string `help:"Date of the plan (YYYY-MM-DD or 'today')." default:"today"`
+	Append bool   `short:"a" help:"Add the text as a new line instead of replacing the note."`
+	Clear  bool   `help:"Remove the note."`
+}
+
+func (c *DayNoteCmd) Run(ctx *cli.Context) error {
+	dateStr, err := parsePlanDate(c.Date, ctx.Now())
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSpace(c.Text)
+	if c.Clear && text != "" {
+		return fmt.Errorf("--clear can't be combined with note text")
+	}
+
+	// The note lives on the latest revision of the day's plan. A day without a
+	// plan gets an empty one, which 'daylit plan' fills in later.
+	plan, err := ctx.Store.GetPlan(dateStr)
+	if err != nil {
+		plan = models.DayPlan{Date: dateStr}
+	}
+
+	if text == "" && !c.Clear {
+		if plan.Note == "" {
+			fmt.Printf("No notes for %s\n", dateStr)
+			return nil
 		}
+		fmt.Println(plan.Note)
+		return nil
+	}
+
+	switch {
+	case c.Clear:
+		plan.Note = ""
+	case c.Append && plan.Note != "":
+		plan.Note += "\n" + text
+	default:
+		plan.Note = text
+	}
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		return fmt.Errorf("failed to save note: %w", err)
 	}
 
+	if c.Clear {
+		fmt.Printf("Cleared the notes for %s\n", dateStr)
+	} else {
+		fmt.Printf("Saved the notes for %s\n", dateStr)
+	}
 	return nil
 }
+
+// indent prefixes every line of s with prefix
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
@@ -0,0 +1,184 @@
+package plans
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/hooks"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+)
+
+type DoneCmd struct {
+	Note string `help:"Optional feedback note."`
+}
+
+func (c *DoneCmd) Run(ctx *cli.Context) error {
+	return c.finish(ctx, ctx.Now())
+}
+
+// finish marks the running slot, or else the slot that covers now, as done
+// with on-track feedback and records now as its actual end
+func (c *DoneCmd) finish(ctx *cli.Context, now time.Time) error {
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+	window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+	if err != nil {
+		return err
+	}
+
+	plan, i, hasPlan := currentSlot(ctx, now, window)
+	if !hasPlan {
+		return fmt.Errorf("no active plan for today")
+	}
+	if j := runningSlot(plan); j >= 0 {
+		i = j
+	}
+	if i < 0 {
+		return fmt.Errorf("no slot is in progress (see 'daylit now')")
+	}
+	slot := &plan.Slots[i]
+	if slot.Feedback != nil {
+		return fmt.Errorf("%s–%s already has feedback", slot.Start, slot.End)
+	}
+	if slot.ActualEnd != nil {
+		return fmt.Errorf("%s–%s was already finished at %s; use 'daylit feedback' to rate it", slot.Start, slot.End, *slot.ActualEnd)
+	}
+
+	start, end, err := window.Range(slot.Start, slot.End)
+	if err != nil {
+		return err
+	}
+	finished := minutesOnPlan(plan, now)
+	if slot.ActualStart != nil {
+		if actualStart, err := window.Minutes(*slot.ActualStart); err == nil {
+			start = actualStart
+		}
+	}
+
+	actualEnd := now.Format("15:04")
+	slot.Status = constants.SlotStatusDone
+	slot.Feedback = &models.Feedback{Rating: constants.FeedbackOnTrack, Note: c.Note}
+	slot.ActualEnd = &actualEnd
+
+	// Let the rest of the day start sooner when the slot ends early
+	early := end - finished
+	var moved int
+	if settings.CompressOnEarlyFinish && early > 0 {
+		tasks, err := ctx.Store.GetAllTasks()
+		if err != nil {
+			return fmt.Errorf("failed to get tasks: %w", err)
+		}
+		moved = pullChainForward(plan, i, finished, window, tasks)
+	}
+
+	// As with feedback, the task's statistics and the plan are saved together
+	taskName := "Unknown task"
+	err = ctx.InTx(func() error {
+		task, err := ctx.Store.GetTask(slot.TaskID)
+		if err == nil {
+			taskName = task.Name
+			applyFeedback(&task, constants.FeedbackOnTrack, finished-start, plan.Date)
+			if err := ctx.Store.UpdateTask(task); err != nil {
+				return fmt.Errorf("update task with feedback: %w", err)
+			}
+		}
+		return ctx.Store.SavePlan(plan)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Done: %s–%s  %s (finished at %s, %s)\n", slot.Start, slot.End, taskName, actualEnd, driftText(-early))
+	if moved > 0 {
+		fmt.Printf("Moved the next %d slot(s) up\n", moved)
+	}
+	data := hooks.Slot(ctx.Store, plan.Date, *slot)
+	hooks.Fire(hooks.SlotDone, data)
+	hooks.Fire(hooks.FeedbackRecorded, data)
+	return nil
+}
+
+// pullChainForward moves the slots that run back to back after slot i of the
+// plan up so the chain starts at from, in minutes on the plan day. The chain
+// ends at the first gap, appointment, locked slot or slot that is already
+// done, and a slot never moves before its task's earliest start or the
+// plan's lock boundary. It returns how many slots moved.
+func pullChainForward(plan models.DayPlan, i, from int, window models.DayWindow, tasks []models.Task) int {
+	slots := plan.Slots
+	taskByID := make(map[string]models.Task, len(tasks))
+	for _, task := range tasks {
+		taskByID[task.ID] = task
+	}
+
+	_, prevEnd, err := window.Range(slots[i].Start, slots[i].End)
+	if err != nil {
+		return 0
+	}
+	cursor := from
+	moved := 0
+	for j := i + 1; j < len(slots); j++ {
+		slot := &slots[j]
+		start, end, err := window.Range(slot.Start, slot.End)
+		if err != nil || start != prevEnd || slot.Status == constants.SlotStatusDone || plan.Locked(*slot, window) {
+			break
+		}
+		task := taskByID[slot.TaskID]
+		if scheduler.IsFixedAppointment(task) {
+			break
+		}
+
+		newStart := cursor
+		for _, bound := range []string{task.EarliestStart, plan.LockedUntil} {
+			if bound == "" {
+				continue
+			}
+			if earliest, err := window.Minutes(bound); err == nil && earliest > newStart {
+				newStart = earliest
+			}
+		}
+		if newStart >= start {
+			break
+		}
+
+		length := end - start
+		slot.Start = formatClock(newStart)
+		slot.End = formatClock(newStart + length)
+		moved++
+		prevEnd = end
+		cursor = newStart + length
+	}
+	return moved
+}
+
+// minutesOnPlan returns now in minutes on plan's day, past MinutesPerDay when
+// it is still yesterday's plan after midnight
+func minutesOnPlan(plan models.DayPlan, now time.Time) int {
+	minutes := now.Hour()*60 + now.Minute()
+	if plan.Date != now.Format(constants.DateFormat) {
+		minutes += models.MinutesPerDay
+	}
+	return minutes
+}
+
+// driftText describes how many minutes late (positive) or early (negative)
+// something happened
+func driftText(minutes int) string {
+	switch {
+	case minutes > 0:
+		return fmt.Sprintf("%dm late", minutes)
+	case minutes < 0:
+		return fmt.Sprintf("%dm early", -minutes)
+	}
+	return "on time"
+}
+
+// formatClock formats minutes on the plan day as an HH:MM clock time
+func formatClock(minutes int) string {
+	minutes %= models.MinutesPerDay
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,149 @@
+package plans
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+)
+
+func setupTestDB(t *testing.T) *cli.Context {
+	t.Helper()
+	store := sqlite.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return &cli.Context{Store: store, Scheduler: scheduler.New()}
+}
+
+// setupDoneTest saves an accepted plan for 2025-06-02 with a chain of three
+// slots, an appointment and one more slot after it
+func setupDoneTest(t *testing.T, compress bool) *cli.Context {
+	t.Helper()
+	ctx := setupTestDB(t)
+
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.CompressOnEarlyFinish = compress
+	if err := ctx.Store.SaveSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	for _, task := range []models.Task{
+		{ID: "write", Name: "Write", Kind: constants.TaskKindFlexible, DurationMin: 60, AvgActualDurationMin: 60, Recurrence: daily, Priority: 3, Active: true},
+		{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: daily, Priority: 3, Active: true},
+		{ID: "read", Name: "Read", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: daily, Priority: 3, Active: true},
+		{ID: "lunch", Name: "Lunch", Kind: constants.TaskKindAppointment, DurationMin: 60, FixedStart: "11:00", FixedEnd: "12:00", Recurrence: daily, Priority: 3, Active: true},
+		{ID: "walk", Name: "Walk", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: daily, Priority: 3, Active: true},
+	} {
+		if err := ctx.Store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+	}
+
+	accepted := "2025-06-02T06:00:00Z"
+	plan := models.DayPlan{Date: "2025-06-02", AcceptedAt: &accepted, Slots: []models.Slot{
+		{Start: "09:00", End: "10:00", TaskID: "write", Status: constants.SlotStatusAccepted},
+		{Start: "10:00", End: "10:30", TaskID: "email", Status: constants.SlotStatusAccepted},
+		{Start: "10:30", End: "11:00", TaskID: "read", Status: constants.SlotStatusAccepted},
+		{Start: "11:00", End: "12:00", TaskID: "lunch", Status: constants.SlotStatusAccepted},
+		{Start: "12:00", End: "12:30", TaskID: "walk", Status: constants.SlotStatusAccepted},
+	}}
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+	return ctx
+}
+
+func planSummary(t *testing.T, ctx *cli.Context) string {
+	t.Helper()
+	plan, err := ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parts []string
+	for _, slot := range plan.Slots {
+		parts = append(parts, slot.Start+"-"+slot.End+" "+slot.TaskID)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func TestDoneCmd(t *testing.T) {
+	ctx := setupDoneTest(t, true)
+
+	now := time.Date(2025, 6, 2, 9, 40, 0, 0, time.Local)
+	if err := (&DoneCmd{Note: "quick"}).finish(ctx, now); err != nil {
+		t.Fatalf("done failed: %v", err)
+	}
+
+	plan, err := ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slot := plan.Slots[0]
+	if slot.Status != constants.SlotStatusDone || slot.Feedback == nil ||
+		slot.Feedback.Rating != constants.FeedbackOnTrack || slot.Feedback.Note != "quick" {
+		t.Errorf("slot not marked done with on-track feedback: %+v", slot)
+	}
+	if slot.ActualEnd == nil || *slot.ActualEnd != "09:40" {
+		t.Errorf("actual end = %v, want 09:40", slot.ActualEnd)
+	}
+
+	// The chain up to the appointment moves up by 20 minutes
+	want := "09:00-10:00 write, 09:40-10:10 email, 10:10-10:40 read, 11:00-12:00 lunch, 12:00-12:30 walk"
+	if got := planSummary(t, ctx); got != want {
+		t.Errorf("slots = %s\nwant    %s", got, want)
+	}
+
+	task, err := ctx.Store.GetTask("write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantAvg := 60*constants.FeedbackExistingWeight + 40*constants.FeedbackNewWeight
+	if task.AvgActualDurationMin != wantAvg || task.LastDone != "2025-06-02" {
+		t.Errorf("task stats = avg %v last done %s, want avg %v", task.AvgActualDurationMin, task.LastDone, wantAvg)
+	}
+
+	history, err := ctx.Store.GetTaskFeedbackHistory("write", 1)
+	if err != nil || len(history) != 1 || history[0].ActualDuration != 40 {
+		t.Errorf("feedback history should use the actual end, got %+v, %v", history, err)
+	}
+
+	// The slot already has feedback now
+	if err := (&DoneCmd{}).finish(ctx, now); err == nil {
+		t.Error("expected error for a slot that already has feedback")
+	}
+}
+
+func TestDoneCmd_WithoutCompression(t *testing.T) {
+	ctx := setupDoneTest(t, false)
+
+	if err := (&DoneCmd{}).finish(ctx, time.Date(2025, 6, 2, 10, 10, 0, 0, time.Local)); err != nil {
+		t.Fatalf("done failed: %v", err)
+	}
+	want := "09:00-10:00 write, 10:00-10:30 email, 10:30-11:00 read, 11:00-12:00 lunch, 12:00-12:30 walk"
+	if got := planSummary(t, ctx); got != want {
+		t.Errorf("slots = %s\nwant    %s", got, want)
+	}
+}
+
+func TestDoneCmd_NoCurrentSlot(t *testing.T) {
+	ctx := setupDoneTest(t, true)
+
+	if err := (&DoneCmd{}).finish(ctx, time.Date(2025, 6, 2, 8, 0, 0, 0, time.Local)); err == nil {
+		t.Error("expected error when no slot is in progress")
+	}
+	if err := (&DoneCmd{}).finish(ctx, time.Date(2025, 6, 3, 9, 30, 0, 0, time.Local)); err == nil {
+		t.Error("expected error when there is no plan")
+	}
+}
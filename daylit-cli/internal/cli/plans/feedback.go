@@ -2,17 +2,18 @@ package plans
 
 import (
 	"fmt"
-	"time"
+	"math"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/hooks"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
-	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
 )
 
 type FeedbackCmd struct {
 	Rating string `help:"Rating (on_track|too_much|unnecessary)." required:""`
 	Note   string `help:"Optional note."`
+	Date   string `help:"Day of the plan in YYYY-MM-DD format, to give feedback for a past day (default: today)."`
 }
 
 func (c *FeedbackCmd) Run(ctx *cli.Context) error {
@@ -33,13 +34,31 @@ func (c *FeedbackCmd) Run(ctx *cli.Context) error {
 		return fmt.Errorf("invalid rating: %s (use on_track, too_much, or unnecessary)", c.Rating)
 	}
 
-	now := time.Now()
-	dateStr := now.Format("2006-01-02")
+	dateStr, err := ctx.PastDate(c.Date)
+	if err != nil {
+		return err
+	}
+	now := ctx.Now()
 	currentMinutes := now.Hour()*60 + now.Minute()
+	if dateStr != now.Format(constants.DateFormat) {
+		// Every slot of an earlier day is over
+		currentMinutes = math.MaxInt
+	}
 
 	plan, err := ctx.Store.GetPlan(dateStr)
 	if err != nil {
-		return fmt.Errorf("no plan found for today")
+		if c.Date == "" {
+			return fmt.Errorf("no plan found for today")
+		}
+		return fmt.Errorf("no plan found for %s", dateStr)
+	}
+
+	// Without valid day boundaries, slots are read as plain clock times
+	var window models.DayWindow
+	if settings, err := ctx.Store.GetSettings(); err == nil {
+		if w, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd); err == nil {
+			window = w
+		}
 	}
 
 	// Find the most recent past slot without feedback
@@ -49,9 +68,9 @@ func (c *FeedbackCmd) Run(ctx *cli.Context) error {
 		slot := &plan.Slots[i]
 		if (slot.Status == constants.SlotStatusAccepted || slot.Status == constants.SlotStatusDone) &&
 			slot.Feedback == nil {
-			endMinutes, err := utils.ParseTimeToMinutes(slot.End)
+			_, endMinutes, err := window.Range(slot.Start, slot.End)
 			if err != nil {
-				// Skip slots with invalid end time format
+				// Skip slots with invalid times
 				continue
 			}
 			if endMinutes <= currentMinutes {
@@ -66,57 +85,78 @@ func (c *FeedbackCmd) Run(ctx *cli.Context) error {
 	}
 
 	// Add feedback
+	wasDone := plan.Slots[targetSlotIdx].Status == constants.SlotStatusDone
 	plan.Slots[targetSlotIdx].Feedback = &models.Feedback{
 		Rating: rating,
 		Note:   c.Note,
 	}
 	plan.Slots[targetSlotIdx].Status = constants.SlotStatusDone
 
-	// Update task statistics
-	task, err := ctx.Store.GetTask(plan.Slots[targetSlotIdx].TaskID)
-	if err == nil {
-		switch rating {
-		case constants.FeedbackOnTrack:
-			// Keep duration as is, nudge slightly toward actual
-			slotDuration := cli.CalculateSlotDuration(plan.Slots[targetSlotIdx])
-			if slotDuration > 0 {
-				if task.AvgActualDurationMin <= 0 {
-					// Initialize average if it was unset or invalid
-					task.AvgActualDurationMin = float64(slotDuration)
-				} else {
-					task.AvgActualDurationMin = task.AvgActualDurationMin*constants.FeedbackExistingWeight + float64(slotDuration)*constants.FeedbackNewWeight
-				}
-			}
-			task.LastDone = dateStr
-		case constants.FeedbackTooMuch:
-			// Reduce duration slightly
-			task.DurationMin = int(float64(task.DurationMin) * constants.FeedbackTooMuchReductionFactor)
-			if task.DurationMin < constants.MinTaskDurationMin {
-				task.DurationMin = constants.MinTaskDurationMin
-			}
-			task.LastDone = dateStr
-		case constants.FeedbackUnnecessary:
-			// Increase interval or reduce priority
-			if task.Recurrence.Type == constants.RecurrenceNDays {
-				task.Recurrence.IntervalDays++
+	// Update task statistics in the same transaction as the plan, so they
+	// never count feedback the plan doesn't have
+	taskName := "Unknown task"
+	err = ctx.InTx(func() error {
+		task, err := ctx.Store.GetTask(plan.Slots[targetSlotIdx].TaskID)
+		if err == nil {
+			taskName = task.Name
+			applyFeedback(&task, rating, cli.CalculateSlotDuration(plan.Slots[targetSlotIdx]), dateStr)
+			if err := ctx.Store.UpdateTask(task); err != nil {
+				return fmt.Errorf("update task with feedback: %w", err)
 			}
 		}
-		if err := ctx.Store.UpdateTask(task); err != nil {
-			return fmt.Errorf("update task with feedback: %w", err)
-		}
-	}
-
-	if err := ctx.Store.SavePlan(plan); err != nil {
+		return ctx.Store.SavePlan(plan)
+	})
+	if err != nil {
 		return err
 	}
 
-	taskName := "Unknown task"
-	if err == nil {
-		taskName = task.Name
-	}
-
 	fmt.Printf("Feedback recorded for: %s–%s  %s\n",
 		plan.Slots[targetSlotIdx].Start, plan.Slots[targetSlotIdx].End, taskName)
 
+	data := hooks.Slot(ctx.Store, dateStr, plan.Slots[targetSlotIdx])
+	if !wasDone {
+		hooks.Fire(hooks.SlotDone, data)
+	}
+	hooks.Fire(hooks.FeedbackRecorded, data)
+
 	return nil
 }
+
+// applyFeedback updates a task's statistics for feedback given on date.
+// actualMin is how long the slot took; on-track feedback nudges the average
+// actual duration toward it.
+func applyFeedback(task *models.Task, rating models.FeedbackRating, actualMin int, date string) {
+	switch rating {
+	case constants.FeedbackOnTrack:
+		// Keep duration as is, nudge slightly toward actual
+		if actualMin > 0 {
+			if task.AvgActualDurationMin <= 0 {
+				// Initialize average if it was unset or invalid
+				task.AvgActualDurationMin = float64(actualMin)
+			} else {
+				task.AvgActualDurationMin = task.AvgActualDurationMin*constants.FeedbackExistingWeight + float64(actualMin)*constants.FeedbackNewWeight
+			}
+		}
+		setLastDone(task, date)
+	case constants.FeedbackTooMuch:
+		// Reduce duration slightly
+		task.DurationMin = int(float64(task.DurationMin) * constants.FeedbackTooMuchReductionFactor)
+		if task.DurationMin < constants.MinTaskDurationMin {
+			task.DurationMin = constants.MinTaskDurationMin
+		}
+		setLastDone(task, date)
+	case constants.FeedbackUnnecessary:
+		// Increase interval or reduce priority
+		if task.Recurrence.Type == constants.RecurrenceNDays {
+			task.Recurrence.IntervalDays++
+		}
+	}
+}
+
+// setLastDone records date as the task's last done day, unless feedback for
+// a later day was already given, as when backfilling an earlier day
+func setLastDone(task *models.Task, date string) {
+	if date > task.LastDone {
+		task.LastDone = date
+	}
+}
@@ -0,0 +1,51 @@
+package plans
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+func TestFeedbackCmd_Backfill(t *testing.T) {
+	ctx := setupDoneTest(t, false)
+	ctx.Clock = clock.Fixed(time.Date(2025, 6, 4, 8, 0, 0, 0, time.Local))
+
+	// Feedback for a later day was already given
+	walk, err := ctx.Store.GetTask("walk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	walk.LastDone = "2025-06-03"
+	if err := ctx.Store.UpdateTask(walk); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every slot of an earlier day is over, so the last one gets feedback
+	if err := (&FeedbackCmd{Rating: "on_track", Date: "2025-06-02"}).Run(ctx); err != nil {
+		t.Fatalf("feedback failed: %v", err)
+	}
+	plan, err := ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slot := plan.Slots[len(plan.Slots)-1]
+	if slot.TaskID != "walk" || slot.Status != constants.SlotStatusDone || slot.Feedback == nil {
+		t.Errorf("expected the walk slot to get feedback, got %+v", slot)
+	}
+
+	walk, err = ctx.Store.GetTask("walk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if walk.LastDone != "2025-06-03" {
+		t.Errorf("backfilling moved last done back to %s", walk.LastDone)
+	}
+
+	for _, date := range []string{"2025-06-05", "06/02/2025"} {
+		if err := (&FeedbackCmd{Rating: "on_track", Date: date}).Run(ctx); err == nil {
+			t.Errorf("expected an error for date %s", date)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package plans
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+)
+
+type FocusCmd struct {
+	Format   string        `short:"f" help:"Go template for the window name; takes the same fields as 'next'." default:"{{.Name}}"`
+	Target   string        `short:"t" help:"tmux window to rename, e.g. 'work:2' (default: the window daylit focus runs in)."`
+	Interval time.Duration `help:"How often to check for the start and end of a block." default:"15s"`
+}
+
+func (c *FocusCmd) Run(ctx *cli.Context) error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	tmpl, err := parseLineFormat(c.Format)
+	if err != nil {
+		return err
+	}
+	window, err := c.terminalWindow()
+	if err != nil {
+		return err
+	}
+	f, err := newFocuser(window)
+	if err != nil {
+		return err
+	}
+
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	fmt.Println("Renaming the window after the block in progress; press Ctrl+C to stop.")
+	for {
+		title, err := focusTitle(ctx, tmpl, ctx.Now())
+		if err == nil {
+			err = f.update(title)
+		}
+		if err != nil {
+			// Put the name back before giving up
+			if rerr := f.update(""); rerr != nil {
+				return fmt.Errorf("%w (and failed to restore the window name: %v)", err, rerr)
+			}
+			return err
+		}
+		select {
+		case <-sigCtx.Done():
+			return f.update("")
+		case <-ticker.C:
+		}
+	}
+}
+
+// terminalWindow picks the multiplexer window to rename: the --target tmux
+// window, or the tmux or screen window daylit focus runs in
+func (c *FocusCmd) terminalWindow() (terminalWindow, error) {
+	switch {
+	case c.Target != "":
+		return &tmuxWindow{target: c.Target, run: runCommand}, nil
+	case os.Getenv("TMUX") != "":
+		return &tmuxWindow{target: os.Getenv("TMUX_PANE"), run: runCommand}, nil
+	case os.Getenv("STY") != "":
+		return &screenWindow{session: os.Getenv("STY"), window: os.Getenv("WINDOW"), run: runCommand}, nil
+	}
+	return nil, fmt.Errorf("daylit focus needs to run inside tmux or GNU screen, or be given a tmux window with --target")
+}
+
+// focusTitle is the window name for the block in progress at now, or ""
+// between blocks
+func focusTitle(ctx *cli.Context, tmpl *template.Template, now time.Time) (string, error) {
+	window, err := dayWindow(ctx)
+	if err != nil {
+		return "", err
+	}
+	plan, i, _ := currentSlot(ctx, now, window)
+	if i < 0 {
+		return "", nil
+	}
+	names, err := taskNames(ctx)
+	if err != nil {
+		return "", err
+	}
+	line, err := renderLine(tmpl, slotLine(plan.Slots[i], minutesOnPlan(plan, now), window, names))
+	if err != nil {
+		return "", err
+	}
+	// Window names are a single line
+	return strings.Join(strings.Fields(line), " "), nil
+}
+
+// focuser renames a window while a block is in progress and gives it back
+// its own name between blocks
+type focuser struct {
+	window   terminalWindow
+	original string
+	renamed  string // The name daylit gave the window, empty when it has its own
+}
+
+func newFocuser(window terminalWindow) (*focuser, error) {
+	original, err := window.Name()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the window name: %w", err)
+	}
+	return &focuser{window: window, original: original}, nil
+}
+
+// update names the window title, or restores its own name when title is
+// empty. The window is only touched when the name changes.
+func (f *focuser) update(title string) error {
+	if title == f.renamed {
+		return nil
+	}
+	if title == "" {
+		if err := f.window.Restore(f.original); err != nil {
+			return fmt.Errorf("failed to restore the window name: %w", err)
+		}
+	} else if err := f.window.Rename(title); err != nil {
+		return fmt.Errorf("failed to rename the window: %w", err)
+	}
+	f.renamed = title
+	return nil
+}
+
+// terminalWindow is a window of a terminal multiplexer
+type terminalWindow interface {
+	Name() (string, error)
+	Rename(name string) error
+	// Restore gives the window back the name it had before Rename
+	Restore(name string) error
+}
+
+// runCommand runs a multiplexer command, returning its output without the
+// trailing newline
+func runCommand(name string, args ...string) (string, error) {
+	out, err := exec.Command(name, args...).Output()
+	if err != nil {
+		if exit, ok := err.(*exec.ExitError); ok && len(exit.Stderr) > 0 {
+			return "", fmt.Errorf("%s: %s", name, strings.TrimSpace(string(exit.Stderr)))
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+type tmuxWindow struct {
+	target string
+	run    func(name string, args ...string) (string, error)
+	// automaticRename is the window's own automatic-rename option before
+	// Rename turned it off, empty when it followed the global one
+	automaticRename string
+}
+
+func (w *tmuxWindow) tmux(args ...string) (string, error) {
+	return w.run("tmux", args...)
+}
+
+func (w *tmuxWindow) Name() (string, error) {
+	name, err := w.tmux("display-message", "-p", "-t", w.target, "#{window_name}")
+	if err != nil {
+		return "", err
+	}
+	w.automaticRename, err = w.tmux("show-options", "-w", "-v", "-t", w.target, "automatic-rename")
+	return name, err
+}
+
+func (w *tmuxWindow) Rename(name string) error {
+	_, err := w.tmux("rename-window", "-t", w.target, name)
+	return err
+}
+
+func (w *tmuxWindow) Restore(name string) error {
+	if _, err := w.tmux("rename-window", "-t", w.target, name); err != nil {
+		return err
+	}
+	// rename-window turns automatic renaming off for the window, so put the
+	// option back as it was
+	args := []string{"set-option", "-w", "-t", w.target, "automatic-rename", w.automaticRename}
+	if w.automaticRename == "" {
+		args = []string{"set-option", "-w", "-u", "-t", w.target, "automatic-rename"}
+	}
+	_, err := w.tmux(args...)
+	return err
+}
+
+type screenWindow struct {
+	session string
+	window  string
+	run     func(name string, args ...string) (string, error)
+}
+
+func (w *screenWindow) screen(args ...string) (string, error) {
+	return w.run("screen", append([]string{"-S", w.session, "-p", w.window}, args...)...)
+}
+
+func (w *screenWindow) Name() (string, error) {
+	return w.screen("-Q", "title")
+}
+
+func (w *screenWindow) Rename(name string) error {
+	_, err := w.screen("-X", "title", name)
+	return err
+}
+
+func (w *screenWindow) Restore(name string) error {
+	return w.Rename(name)
+}
@@ -0,0 +1,80 @@
+package plans
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeTmux records tmux commands and answers queries about the window
+type fakeTmux struct {
+	name       string
+	autoRename string
+	calls      []string
+}
+
+func (f *fakeTmux) run(name string, args ...string) (string, error) {
+	f.calls = append(f.calls, strings.Join(args, " "))
+	switch args[0] {
+	case "display-message":
+		return f.name, nil
+	case "show-options":
+		return f.autoRename, nil
+	case "rename-window":
+		f.name = args[len(args)-1]
+	}
+	return "", nil
+}
+
+func TestFocuser(t *testing.T) {
+	ctx := setupDoneTest(t, false)
+	tmpl, err := parseLineFormat("{{.Name}} ({{.End}})")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake := &fakeTmux{name: "zsh"}
+	f, err := newFocuser(&tmuxWindow{target: "%1", run: fake.run})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, now := range []int{8*60 + 50, 9 * 60, 9*60 + 30, 10 * 60, 12*60 + 30} {
+		title, err := focusTitle(ctx, tmpl, at(now/60, now%60))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.update(title); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.update(""); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"display-message -p -t %1 #{window_name}",
+		"show-options -w -v -t %1 automatic-rename",
+		"rename-window -t %1 Write (10:00)",
+		"rename-window -t %1 Email (10:30)",
+		"rename-window -t %1 zsh",
+		"set-option -w -u -t %1 automatic-rename",
+	}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("tmux calls:\n%s\nwant:\n%s", strings.Join(fake.calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestTmuxWindow_RestoreKeepsOwnOption(t *testing.T) {
+	fake := &fakeTmux{name: "editor", autoRename: "on"}
+	w := &tmuxWindow{target: "work:2", run: fake.run}
+	if _, err := w.Name(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Restore("editor"); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.calls[len(fake.calls)-1]; got != "set-option -w -t work:2 automatic-rename on" {
+		t.Errorf("last call = %q", got)
+	}
+}
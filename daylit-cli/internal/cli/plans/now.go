@@ -1,59 +1,133 @@
 package plans
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/i18n"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
 )
 
-type NowCmd struct{}
+// nowBarWidth is the width of the block progress bar
+const nowBarWidth = 30
+
+type NowCmd struct {
+	Watch    bool          `short:"w" help:"Keep the display open, refreshing it until interrupted."`
+	Interval time.Duration `help:"How often --watch refreshes the display." default:"5s"`
+}
 
 func (c *NowCmd) Run(ctx *cli.Context) error {
-	now := time.Now()
-	dateStr := now.Format("2006-01-02")
-	currentMinutes := now.Hour()*60 + now.Minute()
+	if !c.Watch {
+		return c.show(ctx)
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
 
-	plan, err := ctx.Store.GetPlan(dateStr)
-	if err != nil {
-		fmt.Println("No active plan for today.")
-		return nil
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Clear the screen and move to the top left corner
+		fmt.Print("\033[H\033[2J")
+		if err := c.show(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-sigCtx.Done():
+			return nil
+		case <-ticker.C:
+		}
 	}
+}
 
-	// Find current slot
-	var currentSlot *models.Slot
-	for i := range plan.Slots {
-		if plan.Slots[i].Status == constants.SlotStatusAccepted || plan.Slots[i].Status == constants.SlotStatusDone {
-			startMinutes, err := utils.ParseTimeToMinutes(plan.Slots[i].Start)
-			if err != nil {
-				continue
-			}
-			endMinutes, err := utils.ParseTimeToMinutes(plan.Slots[i].End)
-			if err != nil {
-				continue
-			}
-			if startMinutes <= currentMinutes && currentMinutes < endMinutes {
-				currentSlot = &plan.Slots[i]
-				break
-			}
+// show prints the current block with its countdown and the next block
+func (c *NowCmd) show(ctx *cli.Context) error {
+	now := ctx.Now()
+
+	// Without valid day boundaries, slots are read as plain clock times
+	var window models.DayWindow
+	if settings, err := ctx.Store.GetSettings(); err == nil {
+		if w, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd); err == nil {
+			window = w
 		}
 	}
 
-	if currentSlot == nil {
-		fmt.Printf("Now (%02d:%02d): Free time\n", now.Hour(), now.Minute())
+	plan, i, hasPlan := currentSlot(ctx, now, window)
+	if !hasPlan {
+		fmt.Println(i18n.T("now.no_plan"))
 		return nil
 	}
 
-	task, err := ctx.Store.GetTask(currentSlot.TaskID)
-	if err != nil {
-		return err
-	}
+	countdown := window.CountdownAt(plan.Slots, minutesOnPlan(plan, now), isActiveSlot)
+
+	if i < 0 {
+		fmt.Println(i18n.T("now.free", now.Hour(), now.Minute()))
+	} else {
+		slot := plan.Slots[i]
+		task, err := ctx.Store.GetTask(slot.TaskID)
+		if err != nil {
+			return err
+		}
 
-	fmt.Printf("Now (%02d:%02d): You planned to be doing:\n\n", now.Hour(), now.Minute())
-	fmt.Printf("%s–%s  %s\n", currentSlot.Start, currentSlot.End, task.Name)
+		fmt.Printf("%s\n\n", i18n.T("now.doing", now.Hour(), now.Minute()))
+		fmt.Printf("%s–%s  %s\n", slot.Start, slot.End, task.Name)
+		fmt.Printf("%s %d%%\n", countdown.ProgressBar(nowBarWidth), int(countdown.Progress()*100))
+		fmt.Println(i18n.T("now.remaining", utils.FormatMinutes(countdown.Remaining)))
+	}
 
+	if countdown.Next < 0 {
+		fmt.Println(i18n.T("now.next_none"))
+		return nil
+	}
+	name := i18n.T("now.unknown_task")
+	if task, err := ctx.Store.GetTask(plan.Slots[countdown.Next].TaskID); err == nil {
+		name = task.Name
+	}
+	fmt.Println(i18n.T("now.next", name, utils.FormatMinutes(countdown.UntilNext)))
 	return nil
 }
+
+// isActiveSlot reports whether slot is part of the day as planned: accepted,
+// or already done
+func isActiveSlot(slot models.Slot) bool {
+	return slot.Status == constants.SlotStatusAccepted || slot.Status == constants.SlotStatusDone
+}
+
+// currentSlot finds the accepted or done slot that covers now. After
+// midnight, yesterday's plan is still running if the day ends late. It
+// returns the plan holding the slot and the slot's index, or -1 when no slot
+// covers now; hasPlan reports whether there was a plan to look in.
+func currentSlot(ctx *cli.Context, now time.Time, window models.DayWindow) (plan models.DayPlan, i int, hasPlan bool) {
+	currentMinutes := now.Hour()*60 + now.Minute()
+
+	plan, err := ctx.Store.GetPlan(now.Format(constants.DateFormat))
+	hasPlan = err == nil
+	if hasPlan {
+		if i := window.SlotAt(plan.Slots, currentMinutes, isActiveSlot); i >= 0 {
+			return plan, i, true
+		}
+	}
+
+	if window.CrossesMidnight() && currentMinutes < window.Start {
+		yesterday := now.AddDate(0, 0, -1).Format(constants.DateFormat)
+		if prev, err := ctx.Store.GetPlan(yesterday); err == nil {
+			if i := window.SlotAt(prev.Slots, currentMinutes+models.MinutesPerDay, isActiveSlot); i >= 0 {
+				return prev, i, true
+			}
+			if !hasPlan {
+				return prev, -1, true
+			}
+		}
+	}
+	return plan, -1, hasPlan
+}
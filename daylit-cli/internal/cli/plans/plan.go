@@ -2,31 +2,89 @@ package plans
 
 import (
 	"bufio"
+	"database/sql"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/julianstephens/daylit/daylit-cli/internal/autoplan"
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/hooks"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
 	"github.com/julianstephens/daylit/daylit-cli/internal/validation"
 )
 
 type PlanCmd struct {
+	Generate PlanGenerateCmd `cmd:"" default:"withargs" help:"Generate the plan for a day."`
+	Lock     PlanLockCmd     `cmd:"" help:"Lock the start of a day's plan so regenerating or reflowing the day keeps it in place."`
+	Unlock   PlanUnlockCmd   `cmd:"" help:"Remove the lock from a day's plan."`
+}
+
+type PlanGenerateCmd struct {
 	Date        string `arg:"" help:"Date to plan (YYYY-MM-DD or 'today')." default:"today"`
 	NewRevision bool   `help:"Create a new revision instead of being blocked when an accepted plan exists." name:"new-revision"`
+	Context     string `help:"Plan for this context instead of the active one (see 'daylit context')."`
+	Template    string `short:"t" help:"Day template whose slots are kept before filling the gaps (see 'daylit template')."`
+	Capacity    string `help:"Share of a normal day to schedule, e.g. 50%. Only the highest-priority tasks and appointments are kept."`
+	Shorten     bool   `help:"Shorten task blocks by the capacity too."`
+	LowEnergy   bool   `help:"Plan a low-energy day: 50% capacity with shortened blocks." name:"low-energy"`
+	Accept      bool   `help:"Accept the proposed plan without asking, e.g. from cron." xor:"answer"`
+	DryRun      bool   `help:"Show the proposed plan without saving it." name:"dry-run" xor:"answer"`
+	Simulate    bool   `help:"Show the proposed plan and why each task was placed or left out, without saving it." xor:"answer"`
+}
+
+// parseCapacity parses a capacity such as "50%" or "50" into a percentage.
+// An empty capacity is a full day.
+func parseCapacity(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 100, nil
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+	if err != nil || percent < 1 || percent > 100 {
+		return 0, fmt.Errorf("invalid capacity %q: use a percentage from 1%% to 100%%", s)
+	}
+	return percent, nil
 }
 
-func (c *PlanCmd) Run(ctx *cli.Context) error {
+func (c *PlanGenerateCmd) Run(ctx *cli.Context) error {
+	capacity, err := parseCapacity(c.Capacity)
+	if err != nil {
+		return err
+	}
+	if c.Simulate {
+		c.DryRun = true
+	}
+	shorten := c.Shorten
+	if c.LowEnergy {
+		if c.Capacity == "" {
+			capacity = 50
+		}
+		shorten = true
+	}
+
+	// Nobody can answer the prompt from a script; fail now rather than hang
+	interactive := !c.Accept && !c.DryRun
+	if interactive && !cli.StdinIsTerminal() {
+		return fmt.Errorf("stdin is not a terminal, so the plan can't be accepted interactively; use --accept to save it or --dry-run to only show it")
+	}
+
 	// Perform automatic backup on plan invocation (after successful load)
-	ctx.PerformAutomaticBackup()
+	if !c.DryRun {
+		ctx.PerformAutomaticBackup()
+	}
 
 	// Parse date
 	var planDate time.Time
 	if c.Date == "today" {
-		planDate = time.Now()
+		planDate = ctx.Now()
 	} else {
-		var err error
 		planDate, err = time.Parse("2006-01-02", c.Date)
 		if err != nil {
 			return fmt.Errorf("invalid date format, use YYYY-MM-DD or 'today': %w", err)
@@ -47,19 +105,18 @@ func (c *PlanCmd) Run(ctx *cli.Context) error {
 				return nil
 			}
 			fmt.Printf("Creating new revision of plan for %s (will be revision %d)\n\n", dateStr, existingPlan.Revision+1)
-		} else {
+		} else if !c.DryRun {
 			// Plan exists but not accepted - can regenerate
 			fmt.Printf("Warning: A plan already exists for %s (revision %d, not accepted). Generating a new plan will replace it.\n", dateStr, existingPlan.Revision)
-			fmt.Print("Continue? [y/N]: ")
-			reader := bufio.NewReader(os.Stdin)
-			response, err := reader.ReadString('\n')
-			if err != nil {
-				return err
-			}
-			response = strings.TrimSpace(response)
-			if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-				fmt.Println("Plan generation cancelled.")
-				return nil
+			if interactive {
+				ok, err := ctx.Confirm("Continue?")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Println("Plan generation cancelled.")
+					return nil
+				}
 			}
 			fmt.Println()
 		}
@@ -77,8 +134,107 @@ func (c *PlanCmd) Run(ctx *cli.Context) error {
 		return fmt.Errorf("failed to get tasks: %w", err)
 	}
 
+	// Only schedule tasks that fit the day's context
+	activeContext := settings.ActiveContext
+	if c.Context != "" {
+		activeContext = models.NormalizeContext(c.Context)
+	}
+	candidates := models.TasksInContext(tasks, activeContext)
+	// With --simulate, decisions records why each task was planned or left out
+	decisions := leftOut(tasks, candidates, fmt.Sprintf("not in the %s context", activeContext))
+	if activeContext != "" {
+		fmt.Printf("Context: %s\n", activeContext)
+	}
+
+	// Nothing recurring is planned while away
+	vacations, err := ctx.Store.GetVacations(dateStr, dateStr)
+	if err != nil {
+		return fmt.Errorf("failed to get vacations: %w", err)
+	}
+	if v, away := models.VacationOn(vacations, dateStr); away {
+		fmt.Printf("%s is during your vacation %s..%s; recurring tasks are skipped.\n", dateStr, v.Start, v.End)
+		offVacation := models.TasksOffVacation(candidates, vacations, dateStr)
+		decisions = append(decisions, leftOut(candidates, offVacation, "recurring, and the day is during a vacation")...)
+		candidates = offVacation
+	}
+
+	// Only one member of each task pool is planned per day
+	pools, err := ctx.Store.GetAllPools()
+	if err != nil {
+		return fmt.Errorf("failed to get task pools: %w", err)
+	}
+	chosen := scheduler.ChoosePoolMembers(candidates, pools, planDate)
+	decisions = append(decisions, leftOut(candidates, chosen, "another member of its task pool is planned today")...)
+	candidates = chosen
+
+	// Shared household tasks go to whoever's turn it is
+	ours, err := autoplan.SharedTurns(ctx.Store, candidates)
+	if err != nil {
+		return err
+	}
+	decisions = append(decisions, leftOut(candidates, ours, "a shared task, and it's someone else's turn")...)
+	candidates = ours
+
+	// Keep the template's slots in place, if one was given
+	var template *models.DayTemplate
+	if c.Template != "" {
+		t, err := ctx.Store.GetDayTemplate(c.Template)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("template %q not found (see 'daylit template list')", c.Template)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get template: %w", err)
+		}
+		// Skip slots for tasks that were deleted after the template was made
+		existing := make(map[string]bool, len(tasks))
+		for _, task := range tasks {
+			existing[task.ID] = true
+		}
+		slots := t.Slots[:0]
+		for _, slot := range t.Slots {
+			if !existing[slot.TaskID] {
+				fmt.Printf("Skipping template slot %s–%s: its task no longer exists\n", slot.Start, slot.End)
+				continue
+			}
+			slots = append(slots, slot)
+		}
+		t.Slots = slots
+		template = &t
+		fmt.Printf("Template: %s\n", t.Name)
+	}
+	// The locked part of the day stays as it is
+	opts := autoplan.LockOptions(ctx.Store, settings, dateStr)
+	opts.Template = template
+	opts.Capacity = capacity
+	opts.ShortenDurations = shorten
+	opts.Preferred = autoplan.Preferences(ctx.Store, settings, dateStr)
+	opts.Aging = autoplan.Aging(ctx.Store, settings, candidates, dateStr)
+	opts.Recovery = settings.Recovery()
+	if c.Simulate {
+		opts.Explain = func(d scheduler.Decision) { decisions = append(decisions, d) }
+	}
+	if opts.LockedUntil != "" {
+		fmt.Printf("Locked until %s: keeping %d slot(s) in place\n", opts.LockedUntil, len(opts.LockedSlots))
+	}
+	if capacity < 100 {
+		fmt.Printf("Capacity: %d%%", capacity)
+		if shorten {
+			fmt.Print(", shortened blocks")
+		}
+		fmt.Println(" (skipped tasks keep their streaks and come back next time)")
+	}
+	if len(opts.Preferred) > 0 {
+		fmt.Printf("Adaptive placement: %d task(s) placed at their best time of day (see 'daylit optimize placement')\n", len(opts.Preferred))
+	}
+	if len(opts.Aging) > 0 {
+		fmt.Printf("Priority aging: %d task(s) raised after being left out of recent plans (see 'daylit task list')\n", len(opts.Aging))
+	}
+	if activeContext != "" || template != nil || opts.LockedUntil != "" || capacity < 100 || len(opts.Preferred) > 0 || len(opts.Aging) > 0 {
+		fmt.Println()
+	}
+
 	// Generate plan
-	plan, err := ctx.Scheduler.GeneratePlan(dateStr, tasks, settings.DayStart, settings.DayEnd)
+	plan, err := ctx.Scheduler.GeneratePlanWithOptions(dateStr, candidates, settings.DayStart, settings.DayEnd, opts)
 	if err != nil {
 		return err
 	}
@@ -88,8 +244,10 @@ func (c *PlanCmd) Run(ctx *cli.Context) error {
 
 	// Validate both tasks and the generated plan
 	validator := validation.New()
+	validator.MinFreeMinutes = settings.MinFreeMinutes
+	validator.Recovery = settings.Recovery()
 	// Use scoped validation - only validate tasks that would be scheduled on this plan date
-	taskValidationResult := validator.ValidateTasksForDate(tasks, &planDate)
+	taskValidationResult := validator.ValidateTasksForDate(candidates, &planDate)
 	planValidationResult := validator.ValidatePlan(plan, tasks, settings.DayStart, settings.DayEnd)
 
 	// Combine validation results
@@ -101,7 +259,6 @@ func (c *PlanCmd) Run(ctx *cli.Context) error {
 
 	if len(plan.Slots) == 0 {
 		fmt.Println("  No tasks scheduled for this day")
-		fmt.Println("\nAccept this plan? [y/N]: ")
 	} else {
 		for _, slot := range plan.Slots {
 			task, err := ctx.Store.GetTask(slot.TaskID)
@@ -109,9 +266,20 @@ func (c *PlanCmd) Run(ctx *cli.Context) error {
 				fmt.Printf("%s–%s  (unknown task)\n", slot.Start, slot.End)
 				continue
 			}
+			if ready := getReadyTime(task, slot.Start); ready != "" {
+				fmt.Printf("%s–%s  %s (get ready %s)\n", slot.Start, slot.End, task.Name, ready)
+				continue
+			}
 			fmt.Printf("%s–%s  %s\n", slot.Start, slot.End, task.Name)
 		}
 
+		if overflow := scheduler.Overflow(plan, candidates, planDate); len(overflow) > 0 {
+			fmt.Println("\nDidn't make the cut (nice to have):")
+			for _, task := range overflow {
+				fmt.Printf("  - %s (%dm)\n", task.Name, task.DurationMin)
+			}
+		}
+
 		// Show validation warnings if any
 		if validationResult.HasConflicts() {
 			fmt.Println("\n⚠️  Validation warnings:")
@@ -119,41 +287,142 @@ func (c *PlanCmd) Run(ctx *cli.Context) error {
 				fmt.Printf("  - %s\n", conflict.Description)
 			}
 		}
+	}
 
-		fmt.Println("\nAccept this plan? [y/N]: ")
+	if c.Simulate {
+		printDecisions(decisions, plan, tasks)
 	}
 
-	// Read user input
-	reader := bufio.NewReader(os.Stdin)
-	response, err := reader.ReadString('\n')
-	if err != nil {
-		return err
+	if c.DryRun {
+		fmt.Println("\nDry run: the plan was not saved.")
+		return nil
 	}
-	response = strings.TrimSpace(response)
 
-	if strings.ToLower(response) == "y" || strings.ToLower(response) == "yes" {
-		// Update all slots to accepted and set accepted_at timestamp
-		for i := range plan.Slots {
-			plan.Slots[i].Status = constants.SlotStatusAccepted
+	accepted := c.Accept
+	if interactive {
+		if len(plan.Slots) == 0 {
+			fmt.Println("\nAccept this plan? [y/N]: ")
+		} else {
+			fmt.Println("\nAccept this plan? [y/N, r to review slots first]: ")
 		}
-		now := time.Now().UTC().Format(time.RFC3339)
-		plan.AcceptedAt = &now
 
-		if err := ctx.Store.SavePlan(plan); err != nil {
+		// Read user input
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
 			return err
 		}
+		response = strings.ToLower(strings.TrimSpace(response))
+
+		accepted = response == "y" || response == "yes"
+		if (response == "r" || response == "review") && len(plan.Slots) > 0 {
+			review := newPlanReview(ctx.Scheduler, plan, tasks, settings.DayStart, settings.DayEnd)
+			accepted, err = review.run(reader)
+			if err != nil {
+				return err
+			}
+			plan = review.plan(plan)
+		}
+	}
+
+	if accepted {
+		// Update the new slots to accepted and set accepted_at timestamp;
+		// locked slots keep their status, such as done
+		for i := range plan.Slots {
+			if plan.Slots[i].Status == constants.SlotStatusPlanned {
+				plan.Slots[i].Status = constants.SlotStatusAccepted
+			}
+		}
+		now := ctx.Now().UTC().Format(time.RFC3339)
+		plan.AcceptedAt = &now
 
-		// Get the saved plan to display the correct revision number
-		savedPlan, err := ctx.Store.GetPlan(dateStr)
+		// Read the saved plan back in the same transaction, so the revision
+		// shown is the one just saved
+		var savedPlan models.DayPlan
+		var readErr error
+		err := ctx.InTx(func() error {
+			if err := ctx.Store.SavePlan(plan); err != nil {
+				return err
+			}
+			savedPlan, readErr = ctx.Store.GetPlan(dateStr)
+			return nil
+		})
 		if err != nil {
+			return err
+		}
+		if readErr != nil {
 			// Fallback to displaying without revision number
 			fmt.Println("Plan accepted and saved!")
+			savedPlan = plan
 		} else {
 			fmt.Printf("Plan accepted and saved as revision %d!\n", savedPlan.Revision)
 		}
+		hooks.Fire(hooks.PlanAccepted, hooks.Plan(ctx.Store, savedPlan))
 	} else {
 		fmt.Println("Plan discarded. You can modify tasks and regenerate.")
 	}
 
 	return nil
 }
+
+// leftOut returns a decision with reason for each task in before that isn't
+// in after
+func leftOut(before, after []models.Task, reason string) []scheduler.Decision {
+	kept := make(map[string]bool, len(after))
+	for _, task := range after {
+		kept[task.ID] = true
+	}
+	var decisions []scheduler.Decision
+	for _, task := range before {
+		if !kept[task.ID] {
+			decisions = append(decisions, scheduler.Decision{TaskID: task.ID, Reason: reason})
+		}
+	}
+	return decisions
+}
+
+// printDecisions lists why each task was placed where it was, in the order
+// of the plan, and then why the others were left out
+func printDecisions(decisions []scheduler.Decision, plan models.DayPlan, tasks []models.Task) {
+	names := make(map[string]string, len(tasks))
+	for _, task := range tasks {
+		names[task.ID] = task.Name
+	}
+	position := make(map[string]int, len(plan.Slots))
+	for i, slot := range plan.Slots {
+		position[slot.TaskID+"@"+slot.Start] = i
+	}
+	rank := func(d scheduler.Decision) int {
+		if d.Slot == nil {
+			return len(plan.Slots)
+		}
+		return position[d.TaskID+"@"+d.Slot.Start]
+	}
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return rank(decisions[i]) < rank(decisions[j])
+	})
+
+	fmt.Println("\nDecisions:")
+	for _, d := range decisions {
+		when := "left out"
+		if d.Slot != nil {
+			when = d.Slot.Start + "–" + d.Slot.End
+		}
+		name := names[d.TaskID]
+		if name == "" {
+			name = d.TaskID
+		}
+		fmt.Printf("  %-11s  %s: %s\n", when, name, d.Reason)
+	}
+}
+
+// getReadyTime is when to start getting ready for a block of task starting
+// at start, or "" when the task has no prep or travel time
+func getReadyTime(task models.Task, start string) string {
+	before, _ := task.Padding()
+	t, err := time.Parse(constants.TimeFormat, start)
+	if before == 0 || err != nil {
+		return ""
+	}
+	return t.Add(-time.Duration(before) * time.Minute).Format(constants.TimeFormat)
+}
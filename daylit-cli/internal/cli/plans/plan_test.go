@@ -0,0 +1,55 @@
+package plans
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestPlanCmd_NonInteractive(t *testing.T) {
+	if cli.StdinIsTerminal() {
+		t.Skip("stdin is a terminal")
+	}
+	t.Setenv(constants.HooksDirEnv, t.TempDir())
+	ctx := setupTestDB(t)
+	task := models.Task{
+		ID: "write", Name: "Write", Kind: constants.TaskKindFlexible, DurationMin: 60,
+		Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 3, Active: true,
+	}
+	if err := ctx.Store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	// Without a flag there is nobody to answer the prompt
+	err := (&PlanGenerateCmd{Date: "2025-06-02"}).Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "--accept") {
+		t.Fatalf("expected an error pointing at --accept, got %v", err)
+	}
+
+	if err := (&PlanGenerateCmd{Date: "2025-06-02", DryRun: true}).Run(ctx); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if _, err := ctx.Store.GetPlan("2025-06-02"); err == nil {
+		t.Fatal("expected a dry run not to save the plan")
+	}
+	if err := (&PlanGenerateCmd{Date: "2025-06-02", Simulate: true}).Run(ctx); err != nil {
+		t.Fatalf("simulation failed: %v", err)
+	}
+	if _, err := ctx.Store.GetPlan("2025-06-02"); err == nil {
+		t.Fatal("expected a simulation not to save the plan")
+	}
+
+	if err := (&PlanGenerateCmd{Date: "2025-06-02", Accept: true}).Run(ctx); err != nil {
+		t.Fatalf("accept failed: %v", err)
+	}
+	plan, err := ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatalf("expected the plan to be saved: %v", err)
+	}
+	if plan.AcceptedAt == nil || len(plan.Slots) != 1 || plan.Slots[0].Status != constants.SlotStatusAccepted {
+		t.Errorf("expected an accepted plan with one accepted slot, got %+v", plan)
+	}
+}
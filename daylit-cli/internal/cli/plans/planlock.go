@@ -0,0 +1,73 @@
+package plans
+
+import (
+	"fmt"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+type PlanLockCmd struct {
+	Until string `help:"Lock the day up to this time (HH:MM); slots that start earlier stay in place." required:""`
+	Date  string `help:"Date of the plan (YYYY-MM-DD or 'today')." default:"today"`
+}
+
+func (c *PlanLockCmd) Run(ctx *cli.Context) error {
+	date, err := parsePlanDate(c.Date, ctx.Now())
+	if err != nil {
+		return err
+	}
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+	window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+	if err != nil {
+		return err
+	}
+	until, err := window.Minutes(c.Until)
+	if err != nil {
+		return fmt.Errorf("invalid time %q (expected HH:MM)", c.Until)
+	}
+	if until <= window.Start || until > window.End {
+		return fmt.Errorf("%s is outside your day (%s–%s)", c.Until, settings.DayStart, settings.DayEnd)
+	}
+
+	plan, err := ctx.Store.GetPlan(date)
+	if err != nil {
+		return fmt.Errorf("no plan found for %s", date)
+	}
+	plan.LockedUntil = formatClock(until)
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		return fmt.Errorf("failed to lock plan: %w", err)
+	}
+
+	fmt.Printf("Locked the plan for %s until %s; %d slot(s) before then stay in place.\n", date, plan.LockedUntil, len(plan.LockedSlots(window)))
+	return nil
+}
+
+type PlanUnlockCmd struct {
+	Date string `help:"Date of the plan (YYYY-MM-DD or 'today')." default:"today"`
+}
+
+func (c *PlanUnlockCmd) Run(ctx *cli.Context) error {
+	date, err := parsePlanDate(c.Date, ctx.Now())
+	if err != nil {
+		return err
+	}
+	plan, err := ctx.Store.GetPlan(date)
+	if err != nil {
+		return fmt.Errorf("no plan found for %s", date)
+	}
+	if plan.LockedUntil == "" {
+		fmt.Printf("The plan for %s isn't locked.\n", date)
+		return nil
+	}
+
+	plan.LockedUntil = ""
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		return fmt.Errorf("failed to unlock plan: %w", err)
+	}
+	fmt.Printf("Unlocked the plan for %s.\n", date)
+	return nil
+}
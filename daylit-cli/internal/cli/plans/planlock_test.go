@@ -0,0 +1,62 @@
+package plans
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+func TestPlanLock(t *testing.T) {
+	ctx := setupDoneTest(t, false)
+	const date = "2025-06-02"
+
+	if err := (&PlanLockCmd{Until: "25:00", Date: date}).Run(ctx); err == nil {
+		t.Error("expected an invalid lock time to fail")
+	}
+	if err := (&PlanLockCmd{Until: "10:30", Date: date}).Run(ctx); err != nil {
+		t.Fatalf("lock failed: %v", err)
+	}
+	plan, err := ctx.Store.GetPlan(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Revision != 1 || plan.LockedUntil != "10:30" {
+		t.Fatalf("expected revision 1 locked until 10:30, got revision %d locked until %q", plan.Revision, plan.LockedUntil)
+	}
+
+	// Mark the first slot done so the new revision must keep its status
+	plan.Slots[0].Status = constants.SlotStatusDone
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := (&PlanGenerateCmd{Date: date, NewRevision: true, Accept: true}).Run(ctx); err != nil {
+		t.Fatalf("regenerate failed: %v", err)
+	}
+	plan, err = ctx.Store.GetPlan(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Revision != 2 || plan.LockedUntil != "10:30" {
+		t.Errorf("expected revision 2 to stay locked until 10:30, got revision %d locked until %q", plan.Revision, plan.LockedUntil)
+	}
+	if got := planSummary(t, ctx); !strings.HasPrefix(got, "09:00-10:00 write, 10:00-10:30 email, ") {
+		t.Errorf("expected the locked slots to be kept, got %s", got)
+	}
+	if plan.Slots[0].Status != constants.SlotStatusDone {
+		t.Errorf("expected the locked slot to stay done, got %q", plan.Slots[0].Status)
+	}
+	for _, slot := range plan.Slots[2:] {
+		if slot.Start < "10:30" || slot.TaskID == "write" || slot.TaskID == "email" {
+			t.Errorf("unexpected slot after the lock: %+v", slot)
+		}
+	}
+
+	if err := (&PlanUnlockCmd{Date: date}).Run(ctx); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+	if plan, _ := ctx.Store.GetPlan(date); plan.LockedUntil != "" {
+		t.Errorf("expected the plan to be unlocked, got %q", plan.LockedUntil)
+	}
+}
@@ -0,0 +1,142 @@
+package plans
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+type ReflowCmd struct {
+	DryRun bool `help:"Show the adjusted plan without saving it."`
+}
+
+func (c *ReflowCmd) Run(ctx *cli.Context) error {
+	return c.reflow(ctx, ctx.Now())
+}
+
+// reflow moves the rest of the day to follow the last tracked slot: the one
+// still running past its end, or else the last one finished early or late.
+// The adjusted plan is saved as a new revision.
+func (c *ReflowCmd) reflow(ctx *cli.Context, now time.Time) error {
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+	window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+	if err != nil {
+		return err
+	}
+
+	plan, _, hasPlan := currentSlot(ctx, now, window)
+	if !hasPlan {
+		return fmt.Errorf("no active plan for today")
+	}
+	window.SortSlots(plan.Slots)
+
+	i, end, err := reflowAnchor(plan, now, window)
+	if err != nil {
+		return err
+	}
+	_, plannedEnd, err := window.Range(plan.Slots[i].Start, plan.Slots[i].End)
+	if err != nil {
+		return err
+	}
+	endMinutes, err := window.Minutes(end)
+	if err != nil {
+		return err
+	}
+	drift := endMinutes - plannedEnd
+	if drift == 0 {
+		fmt.Println("The plan is on schedule; nothing to reflow.")
+		return nil
+	}
+
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	shifted, dropped, err := ctx.Scheduler.Shift(plan, i, end, tasks, settings.DayStart, settings.DayEnd)
+	if err != nil {
+		return err
+	}
+
+	direction := "later"
+	if drift < 0 {
+		direction = "earlier"
+	}
+	fmt.Printf("%s ended at %s, %s.\n", slotLabel(ctx, plan.Slots[i]), end, driftText(drift))
+	fmt.Printf("Moving the rest of the day up to %d minutes %s:\n\n", abs(drift), direction)
+
+	before := make(map[string]models.Slot, len(plan.Slots))
+	for _, slot := range plan.Slots[i+1:] {
+		before[slot.TaskID] = slot
+	}
+	moved := 0
+	for _, slot := range shifted[i+1:] {
+		old := before[slot.TaskID]
+		if old.Start == slot.Start {
+			continue
+		}
+		fmt.Printf("  %s–%s → %s\n", old.Start, old.End, slotLabel(ctx, slot))
+		moved++
+	}
+	for _, slot := range dropped {
+		fmt.Printf("  Dropped: %s (no longer fits in the day)\n", slotLabel(ctx, slot))
+	}
+	if moved == 0 && len(dropped) == 0 {
+		fmt.Println("  No slots could move.")
+		return nil
+	}
+	if c.DryRun {
+		fmt.Println("\nDry run; the plan was not changed.")
+		return nil
+	}
+
+	plan.Slots = shifted
+	plan.Revision = 0
+	plan.Version = 0
+	accepted := ctx.Now().UTC().Format(time.RFC3339)
+	plan.AcceptedAt = &accepted
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		return err
+	}
+
+	saved, err := ctx.Store.GetPlan(plan.Date)
+	if err != nil {
+		fmt.Println("\nAdjusted plan saved.")
+		return nil
+	}
+	fmt.Printf("\nAdjusted plan saved as revision %d.\n", saved.Revision)
+	return nil
+}
+
+// reflowAnchor finds the slot the rest of the day should follow and when it
+// ended: now for a slot still running past its planned end, or else the
+// actual end of the last finished slot
+func reflowAnchor(plan models.DayPlan, now time.Time, window models.DayWindow) (int, string, error) {
+	if i := runningSlot(plan); i >= 0 {
+		_, end, err := window.Range(plan.Slots[i].Start, plan.Slots[i].End)
+		if err != nil {
+			return 0, "", err
+		}
+		if minutesOnPlan(plan, now) <= end {
+			return 0, "", fmt.Errorf("%s–%s is still running and not over time yet; reflow once it ends with 'daylit stop' or 'daylit done'", plan.Slots[i].Start, plan.Slots[i].End)
+		}
+		return i, now.Format("15:04"), nil
+	}
+	for i := len(plan.Slots) - 1; i >= 0; i-- {
+		if plan.Slots[i].ActualEnd != nil {
+			return i, *plan.Slots[i].ActualEnd, nil
+		}
+	}
+	return 0, "", fmt.Errorf("no tracked slot to reflow from (use 'daylit start' with 'daylit stop' or 'daylit done')")
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
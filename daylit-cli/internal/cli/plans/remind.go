@@ -0,0 +1,191 @@
+package plans
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+type RemindCmd struct {
+	Add    RemindAddCmd    `cmd:"" help:"Add reminders to a slot, e.g. 30 minutes before and at the start."`
+	List   RemindListCmd   `cmd:"" help:"List the slot reminders for a day." default:"1"`
+	Delete RemindDeleteCmd `cmd:"" help:"Delete a slot reminder."`
+}
+
+type RemindAddCmd struct {
+	Slot    string   `arg:"" help:"Slot start time (HH:MM) or task name."`
+	Before  []string `short:"b" help:"How long before the slot starts to remind, e.g. 30m,0 (default: at the start)."`
+	Date    string   `help:"Date of the plan (YYYY-MM-DD or 'today')." default:"today"`
+	Message string   `short:"m" help:"Custom reminder text."`
+}
+
+func (c *RemindAddCmd) Run(ctx *cli.Context) error {
+	date, err := parsePlanDate(c.Date, ctx.Now())
+	if err != nil {
+		return err
+	}
+	offsets, err := parseOffsets(c.Before)
+	if err != nil {
+		return err
+	}
+
+	plan, err := ctx.Store.GetPlan(date)
+	if err != nil {
+		return fmt.Errorf("no plan found for %s", date)
+	}
+	i, err := findSlot(ctx, plan, c.Slot)
+	if err != nil {
+		return err
+	}
+	slot := plan.Slots[i]
+
+	for _, offset := range offsets {
+		reminder := models.SlotReminder{
+			ID:        uuid.New().String(),
+			PlanDate:  date,
+			SlotStart: slot.Start,
+			TaskID:    slot.TaskID,
+			OffsetMin: offset,
+			Message:   strings.TrimSpace(c.Message),
+			CreatedAt: ctx.Now(),
+		}
+		if err := ctx.Store.AddSlotReminder(reminder); err != nil {
+			return fmt.Errorf("failed to add reminder: %w", err)
+		}
+		fmt.Printf("Added reminder %s for %s (ID: %s)\n", offsetText(offset), slotLabel(ctx, slot), reminder.ID)
+	}
+	return nil
+}
+
+type RemindListCmd struct {
+	Date string `help:"Date of the plan (YYYY-MM-DD or 'today')." default:"today"`
+}
+
+func (c *RemindListCmd) Run(ctx *cli.Context) error {
+	date, err := parsePlanDate(c.Date, ctx.Now())
+	if err != nil {
+		return err
+	}
+	reminders, err := ctx.Store.GetSlotReminders(date, date)
+	if err != nil {
+		return fmt.Errorf("failed to get reminders: %w", err)
+	}
+	if len(reminders) == 0 {
+		fmt.Printf("No slot reminders for %s\n", date)
+		return nil
+	}
+
+	plan, _ := ctx.Store.GetPlan(date)
+	fmt.Printf("Slot reminders for %s:\n", date)
+	for _, r := range reminders {
+		label := fmt.Sprintf("%s  (slot no longer in the plan)", r.SlotStart)
+		if i := r.FindSlot(plan.Slots); i >= 0 {
+			label = slotLabel(ctx, plan.Slots[i])
+		}
+		line := fmt.Sprintf("  %s: %s", offsetText(r.OffsetMin), label)
+		if r.Message != "" {
+			line += fmt.Sprintf(" — %q", r.Message)
+		}
+		if r.SentAt != nil {
+			line += fmt.Sprintf(" [sent %s]", r.SentAt.Local().Format("15:04"))
+		}
+		fmt.Printf("%s\n      ID: %s\n", line, r.ID)
+	}
+	return nil
+}
+
+type RemindDeleteCmd struct {
+	ID string `arg:"" help:"Reminder ID to delete (see 'daylit remind list')."`
+}
+
+func (c *RemindDeleteCmd) Run(ctx *cli.Context) error {
+	if err := ctx.Store.DeleteSlotReminder(c.ID); err != nil {
+		return fmt.Errorf("failed to delete reminder %s: %w", c.ID, err)
+	}
+	fmt.Printf("Deleted reminder %s\n", c.ID)
+	return nil
+}
+
+// findSlot returns the index of the slot that ref refers to: the slot
+// starting at ref if it is a time, or else the only slot of the task ref names
+func findSlot(ctx *cli.Context, plan models.DayPlan, ref string) (int, error) {
+	if _, err := time.Parse(constants.TimeFormat, ref); err == nil {
+		for i, slot := range plan.Slots {
+			if slot.Start == ref {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("no slot starts at %s on %s (see 'daylit day %s')", ref, plan.Date, plan.Date)
+	}
+
+	task, err := ctx.ResolveTask(ref, cli.LiveTasks)
+	if err != nil {
+		return -1, err
+	}
+	var found []int
+	for i, slot := range plan.Slots {
+		if slot.TaskID == task.ID {
+			found = append(found, i)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return -1, fmt.Errorf("%s isn't in the plan for %s", task.Name, plan.Date)
+	case 1:
+		return found[0], nil
+	}
+	starts := make([]string, len(found))
+	for j, i := range found {
+		starts[j] = plan.Slots[i].Start
+	}
+	return -1, fmt.Errorf("%s has %d slots on %s; give the start time instead: %s", task.Name, len(found), plan.Date, strings.Join(starts, ", "))
+}
+
+// parseOffsets parses reminder offsets such as "30m", "1h" or "0" into
+// minutes. No offsets means one reminder at the start.
+func parseOffsets(values []string) ([]int, error) {
+	if len(values) == 0 {
+		return []int{0}, nil
+	}
+	var offsets []int
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if minutes, err := strconv.Atoi(v); err == nil && minutes >= 0 {
+			offsets = append(offsets, minutes)
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 || d%time.Minute != 0 {
+			return nil, fmt.Errorf("invalid reminder offset %q: use minutes such as 30, 30m or 1h", v)
+		}
+		offsets = append(offsets, int(d/time.Minute))
+	}
+	return offsets, nil
+}
+
+// offsetText describes a reminder offset
+func offsetText(minutes int) string {
+	if minutes == 0 {
+		return "at start"
+	}
+	return fmt.Sprintf("%dm before", minutes)
+}
+
+// parsePlanDate accepts YYYY-MM-DD or 'today' and returns the date in
+// YYYY-MM-DD format
+func parsePlanDate(value string, now time.Time) (string, error) {
+	if value == "today" {
+		return now.Format(constants.DateFormat), nil
+	}
+	if _, err := time.Parse(constants.DateFormat, value); err != nil {
+		return "", fmt.Errorf("invalid date format, use YYYY-MM-DD or 'today': %w", err)
+	}
+	return value, nil
+}
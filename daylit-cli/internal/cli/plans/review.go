@@ -0,0 +1,295 @@
+package plans
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+)
+
+const reviewHelp = `Review commands:
+  x N        Reject slot N; its task is left out and later slots move up
+  s N +M/-M  Shift slot N later or earlier by M minutes
+  m N P      Move slot N to position P
+  u          Undo the last change
+  y          Accept the plan
+  q          Discard the plan
+`
+
+// planReview lets the user tweak a proposed plan slot by slot before it is
+// accepted. Every change re-flows the remaining slots with the scheduler.
+type planReview struct {
+	scheduler *scheduler.Scheduler
+	tasks     []models.Task
+	dayStart  string
+	dayEnd    string
+	// lockedUntil (HH:MM) is the plan's lock boundary: slots before it are
+	// pinned and the others can't move ahead of it
+	lockedUntil string
+	slots       []scheduler.ReflowSlot
+	history     [][]scheduler.ReflowSlot
+}
+
+func newPlanReview(s *scheduler.Scheduler, plan models.DayPlan, tasks []models.Task, dayStart, dayEnd string) *planReview {
+	r := &planReview{scheduler: s, tasks: tasks, dayStart: dayStart, dayEnd: dayEnd, lockedUntil: plan.LockedUntil}
+	window, err := models.ParseDayWindow(dayStart, dayEnd)
+	for _, slot := range plan.Slots {
+		r.slots = append(r.slots, scheduler.ReflowSlot{Slot: slot, Pinned: err == nil && plan.Locked(slot, window)})
+	}
+	return r
+}
+
+// run reads review commands from in until the plan is accepted or
+// discarded, and reports whether it was accepted. Running out of input
+// discards the plan.
+func (r *planReview) run(in *bufio.Reader) (bool, error) {
+	fmt.Print(reviewHelp)
+	for {
+		fmt.Println()
+		r.print()
+		fmt.Print("\nreview> ")
+
+		line, err := in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return false, err
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			if err == io.EOF {
+				return false, nil
+			}
+			continue
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case "y", "yes", "accept":
+			return true, nil
+		case "q", "n", "no", "discard":
+			return false, nil
+		case "?", "h", "help":
+			fmt.Print(reviewHelp)
+		case "u", "undo":
+			if !r.undo() {
+				fmt.Println("Nothing to undo")
+			}
+		default:
+			if cmdErr := r.apply(fields); cmdErr != nil {
+				fmt.Printf("Error: %v\n", cmdErr)
+			}
+		}
+		if err == io.EOF {
+			return false, nil
+		}
+	}
+}
+
+// apply runs a command that changes the slots
+func (r *planReview) apply(fields []string) error {
+	switch strings.ToLower(fields[0]) {
+	case "x", "reject":
+		if len(fields) != 2 {
+			return fmt.Errorf("usage: x N")
+		}
+		n, err := r.slotNumber(fields[1])
+		if err != nil {
+			return err
+		}
+		return r.reject(n)
+	case "s", "shift":
+		if len(fields) != 3 {
+			return fmt.Errorf("usage: s N +M or s N -M")
+		}
+		n, err := r.slotNumber(fields[1])
+		if err != nil {
+			return err
+		}
+		minutes, err := strconv.Atoi(fields[2])
+		if err != nil || minutes == 0 {
+			return fmt.Errorf("invalid shift %q: use a number of minutes such as +15 or -10", fields[2])
+		}
+		return r.shift(n, minutes)
+	case "m", "move":
+		if len(fields) != 3 {
+			return fmt.Errorf("usage: m N P")
+		}
+		n, err := r.slotNumber(fields[1])
+		if err != nil {
+			return err
+		}
+		pos, err := r.slotNumber(fields[2])
+		if err != nil {
+			return err
+		}
+		return r.move(n, pos)
+	}
+	return fmt.Errorf("unknown command %q (type ? for help)", fields[0])
+}
+
+// slotNumber parses a 1-based slot number and returns its index
+func (r *planReview) slotNumber(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > len(r.slots) {
+		return 0, fmt.Errorf("invalid slot %q: expected 1-%d", s, len(r.slots))
+	}
+	return n - 1, nil
+}
+
+func (r *planReview) reject(i int) error {
+	if err := r.checkLocked(i); err != nil {
+		return err
+	}
+	slots := append([]scheduler.ReflowSlot{}, r.slots[:i]...)
+	slots = append(slots, r.slots[i+1:]...)
+	return r.reflow(slots)
+}
+
+func (r *planReview) shift(i, minutes int) error {
+	if err := r.checkLocked(i); err != nil {
+		return err
+	}
+	if r.isFixed(r.slots[i]) {
+		return fmt.Errorf("%s is an appointment at a fixed time; edit the task to move it", r.taskName(r.slots[i].Slot.TaskID))
+	}
+	window, err := models.ParseDayWindow(r.dayStart, r.dayEnd)
+	if err != nil {
+		return err
+	}
+	start, err := window.Minutes(r.slots[i].Slot.Start)
+	if err != nil {
+		return err
+	}
+	start += minutes
+	if start < window.Start {
+		start = window.Start
+	}
+
+	slots := append([]scheduler.ReflowSlot{}, r.slots...)
+	slots[i].NotBefore = formatClock(start)
+	if minutes < 0 {
+		// Moving earlier means going ahead of the slots that now start later
+		j := i
+		for ; j > 0; j-- {
+			prev, err := window.Minutes(slots[j-1].Slot.Start)
+			if err != nil || prev < start {
+				break
+			}
+			slots[j-1], slots[j] = slots[j], slots[j-1]
+		}
+		if j > 0 && !r.isFixed(slots[j-1]) {
+			if _, prevEnd, err := window.Range(slots[j-1].Slot.Start, slots[j-1].Slot.End); err == nil && prevEnd > start {
+				return fmt.Errorf("slot %d can't start before %s, when slot %d ends; use m to move it ahead", i+1, slots[j-1].Slot.End, j)
+			}
+		}
+	}
+	return r.reflow(slots)
+}
+
+func (r *planReview) move(from, to int) error {
+	if from == to {
+		return nil
+	}
+	if err := r.checkLocked(from); err != nil {
+		return err
+	}
+	slots := append([]scheduler.ReflowSlot{}, r.slots...)
+	moved := slots[from]
+	slots = append(slots[:from], slots[from+1:]...)
+	slots = append(slots[:to], append([]scheduler.ReflowSlot{moved}, slots[to:]...)...)
+
+	// The moved slot takes the place of the one it jumped over, so it mustn't
+	// keep an earlier nudge that would hold it back
+	slots[to].NotBefore = ""
+	return r.reflow(slots)
+}
+
+// reflow re-places slots and makes them the current ones, remembering the
+// previous state for undo
+func (r *planReview) reflow(slots []scheduler.ReflowSlot) error {
+	if r.lockedUntil != "" {
+		if window, err := models.ParseDayWindow(r.dayStart, r.dayEnd); err == nil {
+			lockEnd, _ := window.Minutes(r.lockedUntil)
+			for i := range slots {
+				if notBefore, err := window.Minutes(slots[i].NotBefore); !slots[i].Pinned && (err != nil || notBefore < lockEnd) {
+					slots[i].NotBefore = r.lockedUntil
+				}
+			}
+		}
+	}
+	placed, dropped, err := r.scheduler.Reflow(slots, r.tasks, r.dayStart, r.dayEnd)
+	if err != nil {
+		return err
+	}
+	for _, d := range dropped {
+		fmt.Printf("%s no longer fits in the day and was left out\n", r.taskName(d.Slot.TaskID))
+	}
+	r.history = append(r.history, r.slots)
+	r.slots = placed
+	return nil
+}
+
+func (r *planReview) undo() bool {
+	if len(r.history) == 0 {
+		return false
+	}
+	r.slots = r.history[len(r.history)-1]
+	r.history = r.history[:len(r.history)-1]
+	return true
+}
+
+// plan returns plan with the reviewed slots
+func (r *planReview) plan(plan models.DayPlan) models.DayPlan {
+	plan.Slots = make([]models.Slot, len(r.slots))
+	for i, rs := range r.slots {
+		plan.Slots[i] = rs.Slot
+	}
+	return plan
+}
+
+func (r *planReview) print() {
+	if len(r.slots) == 0 {
+		fmt.Println("  No tasks scheduled")
+		return
+	}
+	for i, rs := range r.slots {
+		line := fmt.Sprintf("  %2d. %s–%s  %s", i+1, rs.Slot.Start, rs.Slot.End, r.taskName(rs.Slot.TaskID))
+		if rs.Pinned {
+			line += " (locked)"
+		} else if r.isFixed(rs) {
+			line += " (fixed)"
+		}
+		fmt.Println(line)
+	}
+}
+
+// checkLocked refuses changes to slot i when it is locked
+func (r *planReview) checkLocked(i int) error {
+	if r.slots[i].Pinned {
+		return fmt.Errorf("slot %d is locked; the plan is locked until %s", i+1, r.lockedUntil)
+	}
+	return nil
+}
+
+func (r *planReview) isFixed(rs scheduler.ReflowSlot) bool {
+	task, ok := r.task(rs.Slot.TaskID)
+	return ok && scheduler.IsFixedAppointment(task)
+}
+
+func (r *planReview) task(id string) (models.Task, bool) {
+	for _, task := range r.tasks {
+		if task.ID == id {
+			return task, true
+		}
+	}
+	return models.Task{}, false
+}
+
+func (r *planReview) taskName(id string) string {
+	if task, ok := r.task(id); ok {
+		return task.Name
+	}
+	return "(unknown task)"
+}
@@ -0,0 +1,124 @@
+package plans
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+)
+
+func newTestReview() *planReview {
+	tasks := []models.Task{
+		{ID: "write", Name: "Write", Kind: constants.TaskKindFlexible, DurationMin: 60},
+		{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30},
+		{ID: "lunch", Name: "Lunch", Kind: constants.TaskKindAppointment, FixedStart: "12:00", FixedEnd: "13:00"},
+		{ID: "read", Name: "Read", Kind: constants.TaskKindFlexible, DurationMin: 30},
+	}
+	plan := models.DayPlan{Date: "2025-06-02", Slots: []models.Slot{
+		{Start: "09:00", End: "10:00", TaskID: "write", Status: constants.SlotStatusPlanned},
+		{Start: "10:00", End: "10:30", TaskID: "email", Status: constants.SlotStatusPlanned},
+		{Start: "10:30", End: "11:00", TaskID: "read", Status: constants.SlotStatusPlanned},
+		{Start: "12:00", End: "13:00", TaskID: "lunch", Status: constants.SlotStatusPlanned},
+	}}
+	return newPlanReview(scheduler.New(), plan, tasks, "09:00", "18:00")
+}
+
+func slotSummary(r *planReview) string {
+	var parts []string
+	for _, rs := range r.slots {
+		parts = append(parts, rs.Slot.Start+" "+rs.Slot.TaskID)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func TestPlanReview(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		accepted bool
+		want     string
+	}{
+		{
+			name:     "reject reflows the rest",
+			input:    "x 1\ny\n",
+			accepted: true,
+			want:     "09:00 email, 09:30 read, 12:00 lunch",
+		},
+		{
+			name:     "shift later pushes the following slots",
+			input:    "s 2 +15\naccept\n",
+			accepted: true,
+			want:     "09:00 write, 10:15 email, 10:45 read, 12:00 lunch",
+		},
+		{
+			name:     "shift earlier jumps ahead",
+			input:    "s 3 -90\ny\n",
+			accepted: true,
+			want:     "09:00 read, 09:30 write, 10:30 email, 12:00 lunch",
+		},
+		{
+			name:     "move reorders",
+			input:    "m 3 1\ny\n",
+			accepted: true,
+			want:     "09:00 read, 09:30 write, 10:30 email, 12:00 lunch",
+		},
+		{
+			name:     "pushed past an appointment",
+			input:    "s 1 +150\ny\n",
+			accepted: true,
+			want:     "12:00 lunch, 13:00 write, 14:00 email, 14:30 read",
+		},
+		{
+			name:     "undo",
+			input:    "x 1\nx 1\nu\ny\n",
+			accepted: true,
+			want:     "09:00 email, 09:30 read, 12:00 lunch",
+		},
+		{
+			name:     "invalid commands change nothing",
+			input:    "x 9\ns 4 +10\ns 2 -10\nm 1\nfoo\ny\n",
+			accepted: true,
+			want:     "09:00 write, 10:00 email, 10:30 read, 12:00 lunch",
+		},
+		{
+			name:  "discard",
+			input: "x 1\nq\n",
+			want:  "09:00 email, 09:30 read, 12:00 lunch",
+		},
+		{
+			name:  "end of input discards",
+			input: "x 2",
+			want:  "09:00 write, 10:00 read, 12:00 lunch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReview()
+			accepted, err := r.run(bufio.NewReader(strings.NewReader(tt.input)))
+			if err != nil {
+				t.Fatalf("review failed: %v", err)
+			}
+			if accepted != tt.accepted {
+				t.Errorf("accepted = %v, want %v", accepted, tt.accepted)
+			}
+			if got := slotSummary(r); got != tt.want {
+				t.Errorf("slots = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanReview_PlanKeepsStatus(t *testing.T) {
+	r := newTestReview()
+	if err := r.reject(0); err != nil {
+		t.Fatal(err)
+	}
+	plan := r.plan(models.DayPlan{Date: "2025-06-02"})
+	if len(plan.Slots) != 3 || plan.Slots[0].TaskID != "email" || plan.Slots[0].Status != constants.SlotStatusPlanned {
+		t.Errorf("unexpected plan slots: %+v", plan.Slots)
+	}
+}
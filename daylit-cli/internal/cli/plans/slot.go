@@ -0,0 +1,108 @@
+package plans
+
+import (
+	"fmt"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+type SlotCmd struct {
+	Delete  SlotDeleteCmd  `cmd:"" help:"Remove one slot from a day's plan, keeping the rest of the plan."`
+	Restore SlotRestoreCmd `cmd:"" help:"Bring back a slot removed with 'plans slot delete'."`
+}
+
+type SlotDeleteCmd struct {
+	Slot string `arg:"" help:"Slot start time (HH:MM) or task name."`
+	Date string `help:"Date of the plan (YYYY-MM-DD or 'today')." default:"today"`
+}
+
+func (c *SlotDeleteCmd) Run(ctx *cli.Context) error {
+	date, err := parsePlanDate(c.Date, ctx.Now())
+	if err != nil {
+		return err
+	}
+	plan, err := ctx.Store.GetPlan(date)
+	if err != nil {
+		return fmt.Errorf("no plan found for %s", date)
+	}
+	i, err := findSlot(ctx, plan, c.Slot)
+	if err != nil {
+		return err
+	}
+	slot := plan.Slots[i]
+
+	if err := ctx.Store.DeleteSlot(date, plan.Revision, slot.Start, slot.TaskID); err != nil {
+		return fmt.Errorf("failed to delete slot: %w", err)
+	}
+
+	fmt.Printf("Deleted slot %s–%s %s from the plan for %s\n", slot.Start, slot.End, taskName(ctx, slot.TaskID), date)
+	fmt.Printf("(This is a soft delete. Use 'daylit plans slot restore %s --date %s' to undo)\n", slot.Start, date)
+	return nil
+}
+
+type SlotRestoreCmd struct {
+	Slot string `arg:"" help:"Start time (HH:MM) or task name of the deleted slot."`
+	Date string `help:"Date of the plan (YYYY-MM-DD or 'today')." default:"today"`
+}
+
+func (c *SlotRestoreCmd) Run(ctx *cli.Context) error {
+	date, err := parsePlanDate(c.Date, ctx.Now())
+	if err != nil {
+		return err
+	}
+	plan, err := ctx.Store.GetPlan(date)
+	if err != nil {
+		return fmt.Errorf("no plan found for %s", date)
+	}
+
+	deleted, err := deletedSlots(ctx, date, plan.Revision)
+	if err != nil {
+		return err
+	}
+	if len(deleted.Slots) == 0 {
+		return fmt.Errorf("the plan for %s has no deleted slots", date)
+	}
+	i, err := findSlot(ctx, deleted, c.Slot)
+	if err != nil {
+		return err
+	}
+	slot := deleted.Slots[i]
+
+	if err := ctx.Store.RestoreSlot(date, plan.Revision, slot.Start, slot.TaskID); err != nil {
+		return fmt.Errorf("failed to restore slot: %w", err)
+	}
+
+	fmt.Printf("Restored slot %s–%s %s to the plan for %s\n", slot.Start, slot.End, taskName(ctx, slot.TaskID), date)
+	return nil
+}
+
+// deletedSlots returns a copy of the plan revision holding only its
+// individually deleted slots
+func deletedSlots(ctx *cli.Context, date string, revision int) (models.DayPlan, error) {
+	plans, err := ctx.Store.GetAllPlans()
+	if err != nil {
+		return models.DayPlan{}, fmt.Errorf("failed to get plans: %w", err)
+	}
+	for _, p := range plans {
+		if p.Date != date || p.Revision != revision {
+			continue
+		}
+		deleted := p
+		deleted.Slots = nil
+		for _, slot := range p.Slots {
+			if slot.DeletedAt != nil {
+				deleted.Slots = append(deleted.Slots, slot)
+			}
+		}
+		return deleted, nil
+	}
+	return models.DayPlan{}, fmt.Errorf("plan not found: %s revision %d", date, revision)
+}
+
+func taskName(ctx *cli.Context, taskID string) string {
+	if task, err := ctx.Store.GetTask(taskID); err == nil {
+		return task.Name
+	}
+	return "unknown task"
+}
@@ -0,0 +1,46 @@
+package plans
+
+import "testing"
+
+func TestSlotDeleteAndRestore(t *testing.T) {
+	ctx := setupDoneTest(t, false)
+
+	// By task name and by start time
+	if err := (&SlotDeleteCmd{Slot: "Email", Date: "2025-06-02"}).Run(ctx); err != nil {
+		t.Fatalf("delete by name failed: %v", err)
+	}
+	if err := (&SlotDeleteCmd{Slot: "10:30", Date: "2025-06-02"}).Run(ctx); err != nil {
+		t.Fatalf("delete by time failed: %v", err)
+	}
+	want := "09:00-10:00 write, 11:00-12:00 lunch, 12:00-12:30 walk"
+	if got := planSummary(t, ctx); got != want {
+		t.Errorf("slots = %s\nwant    %s", got, want)
+	}
+
+	// The accepted plan is changed in place
+	plan, err := ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Revision != 1 || plan.AcceptedAt == nil {
+		t.Errorf("expected accepted revision 1 to be kept, got revision %d (accepted %v)", plan.Revision, plan.AcceptedAt)
+	}
+
+	if err := (&SlotDeleteCmd{Slot: "10:00", Date: "2025-06-02"}).Run(ctx); err == nil {
+		t.Error("expected deleting a removed slot to fail")
+	}
+
+	if err := (&SlotRestoreCmd{Slot: "10:00", Date: "2025-06-02"}).Run(ctx); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if err := (&SlotRestoreCmd{Slot: "Read", Date: "2025-06-02"}).Run(ctx); err != nil {
+		t.Fatalf("restore by name failed: %v", err)
+	}
+	want = "09:00-10:00 write, 10:00-10:30 email, 10:30-11:00 read, 11:00-12:00 lunch, 12:00-12:30 walk"
+	if got := planSummary(t, ctx); got != want {
+		t.Errorf("slots = %s\nwant    %s", got, want)
+	}
+	if err := (&SlotRestoreCmd{Slot: "10:00", Date: "2025-06-02"}).Run(ctx); err == nil {
+		t.Error("expected restoring with no deleted slots to fail")
+	}
+}
@@ -0,0 +1,301 @@
+package plans
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/i18n"
+	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+// Status classes, used as the CSS class in waybar and to pick the color in
+// polybar and i3blocks
+const (
+	statusActive = "active" // A block is in progress
+	statusEnding = "ending" // The block in progress ends within statusEndingMinutes
+	statusDone   = "done"   // The block in progress was already marked done
+	statusFree   = "free"   // Between blocks, with another one later today
+	statusNone   = "none"   // No plan, or nothing left today
+)
+
+// statusEndingMinutes is how close to its end a block is shown as ending
+const statusEndingMinutes = 5
+
+// statusColors are the polybar and i3blocks colors of each class; classes
+// without one use the bar's own foreground
+var statusColors = map[string]string{
+	statusEnding: "#e0af68",
+	statusDone:   "#9ece6a",
+	statusFree:   "#7f849c",
+}
+
+type StatusCmd struct {
+	Format   string        `short:"f" help:"Output for the status bar." enum:"waybar,polybar,i3blocks" default:"waybar"`
+	CacheTTL time.Duration `name:"cache-ttl" help:"How long to reuse the plan read from the database; 0 reads it on every call." default:"30s"`
+	Empty    string        `help:"Text to show when there is no plan or nothing left today (default: hide the module)."`
+}
+
+// statusBlock is a slot of the plan, with its times on the calendar
+type statusBlock struct {
+	Name    string    `json:"name"`
+	Start   string    `json:"start"`
+	End     string    `json:"end"`
+	Done    bool      `json:"done"`
+	StartAt time.Time `json:"start_at"`
+	EndAt   time.Time `json:"end_at"`
+}
+
+// statusSnapshot is what the status bar shows, cached between polls. It
+// holds calendar times rather than countdowns, so the time remaining stays
+// right when it is read from the cache.
+type statusSnapshot struct {
+	Locale  string       `json:"locale"`
+	Written time.Time    `json:"written"`
+	Expires time.Time    `json:"expires"`
+	Current *statusBlock `json:"current,omitempty"`
+	Next    *statusBlock `json:"next,omitempty"`
+}
+
+func (c *StatusCmd) Run(ctx *cli.Context) error {
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("--cache-ttl cannot be negative")
+	}
+	now := ctx.Now()
+	cachePath := statusCachePath(ctx.Store)
+
+	// The store isn't loaded for status, so a cache hit doesn't touch the
+	// database at all
+	snap, ok := readStatusCache(cachePath, now)
+	if !ok {
+		if err := ctx.Store.Load(); err != nil {
+			return err
+		}
+		var err error
+		if snap, err = buildStatus(ctx, now, c.CacheTTL); err != nil {
+			return err
+		}
+		if c.CacheTTL > 0 {
+			if err := writeStatusCache(cachePath, snap); err != nil {
+				logger.Warn("Failed to write status cache", "path", cachePath, "error", err)
+			}
+		}
+	}
+	i18n.Set(snap.Locale)
+
+	fmt.Println(c.render(snap, now))
+	return nil
+}
+
+// buildStatus reads the current and next blocks from the store. The
+// snapshot expires after ttl, or sooner when the current block ends or the
+// next one starts.
+func buildStatus(ctx *cli.Context, now time.Time, ttl time.Duration) (statusSnapshot, error) {
+	snap := statusSnapshot{Written: now, Expires: now.Add(ttl)}
+
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return snap, fmt.Errorf("failed to get settings: %w", err)
+	}
+	snap.Locale = i18n.Resolve(settings.Locale)
+	// Without valid day boundaries, slots are read as plain clock times
+	window, _ := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+
+	plan, _, hasPlan := currentSlot(ctx, now, window)
+	if !hasPlan {
+		return snap, nil
+	}
+	day, err := time.ParseInLocation(constants.DateFormat, plan.Date, now.Location())
+	if err != nil {
+		return snap, fmt.Errorf("invalid plan date %q: %w", plan.Date, err)
+	}
+
+	countdown := window.CountdownAt(plan.Slots, minutesOnPlan(plan, now), isActiveSlot)
+	block := func(i int) (*statusBlock, error) {
+		slot := plan.Slots[i]
+		start, end, err := window.Range(slot.Start, slot.End)
+		if err != nil {
+			return nil, err
+		}
+		name := i18n.T("now.unknown_task")
+		if task, err := ctx.Store.GetTask(slot.TaskID); err == nil {
+			name = task.Name
+		}
+		return &statusBlock{
+			Name:    name,
+			Start:   slot.Start,
+			End:     slot.End,
+			Done:    slot.Status == constants.SlotStatusDone,
+			StartAt: day.Add(time.Duration(start) * time.Minute),
+			EndAt:   day.Add(time.Duration(end) * time.Minute),
+		}, nil
+	}
+	if countdown.Current >= 0 {
+		if snap.Current, err = block(countdown.Current); err != nil {
+			return snap, err
+		}
+		if snap.Current.EndAt.Before(snap.Expires) {
+			snap.Expires = snap.Current.EndAt
+		}
+	}
+	if countdown.Next >= 0 {
+		if snap.Next, err = block(countdown.Next); err != nil {
+			return snap, err
+		}
+		if snap.Next.StartAt.Before(snap.Expires) {
+			snap.Expires = snap.Next.StartAt
+		}
+	}
+	return snap, nil
+}
+
+// statusClass is the class of snap at now
+func statusClass(snap statusSnapshot, now time.Time) string {
+	switch {
+	case snap.Current != nil && snap.Current.Done:
+		return statusDone
+	case snap.Current != nil && snap.Current.EndAt.Sub(now) <= statusEndingMinutes*time.Minute:
+		return statusEnding
+	case snap.Current != nil:
+		return statusActive
+	case snap.Next != nil:
+		return statusFree
+	}
+	return statusNone
+}
+
+// minutesUntil counts the minutes from now to t, rounding up so a block
+// never shows 0m while it is still running
+func minutesUntil(now, t time.Time) int {
+	d := t.Sub(now)
+	if d <= 0 {
+		return 0
+	}
+	return int((d + time.Minute - time.Nanosecond) / time.Minute)
+}
+
+func (c *StatusCmd) render(snap statusSnapshot, now time.Time) string {
+	class := statusClass(snap, now)
+
+	var text, short string
+	var progress float64
+	switch {
+	case snap.Current != nil:
+		left := utils.FormatMinutes(minutesUntil(now, snap.Current.EndAt))
+		text = i18n.T("status.left", snap.Current.Name, left)
+		short = left
+		if total := snap.Current.EndAt.Sub(snap.Current.StartAt); total > 0 {
+			progress = float64(now.Sub(snap.Current.StartAt)) / float64(total)
+		}
+	case snap.Next != nil:
+		in := utils.FormatMinutes(minutesUntil(now, snap.Next.StartAt))
+		text = i18n.T("status.free_next", snap.Next.Name, in)
+		short = in
+	default:
+		text, short = c.Empty, c.Empty
+	}
+
+	var tooltip []string
+	if snap.Current != nil {
+		tooltip = append(tooltip, fmt.Sprintf("%s–%s  %s", snap.Current.Start, snap.Current.End, snap.Current.Name))
+	}
+	if snap.Next != nil {
+		tooltip = append(tooltip, i18n.T("now.next", snap.Next.Name, utils.FormatMinutes(minutesUntil(now, snap.Next.StartAt))))
+	} else if snap.Current != nil {
+		tooltip = append(tooltip, i18n.T("now.next_none"))
+	}
+
+	color := statusColors[class]
+	switch c.Format {
+	case "polybar":
+		if color == "" || text == "" {
+			return text
+		}
+		return fmt.Sprintf("%%{F%s}%s%%{F-}", color, text)
+	case "i3blocks":
+		// i3blocks reads the full text, the short text and the color, one
+		// per line
+		lines := []string{text, short}
+		if color != "" && text != "" {
+			lines = append(lines, color)
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	out, _ := json.Marshal(struct {
+		Text       string `json:"text"`
+		Alt        string `json:"alt"`
+		Tooltip    string `json:"tooltip"`
+		Class      string `json:"class"`
+		Percentage int    `json:"percentage"`
+	}{text, class, strings.Join(tooltip, "\n"), class, int(min(max(progress, 0), 1) * 100)})
+	return string(out)
+}
+
+// statusCachePath is the cache file of the store, one per database and user
+// so bars for different databases don't share it
+func statusCachePath(store storage.Provider) string {
+	key := store.GetConfigPath()
+	if h, ok := storage.Unwrap(store).(storage.Household); ok {
+		key += "\x00" + h.CurrentUser()
+	}
+	sum := sha256.Sum256([]byte(key))
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, constants.AppName, "status-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// readStatusCache returns the cached snapshot when it was written before
+// now and hasn't expired
+func readStatusCache(path string, now time.Time) (statusSnapshot, bool) {
+	var snap statusSnapshot
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snap, false
+	}
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return snap, false
+	}
+	if now.Before(snap.Written) || !now.Before(snap.Expires) {
+		return snap, false
+	}
+	return snap, true
+}
+
+// writeStatusCache replaces the cache file in one step, so a bar polling at
+// the same time never reads half of it
+func writeStatusCache(path string, snap statusSnapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".status-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
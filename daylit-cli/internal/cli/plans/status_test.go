@@ -0,0 +1,92 @@
+package plans
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBuildStatus(t *testing.T) {
+	ctx := setupDoneTest(t, false)
+
+	snap, err := buildStatus(ctx, at(9, 40), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Current == nil || snap.Current.Name != "Write" || !snap.Current.EndAt.Equal(at(10, 0)) {
+		t.Fatalf("Current = %+v, want Write ending at 10:00", snap.Current)
+	}
+	if snap.Next == nil || snap.Next.Name != "Email" {
+		t.Fatalf("Next = %+v, want Email", snap.Next)
+	}
+	if !snap.Expires.Equal(at(9, 41)) {
+		t.Errorf("Expires = %v, want the TTL", snap.Expires)
+	}
+
+	// The cache never outlives the block in progress
+	snap, err = buildStatus(ctx, at(9, 59), 30*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !snap.Expires.Equal(at(10, 0)) {
+		t.Errorf("Expires = %v, want the end of the block", snap.Expires)
+	}
+
+	snap, err = buildStatus(ctx, at(8, 0), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Current != nil || snap.Next == nil || snap.Next.Name != "Write" {
+		t.Errorf("before the plan: Current = %+v, Next = %+v", snap.Current, snap.Next)
+	}
+	if got := statusClass(snap, at(8, 0)); got != statusFree {
+		t.Errorf("class = %q, want %q", got, statusFree)
+	}
+}
+
+func TestStatusRender(t *testing.T) {
+	snap := statusSnapshot{
+		Locale:  "en",
+		Current: &statusBlock{Name: "Write", Start: "09:00", End: "10:00", StartAt: at(9, 0), EndAt: at(10, 0)},
+	}
+
+	tests := []struct {
+		format string
+		now    time.Time
+		want   string
+	}{
+		{"waybar", at(9, 30), `{"text":"Write · 30m left","alt":"active","tooltip":"09:00–10:00  Write\nNothing else planned today","class":"active","percentage":50}`},
+		{"polybar", at(9, 30), "Write · 30m left"},
+		{"polybar", at(9, 57), "%{F#e0af68}Write · 3m left%{F-}"},
+		{"i3blocks", at(9, 57), "Write · 3m left\n3m\n#e0af68"},
+	}
+	for _, tt := range tests {
+		c := &StatusCmd{Format: tt.format}
+		if got := c.render(snap, tt.now); got != tt.want {
+			t.Errorf("%s at %s = %q, want %q", tt.format, tt.now.Format("15:04"), got, tt.want)
+		}
+	}
+
+	c := &StatusCmd{Format: "polybar", Empty: "idle"}
+	if got := c.render(statusSnapshot{}, at(9, 0)); got != "idle" {
+		t.Errorf("empty = %q, want %q", got, "idle")
+	}
+}
+
+func TestStatusCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.json")
+	snap := statusSnapshot{Locale: "en", Written: at(9, 0), Expires: at(9, 1)}
+	if err := writeStatusCache(path, snap); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := readStatusCache(path, at(9, 0).Add(30*time.Second)); !ok {
+		t.Error("expected a hit before the cache expires")
+	}
+	if _, ok := readStatusCache(path, at(9, 1)); ok {
+		t.Error("expected a miss once the cache expires")
+	}
+	if _, ok := readStatusCache(path, at(8, 59)); ok {
+		t.Error("expected a miss before the cache was written")
+	}
+}
@@ -0,0 +1,148 @@
+package plans
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/hooks"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+type StartCmd struct{}
+
+func (c *StartCmd) Run(ctx *cli.Context) error {
+	return c.start(ctx, ctx.Now())
+}
+
+// start records now as the actual start of the slot that covers now, or of
+// the next slot if none does
+func (c *StartCmd) start(ctx *cli.Context, now time.Time) error {
+	window, err := dayWindow(ctx)
+	if err != nil {
+		return err
+	}
+
+	plan, i, hasPlan := currentSlot(ctx, now, window)
+	if !hasPlan {
+		return fmt.Errorf("no active plan for today")
+	}
+	if j := runningSlot(plan); j >= 0 {
+		return fmt.Errorf("%s is still running; finish it with 'daylit stop' or 'daylit done' first", slotLabel(ctx, plan.Slots[j]))
+	}
+	minutes := minutesOnPlan(plan, now)
+	if i < 0 {
+		i = nextSlot(plan, minutes, window)
+	}
+	if i < 0 {
+		return fmt.Errorf("no slot left to start today")
+	}
+	slot := &plan.Slots[i]
+	if slot.ActualEnd != nil {
+		return fmt.Errorf("%s was already finished at %s", slotLabel(ctx, *slot), *slot.ActualEnd)
+	}
+
+	start, err := window.Minutes(slot.Start)
+	if err != nil {
+		return err
+	}
+	actualStart := now.Format("15:04")
+	slot.ActualStart = &actualStart
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		return err
+	}
+
+	fmt.Printf("Started: %s at %s (%s)\n", slotLabel(ctx, *slot), actualStart, driftText(minutes-start))
+	hooks.Fire(hooks.SlotStarted, hooks.Slot(ctx.Store, plan.Date, *slot))
+	return nil
+}
+
+type StopCmd struct{}
+
+func (c *StopCmd) Run(ctx *cli.Context) error {
+	return c.stop(ctx, ctx.Now())
+}
+
+// stop records now as the actual end of the running slot and marks it done,
+// leaving the rating to 'daylit feedback'
+func (c *StopCmd) stop(ctx *cli.Context, now time.Time) error {
+	window, err := dayWindow(ctx)
+	if err != nil {
+		return err
+	}
+
+	plan, _, hasPlan := currentSlot(ctx, now, window)
+	if !hasPlan {
+		return fmt.Errorf("no active plan for today")
+	}
+	i := runningSlot(plan)
+	if i < 0 {
+		return fmt.Errorf("no slot is running (start one with 'daylit start')")
+	}
+	slot := &plan.Slots[i]
+
+	_, end, err := window.Range(slot.Start, slot.End)
+	if err != nil {
+		return err
+	}
+	actualEnd := now.Format("15:04")
+	slot.ActualEnd = &actualEnd
+	slot.Status = constants.SlotStatusDone
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		return err
+	}
+
+	drift := minutesOnPlan(plan, now) - end
+	fmt.Printf("Stopped: %s at %s (%s)\n", slotLabel(ctx, *slot), actualEnd, driftText(drift))
+	hooks.Fire(hooks.SlotDone, hooks.Slot(ctx.Store, plan.Date, *slot))
+	if drift != 0 && i < len(plan.Slots)-1 {
+		fmt.Println("Run 'daylit reflow' to move the rest of the day to match.")
+	}
+	return nil
+}
+
+// runningSlot returns the index of the slot that was started and not yet
+// finished, or -1
+func runningSlot(plan models.DayPlan) int {
+	for i, slot := range plan.Slots {
+		if slot.ActualStart != nil && slot.ActualEnd == nil && slot.Status != constants.SlotStatusDone {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextSlot returns the index of the earliest accepted slot that starts after
+// minutes, or -1
+func nextSlot(plan models.DayPlan, minutes int, window models.DayWindow) int {
+	next, nextStart := -1, 0
+	for i, slot := range plan.Slots {
+		if slot.Status != constants.SlotStatusAccepted {
+			continue
+		}
+		start, err := window.Minutes(slot.Start)
+		if err == nil && start > minutes && (next < 0 || start < nextStart) {
+			next, nextStart = i, start
+		}
+	}
+	return next
+}
+
+// dayWindow returns the day boundaries from the settings
+func dayWindow(ctx *cli.Context) (models.DayWindow, error) {
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return models.DayWindow{}, fmt.Errorf("failed to get settings: %w", err)
+	}
+	return models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+}
+
+// slotLabel describes slot by its times and task name
+func slotLabel(ctx *cli.Context, slot models.Slot) string {
+	name := "Unknown task"
+	if task, err := ctx.Store.GetTask(slot.TaskID); err == nil {
+		name = task.Name
+	}
+	return fmt.Sprintf("%s–%s  %s", slot.Start, slot.End, name)
+}
@@ -0,0 +1,120 @@
+package plans
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2025, 6, 2, hour, minute, 0, 0, time.Local)
+}
+
+func TestStartStopAndReflow(t *testing.T) {
+	ctx := setupDoneTest(t, false)
+
+	if err := (&StartCmd{}).start(ctx, at(9, 5)); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := (&StartCmd{}).start(ctx, at(9, 10)); err == nil {
+		t.Error("expected error starting a second slot while one is running")
+	}
+	if err := (&ReflowCmd{}).reflow(ctx, at(9, 30)); err == nil {
+		t.Error("expected error reflowing while the running slot is on time")
+	}
+
+	if err := (&StopCmd{}).stop(ctx, at(10, 20)); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	plan, err := ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slot := plan.Slots[0]
+	if slot.ActualStart == nil || *slot.ActualStart != "09:05" || slot.ActualEnd == nil || *slot.ActualEnd != "10:20" {
+		t.Errorf("tracked times = %v–%v, want 09:05–10:20", slot.ActualStart, slot.ActualEnd)
+	}
+	if slot.Status != constants.SlotStatusDone || slot.Feedback != nil {
+		t.Errorf("stopped slot should be done without feedback: %+v", slot)
+	}
+
+	if err := (&ReflowCmd{DryRun: true}).reflow(ctx, at(10, 25)); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if plan, _ := ctx.Store.GetPlan("2025-06-02"); plan.Revision != 1 {
+		t.Errorf("dry run saved revision %d", plan.Revision)
+	}
+
+	if err := (&ReflowCmd{}).reflow(ctx, at(10, 25)); err != nil {
+		t.Fatalf("reflow failed: %v", err)
+	}
+	// Read no longer fits before lunch, so it follows it
+	want := "09:00-10:20 write, 10:20-10:50 email, 11:00-12:00 lunch, 12:00-12:30 read, 12:30-13:00 walk"
+	if got := planSummary(t, ctx); got != want {
+		t.Errorf("slots = %s\nwant    %s", got, want)
+	}
+	plan, err = ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Revision != 2 || plan.AcceptedAt == nil {
+		t.Errorf("reflow should save an accepted revision 2, got revision %d accepted %v", plan.Revision, plan.AcceptedAt)
+	}
+
+	// The new revision already follows the slot, so there is nothing left to move
+	if err := (&ReflowCmd{}).reflow(ctx, at(10, 30)); err != nil {
+		t.Fatalf("second reflow failed: %v", err)
+	}
+	if plan, _ := ctx.Store.GetPlan("2025-06-02"); plan.Revision != 2 {
+		t.Errorf("second reflow saved revision %d", plan.Revision)
+	}
+}
+
+func TestReflow_RunningOverTime(t *testing.T) {
+	ctx := setupDoneTest(t, false)
+
+	if err := (&StartCmd{}).start(ctx, at(9, 0)); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := (&ReflowCmd{}).reflow(ctx, at(10, 15)); err != nil {
+		t.Fatalf("reflow failed: %v", err)
+	}
+	want := "09:00-10:15 write, 10:15-10:45 email, 11:00-12:00 lunch, 12:00-12:30 read, 12:30-13:00 walk"
+	if got := planSummary(t, ctx); got != want {
+		t.Errorf("slots = %s\nwant    %s", got, want)
+	}
+
+	// The slot is still running in the new revision
+	if err := (&DoneCmd{}).finish(ctx, at(10, 40)); err != nil {
+		t.Fatalf("done failed: %v", err)
+	}
+	plan, err := ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Slots[0].Status != constants.SlotStatusDone || plan.Slots[1].Status == constants.SlotStatusDone {
+		t.Errorf("done should finish the running slot, got %+v", plan.Slots[:2])
+	}
+}
+
+func TestStart_NextSlot(t *testing.T) {
+	ctx := setupDoneTest(t, false)
+
+	if err := (&StartCmd{}).start(ctx, at(8, 50)); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	plan, err := ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start := plan.Slots[0].ActualStart; start == nil || *start != "08:50" {
+		t.Errorf("expected the next slot to start at 08:50, got %v", start)
+	}
+	if err := (&StopCmd{}).stop(ctx, at(8, 55)); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if err := (&StopCmd{}).stop(ctx, at(9, 0)); err == nil {
+		t.Error("expected error when no slot is running")
+	}
+}
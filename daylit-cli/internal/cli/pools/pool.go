@@ -0,0 +1,135 @@
+package pools
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+)
+
+type PoolCmd struct {
+	Add    PoolAddCmd    `cmd:"" help:"Add a task pool, optionally with its tasks."`
+	List   PoolListCmd   `cmd:"" help:"List task pools, their tasks and today's pick." default:"1"`
+	Delete PoolDeleteCmd `cmd:"" help:"Delete a task pool; its tasks are scheduled on their own again."`
+}
+
+type PoolAddCmd struct {
+	Name     string   `arg:"" help:"Pool name."`
+	Tasks    []string `arg:"" optional:"" help:"Tasks (ID or name) to put in the pool. Use 'daylit task edit --pool' to add more later."`
+	Strategy string   `short:"s" help:"How the day's task is picked: round_robin (take turns) or least_recent (done longest ago)." default:"round_robin"`
+}
+
+func (c *PoolAddCmd) Run(ctx *cli.Context) error {
+	name := strings.TrimSpace(c.Name)
+	if _, err := ctx.Store.GetPoolByName(name); err == nil {
+		return fmt.Errorf("task pool %q already exists", name)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to check for existing task pool: %w", err)
+	}
+
+	pool := models.TaskPool{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Strategy:  constants.PoolStrategy(c.Strategy),
+		CreatedAt: ctx.Now(),
+	}
+	if err := pool.Validate(); err != nil {
+		return fmt.Errorf("invalid task pool: %w", err)
+	}
+
+	// Resolve every task before saving anything
+	var members []models.Task
+	for _, ref := range c.Tasks {
+		task, err := ctx.ResolveTask(ref, cli.LiveTasks)
+		if err != nil {
+			return err
+		}
+		members = append(members, task)
+	}
+
+	if err := ctx.Store.AddPool(pool); err != nil {
+		return fmt.Errorf("failed to add task pool: %w", err)
+	}
+	for _, task := range members {
+		if task.PoolID != "" {
+			fmt.Printf("Moving %s out of its previous pool\n", task.Name)
+		}
+		task.PoolID = pool.ID
+		if err := ctx.Store.UpdateTask(task); err != nil {
+			return fmt.Errorf("failed to add %s to the pool: %w", task.Name, err)
+		}
+	}
+
+	fmt.Printf("Added task pool: %s (%s, %d tasks)\n", pool.Name, pool.Strategy, len(members))
+	return nil
+}
+
+type PoolListCmd struct{}
+
+func (c *PoolListCmd) Run(ctx *cli.Context) error {
+	pools, err := ctx.Store.GetAllPools()
+	if err != nil {
+		return fmt.Errorf("failed to get task pools: %w", err)
+	}
+	if len(pools) == 0 {
+		fmt.Println("No task pools found")
+		return nil
+	}
+
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	byPool := make(map[string][]string)
+	for _, t := range tasks {
+		if t.PoolID != "" {
+			byPool[t.PoolID] = append(byPool[t.PoolID], t.Name)
+		}
+	}
+
+	// The members left after picking are today's choices
+	picked := make(map[string]string)
+	for _, t := range scheduler.ChoosePoolMembers(tasks, pools, ctx.Now()) {
+		if t.PoolID != "" {
+			picked[t.PoolID] = t.Name
+		}
+	}
+
+	fmt.Println("Task pools:")
+	for _, p := range pools {
+		fmt.Printf("  %s (%s)\n", p.Name, p.Strategy)
+		if names := byPool[p.ID]; len(names) > 0 {
+			fmt.Printf("      Tasks: %s\n", strings.Join(names, ", "))
+		}
+		if name, ok := picked[p.ID]; ok {
+			fmt.Printf("      Today: %s\n", name)
+		}
+	}
+	return nil
+}
+
+type PoolDeleteCmd struct {
+	Name string `arg:"" help:"Pool name."`
+}
+
+func (c *PoolDeleteCmd) Run(ctx *cli.Context) error {
+	pool, err := ctx.Store.GetPoolByName(strings.TrimSpace(c.Name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("task pool %q not found", c.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get task pool: %w", err)
+	}
+	if err := ctx.Store.DeletePool(pool.ID); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted task pool: %s\n", pool.Name)
+	return nil
+}
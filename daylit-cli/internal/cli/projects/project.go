@@ -0,0 +1,201 @@
+package projects
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+type ProjectCmd struct {
+	Add    ProjectAddCmd    `cmd:"" help:"Add a new project."`
+	List   ProjectListCmd   `cmd:"" help:"List projects and their tasks." default:"1"`
+	Report ProjectReportCmd `cmd:"" help:"Show hours spent per project this week versus target."`
+}
+
+type ProjectAddCmd struct {
+	Name   string  `arg:"" help:"Project name."`
+	Target float64 `short:"t" help:"Target hours per week."`
+	Color  string  `short:"c" help:"Display color as #RRGGBB."`
+}
+
+func (c *ProjectAddCmd) Run(ctx *cli.Context) error {
+	name := strings.TrimSpace(c.Name)
+	if _, err := ctx.Store.GetProjectByName(name); err == nil {
+		return fmt.Errorf("project %q already exists", name)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to check for existing project: %w", err)
+	}
+
+	project := models.Project{
+		ID:                 uuid.New().String(),
+		Name:               name,
+		TargetHoursPerWeek: c.Target,
+		Color:              c.Color,
+		CreatedAt:          ctx.Now(),
+	}
+	if err := project.Validate(); err != nil {
+		return fmt.Errorf("invalid project: %w", err)
+	}
+	if err := ctx.Store.AddProject(project); err != nil {
+		return fmt.Errorf("failed to add project: %w", err)
+	}
+
+	fmt.Printf("Added project: %s (ID: %s)\n", project.Name, project.ID)
+	return nil
+}
+
+type ProjectListCmd struct{}
+
+func (c *ProjectListCmd) Run(ctx *cli.Context) error {
+	projects, err := ctx.Store.GetAllProjects()
+	if err != nil {
+		return fmt.Errorf("failed to get projects: %w", err)
+	}
+	if len(projects) == 0 {
+		fmt.Println("No projects found")
+		return nil
+	}
+
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	byProject := make(map[string][]string)
+	for _, t := range tasks {
+		if t.ProjectID != "" {
+			byProject[t.ProjectID] = append(byProject[t.ProjectID], t.Name)
+		}
+	}
+
+	fmt.Println("Projects:")
+	for _, p := range projects {
+		details := []string{fmt.Sprintf("target %s/week", formatHours(p.TargetHoursPerWeek*60))}
+		if p.Color != "" {
+			details = append(details, p.Color)
+		}
+		fmt.Printf("  %s (%s)\n", p.Name, strings.Join(details, ", "))
+		if names := byProject[p.ID]; len(names) > 0 {
+			fmt.Printf("      Tasks: %s\n", strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+type ProjectReportCmd struct {
+	Week string `help:"Any date in the week to report (YYYY-MM-DD or 'today')." default:"today"`
+}
+
+// ProjectHours is the time planned and done for one project in a week
+type ProjectHours struct {
+	Project        models.Project
+	PlannedMinutes int
+	DoneMinutes    int
+}
+
+func (c *ProjectReportCmd) Run(ctx *cli.Context) error {
+	day := ctx.Now()
+	if c.Week != "today" {
+		var err error
+		if day, err = time.ParseInLocation(constants.DateFormat, c.Week, time.Local); err != nil {
+			return fmt.Errorf("invalid date %q, use YYYY-MM-DD or 'today'", c.Week)
+		}
+	}
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+	first := settings.FirstWeekday()
+	start := utils.StartOfWeek(day, first)
+	end := start.AddDate(0, 0, 6)
+	from := start.Format(constants.DateFormat)
+	to := end.Format(constants.DateFormat)
+
+	report, unassigned, err := weeklyHours(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Project hours for week %d, %s to %s\n\n", utils.WeekNumber(end, first), from, to)
+	if len(report) == 0 {
+		fmt.Println("No projects found")
+		return nil
+	}
+
+	fmt.Printf("%-24s %-9s %-9s %-9s %s\n", "PROJECT", "DONE", "PLANNED", "TARGET", "PROGRESS")
+	for _, r := range report {
+		name := r.Project.Name
+		if len(name) > 22 {
+			name = name[:19] + "..."
+		}
+		fmt.Printf("%-24s %-9s %-9s %-9s %s\n", name, formatHours(float64(r.DoneMinutes)),
+			formatHours(float64(r.PlannedMinutes)), formatHours(r.Project.TargetHoursPerWeek*60), formatProgress(r))
+	}
+	if unassigned.PlannedMinutes > 0 {
+		fmt.Printf("%-24s %-9s %-9s\n", "(no project)", formatHours(float64(unassigned.DoneMinutes)),
+			formatHours(float64(unassigned.PlannedMinutes)))
+	}
+	return nil
+}
+
+// weeklyHours sums planned and done minutes per project for the inclusive
+// date range. Time on tasks without a project is returned separately.
+func weeklyHours(ctx *cli.Context, from, to string) ([]ProjectHours, ProjectHours, error) {
+	projects, err := ctx.Store.GetAllProjects()
+	if err != nil {
+		return nil, ProjectHours{}, fmt.Errorf("failed to get projects: %w", err)
+	}
+	// Deleted tasks still count toward the weeks they were scheduled in
+	tasks, err := ctx.Store.GetAllTasksIncludingDeleted()
+	if err != nil {
+		return nil, ProjectHours{}, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	stats, err := ctx.Store.GetTaskStats(from, to)
+	if err != nil {
+		return nil, ProjectHours{}, fmt.Errorf("failed to get task stats: %w", err)
+	}
+
+	taskProject := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		taskProject[t.ID] = t.ProjectID
+	}
+
+	report := make([]ProjectHours, len(projects))
+	index := make(map[string]int, len(projects))
+	for i, p := range projects {
+		report[i].Project = p
+		index[p.ID] = i
+	}
+
+	var unassigned ProjectHours
+	for _, s := range stats {
+		row := &unassigned
+		if i, ok := index[taskProject[s.TaskID]]; ok {
+			row = &report[i]
+		}
+		row.PlannedMinutes += s.PlannedMinutes
+		row.DoneMinutes += s.DoneMinutes
+	}
+	return report, unassigned, nil
+}
+
+// formatHours formats minutes as hours, e.g. "2.5h"
+func formatHours(minutes float64) string {
+	return fmt.Sprintf("%.1fh", minutes/60)
+}
+
+// formatProgress shows done time as a percentage of the weekly target
+func formatProgress(r ProjectHours) string {
+	if r.Project.TargetHoursPerWeek <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", int(float64(r.DoneMinutes)/(r.Project.TargetHoursPerWeek*60)*100+0.5))
+}
@@ -0,0 +1,130 @@
+package projects
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+func setupTestDB(t *testing.T) (*cli.Context, func()) {
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+
+	ctx := &cli.Context{
+		Store:     store,
+		Scheduler: scheduler.New(),
+	}
+
+	cleanup := func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("failed to close store: %v", err)
+		}
+	}
+
+	return ctx, cleanup
+}
+
+func TestProjectAddCmd(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	cmd := &ProjectAddCmd{Name: "Writing", Target: 5, Color: "#ff8800"}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("project add failed: %v", err)
+	}
+	if err := cmd.Run(ctx); err == nil {
+		t.Error("expected error when adding a duplicate project")
+	}
+
+	bad := &ProjectAddCmd{Name: "Fitness", Target: -1}
+	if err := bad.Run(ctx); err == nil {
+		t.Error("expected error for a negative target")
+	}
+
+	if err := (&ProjectListCmd{}).Run(ctx); err != nil {
+		t.Errorf("project list failed: %v", err)
+	}
+}
+
+func TestWeeklyHours(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"Writing", "Fitness"} {
+		if err := (&ProjectAddCmd{Name: name, Target: 4}).Run(ctx); err != nil {
+			t.Fatalf("project add failed: %v", err)
+		}
+	}
+	writing, err := ctx.Store.GetProjectByName("Writing")
+	if err != nil {
+		t.Fatalf("failed to get project: %v", err)
+	}
+
+	tasks := []models.Task{
+		{ID: "write", Name: "Write", Kind: constants.TaskKindFlexible, DurationMin: 60, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 1, Active: true, ProjectID: writing.ID},
+		{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 3, Active: true},
+	}
+	for _, task := range tasks {
+		if err := ctx.Store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+	}
+
+	plans := []models.DayPlan{
+		{Date: "2024-05-05", Slots: []models.Slot{
+			{Start: "09:00", End: "10:30", TaskID: "write", Status: constants.SlotStatusDone},
+			{Start: "11:00", End: "11:30", TaskID: "email", Status: constants.SlotStatusDone},
+		}},
+		{Date: "2024-05-06", Slots: []models.Slot{
+			{Start: "09:00", End: "10:00", TaskID: "write", Status: constants.SlotStatusSkipped},
+		}},
+		// The following Sunday starts a new week
+		{Date: "2024-05-12", Slots: []models.Slot{
+			{Start: "09:00", End: "10:00", TaskID: "write", Status: constants.SlotStatusDone},
+		}},
+	}
+	for _, plan := range plans {
+		if err := ctx.Store.SavePlan(plan); err != nil {
+			t.Fatalf("failed to save plan: %v", err)
+		}
+	}
+
+	start := utils.StartOfWeek(time.Date(2024, 5, 8, 15, 0, 0, 0, time.Local), time.Sunday)
+	if got := start.Format(constants.DateFormat); got != "2024-05-05" {
+		t.Fatalf("StartOfWeek = %s, want 2024-05-05", got)
+	}
+
+	report, unassigned, err := weeklyHours(ctx, "2024-05-05", "2024-05-11")
+	if err != nil {
+		t.Fatalf("weeklyHours failed: %v", err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("expected 2 projects, got %d", len(report))
+	}
+
+	byName := map[string]ProjectHours{}
+	for _, r := range report {
+		byName[r.Project.Name] = r
+	}
+	if w := byName["Writing"]; w.PlannedMinutes != 150 || w.DoneMinutes != 90 {
+		t.Errorf("Writing = %d planned / %d done, want 150 / 90", w.PlannedMinutes, w.DoneMinutes)
+	}
+	if f := byName["Fitness"]; f.PlannedMinutes != 0 || f.DoneMinutes != 0 {
+		t.Errorf("Fitness should have no time, got %+v", f)
+	}
+	if unassigned.PlannedMinutes != 30 || unassigned.DoneMinutes != 30 {
+		t.Errorf("unassigned = %+v, want 30 / 30", unassigned)
+	}
+
+	if got := formatProgress(byName["Writing"]); got != "38%" {
+		t.Errorf("formatProgress = %s, want 38%%", got)
+	}
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/mattn/go-isatty"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// TaskScope selects which tasks ResolveTask searches
+type TaskScope int
+
+const (
+	LiveTasks    TaskScope = iota // Tasks that haven't been deleted
+	DeletedTasks                  // Soft-deleted tasks only
+	AllTasks                      // Live and deleted tasks
+)
+
+// Match quality, best first
+const (
+	matchExact = iota
+	matchPrefix
+	matchContains
+	matchTypo
+	matchNone
+)
+
+// ResolveTask finds the task that ref refers to: an exact ID, an exact
+// case-insensitive name, or failing that a fuzzy match on the name. When
+// several tasks match equally well the user picks one, or an error lists them
+// if stdin isn't a terminal.
+func (c *Context) ResolveTask(ref string, scope TaskScope) (models.Task, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return models.Task{}, fmt.Errorf("task ID or name is required")
+	}
+
+	tasks, err := c.Store.GetAllTasksIncludingDeleted()
+	if err != nil {
+		return models.Task{}, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	var candidates []models.Task
+	for _, task := range tasks {
+		deleted := task.DeletedAt != nil
+		if (scope == LiveTasks && deleted) || (scope == DeletedTasks && !deleted) {
+			continue
+		}
+		candidates = append(candidates, task)
+	}
+
+	matches := MatchTasks(candidates, ref)
+	switch len(matches) {
+	case 0:
+		return models.Task{}, fmt.Errorf("%stask not found: %s (see 'daylit task list')", scopeLabel(scope), ref)
+	case 1:
+		return matches[0], nil
+	}
+	if !StdinIsTerminal() {
+		names := make([]string, len(matches))
+		for i, task := range matches {
+			names[i] = fmt.Sprintf("%s (%s)", task.Name, task.ID)
+		}
+		return models.Task{}, fmt.Errorf("%q matches %d tasks; use one of the IDs: %s", ref, len(matches), strings.Join(names, ", "))
+	}
+	return promptForTask(ref, matches)
+}
+
+// MatchTasks returns the tasks whose ID or name best match ref. An exact ID
+// wins outright. Otherwise exact names beat names that start with ref, which
+// beat names containing it, which beat names within a typo or two of it; only
+// the best group is returned. Among equally good matches, live tasks are
+// preferred over deleted ones.
+func MatchTasks(tasks []models.Task, ref string) []models.Task {
+	ref = strings.TrimSpace(ref)
+	best := matchNone
+	var matches []models.Task
+	for _, task := range tasks {
+		if task.ID == ref {
+			return []models.Task{task}
+		}
+		quality := matchName(task.Name, ref)
+		if quality < best {
+			best = quality
+			matches = matches[:0]
+		}
+		if quality == best && quality != matchNone {
+			matches = append(matches, task)
+		}
+	}
+
+	var live []models.Task
+	for _, task := range matches {
+		if task.DeletedAt == nil {
+			live = append(live, task)
+		}
+	}
+	if len(live) > 0 {
+		matches = live
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return strings.ToLower(matches[i].Name) < strings.ToLower(matches[j].Name)
+	})
+	return matches
+}
+
+// matchName rates how well name matches ref, ignoring case
+func matchName(name, ref string) int {
+	name, ref = strings.ToLower(strings.TrimSpace(name)), strings.ToLower(ref)
+	switch {
+	case ref == "":
+		return matchNone
+	case name == ref:
+		return matchExact
+	case strings.HasPrefix(name, ref):
+		return matchPrefix
+	case strings.Contains(name, ref):
+		return matchContains
+	}
+
+	// Allow roughly one typo per four characters, so short refs must be close
+	maxEdits := len([]rune(ref)) / 4
+	if maxEdits > 0 && editDistance(name, ref) <= maxEdits {
+		return matchTypo
+	}
+	return matchNone
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func scopeLabel(scope TaskScope) string {
+	if scope == DeletedTasks {
+		return "deleted "
+	}
+	return ""
+}
+
+// StdinIsTerminal reports whether stdin is a terminal someone can answer
+// prompts on, rather than a pipe, a file or cron
+func StdinIsTerminal() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
+}
+
+func promptForTask(ref string, matches []models.Task) (models.Task, error) {
+	options := make([]huh.Option[int], len(matches))
+	for i, task := range matches {
+		label := fmt.Sprintf("%s (%s)", task.Name, task.ID)
+		if task.DeletedAt != nil {
+			label += " [deleted]"
+		}
+		options[i] = huh.NewOption(label, i)
+	}
+
+	var choice int
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[int]().
+				Title(fmt.Sprintf("%q matches %d tasks. Which one?", ref, len(matches))).
+				Options(options...).
+				Value(&choice),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return models.Task{}, fmt.Errorf("task selection cancelled: %w", err)
+	}
+	return matches[choice], nil
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+)
+
+func TestMatchTasks(t *testing.T) {
+	deleted := time.Now().UTC().Format(time.RFC3339)
+	tasks := []models.Task{
+		{ID: "id-read", Name: "Read"},
+		{ID: "id-reading", Name: "Reading"},
+		{ID: "id-reading-list", Name: "Reading list"},
+		{ID: "id-groceries", Name: "Groceries"},
+		{ID: "id-email", Name: "Answer email"},
+		{ID: "id-old-email", Name: "Answer email", DeletedAt: &deleted},
+		{ID: "id-old-laundry", Name: "Laundry", DeletedAt: &deleted},
+	}
+
+	tests := []struct {
+		name string
+		ref  string
+		want []string
+	}{
+		{name: "exact id", ref: "id-reading", want: []string{"id-reading"}},
+		{name: "exact name beats prefix", ref: "read", want: []string{"id-read"}},
+		{name: "prefix", ref: "readi", want: []string{"id-reading", "id-reading-list"}},
+		{name: "contains", ref: "email", want: []string{"id-email"}},
+		{name: "typo", ref: "grocreies", want: []string{"id-groceries"}},
+		{name: "short refs need to be close", ref: "rid", want: nil},
+		{name: "deleted when nothing live matches", ref: "laundry", want: []string{"id-old-laundry"}},
+		{name: "no match", ref: "swim", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := MatchTasks(tasks, tt.ref)
+			var got []string
+			for _, task := range matches {
+				got = append(got, task.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("MatchTasks(%q) = %v, want %v", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveTask(t *testing.T) {
+	store := sqlite.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+	ctx := &Context{Store: store}
+
+	for _, task := range []models.Task{
+		{ID: "walk-1", Name: "Walk dog", Kind: constants.TaskKindFlexible, DurationMin: 20, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 3, Active: true},
+		{ID: "walk-2", Name: "Walk to work", Kind: constants.TaskKindFlexible, DurationMin: 20, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 3, Active: true},
+		{ID: "stretch", Name: "Stretch", Kind: constants.TaskKindFlexible, DurationMin: 10, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 3, Active: true},
+	} {
+		if err := store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+	}
+	if err := store.DeleteTask("stretch"); err != nil {
+		t.Fatal(err)
+	}
+
+	if task, err := ctx.ResolveTask("walk dog", LiveTasks); err != nil || task.ID != "walk-1" {
+		t.Errorf("resolve by name = %q, %v", task.ID, err)
+	}
+
+	// Tests don't run on a terminal, so ambiguity is an error listing the IDs
+	_, err := ctx.ResolveTask("walk", LiveTasks)
+	if err == nil || !strings.Contains(err.Error(), "walk-1") || !strings.Contains(err.Error(), "walk-2") {
+		t.Errorf("expected ambiguous match error, got %v", err)
+	}
+
+	if _, err := ctx.ResolveTask("stretch", LiveTasks); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("deleted task should not resolve as live, got %v", err)
+	}
+	if task, err := ctx.ResolveTask("strech", DeletedTasks); err != nil || task.ID != "stretch" {
+		t.Errorf("resolve deleted task = %q, %v", task.ID, err)
+	}
+	if _, err := ctx.ResolveTask("walk dog", DeletedTasks); err == nil {
+		t.Error("live task should not resolve as deleted")
+	}
+	if task, err := ctx.ResolveTask("Stretch", AllTasks); err != nil || task.ID != "stretch" {
+		t.Errorf("resolve any task = %q, %v", task.ID, err)
+	}
+}
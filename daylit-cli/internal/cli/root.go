@@ -1,12 +1,16 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/backup"
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/config"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
 	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
@@ -17,10 +21,63 @@ import (
 type Context struct {
 	Store     storage.Provider
 	Scheduler *scheduler.Scheduler
+	Config    config.Config
+	Clock     clock.Clock // The system clock when nil
+}
+
+// Now returns the current time by the context's clock
+func (c *Context) Now() time.Time {
+	return clock.Or(c.Clock).Now()
+}
+
+// InTx runs fn with c.Store in one transaction, so the writes fn makes
+// through it are all saved or, when fn returns an error, none are
+func (c *Context) InTx(fn func() error) error {
+	store := c.Store
+	defer func() { c.Store = store }()
+	return store.WithTx(func(tx storage.Provider) error {
+		c.Store = tx
+		return fn()
+	})
+}
+
+// PastDate resolves a --date flag for recording something that happened:
+// empty means today, and dates after today are refused
+func (c *Context) PastDate(date string) (string, error) {
+	today := c.Now().Format(constants.DateFormat)
+	if date == "" {
+		return today, nil
+	}
+	if _, err := time.Parse(constants.DateFormat, date); err != nil {
+		return "", fmt.Errorf("invalid date format: %s (expected YYYY-MM-DD)", date)
+	}
+	if date > today {
+		return "", fmt.Errorf("%s is in the future; only today or earlier days can be recorded", date)
+	}
+	return date, nil
+}
+
+// Confirm prints question and reports whether the answer on stdin was yes.
+// It answers yes without asking when confirm is off in config.toml.
+func (c *Context) Confirm(question string) (bool, error) {
+	if !c.Config.Confirms() {
+		return true, nil
+	}
+	fmt.Printf("%s [y/N]: ", question)
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes", nil
 }
 
 // PerformAutomaticBackup creates an automatic backup and silently handles errors
 func (c *Context) PerformAutomaticBackup() {
+	// There is no file behind an in-memory store
+	if _, ok := storage.Unwrap(c.Store).(*storage.MemoryStore); ok {
+		return
+	}
 	mgr := backup.NewManager(c.Store.GetConfigPath())
 	_, err := mgr.CreateBackup()
 	if err != nil {
@@ -102,8 +159,20 @@ func ParseWeekday(s string) (time.Weekday, error) {
 	return time.Sunday, fmt.Errorf("invalid weekday: %s", s)
 }
 
-// FormatRecurrence formats a recurrence rule into a human-readable string
+// FormatRecurrence formats a recurrence rule into a human-readable string,
+// with the count or date it ends after
 func FormatRecurrence(rec models.Recurrence) string {
+	text := formatRule(rec)
+	if rec.Count > 0 {
+		text += fmt.Sprintf(", %d times from %s", rec.Count, rec.Start)
+	}
+	if rec.Until != "" {
+		text += ", until " + rec.Until
+	}
+	return text
+}
+
+func formatRule(rec models.Recurrence) string {
 	switch rec.Type {
 	case constants.RecurrenceDaily:
 		return "daily"
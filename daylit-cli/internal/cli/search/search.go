@@ -0,0 +1,39 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+)
+
+type SearchCmd struct {
+	Query []string `arg:"" help:"Words to search for in task names, habit names, OT entries, and feedback notes."`
+	Limit int      `help:"Maximum number of results to show (0 for all)." default:"20"`
+}
+
+func (c *SearchCmd) Run(ctx *cli.Context) error {
+	query := strings.Join(c.Query, " ")
+	results, err := ctx.Store.Search(query, c.Limit)
+	if err != nil {
+		return fmt.Errorf("failed to search: %w", err)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No matches for %q\n", query)
+		return nil
+	}
+
+	fmt.Printf("%-6s %-10s %s\n", "KIND", "DATE", "TITLE")
+	for _, r := range results {
+		date := r.Date
+		if date == "" {
+			date = "-"
+		}
+		fmt.Printf("%-6s %-10s %s\n", r.Kind, date, r.Title)
+		if r.Snippet != "" {
+			fmt.Printf("%-17s %s\n", "", r.Snippet)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func setupTestDB(t *testing.T) (*cli.Context, func()) {
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+
+	ctx := &cli.Context{
+		Store:     store,
+		Scheduler: scheduler.New(),
+	}
+
+	cleanup := func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("failed to close store: %v", err)
+		}
+	}
+
+	return ctx, cleanup
+}
+
+func TestSearchCmd(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	task := models.Task{
+		ID:          "task-1",
+		Name:        "Water plants",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 10,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+		Active:      true,
+	}
+	if err := ctx.Store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	for _, query := range [][]string{{"water"}, {"no", "such", "thing"}} {
+		cmd := &SearchCmd{Query: query, Limit: 20}
+		if err := cmd.Run(ctx); err != nil {
+			t.Errorf("search %v failed: %v", query, err)
+		}
+	}
+}
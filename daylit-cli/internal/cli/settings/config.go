@@ -0,0 +1,127 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/config"
+)
+
+type ConfigCmd struct {
+	List ConfigListCmd `cmd:"" help:"List the preferences in config.toml." default:"1"`
+	Get  ConfigGetCmd  `cmd:"" help:"Print a preference from config.toml."`
+	Set  ConfigSetCmd  `cmd:"" help:"Change a preference in config.toml."`
+	Path ConfigPathCmd `cmd:"" help:"Print the location of config.toml."`
+	Edit ConfigEditCmd `cmd:"" help:"Open config.toml in an editor."`
+}
+
+type ConfigListCmd struct{}
+
+func (c *ConfigListCmd) Run(ctx *cli.Context) error {
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Preferences (%s):\n", config.Path())
+	for _, key := range config.Keys {
+		value, _ := cfg.Get(key.Name)
+		if value == "" {
+			value = fmt.Sprintf("(default: %s)", key.Default)
+		}
+		fmt.Printf("  %-14s %-25s %s\n", key.Name, value, key.Help)
+	}
+	return nil
+}
+
+type ConfigGetCmd struct {
+	Key string `arg:"" help:"Preference to print (see 'daylit config list')."`
+}
+
+func (c *ConfigGetCmd) Run(ctx *cli.Context) error {
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return err
+	}
+	value, err := cfg.Get(c.Key)
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
+
+type ConfigSetCmd struct {
+	Key   string `arg:"" help:"Preference to change (see 'daylit config list')."`
+	Value string `arg:"" help:"New value, or \"\" to restore the default."`
+}
+
+func (c *ConfigSetCmd) Run(ctx *cli.Context) error {
+	path := config.Path()
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Set(c.Key, c.Value); err != nil {
+		return err
+	}
+	if err := config.Save(path, cfg); err != nil {
+		return fmt.Errorf("failed to save %s: %w", path, err)
+	}
+
+	if c.Value == "" {
+		fmt.Printf("Reset %s to the default\n", c.Key)
+	} else {
+		fmt.Printf("Set %s = %s\n", c.Key, c.Value)
+	}
+	return nil
+}
+
+type ConfigPathCmd struct{}
+
+func (c *ConfigPathCmd) Run(ctx *cli.Context) error {
+	fmt.Println(config.Path())
+	return nil
+}
+
+type ConfigEditCmd struct{}
+
+func (c *ConfigEditCmd) Run(ctx *cli.Context) error {
+	path := config.Path()
+	// Check the current file first so an existing mistake isn't blamed on the edit
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := config.Save(path, cfg); err != nil {
+			return fmt.Errorf("failed to create %s: %w", path, err)
+		}
+	}
+
+	editor := strings.Fields(editorCommand(cfg))
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor failed: %w", err)
+	}
+
+	if _, err := config.Load(path); err != nil {
+		return fmt.Errorf("config.toml is invalid after editing: %w", err)
+	}
+	return nil
+}
+
+// editorCommand returns the editor from config.toml, $VISUAL or $EDITOR,
+// falling back to vi
+func editorCommand(cfg config.Config) string {
+	for _, editor := range []string{cfg.Editor, os.Getenv("VISUAL"), os.Getenv("EDITOR")} {
+		if strings.TrimSpace(editor) != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
@@ -2,20 +2,47 @@ package settings
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/alecthomas/kong"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/autoplan"
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/i18n"
+	"github.com/julianstephens/daylit/daylit-cli/internal/summary"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
 	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
 )
 
 type SettingsCmd struct {
 	List bool `help:"List current settings."`
 
-	Timezone             *string `help:"Set timezone (IANA name, e.g., 'America/New_York', 'Europe/London', or 'Local')."`
-	NotificationsEnabled *bool   `help:"Enable or disable notifications."`
-	NotifyBlockStart     *bool   `help:"Notify on block start."`
-	NotifyBlockEnd       *bool   `help:"Notify on block end."`
-	BlockStartOffsetMin  *int    `help:"Minutes before block start to notify."`
-	BlockEndOffsetMin    *int    `help:"Minutes before block end to notify."`
+	Timezone                    *string `help:"Set timezone (IANA name, e.g., 'America/New_York', 'Europe/London', or 'Local')."`
+	Theme                       *string `help:"Set TUI color theme (dark, light, high-contrast, or no-color)."`
+	Locale                      *string `help:"Set the language of messages and dates (en or es; empty to follow DAYLIT_LANG or the system locale)."`
+	WeekStart                   *string `help:"Set the first day of the week for the week and calendar views, reports and week numbers (sunday or monday)."`
+	MarkdownExportDir           *string `help:"Set the directory 'export md' writes daily notes to (empty to write to stdout)."`
+	NotificationsEnabled        *bool   `help:"Enable or disable notifications."`
+	NotifyBlockStart            *bool   `help:"Notify on block start."`
+	NotifyBlockEnd              *bool   `help:"Notify on block end."`
+	BlockStartOffsetMin         *int    `help:"Minutes before block start to notify."`
+	BlockEndOffsetMin           *int    `help:"Minutes before block end to notify."`
+	NotificationDigestWindowMin *int    `help:"Send notifications due within this many minutes of each other as one digest (0 to send each on its own)."`
+	MorningPlan                 *string `help:"At day start, if today has no accepted plan: off, prompt (notify and ask in the TUI), or hands-free (generate and accept one)."`
+	CompressOnEarlyFinish       *bool   `help:"Move the following back-to-back slots up when 'daylit done' finishes a slot early."`
+	WeeklySummary               *string `help:"Send a weekly summary: off, notify, report (write it to the markdown export dir), or both."`
+	WeeklySummaryAt             *string `help:"When the weekly summary goes out, as a weekday and time (e.g. 'sun 18:00')."`
+	Metrics                     *bool   `help:"Record daily scheduling metrics in the local database for 'stats' and 'optimize' (off by default)."`
+	AdaptivePlacement           *bool   `help:"Place tasks at the time of day they have been done most reliably (off by default; see 'daylit optimize placement')."`
+	PriorityAgingDays           *int    `help:"Raise a flexible task's priority one level for every this many days in a row it is left out of the plan (0 to turn off)."`
+	MinFreeMinutes              *int    `help:"Minutes of the day a plan must leave unscheduled, counting leisure tasks as free (0 for no minimum)."`
+	RecoveryMinutes             *int    `help:"Minutes of recovery a demanding task must leave before the next demanding task starts (0 to turn off)."`
+	DemandingMinutes            *int    `help:"Count tasks longer than this many minutes as demanding, as well as high-energy ones (0 for high-energy tasks only)."`
+	TaskDeleteSlots             *string `help:"What deleting a task does to its upcoming slots in accepted plans: ask, remove, or keep."`
+	HabitDeleteEntries          *string `help:"What deleting a habit does to its entries: keep or delete."`
+	ArchivedHabitStats          *string `help:"Whether archived habits count in habit stats: hide or keep (up to the day they were archived)."`
 
 	OTPromptOnEmpty  *bool `help:"OT: Prompt when no entry exists for today."`
 	OTStrictMode     *bool `help:"OT: Strict mode - only one entry per day."`
@@ -39,6 +66,33 @@ func (c *SettingsCmd) Run(ctx *cli.Context) error {
 		fmt.Printf("  Day End:               %s\n", settings.DayEnd)
 		fmt.Printf("  Default Block Min:     %d\n", settings.DefaultBlockMin)
 		fmt.Printf("  Timezone:              %s\n", settings.Timezone)
+		fmt.Printf("  Theme:                 %s\n", settings.Theme)
+		locale := settings.Locale
+		if locale == "" {
+			locale = fmt.Sprintf("(from environment: %s)", i18n.Resolve(""))
+		}
+		fmt.Printf("  Locale:                %s\n", locale)
+		fmt.Printf("  Week Start:            %s\n", settings.WeekStart)
+		exportDir := settings.MarkdownExportDir
+		if exportDir == "" {
+			exportDir = "(not set)"
+		}
+		fmt.Printf("  Markdown Export Dir:   %s\n", exportDir)
+		activeContext := settings.ActiveContext
+		if activeContext == "" {
+			activeContext = "(none)"
+		}
+		fmt.Printf("  Active Context:        %s\n", activeContext)
+		fmt.Printf("  Compress Early Finish: %v\n", settings.CompressOnEarlyFinish)
+		fmt.Printf("  Metrics:               %v\n", settings.MetricsEnabled)
+		fmt.Printf("  Adaptive Placement:    %v\n", settings.AdaptivePlacement)
+		fmt.Printf("  Priority Aging Days:   %d\n", settings.PriorityAgingDays)
+		fmt.Printf("  Min Free Minutes:      %d\n", settings.MinFreeMinutes)
+		fmt.Printf("  Recovery Minutes:      %d\n", settings.RecoveryMinutes)
+		fmt.Printf("  Demanding Minutes:     %d\n", settings.DemandingMinutes)
+		fmt.Printf("  Task Delete Slots:     %s\n", orDefault(settings.TaskDeleteSlots, constants.DefaultTaskDeleteSlots))
+		fmt.Printf("  Habit Delete Entries:  %s\n", orDefault(settings.HabitDeleteEntries, constants.DefaultHabitDeleteEntries))
+		fmt.Printf("  Archived Habit Stats:  %s\n", orDefault(settings.ArchivedHabitStats, constants.DefaultArchivedHabitStats))
 		fmt.Println("\nOnce Today (OT) Settings:")
 		fmt.Printf("  Prompt On Empty:       %v\n", otSettings.PromptOnEmpty)
 		fmt.Printf("  Strict Mode:           %v\n", otSettings.StrictMode)
@@ -49,6 +103,21 @@ func (c *SettingsCmd) Run(ctx *cli.Context) error {
 		fmt.Printf("  Notify Block End:      %v\n", settings.NotifyBlockEnd)
 		fmt.Printf("  Block Start Offset:    %d min\n", settings.BlockStartOffsetMin)
 		fmt.Printf("  Block End Offset:      %d min\n", settings.BlockEndOffsetMin)
+		digestWindow := "off"
+		if settings.NotificationDigestWindowMin > 0 {
+			digestWindow = fmt.Sprintf("%d min", settings.NotificationDigestWindowMin)
+		}
+		fmt.Printf("  Digest Window:         %s\n", digestWindow)
+		morningPlan := settings.MorningPlan
+		if morningPlan == "" {
+			morningPlan = constants.MorningPlanOff
+		}
+		fmt.Printf("  Morning Plan:          %s\n", morningPlan)
+		weeklySummary := settings.WeeklySummary
+		if weeklySummary != constants.WeeklySummaryOff {
+			weeklySummary += " (" + settings.WeeklySummaryAt + ")"
+		}
+		fmt.Printf("  Weekly Summary:        %s\n", weeklySummary)
 		return nil
 	}
 
@@ -63,6 +132,47 @@ func (c *SettingsCmd) Run(ctx *cli.Context) error {
 		updated = true
 	}
 
+	if c.Theme != nil {
+		if _, ok := theme.Lookup(*c.Theme); !ok {
+			return fmt.Errorf("invalid theme: %s (available: %s)", *c.Theme, strings.Join(theme.Names(), ", "))
+		}
+		settings.Theme = *c.Theme
+		updated = true
+	}
+
+	if c.Locale != nil {
+		locale := strings.TrimSpace(*c.Locale)
+		if err := i18n.Validate(locale); err != nil {
+			return err
+		}
+		if locale != "" {
+			locale, _ = i18n.Parse(locale)
+		}
+		settings.Locale = locale
+		updated = true
+	}
+
+	if c.WeekStart != nil {
+		day, err := cli.ParseWeekday(*c.WeekStart)
+		if err != nil || (day != time.Sunday && day != time.Monday) {
+			return fmt.Errorf("invalid week start: %s (use sunday or monday)", *c.WeekStart)
+		}
+		settings.WeekStart = constants.WeekStartSunday
+		if day == time.Monday {
+			settings.WeekStart = constants.WeekStartMonday
+		}
+		updated = true
+	}
+
+	if c.MarkdownExportDir != nil {
+		dir := strings.TrimSpace(*c.MarkdownExportDir)
+		if dir != "" {
+			dir = kong.ExpandPath(dir)
+		}
+		settings.MarkdownExportDir = dir
+		updated = true
+	}
+
 	if c.NotificationsEnabled != nil {
 		settings.NotificationsEnabled = *c.NotificationsEnabled
 		updated = true
@@ -83,6 +193,117 @@ func (c *SettingsCmd) Run(ctx *cli.Context) error {
 		settings.BlockEndOffsetMin = *c.BlockEndOffsetMin
 		updated = true
 	}
+	if c.NotificationDigestWindowMin != nil {
+		if *c.NotificationDigestWindowMin < 0 {
+			return fmt.Errorf("notification digest window must not be negative")
+		}
+		settings.NotificationDigestWindowMin = *c.NotificationDigestWindowMin
+		updated = true
+	}
+
+	if c.MorningPlan != nil {
+		mode := strings.ToLower(strings.TrimSpace(*c.MorningPlan))
+		if !autoplan.ValidMode(mode) {
+			return fmt.Errorf("invalid morning plan mode: %s (use %s, %s, or %s)", *c.MorningPlan,
+				constants.MorningPlanOff, constants.MorningPlanPrompt, constants.MorningPlanHandsFree)
+		}
+		settings.MorningPlan = mode
+		updated = true
+	}
+
+	if c.CompressOnEarlyFinish != nil {
+		settings.CompressOnEarlyFinish = *c.CompressOnEarlyFinish
+		updated = true
+	}
+
+	if c.WeeklySummary != nil {
+		mode := strings.ToLower(strings.TrimSpace(*c.WeeklySummary))
+		if !summary.ValidMode(mode) {
+			return fmt.Errorf("invalid weekly summary mode: %s (use %s, %s, %s, or %s)", *c.WeeklySummary,
+				constants.WeeklySummaryOff, constants.WeeklySummaryNotify, constants.WeeklySummaryReport, constants.WeeklySummaryBoth)
+		}
+		settings.WeeklySummary = mode
+		updated = true
+	}
+	if c.WeeklySummaryAt != nil {
+		if _, _, err := summary.ParseSchedule(*c.WeeklySummaryAt); err != nil {
+			return err
+		}
+		settings.WeeklySummaryAt = strings.ToLower(strings.TrimSpace(*c.WeeklySummaryAt))
+		updated = true
+	}
+
+	if c.Metrics != nil {
+		settings.MetricsEnabled = *c.Metrics
+		updated = true
+	}
+
+	if c.AdaptivePlacement != nil {
+		settings.AdaptivePlacement = *c.AdaptivePlacement
+		updated = true
+	}
+
+	if c.PriorityAgingDays != nil {
+		if *c.PriorityAgingDays < 0 {
+			return fmt.Errorf("priority aging days must not be negative")
+		}
+		settings.PriorityAgingDays = *c.PriorityAgingDays
+		updated = true
+	}
+
+	if c.MinFreeMinutes != nil {
+		if *c.MinFreeMinutes < 0 {
+			return fmt.Errorf("minimum free minutes must not be negative")
+		}
+		settings.MinFreeMinutes = *c.MinFreeMinutes
+		updated = true
+	}
+
+	if c.RecoveryMinutes != nil {
+		if *c.RecoveryMinutes < 0 {
+			return fmt.Errorf("recovery minutes must not be negative")
+		}
+		settings.RecoveryMinutes = *c.RecoveryMinutes
+		updated = true
+	}
+
+	if c.DemandingMinutes != nil {
+		if *c.DemandingMinutes < 0 {
+			return fmt.Errorf("demanding minutes must not be negative")
+		}
+		settings.DemandingMinutes = *c.DemandingMinutes
+		updated = true
+	}
+
+	if c.TaskDeleteSlots != nil {
+		mode, err := choice("task delete slots", *c.TaskDeleteSlots,
+			constants.TaskDeleteSlotsAsk, constants.TaskDeleteSlotsRemove, constants.TaskDeleteSlotsKeep)
+		if err != nil {
+			return err
+		}
+		settings.TaskDeleteSlots = mode
+		updated = true
+	}
+
+	if c.HabitDeleteEntries != nil {
+		mode, err := choice("habit delete entries", *c.HabitDeleteEntries,
+			constants.HabitDeleteEntriesKeep, constants.HabitDeleteEntriesDelete)
+		if err != nil {
+			return err
+		}
+		settings.HabitDeleteEntries = mode
+		updated = true
+	}
+
+	if c.ArchivedHabitStats != nil {
+		mode, err := choice("archived habit stats", *c.ArchivedHabitStats,
+			constants.ArchivedHabitStatsHide, constants.ArchivedHabitStatsKeep)
+		if err != nil {
+			return err
+		}
+		settings.ArchivedHabitStats = mode
+		updated = true
+	}
 
 	if c.OTPromptOnEmpty != nil {
 		otSettings.PromptOnEmpty = *c.OTPromptOnEmpty
@@ -120,3 +341,22 @@ func (c *SettingsCmd) Run(ctx *cli.Context) error {
 
 	return nil
 }
+
+// choice normalizes value and checks it is one of the choices for setting
+func choice(setting, value string, choices ...string) (string, error) {
+	mode := strings.ToLower(strings.TrimSpace(value))
+	for _, c := range choices {
+		if mode == c {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("invalid %s: %s (use %s)", setting, value, strings.Join(choices, ", "))
+}
+
+// orDefault is value, or def when it was never set
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
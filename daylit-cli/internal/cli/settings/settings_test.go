@@ -199,3 +199,57 @@ func TestSettingsCmd_UpdateMultipleOTSettings(t *testing.T) {
 		t.Errorf("expected DefaultLogDays to be %d, got %d", defaultLogDays, updatedSettings.DefaultLogDays)
 	}
 }
+
+func TestSettingsCmd_MarkdownExportDir(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	cmd := &SettingsCmd{MarkdownExportDir: &dir}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("failed to set markdown export dir: %v", err)
+	}
+
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if settings.MarkdownExportDir != dir {
+		t.Errorf("MarkdownExportDir = %q, want %q", settings.MarkdownExportDir, dir)
+	}
+
+	empty := ""
+	cmd = &SettingsCmd{MarkdownExportDir: &empty}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("failed to clear markdown export dir: %v", err)
+	}
+	settings, err = ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if settings.MarkdownExportDir != "" {
+		t.Errorf("MarkdownExportDir = %q, want empty", settings.MarkdownExportDir)
+	}
+}
+
+func TestSettingsCmd_MorningPlan(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mode := "Hands-Free"
+	if err := (&SettingsCmd{MorningPlan: &mode}).Run(ctx); err != nil {
+		t.Fatalf("settings update failed: %v", err)
+	}
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if settings.MorningPlan != "hands-free" {
+		t.Errorf("MorningPlan = %q, want %q", settings.MorningPlan, "hands-free")
+	}
+
+	invalid := "sometimes"
+	if err := (&SettingsCmd{MorningPlan: &invalid}).Run(ctx); err == nil {
+		t.Error("expected error for invalid morning plan mode")
+	}
+}
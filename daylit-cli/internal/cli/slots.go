@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// lastPlanDay bounds the plans looked at by UpcomingSlots
+const lastPlanDay = "9999-12-31"
+
+// PlannedSlot is a slot of a plan revision
+type PlannedSlot struct {
+	Date     string
+	Revision int
+	Slot     models.Slot
+}
+
+// UpcomingSlots returns the slots of accepted plans, from now on, that are
+// still to be done and for which keep returns true. Today's slots count when
+// they haven't started yet.
+func (c *Context) UpcomingSlots(keep func(models.Slot) bool) ([]PlannedSlot, error) {
+	settings, err := c.Store.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+	if err != nil {
+		return nil, err
+	}
+	now := c.Now()
+	plans, err := c.Store.GetPlansRange(now.Format(constants.DateFormat), lastPlanDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plans: %w", err)
+	}
+	var upcoming []PlannedSlot
+	for _, plan := range plans {
+		if plan.AcceptedAt == nil {
+			continue
+		}
+		for _, slot := range plan.Slots {
+			if window.Upcoming(plan.Date, slot, now) && keep(slot) {
+				upcoming = append(upcoming, PlannedSlot{Date: plan.Date, Revision: plan.Revision, Slot: slot})
+			}
+		}
+	}
+	return upcoming, nil
+}
+
+// RemoveSlots soft-deletes the slots from their plans; 'daylit plans slot
+// restore' brings them back
+func (c *Context) RemoveSlots(slots []PlannedSlot) error {
+	for _, ps := range slots {
+		if err := c.Store.DeleteSlot(ps.Date, ps.Revision, ps.Slot.Start, ps.Slot.TaskID); err != nil {
+			return fmt.Errorf("failed to remove slot %s %s: %w", ps.Date, ps.Slot.Start, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+// FreeTimeWeek is the average free time of the planned days in one week of
+// the range
+type FreeTimeWeek struct {
+	From    string `json:"from"` // First day of the week
+	Days    int    `json:"days"` // Days with a plan
+	AvgFree int    `json:"avg_free_minutes"`
+}
+
+// FreeTimeTrend is the free time the plans in the range left
+type FreeTimeTrend struct {
+	Days       int             `json:"days"` // Days with a plan
+	AvgFree    int             `json:"avg_free_minutes"`
+	AvgLeisure int             `json:"avg_leisure_minutes"`
+	LeastFree  models.FreeTime `json:"least_free"`               // The day with the least free time
+	MinFree    int             `json:"min_free_minutes"`         // Minimum free time setting; 0 for none
+	BelowMin   int             `json:"days_below_min,omitempty"` // Days that left less than MinFree
+	Weeks      []FreeTimeWeek  `json:"weeks"`
+}
+
+// freeTimeTrend sums up the free time of plans, with averages for each
+// week from the week starting on from to show the trend. It returns nil
+// without any plans.
+func freeTimeTrend(plans []models.DayPlan, window models.DayWindow, leisure func(string) bool, minFree int, from time.Time) *FreeTimeTrend {
+	trend := FreeTimeTrend{MinFree: minFree, Weeks: []FreeTimeWeek{}}
+	totalFree, totalLeisure := 0, 0
+	weekFree := make(map[int]int)
+	weekDays := make(map[int]int)
+	lastWeek := -1
+	for _, plan := range plans {
+		day, err := time.Parse(constants.DateFormat, plan.Date)
+		if err != nil || plan.DeletedAt != nil {
+			continue
+		}
+		ft := models.PlanFreeTime(plan, window, leisure)
+		if trend.Days == 0 || ft.Free < trend.LeastFree.Free {
+			trend.LeastFree = ft
+		}
+		trend.Days++
+		totalFree += ft.Free
+		totalLeisure += ft.Leisure
+		if minFree > 0 && ft.Free < minFree {
+			trend.BelowMin++
+		}
+
+		week := int(day.Sub(from).Hours()/24) / 7
+		weekFree[week] += ft.Free
+		weekDays[week]++
+		lastWeek = max(lastWeek, week)
+	}
+	if trend.Days == 0 {
+		return nil
+	}
+
+	trend.AvgFree = totalFree / trend.Days
+	trend.AvgLeisure = totalLeisure / trend.Days
+	for week := 0; week <= lastWeek; week++ {
+		if weekDays[week] == 0 {
+			continue
+		}
+		trend.Weeks = append(trend.Weeks, FreeTimeWeek{
+			From:    from.AddDate(0, 0, week*7).Format(constants.DateFormat),
+			Days:    weekDays[week],
+			AvgFree: weekFree[week] / weekDays[week],
+		})
+	}
+	return &trend
+}
+
+// printFreeTime prints the free time the plans left and its weekly trend
+func printFreeTime(ft *FreeTimeTrend) {
+	if ft == nil {
+		return
+	}
+	fmt.Printf("\nFREE TIME (%d planned days)\n", ft.Days)
+	fmt.Printf("  Avg free:   %s a day", utils.FormatMinutes(ft.AvgFree))
+	if ft.AvgLeisure > 0 {
+		fmt.Printf(", including %s of leisure", utils.FormatMinutes(ft.AvgLeisure))
+	}
+	fmt.Println()
+	fmt.Printf("  Least free: %s (%s)\n", utils.FormatMinutes(ft.LeastFree.Free), ft.LeastFree.Date)
+	if ft.MinFree > 0 {
+		fmt.Printf("  Below min:  %d of %d days left less than %s\n", ft.BelowMin, ft.Days, utils.FormatMinutes(ft.MinFree))
+	}
+	if len(ft.Weeks) > 1 {
+		var weeks []string
+		for _, w := range ft.Weeks {
+			weeks = append(weeks, fmt.Sprintf("%s %s", w.From[5:], utils.FormatMinutes(w.AvgFree)))
+		}
+		fmt.Printf("  Weekly avg: %s\n", strings.Join(weeks, " → "))
+	}
+}
@@ -0,0 +1,334 @@
+package stats
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+type StatsCmd struct {
+	Range string `help:"Number of days to report, ending today, e.g. '30d' or '4w'." default:"30d"`
+	JSON  bool   `help:"Output the report as JSON." name:"json"`
+}
+
+// BandStats aggregates task stats for a priority band
+type BandStats struct {
+	Band string `json:"band"`
+	models.TaskStats
+}
+
+// ContextStats aggregates task stats for a context, which stands in for tags
+// as tasks have none; tasks without a context fall under the empty one
+type ContextStats struct {
+	Context string `json:"context"`
+	models.TaskStats
+}
+
+// Quality is the scheduling quality over the range, from the recorded metrics
+type Quality struct {
+	models.MetricsSummary
+	AcceptanceRate int `json:"acceptance_rate"` // Percentage of planned days whose plan was accepted
+	CompletionRate int `json:"completion_rate"` // Percentage of planned slots that were done
+	AvgDailyDrift  int `json:"avg_daily_drift"` // Average minutes a tracked day drifted from its plan
+}
+
+// Report is the stats output for a date range
+type Report struct {
+	From      string                      `json:"from"`
+	To        string                      `json:"to"`
+	Total     models.TaskStats            `json:"total"`
+	Adherence int                         `json:"adherence"` // Percentage of slots in accepted plans that were done
+	Tasks     []models.TaskStats          `json:"tasks"`
+	Bands     []BandStats                 `json:"priority_bands"`
+	Contexts  []ContextStats              `json:"contexts"`
+	Habits    []models.HabitCategoryStats `json:"habit_categories"`
+	Quality   *Quality                    `json:"quality,omitempty"`   // Only when metrics were recorded in the range
+	FreeTime  *FreeTimeTrend              `json:"free_time,omitempty"` // Only when there are plans in the range
+}
+
+// priorityBands maps task priorities to report bands, highest priority first
+var priorityBands = []struct {
+	Name     string
+	Min, Max int
+}{
+	{"high (1-2)", 1, 2},
+	{"medium (3)", 3, 3},
+	{"low (4-5)", 4, 5},
+	{"unknown", 0, 0},
+}
+
+func (c *StatsCmd) Run(ctx *cli.Context) error {
+	days, err := parseRange(c.Range)
+	if err != nil {
+		return err
+	}
+
+	end := ctx.Now()
+	start := end.AddDate(0, 0, -(days - 1))
+	report := Report{
+		From:  start.Format(constants.DateFormat),
+		To:    end.Format(constants.DateFormat),
+		Tasks: []models.TaskStats{},
+	}
+
+	tasks, err := ctx.Store.GetTaskStats(report.From, report.To)
+	if err != nil {
+		return fmt.Errorf("failed to get task stats: %w", err)
+	}
+	if tasks != nil {
+		report.Tasks = tasks
+	}
+
+	for _, band := range priorityBands {
+		b := BandStats{Band: band.Name}
+		for _, t := range report.Tasks {
+			if t.Priority >= band.Min && t.Priority <= band.Max {
+				b.Add(t)
+			}
+		}
+		if b.PlannedSlots > 0 {
+			report.Bands = append(report.Bands, b)
+		}
+	}
+	report.Contexts = contextBreakdown(report.Tasks)
+	for _, t := range report.Tasks {
+		report.Total.Add(t)
+	}
+	report.Adherence = report.Total.Adherence()
+
+	report.Habits, err = ctx.Store.GetHabitCategoryStats(report.From, report.To)
+	if err != nil {
+		return fmt.Errorf("failed to get habit stats: %w", err)
+	}
+	if report.Habits == nil {
+		report.Habits = []models.HabitCategoryStats{}
+	}
+
+	daily, err := ctx.Store.GetDailyMetrics(report.From, report.To)
+	if err != nil {
+		return fmt.Errorf("failed to get metrics: %w", err)
+	}
+	if len(daily) > 0 {
+		summary := models.SummarizeMetrics(daily)
+		report.Quality = &Quality{
+			MetricsSummary: summary,
+			AcceptanceRate: summary.AcceptanceRate(),
+			CompletionRate: summary.CompletionRate(),
+			AvgDailyDrift:  summary.AvgDailyDrift(),
+		}
+	}
+
+	if report.FreeTime, err = planFreeTime(ctx, report.From, report.To); err != nil {
+		return err
+	}
+
+	if c.JSON {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal stats: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	printReport(report)
+	return nil
+}
+
+// contextBreakdown sums up the stats of tasks per context, most planned
+// minutes first
+func contextBreakdown(tasks []models.TaskStats) []ContextStats {
+	contexts := []ContextStats{}
+	index := make(map[string]int)
+	for _, t := range tasks {
+		i, ok := index[t.Context]
+		if !ok {
+			i = len(contexts)
+			index[t.Context] = i
+			contexts = append(contexts, ContextStats{Context: t.Context})
+		}
+		contexts[i].Add(t)
+	}
+	slices.SortStableFunc(contexts, func(a, b ContextStats) int {
+		if a.PlannedMinutes != b.PlannedMinutes {
+			return cmp.Compare(b.PlannedMinutes, a.PlannedMinutes)
+		}
+		return strings.Compare(a.Context, b.Context)
+	})
+	return contexts
+}
+
+// planFreeTime sums up the free time the plans from from to to left, with
+// leisure tasks counting as free
+func planFreeTime(ctx *cli.Context, from, to string) (*FreeTimeTrend, error) {
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := ctx.Store.GetAllTasksIncludingDeleted()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	leisure := make(map[string]bool)
+	for _, task := range tasks {
+		leisure[task.ID] = task.Leisure
+	}
+	plans, err := ctx.Store.GetPlansRange(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plans: %w", err)
+	}
+
+	start, err := time.Parse(constants.DateFormat, from)
+	if err != nil {
+		return nil, err
+	}
+	// Weekly averages follow the week_start setting, so the first week may
+	// start before from
+	weeks := utils.StartOfWeek(start, settings.FirstWeekday())
+	return freeTimeTrend(plans, window, func(id string) bool { return leisure[id] }, settings.MinFreeMinutes, weeks), nil
+}
+
+// parseRange parses a range such as "30d" or "4w" into a number of days
+func parseRange(raw string) (int, error) {
+	value := strings.TrimSpace(strings.ToLower(raw))
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(value, "w"):
+		multiplier = 7
+		value = strings.TrimSuffix(value, "w")
+	case strings.HasSuffix(value, "d"):
+		value = strings.TrimSuffix(value, "d")
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid range %q (expected e.g. '30d' or '4w')", raw)
+	}
+	return n * multiplier, nil
+}
+
+func printReport(r Report) {
+	fmt.Printf("Stats for %s to %s\n\n", r.From, r.To)
+
+	if len(r.Tasks) == 0 {
+		fmt.Println("No planned slots in this range.")
+		printHabits(r.Habits)
+		printQuality(r.Quality)
+		printFreeTime(r.FreeTime)
+		return
+	}
+
+	fmt.Printf("Planned: %s in %d slots\n", utils.FormatMinutes(r.Total.PlannedMinutes), r.Total.PlannedSlots)
+	fmt.Printf("Done:    %s in %d slots\n", utils.FormatMinutes(r.Total.DoneMinutes), r.Total.DoneSlots)
+	if r.Total.AcceptedSlots == 0 {
+		fmt.Print("Adherence: - (no accepted plans in this range)\n\n")
+	} else {
+		fmt.Printf("Adherence: %d%% (%d of %d accepted slots done)\n\n", r.Adherence, r.Total.AcceptedDoneSlots, r.Total.AcceptedSlots)
+	}
+
+	fmt.Printf("%-28s %-8s %-9s %-9s %-9s %s\n", "TASK", "PRIORITY", "PLANNED", "DONE", "SLOTS", "ADHERENCE")
+	for _, t := range r.Tasks {
+		name := t.TaskName
+		if len(name) > 26 {
+			name = name[:23] + "..."
+		}
+		priority := "-"
+		if t.Priority > 0 {
+			priority = strconv.Itoa(t.Priority)
+		}
+		fmt.Printf("%-28s %-8s %-9s %-9s %-9s %s\n",
+			name, priority, utils.FormatMinutes(t.PlannedMinutes), utils.FormatMinutes(t.DoneMinutes),
+			fmt.Sprintf("%d/%d", t.DoneSlots, t.PlannedSlots), formatAdherence(t))
+	}
+
+	fmt.Printf("\n%-28s %-8s %-9s %-9s %-9s %s\n", "PRIORITY BAND", "", "PLANNED", "DONE", "SLOTS", "ADHERENCE")
+	for _, b := range r.Bands {
+		fmt.Printf("%-28s %-8s %-9s %-9s %-9s %s\n",
+			b.Band, "", utils.FormatMinutes(b.PlannedMinutes), utils.FormatMinutes(b.DoneMinutes),
+			fmt.Sprintf("%d/%d", b.DoneSlots, b.PlannedSlots), formatAdherence(b.TaskStats))
+	}
+
+	fmt.Printf("\n%-28s %-8s %-9s %-9s %-9s %s\n", "CONTEXT", "", "PLANNED", "DONE", "SLOTS", "ADHERENCE")
+	for _, c := range r.Contexts {
+		name := c.Context
+		if name == "" {
+			name = "no context"
+		}
+		fmt.Printf("%-28s %-8s %-9s %-9s %-9s %s\n",
+			name, "", utils.FormatMinutes(c.PlannedMinutes), utils.FormatMinutes(c.DoneMinutes),
+			fmt.Sprintf("%d/%d", c.DoneSlots, c.PlannedSlots), formatAdherence(c.TaskStats))
+	}
+
+	printHabits(r.Habits)
+	printQuality(r.Quality)
+	printFreeTime(r.FreeTime)
+}
+
+// printQuality prints the scheduling quality from the recorded metrics
+func printQuality(q *Quality) {
+	if q == nil {
+		return
+	}
+	fmt.Printf("\nSCHEDULING QUALITY (%d days of metrics)\n", q.Days)
+	if q.PlannedDays == 0 {
+		fmt.Println("  Plans accepted:  - (no plans)")
+	} else {
+		fmt.Printf("  Plans accepted:  %d%% (%d of %d planned days)\n", q.AcceptanceRate, q.AcceptedDays, q.PlannedDays)
+	}
+	if q.PlannedSlots == 0 {
+		fmt.Println("  Slots completed: - (no slots)")
+	} else {
+		fmt.Printf("  Slots completed: %d%% (%d of %d)\n", q.CompletionRate, q.DoneSlots, q.PlannedSlots)
+	}
+	if q.TrackedDays == 0 {
+		fmt.Println("  Avg daily drift: - (nothing tracked)")
+	} else {
+		fmt.Printf("  Avg daily drift: %s (over %d tracked days)\n", utils.FormatMinutes(q.AvgDailyDrift), q.TrackedDays)
+	}
+	if q.Rated() == 0 {
+		fmt.Println("  Feedback mix:    - (no feedback)")
+	} else {
+		fmt.Printf("  Feedback mix:    %d%% on track, %d%% too much, %d%% unnecessary (%d rated)\n",
+			q.FeedbackShare(q.OnTrack), q.FeedbackShare(q.TooMuch), q.FeedbackShare(q.Unnecessary), q.Rated())
+	}
+}
+
+// printHabits prints habit completion per category
+func printHabits(categories []models.HabitCategoryStats) {
+	if len(categories) == 0 {
+		return
+	}
+	fmt.Printf("\n%-28s %-8s %-9s %s\n", "HABIT CATEGORY", "HABITS", "DONE", "COMPLETION")
+	for _, c := range categories {
+		name := c.Category
+		if name == "" {
+			name = "uncategorized"
+		}
+		completion := "-"
+		if c.DueDays > 0 {
+			completion = fmt.Sprintf("%d%%", c.Completion())
+		}
+		fmt.Printf("%-28s %-8d %-9s %s\n", name, c.Habits, fmt.Sprintf("%d/%d", c.DoneDays, c.DueDays), completion)
+	}
+}
+
+// formatAdherence shows "-" for rows without any accepted slots
+func formatAdherence(s models.TaskStats) string {
+	if s.AcceptedSlots == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", s.Adherence())
+}
@@ -0,0 +1,170 @@
+package stats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func setupTestDB(t *testing.T) (*cli.Context, func()) {
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+
+	ctx := &cli.Context{
+		Store:     store,
+		Scheduler: scheduler.New(),
+	}
+
+	cleanup := func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("failed to close store: %v", err)
+		}
+	}
+
+	return ctx, cleanup
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"30d", 30, false},
+		{"4w", 28, false},
+		{"7", 7, false},
+		{" 14D ", 14, false},
+		{"0d", 0, true},
+		{"-3d", 0, true},
+		{"month", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseRange(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseRange(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseRange(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestStatsCmd(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	task := models.Task{
+		ID:          "task-1",
+		Name:        "Read",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+		Priority:    2,
+		Active:      true,
+	}
+	if err := ctx.Store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	plan := models.DayPlan{
+		Date:  time.Now().Format(constants.DateFormat),
+		Slots: []models.Slot{{Start: "09:00", End: "09:30", TaskID: task.ID, Status: constants.SlotStatusDone}},
+	}
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	for _, cmd := range []*StatsCmd{{Range: "7d"}, {Range: "7d", JSON: true}} {
+		if err := cmd.Run(ctx); err != nil {
+			t.Errorf("stats %+v failed: %v", cmd, err)
+		}
+	}
+
+	if err := (&StatsCmd{Range: "soon"}).Run(ctx); err == nil {
+		t.Error("expected error for invalid range")
+	}
+}
+
+func TestFreeTimeTrend(t *testing.T) {
+	window, err := models.ParseDayWindow("08:00", "18:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A plan with hours of work on each of ten days, busier every day
+	var plans []models.DayPlan
+	for i := 0; i < 10; i++ {
+		plans = append(plans, models.DayPlan{
+			Date: time.Date(2025, 6, 1+i, 0, 0, 0, 0, time.UTC).Format(constants.DateFormat),
+			Slots: []models.Slot{
+				{Start: "08:00", End: fmt.Sprintf("%02d:00", 9+i/2), TaskID: "work"},
+				{Start: "17:00", End: "18:00", TaskID: "walk"},
+			},
+		})
+	}
+	leisure := func(id string) bool { return id == "walk" }
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	trend := freeTimeTrend(plans, window, leisure, 6*60, from)
+	if trend == nil {
+		t.Fatal("expected a free time trend")
+	}
+	if trend.Days != 10 || trend.AvgLeisure != 60 {
+		t.Errorf("unexpected trend %+v", trend)
+	}
+	// Days 9 and 10 leave 5h; days 7 and 8 leave exactly 6h
+	if trend.LeastFree.Date != "2025-06-09" || trend.LeastFree.Free != 300 || trend.BelowMin != 2 {
+		t.Errorf("unexpected least free day %+v or days below min %d", trend.LeastFree, trend.BelowMin)
+	}
+	if len(trend.Weeks) != 2 || trend.Weeks[0].Days != 7 || trend.Weeks[1].From != "2025-06-08" {
+		t.Fatalf("unexpected weeks %+v", trend.Weeks)
+	}
+	if trend.Weeks[1].AvgFree >= trend.Weeks[0].AvgFree {
+		t.Errorf("expected free time to shrink, got %+v", trend.Weeks)
+	}
+
+	if trend := freeTimeTrend(nil, window, leisure, 0, from); trend != nil {
+		t.Errorf("expected no trend without plans, got %+v", trend)
+	}
+}
+
+func TestContextBreakdown(t *testing.T) {
+	tasks := []models.TaskStats{
+		{TaskID: "groceries", Context: "errands", PlannedSlots: 1, PlannedMinutes: 45, AcceptedSlots: 1},
+		{TaskID: "report", Context: "office", PlannedSlots: 2, PlannedMinutes: 120, DoneSlots: 1, DoneMinutes: 60, AcceptedSlots: 2, AcceptedDoneSlots: 1},
+		{TaskID: "read", PlannedSlots: 1, PlannedMinutes: 30, DoneSlots: 1, DoneMinutes: 30},
+		{TaskID: "standup", Context: "office", PlannedSlots: 3, PlannedMinutes: 45, DoneSlots: 3, DoneMinutes: 45, AcceptedSlots: 3, AcceptedDoneSlots: 3},
+	}
+
+	got := contextBreakdown(tasks)
+	want := []ContextStats{
+		{Context: "office", TaskStats: models.TaskStats{PlannedSlots: 5, PlannedMinutes: 165, DoneSlots: 4, DoneMinutes: 105, AcceptedSlots: 5, AcceptedDoneSlots: 4}},
+		{Context: "errands", TaskStats: models.TaskStats{PlannedSlots: 1, PlannedMinutes: 45, AcceptedSlots: 1}},
+		{Context: "", TaskStats: models.TaskStats{PlannedSlots: 1, PlannedMinutes: 30, DoneSlots: 1, DoneMinutes: 30}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("contexts = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("context %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if adherence := got[0].Adherence(); adherence != 80 {
+		t.Errorf("office adherence = %d%%, want 80%%", adherence)
+	}
+
+	if got := contextBreakdown(nil); got == nil || len(got) != 0 {
+		t.Errorf("contexts without tasks = %#v, want an empty list", got)
+	}
+}
@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/summary"
+)
+
+type SummaryCmd struct {
+	JSON  bool `help:"Output the summary as JSON." name:"json"`
+	Write bool `help:"Write the report to the markdown export directory instead of printing it."`
+}
+
+func (c *SummaryCmd) Run(ctx *cli.Context) error {
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+	weekly, err := summary.Build(ctx.Store, ctx.Now(), settings.FirstWeekday())
+	if err != nil {
+		return err
+	}
+
+	if c.JSON {
+		jsonBytes, err := json.MarshalIndent(weekly, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal summary: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	if c.Write {
+		if settings.MarkdownExportDir == "" {
+			return fmt.Errorf("no markdown export directory set (use 'daylit settings --markdown-export-dir')")
+		}
+		path, err := weekly.WriteReport(settings.MarkdownExportDir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Wrote weekly summary to %s\n", path)
+		return nil
+	}
+
+	fmt.Print(weekly.Markdown())
+	return nil
+}
@@ -0,0 +1,42 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cheatsheet"
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+)
+
+type CheatsheetCmd struct {
+	Command []string `arg:"" optional:"" help:"Only show commands starting with these words, e.g. 'task' or 'plan generate'."`
+	Flags   bool     `help:"Show every argument and flag with its default, even without a command."`
+	JSON    bool     `help:"Output the cheat sheet as JSON." name:"json"`
+}
+
+func (c *CheatsheetCmd) Run(ctx *cli.Context, kctx *kong.Context) error {
+	entries := cheatsheet.Filter(cheatsheet.Build(kctx.Model.Node), c.Command...)
+	if len(entries) == 0 {
+		return fmt.Errorf("no commands match %q", strings.Join(c.Command, " "))
+	}
+
+	if c.JSON {
+		jsonBytes, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal cheat sheet: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	// Narrowing to a command shows its options; the full list stays compact
+	cheatsheet.Write(os.Stdout, entries, c.Flags || len(c.Command) > 0)
+	if len(c.Command) == 0 && !c.Flags {
+		fmt.Println("\nRun 'daylit cheatsheet <command>' to see its arguments, flags and defaults.")
+	}
+	return nil
+}
@@ -1,13 +1,16 @@
 package system
 
 import (
+	"bufio"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
 )
 
 type DebugCmd struct {
@@ -18,6 +21,9 @@ type DebugCmd struct {
 	DumpOT       *DebugDumpOTCmd       `cmd:"" help:"Dump OT intention data as JSON."`
 	DumpAlert    *DebugDumpAlertCmd    `cmd:"" help:"Dump alert data as JSON."`
 	DumpSettings *DebugDumpSettingsCmd `cmd:"" help:"Dump settings data as JSON."`
+	Logs         *DebugLogsCmd         `cmd:"" help:"Show recent log lines with secrets redacted."`
+	Dump         *DebugDumpCmd         `cmd:"" help:"Write a sanitized bug report bundle to attach to an issue."`
+	Profile      *DebugProfileCmd      `cmd:"" help:"Summarize the slowest storage calls and scheduler phases of commands run with --debug."`
 }
 
 type DebugDBPathCmd struct{}
@@ -81,7 +87,7 @@ func (cmd *DebugDumpPlanCmd) Run(ctx *cli.Context) error {
 }
 
 type DebugDumpTaskCmd struct {
-	ID string `arg:"" help:"ID of the task to dump."`
+	ID string `arg:"" help:"ID or name of the task to dump."`
 }
 
 func (cmd *DebugDumpTaskCmd) Run(ctx *cli.Context) error {
@@ -90,14 +96,10 @@ func (cmd *DebugDumpTaskCmd) Run(ctx *cli.Context) error {
 		return fmt.Errorf("failed to load database: %w", err)
 	}
 
-	// Get the task
-	task, err := ctx.Store.GetTask(cmd.ID)
+	// Get the task, including deleted ones
+	task, err := ctx.ResolveTask(cmd.ID, cli.AllTasks)
 	if err != nil {
-		// Check if it's a not found error
-		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("task not found: %s", cmd.ID)
-		}
-		return fmt.Errorf("failed to get task: %w", err)
+		return err
 	}
 
 	// Marshal to JSON
@@ -240,3 +242,56 @@ func (cmd *DebugDumpSettingsCmd) Run(ctx *cli.Context) error {
 	fmt.Println(string(jsonBytes))
 	return nil
 }
+
+type DebugLogsCmd struct {
+	Tail int `help:"Number of lines to show." default:"50"`
+}
+
+func (cmd *DebugLogsCmd) Run(ctx *cli.Context) error {
+	if cmd.Tail < 1 {
+		return fmt.Errorf("--tail must be at least 1")
+	}
+
+	path := logger.Path()
+	if path == "" {
+		return fmt.Errorf("logging is not initialized")
+	}
+
+	lines, err := tailLines(path, cmd.Tail)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("No log file at %s\n", path)
+			return nil
+		}
+		return fmt.Errorf("failed to read log file: %w", err)
+	}
+
+	// Lines written before redaction existed are masked again here
+	for _, line := range lines {
+		fmt.Println(logger.Redact(line))
+	}
+	return nil
+}
+
+// tailLines returns the last n lines of the file at path.
+func tailLines(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lines := make([]string, 0, n)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(lines) == n {
+			lines = lines[1:]
+		}
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
@@ -1,16 +1,22 @@
 package system
 
 import (
+	"archive/zip"
 	"encoding/json"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+	"github.com/julianstephens/daylit/daylit-cli/internal/telemetry"
 )
 
 func setupTestDebugDB(t *testing.T) (*cli.Context, func()) {
@@ -593,3 +599,140 @@ func TestDebugDumpSettingsCmd_JSONOutput(t *testing.T) {
 		}
 	}
 }
+
+func TestTailLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daylit.log")
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree\nfour\n"), 0644); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+
+	lines, err := tailLines(path, 2)
+	if err != nil {
+		t.Fatalf("tailLines failed: %v", err)
+	}
+	if strings.Join(lines, ",") != "three,four" {
+		t.Errorf("expected last two lines, got %v", lines)
+	}
+
+	lines, err = tailLines(path, 10)
+	if err != nil {
+		t.Fatalf("tailLines failed: %v", err)
+	}
+	if len(lines) != 4 {
+		t.Errorf("expected all 4 lines, got %v", lines)
+	}
+}
+
+func TestDebugDumpCmd_Sanitized(t *testing.T) {
+	ctx, cleanup := setupTestDebugDB(t)
+	defer cleanup()
+
+	task := models.Task{
+		ID:            "real-task-id",
+		Name:          "Call my therapist",
+		Kind:          constants.TaskKindFlexible,
+		DurationMin:   30,
+		Recurrence:    models.Recurrence{Type: constants.RecurrenceAdHoc},
+		Priority:      2,
+		Active:        true,
+		NotifyMessage: "Dial the clinic",
+	}
+	if err := ctx.Store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	plan := models.DayPlan{
+		Date:     ctx.Now().Format(constants.DateFormat),
+		Revision: 1,
+		Note:     "rough morning",
+		Slots: []models.Slot{{
+			Start:    "09:00",
+			End:      "09:30",
+			TaskID:   "real-task-id",
+			Status:   constants.SlotStatusDone,
+			Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack, Note: "felt anxious"},
+		}},
+	}
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	output := filepath.Join(t.TempDir(), "bundle.zip")
+	cmd := &DebugDumpCmd{Output: output, Days: 14, Logs: 10, Yes: true}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("debug dump failed: %v", err)
+	}
+
+	zr, err := zip.OpenReader(output)
+	if err != nil {
+		t.Fatalf("failed to open bundle: %v", err)
+	}
+	defer zr.Close()
+
+	contents := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", f.Name, err)
+		}
+		contents[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"platform.json", "settings.json", "tasks.json", "plans.json", "daylit.log"} {
+		if _, ok := contents[name]; !ok {
+			t.Errorf("bundle is missing %s", name)
+		}
+	}
+	all := strings.Join([]string{contents["tasks.json"], contents["plans.json"]}, "\n")
+	for _, secret := range []string{"real-task-id", "therapist", "clinic", "rough morning", "anxious"} {
+		if strings.Contains(all, secret) {
+			t.Errorf("bundle contains %q", secret)
+		}
+	}
+	if !strings.Contains(contents["tasks.json"], `"task-1"`) || !strings.Contains(contents["plans.json"], `"task-1"`) {
+		t.Errorf("expected task IDs replaced consistently, got tasks %s plans %s", contents["tasks.json"], contents["plans.json"])
+	}
+	if !strings.Contains(contents["platform.json"], `"backend": "sqlite"`) {
+		t.Errorf("expected sqlite backend in platform info, got %s", contents["platform.json"])
+	}
+}
+
+func TestDebugProfileCmd(t *testing.T) {
+	ctx, cleanup := setupTestDebugDB(t)
+	defer cleanup()
+	if err := logger.Init(logger.Config{ConfigDir: t.TempDir()}); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+
+	// Nothing recorded yet
+	if err := (&DebugProfileCmd{Top: 15}).Run(ctx); err != nil {
+		t.Errorf("profile without timings failed: %v", err)
+	}
+
+	r := telemetry.New("plan <date>")
+	r.Storage("SavePlan", 12*time.Millisecond)
+	r.Scheduler("place flexible tasks", time.Millisecond)
+	path := telemetry.Path()
+	if err := telemetry.Append(path, r.Finish()); err != nil {
+		t.Fatalf("failed to record run: %v", err)
+	}
+	for _, command := range []string{"", "plan", "notify"} {
+		if err := (&DebugProfileCmd{Command: command, Top: 1}).Run(ctx); err != nil {
+			t.Errorf("profile of %q failed: %v", command, err)
+		}
+	}
+
+	if err := (&DebugProfileCmd{Clear: true}).Run(ctx); err != nil {
+		t.Fatalf("clearing timings failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("profile file still exists after --clear: %v", err)
+	}
+	if err := (&DebugProfileCmd{Top: -1}).Validate(); err == nil {
+		t.Error("a negative --top was accepted")
+	}
+}
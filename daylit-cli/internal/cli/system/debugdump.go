@@ -0,0 +1,307 @@
+package system
+
+import (
+	"archive/zip"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
+	"github.com/julianstephens/daylit/daylit-cli/internal/migration"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/mysql"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/postgres"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+	"github.com/julianstephens/daylit/daylit-cli/migrations"
+)
+
+// bundleContents describes each file of a bug report bundle, in the order
+// they are written and listed in the consent prompt
+var bundleContents = []struct {
+	name string
+	desc string
+}{
+	{"platform.json", "daylit version, OS and architecture, storage backend and schema version"},
+	{"settings.json", "application settings, with the markdown export directory removed"},
+	{"tasks.json", "task shapes: durations, windows, recurrence and priorities, with names, messages and IDs replaced"},
+	{"plans.json", "plans from the last %d days, with task IDs replaced and notes removed"},
+	{"daylit.log", "the last %d log lines, with passwords and notes redacted"},
+}
+
+type DebugDumpCmd struct {
+	Output string `short:"o" help:"Archive to write (default: daylit-debug-<timestamp>.zip in the current directory)." type:"path"`
+	Days   int    `help:"Days of plans to include." default:"14"`
+	Logs   int    `help:"Number of recent log lines to include." default:"200"`
+	Yes    bool   `short:"y" help:"Write the bundle without asking for confirmation."`
+}
+
+func (cmd *DebugDumpCmd) Validate() error {
+	if cmd.Days < 0 {
+		return fmt.Errorf("--days cannot be negative")
+	}
+	if cmd.Logs < 0 {
+		return fmt.Errorf("--logs cannot be negative")
+	}
+	return nil
+}
+
+func (cmd *DebugDumpCmd) Run(ctx *cli.Context) error {
+	if err := ctx.Store.Load(); err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	output := cmd.Output
+	if output == "" {
+		output = fmt.Sprintf("daylit-debug-%s.zip", ctx.Now().Format("20060102-150405"))
+	}
+
+	fmt.Println("The bug report bundle will contain:")
+	for _, c := range bundleContents {
+		desc := c.desc
+		switch c.name {
+		case "plans.json":
+			desc = fmt.Sprintf(desc, cmd.Days)
+		case "daylit.log":
+			desc = fmt.Sprintf(desc, cmd.Logs)
+		}
+		fmt.Printf("  %-14s %s\n", c.name, desc)
+	}
+	fmt.Println("Review the archive before attaching it to an issue.")
+
+	if !cmd.Yes {
+		ok, err := ctx.Confirm(fmt.Sprintf("\nWrite %s?", output))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	files, err := cmd.collect(ctx)
+	if err != nil {
+		return err
+	}
+	if err := writeBundle(output, files); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %s\n", output)
+	return nil
+}
+
+// collect gathers the contents of each bundle file, keyed by file name.
+func (cmd *DebugDumpCmd) collect(ctx *cli.Context) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	add := func(name string, v interface{}) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		files[name] = data
+		return nil
+	}
+
+	if err := add("platform.json", platformInfo(ctx)); err != nil {
+		return nil, err
+	}
+
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	if settings.MarkdownExportDir != "" {
+		settings.MarkdownExportDir = logger.Redacted
+	}
+	if err := add("settings.json", settings); err != nil {
+		return nil, err
+	}
+
+	anon := newAnonymizer()
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+	for i := range tasks {
+		anon.task(&tasks[i])
+	}
+	if err := add("tasks.json", tasks); err != nil {
+		return nil, err
+	}
+
+	plans, err := ctx.Store.GetAllPlans()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plans: %w", err)
+	}
+	since := ctx.Now().AddDate(0, 0, -cmd.Days).Format(constants.DateFormat)
+	recent := []models.DayPlan{}
+	for _, plan := range plans {
+		if plan.Date < since {
+			continue
+		}
+		anon.plan(&plan)
+		recent = append(recent, plan)
+	}
+	if err := add("plans.json", recent); err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	if path := logger.Path(); path != "" && cmd.Logs > 0 {
+		// A missing log file leaves the log empty rather than failing
+		if tail, err := tailLines(path, cmd.Logs); err == nil {
+			lines = tail
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read log file: %w", err)
+		}
+	}
+	var log strings.Builder
+	for _, line := range lines {
+		log.WriteString(logger.Redact(line))
+		log.WriteString("\n")
+	}
+	files["daylit.log"] = []byte(log.String())
+
+	return files, nil
+}
+
+func writeBundle(path string, files map[string][]byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+
+	zw := zip.NewWriter(f)
+	for _, c := range bundleContents {
+		w, err := zw.Create(c.name)
+		if err == nil {
+			_, err = w.Write(files[c.name])
+		}
+		if err != nil {
+			zw.Close()
+			f.Close()
+			return fmt.Errorf("failed to write %s to bundle: %w", c.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return f.Close()
+}
+
+// platform describes the environment daylit runs in
+type platform struct {
+	Version        string `json:"version"`
+	GoVersion      string `json:"go_version"`
+	OS             string `json:"os"`
+	Arch           string `json:"arch"`
+	Backend        string `json:"backend"`
+	SchemaVersion  int    `json:"schema_version,omitempty"`
+	LatestSchema   int    `json:"latest_schema_version,omitempty"`
+	NotesEncrypted bool   `json:"notes_encrypted"`
+	SchemaError    string `json:"schema_error,omitempty"`
+}
+
+func platformInfo(ctx *cli.Context) platform {
+	p := platform{
+		Version:   constants.Version,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Backend:   "memory",
+	}
+	if enc, ok := storage.Unwrap(ctx.Store).(storage.NoteEncrypter); ok {
+		p.NotesEncrypted = enc.NotesEncrypted()
+	}
+
+	var db *sql.DB
+	switch s := storage.Unwrap(ctx.Store).(type) {
+	case *sqlite.Store:
+		db, p.Backend = s.GetDB(), "sqlite"
+	case *postgres.Store:
+		db, p.Backend = s.GetDB(), "postgres"
+	case *mysql.Store:
+		db, p.Backend = s.GetDB(), "mysql"
+	}
+	if db == nil {
+		return p
+	}
+
+	subFS, err := migrations.Backend(p.Backend)
+	if err == nil {
+		runner := migration.NewRunner(db, subFS)
+		if p.SchemaVersion, err = runner.GetCurrentVersion(); err == nil {
+			p.LatestSchema, err = runner.GetLatestVersion()
+		}
+	}
+	if err != nil {
+		p.SchemaError = logger.Redact(err.Error())
+	}
+	return p
+}
+
+// anonymizer replaces identifying values in tasks and plans with stable
+// placeholders such as "task-3", so references between them still line up
+type anonymizer struct {
+	ids map[string]string
+	n   map[string]int
+}
+
+func newAnonymizer() *anonymizer {
+	return &anonymizer{ids: make(map[string]string), n: make(map[string]int)}
+}
+
+// id returns the placeholder for value within kind, or "" for an empty value
+func (a *anonymizer) id(kind, value string) string {
+	if value == "" {
+		return ""
+	}
+	key := kind + "\x00" + value
+	if id, ok := a.ids[key]; ok {
+		return id
+	}
+	a.n[kind]++
+	id := fmt.Sprintf("%s-%d", kind, a.n[kind])
+	a.ids[key] = id
+	return id
+}
+
+func (a *anonymizer) task(t *models.Task) {
+	t.ID = a.id("task", t.ID)
+	t.Name = t.ID
+	t.ProjectID = a.id("project", t.ProjectID)
+	t.PoolID = a.id("pool", t.PoolID)
+	t.Context = a.id("context", t.Context)
+	if t.NotifyMessage != "" {
+		t.NotifyMessage = logger.Redacted
+	}
+	if t.NotifySound != "" {
+		t.NotifySound = logger.Redacted
+	}
+}
+
+func (a *anonymizer) plan(p *models.DayPlan) {
+	if p.Note != "" {
+		p.Note = logger.Redacted
+	}
+	slots := make([]models.Slot, len(p.Slots))
+	copy(slots, p.Slots)
+	for i := range slots {
+		slots[i].TaskID = a.id("task", slots[i].TaskID)
+		if fb := slots[i].Feedback; fb != nil && fb.Note != "" {
+			slots[i].Feedback = &models.Feedback{Rating: fb.Rating, Note: logger.Redacted}
+		}
+	}
+	p.Slots = slots
+}
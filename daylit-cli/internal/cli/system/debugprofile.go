@@ -0,0 +1,106 @@
+package system
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/telemetry"
+)
+
+// slowestRuns is how many of the slowest command runs the profile lists
+const slowestRuns = 5
+
+type DebugProfileCmd struct {
+	Command string `help:"Only include runs of commands starting with this, such as 'plan' or 'notify'."`
+	Top     int    `short:"n" help:"Number of operations to show (0 for all)." default:"15"`
+	Clear   bool   `help:"Delete the recorded timings."`
+}
+
+func (cmd *DebugProfileCmd) Validate() error {
+	if cmd.Top < 0 {
+		return fmt.Errorf("--top cannot be negative")
+	}
+	return nil
+}
+
+func (cmd *DebugProfileCmd) Run(ctx *cli.Context) error {
+	path := telemetry.Path()
+	if path == "" {
+		return fmt.Errorf("logging is not initialized")
+	}
+
+	if cmd.Clear {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to clear timings: %w", err)
+		}
+		fmt.Println("Cleared recorded timings.")
+		return nil
+	}
+
+	runs, err := telemetry.Load(path)
+	if err != nil {
+		return fmt.Errorf("failed to read timings: %w", err)
+	}
+	if cmd.Command != "" {
+		runs = slices.DeleteFunc(runs, func(r telemetry.Run) bool {
+			return !strings.HasPrefix(r.Command, cmd.Command)
+		})
+	}
+	if len(runs) == 0 {
+		if cmd.Command != "" {
+			fmt.Printf("No timings recorded for commands starting with %q.\n", cmd.Command)
+		} else {
+			fmt.Println("No timings recorded yet. Run a command with --debug to record its storage calls and scheduler phases.")
+		}
+		return nil
+	}
+
+	noun := "runs"
+	if len(runs) == 1 {
+		noun = "run"
+	}
+	fmt.Printf("Timings of %d %s, %s to %s\n\n", len(runs), noun,
+		runs[0].Started.Local().Format("2006-01-02 15:04"), runs[len(runs)-1].Started.Local().Format("2006-01-02 15:04"))
+
+	slowest := slices.Clone(runs)
+	slices.SortStableFunc(slowest, func(a, b telemetry.Run) int { return cmp.Compare(b.Duration, a.Duration) })
+	fmt.Println("Slowest runs:")
+	fmt.Printf("%-16s %-10s %s\n", "Started", "Elapsed", "Command")
+	fmt.Println(strings.Repeat("-", 60))
+	for _, run := range slowest[:min(len(slowest), slowestRuns)] {
+		fmt.Printf("%-16s %-10s %s\n", run.Started.Local().Format("2006-01-02 15:04"), formatElapsed(run.Duration), run.Command)
+	}
+
+	ops := telemetry.Summarize(runs)
+	if cmd.Top > 0 && len(ops) > cmd.Top {
+		ops = ops[:cmd.Top]
+	}
+	fmt.Println()
+	fmt.Println("Slowest operations:")
+	fmt.Printf("%-10s %-32s %6s %10s %10s %10s\n", "Kind", "Operation", "Calls", "Total", "Avg", "Max")
+	fmt.Println(strings.Repeat("-", 83))
+	for _, op := range ops {
+		fmt.Printf("%-10s %-32s %6d %10s %10s %10s\n", op.Kind, op.Name, op.Calls,
+			formatElapsed(op.Total), formatElapsed(op.Avg()), formatElapsed(op.Max))
+	}
+	return nil
+}
+
+// formatElapsed rounds d to three significant digits or so, enough to tell
+// a slow call from a fast one
+func formatElapsed(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
@@ -0,0 +1,72 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/demo"
+	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+)
+
+type DemoCmd struct {
+	Keep  bool   `help:"Keep the demo database after the TUI exits and print where it is."`
+	NoTui bool   `help:"Only create the demo database and print its path; implies --keep." name:"no-tui"`
+	Seed  uint64 `help:"Seed for the generated data; the same seed on the same day gives the same data." default:"1"`
+}
+
+func (c *DemoCmd) Run(ctx *cli.Context, kctx *kong.Context) error {
+	dir, err := os.MkdirTemp("", "daylit-demo-")
+	if err != nil {
+		return fmt.Errorf("failed to create demo directory: %w", err)
+	}
+	keep := c.Keep || c.NoTui
+	if !keep {
+		defer os.RemoveAll(dir)
+	}
+
+	// The demo must not run the user's hooks on made-up events
+	if err := os.Setenv(constants.HooksDirEnv, filepath.Join(dir, "hooks")); err != nil {
+		return fmt.Errorf("failed to disable hooks: %w", err)
+	}
+
+	// Only a demo that's kept needs a database file
+	path := filepath.Join(dir, "daylit.db")
+	var store storage.Provider = storage.NewMemoryStore()
+	if keep {
+		store = sqlite.NewStore(path)
+	}
+	defer store.Close()
+	if err := store.Init(); err != nil {
+		return fmt.Errorf("failed to initialize demo storage: %w", err)
+	}
+	if err := demo.Populate(store, ctx.Scheduler, ctx.Now(), c.Seed); err != nil {
+		return fmt.Errorf("failed to generate demo data: %w", err)
+	}
+	if keep {
+		logger.Debug("Created demo database", "path", path)
+	}
+
+	if c.NoTui {
+		fmt.Printf("Demo database with %d days of history: %s\n", demo.HistoryDays, path)
+		fmt.Printf("Explore it with: daylit --config %s\n", path)
+		return nil
+	}
+
+	demoCtx := &cli.Context{Store: store, Scheduler: ctx.Scheduler, Config: ctx.Config}
+	if err := (&TuiCmd{}).Run(demoCtx, kctx); err != nil {
+		return err
+	}
+	if keep {
+		fmt.Printf("Demo database kept at: %s\n", path)
+	} else {
+		fmt.Println("Demo data deleted. Your own data was never touched.")
+	}
+	return nil
+}
@@ -1,20 +1,33 @@
 package system
 
 import (
+	"database/sql"
 	"fmt"
-	"io/fs"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/backup"
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/daemon"
 	"github.com/julianstephens/daylit/daylit-cli/internal/migration"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/postgres"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
 	"github.com/julianstephens/daylit/daylit-cli/migrations"
 )
 
-type DoctorCmd struct{}
+type DoctorCmd struct {
+	Remote string `help:"URL of a running 'daylit notify serve' to check instead of the local database (e.g. http://127.0.0.1:9184)."`
+	Fix    bool   `help:"Remove upcoming plan slots whose task was deleted."`
+}
 
 func (cmd *DoctorCmd) Run(ctx *cli.Context) error {
+	if cmd.Remote != "" {
+		return cmd.runRemote()
+	}
+
 	fmt.Println("Running diagnostics...")
 	fmt.Println()
 
@@ -152,6 +165,69 @@ func (cmd *DoctorCmd) Run(ctx *cli.Context) error {
 		fmt.Printf("⊘ Timestamp integrity: SKIPPED (database not reachable)\n")
 	}
 
+	// Check 12: Schema integrity (only if DB is reachable)
+	if dbReachable {
+		warning, err := checkSchemaIntegrity(ctx)
+		switch {
+		case err != nil:
+			fmt.Printf("❌ Schema integrity: FAIL\n")
+			fmt.Printf("   Error: %v\n", err)
+			hasError = true
+		case warning != "":
+			fmt.Printf("⚠ Schema integrity: WARNING\n")
+			fmt.Printf("   %s\n", warning)
+		default:
+			fmt.Printf("✓ Schema integrity: OK\n")
+		}
+	} else {
+		fmt.Printf("⊘ Schema integrity: SKIPPED (database not reachable)\n")
+	}
+
+	// Check 13: Orphaned slots (only if DB is reachable)
+	if dbReachable {
+		orphans, err := findOrphanedSlots(ctx)
+		switch {
+		case err != nil:
+			fmt.Printf("❌ Orphaned slots: FAIL\n")
+			fmt.Printf("   Error: %v\n", err)
+			hasError = true
+		case len(orphans) > 0 && cmd.Fix:
+			if err := ctx.RemoveSlots(orphans); err != nil {
+				fmt.Printf("❌ Orphaned slots: FAIL\n")
+				fmt.Printf("   Error: %v\n", err)
+				hasError = true
+			} else {
+				fmt.Printf("✓ Orphaned slots: FIXED (removed %d slot(s) of deleted tasks)\n", len(orphans))
+			}
+		case len(orphans) > 0:
+			fmt.Printf("⚠ Orphaned slots: WARNING\n")
+			fmt.Printf("   %d upcoming slot(s) belong to deleted tasks, first on %s at %s; run 'daylit doctor --fix' to remove them\n",
+				len(orphans), orphans[0].Date, orphans[0].Slot.Start)
+		default:
+			fmt.Printf("✓ Orphaned slots: OK\n")
+		}
+	} else {
+		fmt.Printf("⊘ Orphaned slots: SKIPPED (database not reachable)\n")
+	}
+
+	// Check 14: Referential integrity (only if DB is reachable)
+	if dbReachable {
+		warning, err := checkReferentialIntegrity(ctx)
+		switch {
+		case err != nil:
+			fmt.Printf("❌ Referential integrity: FAIL\n")
+			fmt.Printf("   Error: %v\n", err)
+			hasError = true
+		case warning != "":
+			fmt.Printf("⚠ Referential integrity: WARNING\n")
+			fmt.Printf("   %s\n", warning)
+		default:
+			fmt.Printf("✓ Referential integrity: OK\n")
+		}
+	} else {
+		fmt.Printf("⊘ Referential integrity: SKIPPED (database not reachable)\n")
+	}
+
 	fmt.Println()
 	if hasError {
 		fmt.Println("Diagnostics completed with errors.")
@@ -169,7 +245,7 @@ func checkDBReachable(ctx *cli.Context) error {
 	}
 
 	// For SQLite, also try a simple query
-	if sqliteStore, ok := ctx.Store.(*sqlite.Store); ok {
+	if sqliteStore, ok := storage.Unwrap(ctx.Store).(*sqlite.Store); ok {
 		db := sqliteStore.GetDB()
 		if db == nil {
 			return fmt.Errorf("database connection is nil")
@@ -184,7 +260,7 @@ func checkDBReachable(ctx *cli.Context) error {
 }
 
 func checkSchemaVersion(ctx *cli.Context) error {
-	sqliteStore, ok := ctx.Store.(*sqlite.Store)
+	sqliteStore, ok := storage.Unwrap(ctx.Store).(*sqlite.Store)
 	if !ok {
 		// JSON store doesn't have schema version
 		return nil
@@ -196,9 +272,9 @@ func checkSchemaVersion(ctx *cli.Context) error {
 	}
 
 	// Get the embedded SQLite migrations sub-filesystem
-	subFS, err := fs.Sub(migrations.FS, "sqlite")
+	subFS, err := migrations.Backend("sqlite")
 	if err != nil {
-		return fmt.Errorf("failed to access sqlite migrations: %w", err)
+		return err
 	}
 
 	runner := migration.NewRunner(db, subFS)
@@ -221,7 +297,7 @@ func checkSchemaVersion(ctx *cli.Context) error {
 }
 
 func checkMigrationsComplete(ctx *cli.Context) error {
-	sqliteStore, ok := ctx.Store.(*sqlite.Store)
+	sqliteStore, ok := storage.Unwrap(ctx.Store).(*sqlite.Store)
 	if !ok {
 		// JSON store doesn't have migrations
 		return nil
@@ -233,9 +309,9 @@ func checkMigrationsComplete(ctx *cli.Context) error {
 	}
 
 	// Get the embedded SQLite migrations sub-filesystem
-	subFS, err := fs.Sub(migrations.FS, "sqlite")
+	subFS, err := migrations.Backend("sqlite")
 	if err != nil {
-		return fmt.Errorf("failed to access sqlite migrations: %w", err)
+		return err
 	}
 
 	runner := migration.NewRunner(db, subFS)
@@ -257,6 +333,77 @@ func checkMigrationsComplete(ctx *cli.Context) error {
 	return nil
 }
 
+// checkSchemaIntegrity verifies the checksums of applied migrations against
+// the embedded files and compares the live tables and columns with the
+// schema the migrations produce. Migrations applied before checksums were
+// tracked only produce a warning, since the next migration run records them.
+func checkSchemaIntegrity(ctx *cli.Context) (string, error) {
+	var (
+		db      *sql.DB
+		backend string
+	)
+	switch s := storage.Unwrap(ctx.Store).(type) {
+	case *sqlite.Store:
+		db, backend = s.GetDB(), "sqlite"
+	case *postgres.Store:
+		db, backend = s.GetDB(), "postgres"
+	default:
+		return "", nil
+	}
+	if db == nil {
+		return "", fmt.Errorf("database connection is nil")
+	}
+
+	subFS, err := migrations.Backend(backend)
+	if err != nil {
+		return "", err
+	}
+
+	checksums, err := migration.NewRunner(db, subFS).VerifyChecksums()
+	if err != nil {
+		return "", fmt.Errorf("failed to verify migration checksums: %w", err)
+	}
+
+	var problems []string
+	unrecorded := 0
+	for _, d := range checksums {
+		if d.Recorded == "" {
+			unrecorded++
+			continue
+		}
+		problems = append(problems, d.String())
+	}
+
+	var want, got migration.Schema
+	if backend == "postgres" {
+		if want, err = migration.ExpectedPostgresSchema(db, subFS); err == nil {
+			got, err = migration.PostgresSchema(db)
+		}
+	} else {
+		if want, err = migration.ExpectedSQLiteSchema(subFS); err == nil {
+			got, err = migration.SQLiteSchema(db)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to introspect schema: %w", err)
+	}
+	for _, d := range migration.CompareSchemas(want, got) {
+		problems = append(problems, d.String())
+	}
+
+	if len(problems) > 0 {
+		return "", fmt.Errorf("schema diverges from the embedded migrations:\n   - %s", strings.Join(problems, "\n   - "))
+	}
+	if unrecorded > 0 {
+		hint := "daylit migrate"
+		if backend == "postgres" {
+			hint = "daylit init"
+		}
+		return fmt.Sprintf("%d applied migration(s) have no recorded checksum - run '%s' to record them", unrecorded, hint), nil
+	}
+	return "", nil
+}
+
 func checkBackupsPresent(ctx *cli.Context) error {
 	mgr := backup.NewManager(ctx.Store.GetConfigPath())
 	backups, err := mgr.ListBackups()
@@ -308,7 +455,7 @@ func checkClockTimezone() error {
 }
 
 func checkHabitsIntegrity(ctx *cli.Context) error {
-	sqliteStore, ok := ctx.Store.(*sqlite.Store)
+	sqliteStore, ok := storage.Unwrap(ctx.Store).(*sqlite.Store)
 	if !ok {
 		return nil // Not SQLite, skip
 	}
@@ -337,7 +484,7 @@ func checkHabitsIntegrity(ctx *cli.Context) error {
 }
 
 func checkHabitEntriesDuplicates(ctx *cli.Context) error {
-	sqliteStore, ok := ctx.Store.(*sqlite.Store)
+	sqliteStore, ok := storage.Unwrap(ctx.Store).(*sqlite.Store)
 	if !ok {
 		return nil // Not SQLite, skip
 	}
@@ -370,7 +517,7 @@ func checkHabitEntriesDuplicates(ctx *cli.Context) error {
 }
 
 func checkOTSettings(ctx *cli.Context) error {
-	sqliteStore, ok := ctx.Store.(*sqlite.Store)
+	sqliteStore, ok := storage.Unwrap(ctx.Store).(*sqlite.Store)
 	if !ok {
 		return nil // Not SQLite, skip
 	}
@@ -394,7 +541,7 @@ func checkOTSettings(ctx *cli.Context) error {
 }
 
 func checkOTEntriesDates(ctx *cli.Context) error {
-	sqliteStore, ok := ctx.Store.(*sqlite.Store)
+	sqliteStore, ok := storage.Unwrap(ctx.Store).(*sqlite.Store)
 	if !ok {
 		return nil // Not SQLite, skip
 	}
@@ -435,7 +582,7 @@ func checkOTEntriesDates(ctx *cli.Context) error {
 }
 
 func checkTimestampIntegrity(ctx *cli.Context) error {
-	sqliteStore, ok := ctx.Store.(*sqlite.Store)
+	sqliteStore, ok := storage.Unwrap(ctx.Store).(*sqlite.Store)
 	if !ok {
 		return nil // Not SQLite, skip
 	}
@@ -487,3 +634,136 @@ func checkTimestampIntegrity(ctx *cli.Context) error {
 
 	return nil
 }
+
+// findOrphanedSlots returns the upcoming slots of accepted plans whose task
+// was deleted, which plans show as an unknown task
+func findOrphanedSlots(ctx *cli.Context) ([]cli.PlannedSlot, error) {
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	live := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		live[task.ID] = true
+	}
+	return ctx.UpcomingSlots(func(slot models.Slot) bool { return !live[slot.TaskID] })
+}
+
+// foreignKeysVersion is the migration that makes the database enforce the
+// keys checkReferentialIntegrity looks at
+const foreignKeysVersion = 40
+
+// checkReferentialIntegrity looks for rows the schema's foreign keys reject:
+// slots of plans or tasks that no longer exist and entries of habits that no
+// longer exist. Before the foreign keys migration has run they only produce a
+// warning, so they can be looked at before migrating removes them.
+func checkReferentialIntegrity(ctx *cli.Context) (string, error) {
+	var (
+		db      *sql.DB
+		backend string
+	)
+	switch s := storage.Unwrap(ctx.Store).(type) {
+	case *sqlite.Store:
+		db, backend = s.GetDB(), "sqlite"
+	case *postgres.Store:
+		db, backend = s.GetDB(), "postgres"
+	default:
+		return "", nil
+	}
+	if db == nil {
+		return "", fmt.Errorf("database connection is nil")
+	}
+
+	checks := []struct {
+		what  string
+		query string
+	}{
+		{"slot(s) of tasks that no longer exist",
+			"SELECT COUNT(*) FROM slots s WHERE s.task_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM tasks t WHERE t.id = s.task_id)"},
+		{"slot(s) of plans that no longer exist",
+			"SELECT COUNT(*) FROM slots s WHERE NOT EXISTS (SELECT 1 FROM plans p WHERE p.date = s.plan_date AND p.revision = s.plan_revision)"},
+		{"habit entries of habits that no longer exist",
+			"SELECT COUNT(*) FROM habit_entries e WHERE NOT EXISTS (SELECT 1 FROM habits h WHERE h.id = e.habit_id)"},
+	}
+	var problems []string
+	for _, c := range checks {
+		var count int
+		if err := db.QueryRow(c.query).Scan(&count); err != nil {
+			return "", fmt.Errorf("failed to count %s: %w", c.what, err)
+		}
+		if count > 0 {
+			problems = append(problems, fmt.Sprintf("%d %s", count, c.what))
+		}
+	}
+	if len(problems) == 0 {
+		return "", nil
+	}
+
+	subFS, err := migrations.Backend(backend)
+	if err != nil {
+		return "", err
+	}
+	version, err := migration.NewRunner(db, subFS).GetCurrentVersion()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current schema version: %w", err)
+	}
+	found := strings.Join(problems, ", ")
+	if version >= foreignKeysVersion {
+		return "", fmt.Errorf("found %s", found)
+	}
+	if backend == "postgres" {
+		return fmt.Sprintf("found %s; remove them before running 'daylit init', or the foreign keys can't be added", found), nil
+	}
+	return fmt.Sprintf("found %s; 'daylit migrate' removes them, so run 'daylit backup create' first to keep a copy", found), nil
+}
+
+// runRemote checks the health endpoint of a running daemon
+func (cmd *DoctorCmd) runRemote() error {
+	fmt.Printf("Checking daemon at %s...\n", cmd.Remote)
+	fmt.Println()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	health, err := daemon.FetchHealth(client, cmd.Remote)
+	if err != nil {
+		fmt.Printf("❌ Daemon reachable: FAIL\n")
+		fmt.Printf("   Error: %v\n", err)
+		fmt.Println()
+		fmt.Println("Diagnostics completed with errors.")
+		return fmt.Errorf("one or more health checks failed")
+	}
+	fmt.Printf("✓ Daemon reachable: OK (up since %s)\n", health.StartedAt.Local().Format("2006-01-02 15:04"))
+
+	hasError := false
+
+	if health.DBUp {
+		fmt.Printf("✓ Database reachable: OK (%.1f ms)\n", health.DBLatencyMs)
+	} else {
+		fmt.Printf("❌ Database reachable: FAIL\n")
+		if health.DBError != "" {
+			fmt.Printf("   Error: %s\n", health.DBError)
+		}
+		hasError = true
+	}
+
+	switch {
+	case health.LastError != "":
+		fmt.Printf("❌ Notification checks: FAIL\n")
+		fmt.Printf("   Error: %s\n", health.LastError)
+		hasError = true
+	case health.LastRun.IsZero():
+		fmt.Printf("⚠ Notification checks: WARNING\n")
+		fmt.Printf("   No check has run yet\n")
+	default:
+		fmt.Printf("✓ Notification checks: OK (%d runs, last %s ago)\n",
+			health.Runs, time.Since(health.LastRun).Round(time.Second))
+	}
+
+	fmt.Println()
+	if hasError || health.Status != daemon.StatusOK {
+		fmt.Println("Diagnostics completed with errors.")
+		return fmt.Errorf("one or more health checks failed")
+	}
+
+	fmt.Println("All diagnostics passed!")
+	return nil
+}
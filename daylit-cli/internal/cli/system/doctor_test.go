@@ -1,13 +1,22 @@
 package system
 
 import (
+	"context"
+	"errors"
 	"io/fs"
+	"net/http/httptest"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/backup"
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/daemon"
 	"github.com/julianstephens/daylit/daylit-cli/internal/migration"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
 	"github.com/julianstephens/daylit/daylit-cli/migrations"
@@ -165,3 +174,173 @@ func TestCheckClockTimezone(t *testing.T) {
 		t.Errorf("clock/timezone check failed: %v", err)
 	}
 }
+
+func TestDoctorCmd_Remote(t *testing.T) {
+	ctx, cleanup := setupTestDoctorDB(t)
+	defer cleanup()
+
+	metrics := daemon.NewMetrics(time.Now())
+	metrics.RecordDBProbe(time.Millisecond, nil)
+	metrics.RecordRun(time.Now(), nil)
+	srv := httptest.NewServer(daemon.Handler(metrics))
+	defer srv.Close()
+
+	cmd := &DoctorCmd{Remote: srv.URL}
+	if err := cmd.Run(ctx); err != nil {
+		t.Errorf("doctor --remote failed on a healthy daemon: %v", err)
+	}
+
+	metrics.RecordDBProbe(time.Millisecond, errors.New("connection refused"))
+	if err := cmd.Run(ctx); err == nil {
+		t.Error("expected doctor --remote to fail when the daemon's database is down")
+	}
+
+	srv.Close()
+	if err := cmd.Run(ctx); err == nil {
+		t.Error("expected doctor --remote to fail when the daemon is unreachable")
+	}
+}
+
+func TestCheckSchemaIntegrity(t *testing.T) {
+	ctx, cleanup := setupTestDoctorDB(t)
+	defer cleanup()
+
+	if warning, err := checkSchemaIntegrity(ctx); err != nil || warning != "" {
+		t.Fatalf("expected a freshly initialized database to pass, got warning %q, error %v", warning, err)
+	}
+
+	db := ctx.Store.(*sqlite.Store).GetDB()
+
+	// A database migrated before checksums were tracked only warns
+	if _, err := db.Exec("DELETE FROM schema_checksums"); err != nil {
+		t.Fatalf("failed to clear checksums: %v", err)
+	}
+	if warning, err := checkSchemaIntegrity(ctx); err != nil || warning == "" {
+		t.Fatalf("expected a warning for unrecorded checksums, got warning %q, error %v", warning, err)
+	}
+
+	if _, err := db.Exec("INSERT INTO schema_checksums (version, checksum) VALUES (1, 'deadbeef')"); err != nil {
+		t.Fatalf("failed to tamper with checksum: %v", err)
+	}
+	if _, err := db.Exec("ALTER TABLE tasks ADD COLUMN scratch TEXT"); err != nil {
+		t.Fatalf("failed to alter tasks: %v", err)
+	}
+	_, err := checkSchemaIntegrity(ctx)
+	if err == nil {
+		t.Fatal("expected schema integrity check to fail")
+	}
+	for _, want := range []string{"migration 1 (init) was modified", "table tasks has unexpected columns scratch"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got: %v", want, err)
+		}
+	}
+}
+
+func TestDoctorCmd_OrphanedSlots(t *testing.T) {
+	ctx, cleanup := setupTestDoctorDB(t)
+	defer cleanup()
+	ctx.Clock = clock.Fixed(time.Date(2025, 6, 2, 10, 0, 0, 0, time.Local))
+
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	for _, task := range []models.Task{
+		{ID: "write", Name: "Write", Kind: constants.TaskKindFlexible, DurationMin: 60, Recurrence: daily, Priority: 3, Active: true},
+		{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: daily, Priority: 3, Active: true},
+	} {
+		if err := ctx.Store.AddTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	accepted := "2025-06-02T06:00:00Z"
+	for _, date := range []string{"2025-06-01", "2025-06-02"} {
+		plan := models.DayPlan{
+			Date:       date,
+			AcceptedAt: &accepted,
+			Slots: []models.Slot{
+				{Start: "09:00", End: "09:30", TaskID: "email", Status: constants.SlotStatusAccepted},
+				{Start: "11:00", End: "12:00", TaskID: "write", Status: constants.SlotStatusAccepted},
+			},
+		}
+		if err := ctx.Store.SavePlan(plan); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Email is deleted before it runs again
+	if err := ctx.Store.DeleteTask("write"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Store.DeleteTask("email"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only today's Write slot is still ahead
+	orphans, err := findOrphanedSlots(ctx)
+	if err != nil {
+		t.Fatalf("findOrphanedSlots failed: %v", err)
+	}
+	if len(orphans) != 1 || orphans[0].Date != "2025-06-02" || orphans[0].Slot.TaskID != "write" {
+		t.Fatalf("orphans = %+v, want today's write slot", orphans)
+	}
+
+	if err := (&DoctorCmd{Fix: true}).Run(ctx); err != nil {
+		t.Fatalf("doctor --fix failed: %v", err)
+	}
+	if orphans, err := findOrphanedSlots(ctx); err != nil || len(orphans) != 0 {
+		t.Errorf("expected no orphans after --fix, got %+v (%v)", orphans, err)
+	}
+	plan, err := ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Slots) != 1 || plan.Slots[0].TaskID != "email" {
+		t.Errorf("expected only the past email slot left, got %+v", plan.Slots)
+	}
+}
+
+func TestCheckReferentialIntegrity(t *testing.T) {
+	ctx, cleanup := setupTestDoctorDB(t)
+	defer cleanup()
+
+	if warning, err := checkReferentialIntegrity(ctx); err != nil || warning != "" {
+		t.Fatalf("expected a freshly initialized database to pass, got warning %q, error %v", warning, err)
+	}
+
+	db := ctx.Store.(*sqlite.Store).GetDB()
+
+	// The store's connections enforce foreign keys, so plant the rows a
+	// database from before they were enforced could hold on one that doesn't
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		"PRAGMA foreign_keys = OFF",
+		"INSERT INTO plans (date, revision) VALUES ('2025-06-02', 1)",
+		"INSERT INTO slots (plan_date, plan_revision, start_time, end_time, task_id, status) VALUES ('2025-06-02', 1, '09:00', '10:00', 'gone', 'planned')",
+		"INSERT INTO habit_entries (id, habit_id, day, created_at, updated_at) VALUES ('e1', 'gone', '2025-06-02', 'x', 'x')",
+		"PRAGMA foreign_keys = ON",
+	} {
+		if _, err := conn.ExecContext(context.Background(), stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	conn.Close()
+
+	_, err = checkReferentialIntegrity(ctx)
+	if err == nil {
+		t.Fatal("expected the check to fail once the foreign keys are in place")
+	}
+	for _, want := range []string{"1 slot(s) of tasks that no longer exist", "1 habit entries of habits"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got: %v", want, err)
+		}
+	}
+
+	// Before the foreign keys migration they are what migrating removes
+	if _, err := db.Exec("UPDATE schema_version SET version = ?", foreignKeysVersion-1); err != nil {
+		t.Fatal(err)
+	}
+	warning, err := checkReferentialIntegrity(ctx)
+	if err != nil || !strings.Contains(warning, "'daylit migrate' removes them") {
+		t.Fatalf("expected a warning before migrating, got warning %q, error %v", warning, err)
+	}
+}
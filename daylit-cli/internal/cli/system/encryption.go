@@ -0,0 +1,73 @@
+package system
+
+import (
+	"fmt"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/keyring"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+type EncryptionCmd struct {
+	Status EncryptionStatusCmd `cmd:"" help:"Show whether notes are encrypted." default:"1"`
+	On     EncryptionOnCmd     `cmd:"" help:"Encrypt feedback, plan, OT and habit notes with a key kept in the OS keyring."`
+	Off    EncryptionOffCmd    `cmd:"" help:"Decrypt every note and remove the key from the OS keyring."`
+}
+
+// noteEncrypter returns the store's note encryption, which only SQLite
+// databases have
+func noteEncrypter(ctx *cli.Context) (storage.NoteEncrypter, error) {
+	enc, ok := storage.Unwrap(ctx.Store).(storage.NoteEncrypter)
+	if !ok {
+		return nil, fmt.Errorf("note encryption is only available for SQLite databases")
+	}
+	return enc, nil
+}
+
+type EncryptionStatusCmd struct{}
+
+func (c *EncryptionStatusCmd) Run(ctx *cli.Context) error {
+	enc, err := noteEncrypter(ctx)
+	if err != nil {
+		return err
+	}
+	if enc.NotesEncrypted() {
+		fmt.Println("Notes are encrypted; the key is in this machine's OS keyring.")
+	} else {
+		fmt.Println("Notes are not encrypted. Turn encryption on with 'daylit encryption on'.")
+	}
+	return nil
+}
+
+type EncryptionOnCmd struct{}
+
+func (c *EncryptionOnCmd) Run(ctx *cli.Context) error {
+	enc, err := noteEncrypter(ctx)
+	if err != nil {
+		return err
+	}
+	if !keyring.IsAvailable() {
+		return fmt.Errorf("the OS keyring is not available, so there's nowhere to keep the key")
+	}
+	if err := enc.EncryptNotes(); err != nil {
+		return fmt.Errorf("failed to encrypt notes: %w", err)
+	}
+	fmt.Println("Notes are now encrypted with a key in this machine's OS keyring.")
+	fmt.Println("Without that key they can't be read: turn encryption off before moving the database to another machine.")
+	fmt.Println("Backups made earlier still hold the notes unencrypted (see 'daylit backup list').")
+	return nil
+}
+
+type EncryptionOffCmd struct{}
+
+func (c *EncryptionOffCmd) Run(ctx *cli.Context) error {
+	enc, err := noteEncrypter(ctx)
+	if err != nil {
+		return err
+	}
+	if err := enc.DecryptNotes(); err != nil {
+		return fmt.Errorf("failed to decrypt notes: %w", err)
+	}
+	fmt.Println("Notes are no longer encrypted, and their key was removed from the OS keyring.")
+	return nil
+}
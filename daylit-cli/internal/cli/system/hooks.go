@@ -0,0 +1,101 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/hooks"
+)
+
+type HooksCmd struct {
+	List HooksListCmd `cmd:"" default:"1" help:"List the hooks directory and the hooks found for each event."`
+	Test HooksTestCmd `cmd:"" help:"Run an event's hooks with sample data, showing their output."`
+}
+
+type HooksListCmd struct{}
+
+func (c *HooksListCmd) Run(ctx *cli.Context) error {
+	dir := hooks.Dir()
+	fmt.Printf("Hooks directory: %s\n", dir)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		fmt.Println("  (does not exist yet)")
+	}
+	fmt.Println()
+
+	for _, event := range hooks.Events {
+		paths, err := hooks.Find(dir, event)
+		if err != nil {
+			return fmt.Errorf("failed to read hooks directory: %w", err)
+		}
+		fmt.Printf("  %s\n", event)
+		if len(paths) == 0 {
+			fmt.Println("      (none)")
+		}
+		for _, path := range paths {
+			fmt.Printf("      %s\n", filepath.Base(path))
+		}
+	}
+	return nil
+}
+
+type HooksTestCmd struct {
+	Event string `arg:"" help:"Event to fire: plan_accepted, slot_started, slot_done, feedback_recorded or habit_marked."`
+}
+
+func (c *HooksTestCmd) Run(ctx *cli.Context) error {
+	event, err := hooks.ParseEvent(c.Event)
+	if err != nil {
+		return err
+	}
+
+	dir := hooks.Dir()
+	paths, err := hooks.Find(dir, event)
+	if err != nil {
+		return fmt.Errorf("failed to read hooks directory: %w", err)
+	}
+	if len(paths) == 0 {
+		fmt.Printf("No hooks for %s in %s\n", event, dir)
+		return nil
+	}
+
+	runner := hooks.Runner{Dir: dir, Timeout: constants.HookTimeout, Stdout: os.Stdout}
+	if err := runner.Run(event, sampleHookData(event, ctx.Now())); err != nil {
+		return err
+	}
+	fmt.Printf("Ran %d hook(s) for %s\n", len(paths), event)
+	return nil
+}
+
+// sampleHookData is made-up data for testing hooks, shaped like the event's
+// real data, dated the day of now
+func sampleHookData(event hooks.Event, now time.Time) any {
+	today := now.Format(constants.DateFormat)
+	slot := hooks.SlotData{
+		Date:     today,
+		Start:    "09:00",
+		End:      "10:30",
+		TaskID:   "00000000-0000-0000-0000-000000000000",
+		TaskName: "Deep work",
+		Status:   constants.SlotStatusAccepted,
+	}
+
+	switch event {
+	case hooks.PlanAccepted:
+		return hooks.PlanData{Date: today, Revision: 1, Slots: []hooks.SlotData{slot}}
+	case hooks.SlotStarted:
+		slot.ActualStart = "09:02"
+	case hooks.SlotDone:
+		slot.Status = constants.SlotStatusDone
+		slot.ActualStart, slot.ActualEnd = "09:02", "10:25"
+	case hooks.FeedbackRecorded:
+		slot.Status = constants.SlotStatusDone
+		slot.Rating = string(constants.FeedbackOnTrack)
+	case hooks.HabitMarked:
+		return hooks.HabitData{HabitID: "00000000-0000-0000-0000-000000000000", Name: "Meditate", Day: today}
+	}
+	return slot
+}
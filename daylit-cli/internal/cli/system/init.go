@@ -9,6 +9,7 @@ import (
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/mysql"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage/postgres"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
 )
@@ -71,7 +72,15 @@ func (c *InitCmd) Run(ctx *cli.Context) error {
 func (c *InitCmd) migrateData(ctx *cli.Context, sourcePath string) error {
 	// Determine source store type and instantiate it
 	var sourceStore storage.Provider
-	if strings.HasPrefix(sourcePath, "postgres://") || strings.HasPrefix(sourcePath, "postgresql://") {
+	if mysql.IsConnString(sourcePath) {
+		if valid, err := mysql.ValidateConnString(sourcePath); !valid {
+			if errors.Is(err, mysql.ErrEmbeddedCredentials) {
+				return fmt.Errorf("MySQL source connection string contains embedded credentials. Use a passwordless account or socket authentication instead")
+			}
+			return err
+		}
+		sourceStore = mysql.New(sourcePath)
+	} else if strings.HasPrefix(sourcePath, "postgres://") || strings.HasPrefix(sourcePath, "postgresql://") {
 		// Validate source connection string for embedded credentials
 		if valid, err := postgres.ValidateConnString(sourcePath); !valid {
 			if errors.Is(err, postgres.ErrEmbeddedCredentials) {
@@ -92,16 +101,65 @@ func (c *InitCmd) migrateData(ctx *cli.Context, sourcePath string) error {
 	}
 	defer sourceStore.Close()
 
+	// Everything is copied in one transaction, so a failure part way leaves
+	// the destination as it was rather than half migrated
+	return ctx.InTx(func() error {
+		return copyData(ctx, sourceStore)
+	})
+}
+
+// copyData copies every record of sourceStore into ctx.Store
+func copyData(ctx *cli.Context, sourceStore storage.Provider) error {
 	// Migrate Settings
 	fmt.Println("  Migrating settings...")
 	settings, err := sourceStore.GetSettings()
 	if err != nil {
 		return fmt.Errorf("failed to get settings from source: %w", err)
 	}
+	settings.Version = 0 // Overwrite the destination's defaults without a conflict check
 	if err := ctx.Store.SaveSettings(settings); err != nil {
 		return fmt.Errorf("failed to save settings to destination: %w", err)
 	}
 
+	// Migrate Projects
+	fmt.Println("  Migrating projects...")
+	projects, err := sourceStore.GetAllProjects()
+	if err != nil {
+		return fmt.Errorf("failed to get projects from source: %w", err)
+	}
+	for _, project := range projects {
+		if err := ctx.Store.AddProject(project); err != nil {
+			return fmt.Errorf("failed to add project %s: %w", project.ID, err)
+		}
+	}
+	fmt.Printf("    Migrated %d projects\n", len(projects))
+
+	// Migrate Task Pools
+	fmt.Println("  Migrating task pools...")
+	pools, err := sourceStore.GetAllPools()
+	if err != nil {
+		return fmt.Errorf("failed to get task pools from source: %w", err)
+	}
+	for _, pool := range pools {
+		if err := ctx.Store.AddPool(pool); err != nil {
+			return fmt.Errorf("failed to add task pool %s: %w", pool.ID, err)
+		}
+	}
+	fmt.Printf("    Migrated %d task pools\n", len(pools))
+
+	// Migrate Inbox
+	fmt.Println("  Migrating inbox...")
+	inbox, err := sourceStore.GetInboxItems()
+	if err != nil {
+		return fmt.Errorf("failed to get inbox from source: %w", err)
+	}
+	for _, item := range inbox {
+		if err := ctx.Store.AddInboxItem(item); err != nil {
+			return fmt.Errorf("failed to add inbox item %s: %w", item.ID, err)
+		}
+	}
+	fmt.Printf("    Migrated %d inbox items\n", len(inbox))
+
 	// Migrate Tasks
 	fmt.Println("  Migrating tasks...")
 	tasks, err := sourceStore.GetAllTasksIncludingDeleted()
@@ -109,12 +167,52 @@ func (c *InitCmd) migrateData(ctx *cli.Context, sourcePath string) error {
 		return fmt.Errorf("failed to get tasks from source: %w", err)
 	}
 	for _, task := range tasks {
+		task.Version = 0 // Versions are local to each database
 		if err := ctx.Store.AddTask(task); err != nil {
 			return fmt.Errorf("failed to add task %s: %w", task.ID, err)
 		}
 	}
 	fmt.Printf("    Migrated %d tasks\n", len(tasks))
 
+	// Migrate Day Templates
+	fmt.Println("  Migrating day templates...")
+	templates, err := sourceStore.GetAllDayTemplates()
+	if err != nil {
+		return fmt.Errorf("failed to get day templates from source: %w", err)
+	}
+	for _, template := range templates {
+		if err := ctx.Store.SaveDayTemplate(template); err != nil {
+			return fmt.Errorf("failed to save day template %s: %w", template.Name, err)
+		}
+	}
+	fmt.Printf("    Migrated %d day templates\n", len(templates))
+
+	// Migrate Vacations
+	fmt.Println("  Migrating vacations...")
+	vacations, err := sourceStore.GetVacations("0000-01-01", "9999-12-31")
+	if err != nil {
+		return fmt.Errorf("failed to get vacations from source: %w", err)
+	}
+	for _, vacation := range vacations {
+		if err := ctx.Store.AddVacation(vacation); err != nil {
+			return fmt.Errorf("failed to add vacation %s: %w", vacation.ID, err)
+		}
+	}
+	fmt.Printf("    Migrated %d vacations\n", len(vacations))
+
+	// Migrate Slot Reminders
+	fmt.Println("  Migrating slot reminders...")
+	reminders, err := sourceStore.GetSlotReminders("0000-01-01", "9999-12-31")
+	if err != nil {
+		return fmt.Errorf("failed to get slot reminders from source: %w", err)
+	}
+	for _, reminder := range reminders {
+		if err := ctx.Store.AddSlotReminder(reminder); err != nil {
+			return fmt.Errorf("failed to add slot reminder %s: %w", reminder.ID, err)
+		}
+	}
+	fmt.Printf("    Migrated %d slot reminders\n", len(reminders))
+
 	// Migrate Plans
 	fmt.Println("  Migrating plans...")
 	plans, err := sourceStore.GetAllPlans()
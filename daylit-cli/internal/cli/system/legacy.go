@@ -0,0 +1,180 @@
+package system
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+)
+
+// sqliteHeader starts every SQLite database file
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+type LegacyCmd struct {
+	Import LegacyImportCmd `cmd:"" help:"Import the state.json or SQLite database of a daylit before v0.4."`
+}
+
+type LegacyImportCmd struct {
+	Path string `arg:"" type:"existingfile" help:"The old state.json, or the old SQLite database."`
+}
+
+func (c *LegacyImportCmd) Run(ctx *cli.Context) error {
+	data, err := os.ReadFile(c.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", c.Path, err)
+	}
+
+	var source storage.Provider
+	var cleanup func()
+	switch {
+	case bytes.HasPrefix(data, sqliteHeader):
+		source, cleanup, err = openLegacySQLite(c.Path, data)
+	case bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")):
+		source, err = loadLegacyState(data)
+		cleanup = func() {}
+	default:
+		return fmt.Errorf("%s is neither a daylit state.json nor a SQLite database", c.Path)
+	}
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	fmt.Printf("Importing data from: %s\n", c.Path)
+	if err := ctx.InTx(func() error {
+		return copyData(ctx, source)
+	}); err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+	fmt.Println("Import completed successfully!")
+	return nil
+}
+
+// legacyState is the state.json the JSON storage of v0.1 to v0.3 kept. Plans
+// were keyed by date in some versions and listed in others.
+type legacyState struct {
+	Settings models.Settings `json:"settings"`
+	Tasks    []models.Task   `json:"tasks"`
+	Plans    json.RawMessage `json:"plans"`
+}
+
+// loadLegacyState reads a state.json into a memory store copyData can read
+// from
+func loadLegacyState(data []byte) (storage.Provider, error) {
+	var state legacyState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state.json: %w", err)
+	}
+	plans, err := legacyPlans(state.Plans)
+	if err != nil {
+		return nil, err
+	}
+
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		return nil, err
+	}
+	if state.Settings.DayStart != "" {
+		settings, err := store.GetSettings()
+		if err != nil {
+			return nil, err
+		}
+		// Only the fields state.json had; the rest keep their defaults
+		settings.DayStart = state.Settings.DayStart
+		settings.DayEnd = state.Settings.DayEnd
+		if state.Settings.DefaultBlockMin > 0 {
+			settings.DefaultBlockMin = state.Settings.DefaultBlockMin
+		}
+		if err := store.SaveSettings(settings); err != nil {
+			return nil, fmt.Errorf("invalid settings in state.json: %w", err)
+		}
+	}
+
+	tasks := make(map[string]bool, len(state.Tasks))
+	for _, task := range state.Tasks {
+		task.Version = 0
+		if err := store.AddTask(task); err != nil {
+			return nil, fmt.Errorf("failed to read task %s: %w", task.ID, err)
+		}
+		tasks[task.ID] = true
+	}
+	for _, plan := range plans {
+		// Slots of tasks the state no longer had can't be kept
+		slots := plan.Slots[:0]
+		for _, slot := range plan.Slots {
+			if tasks[slot.TaskID] {
+				slots = append(slots, slot)
+			} else {
+				fmt.Printf("  Skipping the %s slot of %s: task %s is missing\n", slot.Start, plan.Date, slot.TaskID)
+			}
+		}
+		plan.Slots = slots
+		plan.Revision, plan.Version = 0, 0
+		if err := store.SavePlan(plan); err != nil {
+			return nil, fmt.Errorf("failed to read plan for %s: %w", plan.Date, err)
+		}
+	}
+	return store, nil
+}
+
+// legacyPlans decodes the plans of a state.json, whether keyed by date or
+// listed, in date order
+func legacyPlans(raw json.RawMessage) ([]models.DayPlan, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	var plans []models.DayPlan
+	if raw[0] == '[' {
+		if err := json.Unmarshal(raw, &plans); err != nil {
+			return nil, fmt.Errorf("failed to parse plans in state.json: %w", err)
+		}
+	} else {
+		var byDate map[string]models.DayPlan
+		if err := json.Unmarshal(raw, &byDate); err != nil {
+			return nil, fmt.Errorf("failed to parse plans in state.json: %w", err)
+		}
+		for date, plan := range byDate {
+			if plan.Date == "" {
+				plan.Date = date
+			}
+			plans = append(plans, plan)
+		}
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].Date < plans[j].Date })
+	return plans, nil
+}
+
+// openLegacySQLite opens a copy of the old SQLite database at path, whose
+// contents are data, with its schema migrated to the current version,
+// leaving the original untouched. cleanup closes the copy and removes it.
+func openLegacySQLite(path string, data []byte) (storage.Provider, func(), error) {
+	dir, err := os.MkdirTemp("", "daylit-legacy-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create a working directory: %w", err)
+	}
+	copyPath := filepath.Join(dir, "daylit.db")
+	if err := os.WriteFile(copyPath, data, 0600); err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, fmt.Errorf("failed to copy %s: %w", path, err)
+	}
+
+	store := sqlite.NewStore(copyPath)
+	cleanup := func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+	if err := store.Init(); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to upgrade %s: %w", path, err)
+	}
+	return store, cleanup, nil
+}
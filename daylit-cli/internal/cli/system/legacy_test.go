@@ -0,0 +1,188 @@
+package system
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+const legacyStateJSON = `{
+  "version": 1,
+  "settings": {"day_start": "06:30", "day_end": "21:00", "default_block_min": 45},
+  "tasks": [
+    {"id": "t1", "name": "Run", "kind": "flexible", "duration_min": 30,
+     "recurrence": {"type": "daily"}, "priority": 2, "active": true}
+  ],
+  "plans": {
+    "2024-03-01": {"slots": [
+      {"start": "07:00", "end": "07:30", "task_id": "t1", "status": "done"},
+      {"start": "08:00", "end": "08:30", "task_id": "gone", "status": "planned"}
+    ]}
+  }
+}`
+
+func TestLegacyImportCmd_StateJSON(t *testing.T) {
+	ctx, _, cleanup := setupTestInitDB(t)
+	defer cleanup()
+	if err := ctx.Store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte(legacyStateJSON), 0600); err != nil {
+		t.Fatalf("failed to write state.json: %v", err)
+	}
+	if err := (&LegacyImportCmd{Path: path}).Run(ctx); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if settings.DayStart != "06:30" || settings.DayEnd != "21:00" || settings.DefaultBlockMin != 45 {
+		t.Errorf("settings = %s-%s/%d, want 06:30-21:00/45", settings.DayStart, settings.DayEnd, settings.DefaultBlockMin)
+	}
+	if settings.Timezone == "" {
+		t.Error("settings missing from state.json lost their defaults")
+	}
+	if task, err := ctx.Store.GetTask("t1"); err != nil || task.Name != "Run" {
+		t.Errorf("task = %+v, %v, want Run", task, err)
+	}
+	plan, err := ctx.Store.GetPlan("2024-03-01")
+	if err != nil {
+		t.Fatalf("failed to get plan: %v", err)
+	}
+	if len(plan.Slots) != 1 || plan.Slots[0].TaskID != "t1" {
+		t.Errorf("slots = %+v, want only the slot of the task that exists", plan.Slots)
+	}
+}
+
+// legacySQLiteSchema is the layout of the SQLite database daylit kept
+// before v0.4, from before schema versions were recorded
+const legacySQLiteSchema = `
+CREATE TABLE settings (
+    key TEXT PRIMARY KEY,
+    value TEXT
+);
+CREATE TABLE tasks (
+    id TEXT PRIMARY KEY,
+    name TEXT,
+    kind TEXT,
+    duration_min INTEGER,
+    earliest_start TEXT,
+    latest_end TEXT,
+    fixed_start TEXT,
+    fixed_end TEXT,
+    recurrence_type TEXT,
+    recurrence_interval INTEGER,
+    recurrence_weekdays TEXT,
+    priority INTEGER,
+    energy_band TEXT,
+    active BOOLEAN,
+    last_done TEXT,
+    success_streak INTEGER,
+    avg_actual_duration REAL
+);
+CREATE TABLE plans (
+    date TEXT PRIMARY KEY
+);
+CREATE TABLE slots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    plan_date TEXT,
+    start_time TEXT,
+    end_time TEXT,
+    task_id TEXT,
+    status TEXT,
+    feedback_rating TEXT,
+    feedback_note TEXT,
+    FOREIGN KEY(plan_date) REFERENCES plans(date),
+    FOREIGN KEY(task_id) REFERENCES tasks(id)
+);
+INSERT INTO settings (key, value) VALUES ('day_start', '06:30'), ('day_end', '21:00'), ('default_block_min', '45');
+INSERT INTO tasks VALUES ('t1', 'Read', 'flexible', 30, '', '', '', '', 'weekly', 1, '[1,3]', 2, 'high', 1, '2024-02-28', 4, 28.5);
+INSERT INTO plans (date) VALUES ('2024-03-01');
+INSERT INTO slots (plan_date, start_time, end_time, task_id, status, feedback_rating, feedback_note)
+VALUES ('2024-03-01', '07:00', '07:30', 't1', 'done', 'on_track', 'good'),
+       ('2024-03-01', '08:00', '08:30', 't1', 'skipped', '', '');
+`
+
+func TestLegacyImportCmd_SQLite(t *testing.T) {
+	ctx, _, cleanup := setupTestInitDB(t)
+	defer cleanup()
+	if err := ctx.Store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+
+	sourcePath := filepath.Join(t.TempDir(), "old.db")
+	db, err := sql.Open("sqlite", sourcePath)
+	if err != nil {
+		t.Fatalf("failed to open source: %v", err)
+	}
+	if _, err := db.Exec(legacySQLiteSchema); err != nil {
+		t.Fatalf("failed to create the old database: %v", err)
+	}
+	db.Close()
+	before, err := os.ReadFile(sourcePath)
+	if err != nil {
+		t.Fatalf("failed to read source: %v", err)
+	}
+
+	if err := (&LegacyImportCmd{Path: sourcePath}).Run(ctx); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if settings.DayStart != "06:30" || settings.DayEnd != "21:00" || settings.DefaultBlockMin != 45 {
+		t.Errorf("settings = %s-%s/%d, want 06:30-21:00/45", settings.DayStart, settings.DayEnd, settings.DefaultBlockMin)
+	}
+	task, err := ctx.Store.GetTask("t1")
+	if err != nil {
+		t.Fatalf("task was not imported: %v", err)
+	}
+	if task.Name != "Read" || task.DurationMin != 30 || task.Priority != 2 || !task.Active ||
+		task.LastDone != "2024-02-28" || task.SuccessStreak != 4 || task.AvgActualDurationMin != 28.5 {
+		t.Errorf("task = %+v, want Read as stored", task)
+	}
+	if task.Recurrence.Type != constants.RecurrenceWeekly || len(task.Recurrence.WeekdayMask) != 2 {
+		t.Errorf("recurrence = %+v, want weekly on two days", task.Recurrence)
+	}
+	plan, err := ctx.Store.GetPlan("2024-03-01")
+	if err != nil {
+		t.Fatalf("failed to get plan: %v", err)
+	}
+	if len(plan.Slots) != 2 {
+		t.Fatalf("slots = %+v, want both", plan.Slots)
+	}
+	done := plan.Slots[0]
+	if done.Start != "07:00" || done.End != "07:30" || done.Status != constants.SlotStatusDone ||
+		done.Feedback == nil || done.Feedback.Rating != constants.FeedbackOnTrack || done.Feedback.Note != "good" {
+		t.Errorf("first slot = %+v, want done and rated on track", done)
+	}
+	if plan.Slots[1].Status != constants.SlotStatusSkipped {
+		t.Errorf("second slot = %+v, want skipped", plan.Slots[1])
+	}
+	if after, _ := os.ReadFile(sourcePath); !bytes.Equal(before, after) {
+		t.Error("the old database was changed")
+	}
+}
+
+func TestLegacyImportCmd_RejectsOtherFiles(t *testing.T) {
+	ctx, _, cleanup := setupTestInitDB(t)
+	defer cleanup()
+
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("not daylit data"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := (&LegacyImportCmd{Path: path}).Run(ctx); err == nil {
+		t.Error("importing a text file succeeded")
+	}
+}
@@ -2,10 +2,10 @@ package system
 
 import (
 	"fmt"
-	"io/fs"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
 	"github.com/julianstephens/daylit/daylit-cli/internal/migration"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
 	"github.com/julianstephens/daylit/daylit-cli/migrations"
 )
@@ -16,15 +16,15 @@ func (c *MigrateCmd) Run(ctx *cli.Context) error {
 	defer ctx.Store.Close()
 
 	// Get database connection for SQLite stores
-	sqliteStore, ok := ctx.Store.(*sqlite.Store)
+	sqliteStore, ok := storage.Unwrap(ctx.Store).(*sqlite.Store)
 	if !ok {
 		return fmt.Errorf("migrate command only supports SQLite storage")
 	}
 
 	// Get the embedded SQLite migrations sub-filesystem
-	subFS, err := fs.Sub(migrations.FS, "sqlite")
+	subFS, err := migrations.Backend("sqlite")
 	if err != nil {
-		return fmt.Errorf("failed to access sqlite migrations: %w", err)
+		return err
 	}
 
 	// Get database connection
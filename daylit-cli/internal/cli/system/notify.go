@@ -1,22 +1,50 @@
 package system
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/julianstephens/daylit/daylit-cli/internal/autoplan"
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/hooks"
+	"github.com/julianstephens/daylit/daylit-cli/internal/i18n"
+	"github.com/julianstephens/daylit/daylit-cli/internal/metrics"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/notifier"
+	"github.com/julianstephens/daylit/daylit-cli/internal/summary"
 	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
 )
 
 type NotifyCmd struct {
-	DryRun bool `help:"Print notifications to stdout instead of sending them."`
+	DryRun     bool   `help:"Print notifications to stdout instead of sending them."`
+	SimulateAt string `name:"simulate-at" placeholder:"TIME" help:"Show what notify would do at this time (YYYY-MM-DDTHH:MM or HH:MM) and why, without sending or saving anything."`
+
+	// OnDeliver, if set, is called with every notification after delivery
+	OnDeliver func(models.NotificationLogEntry) `kong:"-"`
+	// Publish, if set, is offered every notification first; it returns
+	// whether a client of the daemon's socket took it, in which case the
+	// tray isn't sent it over its webhook
+	Publish func(text string, style notifier.Options) bool `kong:"-"`
+
+	// batching holds notifications in pending, to be sent as one digest
+	batching bool
+	pending  []models.NotificationLogEntry
+	// simulating makes explain print why notifications are or aren't sent
+	simulating bool
 }
 
+// errSimulated rolls back the transaction a simulation runs in
+var errSimulated = errors.New("simulated")
+
 func (c *NotifyCmd) Run(ctx *cli.Context) error {
+	if c.SimulateAt != "" {
+		return c.simulate(ctx)
+	}
+
 	var err error
 	for attempt := 0; attempt < constants.NotifyMaxRetries; attempt++ {
 		err = c.runWithRetry(ctx)
@@ -33,6 +61,52 @@ func (c *NotifyCmd) Run(ctx *cli.Context) error {
 	return err
 }
 
+// simulate runs a notification check as if it were SimulateAt, printing the
+// notifications it would send and why the others aren't due. It runs in a
+// transaction that is rolled back, and with hooks suppressed, so nothing it
+// does is kept; within the run, later checks still see what earlier ones
+// did, such as a plan generated hands-free.
+func (c *NotifyCmd) simulate(ctx *cli.Context) error {
+	at, err := clock.Parse(c.SimulateAt, ctx.Now())
+	if err != nil {
+		return err
+	}
+	if err := ctx.Store.Load(); err != nil {
+		return err
+	}
+
+	saved := ctx.Clock
+	ctx.Clock = clock.Fixed(at)
+	defer func() { ctx.Clock = saved }()
+	defer hooks.Suppress()()
+	c.DryRun, c.simulating = true, true
+
+	fmt.Printf("Simulating daylit notify at %s; nothing is sent or saved.\n\n", at.Format("Mon 2006-01-02 15:04"))
+	err = ctx.InTx(func() error {
+		if err := c.runWithRetry(ctx); err != nil {
+			return err
+		}
+		return errSimulated
+	})
+	if errors.Is(err, errSimulated) {
+		return nil
+	}
+	return err
+}
+
+// explain prints why a notification is or isn't sent, when simulating
+func (c *NotifyCmd) explain(format string, args ...any) {
+	if c.simulating {
+		fmt.Printf("  · "+format+"\n", args...)
+	}
+}
+
+// clockAt returns the clock time minutes from midnight of the plan date,
+// given that now is currentMinutes from it
+func clockAt(now time.Time, currentMinutes, minutes int) string {
+	return now.Add(time.Duration(minutes-currentMinutes) * time.Minute).Format(constants.TimeFormat)
+}
+
 func isDatabaseBusyError(err error) bool {
 	if err == nil {
 		return false
@@ -54,6 +128,32 @@ func (c *NotifyCmd) runWithRetry(ctx *cli.Context) error {
 		return fmt.Errorf("failed to get settings: %w", err)
 	}
 
+	now := ctx.Now()
+	n := notifier.New()
+
+	// Notifications due in the same run go out together as one digest
+	if settings.NotificationDigestWindowMin > 0 {
+		c.batching = true
+		defer c.flushDigest(ctx, n)
+	}
+
+	// Runs before the notifications check so hands-free mode can plan the day
+	// even when notifications are off
+	if err := c.checkMorningPlan(ctx, settings, now, n); err != nil {
+		return err
+	}
+	if err := c.checkWeeklySummary(ctx, settings, now, n); err != nil {
+		return err
+	}
+	if err := c.checkMetrics(ctx, settings, now); err != nil {
+		return err
+	}
+
+	// Claims this old are past any notification's grace period
+	if err := ctx.Store.DeleteNotificationClaims(now.Add(-constants.NotifyClaimRetention)); err != nil {
+		fmt.Printf("Failed to delete old notification claims: %v\n", err)
+	}
+
 	if !settings.NotificationsEnabled {
 		if c.DryRun {
 			fmt.Println("Notifications are disabled in settings.")
@@ -61,10 +161,44 @@ func (c *NotifyCmd) runWithRetry(ctx *cli.Context) error {
 		return nil
 	}
 
-	now := time.Now()
 	dateStr := now.Format("2006-01-02")
 	currentMinutes := now.Hour()*60 + now.Minute()
 
+	if vacations, err := ctx.Store.GetVacations(dateStr, dateStr); err == nil && len(vacations) > 0 {
+		if c.DryRun {
+			fmt.Println("Notifications are muted while on vacation.")
+		}
+		return nil
+	}
+
+	// Notifications that failed on earlier runs go out before new ones
+	if !c.DryRun {
+		c.retryOutbox(ctx, n, now)
+	}
+
+	// Without valid day boundaries, slots are read as plain clock times
+	window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+	if err != nil {
+		window = models.DayWindow{}
+	}
+
+	// Before the day starts, yesterday's plan may still be running if the day
+	// ends late, and blocks that ended shortly before midnight are still
+	// within the grace period
+	if currentMinutes < window.Start || currentMinutes <= settings.NotificationGracePeriodMin {
+		yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+		if prev, err := ctx.Store.GetLatestPlanRevision(yesterday); err == nil {
+			if err := c.checkPlanSlots(ctx, settings, window, prev, currentMinutes+models.MinutesPerDay, now, 0, n); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Habit reminders go out whether or not there is a plan
+	if err := c.checkHabitReminders(ctx, now, currentMinutes, settings.NotificationGracePeriodMin, n); err != nil {
+		return err
+	}
+
 	// Get the latest plan for today
 	plan, err := ctx.Store.GetLatestPlanRevision(dateStr)
 	if err != nil {
@@ -75,33 +209,93 @@ func (c *NotifyCmd) runWithRetry(ctx *cli.Context) error {
 		return nil
 	}
 
-	n := notifier.New()
+	if err := c.checkPlanSlots(ctx, settings, window, plan, currentMinutes, now, 0, n); err != nil {
+		return err
+	}
+
+	// Check alerts
+	if err := c.checkAndSendAlerts(ctx, now, n); err != nil {
+		return err
+	}
+
+	// A digest is going out anyway, so blocks starting within the digest
+	// window join it rather than sending their own notification moments later
+	if len(c.pending) > 0 {
+		// Reload the plan so slots notified above aren't picked up again
+		plan, err := ctx.Store.GetLatestPlanRevision(dateStr)
+		if err != nil {
+			return nil
+		}
+		if err := c.checkPlanSlots(ctx, settings, window, plan, currentMinutes, now, settings.NotificationDigestWindowMin, n); err != nil {
+			return err
+		}
+	}
 
+	return nil
+}
+
+// checkPlanSlots sends the start and end notifications due for a plan's
+// slots. currentMinutes is measured from midnight of the plan date. A
+// positive lookaheadMin only checks start notifications, including those due
+// within the next lookaheadMin minutes.
+func (c *NotifyCmd) checkPlanSlots(
+	ctx *cli.Context,
+	settings models.Settings,
+	window models.DayWindow,
+	plan models.DayPlan,
+	currentMinutes int,
+	now time.Time,
+	lookaheadMin int,
+	n *notifier.Notifier,
+) error {
 	for _, slot := range plan.Slots {
 		// Only notify for accepted or done slots
 		if slot.Status != constants.SlotStatusAccepted && slot.Status != constants.SlotStatusDone {
+			if lookaheadMin == 0 {
+				c.explain("%s: slot is %s, not accepted", slot.Start, slot.Status)
+			}
 			continue
 		}
 
-		startMinutes, err := utils.ParseTimeToMinutes(slot.Start)
-		if err != nil {
-			continue
-		}
-		endMinutes, err := utils.ParseTimeToMinutes(slot.End)
+		startMinutes, endMinutes, err := window.Range(slot.Start, slot.End)
 		if err != nil {
 			continue
 		}
 
-		taskName := "Unknown Task"
+		taskName := i18n.T("notify.unknown_task")
+		notifyStart := settings.NotifyBlockStart
+		startOffset := settings.BlockStartOffsetMin
+		startMessage := ""
+		var startStyle notifier.Options
 		if task, err := ctx.Store.GetTask(slot.TaskID); err == nil {
 			taskName = task.Name
+			// Per-task preferences override the global start notification settings
+			if task.NotifyStartDisabled {
+				notifyStart = false
+			}
+			if task.NotifyOffsetMin != nil {
+				startOffset = *task.NotifyOffsetMin
+			}
+			startMessage = task.NotifyMessage
+			startStyle = notifier.Options{Urgency: task.NotifyUrgency, Sound: task.NotifySound}
+			// Appointments with prep or travel time are notified when it's
+			// time to get ready rather than when they start
+			if before, _ := task.Padding(); before > 0 {
+				startOffset += before
+				if startMessage == "" {
+					startMessage = getReadyMessage(task, startMinutes)
+				}
+			}
 		}
 
 		// Check Start Notification
-		if settings.NotifyBlockStart {
+		if !notifyStart && lookaheadMin == 0 {
+			c.explain("%s %s: start notifications are off", slot.Start, taskName)
+		}
+		if notifyStart {
 			if err := c.checkAndSendStartNotification(
-				ctx, &slot, taskName, startMinutes, currentMinutes, now,
-				settings.BlockStartOffsetMin, settings.NotificationGracePeriodMin,
+				ctx, &slot, taskName, startMessage, startStyle, startMinutes, currentMinutes, now,
+				startOffset, settings.NotificationGracePeriodMin, lookaheadMin,
 				plan.Date, plan.Revision, n,
 			); err != nil {
 				return err
@@ -109,7 +303,7 @@ func (c *NotifyCmd) runWithRetry(ctx *cli.Context) error {
 		}
 
 		// Check End Notification
-		if settings.NotifyBlockEnd {
+		if settings.NotifyBlockEnd && lookaheadMin == 0 {
 			if err := c.checkAndSendEndNotification(
 				ctx, &slot, taskName, endMinutes, currentMinutes, now,
 				settings.BlockEndOffsetMin, settings.NotificationGracePeriodMin,
@@ -120,36 +314,268 @@ func (c *NotifyCmd) runWithRetry(ctx *cli.Context) error {
 		}
 	}
 
-	// Check alerts
-	if err := c.checkAndSendAlerts(ctx, now, n); err != nil {
-		return err
+	if lookaheadMin > 0 {
+		return nil
+	}
+	return c.checkSlotReminders(ctx, window, plan, currentMinutes, now, settings.NotificationGracePeriodMin, n)
+}
+
+// getReadyMessage is the start notification text of an appointment with prep
+// or travel time, starting at startMinutes on the plan day
+func getReadyMessage(task models.Task, startMinutes int) string {
+	switch {
+	case task.PrepMin > 0 && task.TravelMin > 0:
+		leave := (startMinutes - task.TravelMin + models.MinutesPerDay) % models.MinutesPerDay
+		return i18n.T("notify.get_ready", task.Name, fmt.Sprintf("%02d:%02d", leave/60, leave%60))
+	case task.PrepMin > 0:
+		return i18n.T("notify.get_ready_here", task.Name)
+	}
+	return i18n.T("notify.leave_for", task.Name)
+}
+
+// checkSlotReminders sends the reminders attached to a plan's slots once
+// they are due. Reminders for slots that are gone, done or no longer
+// accepted are skipped. currentMinutes is measured from midnight of the plan
+// date.
+func (c *NotifyCmd) checkSlotReminders(
+	ctx *cli.Context,
+	window models.DayWindow,
+	plan models.DayPlan,
+	currentMinutes int,
+	now time.Time,
+	gracePeriodMin int,
+	n *notifier.Notifier,
+) error {
+	reminders, err := ctx.Store.GetSlotReminders(plan.Date, plan.Date)
+	if err != nil {
+		return fmt.Errorf("failed to get slot reminders: %w", err)
+	}
+
+	for _, reminder := range reminders {
+		if reminder.SentAt != nil {
+			continue
+		}
+		i := reminder.FindSlot(plan.Slots)
+		if i < 0 || plan.Slots[i].Status != constants.SlotStatusAccepted {
+			continue
+		}
+		slot := plan.Slots[i]
+		startMinutes, err := window.Minutes(slot.Start)
+		if err != nil {
+			continue
+		}
+
+		triggerTime := startMinutes - reminder.OffsetMin
+		if currentMinutes < triggerTime || currentMinutes-triggerTime > gracePeriodMin {
+			continue
+		}
+
+		taskName := i18n.T("notify.unknown_task")
+		if task, err := ctx.Store.GetTask(slot.TaskID); err == nil {
+			taskName = task.Name
+		}
+		var msg string
+		switch untilStart := startMinutes - currentMinutes; {
+		case reminder.Message != "":
+			msg = fmt.Sprintf("%s (%s)", reminder.Message, slot.Start)
+		case untilStart > 0:
+			msg = i18n.T("notify.reminder", taskName, untilStart, slot.Start)
+		case untilStart == 0:
+			msg = i18n.T("notify.starting_now", taskName, slot.Start)
+		default:
+			msg = i18n.T("notify.started_ago", -untilStart, taskName, slot.Start)
+		}
+
+		// Mark the reminder sent BEFORE sending to avoid duplicates
+		if err := ctx.Store.MarkSlotReminderSent(reminder.ID, now); err != nil {
+			return fmt.Errorf("failed to update slot reminder: %w", err)
+		}
+
+		entry := models.NotificationLogEntry{
+			SentAt:    now,
+			Kind:      constants.NotificationKindSlotReminder,
+			PlanDate:  plan.Date,
+			SlotStart: slot.Start,
+			TaskID:    slot.TaskID,
+			Message:   msg,
+		}
+		if err := c.deliver(ctx, n, entry); err != nil {
+			// Log error but continue
+			fmt.Printf("Failed to send slot reminder: %v\n", err)
+		}
 	}
 
 	return nil
 }
 
+// checkMorningPlan runs once a day, after day_start, when today has no
+// accepted plan. Prompt mode sends a reminder; hands-free mode generates and
+// accepts a plan.
+func (c *NotifyCmd) checkMorningPlan(
+	ctx *cli.Context,
+	settings models.Settings,
+	now time.Time,
+	n *notifier.Notifier,
+) error {
+	dateStr := now.Format(constants.DateFormat)
+	if settings.MorningPlanNotifiedOn == dateStr || !autoplan.Due(ctx.Store, settings, now) {
+		return nil
+	}
+
+	// Record the check BEFORE acting to avoid repeats if acting succeeds but the update fails
+	settings.MorningPlanNotifiedOn = dateStr
+	if err := ctx.Store.SaveSettings(settings); err != nil {
+		return fmt.Errorf("failed to update morning plan check: %w", err)
+	}
+
+	var msg string
+	if settings.MorningPlan == constants.MorningPlanHandsFree {
+		plan, err := autoplan.Generate(ctx.Store, ctx.Scheduler, settings, dateStr, true, now)
+		if err != nil {
+			msg = i18n.T("notify.plan_failed", err)
+		} else {
+			msg = i18n.T("notify.plan_ready", len(plan.Slots))
+		}
+	} else {
+		msg = i18n.T("notify.plan_missing")
+	}
+
+	if !settings.NotificationsEnabled {
+		return nil
+	}
+	entry := models.NotificationLogEntry{
+		SentAt:   now,
+		Kind:     constants.NotificationKindMorningPlan,
+		PlanDate: dateStr,
+		Message:  msg,
+	}
+	if err := c.deliver(ctx, n, entry); err != nil {
+		// Log error but continue
+		fmt.Printf("Failed to send morning plan notification: %v\n", err)
+	}
+
+	return nil
+}
+
+// checkWeeklySummary sends the weekly summary once, at the scheduled weekday
+// and time, as a notification, a Markdown report, or both
+func (c *NotifyCmd) checkWeeklySummary(
+	ctx *cli.Context,
+	settings models.Settings,
+	now time.Time,
+	n *notifier.Notifier,
+) error {
+	if !summary.Due(settings, now) {
+		return nil
+	}
+
+	// Reload the settings, which the morning plan check may have just saved,
+	// and record the summary BEFORE sending it to avoid repeats
+	latest, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+	dateStr := now.Format(constants.DateFormat)
+	latest.WeeklySummarySentOn = dateStr
+	if err := ctx.Store.SaveSettings(latest); err != nil {
+		return fmt.Errorf("failed to update weekly summary check: %w", err)
+	}
+
+	weekly, err := summary.Build(ctx.Store, now, latest.FirstWeekday())
+	if err != nil {
+		fmt.Printf("Failed to build weekly summary: %v\n", err)
+		return nil
+	}
+
+	mode := settings.WeeklySummary
+	if mode == constants.WeeklySummaryReport || mode == constants.WeeklySummaryBoth {
+		if settings.MarkdownExportDir == "" {
+			fmt.Println("Weekly summary report skipped: set --markdown-export-dir to write reports.")
+		} else if c.DryRun {
+			fmt.Printf("[DryRun] Would write weekly summary to %s\n", settings.MarkdownExportDir)
+		} else if _, err := weekly.WriteReport(settings.MarkdownExportDir); err != nil {
+			fmt.Printf("Failed to write weekly summary: %v\n", err)
+		}
+	}
+
+	if (mode != constants.WeeklySummaryNotify && mode != constants.WeeklySummaryBoth) || !settings.NotificationsEnabled {
+		return nil
+	}
+	entry := models.NotificationLogEntry{
+		SentAt:   now,
+		Kind:     constants.NotificationKindWeeklySummary,
+		PlanDate: dateStr,
+		Message:  weekly.Message(),
+	}
+	if err := c.deliver(ctx, n, entry); err != nil {
+		// Log error but continue
+		fmt.Printf("Failed to send weekly summary notification: %v\n", err)
+	}
+	return nil
+}
+
+// checkMetrics records the scheduling metrics of the past days once a day,
+// after the day starts, when metrics are turned on
+func (c *NotifyCmd) checkMetrics(ctx *cli.Context, settings models.Settings, now time.Time) error {
+	if !metrics.Due(settings, now) {
+		return nil
+	}
+	if c.DryRun {
+		fmt.Printf("[DryRun] Would record scheduling metrics for the past %d days\n", metrics.LookbackDays)
+		return nil
+	}
+
+	// Reload the settings, which the earlier checks may have just saved
+	latest, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+	latest.MetricsRecordedOn = now.Format(constants.DateFormat)
+	if err := ctx.Store.SaveSettings(latest); err != nil {
+		return fmt.Errorf("failed to update metrics check: %w", err)
+	}
+
+	if _, err := metrics.Record(ctx.Store, settings, now); err != nil {
+		// Log error but continue
+		fmt.Printf("Failed to record metrics: %v\n", err)
+	}
+	return nil
+}
+
+// checkAndSendStartNotification sends a slot's start notification once it
+// is due, or up to lookaheadMin minutes early. A non-empty customMsg replaces
+// the default text, and style sets the task's urgency and sound.
 func (c *NotifyCmd) checkAndSendStartNotification(
 	ctx *cli.Context,
 	slot *models.Slot,
-	taskName string,
+	taskName, customMsg string,
+	style notifier.Options,
 	startMinutes, currentMinutes int,
 	now time.Time,
-	offsetMin, gracePeriodMin int,
+	offsetMin, gracePeriodMin, lookaheadMin int,
 	planDate string,
 	planRevision int,
 	n *notifier.Notifier,
 ) error {
 	triggerTime := startMinutes - offsetMin
+	// The digest's look ahead checks the slots again; they were explained
+	// the first time
+	explain := c.explain
+	if lookaheadMin > 0 {
+		explain = func(string, ...any) {}
+	}
 
 	// Check if we've already notified
 	if slot.LastNotifiedStart != nil {
 		// Already notified, skip
+		explain("%s %s: start notification already sent (%s)", slot.Start, taskName, *slot.LastNotifiedStart)
 		return nil
 	}
 
 	// Check if current time is past the trigger time
-	if currentMinutes < triggerTime {
+	if currentMinutes < triggerTime-lookaheadMin {
 		// Not time yet
+		explain("%s %s: start notification not due until %s", slot.Start, taskName, clockAt(now, currentMinutes, triggerTime))
 		return nil
 	}
 
@@ -158,34 +584,57 @@ func (c *NotifyCmd) checkAndSendStartNotification(
 
 	// If we're too late (beyond grace period), skip
 	if minutesLate > gracePeriodMin {
+		explain("%s %s: start notification was due at %s, %d min ago, past the %d min grace period",
+			slot.Start, taskName, clockAt(now, currentMinutes, triggerTime), minutesLate, gracePeriodMin)
 		return nil
 	}
 
 	// Build notification message
 	var msg string
-	if minutesLate == 0 {
+	if minutesLate < 0 {
+		// Early, to go out with a digest
+		if untilStart := startMinutes - currentMinutes; untilStart > 0 {
+			msg = i18n.T("notify.upcoming", taskName, untilStart, slot.Start)
+		} else {
+			msg = i18n.T("notify.starting_now", taskName, slot.Start)
+		}
+	} else if minutesLate == 0 {
 		// On time
 		if offsetMin == 0 {
-			msg = fmt.Sprintf("Starting now: %s (%s)", taskName, slot.Start)
+			msg = i18n.T("notify.starting_now", taskName, slot.Start)
 		} else {
-			msg = fmt.Sprintf("Upcoming: %s starts in %d min (%s)", taskName, offsetMin, slot.Start)
+			msg = i18n.T("notify.upcoming", taskName, offsetMin, slot.Start)
 		}
 	} else {
 		// Late notification
 		if offsetMin == 0 {
-			msg = fmt.Sprintf("Started %d min ago: %s (%s)", minutesLate, taskName, slot.Start)
+			msg = i18n.T("notify.started_ago", minutesLate, taskName, slot.Start)
 		} else {
 			// minutesRelativeToStart > 0: minutes after start; < 0: minutes until start
 			minutesRelativeToStart := minutesLate - offsetMin
 			if minutesRelativeToStart > 0 {
-				msg = fmt.Sprintf("Started %d min ago: %s (%s)", minutesRelativeToStart, taskName, slot.Start)
+				msg = i18n.T("notify.started_ago", minutesRelativeToStart, taskName, slot.Start)
 			} else {
 				// Still in the "upcoming" window
 				minutesUntilStart := -minutesRelativeToStart
-				msg = fmt.Sprintf("Upcoming: %s starts in %d min (%s)", taskName, minutesUntilStart, slot.Start)
+				msg = i18n.T("notify.upcoming", taskName, minutesUntilStart, slot.Start)
 			}
 		}
 	}
+	if customMsg != "" {
+		msg = fmt.Sprintf("%s (%s)", customMsg, slot.Start)
+	}
+
+	// Claim the notification first, so a notify run racing this one, such
+	// as the daemon's and cron's, can't send it too
+	key := models.SlotNotificationKey(constants.NotificationKindBlockStart, planDate, slot.Start, slot.TaskID)
+	if claimed, err := ctx.Store.ClaimNotification(key, now); err != nil || !claimed {
+		if err == nil {
+			explain("%s %s: start notification already claimed by another notify run", slot.Start, taskName)
+		}
+		return err
+	}
+	explain("%s %s: start notification due at %s", slot.Start, taskName, clockAt(now, currentMinutes, triggerTime))
 
 	// Update notification timestamp BEFORE sending to avoid duplicates if send succeeds but update fails
 	timestamp := now.Format(time.RFC3339)
@@ -194,13 +643,19 @@ func (c *NotifyCmd) checkAndSendStartNotification(
 	}
 
 	// Send notification
-	if c.DryRun {
-		fmt.Println("[DryRun] " + msg)
-	} else {
-		if err := n.Notify(msg); err != nil {
-			// Log error but continue
-			fmt.Printf("Failed to send notification: %v\n", err)
-		}
+	entry := models.NotificationLogEntry{
+		SentAt:    now,
+		Kind:      constants.NotificationKindBlockStart,
+		PlanDate:  planDate,
+		SlotStart: slot.Start,
+		TaskID:    slot.TaskID,
+		Message:   msg,
+		Urgency:   style.Urgency,
+		Sound:     style.Sound,
+	}
+	if err := c.deliver(ctx, n, entry); err != nil {
+		// Log error but continue
+		fmt.Printf("Failed to send notification: %v\n", err)
 	}
 
 	return nil
@@ -222,12 +677,14 @@ func (c *NotifyCmd) checkAndSendEndNotification(
 	// Check if we've already notified
 	if slot.LastNotifiedEnd != nil {
 		// Already notified, skip
+		c.explain("%s %s: end notification already sent (%s)", slot.Start, taskName, *slot.LastNotifiedEnd)
 		return nil
 	}
 
 	// Check if current time is past the trigger time
 	if currentMinutes < triggerTime {
 		// Not time yet
+		c.explain("%s %s: end notification not due until %s", slot.Start, taskName, clockAt(now, currentMinutes, triggerTime))
 		return nil
 	}
 
@@ -236,6 +693,8 @@ func (c *NotifyCmd) checkAndSendEndNotification(
 
 	// If we're too late (beyond grace period), skip
 	if minutesLate > gracePeriodMin {
+		c.explain("%s %s: end notification was due at %s, %d min ago, past the %d min grace period",
+			slot.Start, taskName, clockAt(now, currentMinutes, triggerTime), minutesLate, gracePeriodMin)
 		return nil
 	}
 
@@ -244,27 +703,38 @@ func (c *NotifyCmd) checkAndSendEndNotification(
 	if minutesLate == 0 {
 		// On time
 		if offsetMin == 0 {
-			msg = fmt.Sprintf("Ending now: %s (%s)", taskName, slot.End)
+			msg = i18n.T("notify.ending_now", taskName, slot.End)
 		} else {
-			msg = fmt.Sprintf("Ending soon: %s ends in %d min (%s)", taskName, offsetMin, slot.End)
+			msg = i18n.T("notify.ending_soon", taskName, offsetMin, slot.End)
 		}
 	} else {
 		// Late notification
 		if offsetMin == 0 {
-			msg = fmt.Sprintf("Ended %d min ago: %s (%s)", minutesLate, taskName, slot.End)
+			msg = i18n.T("notify.ended_ago", minutesLate, taskName, slot.End)
 		} else {
 			// minutesRelativeToEnd > 0: minutes after end; < 0: minutes until end
 			minutesRelativeToEnd := minutesLate - offsetMin
 			if minutesRelativeToEnd > 0 {
-				msg = fmt.Sprintf("Ended %d min ago: %s (%s)", minutesRelativeToEnd, taskName, slot.End)
+				msg = i18n.T("notify.ended_ago", minutesRelativeToEnd, taskName, slot.End)
 			} else {
 				// Still in the "ending soon" window
 				minutesUntilEnd := -minutesRelativeToEnd
-				msg = fmt.Sprintf("Ending soon: %s ends in %d min (%s)", taskName, minutesUntilEnd, slot.End)
+				msg = i18n.T("notify.ending_soon", taskName, minutesUntilEnd, slot.End)
 			}
 		}
 	}
 
+	// Claim the notification first, so a notify run racing this one, such
+	// as the daemon's and cron's, can't send it too
+	key := models.SlotNotificationKey(constants.NotificationKindBlockEnd, planDate, slot.Start, slot.TaskID)
+	if claimed, err := ctx.Store.ClaimNotification(key, now); err != nil || !claimed {
+		if err == nil {
+			c.explain("%s %s: end notification already claimed by another notify run", slot.Start, taskName)
+		}
+		return err
+	}
+	c.explain("%s %s: end notification due at %s", slot.Start, taskName, clockAt(now, currentMinutes, triggerTime))
+
 	// Update notification timestamp BEFORE sending to avoid duplicates if send succeeds but update fails
 	timestamp := now.Format(time.RFC3339)
 	if err := ctx.Store.UpdateSlotNotificationTimestamp(planDate, planRevision, slot.Start, slot.TaskID, "end", timestamp); err != nil {
@@ -272,13 +742,17 @@ func (c *NotifyCmd) checkAndSendEndNotification(
 	}
 
 	// Send notification
-	if c.DryRun {
-		fmt.Println("[DryRun] " + msg)
-	} else {
-		if err := n.Notify(msg); err != nil {
-			// Log error but continue
-			fmt.Printf("Failed to send notification: %v\n", err)
-		}
+	entry := models.NotificationLogEntry{
+		SentAt:    now,
+		Kind:      constants.NotificationKindBlockEnd,
+		PlanDate:  planDate,
+		SlotStart: slot.Start,
+		TaskID:    slot.TaskID,
+		Message:   msg,
+	}
+	if err := c.deliver(ctx, n, entry); err != nil {
+		// Log error but continue
+		fmt.Printf("Failed to send notification: %v\n", err)
 	}
 
 	return nil
@@ -298,54 +772,59 @@ func (c *NotifyCmd) checkAndSendAlerts(
 	dateStr := now.Format("2006-01-02")
 	currentMinutes := now.Hour()*60 + now.Minute()
 
+	if vacations, err := ctx.Store.GetVacations(dateStr, dateStr); err == nil && len(vacations) > 0 {
+		if c.DryRun {
+			fmt.Println("Notifications are muted while on vacation.")
+		}
+		return nil
+	}
+
+	// Get grace period from settings
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+
 	for _, alert := range alerts {
 		// Skip inactive alerts
 		if !alert.Active {
+			c.explain("Alert %q: inactive", alert.Message)
 			continue
 		}
 
-		// Check if alert is due today
-		if !alert.IsDueToday(now) {
-			continue
-		}
-
-		// Parse alert time
-		alertMinutes, err := utils.ParseTimeToMinutes(alert.Time)
-		if err != nil {
-			continue
-		}
+		// Build notification message
+		msg := fmt.Sprintf("⏰ %s", alert.Message)
 
-		// Check if we've already sent this alert today
-		if alert.LastSent != nil {
-			lastSentDate := alert.LastSent.Format("2006-01-02")
-			if lastSentDate == dateStr {
-				// Already sent today
+		// Leave-by alerts follow their appointment, and cron alerts may run
+		// several times a day; both are due whenever a time in the grace
+		// period hasn't been sent yet
+		var dueAt time.Time
+		var due bool
+		if alert.IsLeaveBy() {
+			var leaveMsg string
+			leaveMsg, dueAt, due = c.leaveByDueNow(ctx, alert, now, settings.NotificationGracePeriodMin)
+			if !due {
 				continue
 			}
-		}
-
-		// Check if current time is at or past the alert time
-		if currentMinutes < alertMinutes {
-			// Not time yet
+			msg = leaveMsg
+		} else if alert.IsCron() {
+			if dueAt, due = alert.CronRun(now, settings.NotificationGracePeriodMin); !due {
+				c.explain("Alert %q: no run of %q within the last %d min that wasn't sent", alert.Message, alert.Cron, settings.NotificationGracePeriodMin)
+				continue
+			}
+		} else if dueAt, due = c.alertDueNow(alert, now, dateStr, currentMinutes, settings.NotificationGracePeriodMin); !due {
 			continue
 		}
 
-		// Calculate how late we are
-		minutesLate := currentMinutes - alertMinutes
-
-		// Get grace period from settings
-		settings, err := ctx.Store.GetSettings()
-		if err != nil {
-			return fmt.Errorf("failed to get settings: %w", err)
-		}
-
-		// If we're too late (beyond grace period), skip
-		if minutesLate > settings.NotificationGracePeriodMin {
+		// Claim the notification first, so a notify run racing this one
+		// can't send it too
+		if claimed, err := ctx.Store.ClaimNotification(models.AlertNotificationKey(alert.ID, dueAt), now); err != nil {
+			return err
+		} else if !claimed {
+			c.explain("Alert %q: already claimed by another notify run", alert.Message)
 			continue
 		}
-
-		// Build notification message
-		msg := fmt.Sprintf("⏰ %s", alert.Message)
+		c.explain("Alert %q: due at %s", alert.Message, dueAt.Format(constants.TimeFormat))
 
 		// Update last_sent timestamp BEFORE sending to avoid duplicates
 		nowTime := now
@@ -355,13 +834,17 @@ func (c *NotifyCmd) checkAndSendAlerts(
 		}
 
 		// Send notification
-		if c.DryRun {
-			fmt.Println("[DryRun] " + msg)
-		} else {
-			if err := n.Notify(msg); err != nil {
-				// Log error but continue
-				fmt.Printf("Failed to send alert notification: %v\n", err)
-			}
+		entry := models.NotificationLogEntry{
+			SentAt:  now,
+			Kind:    constants.NotificationKindAlert,
+			AlertID: alert.ID,
+			Message: msg,
+			Urgency: alert.Urgency,
+			Sound:   alert.Sound,
+		}
+		if err := c.deliver(ctx, n, entry); err != nil {
+			// Log error but continue
+			fmt.Printf("Failed to send alert notification: %v\n", err)
 		}
 
 		// If this is a one-time alert and it was sent, deactivate it
@@ -376,3 +859,312 @@ func (c *NotifyCmd) checkAndSendAlerts(
 
 	return nil
 }
+
+// checkHabitReminders nags about habits that are still unmarked today once
+// their reminder time has passed, within the grace period and once a day
+func (c *NotifyCmd) checkHabitReminders(
+	ctx *cli.Context,
+	now time.Time,
+	currentMinutes, grace int,
+	n *notifier.Notifier,
+) error {
+	habits, err := ctx.Store.GetAllHabits(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to get habits: %w", err)
+	}
+
+	dateStr := now.Format(constants.DateFormat)
+	var marked map[string]bool
+	for _, habit := range habits {
+		if !habit.RemindsOn(now.Weekday()) || habit.PausedOn(dateStr) {
+			continue
+		}
+		if habit.LastReminded != nil && habit.LastReminded.Format(constants.DateFormat) == dateStr {
+			continue
+		}
+		remindAt, err := utils.ParseTimeToMinutes(habit.ReminderTime)
+		if err != nil {
+			continue
+		}
+		if minutesLate := currentMinutes - remindAt; minutesLate < 0 || minutesLate > grace {
+			continue
+		}
+
+		// Only look up today's entries once a reminder is due
+		if marked == nil {
+			entries, err := ctx.Store.GetHabitEntriesForDay(dateStr)
+			if err != nil {
+				return fmt.Errorf("failed to get habit entries: %w", err)
+			}
+			marked = make(map[string]bool, len(entries))
+			for _, e := range entries {
+				marked[e.HabitID] = true
+			}
+		}
+		if marked[habit.ID] {
+			continue
+		}
+
+		// Update last_reminded BEFORE sending to avoid duplicates
+		nowTime := now
+		habit.LastReminded = &nowTime
+		if err := ctx.Store.UpdateHabit(habit); err != nil {
+			return fmt.Errorf("failed to update habit: %w", err)
+		}
+
+		entry := models.NotificationLogEntry{
+			SentAt:  now,
+			Kind:    constants.NotificationKindHabitReminder,
+			Message: i18n.T("notify.habit", habit.Name),
+		}
+		if err := c.deliver(ctx, n, entry); err != nil {
+			// Log error but continue
+			fmt.Printf("Failed to send habit reminder: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// alertDueNow reports whether an alert with a time of day should be sent now,
+// and the time today it is due at: it is due today, its time has passed by no
+// more than grace minutes and it hasn't been sent today
+func (c *NotifyCmd) alertDueNow(alert models.Alert, now time.Time, dateStr string, currentMinutes, grace int) (time.Time, bool) {
+	// Check if alert is due today
+	if !alert.IsDueToday(now) {
+		c.explain("Alert %q: not due today (%s)", alert.Message, alert.Recurrence.Type)
+		return time.Time{}, false
+	}
+
+	// Parse alert time
+	alertMinutes, err := utils.ParseTimeToMinutes(alert.Time)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	// Check if we've already sent this alert today
+	if alert.LastSent != nil && alert.LastSent.Format("2006-01-02") == dateStr {
+		c.explain("Alert %q: already sent today at %s", alert.Message, alert.LastSent.Local().Format(constants.TimeFormat))
+		return time.Time{}, false
+	}
+
+	// Check if current time is at or past the alert time, and we're not
+	// too late (beyond grace period)
+	minutesLate := currentMinutes - alertMinutes
+	dueAt := time.Date(now.Year(), now.Month(), now.Day(), alertMinutes/60, alertMinutes%60, 0, 0, now.Location())
+	switch {
+	case minutesLate < 0:
+		c.explain("Alert %q: not due until %s", alert.Message, alert.Time)
+	case minutesLate > grace:
+		c.explain("Alert %q: was due at %s, %d min ago, past the %d min grace period", alert.Message, alert.Time, minutesLate, grace)
+	}
+	return dueAt, minutesLate >= 0 && minutesLate <= grace
+}
+
+// leaveByDueNow reports whether a leave-by alert should be sent now, the
+// message to send and the leave-by time. It is due on days its appointment takes place, once its
+// leave-by time has passed by no more than grace minutes. The time follows the
+// appointment's current fixed start, so an appointment moved later today
+// gets a fresh alert.
+func (c *NotifyCmd) leaveByDueNow(ctx *cli.Context, alert models.Alert, now time.Time, grace int) (string, time.Time, bool) {
+	task, err := ctx.Store.GetTask(alert.TaskID)
+	if err != nil || task.DeletedAt != nil || !task.Active {
+		return "", time.Time{}, false
+	}
+
+	dateStr := now.Format(constants.DateFormat)
+	today := utils.ShouldScheduleTask(task, now)
+	if !today {
+		if plan, err := ctx.Store.GetPlan(dateStr); err == nil {
+			for _, slot := range plan.Slots {
+				if slot.TaskID == task.ID {
+					today = true
+					break
+				}
+			}
+		}
+	}
+	if !today {
+		c.explain("Alert %q: %s isn't on today", alert.Message, task.Name)
+		return "", time.Time{}, false
+	}
+
+	leave, err := alert.LeaveBy(task)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	leaveAt := time.Date(now.Year(), now.Month(), now.Day(), leave/60, leave%60, 0, 0, now.Location())
+	late := now.Sub(leaveAt)
+	if late < 0 || late > time.Duration(grace)*time.Minute {
+		c.explain("Alert %q: leave by %s for %s, outside the %d min grace period", alert.Message, leaveAt.Format(constants.TimeFormat), task.Name, grace)
+		return "", time.Time{}, false
+	}
+	if alert.LastSent != nil && !alert.LastSent.Before(leaveAt) {
+		c.explain("Alert %q: already sent for leaving at %s", alert.Message, leaveAt.Format(constants.TimeFormat))
+		return "", time.Time{}, false
+	}
+
+	return i18n.T("notify.leave_by", alert.Message, leaveAt.Format(constants.TimeFormat), task.Name, task.FixedStart), leaveAt, true
+}
+
+// deliver sends a notification, or prints it in dry-run mode, and records the
+// attempt in the notification log. It returns the delivery error, if any.
+// While batching, the notification is held for the digest instead.
+func (c *NotifyCmd) deliver(ctx *cli.Context, n *notifier.Notifier, entry models.NotificationLogEntry) error {
+	if c.batching {
+		c.pending = append(c.pending, entry)
+		return nil
+	}
+
+	channel, sendErr := c.send(n, entry.Message, notifier.Options{Urgency: entry.Urgency, Sound: entry.Sound})
+	c.record(ctx, entry, channel, sendErr)
+	return sendErr
+}
+
+// flushDigest sends the notifications held while batching. A lone
+// notification is sent as usual; several are summarized in one digest. Each
+// is still recorded in the notification log. The digest takes the urgency
+// and sound of its most urgent notification.
+func (c *NotifyCmd) flushDigest(ctx *cli.Context, n *notifier.Notifier) {
+	pending := c.pending
+	c.batching, c.pending = false, nil
+
+	if len(pending) == 1 {
+		if err := c.deliver(ctx, n, pending[0]); err != nil {
+			fmt.Printf("Failed to send notification: %v\n", err)
+		}
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	lines := make([]string, len(pending))
+	var style notifier.Options
+	for i, entry := range pending {
+		lines[i] = "• " + entry.Message
+		if i == 0 || urgencyRank(entry.Urgency) > urgencyRank(style.Urgency) {
+			style = notifier.Options{Urgency: entry.Urgency, Sound: entry.Sound}
+		}
+	}
+	msg := i18n.T("notify.digest", len(pending), strings.Join(lines, "\n"))
+
+	channel, sendErr := c.send(n, msg, style)
+	if sendErr != nil {
+		fmt.Printf("Failed to send notification digest: %v\n", sendErr)
+	}
+	for _, entry := range pending {
+		c.record(ctx, entry, channel, sendErr)
+	}
+}
+
+// send shows msg, or prints it in dry-run mode, and returns the channel used
+func (c *NotifyCmd) send(n *notifier.Notifier, msg string, style notifier.Options) (string, error) {
+	if c.DryRun {
+		prefix := "[DryRun] "
+		if c.simulating {
+			prefix = "  → would send: "
+		}
+		if style.Urgency != "" && style.Urgency != constants.NotificationUrgencyNormal {
+			prefix += "[" + style.Urgency + "] "
+		}
+		fmt.Println(prefix + msg)
+		return constants.NotificationChannelDryRun, nil
+	}
+	if c.Publish != nil && c.Publish(msg, style) {
+		return constants.NotificationChannelSocket, nil
+	}
+	return constants.NotificationChannelTray, n.Notify(msg, style)
+}
+
+// urgencyRank orders notification urgencies from low to critical
+func urgencyRank(urgency string) int {
+	switch urgency {
+	case constants.NotificationUrgencyLow:
+		return 0
+	case constants.NotificationUrgencyCritical:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// retryOutbox resends the queued notifications whose next attempt is due,
+// oldest first. It stops at the first one that fails again, as the rest
+// would most likely fail the same way. A notification is given up on once
+// it has failed constants.NotifyOutboxMaxAttempts times, or unsent when it
+// is older than constants.NotifyOutboxMaxAge, as it would only be noise by
+// then.
+func (c *NotifyCmd) retryOutbox(ctx *cli.Context, n *notifier.Notifier, now time.Time) {
+	items, err := ctx.Store.GetDueOutboxItems(now, constants.NotifyOutboxBatch)
+	if err != nil {
+		fmt.Printf("Failed to get queued notifications: %v\n", err)
+		return
+	}
+
+	for _, item := range items {
+		if now.Sub(item.CreatedAt) > constants.NotifyOutboxMaxAge {
+			item.Status = models.OutboxFailed
+			item.LastError = fmt.Sprintf("expired after %d attempts: %s", item.Attempts, item.LastError)
+			c.updateOutboxItem(ctx, item)
+			continue
+		}
+
+		entry := item.Entry(now)
+		channel, sendErr := c.send(n, item.Message, notifier.Options{Urgency: item.Urgency, Sound: item.Sound})
+		if sendErr == nil {
+			c.logAttempt(ctx, entry, channel, nil)
+			if err := ctx.Store.DeleteOutboxItem(item.ID); err != nil {
+				fmt.Printf("Failed to remove sent notification from the outbox: %v\n", err)
+			}
+			continue
+		}
+
+		item.Attempts++
+		item.LastError = sendErr.Error()
+		if item.Attempts >= constants.NotifyOutboxMaxAttempts {
+			item.Status = models.OutboxFailed
+			sendErr = fmt.Errorf("gave up after %d attempts: %w", item.Attempts, sendErr)
+		} else {
+			item.NextAttemptAt = now.Add(item.Backoff())
+		}
+		c.logAttempt(ctx, entry, channel, sendErr)
+		c.updateOutboxItem(ctx, item)
+		return
+	}
+}
+
+func (c *NotifyCmd) updateOutboxItem(ctx *cli.Context, item models.OutboxItem) {
+	if err := ctx.Store.UpdateOutboxItem(item); err != nil {
+		fmt.Printf("Failed to update queued notification: %v\n", err)
+	}
+}
+
+// record logs a delivery attempt with logAttempt and queues a notification
+// that couldn't be delivered in the outbox, to be retried on later runs
+func (c *NotifyCmd) record(ctx *cli.Context, entry models.NotificationLogEntry, channel string, sendErr error) {
+	c.logAttempt(ctx, entry, channel, sendErr)
+	if sendErr == nil || c.DryRun {
+		return
+	}
+	if err := ctx.Store.AddOutboxItem(models.NewOutboxItem(entry, sendErr)); err != nil {
+		// Like logging, queueing is best-effort and should not fail the run
+		fmt.Printf("Failed to queue notification for retry: %v\n", err)
+	}
+}
+
+// logAttempt adds a delivery attempt to the notification log and reports it
+// to OnDeliver
+func (c *NotifyCmd) logAttempt(ctx *cli.Context, entry models.NotificationLogEntry, channel string, sendErr error) {
+	entry.Channel = channel
+	entry.Success = sendErr == nil
+	if sendErr != nil {
+		entry.Error = sendErr.Error()
+	}
+	if err := ctx.Store.AddNotificationLog(entry); err != nil {
+		// Logging is best-effort and should not fail the run
+		fmt.Printf("Failed to record notification: %v\n", err)
+	}
+	if c.OnDeliver != nil {
+		c.OnDeliver(entry)
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/notifier"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
 )
 
@@ -30,6 +34,9 @@ func setupTestStore(t *testing.T) (*sqlite.Store, func()) {
 	return store, cleanup
 }
 
+// notifyTestNow is midday, so slots around it never cross midnight
+var notifyTestNow = time.Date(2025, 3, 10, 12, 0, 0, 0, time.Local)
+
 // Helper function to calculate end time correctly handling hour overflow
 func calculateEndTime(startMinutes, durationMin int) string {
 	endMinutes := startMinutes + durationMin
@@ -59,7 +66,7 @@ func TestNotifyCmd_Idempotency(t *testing.T) {
 	}
 
 	// Create a plan with a slot that should trigger notification
-	now := time.Now()
+	now := notifyTestNow
 	currentMinutes := now.Hour()*60 + now.Minute()
 
 	// We want a slot that triggers a notification.
@@ -70,12 +77,6 @@ func TestNotifyCmd_Idempotency(t *testing.T) {
 	// 2 <= 10 (grace period), so it should trigger.
 	startMinutes := currentMinutes + 3
 
-	// Skip if near end of day to avoid crossing midnight (which would make startTime invalid for today)
-	// We also need endTime (start + 30) to be valid.
-	if startMinutes+30 >= 24*60 {
-		t.Skip("Skipping test near end of day")
-	}
-
 	startHour := startMinutes / 60
 	startMin := startMinutes % 60
 	startTime := fmt.Sprintf("%02d:%02d", startHour, startMin)
@@ -103,6 +104,7 @@ func TestNotifyCmd_Idempotency(t *testing.T) {
 	// Create context
 	ctx := &cli.Context{
 		Store: store,
+		Clock: clock.Fixed(now),
 	}
 
 	// Run notify command first time
@@ -170,6 +172,127 @@ func TestNotifyCmd_Idempotency(t *testing.T) {
 	}
 }
 
+func TestNotifyCmd_IdempotencyKeys(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	task := createTestTask("task-key", "Write")
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	alert := models.Alert{
+		ID:         "alert-key",
+		Message:    "Stretch",
+		Time:       "11:58",
+		Recurrence: models.Recurrence{Type: constants.RecurrenceDaily},
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+	if err := store.AddAlert(alert); err != nil {
+		t.Fatalf("failed to add alert: %v", err)
+	}
+
+	now := notifyTestNow
+	nowStr := time.Now().UTC().Format(time.RFC3339)
+	plan := models.DayPlan{
+		Date:       now.Format(constants.DateFormat),
+		AcceptedAt: &nowStr,
+		Slots: []models.Slot{
+			{Start: "12:03", End: "12:33", TaskID: task.ID, Status: constants.SlotStatusAccepted},
+		},
+	}
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	ctx := &cli.Context{Store: store, Clock: clock.Fixed(now)}
+	cmd := &NotifyCmd{DryRun: true}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("first notify run failed: %v", err)
+	}
+
+	// A second run that read the plan and alert before the first one marked
+	// them sent still finds the notifications claimed
+	plan.Revision = 1
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+	alert.LastSent = nil
+	if err := store.UpdateAlert(alert); err != nil {
+		t.Fatalf("failed to reset alert: %v", err)
+	}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("second notify run failed: %v", err)
+	}
+
+	entries, err := store.GetNotificationLog(time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("failed to get notification log: %v", err)
+	}
+	kinds := map[string]int{}
+	for _, e := range entries {
+		kinds[e.Kind]++
+	}
+	if kinds[constants.NotificationKindBlockStart] != 1 || kinds[constants.NotificationKindAlert] != 1 {
+		t.Errorf("notifications sent = %v, want the block start and the alert once each", kinds)
+	}
+}
+
+func TestNotifyCmd_SimulateAt(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	alert := models.Alert{
+		ID:         "alert-simulate",
+		Message:    "Stretch",
+		Time:       "10:00",
+		Recurrence: models.Recurrence{Type: constants.RecurrenceDaily},
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+	if err := store.AddAlert(alert); err != nil {
+		t.Fatalf("failed to add alert: %v", err)
+	}
+	// Alerts are only checked on days with a plan
+	task := createTestTask("task-simulate", "Write")
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	nowStr := time.Now().UTC().Format(time.RFC3339)
+	if err := store.SavePlan(models.DayPlan{Date: "2026-01-05", AcceptedAt: &nowStr, Slots: []models.Slot{
+		{Start: "14:00", End: "15:00", TaskID: task.ID, Status: constants.SlotStatusAccepted},
+	}}); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	ctx := &cli.Context{Store: store, Clock: clock.Fixed(notifyTestNow)}
+	if err := (&NotifyCmd{SimulateAt: "2026-01-05T10:02"}).Run(ctx); err != nil {
+		t.Fatalf("simulation failed: %v", err)
+	}
+	if ctx.Now() != notifyTestNow {
+		t.Error("the simulation's clock was left in place")
+	}
+	if entries, err := store.GetNotificationLog(time.Time{}, 0); err != nil || len(entries) != 0 {
+		t.Errorf("log after a simulation = %+v, %v, want it empty", entries, err)
+	}
+	if saved, err := store.GetAlert(alert.ID); err != nil || saved.LastSent != nil {
+		t.Errorf("alert after a simulation = %+v, %v, want it unsent", saved, err)
+	}
+
+	// Nothing the simulation claimed keeps the real run from sending
+	ctx.Clock = clock.Fixed(time.Date(2026, 1, 5, 10, 2, 0, 0, time.Local))
+	if err := (&NotifyCmd{DryRun: true}).Run(ctx); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+	if entries, err := store.GetNotificationLog(time.Time{}, 0); err != nil || len(entries) != 1 || entries[0].AlertID != alert.ID {
+		t.Errorf("log = %+v, %v, want the alert", entries, err)
+	}
+
+	if err := (&NotifyCmd{SimulateAt: "half past ten"}).Run(ctx); err == nil {
+		t.Error("simulating at a malformed time succeeded")
+	}
+}
+
 func TestNotifyCmd_GracePeriod(t *testing.T) {
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
@@ -190,14 +313,9 @@ func TestNotifyCmd_GracePeriod(t *testing.T) {
 		t.Fatalf("failed to add task: %v", err)
 	}
 
-	now := time.Now()
+	now := notifyTestNow
 	currentMinutes := now.Hour()*60 + now.Minute()
 
-	// Skip test if running near midnight to avoid crossing day boundary with invalid times (e.g. 24:xx)
-	if currentMinutes >= 24*60-35 {
-		t.Skip("Skipping test near end of day to avoid invalid time generation")
-	}
-
 	// Test 1: Notification within grace period (5 minutes late)
 	t.Run("WithinGracePeriod", func(t *testing.T) {
 		// Set start time to now. With 5 min offset, notification should have happened 5 mins ago.
@@ -227,7 +345,7 @@ func TestNotifyCmd_GracePeriod(t *testing.T) {
 			t.Fatalf("failed to save plan: %v", err)
 		}
 
-		ctx := &cli.Context{Store: store}
+		ctx := &cli.Context{Store: store, Clock: clock.Fixed(now)}
 		cmd := &NotifyCmd{DryRun: true}
 
 		if err := cmd.Run(ctx); err != nil {
@@ -277,7 +395,7 @@ func TestNotifyCmd_GracePeriod(t *testing.T) {
 			t.Fatalf("failed to save plan: %v", err)
 		}
 
-		ctx := &cli.Context{Store: store}
+		ctx := &cli.Context{Store: store, Clock: clock.Fixed(now)}
 		cmd := &NotifyCmd{DryRun: true}
 
 		if err := cmd.Run(ctx); err != nil {
@@ -319,14 +437,9 @@ func TestNotifyCmd_NoNotificationBeforeTime(t *testing.T) {
 		t.Fatalf("failed to add task: %v", err)
 	}
 
-	now := time.Now()
+	now := notifyTestNow
 	currentMinutes := now.Hour()*60 + now.Minute()
 
-	// Skip test if running near midnight to avoid crossing day boundary with invalid times
-	if currentMinutes >= 24*60-40 {
-		t.Skip("Skipping test near end of day to avoid invalid time generation")
-	}
-
 	// Create a slot that should trigger 10 minutes from now
 	triggerMinutes := currentMinutes + 10
 	startHour := triggerMinutes / 60
@@ -353,7 +466,7 @@ func TestNotifyCmd_NoNotificationBeforeTime(t *testing.T) {
 		t.Fatalf("failed to save plan: %v", err)
 	}
 
-	ctx := &cli.Context{Store: store}
+	ctx := &cli.Context{Store: store, Clock: clock.Fixed(now)}
 	cmd := &NotifyCmd{DryRun: true}
 
 	if err := cmd.Run(ctx); err != nil {
@@ -404,14 +517,9 @@ func TestNotifyCmd_DisabledNotifications(t *testing.T) {
 		t.Fatalf("failed to add task: %v", err)
 	}
 
-	now := time.Now()
+	now := notifyTestNow
 	currentMinutes := now.Hour()*60 + now.Minute()
 
-	// Skip test if running near start of day to avoid negative time calculations
-	if currentMinutes < 5 {
-		t.Skip("Skipping test near start of day to avoid invalid time generation")
-	}
-
 	triggerMinutes := currentMinutes - 2
 	startHour := triggerMinutes / 60
 	startMin := triggerMinutes % 60
@@ -437,7 +545,7 @@ func TestNotifyCmd_DisabledNotifications(t *testing.T) {
 		t.Fatalf("failed to save plan: %v", err)
 	}
 
-	ctx := &cli.Context{Store: store}
+	ctx := &cli.Context{Store: store, Clock: clock.Fixed(now)}
 	cmd := &NotifyCmd{DryRun: true}
 
 	if err := cmd.Run(ctx); err != nil {
@@ -608,14 +716,9 @@ func TestNotifyCmd_BothStartAndEndNotifications(t *testing.T) {
 		t.Fatalf("failed to add task: %v", err)
 	}
 
-	now := time.Now()
+	now := notifyTestNow
 	currentMinutes := now.Hour()*60 + now.Minute()
 
-	// Skip test if running near midnight to avoid negative time calculations
-	if currentMinutes < 40 {
-		t.Skip("Skipping test when running too close to midnight (currentMinutes < 40)")
-	}
-
 	// Create a slot where both start and end should have triggered
 	triggerMinutes := currentMinutes - 35 // Started 35 minutes ago
 	startHour := triggerMinutes / 60
@@ -646,7 +749,7 @@ func TestNotifyCmd_BothStartAndEndNotifications(t *testing.T) {
 		t.Fatalf("failed to save plan: %v", err)
 	}
 
-	ctx := &cli.Context{Store: store}
+	ctx := &cli.Context{Store: store, Clock: clock.Fixed(now)}
 	cmd := &NotifyCmd{DryRun: true}
 
 	if err := cmd.Run(ctx); err != nil {
@@ -710,14 +813,9 @@ func TestNotifyCmd_OnlyAcceptedOrDoneSlots(t *testing.T) {
 		t.Fatalf("failed to add task2: %v", err)
 	}
 
-	now := time.Now()
+	now := notifyTestNow
 	currentMinutes := now.Hour()*60 + now.Minute()
 
-	// Skip test if running near midnight to avoid crossing day boundary with invalid times (e.g. 24:xx)
-	if currentMinutes >= 24*60-35 {
-		t.Skip("Skipping test near end of day to avoid invalid time generation")
-	}
-
 	// Set start time to now. With 5 min offset, notification should have happened 5 mins ago.
 	// This is within the 10 min grace period.
 	startMinutes := currentMinutes
@@ -751,7 +849,7 @@ func TestNotifyCmd_OnlyAcceptedOrDoneSlots(t *testing.T) {
 		t.Fatalf("failed to save plan: %v", err)
 	}
 
-	ctx := &cli.Context{Store: store}
+	ctx := &cli.Context{Store: store, Clock: clock.Fixed(now)}
 	cmd := &NotifyCmd{DryRun: true}
 
 	if err := cmd.Run(ctx); err != nil {
@@ -1061,6 +1159,150 @@ func TestNotifyCmd_Alerts_NDaysRecurrence(t *testing.T) {
 	}
 }
 
+func TestNotifyCmd_Alerts_CronRecurrence(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	settings, _ := store.GetSettings()
+	settings.NotificationsEnabled = true
+	settings.NotificationGracePeriodMin = 10
+	store.SaveSettings(settings)
+
+	// Twice a day on weekdays
+	alert := models.Alert{
+		ID:         "alert-cron",
+		Message:    "Stretch",
+		Recurrence: models.Recurrence{Type: constants.RecurrenceCron},
+		Cron:       "0 11,15 * * mon-fri",
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+	if err := store.AddAlert(alert); err != nil {
+		t.Fatalf("AddAlert failed: %v", err)
+	}
+
+	ctx := &cli.Context{Store: store}
+	cmd := &NotifyCmd{DryRun: true}
+
+	sends := func() int {
+		entries, err := store.GetNotificationLog(time.Time{}, 0)
+		if err != nil {
+			t.Fatalf("GetNotificationLog failed: %v", err)
+		}
+		return len(entries)
+	}
+
+	// Monday 2026-01-05: both runs fire once each, even within the grace period
+	for _, at := range []time.Time{
+		time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC),
+		time.Date(2026, 1, 5, 11, 2, 0, 0, time.UTC),
+		time.Date(2026, 1, 5, 11, 5, 0, 0, time.UTC),
+		time.Date(2026, 1, 5, 15, 0, 0, 0, time.UTC),
+	} {
+		if err := cmd.checkAndSendAlerts(ctx, at, nil); err != nil {
+			t.Fatalf("checkAndSendAlerts failed: %v", err)
+		}
+	}
+	if got := sends(); got != 2 {
+		t.Errorf("expected 2 cron alerts on Monday, got %d", got)
+	}
+
+	// Saturday: nothing
+	if err := cmd.checkAndSendAlerts(ctx, time.Date(2026, 1, 10, 11, 0, 0, 0, time.UTC), nil); err != nil {
+		t.Fatalf("checkAndSendAlerts failed: %v", err)
+	}
+	if got := sends(); got != 2 {
+		t.Errorf("expected no cron alert on Saturday, got %d sends in total", got)
+	}
+
+	// The alert stays active
+	updated, _ := store.GetAlert("alert-cron")
+	if !updated.Active || updated.Cron != alert.Cron {
+		t.Errorf("expected the cron alert to be kept, got %+v", updated)
+	}
+}
+
+func TestNotifyCmd_Alerts_LeaveBy(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	settings, _ := store.GetSettings()
+	settings.NotificationsEnabled = true
+	settings.NotificationGracePeriodMin = 10
+	store.SaveSettings(settings)
+
+	task := models.Task{
+		ID:          "dentist",
+		Name:        "Dentist",
+		Kind:        constants.TaskKindAppointment,
+		DurationMin: 60,
+		FixedStart:  "10:00",
+		FixedEnd:    "11:00",
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceWeekly, WeekdayMask: []time.Weekday{time.Monday}},
+		Priority:    1,
+		Active:      true,
+	}
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("AddTask failed: %v", err)
+	}
+	alert := models.Alert{
+		ID:        "alert-leave",
+		Message:   "Time to go",
+		TaskID:    task.ID,
+		TravelMin: 30,
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+	if err := store.AddAlert(alert); err != nil {
+		t.Fatalf("AddAlert failed: %v", err)
+	}
+
+	ctx := &cli.Context{Store: store}
+	cmd := &NotifyCmd{DryRun: true}
+	check := func(at time.Time) []models.NotificationLogEntry {
+		t.Helper()
+		if err := cmd.checkAndSendAlerts(ctx, at, nil); err != nil {
+			t.Fatalf("checkAndSendAlerts failed: %v", err)
+		}
+		entries, err := store.GetNotificationLog(time.Time{}, 0)
+		if err != nil {
+			t.Fatalf("GetNotificationLog failed: %v", err)
+		}
+		return entries
+	}
+
+	// Monday 2026-01-05: nothing at 09:20, the alert at 09:31, once
+	if got := check(time.Date(2026, 1, 5, 9, 20, 0, 0, time.UTC)); len(got) != 0 {
+		t.Fatalf("expected no alert before the leave-by time, got %d", len(got))
+	}
+	check(time.Date(2026, 1, 5, 9, 31, 0, 0, time.UTC))
+	entries := check(time.Date(2026, 1, 5, 9, 33, 0, 0, time.UTC))
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 leave-by alert, got %d", len(entries))
+	}
+	if !strings.Contains(entries[0].Message, "leave by 09:30 for Dentist at 10:00") {
+		t.Errorf("unexpected message: %q", entries[0].Message)
+	}
+
+	// Moving the appointment later in the day brings a new alert at the new time
+	task.FixedStart = "13:00"
+	task.FixedEnd = "14:00"
+	if err := store.UpdateTask(task); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if got := check(time.Date(2026, 1, 5, 12, 25, 0, 0, time.UTC)); len(got) != 1 {
+		t.Fatalf("expected no new alert before 12:30, got %d in total", len(got))
+	}
+	if got := check(time.Date(2026, 1, 5, 12, 30, 0, 0, time.UTC)); len(got) != 2 {
+		t.Fatalf("expected an alert for the moved appointment, got %d in total", len(got))
+	}
+
+	// Tuesday: the appointment isn't on, so neither is the alert
+	if got := check(time.Date(2026, 1, 6, 12, 30, 0, 0, time.UTC)); len(got) != 2 {
+		t.Errorf("expected no alert on a day without the appointment, got %d in total", len(got))
+	}
+}
+
 func TestNotifyCmd_Alerts_InactiveSkipped(t *testing.T) {
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
@@ -1099,3 +1341,824 @@ func TestNotifyCmd_Alerts_InactiveSkipped(t *testing.T) {
 		t.Error("expected inactive alert not to fire")
 	}
 }
+
+func TestNotifyCmd_MorningPlan_HandsFree(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	task := models.Task{
+		ID:          "task-morning-1",
+		Name:        "Morning Review",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 1,
+		Recurrence: models.Recurrence{
+			Type: constants.RecurrenceDaily,
+		},
+		Priority: 1,
+		Active:   true,
+	}
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	settings, err := store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	settings.DayStart = "00:00"
+	settings.DayEnd = "23:59"
+	settings.MorningPlan = constants.MorningPlanHandsFree
+	if err := store.SaveSettings(settings); err != nil {
+		t.Fatalf("failed to save settings: %v", err)
+	}
+
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local)
+	ctx := &cli.Context{
+		Store:     store,
+		Scheduler: scheduler.New(),
+		Clock:     clock.Fixed(now),
+	}
+
+	cmd := &NotifyCmd{DryRun: true}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+
+	today := now.Format(constants.DateFormat)
+	plan, err := store.GetPlan(today)
+	if err != nil {
+		t.Fatalf("expected a plan for today: %v", err)
+	}
+	if want := now.UTC().Format(time.RFC3339); plan.AcceptedAt == nil || *plan.AcceptedAt != want {
+		t.Errorf("hands-free plan accepted at %v, want %s by the clock", plan.AcceptedAt, want)
+	}
+
+	settings, err = store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if settings.MorningPlanNotifiedOn != today {
+		t.Errorf("MorningPlanNotifiedOn = %q, want %q", settings.MorningPlanNotifiedOn, today)
+	}
+
+	// A second run leaves the accepted plan alone
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("second notify run failed: %v", err)
+	}
+	again, err := store.GetPlan(today)
+	if err != nil {
+		t.Fatalf("failed to get plan: %v", err)
+	}
+	if again.Revision != plan.Revision {
+		t.Errorf("second run created revision %d, want %d", again.Revision, plan.Revision)
+	}
+}
+
+func TestNotifyCmd_MorningPlan_PromptDoesNotGenerate(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	settings, err := store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	settings.DayStart = "00:00"
+	settings.MorningPlan = constants.MorningPlanPrompt
+	if err := store.SaveSettings(settings); err != nil {
+		t.Fatalf("failed to save settings: %v", err)
+	}
+
+	ctx := &cli.Context{
+		Store:     store,
+		Scheduler: scheduler.New(),
+	}
+
+	cmd := &NotifyCmd{DryRun: true}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+
+	today := time.Now().Format(constants.DateFormat)
+	if _, err := store.GetPlan(today); err == nil {
+		t.Error("prompt mode should not generate a plan")
+	}
+
+	settings, err = store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if settings.MorningPlanNotifiedOn != today {
+		t.Errorf("MorningPlanNotifiedOn = %q, want %q", settings.MorningPlanNotifiedOn, today)
+	}
+}
+
+func TestNotifyCmd_TaskNotificationPreferences(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	offset := 15
+	lunch := models.Task{
+		ID: "task-lunch", Name: "Lunch", Kind: constants.TaskKindFlexible, DurationMin: 30,
+		Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 1, Active: true,
+		NotifyStartDisabled: true,
+	}
+	train := models.Task{
+		ID: "task-train", Name: "Leave for train", Kind: constants.TaskKindFlexible, DurationMin: 10,
+		Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 1, Active: true,
+		NotifyOffsetMin: &offset, NotifyMessage: "Grab your pass",
+		NotifyUrgency: constants.NotificationUrgencyCritical, NotifySound: "Glass",
+	}
+	for _, task := range []models.Task{lunch, train} {
+		if err := store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+	}
+
+	saved, err := store.GetTask(train.ID)
+	if err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if saved.NotifyOffsetMin == nil || *saved.NotifyOffsetMin != 15 || saved.NotifyMessage != "Grab your pass" ||
+		saved.NotifyUrgency != constants.NotificationUrgencyCritical || saved.NotifySound != "Glass" {
+		t.Fatalf("notification preferences not stored: %+v", saved)
+	}
+
+	now := notifyTestNow
+	currentMinutes := now.Hour()*60 + now.Minute()
+
+	// Lunch starts in 3 minutes and would be due with the default 5 minute
+	// offset; the train starts in 12 minutes and is only due with its own
+	// 15 minute offset
+	lunchStart := currentMinutes + 3
+	trainStart := currentMinutes + 12
+
+	nowStr := time.Now().UTC().Format(time.RFC3339)
+	plan := models.DayPlan{
+		Date:       now.Format("2006-01-02"),
+		Revision:   0,
+		AcceptedAt: &nowStr,
+		Slots: []models.Slot{
+			{
+				Start:  calculateEndTime(lunchStart, 0),
+				End:    calculateEndTime(lunchStart, 30),
+				TaskID: lunch.ID,
+				Status: constants.SlotStatusAccepted,
+			},
+			{
+				Start:  calculateEndTime(trainStart, 0),
+				End:    calculateEndTime(trainStart, 10),
+				TaskID: train.ID,
+				Status: constants.SlotStatusAccepted,
+			},
+		},
+	}
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	var sent []models.NotificationLogEntry
+	ctx := &cli.Context{Store: store, Clock: clock.Fixed(now)}
+	cmd := &NotifyCmd{DryRun: true, OnDeliver: func(e models.NotificationLogEntry) { sent = append(sent, e) }}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("notify run failed: %v", err)
+	}
+	if len(sent) != 1 || sent[0].Urgency != constants.NotificationUrgencyCritical || sent[0].Sound != "Glass" {
+		t.Errorf("expected the train's critical notification with its sound, got %+v", sent)
+	}
+
+	retrievedPlan, err := store.GetPlan(plan.Date)
+	if err != nil {
+		t.Fatalf("failed to retrieve plan: %v", err)
+	}
+
+	for _, slot := range retrievedPlan.Slots {
+		switch slot.TaskID {
+		case lunch.ID:
+			if slot.LastNotifiedStart != nil {
+				t.Error("expected no start notification for a task with start notifications disabled")
+			}
+		case train.ID:
+			if slot.LastNotifiedStart == nil {
+				t.Error("expected start notification using the task's own offset")
+			}
+		}
+	}
+}
+
+func TestNotifyCmd_AppointmentPadding(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	now := notifyTestNow
+	currentMinutes := now.Hour()*60 + now.Minute()
+
+	// The dentist starts in 45 minutes, with 10 minutes to get ready and 30
+	// to get there; with the default 5 minute offset the notification is
+	// due now, 40 minutes earlier than without the padding
+	start := currentMinutes + 45
+	dentist := models.Task{
+		ID: "task-dentist", Name: "Dentist", Kind: constants.TaskKindAppointment, DurationMin: 30,
+		FixedStart: calculateEndTime(start, 0), FixedEnd: calculateEndTime(start, 30),
+		Recurrence: models.Recurrence{Type: constants.RecurrenceAdHoc}, Priority: 1, Active: true,
+		PrepMin: 10, TravelMin: 30,
+	}
+	if err := store.AddTask(dentist); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	saved, err := store.GetTask(dentist.ID)
+	if err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if saved.PrepMin != 10 || saved.TravelMin != 30 {
+		t.Fatalf("prep and travel minutes not stored: %+v", saved)
+	}
+
+	nowStr := time.Now().UTC().Format(time.RFC3339)
+	plan := models.DayPlan{
+		Date:       now.Format("2006-01-02"),
+		AcceptedAt: &nowStr,
+		Slots: []models.Slot{{
+			Start:  dentist.FixedStart,
+			End:    dentist.FixedEnd,
+			TaskID: dentist.ID,
+			Status: constants.SlotStatusAccepted,
+		}},
+	}
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	var sent []models.NotificationLogEntry
+	ctx := &cli.Context{Store: store, Clock: clock.Fixed(now)}
+	cmd := &NotifyCmd{DryRun: true, OnDeliver: func(e models.NotificationLogEntry) { sent = append(sent, e) }}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("notify run failed: %v", err)
+	}
+	want := fmt.Sprintf("Get ready for Dentist, leave at %s (%s)", calculateEndTime(start-30, 0), dentist.FixedStart)
+	if len(sent) != 1 || sent[0].Message != want {
+		t.Errorf("expected %q, got %+v", want, sent)
+	}
+}
+
+func TestNotifyCmd_RecordsNotificationLog(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	alert := models.Alert{
+		ID:         "alert-log",
+		Message:    "Stretch",
+		Time:       "10:00",
+		Recurrence: models.Recurrence{Type: constants.RecurrenceDaily},
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+	if err := store.AddAlert(alert); err != nil {
+		t.Fatalf("failed to add alert: %v", err)
+	}
+
+	ctx := &cli.Context{Store: store}
+	cmd := &NotifyCmd{DryRun: true}
+
+	now := time.Date(2026, 1, 5, 10, 2, 0, 0, time.UTC)
+	if err := cmd.checkAndSendAlerts(ctx, now, nil); err != nil {
+		t.Fatalf("checkAndSendAlerts failed: %v", err)
+	}
+
+	entries, err := store.GetNotificationLog(time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), 0)
+	if err != nil {
+		t.Fatalf("failed to get notification log: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Kind != constants.NotificationKindAlert || e.AlertID != alert.ID {
+		t.Errorf("unexpected entry kind/alert: %+v", e)
+	}
+	if e.Channel != constants.NotificationChannelDryRun || !e.Success {
+		t.Errorf("expected a successful dry-run entry, got %+v", e)
+	}
+	if !e.SentAt.Equal(now) {
+		t.Errorf("SentAt = %v, want %v", e.SentAt, now)
+	}
+
+	// Entries before since are excluded
+	entries, err = store.GetNotificationLog(time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC), 0)
+	if err != nil {
+		t.Fatalf("failed to get notification log: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries after the day, got %d", len(entries))
+	}
+
+	history := &NotifyHistoryCmd{Limit: 10}
+	if err := history.Run(ctx); err != nil {
+		t.Errorf("notify history failed: %v", err)
+	}
+}
+
+func TestNotifyCmd_PublishToSocket(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	alert := models.Alert{
+		ID:         "alert-socket",
+		Message:    "Stretch",
+		Time:       "10:00",
+		Recurrence: models.Recurrence{Type: constants.RecurrenceDaily},
+		Active:     true,
+		CreatedAt:  time.Now(),
+		Urgency:    constants.NotificationUrgencyCritical,
+	}
+	if err := store.AddAlert(alert); err != nil {
+		t.Fatalf("failed to add alert: %v", err)
+	}
+
+	var published []string
+	ctx := &cli.Context{Store: store}
+	cmd := &NotifyCmd{Publish: func(text string, style notifier.Options) bool {
+		published = append(published, style.Urgency+" "+text)
+		return true
+	}}
+
+	now := time.Date(2026, 1, 5, 10, 2, 0, 0, time.UTC)
+	if err := cmd.checkAndSendAlerts(ctx, now, nil); err != nil {
+		t.Fatalf("checkAndSendAlerts failed: %v", err)
+	}
+	if len(published) != 1 || !strings.HasPrefix(published[0], "critical ") || !strings.HasSuffix(published[0], "Stretch") {
+		t.Errorf("published %q, want the critical alert", published)
+	}
+
+	entries, err := store.GetNotificationLog(time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), 0)
+	if err != nil {
+		t.Fatalf("failed to get notification log: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Channel != constants.NotificationChannelSocket || !entries[0].Success {
+		t.Errorf("log = %+v, want one delivery over the socket", entries)
+	}
+}
+
+func TestNotifyCmd_Outbox(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	// Without a running tray, delivery fails
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	alert := models.Alert{
+		ID:         "alert-outbox",
+		Message:    "Stretch",
+		Time:       "10:00",
+		Recurrence: models.Recurrence{Type: constants.RecurrenceDaily},
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+	if err := store.AddAlert(alert); err != nil {
+		t.Fatalf("failed to add alert: %v", err)
+	}
+
+	socketUp := false
+	ctx := &cli.Context{Store: store}
+	cmd := &NotifyCmd{Publish: func(string, notifier.Options) bool { return socketUp }}
+	n := notifier.New()
+
+	now := time.Date(2026, 1, 5, 10, 2, 0, 0, time.UTC)
+	if err := cmd.checkAndSendAlerts(ctx, now, n); err != nil {
+		t.Fatalf("checkAndSendAlerts failed: %v", err)
+	}
+	queued, err := store.GetOutboxItems(time.Time{})
+	if err != nil {
+		t.Fatalf("failed to get outbox: %v", err)
+	}
+	if len(queued) != 1 || queued[0].AlertID != alert.ID || queued[0].Attempts != 1 ||
+		!queued[0].NextAttemptAt.Equal(now.Add(constants.NotifyOutboxBaseDelay)) {
+		t.Fatalf("outbox = %+v, want the failed alert due after the base delay", queued)
+	}
+
+	logged := func() []models.NotificationLogEntry {
+		entries, err := store.GetNotificationLog(time.Time{}, 0)
+		if err != nil {
+			t.Fatalf("failed to get notification log: %v", err)
+		}
+		return entries
+	}
+
+	// Not due yet
+	cmd.retryOutbox(ctx, n, now.Add(30*time.Second))
+	if entries := logged(); len(entries) != 1 {
+		t.Errorf("retried before the item was due: %+v", entries)
+	}
+
+	// Due, but the tray is still down: the wait doubles
+	now = now.Add(constants.NotifyOutboxBaseDelay)
+	cmd.retryOutbox(ctx, n, now)
+	queued, _ = store.GetOutboxItems(time.Time{})
+	if len(queued) != 1 || queued[0].Attempts != 2 || !queued[0].NextAttemptAt.Equal(now.Add(2*constants.NotifyOutboxBaseDelay)) {
+		t.Fatalf("outbox after a failed retry = %+v, want a second attempt and a doubled wait", queued)
+	}
+
+	// Delivered once the daemon's socket is up
+	socketUp = true
+	now = now.Add(2 * constants.NotifyOutboxBaseDelay)
+	cmd.retryOutbox(ctx, n, now)
+	if queued, _ := store.GetOutboxItems(time.Time{}); len(queued) != 0 {
+		t.Errorf("outbox after delivery = %+v, want it empty", queued)
+	}
+	if entries := logged(); len(entries) != 3 || !entries[0].Success || entries[0].Channel != constants.NotificationChannelSocket ||
+		entries[0].AlertID != alert.ID || !entries[0].SentAt.Equal(now) {
+		t.Errorf("log = %+v, want the delivered retry last", entries)
+	}
+
+	// Given up on after too many attempts, and when too old to be of use
+	socketUp = false
+	for _, item := range []models.OutboxItem{
+		{CreatedAt: now, Kind: constants.NotificationKindAlert, Message: "Drink water",
+			Attempts: constants.NotifyOutboxMaxAttempts - 1, NextAttemptAt: now, Status: models.OutboxPending},
+		{CreatedAt: now.Add(-3 * constants.NotifyOutboxMaxAge), Kind: constants.NotificationKindAlert, Message: "Old",
+			Attempts: 1, NextAttemptAt: now, Status: models.OutboxPending},
+	} {
+		if err := store.AddOutboxItem(item); err != nil {
+			t.Fatalf("failed to queue notification: %v", err)
+		}
+	}
+	cmd.retryOutbox(ctx, n, now)
+	queued, _ = store.GetOutboxItems(time.Time{})
+	if len(queued) != 2 || queued[0].Status != models.OutboxFailed || queued[1].Status != models.OutboxFailed {
+		t.Errorf("outbox = %+v, want both given up on", queued)
+	}
+	if entries := logged(); len(entries) != 4 || entries[0].Success || !strings.Contains(entries[0].Error, "gave up after") {
+		t.Errorf("log = %+v, want the last attempt recorded as given up", entries)
+	}
+	if due, _ := store.GetDueOutboxItems(now.Add(time.Hour), 0); len(due) != 0 {
+		t.Errorf("items given up on are still due: %+v", due)
+	}
+
+	history := &NotifyHistoryCmd{Limit: 10}
+	if err := history.Run(ctx); err != nil {
+		t.Errorf("notify history failed: %v", err)
+	}
+}
+
+func TestNotifyCmd_SlotReminders(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	dentist := models.Task{
+		ID: "task-dentist", Name: "Dentist", Kind: constants.TaskKindAppointment,
+		FixedStart: "14:00", FixedEnd: "15:00",
+		Recurrence: models.Recurrence{Type: constants.RecurrenceAdHoc}, Priority: 1, Active: true,
+	}
+	if err := store.AddTask(dentist); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	plan := models.DayPlan{
+		Date: "2026-01-05",
+		Slots: []models.Slot{
+			{Start: "14:00", End: "15:00", TaskID: dentist.ID, Status: constants.SlotStatusAccepted},
+		},
+	}
+	for _, r := range []models.SlotReminder{
+		{ID: "before", PlanDate: plan.Date, SlotStart: "14:00", TaskID: dentist.ID, OffsetMin: 30, CreatedAt: time.Now()},
+		{ID: "at-start", PlanDate: plan.Date, SlotStart: "14:00", TaskID: dentist.ID, Message: "Go in now", CreatedAt: time.Now()},
+	} {
+		if err := store.AddSlotReminder(r); err != nil {
+			t.Fatalf("failed to add reminder: %v", err)
+		}
+	}
+
+	var sent []models.NotificationLogEntry
+	ctx := &cli.Context{Store: store}
+	cmd := &NotifyCmd{DryRun: true, OnDeliver: func(e models.NotificationLogEntry) { sent = append(sent, e) }}
+	at := func(h, m int) time.Time { return time.Date(2026, 1, 5, h, m, 0, 0, time.Local) }
+	check := func(now time.Time) {
+		t.Helper()
+		if err := cmd.checkSlotReminders(ctx, models.DayWindow{}, plan, now.Hour()*60+now.Minute(), now, 10, nil); err != nil {
+			t.Fatalf("checkSlotReminders failed: %v", err)
+		}
+	}
+
+	check(at(13, 20))
+	if len(sent) != 0 {
+		t.Fatalf("expected no reminders 40 min before, got %+v", sent)
+	}
+
+	check(at(13, 31))
+	check(at(13, 35))
+	if len(sent) != 1 || sent[0].Message != "Reminder: Dentist starts in 29 min (14:00)" || sent[0].Kind != constants.NotificationKindSlotReminder {
+		t.Fatalf("expected one reminder 30 min before, got %+v", sent)
+	}
+
+	// The slot moved, so the reminder follows the task's only slot
+	plan.Slots[0].Start, plan.Slots[0].End = "14:30", "15:30"
+	check(at(14, 30))
+	if len(sent) != 2 || sent[1].Message != "Go in now (14:30)" {
+		t.Fatalf("expected the start reminder at the moved slot, got %+v", sent)
+	}
+
+	reminders, err := store.GetSlotReminders(plan.Date, plan.Date)
+	if err != nil {
+		t.Fatalf("failed to get reminders: %v", err)
+	}
+	for _, r := range reminders {
+		if r.SentAt == nil {
+			t.Errorf("reminder %s should be marked sent", r.ID)
+		}
+	}
+}
+
+func TestNotifyCmd_Digest(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	settings, err := store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	settings.NotifyBlockEnd = false
+	settings.NotificationDigestWindowMin = 10
+	if err := store.SaveSettings(settings); err != nil {
+		t.Fatalf("failed to save settings: %v", err)
+	}
+
+	plan := models.DayPlan{Date: notifyTestNow.Format(constants.DateFormat)}
+	for _, s := range []struct{ id, start, end string }{
+		{"a", "11:58", "12:03"},
+		{"b", "12:03", "12:12"},
+		{"c", "12:12", "12:30"},
+		{"d", "12:30", "13:00"},
+	} {
+		task := models.Task{
+			ID: "task-" + s.id, Name: strings.ToUpper(s.id), Kind: constants.TaskKindFlexible, DurationMin: 15,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 1, Active: true,
+		}
+		if err := store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+		plan.Slots = append(plan.Slots, models.Slot{Start: s.start, End: s.end, TaskID: task.ID, Status: constants.SlotStatusAccepted})
+	}
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	var sent []string
+	cmd := &NotifyCmd{DryRun: true, OnDeliver: func(e models.NotificationLogEntry) { sent = append(sent, e.Message) }}
+
+	// A and B were missed while asleep; C starts within the digest window
+	// and joins them, D is too far off
+	ctx := &cli.Context{Store: store, Clock: clock.Fixed(notifyTestNow)}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+	want := []string{
+		"Started 2 min ago: A (11:58)",
+		"Upcoming: B starts in 3 min (12:03)",
+		"Upcoming: C starts in 12 min (12:12)",
+	}
+	if strings.Join(sent, "|") != strings.Join(want, "|") {
+		t.Fatalf("digest = %q, want %q", sent, want)
+	}
+
+	entries, err := store.GetNotificationLog(notifyTestNow.Add(-time.Hour), 0)
+	if err != nil {
+		t.Fatalf("failed to get notification log: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("expected each digest entry in the log, got %d entries", len(entries))
+	}
+
+	// Nothing else is due before D, which then goes out on its own
+	sent = nil
+	ctx.Clock = clock.Fixed(notifyTestNow.Add(10 * time.Minute))
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+	if len(sent) != 0 {
+		t.Fatalf("expected no notifications at 12:10, got %q", sent)
+	}
+	ctx.Clock = clock.Fixed(notifyTestNow.Add(25 * time.Minute))
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+	if len(sent) != 1 || sent[0] != "Upcoming: D starts in 5 min (12:30)" {
+		t.Fatalf("expected D on its own, got %q", sent)
+	}
+}
+
+func TestNotifyCmd_YesterdayEndsWithinGrace(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	// The day ends before midnight, but blocks may still end late
+	settings, err := store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	settings.DayEnd = "23:59"
+	settings.NotifyBlockStart = false
+	if err := store.SaveSettings(settings); err != nil {
+		t.Fatalf("failed to save settings: %v", err)
+	}
+
+	plan := models.DayPlan{Date: "2025-03-09"}
+	for _, s := range []struct{ id, start, end string }{
+		{"review", "21:00", "22:00"},
+		{"read", "23:00", "23:58"},
+		{"film", "23:45", "00:20"},
+	} {
+		task := models.Task{
+			ID: "task-" + s.id, Name: s.id, Kind: constants.TaskKindFlexible, DurationMin: 30,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 1, Active: true,
+		}
+		if err := store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+		plan.Slots = append(plan.Slots, models.Slot{Start: s.start, End: s.end, TaskID: task.ID, Status: constants.SlotStatusAccepted})
+	}
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	var sent []string
+	cmd := &NotifyCmd{DryRun: true, OnDeliver: func(e models.NotificationLogEntry) { sent = append(sent, e.Message) }}
+	at := func(h, m int) time.Time { return time.Date(2025, 3, 10, h, m, 0, 0, time.Local) }
+
+	for _, now := range []time.Time{at(0, 1), at(0, 16), at(0, 40)} {
+		ctx := &cli.Context{Store: store, Clock: clock.Fixed(now)}
+		if err := cmd.Run(ctx); err != nil {
+			t.Fatalf("notify failed: %v", err)
+		}
+	}
+
+	// Review ended too long ago to be notified
+	want := []string{
+		"Ended 3 min ago: read (23:58)",
+		"Ending soon: film ends in 4 min (00:20)",
+	}
+	if strings.Join(sent, "|") != strings.Join(want, "|") {
+		t.Errorf("sent %q, want %q", sent, want)
+	}
+}
+
+func TestNotifyCmd_Alerts_Urgency(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	alert := models.Alert{
+		ID:         "alert-meds",
+		Message:    "Take medication",
+		Time:       "09:00",
+		Recurrence: models.Recurrence{Type: constants.RecurrenceDaily},
+		Urgency:    constants.NotificationUrgencyCritical,
+		Sound:      "Glass",
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+	if err := store.AddAlert(alert); err != nil {
+		t.Fatalf("failed to add alert: %v", err)
+	}
+	saved, err := store.GetAlert(alert.ID)
+	if err != nil {
+		t.Fatalf("failed to get alert: %v", err)
+	}
+	if saved.Urgency != alert.Urgency || saved.Sound != alert.Sound {
+		t.Fatalf("urgency and sound not stored: %+v", saved)
+	}
+
+	if err := store.AddAlert(models.Alert{
+		ID: "alert-bad", Message: "Bad", Time: "09:00", Recurrence: models.Recurrence{Type: constants.RecurrenceDaily},
+		Urgency: "loud", Active: true, CreatedAt: time.Now(),
+	}); err == nil {
+		t.Error("expected an unknown urgency to be refused")
+	}
+
+	var sent []models.NotificationLogEntry
+	ctx := &cli.Context{Store: store}
+	cmd := &NotifyCmd{DryRun: true, OnDeliver: func(e models.NotificationLogEntry) { sent = append(sent, e) }}
+	if err := cmd.checkAndSendAlerts(ctx, time.Date(2026, 1, 5, 9, 1, 0, 0, time.Local), nil); err != nil {
+		t.Fatalf("checkAndSendAlerts failed: %v", err)
+	}
+	if len(sent) != 1 || sent[0].Urgency != constants.NotificationUrgencyCritical || sent[0].Sound != "Glass" {
+		t.Errorf("expected a critical alert with its sound, got %+v", sent)
+	}
+}
+
+func TestNotifyCmd_WeeklySummary(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	settings, err := store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	// notifyTestNow is a Monday at noon; the morning plan check saves the
+	// settings in the same run
+	settings.MorningPlan = constants.MorningPlanPrompt
+	settings.WeeklySummary = constants.WeeklySummaryNotify
+	settings.WeeklySummaryAt = "mon 11:30"
+	if err := store.SaveSettings(settings); err != nil {
+		t.Fatalf("failed to save settings: %v", err)
+	}
+	if err := store.AddHabit(models.Habit{ID: "habit-read", Name: "Read", CreatedAt: notifyTestNow.AddDate(0, 0, -30)}); err != nil {
+		t.Fatalf("failed to add habit: %v", err)
+	}
+
+	var sent []models.NotificationLogEntry
+	cmd := &NotifyCmd{DryRun: true, OnDeliver: func(e models.NotificationLogEntry) { sent = append(sent, e) }}
+	ctx := &cli.Context{Store: store, Clock: clock.Fixed(notifyTestNow)}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+
+	var summaries []string
+	for _, e := range sent {
+		if e.Kind == constants.NotificationKindWeeklySummary {
+			summaries = append(summaries, e.Message)
+		}
+	}
+	want := "Weekly summary: habits 0/7; tomorrow: no plan yet"
+	if len(summaries) != 1 || summaries[0] != want {
+		t.Fatalf("weekly summaries = %q, want [%q]", summaries, want)
+	}
+
+	settings, err = store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if settings.WeeklySummarySentOn != notifyTestNow.Format(constants.DateFormat) || settings.MorningPlanNotifiedOn == "" {
+		t.Errorf("expected both checks to be recorded, got weekly %q and morning %q", settings.WeeklySummarySentOn, settings.MorningPlanNotifiedOn)
+	}
+
+	// The summary goes out once
+	sent = nil
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("second notify run failed: %v", err)
+	}
+	for _, e := range sent {
+		if e.Kind == constants.NotificationKindWeeklySummary {
+			t.Errorf("unexpected second summary: %s", e.Message)
+		}
+	}
+}
+
+func TestNotifyCmd_HabitReminders(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	// notifyTestNow is a Monday at noon and the grace period is 10 minutes
+	created := notifyTestNow.AddDate(0, 0, -7)
+	habits := []models.Habit{
+		{ID: "read", Name: "Read", ReminderTime: "11:55", ReminderEnabled: true},
+		{ID: "walk", Name: "Walk", ReminderTime: "11:55", ReminderEnabled: true},
+		{ID: "gym", Name: "Gym", ReminderTime: "11:55", ReminderDays: []time.Weekday{time.Monday, time.Thursday}, ReminderEnabled: true},
+		{ID: "stretch", Name: "Stretch", ReminderTime: "11:55", ReminderDays: []time.Weekday{time.Tuesday}, ReminderEnabled: true},
+		{ID: "journal", Name: "Journal", ReminderTime: "11:30", ReminderEnabled: true},
+		{ID: "meditate", Name: "Meditate", ReminderTime: "11:55"},
+	}
+	for _, h := range habits {
+		h.CreatedAt = created
+		if err := store.AddHabit(h); err != nil {
+			t.Fatalf("failed to add habit %s: %v", h.Name, err)
+		}
+	}
+	if err := store.AddHabitEntry(models.HabitEntry{
+		ID: "walk-today", HabitID: "walk", Day: notifyTestNow.Format(constants.DateFormat),
+		CreatedAt: notifyTestNow, UpdatedAt: notifyTestNow,
+	}); err != nil {
+		t.Fatalf("failed to add habit entry: %v", err)
+	}
+
+	var sent []string
+	cmd := &NotifyCmd{DryRun: true, OnDeliver: func(e models.NotificationLogEntry) {
+		if e.Kind == constants.NotificationKindHabitReminder {
+			sent = append(sent, e.Message)
+		}
+	}}
+	ctx := &cli.Context{Store: store, Clock: clock.Fixed(notifyTestNow)}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+
+	// Walk is marked, Stretch isn't reminded on Mondays, Journal is past the
+	// grace period and Meditate's reminder is off
+	want := []string{"🔁 Read isn't marked yet today", "🔁 Gym isn't marked yet today"}
+	if len(sent) != len(want) || sent[0] != want[0] || sent[1] != want[1] {
+		t.Fatalf("habit reminders = %q, want %q", sent, want)
+	}
+
+	read, err := store.GetHabit("read")
+	if err != nil {
+		t.Fatalf("failed to get habit: %v", err)
+	}
+	if read.LastReminded == nil || !read.LastReminded.Equal(notifyTestNow) {
+		t.Errorf("expected last_reminded to be recorded, got %v", read.LastReminded)
+	}
+
+	// Reminders go out once a day
+	sent = nil
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("second notify run failed: %v", err)
+	}
+	if len(sent) != 0 {
+		t.Errorf("unexpected repeated reminders: %q", sent)
+	}
+}
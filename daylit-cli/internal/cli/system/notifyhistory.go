@@ -0,0 +1,90 @@
+package system
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+type NotifyHistoryCmd struct {
+	Today bool `help:"Only show notifications sent today."`
+	Limit int  `short:"n" help:"Maximum number of notifications to show (0 for all)." default:"50"`
+}
+
+func (c *NotifyHistoryCmd) Run(ctx *cli.Context) error {
+	if err := ctx.Store.Load(); err != nil {
+		return err
+	}
+
+	var since time.Time
+	if c.Today {
+		now := ctx.Now()
+		since = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	}
+
+	entries, err := ctx.Store.GetNotificationLog(since, c.Limit)
+	if err != nil {
+		return fmt.Errorf("failed to get notification history: %w", err)
+	}
+
+	outbox, err := ctx.Store.GetOutboxItems(since)
+	if err != nil {
+		return fmt.Errorf("failed to get queued notifications: %w", err)
+	}
+
+	if len(entries) == 0 {
+		if c.Today {
+			fmt.Println("No notifications sent today.")
+		} else {
+			fmt.Println("No notifications sent yet.")
+		}
+	} else {
+		printNotificationLog(entries)
+	}
+	if len(outbox) > 0 {
+		fmt.Println()
+		printOutbox(outbox)
+	}
+
+	return nil
+}
+
+func printNotificationLog(entries []models.NotificationLogEntry) {
+	fmt.Printf("%-16s %-13s %-8s %-7s %s\n", "Time", "Kind", "Channel", "Status", "Message")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, e := range entries {
+		status := "sent"
+		if !e.Success {
+			status = "failed"
+		}
+		fmt.Printf("%-16s %-13s %-8s %-7s %s\n",
+			e.SentAt.Local().Format("2006-01-02 15:04"), e.Kind, e.Channel, status, e.Message)
+		if e.Error != "" {
+			fmt.Printf("%-16s error: %s\n", "", e.Error)
+		}
+	}
+}
+
+// printOutbox lists the notifications waiting to be retried and the ones
+// given up on
+func printOutbox(items []models.OutboxItem) {
+	fmt.Println("Undelivered notifications:")
+	fmt.Printf("%-16s %-13s %-8s %-16s %s\n", "Queued", "Kind", "Attempts", "Next attempt", "Message")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, item := range items {
+		next := item.NextAttemptAt.Local().Format("2006-01-02 15:04")
+		if item.Status == models.OutboxFailed {
+			next = "gave up"
+		}
+		fmt.Printf("%-16s %-13s %-8d %-16s %s\n",
+			item.CreatedAt.Local().Format("2006-01-02 15:04"), item.Kind, item.Attempts, next, item.Message)
+		if item.LastError != "" {
+			fmt.Printf("%-16s error: %s\n", "", item.LastError)
+		}
+	}
+}
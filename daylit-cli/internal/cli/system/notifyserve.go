@@ -0,0 +1,167 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli/plans"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/daemon"
+	"github.com/julianstephens/daylit/daylit-cli/internal/notifier"
+)
+
+// planAcceptanceDays is how far back the plan acceptance ratio looks
+const planAcceptanceDays = 30
+
+type NotifyServeCmd struct {
+	Addr     string        `help:"Address to serve /healthz and /metrics on." default:"127.0.0.1:9184"`
+	Interval time.Duration `help:"How often to check for due notifications." default:"1m"`
+	DryRun   bool          `help:"Print notifications to stdout instead of sending them."`
+	Socket   string        `help:"Unix socket the tray connects to for notifications and actions; empty to not open one." type:"path" default:"~/.config/daylit/daylit.sock"`
+}
+
+func (c *NotifyServeCmd) Run(ctx *cli.Context) error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+
+	metrics := daemon.NewMetrics(time.Now())
+	listener, err := net.Listen("tcp", c.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", c.Addr, err)
+	}
+	server := &http.Server{
+		Handler:           daemon.Handler(metrics),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Metrics server stopped: %v\n", err)
+		}
+	}()
+	fmt.Printf("Serving %s and %s on http://%s\n", daemon.HealthPath, daemon.MetricsPath, listener.Addr())
+
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	notify := &NotifyCmd{DryRun: c.DryRun, OnDeliver: metrics.RecordNotification}
+
+	// Actions from socket clients run on this goroutine between checks, so
+	// the daemon never touches the database from two places at once
+	actions := make(chan func())
+	if c.Socket != "" {
+		rpc, err := c.serveSocket(ctx, sigCtx, notify, metrics, actions)
+		if err != nil {
+			return err
+		}
+		defer rpc.Close()
+		defer os.Remove(c.Socket)
+		notify.Publish = func(text string, style notifier.Options) bool {
+			payload := notifier.WebhookPayload{
+				Text:       text,
+				DurationMs: constants.NotificationDurationMs,
+				Urgency:    style.Urgency,
+				Sound:      style.Sound,
+			}
+			return rpc.Publish(daemon.Event{Type: daemon.EventNotification, Data: payload}) > 0
+		}
+	}
+
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	c.check(ctx, notify, metrics)
+	for {
+		select {
+		case <-sigCtx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return server.Shutdown(shutdownCtx)
+		case action := <-actions:
+			action()
+		case <-ticker.C:
+			c.check(ctx, notify, metrics)
+		}
+	}
+}
+
+// serveSocket serves the RPC methods on the socket: health, check (run a
+// notification check now) and done (finish the slot in progress, as
+// 'daylit done' does). Subscribed clients are sent every notification.
+func (c *NotifyServeCmd) serveSocket(ctx *cli.Context, sigCtx context.Context, notify *NotifyCmd, metrics *daemon.Metrics, actions chan<- func()) (*daemon.RPCServer, error) {
+	listener, err := daemon.ListenUnix(c.Socket)
+	if err != nil {
+		return nil, err
+	}
+
+	// run hands fn to the main loop and waits for it
+	run := func(fn func() error) error {
+		errc := make(chan error, 1)
+		select {
+		case actions <- func() { errc <- fn() }:
+		case <-sigCtx.Done():
+			return errors.New("daemon is shutting down")
+		}
+		return <-errc
+	}
+
+	rpc := daemon.NewRPCServer()
+	rpc.Handle("health", func(json.RawMessage) (any, error) {
+		return metrics.Health(), nil
+	})
+	rpc.Handle("check", func(json.RawMessage) (any, error) {
+		return nil, run(func() error {
+			c.check(ctx, notify, metrics)
+			return nil
+		})
+	})
+	rpc.Handle("done", func(params json.RawMessage) (any, error) {
+		var done plans.DoneCmd
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &done); err != nil {
+				return nil, fmt.Errorf("%w: %v", daemon.ErrInvalidParams, err)
+			}
+		}
+		return nil, run(func() error { return done.Run(ctx) })
+	})
+
+	go func() {
+		if err := rpc.Serve(listener); err != nil {
+			fmt.Fprintf(os.Stderr, "Socket server stopped: %v\n", err)
+		}
+	}()
+	fmt.Printf("Serving the tray socket on %s\n", c.Socket)
+	return rpc, nil
+}
+
+// check probes the database, runs one notification check, and refreshes the
+// plan acceptance ratio
+func (c *NotifyServeCmd) check(ctx *cli.Context, notify *NotifyCmd, metrics *daemon.Metrics) {
+	start := time.Now()
+	_, err := ctx.Store.GetSettings()
+	metrics.RecordDBProbe(time.Since(start), err)
+
+	err = notify.Run(ctx)
+	metrics.RecordRun(time.Now(), err)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Notification check failed: %v\n", err)
+	}
+
+	today := ctx.Now()
+	summaries, err := ctx.Store.GetDaySummaries(
+		today.AddDate(0, 0, -(planAcceptanceDays-1)).Format(constants.DateFormat),
+		today.Format(constants.DateFormat),
+	)
+	if err == nil {
+		metrics.RecordPlans(summaries)
+	}
+}
@@ -0,0 +1,152 @@
+package system
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+type PurgeCmd struct {
+	Kind      string `arg:"" optional:"" help:"Purge one deleted item of this kind (task|plan|habit|entry|ot), whatever its age."`
+	ID        string `arg:"" optional:"" help:"ID, or date of a plan or OT entry, of the item to purge, as shown by 'daylit restore list'."`
+	OlderThan string `help:"Purge items deleted longer ago than this, e.g. '90d' or '12w'." default:"90d"`
+	Type      string `help:"Only purge this kind of item (task|plan|habit)."`
+	Yes       bool   `short:"y" help:"Purge without asking for confirmation."`
+}
+
+func (c *PurgeCmd) Validate() error {
+	if c.Kind != "" {
+		if _, err := models.ParseTrashKind(c.Kind); err != nil {
+			return err
+		}
+		if c.ID == "" {
+			return fmt.Errorf("an ID is required to purge a single %s", c.Kind)
+		}
+		if c.Type != "" {
+			return fmt.Errorf("--type cannot be combined with a single item")
+		}
+	}
+	if _, err := parseRetention(c.OlderThan); err != nil {
+		return err
+	}
+	if c.Type != "" {
+		if _, err := models.ParsePurgeKind(c.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *PurgeCmd) Run(ctx *cli.Context) error {
+	if c.Kind != "" {
+		return c.purgeItem(ctx)
+	}
+
+	days, err := parseRetention(c.OlderThan)
+	if err != nil {
+		return err
+	}
+	kinds := models.PurgeKinds
+	if c.Type != "" {
+		kind, err := models.ParsePurgeKind(c.Type)
+		if err != nil {
+			return err
+		}
+		kinds = []models.PurgeKind{kind}
+	}
+	cutoff := ctx.Now().AddDate(0, 0, -days)
+
+	preview, err := ctx.Store.PurgeDeleted(cutoff, kinds, true)
+	if err != nil {
+		return fmt.Errorf("failed to preview purge: %w", err)
+	}
+
+	fmt.Printf("Items deleted before %s (%d days ago):\n", cutoff.Format(constants.DateFormat), days)
+	printPurgeSummary(preview)
+	if preview.Total() == 0 {
+		fmt.Println("\nNothing to purge.")
+		return nil
+	}
+
+	if !c.Yes {
+		ok, err := ctx.Confirm(fmt.Sprintf("\nPermanently remove %d row(s)? This cannot be undone.", preview.Total()))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Purge cancelled.")
+			return nil
+		}
+	}
+
+	ctx.PerformAutomaticBackup()
+
+	summary, err := ctx.Store.PurgeDeleted(cutoff, kinds, false)
+	if err != nil {
+		return fmt.Errorf("failed to purge: %w", err)
+	}
+	fmt.Printf("Purged %d row(s).\n", summary.Total())
+	return nil
+}
+
+// purgeItem permanently removes the single item named on the command line
+func (c *PurgeCmd) purgeItem(ctx *cli.Context) error {
+	kind, err := models.ParseTrashKind(c.Kind)
+	if err != nil {
+		return err
+	}
+
+	if !c.Yes {
+		ok, err := ctx.Confirm(fmt.Sprintf("Permanently remove deleted %s %s? This cannot be undone.", kind, c.ID))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Purge cancelled.")
+			return nil
+		}
+	}
+
+	ctx.PerformAutomaticBackup()
+
+	if err := ctx.Store.PurgeItem(kind, c.ID); err != nil {
+		return fmt.Errorf("failed to purge %s: %w", kind, err)
+	}
+	fmt.Printf("Purged %s: %s\n", kind, c.ID)
+	return nil
+}
+
+func printPurgeSummary(s models.PurgeSummary) {
+	fmt.Printf("  Tasks:          %d\n", s.Tasks)
+	fmt.Printf("  Plan revisions: %d\n", s.Plans)
+	fmt.Printf("  Slots:          %d\n", s.Slots)
+	fmt.Printf("  Habits:         %d\n", s.Habits)
+	fmt.Printf("  Habit entries:  %d\n", s.HabitEntries)
+	if s.KeptTasks > 0 {
+		fmt.Printf("  %d deleted task(s) kept because plans or templates still use them\n", s.KeptTasks)
+	}
+}
+
+// parseRetention parses a retention window such as "90d" or "12w" into a
+// number of days
+func parseRetention(raw string) (int, error) {
+	value := strings.TrimSpace(strings.ToLower(raw))
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(value, "w"):
+		multiplier = 7
+		value = strings.TrimSuffix(value, "w")
+	case strings.HasSuffix(value, "d"):
+		value = strings.TrimSuffix(value, "d")
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid retention %q (expected e.g. '90d' or '12w')", raw)
+	}
+	return n * multiplier, nil
+}
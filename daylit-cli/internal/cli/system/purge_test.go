@@ -0,0 +1,90 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestParseRetention(t *testing.T) {
+	tests := map[string]int{"90d": 90, "12w": 84, "30": 30, " 0d ": 0}
+	for raw, want := range tests {
+		got, err := parseRetention(raw)
+		if err != nil || got != want {
+			t.Errorf("parseRetention(%q) = %d, %v; want %d", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"", "-1d", "3m", "d"} {
+		if _, err := parseRetention(raw); err == nil {
+			t.Errorf("parseRetention(%q) should fail", raw)
+		}
+	}
+}
+
+func TestPurgeCmd(t *testing.T) {
+	ctx, cleanup := setupTestDoctorDB(t)
+	defer cleanup()
+
+	for _, id := range []string{"task-1", "task-2"} {
+		task := models.Task{
+			ID:          id,
+			Name:        "Task " + id,
+			Kind:        constants.TaskKindFlexible,
+			DurationMin: 30,
+			Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+			Priority:    1,
+			Active:      true,
+		}
+		if err := ctx.Store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+		if err := ctx.Store.DeleteTask(id); err != nil {
+			t.Fatalf("failed to delete task: %v", err)
+		}
+	}
+
+	// Tasks deleted just now are inside the default 90-day window
+	cmd := &PurgeCmd{OlderThan: "90d", Yes: true}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("purge failed: %v", err)
+	}
+	if tasks, _ := ctx.Store.GetAllTasksIncludingDeleted(); len(tasks) != 2 {
+		t.Fatalf("expected recently deleted tasks to stay, got %d", len(tasks))
+	}
+
+	// A single item goes whatever its age
+	single := &PurgeCmd{Kind: "task", ID: "task-1", OlderThan: "90d", Yes: true}
+	if err := single.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	if err := single.Run(ctx); err != nil {
+		t.Fatalf("purge of one task failed: %v", err)
+	}
+	if tasks, _ := ctx.Store.GetAllTasksIncludingDeleted(); len(tasks) != 1 || tasks[0].ID != "task-2" {
+		t.Fatalf("expected only task-1 to be purged, got %+v", tasks)
+	}
+	if err := (&PurgeCmd{Kind: "task", OlderThan: "90d"}).Validate(); err == nil {
+		t.Error("expected a kind without an ID to be rejected")
+	}
+
+	// 91 days later they are past it
+	ctx.Clock = clock.Fixed(time.Now().AddDate(0, 0, 91))
+	cmd.Type = "habit"
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("purge failed: %v", err)
+	}
+	if tasks, _ := ctx.Store.GetAllTasksIncludingDeleted(); len(tasks) != 1 {
+		t.Fatalf("expected --type habit to leave tasks alone, got %d", len(tasks))
+	}
+
+	cmd.Type = "task"
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("purge failed: %v", err)
+	}
+	if tasks, _ := ctx.Store.GetAllTasksIncludingDeleted(); len(tasks) != 0 {
+		t.Errorf("expected the remaining task to be purged, got %d", len(tasks))
+	}
+}
@@ -0,0 +1,281 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/keyring"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/postgres"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+const (
+	backendSQLite   = "sqlite"
+	backendPostgres = "postgres"
+)
+
+type SetupCmd struct{}
+
+// setupAnswers holds the choices made in the setup wizard
+type setupAnswers struct {
+	Backend          string
+	ConnString       string
+	UseKeyring       bool
+	DayStart         string
+	DayEnd           string
+	Timezone         string
+	Notifications    bool
+	NotifyBlockStart bool
+	NotifyBlockEnd   bool
+	SeedTasks        bool
+}
+
+func (c *SetupCmd) Run(ctx *cli.Context) error {
+	if !cli.StdinIsTerminal() {
+		return fmt.Errorf("setup asks questions and needs a terminal; in scripts use 'daylit init' and 'daylit settings' instead")
+	}
+
+	answers := setupAnswers{
+		Backend:          backendSQLite,
+		UseKeyring:       true,
+		DayStart:         constants.DefaultDayStart,
+		DayEnd:           constants.DefaultDayEnd,
+		Timezone:         constants.DefaultTimezone,
+		Notifications:    constants.DefaultNotificationsEnabled,
+		NotifyBlockStart: constants.DefaultNotifyBlockStart,
+		NotifyBlockEnd:   constants.DefaultNotifyBlockEnd,
+		SeedTasks:        true,
+	}
+	sqlitePath := ""
+	if store, ok := storage.Unwrap(ctx.Store).(*sqlite.Store); ok {
+		sqlitePath = store.GetConfigPath()
+	}
+
+	fmt.Println("👋 Welcome to daylit! A few questions to get your days set up.")
+	fmt.Println("   Everything here can be changed later with 'daylit settings'.")
+	fmt.Println()
+	if err := newSetupForm(&answers, sqlitePath).Run(); err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			fmt.Println("Setup cancelled. Nothing was changed.")
+			return nil
+		}
+		return fmt.Errorf("setup form error: %w", err)
+	}
+
+	answers.ConnString = strings.TrimSpace(answers.ConnString)
+	store := ctx.Store
+	if answers.Backend == backendPostgres {
+		store = postgres.New(answers.ConnString)
+		defer store.Close()
+	} else if sqlitePath == "" {
+		return fmt.Errorf("a PostgreSQL connection string is stored in the OS keyring; remove it with 'daylit keyring delete' to use SQLite")
+	}
+
+	if err := store.Init(); err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	fmt.Printf("✓ Initialized daylit storage (%s)\n", answers.Backend)
+
+	if answers.Backend == backendPostgres && answers.UseKeyring {
+		if err := keyring.SetConnectionString(answers.ConnString); err != nil {
+			return fmt.Errorf("failed to store connection string in keyring: %w", err)
+		}
+		fmt.Println("✓ Stored the connection string in the OS keyring")
+	}
+
+	seeded, err := applySetup(store, answers)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Saved your day window (%s–%s, %s)\n", answers.DayStart, answers.DayEnd, answers.Timezone)
+	if seeded > 0 {
+		fmt.Printf("✓ Added %d example tasks; see them with 'daylit task list'\n", seeded)
+	}
+
+	if answers.Backend == backendPostgres && !answers.UseKeyring {
+		fmt.Println()
+		fmt.Println("To use this database, set it for every run, e.g.:")
+		fmt.Println("  export DAYLIT_CONFIG=\"<your connection string>\"")
+	}
+	fmt.Println()
+	fmt.Println("Next steps:")
+	fmt.Println("  daylit task add \"Read\" --duration 30 --recurrence daily   # add your own tasks")
+	fmt.Println("  daylit plan                                             # plan today")
+	fmt.Println("  daylit tui                                              # open the dashboard")
+	return nil
+}
+
+func newSetupForm(a *setupAnswers, sqlitePath string) *huh.Form {
+	sqliteLabel := "SQLite (a local file; best for one machine)"
+	if sqlitePath != "" {
+		sqliteLabel = fmt.Sprintf("SQLite (%s)", sqlitePath)
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Where should daylit keep your data?").
+				Options(
+					huh.NewOption(sqliteLabel, backendSQLite),
+					huh.NewOption("PostgreSQL (share one database between machines)", backendPostgres),
+				).
+				Value(&a.Backend),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Store the connection string in the OS keyring?").
+				Description("daylit then finds the database without --config or DAYLIT_CONFIG").
+				Value(&a.UseKeyring),
+			huh.NewInput().
+				Title("PostgreSQL connection string").
+				Description("e.g. postgresql://me@localhost:5432/daylit").
+				Value(&a.ConnString).
+				Validate(func(s string) error {
+					_, err := postgres.ValidateConnString(strings.TrimSpace(s))
+					if errors.Is(err, postgres.ErrEmbeddedCredentials) {
+						if a.UseKeyring {
+							return nil
+						}
+						return fmt.Errorf("passwords can only be saved in the keyring; use .pgpass or the keyring instead")
+					}
+					return err
+				}),
+		).WithHideFunc(func() bool { return a.Backend != backendPostgres }),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("When does your day start? (HH:MM)").
+				Value(&a.DayStart).
+				Validate(validateSetupTime),
+			huh.NewInput().
+				Title("When does it end? (HH:MM, may be after midnight)").
+				Value(&a.DayEnd).
+				Validate(func(s string) error {
+					if err := validateSetupTime(s); err != nil {
+						return err
+					}
+					if s == a.DayStart {
+						return fmt.Errorf("day end must differ from day start")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title("Timezone (IANA name or 'Local')").
+				Description("Examples: Local, UTC, America/New_York, Europe/London").
+				Value(&a.Timezone).
+				Validate(func(s string) error {
+					if !utils.ValidateTimezone(s) {
+						return fmt.Errorf("invalid timezone name")
+					}
+					return nil
+				}),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Send desktop notifications?").
+				Description("They're sent by 'daylit notify', run every minute from a scheduler").
+				Value(&a.Notifications),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Notify when a block starts?").
+				Value(&a.NotifyBlockStart),
+			huh.NewConfirm().
+				Title("Notify when a block ends?").
+				Value(&a.NotifyBlockEnd),
+		).WithHideFunc(func() bool { return !a.Notifications }),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Add a few example tasks to plan with?").
+				Description("Deep work, email, lunch and a walk; edit or delete them any time").
+				Value(&a.SeedTasks),
+		),
+	)
+}
+
+func validateSetupTime(s string) error {
+	if _, err := time.Parse(constants.TimeFormat, s); err != nil {
+		return fmt.Errorf("invalid time format, use HH:MM")
+	}
+	return nil
+}
+
+// applySetup saves the wizard's settings to store and adds the example tasks
+// if asked, skipping any whose name is already taken. It returns how many
+// tasks were added.
+func applySetup(store storage.Provider, a setupAnswers) (int, error) {
+	settings, err := store.GetSettings()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get settings: %w", err)
+	}
+	settings.DayStart = a.DayStart
+	settings.DayEnd = a.DayEnd
+	settings.Timezone = a.Timezone
+	settings.NotificationsEnabled = a.Notifications
+	settings.NotifyBlockStart = a.Notifications && a.NotifyBlockStart
+	settings.NotifyBlockEnd = a.Notifications && a.NotifyBlockEnd
+	if _, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd); err != nil {
+		return 0, err
+	}
+	if err := store.SaveSettings(settings); err != nil {
+		return 0, fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	if !a.SeedTasks {
+		return 0, nil
+	}
+	existing, err := store.GetAllTasks()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, task := range existing {
+		taken[strings.ToLower(task.Name)] = true
+	}
+
+	added := 0
+	for _, task := range exampleTasks() {
+		if taken[strings.ToLower(task.Name)] {
+			continue
+		}
+		if err := store.AddTask(task); err != nil {
+			return added, fmt.Errorf("failed to add example task %q: %w", task.Name, err)
+		}
+		added++
+	}
+	return added, nil
+}
+
+// exampleTasks is a small, realistic starting point for a first plan
+func exampleTasks() []models.Task {
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	return []models.Task{
+		{
+			ID: uuid.New().String(), Name: "Deep work", Kind: constants.TaskKindFlexible, DurationMin: 90,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceWeekdays},
+			Priority:   1, EnergyBand: constants.EnergyHigh, Active: true,
+		},
+		{
+			ID: uuid.New().String(), Name: "Email and messages", Kind: constants.TaskKindFlexible, DurationMin: 30,
+			Recurrence: daily, Priority: 3, EnergyBand: constants.EnergyLow, Active: true,
+		},
+		{
+			ID: uuid.New().String(), Name: "Lunch", Kind: constants.TaskKindAppointment, DurationMin: 60,
+			FixedStart: "12:00", FixedEnd: "13:00", Recurrence: daily, Priority: 2, Active: true,
+		},
+		{
+			ID: uuid.New().String(), Name: "Walk", Kind: constants.TaskKindFlexible, DurationMin: 30,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceNDays, IntervalDays: 2},
+			Priority:   4, EnergyBand: constants.EnergyMedium, Active: true,
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+func TestApplySetup(t *testing.T) {
+	ctx, _, cleanup := setupTestInitDB(t)
+	defer cleanup()
+	if err := ctx.Store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+
+	answers := setupAnswers{
+		Backend:          backendSQLite,
+		DayStart:         "06:30",
+		DayEnd:           "22:00",
+		Timezone:         "UTC",
+		Notifications:    false,
+		NotifyBlockStart: true,
+		NotifyBlockEnd:   true,
+		SeedTasks:        true,
+	}
+	added, err := applySetup(ctx.Store, answers)
+	if err != nil {
+		t.Fatalf("applySetup failed: %v", err)
+	}
+	if added != len(exampleTasks()) {
+		t.Errorf("expected %d example tasks, got %d", len(exampleTasks()), added)
+	}
+
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.DayStart != "06:30" || settings.DayEnd != "22:00" || settings.Timezone != "UTC" {
+		t.Errorf("expected the day window 06:30-22:00 UTC, got %s-%s %s", settings.DayStart, settings.DayEnd, settings.Timezone)
+	}
+	// Block notifications mean nothing with notifications off
+	if settings.NotificationsEnabled || settings.NotifyBlockStart || settings.NotifyBlockEnd {
+		t.Errorf("expected notifications off, got %+v", settings)
+	}
+
+	// Running setup again doesn't duplicate the examples
+	added, err = applySetup(ctx.Store, answers)
+	if err != nil {
+		t.Fatalf("second applySetup failed: %v", err)
+	}
+	if added != 0 {
+		t.Errorf("expected no new tasks the second time, got %d", added)
+	}
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != len(exampleTasks()) {
+		t.Errorf("expected %d tasks, got %d", len(exampleTasks()), len(tasks))
+	}
+	for _, task := range tasks {
+		if err := task.Validate(); err != nil {
+			t.Errorf("example task %q is invalid: %v", task.Name, err)
+		}
+		if task.Name == "Lunch" && task.Kind != constants.TaskKindAppointment {
+			t.Errorf("expected Lunch to be an appointment, got %s", task.Kind)
+		}
+	}
+}
@@ -3,19 +3,29 @@ package system
 import (
 	"fmt"
 
+	"github.com/alecthomas/kong"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/julianstephens/daylit/daylit-cli/internal/cheatsheet"
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui"
 )
 
 type TuiCmd struct{}
 
-func (c *TuiCmd) Run(ctx *cli.Context) error {
+func (c *TuiCmd) Run(ctx *cli.Context, kctx *kong.Context) error {
 	// Perform automatic backup on TUI startup (after successful load)
 	ctx.PerformAutomaticBackup()
 
-	p := tea.NewProgram(tui.NewModel(ctx.Store, ctx.Scheduler), tea.WithAltScreen())
+	m := tui.NewModel(ctx.Store, ctx.Scheduler, ctx.Clock)
+	defer m.Close()
+	m.Cheatsheet = cheatsheet.Build(kctx.Model.Node)
+	if ctx.Config.Theme != "" {
+		m.ThemeOverride = ctx.Config.Theme
+		m.ApplyTheme(ctx.Config.Theme)
+	}
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("TUI error: %w", err)
 	}
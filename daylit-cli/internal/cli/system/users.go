@@ -0,0 +1,50 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+type UsersCmd struct {
+	JSON bool `help:"Output as JSON."`
+}
+
+func (c *UsersCmd) Run(ctx *cli.Context) error {
+	lister, ok := storage.Unwrap(ctx.Store).(storage.UserLister)
+	if !ok {
+		return fmt.Errorf("this database holds a single user; users need a shared PostgreSQL database")
+	}
+	users, err := lister.ListUsers()
+	if err != nil {
+		return err
+	}
+
+	if c.JSON {
+		if users == nil {
+			users = []models.User{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(users)
+	}
+
+	fmt.Printf("Users (%d):\n", len(users))
+	for _, u := range users {
+		marker := " "
+		if u.Current {
+			marker = "*"
+		}
+		lastPlan := "no plans yet"
+		if u.LastPlan != "" {
+			lastPlan = "last plan " + u.LastPlan
+		}
+		fmt.Printf("%s %-32s  %3d tasks, %s\n", marker, models.UserLabel(u.Name), u.Tasks, lastPlan)
+	}
+	fmt.Println("\nAdd a user with 'daylit --user NAME init'.")
+	return nil
+}
@@ -1,20 +1,31 @@
 package system
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/validation"
 )
 
 type ValidateCmd struct {
-	Fix bool `help:"Automatically fix conflicts where possible (e.g., remove duplicate tasks)." default:"false"`
+	Fix     bool `help:"Automatically fix conflicts where possible (e.g., remove duplicate tasks)." default:"false"`
+	Resolve bool `help:"Walk through overlapping appointments in today's plan and pick a fix for each." default:"false"`
 }
 
 func (cmd *ValidateCmd) Run(ctx *cli.Context) error {
 	defer ctx.Store.Close()
 
+	if cmd.Resolve && !cli.StdinIsTerminal() {
+		return fmt.Errorf("--resolve asks which fix to apply and needs a terminal")
+	}
+
 	// Get settings for day boundaries
 	settings, err := ctx.Store.GetSettings()
 	if err != nil {
@@ -29,6 +40,8 @@ func (cmd *ValidateCmd) Run(ctx *cli.Context) error {
 
 	// Create validator
 	validator := validation.New()
+	validator.MinFreeMinutes = settings.MinFreeMinutes
+	validator.Recovery = settings.Recovery()
 
 	// Validate tasks
 	fmt.Println("Validating tasks...")
@@ -37,7 +50,7 @@ func (cmd *ValidateCmd) Run(ctx *cli.Context) error {
 	// For plan validation, we'll only validate today's plan if it exists
 	fmt.Println("Validating today's plan...")
 	// Get today's date
-	today := time.Now()
+	today := ctx.Now()
 	dateStr := today.Format("2006-01-02")
 
 	plan, err := ctx.Store.GetPlan(dateStr)
@@ -91,6 +104,24 @@ func (cmd *ValidateCmd) Run(ctx *cli.Context) error {
 		}
 	}
 
+	if cmd.Resolve {
+		fmt.Println()
+		resolved, err := cmd.resolve(ctx, plan, tasks, settings)
+		if err != nil {
+			return err
+		}
+		if resolved {
+			plan, err = ctx.Store.GetPlan(dateStr)
+			if err == nil && len(plan.Slots) > 0 {
+				planResult = validator.ValidatePlan(plan, tasks, settings.DayStart, settings.DayEnd)
+			} else {
+				planResult = validation.ValidationResult{Conflicts: []validation.Conflict{}}
+			}
+			allConflicts = append(taskResult.Conflicts, planResult.Conflicts...)
+			combinedResult = validation.ValidationResult{Conflicts: allConflicts}
+		}
+	}
+
 	// Print report
 	fmt.Println()
 	fmt.Println(combinedResult.FormatReport())
@@ -101,3 +132,120 @@ func (cmd *ValidateCmd) Run(ctx *cli.Context) error {
 
 	return nil
 }
+
+// resolve offers fixes for the overlapping appointments in today's plan and
+// saves the plan with the chosen ones as a new revision. It reports whether
+// the plan changed.
+func (cmd *ValidateCmd) resolve(ctx *cli.Context, plan models.DayPlan, tasks []models.Task, settings models.Settings) (bool, error) {
+	window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+	if err != nil {
+		return false, err
+	}
+	if len(plan.Slots) == 0 {
+		fmt.Println("No plan for today; nothing to resolve.")
+		return false, nil
+	}
+
+	resolved, changed, err := resolveOverlaps(bufio.NewReader(os.Stdin), plan, tasks, window)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	resolved.Revision = 0
+	resolved.Version = 0
+	if resolved.AcceptedAt != nil {
+		accepted := ctx.Now().UTC().Format(time.RFC3339)
+		resolved.AcceptedAt = &accepted
+	}
+	if err := ctx.Store.SavePlan(resolved); err != nil {
+		return false, fmt.Errorf("failed to save plan: %w", err)
+	}
+	if saved, err := ctx.Store.GetPlan(resolved.Date); err == nil {
+		fmt.Printf("\nResolved plan saved as revision %d.\n", saved.Revision)
+	} else {
+		fmt.Println("\nResolved plan saved.")
+	}
+	return true, nil
+}
+
+// resolveOverlaps asks, for each pair of overlapping appointments in plan,
+// which fix to apply, reading the answers from in. An overlap can be left
+// as it is, and running out of input leaves the rest. It returns the plan
+// with the chosen fixes and whether any were chosen.
+func resolveOverlaps(in *bufio.Reader, plan models.DayPlan, tasks []models.Task, window models.DayWindow) (models.DayPlan, bool, error) {
+	names := make(map[string]string, len(tasks))
+	for _, task := range tasks {
+		names[task.ID] = task.Name
+	}
+	name := func(id string) string {
+		if n, ok := names[id]; ok {
+			return n
+		}
+		return id
+	}
+	label := func(slot models.Slot) string {
+		return fmt.Sprintf("%s %s–%s", name(slot.TaskID), slot.Start, slot.End)
+	}
+
+	changed := false
+	kept := make(map[string]bool)
+	for {
+		var overlap *validation.Overlap
+		for _, o := range validation.AppointmentOverlaps(plan, tasks, window) {
+			if !kept[overlapKey(plan, o)] {
+				overlap = &o
+				break
+			}
+		}
+		if overlap == nil {
+			if !changed && len(kept) == 0 {
+				fmt.Println("No overlapping appointments in today's plan.")
+			}
+			return plan, changed, nil
+		}
+
+		options := validation.Resolutions(plan, *overlap, window, name)
+		fmt.Printf("%s overlaps %s:\n", label(plan.Slots[overlap.First]), label(plan.Slots[overlap.Second]))
+		for i, r := range options {
+			fmt.Printf("  %d) %s\n", i+1, r.Description)
+		}
+		fmt.Println("  k) Keep both as they are")
+
+		for {
+			fmt.Print("Fix> ")
+			line, err := in.ReadString('\n')
+			if err != nil && err != io.EOF {
+				return plan, changed, err
+			}
+			answer := strings.ToLower(strings.TrimSpace(line))
+			if answer == "" && err == io.EOF {
+				fmt.Println()
+				return plan, changed, nil
+			}
+			if answer == "k" || answer == "keep" {
+				kept[overlapKey(plan, *overlap)] = true
+				break
+			}
+			n, convErr := strconv.Atoi(answer)
+			if convErr != nil || n < 1 || n > len(options) {
+				fmt.Printf("Choose 1-%d, or k to keep both.\n", len(options))
+				if err == io.EOF {
+					return plan, changed, nil
+				}
+				continue
+			}
+			plan.Slots = options[n-1].Slots
+			changed = true
+			fmt.Printf("✓ %s\n", options[n-1].Description)
+			break
+		}
+		fmt.Println()
+	}
+}
+
+// overlapKey identifies an overlap by its slots, so a kept one isn't asked
+// about again after other fixes change the slot indexes
+func overlapKey(plan models.DayPlan, o validation.Overlap) string {
+	first, second := plan.Slots[o.First], plan.Slots[o.Second]
+	return strings.Join([]string{first.TaskID, first.Start, second.TaskID, second.Start}, "|")
+}
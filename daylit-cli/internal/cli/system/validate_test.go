@@ -0,0 +1,73 @@
+package system
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestResolveOverlaps(t *testing.T) {
+	tasks := []models.Task{
+		{ID: "dentist", Name: "Dentist", Kind: constants.TaskKindAppointment},
+		{ID: "call", Name: "Call", Kind: constants.TaskKindAppointment},
+		{ID: "lunch", Name: "Lunch", Kind: constants.TaskKindAppointment},
+	}
+	plan := models.DayPlan{Date: "2026-11-02", Slots: []models.Slot{
+		{TaskID: "dentist", Start: "09:00", End: "10:00"},
+		{TaskID: "call", Start: "09:30", End: "10:30"},
+		{TaskID: "lunch", Start: "12:00", End: "13:00"},
+	}}
+	window, _ := models.ParseDayWindow("07:00", "22:00")
+
+	tests := []struct {
+		name    string
+		input   string
+		changed bool
+		want    string
+	}{
+		{
+			name:    "shift the later one",
+			input:   "1\n",
+			changed: true,
+			want:    "09:00 dentist, 10:00 call, 12:00 lunch",
+		},
+		{
+			name:    "invalid answer asks again",
+			input:   "9\nskip\n6\n",
+			changed: true,
+			want:    "09:00 dentist, 12:00 lunch",
+		},
+		{
+			name:  "keep both",
+			input: "k\n",
+			want:  "09:00 dentist, 09:30 call, 12:00 lunch",
+		},
+		{
+			name:  "no answer keeps the plan",
+			input: "",
+			want:  "09:00 dentist, 09:30 call, 12:00 lunch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, err := resolveOverlaps(bufio.NewReader(strings.NewReader(tt.input)), plan, tasks, window)
+			if err != nil {
+				t.Fatalf("resolveOverlaps failed: %v", err)
+			}
+			if changed != tt.changed {
+				t.Errorf("changed = %v, want %v", changed, tt.changed)
+			}
+			var parts []string
+			for _, slot := range got.Slots {
+				parts = append(parts, slot.Start+" "+slot.TaskID)
+			}
+			if summary := strings.Join(parts, ", "); summary != tt.want {
+				t.Errorf("slots = %q, want %q", summary, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package tasks
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -12,8 +15,8 @@ import (
 )
 
 type TaskAddCmd struct {
-	Name             string `arg:"" help:"Task name."`
-	Duration         int    `short:"d" help:"Duration in minutes." required:""`
+	Name             string `arg:"" optional:"" help:"Task name."`
+	Duration         int    `short:"d" help:"Duration in minutes. Required unless --from-file is given."`
 	Recurrence       string `short:"r" help:"Recurrence type (daily|weekly|n_days|ad_hoc|monthly_date|monthly_day|yearly|weekdays)." default:"ad_hoc"`
 	Interval         int    `short:"i" help:"Interval for n_days recurrence." default:"1"`
 	Weekdays         string `short:"w" help:"Comma-separated weekdays for weekly recurrence."`
@@ -21,14 +24,40 @@ type TaskAddCmd struct {
 	Month            int    `help:"Month (1-12) for yearly recurrence."`
 	WeekOccurrence   int    `help:"Week occurrence for monthly_day recurrence (-1=last, 1=first, 2=second, etc.)."`
 	DayOfWeekInMonth string `help:"Day of week for monthly_day recurrence (e.g., 'monday', 'friday')."`
+	Until            string `help:"Last date the task recurs on (YYYY-MM-DD)."`
+	Count            int    `help:"Stop recurring after this many occurrences, counted from today."`
 	Earliest         string `short:"s" help:"Earliest start time (HH:MM)."`
 	Latest           string `short:"e" help:"Latest end time (HH:MM)."`
 	FixedStart       string `short:"S" help:"Fixed start time for appointments (HH:MM)."`
 	FixedEnd         string `short:"E" help:"Fixed end time for appointments (HH:MM)."`
+	Prep             int    `help:"Minutes to get ready before an appointment; plans keep them free and notifications come when it's time to get ready."`
+	Travel           int    `help:"Minutes to travel to an appointment; plans keep them free before and after it."`
 	Priority         int    `short:"p" help:"Priority (1-5, lower is higher priority)." default:"3"`
+	Project          string `short:"P" help:"Name of the project the task belongs to."`
+	Context          string `short:"c" help:"Context the task needs, e.g. 'home' or 'office'. Tasks without a context fit any day."`
+	Pool             string `help:"Name of the task pool the task takes turns in (see 'daylit pool')."`
+	NiceToHave       bool   `help:"Only schedule the task in time left over by the other tasks." name:"nice-to-have"`
+	Leisure          bool   `help:"Count the task's blocks as free time, e.g. for reading or a walk."`
+	Energy           string `help:"Energy the task takes (low|medium|high). High-energy tasks get recovery time after them (see the recovery_minutes setting)."`
+	NoStartNotify    bool   `help:"Don't send a notification when the task's blocks start."`
+	NotifyOffset     *int   `help:"Minutes before the task's blocks start to notify, overriding the block start offset setting."`
+	NotifyMessage    string `help:"Custom text for the task's start notification."`
+	NotifyUrgency    string `help:"Urgency of the task's start notification (low|normal|critical)."`
+	NotifySound      string `help:"Sound name for the task's start notification; empty uses the default."`
+	FromFile         string `short:"f" help:"Add every task in a YAML or JSON file instead, or '-' to read from stdin."`
 }
 
 func (c *TaskAddCmd) Validate() error {
+	if c.FromFile != "" {
+		if c.Name != "" {
+			return fmt.Errorf("a task name can't be combined with --from-file")
+		}
+		return nil
+	}
+	if c.Name == "" {
+		return fmt.Errorf("task name is required")
+	}
+
 	// Validate priority
 	if c.Priority < 1 || c.Priority > 5 {
 		return fmt.Errorf("priority must be between 1 and 5")
@@ -80,6 +109,19 @@ func (c *TaskAddCmd) Validate() error {
 		// The scheduler will skip years where this date doesn't exist.
 	}
 
+	// Validate recurrence bounds
+	if c.Until != "" {
+		if _, err := time.Parse(constants.DateFormat, c.Until); err != nil {
+			return fmt.Errorf("invalid --until date %q (expected YYYY-MM-DD)", c.Until)
+		}
+	}
+	if c.Count < 0 {
+		return fmt.Errorf("--count cannot be negative")
+	}
+	if c.Count > 0 && (c.Recurrence == "n_days" || c.Recurrence == "ad_hoc") {
+		return fmt.Errorf("--count can't be used with %s recurrence", c.Recurrence)
+	}
+
 	// Validate time formats
 	if c.Earliest != "" {
 		if _, err := utils.ParseTime(c.Earliest); err != nil {
@@ -102,11 +144,13 @@ func (c *TaskAddCmd) Validate() error {
 		}
 	}
 
-	// Validate FixedStart comes before FixedEnd
+	// Validate FixedStart comes before FixedEnd; an appointment may cross
+	// midnight, e.g. 23:00-00:30
 	if c.FixedStart != "" && c.FixedEnd != "" {
-		start, _ := utils.ParseTime(c.FixedStart) // Already validated above, won't fail
-		end, _ := utils.ParseTime(c.FixedEnd)     // Already validated above, won't fail
-		if !start.Before(end) {
+		if c.FixedStart == c.FixedEnd {
+			return fmt.Errorf("fixedStart must be before FixedEnd")
+		}
+		if _, _, err := (models.DayWindow{}).Range(c.FixedStart, c.FixedEnd); err != nil {
 			return fmt.Errorf("fixedStart must be before FixedEnd")
 		}
 	}
@@ -130,6 +174,24 @@ func (c *TaskAddCmd) Validate() error {
 }
 
 func (c *TaskAddCmd) Run(ctx *cli.Context) error {
+	if c.FromFile != "" {
+		return addFromFile(ctx, c.FromFile)
+	}
+
+	task, err := c.task(ctx)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Store.AddTask(task); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added task: %s (ID: %s)\n", c.Name, task.ID)
+	return nil
+}
+
+// task builds a new, validated task from the flags
+func (c *TaskAddCmd) task(ctx *cli.Context) (models.Task, error) {
 	// Determine task kind
 	taskKind := constants.TaskKindFlexible
 	if c.FixedStart != "" && c.FixedEnd != "" {
@@ -156,19 +218,24 @@ func (c *TaskAddCmd) Run(ctx *cli.Context) error {
 	case "weekdays":
 		recType = constants.RecurrenceWeekdays
 	default:
-		return fmt.Errorf("invalid recurrence type: %s", c.Recurrence)
+		return models.Task{}, fmt.Errorf("invalid recurrence type: %s", c.Recurrence)
 	}
 
 	rec := models.Recurrence{
 		Type:         recType,
 		IntervalDays: c.Interval,
+		Until:        c.Until,
+		Count:        c.Count,
+	}
+	if c.Count > 0 {
+		rec.Start = ctx.Now().Format(constants.DateFormat)
 	}
 
 	// Parse weekdays for weekly recurrence
 	if recType == constants.RecurrenceWeekly && c.Weekdays != "" {
 		wds, err := cli.ParseWeekdays(c.Weekdays)
 		if err != nil {
-			return err
+			return models.Task{}, err
 		}
 		rec.WeekdayMask = wds
 	}
@@ -183,7 +250,7 @@ func (c *TaskAddCmd) Run(ctx *cli.Context) error {
 		rec.WeekOccurrence = c.WeekOccurrence
 		wd, err := cli.ParseWeekday(c.DayOfWeekInMonth)
 		if err != nil {
-			return fmt.Errorf("invalid --day-of-week-in-month: %w", err)
+			return models.Task{}, fmt.Errorf("invalid --day-of-week-in-month: %w", err)
 		}
 		rec.DayOfWeekInMonth = wd
 	}
@@ -194,6 +261,19 @@ func (c *TaskAddCmd) Run(ctx *cli.Context) error {
 		rec.MonthDay = c.MonthDay
 	}
 
+	projectID, err := resolveProject(ctx, c.Project)
+	if err != nil {
+		return models.Task{}, err
+	}
+	poolID, err := resolvePool(ctx, c.Pool)
+	if err != nil {
+		return models.Task{}, err
+	}
+	energy, err := parseEnergyBand(c.Energy)
+	if err != nil {
+		return models.Task{}, err
+	}
+
 	// Create task
 	task := models.Task{
 		ID:                   uuid.New().String(),
@@ -209,16 +289,55 @@ func (c *TaskAddCmd) Run(ctx *cli.Context) error {
 		Active:               true,
 		SuccessStreak:        0,
 		AvgActualDurationMin: float64(c.Duration),
+		ProjectID:            projectID,
+		PoolID:               poolID,
+		NiceToHave:           c.NiceToHave,
+		Leisure:              c.Leisure,
+		PrepMin:              c.Prep,
+		TravelMin:            c.Travel,
+		EnergyBand:           energy,
+		Context:              models.NormalizeContext(c.Context),
+		NotifyStartDisabled:  c.NoStartNotify,
+		NotifyOffsetMin:      c.NotifyOffset,
+		NotifyMessage:        c.NotifyMessage,
+		NotifyUrgency:        c.NotifyUrgency,
+		NotifySound:          c.NotifySound,
 	}
 
 	if err := task.Validate(); err != nil {
-		return fmt.Errorf("invalid task: %w", err)
+		return models.Task{}, fmt.Errorf("invalid task: %w", err)
 	}
+	return task, nil
+}
 
-	if err := ctx.Store.AddTask(task); err != nil {
-		return err
+// resolveProject returns the ID of the named project, or an empty ID for an
+// empty name
+func resolveProject(ctx *cli.Context, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	project, err := ctx.Store.GetProjectByName(name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("project %q not found (create it with 'daylit project add')", name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get project: %w", err)
 	}
+	return project.ID, nil
+}
 
-	fmt.Printf("Added task: %s (ID: %s)\n", c.Name, task.ID)
-	return nil
+// resolvePool returns the ID of the named task pool, or an empty ID for an
+// empty name
+func resolvePool(ctx *cli.Context, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	pool, err := ctx.Store.GetPoolByName(name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("task pool %q not found (create it with 'daylit pool add')", name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get task pool: %w", err)
+	}
+	return pool.ID, nil
 }
@@ -0,0 +1,292 @@
+package tasks
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+type TaskBulkCmd struct {
+	// Filters
+	All        bool   `help:"Select every task. Required when no other filter is given."`
+	Context    string `short:"c" aliases:"tag" help:"Select tasks in this context, e.g. 'errands'."`
+	Project    string `short:"P" help:"Select tasks in this project."`
+	Recurrence string `short:"r" help:"Select tasks with this recurrence type (daily|weekly|n_days|ad_hoc|monthly_date|monthly_day|yearly|weekdays)."`
+	Priority   int    `short:"p" help:"Select tasks with this priority (1-5)."`
+	Match      string `short:"m" help:"Select tasks whose name contains this text (case-insensitive)."`
+	Active     bool   `help:"Select active tasks." xor:"active"`
+	Inactive   bool   `help:"Select inactive tasks." xor:"active"`
+
+	// Operations
+	Set    []string `help:"Change a field on every selected task, as key=value. Repeat for several fields. Keys are the 'task edit' flag names, e.g. priority=4 or interval=2." sep:"none" xor:"op"`
+	Delete bool     `help:"Delete every selected task." xor:"op"`
+	DryRun bool     `help:"Show what would change without saving."`
+}
+
+func (c *TaskBulkCmd) Validate() error {
+	if len(c.Set) == 0 && !c.Delete {
+		return fmt.Errorf("specify --set or --delete")
+	}
+	if !c.All && !c.hasFilter() {
+		return fmt.Errorf("specify a filter, or --all to select every task")
+	}
+	if c.Priority != 0 && (c.Priority < 1 || c.Priority > 5) {
+		return fmt.Errorf("priority must be between 1 and 5")
+	}
+	if c.Recurrence != "" {
+		if _, err := parseRecurrenceType(c.Recurrence); err != nil {
+			return err
+		}
+	}
+	_, err := parseBulkSet(c.Set)
+	return err
+}
+
+func (c *TaskBulkCmd) hasFilter() bool {
+	return c.Context != "" || c.Project != "" || c.Recurrence != "" || c.Priority != 0 ||
+		c.Match != "" || c.Active || c.Inactive
+}
+
+func (c *TaskBulkCmd) Run(ctx *cli.Context) error {
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	projectID := ""
+	if c.Project != "" {
+		if projectID, err = resolveProject(ctx, c.Project); err != nil {
+			return err
+		}
+	}
+
+	var selected []models.Task
+	for _, task := range tasks {
+		if c.matches(task, projectID) {
+			selected = append(selected, task)
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Println("No tasks match the filter")
+		return nil
+	}
+
+	if c.Delete {
+		return c.delete(ctx, selected)
+	}
+	return c.update(ctx, selected)
+}
+
+// matches reports whether task passes every filter
+func (c *TaskBulkCmd) matches(task models.Task, projectID string) bool {
+	if c.Context != "" && task.Context != models.NormalizeContext(c.Context) {
+		return false
+	}
+	if c.Project != "" && task.ProjectID != projectID {
+		return false
+	}
+	if c.Recurrence != "" && string(task.Recurrence.Type) != c.Recurrence {
+		return false
+	}
+	if c.Priority != 0 && task.Priority != c.Priority {
+		return false
+	}
+	if c.Match != "" && !strings.Contains(strings.ToLower(task.Name), strings.ToLower(c.Match)) {
+		return false
+	}
+	if c.Active && !task.Active || c.Inactive && task.Active {
+		return false
+	}
+	return true
+}
+
+func (c *TaskBulkCmd) delete(ctx *cli.Context, selected []models.Task) error {
+	if c.DryRun {
+		fmt.Printf("Would delete %d task(s):\n", len(selected))
+		for _, task := range selected {
+			fmt.Printf("  %s (ID: %s)\n", task.Name, task.ID)
+		}
+		return nil
+	}
+
+	for _, task := range selected {
+		if err := ctx.Store.DeleteTask(task.ID); err != nil {
+			return fmt.Errorf("failed to delete task %s: %w", task.Name, err)
+		}
+		fmt.Printf("Deleted task: %s (ID: %s)\n", task.Name, task.ID)
+	}
+	fmt.Printf("Deleted %d task(s)\n", len(selected))
+	return nil
+}
+
+func (c *TaskBulkCmd) update(ctx *cli.Context, selected []models.Task) error {
+	edit, err := parseBulkSet(c.Set)
+	if err != nil {
+		return err
+	}
+
+	// Apply every change before saving any, so an invalid result leaves all
+	// tasks untouched
+	type change struct {
+		task    models.Task
+		changes []models.FieldChange
+	}
+	var changed []change
+	for _, task := range selected {
+		updated := task
+		if err := edit.apply(ctx, &updated); err != nil {
+			return fmt.Errorf("%s: %w", task.Name, err)
+		}
+		if diff := models.DiffFields(task, updated); len(diff) > 0 {
+			changed = append(changed, change{task: updated, changes: diff})
+		}
+	}
+	if len(changed) == 0 {
+		fmt.Printf("No changes: %d matching task(s) already have these values\n", len(selected))
+		return nil
+	}
+
+	if c.DryRun {
+		fmt.Printf("Would update %d task(s):\n", len(changed))
+	}
+	for _, ch := range changed {
+		if !c.DryRun {
+			if err := ctx.Store.UpdateTask(ch.task); err != nil {
+				return fmt.Errorf("failed to update task %s: %w", ch.task.Name, err)
+			}
+		}
+		fmt.Printf("  %s: %s\n", ch.task.Name, formatFieldChanges(ch.changes))
+	}
+	if !c.DryRun {
+		fmt.Printf("Updated %d task(s)\n", len(changed))
+	}
+	return nil
+}
+
+// parseBulkSet turns key=value pairs into the equivalent 'task edit' flags
+func parseBulkSet(pairs []string) (*TaskEditCmd, error) {
+	edit := &TaskEditCmd{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --set %q: expected key=value", pair)
+		}
+		key = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "_", "-")
+
+		var err error
+		switch key {
+		case "duration":
+			edit.Duration, err = parseIntValue(key, value)
+		case "recurrence":
+			if _, err = parseRecurrenceType(value); err == nil {
+				edit.Recurrence = &value
+			}
+		case "interval":
+			edit.Interval, err = parseIntValue(key, value)
+		case "weekdays":
+			edit.Weekdays = &value
+		case "month-day":
+			edit.MonthDay, err = parseIntValue(key, value)
+		case "month":
+			edit.Month, err = parseIntValue(key, value)
+		case "week-occurrence":
+			edit.WeekOccurrence, err = parseIntValue(key, value)
+		case "day-of-week-in-month":
+			edit.DayOfWeekInMonth = &value
+		case "earliest":
+			edit.Earliest = &value
+		case "latest":
+			edit.Latest = &value
+		case "fixed-start":
+			edit.FixedStart = &value
+		case "fixed-end":
+			edit.FixedEnd = &value
+		case "priority":
+			edit.Priority, err = parseIntValue(key, value)
+		case "active":
+			edit.Active, err = parseBoolValue(key, value)
+		case "project":
+			edit.Project = &value
+		case "context":
+			edit.Context = &value
+		case "start-notify":
+			edit.StartNotify, err = parseBoolValue(key, value)
+		case "notify-offset":
+			edit.NotifyOffset, err = parseIntValue(key, value)
+		case "notify-message":
+			edit.NotifyMessage = &value
+		case "notify-urgency":
+			edit.NotifyUrgency = &value
+		case "notify-sound":
+			edit.NotifySound = &value
+		case "name":
+			return nil, fmt.Errorf("--set name is not supported; rename tasks one at a time with 'task edit'")
+		default:
+			return nil, fmt.Errorf("unknown --set key: %s", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return edit, nil
+}
+
+func parseIntValue(key, value string) (*int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: expected a number", key, value)
+	}
+	return &n, nil
+}
+
+func parseBoolValue(key, value string) (*bool, error) {
+	b, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: expected true or false", key, value)
+	}
+	return &b, nil
+}
+
+// parseRecurrenceType checks a recurrence type name given on the command line
+func parseRecurrenceType(value string) (constants.RecurrenceType, error) {
+	switch rt := constants.RecurrenceType(value); rt {
+	case constants.RecurrenceDaily, constants.RecurrenceWeekly, constants.RecurrenceNDays,
+		constants.RecurrenceAdHoc, constants.RecurrenceMonthlyDate, constants.RecurrenceMonthlyDay,
+		constants.RecurrenceYearly, constants.RecurrenceWeekdays:
+		return rt, nil
+	}
+	return "", fmt.Errorf("invalid recurrence type: %s", value)
+}
+
+// formatFieldChanges describes changes as "field old → new" pairs
+func formatFieldChanges(changes []models.FieldChange) string {
+	parts := make([]string, 0, len(changes))
+	for _, ch := range changes {
+		// Show which parts of the recurrence changed rather than the whole rule
+		if oldRec, ok := ch.Old.(models.Recurrence); ok {
+			parts = append(parts, formatFieldChanges(models.DiffFields(oldRec, ch.New.(models.Recurrence))))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %s → %s", ch.Field, formatFieldValue(ch.Old), formatFieldValue(ch.New)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatFieldValue(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "none"
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.String && rv.String() == "" {
+		return `""`
+	}
+	return fmt.Sprint(rv.Interface())
+}
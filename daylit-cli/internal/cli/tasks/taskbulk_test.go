@@ -0,0 +1,168 @@
+package tasks
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+)
+
+func setupTestDB(t *testing.T) (*cli.Context, func()) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "test.db")
+
+	store := sqlite.NewStore(dbPath)
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+
+	ctx := &cli.Context{
+		Store:     store,
+		Scheduler: scheduler.New(),
+	}
+
+	cleanup := func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("failed to close store: %v", err)
+		}
+	}
+
+	return ctx, cleanup
+}
+
+func addBulkTestTasks(t *testing.T, ctx *cli.Context) {
+	t.Helper()
+	tasks := []models.Task{
+		{ID: "groceries", Name: "Groceries", Kind: constants.TaskKindFlexible, DurationMin: 45, Recurrence: models.Recurrence{Type: constants.RecurrenceWeekly, WeekdayMask: []time.Weekday{time.Saturday}}, Priority: 3, Active: true, Context: "errands"},
+		{ID: "pharmacy", Name: "Pharmacy", Kind: constants.TaskKindFlexible, DurationMin: 20, Recurrence: models.Recurrence{Type: constants.RecurrenceAdHoc}, Priority: 2, Active: false, Context: "errands"},
+		{ID: "reading", Name: "Reading", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: models.Recurrence{Type: constants.RecurrenceNDays, IntervalDays: 3}, Priority: 3, Active: true},
+	}
+	for _, task := range tasks {
+		if err := ctx.Store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+	}
+}
+
+func TestTaskBulkCmd_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     TaskBulkCmd
+		wantErr bool
+	}{
+		{name: "set with filter", cmd: TaskBulkCmd{Context: "errands", Set: []string{"priority=4"}}},
+		{name: "delete all", cmd: TaskBulkCmd{All: true, Delete: true}},
+		{name: "no operation", cmd: TaskBulkCmd{Context: "errands"}, wantErr: true},
+		{name: "no filter", cmd: TaskBulkCmd{Set: []string{"priority=4"}}, wantErr: true},
+		{name: "bad recurrence filter", cmd: TaskBulkCmd{Recurrence: "hourly", Delete: true}, wantErr: true},
+		{name: "missing value", cmd: TaskBulkCmd{All: true, Set: []string{"priority"}}, wantErr: true},
+		{name: "unknown key", cmd: TaskBulkCmd{All: true, Set: []string{"colour=red"}}, wantErr: true},
+		{name: "rename", cmd: TaskBulkCmd{All: true, Set: []string{"name=Same"}}, wantErr: true},
+		{name: "bad number", cmd: TaskBulkCmd{All: true, Set: []string{"interval=two"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate()
+			if tt.wantErr && err == nil {
+				t.Error("expected error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTaskBulkCmd_Set(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+	addBulkTestTasks(t, ctx)
+
+	// A dry run saves nothing
+	dry := &TaskBulkCmd{Context: "errands", Set: []string{"priority=4"}, DryRun: true}
+	if err := dry.Run(ctx); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	task, _ := ctx.Store.GetTask("groceries")
+	if task.Priority != 3 {
+		t.Errorf("dry run changed priority to %d", task.Priority)
+	}
+
+	cmd := &TaskBulkCmd{Context: "errands", Set: []string{"priority=4"}}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("bulk set failed: %v", err)
+	}
+	for id, want := range map[string]int{"groceries": 4, "pharmacy": 4, "reading": 3} {
+		task, err := ctx.Store.GetTask(id)
+		if err != nil {
+			t.Fatalf("failed to get task %s: %v", id, err)
+		}
+		if task.Priority != want {
+			t.Errorf("%s priority = %d, want %d", id, task.Priority, want)
+		}
+	}
+
+	nDays := &TaskBulkCmd{Recurrence: "n_days", Set: []string{"interval=2"}}
+	if err := nDays.Run(ctx); err != nil {
+		t.Fatalf("bulk set interval failed: %v", err)
+	}
+	if task, _ := ctx.Store.GetTask("reading"); task.Recurrence.IntervalDays != 2 {
+		t.Errorf("reading interval = %d, want 2", task.Recurrence.IntervalDays)
+	}
+	if task, _ := ctx.Store.GetTask("groceries"); task.Recurrence.IntervalDays == 2 {
+		t.Error("weekly task should not have been changed")
+	}
+}
+
+func TestTaskBulkCmd_Delete(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+	addBulkTestTasks(t, ctx)
+
+	dry := &TaskBulkCmd{Inactive: true, Delete: true, DryRun: true}
+	if err := dry.Run(ctx); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if tasks, _ := ctx.Store.GetAllTasks(); len(tasks) != 3 {
+		t.Fatalf("dry run deleted tasks: %d left", len(tasks))
+	}
+
+	cmd := &TaskBulkCmd{Inactive: true, Delete: true}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("bulk delete failed: %v", err)
+	}
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		t.Fatalf("failed to get tasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks left, got %d", len(tasks))
+	}
+	for _, task := range tasks {
+		if task.ID == "pharmacy" {
+			t.Error("inactive task was not deleted")
+		}
+	}
+}
+
+func TestTaskBulkCmd_InvalidResultSavesNothing(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+	addBulkTestTasks(t, ctx)
+
+	// Groceries can take the change, but the ad-hoc Pharmacy task has no
+	// weekdays to become weekly with, so neither is saved
+	cmd := &TaskBulkCmd{Context: "errands", Set: []string{"recurrence=weekly", "priority=5"}}
+	if err := cmd.Run(ctx); err == nil {
+		t.Fatal("expected error for weekly recurrence without weekdays")
+	}
+	if task, _ := ctx.Store.GetTask("groceries"); task.Priority != 3 {
+		t.Errorf("groceries priority = %d, want 3", task.Priority)
+	}
+}
@@ -4,23 +4,63 @@ import (
 	"fmt"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 )
 
 type TaskDeleteCmd struct {
-	ID string `arg:"" help:"Task ID to delete."`
+	ID          string `arg:"" help:"Task ID or name to delete."`
+	RemoveSlots bool   `help:"Also remove the task's upcoming slots from accepted plans without asking, whatever task_delete_slots says." name:"remove-slots" xor:"slots"`
+	Force       bool   `help:"Delete the task even though accepted plans have upcoming slots for it, leaving them in place, whatever task_delete_slots says." xor:"slots"`
 }
 
 func (c *TaskDeleteCmd) Run(ctx *cli.Context) error {
-	// Check if task exists first
-	task, err := ctx.Store.GetTask(c.ID)
+	task, err := ctx.ResolveTask(c.ID, cli.LiveTasks)
 	if err != nil {
-		return fmt.Errorf("failed to find task with ID %s: %w", c.ID, err)
+		return err
+	}
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	// Slots of a deleted task are left without a task in the plan, so
+	// task_delete_slots decides what happens to them unless a flag does
+	remove := c.RemoveSlots || (!c.Force && settings.TaskDeleteSlots == constants.TaskDeleteSlotsRemove)
+	keep := c.Force || (!c.RemoveSlots && settings.TaskDeleteSlots == constants.TaskDeleteSlotsKeep)
+	if !remove && !keep {
+		slots, err := ctx.UpcomingSlots(func(slot models.Slot) bool { return slot.TaskID == task.ID })
+		if err != nil {
+			return err
+		}
+		if len(slots) > 0 {
+			fmt.Printf("%s has %d upcoming slot(s) in accepted plans:\n", task.Name, len(slots))
+			for _, ps := range slots {
+				fmt.Printf("  %s  %s–%s\n", ps.Date, ps.Slot.Start, ps.Slot.End)
+			}
+			if !cli.StdinIsTerminal() {
+				return fmt.Errorf("task not deleted: use --remove-slots to remove its slots too, or --force to leave them in the plans (or set 'daylit settings --task-delete-slots')")
+			}
+			ok, err := ctx.Confirm("Remove them and delete the task?")
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Task not deleted.")
+				return nil
+			}
+			remove = true
+		}
 	}
 
-	if err := ctx.Store.DeleteTask(c.ID); err != nil {
+	removed, err := ctx.Store.DeleteTaskCascade(task.ID, remove, ctx.Now())
+	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
 
-	fmt.Printf("Deleted task: %s (ID: %s)\n", task.Name, c.ID)
+	fmt.Printf("Deleted task: %s (ID: %s)\n", task.Name, task.ID)
+	if removed > 0 {
+		fmt.Printf("Removed %d slot(s) from the plans ('daylit restore task' brings them back with the task).\n", removed)
+	}
 	return nil
 }
@@ -0,0 +1,112 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+func TestTaskDeleteAndRestoreByName(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+	addBulkTestTasks(t, ctx)
+
+	if err := (&TaskDeleteCmd{ID: "grocries"}).Run(ctx); err != nil {
+		t.Fatalf("delete by misspelled name failed: %v", err)
+	}
+	if _, err := ctx.Store.GetTask("groceries"); err == nil {
+		t.Fatal("groceries should be deleted")
+	}
+
+	if err := (&TaskRestoreCmd{ID: "Groceries"}).Run(ctx); err != nil {
+		t.Fatalf("restore by name failed: %v", err)
+	}
+	if _, err := ctx.Store.GetTask("groceries"); err != nil {
+		t.Errorf("groceries should be restored: %v", err)
+	}
+
+	if err := (&TaskEditCmd{ID: "read", Priority: new(int)}).Run(ctx); err == nil {
+		t.Error("expected error for invalid priority")
+	}
+}
+
+func TestTaskDeleteCmd_UpcomingSlots(t *testing.T) {
+	// Write has a slot at 10:30, after the 10:00 the test runs at
+	ctx, cleanup := setupImpactTest(t)
+	defer cleanup()
+
+	// Without a terminal to ask on, deletion is refused
+	if err := (&TaskDeleteCmd{ID: "write"}).Run(ctx); err == nil {
+		t.Fatal("expected deleting a task with upcoming slots to fail")
+	}
+	if _, err := ctx.Store.GetTask("write"); err != nil {
+		t.Fatalf("write should not be deleted: %v", err)
+	}
+
+	// Read's only slot is done, so it goes without asking
+	if err := (&TaskDeleteCmd{ID: "read"}).Run(ctx); err != nil {
+		t.Fatalf("delete read failed: %v", err)
+	}
+
+	if err := (&TaskDeleteCmd{ID: "write", RemoveSlots: true}).Run(ctx); err != nil {
+		t.Fatalf("delete with --remove-slots failed: %v", err)
+	}
+	if err := (&TaskDeleteCmd{ID: "email", Force: true}).Run(ctx); err != nil {
+		t.Fatalf("delete with --force failed: %v", err)
+	}
+
+	plan, err := ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	left := make(map[string]bool)
+	for _, slot := range plan.Slots {
+		left[slot.TaskID] = true
+	}
+	if left["write"] || !left["email"] || !left["read"] {
+		t.Errorf("expected write's slot removed and the others kept, got %+v", plan.Slots)
+	}
+}
+
+func TestTaskDeleteCmd_SlotSetting(t *testing.T) {
+	ctx, cleanup := setupImpactTest(t)
+	defer cleanup()
+
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.TaskDeleteSlots = constants.TaskDeleteSlotsRemove
+	if err := ctx.Store.SaveSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	// The setting answers for the terminal there is no one to ask on
+	if err := (&TaskDeleteCmd{ID: "walk"}).Run(ctx); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	// --force still leaves the slots
+	if err := (&TaskDeleteCmd{ID: "email", Force: true}).Run(ctx); err != nil {
+		t.Fatalf("delete with --force failed: %v", err)
+	}
+
+	plan, err := ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	left := make(map[string]bool)
+	for _, slot := range plan.Slots {
+		left[slot.TaskID] = true
+	}
+	if left["walk"] || !left["email"] {
+		t.Errorf("expected walk's slot removed and email's kept, got %+v", plan.Slots)
+	}
+
+	// Restoring the task puts its slot back
+	if err := ctx.Store.RestoreTask("walk"); err != nil {
+		t.Fatal(err)
+	}
+	if plan, err = ctx.Store.GetPlan("2025-06-02"); err != nil || len(plan.Slots) != 5 {
+		t.Errorf("expected 5 slots after restoring walk, got %+v (%v)", plan.Slots, err)
+	}
+}
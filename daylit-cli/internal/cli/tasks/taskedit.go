@@ -2,14 +2,16 @@ package tasks
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
 )
 
 type TaskEditCmd struct {
-	ID               string  `arg:"" help:"Task ID."`
+	ID               string  `arg:"" help:"Task ID or name."`
 	Name             *string `help:"New task name."`
 	Duration         *int    `short:"d" help:"New duration in minutes."`
 	Recurrence       *string `short:"r" help:"New recurrence type (daily|weekly|n_days|ad_hoc|monthly_date|monthly_day|yearly|weekdays)."`
@@ -19,20 +21,52 @@ type TaskEditCmd struct {
 	Month            *int    `help:"New month (1-12) for yearly recurrence."`
 	WeekOccurrence   *int    `help:"New week occurrence for monthly_day recurrence (-1=last, 1=first, 2=second, etc.)."`
 	DayOfWeekInMonth *string `help:"New day of week for monthly_day recurrence (e.g., 'monday', 'friday')."`
+	Until            *string `help:"New last date the task recurs on (YYYY-MM-DD, empty for no end date)."`
+	Count            *int    `help:"New number of occurrences to stop after, counted from today (0 for no limit)."`
 	Earliest         *string `short:"s" help:"New earliest start time (HH:MM)."`
 	Latest           *string `short:"e" help:"New latest end time (HH:MM)."`
 	FixedStart       *string `short:"S" help:"New fixed start time for appointments (HH:MM)."`
 	FixedEnd         *string `short:"E" help:"New fixed end time for appointments (HH:MM)."`
+	Prep             *int    `help:"New minutes to get ready before an appointment (0 for none)."`
+	Travel           *int    `help:"New minutes to travel to and from an appointment (0 for none)."`
 	Priority         *int    `short:"p" help:"New priority (1-5)."`
 	Active           *bool   `help:"Set active status."`
+	Project          *string `short:"P" help:"New project name (empty to remove the task from its project)."`
+	Context          *string `short:"c" help:"New context (empty to let the task fit any context)."`
+	Pool             *string `help:"New task pool name (empty to take the task out of its pool)."`
+	NiceToHave       *bool   `help:"Set whether the task is only scheduled in time left over by the other tasks." name:"nice-to-have"`
+	Leisure          *bool   `help:"Set whether the task's blocks count as free time."`
+	Energy           *string `help:"New energy the task takes (low|medium|high, empty to clear)."`
+	StartNotify      *bool   `help:"Enable or disable the notification when the task's blocks start."`
+	NotifyOffset     *int    `help:"New start notification lead time in minutes (-1 to use the block start offset setting)."`
+	NotifyMessage    *string `help:"New start notification text (empty for the default)."`
+	NotifyUrgency    *string `help:"New start notification urgency (low|normal|critical, empty for normal)."`
+	NotifySound      *string `help:"New start notification sound (empty for the default)."`
+	Reflow           bool    `help:"Reflow today's accepted plan to fit the change without asking." xor:"plan"`
+	KeepPlan         bool    `help:"Leave today's accepted plan as it is after the change." name:"keep-plan" xor:"plan"`
 }
 
 func (c *TaskEditCmd) Run(ctx *cli.Context) error {
-	task, err := ctx.Store.GetTask(c.ID)
+	task, err := ctx.ResolveTask(c.ID, cli.LiveTasks)
 	if err != nil {
-		return fmt.Errorf("failed to find task: %w", err)
+		return err
 	}
 
+	before := task
+	if err := c.apply(ctx, &task); err != nil {
+		return err
+	}
+
+	if err := ctx.Store.UpdateTask(task); err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	fmt.Printf("Task updated: %s\n", task.Name)
+	return c.updatePlan(ctx, before, task)
+}
+
+// apply makes the requested changes to task and validates the result
+func (c *TaskEditCmd) apply(ctx *cli.Context, task *models.Task) error {
 	if c.Name != nil {
 		task.Name = *c.Name
 	}
@@ -51,6 +85,62 @@ func (c *TaskEditCmd) Run(ctx *cli.Context) error {
 	if c.Active != nil {
 		task.Active = *c.Active
 	}
+	if c.Project != nil {
+		projectID, err := resolveProject(ctx, *c.Project)
+		if err != nil {
+			return err
+		}
+		task.ProjectID = projectID
+	}
+	if c.Pool != nil {
+		poolID, err := resolvePool(ctx, *c.Pool)
+		if err != nil {
+			return err
+		}
+		task.PoolID = poolID
+	}
+	if c.NiceToHave != nil {
+		task.NiceToHave = *c.NiceToHave
+	}
+	if c.Leisure != nil {
+		task.Leisure = *c.Leisure
+	}
+	if c.Prep != nil {
+		task.PrepMin = *c.Prep
+	}
+	if c.Travel != nil {
+		task.TravelMin = *c.Travel
+	}
+	if c.Context != nil {
+		task.Context = models.NormalizeContext(*c.Context)
+	}
+	if c.Energy != nil {
+		energy, err := parseEnergyBand(*c.Energy)
+		if err != nil {
+			return err
+		}
+		task.EnergyBand = energy
+	}
+	if c.StartNotify != nil {
+		task.NotifyStartDisabled = !*c.StartNotify
+	}
+	if c.NotifyOffset != nil {
+		if *c.NotifyOffset < 0 {
+			task.NotifyOffsetMin = nil
+		} else {
+			offset := *c.NotifyOffset
+			task.NotifyOffsetMin = &offset
+		}
+	}
+	if c.NotifyMessage != nil {
+		task.NotifyMessage = *c.NotifyMessage
+	}
+	if c.NotifyUrgency != nil {
+		task.NotifyUrgency = *c.NotifyUrgency
+	}
+	if c.NotifySound != nil {
+		task.NotifySound = *c.NotifySound
+	}
 
 	// Update recurrence
 	if c.Recurrence != nil {
@@ -120,6 +210,27 @@ func (c *TaskEditCmd) Run(ctx *cli.Context) error {
 		task.Recurrence.DayOfWeekInMonth = wd
 	}
 
+	if c.Until != nil {
+		if *c.Until != "" {
+			if _, err := time.Parse(constants.DateFormat, *c.Until); err != nil {
+				return fmt.Errorf("invalid --until date %q (expected YYYY-MM-DD)", *c.Until)
+			}
+		}
+		task.Recurrence.Until = *c.Until
+	}
+
+	// A new count starts over from today
+	if c.Count != nil {
+		if *c.Count < 0 {
+			return fmt.Errorf("--count cannot be negative")
+		}
+		task.Recurrence.Count = *c.Count
+		task.Recurrence.Start = ""
+		if *c.Count > 0 {
+			task.Recurrence.Start = ctx.Now().Format(constants.DateFormat)
+		}
+	}
+
 	// Update time constraints
 	if c.Earliest != nil {
 		if _, err := utils.ParseTime(*c.Earliest); err != nil {
@@ -156,11 +267,5 @@ func (c *TaskEditCmd) Run(ctx *cli.Context) error {
 	if err := task.Validate(); err != nil {
 		return fmt.Errorf("invalid task: %w", err)
 	}
-
-	if err := ctx.Store.UpdateTask(task); err != nil {
-		return fmt.Errorf("failed to update task: %w", err)
-	}
-
-	fmt.Printf("Task updated: %s\n", task.Name)
 	return nil
 }
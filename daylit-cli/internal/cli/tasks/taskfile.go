@@ -0,0 +1,246 @@
+package tasks
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// taskSpec is one task in a file read by 'task add --from-file'. The fields
+// mirror the 'task add' flags and take the same defaults.
+type taskSpec struct {
+	Name             string      `yaml:"name"`
+	Duration         int         `yaml:"duration"`
+	Recurrence       string      `yaml:"recurrence"`
+	Interval         int         `yaml:"interval"`
+	Weekdays         weekdayList `yaml:"weekdays"`
+	MonthDay         int         `yaml:"month_day"`
+	Month            int         `yaml:"month"`
+	WeekOccurrence   int         `yaml:"week_occurrence"`
+	DayOfWeekInMonth string      `yaml:"day_of_week_in_month"`
+	Until            string      `yaml:"until"`
+	Count            int         `yaml:"count"`
+	Earliest         string      `yaml:"earliest"`
+	Latest           string      `yaml:"latest"`
+	FixedStart       string      `yaml:"fixed_start"`
+	FixedEnd         string      `yaml:"fixed_end"`
+	Prep             int         `yaml:"prep"`
+	Travel           int         `yaml:"travel"`
+	Priority         int         `yaml:"priority"`
+	EnergyBand       string      `yaml:"energy_band"`
+	Project          string      `yaml:"project"`
+	Context          string      `yaml:"context"`
+	NiceToHave       bool        `yaml:"nice_to_have"`
+	Leisure          bool        `yaml:"leisure"`
+	Active           *bool       `yaml:"active"`
+	StartNotify      *bool       `yaml:"start_notify"`
+	NotifyOffset     *int        `yaml:"notify_offset"`
+	NotifyMessage    string      `yaml:"notify_message"`
+	NotifyUrgency    string      `yaml:"notify_urgency"`
+	NotifySound      string      `yaml:"notify_sound"`
+}
+
+// weekdayList accepts weekdays either as a list or as a comma-separated string
+type weekdayList string
+
+func (w *weekdayList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		var days []string
+		if err := node.Decode(&days); err != nil {
+			return err
+		}
+		*w = weekdayList(strings.Join(days, ","))
+		return nil
+	}
+	var days string
+	if err := node.Decode(&days); err != nil {
+		return err
+	}
+	*w = weekdayList(days)
+	return nil
+}
+
+// task builds a new, validated task from the spec
+func (s taskSpec) task(ctx *cli.Context) (models.Task, error) {
+	cmd := TaskAddCmd{
+		Name:             s.Name,
+		Duration:         s.Duration,
+		Recurrence:       s.Recurrence,
+		Interval:         s.Interval,
+		Weekdays:         string(s.Weekdays),
+		MonthDay:         s.MonthDay,
+		Month:            s.Month,
+		WeekOccurrence:   s.WeekOccurrence,
+		DayOfWeekInMonth: s.DayOfWeekInMonth,
+		Until:            s.Until,
+		Count:            s.Count,
+		Earliest:         s.Earliest,
+		Latest:           s.Latest,
+		FixedStart:       s.FixedStart,
+		FixedEnd:         s.FixedEnd,
+		Prep:             s.Prep,
+		Travel:           s.Travel,
+		Priority:         s.Priority,
+		Project:          s.Project,
+		Context:          s.Context,
+		NiceToHave:       s.NiceToHave,
+		Leisure:          s.Leisure,
+		NoStartNotify:    s.StartNotify != nil && !*s.StartNotify,
+		NotifyOffset:     s.NotifyOffset,
+		NotifyMessage:    s.NotifyMessage,
+		NotifyUrgency:    s.NotifyUrgency,
+		NotifySound:      s.NotifySound,
+	}
+	// Same defaults as the flags
+	if cmd.Recurrence == "" {
+		cmd.Recurrence = string(constants.RecurrenceAdHoc)
+	}
+	if cmd.Interval == 0 {
+		cmd.Interval = 1
+	}
+	if cmd.Priority == 0 {
+		cmd.Priority = 3
+	}
+
+	if err := cmd.Validate(); err != nil {
+		return models.Task{}, err
+	}
+	task, err := cmd.task(ctx)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	if task.EnergyBand, err = parseEnergyBand(s.EnergyBand); err != nil {
+		return models.Task{}, fmt.Errorf("invalid energy_band %q (expected low, medium or high)", s.EnergyBand)
+	}
+	if s.Active != nil {
+		task.Active = *s.Active
+	}
+	return task, nil
+}
+
+// parseTaskFile reads task specs from YAML or JSON, either as a list or as a
+// mapping with a "tasks" list
+func parseTaskFile(r io.Reader) ([]taskSpec, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) == 0 {
+		return nil, fmt.Errorf("no tasks found")
+	}
+
+	// Decode again so unknown fields are reported rather than ignored
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var specs []taskSpec
+	if root.Content[0].Kind == yaml.MappingNode {
+		var doc struct {
+			Tasks []taskSpec `yaml:"tasks"`
+		}
+		err = dec.Decode(&doc)
+		specs = doc.Tasks
+	} else {
+		err = dec.Decode(&specs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no tasks found")
+	}
+	return specs, nil
+}
+
+// addFromFile adds every task in the file at path, or stdin for "-". Tasks
+// whose names already exist are skipped, so a checked-in task list can be
+// applied again; nothing is added if any task is invalid.
+func addFromFile(ctx *cli.Context, path string) error {
+	in := io.Reader(os.Stdin)
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	specs, err := parseTaskFile(in)
+	if err != nil {
+		return fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	existing, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	exists := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		exists[taskNameKey(t.Name)] = true
+	}
+
+	var toAdd []models.Task
+	inFile := make(map[string]bool, len(specs))
+	skipped := 0
+	for i, spec := range specs {
+		key := taskNameKey(spec.Name)
+		if key != "" && inFile[key] {
+			return fmt.Errorf("task %d: %q appears more than once", i+1, spec.Name)
+		}
+		inFile[key] = true
+		if exists[key] {
+			skipped++
+			continue
+		}
+
+		task, err := spec.task(ctx)
+		if err != nil {
+			if spec.Name == "" {
+				return fmt.Errorf("task %d: %w", i+1, err)
+			}
+			return fmt.Errorf("task %d (%s): %w", i+1, spec.Name, err)
+		}
+		toAdd = append(toAdd, task)
+	}
+
+	for _, task := range toAdd {
+		if err := ctx.Store.AddTask(task); err != nil {
+			return fmt.Errorf("failed to add task %q: %w", task.Name, err)
+		}
+		fmt.Printf("Added task: %s (ID: %s)\n", task.Name, task.ID)
+	}
+	if skipped > 0 {
+		fmt.Printf("Skipped %d task(s) that already exist\n", skipped)
+	}
+	fmt.Printf("Added %d task(s)\n", len(toAdd))
+	return nil
+}
+
+// taskNameKey normalizes a task name for duplicate detection
+func taskNameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// parseEnergyBand reads an energy band name; empty leaves the band unset
+func parseEnergyBand(s string) (constants.EnergyBand, error) {
+	switch band := constants.EnergyBand(strings.ToLower(strings.TrimSpace(s))); band {
+	case "", constants.EnergyLow, constants.EnergyMedium, constants.EnergyHigh:
+		return band, nil
+	}
+	return "", fmt.Errorf("invalid energy %q (expected low, medium or high)", s)
+}
@@ -0,0 +1,149 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+func TestParseTaskFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantNames []string
+		wantErr   bool
+	}{
+		{name: "yaml list", input: "- name: A\n  duration: 10\n- name: B\n  duration: 20\n", wantNames: []string{"A", "B"}},
+		{name: "yaml tasks key", input: "tasks:\n  - name: A\n    duration: 10\n", wantNames: []string{"A"}},
+		{name: "json list", input: `[{"name": "A", "duration": 10}]`, wantNames: []string{"A"}},
+		{name: "json tasks key", input: `{"tasks": [{"name": "A", "duration": 10}]}`, wantNames: []string{"A"}},
+		{name: "unknown field", input: "- name: A\n  colour: red\n", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+		{name: "empty list", input: "tasks: []\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs, err := parseTaskFile(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(specs) != len(tt.wantNames) {
+				t.Fatalf("got %d tasks, want %d", len(specs), len(tt.wantNames))
+			}
+			for i, name := range tt.wantNames {
+				if specs[i].Name != name {
+					t.Errorf("task %d name = %q, want %q", i, specs[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+func TestAddFromFile(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := (&TaskAddCmd{Name: "Stretch", Duration: 10, Recurrence: "daily", Interval: 1, Priority: 3}).Run(ctx); err != nil {
+		t.Fatalf("task add failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "tasks.yaml")
+	content := `
+- name: Groceries
+  duration: 45
+  recurrence: weekly
+  weekdays: [sat, sun]
+  earliest: 09:00
+  latest: "12:00"
+  context: Errands
+  energy_band: low
+- name: Dentist
+  duration: 60
+  recurrence: yearly
+  month: 3
+  month_day: 14
+  fixed_start: "10:00"
+  fixed_end: "11:00"
+  active: false
+  start_notify: false
+- name: stretch
+  duration: 15
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &TaskAddCmd{FromFile: path}
+	if err := cmd.Validate(); err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("add from file failed: %v", err)
+	}
+
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		t.Fatalf("failed to get tasks: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("expected 3 tasks, got %d", len(tasks))
+	}
+
+	for _, task := range tasks {
+		switch task.Name {
+		case "Groceries":
+			if len(task.Recurrence.WeekdayMask) != 2 || task.Recurrence.WeekdayMask[0] != time.Saturday {
+				t.Errorf("weekdays = %v, want [Saturday Sunday]", task.Recurrence.WeekdayMask)
+			}
+			if task.EarliestStart != "09:00" || task.LatestEnd != "12:00" {
+				t.Errorf("window = %s-%s, want 09:00-12:00", task.EarliestStart, task.LatestEnd)
+			}
+			if task.Context != "errands" || task.EnergyBand != constants.EnergyLow || task.Priority != 3 {
+				t.Errorf("unexpected fields: %+v", task)
+			}
+		case "Dentist":
+			if task.Kind != constants.TaskKindAppointment || task.Active || !task.NotifyStartDisabled {
+				t.Errorf("unexpected fields: %+v", task)
+			}
+		case "Stretch":
+			if task.DurationMin != 10 {
+				t.Errorf("existing task was changed: %+v", task)
+			}
+		default:
+			t.Errorf("unexpected task %q", task.Name)
+		}
+	}
+}
+
+func TestAddFromFile_InvalidTaskAddsNothing(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	content := `[{"name": "Read", "duration": 30}, {"name": "Run", "duration": 30, "recurrence": "weekly"}]`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := (&TaskAddCmd{FromFile: path}).Run(ctx); err == nil {
+		t.Fatal("expected error for weekly task without weekdays")
+	}
+	if tasks, _ := ctx.Store.GetAllTasks(); len(tasks) != 0 {
+		t.Errorf("expected no tasks, got %d", len(tasks))
+	}
+
+	if err := (&TaskAddCmd{Name: "Read", FromFile: path}).Validate(); err == nil {
+		t.Error("expected error when combining a name with --from-file")
+	}
+}
@@ -0,0 +1,206 @@
+package tasks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+)
+
+// slotMove is a slot of today's plan at its old and new times
+type slotMove struct {
+	from, to models.Slot
+}
+
+// planImpact is how today's accepted plan changes to fit an edited task
+type planImpact struct {
+	plan    models.DayPlan // The plan with the change made
+	moved   []slotMove
+	dropped []models.Slot
+}
+
+// timingChanged reports whether an edit from before to after changes when or
+// for how long the task's blocks run
+func timingChanged(before, after models.Task) bool {
+	return before.Kind != after.Kind ||
+		before.DurationMin != after.DurationMin ||
+		before.EarliestStart != after.EarliestStart ||
+		before.LatestEnd != after.LatestEnd ||
+		before.FixedStart != after.FixedStart ||
+		before.FixedEnd != after.FixedEnd ||
+		before.PrepMin != after.PrepMin ||
+		before.TravelMin != after.TravelMin
+}
+
+// editImpact works out how today's accepted plan would change to fit task,
+// edited from before. The task's blocks that haven't started yet take its new
+// length or fixed times, and the rest of the day flows around them as in
+// 'daylit reflow'; slots that are done, started, past or locked stay where
+// they are. It reports false when there is no accepted plan for today, the
+// task has no block left in it, or the plan still fits the task.
+func editImpact(ctx *cli.Context, before, task models.Task, now time.Time) (planImpact, bool, error) {
+	var impact planImpact
+	if !timingChanged(before, task) {
+		return impact, false, nil
+	}
+
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return impact, false, fmt.Errorf("failed to get settings: %w", err)
+	}
+	window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+	if err != nil {
+		return impact, false, err
+	}
+	plan, err := ctx.Store.GetPlan(now.Format(constants.DateFormat))
+	if err != nil || plan.AcceptedAt == nil {
+		return impact, false, nil
+	}
+	nowMinutes, err := window.Minutes(now.Format(constants.TimeFormat))
+	if err != nil {
+		return impact, false, err
+	}
+
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		return impact, false, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	for i := range tasks {
+		if tasks[i].ID == task.ID {
+			tasks[i] = task
+		}
+	}
+
+	var slots []scheduler.ReflowSlot
+	var deleted []models.Slot
+	original := make(map[string]models.Slot, len(plan.Slots))
+	upcoming := false
+	for _, slot := range plan.Slots {
+		if slot.DeletedAt != nil {
+			deleted = append(deleted, slot)
+			continue
+		}
+		original[slot.TaskID] = slot
+		start, err := window.Minutes(slot.Start)
+		if err != nil {
+			return impact, false, err
+		}
+		rs := scheduler.ReflowSlot{Slot: slot, NotBefore: slot.Start}
+		switch {
+		case slot.Status == constants.SlotStatusDone || slot.ActualStart != nil || start < nowMinutes || plan.Locked(slot, window):
+			rs.Pinned = true
+		case slot.TaskID == task.ID:
+			upcoming = true
+			rs.Slot = refitSlot(slot, before, task)
+		}
+		slots = append(slots, rs)
+	}
+	if !upcoming {
+		return impact, false, nil
+	}
+
+	placed, dropped, err := ctx.Scheduler.Reflow(slots, tasks, settings.DayStart, settings.DayEnd)
+	if err != nil {
+		return impact, false, err
+	}
+	for _, rs := range placed {
+		slot := rs.Slot
+		if old := original[slot.TaskID]; !rs.Pinned && (old.Start != slot.Start || old.End != slot.End) {
+			// The slot is due to be notified again at its new times
+			slot.LastNotifiedStart = nil
+			slot.LastNotifiedEnd = nil
+			impact.moved = append(impact.moved, slotMove{from: old, to: slot})
+		}
+		impact.plan.Slots = append(impact.plan.Slots, slot)
+	}
+	for _, rs := range dropped {
+		impact.dropped = append(impact.dropped, original[rs.Slot.TaskID])
+	}
+	if len(impact.moved) == 0 && len(impact.dropped) == 0 {
+		return impact, false, nil
+	}
+
+	impact.plan.Date = plan.Date
+	impact.plan.Note = plan.Note
+	impact.plan.LockedUntil = plan.LockedUntil
+	impact.plan.Slots = append(impact.plan.Slots, deleted...)
+	window.SortSlots(impact.plan.Slots)
+	return impact, true, nil
+}
+
+// refitSlot gives a block of task that was planned for it as before the
+// task's new fixed times or length
+func refitSlot(slot models.Slot, before, task models.Task) models.Slot {
+	if scheduler.IsFixedAppointment(task) {
+		slot.Start, slot.End = task.FixedStart, task.FixedEnd
+		return slot
+	}
+	if task.DurationMin == before.DurationMin {
+		return slot
+	}
+	if start, err := time.Parse(constants.TimeFormat, slot.Start); err == nil {
+		slot.End = start.Add(time.Duration(task.DurationMin) * time.Minute).Format(constants.TimeFormat)
+	}
+	return slot
+}
+
+// updatePlan shows how today's accepted plan would change to fit the edited
+// task, and saves the reflowed plan as a new revision when asked to
+func (c *TaskEditCmd) updatePlan(ctx *cli.Context, before, task models.Task) error {
+	impact, ok, err := editImpact(ctx, before, task, ctx.Now())
+	if err != nil || !ok {
+		return err
+	}
+
+	fmt.Println("\nToday's plan no longer fits the task. Reflowing it would change:")
+	for _, m := range impact.moved {
+		fmt.Printf("  %s–%s → %s–%s  %s\n", m.from.Start, m.from.End, m.to.Start, m.to.End, slotTaskName(ctx, m.to))
+	}
+	for _, slot := range impact.dropped {
+		fmt.Printf("  Dropped: %s–%s  %s (no longer fits in the day)\n", slot.Start, slot.End, slotTaskName(ctx, slot))
+	}
+
+	switch {
+	case c.KeepPlan:
+		fmt.Println("\nToday's plan was left as it is.")
+		return nil
+	case !c.Reflow:
+		if !cli.StdinIsTerminal() {
+			fmt.Println("\nToday's plan was left as it is; use --reflow to update it.")
+			return nil
+		}
+		ok, err := ctx.Confirm("\nReflow today's plan?")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Today's plan was left as it is.")
+			return nil
+		}
+	}
+
+	plan := impact.plan
+	accepted := ctx.Now().UTC().Format(time.RFC3339)
+	plan.AcceptedAt = &accepted
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		return err
+	}
+	saved, err := ctx.Store.GetPlan(plan.Date)
+	if err != nil {
+		fmt.Println("Today's plan was reflowed.")
+		return nil
+	}
+	fmt.Printf("Today's plan was reflowed and saved as revision %d.\n", saved.Revision)
+	return nil
+}
+
+// slotTaskName is the name of slot's task
+func slotTaskName(ctx *cli.Context, slot models.Slot) string {
+	if task, err := ctx.Store.GetTask(slot.TaskID); err == nil {
+		return task.Name
+	}
+	return "(unknown task)"
+}
@@ -0,0 +1,164 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// setupImpactTest adds tasks and today's accepted plan, at 10:00 with Read
+// already done
+func setupImpactTest(t *testing.T) (*cli.Context, func()) {
+	t.Helper()
+	ctx, cleanup := setupTestDB(t)
+	ctx.Clock = clock.Fixed(time.Date(2025, 6, 2, 10, 0, 0, 0, time.Local))
+
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	tasks := []models.Task{
+		{ID: "read", Name: "Read", Kind: constants.TaskKindFlexible, DurationMin: 60, Recurrence: daily, Priority: 3, Active: true},
+		{ID: "write", Name: "Write", Kind: constants.TaskKindFlexible, DurationMin: 60, Recurrence: daily, Priority: 3, Active: true},
+		{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: daily, Priority: 3, Active: true},
+		{ID: "dentist", Name: "Dentist", Kind: constants.TaskKindAppointment, DurationMin: 60, FixedStart: "12:00", FixedEnd: "13:00", Recurrence: daily, Priority: 3, Active: true},
+		{ID: "walk", Name: "Walk", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: daily, Priority: 3, Active: true},
+	}
+	for _, task := range tasks {
+		if err := ctx.Store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+	}
+
+	accepted := "2025-06-02T06:00:00Z"
+	plan := models.DayPlan{
+		Date:       "2025-06-02",
+		AcceptedAt: &accepted,
+		Slots: []models.Slot{
+			{Start: "09:00", End: "10:00", TaskID: "read", Status: constants.SlotStatusDone},
+			{Start: "10:30", End: "11:30", TaskID: "write", Status: constants.SlotStatusAccepted},
+			{Start: "11:30", End: "12:00", TaskID: "email", Status: constants.SlotStatusAccepted},
+			{Start: "12:00", End: "13:00", TaskID: "dentist", Status: constants.SlotStatusAccepted},
+			{Start: "13:00", End: "13:30", TaskID: "walk", Status: constants.SlotStatusAccepted},
+		},
+	}
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+	return ctx, cleanup
+}
+
+func TestEditImpact(t *testing.T) {
+	ctx, cleanup := setupImpactTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name   string
+		edit   func(task *models.Task)
+		taskID string
+		want   map[string]string // Task ID to new slot times; nil for no impact
+	}{
+		{
+			name:   "longer task pushes the rest around the appointment",
+			taskID: "write",
+			edit:   func(task *models.Task) { task.DurationMin = 90 },
+			want:   map[string]string{"write": "10:30-12:00", "email": "13:00-13:30", "walk": "13:30-14:00"},
+		},
+		{
+			name:   "shorter task leaves a gap",
+			taskID: "write",
+			edit:   func(task *models.Task) { task.DurationMin = 45 },
+			want:   map[string]string{"write": "10:30-11:15"},
+		},
+		{
+			name:   "new window the slot still fits",
+			taskID: "write",
+			edit:   func(task *models.Task) { task.EarliestStart = "10:00" },
+		},
+		{
+			name:   "moved appointment",
+			taskID: "dentist",
+			edit:   func(task *models.Task) { task.FixedStart, task.FixedEnd = "11:00", "12:00" },
+			want:   map[string]string{"dentist": "11:00-12:00", "write": "12:00-13:00", "email": "13:00-13:30", "walk": "13:30-14:00"},
+		},
+		{
+			name:   "done slots don't change",
+			taskID: "read",
+			edit:   func(task *models.Task) { task.DurationMin = 90 },
+		},
+		{
+			name:   "priority doesn't affect the plan",
+			taskID: "walk",
+			edit:   func(task *models.Task) { task.Priority = 1 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, err := ctx.Store.GetTask(tt.taskID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			task := before
+			tt.edit(&task)
+
+			impact, ok, err := editImpact(ctx, before, task, ctx.Now())
+			if err != nil {
+				t.Fatalf("editImpact failed: %v", err)
+			}
+			if ok != (tt.want != nil) {
+				t.Fatalf("impact = %v, want %v (%+v)", ok, tt.want != nil, impact.moved)
+			}
+			got := make(map[string]string)
+			for _, m := range impact.moved {
+				got[m.to.TaskID] = m.to.Start + "-" + m.to.End
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("moved %v, want %v", got, tt.want)
+			}
+			for id, times := range tt.want {
+				if got[id] != times {
+					t.Errorf("%s moved to %q, want %q", id, got[id], times)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskEditCmd_ReflowsTodaysPlan(t *testing.T) {
+	ctx, cleanup := setupImpactTest(t)
+	defer cleanup()
+
+	// Without a terminal to ask on, the plan is left alone
+	duration := 90
+	if err := (&TaskEditCmd{ID: "write", Duration: &duration}).Run(ctx); err != nil {
+		t.Fatalf("edit failed: %v", err)
+	}
+	plan, err := ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Revision != 1 {
+		t.Fatalf("plan revision = %d, want it left at 1", plan.Revision)
+	}
+
+	duration = 120
+	if err := (&TaskEditCmd{ID: "write", Duration: &duration, Reflow: true}).Run(ctx); err != nil {
+		t.Fatalf("edit failed: %v", err)
+	}
+	plan, err = ctx.Store.GetPlan("2025-06-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Revision != 2 || plan.AcceptedAt == nil {
+		t.Fatalf("expected an accepted revision 2, got revision %d", plan.Revision)
+	}
+	// Two hours no longer fit before the appointment
+	want := map[string]string{"read": "09:00-10:00", "dentist": "12:00-13:00", "write": "13:00-15:00", "email": "15:00-15:30", "walk": "15:30-16:00"}
+	for _, slot := range plan.Slots {
+		if got := slot.Start + "-" + slot.End; got != want[slot.TaskID] {
+			t.Errorf("%s = %s, want %s", slot.TaskID, got, want[slot.TaskID])
+		}
+	}
+}
@@ -2,9 +2,12 @@ package tasks
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/julianstephens/daylit/daylit-cli/internal/autoplan"
 	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 )
 
 type TaskListCmd struct {
@@ -22,6 +25,21 @@ func (c *TaskListCmd) Run(ctx *cli.Context) error {
 		return nil
 	}
 
+	projects, err := ctx.Store.GetAllProjects()
+	if err != nil {
+		return fmt.Errorf("failed to get projects: %w", err)
+	}
+	projectNames := make(map[string]string, len(projects))
+	for _, p := range projects {
+		projectNames[p.ID] = p.Name
+	}
+
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+	aging := autoplan.Aging(ctx.Store, settings, tasks, ctx.Now().Format(constants.DateFormat))
+
 	fmt.Println("Tasks:")
 	for _, task := range tasks {
 		if c.ActiveOnly && !task.Active {
@@ -39,15 +57,51 @@ func (c *TaskListCmd) Run(ctx *cli.Context) error {
 		}
 
 		recStr := cli.FormatRecurrence(task.Recurrence)
-		fmt.Printf("  [%s] %s%s - %dm (%s, priority %d)\n",
-			status, task.Name, idStr, task.DurationMin, recStr, task.Priority)
+		projectStr := ""
+		if name, ok := projectNames[task.ProjectID]; ok {
+			projectStr = ", project " + name
+		}
+		contextStr := ""
+		if task.Context != "" {
+			contextStr = " @" + task.Context
+		}
+		fmt.Printf("  [%s] %s%s%s - %dm (%s, priority %d%s)\n",
+			status, task.Name, contextStr, idStr, task.DurationMin, recStr, task.Priority, projectStr)
 
 		if task.Kind == constants.TaskKindAppointment {
 			fmt.Printf("      Fixed: %s - %s\n", task.FixedStart, task.FixedEnd)
 		} else if task.EarliestStart != "" || task.LatestEnd != "" {
 			fmt.Printf("      Window: %s - %s\n", task.EarliestStart, task.LatestEnd)
 		}
+		if a, ok := aging[task.ID]; ok {
+			fmt.Printf("      Aging: priority %d → %d after %d days left out of the plan\n", task.Priority, a.Priority(task), a.MissedDays)
+		}
+		if notifyStr := formatNotifyPrefs(task); notifyStr != "" {
+			fmt.Printf("      Notify: %s\n", notifyStr)
+		}
 	}
 
 	return nil
 }
+
+// formatNotifyPrefs describes a task's start notification overrides, or
+// returns an empty string if it uses the global settings
+func formatNotifyPrefs(task models.Task) string {
+	if task.NotifyStartDisabled {
+		return "start notification off"
+	}
+	var parts []string
+	if task.NotifyOffsetMin != nil {
+		parts = append(parts, fmt.Sprintf("%d min before start", *task.NotifyOffsetMin))
+	}
+	if task.NotifyMessage != "" {
+		parts = append(parts, fmt.Sprintf("%q", task.NotifyMessage))
+	}
+	if task.NotifyUrgency != "" && task.NotifyUrgency != constants.NotificationUrgencyNormal {
+		parts = append(parts, task.NotifyUrgency)
+	}
+	if task.NotifySound != "" {
+		parts = append(parts, "sound "+task.NotifySound)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -7,14 +7,19 @@ import (
 )
 
 type TaskRestoreCmd struct {
-	ID string `arg:"" help:"Task ID to restore."`
+	ID string `arg:"" help:"Task ID or name to restore."`
 }
 
 func (c *TaskRestoreCmd) Run(ctx *cli.Context) error {
-	if err := ctx.Store.RestoreTask(c.ID); err != nil {
+	task, err := ctx.ResolveTask(c.ID, cli.DeletedTasks)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Store.RestoreTask(task.ID); err != nil {
 		return fmt.Errorf("failed to restore task: %w", err)
 	}
 
-	fmt.Printf("Restored task with ID: %s\n", c.ID)
+	fmt.Printf("Restored task: %s (ID: %s)\n", task.Name, task.ID)
 	return nil
 }
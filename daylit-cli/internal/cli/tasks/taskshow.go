@@ -0,0 +1,249 @@
+package tasks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+// maxTrendPoints caps how many EMA values the duration trend shows
+const maxTrendPoints = 6
+
+type TaskShowCmd struct {
+	Task  string `arg:"" help:"Task ID or name."`
+	Limit int    `short:"n" help:"Number of recent slots to show." default:"10"`
+}
+
+func (c *TaskShowCmd) Validate() error {
+	if c.Limit < 0 {
+		return fmt.Errorf("limit cannot be negative")
+	}
+	return nil
+}
+
+func (c *TaskShowCmd) Run(ctx *cli.Context) error {
+	task, err := ctx.ResolveTask(c.Task, cli.AllTasks)
+	if err != nil {
+		return err
+	}
+
+	history, err := ctx.Store.GetTaskSlotHistory(task.ID, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get slot history: %w", err)
+	}
+
+	printTaskDetails(ctx, task)
+	fmt.Println()
+
+	if len(history) == 0 {
+		fmt.Println("Not scheduled yet")
+		return nil
+	}
+
+	summary := summarizeSlots(history)
+	fmt.Printf("History: scheduled on %d day(s), %d done, %d skipped\n", summary.days, summary.done, summary.skipped)
+	fmt.Printf("Feedback: %d on track, %d too much, %d unnecessary\n",
+		summary.ratings[constants.FeedbackOnTrack], summary.ratings[constants.FeedbackTooMuch],
+		summary.ratings[constants.FeedbackUnnecessary])
+	if trend := durationTrend(history); len(trend) > 0 {
+		fmt.Printf("Duration trend (EMA of on-track blocks): %s\n", formatTrend(trend))
+	}
+
+	recent := history
+	if c.Limit > 0 && len(recent) > c.Limit {
+		recent = recent[:c.Limit]
+	}
+	fmt.Printf("\nLast %d slot(s):\n", len(recent))
+	for _, e := range recent {
+		line := fmt.Sprintf("  %s  %s–%s  %-8s", e.Date, e.Start, e.End, e.Status)
+		if e.Rating != "" {
+			line += fmt.Sprintf("  %-11s", e.Rating)
+		}
+		if e.Note != "" {
+			line += fmt.Sprintf("  %q", e.Note)
+		}
+		if e.Revision > 1 {
+			line += fmt.Sprintf("  (revision %d)", e.Revision)
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+	return nil
+}
+
+func printTaskDetails(ctx *cli.Context, task models.Task) {
+	status := "active"
+	if task.DeletedAt != nil {
+		status = "deleted"
+	} else if !task.Active {
+		status = "inactive"
+	}
+
+	fmt.Printf("%s (ID: %s)\n", task.Name, task.ID)
+	fmt.Printf("  Status:      %s\n", status)
+	fmt.Printf("  Kind:        %s\n", task.Kind)
+	fmt.Printf("  Duration:    %dm", task.DurationMin)
+	if task.AvgActualDurationMin > 0 {
+		fmt.Printf(" (average actual %.0fm)", task.AvgActualDurationMin)
+	}
+	fmt.Println()
+	fmt.Printf("  Recurrence:  %s\n", cli.FormatRecurrence(task.Recurrence))
+	if end, ok := utils.RecurrenceEnd(task); ok {
+		if end < ctx.Now().Format(constants.DateFormat) {
+			fmt.Printf("  Ends:        %s (ended)\n", end)
+		} else {
+			fmt.Printf("  Ends:        %s\n", end)
+		}
+	}
+	if task.Kind == constants.TaskKindAppointment {
+		fmt.Printf("  Fixed:       %s - %s\n", task.FixedStart, task.FixedEnd)
+		if task.PrepMin > 0 || task.TravelMin > 0 {
+			fmt.Printf("  Padding:     %dm prep, %dm travel each way\n", task.PrepMin, task.TravelMin)
+		}
+	} else if task.EarliestStart != "" || task.LatestEnd != "" {
+		fmt.Printf("  Window:      %s - %s\n", task.EarliestStart, task.LatestEnd)
+	}
+	if task.NiceToHave {
+		fmt.Printf("  Priority:    %d (nice to have)\n", task.Priority)
+	} else {
+		fmt.Printf("  Priority:    %d\n", task.Priority)
+	}
+	if task.Leisure {
+		fmt.Println("  Leisure:     yes (counts as free time)")
+	}
+	if task.EnergyBand != "" {
+		fmt.Printf("  Energy:      %s\n", task.EnergyBand)
+	}
+	if task.ProjectID != "" {
+		fmt.Printf("  Project:     %s\n", projectName(ctx, task.ProjectID))
+	}
+	if task.PoolID != "" {
+		fmt.Printf("  Pool:        %s\n", poolName(ctx, task.PoolID))
+	}
+	if task.Context != "" {
+		fmt.Printf("  Context:     %s\n", task.Context)
+	}
+	if notifyStr := formatNotifyPrefs(task); notifyStr != "" {
+		fmt.Printf("  Notify:      %s\n", notifyStr)
+	}
+	lastDone := task.LastDone
+	if lastDone == "" {
+		lastDone = "never"
+	}
+	fmt.Printf("  Last done:   %s\n", lastDone)
+	fmt.Printf("  Streak:      %d\n", task.SuccessStreak)
+}
+
+// projectName returns the name of a project, falling back to its ID
+func projectName(ctx *cli.Context, id string) string {
+	projects, err := ctx.Store.GetAllProjects()
+	if err != nil {
+		return id
+	}
+	for _, p := range projects {
+		if p.ID == id {
+			return p.Name
+		}
+	}
+	return id
+}
+
+// poolName returns the name of a task pool, falling back to its ID
+func poolName(ctx *cli.Context, id string) string {
+	pools, err := ctx.Store.GetAllPools()
+	if err != nil {
+		return id
+	}
+	for _, p := range pools {
+		if p.ID == id {
+			return p.Name
+		}
+	}
+	return id
+}
+
+type slotSummary struct {
+	days    int
+	done    int
+	skipped int
+	ratings map[models.FeedbackRating]int
+}
+
+func summarizeSlots(history []models.TaskSlotEntry) slotSummary {
+	s := slotSummary{ratings: map[models.FeedbackRating]int{}}
+	days := map[string]bool{}
+	for _, e := range history {
+		days[e.Date] = true
+		switch e.Status {
+		case constants.SlotStatusDone:
+			s.done++
+		case constants.SlotStatusSkipped:
+			s.skipped++
+		}
+		if e.Rating != "" {
+			s.ratings[e.Rating]++
+		}
+	}
+	s.days = len(days)
+	return s
+}
+
+// durationTrend replays the exponential moving average that on-track
+// feedback applies to a task's actual duration, oldest first. history is
+// newest first.
+func durationTrend(history []models.TaskSlotEntry) []float64 {
+	var trend []float64
+	for i := len(history) - 1; i >= 0; i-- {
+		e := history[i]
+		if e.Rating != constants.FeedbackOnTrack {
+			continue
+		}
+		start, err := utils.ParseTimeToMinutes(e.Start)
+		if err != nil {
+			continue
+		}
+		end, err := utils.ParseTimeToMinutes(e.End)
+		if err != nil {
+			continue
+		}
+		// Blocks that cross midnight end on the next day
+		if end < start {
+			end += models.MinutesPerDay
+		}
+		actual := float64(end - start)
+		if actual <= 0 {
+			continue
+		}
+
+		if len(trend) == 0 {
+			trend = append(trend, actual)
+			continue
+		}
+		prev := trend[len(trend)-1]
+		trend = append(trend, prev*constants.FeedbackExistingWeight+actual*constants.FeedbackNewWeight)
+	}
+	return trend
+}
+
+// formatTrend shows the last few EMA values and which way they moved
+func formatTrend(trend []float64) string {
+	shown := trend
+	if len(shown) > maxTrendPoints {
+		shown = shown[len(shown)-maxTrendPoints:]
+	}
+	parts := make([]string, len(shown))
+	for i, v := range shown {
+		parts[i] = fmt.Sprintf("%.0fm", v)
+	}
+
+	direction := "steady"
+	if delta := trend[len(trend)-1] - trend[0]; delta >= 1 {
+		direction = "longer"
+	} else if delta <= -1 {
+		direction = "shorter"
+	}
+	return fmt.Sprintf("%s (%s)", strings.Join(parts, " → "), direction)
+}
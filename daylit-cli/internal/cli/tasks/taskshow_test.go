@@ -0,0 +1,63 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestDurationTrend(t *testing.T) {
+	// Newest first, as returned by GetTaskSlotHistory
+	history := []models.TaskSlotEntry{
+		{Date: "2024-05-04", Start: "23:40", End: "00:20", Rating: constants.FeedbackOnTrack},
+		{Date: "2024-05-03", Start: "09:00", End: "09:10", Rating: constants.FeedbackTooMuch},
+		{Date: "2024-05-02", Start: "09:00", End: "09:40", Rating: constants.FeedbackOnTrack},
+		{Date: "2024-05-01", Start: "09:00", End: "10:00", Rating: constants.FeedbackOnTrack},
+	}
+
+	trend := durationTrend(history)
+	first := 60.0
+	second := first*constants.FeedbackExistingWeight + 40*constants.FeedbackNewWeight
+	third := second*constants.FeedbackExistingWeight + 40*constants.FeedbackNewWeight
+	want := []float64{first, second, third}
+	if len(trend) != len(want) {
+		t.Fatalf("trend = %v, want %v", trend, want)
+	}
+	for i := range want {
+		if trend[i] != want[i] {
+			t.Errorf("trend[%d] = %v, want %v", i, trend[i], want[i])
+		}
+	}
+	if got := formatTrend(trend); !strings.HasSuffix(got, "(shorter)") {
+		t.Errorf("formatTrend = %q, want a shorter trend", got)
+	}
+}
+
+func TestTaskShowCmd_Run(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+	addBulkTestTasks(t, ctx)
+
+	cmd := &TaskShowCmd{Task: "Reading", Limit: 10}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("show without history failed: %v", err)
+	}
+
+	date := time.Now().Format(constants.DateFormat)
+	plan := models.DayPlan{Date: date, Slots: []models.Slot{
+		{Start: "09:00", End: "09:30", TaskID: "reading", Status: constants.SlotStatusDone, Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack, Note: "good"}},
+	}}
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Run(ctx); err != nil {
+		t.Fatalf("show with history failed: %v", err)
+	}
+
+	if err := (&TaskShowCmd{Task: "Reading", Limit: -1}).Validate(); err == nil {
+		t.Error("expected error for negative limit")
+	}
+}
@@ -0,0 +1,241 @@
+package templates
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+type TemplateCmd struct {
+	Add    TemplateAddCmd    `cmd:"" help:"Add a day template."`
+	List   TemplateListCmd   `cmd:"" help:"List day templates and their slots." default:"1"`
+	Edit   TemplateEditCmd   `cmd:"" help:"Rename a day template or replace its slots."`
+	Delete TemplateDeleteCmd `cmd:"" help:"Delete a day template."`
+}
+
+type TemplateAddCmd struct {
+	Name     string   `arg:"" help:"Template name, e.g. 'deep-work'."`
+	Slots    []string `name:"slot" short:"s" sep:"none" help:"Slot as 'HH:MM-HH:MM=Task name' (repeatable)."`
+	FromPlan string   `help:"Copy the slots of the plan for this date (YYYY-MM-DD)."`
+}
+
+func (c *TemplateAddCmd) Run(ctx *cli.Context) error {
+	name := strings.TrimSpace(c.Name)
+	if _, err := ctx.Store.GetDayTemplate(name); err == nil {
+		return fmt.Errorf("template %q already exists", name)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to check for existing template: %w", err)
+	}
+
+	var slots []models.TemplateSlot
+	var err error
+	switch {
+	case c.FromPlan != "" && len(c.Slots) > 0:
+		return fmt.Errorf("use either --slot or --from-plan, not both")
+	case c.FromPlan != "":
+		slots, err = slotsFromPlan(ctx, c.FromPlan)
+	default:
+		slots, err = parseSlots(ctx, c.Slots)
+	}
+	if err != nil {
+		return err
+	}
+	if len(slots) == 0 {
+		return fmt.Errorf("a template needs at least one slot (use --slot or --from-plan)")
+	}
+
+	template := models.DayTemplate{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Slots:     slots,
+		CreatedAt: ctx.Now(),
+	}
+	if err := ctx.Store.SaveDayTemplate(template); err != nil {
+		return fmt.Errorf("failed to save template: %w", err)
+	}
+
+	fmt.Printf("Added template: %s (%d slot(s))\n", template.Name, len(template.Slots))
+	return nil
+}
+
+type TemplateListCmd struct{}
+
+func (c *TemplateListCmd) Run(ctx *cli.Context) error {
+	templates, err := ctx.Store.GetAllDayTemplates()
+	if err != nil {
+		return fmt.Errorf("failed to get templates: %w", err)
+	}
+	if len(templates) == 0 {
+		fmt.Println("No templates found")
+		return nil
+	}
+
+	tasks, err := ctx.Store.GetAllTasksIncludingDeleted()
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	names := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		names[t.ID] = t.Name
+		if t.DeletedAt != nil {
+			names[t.ID] += " (deleted)"
+		}
+	}
+
+	fmt.Println("Templates:")
+	for _, t := range templates {
+		fmt.Printf("  %s\n", t.Name)
+		for _, slot := range t.Slots {
+			name, ok := names[slot.TaskID]
+			if !ok {
+				name = "(unknown task)"
+			}
+			fmt.Printf("      %s–%s  %s\n", slot.Start, slot.End, name)
+		}
+	}
+	return nil
+}
+
+type TemplateEditCmd struct {
+	Name    string   `arg:"" help:"Template to edit."`
+	NewName *string  `name:"name" help:"New template name."`
+	Slots   []string `name:"slot" short:"s" sep:"none" help:"Replace the slots; 'HH:MM-HH:MM=Task name' (repeatable)."`
+}
+
+func (c *TemplateEditCmd) Run(ctx *cli.Context) error {
+	template, err := getTemplate(ctx, c.Name)
+	if err != nil {
+		return err
+	}
+
+	if c.NewName != nil {
+		name := strings.TrimSpace(*c.NewName)
+		if name != template.Name {
+			if _, err := ctx.Store.GetDayTemplate(name); err == nil {
+				return fmt.Errorf("template %q already exists", name)
+			}
+		}
+		template.Name = name
+	}
+	if len(c.Slots) > 0 {
+		if template.Slots, err = parseSlots(ctx, c.Slots); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Store.SaveDayTemplate(template); err != nil {
+		return fmt.Errorf("failed to save template: %w", err)
+	}
+	fmt.Printf("Template updated: %s\n", template.Name)
+	return nil
+}
+
+type TemplateDeleteCmd struct {
+	Name string `arg:"" help:"Template to delete."`
+}
+
+func (c *TemplateDeleteCmd) Run(ctx *cli.Context) error {
+	if err := ctx.Store.DeleteDayTemplate(c.Name); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted template: %s\n", c.Name)
+	return nil
+}
+
+func getTemplate(ctx *cli.Context, name string) (models.DayTemplate, error) {
+	template, err := ctx.Store.GetDayTemplate(name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.DayTemplate{}, fmt.Errorf("template %q not found", name)
+	}
+	if err != nil {
+		return models.DayTemplate{}, fmt.Errorf("failed to get template: %w", err)
+	}
+	return template, nil
+}
+
+// parseSlots parses 'HH:MM-HH:MM=Task' slot flags, resolving tasks by name or ID
+func parseSlots(ctx *cli.Context, values []string) ([]models.TemplateSlot, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	tasks, err := ctx.Store.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	var slots []models.TemplateSlot
+	for _, value := range values {
+		times, taskName, ok := strings.Cut(value, "=")
+		startStr, endStr, ok2 := strings.Cut(times, "-")
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("invalid slot %q (expected 'HH:MM-HH:MM=Task name')", value)
+		}
+
+		start, err := utils.ParseTime(strings.TrimSpace(startStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid start time in slot %q: %w", value, err)
+		}
+		end, err := utils.ParseTime(strings.TrimSpace(endStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid end time in slot %q: %w", value, err)
+		}
+
+		task, err := findTask(tasks, strings.TrimSpace(taskName))
+		if err != nil {
+			return nil, err
+		}
+
+		slots = append(slots, models.TemplateSlot{
+			Start:  start.Format("15:04"),
+			End:    end.Format("15:04"),
+			TaskID: task.ID,
+		})
+	}
+	return slots, nil
+}
+
+// findTask finds a task by ID or by case-insensitive name
+func findTask(tasks []models.Task, ref string) (models.Task, error) {
+	var matches []models.Task
+	for _, t := range tasks {
+		if t.ID == ref {
+			return t, nil
+		}
+		if strings.EqualFold(t.Name, ref) {
+			matches = append(matches, t)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return models.Task{}, fmt.Errorf("task %q not found", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return models.Task{}, fmt.Errorf("task name %q is ambiguous; use the task ID (see 'daylit task list --show-ids')", ref)
+	}
+}
+
+// slotsFromPlan copies the slots of the latest plan revision for a date
+func slotsFromPlan(ctx *cli.Context, date string) ([]models.TemplateSlot, error) {
+	plan, err := ctx.Store.GetLatestPlanRevision(date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan for %s: %w", date, err)
+	}
+
+	var slots []models.TemplateSlot
+	for _, slot := range plan.Slots {
+		if slot.DeletedAt != nil {
+			continue
+		}
+		slots = append(slots, models.TemplateSlot{Start: slot.Start, End: slot.End, TaskID: slot.TaskID})
+	}
+	return slots, nil
+}
@@ -0,0 +1,156 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func setupTestDB(t *testing.T) (*cli.Context, func()) {
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+
+	ctx := &cli.Context{
+		Store:     store,
+		Scheduler: scheduler.New(),
+	}
+
+	cleanup := func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("failed to close store: %v", err)
+		}
+	}
+
+	return ctx, cleanup
+}
+
+func addTasks(t *testing.T, ctx *cli.Context) {
+	t.Helper()
+	tasks := []models.Task{
+		{ID: "deep", Name: "Deep Work", Kind: constants.TaskKindFlexible, DurationMin: 120, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 1, Active: true},
+		{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 2, Active: true},
+	}
+	for _, task := range tasks {
+		if err := ctx.Store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+	}
+}
+
+func TestTemplateCRUD(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+	addTasks(t, ctx)
+
+	add := &TemplateAddCmd{Name: "deep-work", Slots: []string{"9:00-12:00=deep work", "13:00-13:30=email"}}
+	if err := add.Run(ctx); err != nil {
+		t.Fatalf("template add failed: %v", err)
+	}
+	if err := add.Run(ctx); err == nil {
+		t.Error("expected error adding a duplicate template")
+	}
+
+	template, err := ctx.Store.GetDayTemplate("deep-work")
+	if err != nil {
+		t.Fatalf("failed to get template: %v", err)
+	}
+	want := []models.TemplateSlot{
+		{Start: "09:00", End: "12:00", TaskID: "deep"},
+		{Start: "13:00", End: "13:30", TaskID: "email"},
+	}
+	if len(template.Slots) != len(want) {
+		t.Fatalf("got %d slots, want %d", len(template.Slots), len(want))
+	}
+	for i, w := range want {
+		if template.Slots[i] != w {
+			t.Errorf("slot %d = %+v, want %+v", i, template.Slots[i], w)
+		}
+	}
+
+	if err := (&TemplateListCmd{}).Run(ctx); err != nil {
+		t.Errorf("template list failed: %v", err)
+	}
+
+	newName := "focus"
+	edit := &TemplateEditCmd{Name: "deep-work", NewName: &newName, Slots: []string{"08:00-10:00=Deep Work"}}
+	if err := edit.Run(ctx); err != nil {
+		t.Fatalf("template edit failed: %v", err)
+	}
+	template, err = ctx.Store.GetDayTemplate("focus")
+	if err != nil {
+		t.Fatalf("failed to get renamed template: %v", err)
+	}
+	if len(template.Slots) != 1 || template.Slots[0].Start != "08:00" {
+		t.Errorf("slots were not replaced: %+v", template.Slots)
+	}
+
+	if err := (&TemplateDeleteCmd{Name: "focus"}).Run(ctx); err != nil {
+		t.Fatalf("template delete failed: %v", err)
+	}
+	if _, err := getTemplate(ctx, "focus"); err == nil {
+		t.Error("expected template to be deleted")
+	}
+}
+
+func TestTemplateAddRejectsBadSlots(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+	addTasks(t, ctx)
+
+	tests := []struct {
+		name  string
+		slots []string
+	}{
+		{"no slots", nil},
+		{"missing task", []string{"09:00-10:00=Gardening"}},
+		{"bad format", []string{"09:00 Deep Work"}},
+		{"bad time", []string{"09:00-25:00=Deep Work"}},
+		{"overlap", []string{"09:00-11:00=Deep Work", "10:00-10:30=Email"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &TemplateAddCmd{Name: "bad", Slots: tt.slots}
+			if err := cmd.Run(ctx); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestTemplateAddFromPlan(t *testing.T) {
+	ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+	addTasks(t, ctx)
+
+	plan := models.DayPlan{
+		Date: "2024-05-06",
+		Slots: []models.Slot{
+			{Start: "09:00", End: "11:00", TaskID: "deep", Status: constants.SlotStatusDone},
+			{Start: "11:00", End: "11:30", TaskID: "email", Status: constants.SlotStatusPlanned},
+		},
+	}
+	if err := ctx.Store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	if err := (&TemplateAddCmd{Name: "monday", FromPlan: "2024-05-06"}).Run(ctx); err != nil {
+		t.Fatalf("template add --from-plan failed: %v", err)
+	}
+	template, err := ctx.Store.GetDayTemplate("monday")
+	if err != nil {
+		t.Fatalf("failed to get template: %v", err)
+	}
+	if len(template.Slots) != 2 || template.Slots[1].TaskID != "email" {
+		t.Errorf("unexpected slots copied from plan: %+v", template.Slots)
+	}
+
+	if err := (&TemplateAddCmd{Name: "empty", FromPlan: "2024-05-07"}).Run(ctx); err == nil {
+		t.Error("expected error for a date without a plan")
+	}
+}
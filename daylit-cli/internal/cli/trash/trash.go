@@ -0,0 +1,95 @@
+package trash
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+type ListCmd struct {
+	Type string `help:"Only list this kind of item (task|plan|habit|entry|ot)."`
+	JSON bool   `help:"Output as JSON."`
+}
+
+func (c *ListCmd) Validate() error {
+	if c.Type != "" {
+		if _, err := models.ParseTrashKind(c.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ListCmd) Run(ctx *cli.Context) error {
+	items, err := storage.ListTrash(ctx.Store)
+	if err != nil {
+		return fmt.Errorf("failed to list deleted items: %w", err)
+	}
+	if c.Type != "" {
+		kind, err := models.ParseTrashKind(c.Type)
+		if err != nil {
+			return err
+		}
+		var filtered []models.TrashItem
+		for _, item := range items {
+			if item.Kind == kind {
+				filtered = append(filtered, item)
+			}
+		}
+		items = filtered
+	}
+
+	if c.JSON {
+		if items == nil {
+			items = []models.TrashItem{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+
+	if len(items) == 0 {
+		fmt.Println("Nothing has been deleted")
+		return nil
+	}
+
+	fmt.Printf("Deleted items (%d):\n", len(items))
+	for _, item := range items {
+		fmt.Printf("  %-5s  %-36s  %s  %s\n", item.Kind, item.ID, deletedAt(item), item.Label)
+	}
+	fmt.Println("\nRestore one with 'daylit restore item KIND ID', or remove it for good with 'daylit purge KIND ID'.")
+	return nil
+}
+
+func deletedAt(item models.TrashItem) string {
+	if item.DeletedAt.IsZero() {
+		return "deleted ?               "
+	}
+	return "deleted " + item.DeletedAt.Local().Format("2006-01-02 15:04")
+}
+
+type RestoreCmd struct {
+	Kind string `arg:"" help:"Kind of item (task|plan|habit|entry|ot)."`
+	ID   string `arg:"" help:"Item ID, or the date of a plan or OT entry, as shown by 'daylit restore list'."`
+}
+
+func (c *RestoreCmd) Validate() error {
+	_, err := models.ParseTrashKind(c.Kind)
+	return err
+}
+
+func (c *RestoreCmd) Run(ctx *cli.Context) error {
+	kind, err := models.ParseTrashKind(c.Kind)
+	if err != nil {
+		return err
+	}
+	if err := storage.RestoreTrashItem(ctx.Store, kind, c.ID); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", kind, err)
+	}
+	fmt.Printf("Restored %s: %s\n", kind, c.ID)
+	return nil
+}
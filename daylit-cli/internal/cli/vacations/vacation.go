@@ -0,0 +1,124 @@
+package vacations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// Bounds used to list every vacation
+const (
+	firstDay = "0000-01-01"
+	lastDay  = "9999-12-31"
+)
+
+type VacationCmd struct {
+	Add    VacationAddCmd    `cmd:"" help:"Add days away, e.g. '2025-07-01..2025-07-14'."`
+	List   VacationListCmd   `cmd:"" help:"List current and upcoming vacations." default:"1"`
+	Delete VacationDeleteCmd `cmd:"" help:"Delete a vacation."`
+}
+
+type VacationAddCmd struct {
+	Range string `arg:"" help:"Days away as YYYY-MM-DD..YYYY-MM-DD, or a single YYYY-MM-DD."`
+	Note  string `short:"n" help:"Optional note, e.g. where you are going."`
+}
+
+func (c *VacationAddCmd) Run(ctx *cli.Context) error {
+	start, end, err := models.ParseDateRange(c.Range)
+	if err != nil {
+		return err
+	}
+
+	overlapping, err := ctx.Store.GetVacations(start, end)
+	if err != nil {
+		return fmt.Errorf("failed to check for overlapping vacations: %w", err)
+	}
+	if len(overlapping) > 0 {
+		v := overlapping[0]
+		return fmt.Errorf("overlaps the vacation %s..%s (ID: %s); delete it first to change it", v.Start, v.End, v.ID)
+	}
+
+	vacation := models.Vacation{
+		ID:        uuid.New().String(),
+		Start:     start,
+		End:       end,
+		Note:      strings.TrimSpace(c.Note),
+		CreatedAt: ctx.Now(),
+	}
+	if err := ctx.Store.AddVacation(vacation); err != nil {
+		return fmt.Errorf("failed to add vacation: %w", err)
+	}
+
+	fmt.Printf("Added vacation: %s (ID: %s)\n", describe(vacation), vacation.ID)
+	return nil
+}
+
+type VacationListCmd struct {
+	All bool `help:"Include past vacations."`
+}
+
+func (c *VacationListCmd) Run(ctx *cli.Context) error {
+	today := ctx.Now().Format(constants.DateFormat)
+	from := today
+	if c.All {
+		from = firstDay
+	}
+	vacations, err := ctx.Store.GetVacations(from, lastDay)
+	if err != nil {
+		return fmt.Errorf("failed to get vacations: %w", err)
+	}
+	if len(vacations) == 0 {
+		fmt.Println("No vacations found")
+		return nil
+	}
+
+	fmt.Println("Vacations:")
+	for _, v := range vacations {
+		line := fmt.Sprintf("  %s", describe(v))
+		if v.Covers(today) {
+			line += " [now]"
+		}
+		fmt.Printf("%s\n      ID: %s\n", line, v.ID)
+	}
+	return nil
+}
+
+type VacationDeleteCmd struct {
+	ID string `arg:"" help:"Vacation ID to delete (see 'daylit vacation list --all')."`
+}
+
+func (c *VacationDeleteCmd) Run(ctx *cli.Context) error {
+	vacations, err := ctx.Store.GetVacations(firstDay, lastDay)
+	if err != nil {
+		return fmt.Errorf("failed to get vacations: %w", err)
+	}
+	for _, v := range vacations {
+		if v.ID != c.ID {
+			continue
+		}
+		if err := ctx.Store.DeleteVacation(v.ID); err != nil {
+			return fmt.Errorf("failed to delete vacation: %w", err)
+		}
+		fmt.Printf("Deleted vacation: %s\n", describe(v))
+		return nil
+	}
+	return fmt.Errorf("vacation not found: %s (see 'daylit vacation list --all')", c.ID)
+}
+
+// describe formats a vacation as its range, length and note
+func describe(v models.Vacation) string {
+	s := fmt.Sprintf("%s..%s (%d day", v.Start, v.End, v.Days())
+	if v.Days() != 1 {
+		s += "s"
+	}
+	s += ")"
+	if v.Note != "" {
+		s += " — " + v.Note
+	}
+	return s
+}
@@ -0,0 +1,87 @@
+package vacations
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/autoplan"
+	"github.com/julianstephens/daylit/daylit-cli/internal/cli"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func setupTestDB(t *testing.T) *cli.Context {
+	t.Helper()
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return &cli.Context{Store: store, Scheduler: scheduler.New()}
+}
+
+func TestVacationAddAndDelete(t *testing.T) {
+	ctx := setupTestDB(t)
+
+	if err := (&VacationAddCmd{Range: "2025-07-01..2025-07-14", Note: "Lisbon"}).Run(ctx); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if err := (&VacationAddCmd{Range: "2025-07-10..2025-07-20"}).Run(ctx); err == nil {
+		t.Error("expected error for an overlapping vacation")
+	}
+	if err := (&VacationAddCmd{Range: "2025-07-14..2025-07-01"}).Run(ctx); err == nil {
+		t.Error("expected error for a backwards range")
+	}
+
+	vacations, err := ctx.Store.GetVacations(firstDay, lastDay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vacations) != 1 || vacations[0].Start != "2025-07-01" || vacations[0].End != "2025-07-14" || vacations[0].Note != "Lisbon" {
+		t.Fatalf("unexpected vacations: %+v", vacations)
+	}
+
+	if err := (&VacationListCmd{All: true}).Run(ctx); err != nil {
+		t.Errorf("list failed: %v", err)
+	}
+
+	if err := (&VacationDeleteCmd{ID: "missing"}).Run(ctx); err == nil {
+		t.Error("expected error deleting a missing vacation")
+	}
+	if err := (&VacationDeleteCmd{ID: vacations[0].ID}).Run(ctx); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if vacations, _ := ctx.Store.GetVacations(firstDay, lastDay); len(vacations) != 0 {
+		t.Errorf("expected no vacations after delete, got %+v", vacations)
+	}
+}
+
+func TestVacationSkipsRecurringTasks(t *testing.T) {
+	ctx := setupTestDB(t)
+
+	task := models.Task{
+		ID: "walk", Name: "Walk", Kind: constants.TaskKindFlexible, DurationMin: 30,
+		Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 3, Active: true,
+	}
+	if err := ctx.Store.AddTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&VacationAddCmd{Range: "2025-07-01..2025-07-14"}).Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := ctx.Store.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for date, want := range map[string]int{"2025-06-30": 1, "2025-07-01": 0, "2025-07-15": 1} {
+		plan, err := autoplan.Generate(ctx.Store, ctx.Scheduler, settings, date, false, ctx.Now())
+		if err != nil {
+			t.Fatalf("generate failed for %s: %v", date, err)
+		}
+		if len(plan.Slots) != want {
+			t.Errorf("plan for %s has %d slots, want %d", date, len(plan.Slots), want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Package clock lets code that depends on the current time run at a time of
+// the caller's choosing, so tests can freeze it and bug reports can be
+// replayed at the time they happened.
+package clock
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the real clock
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Fixed is a clock stopped at a time
+type Fixed time.Time
+
+func (f Fixed) Now() time.Time {
+	return time.Time(f)
+}
+
+// StartingAt returns a clock that reads t now and runs on from there, so
+// long-running code like the TUI still sees time pass
+func StartingAt(t time.Time) Clock {
+	return offsetClock(time.Until(t))
+}
+
+type offsetClock time.Duration
+
+func (o offsetClock) Now() time.Time {
+	return time.Now().Add(time.Duration(o))
+}
+
+// Or returns c, or the system clock if c is nil
+func Or(c Clock) Clock {
+	if c == nil {
+		return System
+	}
+	return c
+}
+
+// layouts are the forms Parse accepts, most specific first
+var layouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Parse reads a time for the --now flag: RFC 3339, a local date and time
+// like 2025-03-10T08:30 or "2025-03-10 08:30", a date (taken as midnight),
+// or an HH:MM time today
+func Parse(s string, now time.Time) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse("15:04", s); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.Local), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use YYYY-MM-DDTHH:MM, YYYY-MM-DD or HH:MM", s)
+}
@@ -0,0 +1,58 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.Local)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2025-03-11T08:30", time.Date(2025, 3, 11, 8, 30, 0, 0, time.Local)},
+		{"2025-03-11 08:30", time.Date(2025, 3, 11, 8, 30, 0, 0, time.Local)},
+		{"2025-03-11", time.Date(2025, 3, 11, 0, 0, 0, 0, time.Local)},
+		{"23:55", time.Date(2025, 3, 10, 23, 55, 0, 0, time.Local)},
+		{"2025-03-11T08:30:00Z", time.Date(2025, 3, 11, 8, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, now)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := Parse("tomorrow", now); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestStartingAt(t *testing.T) {
+	at := time.Date(2025, 3, 10, 23, 59, 0, 0, time.Local)
+	c := StartingAt(at)
+
+	got := c.Now()
+	if got.Before(at) || got.Sub(at) > time.Minute {
+		t.Errorf("expected the clock to start at %v, got %v", at, got)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if !c.Now().After(got) {
+		t.Error("expected the clock to keep running")
+	}
+}
+
+func TestOr(t *testing.T) {
+	fixed := Fixed(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	if got := Or(fixed).Now(); !got.Equal(time.Time(fixed)) {
+		t.Errorf("expected the given clock, got %v", got)
+	}
+	if Or(nil) != System {
+		t.Error("expected the system clock for nil")
+	}
+}
@@ -0,0 +1,207 @@
+// Package config reads and writes config.toml, the per-machine CLI
+// preferences kept alongside the settings stored in the database.
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/alecthomas/kong"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
+)
+
+// Output formats
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// Config holds the preferences in config.toml. Unset keys keep the built-in
+// behavior.
+type Config struct {
+	Output       string `toml:"output,omitempty"`        // Default output format for commands with --json
+	Theme        string `toml:"theme,omitempty"`         // TUI theme on this machine, overriding the theme setting
+	Confirm      *bool  `toml:"confirm,omitempty"`       // Ask before replacing or importing data; default true
+	Editor       string `toml:"editor,omitempty"`        // Editor for 'config edit'
+	PlanTemplate string `toml:"plan_template,omitempty"` // Day template 'plan' uses when --template isn't given
+	User         string `toml:"user,omitempty"`          // User on a shared PostgreSQL database when --user isn't given
+}
+
+// Key describes a key accepted by 'config get' and 'config set'
+type Key struct {
+	Name    string
+	Default string
+	Help    string
+}
+
+// Keys lists every config.toml key
+var Keys = []Key{
+	{Name: "output", Default: OutputText, Help: "Default output format for commands with --json (text or json)"},
+	{Name: "theme", Default: "theme setting", Help: "TUI theme on this machine (" + strings.Join(theme.Names(), ", ") + ")"},
+	{Name: "confirm", Default: "true", Help: "Ask before replacing a plan, restoring a backup or importing tasks"},
+	{Name: "editor", Default: "$EDITOR or vi", Help: "Editor opened by 'daylit config edit'"},
+	{Name: "plan_template", Default: "none", Help: "Day template 'daylit plan' uses when --template isn't given"},
+	{Name: "user", Default: "default user", Help: "User on a shared PostgreSQL database when --user isn't given"},
+}
+
+// Path returns the location of config.toml: $DAYLIT_CONFIG_FILE, or
+// ~/.config/daylit/config.toml
+func Path() string {
+	if path := os.Getenv(constants.PreferencesPathEnv); path != "" {
+		return path
+	}
+	path := constants.DefaultPreferencesPath
+	if home, err := os.UserHomeDir(); err == nil {
+		path = filepath.Join(home, strings.TrimPrefix(path, "~/"))
+	}
+	return path
+}
+
+// Load reads the config file at path. A missing file is an empty config.
+func Load(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	md, err := toml.Decode(string(data), &cfg)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return Config{}, fmt.Errorf("%s: unknown key %q", path, undecoded[0].String())
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes cfg to path, creating its directory if needed
+func Save(path string, cfg Config) error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(cfg); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0600)
+}
+
+// Validate checks the values that have a fixed set of choices
+func (c Config) Validate() error {
+	switch c.Output {
+	case "", OutputText, OutputJSON:
+	default:
+		return fmt.Errorf("invalid output: %s (expected text or json)", c.Output)
+	}
+	if c.Theme != "" {
+		if _, ok := theme.Lookup(c.Theme); !ok {
+			return fmt.Errorf("invalid theme: %s (available: %s)", c.Theme, strings.Join(theme.Names(), ", "))
+		}
+	}
+	if c.User != "" {
+		if err := models.ValidateUserName(c.User); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Confirms reports whether commands should ask before replacing or importing
+// data
+func (c Config) Confirms() bool {
+	return c.Confirm == nil || *c.Confirm
+}
+
+// Get returns the value of key, or "" when it is unset
+func (c Config) Get(key string) (string, error) {
+	switch key {
+	case "output":
+		return c.Output, nil
+	case "theme":
+		return c.Theme, nil
+	case "confirm":
+		if c.Confirm == nil {
+			return "", nil
+		}
+		return strconv.FormatBool(*c.Confirm), nil
+	case "editor":
+		return c.Editor, nil
+	case "plan_template":
+		return c.PlanTemplate, nil
+	case "user":
+		return c.User, nil
+	}
+	return "", fmt.Errorf("unknown key: %s", key)
+}
+
+// Set changes key to value. An empty value unsets the key.
+func (c *Config) Set(key, value string) error {
+	switch key {
+	case "output":
+		c.Output = value
+	case "theme":
+		c.Theme = value
+	case "confirm":
+		if value == "" {
+			c.Confirm = nil
+			break
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid confirm: %s (expected true or false)", value)
+		}
+		c.Confirm = &b
+	case "editor":
+		c.Editor = value
+	case "plan_template":
+		c.PlanTemplate = value
+	case "user":
+		c.User = value
+	default:
+		return fmt.Errorf("unknown key: %s", key)
+	}
+	return c.Validate()
+}
+
+// isPlanGenerate reports whether node is the command that generates plans,
+// 'daylit plan [generate]'
+func isPlanGenerate(node *kong.Node) bool {
+	return node != nil && node.Name == "generate" && node.Parent != nil && node.Parent.Name == "plan"
+}
+
+// Resolver supplies flag values from the config file. Flags given on the
+// command line or through their environment variable take precedence.
+func (c Config) Resolver() kong.Resolver {
+	return kong.ResolverFunc(func(_ *kong.Context, parent *kong.Path, flag *kong.Flag) (any, error) {
+		for _, env := range flag.Envs {
+			if _, ok := os.LookupEnv(env); ok {
+				return nil, nil
+			}
+		}
+
+		switch {
+		case flag.Name == "json" && c.Output != "":
+			return c.Output == OutputJSON, nil
+		case flag.Name == "template" && c.PlanTemplate != "" && isPlanGenerate(parent.Command):
+			return c.PlanTemplate, nil
+		case flag.Name == "user" && c.User != "":
+			return c.User, nil
+		}
+		return nil, nil
+	})
+}
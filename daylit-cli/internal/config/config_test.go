@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/kong"
+)
+
+func TestLoadAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daylit", "config.toml")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("loading a missing file failed: %v", err)
+	}
+	if !cfg.Confirms() || cfg.Output != "" {
+		t.Errorf("missing file should give the defaults, got %+v", cfg)
+	}
+
+	for key, value := range map[string]string{"output": "json", "theme": "light", "confirm": "false", "editor": "nano", "plan_template": "Workday"} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatalf("set %s failed: %v", key, err)
+		}
+	}
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if loaded.Output != "json" || loaded.Theme != "light" || loaded.Confirms() ||
+		loaded.Editor != "nano" || loaded.PlanTemplate != "Workday" {
+		t.Errorf("round trip lost values: %+v", loaded)
+	}
+	if v, _ := loaded.Get("confirm"); v != "false" {
+		t.Errorf("get confirm = %q, want false", v)
+	}
+
+	// An empty value restores the default
+	if err := loaded.Set("confirm", ""); err != nil {
+		t.Fatalf("unset failed: %v", err)
+	}
+	if !loaded.Confirms() {
+		t.Error("confirm should default to true once unset")
+	}
+}
+
+func TestLoadRejectsBadFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "unknown key", content: "colour = \"red\"\n"},
+		{name: "bad output", content: "output = \"yaml\"\n"},
+		{name: "bad theme", content: "theme = \"neon\"\n"},
+		{name: "bad user", content: "user = \"no spaces\"\n"},
+		{name: "not toml", content: "output = \n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestSetRejectsBadValues(t *testing.T) {
+	var cfg Config
+	for _, kv := range [][2]string{{"output", "yaml"}, {"theme", "neon"}, {"confirm", "maybe"}, {"colour", "red"}, {"user", "Sam"}} {
+		if err := cfg.Set(kv[0], kv[1]); err == nil {
+			t.Errorf("set %s=%s: expected error", kv[0], kv[1])
+		}
+	}
+}
+
+func TestResolver(t *testing.T) {
+	type cli struct {
+		Plan struct {
+			Generate struct {
+				Template string `help:"Template."`
+			} `cmd:"" default:"withargs"`
+			Lock struct{} `cmd:""`
+		} `cmd:""`
+		Stats struct {
+			JSON bool `name:"json" help:"JSON."`
+		} `cmd:""`
+	}
+	cfg := Config{Output: OutputJSON, PlanTemplate: "Workday"}
+
+	parse := func(args ...string) cli {
+		t.Helper()
+		var c cli
+		parser, err := kong.New(&c, kong.Resolvers(cfg.Resolver()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := parser.Parse(args); err != nil {
+			t.Fatalf("parse %v failed: %v", args, err)
+		}
+		return c
+	}
+
+	if c := parse("stats"); !c.Stats.JSON {
+		t.Error("output = json should turn on --json")
+	}
+	if c := parse("stats", "--json=false"); c.Stats.JSON {
+		t.Error("--json=false should win over config.toml")
+	}
+	if c := parse("plan"); c.Plan.Generate.Template != "Workday" {
+		t.Errorf("template = %q, want Workday", c.Plan.Generate.Template)
+	}
+	if c := parse("plan", "--template", "Weekend"); c.Plan.Generate.Template != "Weekend" {
+		t.Errorf("template = %q, want Weekend", c.Plan.Generate.Template)
+	}
+}
@@ -17,12 +17,28 @@ type RecurrenceType string
 // EnergyBand represents the energy band of a task
 type EnergyBand string
 
+// PoolStrategy is how a task pool picks the member to schedule each day
+type PoolStrategy string
+
+// SearchKind identifies the source of a search result
+type SearchKind string
+
 const (
 	AppName            = "daylit"
 	DefaultKeyringUser = "database-connection"
+	NotesKeyringPrefix = "notes-key:" // Keyring entries of the keys that encrypt SQLite notes
 	DefaultConfigPath  = "~/.config/daylit/daylit.db"
 	Version            = "v1.0.0"
 
+	// Preferences file constants
+	DefaultPreferencesPath = "~/.config/daylit/config.toml"
+	PreferencesPathEnv     = "DAYLIT_CONFIG_FILE" // Overrides DefaultPreferencesPath
+
+	// Hooks constants
+	DefaultHooksDir = "~/.config/daylit/hooks"
+	HooksDirEnv     = "DAYLIT_HOOKS_DIR" // Overrides DefaultHooksDir
+	HookTimeout     = 10 * time.Second   // How long a hook may run before it is killed
+
 	// DateFormat is the standard date format used throughout the application (YYYY-MM-DD)
 	DateFormat = "2006-01-02"
 
@@ -39,6 +55,28 @@ const (
 	NotifyMaxRetries = 3
 	NotifyRetryDelay = 100 * time.Millisecond
 
+	// Notification outbox constants: failed deliveries are retried on later
+	// notify runs, waiting twice as long after each failed attempt
+	NotifyOutboxBaseDelay   = time.Minute
+	NotifyOutboxMaxDelay    = 15 * time.Minute
+	NotifyOutboxMaxAttempts = 6
+	NotifyOutboxMaxAge      = 2 * time.Hour // Older notifications are too late to be of use
+	NotifyOutboxBatch       = 10            // Retries per run, so a backlog doesn't flood the tray
+
+	// NotifyClaimRetention is how long notification claims are kept; past
+	// the grace period and the previous day's plan, no run can send them again
+	NotifyClaimRetention = 48 * time.Hour
+
+	// Change propagation constants
+	ChangeChannel      = "daylit_changes" // Postgres LISTEN/NOTIFY channel
+	ChangePollInterval = 2 * time.Second  // How often SQLite clients check for changes
+
+	// Storage health constants, for database servers the TUI can lose
+	HealthCheckInterval = 15 * time.Second // How often the TUI checks the connection
+	HealthCheckTimeout  = 5 * time.Second  // How long a check waits for the server
+	ReconnectMinDelay   = time.Second      // First retry after the connection is lost
+	ReconnectMaxDelay   = 30 * time.Second // Retries back off up to this delay
+
 	// Slot Status constants
 	SlotStatusPlanned  = "planned"
 	SlotStatusAccepted = "accepted"
@@ -58,19 +96,47 @@ const (
 	RecurrenceMonthlyDay  RecurrenceType = "monthly_day"  // e.g., last Friday of the month
 	RecurrenceYearly      RecurrenceType = "yearly"       // e.g., every year on January 1st
 	RecurrenceWeekdays    RecurrenceType = "weekdays"     // every weekday (Mon-Fri)
+	RecurrenceCron        RecurrenceType = "cron"         // alerts only: a cron expression, e.g. "0 9 * * 1-5"
+
+	// Pool Strategy constants
+	PoolStrategyRoundRobin  PoolStrategy = "round_robin"  // members take turns, one per day
+	PoolStrategyLeastRecent PoolStrategy = "least_recent" // the member done longest ago
 
 	// Energy Band constants
 	EnergyLow    EnergyBand = "low"
 	EnergyMedium EnergyBand = "medium"
 	EnergyHigh   EnergyBand = "high"
 
+	// Search Kind constants
+	SearchKindTask  SearchKind = "task"  // task name
+	SearchKindHabit SearchKind = "habit" // habit name
+	SearchKindOT    SearchKind = "ot"    // OT title and note
+	SearchKindNote  SearchKind = "note"  // slot feedback note
+
 	// Notification constants
 	NotifierLockfileName   = "daylit-tray.lock"
 	NotificationDurationMs = 5000
 	TrayAppIdentifier      = "com.daylit.daylit-tray"
 
+	// Notification log kinds and delivery channels
+	NotificationKindBlockStart    = "block_start"
+	NotificationKindBlockEnd      = "block_end"
+	NotificationKindAlert         = "alert"
+	NotificationKindMorningPlan   = "morning_plan"
+	NotificationKindSlotReminder  = "slot_reminder"
+	NotificationKindWeeklySummary = "weekly_summary"
+	NotificationKindHabitReminder = "habit_reminder"
+	NotificationChannelTray       = "tray"
+	NotificationChannelSocket     = "socket" // a tray subscribed to the notify serve socket
+	NotificationChannelDryRun     = "dry_run"
+
+	// Notification urgencies; an empty urgency is normal
+	NotificationUrgencyLow      = "low"
+	NotificationUrgencyNormal   = "normal"
+	NotificationUrgencyCritical = "critical"
+
 	// NumMainTabs is the number of main navigation tabs in the TUI
-	NumMainTabs = 7 // Now, Plan, Tasks, Habits, OT, Alerts, Settings
+	NumMainTabs = 11 // Now, Plan, Calendar, Week, Tasks, Habits, OT, Alerts, Inbox, Trash, Settings
 
 	// Conflict Types
 	ConflictOverlappingFixedTasks ConflictType = "overlapping_fixed_tasks"
@@ -80,14 +146,21 @@ const (
 	ConflictMissingTaskID         ConflictType = "missing_task_id"
 	ConflictDuplicateTaskName     ConflictType = "duplicate_task_name"
 	ConflictInvalidDateTime       ConflictType = "invalid_datetime"
+	ConflictNotEnoughFreeTime     ConflictType = "not_enough_free_time"
+	ConflictNoRecovery            ConflictType = "no_recovery"
+	ConflictPaddingOverlap        ConflictType = "padding_overlap"
 
 	// TUI Session States
 	StateNow SessionState = iota
 	StatePlan
+	StateCalendar
+	StateWeek
 	StateTasks
 	StateHabits
 	StateOT
 	StateAlerts
+	StateInbox
+	StateTrash
 	StateSettings
 	StateFeedback
 	StateEditing
@@ -95,8 +168,15 @@ const (
 	StateConfirmRestore
 	StateConfirmOverwrite
 	StateConfirmArchive
+	StateConfirmPurge
+	StateConfirmMorningPlan
+	StateConfirmReview
+	StateConfirmConflict
 	StateAddHabit
 	StateAddAlert
 	StateEditOT
+	StateEditNote
 	StateEditSettings
+	StateStorageDown
+	StateSearch
 )
@@ -2,16 +2,56 @@ package constants
 
 const (
 	// General Settings
-	SettingDayStart                   = "day_start"
-	SettingDayEnd                     = "day_end"
-	SettingDefaultBlockMin            = "default_block_min"
-	SettingNotificationsEnabled       = "notifications_enabled"
-	SettingNotifyBlockStart           = "notify_block_start"
-	SettingNotifyBlockEnd             = "notify_block_end"
-	SettingBlockStartOffsetMin        = "block_start_offset_min"
-	SettingBlockEndOffsetMin          = "block_end_offset_min"
-	SettingNotificationGracePeriodMin = "notification_grace_period_min"
-	SettingTimezone                   = "timezone"
+	SettingDayStart                    = "day_start"
+	SettingDayEnd                      = "day_end"
+	SettingDefaultBlockMin             = "default_block_min"
+	SettingNotificationsEnabled        = "notifications_enabled"
+	SettingNotifyBlockStart            = "notify_block_start"
+	SettingNotifyBlockEnd              = "notify_block_end"
+	SettingBlockStartOffsetMin         = "block_start_offset_min"
+	SettingBlockEndOffsetMin           = "block_end_offset_min"
+	SettingNotificationGracePeriodMin  = "notification_grace_period_min"
+	SettingNotificationDigestWindowMin = "notification_digest_window_min"
+	SettingTimezone                    = "timezone"
+	SettingTheme                       = "theme"
+	SettingLocale                      = "locale"
+	SettingWeekStart                   = "week_start"
+	SettingMarkdownExportDir           = "markdown_export_dir"
+	SettingActiveContext               = "active_context"
+	SettingMorningPlan                 = "morning_plan"
+	SettingCompressOnEarlyFinish       = "compress_on_early_finish"
+	SettingWeeklySummary               = "weekly_summary"
+	SettingWeeklySummaryAt             = "weekly_summary_at"
+	SettingMetricsEnabled              = "metrics_enabled"
+	SettingAdaptivePlacement           = "adaptive_placement"
+	SettingPriorityAgingDays           = "priority_aging_days"
+	SettingMinFreeMinutes              = "min_free_minutes"
+	SettingRecoveryMinutes             = "recovery_minutes"
+	SettingDemandingMinutes            = "demanding_minutes"
+	SettingTaskDeleteSlots             = "task_delete_slots"
+	SettingHabitDeleteEntries          = "habit_delete_entries"
+	SettingArchivedHabitStats          = "archived_habit_stats"
+
+	// Internal markers so the morning plan check acts at most once a day
+	SettingMorningPlanNotifiedOn  = "morning_plan_notified_on"
+	SettingMorningPlanDismissedOn = "morning_plan_dismissed_on"
+
+	// Internal marker so the weekly summary goes out at most once a day
+	SettingWeeklySummarySentOn = "weekly_summary_sent_on"
+
+	// Internal marker so the nightly metrics run happens at most once a day
+	SettingMetricsRecordedOn = "metrics_recorded_on"
+
+	// Internal markers of encrypted notes in SQLite: the keyring entry of the
+	// key, and a value sealed with it to tell a wrong key
+	SettingNotesKeyID    = "notes_key_id"
+	SettingNotesKeyCheck = "notes_key_check"
+
+	// SettingVersion counts saves so concurrent editors can detect conflicts
+	SettingVersion = "version"
+
+	// SettingKeysPrefix prefixes TUI key binding overrides, e.g. "keys.quit"
+	SettingKeysPrefix = "keys."
 
 	// OT Settings
 	SettingOTPromptOnEmpty  = "ot_prompt_on_empty"
@@ -29,4 +69,46 @@ const (
 	DefaultBlockEndOffsetMin          = 5
 	DefaultNotificationGracePeriodMin = 10
 	DefaultTimezone                   = "Local" // Use system local timezone by default
+	DefaultTheme                      = ThemeDark
+	DefaultWeekStart                  = WeekStartSunday
+	DefaultMorningPlan                = MorningPlanOff
+	DefaultWeeklySummary              = WeeklySummaryOff
+	DefaultWeeklySummaryAt            = "sun 18:00"
+	DefaultTaskDeleteSlots            = TaskDeleteSlotsAsk
+	DefaultHabitDeleteEntries         = HabitDeleteEntriesKeep
+	DefaultArchivedHabitStats         = ArchivedHabitStatsHide
+
+	// Morning plan modes: what happens at day_start when today has no accepted plan
+	MorningPlanOff       = "off"        // do nothing
+	MorningPlanPrompt    = "prompt"     // notify and offer to generate a plan on the next TUI launch
+	MorningPlanHandsFree = "hands-free" // generate and accept a plan automatically
+
+	// Weekly summary modes: how the scheduled weekly summary is delivered
+	WeeklySummaryOff    = "off"    // don't send a summary
+	WeeklySummaryNotify = "notify" // send a notification
+	WeeklySummaryReport = "report" // write a Markdown report to the markdown export directory
+	WeeklySummaryBoth   = "both"   // notify and write the report
+
+	// What deleting a task does to its upcoming slots in accepted plans
+	TaskDeleteSlotsAsk    = "ask"    // 'task delete' asks; other deletes leave them
+	TaskDeleteSlotsRemove = "remove" // remove them along with the task
+	TaskDeleteSlotsKeep   = "keep"   // leave them in the plans
+
+	// What deleting a habit does to its entries
+	HabitDeleteEntriesKeep   = "keep"   // leave them; restoring the habit brings its history back as it was
+	HabitDeleteEntriesDelete = "delete" // delete them along with the habit; restoring the habit restores them
+
+	// Whether archived habits count in habit stats
+	ArchivedHabitStatsHide = "hide" // leave them out
+	ArchivedHabitStatsKeep = "keep" // count them up to the day they were archived
+
+	// Days a week can start on
+	WeekStartSunday = "sunday"
+	WeekStartMonday = "monday"
+
+	// Built-in TUI themes
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeNoColor      = "no-color"
 )
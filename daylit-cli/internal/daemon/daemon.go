@@ -0,0 +1,251 @@
+// Package daemon serves the health and metrics endpoints of
+// `daylit notify serve` and queries them for `daylit doctor --remote`, and
+// serves the socket the tray talks to the daemon over.
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+const (
+	// HealthPath and MetricsPath are the endpoints served by Handler
+	HealthPath  = "/healthz"
+	MetricsPath = "/metrics"
+
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// Health is the JSON body returned by the health endpoint
+type Health struct {
+	Status      string    `json:"status"` // ok or error
+	StartedAt   time.Time `json:"started_at"`
+	LastRun     time.Time `json:"last_run,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	Runs        int       `json:"runs"`
+	DBUp        bool      `json:"db_up"`
+	DBLatencyMs float64   `json:"db_latency_ms"`
+	DBError     string    `json:"db_error,omitempty"`
+}
+
+type notificationKey struct {
+	kind   string
+	result string
+}
+
+// Metrics collects the daemon's counters and gauges. It is safe for
+// concurrent use.
+type Metrics struct {
+	mu            sync.Mutex
+	startedAt     time.Time
+	notifications map[notificationKey]int
+	runs          map[string]int
+	lastRun       time.Time
+	lastRunErr    string
+	dbLatency     time.Duration
+	dbErr         string
+	dbProbed      bool
+	plansTotal    int
+	plansAccepted int
+}
+
+// NewMetrics returns empty metrics for a daemon started at startedAt
+func NewMetrics(startedAt time.Time) *Metrics {
+	return &Metrics{
+		startedAt:     startedAt,
+		notifications: make(map[notificationKey]int),
+		runs:          make(map[string]int),
+	}
+}
+
+// RecordNotification counts a notification by kind and delivery result
+func (m *Metrics) RecordNotification(entry models.NotificationLogEntry) {
+	result := "success"
+	if !entry.Success {
+		result = "failure"
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notifications[notificationKey{kind: entry.Kind, result: result}]++
+}
+
+// RecordRun counts a notification check run that finished at the given time
+func (m *Metrics) RecordRun(at time.Time, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastRun = at
+	if err != nil {
+		m.runs["failure"]++
+		m.lastRunErr = err.Error()
+	} else {
+		m.runs["success"]++
+		m.lastRunErr = ""
+	}
+}
+
+// RecordDBProbe stores the result of the latest database round trip
+func (m *Metrics) RecordDBProbe(latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dbProbed = true
+	m.dbLatency = latency
+	m.dbErr = ""
+	if err != nil {
+		m.dbErr = err.Error()
+	}
+}
+
+// RecordPlans stores how many of the summarized days had a plan and how many
+// of those plans were accepted
+func (m *Metrics) RecordPlans(summaries []models.DaySummary) {
+	total, accepted := 0, 0
+	for _, s := range summaries {
+		if !s.HasPlan {
+			continue
+		}
+		total++
+		if s.Accepted {
+			accepted++
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.plansTotal = total
+	m.plansAccepted = accepted
+}
+
+// Health reports the daemon's current health. It is an error when the last
+// check run or database probe failed.
+func (m *Metrics) Health() Health {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h := Health{
+		Status:      StatusOK,
+		StartedAt:   m.startedAt,
+		LastRun:     m.lastRun,
+		LastError:   m.lastRunErr,
+		Runs:        m.runs["success"] + m.runs["failure"],
+		DBUp:        m.dbProbed && m.dbErr == "",
+		DBLatencyMs: float64(m.dbLatency.Microseconds()) / 1000,
+		DBError:     m.dbErr,
+	}
+	if m.lastRunErr != "" || m.dbErr != "" {
+		h.Status = StatusError
+	}
+	return h
+}
+
+// WritePrometheus writes the metrics in the Prometheus text format
+func (m *Metrics) WritePrometheus(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP daylit_notifications_total Notifications emitted by kind and delivery result.")
+	fmt.Fprintln(w, "# TYPE daylit_notifications_total counter")
+	keys := make([]notificationKey, 0, len(m.notifications))
+	for k := range m.notifications {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].kind != keys[j].kind {
+			return keys[i].kind < keys[j].kind
+		}
+		return keys[i].result < keys[j].result
+	})
+	for _, k := range keys {
+		fmt.Fprintf(w, "daylit_notifications_total{kind=%q,result=%q} %d\n", k.kind, k.result, m.notifications[k])
+	}
+
+	fmt.Fprintln(w, "# HELP daylit_scheduler_runs_total Scheduled notification checks by result.")
+	fmt.Fprintln(w, "# TYPE daylit_scheduler_runs_total counter")
+	for _, result := range []string{"failure", "success"} {
+		fmt.Fprintf(w, "daylit_scheduler_runs_total{result=%q} %d\n", result, m.runs[result])
+	}
+
+	fmt.Fprintln(w, "# HELP daylit_scheduler_last_run_timestamp_seconds Unix time of the last notification check.")
+	fmt.Fprintln(w, "# TYPE daylit_scheduler_last_run_timestamp_seconds gauge")
+	fmt.Fprintf(w, "daylit_scheduler_last_run_timestamp_seconds %d\n", unixOrZero(m.lastRun))
+
+	fmt.Fprintln(w, "# HELP daylit_db_up Whether the last database probe succeeded.")
+	fmt.Fprintln(w, "# TYPE daylit_db_up gauge")
+	fmt.Fprintf(w, "daylit_db_up %d\n", boolToInt(m.dbProbed && m.dbErr == ""))
+
+	fmt.Fprintln(w, "# HELP daylit_db_latency_seconds Round-trip time of the last database probe.")
+	fmt.Fprintln(w, "# TYPE daylit_db_latency_seconds gauge")
+	fmt.Fprintf(w, "daylit_db_latency_seconds %g\n", m.dbLatency.Seconds())
+
+	fmt.Fprintln(w, "# HELP daylit_plan_acceptance_ratio Share of planned days in the last 30 days whose plan was accepted.")
+	fmt.Fprintln(w, "# TYPE daylit_plan_acceptance_ratio gauge")
+	ratio := 0.0
+	if m.plansTotal > 0 {
+		ratio = float64(m.plansAccepted) / float64(m.plansTotal)
+	}
+	fmt.Fprintf(w, "daylit_plan_acceptance_ratio %g\n", ratio)
+
+	fmt.Fprintln(w, "# HELP daylit_start_time_seconds Unix time the daemon started.")
+	fmt.Fprintln(w, "# TYPE daylit_start_time_seconds gauge")
+	fmt.Fprintf(w, "daylit_start_time_seconds %d\n", unixOrZero(m.startedAt))
+}
+
+// Handler serves the health and metrics endpoints
+func Handler(m *Metrics) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthPath, func(w http.ResponseWriter, r *http.Request) {
+		h := m.Health()
+		w.Header().Set("Content-Type", "application/json")
+		if h.Status != StatusOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(h)
+	})
+	mux.HandleFunc(MetricsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		m.WritePrometheus(w)
+	})
+	return mux
+}
+
+// FetchHealth queries the health endpoint of the daemon at baseURL. An
+// unhealthy daemon is not an error; check the returned status.
+func FetchHealth(client *http.Client, baseURL string) (Health, error) {
+	url := strings.TrimSuffix(baseURL, "/") + HealthPath
+	res, err := client.Get(url)
+	if err != nil {
+		return Health{}, fmt.Errorf("failed to reach daemon: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusServiceUnavailable {
+		return Health{}, fmt.Errorf("unexpected status from %s: %s", url, res.Status)
+	}
+
+	var h Health
+	if err := json.NewDecoder(res.Body).Decode(&h); err != nil {
+		return Health{}, fmt.Errorf("invalid health response: %w", err)
+	}
+	return h, nil
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
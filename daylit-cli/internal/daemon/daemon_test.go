@@ -0,0 +1,87 @@
+package daemon
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestHandler_Metrics(t *testing.T) {
+	m := NewMetrics(time.Unix(1000, 0))
+	m.RecordNotification(models.NotificationLogEntry{Kind: "alert", Success: true})
+	m.RecordNotification(models.NotificationLogEntry{Kind: "alert", Success: true})
+	m.RecordNotification(models.NotificationLogEntry{Kind: "block_start", Success: false})
+	m.RecordRun(time.Unix(2000, 0), nil)
+	m.RecordDBProbe(1500*time.Microsecond, nil)
+	m.RecordPlans([]models.DaySummary{
+		{HasPlan: true, Accepted: true},
+		{HasPlan: true},
+		{HasPlan: false},
+		{HasPlan: true, Accepted: true},
+		{HasPlan: true, Accepted: true},
+	})
+
+	srv := httptest.NewServer(Handler(m))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + MetricsPath)
+	if err != nil {
+		t.Fatalf("GET metrics failed: %v", err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+
+	for _, want := range []string{
+		`daylit_notifications_total{kind="alert",result="success"} 2`,
+		`daylit_notifications_total{kind="block_start",result="failure"} 1`,
+		`daylit_scheduler_runs_total{result="success"} 1`,
+		`daylit_scheduler_last_run_timestamp_seconds 2000`,
+		`daylit_db_up 1`,
+		`daylit_db_latency_seconds 0.0015`,
+		`daylit_plan_acceptance_ratio 0.75`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestHandler_Health(t *testing.T) {
+	m := NewMetrics(time.Now())
+	srv := httptest.NewServer(Handler(m))
+	defer srv.Close()
+
+	m.RecordDBProbe(time.Millisecond, nil)
+	m.RecordRun(time.Now(), nil)
+	h, err := FetchHealth(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatalf("FetchHealth failed: %v", err)
+	}
+	if h.Status != StatusOK || !h.DBUp || h.Runs != 1 {
+		t.Errorf("unexpected healthy response: %+v", h)
+	}
+
+	m.RecordRun(time.Now(), errors.New("database is locked"))
+	res, err := http.Get(srv.URL + HealthPath)
+	if err != nil {
+		t.Fatalf("GET healthz failed: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d after a failed run", res.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	h, err = FetchHealth(srv.Client(), srv.URL+"/")
+	if err != nil {
+		t.Fatalf("FetchHealth failed: %v", err)
+	}
+	if h.Status != StatusError || h.LastError != "database is locked" {
+		t.Errorf("unexpected unhealthy response: %+v", h)
+	}
+}
@@ -0,0 +1,350 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// The daemon's socket speaks JSON-RPC 2.0, one message per line. Clients
+// call methods; after calling SubscribeMethod they are also sent an
+// EventMethod notification (a message without an ID) for every event the
+// daemon publishes, such as a notification to show.
+const (
+	SubscribeMethod = "subscribe"
+	EventMethod     = "event"
+
+	// EventNotification is the type of the event published for every
+	// notification, whose data is the text to show and how to show it
+	EventNotification = "notification"
+
+	// JSON-RPC error codes
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeServerError    = -32000
+
+	// maxMessageBytes bounds a single message, so a client can't make the
+	// daemon buffer without end
+	maxMessageBytes = 1 << 20
+	// writeTimeout bounds a write, so a client that stopped reading can't
+	// hold up the others
+	writeTimeout = 5 * time.Second
+)
+
+// ErrInvalidParams is returned by a method whose params can't be decoded;
+// it is reported to the client with the JSON-RPC invalid params code
+var ErrInvalidParams = errors.New("invalid params")
+
+// Method handles a call to one RPC method. params is the raw JSON of the
+// call's params, nil when there were none. The result is encoded as JSON.
+type Method func(params json.RawMessage) (any, error)
+
+// Event is a message the daemon publishes to subscribed clients
+type Event struct {
+	Type string `json:"type"`
+	Data any    `json:"data,omitempty"`
+}
+
+// RPCError is the error of a failed call
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCServer serves RPC methods to the clients of a socket and publishes
+// events to the ones that subscribed. It is safe for concurrent use; the
+// methods themselves run on the connection's goroutine.
+type RPCServer struct {
+	mu        sync.Mutex
+	methods   map[string]Method
+	conns     map[*rpcConn]bool
+	listeners []net.Listener
+	closed    bool
+}
+
+type rpcConn struct {
+	conn       net.Conn
+	mu         sync.Mutex // serializes writes
+	subscribed bool
+}
+
+// NewRPCServer returns a server with no methods but SubscribeMethod
+func NewRPCServer() *RPCServer {
+	return &RPCServer{
+		methods: make(map[string]Method),
+		conns:   make(map[*rpcConn]bool),
+	}
+}
+
+// Handle registers fn as the method named name
+func (s *RPCServer) Handle(name string, fn Method) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.methods[name] = fn
+}
+
+// ListenUnix listens on a unix socket at path that only the current user
+// can connect to, replacing a socket a daemon that is gone left behind
+func ListenUnix(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("another daemon is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+	return listener, nil
+}
+
+// Serve accepts clients on listener until Close is called
+func (s *RPCServer) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		listener.Close()
+		return net.ErrClosed
+	}
+	s.listeners = append(s.listeners, listener)
+	s.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		c := &rpcConn{conn: conn}
+		s.mu.Lock()
+		s.conns[c] = true
+		s.mu.Unlock()
+		go s.serveConn(c)
+	}
+}
+
+// Close stops the listeners and disconnects every client
+func (s *RPCServer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for _, l := range s.listeners {
+		l.Close()
+	}
+	for c := range s.conns {
+		c.conn.Close()
+	}
+	return nil
+}
+
+// Publish sends event to every subscribed client and returns how many it
+// reached
+func (s *RPCServer) Publish(event Event) int {
+	s.mu.Lock()
+	subscribers := make([]*rpcConn, 0, len(s.conns))
+	for c := range s.conns {
+		if c.subscribed {
+			subscribers = append(subscribers, c)
+		}
+	}
+	s.mu.Unlock()
+
+	params, err := json.Marshal(event)
+	if err != nil {
+		return 0
+	}
+	sent := 0
+	for _, c := range subscribers {
+		if c.write(rpcMessage{JSONRPC: "2.0", Method: EventMethod, Params: params}) == nil {
+			sent++
+		}
+	}
+	return sent
+}
+
+// Subscribers returns how many clients receive published events
+func (s *RPCServer) Subscribers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for c := range s.conns {
+		if c.subscribed {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *RPCServer) serveConn(c *rpcConn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		c.conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxMessageBytes)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		reply, ok := s.call(c, line)
+		if !ok {
+			continue
+		}
+		if err := c.write(reply); err != nil {
+			return
+		}
+	}
+}
+
+// call runs the request in line and returns the reply to send; ok is false
+// for a notification, which gets none
+func (s *RPCServer) call(c *rpcConn, line []byte) (reply rpcMessage, ok bool) {
+	reply = rpcMessage{JSONRPC: "2.0", ID: json.RawMessage("null")}
+
+	var req rpcMessage
+	if err := json.Unmarshal(line, &req); err != nil {
+		reply.Error = &RPCError{Code: codeParseError, Message: err.Error()}
+		return reply, true
+	}
+	if len(req.ID) > 0 {
+		reply.ID = req.ID
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		reply.Error = &RPCError{Code: codeInvalidRequest, Message: "not a JSON-RPC 2.0 request"}
+		return reply, true
+	}
+
+	var result any
+	var err error
+	if req.Method == SubscribeMethod {
+		s.mu.Lock()
+		c.subscribed = true
+		s.mu.Unlock()
+		result = true
+	} else {
+		s.mu.Lock()
+		fn, found := s.methods[req.Method]
+		s.mu.Unlock()
+		if !found {
+			reply.Error = &RPCError{Code: codeMethodNotFound, Message: "unknown method " + req.Method}
+			return reply, len(req.ID) > 0
+		}
+		result, err = fn(req.Params)
+	}
+
+	if len(req.ID) == 0 {
+		return reply, false
+	}
+	switch {
+	case errors.Is(err, ErrInvalidParams):
+		reply.Error = &RPCError{Code: codeInvalidParams, Message: err.Error()}
+	case err != nil:
+		reply.Error = &RPCError{Code: codeServerError, Message: err.Error()}
+	default:
+		data, err := json.Marshal(result)
+		if err != nil {
+			reply.Error = &RPCError{Code: codeServerError, Message: err.Error()}
+		} else {
+			reply.Result = data
+		}
+	}
+	return reply, true
+}
+
+func (c *rpcConn) write(msg rpcMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err = c.conn.Write(append(data, '\n'))
+	return err
+}
+
+// Call calls method on the daemon listening on the unix socket at path and
+// decodes its result into result, which may be nil
+func Call(path, method string, params, result any) error {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to reach daemon: %w", err)
+	}
+	defer conn.Close()
+
+	req := rpcMessage{JSONRPC: "2.0", ID: json.RawMessage("1"), Method: method}
+	if params != nil {
+		if req.Params, err = json.Marshal(params); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxMessageBytes)
+	for scanner.Scan() {
+		var reply rpcMessage
+		if err := json.Unmarshal(scanner.Bytes(), &reply); err != nil {
+			return fmt.Errorf("invalid reply: %w", err)
+		}
+		if reply.Method != "" {
+			continue // An event, not the reply
+		}
+		if reply.Error != nil {
+			return reply.Error
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(reply.Result, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read reply: %w", err)
+	}
+	return errors.New("daemon closed the connection without replying")
+}
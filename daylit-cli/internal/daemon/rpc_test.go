@@ -0,0 +1,132 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startRPCServer serves s on a socket in a short temp dir, since socket paths
+// are limited to about 100 bytes
+func startRPCServer(t *testing.T, s *RPCServer) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "dl")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "d.sock")
+
+	listener, err := ListenUnix(path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go s.Serve(listener)
+	t.Cleanup(func() { s.Close() })
+	return path
+}
+
+func TestRPCServer_Call(t *testing.T) {
+	s := NewRPCServer()
+	s.Handle("echo", func(params json.RawMessage) (any, error) {
+		var p struct{ Text string }
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParams, err)
+		}
+		return p.Text, nil
+	})
+	s.Handle("fail", func(json.RawMessage) (any, error) {
+		return nil, errors.New("no slot is in progress")
+	})
+	path := startRPCServer(t, s)
+
+	var got string
+	if err := Call(path, "echo", map[string]string{"text": "hi"}, &got); err != nil || got != "hi" {
+		t.Errorf("echo = %q, %v, want hi", got, err)
+	}
+
+	tests := []struct {
+		method string
+		params any
+		code   int
+	}{
+		{"fail", nil, codeServerError},
+		{"echo", "not an object", codeInvalidParams},
+		{"missing", nil, codeMethodNotFound},
+	}
+	for _, tt := range tests {
+		var rpcErr *RPCError
+		if err := Call(path, tt.method, tt.params, nil); !errors.As(err, &rpcErr) || rpcErr.Code != tt.code {
+			t.Errorf("%s: got %v, want error code %d", tt.method, err, tt.code)
+		}
+	}
+}
+
+func TestRPCServer_Publish(t *testing.T) {
+	s := NewRPCServer()
+	path := startRPCServer(t, s)
+
+	if n := s.Publish(Event{Type: EventNotification, Data: "nobody"}); n != 0 {
+		t.Errorf("published to %d clients before any subscribed", n)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(conn)
+
+	// Malformed lines get an error reply and leave the connection open
+	fmt.Fprintln(conn, `{not json`)
+	if line, _ := reader.ReadString('\n'); !strings.Contains(line, fmt.Sprint(codeParseError)) {
+		t.Errorf("reply to malformed JSON = %q, want a parse error", line)
+	}
+
+	fmt.Fprintln(conn, `{"jsonrpc":"2.0","id":7,"method":"subscribe"}`)
+	if line, _ := reader.ReadString('\n'); !strings.Contains(line, `"id":7`) || !strings.Contains(line, `"result":true`) {
+		t.Fatalf("subscribe reply = %q", line)
+	}
+	if n := s.Subscribers(); n != 1 {
+		t.Fatalf("subscribers = %d, want 1", n)
+	}
+
+	if n := s.Publish(Event{Type: EventNotification, Data: map[string]string{"text": "Write starts now"}}); n != 1 {
+		t.Errorf("published to %d clients, want 1", n)
+	}
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("failed to read event: %v", err)
+	}
+	var msg struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params struct {
+			Type string `json:"type"`
+			Data struct {
+				Text string `json:"text"`
+			} `json:"data"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal([]byte(line), &msg); err != nil {
+		t.Fatalf("invalid event %q: %v", line, err)
+	}
+	if msg.ID != nil || msg.Method != EventMethod || msg.Params.Type != EventNotification || msg.Params.Data.Text != "Write starts now" {
+		t.Errorf("event = %q", line)
+	}
+}
+
+func TestListenUnix_InUse(t *testing.T) {
+	path := startRPCServer(t, NewRPCServer())
+	if _, err := ListenUnix(path); err == nil {
+		t.Error("listening on a socket a daemon is serving succeeded")
+	}
+}
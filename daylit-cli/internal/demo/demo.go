@@ -0,0 +1,318 @@
+// Package demo fills a store with made-up but realistic data: tasks, two
+// weeks of accepted plans with feedback, habit streaks and One Thing
+// entries, so daylit can be explored without touching real data.
+package demo
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+// HistoryDays is how many days before today get a plan
+const HistoryDays = 14
+
+// habitRates is how often each demo habit is kept up, so the streaks differ
+var habitRates = []struct {
+	Name string
+	Rate float64
+}{
+	{"Meditate", 1},
+	{"Read 20 pages", 0.75},
+	{"No phone after 22:00", 0.5},
+}
+
+var otTitles = []string{
+	"Finish the chapter draft",
+	"Send the quarterly report",
+	"Fix the flaky login test",
+	"Call the landlord about the heater",
+	"Outline next week's talk",
+	"Clear the review queue",
+	"Book the dentist",
+}
+
+var dayNotes = map[int]string{
+	3:  "Slept badly; took the afternoon slow.",
+	9:  "Great focus day, the chapter finally clicked.",
+	12: "Back-to-back interruptions after lunch.",
+}
+
+// Populate fills store with demo data around today. The same seed and day
+// give the same data.
+func Populate(store storage.Provider, sched *scheduler.Scheduler, today time.Time, seed uint64) error {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	settings, err := store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	project := models.Project{ID: uuid.New().String(), Name: "Writing", TargetHoursPerWeek: 6, Color: "#7aa2f7", CreatedAt: today}
+	if err := store.AddProject(project); err != nil {
+		return fmt.Errorf("failed to add project: %w", err)
+	}
+
+	tasks := demoTasks(project.ID)
+	for _, task := range tasks {
+		if err := store.AddTask(task); err != nil {
+			return fmt.Errorf("failed to add task %q: %w", task.Name, err)
+		}
+	}
+
+	// Plan each past day with the real scheduler, then play it out
+	for offset := HistoryDays; offset >= 1; offset-- {
+		date := today.AddDate(0, 0, -offset).Format(constants.DateFormat)
+		plan, err := sched.GeneratePlan(date, tasks, settings.DayStart, settings.DayEnd)
+		if err != nil {
+			return fmt.Errorf("failed to plan %s: %w", date, err)
+		}
+		for i := range plan.Slots {
+			playOut(rng, &plan.Slots[i], taskByID(tasks, plan.Slots[i].TaskID), date)
+		}
+		plan.Note = dayNotes[offset]
+		if err := savePlan(store, plan, date, settings.DayStart); err != nil {
+			return err
+		}
+	}
+
+	// Today is under way: slots that have ended are done, the rest are ahead
+	date := today.Format(constants.DateFormat)
+	plan, err := sched.GeneratePlan(date, tasks, settings.DayStart, settings.DayEnd)
+	if err != nil {
+		return fmt.Errorf("failed to plan today: %w", err)
+	}
+	now := today.Hour()*60 + today.Minute()
+	for i := range plan.Slots {
+		slot := &plan.Slots[i]
+		slot.Status = constants.SlotStatusAccepted
+		if end, err := utils.ParseTimeToMinutes(slot.End); err == nil && end <= now {
+			playOut(rng, slot, taskByID(tasks, slot.TaskID), date)
+		}
+	}
+	if err := savePlan(store, plan, date, settings.DayStart); err != nil {
+		return err
+	}
+
+	// Save the tasks' statistics from the days played out
+	for _, task := range tasks {
+		stored, err := store.GetTask(task.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get task %q: %w", task.Name, err)
+		}
+		stored.LastDone = task.LastDone
+		stored.SuccessStreak = task.SuccessStreak
+		stored.AvgActualDurationMin = task.AvgActualDurationMin
+		if err := store.UpdateTask(stored); err != nil {
+			return fmt.Errorf("failed to update task %q: %w", task.Name, err)
+		}
+	}
+
+	if err := addHabits(store, rng, today); err != nil {
+		return err
+	}
+	if err := addOTEntries(store, rng, today); err != nil {
+		return err
+	}
+
+	alert := models.Alert{
+		ID:         uuid.New().String(),
+		Message:    "Stand up and stretch",
+		Time:       "15:00",
+		Recurrence: models.Recurrence{Type: constants.RecurrenceWeekdays},
+		Active:     true,
+		CreatedAt:  today,
+	}
+	if err := store.AddAlert(alert); err != nil {
+		return fmt.Errorf("failed to add alert: %w", err)
+	}
+
+	for i, text := range []string{"buy stamps", "look into a standing desk"} {
+		item := models.InboxItem{ID: uuid.New().String(), Text: text, CreatedAt: today.Add(time.Duration(i-2) * time.Hour)}
+		if err := store.AddInboxItem(item); err != nil {
+			return fmt.Errorf("failed to add inbox item: %w", err)
+		}
+	}
+	return nil
+}
+
+func demoTasks(projectID string) []models.Task {
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	weekdays := models.Recurrence{Type: constants.RecurrenceWeekdays}
+	task := func(name string, kind constants.TaskKind, duration int, rec models.Recurrence, priority int, energy constants.EnergyBand) models.Task {
+		return models.Task{
+			ID: uuid.New().String(), Name: name, Kind: kind, DurationMin: duration,
+			Recurrence: rec, Priority: priority, EnergyBand: energy, Active: true,
+		}
+	}
+
+	writing := task("Write chapter draft", constants.TaskKindFlexible, 90, weekdays, 1, constants.EnergyHigh)
+	writing.ProjectID = projectID
+	writing.EarliestStart, writing.LatestEnd = "08:00", "12:00"
+	editing := task("Edit yesterday's pages", constants.TaskKindFlexible, 45, models.Recurrence{Type: constants.RecurrenceNDays, IntervalDays: 2}, 2, constants.EnergyMedium)
+	editing.ProjectID = projectID
+	standup := task("Team standup", constants.TaskKindAppointment, 15, weekdays, 1, constants.EnergyLow)
+	standup.FixedStart, standup.FixedEnd = "09:30", "09:45"
+	lunch := task("Lunch", constants.TaskKindAppointment, 60, daily, 2, constants.EnergyLow)
+	lunch.FixedStart, lunch.FixedEnd = "12:30", "13:30"
+	gym := task("Gym", constants.TaskKindFlexible, 60, models.Recurrence{
+		Type:        constants.RecurrenceWeekly,
+		WeekdayMask: []time.Weekday{time.Monday, time.Wednesday, time.Friday},
+	}, 2, constants.EnergyHigh)
+	gym.EarliestStart, gym.LatestEnd = "16:00", "20:00"
+	guitar := task("Guitar practice", constants.TaskKindFlexible, 30, daily, 4, constants.EnergyMedium)
+	guitar.NiceToHave = true
+
+	return []models.Task{
+		writing,
+		editing,
+		standup,
+		lunch,
+		task("Email and messages", constants.TaskKindFlexible, 30, daily, 3, constants.EnergyLow),
+		gym,
+		task("Weekly review", constants.TaskKindFlexible, 45, models.Recurrence{
+			Type:        constants.RecurrenceWeekly,
+			WeekdayMask: []time.Weekday{time.Friday},
+		}, 2, constants.EnergyMedium),
+		task("Tidy the flat", constants.TaskKindFlexible, 20, models.Recurrence{Type: constants.RecurrenceNDays, IntervalDays: 3}, 4, constants.EnergyLow),
+		guitar,
+	}
+}
+
+func taskByID(tasks []models.Task, id string) *models.Task {
+	for i := range tasks {
+		if tasks[i].ID == id {
+			return &tasks[i]
+		}
+	}
+	return nil
+}
+
+// playOut decides how a slot went: usually done on track, sometimes too
+// much or skipped. It updates task's statistics the way feedback would.
+func playOut(rng *rand.Rand, slot *models.Slot, task *models.Task, date string) {
+	start, err := utils.ParseTimeToMinutes(slot.Start)
+	if err != nil || task == nil {
+		return
+	}
+	end, _ := utils.ParseTimeToMinutes(slot.End)
+
+	roll := rng.Float64()
+	if roll < 0.1 && task.Kind != constants.TaskKindAppointment {
+		slot.Status = constants.SlotStatusSkipped
+		task.SuccessStreak = 0
+		return
+	}
+
+	// Actual time runs from a bit short to a fair bit long
+	planned := end - start
+	actual := max(int(float64(planned)*(0.8+rng.Float64()*0.5)), 5)
+	actualStart := min(start+rng.IntN(8), 23*60+50)
+	actualEnd := min(actualStart+actual, 23*60+59)
+	slot.Status = constants.SlotStatusDone
+	slot.ActualStart = ptr(utils.FormatTimeOfDay(actualStart))
+	slot.ActualEnd = ptr(utils.FormatTimeOfDay(actualEnd))
+
+	rating := models.FeedbackRating(constants.FeedbackOnTrack)
+	note := ""
+	switch {
+	case task.Kind == constants.TaskKindAppointment:
+		// Appointments happen when they happen
+	case roll > 0.95:
+		rating, note = constants.FeedbackUnnecessary, "Could have skipped this one."
+	case roll > 0.8 || actual > planned*5/4:
+		rating, note = constants.FeedbackTooMuch, "Ran long."
+	}
+	slot.Feedback = &models.Feedback{Rating: rating, Note: note}
+
+	task.LastDone = date
+	if rating == constants.FeedbackOnTrack {
+		task.SuccessStreak++
+	} else {
+		task.SuccessStreak = 0
+	}
+	if task.AvgActualDurationMin <= 0 {
+		task.AvgActualDurationMin = float64(actual)
+	} else {
+		task.AvgActualDurationMin = task.AvgActualDurationMin*constants.FeedbackExistingWeight + float64(actual)*constants.FeedbackNewWeight
+	}
+}
+
+// savePlan saves plan as accepted at the start of its day
+func savePlan(store storage.Provider, plan models.DayPlan, date, dayStart string) error {
+	acceptedAt, err := time.ParseInLocation(constants.DateFormat+" "+constants.TimeFormat, date+" "+dayStart, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid day start: %w", err)
+	}
+	accepted := acceptedAt.UTC().Format(time.RFC3339)
+	plan.AcceptedAt = &accepted
+	plan.Revision = 0
+	for i := range plan.Slots {
+		if plan.Slots[i].Status == constants.SlotStatusPlanned {
+			plan.Slots[i].Status = constants.SlotStatusAccepted
+		}
+	}
+	if err := store.SavePlan(plan); err != nil {
+		return fmt.Errorf("failed to save plan for %s: %w", date, err)
+	}
+	return nil
+}
+
+func addHabits(store storage.Provider, rng *rand.Rand, today time.Time) error {
+	created := today.AddDate(0, 0, -HistoryDays)
+	for _, h := range habitRates {
+		habit := models.Habit{ID: uuid.New().String(), Name: h.Name, CreatedAt: created}
+		if err := store.AddHabit(habit); err != nil {
+			return fmt.Errorf("failed to add habit %q: %w", h.Name, err)
+		}
+		for offset := HistoryDays; offset >= 1; offset-- {
+			if rng.Float64() >= h.Rate {
+				continue
+			}
+			day := today.AddDate(0, 0, -offset)
+			entry := models.HabitEntry{
+				ID:        uuid.New().String(),
+				HabitID:   habit.ID,
+				Day:       day.Format(constants.DateFormat),
+				CreatedAt: day,
+				UpdatedAt: day,
+			}
+			if err := store.AddHabitEntry(entry); err != nil {
+				return fmt.Errorf("failed to add habit entry: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func addOTEntries(store storage.Provider, rng *rand.Rand, today time.Time) error {
+	for offset := HistoryDays; offset >= 0; offset-- {
+		// Not every day gets a One Thing
+		if offset > 0 && rng.Float64() < 0.3 {
+			continue
+		}
+		day := today.AddDate(0, 0, -offset)
+		entry := models.OTEntry{
+			ID:        uuid.New().String(),
+			Day:       day.Format(constants.DateFormat),
+			Title:     otTitles[rng.IntN(len(otTitles))],
+			CreatedAt: day,
+			UpdatedAt: day,
+		}
+		if err := store.AddOTEntry(entry); err != nil {
+			return fmt.Errorf("failed to add One Thing: %w", err)
+		}
+	}
+	return nil
+}
+
+func ptr(s string) *string {
+	return &s
+}
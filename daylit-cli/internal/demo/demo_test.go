@@ -0,0 +1,90 @@
+package demo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func TestPopulate(t *testing.T) {
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+
+	today := time.Date(2025, 6, 11, 15, 0, 0, 0, time.Local)
+	if err := Populate(store, scheduler.New(), today, 1); err != nil {
+		t.Fatalf("Populate failed: %v", err)
+	}
+
+	// Every past day has an accepted plan, and most slots have feedback
+	start := today.AddDate(0, 0, -HistoryDays).Format(constants.DateFormat)
+	end := today.AddDate(0, 0, -1).Format(constants.DateFormat)
+	summaries, err := store.GetDaySummaries(start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != HistoryDays {
+		t.Fatalf("expected %d days of history, got %d", HistoryDays, len(summaries))
+	}
+	withFeedback, slots := 0, 0
+	for _, s := range summaries {
+		if !s.Accepted || s.TotalSlots == 0 {
+			t.Errorf("expected an accepted plan with slots on %s, got %+v", s.Date, s)
+		}
+		withFeedback += s.SlotsWithFeedback
+		slots += s.TotalSlots
+	}
+	if withFeedback*2 < slots {
+		t.Errorf("expected most slots to have feedback, got %d of %d", withFeedback, slots)
+	}
+
+	// Today's slots that already ended are done
+	plan, err := store.GetPlan(today.Format(constants.DateFormat))
+	if err != nil {
+		t.Fatalf("expected a plan for today: %v", err)
+	}
+	for _, slot := range plan.Slots {
+		if slot.End <= "15:00" && slot.Status == constants.SlotStatusAccepted {
+			t.Errorf("expected the %s-%s slot to be played out, got %s", slot.Start, slot.End, slot.Status)
+		}
+		if slot.Start >= "15:00" && slot.Status != constants.SlotStatusAccepted {
+			t.Errorf("expected the %s-%s slot to be ahead, got %s", slot.Start, slot.End, slot.Status)
+		}
+	}
+
+	// The always-kept habit has an entry every day
+	habits, err := store.GetAllHabits(false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(habits) != len(habitRates) {
+		t.Fatalf("expected %d habits, got %d", len(habitRates), len(habits))
+	}
+	for _, habit := range habits {
+		if habit.Name != "Meditate" {
+			continue
+		}
+		entries, err := store.GetHabitEntriesForHabit(habit.ID, start, end)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != HistoryDays {
+			t.Errorf("expected a %d-day Meditate streak, got %d entries", HistoryDays, len(entries))
+		}
+	}
+
+	tasks, err := store.GetAllTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range tasks {
+		if err := task.Validate(); err != nil {
+			t.Errorf("demo task %q is invalid: %v", task.Name, err)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// Package hooks runs the user's scripts when something happens in their day,
+// such as a plan being accepted or a slot starting. A hook is an executable
+// in the hooks directory named after its event, like slot_started or
+// slot_started.sh; several hooks for one event run in name order. Each hook
+// reads the event as JSON on stdin.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+// Event names a point in the day that hooks can run on
+type Event string
+
+const (
+	PlanAccepted     Event = "plan_accepted"     // A plan was accepted, by hand or hands-free
+	SlotStarted      Event = "slot_started"      // 'daylit start' began a slot
+	SlotDone         Event = "slot_done"         // A slot was finished or marked done
+	FeedbackRecorded Event = "feedback_recorded" // A slot was rated
+	HabitMarked      Event = "habit_marked"      // A habit was marked done for a day
+)
+
+// Events lists every event in the order they usually happen
+var Events = []Event{PlanAccepted, SlotStarted, SlotDone, FeedbackRecorded, HabitMarked}
+
+// ParseEvent returns the event with the given name
+func ParseEvent(name string) (Event, error) {
+	for _, e := range Events {
+		if string(e) == name {
+			return e, nil
+		}
+	}
+	names := make([]string, len(Events))
+	for i, e := range Events {
+		names[i] = string(e)
+	}
+	return "", fmt.Errorf("unknown event %q (expected one of %s)", name, strings.Join(names, ", "))
+}
+
+// Payload is the JSON a hook reads on stdin
+type Payload struct {
+	Event Event  `json:"event"`
+	Time  string `json:"time"` // RFC 3339
+	Data  any    `json:"data"`
+}
+
+// SlotData describes a slot for slot and feedback events
+type SlotData struct {
+	Date        string `json:"date"`
+	Start       string `json:"start"`
+	End         string `json:"end"`
+	TaskID      string `json:"task_id"`
+	TaskName    string `json:"task_name"`
+	Status      string `json:"status"`
+	ActualStart string `json:"actual_start,omitempty"`
+	ActualEnd   string `json:"actual_end,omitempty"`
+	Rating      string `json:"rating,omitempty"`
+	Note        string `json:"note,omitempty"`
+}
+
+// PlanData describes an accepted plan
+type PlanData struct {
+	Date     string     `json:"date"`
+	Revision int        `json:"revision"`
+	Slots    []SlotData `json:"slots"`
+}
+
+// HabitData describes a habit marked for a day
+type HabitData struct {
+	HabitID string `json:"habit_id"`
+	Name    string `json:"name"`
+	Day     string `json:"day"`
+	Note    string `json:"note,omitempty"`
+}
+
+// Slot describes slot on date, looking up its task's name in store
+func Slot(store storage.Provider, date string, slot models.Slot) SlotData {
+	data := SlotData{
+		Date:     date,
+		Start:    slot.Start,
+		End:      slot.End,
+		TaskID:   slot.TaskID,
+		TaskName: "Unknown task",
+		Status:   string(slot.Status),
+	}
+	if task, err := store.GetTask(slot.TaskID); err == nil {
+		data.TaskName = task.Name
+	}
+	if slot.ActualStart != nil {
+		data.ActualStart = *slot.ActualStart
+	}
+	if slot.ActualEnd != nil {
+		data.ActualEnd = *slot.ActualEnd
+	}
+	if slot.Feedback != nil {
+		data.Rating = string(slot.Feedback.Rating)
+		data.Note = slot.Feedback.Note
+	}
+	return data
+}
+
+// Plan describes plan, looking up its tasks' names in store
+func Plan(store storage.Provider, plan models.DayPlan) PlanData {
+	data := PlanData{Date: plan.Date, Revision: plan.Revision, Slots: []SlotData{}}
+	for _, slot := range plan.Slots {
+		data.Slots = append(data.Slots, Slot(store, plan.Date, slot))
+	}
+	return data
+}
+
+// Dir returns the hooks directory: $DAYLIT_HOOKS_DIR, or
+// ~/.config/daylit/hooks
+func Dir() string {
+	if dir := os.Getenv(constants.HooksDirEnv); dir != "" {
+		return dir
+	}
+	dir := constants.DefaultHooksDir
+	if home, err := os.UserHomeDir(); err == nil {
+		dir = filepath.Join(home, strings.TrimPrefix(dir, "~/"))
+	}
+	return dir
+}
+
+// Find returns the hooks in dir for event, in name order. A missing
+// directory has no hooks, and files that aren't executable are skipped.
+func Find(dir string, event Event) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if name != string(event) && !strings.HasPrefix(name, string(event)+".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, name))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Runner runs the hooks in a directory
+type Runner struct {
+	Dir     string
+	Timeout time.Duration
+	Stdout  io.Writer // Where hooks' output goes; discarded when nil
+	Now     func() time.Time
+}
+
+// Run runs every hook for event with the event and data as JSON on stdin.
+// A failing hook doesn't stop the others; their errors are returned
+// together.
+func (r Runner) Run(event Event, data any) error {
+	paths, err := Find(r.Dir, event)
+	if err != nil {
+		return fmt.Errorf("failed to read hooks directory: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	now := time.Now
+	if r.Now != nil {
+		now = r.Now
+	}
+	input, err := json.Marshal(Payload{Event: event, Time: now().Format(time.RFC3339), Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event, err)
+	}
+
+	var errs []error
+	for _, path := range paths {
+		if err := r.runHook(path, event, input); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(path), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r Runner) runHook(path string, event Event, input []byte) error {
+	ctx := context.Background()
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Dir = r.Dir
+	cmd.Env = append(os.Environ(), "DAYLIT_EVENT="+string(event))
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = r.Stdout
+	cmd.Stderr = &stderr
+	// Don't wait on children of a killed hook that still hold its output open
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", r.Timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// suppressed is set while hooks must not run
+var suppressed atomic.Bool
+
+// Suppress keeps Fire from running hooks, such as while a simulation does
+// what would happen without keeping any of it, until the returned function
+// is called
+func Suppress() (restore func()) {
+	previous := suppressed.Swap(true)
+	return func() { suppressed.Store(previous) }
+}
+
+// Fire runs the hooks for event in the hooks directory. Hooks never stop the
+// command that fired them, so failures are only logged.
+func Fire(event Event, data any) {
+	if suppressed.Load() {
+		return
+	}
+	runner := Runner{Dir: Dir(), Timeout: constants.HookTimeout}
+	if err := runner.Run(event, data); err != nil {
+		logger.Warn("Hook failed", "event", event, "error", err)
+	}
+}
@@ -0,0 +1,106 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeHook(t *testing.T, dir, name, script string, mode os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), mode); err != nil {
+		t.Fatalf("failed to write hook: %v", err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are found by their executable bit")
+	}
+	dir := t.TempDir()
+	writeHook(t, dir, "slot_started", "", 0755)
+	writeHook(t, dir, "slot_started.sh", "", 0755)
+	writeHook(t, dir, "slot_started.txt", "", 0644) // Not executable
+	writeHook(t, dir, "slot_started_extra", "", 0755)
+	writeHook(t, dir, "slot_done", "", 0755)
+
+	paths, err := Find(dir, SlotStarted)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	var names []string
+	for _, p := range paths {
+		names = append(names, filepath.Base(p))
+	}
+	if strings.Join(names, ",") != "slot_started,slot_started.sh" {
+		t.Errorf("expected slot_started and slot_started.sh, got %v", names)
+	}
+
+	paths, err = Find(filepath.Join(dir, "missing"), SlotStarted)
+	if err != nil || len(paths) != 0 {
+		t.Errorf("expected no hooks and no error for a missing directory, got %v, %v", paths, err)
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts in this test")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.json")
+	writeHook(t, dir, "habit_marked", `cat > "`+out+`"; echo "$DAYLIT_EVENT" > "`+out+`.event"`+"\n", 0755)
+	writeHook(t, dir, "habit_marked.fail", "echo broken >&2; exit 3\n", 0755)
+
+	now := time.Date(2026, 11, 2, 7, 30, 0, 0, time.UTC)
+	runner := Runner{Dir: dir, Timeout: 5 * time.Second, Now: func() time.Time { return now }}
+	err := runner.Run(HabitMarked, HabitData{HabitID: "h1", Name: "Meditate", Day: "2026-11-02"})
+	if err == nil || !strings.Contains(err.Error(), "habit_marked.fail") || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("expected the failing hook's error with its stderr, got %v", err)
+	}
+
+	// The failing hook doesn't stop the other one
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook didn't run: %v", err)
+	}
+	var payload struct {
+		Event Event     `json:"event"`
+		Time  string    `json:"time"`
+		Data  HabitData `json:"data"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("hook got invalid JSON %q: %v", data, err)
+	}
+	if payload.Event != HabitMarked || payload.Time != "2026-11-02T07:30:00Z" || payload.Data.Name != "Meditate" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+	if env, _ := os.ReadFile(out + ".event"); strings.TrimSpace(string(env)) != "habit_marked" {
+		t.Errorf("expected DAYLIT_EVENT=habit_marked, got %q", env)
+	}
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts in this test")
+	}
+	dir := t.TempDir()
+	writeHook(t, dir, "slot_done", "sleep 5\n", 0755)
+
+	runner := Runner{Dir: dir, Timeout: 100 * time.Millisecond}
+	if err := runner.Run(SlotDone, SlotData{}); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
+
+func TestParseEvent(t *testing.T) {
+	if e, err := ParseEvent("plan_accepted"); err != nil || e != PlanAccepted {
+		t.Errorf("expected plan_accepted, got %q, %v", e, err)
+	}
+	if _, err := ParseEvent("plan_rejected"); err == nil {
+		t.Error("expected an error for an unknown event")
+	}
+}
@@ -0,0 +1,198 @@
+// Package i18n holds the message catalogs for user-facing text and formats
+// dates with the month and weekday names of the active locale.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	English = "en"
+	Spanish = "es"
+
+	// EnvLocale overrides the locale setting and the system locale
+	EnvLocale = "DAYLIT_LANG"
+)
+
+// Supported lists the locales with a catalog, English first
+var Supported = []string{English, Spanish}
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+var (
+	catalogs = loadCatalogs()
+	current  = English
+)
+
+func loadCatalogs() map[string]map[string]string {
+	out := make(map[string]map[string]string, len(Supported))
+	for _, locale := range Supported {
+		data, err := localesFS.ReadFile("locales/" + locale + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog for %s: %v", locale, err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog for %s: %v", locale, err))
+		}
+		out[locale] = messages
+	}
+	return out
+}
+
+// Parse reads a locale name such as "es", "es_ES.UTF-8" or "en-US" and
+// returns the supported locale it names
+func Parse(name string) (string, bool) {
+	lang := strings.ToLower(name)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	for _, locale := range Supported {
+		if lang == locale {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Validate checks a locale setting; empty follows the environment
+func Validate(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, ok := Parse(name); !ok {
+		return fmt.Errorf("unsupported locale %q (supported: %s)", name, strings.Join(Supported, ", "))
+	}
+	return nil
+}
+
+// Resolve picks the locale to use: DAYLIT_LANG, then the locale setting,
+// then the system locale from LC_ALL, LC_MESSAGES or LANG, then English.
+// Names that aren't supported are skipped.
+func Resolve(setting string) string {
+	candidates := []string{os.Getenv(EnvLocale), setting, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")}
+	for _, name := range candidates {
+		if locale, ok := Parse(name); ok {
+			return locale
+		}
+	}
+	return English
+}
+
+// Set makes locale the active locale. It is called once at startup, before
+// any output; unsupported names select English.
+func Set(locale string) {
+	if l, ok := Parse(locale); ok {
+		current = l
+		return
+	}
+	current = English
+}
+
+// Current returns the active locale
+func Current() string {
+	return current
+}
+
+// T returns the message for key in the active locale, formatted with args
+// when any are given. Messages missing from the catalog fall back to
+// English, and to the key itself if English lacks them too.
+func T(key string, args ...interface{}) string {
+	msg, ok := catalogs[current][key]
+	if !ok {
+		if msg, ok = catalogs[English][key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Layout names of the date layouts in the catalogs, for Format
+const (
+	LayoutMonthYear    = "layout.month_year"     // e.g. "January 2006"
+	LayoutDayMonth     = "layout.day_month"      // e.g. "Jan 2"
+	LayoutDayMonthYear = "layout.day_month_year" // e.g. "Jan 2, 2006"
+	LayoutWeekdayDate  = "layout.weekday_date"   // e.g. "Mon 01/02"
+	LayoutTimestamp    = "layout.timestamp"      // e.g. "Mon Jan 2 15:04"
+	LayoutLongDate     = "layout.long_date"      // e.g. "Monday, January 2"
+)
+
+// Weekday returns the full name of d in the active locale
+func Weekday(d time.Weekday) string {
+	return T(fmt.Sprintf("weekday.%d", d))
+}
+
+// WeekdayShort returns the abbreviated name of d in the active locale
+func WeekdayShort(d time.Weekday) string {
+	return T(fmt.Sprintf("weekday_short.%d", d))
+}
+
+// Month returns the full name of m in the active locale
+func Month(m time.Month) string {
+	return T(fmt.Sprintf("month.%d", m))
+}
+
+// MonthShort returns the abbreviated name of m in the active locale
+func MonthShort(m time.Month) string {
+	return T(fmt.Sprintf("month_short.%d", m))
+}
+
+// Format formats t with the active locale's date layout named by layout,
+// one of the Layout constants
+func Format(t time.Time, layout string) string {
+	return FormatLayout(t, T(layout))
+}
+
+// nameTokens are the layout elements that spell out a name, longest first so
+// "January" isn't read as "Jan" followed by "uary"
+var nameTokens = []string{"January", "Monday", "Jan", "Mon"}
+
+// FormatLayout formats t like time.Format, with month and weekday names in
+// the active locale
+func FormatLayout(t time.Time, layout string) string {
+	var b strings.Builder
+	for layout != "" {
+		i, token := nextNameToken(layout)
+		if i < 0 {
+			b.WriteString(t.Format(layout))
+			break
+		}
+		if i > 0 {
+			b.WriteString(t.Format(layout[:i]))
+		}
+		switch token {
+		case "January":
+			b.WriteString(Month(t.Month()))
+		case "Jan":
+			b.WriteString(MonthShort(t.Month()))
+		case "Monday":
+			b.WriteString(Weekday(t.Weekday()))
+		case "Mon":
+			b.WriteString(WeekdayShort(t.Weekday()))
+		}
+		layout = layout[i+len(token):]
+	}
+	return b.String()
+}
+
+// nextNameToken finds the first name element in layout, returning its
+// index, or -1 if there is none
+func nextNameToken(layout string) (int, string) {
+	best, bestToken := -1, ""
+	for _, token := range nameTokens {
+		i := strings.Index(layout, token)
+		if i >= 0 && (best < 0 || i < best || (i == best && len(token) > len(bestToken))) {
+			best, bestToken = i, token
+		}
+	}
+	return best, bestToken
+}
@@ -0,0 +1,135 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+)
+
+var verb = regexp.MustCompile(`%(\[\d+\])?[-+#0]*[\d.]*[a-zA-Z%]`)
+
+// verbs returns the formatting verbs of msg without argument indexes, sorted
+func verbs(msg string) []string {
+	var out []string
+	for _, v := range verb.FindAllString(msg, -1) {
+		out = append(out, regexp.MustCompile(`\[\d+\]`).ReplaceAllString(v, ""))
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestCatalogsMatch(t *testing.T) {
+	en := catalogs[English]
+	for _, locale := range Supported[1:] {
+		other := catalogs[locale]
+		for key, msg := range en {
+			translated, ok := other[key]
+			if !ok {
+				t.Errorf("%s catalog is missing %q", locale, key)
+				continue
+			}
+			if !slices.Equal(verbs(msg), verbs(translated)) {
+				t.Errorf("%s %q has verbs %v, English has %v", locale, key, verbs(translated), verbs(msg))
+			}
+		}
+		for key := range other {
+			if _, ok := en[key]; !ok {
+				t.Errorf("%s catalog has %q, which English lacks", locale, key)
+			}
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"es", Spanish, true},
+		{"es_ES.UTF-8", Spanish, true},
+		{"en-US", English, true},
+		{"EN", English, true},
+		{"fr_FR", "", false},
+		{"C.UTF-8", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Parse(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv(EnvLocale, "")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "C.UTF-8")
+
+	if got := Resolve(""); got != English {
+		t.Errorf("expected English without a setting or supported system locale, got %s", got)
+	}
+	if got := Resolve("es"); got != Spanish {
+		t.Errorf("expected the setting to be used, got %s", got)
+	}
+
+	t.Setenv("LANG", "es_MX.UTF-8")
+	if got := Resolve(""); got != Spanish {
+		t.Errorf("expected the system locale to be used, got %s", got)
+	}
+
+	t.Setenv(EnvLocale, "en")
+	if got := Resolve("es"); got != English {
+		t.Errorf("expected %s to override the setting, got %s", EnvLocale, got)
+	}
+}
+
+func TestT(t *testing.T) {
+	defer Set(English)
+
+	Set(Spanish)
+	if got := T("notify.starting_now", "Leer", "07:00"); got != "Empieza ahora: Leer (07:00)" {
+		t.Errorf("unexpected Spanish message: %q", got)
+	}
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("expected a missing key to be returned as is, got %q", got)
+	}
+
+	Set("fr")
+	if Current() != English {
+		t.Errorf("expected an unsupported locale to select English, got %s", Current())
+	}
+	if got := T("now.no_plan"); got != "No active plan for today." {
+		t.Errorf("unexpected English message: %q", got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	defer Set(English)
+	day := time.Date(2026, time.January, 5, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale string
+		layout string
+		want   string
+	}{
+		{English, LayoutMonthYear, "January 2026"},
+		{English, LayoutDayMonthYear, "Jan 5, 2026"},
+		{English, LayoutWeekdayDate, "Mon 01/05"},
+		{English, LayoutLongDate, "Monday, January 5"},
+		{Spanish, LayoutMonthYear, "enero de 2026"},
+		{Spanish, LayoutDayMonthYear, "5 ene 2026"},
+		{Spanish, LayoutWeekdayDate, "lun 05/01"},
+		{Spanish, LayoutTimestamp, "lun 5 ene 09:30"},
+		{Spanish, LayoutLongDate, "lunes, 5 de enero"},
+	}
+	for _, tt := range tests {
+		Set(tt.locale)
+		if got := Format(day, tt.layout); got != tt.want {
+			t.Errorf("%s %s: got %q, want %q", tt.locale, tt.layout, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Package importer converts task lists from other tools into daylit tasks.
+package importer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// Options controls the defaults applied to imported tasks
+type Options struct {
+	DurationMin int       // Duration given to every imported task
+	Priority    int       // Priority used when the source has none
+	Today       time.Time // Anchor for recurrences without a due date
+}
+
+// periodPattern matches short periods such as "3d", "+1w", or "2m"
+var periodPattern = regexp.MustCompile(`^\+?(\d+)\s*([dwmy])$`)
+
+// recurrenceFromPeriod maps a repeat period such as "daily", "weekly", "3d", or
+// "1m" onto a daylit recurrence, using anchor for the weekday or day of month.
+// It reports false for periods that have no daylit equivalent.
+func recurrenceFromPeriod(period string, anchor time.Time) (models.Recurrence, bool) {
+	period = strings.ToLower(strings.TrimSpace(period))
+
+	switch period {
+	case "daily", "day":
+		return models.Recurrence{Type: constants.RecurrenceDaily}, true
+	case "weekdays":
+		return models.Recurrence{Type: constants.RecurrenceWeekdays}, true
+	case "weekly", "week":
+		return weekly(anchor), true
+	case "biweekly", "fortnight":
+		return models.Recurrence{Type: constants.RecurrenceNDays, IntervalDays: 14}, true
+	case "monthly", "month":
+		return monthly(anchor), true
+	case "yearly", "annual", "year":
+		return yearly(anchor), true
+	}
+
+	m := periodPattern.FindStringSubmatch(period)
+	if m == nil {
+		return models.Recurrence{}, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n < 1 {
+		return models.Recurrence{}, false
+	}
+
+	switch m[2] {
+	case "d":
+		if n == 1 {
+			return models.Recurrence{Type: constants.RecurrenceDaily}, true
+		}
+		return models.Recurrence{Type: constants.RecurrenceNDays, IntervalDays: n}, true
+	case "w":
+		if n == 1 {
+			return weekly(anchor), true
+		}
+		return models.Recurrence{Type: constants.RecurrenceNDays, IntervalDays: n * 7}, true
+	case "m":
+		if n == 1 {
+			return monthly(anchor), true
+		}
+	case "y":
+		if n == 1 {
+			return yearly(anchor), true
+		}
+	}
+	return models.Recurrence{}, false
+}
+
+func weekly(anchor time.Time) models.Recurrence {
+	return models.Recurrence{Type: constants.RecurrenceWeekly, WeekdayMask: []time.Weekday{anchor.Weekday()}}
+}
+
+func monthly(anchor time.Time) models.Recurrence {
+	return models.Recurrence{Type: constants.RecurrenceMonthlyDate, MonthDay: anchor.Day()}
+}
+
+func yearly(anchor time.Time) models.Recurrence {
+	return models.Recurrence{Type: constants.RecurrenceYearly, Month: int(anchor.Month()), MonthDay: anchor.Day()}
+}
+
+// newTask returns a flexible, active task with the option defaults applied
+func newTask(name string, opts Options) models.Task {
+	return models.Task{
+		ID:                   uuid.New().String(),
+		Name:                 name,
+		Kind:                 constants.TaskKindFlexible,
+		DurationMin:          opts.DurationMin,
+		Recurrence:           models.Recurrence{Type: constants.RecurrenceAdHoc},
+		Priority:             opts.Priority,
+		Active:               true,
+		AvgActualDurationMin: float64(opts.DurationMin),
+	}
+}
@@ -0,0 +1,161 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+var testOpts = Options{
+	DurationMin: 30,
+	Priority:    3,
+	Today:       time.Date(2026, 3, 4, 0, 0, 0, 0, time.Local), // a Wednesday
+}
+
+func TestRecurrenceFromPeriod(t *testing.T) {
+	anchor := time.Date(2026, 3, 6, 0, 0, 0, 0, time.Local) // a Friday
+
+	tests := []struct {
+		period   string
+		wantType constants.RecurrenceType
+		wantDays int
+		ok       bool
+	}{
+		{"daily", constants.RecurrenceDaily, 0, true},
+		{"1d", constants.RecurrenceDaily, 0, true},
+		{"3d", constants.RecurrenceNDays, 3, true},
+		{"+2d", constants.RecurrenceNDays, 2, true},
+		{"weekdays", constants.RecurrenceWeekdays, 0, true},
+		{"weekly", constants.RecurrenceWeekly, 0, true},
+		{"1w", constants.RecurrenceWeekly, 0, true},
+		{"2w", constants.RecurrenceNDays, 14, true},
+		{"biweekly", constants.RecurrenceNDays, 14, true},
+		{"monthly", constants.RecurrenceMonthlyDate, 0, true},
+		{"1y", constants.RecurrenceYearly, 0, true},
+		{"3m", "", 0, false},
+		{"0d", "", 0, false},
+		{"quarterly", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			rec, ok := recurrenceFromPeriod(tt.period, anchor)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if rec.Type != tt.wantType {
+				t.Errorf("type = %s, want %s", rec.Type, tt.wantType)
+			}
+			if rec.IntervalDays != tt.wantDays {
+				t.Errorf("interval = %d, want %d", rec.IntervalDays, tt.wantDays)
+			}
+			switch rec.Type {
+			case constants.RecurrenceWeekly:
+				if len(rec.WeekdayMask) != 1 || rec.WeekdayMask[0] != time.Friday {
+					t.Errorf("weekdays = %v, want [Friday]", rec.WeekdayMask)
+				}
+			case constants.RecurrenceMonthlyDate:
+				if rec.MonthDay != 6 {
+					t.Errorf("month day = %d, want 6", rec.MonthDay)
+				}
+			case constants.RecurrenceYearly:
+				if rec.Month != 3 || rec.MonthDay != 6 {
+					t.Errorf("yearly = %d/%d, want 3/6", rec.Month, rec.MonthDay)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTaskwarrior(t *testing.T) {
+	export := `[
+{"uuid":"a","description":"Write report","status":"pending","priority":"H"},
+{"uuid":"b","description":"Old thing","status":"completed"},
+{"uuid":"c","description":"Water plants","status":"recurring","recur":"weekly","due":"20260306T120000Z"},
+{"uuid":"d","description":"Water plants","status":"pending","parent":"c"},
+{"uuid":"e","description":"Call mom","status":"waiting","priority":"L"}
+]`
+
+	tasks, err := ParseTaskwarrior(strings.NewReader(export), testOpts)
+	if err != nil {
+		t.Fatalf("ParseTaskwarrior failed: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("got %d tasks, want 3", len(tasks))
+	}
+
+	if tasks[0].Name != "Write report" || tasks[0].Priority != 1 {
+		t.Errorf("task 0 = %q priority %d, want %q priority 1", tasks[0].Name, tasks[0].Priority, "Write report")
+	}
+	if tasks[0].Recurrence.Type != constants.RecurrenceAdHoc || tasks[0].DurationMin != 30 {
+		t.Errorf("task 0 should use the ad hoc default with 30m, got %s %dm", tasks[0].Recurrence.Type, tasks[0].DurationMin)
+	}
+	if tasks[1].Recurrence.Type != constants.RecurrenceWeekly || tasks[1].Recurrence.WeekdayMask[0] != time.Friday {
+		t.Errorf("task 1 recurrence = %+v, want weekly on Friday", tasks[1].Recurrence)
+	}
+	if tasks[2].Priority != 5 {
+		t.Errorf("task 2 priority = %d, want 5", tasks[2].Priority)
+	}
+
+	// One object per line is also accepted
+	lines := `{"description":"One","status":"pending"}
+{"description":"Two","status":"pending"}`
+	tasks, err = ParseTaskwarrior(strings.NewReader(lines), testOpts)
+	if err != nil {
+		t.Fatalf("ParseTaskwarrior (lines) failed: %v", err)
+	}
+	if len(tasks) != 2 || tasks[1].Name != "Two" {
+		t.Errorf("line-delimited export parsed as %+v", tasks)
+	}
+
+	if _, err := ParseTaskwarrior(strings.NewReader("[{"), testOpts); err == nil {
+		t.Error("expected error for malformed export")
+	}
+}
+
+func TestParseTodoTxt(t *testing.T) {
+	input := `(A) 2026-03-01 Call dentist +health @phone
+x 2026-03-02 Done already
+Water plants rec:1w due:2026-03-06
+
+(E) Stretch rec:daily
++project @context
+Read http://example.com t:2026-03-10
+`
+
+	tasks, err := ParseTodoTxt(strings.NewReader(input), testOpts)
+	if err != nil {
+		t.Fatalf("ParseTodoTxt failed: %v", err)
+	}
+
+	want := []struct {
+		name     string
+		priority int
+		rec      constants.RecurrenceType
+	}{
+		{"Call dentist", 1, constants.RecurrenceAdHoc},
+		{"Water plants", 3, constants.RecurrenceWeekly},
+		{"Stretch", 5, constants.RecurrenceDaily},
+		{"Read http://example.com", 3, constants.RecurrenceAdHoc},
+	}
+	if len(tasks) != len(want) {
+		t.Fatalf("got %d tasks, want %d: %+v", len(tasks), len(want), tasks)
+	}
+	for i, w := range want {
+		if tasks[i].Name != w.name || tasks[i].Priority != w.priority || tasks[i].Recurrence.Type != w.rec {
+			t.Errorf("task %d = %q priority %d %s, want %q priority %d %s",
+				i, tasks[i].Name, tasks[i].Priority, tasks[i].Recurrence.Type, w.name, w.priority, w.rec)
+		}
+	}
+	if tasks[0].Context != "phone" || tasks[1].Context != "" {
+		t.Errorf("contexts = %q, %q, want %q, %q", tasks[0].Context, tasks[1].Context, "phone", "")
+	}
+	if mask := tasks[1].Recurrence.WeekdayMask; len(mask) != 1 || mask[0] != time.Friday {
+		t.Errorf("weekly recurrence should be anchored on the due date, got %v", mask)
+	}
+}
@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// taskwarriorDateFormat is the compact UTC format used in Taskwarrior exports
+const taskwarriorDateFormat = "20060102T150405Z"
+
+// taskwarriorTask holds the fields of a Taskwarrior export entry that daylit uses
+type taskwarriorTask struct {
+	UUID        string `json:"uuid"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	Priority    string `json:"priority"`
+	Recur       string `json:"recur"`
+	Due         string `json:"due"`
+	Parent      string `json:"parent"`
+}
+
+// ParseTaskwarrior reads the output of `task export`, either a JSON array or
+// one JSON object per line. Completed and deleted tasks are skipped, as are
+// the generated instances of recurring tasks; the recurring parent is imported
+// with the matching daylit recurrence.
+func ParseTaskwarrior(r io.Reader, opts Options) ([]models.Task, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Taskwarrior export: %w", err)
+	}
+
+	var entries []taskwarriorTask
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, fmt.Errorf("invalid Taskwarrior export: %w", err)
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		for {
+			var e taskwarriorTask
+			if err := dec.Decode(&e); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("invalid Taskwarrior export: %w", err)
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	return taskwarriorTasks(entries, opts), nil
+}
+
+func taskwarriorTasks(entries []taskwarriorTask, opts Options) []models.Task {
+	var tasks []models.Task
+	for _, e := range entries {
+		name := strings.TrimSpace(e.Description)
+		if name == "" || e.Parent != "" {
+			continue
+		}
+		switch e.Status {
+		case "completed", "deleted":
+			continue
+		}
+
+		task := newTask(name, opts)
+		switch strings.ToUpper(e.Priority) {
+		case "H":
+			task.Priority = 1
+		case "M":
+			task.Priority = 3
+		case "L":
+			task.Priority = 5
+		}
+
+		if e.Recur != "" {
+			anchor := opts.Today
+			if due, err := time.Parse(taskwarriorDateFormat, e.Due); err == nil {
+				anchor = due.Local()
+			}
+			if rec, ok := recurrenceFromPeriod(e.Recur, anchor); ok {
+				task.Recurrence = rec
+			}
+		}
+
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
@@ -0,0 +1,89 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// todoPriorityPattern matches a leading priority such as "(A) "
+var todoPriorityPattern = regexp.MustCompile(`^\(([A-Z])\)\s+`)
+
+// todoDatePattern matches a leading creation date
+var todoDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}\s+`)
+
+// ParseTodoTxt reads a todo.txt file. Completed lines (starting with "x ") are
+// skipped. Priorities (A) to (D) map to daylit priorities 1 to 4 and lower
+// letters to 5. Projects (+name), contexts (@name), and key:value tags are
+// removed from task names. The first context becomes the task's context, and
+// the rec: tag sets the recurrence, anchored at the due: date when present.
+func ParseTodoTxt(r io.Reader, opts Options) ([]models.Task, error) {
+	var tasks []models.Task
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "x ") {
+			continue
+		}
+
+		task := newTask("", opts)
+		if m := todoPriorityPattern.FindStringSubmatch(line); m != nil {
+			task.Priority = min(int(m[1][0]-'A')+1, 5)
+			line = line[len(m[0]):]
+		}
+		line = todoDatePattern.ReplaceAllString(line, "")
+
+		var words []string
+		var rec, due string
+		for _, word := range strings.Fields(line) {
+			if key, value, ok := strings.Cut(word, ":"); ok && key != "" && value != "" && !strings.Contains(value, "/") {
+				switch key {
+				case "rec":
+					rec = value
+				case "due":
+					due = value
+				}
+				continue
+			}
+			if len(word) > 1 && word[0] == '@' {
+				if task.Context == "" {
+					task.Context = models.NormalizeContext(word[1:])
+				}
+				continue
+			}
+			if len(word) > 1 && word[0] == '+' {
+				continue
+			}
+			words = append(words, word)
+		}
+
+		task.Name = strings.Join(words, " ")
+		if task.Name == "" {
+			continue
+		}
+
+		if rec != "" {
+			anchor := opts.Today
+			if d, err := time.ParseInLocation(constants.DateFormat, due, time.Local); err == nil {
+				anchor = d
+			}
+			if r, ok := recurrenceFromPeriod(rec, anchor); ok {
+				task.Recurrence = r
+			}
+		}
+
+		tasks = append(tasks, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read todo.txt: %w", err)
+	}
+
+	return tasks, nil
+}
@@ -1,6 +1,7 @@
 package keyring
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 
@@ -63,3 +64,47 @@ func IsAvailable() bool {
 	// Any other error likely indicates the keyring is not available
 	return err == nil || err == keyring.ErrNotFound
 }
+
+// notesKeyUser is the keyring entry holding the notes key of the database
+// with the given key ID
+func notesKeyUser(keyID string) string {
+	return constants.NotesKeyringPrefix + keyID
+}
+
+// GetNotesKey retrieves the key that encrypts a database's notes from the OS
+// keyring. Returns ErrNotFound if no key is stored under keyID.
+func GetNotesKey(keyID string) ([]byte, error) {
+	encoded, err := keyring.Get(constants.AppName, notesKeyUser(keyID))
+	if err != nil {
+		if err == keyring.ErrNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", ErrKeyringUnavailable, err)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("malformed notes key in keyring: %w", err)
+	}
+	return key, nil
+}
+
+// SetNotesKey stores the key that encrypts a database's notes in the OS
+// keyring under keyID
+func SetNotesKey(keyID string, key []byte) error {
+	if err := keyring.Set(constants.AppName, notesKeyUser(keyID), base64.StdEncoding.EncodeToString(key)); err != nil {
+		return fmt.Errorf("failed to store notes key in keyring: %w", err)
+	}
+	return nil
+}
+
+// DeleteNotesKey removes a database's notes key from the OS keyring
+func DeleteNotesKey(keyID string) error {
+	err := keyring.Delete(constants.AppName, notesKeyUser(keyID))
+	if err != nil {
+		if err == keyring.ErrNotFound {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to delete notes key from keyring: %w", err)
+	}
+	return nil
+}
@@ -12,6 +12,9 @@ import (
 var (
 	// Logger is the global logger instance
 	Logger *log.Logger
+
+	// logPath is the file the logger writes to, set by Init
+	logPath string
 )
 
 // Config holds logger configuration
@@ -29,6 +32,7 @@ func Init(cfg Config) error {
 	}
 
 	logFile := filepath.Join(logDir, "daylit.log")
+	logPath = logFile
 
 	// Create rotating file handler
 	fileWriter := &lumberjack.Logger{
@@ -55,7 +59,7 @@ func Init(cfg Config) error {
 		writer = fileWriter
 	}
 
-	Logger = log.NewWithOptions(writer, log.Options{
+	Logger = log.NewWithOptions(redactWriter{w: writer}, log.Options{
 		ReportCaller:    cfg.Debug,
 		ReportTimestamp: true,
 		Level:           level,
@@ -65,38 +69,43 @@ func Init(cfg Config) error {
 	return nil
 }
 
+// Path returns the log file written by the logger, or "" before Init.
+func Path() string {
+	return logPath
+}
+
 // Debug logs a debug message
 func Debug(msg string, keyvals ...interface{}) {
 	if Logger != nil {
-		Logger.Debug(msg, keyvals...)
+		Logger.Debug(msg, redactKeyvals(keyvals)...)
 	}
 }
 
 // Info logs an info message
 func Info(msg string, keyvals ...interface{}) {
 	if Logger != nil {
-		Logger.Info(msg, keyvals...)
+		Logger.Info(msg, redactKeyvals(keyvals)...)
 	}
 }
 
 // Warn logs a warning message
 func Warn(msg string, keyvals ...interface{}) {
 	if Logger != nil {
-		Logger.Warn(msg, keyvals...)
+		Logger.Warn(msg, redactKeyvals(keyvals)...)
 	}
 }
 
 // Error logs an error message
 func Error(msg string, keyvals ...interface{}) {
 	if Logger != nil {
-		Logger.Error(msg, keyvals...)
+		Logger.Error(msg, redactKeyvals(keyvals)...)
 	}
 }
 
 // Fatal logs a fatal error and exits
 func Fatal(msg string, keyvals ...interface{}) {
 	if Logger != nil {
-		Logger.Fatal(msg, keyvals...)
+		Logger.Fatal(msg, redactKeyvals(keyvals)...)
 		// Logger.Fatal() already calls os.Exit, so this line won't be reached
 	} else {
 		// If Logger is nil, exit manually
@@ -0,0 +1,103 @@
+// Package metrics records the opt-in scheduling quality indicators read by
+// 'daylit stats' and 'daylit optimize'. Nothing is recorded unless metrics
+// are turned on in the settings, and the numbers never leave the database.
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+// LookbackDays is how many days before today each nightly run records, so
+// feedback and tracking added to a past day after the fact still count
+const LookbackDays = 7
+
+// Due reports whether the nightly metrics run should happen at now: metrics
+// are on, today's day has started, so yesterday is over, and the run hasn't
+// happened today
+func Due(settings models.Settings, now time.Time) bool {
+	if !settings.MetricsEnabled || settings.MetricsRecordedOn == now.Format(constants.DateFormat) {
+		return false
+	}
+	dayStart, err := utils.ParseTimeToMinutes(settings.DayStart)
+	if err != nil {
+		return false
+	}
+	return now.Hour()*60+now.Minute() >= dayStart
+}
+
+// Day computes the metrics of a plan's day. Pass a zero plan with only the
+// date set for a day without a plan.
+func Day(plan models.DayPlan, window models.DayWindow, now time.Time) models.DailyMetrics {
+	m := models.DailyMetrics{
+		Date:         plan.Date,
+		HasPlan:      plan.Revision > 0,
+		Accepted:     plan.AcceptedAt != nil,
+		PlannedSlots: len(plan.Slots),
+		DriftMinutes: window.TotalDrift(plan.Slots),
+		ComputedAt:   now,
+	}
+	for _, slot := range plan.Slots {
+		switch slot.Status {
+		case constants.SlotStatusDone:
+			m.DoneSlots++
+		case constants.SlotStatusSkipped:
+			m.SkippedSlots++
+		}
+		if window.Drift(slot).Tracked() {
+			m.TrackedSlots++
+		}
+		if slot.Feedback == nil {
+			continue
+		}
+		switch slot.Feedback.Rating {
+		case constants.FeedbackOnTrack:
+			m.OnTrack++
+		case constants.FeedbackTooMuch:
+			m.TooMuch++
+		case constants.FeedbackUnnecessary:
+			m.Unnecessary++
+		}
+	}
+	return m
+}
+
+// Record computes and saves the metrics of the LookbackDays days before
+// now's day, replacing what earlier runs recorded for them, and returns how
+// many days it recorded
+func Record(store storage.Provider, settings models.Settings, now time.Time) (int, error) {
+	window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+	if err != nil {
+		return 0, err
+	}
+	start := now.AddDate(0, 0, -LookbackDays).Format(constants.DateFormat)
+	end := now.AddDate(0, 0, -1).Format(constants.DateFormat)
+
+	plans, err := store.GetPlansRange(start, end)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get plans: %w", err)
+	}
+	byDate := make(map[string]models.DayPlan, len(plans))
+	for _, plan := range plans {
+		byDate[plan.Date] = plan
+	}
+
+	recorded := 0
+	for i := LookbackDays; i >= 1; i-- {
+		date := now.AddDate(0, 0, -i).Format(constants.DateFormat)
+		plan, ok := byDate[date]
+		if !ok {
+			plan = models.DayPlan{Date: date}
+		}
+		if err := store.SaveDailyMetrics(Day(plan, window, now)); err != nil {
+			return recorded, fmt.Errorf("failed to save metrics for %s: %w", date, err)
+		}
+		recorded++
+	}
+	return recorded, nil
+}
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+// metricsTestNow is a Monday morning
+var metricsTestNow = time.Date(2025, 3, 10, 8, 0, 0, 0, time.Local)
+
+func TestRecord(t *testing.T) {
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	started, ended := "09:10", "10:20"
+	accepted := metricsTestNow.UTC().Format(time.RFC3339)
+	yesterday := metricsTestNow.AddDate(0, 0, -1).Format(constants.DateFormat)
+	if err := store.SavePlan(models.DayPlan{
+		Date:       yesterday,
+		AcceptedAt: &accepted,
+		Slots: []models.Slot{
+			{Start: "09:00", End: "10:00", TaskID: "write", Status: constants.SlotStatusDone, ActualStart: &started, ActualEnd: &ended,
+				Feedback: &models.Feedback{Rating: constants.FeedbackTooMuch}},
+			{Start: "11:00", End: "11:30", TaskID: "email", Status: constants.SlotStatusDone, Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack}},
+			{Start: "14:00", End: "15:00", TaskID: "read", Status: constants.SlotStatusSkipped},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Today is left alone until it is over
+	today := metricsTestNow.Format(constants.DateFormat)
+	if err := store.SavePlan(models.DayPlan{Date: today, Slots: []models.Slot{{Start: "09:00", End: "10:00", TaskID: "write"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	settings := models.Settings{DayStart: "07:00", DayEnd: "22:00", MetricsEnabled: true}
+	recorded, err := Record(store, settings, metricsTestNow)
+	if err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if recorded != LookbackDays {
+		t.Errorf("recorded %d days, want %d", recorded, LookbackDays)
+	}
+
+	daily, err := store.GetDailyMetrics("2025-01-01", today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(daily) != LookbackDays || daily[len(daily)-1].Date != yesterday {
+		t.Fatalf("expected the %d days up to yesterday, got %+v", LookbackDays, daily)
+	}
+	got := daily[len(daily)-1]
+	want := models.DailyMetrics{
+		Date: yesterday, HasPlan: true, Accepted: true, PlannedSlots: 3, DoneSlots: 2, SkippedSlots: 1,
+		TrackedSlots: 1, DriftMinutes: 20, OnTrack: 1, TooMuch: 1,
+	}
+	got.ComputedAt = time.Time{}
+	if got != want {
+		t.Errorf("yesterday's metrics = %+v\nwant %+v", got, want)
+	}
+	if daily[0].HasPlan || daily[0].PlannedSlots != 0 {
+		t.Errorf("expected an empty day without a plan, got %+v", daily[0])
+	}
+
+	// A later run replaces the days instead of adding to them
+	if _, err := Record(store, settings, metricsTestNow); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := store.GetDailyMetrics("2025-01-01", today); len(again) != LookbackDays {
+		t.Errorf("expected %d days after recording again, got %d", LookbackDays, len(again))
+	}
+}
+
+func TestDue(t *testing.T) {
+	on := models.Settings{DayStart: "07:00", MetricsEnabled: true}
+	tests := []struct {
+		name     string
+		settings models.Settings
+		now      time.Time
+		want     bool
+	}{
+		{name: "off", settings: models.Settings{DayStart: "07:00"}, now: metricsTestNow},
+		{name: "after day start", settings: on, now: metricsTestNow, want: true},
+		{name: "before day start", settings: on, now: time.Date(2025, 3, 10, 6, 0, 0, 0, time.Local)},
+		{name: "already ran", settings: models.Settings{DayStart: "07:00", MetricsEnabled: true, MetricsRecordedOn: "2025-03-10"}, now: metricsTestNow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Due(tt.settings, tt.now); got != tt.want {
+				t.Errorf("Due = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Checksum returns the hex-encoded SHA-256 of a migration's SQL
+func Checksum(sql string) string {
+	sum := sha256.Sum256([]byte(sql))
+	return hex.EncodeToString(sum[:])
+}
+
+// EnsureChecksumTable creates the schema_checksums table if it doesn't exist
+func (r *Runner) EnsureChecksumTable() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_checksums (
+			version INTEGER PRIMARY KEY,
+			checksum VARCHAR(64) NOT NULL
+		)
+	`)
+	return err
+}
+
+// recordedChecksums returns the checksum stored for each applied version
+func (r *Runner) recordedChecksums() (map[int]string, error) {
+	if err := r.EnsureChecksumTable(); err != nil {
+		return nil, fmt.Errorf("failed to ensure schema_checksums table: %w", err)
+	}
+
+	rows, err := r.db.Query("SELECT version, checksum FROM schema_checksums")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration checksums: %w", err)
+	}
+	defer rows.Close()
+
+	recorded := make(map[int]string)
+	for rows.Next() {
+		var version int
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, fmt.Errorf("failed to scan migration checksum: %w", err)
+		}
+		recorded[version] = checksum
+	}
+	return recorded, rows.Err()
+}
+
+// recordMissingChecksums stores the checksum of every migration at or below
+// currentVersion that has none yet. Databases migrated before checksums were
+// tracked adopt the embedded files as their baseline this way.
+func (r *Runner) recordMissingChecksums(migrations []Migration, currentVersion int) error {
+	recorded, err := r.recordedChecksums()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.Version > currentVersion {
+			break
+		}
+		if _, ok := recorded[m.Version]; ok {
+			continue
+		}
+		// The checksum is hex, so it is safe to inline like the version number
+		query := fmt.Sprintf("INSERT INTO schema_checksums (version, checksum) VALUES (%d, '%s')", m.Version, Checksum(m.SQL))
+		if _, err := r.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to record checksum of migration %d: %w", m.Version, err)
+		}
+	}
+	return nil
+}
+
+// ChecksumDivergence describes an applied migration whose recorded checksum
+// does not match the embedded file. Recorded is empty when no checksum was
+// stored; Embedded is empty when the binary has no file for that version.
+type ChecksumDivergence struct {
+	Version  int
+	Name     string
+	Recorded string
+	Embedded string
+}
+
+func (d ChecksumDivergence) String() string {
+	switch {
+	case d.Recorded == "":
+		return fmt.Sprintf("migration %d (%s) has no recorded checksum", d.Version, d.Name)
+	case d.Embedded == "":
+		return fmt.Sprintf("migration %d was applied but is not embedded in this build", d.Version)
+	default:
+		return fmt.Sprintf("migration %d (%s) was modified after it was applied: recorded %s, embedded %s",
+			d.Version, d.Name, shortChecksum(d.Recorded), shortChecksum(d.Embedded))
+	}
+}
+
+func shortChecksum(checksum string) string {
+	if len(checksum) > 12 {
+		return checksum[:12]
+	}
+	return checksum
+}
+
+// VerifyChecksums compares the recorded checksum of every applied migration
+// with the embedded file and returns each divergence, ordered by version
+func (r *Runner) VerifyChecksums() ([]ChecksumDivergence, error) {
+	currentVersion, err := r.GetCurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := r.ReadMigrationFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	recorded, err := r.recordedChecksums()
+	if err != nil {
+		return nil, err
+	}
+
+	var divergences []ChecksumDivergence
+	embedded := make(map[int]bool, len(migrations))
+	for _, m := range migrations {
+		embedded[m.Version] = true
+		if m.Version > currentVersion {
+			continue
+		}
+		want := Checksum(m.SQL)
+		if got := recorded[m.Version]; got != want {
+			divergences = append(divergences, ChecksumDivergence{
+				Version:  m.Version,
+				Name:     m.Name,
+				Recorded: got,
+				Embedded: want,
+			})
+		}
+	}
+	for version, checksum := range recorded {
+		if !embedded[version] {
+			divergences = append(divergences, ChecksumDivergence{Version: version, Recorded: checksum})
+		}
+	}
+
+	sort.Slice(divergences, func(i, j int) bool {
+		return divergences[i].Version < divergences[j].Version
+	})
+	return divergences, nil
+}
+
+// Schema maps each table to its column names, sorted
+type Schema map[string][]string
+
+// bookkeepingTables are maintained by the runner rather than by migrations,
+// so they are left out of schema comparisons
+var bookkeepingTables = map[string]bool{
+	"schema_version":   true,
+	"schema_checksums": true,
+}
+
+// SchemaDrift describes how one table of a live database differs from the
+// schema the migrations produce
+type SchemaDrift struct {
+	Table           string
+	MissingTable    bool
+	UnexpectedTable bool
+	MissingColumns  []string
+	ExtraColumns    []string
+}
+
+func (d SchemaDrift) String() string {
+	switch {
+	case d.MissingTable:
+		return fmt.Sprintf("table %s is missing", d.Table)
+	case d.UnexpectedTable:
+		return fmt.Sprintf("table %s is not created by any migration", d.Table)
+	}
+
+	var parts []string
+	if len(d.MissingColumns) > 0 {
+		parts = append(parts, "missing columns "+strings.Join(d.MissingColumns, ", "))
+	}
+	if len(d.ExtraColumns) > 0 {
+		parts = append(parts, "unexpected columns "+strings.Join(d.ExtraColumns, ", "))
+	}
+	return fmt.Sprintf("table %s has %s", d.Table, strings.Join(parts, "; "))
+}
+
+// CompareSchemas returns the drift of got relative to want, ordered by table
+func CompareSchemas(want, got Schema) []SchemaDrift {
+	tables := make(map[string]bool)
+	for table := range want {
+		tables[table] = true
+	}
+	for table := range got {
+		tables[table] = true
+	}
+
+	var drift []SchemaDrift
+	for table := range tables {
+		if bookkeepingTables[table] {
+			continue
+		}
+		wantCols, inWant := want[table]
+		gotCols, inGot := got[table]
+		switch {
+		case !inGot:
+			drift = append(drift, SchemaDrift{Table: table, MissingTable: true})
+		case !inWant:
+			drift = append(drift, SchemaDrift{Table: table, UnexpectedTable: true})
+		default:
+			missing, extra := diffColumns(wantCols, gotCols)
+			if len(missing) > 0 || len(extra) > 0 {
+				drift = append(drift, SchemaDrift{Table: table, MissingColumns: missing, ExtraColumns: extra})
+			}
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		return drift[i].Table < drift[j].Table
+	})
+	return drift
+}
+
+func diffColumns(want, got []string) (missing, extra []string) {
+	gotSet := make(map[string]bool, len(got))
+	for _, col := range got {
+		gotSet[col] = true
+	}
+	wantSet := make(map[string]bool, len(want))
+	for _, col := range want {
+		wantSet[col] = true
+		if !gotSet[col] {
+			missing = append(missing, col)
+		}
+	}
+	for _, col := range got {
+		if !wantSet[col] {
+			extra = append(extra, col)
+		}
+	}
+	return missing, extra
+}
+
+// SQLiteSchema introspects the tables and columns of a SQLite database
+func SQLiteSchema(db *sql.DB) (Schema, error) {
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	schema := make(Schema, len(tables))
+	for _, table := range tables {
+		var cols []string
+		colRows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		for colRows.Next() {
+			var col string
+			if err := colRows.Scan(&col); err != nil {
+				colRows.Close()
+				return nil, fmt.Errorf("failed to scan column of %s: %w", table, err)
+			}
+			cols = append(cols, col)
+		}
+		colRows.Close()
+		sort.Strings(cols)
+		schema[table] = cols
+	}
+	return schema, nil
+}
+
+// ExpectedSQLiteSchema applies the migrations to a scratch in-memory
+// database and returns the schema they produce
+func ExpectedSQLiteSchema(migrationFS fs.FS) (Schema, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open scratch database: %w", err)
+	}
+	defer db.Close()
+	// Every connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+
+	if _, err := NewRunner(db, migrationFS).ApplyMigrations(nil); err != nil {
+		return nil, fmt.Errorf("failed to build expected schema: %w", err)
+	}
+	return SQLiteSchema(db)
+}
+
+// PostgresSchema introspects the tables and columns of the current schema
+// of a PostgreSQL database
+func PostgresSchema(db *sql.DB) (Schema, error) {
+	return postgresSchema(db, "current_schema()")
+}
+
+// ExpectedPostgresSchema applies the migrations to a scratch schema inside a
+// transaction that is rolled back, and returns the schema they produce. DDL
+// is transactional in PostgreSQL, so the live data is never touched.
+func ExpectedPostgresSchema(db *sql.DB, migrationFS fs.FS) (Schema, error) {
+	migrations, err := NewRunner(db, migrationFS).ReadMigrationFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	scratch := fmt.Sprintf("daylit_doctor_%d", time.Now().UnixNano())
+	if _, err := tx.Exec("CREATE SCHEMA " + scratch); err != nil {
+		return nil, fmt.Errorf("failed to create scratch schema: %w", err)
+	}
+	if _, err := tx.Exec("SET LOCAL search_path TO " + scratch); err != nil {
+		return nil, fmt.Errorf("failed to switch to scratch schema: %w", err)
+	}
+	for _, m := range migrations {
+		if _, err := tx.Exec(m.SQL); err != nil {
+			return nil, fmt.Errorf("failed to apply migration %d (%s) to scratch schema: %w", m.Version, m.Name, err)
+		}
+	}
+
+	return postgresSchema(tx, "'"+scratch+"'")
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// postgresSchema reads information_schema for the schema named by the SQL
+// expression schemaExpr
+func postgresSchema(q queryer, schemaExpr string) (Schema, error) {
+	rows, err := q.Query(`
+		SELECT c.table_name, c.column_name
+		FROM information_schema.columns c
+		JOIN information_schema.tables t
+			ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		WHERE c.table_schema = ` + schemaExpr + ` AND t.table_type = 'BASE TABLE'
+		ORDER BY c.table_name, c.column_name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+	defer rows.Close()
+
+	schema := make(Schema)
+	for rows.Next() {
+		var table, col string
+		if err := rows.Scan(&table, &col); err != nil {
+			return nil, fmt.Errorf("failed to scan column: %w", err)
+		}
+		schema[table] = append(schema[table], col)
+	}
+	return schema, rows.Err()
+}
@@ -0,0 +1,146 @@
+package migration
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestVerifyChecksums(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mapFS := setupTestMigrations(t, map[string]string{
+		"001_init.sql":  "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);",
+		"002_posts.sql": "CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER);",
+	}).(fstest.MapFS)
+
+	runner := NewRunner(db, mapFS)
+	if _, err := runner.ApplyMigrations(nil); err != nil {
+		t.Fatalf("ApplyMigrations failed: %v", err)
+	}
+
+	divergences, err := runner.VerifyChecksums()
+	if err != nil {
+		t.Fatalf("VerifyChecksums failed: %v", err)
+	}
+	if len(divergences) != 0 {
+		t.Fatalf("expected no divergences after applying, got %v", divergences)
+	}
+
+	// Edit an applied migration in place
+	mapFS["002_posts.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE posts (id INTEGER PRIMARY KEY);")}
+	divergences, err = runner.VerifyChecksums()
+	if err != nil {
+		t.Fatalf("VerifyChecksums failed: %v", err)
+	}
+	if len(divergences) != 1 || divergences[0].Version != 2 || divergences[0].Recorded == "" {
+		t.Fatalf("expected migration 2 to be reported as modified, got %v", divergences)
+	}
+
+	// Drop the file entirely
+	delete(mapFS, "002_posts.sql")
+	divergences, err = runner.VerifyChecksums()
+	if err != nil {
+		t.Fatalf("VerifyChecksums failed: %v", err)
+	}
+	if len(divergences) != 1 || divergences[0].Embedded != "" {
+		t.Fatalf("expected migration 2 to be reported as not embedded, got %v", divergences)
+	}
+}
+
+func TestApplyMigrationsRecordsMissingChecksums(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mapFS := setupTestMigrations(t, map[string]string{
+		"001_init.sql": "CREATE TABLE users (id INTEGER PRIMARY KEY);",
+	})
+	runner := NewRunner(db, mapFS)
+	if _, err := runner.ApplyMigrations(nil); err != nil {
+		t.Fatalf("ApplyMigrations failed: %v", err)
+	}
+
+	// Simulate a database migrated before checksums were tracked
+	if _, err := db.Exec("DELETE FROM schema_checksums"); err != nil {
+		t.Fatalf("failed to clear checksums: %v", err)
+	}
+	divergences, err := runner.VerifyChecksums()
+	if err != nil {
+		t.Fatalf("VerifyChecksums failed: %v", err)
+	}
+	if len(divergences) != 1 || divergences[0].Recorded != "" {
+		t.Fatalf("expected migration 1 to be reported as unrecorded, got %v", divergences)
+	}
+
+	if _, err := runner.ApplyMigrations(nil); err != nil {
+		t.Fatalf("ApplyMigrations failed: %v", err)
+	}
+	divergences, err = runner.VerifyChecksums()
+	if err != nil {
+		t.Fatalf("VerifyChecksums failed: %v", err)
+	}
+	if len(divergences) != 0 {
+		t.Errorf("expected checksums to be backfilled, got %v", divergences)
+	}
+}
+
+func TestCompareSchemas(t *testing.T) {
+	want := Schema{
+		"users":          {"id", "name"},
+		"posts":          {"id", "user_id"},
+		"schema_version": {"version"},
+	}
+	got := Schema{
+		"users": {"email", "id"},
+		"extra": {"id"},
+	}
+
+	drift := CompareSchemas(want, got)
+	if len(drift) != 3 {
+		t.Fatalf("expected 3 drifted tables, got %v", drift)
+	}
+	if drift[0].Table != "extra" || !drift[0].UnexpectedTable {
+		t.Errorf("expected extra to be unexpected, got %+v", drift[0])
+	}
+	if drift[1].Table != "posts" || !drift[1].MissingTable {
+		t.Errorf("expected posts to be missing, got %+v", drift[1])
+	}
+	if got := drift[2].String(); got != "table users has missing columns name; unexpected columns email" {
+		t.Errorf("unexpected users drift: %s", got)
+	}
+}
+
+func TestSQLiteSchemaDrift(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mapFS := setupTestMigrations(t, map[string]string{
+		"001_init.sql": "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);",
+	})
+	if _, err := NewRunner(db, mapFS).ApplyMigrations(nil); err != nil {
+		t.Fatalf("ApplyMigrations failed: %v", err)
+	}
+
+	want, err := ExpectedSQLiteSchema(mapFS)
+	if err != nil {
+		t.Fatalf("ExpectedSQLiteSchema failed: %v", err)
+	}
+	got, err := SQLiteSchema(db)
+	if err != nil {
+		t.Fatalf("SQLiteSchema failed: %v", err)
+	}
+	if drift := CompareSchemas(want, got); len(drift) != 0 {
+		t.Fatalf("expected no drift, got %v", drift)
+	}
+
+	if _, err := db.Exec("ALTER TABLE users ADD COLUMN nickname TEXT"); err != nil {
+		t.Fatalf("failed to alter table: %v", err)
+	}
+	if got, err = SQLiteSchema(db); err != nil {
+		t.Fatalf("SQLiteSchema failed: %v", err)
+	}
+	drift := CompareSchemas(want, got)
+	if len(drift) != 1 || len(drift[0].ExtraColumns) != 1 || drift[0].ExtraColumns[0] != "nickname" {
+		t.Errorf("expected nickname to be reported as unexpected, got %v", drift)
+	}
+}
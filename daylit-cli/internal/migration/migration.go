@@ -13,6 +13,10 @@ import (
 	_ "modernc.org/sqlite"
 )
 
+// ErrNoMigrations is returned when the runner is given no migration files,
+// which would otherwise leave the schema untouched without a word
+var ErrNoMigrations = errors.New("no migration files found")
+
 // Migration represents a single database migration
 type Migration struct {
 	Version int
@@ -170,8 +174,7 @@ func (r *Runner) ApplyMigrations(logFn func(string)) (int, error) {
 	}
 
 	if len(migrations) == 0 {
-		logFn("No migration files found")
-		return 0, nil
+		return 0, ErrNoMigrations
 	}
 
 	latestVersion := migrations[len(migrations)-1].Version
@@ -181,6 +184,12 @@ func (r *Runner) ApplyMigrations(logFn func(string)) (int, error) {
 		return 0, fmt.Errorf("database schema version (%d) is newer than supported version (%d) - please upgrade the application", currentVersion, latestVersion)
 	}
 
+	// Adopt the embedded files as the baseline for versions applied before
+	// checksums were tracked
+	if err := r.recordMissingChecksums(migrations, currentVersion); err != nil {
+		return 0, err
+	}
+
 	// Filter migrations that need to be applied
 	var pendingMigrations []Migration
 	for _, m := range migrations {
@@ -233,6 +242,17 @@ func (r *Runner) ApplyMigrations(logFn func(string)) (int, error) {
 			return appliedCount, fmt.Errorf("failed to set version in migration %d: %w", migration.Version, err)
 		}
 
+		// Record the checksum alongside the version so later edits to the file are detectable
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM schema_checksums WHERE version = %d", migration.Version)); err != nil {
+			_ = tx.Rollback()
+			return appliedCount, fmt.Errorf("failed to clear checksum in migration %d: %w", migration.Version, err)
+		}
+		query = fmt.Sprintf("INSERT INTO schema_checksums (version, checksum) VALUES (%d, '%s')", migration.Version, Checksum(migration.SQL))
+		if _, err := tx.Exec(query); err != nil {
+			_ = tx.Rollback()
+			return appliedCount, fmt.Errorf("failed to record checksum in migration %d: %w", migration.Version, err)
+		}
+
 		// Commit the transaction
 		if err := tx.Commit(); err != nil {
 			return appliedCount, fmt.Errorf("failed to commit migration %d: %w", migration.Version, err)
@@ -259,6 +279,9 @@ func (r *Runner) ValidateVersion() error {
 	if err != nil {
 		return err
 	}
+	if latestVersion == 0 {
+		return ErrNoMigrations
+	}
 
 	if currentVersion > latestVersion {
 		return fmt.Errorf("database schema version (%d) is newer than supported version (%d) - please upgrade the application", currentVersion, latestVersion)
@@ -2,6 +2,7 @@ package migration
 
 import (
 	"database/sql"
+	"errors"
 	"io/fs"
 	"path/filepath"
 	"strings"
@@ -307,6 +308,20 @@ func TestValidateVersionNewerDatabase(t *testing.T) {
 	}
 }
 
+func TestNoMigrations(t *testing.T) {
+	db, _, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runner := NewRunner(db, setupTestMigrations(t, map[string]string{}))
+
+	if _, err := runner.ApplyMigrations(nil); !errors.Is(err, ErrNoMigrations) {
+		t.Errorf("ApplyMigrations should fail with ErrNoMigrations, got %v", err)
+	}
+	if err := runner.ValidateVersion(); !errors.Is(err, ErrNoMigrations) {
+		t.Errorf("ValidateVersion should fail with ErrNoMigrations, got %v", err)
+	}
+}
+
 func TestGetLatestVersion(t *testing.T) {
 	db, _, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -11,9 +11,14 @@ import (
 type Alert struct {
 	ID         string     `json:"id"`
 	Message    string     `json:"message"`
-	Time       string     `json:"time"`           // HH:MM format
-	Date       string     `json:"date,omitempty"` // YYYY-MM-DD (for one-time alerts)
-	Recurrence Recurrence `json:"recurrence"`     // Re-use existing Recurrence struct
+	Time       string     `json:"time"`                 // HH:MM format, unused for cron and leave-by alerts
+	Date       string     `json:"date,omitempty"`       // YYYY-MM-DD (for one-time alerts)
+	Recurrence Recurrence `json:"recurrence"`           // Re-use existing Recurrence struct
+	Cron       string     `json:"cron,omitempty"`       // Cron expression for cron recurrence
+	TaskID     string     `json:"task_id,omitempty"`    // Appointment a leave-by alert counts down to
+	TravelMin  int        `json:"travel_min,omitempty"` // Minutes before the appointment to leave
+	Urgency    string     `json:"urgency,omitempty"`    // low, normal or critical; empty is normal
+	Sound      string     `json:"sound,omitempty"`      // Notification sound; empty uses the default
 	Active     bool       `json:"active"`
 	LastSent   *time.Time `json:"last_sent,omitempty"` // RFC3339 timestamp
 	CreatedAt  time.Time  `json:"created_at"`
@@ -23,14 +28,32 @@ func (a *Alert) Validate() error {
 	if a.Message == "" {
 		return fmt.Errorf("alert message cannot be empty")
 	}
-
-	if a.Time == "" {
-		return fmt.Errorf("alert time cannot be empty")
+	if err := ValidateUrgency(a.Urgency); err != nil {
+		return err
 	}
 
-	// Validate time format (HH:MM)
-	if _, err := time.Parse("15:04", a.Time); err != nil {
-		return fmt.Errorf("invalid time format (expected HH:MM): %w", err)
+	// Leave-by alerts take their times from the appointment, and cron
+	// alerts from the expression
+	if a.IsLeaveBy() {
+		if a.TravelMin < 0 {
+			return fmt.Errorf("travel time cannot be negative")
+		}
+		if a.Date != "" || a.IsCron() {
+			return fmt.Errorf("leave-by alerts follow their appointment and can't have a date or cron expression")
+		}
+	} else if a.IsCron() {
+		if _, err := ParseCron(a.Cron); err != nil {
+			return err
+		}
+	} else {
+		if a.Time == "" {
+			return fmt.Errorf("alert time cannot be empty")
+		}
+
+		// Validate time format (HH:MM)
+		if _, err := time.Parse("15:04", a.Time); err != nil {
+			return fmt.Errorf("invalid time format (expected HH:MM): %w", err)
+		}
 	}
 
 	// Validate date format if provided (one-time alert)
@@ -41,13 +64,27 @@ func (a *Alert) Validate() error {
 	}
 
 	// If not a one-time alert, validate recurrence
-	if a.Date == "" {
+	if a.Date == "" && !a.IsLeaveBy() {
 		if a.Recurrence.Type == constants.RecurrenceWeekly && len(a.Recurrence.WeekdayMask) == 0 {
 			return fmt.Errorf("weekdays must be specified for weekly recurrence")
 		}
 		if a.Recurrence.Type == constants.RecurrenceNDays && a.Recurrence.IntervalDays < 1 {
 			return fmt.Errorf("interval must be at least 1 for n_days recurrence")
 		}
+		if a.Recurrence.Type == constants.RecurrenceMonthlyDate && (a.Recurrence.MonthDay < 1 || a.Recurrence.MonthDay > 31) {
+			return fmt.Errorf("month day must be between 1 and 31 for monthly_date recurrence")
+		}
+		if a.Recurrence.Type == constants.RecurrenceYearly {
+			if a.Recurrence.Month < 1 || a.Recurrence.Month > 12 {
+				return fmt.Errorf("month must be between 1 and 12 for yearly recurrence")
+			}
+			// Day 29 is allowed in February for leap-day birthdays
+			if a.Recurrence.MonthDay < 1 || a.Recurrence.MonthDay > daysIn(time.Month(a.Recurrence.Month), 2024) {
+				return fmt.Errorf("month day %d doesn't exist in %s", a.Recurrence.MonthDay, time.Month(a.Recurrence.Month))
+			}
+		}
+	} else if a.IsCron() {
+		return fmt.Errorf("cron alerts can't have a date")
 	}
 
 	return nil
@@ -58,6 +95,52 @@ func (a *Alert) IsOneTime() bool {
 	return a.Date != ""
 }
 
+// IsCron returns true if the alert runs on a cron expression
+func (a *Alert) IsCron() bool {
+	return a.Recurrence.Type == constants.RecurrenceCron
+}
+
+// IsLeaveBy returns true if the alert counts down to an appointment
+func (a *Alert) IsLeaveBy() bool {
+	return a.TaskID != ""
+}
+
+// LeaveBy returns the time of day, in minutes since midnight, to leave for
+// the appointment task so as to arrive by its fixed start. It is worked out
+// from the task each time, so it follows edits to the appointment.
+func (a *Alert) LeaveBy(task Task) (int, error) {
+	if task.FixedStart == "" {
+		return 0, fmt.Errorf("%s has no fixed start time", task.Name)
+	}
+	start, err := time.Parse("15:04", task.FixedStart)
+	if err != nil {
+		return 0, fmt.Errorf("invalid fixed start for %s: %w", task.Name, err)
+	}
+	leave := start.Hour()*60 + start.Minute() - a.TravelMin
+	if leave < 0 {
+		return 0, fmt.Errorf("leaving %d min before %s at %s would be the day before", a.TravelMin, task.Name, task.FixedStart)
+	}
+	return leave, nil
+}
+
+// CronRun returns the latest time at or before now, within the past grace
+// minutes, when a cron alert is scheduled to run. It reports false when
+// there is none or the alert was already sent for it.
+func (a *Alert) CronRun(now time.Time, grace int) (time.Time, bool) {
+	if !a.IsCron() {
+		return time.Time{}, false
+	}
+	schedule, err := ParseCron(a.Cron)
+	if err != nil {
+		return time.Time{}, false
+	}
+	run, ok := schedule.Prev(now, grace)
+	if !ok || (a.LastSent != nil && !a.LastSent.Before(run)) {
+		return time.Time{}, false
+	}
+	return run, true
+}
+
 // IsDueToday checks if the alert should fire today based on its recurrence pattern
 func (a *Alert) IsDueToday(today time.Time) bool {
 	// One-time alerts: check if date matches
@@ -106,6 +189,19 @@ func (a *Alert) IsDueToday(today time.Time) bool {
 
 		// Fire on exact interval boundaries (0, interval, 2*interval, etc.)
 		return daysSince%interval == 0
+	case constants.RecurrenceMonthlyDate:
+		// Days past the end of a short month fire on its last day, so an
+		// alert for the 31st still goes off in April
+		return today.Day() == min(a.Recurrence.MonthDay, daysIn(today.Month(), today.Year()))
+	case constants.RecurrenceYearly:
+		// Feb 29 fires on Feb 28 outside leap years
+		if today.Month() != time.Month(a.Recurrence.Month) {
+			return false
+		}
+		return today.Day() == min(a.Recurrence.MonthDay, daysIn(today.Month(), today.Year()))
+	case constants.RecurrenceCron:
+		schedule, err := ParseCron(a.Cron)
+		return err == nil && schedule.MatchesDate(today)
 	case constants.RecurrenceAdHoc:
 		// Ad-hoc alerts don't recur
 		return false
@@ -114,11 +210,31 @@ func (a *Alert) IsDueToday(today time.Time) bool {
 	}
 }
 
+// daysIn returns the number of days in month of year
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// FormatTime returns the alert's time of day, or what it comes from for
+// cron and leave-by alerts
+func (a *Alert) FormatTime() string {
+	if a.IsCron() {
+		return "cron"
+	}
+	if a.IsLeaveBy() {
+		return "leave-by"
+	}
+	return a.Time
+}
+
 // FormatRecurrence returns a human-readable string describing the alert's recurrence pattern
 func (a *Alert) FormatRecurrence() string {
 	if a.Date != "" {
 		return fmt.Sprintf("Once on %s", a.Date)
 	}
+	if a.IsLeaveBy() {
+		return fmt.Sprintf("%dm before appt", a.TravelMin)
+	}
 
 	switch a.Recurrence.Type {
 	case constants.RecurrenceDaily:
@@ -134,6 +250,12 @@ func (a *Alert) FormatRecurrence() string {
 			return "Daily"
 		}
 		return fmt.Sprintf("Every %d days", a.Recurrence.IntervalDays)
+	case constants.RecurrenceMonthlyDate:
+		return fmt.Sprintf("Monthly on day %d", a.Recurrence.MonthDay)
+	case constants.RecurrenceYearly:
+		return fmt.Sprintf("Yearly on %s %d", time.Month(a.Recurrence.Month).String()[:3], a.Recurrence.MonthDay)
+	case constants.RecurrenceCron:
+		return fmt.Sprintf("Cron: %s", a.Cron)
 	default:
 		return "One-time"
 	}
@@ -267,6 +267,72 @@ func TestAlert_IsDueToday(t *testing.T) {
 			today: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
 			want:  false,
 		},
+		{
+			name: "monthly alert on its day",
+			alert: Alert{
+				Recurrence: Recurrence{Type: constants.RecurrenceMonthlyDate, MonthDay: 1},
+			},
+			today: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			want:  true,
+		},
+		{
+			name: "monthly alert for the 31st fires on the last day of a short month",
+			alert: Alert{
+				Recurrence: Recurrence{Type: constants.RecurrenceMonthlyDate, MonthDay: 31},
+			},
+			today: time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC),
+			want:  true,
+		},
+		{
+			name: "monthly alert on another day",
+			alert: Alert{
+				Recurrence: Recurrence{Type: constants.RecurrenceMonthlyDate, MonthDay: 31},
+			},
+			today: time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC),
+			want:  false,
+		},
+		{
+			name: "yearly alert on its date",
+			alert: Alert{
+				Recurrence: Recurrence{Type: constants.RecurrenceYearly, Month: 3, MonthDay: 14},
+			},
+			today: time.Date(2027, 3, 14, 0, 0, 0, 0, time.UTC),
+			want:  true,
+		},
+		{
+			name: "yearly alert in another month",
+			alert: Alert{
+				Recurrence: Recurrence{Type: constants.RecurrenceYearly, Month: 3, MonthDay: 14},
+			},
+			today: time.Date(2027, 4, 14, 0, 0, 0, 0, time.UTC),
+			want:  false,
+		},
+		{
+			name: "leap day alert fires on Feb 28 in other years",
+			alert: Alert{
+				Recurrence: Recurrence{Type: constants.RecurrenceYearly, Month: 2, MonthDay: 29},
+			},
+			today: time.Date(2027, 2, 28, 0, 0, 0, 0, time.UTC),
+			want:  true,
+		},
+		{
+			name: "cron alert on a matching weekday",
+			alert: Alert{
+				Recurrence: Recurrence{Type: constants.RecurrenceCron},
+				Cron:       "0 9 * * mon-fri",
+			},
+			today: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), // Monday
+			want:  true,
+		},
+		{
+			name: "cron alert on a weekend",
+			alert: Alert{
+				Recurrence: Recurrence{Type: constants.RecurrenceCron},
+				Cron:       "0 9 * * mon-fri",
+			},
+			today: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC), // Sunday
+			want:  false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -278,6 +344,64 @@ func TestAlert_IsDueToday(t *testing.T) {
 	}
 }
 
+func TestAlert_CronRun(t *testing.T) {
+	alert := Alert{
+		Recurrence: Recurrence{Type: constants.RecurrenceCron},
+		Cron:       "*/30 9-17 * * *",
+	}
+
+	now := time.Date(2026, 1, 5, 10, 35, 0, 0, time.UTC)
+	run, ok := alert.CronRun(now, 10)
+	if !ok || !run.Equal(time.Date(2026, 1, 5, 10, 30, 0, 0, time.UTC)) {
+		t.Fatalf("CronRun() = %v, %v; want 10:30, true", run, ok)
+	}
+
+	// Already sent for this run
+	alert.LastSent = ptrTime(now)
+	if _, ok := alert.CronRun(now.Add(2*time.Minute), 10); ok {
+		t.Error("CronRun() reported a run that was already sent")
+	}
+
+	// The next run is due again
+	if run, ok := alert.CronRun(time.Date(2026, 1, 5, 11, 1, 0, 0, time.UTC), 10); !ok || run.Hour() != 11 || run.Minute() != 0 {
+		t.Errorf("CronRun() = %v, %v; want 11:00, true", run, ok)
+	}
+
+	// Runs older than the grace period are missed
+	if _, ok := alert.CronRun(time.Date(2026, 1, 5, 18, 20, 0, 0, time.UTC), 10); ok {
+		t.Error("CronRun() reported a run outside the grace period")
+	}
+}
+
+func TestAlert_LeaveBy(t *testing.T) {
+	alert := Alert{Message: "Time to go", TaskID: "dentist", TravelMin: 25}
+	if err := alert.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	task := Task{Name: "Dentist", Kind: constants.TaskKindAppointment, FixedStart: "10:00", FixedEnd: "11:00"}
+	leave, err := alert.LeaveBy(task)
+	if err != nil || leave != 9*60+35 {
+		t.Errorf("LeaveBy() = %d, %v; want 09:35", leave, err)
+	}
+
+	// Moving the appointment moves the leave-by time
+	task.FixedStart = "14:15"
+	if leave, _ := alert.LeaveBy(task); leave != 13*60+50 {
+		t.Errorf("LeaveBy() after edit = %d, want 13:50", leave)
+	}
+
+	task.FixedStart = "00:10"
+	if _, err := alert.LeaveBy(task); err == nil {
+		t.Error("LeaveBy() expected an error when leaving the day before")
+	}
+
+	alert.Date = "2026-01-15"
+	if err := alert.Validate(); err == nil {
+		t.Error("Validate() expected an error for a leave-by alert with a date")
+	}
+}
+
 func ptrTime(t time.Time) *time.Time {
 	return &t
 }
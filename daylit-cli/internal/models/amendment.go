@@ -0,0 +1,81 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+// AmendmentKind is the change an amendment makes to an accepted plan
+type AmendmentKind string
+
+const (
+	AmendmentAdd    AmendmentKind = "add"
+	AmendmentMove   AmendmentKind = "move"
+	AmendmentRemove AmendmentKind = "remove"
+)
+
+// PlanAmendment is one targeted change made in place to an accepted plan
+// revision. The slot is found by TaskID and FromStart; FromStart and FromEnd
+// are empty for an add, ToStart and ToEnd for a remove.
+type PlanAmendment struct {
+	ID        int64         `json:"id"` // Assigned by the store
+	Date      string        `json:"date"`
+	Revision  int           `json:"revision"`
+	Kind      AmendmentKind `json:"kind"`
+	TaskID    string        `json:"task_id"`
+	FromStart string        `json:"from_start,omitempty"`
+	FromEnd   string        `json:"from_end,omitempty"`
+	ToStart   string        `json:"to_start,omitempty"`
+	ToEnd     string        `json:"to_end,omitempty"`
+	Reason    string        `json:"reason,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+func (a *PlanAmendment) Validate() error {
+	if _, err := time.Parse(constants.DateFormat, a.Date); err != nil {
+		return fmt.Errorf("invalid plan date %q (expected YYYY-MM-DD)", a.Date)
+	}
+	if a.Revision < 1 {
+		return fmt.Errorf("amendment needs a plan revision")
+	}
+	if a.TaskID == "" {
+		return fmt.Errorf("amendment needs a task")
+	}
+
+	var times []string
+	switch a.Kind {
+	case AmendmentAdd:
+		times = []string{a.ToStart, a.ToEnd}
+	case AmendmentMove:
+		times = []string{a.FromStart, a.ToStart, a.ToEnd}
+	case AmendmentRemove:
+		times = []string{a.FromStart}
+	default:
+		return fmt.Errorf("invalid amendment kind %q (expected add, move or remove)", a.Kind)
+	}
+	for _, t := range times {
+		if _, err := time.Parse(constants.TimeFormat, t); err != nil {
+			return fmt.Errorf("invalid time %q (expected HH:MM)", t)
+		}
+	}
+	if a.Kind != AmendmentRemove && a.ToStart == a.ToEnd {
+		return fmt.Errorf("slot must end after it starts")
+	}
+	return nil
+}
+
+// Describe returns a one-line account of the change, with taskName standing
+// in for the task
+func (a PlanAmendment) Describe(taskName string) string {
+	switch a.Kind {
+	case AmendmentAdd:
+		return fmt.Sprintf("added %s %s–%s", taskName, a.ToStart, a.ToEnd)
+	case AmendmentMove:
+		return fmt.Sprintf("moved %s from %s–%s to %s–%s", taskName, a.FromStart, a.FromEnd, a.ToStart, a.ToEnd)
+	case AmendmentRemove:
+		return fmt.Sprintf("removed %s %s–%s", taskName, a.FromStart, a.FromEnd)
+	}
+	return string(a.Kind) + " " + taskName
+}
@@ -0,0 +1,7 @@
+package models
+
+// Change reports a write to the store, possibly made by another process or
+// another machine sharing the database
+type Change struct {
+	Table string // Table that changed, or empty when unknown
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrConflict is matched by errors.Is for writes rejected because the record
+// changed since it was loaded
+var ErrConflict = errors.New("record changed since you loaded it")
+
+// ConflictError reports a write rejected because another writer saved the
+// record after it was loaded
+type ConflictError struct {
+	Record string // Kind of record, e.g. "task"
+	ID     string // Identifies the record, e.g. a task ID or plan date
+}
+
+func (e *ConflictError) Error() string {
+	if e.ID == "" {
+		return fmt.Sprintf("%s changed since you loaded it; reload and try again", e.Record)
+	}
+	return fmt.Sprintf("%s %s changed since you loaded it; reload and try again", e.Record, e.ID)
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// MergeChanges applies the fields of a struct that changed from base to
+// mine on top of theirs, the latest stored record. It also returns the
+// names of the fields that both sides changed to different values; mine
+// wins for those. Fields are named by their JSON name when they have one.
+func MergeChanges[T any](base, mine, theirs T) (T, []string) {
+	merged := theirs
+	bv, mv := reflect.ValueOf(base), reflect.ValueOf(mine)
+	tv := reflect.ValueOf(&merged).Elem()
+
+	var overlapping []string
+	for i := 0; i < bv.NumField(); i++ {
+		field := bv.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		b, m, t := bv.Field(i).Interface(), mv.Field(i).Interface(), tv.Field(i).Interface()
+		if reflect.DeepEqual(b, m) {
+			continue
+		}
+		if !reflect.DeepEqual(b, t) && !reflect.DeepEqual(m, t) {
+			overlapping = append(overlapping, fieldName(field))
+		}
+		tv.Field(i).Set(mv.Field(i))
+	}
+	return merged, overlapping
+}
+
+// fieldName returns the JSON name of a struct field, or its Go name
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// FieldChange is a struct field that differs between two versions of a record
+type FieldChange struct {
+	Field string // JSON name of the field, or its Go name
+	Old   interface{}
+	New   interface{}
+}
+
+// DiffFields lists the exported fields of a struct that differ between
+// before and after, in declaration order
+func DiffFields[T any](before, after T) []FieldChange {
+	bv, av := reflect.ValueOf(before), reflect.ValueOf(after)
+
+	var changes []FieldChange
+	for i := 0; i < bv.NumField(); i++ {
+		field := bv.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		b, a := bv.Field(i).Interface(), av.Field(i).Interface()
+		if !reflect.DeepEqual(b, a) {
+			changes = append(changes, FieldChange{Field: fieldName(field), Old: b, New: a})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMergeChanges(t *testing.T) {
+	offset := 10
+	base := Task{ID: "t1", Name: "Read", DurationMin: 30, Priority: 3, Context: "home", Version: 1}
+
+	mine := base
+	mine.Name = "Read a book"
+	mine.Context = ""
+	mine.NotifyOffsetMin = &offset
+
+	theirs := base
+	theirs.DurationMin = 45
+	theirs.Context = "office"
+	theirs.SuccessStreak = 2
+	theirs.Version = 2
+
+	merged, overlapping := MergeChanges(base, mine, theirs)
+
+	if merged.Name != "Read a book" || merged.NotifyOffsetMin == nil || *merged.NotifyOffsetMin != 10 {
+		t.Errorf("expected my changes to be applied, got %+v", merged)
+	}
+	if merged.DurationMin != 45 || merged.SuccessStreak != 2 {
+		t.Errorf("expected their changes to be kept, got %+v", merged)
+	}
+	if merged.Context != "" {
+		t.Errorf("expected my change to win where both changed, got context %q", merged.Context)
+	}
+	if merged.Version != 2 {
+		t.Errorf("expected the latest version, got %d", merged.Version)
+	}
+	if want := []string{"context"}; !reflect.DeepEqual(overlapping, want) {
+		t.Errorf("overlapping = %v, want %v", overlapping, want)
+	}
+}
+
+func TestMergeChanges_SameEdit(t *testing.T) {
+	base := Settings{DayStart: "07:00", MorningPlanNotifiedOn: "2024-01-01"}
+	mine := base
+	mine.DayStart = "06:00"
+	theirs := base
+	theirs.DayStart = "06:00"
+	theirs.MorningPlanNotifiedOn = "2024-01-02"
+
+	merged, overlapping := MergeChanges(base, mine, theirs)
+	if len(overlapping) != 0 {
+		t.Errorf("identical edits should not overlap, got %v", overlapping)
+	}
+	if merged.MorningPlanNotifiedOn != "2024-01-02" {
+		t.Errorf("expected their marker to be kept, got %q", merged.MorningPlanNotifiedOn)
+	}
+}
+
+func TestConflictError(t *testing.T) {
+	err := error(&ConflictError{Record: "task", ID: "t1"})
+	if !errors.Is(err, ErrConflict) {
+		t.Error("ConflictError should match ErrConflict")
+	}
+	if got, want := err.Error(), "task t1 changed since you loaded it; reload and try again"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	valid := []string{
+		"* * * * *",
+		"0 9 * * 1-5",
+		"*/15 8-18 * * mon,wed,fri",
+		"30 7 1 * *",
+		"0 12 14 3 *",
+		"0 0 * jan-mar sun",
+		"5/20 * * * 7",
+		"@daily",
+		"@Monthly",
+	}
+	for _, expr := range valid {
+		if _, err := ParseCron(expr); err != nil {
+			t.Errorf("ParseCron(%q) error = %v", expr, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"x * * * *",
+		"@sometimes",
+	}
+	for _, expr := range invalid {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) expected an error", expr)
+		}
+	}
+}
+
+func TestCronSchedule_Matches(t *testing.T) {
+	tests := []struct {
+		expr string
+		at   time.Time
+		want bool
+	}{
+		{"0 9 * * 1-5", time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), true},  // Monday
+		{"0 9 * * 1-5", time.Date(2026, 1, 5, 9, 1, 0, 0, time.UTC), false}, // wrong minute
+		{"0 9 * * 1-5", time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC), false}, // Saturday
+		{"*/15 * * * *", time.Date(2026, 1, 5, 14, 45, 0, 0, time.UTC), true},
+		{"*/15 * * * *", time.Date(2026, 1, 5, 14, 50, 0, 0, time.UTC), false},
+		{"0 8 1 * *", time.Date(2026, 2, 1, 8, 0, 0, 0, time.UTC), true},
+		{"0 0 * * 7", time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC), true},     // Sunday as 7
+		{"0 10 14 3 *", time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC), true}, // yearly
+		{"0 10 14 3 *", time.Date(2026, 4, 14, 10, 0, 0, 0, time.UTC), false},
+		// Day of month and day of week are either/or when both are set
+		{"0 9 13 * fri", time.Date(2026, 1, 13, 9, 0, 0, 0, time.UTC), true}, // Tuesday the 13th
+		{"0 9 13 * fri", time.Date(2026, 1, 16, 9, 0, 0, 0, time.UTC), true}, // Friday the 16th
+		{"0 9 13 * fri", time.Date(2026, 1, 14, 9, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) error = %v", tt.expr, err)
+		}
+		if got := c.Matches(tt.at); got != tt.want {
+			t.Errorf("%q.Matches(%s) = %v, want %v", tt.expr, tt.at.Format("Mon 2006-01-02 15:04"), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+const (
+	// MinutesPerDay is the number of minutes from one midnight to the next
+	MinutesPerDay = 24 * 60
+
+	// MaxOvernightMinutes caps how long a range ending before its start may
+	// be. Longer ranges are taken as an end before the start, not as a range
+	// that crosses midnight.
+	MaxOvernightMinutes = 12 * 60
+)
+
+// DayWindow is the waking window of a plan day in minutes from midnight of
+// the plan date. End is past MinutesPerDay when the day ends after midnight,
+// e.g. a 07:00–01:30 window is 420–1530.
+type DayWindow struct {
+	Start int
+	End   int
+}
+
+// ParseDayWindow parses day_start and day_end. A day_end before day_start
+// is on the next day.
+func ParseDayWindow(dayStart, dayEnd string) (DayWindow, error) {
+	start, err := parseClock(dayStart)
+	if err != nil {
+		return DayWindow{}, fmt.Errorf("invalid day start time: %w", err)
+	}
+	end, err := parseClock(dayEnd)
+	if err != nil {
+		return DayWindow{}, fmt.Errorf("invalid day end time: %w", err)
+	}
+	if end == start {
+		return DayWindow{}, fmt.Errorf("day_start (%s) and day_end (%s) must differ", dayStart, dayEnd)
+	}
+	if end < start {
+		end += MinutesPerDay
+	}
+	return DayWindow{Start: start, End: end}, nil
+}
+
+// CrossesMidnight reports whether the day ends after midnight
+func (w DayWindow) CrossesMidnight() bool {
+	return w.End > MinutesPerDay
+}
+
+// Minutes converts an HH:MM time to minutes on the plan day. When the day
+// ends after midnight, times before the day start belong to the next day.
+func (w DayWindow) Minutes(timeStr string) (int, error) {
+	m, err := parseClock(timeStr)
+	if err != nil {
+		return 0, err
+	}
+	if w.CrossesMidnight() && m < w.Start {
+		m += MinutesPerDay
+	}
+	return m, nil
+}
+
+// Range converts a start and end time to minutes on the plan day. A range
+// that ends before it starts crosses midnight; it is an error if that would
+// make it longer than MaxOvernightMinutes.
+func (w DayWindow) Range(start, end string) (int, int, error) {
+	s, err := w.Minutes(start)
+	if err != nil {
+		return 0, 0, err
+	}
+	e, err := parseClock(end)
+	if err != nil {
+		return 0, 0, err
+	}
+	e += s - s%MinutesPerDay
+	if e < s {
+		e += MinutesPerDay
+		if e-s > MaxOvernightMinutes {
+			return 0, 0, fmt.Errorf("end time %s is before start time %s", end, start)
+		}
+	}
+	return s, e, nil
+}
+
+// SortSlots orders slots by their start on the plan day, so slots after
+// midnight follow the late-evening ones
+func (w DayWindow) SortSlots(slots []Slot) {
+	sort.SliceStable(slots, func(i, j int) bool {
+		si, erri := w.Minutes(slots[i].Start)
+		sj, errj := w.Minutes(slots[j].Start)
+		if erri != nil || errj != nil {
+			return slots[i].Start < slots[j].Start
+		}
+		return si < sj
+	})
+}
+
+// Upcoming reports whether slot, planned for date, is still to be done at
+// now: on a later day, or today and not started yet. Deleted, done and
+// skipped slots aren't upcoming.
+func (w DayWindow) Upcoming(date string, slot Slot, now time.Time) bool {
+	if slot.DeletedAt != nil || slot.Status == constants.SlotStatusDone || slot.Status == constants.SlotStatusSkipped {
+		return false
+	}
+	today := now.Format(constants.DateFormat)
+	if date != today {
+		return date > today
+	}
+	start, err := w.Minutes(slot.Start)
+	if err != nil {
+		return false
+	}
+	nowMinutes, err := w.Minutes(now.Format(constants.TimeFormat))
+	return err == nil && start >= nowMinutes
+}
+
+// parseClock returns the minutes from midnight of an HH:MM time
+func parseClock(timeStr string) (int, error) {
+	t, err := time.Parse("15:04", timeStr)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// SlotAt returns the index of the first slot that covers minute m of the
+// plan day and satisfies keep, or -1. A nil keep accepts every slot.
+func (w DayWindow) SlotAt(slots []Slot, m int, keep func(Slot) bool) int {
+	for i, slot := range slots {
+		if keep != nil && !keep(slot) {
+			continue
+		}
+		start, end, err := w.Range(slot.Start, slot.End)
+		if err != nil {
+			continue
+		}
+		if start <= m && m < end {
+			return i
+		}
+	}
+	return -1
+}
+
+// Countdown places a minute of the plan day among the slots: the block it
+// falls in, how far along that block is, and the next block to start
+type Countdown struct {
+	Current   int // Index of the slot covering the minute, or -1
+	Elapsed   int // Minutes of the current block gone by
+	Remaining int // Minutes left in the current block
+	Next      int // Index of the next slot to start, or -1
+	UntilNext int // Minutes until the next slot starts
+}
+
+// Progress is the fraction of the current block gone by, from 0 to 1
+func (c Countdown) Progress() float64 {
+	total := c.Elapsed + c.Remaining
+	if c.Current < 0 || total <= 0 {
+		return 0
+	}
+	return float64(c.Elapsed) / float64(total)
+}
+
+// ProgressBar draws the progress of the current block as a bar width
+// characters wide
+func (c Countdown) ProgressBar(width int) string {
+	filled := int(c.Progress()*float64(width) + 0.5)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// CountdownAt finds the slot covering minute m of the plan day and the next
+// slot to start after m, among the slots that satisfy keep. A nil keep
+// accepts every slot.
+func (w DayWindow) CountdownAt(slots []Slot, m int, keep func(Slot) bool) Countdown {
+	c := Countdown{Current: -1, Next: -1}
+	if i := w.SlotAt(slots, m, keep); i >= 0 {
+		start, end, _ := w.Range(slots[i].Start, slots[i].End)
+		c.Current, c.Elapsed, c.Remaining = i, m-start, end-m
+	}
+	for i, slot := range slots {
+		if keep != nil && !keep(slot) {
+			continue
+		}
+		start, _, err := w.Range(slot.Start, slot.End)
+		if err != nil || start <= m {
+			continue
+		}
+		if c.Next < 0 || start-m < c.UntilNext {
+			c.Next, c.UntilNext = i, start-m
+		}
+	}
+	return c
+}
+
+// SlotDrift compares the tracked start and end of a slot with its planned
+// times, in minutes late (positive) or early (negative)
+type SlotDrift struct {
+	Start    int
+	End      int
+	HasStart bool // The slot has a valid actual start
+	HasEnd   bool // The slot has a valid actual end
+}
+
+// Tracked reports whether the slot has any actual time to compare
+func (d SlotDrift) Tracked() bool {
+	return d.HasStart || d.HasEnd
+}
+
+// Minutes is how far the slot ran off its plan: its end drift once it has
+// ended, else its start drift, always positive
+func (d SlotDrift) Minutes() int {
+	m := 0
+	switch {
+	case d.HasEnd:
+		m = d.End
+	case d.HasStart:
+		m = d.Start
+	}
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// Drift compares slot's actual start and end with its planned ones. Actual
+// times that can't be parsed are left out.
+func (w DayWindow) Drift(slot Slot) SlotDrift {
+	var d SlotDrift
+	start, end, err := w.Range(slot.Start, slot.End)
+	if err != nil {
+		return d
+	}
+	if slot.ActualStart != nil {
+		if m, err := w.Minutes(*slot.ActualStart); err == nil {
+			d.Start, d.HasStart = clockDiff(m, start), true
+		}
+	}
+	if slot.ActualEnd != nil {
+		if m, err := w.Minutes(*slot.ActualEnd); err == nil {
+			d.End, d.HasEnd = clockDiff(m, end), true
+		}
+	}
+	return d
+}
+
+// TotalDrift sums the drift minutes of the slots
+func (w DayWindow) TotalDrift(slots []Slot) int {
+	total := 0
+	for _, slot := range slots {
+		total += w.Drift(slot).Minutes()
+	}
+	return total
+}
+
+// clockDiff returns actual - planned, taking the shorter way around the
+// clock so times just past midnight compare with those just before it
+func clockDiff(actual, planned int) int {
+	diff := (actual - planned) % MinutesPerDay
+	switch {
+	case diff > MinutesPerDay/2:
+		diff -= MinutesPerDay
+	case diff < -MinutesPerDay/2:
+		diff += MinutesPerDay
+	}
+	return diff
+}
@@ -0,0 +1,165 @@
+package models
+
+import "testing"
+
+func TestParseDayWindow(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     string
+		end       string
+		wantStart int
+		wantEnd   int
+		wantErr   bool
+	}{
+		{name: "same day", start: "07:00", end: "22:00", wantStart: 420, wantEnd: 1320},
+		{name: "ends after midnight", start: "07:00", end: "01:30", wantStart: 420, wantEnd: 1530},
+		{name: "equal times", start: "07:00", end: "07:00", wantErr: true},
+		{name: "invalid end", start: "07:00", end: "25:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ParseDayWindow(tt.start, tt.end)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if w.Start != tt.wantStart || w.End != tt.wantEnd {
+				t.Errorf("got %d–%d, want %d–%d", w.Start, w.End, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestDayWindow_Range(t *testing.T) {
+	late := DayWindow{Start: 420, End: 1530}
+
+	tests := []struct {
+		name      string
+		window    DayWindow
+		start     string
+		end       string
+		wantStart int
+		wantEnd   int
+		wantErr   bool
+	}{
+		{name: "plain", window: DayWindow{}, start: "09:00", end: "10:00", wantStart: 540, wantEnd: 600},
+		{name: "crosses midnight", window: DayWindow{}, start: "23:30", end: "00:30", wantStart: 1410, wantEnd: 1470},
+		{name: "after midnight in late window", window: late, start: "00:30", end: "01:00", wantStart: 1470, wantEnd: 1500},
+		{name: "end well before start", window: DayWindow{}, start: "10:00", end: "09:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, e, err := tt.window.Range(tt.start, tt.end)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s != tt.wantStart || e != tt.wantEnd {
+				t.Errorf("got %d–%d, want %d–%d", s, e, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestDayWindow_SortSlotsAndSlotAt(t *testing.T) {
+	w := DayWindow{Start: 420, End: 1530}
+	slots := []Slot{
+		{Start: "00:30", End: "01:00", TaskID: "late"},
+		{Start: "23:30", End: "00:30", TaskID: "overnight"},
+		{Start: "08:00", End: "09:00", TaskID: "morning"},
+	}
+
+	w.SortSlots(slots)
+	want := []string{"morning", "overnight", "late"}
+	for i, id := range want {
+		if slots[i].TaskID != id {
+			t.Fatalf("slot %d = %s, want %s", i, slots[i].TaskID, id)
+		}
+	}
+
+	// 00:10 on the next day
+	if i := w.SlotAt(slots, MinutesPerDay+10, nil); i != 1 {
+		t.Errorf("SlotAt(00:10) = %d, want 1", i)
+	}
+	if i := w.SlotAt(slots, 600, nil); i != -1 {
+		t.Errorf("SlotAt(10:00) = %d, want -1", i)
+	}
+}
+
+func TestDayWindow_CountdownAt(t *testing.T) {
+	w := DayWindow{Start: 420, End: 1530}
+	slots := []Slot{
+		{Start: "09:00", End: "10:00", TaskID: "read", Status: "accepted"},
+		{Start: "11:00", End: "12:00", TaskID: "gym", Status: "skipped"},
+		{Start: "12:30", End: "13:00", TaskID: "lunch", Status: "accepted"},
+		{Start: "23:30", End: "00:30", TaskID: "overnight", Status: "accepted"},
+	}
+
+	// 09:37: 23 minutes left of read, gym starts in 83
+	c := w.CountdownAt(slots, 577, nil)
+	if c.Current != 0 || c.Elapsed != 37 || c.Remaining != 23 || c.Next != 1 || c.UntilNext != 83 {
+		t.Errorf("CountdownAt(09:37) = %+v", c)
+	}
+	if bar := c.ProgressBar(10); bar != "██████░░░░" {
+		t.Errorf("ProgressBar = %q", bar)
+	}
+
+	// Skipped slots are left out when keep says so
+	active := func(s Slot) bool { return s.Status != "skipped" }
+	c = w.CountdownAt(slots, 630, active)
+	if c.Current != -1 || c.Progress() != 0 || c.Next != 2 || c.UntilNext != 120 {
+		t.Errorf("CountdownAt(10:30) = %+v", c)
+	}
+
+	// 00:10 on the next day, with nothing after the overnight block
+	c = w.CountdownAt(slots, MinutesPerDay+10, nil)
+	if c.Current != 3 || c.Remaining != 20 || c.Next != -1 {
+		t.Errorf("CountdownAt(00:10) = %+v", c)
+	}
+}
+
+func TestDayWindow_Drift(t *testing.T) {
+	at := func(s string) *string { return &s }
+	w := DayWindow{Start: 420, End: 1320}
+
+	tests := []struct {
+		name        string
+		slot        Slot
+		wantStart   int
+		wantEnd     int
+		wantMinutes int
+		wantTracked bool
+	}{
+		{name: "untracked", slot: Slot{Start: "09:00", End: "10:00"}},
+		{name: "started late", slot: Slot{Start: "09:00", End: "10:00", ActualStart: at("09:10")}, wantStart: 10, wantMinutes: 10, wantTracked: true},
+		{name: "ended early", slot: Slot{Start: "09:00", End: "10:00", ActualStart: at("09:10"), ActualEnd: at("09:45")}, wantStart: 10, wantEnd: -15, wantMinutes: 15, wantTracked: true},
+		{name: "ran past midnight", slot: Slot{Start: "23:00", End: "23:50", ActualEnd: at("00:05")}, wantEnd: 15, wantMinutes: 15, wantTracked: true},
+		{name: "invalid actual", slot: Slot{Start: "09:00", End: "10:00", ActualStart: at("later")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := w.Drift(tt.slot)
+			if d.Start != tt.wantStart || d.End != tt.wantEnd || d.Minutes() != tt.wantMinutes || d.Tracked() != tt.wantTracked {
+				t.Errorf("got %+v (%d minutes), want start %d, end %d, %d minutes", d, d.Minutes(), tt.wantStart, tt.wantEnd, tt.wantMinutes)
+			}
+		})
+	}
+
+	slots := []Slot{tests[1].slot, tests[2].slot, tests[3].slot}
+	if total := w.TotalDrift(slots); total != 40 {
+		t.Errorf("TotalDrift = %d, want 40", total)
+	}
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"sort"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+// FreeTime is how much of a day a plan leaves unscheduled
+type FreeTime struct {
+	Date    string `json:"date"`
+	Free    int    `json:"free_minutes"`    // Minutes of the day window without a slot, leisure slots included
+	Leisure int    `json:"leisure_minutes"` // Minutes of leisure slots
+}
+
+// PlanFreeTime works out the free time plan leaves in the day window.
+// Deleted and skipped slots, and slots for tasks that leisure reports as
+// leisure, count as free time. Overlapping slots are only counted once, and
+// slots are cut to the day window.
+func PlanFreeTime(plan DayPlan, window DayWindow, leisure func(taskID string) bool) FreeTime {
+	ft := FreeTime{Date: plan.Date}
+
+	type span struct{ start, end int }
+	var busy []span
+	for _, slot := range plan.Slots {
+		if slot.DeletedAt != nil || slot.Status == constants.SlotStatusSkipped {
+			continue
+		}
+		start, end, err := window.Range(slot.Start, slot.End)
+		if err != nil {
+			continue
+		}
+		start, end = max(start, window.Start), min(end, window.End)
+		if start >= end {
+			continue
+		}
+		if leisure != nil && leisure(slot.TaskID) {
+			ft.Leisure += end - start
+			continue
+		}
+		busy = append(busy, span{start, end})
+	}
+
+	sort.Slice(busy, func(i, j int) bool { return busy[i].start < busy[j].start })
+	scheduled, cursor := 0, window.Start
+	for _, s := range busy {
+		start := max(s.start, cursor)
+		if s.end > start {
+			scheduled += s.end - start
+			cursor = s.end
+		}
+	}
+	ft.Free = window.End - window.Start - scheduled
+	return ft
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+func TestPlanFreeTime(t *testing.T) {
+	deleted := "2025-06-02T08:00:00Z"
+	plan := DayPlan{
+		Date: "2025-06-02",
+		Slots: []Slot{
+			{Start: "07:00", End: "09:00", TaskID: "early"}, // Only the hour from 08:00 counts
+			{Start: "09:00", End: "11:00", TaskID: "work"},
+			{Start: "10:00", End: "12:00", TaskID: "meeting"}, // Overlaps work by an hour
+			{Start: "12:00", End: "13:00", TaskID: "walk"},
+			{Start: "13:00", End: "14:00", TaskID: "email", Status: constants.SlotStatusSkipped},
+			{Start: "14:00", End: "15:00", TaskID: "work", DeletedAt: &deleted},
+		},
+	}
+	window, err := ParseDayWindow("08:00", "18:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ft := PlanFreeTime(plan, window, func(id string) bool { return id == "walk" })
+	// 10h day minus 08:00-12:00 busy
+	if ft.Free != 360 || ft.Leisure != 60 || ft.Date != "2025-06-02" {
+		t.Errorf("PlanFreeTime = %+v, want 360m free with 60m leisure", ft)
+	}
+
+	if ft := PlanFreeTime(plan, window, nil); ft.Free != 300 || ft.Leisure != 0 {
+		t.Errorf("PlanFreeTime without leisure = %+v, want 300m free", ft)
+	}
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"sort"
+	"strings"
+	"time"
+)
 
 // Habit represents a recurring practice to track
 type Habit struct {
@@ -9,6 +13,91 @@ type Habit struct {
 	CreatedAt  time.Time  `json:"created_at"`
 	ArchivedAt *time.Time `json:"archived_at,omitempty"`
 	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	Category   string     `json:"category,omitempty"` // e.g. health, chores, learning; empty for none
+
+	// A pause makes the days from PausedFrom to PausedUntil, inclusive and in
+	// YYYY-MM-DD format, neutral: they neither count toward nor break streaks
+	// and completion rates. Empty when the habit was never paused.
+	PausedFrom  string `json:"paused_from,omitempty"`
+	PausedUntil string `json:"paused_until,omitempty"`
+
+	// Reminder nags about the habit at a time of day while it is unmarked
+	ReminderTime    string         `json:"reminder_time,omitempty"` // HH:MM, empty for no reminder
+	ReminderDays    []time.Weekday `json:"reminder_days,omitempty"` // Days to remind on, empty for every day
+	ReminderEnabled bool           `json:"reminder_enabled,omitempty"`
+	LastReminded    *time.Time     `json:"last_reminded,omitempty"`
+}
+
+// RemindsOn reports whether the habit has a reminder that is on for day
+func (h *Habit) RemindsOn(day time.Weekday) bool {
+	if !h.ReminderEnabled || h.ReminderTime == "" {
+		return false
+	}
+	if len(h.ReminderDays) == 0 {
+		return true
+	}
+	for _, d := range h.ReminderDays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// PausedOn reports whether day (YYYY-MM-DD) falls in the habit's pause
+func (h *Habit) PausedOn(day string) bool {
+	return h.PausedFrom != "" && day >= h.PausedFrom && day <= h.PausedUntil
+}
+
+// ReminderSummary describes the habit's reminder, e.g. "21:00 on mon,fri"
+func (h *Habit) ReminderSummary() string {
+	if h.ReminderTime == "" {
+		return "none"
+	}
+	summary := h.ReminderTime + " daily"
+	if len(h.ReminderDays) > 0 {
+		days := make([]string, len(h.ReminderDays))
+		for i, d := range h.ReminderDays {
+			days[i] = d.String()[:3]
+		}
+		summary = h.ReminderTime + " on " + strings.Join(days, ",")
+	}
+	if !h.ReminderEnabled {
+		summary += " (off)"
+	}
+	return summary
+}
+
+// NormalizeHabitCategory trims and lowercases a category so "Health" and
+// "health " group together
+func NormalizeHabitCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+// HabitGroup is the habits of one category
+type HabitGroup struct {
+	Category string // Empty for habits without a category
+	Habits   []Habit
+}
+
+// GroupHabits groups habits by category, ordered by category with
+// uncategorized habits first. Habits keep their order within a group.
+func GroupHabits(habits []Habit) []HabitGroup {
+	var groups []HabitGroup
+	index := make(map[string]int)
+	for _, h := range habits {
+		i, ok := index[h.Category]
+		if !ok {
+			i = len(groups)
+			index[h.Category] = i
+			groups = append(groups, HabitGroup{Category: h.Category})
+		}
+		groups[i].Habits = append(groups[i].Habits, h)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Category < groups[j].Category
+	})
+	return groups
 }
 
 // HabitEntry represents a single day's record of a habit
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultUserName refers to the default user, whose name is empty, where a
+// user has to be named
+const DefaultUserName = "default"
+
+// SharedMember is one of the users taking turns at a shared task
+type SharedMember struct {
+	User     string `json:"user"`                // Empty for the default user
+	LastDone string `json:"last_done,omitempty"` // YYYY-MM-DD they last did the task
+}
+
+// SharedTask is a household task, such as taking out the recycling, that
+// several users on a shared database take turns at. Every member has their
+// own copy of the task with the same ID.
+type SharedTask struct {
+	TaskID    string         `json:"task_id"`
+	Members   []SharedMember `json:"members"` // In turn order
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+func (t *SharedTask) Validate() error {
+	if t.TaskID == "" {
+		return fmt.Errorf("shared task needs a task")
+	}
+	if len(t.Members) < 2 {
+		return fmt.Errorf("a shared task needs at least two users")
+	}
+	seen := make(map[string]bool, len(t.Members))
+	for _, m := range t.Members {
+		if m.User != "" {
+			if err := ValidateUserName(m.User); err != nil {
+				return err
+			}
+		}
+		if seen[m.User] {
+			return fmt.Errorf("user %s is listed twice", UserLabel(m.User))
+		}
+		seen[m.User] = true
+	}
+	return nil
+}
+
+// LastDoneBy returns the member who did the task most recently, or false
+// when nobody has done it yet. Members who did it on the same day count in
+// turn order, the later one last.
+func (t SharedTask) LastDoneBy() (SharedMember, bool) {
+	var last SharedMember
+	found := false
+	for _, m := range t.Members {
+		if m.LastDone != "" && m.LastDone >= last.LastDone {
+			last, found = m, true
+		}
+	}
+	return last, found
+}
+
+// Turn returns the user whose turn it is: the member after the one who did
+// the task last, or the first member when nobody has done it yet
+func (t SharedTask) Turn() string {
+	if len(t.Members) == 0 {
+		return ""
+	}
+	last, ok := t.LastDoneBy()
+	if !ok {
+		return t.Members[0].User
+	}
+	for i, m := range t.Members {
+		if m.User == last.User {
+			return t.Members[(i+1)%len(t.Members)].User
+		}
+	}
+	return t.Members[0].User
+}
+
+// UserLabel names a user for display, the default user as "default"
+func UserLabel(name string) string {
+	if name == "" {
+		return DefaultUserName
+	}
+	return name
+}
@@ -0,0 +1,62 @@
+package models
+
+import "testing"
+
+func TestSharedTask_Turn(t *testing.T) {
+	members := func(lastDone ...string) []SharedMember {
+		users := []string{"", "sam", "alex"}
+		var ms []SharedMember
+		for i, d := range lastDone {
+			ms = append(ms, SharedMember{User: users[i], LastDone: d})
+		}
+		return ms
+	}
+
+	tests := []struct {
+		name     string
+		members  []SharedMember
+		wantLast string
+		wantTurn string
+	}{
+		{name: "never done", members: members("", "", ""), wantLast: "-", wantTurn: ""},
+		{name: "after the last one", members: members("2026-11-01", "2026-11-03", ""), wantLast: "sam", wantTurn: "alex"},
+		{name: "wraps around", members: members("2026-11-01", "2026-11-02", "2026-11-04"), wantLast: "alex", wantTurn: ""},
+		{name: "same day goes by turn order", members: members("2026-11-04", "2026-11-04", ""), wantLast: "sam", wantTurn: "alex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := SharedTask{TaskID: "recycling", Members: tt.members}
+			last, ok := st.LastDoneBy()
+			if got := map[bool]string{true: last.User, false: "-"}[ok]; got != tt.wantLast {
+				t.Errorf("LastDoneBy() = %q, want %q", got, tt.wantLast)
+			}
+			if got := st.Turn(); got != tt.wantTurn {
+				t.Errorf("Turn() = %q, want %q", got, tt.wantTurn)
+			}
+		})
+	}
+}
+
+func TestSharedTask_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		members []string
+		wantErr bool
+	}{
+		{name: "default user and another", members: []string{"", "sam"}},
+		{name: "alone", members: []string{"sam"}, wantErr: true},
+		{name: "listed twice", members: []string{"sam", "alex", "sam"}, wantErr: true},
+		{name: "reserved name", members: []string{"sam", "default"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := SharedTask{TaskID: "recycling"}
+			for _, user := range tt.members {
+				st.Members = append(st.Members, SharedMember{User: user})
+			}
+			if err := st.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
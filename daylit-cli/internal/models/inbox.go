@@ -0,0 +1,22 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// InboxItem is raw text captured for later, to be triaged into a task, a One
+// Thing or an alert
+type InboxItem struct {
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (i *InboxItem) Validate() error {
+	if strings.TrimSpace(i.Text) == "" {
+		return fmt.Errorf("inbox text cannot be empty")
+	}
+	return nil
+}
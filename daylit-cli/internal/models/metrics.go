@@ -0,0 +1,100 @@
+package models
+
+import "time"
+
+// DailyMetrics are the scheduling quality indicators of one day, computed
+// from the latest revision of its plan. They are only recorded when metrics
+// are turned on in the settings, and never leave the local database.
+type DailyMetrics struct {
+	Date         string    `json:"date"`          // YYYY-MM-DD
+	HasPlan      bool      `json:"has_plan"`      // Whether the day had a plan
+	Accepted     bool      `json:"accepted"`      // Whether the plan was accepted
+	PlannedSlots int       `json:"planned_slots"` // Slots in the plan
+	DoneSlots    int       `json:"done_slots"`    // Slots marked done
+	SkippedSlots int       `json:"skipped_slots"` // Slots marked skipped
+	TrackedSlots int       `json:"tracked_slots"` // Slots with a tracked start or stop time
+	DriftMinutes int       `json:"drift_minutes"` // Total drift of the tracked slots from the plan
+	OnTrack      int       `json:"on_track"`      // Slots rated on_track
+	TooMuch      int       `json:"too_much"`      // Slots rated too_much
+	Unnecessary  int       `json:"unnecessary"`   // Slots rated unnecessary
+	ComputedAt   time.Time `json:"computed_at"`
+}
+
+// MetricsSummary aggregates daily metrics over a period
+type MetricsSummary struct {
+	Days         int `json:"days"`         // Days with metrics
+	PlannedDays  int `json:"planned_days"` // Days that had a plan
+	AcceptedDays int `json:"accepted_days"`
+	PlannedSlots int `json:"planned_slots"`
+	DoneSlots    int `json:"done_slots"`
+	TrackedDays  int `json:"tracked_days"` // Days with at least one tracked slot
+	DriftMinutes int `json:"drift_minutes"`
+	OnTrack      int `json:"on_track"`
+	TooMuch      int `json:"too_much"`
+	Unnecessary  int `json:"unnecessary"`
+}
+
+// SummarizeMetrics adds up the metrics of the given days
+func SummarizeMetrics(days []DailyMetrics) MetricsSummary {
+	var s MetricsSummary
+	for _, d := range days {
+		s.Days++
+		if d.HasPlan {
+			s.PlannedDays++
+		}
+		if d.Accepted {
+			s.AcceptedDays++
+		}
+		s.PlannedSlots += d.PlannedSlots
+		s.DoneSlots += d.DoneSlots
+		if d.TrackedSlots > 0 {
+			s.TrackedDays++
+			s.DriftMinutes += d.DriftMinutes
+		}
+		s.OnTrack += d.OnTrack
+		s.TooMuch += d.TooMuch
+		s.Unnecessary += d.Unnecessary
+	}
+	return s
+}
+
+// AcceptanceRate returns the percentage of planned days whose plan was
+// accepted, or 0 when no day had a plan
+func (s MetricsSummary) AcceptanceRate() int {
+	if s.PlannedDays == 0 {
+		return 0
+	}
+	return s.AcceptedDays * 100 / s.PlannedDays
+}
+
+// CompletionRate returns the percentage of planned slots that were done, or
+// 0 when nothing was planned
+func (s MetricsSummary) CompletionRate() int {
+	if s.PlannedSlots == 0 {
+		return 0
+	}
+	return s.DoneSlots * 100 / s.PlannedSlots
+}
+
+// AvgDailyDrift returns the average drift in minutes of the days with
+// tracked slots, or 0 when none were tracked
+func (s MetricsSummary) AvgDailyDrift() int {
+	if s.TrackedDays == 0 {
+		return 0
+	}
+	return s.DriftMinutes / s.TrackedDays
+}
+
+// Rated returns how many slots received feedback
+func (s MetricsSummary) Rated() int {
+	return s.OnTrack + s.TooMuch + s.Unnecessary
+}
+
+// FeedbackShare returns count as a percentage of the rated slots, or 0 when
+// none were rated
+func (s MetricsSummary) FeedbackShare(count int) int {
+	if s.Rated() == 0 {
+		return 0
+	}
+	return count * 100 / s.Rated()
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+// NotificationLogEntry records a notification that `daylit notify` sent or
+// failed to send
+type NotificationLogEntry struct {
+	ID        int64     `json:"id"`
+	SentAt    time.Time `json:"sent_at"`
+	Kind      string    `json:"kind"`                 // block_start, block_end, alert, or morning_plan
+	PlanDate  string    `json:"plan_date,omitempty"`  // YYYY-MM-DD, for block notifications
+	SlotStart string    `json:"slot_start,omitempty"` // HH:MM, for block notifications
+	TaskID    string    `json:"task_id,omitempty"`
+	AlertID   string    `json:"alert_id,omitempty"`
+	Message   string    `json:"message"`
+	Channel   string    `json:"channel"` // tray or dry_run
+	Success   bool      `json:"success"`
+	Error     string    `json:"error,omitempty"`
+
+	// Urgency and Sound are passed on to the notifier and aren't recorded
+	Urgency string `json:"-"`
+	Sound   string `json:"-"`
+}
+
+// ValidateUrgency checks a notification urgency; empty is normal
+func ValidateUrgency(urgency string) error {
+	switch urgency {
+	case "", constants.NotificationUrgencyLow, constants.NotificationUrgencyNormal, constants.NotificationUrgencyCritical:
+		return nil
+	}
+	return fmt.Errorf("invalid urgency %q (use %s, %s or %s)", urgency,
+		constants.NotificationUrgencyLow, constants.NotificationUrgencyNormal, constants.NotificationUrgencyCritical)
+}
+
+// SlotNotificationKey returns the idempotency key of a slot's start or end
+// notification (kind), which names the slot by plan date, start and task,
+// so a plan revised with the same slot doesn't notify it again
+func SlotNotificationKey(kind, planDate, slotStart, taskID string) string {
+	return strings.Join([]string{kind, planDate, slotStart, taskID}, "|")
+}
+
+// AlertNotificationKey returns the idempotency key of an alert's
+// notification for the time it is due at
+func AlertNotificationKey(alertID string, due time.Time) string {
+	return strings.Join([]string{constants.NotificationKindAlert, alertID, due.UTC().Format(time.RFC3339)}, "|")
+}
+
+// Outbox statuses
+const (
+	OutboxPending = "pending" // waiting for its next attempt
+	OutboxFailed  = "failed"  // given up on
+)
+
+// OutboxItem is a notification whose delivery failed, kept to be retried on
+// later `daylit notify` runs
+type OutboxItem struct {
+	ID            int64     `json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	Kind          string    `json:"kind"`
+	PlanDate      string    `json:"plan_date,omitempty"`
+	SlotStart     string    `json:"slot_start,omitempty"`
+	TaskID        string    `json:"task_id,omitempty"`
+	AlertID       string    `json:"alert_id,omitempty"`
+	Message       string    `json:"message"`
+	Urgency       string    `json:"urgency,omitempty"`
+	Sound         string    `json:"sound,omitempty"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error,omitempty"`
+	Status        string    `json:"status"` // pending or failed
+}
+
+// NewOutboxItem returns a pending outbox item for a notification whose first
+// delivery failed with sendErr
+func NewOutboxItem(entry NotificationLogEntry, sendErr error) OutboxItem {
+	item := OutboxItem{
+		CreatedAt: entry.SentAt,
+		Kind:      entry.Kind,
+		PlanDate:  entry.PlanDate,
+		SlotStart: entry.SlotStart,
+		TaskID:    entry.TaskID,
+		AlertID:   entry.AlertID,
+		Message:   entry.Message,
+		Urgency:   entry.Urgency,
+		Sound:     entry.Sound,
+		Attempts:  1,
+		Status:    OutboxPending,
+	}
+	if sendErr != nil {
+		item.LastError = sendErr.Error()
+	}
+	item.NextAttemptAt = entry.SentAt.Add(item.Backoff())
+	return item
+}
+
+// Backoff returns how long to wait before the next attempt, doubling from
+// constants.NotifyOutboxBaseDelay with each attempt made, up to
+// constants.NotifyOutboxMaxDelay
+func (o OutboxItem) Backoff() time.Duration {
+	delay := constants.NotifyOutboxBaseDelay
+	for i := 1; i < o.Attempts && delay < constants.NotifyOutboxMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, constants.NotifyOutboxMaxDelay)
+}
+
+// Entry returns the notification log entry of an attempt made at at
+func (o OutboxItem) Entry(at time.Time) NotificationLogEntry {
+	return NotificationLogEntry{
+		SentAt:    at,
+		Kind:      o.Kind,
+		PlanDate:  o.PlanDate,
+		SlotStart: o.SlotStart,
+		TaskID:    o.TaskID,
+		AlertID:   o.AlertID,
+		Message:   o.Message,
+		Urgency:   o.Urgency,
+		Sound:     o.Sound,
+	}
+}
@@ -1,5 +1,14 @@
 package models
 
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
 type SlotStatus string
 
 type FeedbackRating string
@@ -18,6 +27,8 @@ type Slot struct {
 	DeletedAt         *string    `json:"deleted_at,omitempty"`          // RFC3339 timestamp
 	LastNotifiedStart *string    `json:"last_notified_start,omitempty"` // RFC3339 timestamp
 	LastNotifiedEnd   *string    `json:"last_notified_end,omitempty"`   // RFC3339 timestamp
+	ActualStart       *string    `json:"actual_start,omitempty"`        // HH:MM when the slot was started, if tracked
+	ActualEnd         *string    `json:"actual_end,omitempty"`          // HH:MM when the slot was finished, if before or after End
 }
 
 type DayPlan struct {
@@ -26,6 +37,81 @@ type DayPlan struct {
 	AcceptedAt *string `json:"accepted_at,omitempty"` // RFC3339 timestamp when this revision was accepted; nil if never accepted
 	Slots      []Slot  `json:"slots"`
 	DeletedAt  *string `json:"deleted_at,omitempty"` // RFC3339 timestamp
+	Version    int     `json:"version,omitempty"`    // Bumped on every save of this revision; 0 skips the conflict check
+	Note       string  `json:"note,omitempty"`       // Free-text notes about the day; carried over to new revisions
+	// LockedUntil (HH:MM) commits the part of the day before it: slots that
+	// start earlier are kept in place when the day is regenerated or
+	// reflowed, and nothing new is placed before it. Empty means unlocked.
+	LockedUntil string `json:"locked_until,omitempty"`
+}
+
+// Locked reports whether slot starts before the plan's lock boundary, so it
+// must stay where it is
+func (p DayPlan) Locked(slot Slot, window DayWindow) bool {
+	if p.LockedUntil == "" {
+		return false
+	}
+	until, err := window.Minutes(p.LockedUntil)
+	if err != nil {
+		return false
+	}
+	start, err := window.Minutes(slot.Start)
+	return err == nil && start < until
+}
+
+// LockedSlots returns the plan's slots that start before its lock boundary
+func (p DayPlan) LockedSlots(window DayWindow) []Slot {
+	var locked []Slot
+	for _, slot := range p.Slots {
+		if slot.DeletedAt == nil && p.Locked(slot, window) {
+			locked = append(locked, slot)
+		}
+	}
+	return locked
+}
+
+// PlanPage is one page of plans from a date range, in date order
+type PlanPage struct {
+	Plans      []DayPlan `json:"plans"`
+	NextCursor string    `json:"next_cursor,omitempty"` // Fetches the next page; empty on the last page
+}
+
+// planCursorPrefix marks a plan page cursor, so a cursor from another kind of
+// page is rejected instead of misread
+const planCursorPrefix = "plan:"
+
+// NewPlanPage returns the first limit plans as a page. Storage backends fetch
+// one plan more than the limit, so a longer list means another page follows,
+// starting after the last plan kept. A limit of zero or less keeps them all.
+func NewPlanPage(plans []DayPlan, limit int) PlanPage {
+	if limit <= 0 || len(plans) <= limit {
+		return PlanPage{Plans: plans}
+	}
+	plans = plans[:limit]
+	return PlanPage{Plans: plans, NextCursor: EncodePlanCursor(plans[limit-1].Date)}
+}
+
+// EncodePlanCursor returns the cursor for the page that starts after the
+// plan for date. Cursors are opaque to callers.
+func EncodePlanCursor(date string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(planCursorPrefix + date))
+}
+
+// DecodePlanCursor returns the date a cursor continues after. An empty
+// cursor starts at the beginning of the range and decodes to "".
+func DecodePlanCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), planCursorPrefix) {
+		return "", fmt.Errorf("invalid plan cursor: %q", cursor)
+	}
+	date := strings.TrimPrefix(string(raw), planCursorPrefix)
+	if _, err := time.Parse(constants.DateFormat, date); err != nil {
+		return "", fmt.Errorf("invalid plan cursor: %q", cursor)
+	}
+	return date, nil
 }
 
 // TaskFeedbackEntry represents a single feedback instance for a task
@@ -38,3 +124,44 @@ type TaskFeedbackEntry struct {
 	ActualStart    string         `json:"actual_start"`    // HH:MM format
 	ActualEnd      string         `json:"actual_end"`      // HH:MM format
 }
+
+// DaySummary aggregates plan, feedback, and habit activity for a single day
+type DaySummary struct {
+	Date              string `json:"date"`                // YYYY-MM-DD format
+	HasPlan           bool   `json:"has_plan"`            // Whether a non-deleted plan exists for the day
+	Accepted          bool   `json:"accepted"`            // Whether the latest plan revision was accepted
+	TotalSlots        int    `json:"total_slots"`         // Number of non-deleted slots in the latest revision
+	SlotsWithFeedback int    `json:"slots_with_feedback"` // Number of those slots that have feedback recorded
+	HabitsCompleted   int    `json:"habits_completed"`    // Number of habit entries recorded for the day
+}
+
+// FeedbackPercent returns the percentage of slots with feedback, or 0 when the day has no slots
+func (d DaySummary) FeedbackPercent() int {
+	if d.TotalSlots == 0 {
+		return 0
+	}
+	return d.SlotsWithFeedback * 100 / d.TotalSlots
+}
+
+// TaskSlotEntry is one slot in a task's history
+type TaskSlotEntry struct {
+	Date     string         `json:"date"`             // YYYY-MM-DD format
+	Revision int            `json:"revision"`         // Plan revision the slot belongs to
+	Start    string         `json:"start"`            // HH:MM format
+	End      string         `json:"end"`              // HH:MM format
+	Status   SlotStatus     `json:"status"`           // Slot status
+	Rating   FeedbackRating `json:"rating,omitempty"` // Feedback rating, if any
+	Note     string         `json:"note,omitempty"`   // Feedback note, if any
+}
+
+// SlotRecord is a slot flattened with its plan date and task name, as used by exports
+type SlotRecord struct {
+	Date     string         `json:"date"`             // YYYY-MM-DD format
+	Start    string         `json:"start"`            // HH:MM format
+	End      string         `json:"end"`              // HH:MM format
+	TaskID   string         `json:"task_id"`          // Task identifier
+	TaskName string         `json:"task_name"`        // Falls back to the task ID if the task no longer exists
+	Status   SlotStatus     `json:"status"`           // Slot status
+	Rating   FeedbackRating `json:"rating,omitempty"` // Feedback rating, if any
+	Note     string         `json:"note,omitempty"`   // Feedback note, if any
+}
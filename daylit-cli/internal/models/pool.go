@@ -0,0 +1,30 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+// TaskPool groups interchangeable tasks, such as workout variants, of which
+// only one is scheduled per day
+type TaskPool struct {
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Strategy  constants.PoolStrategy `json:"strategy"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+func (p *TaskPool) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("pool name cannot be empty")
+	}
+	switch p.Strategy {
+	case constants.PoolStrategyRoundRobin, constants.PoolStrategyLeastRecent:
+	default:
+		return fmt.Errorf("invalid pool strategy %q (expected %s or %s)", p.Strategy,
+			constants.PoolStrategyRoundRobin, constants.PoolStrategyLeastRecent)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// colorPattern matches a #RRGGBB hex color
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Project groups tasks that contribute to a larger goal
+type Project struct {
+	ID                 string    `json:"id"`
+	Name               string    `json:"name"`
+	TargetHoursPerWeek float64   `json:"target_hours_per_week"`
+	Color              string    `json:"color,omitempty"` // #RRGGBB
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+func (p *Project) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("project name cannot be empty")
+	}
+	if p.TargetHoursPerWeek < 0 {
+		return fmt.Errorf("target hours per week cannot be negative")
+	}
+	if p.Color != "" && !colorPattern.MatchString(p.Color) {
+		return fmt.Errorf("invalid color %q (expected #RRGGBB)", p.Color)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PurgeKind selects which soft-deleted records a purge removes
+type PurgeKind string
+
+const (
+	PurgeTasks  PurgeKind = "task"  // Tasks no slot or template still refers to
+	PurgePlans  PurgeKind = "plan"  // Plan revisions with their slots, and deleted slots of live plans
+	PurgeHabits PurgeKind = "habit" // Habits with their entries, and deleted entries of live habits
+)
+
+// PurgeKinds lists every kind, in the order a purge processes them: plans
+// first, so the slots they free no longer keep tasks alive
+var PurgeKinds = []PurgeKind{PurgePlans, PurgeHabits, PurgeTasks}
+
+// ParsePurgeKind parses a kind name as given on the command line
+func ParsePurgeKind(raw string) (PurgeKind, error) {
+	kind := PurgeKind(strings.TrimSpace(strings.ToLower(raw)))
+	for _, k := range PurgeKinds {
+		if k == kind {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("invalid type %q (expected task, plan or habit)", raw)
+}
+
+// PurgeSummary counts the rows a purge removed, or would remove
+type PurgeSummary struct {
+	Tasks        int `json:"tasks"`
+	Plans        int `json:"plans"` // Plan revisions
+	Slots        int `json:"slots"`
+	Habits       int `json:"habits"`
+	HabitEntries int `json:"habit_entries"`
+	// KeptTasks counts tasks past the retention window that stay because a
+	// slot or day template still refers to them
+	KeptTasks int `json:"kept_tasks"`
+}
+
+// Total returns the number of rows removed
+func (s PurgeSummary) Total() int {
+	return s.Tasks + s.Plans + s.Slots + s.Habits + s.HabitEntries
+}
+
+// DeletedBefore reports whether a soft-delete timestamp (RFC3339) falls
+// before the cutoff. Timestamps that don't parse are never purged.
+func DeletedBefore(deletedAt string, cutoff time.Time) bool {
+	t, err := time.Parse(time.RFC3339, deletedAt)
+	if err != nil {
+		return false
+	}
+	return t.Before(cutoff)
+}
+
+// HasPurgeKind reports whether kinds includes kind
+func HasPurgeKind(kinds []PurgeKind, kind PurgeKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
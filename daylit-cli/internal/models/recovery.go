@@ -0,0 +1,34 @@
+package models
+
+import "github.com/julianstephens/daylit/daylit-cli/internal/constants"
+
+// Recovery is the rest a demanding task needs before the next demanding task
+// starts. Lighter tasks may fill the gap; only demanding work is kept out.
+type Recovery struct {
+	// GapMinutes between the end of a demanding task and the start of the
+	// next one; zero turns the rule off
+	GapMinutes int
+	// LongerThan makes tasks longer than this many minutes demanding, as
+	// well as high-energy ones; zero counts high-energy tasks only
+	LongerThan int
+}
+
+// Enabled reports whether the rule keeps demanding tasks apart
+func (r Recovery) Enabled() bool {
+	return r.GapMinutes > 0
+}
+
+// Demanding reports whether a block of task lasting minutes needs recovery
+// after it
+func (r Recovery) Demanding(task Task, minutes int) bool {
+	if task.EnergyBand == constants.EnergyHigh {
+		return true
+	}
+	return r.LongerThan > 0 && minutes > r.LongerThan
+}
+
+// TooClose reports whether two demanding blocks, given as minute ranges,
+// leave less than the recovery gap between them
+func (r Recovery) TooClose(start1, end1, start2, end2 int) bool {
+	return start1 < end2+r.GapMinutes && start2 < end1+r.GapMinutes
+}
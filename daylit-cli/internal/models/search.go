@@ -0,0 +1,45 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+// SearchResult is a single match from the full-text search index
+type SearchResult struct {
+	Kind    constants.SearchKind `json:"kind"`
+	ID      string               `json:"id"`             // Task, habit, or OT entry ID; slot ID for notes
+	Date    string               `json:"date,omitempty"` // YYYY-MM-DD for OT entries and notes
+	Title   string               `json:"title"`          // Matched name or title; the slot's task name for notes
+	Snippet string               `json:"snippet"`        // Excerpt of the matched note, if any
+}
+
+// SearchTerms splits a free-text query into the words matched by the search
+// index. Punctuation is dropped so user input can never be interpreted as
+// index query syntax.
+func SearchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// MatchesSearchTerms reports whether every term starts a word of text, the
+// way the search indexes match terms as prefixes
+func MatchesSearchTerms(text string, terms []string) bool {
+	words := SearchTerms(text)
+	for _, term := range terms {
+		found := false
+		for _, word := range words {
+			if strings.HasPrefix(word, term) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
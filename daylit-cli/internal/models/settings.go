@@ -1,15 +1,60 @@
 package models
 
+import (
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
 // Settings represents application-wide settings
 type Settings struct {
-	DayStart                   string `json:"day_start"`                     // the time the day starts, e.g. "08:00"
-	DayEnd                     string `json:"day_end"`                       // the time the day ends, e.g. "18:00"
-	DefaultBlockMin            int    `json:"default_block_min"`             // the default block duration in minutes
-	NotificationsEnabled       bool   `json:"notifications_enabled"`         // whether notifications are enabled
-	NotifyBlockStart           bool   `json:"notify_block_start"`            // whether to notify at the start of a block
-	NotifyBlockEnd             bool   `json:"notify_block_end"`              // whether to notify at the end of a block
-	BlockStartOffsetMin        int    `json:"block_start_offset_min"`        // the offset in minutes for block start notifications
-	BlockEndOffsetMin          int    `json:"block_end_offset_min"`          // the offset in minutes for block end notifications
-	NotificationGracePeriodMin int    `json:"notification_grace_period_min"` // grace period for late notifications in minutes
-	Timezone                   string `json:"timezone"`                      // IANA timezone name (e.g. "America/New_York", "Europe/London", or "Local" for system timezone)
+	DayStart                    string            `json:"day_start"`                      // the time the day starts, e.g. "08:00"
+	DayEnd                      string            `json:"day_end"`                        // the time the day ends, e.g. "18:00"
+	DefaultBlockMin             int               `json:"default_block_min"`              // the default block duration in minutes
+	NotificationsEnabled        bool              `json:"notifications_enabled"`          // whether notifications are enabled
+	NotifyBlockStart            bool              `json:"notify_block_start"`             // whether to notify at the start of a block
+	NotifyBlockEnd              bool              `json:"notify_block_end"`               // whether to notify at the end of a block
+	BlockStartOffsetMin         int               `json:"block_start_offset_min"`         // the offset in minutes for block start notifications
+	BlockEndOffsetMin           int               `json:"block_end_offset_min"`           // the offset in minutes for block end notifications
+	NotificationGracePeriodMin  int               `json:"notification_grace_period_min"`  // grace period for late notifications in minutes
+	NotificationDigestWindowMin int               `json:"notification_digest_window_min"` // notifications due within this many minutes of each other are sent as one digest; 0 sends each on its own
+	Timezone                    string            `json:"timezone"`                       // IANA timezone name (e.g. "America/New_York", "Europe/London", or "Local" for system timezone)
+	Theme                       string            `json:"theme"`                          // TUI color theme (dark, light, high-contrast, or no-color)
+	Locale                      string            `json:"locale,omitempty"`               // language of messages and dates (en or es); empty follows DAYLIT_LANG or the system locale
+	WeekStart                   string            `json:"week_start"`                     // first day of the week for week views, reports and week numbers (sunday or monday)
+	Keys                        map[string]string `json:"keys,omitempty"`                 // TUI key binding overrides by action name, as comma-separated keys
+	MarkdownExportDir           string            `json:"markdown_export_dir,omitempty"`  // directory that `export md` writes daily notes to; empty writes to stdout
+	ActiveContext               string            `json:"active_context,omitempty"`       // context used for plan generation (e.g. "office"); empty schedules tasks from every context
+	MorningPlan                 string            `json:"morning_plan"`                   // what to do at day start when today has no accepted plan (off, prompt, or hands-free)
+	CompressOnEarlyFinish       bool              `json:"compress_on_early_finish"`       // whether 'done' moves the following slots up when a slot finishes early
+	WeeklySummary               string            `json:"weekly_summary"`                 // how the weekly summary is delivered (off, notify, report, or both)
+	WeeklySummaryAt             string            `json:"weekly_summary_at"`              // when the weekly summary goes out, as a weekday and time, e.g. "sun 18:00"
+	MetricsEnabled              bool              `json:"metrics_enabled"`                // whether the notify daemon records daily scheduling metrics (opt-in)
+	AdaptivePlacement           bool              `json:"adaptive_placement"`             // whether plans place tasks at the time of day they get done best (opt-in)
+	PriorityAgingDays           int               `json:"priority_aging_days"`            // days a flexible task can be left out of the plan in a row before its priority goes up a level (0 = off)
+	MinFreeMinutes              int               `json:"min_free_minutes"`               // unscheduled minutes a plan must leave in the day, leisure tasks included (0 = no minimum)
+	RecoveryMinutes             int               `json:"recovery_minutes"`               // minutes a demanding task must end before the next demanding task starts (0 = off)
+	DemandingMinutes            int               `json:"demanding_minutes"`              // tasks longer than this many minutes count as demanding, as well as high-energy ones (0 = only high-energy)
+	TaskDeleteSlots             string            `json:"task_delete_slots"`              // what deleting a task does to its upcoming slots in accepted plans (ask, remove, or keep)
+	HabitDeleteEntries          string            `json:"habit_delete_entries"`           // what deleting a habit does to its entries (keep or delete)
+	ArchivedHabitStats          string            `json:"archived_habit_stats"`           // whether archived habits count in habit stats (hide or keep)
+	MorningPlanNotifiedOn       string            `json:"-"`                              // date (YYYY-MM-DD) the morning plan check last ran in the notify daemon
+	MorningPlanDismissedOn      string            `json:"-"`                              // date (YYYY-MM-DD) the TUI morning plan prompt was last declined
+	WeeklySummarySentOn         string            `json:"-"`                              // date (YYYY-MM-DD) the weekly summary last went out
+	MetricsRecordedOn           string            `json:"-"`                              // date (YYYY-MM-DD) the nightly metrics run last happened
+	Version                     int               `json:"-"`                              // bumped on every save; 0 skips the conflict check
+}
+
+// FirstWeekday returns the day weeks start on: Monday when WeekStart is
+// "monday", Sunday otherwise
+func (s Settings) FirstWeekday() time.Weekday {
+	if s.WeekStart == constants.WeekStartMonday {
+		return time.Monday
+	}
+	return time.Sunday
+}
+
+// Recovery returns the rule for rest between demanding tasks
+func (s Settings) Recovery() Recovery {
+	return Recovery{GapMinutes: s.RecoveryMinutes, LongerThan: s.DemandingMinutes}
 }
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
 )
@@ -38,27 +39,122 @@ func MapToSettings(data map[string]string) (Settings, error) {
 			if _, err := fmt.Sscanf(value, "%d", &settings.NotificationGracePeriodMin); err != nil {
 				return Settings{}, fmt.Errorf("parsing notification_grace_period_min: %w", err)
 			}
+		case constants.SettingNotificationDigestWindowMin:
+			if _, err := fmt.Sscanf(value, "%d", &settings.NotificationDigestWindowMin); err != nil {
+				return Settings{}, fmt.Errorf("parsing notification_digest_window_min: %w", err)
+			}
 		case constants.SettingTimezone:
 			settings.Timezone = value
+		case constants.SettingTheme:
+			settings.Theme = value
+		case constants.SettingLocale:
+			settings.Locale = value
+		case constants.SettingWeekStart:
+			settings.WeekStart = value
+		case constants.SettingMarkdownExportDir:
+			settings.MarkdownExportDir = value
+		case constants.SettingActiveContext:
+			settings.ActiveContext = value
+		case constants.SettingMorningPlan:
+			settings.MorningPlan = value
+		case constants.SettingCompressOnEarlyFinish:
+			settings.CompressOnEarlyFinish = value == "true"
+		case constants.SettingWeeklySummary:
+			settings.WeeklySummary = value
+		case constants.SettingWeeklySummaryAt:
+			settings.WeeklySummaryAt = value
+		case constants.SettingWeeklySummarySentOn:
+			settings.WeeklySummarySentOn = value
+		case constants.SettingMetricsEnabled:
+			settings.MetricsEnabled = value == "true"
+		case constants.SettingAdaptivePlacement:
+			settings.AdaptivePlacement = value == "true"
+		case constants.SettingPriorityAgingDays:
+			if _, err := fmt.Sscanf(value, "%d", &settings.PriorityAgingDays); err != nil {
+				return Settings{}, fmt.Errorf("parsing priority_aging_days: %w", err)
+			}
+		case constants.SettingMinFreeMinutes:
+			if _, err := fmt.Sscanf(value, "%d", &settings.MinFreeMinutes); err != nil {
+				return Settings{}, fmt.Errorf("parsing min_free_minutes: %w", err)
+			}
+		case constants.SettingRecoveryMinutes:
+			if _, err := fmt.Sscanf(value, "%d", &settings.RecoveryMinutes); err != nil {
+				return Settings{}, fmt.Errorf("parsing recovery_minutes: %w", err)
+			}
+		case constants.SettingDemandingMinutes:
+			if _, err := fmt.Sscanf(value, "%d", &settings.DemandingMinutes); err != nil {
+				return Settings{}, fmt.Errorf("parsing demanding_minutes: %w", err)
+			}
+		case constants.SettingTaskDeleteSlots:
+			settings.TaskDeleteSlots = value
+		case constants.SettingHabitDeleteEntries:
+			settings.HabitDeleteEntries = value
+		case constants.SettingArchivedHabitStats:
+			settings.ArchivedHabitStats = value
+		case constants.SettingMetricsRecordedOn:
+			settings.MetricsRecordedOn = value
+		case constants.SettingMorningPlanNotifiedOn:
+			settings.MorningPlanNotifiedOn = value
+		case constants.SettingMorningPlanDismissedOn:
+			settings.MorningPlanDismissedOn = value
+		case constants.SettingVersion:
+			if _, err := fmt.Sscanf(value, "%d", &settings.Version); err != nil {
+				return Settings{}, fmt.Errorf("parsing version: %w", err)
+			}
+		default:
+			if action, ok := strings.CutPrefix(key, constants.SettingKeysPrefix); ok {
+				if settings.Keys == nil {
+					settings.Keys = make(map[string]string)
+				}
+				settings.Keys[action] = value
+			}
 		}
 	}
 	return settings, nil
 }
 
-// SettingsToMap converts a Settings struct to a map of key-value pairs.
+// SettingsToMap converts a Settings struct to a map of key-value pairs. The
+// version is left out; stores write it when they check for conflicts.
 func SettingsToMap(settings Settings) map[string]string {
-	return map[string]string{
-		constants.SettingDayStart:                   settings.DayStart,
-		constants.SettingDayEnd:                     settings.DayEnd,
-		constants.SettingDefaultBlockMin:            fmt.Sprintf("%d", settings.DefaultBlockMin),
-		constants.SettingNotificationsEnabled:       fmt.Sprintf("%v", settings.NotificationsEnabled),
-		constants.SettingNotifyBlockStart:           fmt.Sprintf("%v", settings.NotifyBlockStart),
-		constants.SettingNotifyBlockEnd:             fmt.Sprintf("%v", settings.NotifyBlockEnd),
-		constants.SettingBlockStartOffsetMin:        fmt.Sprintf("%d", settings.BlockStartOffsetMin),
-		constants.SettingBlockEndOffsetMin:          fmt.Sprintf("%d", settings.BlockEndOffsetMin),
-		constants.SettingNotificationGracePeriodMin: fmt.Sprintf("%d", settings.NotificationGracePeriodMin),
-		constants.SettingTimezone:                   settings.Timezone,
+	data := map[string]string{
+		constants.SettingDayStart:                    settings.DayStart,
+		constants.SettingDayEnd:                      settings.DayEnd,
+		constants.SettingDefaultBlockMin:             fmt.Sprintf("%d", settings.DefaultBlockMin),
+		constants.SettingNotificationsEnabled:        fmt.Sprintf("%v", settings.NotificationsEnabled),
+		constants.SettingNotifyBlockStart:            fmt.Sprintf("%v", settings.NotifyBlockStart),
+		constants.SettingNotifyBlockEnd:              fmt.Sprintf("%v", settings.NotifyBlockEnd),
+		constants.SettingBlockStartOffsetMin:         fmt.Sprintf("%d", settings.BlockStartOffsetMin),
+		constants.SettingBlockEndOffsetMin:           fmt.Sprintf("%d", settings.BlockEndOffsetMin),
+		constants.SettingNotificationGracePeriodMin:  fmt.Sprintf("%d", settings.NotificationGracePeriodMin),
+		constants.SettingNotificationDigestWindowMin: fmt.Sprintf("%d", settings.NotificationDigestWindowMin),
+		constants.SettingTimezone:                    settings.Timezone,
+		constants.SettingTheme:                       settings.Theme,
+		constants.SettingLocale:                      settings.Locale,
+		constants.SettingWeekStart:                   settings.WeekStart,
+		constants.SettingMarkdownExportDir:           settings.MarkdownExportDir,
+		constants.SettingActiveContext:               settings.ActiveContext,
+		constants.SettingMorningPlan:                 settings.MorningPlan,
+		constants.SettingCompressOnEarlyFinish:       fmt.Sprintf("%v", settings.CompressOnEarlyFinish),
+		constants.SettingMorningPlanNotifiedOn:       settings.MorningPlanNotifiedOn,
+		constants.SettingMorningPlanDismissedOn:      settings.MorningPlanDismissedOn,
+		constants.SettingWeeklySummary:               settings.WeeklySummary,
+		constants.SettingWeeklySummaryAt:             settings.WeeklySummaryAt,
+		constants.SettingWeeklySummarySentOn:         settings.WeeklySummarySentOn,
+		constants.SettingMetricsEnabled:              fmt.Sprintf("%v", settings.MetricsEnabled),
+		constants.SettingMetricsRecordedOn:           settings.MetricsRecordedOn,
+		constants.SettingAdaptivePlacement:           fmt.Sprintf("%v", settings.AdaptivePlacement),
+		constants.SettingPriorityAgingDays:           fmt.Sprintf("%d", settings.PriorityAgingDays),
+		constants.SettingMinFreeMinutes:              fmt.Sprintf("%d", settings.MinFreeMinutes),
+		constants.SettingRecoveryMinutes:             fmt.Sprintf("%d", settings.RecoveryMinutes),
+		constants.SettingDemandingMinutes:            fmt.Sprintf("%d", settings.DemandingMinutes),
+		constants.SettingTaskDeleteSlots:             settings.TaskDeleteSlots,
+		constants.SettingHabitDeleteEntries:          settings.HabitDeleteEntries,
+		constants.SettingArchivedHabitStats:          settings.ArchivedHabitStats,
+	}
+	for action, keys := range settings.Keys {
+		data[constants.SettingKeysPrefix+action] = keys
 	}
+	return data
 }
 
 // ApplyDefaultSettings applies default values to missing settings.
@@ -85,4 +181,28 @@ func ApplyDefaultSettings(settings *Settings) {
 	if settings.Timezone == "" {
 		settings.Timezone = constants.DefaultTimezone
 	}
+	if settings.Theme == "" {
+		settings.Theme = constants.DefaultTheme
+	}
+	if settings.WeekStart == "" {
+		settings.WeekStart = constants.DefaultWeekStart
+	}
+	if settings.MorningPlan == "" {
+		settings.MorningPlan = constants.DefaultMorningPlan
+	}
+	if settings.WeeklySummary == "" {
+		settings.WeeklySummary = constants.DefaultWeeklySummary
+	}
+	if settings.WeeklySummaryAt == "" {
+		settings.WeeklySummaryAt = constants.DefaultWeeklySummaryAt
+	}
+	if settings.TaskDeleteSlots == "" {
+		settings.TaskDeleteSlots = constants.DefaultTaskDeleteSlots
+	}
+	if settings.HabitDeleteEntries == "" {
+		settings.HabitDeleteEntries = constants.DefaultHabitDeleteEntries
+	}
+	if settings.ArchivedHabitStats == "" {
+		settings.ArchivedHabitStats = constants.DefaultArchivedHabitStats
+	}
 }
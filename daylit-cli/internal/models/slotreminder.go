@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+// SlotReminder is an extra notification for one slot of a day's plan, sent
+// OffsetMin minutes before the slot starts. It adds to the global block
+// start and end notifications.
+type SlotReminder struct {
+	ID        string     `json:"id"`
+	PlanDate  string     `json:"plan_date"`  // YYYY-MM-DD format
+	SlotStart string     `json:"slot_start"` // HH:MM start of the slot when the reminder was added
+	TaskID    string     `json:"task_id"`
+	OffsetMin int        `json:"offset_min"` // Minutes before the slot starts; 0 reminds at the start
+	Message   string     `json:"message,omitempty"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (r *SlotReminder) Validate() error {
+	if _, err := time.Parse(constants.DateFormat, r.PlanDate); err != nil {
+		return fmt.Errorf("invalid plan date %q (expected YYYY-MM-DD)", r.PlanDate)
+	}
+	if _, err := time.Parse("15:04", r.SlotStart); err != nil {
+		return fmt.Errorf("invalid slot start %q (expected HH:MM)", r.SlotStart)
+	}
+	if r.TaskID == "" {
+		return fmt.Errorf("reminder needs a task")
+	}
+	if r.OffsetMin < 0 || r.OffsetMin >= MinutesPerDay {
+		return fmt.Errorf("reminder offset must be between 0 and %d minutes", MinutesPerDay-1)
+	}
+	return nil
+}
+
+// FindSlot returns the index of the reminder's slot in slots: the slot of its
+// task that starts at SlotStart or, if that slot has moved, the task's only
+// slot. It returns -1 when there is no such slot.
+func (r SlotReminder) FindSlot(slots []Slot) int {
+	found := -1
+	count := 0
+	for i, slot := range slots {
+		if slot.TaskID != r.TaskID {
+			continue
+		}
+		if slot.Start == r.SlotStart {
+			return i
+		}
+		found = i
+		count++
+	}
+	if count == 1 {
+		return found
+	}
+	return -1
+}
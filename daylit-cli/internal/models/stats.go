@@ -0,0 +1,53 @@
+package models
+
+// TaskStats aggregates the slots of a single task over a date range, counting
+// only the latest non-deleted revision of each day's plan
+type TaskStats struct {
+	TaskID            string `json:"task_id"`
+	TaskName          string `json:"task_name"`           // Falls back to the task ID if the task no longer exists
+	Priority          int    `json:"priority"`            // 0 if the task no longer exists
+	Context           string `json:"context"`             // Empty if the task has none or no longer exists
+	PlannedSlots      int    `json:"planned_slots"`       // Number of slots scheduled for the task
+	PlannedMinutes    int    `json:"planned_minutes"`     // Total scheduled minutes
+	DoneSlots         int    `json:"done_slots"`          // Number of those slots marked done
+	DoneMinutes       int    `json:"done_minutes"`        // Scheduled minutes of the slots marked done
+	AcceptedSlots     int    `json:"accepted_slots"`      // Number of slots in accepted plans
+	AcceptedDoneSlots int    `json:"accepted_done_slots"` // Number of slots in accepted plans marked done
+}
+
+// Adherence returns the percentage of slots in accepted plans that were done,
+// or 0 when no slots were accepted
+func (s TaskStats) Adherence() int {
+	if s.AcceptedSlots == 0 {
+		return 0
+	}
+	return s.AcceptedDoneSlots * 100 / s.AcceptedSlots
+}
+
+// Add accumulates the slot counts and minutes of other into s
+func (s *TaskStats) Add(other TaskStats) {
+	s.PlannedSlots += other.PlannedSlots
+	s.PlannedMinutes += other.PlannedMinutes
+	s.DoneSlots += other.DoneSlots
+	s.DoneMinutes += other.DoneMinutes
+	s.AcceptedSlots += other.AcceptedSlots
+	s.AcceptedDoneSlots += other.AcceptedDoneSlots
+}
+
+// HabitCategoryStats aggregates the active habits of a category over a date
+// range
+type HabitCategoryStats struct {
+	Category string `json:"category"`  // Empty for habits without a category
+	Habits   int    `json:"habits"`    // Number of active habits in the category
+	DoneDays int    `json:"done_days"` // Habit entries recorded in the range
+	DueDays  int    `json:"due_days"`  // Days in the range each habit existed, summed
+}
+
+// Completion returns the percentage of due habit days that were done, or 0
+// when none were due
+func (s HabitCategoryStats) Completion() int {
+	if s.DueDays == 0 {
+		return 0
+	}
+	return min(100, s.DoneDays*100/s.DueDays)
+}
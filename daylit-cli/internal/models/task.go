@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
@@ -15,6 +16,15 @@ type Recurrence struct {
 	WeekOccurrence   int                      `json:"week_occurrence,omitempty"`      // Week occurrence (-1=last, 1=first, 2=second, etc.) for monthly_day
 	Month            int                      `json:"month,omitempty"`                // Month (1-12) for yearly
 	DayOfWeekInMonth time.Weekday             `json:"day_of_week_in_month,omitempty"` // Weekday for monthly_day (e.g., Friday for "last Friday")
+	Start            string                   `json:"start,omitempty"`                // YYYY-MM-DD the rule starts on; Count is counted from it
+	Until            string                   `json:"until,omitempty"`                // YYYY-MM-DD of the last day the rule applies
+	Count            int                      `json:"count,omitempty"`                // Occurrences after which the rule ends; 0 for no limit
+}
+
+// Bounded reports whether the rule ends, after a last date or a number of
+// occurrences
+func (r Recurrence) Bounded() bool {
+	return r.Until != "" || r.Count > 0
 }
 
 type Task struct {
@@ -33,7 +43,20 @@ type Task struct {
 	LastDone             string               `json:"last_done,omitempty"` // YYYY-MM-DD format
 	SuccessStreak        int                  `json:"success_streak"`
 	AvgActualDurationMin float64              `json:"avg_actual_duration_min"`
-	DeletedAt            *string              `json:"deleted_at,omitempty"` // RFC3339 timestamp
+	ProjectID            string               `json:"project_id,omitempty"`
+	PoolID               string               `json:"pool_id,omitempty"`      // Pool the task takes turns in, at most one member a day
+	NiceToHave           bool                 `json:"nice_to_have,omitempty"` // Scheduled only in time left over by the other tasks
+	Leisure              bool                 `json:"leisure,omitempty"`      // Its blocks count as free time, e.g. reading or a walk
+	PrepMin              int                  `json:"prep_min,omitempty"`     // Appointments only: minutes to get ready before leaving
+	TravelMin            int                  `json:"travel_min,omitempty"`   // Appointments only: minutes to get there, and again to get back
+	Context              string               `json:"context,omitempty"`      // Where the task can be done, e.g. "home" or "office"
+	NotifyStartDisabled  bool                 `json:"notify_start_disabled,omitempty"`
+	NotifyOffsetMin      *int                 `json:"notify_offset_min,omitempty"` // Overrides block_start_offset_min when set
+	NotifyMessage        string               `json:"notify_message,omitempty"`    // Replaces the default block start notification text
+	NotifyUrgency        string               `json:"notify_urgency,omitempty"`    // Urgency of the block start notification (low, normal or critical)
+	NotifySound          string               `json:"notify_sound,omitempty"`      // Sound for the block start notification; empty uses the default
+	DeletedAt            *string              `json:"deleted_at,omitempty"`        // RFC3339 timestamp
+	Version              int                  `json:"version,omitempty"`           // Bumped on every save; 0 skips the conflict check
 }
 
 func (t *Task) Validate() error {
@@ -46,6 +69,18 @@ func (t *Task) Validate() error {
 	if t.Priority < 1 || t.Priority > 5 {
 		return fmt.Errorf("priority must be between 1 and 5")
 	}
+	if t.NotifyOffsetMin != nil && *t.NotifyOffsetMin < 0 {
+		return fmt.Errorf("notification offset cannot be negative")
+	}
+	if err := ValidateUrgency(t.NotifyUrgency); err != nil {
+		return err
+	}
+	if t.PrepMin < 0 || t.TravelMin < 0 {
+		return fmt.Errorf("prep and travel minutes cannot be negative")
+	}
+	if (t.PrepMin > 0 || t.TravelMin > 0) && t.Kind != constants.TaskKindAppointment {
+		return fmt.Errorf("prep and travel minutes can only be set on appointments")
+	}
 
 	// Recurrence validation
 	if t.Recurrence.Type == constants.RecurrenceNDays && t.Recurrence.IntervalDays < 1 {
@@ -81,6 +116,74 @@ func (t *Task) Validate() error {
 		// Note: We allow potentially invalid dates like Feb 31.
 		// The scheduler will skip years where this date doesn't exist.
 	}
+	if err := t.Recurrence.ValidateBounds(); err != nil {
+		return err
+	}
 
 	return nil
 }
+
+// ValidateBounds checks the dates and count that end the rule
+func (r Recurrence) ValidateBounds() error {
+	for _, date := range []string{r.Start, r.Until} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(constants.DateFormat, date); err != nil {
+			return fmt.Errorf("invalid recurrence date %q (expected YYYY-MM-DD)", date)
+		}
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("recurrence count cannot be negative")
+	}
+	if r.Count > 0 {
+		switch r.Type {
+		case constants.RecurrenceNDays, constants.RecurrenceAdHoc:
+			return fmt.Errorf("a recurrence count can't be used with %s recurrence", r.Type)
+		}
+		if r.Start == "" {
+			return fmt.Errorf("a recurrence count needs a start date to count from")
+		}
+	}
+	if r.Start != "" && r.Until != "" && r.Until < r.Start {
+		return fmt.Errorf("recurrence until date %s is before its start %s", r.Until, r.Start)
+	}
+	return nil
+}
+
+// Padding is the time an appointment keeps free around its block: prep and
+// travel before it, and travel back after it
+func (t *Task) Padding() (before, after int) {
+	if t.Kind != constants.TaskKindAppointment {
+		return 0, 0
+	}
+	return t.PrepMin + t.TravelMin, t.TravelMin
+}
+
+// NormalizeContext trims and lowercases a context name so "Office" and
+// "office " refer to the same context
+func NormalizeContext(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// InContext reports whether the task may be scheduled while the given context
+// is active. Tasks without a context fit every context, and an empty active
+// context allows every task.
+func (t *Task) InContext(active string) bool {
+	return active == "" || t.Context == "" || t.Context == NormalizeContext(active)
+}
+
+// TasksInContext returns the tasks that may be scheduled while the given
+// context is active
+func TasksInContext(tasks []Task, active string) []Task {
+	if active == "" {
+		return tasks
+	}
+	var filtered []Task
+	for _, t := range tasks {
+		if t.InContext(active) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+func TestTasksInContext(t *testing.T) {
+	tasks := []Task{
+		{ID: "plants", Context: "home"},
+		{ID: "report", Context: "office"},
+		{ID: "read"},
+	}
+
+	tests := []struct {
+		name   string
+		active string
+		want   []string
+	}{
+		{name: "no active context", active: "", want: []string{"plants", "report", "read"}},
+		{name: "home", active: "home", want: []string{"plants", "read"}},
+		{name: "office ignores case", active: " Office", want: []string{"report", "read"}},
+		{name: "unused context", active: "errands", want: []string{"read"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TasksInContext(tasks, tt.active)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d tasks, want %d", len(got), len(tt.want))
+			}
+			for i, id := range tt.want {
+				if got[i].ID != id {
+					t.Errorf("task %d = %s, want %s", i, got[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrence_ValidateBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		rec     Recurrence
+		wantErr bool
+	}{
+		{name: "unbounded", rec: Recurrence{Type: constants.RecurrenceDaily}},
+		{name: "until and count", rec: Recurrence{Type: constants.RecurrenceWeekdays, Start: "2026-10-17", Count: 5, Until: "2026-12-31"}},
+		{name: "bad until", rec: Recurrence{Type: constants.RecurrenceDaily, Until: "12/31/2026"}, wantErr: true},
+		{name: "count without start", rec: Recurrence{Type: constants.RecurrenceDaily, Count: 5}, wantErr: true},
+		{name: "count for n_days", rec: Recurrence{Type: constants.RecurrenceNDays, IntervalDays: 2, Start: "2026-10-17", Count: 5}, wantErr: true},
+		{name: "until before start", rec: Recurrence{Type: constants.RecurrenceDaily, Start: "2026-10-17", Count: 5, Until: "2026-10-01"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rec.ValidateBounds(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBounds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTask_Padding(t *testing.T) {
+	base := Task{Name: "Dentist", Kind: constants.TaskKindAppointment, DurationMin: 60, Priority: 3, FixedStart: "10:00", FixedEnd: "11:00"}
+
+	tests := []struct {
+		name                  string
+		kind                  constants.TaskKind
+		prep, travel          int
+		wantErr               bool
+		wantBefore, wantAfter int
+	}{
+		{name: "none", kind: constants.TaskKindAppointment},
+		{name: "prep and travel", kind: constants.TaskKindAppointment, prep: 15, travel: 30, wantBefore: 45, wantAfter: 30},
+		{name: "negative", kind: constants.TaskKindAppointment, prep: -5, wantErr: true},
+		{name: "flexible task", kind: constants.TaskKindFlexible, travel: 10, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := base
+			task.Kind, task.PrepMin, task.TravelMin = tt.kind, tt.prep, tt.travel
+			if err := task.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if before, after := task.Padding(); before != tt.wantBefore || after != tt.wantAfter {
+				t.Errorf("Padding() = %d, %d, want %d, %d", before, after, tt.wantBefore, tt.wantAfter)
+			}
+		})
+	}
+}
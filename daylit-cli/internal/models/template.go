@@ -0,0 +1,57 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TemplateSlot is a task placed at a fixed time in a day template
+type TemplateSlot struct {
+	Start  string `json:"start"` // HH:MM format
+	End    string `json:"end"`   // HH:MM format
+	TaskID string `json:"task_id"`
+}
+
+// DayTemplate is a reusable skeleton of slots, such as a "deep work day".
+// Plans generated from a template keep its slots and fill the gaps with the
+// normal scheduler.
+type DayTemplate struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Slots     []TemplateSlot `json:"slots"` // Ordered by start time
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+func (t *DayTemplate) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("template name cannot be empty")
+	}
+
+	slots := make([]TemplateSlot, len(t.Slots))
+	copy(slots, t.Slots)
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Start < slots[j].Start })
+
+	var prevEnd time.Time
+	for i, slot := range slots {
+		if slot.TaskID == "" {
+			return fmt.Errorf("slot %s-%s has no task", slot.Start, slot.End)
+		}
+		start, err := time.Parse("15:04", slot.Start)
+		if err != nil {
+			return fmt.Errorf("invalid slot start (expected HH:MM): %w", err)
+		}
+		end, err := time.Parse("15:04", slot.End)
+		if err != nil {
+			return fmt.Errorf("invalid slot end (expected HH:MM): %w", err)
+		}
+		if !start.Before(end) {
+			return fmt.Errorf("slot %s-%s must start before it ends", slot.Start, slot.End)
+		}
+		if i > 0 && start.Before(prevEnd) {
+			return fmt.Errorf("slot %s-%s overlaps the previous slot", slot.Start, slot.End)
+		}
+		prevEnd = end
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TrashKind names the kind of a soft-deleted item
+type TrashKind string
+
+const (
+	TrashTask       TrashKind = "task"
+	TrashPlan       TrashKind = "plan"  // Identified by date; covers every deleted revision of the day
+	TrashHabit      TrashKind = "habit" // Restoring or purging a habit covers its entries
+	TrashHabitEntry TrashKind = "entry"
+	TrashOTEntry    TrashKind = "ot" // Identified by day
+)
+
+// TrashKinds lists every kind, in the order they are described to users
+var TrashKinds = []TrashKind{TrashTask, TrashPlan, TrashHabit, TrashHabitEntry, TrashOTEntry}
+
+// ParseTrashKind parses a kind name as given on the command line
+func ParseTrashKind(raw string) (TrashKind, error) {
+	kind := TrashKind(strings.TrimSpace(strings.ToLower(raw)))
+	for _, k := range TrashKinds {
+		if k == kind {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("invalid kind %q (expected task, plan, habit, entry or ot)", raw)
+}
+
+// TrashItem is a soft-deleted item that can still be restored or purged
+type TrashItem struct {
+	Kind TrashKind `json:"kind"`
+	// ID is the task, habit or habit entry id, or the date of a plan or OT entry
+	ID        string    `json:"id"`
+	Label     string    `json:"label"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// User is one person's share of a database that several people use. Each
+// user's tasks, plans, habits and settings are kept apart from the others'.
+type User struct {
+	Name     string `json:"name"` // Empty for the default user
+	Tasks    int    `json:"tasks"`
+	LastPlan string `json:"last_plan,omitempty"` // YYYY-MM-DD of the latest plan
+	Current  bool   `json:"current"`             // The user this process runs as
+}
+
+var userNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// ValidateUserName checks that name can name a user: a lowercase letter
+// followed by up to 31 lowercase letters, digits or underscores. "default"
+// is kept for the default user.
+func ValidateUserName(name string) error {
+	if !userNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid user name %q: use a lowercase letter followed by up to 31 lowercase letters, digits or underscores", name)
+	}
+	if name == DefaultUserName {
+		return fmt.Errorf("invalid user name %q: it refers to the default user", name)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+// Vacation is an inclusive range of days away. Recurring tasks aren't
+// scheduled, habits aren't expected and notifications are muted on those days.
+type Vacation struct {
+	ID        string    `json:"id"`
+	Start     string    `json:"start"` // YYYY-MM-DD format
+	End       string    `json:"end"`   // YYYY-MM-DD format, inclusive
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (v *Vacation) Validate() error {
+	start, err := time.Parse(constants.DateFormat, v.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start date %q (expected YYYY-MM-DD)", v.Start)
+	}
+	end, err := time.Parse(constants.DateFormat, v.End)
+	if err != nil {
+		return fmt.Errorf("invalid end date %q (expected YYYY-MM-DD)", v.End)
+	}
+	if end.Before(start) {
+		return fmt.Errorf("vacation ends (%s) before it starts (%s)", v.End, v.Start)
+	}
+	return nil
+}
+
+// Covers reports whether day (YYYY-MM-DD) falls within the vacation
+func (v Vacation) Covers(day string) bool {
+	return v.Start <= day && day <= v.End
+}
+
+// Days returns the number of days in the vacation
+func (v Vacation) Days() int {
+	start, err1 := time.Parse(constants.DateFormat, v.Start)
+	end, err2 := time.Parse(constants.DateFormat, v.End)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(end.Sub(start).Hours()/24) + 1
+}
+
+// ParseDateRange parses an inclusive "YYYY-MM-DD..YYYY-MM-DD" range. A single
+// date is a one-day range.
+func ParseDateRange(s string) (start, end string, err error) {
+	start, end, found := strings.Cut(strings.TrimSpace(s), "..")
+	if !found {
+		end = start
+	}
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	for _, d := range []string{start, end} {
+		if _, err := time.Parse(constants.DateFormat, d); err != nil {
+			return "", "", fmt.Errorf("invalid date range %q (expected YYYY-MM-DD..YYYY-MM-DD)", s)
+		}
+	}
+	if end < start {
+		return "", "", fmt.Errorf("invalid date range %q: it ends before it starts", s)
+	}
+	return start, end, nil
+}
+
+// VacationOn returns the vacation that covers day, if any
+func VacationOn(vacations []Vacation, day string) (Vacation, bool) {
+	for _, v := range vacations {
+		if v.Covers(day) {
+			return v, true
+		}
+	}
+	return Vacation{}, false
+}
+
+// TasksOffVacation returns the tasks to schedule on day: all of them, or none
+// of the recurring ones when day is covered by one of vacations
+func TasksOffVacation(tasks []Task, vacations []Vacation, day string) []Task {
+	if _, away := VacationOn(vacations, day); !away {
+		return tasks
+	}
+	var kept []Task
+	for _, task := range tasks {
+		if task.Recurrence.Type == constants.RecurrenceAdHoc {
+			kept = append(kept, task)
+		}
+	}
+	return kept
+}
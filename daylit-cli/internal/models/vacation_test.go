@@ -0,0 +1,57 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+func TestParseDateRange(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantStart string
+		wantEnd   string
+		wantErr   bool
+	}{
+		{input: "2025-07-01..2025-07-14", wantStart: "2025-07-01", wantEnd: "2025-07-14"},
+		{input: " 2025-07-01 .. 2025-07-01 ", wantStart: "2025-07-01", wantEnd: "2025-07-01"},
+		{input: "2025-07-04", wantStart: "2025-07-04", wantEnd: "2025-07-04"},
+		{input: "2025-07-14..2025-07-01", wantErr: true},
+		{input: "2025-07-01..next week", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			start, end, err := ParseDateRange(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDateRange(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("ParseDateRange(%q) = %s..%s, want %s..%s", tt.input, start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestTasksOffVacation(t *testing.T) {
+	vacations := []Vacation{{Start: "2025-07-01", End: "2025-07-14"}}
+	tasks := []Task{
+		{ID: "daily", Recurrence: Recurrence{Type: constants.RecurrenceDaily}},
+		{ID: "weekly", Recurrence: Recurrence{Type: constants.RecurrenceWeekly}},
+		{ID: "adhoc", Recurrence: Recurrence{Type: constants.RecurrenceAdHoc}},
+	}
+
+	if got := TasksOffVacation(tasks, vacations, "2025-06-30"); len(got) != 3 {
+		t.Errorf("expected every task the day before the vacation, got %d", len(got))
+	}
+	for _, day := range []string{"2025-07-01", "2025-07-14"} {
+		got := TasksOffVacation(tasks, vacations, day)
+		if len(got) != 1 || got[0].ID != "adhoc" {
+			t.Errorf("expected only the ad hoc task on %s, got %+v", day, got)
+		}
+	}
+	if days := vacations[0].Days(); days != 14 {
+		t.Errorf("Days() = %d, want 14", days)
+	}
+}
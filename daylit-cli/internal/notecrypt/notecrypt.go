@@ -0,0 +1,88 @@
+// Package notecrypt seals free-text notes with AES-256-GCM, so the notes in
+// a database file can't be read without the key that sealed them.
+package notecrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the length of a key in bytes
+const KeySize = 32
+
+// Prefix starts sealed text, so notes written before encryption was turned
+// on are told apart from sealed ones
+const Prefix = "enc:v1:"
+
+// ErrWrongKey is returned when sealed text can't be opened with the key
+var ErrWrongKey = errors.New("the encryption key doesn't match")
+
+// Cipher seals and opens notes with one key
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// GenerateKey returns a new random key
+func GenerateKey() []byte {
+	key := make([]byte, KeySize)
+	// crypto/rand.Read never fails; it crashes the program instead
+	_, _ = rand.Read(key)
+	return key
+}
+
+// New returns a cipher for key, which must be KeySize bytes long
+func New(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// IsSealed reports whether text was sealed by a cipher
+func IsSealed(text string) bool {
+	return strings.HasPrefix(text, Prefix)
+}
+
+// Seal encrypts text. Empty and already sealed text is returned unchanged,
+// so sealing a value read back from the database is harmless.
+func (c *Cipher) Seal(text string) string {
+	if text == "" || IsSealed(text) {
+		return text
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	_, _ = rand.Read(nonce)
+	sealed := c.aead.Seal(nonce, nonce, []byte(text), nil)
+	return Prefix + base64.StdEncoding.EncodeToString(sealed)
+}
+
+// Open decrypts text sealed with the same key. Text that isn't sealed is
+// returned unchanged.
+func (c *Cipher) Open(text string) (string, error) {
+	encoded, ok := strings.CutPrefix(text, Prefix)
+	if !ok {
+		return text, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted note")
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrWrongKey
+	}
+	return string(plain), nil
+}
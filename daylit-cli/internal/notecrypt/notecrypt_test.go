@@ -0,0 +1,54 @@
+package notecrypt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	c, err := New(GenerateKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	note := "felt anxious before the call"
+	sealed := c.Seal(note)
+	if !IsSealed(sealed) || strings.Contains(sealed, "anxious") {
+		t.Fatalf("Seal() = %q, want sealed text", sealed)
+	}
+	if c.Seal(note) == sealed {
+		t.Error("sealing twice should use a fresh nonce")
+	}
+	if again := c.Seal(sealed); again != sealed {
+		t.Error("sealing sealed text should leave it unchanged")
+	}
+	if got, err := c.Open(sealed); err != nil || got != note {
+		t.Errorf("Open() = %q, %v, want %q", got, err, note)
+	}
+
+	// Empty and plain text pass through
+	if got := c.Seal(""); got != "" {
+		t.Errorf("Seal(\"\") = %q", got)
+	}
+	if got, err := c.Open("written before encryption"); err != nil || got != "written before encryption" {
+		t.Errorf("Open(plain) = %q, %v", got, err)
+	}
+}
+
+func TestOpenWrongKey(t *testing.T) {
+	a, _ := New(GenerateKey())
+	b, _ := New(GenerateKey())
+	if _, err := b.Open(a.Seal("private")); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("Open() with another key error = %v, want ErrWrongKey", err)
+	}
+	if _, err := a.Open(Prefix + "not base64!"); err == nil {
+		t.Error("Open() of malformed text should fail")
+	}
+}
+
+func TestNewRejectsShortKey(t *testing.T) {
+	if _, err := New([]byte("short")); err == nil {
+		t.Error("New() should reject a short key")
+	}
+}
@@ -27,13 +27,21 @@ type Notifier struct{}
 type WebhookPayload struct {
 	Text       string `json:"text"`
 	DurationMs uint32 `json:"duration_ms"`
+	Urgency    string `json:"urgency,omitempty"` // low, normal or critical; empty is normal
+	Sound      string `json:"sound,omitempty"`   // sound for native notifications; empty uses the default
+}
+
+// Options change how a notification is shown
+type Options struct {
+	Urgency string
+	Sound   string
 }
 
 func New() *Notifier {
 	return &Notifier{}
 }
 
-func (n *Notifier) Notify(text string) error {
+func (n *Notifier) Notify(text string, opts Options) error {
 	trayAppConfigPath, err := GetTrayAppConfigDir()
 	if err != nil {
 		return err
@@ -47,6 +55,8 @@ func (n *Notifier) Notify(text string) error {
 	payload := WebhookPayload{
 		Text:       text,
 		DurationMs: constants.NotificationDurationMs,
+		Urgency:    opts.Urgency,
+		Sound:      opts.Sound,
 	}
 
 	if err := sendNotification(port, secret, payload); err != nil {
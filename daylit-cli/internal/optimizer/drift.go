@@ -0,0 +1,111 @@
+package optimizer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// DurationDrift compares a task's configured duration with how long its
+// recent blocks actually took
+type DurationDrift struct {
+	TaskID       string  `json:"task_id"`
+	TaskName     string  `json:"task_name"`
+	DurationMin  int     `json:"duration_min"`   // Configured duration
+	ActualAvgMin float64 `json:"actual_avg_min"` // Average actual duration over the recent feedback
+	Samples      int     `json:"samples"`        // Feedback entries with a duration
+	OnTrack      int     `json:"on_track"`
+	TooMuch      int     `json:"too_much"`
+	Unnecessary  int     `json:"unnecessary"`
+	SuggestedMin int     `json:"suggested_min"` // Suggested new duration
+}
+
+// ErrorPercent is how far the actual average is from the configured
+// duration, as a percentage of it. Positive means the task runs long.
+func (d DurationDrift) ErrorPercent() float64 {
+	if d.DurationMin <= 0 {
+		return 0
+	}
+	return (d.ActualAvgMin - float64(d.DurationMin)) / float64(d.DurationMin) * 100
+}
+
+// AnalyzeDrift compares every active task's duration with the actual
+// durations in its recent feedback, largest estimation error first. Tasks
+// without feedback are left out. The suggested duration is the actual
+// average rounded to 5 minutes, except that a task mostly rated too_much is
+// never suggested to grow: running long there means the block is too big,
+// not that it needs more time.
+func (fa *FeedbackAnalyzer) AnalyzeDrift(feedbackLimit int) ([]DurationDrift, error) {
+	if feedbackLimit <= 0 {
+		return nil, fmt.Errorf("feedbackLimit must be positive, got %d", feedbackLimit)
+	}
+
+	tasks, err := fa.store.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	var drifts []DurationDrift
+	for _, task := range tasks {
+		// Appointments last as long as their fixed times say
+		if !task.Active || task.Kind == constants.TaskKindAppointment {
+			continue
+		}
+
+		history, err := fa.store.GetTaskFeedbackHistory(task.ID, feedbackLimit)
+		if err != nil {
+			logger.Warn("Failed to get feedback history", "task", task.Name, "id", task.ID, "error", err)
+			continue
+		}
+		if drift, ok := durationDrift(task, history); ok {
+			drifts = append(drifts, drift)
+		}
+	}
+
+	sort.SliceStable(drifts, func(i, j int) bool {
+		return math.Abs(drifts[i].ErrorPercent()) > math.Abs(drifts[j].ErrorPercent())
+	})
+	return drifts, nil
+}
+
+// durationDrift summarizes a task's feedback history, reporting false when
+// none of it has a duration
+func durationDrift(task models.Task, history []models.TaskFeedbackEntry) (DurationDrift, bool) {
+	drift := DurationDrift{
+		TaskID:      task.ID,
+		TaskName:    task.Name,
+		DurationMin: task.DurationMin,
+	}
+
+	total := 0
+	for _, entry := range history {
+		switch entry.Rating {
+		case constants.FeedbackOnTrack:
+			drift.OnTrack++
+		case constants.FeedbackTooMuch:
+			drift.TooMuch++
+		case constants.FeedbackUnnecessary:
+			drift.Unnecessary++
+		}
+		if entry.ActualDuration > 0 {
+			total += entry.ActualDuration
+			drift.Samples++
+		}
+	}
+	if drift.Samples == 0 {
+		return DurationDrift{}, false
+	}
+
+	drift.ActualAvgMin = float64(total) / float64(drift.Samples)
+	suggested := int(math.Round(drift.ActualAvgMin/5)) * 5
+	suggested = max(suggested, constants.MinTaskDurationMin)
+	if drift.TooMuch*2 > len(history) && suggested > task.DurationMin {
+		suggested = task.DurationMin
+	}
+	drift.SuggestedMin = suggested
+	return drift, true
+}
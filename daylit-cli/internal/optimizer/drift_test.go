@@ -0,0 +1,90 @@
+package optimizer
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestAnalyzeDrift_RanksByEstimationError(t *testing.T) {
+	store := &mockStore{
+		feedbackHistory: map[string][]models.TaskFeedbackEntry{
+			"close": {
+				{TaskID: "close", Rating: constants.FeedbackOnTrack, ActualDuration: 32},
+				{TaskID: "close", Rating: constants.FeedbackOnTrack, ActualDuration: 28},
+			},
+			"long": {
+				{TaskID: "long", Rating: constants.FeedbackOnTrack, ActualDuration: 80},
+				{TaskID: "long", Rating: constants.FeedbackOnTrack, ActualDuration: 86},
+			},
+			"short": {
+				{TaskID: "short", Rating: constants.FeedbackOnTrack, ActualDuration: 40},
+			},
+		},
+		tasks: []models.Task{
+			{ID: "close", Name: "Close", DurationMin: 30, Active: true},
+			{ID: "long", Name: "Long", DurationMin: 60, Active: true},
+			{ID: "short", Name: "Short", DurationMin: 90, Active: true},
+			{ID: "none", Name: "No feedback", DurationMin: 45, Active: true},
+		},
+	}
+
+	drifts, err := NewFeedbackAnalyzer(store).AnalyzeDrift(10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(drifts) != 3 {
+		t.Fatalf("expected 3 tasks with feedback, got %d", len(drifts))
+	}
+
+	// Short is 56% under, Long 38% over, Close on the estimate
+	wantOrder := []string{"short", "long", "close"}
+	for i, id := range wantOrder {
+		if drifts[i].TaskID != id {
+			t.Errorf("position %d: expected %s, got %s", i, id, drifts[i].TaskID)
+		}
+	}
+	if drifts[0].SuggestedMin != 40 {
+		t.Errorf("expected 40m for Short, got %dm", drifts[0].SuggestedMin)
+	}
+	if drifts[1].SuggestedMin != 85 || drifts[1].ActualAvgMin != 83 {
+		t.Errorf("expected 83m average and 85m suggested for Long, got %.0fm and %dm", drifts[1].ActualAvgMin, drifts[1].SuggestedMin)
+	}
+	if drifts[2].SuggestedMin != 30 {
+		t.Errorf("expected Close to keep 30m, got %dm", drifts[2].SuggestedMin)
+	}
+}
+
+func TestAnalyzeDrift_TooMuchNeverGrows(t *testing.T) {
+	store := &mockStore{
+		feedbackHistory: map[string][]models.TaskFeedbackEntry{
+			"task-1": {
+				{TaskID: "task-1", Rating: constants.FeedbackTooMuch, ActualDuration: 75},
+				{TaskID: "task-1", Rating: constants.FeedbackTooMuch, ActualDuration: 70},
+				{TaskID: "task-1", Rating: constants.FeedbackOnTrack, ActualDuration: 60},
+			},
+		},
+		tasks: []models.Task{{ID: "task-1", Name: "Task 1", DurationMin: 60, Active: true}},
+	}
+
+	drifts, err := NewFeedbackAnalyzer(store).AnalyzeDrift(10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(drifts) != 1 {
+		t.Fatalf("expected 1 drift, got %d", len(drifts))
+	}
+	if drifts[0].TooMuch != 2 || drifts[0].OnTrack != 1 {
+		t.Errorf("expected 2 too_much and 1 on_track, got %+v", drifts[0])
+	}
+	if drifts[0].SuggestedMin != 60 {
+		t.Errorf("expected a task rated too_much to keep 60m, got %dm", drifts[0].SuggestedMin)
+	}
+}
+
+func TestAnalyzeDrift_InvalidLimit(t *testing.T) {
+	if _, err := NewFeedbackAnalyzer(&mockStore{}).AnalyzeDrift(0); err == nil {
+		t.Error("expected an error for a zero feedback limit")
+	}
+}
@@ -2,15 +2,19 @@ package optimizer
 
 import (
 	"testing"
+	"time"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
 )
 
 // mockStore is a mock implementation of storage.Provider for testing
 type mockStore struct {
 	feedbackHistory map[string][]models.TaskFeedbackEntry
 	tasks           []models.Task
+	metrics         []models.DailyMetrics
+	plans           []models.DayPlan
 }
 
 func (m *mockStore) GetTaskFeedbackHistory(taskID string, limit int) ([]models.TaskFeedbackEntry, error) {
@@ -45,9 +49,21 @@ func (m *mockStore) GetPlan(date string) (models.DayPlan, error)         { retur
 func (m *mockStore) GetPlanRevision(date string, revision int) (models.DayPlan, error) {
 	return models.DayPlan{}, nil
 }
+func (m *mockStore) DeleteTaskCascade(id string, removeSlots bool, now time.Time) (int, error) {
+	return 0, nil
+}
+func (m *mockStore) WithTx(fn func(storage.Provider) error) error {
+	return fn(m)
+}
 func (m *mockStore) GetLatestPlanRevision(date string) (models.DayPlan, error) {
 	return models.DayPlan{}, nil
 }
+func (m *mockStore) GetPlansRange(startDay, endDay string) ([]models.DayPlan, error) {
+	return m.plans, nil
+}
+func (m *mockStore) GetPlansPage(startDay, endDay, cursor string, limit int) (models.PlanPage, error) {
+	return models.PlanPage{}, nil
+}
 func (m *mockStore) DeletePlan(date string) error  { return nil }
 func (m *mockStore) RestorePlan(date string) error { return nil }
 func (m *mockStore) UpdateSlotNotificationTimestamp(date string, revision int, startTime string, taskID string, notificationType string, timestamp string) error {
@@ -94,6 +110,114 @@ func (m *mockStore) GetAlert(id string) (models.Alert, error)         { return m
 func (m *mockStore) GetAllAlerts() ([]models.Alert, error)            { return nil, nil }
 func (m *mockStore) UpdateAlert(models.Alert) error                   { return nil }
 func (m *mockStore) DeleteAlert(id string) error                      { return nil }
+func (m *mockStore) AddVacation(models.Vacation) error                { return nil }
+func (m *mockStore) GetVacations(startDay, endDay string) ([]models.Vacation, error) {
+	return nil, nil
+}
+func (m *mockStore) DeleteVacation(id string) error            { return nil }
+func (m *mockStore) AddSlotReminder(models.SlotReminder) error { return nil }
+func (m *mockStore) GetSlotReminders(startDay, endDay string) ([]models.SlotReminder, error) {
+	return nil, nil
+}
+func (m *mockStore) MarkSlotReminderSent(id string, sentAt time.Time) error { return nil }
+func (m *mockStore) DeleteSlotReminder(id string) error                     { return nil }
+func (m *mockStore) AddNotificationLog(models.NotificationLogEntry) error   { return nil }
+func (m *mockStore) GetNotificationLog(since time.Time, limit int) ([]models.NotificationLogEntry, error) {
+	return nil, nil
+}
+func (m *mockStore) AddOutboxItem(models.OutboxItem) error { return nil }
+func (m *mockStore) GetDueOutboxItems(now time.Time, limit int) ([]models.OutboxItem, error) {
+	return nil, nil
+}
+func (m *mockStore) UpdateOutboxItem(models.OutboxItem) error { return nil }
+func (m *mockStore) DeleteOutboxItem(id int64) error          { return nil }
+func (m *mockStore) GetOutboxItems(since time.Time) ([]models.OutboxItem, error) {
+	return nil, nil
+}
+func (m *mockStore) ClaimNotification(key string, at time.Time) (bool, error) { return true, nil }
+func (m *mockStore) DeleteNotificationClaims(before time.Time) error          { return nil }
+func (m *mockStore) GetDaySummaries(startDay, endDay string) ([]models.DaySummary, error) {
+	return nil, nil
+}
+func (m *mockStore) AddProject(models.Project) error {
+	return nil
+}
+func (m *mockStore) GetProjectByName(name string) (models.Project, error) {
+	return models.Project{}, nil
+}
+func (m *mockStore) GetAllProjects() ([]models.Project, error) {
+	return nil, nil
+}
+func (m *mockStore) AddPool(models.TaskPool) error {
+	return nil
+}
+func (m *mockStore) GetPoolByName(name string) (models.TaskPool, error) {
+	return models.TaskPool{}, nil
+}
+func (m *mockStore) GetAllPools() ([]models.TaskPool, error) {
+	return nil, nil
+}
+func (m *mockStore) DeletePool(id string) error {
+	return nil
+}
+func (m *mockStore) AddInboxItem(item models.InboxItem) error {
+	return nil
+}
+func (m *mockStore) GetInboxItems() ([]models.InboxItem, error) {
+	return nil, nil
+}
+func (m *mockStore) DeleteInboxItem(id string) error {
+	return nil
+}
+func (m *mockStore) SaveDayTemplate(models.DayTemplate) error {
+	return nil
+}
+func (m *mockStore) GetDayTemplate(name string) (models.DayTemplate, error) {
+	return models.DayTemplate{}, nil
+}
+func (m *mockStore) GetAllDayTemplates() ([]models.DayTemplate, error) {
+	return nil, nil
+}
+func (m *mockStore) DeleteDayTemplate(name string) error {
+	return nil
+}
+func (m *mockStore) EachSlot(startDay, endDay string, fn func(models.SlotRecord) error) error {
+	return nil
+}
+func (m *mockStore) PurgeDeleted(before time.Time, kinds []models.PurgeKind, dryRun bool) (models.PurgeSummary, error) {
+	return models.PurgeSummary{}, nil
+}
+func (m *mockStore) PurgeItem(kind models.TrashKind, id string) error {
+	return nil
+}
+func (m *mockStore) DeleteSlot(date string, revision int, startTime string, taskID string) error {
+	return nil
+}
+func (m *mockStore) RestoreSlot(date string, revision int, startTime string, taskID string) error {
+	return nil
+}
+func (m *mockStore) AmendPlan(a models.PlanAmendment) (models.PlanAmendment, error) {
+	return a, nil
+}
+func (m *mockStore) GetPlanAmendments(date string) ([]models.PlanAmendment, error) {
+	return nil, nil
+}
+func (m *mockStore) GetTaskStats(startDay, endDay string) ([]models.TaskStats, error) {
+	return nil, nil
+}
+func (m *mockStore) GetHabitCategoryStats(startDay, endDay string) ([]models.HabitCategoryStats, error) {
+	return nil, nil
+}
+func (m *mockStore) SaveDailyMetrics(models.DailyMetrics) error { return nil }
+func (m *mockStore) GetDailyMetrics(startDay, endDay string) ([]models.DailyMetrics, error) {
+	return m.metrics, nil
+}
+func (m *mockStore) GetTaskSlotHistory(taskID string, limit int) ([]models.TaskSlotEntry, error) {
+	return nil, nil
+}
+func (m *mockStore) Search(query string, limit int) ([]models.SearchResult, error) {
+	return nil, nil
+}
 
 func TestAnalyzeTask_NoFeedback(t *testing.T) {
 	store := &mockStore{
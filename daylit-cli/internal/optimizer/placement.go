@@ -0,0 +1,187 @@
+package optimizer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+const (
+	// PlacementBucketMinutes is the length of the times of day slots are
+	// grouped into, starting at midnight
+	PlacementBucketMinutes = 180
+	// placementMinSamples is how many resolved slots a task needs before a
+	// preference is learned for it
+	placementMinSamples = 6
+	// placementMinBucketSamples is how many of them the preferred time needs
+	placementMinBucketSamples = 3
+	// placementMinLift is how much higher the done rate at the preferred
+	// time must be than at all other times
+	placementMinLift = 0.25
+)
+
+// PlacementPreference is the time of day a task gets done most reliably,
+// compared with how it does at all other times
+type PlacementPreference struct {
+	TaskID       string `json:"task_id"`
+	TaskName     string `json:"task_name"`
+	Start        string `json:"start"` // HH:MM
+	End          string `json:"end"`   // HH:MM
+	Done         int    `json:"done"`  // Slots done at the preferred time
+	Samples      int    `json:"samples"`
+	OtherDone    int    `json:"other_done"` // Slots done at all other times
+	OtherSamples int    `json:"other_samples"`
+}
+
+// Rate is the share of slots at the preferred time that were done
+func (p PlacementPreference) Rate() float64 {
+	return share(p.Done, p.Samples)
+}
+
+// OtherRate is the share of slots at all other times that were done
+func (p PlacementPreference) OtherRate() float64 {
+	return share(p.OtherDone, p.OtherSamples)
+}
+
+func share(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// placementOutcome counts the resolved slots in one time of day
+type placementOutcome struct {
+	done, samples int
+}
+
+// AnalyzePlacement learns, from the accepted plans from startDay to endDay,
+// the time of day each flexible task gets done most reliably. A slot counts
+// at its actual start when one was tracked and at its planned start
+// otherwise; it is resolved once the plan was accepted, and done only when
+// marked done. A task gets a preference when one time of day beats all the
+// others by a clear margin, with the best lift first.
+func (fa *FeedbackAnalyzer) AnalyzePlacement(startDay, endDay string) ([]PlacementPreference, error) {
+	tasks, err := fa.store.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	plans, err := fa.store.GetPlansRange(startDay, endDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plans: %w", err)
+	}
+
+	outcomes := make(map[string]map[int]*placementOutcome)
+	for _, plan := range plans {
+		if plan.AcceptedAt == nil || plan.DeletedAt != nil {
+			continue
+		}
+		for _, slot := range plan.Slots {
+			if slot.DeletedAt != nil {
+				continue
+			}
+			start := slot.Start
+			if slot.ActualStart != nil {
+				start = *slot.ActualStart
+			}
+			minutes, err := utils.ParseTimeToMinutes(start)
+			if err != nil {
+				continue
+			}
+			bucket := minutes / PlacementBucketMinutes
+			if outcomes[slot.TaskID] == nil {
+				outcomes[slot.TaskID] = make(map[int]*placementOutcome)
+			}
+			o := outcomes[slot.TaskID][bucket]
+			if o == nil {
+				o = &placementOutcome{}
+				outcomes[slot.TaskID][bucket] = o
+			}
+			o.samples++
+			if slot.Status == constants.SlotStatusDone {
+				o.done++
+			}
+		}
+	}
+
+	var prefs []PlacementPreference
+	for _, task := range tasks {
+		// Appointments happen when they are booked
+		if !task.Active || task.Kind != constants.TaskKindFlexible {
+			continue
+		}
+		if pref, ok := placementPreference(task, outcomes[task.ID]); ok {
+			prefs = append(prefs, pref)
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].Rate()-prefs[i].OtherRate() > prefs[j].Rate()-prefs[j].OtherRate()
+	})
+	return prefs, nil
+}
+
+// placementPreference picks the time of day task has the best done rate at,
+// reporting false when there is too little history or no time clearly beats
+// the rest
+func placementPreference(task models.Task, buckets map[int]*placementOutcome) (PlacementPreference, bool) {
+	total := placementOutcome{}
+	for _, o := range buckets {
+		total.done += o.done
+		total.samples += o.samples
+	}
+	if total.samples < placementMinSamples {
+		return PlacementPreference{}, false
+	}
+
+	best, found := 0, false
+	for bucket, o := range buckets {
+		if o.samples < placementMinBucketSamples {
+			continue
+		}
+		if !found || betterBucket(o, buckets[best], bucket, best) {
+			best, found = bucket, true
+		}
+	}
+	if !found {
+		return PlacementPreference{}, false
+	}
+
+	o := buckets[best]
+	pref := PlacementPreference{
+		TaskID:       task.ID,
+		TaskName:     task.Name,
+		Start:        bucketClock(best),
+		End:          bucketClock(best + 1),
+		Done:         o.done,
+		Samples:      o.samples,
+		OtherDone:    total.done - o.done,
+		OtherSamples: total.samples - o.samples,
+	}
+	// Without history at other times there is nothing to prefer it over
+	if pref.OtherSamples == 0 || pref.Rate()-pref.OtherRate() < placementMinLift {
+		return PlacementPreference{}, false
+	}
+	return pref, true
+}
+
+// betterBucket reports whether o has a higher done rate than other, going by
+// more samples and then the earlier time of day on a tie
+func betterBucket(o, other *placementOutcome, bucket, otherBucket int) bool {
+	rate, otherRate := share(o.done, o.samples), share(other.done, other.samples)
+	if rate != otherRate {
+		return rate > otherRate
+	}
+	if o.samples != other.samples {
+		return o.samples > other.samples
+	}
+	return bucket < otherBucket
+}
+
+// bucketClock returns the HH:MM start of a time-of-day bucket
+func bucketClock(bucket int) string {
+	minutes := bucket * PlacementBucketMinutes % models.MinutesPerDay
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,103 @@
+package optimizer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// placementPlans returns accepted plans with one slot of taskID each,
+// starting at the given times and done when marked so
+func placementPlans(taskID string, starts []string, done []bool) []models.DayPlan {
+	accepted := "2025-06-01T07:00:00Z"
+	var plans []models.DayPlan
+	for i, start := range starts {
+		status := models.SlotStatus(constants.SlotStatusAccepted)
+		if done[i] {
+			status = constants.SlotStatusDone
+		}
+		plans = append(plans, models.DayPlan{
+			Date:       fmt.Sprintf("2025-06-%02d", i+1),
+			AcceptedAt: &accepted,
+			Slots:      []models.Slot{{Start: start, End: start, TaskID: taskID, Status: status}},
+		})
+	}
+	return plans
+}
+
+func TestAnalyzePlacement(t *testing.T) {
+	journal := models.Task{ID: "journal", Name: "Journal", Kind: constants.TaskKindFlexible, Active: true}
+
+	tests := []struct {
+		name   string
+		starts []string
+		done   []bool
+		want   string // Preferred start, or empty for no preference
+	}{
+		{
+			name:   "too little history",
+			starts: []string{"07:00", "07:30", "08:00", "20:00"},
+			done:   []bool{true, true, true, false},
+		},
+		{
+			name:   "mornings win",
+			starts: []string{"07:00", "07:30", "08:00", "08:30", "20:00", "20:30", "21:00"},
+			done:   []bool{true, true, true, false, false, true, false},
+			want:   "06:00",
+		},
+		{
+			name:   "no clear winner",
+			starts: []string{"07:00", "07:30", "08:00", "20:00", "20:30", "21:00"},
+			done:   []bool{true, true, false, true, false, true},
+		},
+		{
+			name:   "only ever one time",
+			starts: []string{"07:00", "07:30", "08:00", "08:15", "08:30", "08:45"},
+			done:   []bool{true, true, true, true, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockStore{tasks: []models.Task{journal}, plans: placementPlans("journal", tt.starts, tt.done)}
+			prefs, err := NewFeedbackAnalyzer(store).AnalyzePlacement("2025-06-01", "2025-06-30")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want == "" {
+				if len(prefs) != 0 {
+					t.Errorf("expected no preference, got %+v", prefs)
+				}
+				return
+			}
+			if len(prefs) != 1 || prefs[0].Start != tt.want {
+				t.Fatalf("expected a preference from %s, got %+v", tt.want, prefs)
+			}
+			if prefs[0].End != "09:00" || prefs[0].Done != 3 || prefs[0].Samples != 4 || prefs[0].OtherSamples != 3 {
+				t.Errorf("unexpected preference %+v", prefs[0])
+			}
+		})
+	}
+}
+
+func TestAnalyzePlacement_ActualStart(t *testing.T) {
+	journal := models.Task{ID: "journal", Name: "Journal", Kind: constants.TaskKindFlexible, Active: true}
+	plans := placementPlans("journal",
+		[]string{"20:00", "20:00", "20:00", "20:00", "20:00", "20:00"},
+		[]bool{true, true, true, false, false, false})
+	// Done early in the morning, however late it was planned
+	for i := 0; i < 3; i++ {
+		early := "06:30"
+		plans[i].Slots[0].ActualStart = &early
+	}
+
+	prefs, err := NewFeedbackAnalyzer(&mockStore{tasks: []models.Task{journal}, plans: plans}).AnalyzePlacement("2025-06-01", "2025-06-30")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prefs) != 1 || prefs[0].Start != "06:00" || prefs[0].Rate() != 1 || prefs[0].OtherRate() != 0 {
+		t.Errorf("expected the tracked mornings to be preferred, got %+v", prefs)
+	}
+}
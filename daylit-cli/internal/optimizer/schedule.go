@@ -0,0 +1,70 @@
+package optimizer
+
+import (
+	"fmt"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// Thresholds for advice about the plans as a whole
+const (
+	scheduleMinDays        = 7  // Days of metrics needed before giving advice
+	lowAcceptanceRate      = 50 // Percent of planned days accepted
+	lowCompletionRate      = 60 // Percent of planned slots done
+	highAvgDailyDriftMin   = 30 // Minutes a tracked day drifts on average
+	highTooMuchShare       = 30 // Percent of feedback rated too_much
+	scheduleMinRatedSlots  = 10 // Rated slots needed before judging the feedback mix
+	scheduleMinTrackedDays = 3  // Tracked days needed before judging drift
+)
+
+// ScheduleInsight is advice about the plans as a whole rather than a single
+// task, drawn from the recorded scheduling metrics
+type ScheduleInsight struct {
+	Reason string `json:"reason"`
+	Advice string `json:"advice"`
+}
+
+// AnalyzeSchedule reads the scheduling metrics recorded for the inclusive
+// date range and returns advice for the indicators that look off. It returns
+// nothing until scheduleMinDays days have been recorded, which never happens
+// while metrics are turned off.
+func (fa *FeedbackAnalyzer) AnalyzeSchedule(startDay, endDay string) ([]ScheduleInsight, error) {
+	daily, err := fa.store.GetDailyMetrics(startDay, endDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics: %w", err)
+	}
+	if len(daily) < scheduleMinDays {
+		return nil, nil
+	}
+	return scheduleInsights(models.SummarizeMetrics(daily)), nil
+}
+
+// scheduleInsights turns a metrics summary into advice
+func scheduleInsights(s models.MetricsSummary) []ScheduleInsight {
+	var insights []ScheduleInsight
+	if s.PlannedDays >= scheduleMinDays && s.AcceptanceRate() < lowAcceptanceRate {
+		insights = append(insights, ScheduleInsight{
+			Reason: fmt.Sprintf("only %d%% of plans were accepted (%d of %d days)", s.AcceptanceRate(), s.AcceptedDays, s.PlannedDays),
+			Advice: "generated plans often don't fit the day; review task windows and priorities, or start from a day template",
+		})
+	}
+	if s.PlannedSlots > 0 && s.CompletionRate() < lowCompletionRate {
+		insights = append(insights, ScheduleInsight{
+			Reason: fmt.Sprintf("only %d%% of planned slots were done (%d of %d)", s.CompletionRate(), s.DoneSlots, s.PlannedSlots),
+			Advice: "plans are overfull; shorten durations or mark some tasks nice-to-have",
+		})
+	}
+	if s.TrackedDays >= scheduleMinTrackedDays && s.AvgDailyDrift() >= highAvgDailyDriftMin {
+		insights = append(insights, ScheduleInsight{
+			Reason: fmt.Sprintf("tracked days drifted %dm from the plan on average", s.AvgDailyDrift()),
+			Advice: "leave gaps between blocks, or run 'daylit optimize drift' to correct durations",
+		})
+	}
+	if s.Rated() >= scheduleMinRatedSlots && s.FeedbackShare(s.TooMuch) >= highTooMuchShare {
+		insights = append(insights, ScheduleInsight{
+			Reason: fmt.Sprintf("%d%% of feedback was too_much (%d of %d rated slots)", s.FeedbackShare(s.TooMuch), s.TooMuch, s.Rated()),
+			Advice: "blocks tend to be too big across the board, not just for the tasks below",
+		})
+	}
+	return insights
+}
@@ -0,0 +1,52 @@
+package optimizer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// scheduleMetrics returns n days of metrics that each look like day
+func scheduleMetrics(n int, day models.DailyMetrics) []models.DailyMetrics {
+	var metrics []models.DailyMetrics
+	for i := 0; i < n; i++ {
+		d := day
+		d.Date = fmt.Sprintf("2025-06-%02d", i+1)
+		metrics = append(metrics, d)
+	}
+	return metrics
+}
+
+func TestAnalyzeSchedule(t *testing.T) {
+	healthy := models.DailyMetrics{HasPlan: true, Accepted: true, PlannedSlots: 5, DoneSlots: 5, TrackedSlots: 2, DriftMinutes: 10, OnTrack: 3}
+	rough := models.DailyMetrics{HasPlan: true, Accepted: false, PlannedSlots: 5, DoneSlots: 2, TrackedSlots: 2, DriftMinutes: 45, OnTrack: 1, TooMuch: 2}
+
+	tests := []struct {
+		name    string
+		metrics []models.DailyMetrics
+		want    []string // Reasons that must appear, in order
+	}{
+		{name: "too few days", metrics: scheduleMetrics(6, rough)},
+		{name: "healthy", metrics: scheduleMetrics(10, healthy)},
+		{name: "rough", metrics: scheduleMetrics(10, rough), want: []string{"plans were accepted", "planned slots were done", "drifted 45m", "too_much"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			insights, err := NewFeedbackAnalyzer(&mockStore{metrics: tt.metrics}).AnalyzeSchedule("2025-06-01", "2025-06-30")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(insights) != len(tt.want) {
+				t.Fatalf("expected %d insights, got %+v", len(tt.want), insights)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(insights[i].Reason, want) {
+					t.Errorf("insight %d = %q, want it to mention %q", i, insights[i].Reason, want)
+				}
+			}
+		})
+	}
+}
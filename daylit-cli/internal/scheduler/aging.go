@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// AgingLookbackDays is how many days of plans are searched for the days a
+// task was left out of
+const AgingLookbackDays = 60
+
+// Aging is how far a task's priority has been raised for being left out of
+// the plan day after day
+type Aging struct {
+	MissedDays int // Days in a row the task was due but left out of the plan
+	Boost      int // Priority levels the task is raised by
+}
+
+// Priority returns task's priority raised by the boost, never above 1
+func (a Aging) Priority(task models.Task) int {
+	return max(task.Priority-a.Boost, 1)
+}
+
+// AgeTasks works out how far each flexible task's priority is raised: one
+// level for every agingDays days in a row, counting back from the most recent
+// plan, that the task was due but the plan left it out. Days without a plan
+// don't count either way. Nice-to-have tasks and pool members are left out
+// on purpose, so they never age. Tasks that aren't raised are not included.
+func AgeTasks(tasks []models.Task, plans []models.DayPlan, agingDays int) map[string]Aging {
+	if agingDays <= 0 {
+		return nil
+	}
+
+	// Most recent plan first
+	plans = append([]models.DayPlan(nil), plans...)
+	sort.Slice(plans, func(i, j int) bool { return plans[i].Date > plans[j].Date })
+
+	aging := make(map[string]Aging)
+	for _, task := range tasks {
+		if !task.Active || task.Kind != constants.TaskKindFlexible || task.NiceToHave || task.PoolID != "" {
+			continue
+		}
+		missed := missedDays(task, plans)
+		if boost := missed / agingDays; boost > 0 && task.Priority > 1 {
+			aging[task.ID] = Aging{MissedDays: missed, Boost: min(boost, task.Priority-1)}
+		}
+	}
+	return aging
+}
+
+// missedDays counts the plans, most recent first, of days task was due but
+// not planned on, up to the last plan it was in
+func missedDays(task models.Task, plans []models.DayPlan) int {
+	missed := 0
+	for _, plan := range plans {
+		day, err := time.Parse(constants.DateFormat, plan.Date)
+		if err != nil || plan.DeletedAt != nil || !shouldScheduleTask(task, day) {
+			continue
+		}
+		if hasSlot(plan, task.ID) {
+			break
+		}
+		missed++
+	}
+	return missed
+}
+
+// hasSlot reports whether plan has a slot for taskID
+func hasSlot(plan models.DayPlan, taskID string) bool {
+	for _, slot := range plan.Slots {
+		if slot.TaskID == taskID && slot.DeletedAt == nil {
+			return true
+		}
+	}
+	return false
+}
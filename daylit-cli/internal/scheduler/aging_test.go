@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// agingPlans returns a plan for each of the days 1–n of June 2026 with a
+// slot for each of taskIDs
+func agingPlans(n int, taskIDs ...string) []models.DayPlan {
+	var plans []models.DayPlan
+	for i := 1; i <= n; i++ {
+		plan := models.DayPlan{Date: fmt.Sprintf("2026-06-%02d", i)}
+		for _, id := range taskIDs {
+			plan.Slots = append(plan.Slots, models.Slot{Start: "09:00", End: "10:00", TaskID: id})
+		}
+		plans = append(plans, plan)
+	}
+	return plans
+}
+
+func TestAgeTasks(t *testing.T) {
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	mondays := models.Recurrence{Type: constants.RecurrenceWeekly, WeekdayMask: []time.Weekday{time.Monday}}
+	tasks := []models.Task{
+		{ID: "taxes", Name: "Taxes", Kind: constants.TaskKindFlexible, Recurrence: daily, Priority: 5, Active: true},
+		{ID: "review", Name: "Review", Kind: constants.TaskKindFlexible, Recurrence: mondays, Priority: 4, Active: true},
+		{ID: "report", Name: "Report", Kind: constants.TaskKindFlexible, Recurrence: daily, Priority: 2, Active: true},
+		{ID: "guitar", Name: "Guitar", Kind: constants.TaskKindFlexible, Recurrence: daily, Priority: 5, Active: true, NiceToHave: true},
+		{ID: "run", Name: "Run", Kind: constants.TaskKindFlexible, Recurrence: daily, Priority: 5, Active: true, PoolID: "workout"},
+	}
+
+	// Taxes was planned on June 1 and left out for 9 days since; Review was
+	// only due on one Monday since, June 8; Report was planned every day
+	plans := agingPlans(10, "report")
+	plans[0].Slots = append(plans[0].Slots,
+		models.Slot{Start: "10:00", End: "11:00", TaskID: "taxes"},
+		models.Slot{Start: "11:00", End: "12:00", TaskID: "review"})
+	// A deleted slot doesn't count as planned
+	deleted := "2026-06-10T08:00:00Z"
+	plans[9].Slots = append(plans[9].Slots, models.Slot{Start: "12:00", End: "13:00", TaskID: "taxes", DeletedAt: &deleted})
+
+	aging := AgeTasks(tasks, plans, 3)
+	if got := aging["taxes"]; got.MissedDays != 9 || got.Boost != 3 || got.Priority(tasks[0]) != 2 {
+		t.Errorf("taxes aging = %+v, want 9 missed days raising priority 5 to 2", got)
+	}
+	if _, ok := aging["review"]; ok {
+		t.Errorf("expected one missed Monday not to age review, got %+v", aging["review"])
+	}
+	for _, id := range []string{"report", "guitar", "run"} {
+		if _, ok := aging[id]; ok {
+			t.Errorf("expected %s not to age, got %+v", id, aging[id])
+		}
+	}
+
+	// Never raised above priority 1
+	if got := AgeTasks(tasks[:1], plans, 1)["taxes"]; got.Boost != 4 || got.Priority(tasks[0]) != 1 {
+		t.Errorf("taxes aging = %+v, want priority capped at 1", got)
+	}
+	if aging := AgeTasks(tasks, plans, 0); aging != nil {
+		t.Errorf("expected aging off at 0 days, got %+v", aging)
+	}
+}
+
+func TestGeneratePlanWithOptions_Aging(t *testing.T) {
+	s := New()
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	tasks := []models.Task{
+		{ID: "report", Name: "Report", Kind: constants.TaskKindFlexible, DurationMin: 60, Recurrence: daily, Priority: 2, Active: true},
+		{ID: "taxes", Name: "Taxes", Kind: constants.TaskKindFlexible, DurationMin: 60, Recurrence: daily, Priority: 5, Active: true},
+	}
+
+	// One hour of room: only the higher priority fits
+	opts := PlanOptions{Aging: map[string]Aging{"taxes": {MissedDays: 9, Boost: 4}}}
+	plan, err := s.GeneratePlanWithOptions("2026-06-11", tasks, "09:00", "10:00", opts)
+	if err != nil {
+		t.Fatalf("GeneratePlanWithOptions failed: %v", err)
+	}
+	if len(plan.Slots) != 1 || plan.Slots[0].TaskID != "taxes" {
+		t.Errorf("expected aged taxes to take the hour, got %+v", plan.Slots)
+	}
+}
@@ -0,0 +1,231 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// scenario is a random day to plan
+type scenario struct {
+	date     string
+	dayStart string
+	dayEnd   string
+	tasks    []models.Task
+	opts     PlanOptions
+}
+
+// randomScenario builds a day from seed: a waking window that may end after
+// midnight, appointments that don't overlap each other, and flexible tasks
+// with random durations, priorities, lateness and time constraints
+func randomScenario(seed uint64) scenario {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	minutes := func(lo, hi int) int { return (lo + rng.IntN(hi-lo+1)) / 5 * 5 }
+
+	start := minutes(4*60, 11*60)
+	end := start + minutes(6*60, 20*60)
+	sc := scenario{
+		date:     "2025-03-10",
+		dayStart: formatTime(start),
+		dayEnd:   formatTime(end),
+	}
+	if rng.IntN(4) == 0 {
+		sc.opts.Capacity = minutes(25, 100)
+	}
+
+	// Appointments, one after another so only the scheduler can make them overlap
+	cursor := start
+	for i := range rng.IntN(5) {
+		apptStart := cursor + minutes(0, 180)
+		apptEnd := apptStart + minutes(15, 120)
+		if apptEnd > end {
+			break
+		}
+		sc.tasks = append(sc.tasks, models.Task{
+			ID:         fmt.Sprintf("appt-%d", i),
+			Kind:       constants.TaskKindAppointment,
+			FixedStart: formatTime(apptStart),
+			FixedEnd:   formatTime(apptEnd),
+			Recurrence: models.Recurrence{Type: constants.RecurrenceDaily},
+			Active:     true,
+		})
+		cursor = apptEnd
+	}
+
+	for i := range rng.IntN(16) {
+		task := models.Task{
+			ID:          fmt.Sprintf("task-%d", i),
+			Kind:        constants.TaskKindFlexible,
+			DurationMin: minutes(5, 180),
+			Priority:    1 + rng.IntN(5),
+			NiceToHave:  rng.IntN(8) == 0,
+			Recurrence:  models.Recurrence{Type: constants.RecurrenceNDays, IntervalDays: 1 + rng.IntN(3)},
+			Active:      rng.IntN(10) != 0,
+		}
+		if rng.IntN(2) == 0 {
+			task.LastDone = fmt.Sprintf("2025-03-%02d", 1+rng.IntN(9))
+		}
+		if rng.IntN(3) == 0 {
+			task.EarliestStart = formatTime(minutes(start, end))
+		}
+		if rng.IntN(3) == 0 {
+			task.LatestEnd = formatTime(minutes(start, end))
+		}
+		sc.tasks = append(sc.tasks, task)
+	}
+	return sc
+}
+
+// checkPlan fails t if plan breaks an invariant of sc: slots overlap, an
+// appointment isn't at its fixed time, a slot is outside the waking window,
+// or a flexible task is placed twice, with the wrong duration or outside
+// its earliest start and latest end
+func checkPlan(t *testing.T, sc scenario, plan models.DayPlan) {
+	t.Helper()
+
+	window, err := models.ParseDayWindow(sc.dayStart, sc.dayEnd)
+	if err != nil {
+		t.Fatalf("invalid window %s-%s: %v", sc.dayStart, sc.dayEnd, err)
+	}
+	tasks := make(map[string]models.Task)
+	for _, task := range sc.tasks {
+		tasks[task.ID] = task
+	}
+
+	type span struct {
+		start, end int
+		taskID     string
+	}
+	var spans []span
+	placed := make(map[string]bool)
+	for _, slot := range plan.Slots {
+		start, end, err := window.Range(slot.Start, slot.End)
+		if err != nil {
+			t.Fatalf("slot %s-%s for %s: %v", slot.Start, slot.End, slot.TaskID, err)
+		}
+		if start < window.Start || end > window.End {
+			t.Errorf("slot %s-%s for %s is outside the day %s-%s", slot.Start, slot.End, slot.TaskID, sc.dayStart, sc.dayEnd)
+		}
+		if placed[slot.TaskID] {
+			t.Errorf("task %s is placed more than once", slot.TaskID)
+		}
+		placed[slot.TaskID] = true
+		spans = append(spans, span{start, end, slot.TaskID})
+
+		task, ok := tasks[slot.TaskID]
+		if !ok {
+			t.Fatalf("slot for unknown task %s", slot.TaskID)
+		}
+		if !task.Active {
+			t.Errorf("inactive task %s is placed", task.ID)
+		}
+		if task.Kind == constants.TaskKindAppointment {
+			if slot.Start != task.FixedStart || slot.End != task.FixedEnd {
+				t.Errorf("appointment %s is at %s-%s, want %s-%s", task.ID, slot.Start, slot.End, task.FixedStart, task.FixedEnd)
+			}
+			continue
+		}
+		if end-start != task.DurationMin {
+			t.Errorf("task %s runs %d minutes, want %d", task.ID, end-start, task.DurationMin)
+		}
+		if task.EarliestStart != "" {
+			if earliest, _ := window.Minutes(task.EarliestStart); start < earliest {
+				t.Errorf("task %s starts at %s, before its earliest start %s", task.ID, slot.Start, task.EarliestStart)
+			}
+		}
+		if task.LatestEnd != "" {
+			if latest, _ := window.Minutes(task.LatestEnd); end > latest {
+				t.Errorf("task %s ends at %s, after its latest end %s", task.ID, slot.End, task.LatestEnd)
+			}
+		}
+	}
+
+	for _, task := range sc.tasks {
+		if task.Active && task.Kind == constants.TaskKindAppointment && !placed[task.ID] {
+			t.Errorf("appointment %s at %s-%s is missing", task.ID, task.FixedStart, task.FixedEnd)
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	for i := 1; i < len(spans); i++ {
+		if spans[i].start < spans[i-1].end {
+			t.Errorf("task %s overlaps task %s", spans[i].taskID, spans[i-1].taskID)
+		}
+	}
+}
+
+// checkSeed plans the scenario for seed twice with a scheduler seeded the
+// same way, and checks both plans are valid and identical
+func checkSeed(t *testing.T, seed uint64) {
+	t.Helper()
+
+	sc := randomScenario(seed)
+	plan, err := NewSeeded(seed).GeneratePlanWithOptions(sc.date, sc.tasks, sc.dayStart, sc.dayEnd, sc.opts)
+	if err != nil {
+		t.Fatalf("seed %d: GeneratePlanWithOptions failed: %v", seed, err)
+	}
+	checkPlan(t, sc, plan)
+
+	again, err := NewSeeded(seed).GeneratePlanWithOptions(sc.date, sc.tasks, sc.dayStart, sc.dayEnd, sc.opts)
+	if err != nil {
+		t.Fatalf("seed %d: GeneratePlanWithOptions failed: %v", seed, err)
+	}
+	if !reflect.DeepEqual(plan.Slots, again.Slots) {
+		t.Errorf("seed %d: plans differ\nfirst:  %+v\nsecond: %+v", seed, plan.Slots, again.Slots)
+	}
+}
+
+func TestGeneratePlan_RandomDaysKeepInvariants(t *testing.T) {
+	for seed := range uint64(500) {
+		checkSeed(t, seed)
+		if t.Failed() {
+			t.Fatalf("seed %d broke an invariant", seed)
+		}
+	}
+}
+
+func TestNewSeeded_BreaksTiesBySeed(t *testing.T) {
+	// Equal tasks that can't all fit, so which ones are placed is down to the tie break
+	var tasks []models.Task
+	for i := range 8 {
+		tasks = append(tasks, models.Task{
+			ID:          fmt.Sprintf("task-%d", i),
+			Kind:        constants.TaskKindFlexible,
+			DurationMin: 60,
+			Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+			Active:      true,
+		})
+	}
+	first := func(s *Scheduler) string {
+		plan, err := s.GeneratePlan("2025-03-10", tasks, "09:00", "11:00")
+		if err != nil {
+			t.Fatalf("GeneratePlan failed: %v", err)
+		}
+		return plan.Slots[0].TaskID
+	}
+
+	// Unseeded, ties keep the order of the task list
+	if got := first(New()); got != "task-0" {
+		t.Errorf("expected task-0 first without a seed, got %s", got)
+	}
+
+	seen := make(map[string]bool)
+	for seed := range uint64(20) {
+		seen[first(NewSeeded(seed))] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("expected different seeds to break ties differently, got %v", seen)
+	}
+}
+
+func FuzzGeneratePlan(f *testing.F) {
+	for _, seed := range []uint64{0, 1, 42, 2025} {
+		f.Add(seed)
+	}
+	f.Fuzz(checkSeed)
+}
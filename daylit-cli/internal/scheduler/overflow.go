@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// Overflow returns the nice-to-have tasks due on date that the plan left
+// out, most important first. These are the tasks that consciously didn't
+// make the cut because the rest of the day took the time.
+func Overflow(plan models.DayPlan, tasks []models.Task, date time.Time) []models.Task {
+	planned := make(map[string]bool, len(plan.Slots))
+	for _, slot := range plan.Slots {
+		planned[slot.TaskID] = true
+	}
+
+	var overflow []models.Task
+	for _, task := range tasks {
+		if task.NiceToHave && task.Active && !planned[task.ID] && shouldScheduleTask(task, date) {
+			overflow = append(overflow, task)
+		}
+	}
+	sort.SliceStable(overflow, func(i, j int) bool {
+		if overflow[i].Priority != overflow[j].Priority {
+			return overflow[i].Priority < overflow[j].Priority
+		}
+		return overflow[i].Name < overflow[j].Name
+	})
+	return overflow
+}
@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestGeneratePlan_NiceToHaveFillsLeftoverTime(t *testing.T) {
+	s := New()
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	tasks := []models.Task{
+		// Listed and prioritized first, but only nice to have
+		{ID: "guitar", Name: "Guitar", Kind: constants.TaskKindFlexible, DurationMin: 60, Recurrence: daily, Priority: 1, Active: true, NiceToHave: true},
+		{ID: "report", Name: "Report", Kind: constants.TaskKindFlexible, DurationMin: 90, Recurrence: daily, Priority: 3, Active: true},
+		{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: daily, Priority: 4, Active: true},
+		{ID: "sketch", Name: "Sketch", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: daily, Priority: 2, Active: true, NiceToHave: true},
+	}
+
+	// Three hours: room for the mandatory tasks and one nice-to-have
+	plan, err := s.GeneratePlan("2026-11-02", tasks, "09:00", "12:00")
+	if err != nil {
+		t.Fatalf("GeneratePlan failed: %v", err)
+	}
+	planned := make(map[string]bool)
+	for _, slot := range plan.Slots {
+		planned[slot.TaskID] = true
+	}
+	if !planned["report"] || !planned["email"] {
+		t.Errorf("expected mandatory tasks to be planned, got %v", planned)
+	}
+	if !planned["guitar"] {
+		t.Errorf("expected the most important nice-to-have to fill the leftover hour, got %v", planned)
+	}
+	if planned["sketch"] {
+		t.Errorf("expected no room for the second nice-to-have, got %v", planned)
+	}
+
+	overflow := Overflow(plan, tasks, time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC))
+	if len(overflow) != 1 || overflow[0].ID != "sketch" {
+		t.Errorf("expected Sketch in the overflow, got %v", overflow)
+	}
+}
+
+func TestGeneratePlanWithOptions_CapacityDropsNiceToHaveFirst(t *testing.T) {
+	s := New()
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	tasks := []models.Task{
+		{ID: "extra", Name: "Extra", Kind: constants.TaskKindFlexible, DurationMin: 60, Recurrence: daily, Priority: 1, Active: true, NiceToHave: true},
+		{ID: "core", Name: "Core", Kind: constants.TaskKindFlexible, DurationMin: 60, Recurrence: daily, Priority: 5, Active: true},
+	}
+
+	plan, err := s.GeneratePlanWithOptions("2026-11-02", tasks, "09:00", "17:00", PlanOptions{Capacity: 50})
+	if err != nil {
+		t.Fatalf("GeneratePlanWithOptions failed: %v", err)
+	}
+	if len(plan.Slots) != 1 || plan.Slots[0].TaskID != "core" {
+		t.Errorf("expected only the mandatory task at half capacity, got %v", plan.Slots)
+	}
+}
+
+func TestOverflow_SkipsTasksNotDue(t *testing.T) {
+	tasks := []models.Task{
+		{ID: "1", Name: "Weekly", Kind: constants.TaskKindFlexible, DurationMin: 30, Active: true, NiceToHave: true,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceWeekly, WeekdayMask: []time.Weekday{time.Friday}}},
+		{ID: "2", Name: "Paused", Kind: constants.TaskKindFlexible, DurationMin: 30, Active: false, NiceToHave: true,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}},
+		{ID: "3", Name: "Mandatory", Kind: constants.TaskKindFlexible, DurationMin: 30, Active: true,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}},
+	}
+
+	// 2026-11-02 is a Monday
+	if overflow := Overflow(models.DayPlan{}, tasks, time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)); len(overflow) != 0 {
+		t.Errorf("expected no overflow, got %v", overflow)
+	}
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// paddedRange stretches a block of task from start to end by the task's
+// prep and travel time, no further than the ends of the day
+func paddedRange(task models.Task, start, end int, window models.DayWindow) (int, int) {
+	before, after := task.Padding()
+	return max(start-before, min(start, window.Start)), min(end+after, max(end, window.End))
+}
+
+// paddedSlots returns copies of the slots covering their appointments' prep
+// and travel time as well, sorted by start, so no other task is placed in it
+func paddedSlots(slots []models.Slot, tasks []models.Task, window models.DayWindow) []models.Slot {
+	byID := make(map[string]models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	padded := make([]models.Slot, 0, len(slots))
+	for _, slot := range slots {
+		if start, end, err := window.Range(slot.Start, slot.End); err == nil {
+			start, end = paddedRange(byID[slot.TaskID], start, end, window)
+			slot.Start, slot.End = formatTime(start), formatTime(end)
+		}
+		padded = append(padded, slot)
+	}
+	window.SortSlots(padded)
+	return padded
+}
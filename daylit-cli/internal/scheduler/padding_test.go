@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func paddingTestTasks() []models.Task {
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	return []models.Task{
+		{ID: "dentist", Name: "Dentist", Kind: constants.TaskKindAppointment, DurationMin: 60, FixedStart: "10:00", FixedEnd: "11:00", Recurrence: daily, Priority: 1, Active: true, PrepMin: 15, TravelMin: 30},
+		{ID: "write", Name: "Write", Kind: constants.TaskKindFlexible, DurationMin: 60, Recurrence: daily, Priority: 1, Active: true},
+		{ID: "read", Name: "Read", Kind: constants.TaskKindFlexible, DurationMin: 90, Recurrence: daily, Priority: 2, Active: true},
+		{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: daily, Priority: 3, Active: true},
+	}
+}
+
+func TestGeneratePlanWithOptions_AppointmentPadding(t *testing.T) {
+	plan, err := New().GeneratePlanWithOptions("2026-11-02", paddingTestTasks(), "08:00", "18:00", PlanOptions{})
+	if err != nil {
+		t.Fatalf("GeneratePlanWithOptions failed: %v", err)
+	}
+
+	// 09:15-10:00 is prep and travel and 11:00-11:30 the way back, so Email
+	// doesn't fit in the 15 minutes left before them
+	want := map[string]string{"dentist": "10:00", "write": "08:00", "read": "11:30", "email": "13:00"}
+	got := make(map[string]string)
+	for _, slot := range plan.Slots {
+		got[slot.TaskID] = slot.Start
+	}
+	for id, start := range want {
+		if got[id] != start {
+			t.Errorf("%s starts at %q, want %q (plan %+v)", id, got[id], start, plan.Slots)
+		}
+	}
+}
+
+func TestPaddedSlots(t *testing.T) {
+	window, err := models.ParseDayWindow("09:30", "18:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slots := []models.Slot{
+		{Start: "12:00", End: "13:00", TaskID: "write"},
+		{Start: "10:00", End: "11:00", TaskID: "dentist"},
+	}
+
+	// The padding stops at the start of the day, slots without any keep their
+	// times, and the result is in day order
+	padded := paddedSlots(slots, paddingTestTasks(), window)
+	if padded[0].Start != "09:30" || padded[0].End != "11:30" {
+		t.Errorf("dentist = %s-%s, want 09:30-11:30", padded[0].Start, padded[0].End)
+	}
+	if padded[1].Start != "12:00" || padded[1].End != "13:00" {
+		t.Errorf("write = %s-%s, want 12:00-13:00", padded[1].Start, padded[1].End)
+	}
+	if slots[1].Start != "10:00" || slots[1].TaskID != "dentist" {
+		t.Error("paddedSlots changed the slots it was given")
+	}
+}
+
+func TestReflow_KeepsAppointmentPadding(t *testing.T) {
+	slots := []ReflowSlot{
+		{Slot: models.Slot{Start: "10:00", End: "11:00", TaskID: "dentist"}},
+		{Slot: models.Slot{Start: "08:00", End: "09:00", TaskID: "write"}, NotBefore: "09:00"},
+	}
+	placed, dropped, err := New().Reflow(slots, paddingTestTasks(), "08:00", "18:00")
+	if err != nil {
+		t.Fatalf("Reflow failed: %v", err)
+	}
+	if len(dropped) != 0 || len(placed) != 2 {
+		t.Fatalf("placed %+v, dropped %+v", placed, dropped)
+	}
+	// Write would fit at 09:00 if it weren't for the prep and travel time
+	if got := placed[1].Slot; got.TaskID != "write" || got.Start != "11:30" {
+		t.Errorf("write placed at %s-%s, want 11:30", got.Start, got.End)
+	}
+}
@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// ChoosePoolMembers leaves out all but one member of each pool among the
+// tasks due on date, so only one of them is scheduled that day. Round-robin
+// pools take turns in name order, one member a day, and least-recent pools
+// pick the member done longest ago, never-done members first. The choice
+// depends only on the tasks and the date, so planning a day again picks the
+// same member. Tasks in a pool that no longer exists are kept.
+func ChoosePoolMembers(tasks []models.Task, pools []models.TaskPool, date time.Time) []models.Task {
+	poolByID := make(map[string]models.TaskPool, len(pools))
+	for _, pool := range pools {
+		poolByID[pool.ID] = pool
+	}
+
+	members := make(map[string][]models.Task)
+	for _, task := range tasks {
+		if _, ok := poolByID[task.PoolID]; ok && task.Active && shouldScheduleTask(task, date) {
+			members[task.PoolID] = append(members[task.PoolID], task)
+		}
+	}
+
+	chosen := make(map[string]string, len(members))
+	for poolID, due := range members {
+		chosen[poolID] = pickPoolMember(poolByID[poolID].Strategy, due, date).ID
+	}
+
+	var kept []models.Task
+	for _, task := range tasks {
+		if _, ok := poolByID[task.PoolID]; ok && chosen[task.PoolID] != task.ID {
+			continue
+		}
+		kept = append(kept, task)
+	}
+	return kept
+}
+
+// pickPoolMember picks the member of a pool to schedule on date from the
+// members due that day
+func pickPoolMember(strategy constants.PoolStrategy, due []models.Task, date time.Time) models.Task {
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].Name != due[j].Name {
+			return due[i].Name < due[j].Name
+		}
+		return due[i].ID < due[j].ID
+	})
+
+	if strategy == constants.PoolStrategyLeastRecent {
+		// LastDone is YYYY-MM-DD, so never done ("") sorts first
+		sort.SliceStable(due, func(i, j int) bool {
+			return due[i].LastDone < due[j].LastDone
+		})
+		return due[0]
+	}
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60)
+	return due[int(day%int64(len(due)))]
+}
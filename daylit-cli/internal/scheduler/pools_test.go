@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func poolTask(id, name, poolID, lastDone string) models.Task {
+	return models.Task{
+		ID: id, Name: name, Kind: constants.TaskKindFlexible, DurationMin: 30,
+		Recurrence: models.Recurrence{Type: constants.RecurrenceDaily},
+		Priority:   3, Active: true, PoolID: poolID, LastDone: lastDone,
+	}
+}
+
+func chosenNames(tasks []models.Task) map[string]bool {
+	names := make(map[string]bool)
+	for _, t := range tasks {
+		names[t.Name] = true
+	}
+	return names
+}
+
+func TestChoosePoolMembers_RoundRobin(t *testing.T) {
+	pools := []models.TaskPool{{ID: "workout", Name: "workout", Strategy: constants.PoolStrategyRoundRobin}}
+	tasks := []models.Task{
+		poolTask("1", "Run", "workout", ""),
+		poolTask("2", "Swim", "workout", ""),
+		poolTask("3", "Bike", "workout", ""),
+		poolTask("4", "Read", "", ""),
+	}
+
+	day := time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		kept := ChoosePoolMembers(tasks, pools, day.AddDate(0, 0, i))
+		if len(kept) != 2 {
+			t.Fatalf("day %d: expected the pick and the task outside the pool, got %d tasks", i, len(kept))
+		}
+		names := chosenNames(kept)
+		if !names["Read"] {
+			t.Errorf("day %d: task outside the pool was dropped", i)
+		}
+		for _, task := range kept {
+			if task.PoolID == "workout" {
+				seen[task.Name] = true
+			}
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("expected each member to take a turn over three days, got %v", seen)
+	}
+
+	// The same day always gets the same pick
+	first := ChoosePoolMembers(tasks, pools, day)
+	again := ChoosePoolMembers(tasks, pools, day)
+	if first[0].ID != again[0].ID {
+		t.Errorf("expected a stable pick for a day, got %s then %s", first[0].Name, again[0].Name)
+	}
+}
+
+func TestChoosePoolMembers_LeastRecent(t *testing.T) {
+	pools := []models.TaskPool{{ID: "workout", Name: "workout", Strategy: constants.PoolStrategyLeastRecent}}
+	tasks := []models.Task{
+		poolTask("1", "Run", "workout", "2026-11-01"),
+		poolTask("2", "Swim", "workout", "2026-10-28"),
+		poolTask("3", "Bike", "workout", "2026-10-30"),
+	}
+
+	kept := ChoosePoolMembers(tasks, pools, time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC))
+	if len(kept) != 1 || kept[0].Name != "Swim" {
+		t.Fatalf("expected Swim, done longest ago, got %v", chosenNames(kept))
+	}
+
+	// A member never done goes first
+	tasks = append(tasks, poolTask("4", "Climb", "workout", ""))
+	kept = ChoosePoolMembers(tasks, pools, time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC))
+	if len(kept) != 1 || kept[0].Name != "Climb" {
+		t.Fatalf("expected Climb, never done, got %v", chosenNames(kept))
+	}
+}
+
+func TestChoosePoolMembers_OnlyDueMembersCompete(t *testing.T) {
+	pools := []models.TaskPool{{ID: "workout", Name: "workout", Strategy: constants.PoolStrategyLeastRecent}}
+	swim := poolTask("2", "Swim", "workout", "")
+	swim.Recurrence = models.Recurrence{Type: constants.RecurrenceWeekly, WeekdayMask: []time.Weekday{time.Saturday}}
+	tasks := []models.Task{
+		poolTask("1", "Run", "workout", "2026-11-01"),
+		swim,
+		poolTask("3", "Yoga", "gone", ""), // pool was deleted
+	}
+
+	// Monday: Swim isn't due, so Run is picked even though Swim was never done
+	kept := ChoosePoolMembers(tasks, pools, time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC))
+	names := chosenNames(kept)
+	if len(kept) != 2 || !names["Run"] || !names["Yoga"] {
+		t.Fatalf("expected Run and Yoga, got %v", names)
+	}
+}
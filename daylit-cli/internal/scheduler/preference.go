@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// Preference is the time of day a task is best placed in, such as the hours
+// it has been done most reliably
+type Preference struct {
+	Start string // HH:MM
+	End   string // HH:MM
+}
+
+// placePreferred places task in the first free block where it can start
+// within its preferred time, keeping its earliest start and latest end. It
+// returns the slot and the index of the block it was placed in, or false if
+// the task doesn't fit there.
+func placePreferred(task models.Task, pref Preference, blocks []timeBlock, window models.DayWindow) (models.Slot, int, bool) {
+	prefStart, prefEnd, err := window.Range(pref.Start, pref.End)
+	if err != nil {
+		return models.Slot{}, -1, false
+	}
+
+	for i, block := range blocks {
+		if block.end <= prefStart || block.start >= prefEnd {
+			continue
+		}
+		// Only the part of the block from the preferred start on is used
+		within := timeBlock{start: max(block.start, prefStart), end: block.end}
+		if !canScheduleInBlock(task, within, window) {
+			continue
+		}
+		slot, ok := placeTaskInBlock(task, within, window)
+		if !ok {
+			continue
+		}
+		// An earliest start can push the task past its preferred time
+		if start, err := window.Minutes(slot.Start); err != nil || start >= prefEnd {
+			continue
+		}
+		return slot, i, true
+	}
+	return models.Slot{}, -1, false
+}
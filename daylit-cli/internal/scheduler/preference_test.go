@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestGeneratePlanWithOptions_Preferred(t *testing.T) {
+	s := New()
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	tasks := []models.Task{
+		{ID: "report", Name: "Report", Kind: constants.TaskKindFlexible, DurationMin: 60, Recurrence: daily, Priority: 1, Active: true},
+		{ID: "journal", Name: "Journal", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: daily, Priority: 2, Active: true},
+		{ID: "walk", Name: "Walk", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: daily, Priority: 3, Active: true, EarliestStart: "16:00"},
+	}
+
+	tests := []struct {
+		name      string
+		preferred map[string]Preference
+		want      map[string]string // Task ID to slot start
+	}{
+		{
+			name: "no preferences",
+			want: map[string]string{"report": "07:00", "journal": "08:00", "walk": "16:00"},
+		},
+		{
+			name: "preferred evening",
+			preferred: map[string]Preference{
+				"journal": {Start: "18:00", End: "21:00"},
+			},
+			want: map[string]string{"report": "07:00", "journal": "18:00", "walk": "16:00"},
+		},
+		{
+			name: "preferred time taken falls back",
+			preferred: map[string]Preference{
+				"journal": {Start: "06:00", End: "08:00"},
+			},
+			want: map[string]string{"report": "07:00", "journal": "08:00", "walk": "16:00"},
+		},
+		{
+			name: "earliest start past the preferred time falls back",
+			preferred: map[string]Preference{
+				"walk": {Start: "09:00", End: "12:00"},
+			},
+			want: map[string]string{"report": "07:00", "journal": "08:00", "walk": "16:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := s.GeneratePlanWithOptions("2026-11-02", tasks, "07:00", "22:00", PlanOptions{Preferred: tt.preferred})
+			if err != nil {
+				t.Fatalf("GeneratePlanWithOptions failed: %v", err)
+			}
+			got := make(map[string]string)
+			for _, slot := range plan.Slots {
+				got[slot.TaskID] = slot.Start
+			}
+			for id, start := range tt.want {
+				if got[id] != start {
+					t.Errorf("%s starts at %q, want %q (plan %v)", id, got[id], start, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// demandingBlocks returns the times of the fixed slots whose tasks need
+// recovery after them
+func demandingBlocks(slots []models.Slot, tasks []models.Task, window models.DayWindow, rec models.Recovery) []timeBlock {
+	if !rec.Enabled() {
+		return nil
+	}
+	byID := make(map[string]models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	var blocks []timeBlock
+	for _, slot := range slots {
+		blocks = addDemanding(blocks, byID[slot.TaskID], slot, window, rec)
+	}
+	return blocks
+}
+
+// addDemanding adds slot to the demanding blocks if task needs recovery
+// after it
+func addDemanding(blocks []timeBlock, task models.Task, slot models.Slot, window models.DayWindow, rec models.Recovery) []timeBlock {
+	if !rec.Enabled() {
+		return blocks
+	}
+	start, end, err := window.Range(slot.Start, slot.End)
+	if err != nil || !rec.Demanding(task, end-start) {
+		return blocks
+	}
+	return append(blocks, timeBlock{start: start, end: end})
+}
+
+// tooClose reports whether slot of a demanding task leaves less than the
+// recovery gap to one of the demanding blocks
+func tooClose(task models.Task, slot models.Slot, window models.DayWindow, rec models.Recovery, demanding []timeBlock) bool {
+	_, ok := closeTo(task, slot, window, rec, demanding)
+	return ok
+}
+
+// closeTo is tooClose, also returning the end of the block slot is too close
+// to
+func closeTo(task models.Task, slot models.Slot, window models.DayWindow, rec models.Recovery, demanding []timeBlock) (int, bool) {
+	if !rec.Enabled() {
+		return 0, false
+	}
+	start, end, err := window.Range(slot.Start, slot.End)
+	if err != nil || !rec.Demanding(task, end-start) {
+		return 0, false
+	}
+	for _, d := range demanding {
+		if rec.TooClose(start, end, d.start, d.end) {
+			return d.end, true
+		}
+	}
+	return 0, false
+}
+
+// placeRested places task in block like placeTaskInBlock, moving a
+// demanding task later until it is a recovery gap away from the other
+// demanding blocks. The part of the block it skips stays free for lighter
+// tasks.
+func placeRested(task models.Task, block timeBlock, window models.DayWindow, rec models.Recovery, demanding []timeBlock) (models.Slot, bool) {
+	for {
+		slot, ok := placeTaskInBlock(task, block, window)
+		if !ok {
+			return slot, false
+		}
+		end, ok := closeTo(task, slot, window, rec, demanding)
+		if !ok {
+			return slot, true
+		}
+		// The slot starts before end plus the gap, so this always moves on
+		block.start = end + rec.GapMinutes
+	}
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestGeneratePlanWithOptions_Recovery(t *testing.T) {
+	s := New()
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	gym := models.Task{ID: "gym", Name: "Gym", Kind: constants.TaskKindFlexible, DurationMin: 60, Recurrence: daily, Priority: 1, Active: true, EnergyBand: constants.EnergyHigh}
+	code := models.Task{ID: "code", Name: "Code", Kind: constants.TaskKindFlexible, DurationMin: 120, Recurrence: daily, Priority: 2, Active: true}
+	email := models.Task{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: daily, Priority: 3, Active: true}
+	review := models.Task{ID: "review", Name: "Review", Kind: constants.TaskKindAppointment, DurationMin: 60, FixedStart: "07:00", FixedEnd: "08:00", Recurrence: daily, Priority: 3, Active: true, EnergyBand: constants.EnergyHigh}
+
+	tests := []struct {
+		name     string
+		tasks    []models.Task
+		recovery models.Recovery
+		want     map[string]string // Task ID to slot start
+	}{
+		{
+			name:  "off",
+			tasks: []models.Task{gym, code, email},
+			want:  map[string]string{"gym": "07:00", "code": "08:00", "email": "10:00"},
+		},
+		{
+			name:     "only high-energy tasks are demanding",
+			tasks:    []models.Task{gym, code, email},
+			recovery: models.Recovery{GapMinutes: 30},
+			want:     map[string]string{"gym": "07:00", "code": "08:00", "email": "10:00"},
+		},
+		{
+			name:     "long tasks wait for the gap and lighter ones fill it",
+			tasks:    []models.Task{gym, code, email},
+			recovery: models.Recovery{GapMinutes: 30, LongerThan: 90},
+			want:     map[string]string{"gym": "07:00", "code": "08:30", "email": "08:00"},
+		},
+		{
+			name:     "appointments count and stay in place",
+			tasks:    []models.Task{review, gym, email},
+			recovery: models.Recovery{GapMinutes: 45},
+			want:     map[string]string{"review": "07:00", "gym": "08:45", "email": "08:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := s.GeneratePlanWithOptions("2026-11-02", tt.tasks, "07:00", "22:00", PlanOptions{Recovery: tt.recovery})
+			if err != nil {
+				t.Fatalf("GeneratePlanWithOptions failed: %v", err)
+			}
+			got := make(map[string]string)
+			for _, slot := range plan.Slots {
+				got[slot.TaskID] = slot.Start
+			}
+			for id, start := range tt.want {
+				if got[id] != start {
+					t.Errorf("%s starts at %q, want %q (plan %v)", id, got[id], start, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// ReflowSlot is a slot to be placed again by Reflow
+type ReflowSlot struct {
+	Slot models.Slot
+	// NotBefore is the earliest start (HH:MM) for the slot. When empty the
+	// slot may start as early as its task allows.
+	NotBefore string
+	// Pinned slots keep their times, like appointments
+	Pinned bool
+}
+
+// Reflow places the slots back to back in the given order, keeping each
+// slot's length. Appointments and pinned slots keep their times and the other slots
+// flow around them and the appointments' prep and travel time, starting no earlier than their NotBefore or their task's
+// earliest start. Slots that no longer end by their task's latest end or the
+// end of the day are returned as dropped. Placed slots are sorted by start.
+func (s *Scheduler) Reflow(slots []ReflowSlot, tasks []models.Task, dayStart, dayEnd string) (placed, dropped []ReflowSlot, err error) {
+	window, err := models.ParseDayWindow(dayStart, dayEnd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	taskByID := make(map[string]models.Task, len(tasks))
+	for _, task := range tasks {
+		taskByID[task.ID] = task
+	}
+
+	// Appointments stay where they are; everything else flows around them
+	var fixed []timeBlock
+	var flexible []ReflowSlot
+	for _, rs := range slots {
+		task := taskByID[rs.Slot.TaskID]
+		if rs.Pinned || IsFixedAppointment(task) {
+			start, end, err := window.Range(rs.Slot.Start, rs.Slot.End)
+			if err != nil {
+				return nil, nil, err
+			}
+			// Nothing flows into an appointment's prep and travel time
+			start, end = paddedRange(task, start, end, window)
+			fixed = append(fixed, timeBlock{start: start, end: end})
+			placed = append(placed, rs)
+			continue
+		}
+		flexible = append(flexible, rs)
+	}
+
+	cursor := window.Start
+	for _, rs := range flexible {
+		start, end, err := window.Range(rs.Slot.Start, rs.Slot.End)
+		if err != nil {
+			return nil, nil, err
+		}
+		length := end - start
+
+		earliest := cursor
+		task := taskByID[rs.Slot.TaskID]
+		for _, bound := range []string{rs.NotBefore, task.EarliestStart} {
+			if bound == "" {
+				continue
+			}
+			if m, err := window.Minutes(bound); err == nil && m > earliest {
+				earliest = m
+			}
+		}
+
+		start = earliestFreeStart(earliest, length, fixed)
+		end = start + length
+
+		latest := window.End
+		if task.LatestEnd != "" {
+			if m, err := window.Minutes(task.LatestEnd); err == nil && m < latest {
+				latest = m
+			}
+		}
+		if end > latest {
+			dropped = append(dropped, rs)
+			continue
+		}
+
+		rs.Slot.Start = formatTime(start)
+		rs.Slot.End = formatTime(end)
+		placed = append(placed, rs)
+		cursor = end
+	}
+
+	sortReflowSlots(window, placed)
+	return placed, dropped, nil
+}
+
+// Shift moves the rest of plan's day after slot i actually ended at end, or
+// is still running at end. Each later slot moves by the difference between
+// end and slot i's planned end, earlier when it finished early and later
+// when it ran long, keeping the gaps between them where it can. Appointments,
+// done slots and slots before the plan's lock boundary keep their times and
+// the moved slots flow around them as in Reflow, never starting before end or
+// the lock boundary. Slot i itself ends at end in the result, so shifting the
+// result again moves nothing. Slots that no longer fit in the day are
+// returned as dropped. The plan's slots must be in day order (see
+// DayWindow.SortSlots).
+func (s *Scheduler) Shift(plan models.DayPlan, i int, end string, tasks []models.Task, dayStart, dayEnd string) (shifted, dropped []models.Slot, err error) {
+	slots := plan.Slots
+	if i < 0 || i >= len(slots) {
+		return nil, nil, fmt.Errorf("slot %d out of range", i)
+	}
+	window, err := models.ParseDayWindow(dayStart, dayEnd)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, plannedEnd, err := window.Range(slots[i].Start, slots[i].End)
+	if err != nil {
+		return nil, nil, err
+	}
+	actualEnd, err := window.Minutes(end)
+	if err != nil {
+		return nil, nil, err
+	}
+	delta := actualEnd - plannedEnd
+	lockEnd := window.Start
+	if plan.LockedUntil != "" {
+		if lockEnd, err = window.Minutes(plan.LockedUntil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	taskByID := make(map[string]models.Task, len(tasks))
+	for _, task := range tasks {
+		taskByID[task.ID] = task
+	}
+
+	var rest []ReflowSlot
+	for _, slot := range slots[i+1:] {
+		rs := ReflowSlot{Slot: slot, Pinned: slot.Status == constants.SlotStatusDone || plan.Locked(slot, window)}
+		if !rs.Pinned && !IsFixedAppointment(taskByID[slot.TaskID]) {
+			start, err := window.Minutes(slot.Start)
+			if err != nil {
+				return nil, nil, err
+			}
+			rs.NotBefore = formatTime(max(start+delta, actualEnd, lockEnd))
+			// The slot is due to be notified again at its new times
+			rs.Slot.LastNotifiedStart = nil
+			rs.Slot.LastNotifiedEnd = nil
+		}
+		rest = append(rest, rs)
+	}
+
+	placed, droppedRest, err := s.Reflow(rest, tasks, dayStart, dayEnd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shifted = append(shifted, slots[:i+1]...)
+	shifted[i].End = end
+	for _, rs := range placed {
+		shifted = append(shifted, rs.Slot)
+	}
+	for _, rs := range droppedRest {
+		dropped = append(dropped, rs.Slot)
+	}
+	return shifted, dropped, nil
+}
+
+// IsFixedAppointment reports whether the scheduler places task at fixed times
+func IsFixedAppointment(task models.Task) bool {
+	return task.Kind == constants.TaskKindAppointment && task.FixedStart != "" && task.FixedEnd != ""
+}
+
+// earliestFreeStart returns the first start at or after from where a block
+// of length minutes doesn't overlap any of the fixed blocks
+func earliestFreeStart(from, length int, fixed []timeBlock) int {
+	start := from
+	for moved := true; moved; {
+		moved = false
+		for _, b := range fixed {
+			if start < b.end && b.start < start+length {
+				start = b.end
+				moved = true
+			}
+		}
+	}
+	return start
+}
+
+// sortReflowSlots orders slots by their start on the plan day
+func sortReflowSlots(window models.DayWindow, slots []ReflowSlot) {
+	sort.SliceStable(slots, func(i, j int) bool {
+		si, _ := window.Minutes(slots[i].Slot.Start)
+		sj, _ := window.Minutes(slots[j].Slot.Start)
+		return si < sj
+	})
+}
@@ -0,0 +1,193 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func reflowTestTasks() []models.Task {
+	return []models.Task{
+		{ID: "write", Name: "Write", Kind: constants.TaskKindFlexible, DurationMin: 60},
+		{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30},
+		{ID: "lunch", Name: "Lunch", Kind: constants.TaskKindAppointment, FixedStart: "10:00", FixedEnd: "11:00"},
+		{ID: "walk", Name: "Walk", Kind: constants.TaskKindFlexible, DurationMin: 45, EarliestStart: "11:30", LatestEnd: "13:00"},
+	}
+}
+
+func TestReflow(t *testing.T) {
+	scheduler := New()
+	tasks := reflowTestTasks()
+
+	slots := []ReflowSlot{
+		{Slot: models.Slot{Start: "09:30", End: "10:00", TaskID: "email"}},
+		{Slot: models.Slot{Start: "10:00", End: "11:00", TaskID: "lunch"}},
+		{Slot: models.Slot{Start: "08:00", End: "09:00", TaskID: "write"}, NotBefore: "09:30"},
+		{Slot: models.Slot{Start: "11:30", End: "12:15", TaskID: "walk"}},
+	}
+
+	placed, dropped, err := scheduler.Reflow(slots, tasks, "08:00", "18:00")
+	if err != nil {
+		t.Fatalf("Reflow failed: %v", err)
+	}
+	if len(dropped) != 0 {
+		t.Errorf("expected nothing dropped, got %+v", dropped)
+	}
+
+	// Email moves to the start of the day; Write can't start before 09:30
+	// and doesn't fit before lunch, so it follows it; Walk waits for its
+	// earliest start
+	want := []models.Slot{
+		{Start: "08:00", End: "08:30", TaskID: "email"},
+		{Start: "10:00", End: "11:00", TaskID: "lunch"},
+		{Start: "11:00", End: "12:00", TaskID: "write"},
+		{Start: "12:00", End: "12:45", TaskID: "walk"},
+	}
+	if len(placed) != len(want) {
+		t.Fatalf("placed %d slots, want %d: %+v", len(placed), len(want), placed)
+	}
+	for i, w := range want {
+		got := placed[i].Slot
+		if got.Start != w.Start || got.End != w.End || got.TaskID != w.TaskID {
+			t.Errorf("slot %d = %s-%s %s, want %s-%s %s", i, got.Start, got.End, got.TaskID, w.Start, w.End, w.TaskID)
+		}
+	}
+}
+
+func TestReflow_DropsSlotsThatNoLongerFit(t *testing.T) {
+	scheduler := New()
+	tasks := reflowTestTasks()
+
+	slots := []ReflowSlot{
+		{Slot: models.Slot{Start: "11:30", End: "12:15", TaskID: "walk"}, NotBefore: "12:30"},
+		{Slot: models.Slot{Start: "16:00", End: "17:00", TaskID: "write"}, NotBefore: "17:30"},
+		{Slot: models.Slot{Start: "09:00", End: "09:30", TaskID: "email"}},
+	}
+
+	placed, dropped, err := scheduler.Reflow(slots, tasks, "08:00", "18:00")
+	if err != nil {
+		t.Fatalf("Reflow failed: %v", err)
+	}
+	if len(dropped) != 2 || dropped[0].Slot.TaskID != "walk" || dropped[1].Slot.TaskID != "write" {
+		t.Errorf("expected walk (past its latest end) and write (past the day end) to be dropped, got %+v", dropped)
+	}
+	if len(placed) != 1 || placed[0].Slot.Start != "08:00" {
+		t.Errorf("expected email at 08:00, got %+v", placed)
+	}
+}
+
+func TestReflow_DayEndsAfterMidnight(t *testing.T) {
+	scheduler := New()
+	tasks := []models.Task{
+		{ID: "read", Name: "Read", Kind: constants.TaskKindFlexible, DurationMin: 60},
+		{ID: "film", Name: "Film", Kind: constants.TaskKindFlexible, DurationMin: 90},
+	}
+	slots := []ReflowSlot{
+		{Slot: models.Slot{Start: "23:00", End: "00:30", TaskID: "film"}, NotBefore: "23:00"},
+		{Slot: models.Slot{Start: "20:00", End: "21:00", TaskID: "read"}},
+	}
+
+	placed, dropped, err := scheduler.Reflow(slots, tasks, "18:00", "02:00")
+	if err != nil {
+		t.Fatalf("Reflow failed: %v", err)
+	}
+	if len(dropped) != 0 || len(placed) != 2 {
+		t.Fatalf("placed %+v, dropped %+v", placed, dropped)
+	}
+	if placed[0].Slot.TaskID != "film" || placed[1].Slot.Start != "00:30" || placed[1].Slot.End != "01:30" {
+		t.Errorf("expected read to follow the film past midnight, got %+v", placed)
+	}
+}
+
+func TestShift(t *testing.T) {
+	scheduler := New()
+	tasks := reflowTestTasks()
+
+	tests := []struct {
+		name        string
+		end         string
+		emailDone   bool
+		lockedUntil string
+		want        string
+		wantDropped string
+	}{
+		{
+			name: "finished early",
+			end:  "08:40",
+			// Walk can't start before 11:30
+			want: "08:00-08:40 write, 08:40-09:10 email, 10:00-11:00 lunch, 11:30-12:15 walk",
+		},
+		{
+			name: "ran long",
+			end:  "09:20",
+			want: "08:00-09:20 write, 09:20-09:50 email, 10:00-11:00 lunch, 11:50-12:35 walk",
+		},
+		{
+			name: "ran into an appointment",
+			end:  "09:45",
+			want: "08:00-09:45 write, 10:00-11:00 lunch, 11:00-11:30 email, 12:15-13:00 walk",
+		},
+		{
+			name:        "pushed past the latest end",
+			end:         "09:50",
+			want:        "08:00-09:50 write, 10:00-11:00 lunch, 11:00-11:30 email",
+			wantDropped: "walk",
+		},
+		{
+			name:      "done slots stay put",
+			end:       "08:40",
+			emailDone: true,
+			want:      "08:00-08:40 write, 09:00-09:30 email, 10:00-11:00 lunch, 11:30-12:15 walk",
+		},
+		{
+			name:        "locked slots stay put",
+			end:         "08:40",
+			lockedUntil: "09:30",
+			want:        "08:00-08:40 write, 09:00-09:30 email, 10:00-11:00 lunch, 11:30-12:15 walk",
+		},
+		{
+			name:        "nothing moves before the lock",
+			end:         "08:40",
+			lockedUntil: "08:50",
+			want:        "08:00-08:40 write, 08:50-09:20 email, 10:00-11:00 lunch, 11:30-12:15 walk",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots := []models.Slot{
+				{Start: "08:00", End: "09:00", TaskID: "write", Status: constants.SlotStatusDone},
+				{Start: "09:00", End: "09:30", TaskID: "email", Status: constants.SlotStatusAccepted},
+				{Start: "10:00", End: "11:00", TaskID: "lunch", Status: constants.SlotStatusAccepted},
+				{Start: "11:30", End: "12:15", TaskID: "walk", Status: constants.SlotStatusAccepted},
+			}
+			if tt.emailDone {
+				slots[1].Status = constants.SlotStatusDone
+			}
+
+			shifted, dropped, err := scheduler.Shift(models.DayPlan{Slots: slots, LockedUntil: tt.lockedUntil}, 0, tt.end, tasks, "08:00", "18:00")
+			if err != nil {
+				t.Fatalf("Shift failed: %v", err)
+			}
+
+			var got, gotDropped []string
+			for _, slot := range shifted {
+				got = append(got, slot.Start+"-"+slot.End+" "+slot.TaskID)
+			}
+			for _, slot := range dropped {
+				gotDropped = append(gotDropped, slot.TaskID)
+			}
+			if strings.Join(got, ", ") != tt.want {
+				t.Errorf("slots = %s\nwant    %s", strings.Join(got, ", "), tt.want)
+			}
+			if strings.Join(gotDropped, ", ") != tt.wantDropped {
+				t.Errorf("dropped = %v, want %s", gotDropped, tt.wantDropped)
+			}
+			if slots[0].End != "09:00" {
+				t.Error("Shift changed the slots it was given")
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package scheduler
 
 import (
 	"fmt"
+	"maps"
 	"math"
+	"math/rand/v2"
 	"sort"
 	"time"
 
@@ -11,17 +13,113 @@ import (
 	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
 )
 
-type Scheduler struct{}
+type Scheduler struct {
+	seed    uint64
+	seeded  bool
+	observe func(phase string, elapsed time.Duration)
+}
 
 func New() *Scheduler {
 	return &Scheduler{}
 }
 
+// NewSeeded returns a scheduler that breaks ties between flexible tasks of
+// equal priority and lateness at random rather than by their order in the
+// task list. The same seed and date always give the same plan.
+func NewSeeded(seed uint64) *Scheduler {
+	return &Scheduler{seed: seed, seeded: true}
+}
+
+// rand returns the random source for planning date, or nil if the scheduler
+// isn't seeded
+func (s *Scheduler) rand(date time.Time) *rand.Rand {
+	if !s.seeded {
+		return nil
+	}
+	return rand.New(rand.NewPCG(s.seed, uint64(date.Unix())))
+}
+
+// Observe makes the scheduler call fn with the name and duration of each
+// phase of planning a day, to find out which one is slow
+func (s *Scheduler) Observe(fn func(phase string, elapsed time.Duration)) {
+	s.observe = fn
+}
+
+// phase starts timing the named phase; call the returned func when it ends
+func (s *Scheduler) phase(name string) func() {
+	if s.observe == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { s.observe(name, time.Since(start)) }
+}
+
 // GeneratePlan creates a day plan for the given date
 func (s *Scheduler) GeneratePlan(date string, tasks []models.Task, dayStart, dayEnd string) (models.DayPlan, error) {
+	return s.GeneratePlanFromTemplate(date, tasks, dayStart, dayEnd, nil)
+}
+
+// GeneratePlanFromTemplate creates a day plan that keeps the slots of a day
+// template in place and fills the remaining gaps like GeneratePlan. Tasks
+// placed by the template are not scheduled again. A nil template generates a
+// plan from scratch.
+func (s *Scheduler) GeneratePlanFromTemplate(date string, tasks []models.Task, dayStart, dayEnd string, template *models.DayTemplate) (models.DayPlan, error) {
+	return s.GeneratePlanWithOptions(date, tasks, dayStart, dayEnd, PlanOptions{Template: template})
+}
+
+// PlanOptions adjusts how GeneratePlanWithOptions fills the day
+type PlanOptions struct {
+	// Template's slots are kept in place before filling the gaps; nil plans
+	// from scratch
+	Template *models.DayTemplate
+	// Capacity is the share of a normal day's flexible work to schedule, in
+	// percent. Zero or 100 schedules a full day.
+	Capacity int
+	// ShortenDurations scales flexible task durations down by Capacity too
+	ShortenDurations bool
+	// LockedUntil (HH:MM) keeps the day before it as it is: LockedSlots are
+	// kept unchanged, and no other slot is placed to start before it. Empty
+	// plans the whole day.
+	LockedUntil string
+	LockedSlots []models.Slot
+	// Preferred maps task IDs to the time of day they are placed in when
+	// they fit there; other tasks, and tasks that don't fit, take the first
+	// free time as usual
+	Preferred map[string]Preference
+	// Aging raises the priority of the tasks in it, which have been left
+	// out of the plan day after day (see AgeTasks)
+	Aging map[string]Aging
+	// Recovery keeps demanding flexible tasks apart; appointments, template
+	// and locked slots stay where they are
+	Recovery models.Recovery
+	// Explain, if set, is told what became of each task and why
+	Explain func(Decision)
+}
+
+// Decision explains what the scheduler did with one task
+type Decision struct {
+	TaskID string
+	Slot   *models.Slot // Where the task was placed; nil when it was left out
+	Reason string
+}
+
+// GeneratePlanWithOptions creates a day plan like GeneratePlanFromTemplate.
+// Below full capacity, appointments and template slots are kept but only the
+// highest-priority flexible tasks are scheduled, up to Capacity percent of the
+// flexible minutes a full day would hold. With a lock, the locked slots take
+// the place of their tasks and the rest of the day is planned after the lock
+// boundary.
+func (s *Scheduler) GeneratePlanWithOptions(date string, tasks []models.Task, dayStart, dayEnd string, opts PlanOptions) (models.DayPlan, error) {
+	template := opts.Template
+	explain := func(taskID string, slot *models.Slot, format string, args ...any) {
+		if opts.Explain != nil {
+			opts.Explain(Decision{TaskID: taskID, Slot: slot, Reason: fmt.Sprintf(format, args...)})
+		}
+	}
 	plan := models.DayPlan{
-		Date:  date,
-		Slots: []models.Slot{},
+		Date:        date,
+		Slots:       []models.Slot{},
+		LockedUntil: opts.LockedUntil,
 	}
 
 	// Parse date
@@ -30,26 +128,63 @@ func (s *Scheduler) GeneratePlan(date string, tasks []models.Task, dayStart, day
 		return plan, fmt.Errorf("invalid date format: %w", err)
 	}
 
-	// Parse day boundaries
-	startTime, err := utils.ParseTimeToMinutes(dayStart)
+	// Parse day boundaries; the day may end after midnight
+	window, err := models.ParseDayWindow(dayStart, dayEnd)
 	if err != nil {
-		return plan, fmt.Errorf("invalid day start time: %w", err)
+		return plan, err
 	}
-	endTime, err := utils.ParseTimeToMinutes(dayEnd)
-	if err != nil {
-		return plan, fmt.Errorf("invalid day end time: %w", err)
+
+	// Nothing new starts before the lock boundary
+	lockEnd := window.Start
+	if opts.LockedUntil != "" {
+		if lockEnd, err = window.Minutes(opts.LockedUntil); err != nil {
+			return plan, fmt.Errorf("invalid lock time: %w", err)
+		}
+	}
+	beforeLock := func(start string) bool {
+		m, err := window.Minutes(start)
+		return err == nil && m < lockEnd
+	}
+
+	// Step 0: Keep the locked and template slots, which take the place of
+	// their tasks
+	done := s.phase("place fixed slots")
+	var fixedSlots []models.Slot
+	placedTasks := make(map[string]bool)
+	for _, slot := range opts.LockedSlots {
+		fixedSlots = append(fixedSlots, slot)
+		placedTasks[slot.TaskID] = true
+		explain(slot.TaskID, &slot, "kept from the locked part of the day, before %s", opts.LockedUntil)
+	}
+	lockedTasks := maps.Clone(placedTasks)
+	if template != nil {
+		for _, slot := range template.Slots {
+			if beforeLock(slot.Start) || lockedTasks[slot.TaskID] {
+				continue
+			}
+			kept := models.Slot{
+				Start:  slot.Start,
+				End:    slot.End,
+				TaskID: slot.TaskID,
+				Status: constants.SlotStatusPlanned,
+			}
+			fixedSlots = append(fixedSlots, kept)
+			placedTasks[slot.TaskID] = true
+			explain(slot.TaskID, &kept, "kept from the %s template", template.Name)
+		}
 	}
 
 	// Filter active tasks
 	var activeTasks []models.Task
 	for _, task := range tasks {
-		if task.Active {
+		if task.Active && !placedTasks[task.ID] {
 			activeTasks = append(activeTasks, task)
+		} else if !task.Active {
+			explain(task.ID, nil, "inactive")
 		}
 	}
 
 	// Step 1: Place fixed appointments
-	var fixedSlots []models.Slot
 	var flexibleTasks []models.Task
 
 	for _, task := range activeTasks {
@@ -57,13 +192,20 @@ func (s *Scheduler) GeneratePlan(date string, tasks []models.Task, dayStart, day
 		case constants.TaskKindAppointment:
 			// Appointments must have both fixed start and end times
 			if task.FixedStart != "" && task.FixedEnd != "" {
-				if shouldScheduleTask(task, planDate) {
-					fixedSlots = append(fixedSlots, models.Slot{
+				switch {
+				case !shouldScheduleTask(task, planDate):
+					explain(task.ID, nil, "appointment not due on this day (%s)", task.Recurrence.Type)
+				case beforeLock(task.FixedStart):
+					explain(task.ID, nil, "appointment starts before the lock at %s", opts.LockedUntil)
+				default:
+					slot := models.Slot{
 						Start:  task.FixedStart,
 						End:    task.FixedEnd,
 						TaskID: task.ID,
 						Status: constants.SlotStatusPlanned,
-					})
+					}
+					fixedSlots = append(fixedSlots, slot)
+					explain(task.ID, &slot, "appointment at its fixed time")
 				}
 			} else {
 				// Treat incomplete appointments as flexible tasks
@@ -75,34 +217,63 @@ func (s *Scheduler) GeneratePlan(date string, tasks []models.Task, dayStart, day
 	}
 
 	// Sort fixed slots by start time
-	sort.Slice(fixedSlots, func(i, j int) bool {
-		return fixedSlots[i].Start < fixedSlots[j].Start
-	})
+	window.SortSlots(fixedSlots)
+	done()
 
 	// Step 2: Filter flexible tasks based on recurrence
+	done = s.phase("order flexible tasks")
 	var candidateTasks []models.Task
 	for _, task := range flexibleTasks {
 		if shouldScheduleTask(task, planDate) {
 			candidateTasks = append(candidateTasks, task)
+		} else {
+			explain(task.ID, nil, "not due on this day (%s)", task.Recurrence.Type)
 		}
 	}
 
-	// Step 3: Sort flexible tasks by priority and lateness
-	sort.Slice(candidateTasks, func(i, j int) bool {
-		// Lower priority number = higher priority
-		if candidateTasks[i].Priority != candidateTasks[j].Priority {
-			return candidateTasks[i].Priority < candidateTasks[j].Priority
+	// Step 3: Sort flexible tasks by priority and lateness, with nice-to-have
+	// tasks after all the others so they only get the time left over. Ties
+	// keep their order, which a seeded scheduler shuffles first.
+	if rng := s.rand(planDate); rng != nil {
+		rng.Shuffle(len(candidateTasks), func(i, j int) {
+			candidateTasks[i], candidateTasks[j] = candidateTasks[j], candidateTasks[i]
+		})
+	}
+	sort.SliceStable(candidateTasks, func(i, j int) bool {
+		if candidateTasks[i].NiceToHave != candidateTasks[j].NiceToHave {
+			return !candidateTasks[i].NiceToHave
+		}
+		// Lower priority number = higher priority, after aging
+		pi := opts.Aging[candidateTasks[i].ID].Priority(candidateTasks[i])
+		pj := opts.Aging[candidateTasks[j].ID].Priority(candidateTasks[j])
+		if pi != pj {
+			return pi < pj
 		}
 		// Then by lateness
 		return calculateLateness(candidateTasks[i], planDate) > calculateLateness(candidateTasks[j], planDate)
 	})
-
-	// Step 4: Find free blocks and schedule flexible tasks
-	freeBlocks := findFreeBlocks(startTime, endTime, fixedSlots)
+	done()
+
+	// Step 4: Find free blocks, outside the appointments' prep and travel
+	// time, and schedule flexible tasks
+	done = s.phase("place flexible tasks")
+	freeBlocks := afterLock(findFreeBlocks(window, paddedSlots(fixedSlots, tasks, window)), lockEnd)
+	if opts.Capacity > 0 && opts.Capacity < 100 {
+		freeMinutes := 0
+		for _, block := range freeBlocks {
+			freeMinutes += block.end - block.start
+		}
+		kept := fitCapacity(candidateTasks, freeMinutes, opts.Capacity, opts.ShortenDurations)
+		for _, task := range candidateTasks[len(kept):] {
+			explain(task.ID, nil, "left out to plan %d%% of a normal day; higher-priority tasks filled it", opts.Capacity)
+		}
+		candidateTasks = kept
+	}
 
 	scheduledSlots := make([]models.Slot, 0)
 	usedTasks := make(map[string]bool)
 	unscheduledTasks := make([]models.Task, 0)
+	demanding := demandingBlocks(fixedSlots, tasks, window, opts.Recovery)
 
 	// Try to place each task in any available block
 	for _, task := range candidateTasks {
@@ -110,39 +281,35 @@ func (s *Scheduler) GeneratePlan(date string, tasks []models.Task, dayStart, day
 			continue
 		}
 
+		if pref, ok := opts.Preferred[task.ID]; ok {
+			if slot, blockIdx, ok := placePreferred(task, pref, freeBlocks, window); ok && !tooClose(task, slot, window, opts.Recovery, demanding) {
+				explain(task.ID, &slot, "placed in its preferred time, %s–%s, learned from past feedback", pref.Start, pref.End)
+				scheduledSlots = append(scheduledSlots, slot)
+				usedTasks[task.ID] = true
+				freeBlocks = splitBlock(freeBlocks, blockIdx, slot, window)
+				demanding = addDemanding(demanding, task, slot, window, opts.Recovery)
+				continue
+			}
+		}
+
 		placed := false
 		for blockIdx := 0; blockIdx < len(freeBlocks); blockIdx++ {
 			block := freeBlocks[blockIdx]
 
 			// Check if task fits in time constraints
-			if !canScheduleInBlock(task, block) {
+			if !canScheduleInBlock(task, block, window) {
 				continue
 			}
 
 			// Try to place task
-			slot, ok := placeTaskInBlock(task, block)
+			slot, ok := placeRested(task, block, window, opts.Recovery, demanding)
 			if ok {
+				explain(task.ID, &slot, "placed in the first free time it fits, %s", placementOrder(task, opts.Aging[task.ID]))
 				scheduledSlots = append(scheduledSlots, slot)
 				usedTasks[task.ID] = true
 				placed = true
-
-				// Update blocks: remove current block and add up to 2 new blocks
-				slotStart, _ := utils.ParseTimeToMinutes(slot.Start)
-				slotEnd, _ := utils.ParseTimeToMinutes(slot.End)
-
-				// Remove the current block
-				freeBlocks = append(freeBlocks[:blockIdx], freeBlocks[blockIdx+1:]...)
-
-				// Add block before the task if there's space
-				if block.start < slotStart {
-					freeBlocks = append(freeBlocks, timeBlock{start: block.start, end: slotStart})
-				}
-
-				// Add block after the task if there's space
-				if slotEnd < block.end {
-					freeBlocks = append(freeBlocks, timeBlock{start: slotEnd, end: block.end})
-				}
-
+				freeBlocks = splitBlock(freeBlocks, blockIdx, slot, window)
+				demanding = addDemanding(demanding, task, slot, window, opts.Recovery)
 				break // Move to next task
 			}
 		}
@@ -150,6 +317,7 @@ func (s *Scheduler) GeneratePlan(date string, tasks []models.Task, dayStart, day
 		if !placed {
 			// Track tasks that couldn't be scheduled
 			unscheduledTasks = append(unscheduledTasks, task)
+			explain(task.ID, nil, "no free time left for its %dm, %s", task.DurationMin, placementOrder(task, opts.Aging[task.ID]))
 		}
 	}
 
@@ -161,26 +329,72 @@ func (s *Scheduler) GeneratePlan(date string, tasks []models.Task, dayStart, day
 
 	// Combine fixed and flexible slots, then sort
 	plan.Slots = append(fixedSlots, scheduledSlots...)
-	sort.Slice(plan.Slots, func(i, j int) bool {
-		return plan.Slots[i].Start < plan.Slots[j].Start
-	})
+	window.SortSlots(plan.Slots)
+	done()
 
 	return plan, nil
 }
 
+// placementOrder says where a flexible task came in the order tasks are
+// placed in, for Decision reasons
+func placementOrder(task models.Task, aging Aging) string {
+	switch {
+	case task.NiceToHave:
+		return "after the other tasks, as it is nice to have"
+	case aging.Boost > 0:
+		return fmt.Sprintf("in priority order (priority %d, raised from %d after %d days left out)",
+			aging.Priority(task), task.Priority, aging.MissedDays)
+	default:
+		return fmt.Sprintf("in priority order (priority %d)", task.Priority)
+	}
+}
+
+// fitCapacity keeps tasks, in priority order, until they fill capacity
+// percent of the flexible minutes a full day would hold: the tasks' total
+// duration, or the free time if that is less. The last task kept may run over
+// that budget, so the most important task is never left out for being long.
+// With shorten, each task's duration is scaled by capacity first.
+func fitCapacity(tasks []models.Task, freeMinutes, capacity int, shorten bool) []models.Task {
+	fullDay := 0
+	for _, task := range tasks {
+		fullDay += task.DurationMin
+	}
+	fullDay = min(fullDay, freeMinutes)
+	budget := fullDay * capacity / 100
+
+	var kept []models.Task
+	used := 0
+	for _, task := range tasks {
+		if used >= budget {
+			break
+		}
+		if shorten {
+			task.DurationMin = scaleDuration(task.DurationMin, capacity)
+		}
+		kept = append(kept, task)
+		used += task.DurationMin
+	}
+	return kept
+}
+
+// scaleDuration scales minutes by percent, rounded to 5 minutes and no
+// shorter than the minimum task duration
+func scaleDuration(minutes, percent int) int {
+	scaled := (minutes*percent/100 + 2) / 5 * 5
+	return max(scaled, min(minutes, constants.MinTaskDurationMin))
+}
+
 type timeBlock struct {
-	start int // minutes from midnight
-	end   int // minutes from midnight
+	start int // minutes from midnight of the plan date
+	end   int // minutes from midnight of the plan date; past 1440 after midnight
 }
 
 func formatTime(minutes int) string {
-	// Ensure minutes value is within valid range (0-1439)
 	if minutes < 0 {
 		minutes = 0
 	}
-	if minutes >= 1440 {
-		minutes = 1439
-	}
+	// Times after midnight wrap to the next day's clock
+	minutes %= models.MinutesPerDay
 	hours := minutes / 60
 	mins := minutes % 60
 	return fmt.Sprintf("%02d:%02d", hours, mins)
@@ -211,17 +425,13 @@ func calculateLateness(task models.Task, date time.Time) float64 {
 	return daysSince / interval
 }
 
-func findFreeBlocks(dayStart, dayEnd int, fixedSlots []models.Slot) []timeBlock {
+func findFreeBlocks(window models.DayWindow, fixedSlots []models.Slot) []timeBlock {
 	var blocks []timeBlock
 
-	currentStart := dayStart
+	currentStart := window.Start
 
 	for _, slot := range fixedSlots {
-		slotStart, err := utils.ParseTimeToMinutes(slot.Start)
-		if err != nil {
-			continue
-		}
-		slotEnd, err := utils.ParseTimeToMinutes(slot.End)
+		slotStart, slotEnd, err := window.Range(slot.Start, slot.End)
 		if err != nil {
 			continue
 		}
@@ -231,18 +441,56 @@ func findFreeBlocks(dayStart, dayEnd int, fixedSlots []models.Slot) []timeBlock
 			blocks = append(blocks, timeBlock{start: currentStart, end: slotStart})
 		}
 
-		currentStart = slotEnd
+		// Overlapping slots (e.g. an appointment inside a template slot) must
+		// not move the cursor backwards
+		if slotEnd > currentStart {
+			currentStart = slotEnd
+		}
 	}
 
 	// Add final block if there's time remaining
-	if currentStart < dayEnd {
-		blocks = append(blocks, timeBlock{start: currentStart, end: dayEnd})
+	if currentStart < window.End {
+		blocks = append(blocks, timeBlock{start: currentStart, end: window.End})
 	}
 
 	return blocks
 }
 
-func canScheduleInBlock(task models.Task, block timeBlock) bool {
+// splitBlock removes the block at blockIdx, which slot was placed in, and
+// adds back the free time left before and after the slot
+func splitBlock(blocks []timeBlock, blockIdx int, slot models.Slot, window models.DayWindow) []timeBlock {
+	block := blocks[blockIdx]
+	slotStart, slotEnd, _ := window.Range(slot.Start, slot.End)
+
+	// Remove the current block
+	blocks = append(blocks[:blockIdx], blocks[blockIdx+1:]...)
+
+	// Add block before the task if there's space
+	if block.start < slotStart {
+		blocks = append(blocks, timeBlock{start: block.start, end: slotStart})
+	}
+
+	// Add block after the task if there's space
+	if slotEnd < block.end {
+		blocks = append(blocks, timeBlock{start: slotEnd, end: block.end})
+	}
+	return blocks
+}
+
+// afterLock trims the blocks to the time from lockEnd on
+func afterLock(blocks []timeBlock, lockEnd int) []timeBlock {
+	var kept []timeBlock
+	for _, b := range blocks {
+		if b.end <= lockEnd {
+			continue
+		}
+		b.start = max(b.start, lockEnd)
+		kept = append(kept, b)
+	}
+	return kept
+}
+
+func canScheduleInBlock(task models.Task, block timeBlock, window models.DayWindow) bool {
 	// Check if task fits in the block duration
 	if task.DurationMin > block.end-block.start {
 		return false
@@ -250,14 +498,14 @@ func canScheduleInBlock(task models.Task, block timeBlock) bool {
 
 	// Check earliest/latest constraints
 	if task.EarliestStart != "" {
-		earliest, err := utils.ParseTimeToMinutes(task.EarliestStart)
+		earliest, err := window.Minutes(task.EarliestStart)
 		if err == nil && block.end <= earliest {
 			return false
 		}
 	}
 
 	if task.LatestEnd != "" {
-		latest, err := utils.ParseTimeToMinutes(task.LatestEnd)
+		latest, err := window.Minutes(task.LatestEnd)
 		if err == nil && block.start >= latest {
 			return false
 		}
@@ -266,12 +514,12 @@ func canScheduleInBlock(task models.Task, block timeBlock) bool {
 	return true
 }
 
-func placeTaskInBlock(task models.Task, block timeBlock) (models.Slot, bool) {
+func placeTaskInBlock(task models.Task, block timeBlock, window models.DayWindow) (models.Slot, bool) {
 	// Determine actual start time within constraints
 	startTime := block.start
 
 	if task.EarliestStart != "" {
-		earliest, err := utils.ParseTimeToMinutes(task.EarliestStart)
+		earliest, err := window.Minutes(task.EarliestStart)
 		if err == nil && earliest > startTime {
 			startTime = earliest
 		}
@@ -282,7 +530,7 @@ func placeTaskInBlock(task models.Task, block timeBlock) (models.Slot, bool) {
 
 	// Check if it fits within latest end constraint
 	if task.LatestEnd != "" {
-		latest, err := utils.ParseTimeToMinutes(task.LatestEnd)
+		latest, err := window.Minutes(task.LatestEnd)
 		if err == nil && endTime > latest {
 			return models.Slot{}, false
 		}
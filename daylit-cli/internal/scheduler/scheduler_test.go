@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -430,3 +431,256 @@ func TestGeneratePlan_ErrorHandling(t *testing.T) {
 		t.Error("Expected error for invalid day end, got nil")
 	}
 }
+
+func TestGeneratePlanFromTemplate_FillsGaps(t *testing.T) {
+	scheduler := New()
+
+	tasks := []models.Task{
+		{ID: "deep", Name: "Deep Work", Kind: constants.TaskKindFlexible, DurationMin: 120, Priority: 1, Active: true,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}},
+		{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30, Priority: 2, Active: true,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}},
+		{ID: "lunch", Name: "Lunch", Kind: constants.TaskKindAppointment, DurationMin: 60, FixedStart: "12:00", FixedEnd: "13:00", Priority: 3, Active: true,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}},
+	}
+	template := &models.DayTemplate{
+		Name: "deep-work",
+		Slots: []models.TemplateSlot{
+			{Start: "08:00", End: "11:00", TaskID: "deep"},
+		},
+	}
+
+	plan, err := scheduler.GeneratePlanFromTemplate("2025-12-31", tasks, "08:00", "18:00", template)
+	if err != nil {
+		t.Fatalf("GeneratePlanFromTemplate failed: %v", err)
+	}
+
+	got := make(map[string][]models.Slot)
+	for _, slot := range plan.Slots {
+		got[slot.TaskID] = append(got[slot.TaskID], slot)
+	}
+
+	if deep := got["deep"]; len(deep) != 1 || deep[0].Start != "08:00" || deep[0].End != "11:00" {
+		t.Errorf("template slot should be kept once as 08:00-11:00, got %+v", deep)
+	}
+	if email := got["email"]; len(email) != 1 || email[0].Start != "11:00" {
+		t.Errorf("email should fill the first gap after the template slot, got %+v", email)
+	}
+	if lunch := got["lunch"]; len(lunch) != 1 || lunch[0].Start != "12:00" {
+		t.Errorf("appointment should still be placed, got %+v", lunch)
+	}
+	for i := 1; i < len(plan.Slots); i++ {
+		if plan.Slots[i].Start < plan.Slots[i-1].End {
+			t.Errorf("slots overlap: %+v and %+v", plan.Slots[i-1], plan.Slots[i])
+		}
+	}
+}
+
+func TestGeneratePlan_DayEndsAfterMidnight(t *testing.T) {
+	scheduler := New()
+
+	tasks := []models.Task{
+		{ID: "show", Name: "Late Show", Kind: constants.TaskKindAppointment, FixedStart: "23:30", FixedEnd: "00:30", Active: true,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}},
+		{ID: "read", Name: "Read", Kind: constants.TaskKindFlexible, DurationMin: 45, Priority: 1, Active: true,
+			EarliestStart: "00:00", Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}},
+	}
+
+	plan, err := scheduler.GeneratePlan("2025-12-31", tasks, "22:00", "01:30")
+	if err != nil {
+		t.Fatalf("GeneratePlan failed: %v", err)
+	}
+
+	if len(plan.Slots) != 2 {
+		t.Fatalf("expected 2 slots, got %+v", plan.Slots)
+	}
+	if plan.Slots[0].TaskID != "show" || plan.Slots[0].Start != "23:30" || plan.Slots[0].End != "00:30" {
+		t.Errorf("expected the overnight appointment first, got %+v", plan.Slots[0])
+	}
+	if plan.Slots[1].TaskID != "read" || plan.Slots[1].Start != "00:30" || plan.Slots[1].End != "01:15" {
+		t.Errorf("expected read to follow after midnight, got %+v", plan.Slots[1])
+	}
+}
+
+func TestGeneratePlanWithOptions_Capacity(t *testing.T) {
+	scheduler := New()
+
+	tasks := []models.Task{
+		{ID: "deep", Name: "Deep Work", Kind: constants.TaskKindFlexible, DurationMin: 120, Priority: 1, Active: true,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}},
+		{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30, Priority: 2, Active: true,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}},
+		{ID: "walk", Name: "Walk", Kind: constants.TaskKindFlexible, DurationMin: 60, Priority: 3, Active: true,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}},
+		{ID: "lunch", Name: "Lunch", Kind: constants.TaskKindAppointment, FixedStart: "12:00", FixedEnd: "13:00", Priority: 5, Active: true,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}},
+	}
+
+	tests := []struct {
+		name    string
+		opts    PlanOptions
+		want    map[string]int // task ID -> slot length in minutes
+		dropped []string
+	}{
+		{
+			name: "full day",
+			opts: PlanOptions{Capacity: 100},
+			want: map[string]int{"deep": 120, "email": 30, "walk": 60, "lunch": 60},
+		},
+		{
+			name:    "half day keeps top priority and appointments",
+			opts:    PlanOptions{Capacity: 50},
+			want:    map[string]int{"deep": 120, "lunch": 60},
+			dropped: []string{"email", "walk"},
+		},
+		{
+			name: "half day with shortened blocks",
+			opts: PlanOptions{Capacity: 50, ShortenDurations: true},
+			want: map[string]int{"deep": 60, "email": 15, "walk": 30, "lunch": 60},
+		},
+		{
+			name: "quarter day with shortened blocks stays above the minimum",
+			opts: PlanOptions{Capacity: 25, ShortenDurations: true},
+			want: map[string]int{"deep": 30, "email": 10, "walk": 15, "lunch": 60},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := scheduler.GeneratePlanWithOptions("2025-12-31", tasks, "08:00", "18:00", tt.opts)
+			if err != nil {
+				t.Fatalf("GeneratePlanWithOptions failed: %v", err)
+			}
+
+			got := make(map[string]int)
+			for _, slot := range plan.Slots {
+				start, _ := time.Parse("15:04", slot.Start)
+				end, _ := time.Parse("15:04", slot.End)
+				got[slot.TaskID] = int(end.Sub(start).Minutes())
+			}
+			for id, length := range tt.want {
+				if got[id] != length {
+					t.Errorf("%s: expected a %d minute slot, got %d (plan %+v)", id, length, got[id], plan.Slots)
+				}
+			}
+			for _, id := range tt.dropped {
+				if _, ok := got[id]; ok {
+					t.Errorf("%s should not be scheduled at this capacity", id)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePlanWithOptions_Explain(t *testing.T) {
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	tasks := []models.Task{
+		{ID: "deep", Name: "Deep Work", Kind: constants.TaskKindFlexible, DurationMin: 120, Priority: 1, Active: true, Recurrence: daily},
+		{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30, Priority: 2, Active: true, Recurrence: daily},
+		{ID: "lunch", Name: "Lunch", Kind: constants.TaskKindAppointment, FixedStart: "12:00", FixedEnd: "13:00", Priority: 5, Active: true, Recurrence: daily},
+		{ID: "yoga", Name: "Yoga", Kind: constants.TaskKindFlexible, DurationMin: 60, Priority: 1, Active: true,
+			Recurrence: models.Recurrence{Type: constants.RecurrenceWeekly, WeekdayMask: []time.Weekday{time.Saturday}}},
+		{ID: "old", Name: "Old", Kind: constants.TaskKindFlexible, DurationMin: 30, Priority: 1, Recurrence: daily},
+	}
+
+	decisions := make(map[string][]Decision)
+	opts := PlanOptions{Capacity: 50, Explain: func(d Decision) { decisions[d.TaskID] = append(decisions[d.TaskID], d) }}
+	// 2025-12-31 is a Wednesday
+	if _, err := New().GeneratePlanWithOptions("2025-12-31", tasks, "08:00", "18:00", opts); err != nil {
+		t.Fatalf("GeneratePlanWithOptions failed: %v", err)
+	}
+
+	want := map[string]struct {
+		start  string // Empty when the task is left out
+		reason string
+	}{
+		"deep":  {"08:00", "first free time"},
+		"lunch": {"12:00", "fixed time"},
+		"email": {"", "50%"},
+		"yoga":  {"", "not due"},
+		"old":   {"", "inactive"},
+	}
+	for id, w := range want {
+		got := decisions[id]
+		if len(got) != 1 {
+			t.Errorf("%s: got decisions %+v, want one", id, got)
+			continue
+		}
+		start := ""
+		if got[0].Slot != nil {
+			start = got[0].Slot.Start
+		}
+		if start != w.start || !strings.Contains(got[0].Reason, w.reason) {
+			t.Errorf("%s: got %q at %q, want a reason mentioning %q at %q", id, got[0].Reason, start, w.reason, w.start)
+		}
+	}
+}
+
+func TestGeneratePlanWithOptions_Lock(t *testing.T) {
+	scheduler := New()
+
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	tasks := []models.Task{
+		{ID: "deep", Name: "Deep Work", Kind: constants.TaskKindFlexible, DurationMin: 120, Priority: 1, Active: true, Recurrence: daily},
+		{ID: "email", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30, Priority: 2, Active: true, Recurrence: daily},
+		{ID: "standup", Name: "Standup", Kind: constants.TaskKindAppointment, FixedStart: "08:30", FixedEnd: "09:00", Active: true, Recurrence: daily},
+		{ID: "lunch", Name: "Lunch", Kind: constants.TaskKindAppointment, FixedStart: "12:00", FixedEnd: "13:00", Active: true, Recurrence: daily},
+	}
+	template := &models.DayTemplate{Slots: []models.TemplateSlot{{Start: "09:00", End: "10:00", TaskID: "deep"}}}
+	opts := PlanOptions{
+		Template:    template,
+		LockedUntil: "10:00",
+		LockedSlots: []models.Slot{{Start: "08:00", End: "09:30", TaskID: "email", Status: constants.SlotStatusDone}},
+	}
+
+	plan, err := scheduler.GeneratePlanWithOptions("2025-12-31", tasks, "08:00", "18:00", opts)
+	if err != nil {
+		t.Fatalf("GeneratePlanWithOptions failed: %v", err)
+	}
+	if plan.LockedUntil != "10:00" {
+		t.Errorf("expected the plan to stay locked until 10:00, got %q", plan.LockedUntil)
+	}
+
+	// The locked slot is kept as it was, the template slot and appointment
+	// before the lock are left out and deep work starts at the boundary
+	var got []string
+	for _, slot := range plan.Slots {
+		got = append(got, slot.Start+"-"+slot.End+" "+slot.TaskID)
+	}
+	want := "08:00-09:30 email, 10:00-12:00 deep, 12:00-13:00 lunch"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("slots = %s\nwant    %s", strings.Join(got, ", "), want)
+	}
+	if plan.Slots[0].Status != constants.SlotStatusDone {
+		t.Errorf("expected the locked slot to keep its status, got %q", plan.Slots[0].Status)
+	}
+
+	if _, err := scheduler.GeneratePlanWithOptions("2025-12-31", tasks, "08:00", "18:00", PlanOptions{LockedUntil: "noon"}); err == nil {
+		t.Error("expected an invalid lock time to be rejected")
+	}
+}
+
+func TestScheduler_Observe(t *testing.T) {
+	daily := models.Recurrence{Type: constants.RecurrenceDaily}
+	tasks := []models.Task{
+		{ID: "deep", Name: "Deep Work", Kind: constants.TaskKindFlexible, DurationMin: 120, Priority: 1, Active: true, Recurrence: daily},
+		{ID: "lunch", Name: "Lunch", Kind: constants.TaskKindAppointment, FixedStart: "12:00", FixedEnd: "13:00", Priority: 5, Active: true, Recurrence: daily},
+	}
+
+	s := New()
+	var phases []string
+	s.Observe(func(phase string, elapsed time.Duration) {
+		if elapsed < 0 {
+			t.Errorf("%s took %v", phase, elapsed)
+		}
+		phases = append(phases, phase)
+	})
+	if _, err := s.GeneratePlan("2025-12-31", tasks, "08:00", "18:00"); err != nil {
+		t.Fatalf("GeneratePlan failed: %v", err)
+	}
+
+	want := []string{"place fixed slots", "order flexible tasks", "place flexible tasks"}
+	if strings.Join(phases, ", ") != strings.Join(want, ", ") {
+		t.Errorf("phases = %v, want %v", phases, want)
+	}
+}
@@ -0,0 +1,27 @@
+package scheduler
+
+import "github.com/julianstephens/daylit/daylit-cli/internal/models"
+
+// ChooseSharedTurns leaves out the shared tasks that are another member's
+// turn, so only the user whose turn it is gets a slot for them. Tasks that
+// aren't shared, or that user isn't a member of, are kept.
+func ChooseSharedTurns(tasks []models.Task, shared []models.SharedTask, user string) []models.Task {
+	turns := make(map[string]string, len(shared))
+	for _, st := range shared {
+		for _, m := range st.Members {
+			if m.User == user {
+				turns[st.TaskID] = st.Turn()
+				break
+			}
+		}
+	}
+
+	var kept []models.Task
+	for _, task := range tasks {
+		if turn, ok := turns[task.ID]; ok && turn != user {
+			continue
+		}
+		kept = append(kept, task)
+	}
+	return kept
+}
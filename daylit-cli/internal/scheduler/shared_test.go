@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestChooseSharedTurns(t *testing.T) {
+	tasks := []models.Task{
+		poolTask("recycling", "Recycling", "", ""),
+		poolTask("dishes", "Dishes", "", ""),
+		poolTask("read", "Read", "", ""),
+	}
+	shared := []models.SharedTask{
+		{TaskID: "recycling", Members: []models.SharedMember{
+			{User: "", LastDone: "2026-11-01"},
+			{User: "sam", LastDone: "2026-10-25"},
+		}},
+		{TaskID: "dishes", Members: []models.SharedMember{
+			{User: "sam", LastDone: "2026-11-01"},
+			{User: "alex"},
+		}},
+	}
+
+	tests := []struct {
+		user string
+		want []string
+	}{
+		// The default user did the recycling last, so it's Sam's turn
+		{user: "", want: []string{"Dishes", "Read"}},
+		{user: "sam", want: []string{"Recycling", "Read"}},
+		{user: "alex", want: []string{"Recycling", "Dishes", "Read"}},
+	}
+	for _, tt := range tests {
+		t.Run(models.UserLabel(tt.user), func(t *testing.T) {
+			kept := chosenNames(ChooseSharedTurns(tasks, shared, tt.user))
+			if len(kept) != len(tt.want) {
+				t.Fatalf("kept %v, want %v", kept, tt.want)
+			}
+			for _, name := range tt.want {
+				if !kept[name] {
+					t.Errorf("kept %v, want %v", kept, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestAmendPlan(t *testing.T) {
+	for name, store := range rangeStores(t) {
+		t.Run(name, func(t *testing.T) {
+			seedPlanRange(t, store)
+			addSlotTasks(t, store, "write", "email", "read", "walk")
+
+			const date = "2025-04-01"
+			acceptedAt := time.Now().UTC().Format(time.RFC3339)
+			plan := models.DayPlan{Date: date, AcceptedAt: &acceptedAt, Slots: []models.Slot{
+				{Start: "09:00", End: "10:00", TaskID: "write", Status: constants.SlotStatusDone,
+					Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack, Note: "good"}},
+				{Start: "10:00", End: "11:00", TaskID: "email", Status: constants.SlotStatusAccepted},
+				{Start: "11:00", End: "12:00", TaskID: "read", Status: constants.SlotStatusAccepted},
+			}}
+			if err := store.SavePlan(plan); err != nil {
+				t.Fatalf("failed to save plan: %v", err)
+			}
+			notified := "2025-04-01T09:00:00Z"
+			for _, task := range []string{"write", "email"} {
+				start := map[string]string{"write": "09:00", "email": "10:00"}[task]
+				if err := store.UpdateSlotNotificationTimestamp(date, 1, start, task, "start", notified); err != nil {
+					t.Fatalf("failed to mark slot notified: %v", err)
+				}
+			}
+			reminder := models.SlotReminder{ID: "r1", PlanDate: date, SlotStart: "10:00", TaskID: "email", CreatedAt: time.Now()}
+			if err := store.AddSlotReminder(reminder); err != nil {
+				t.Fatalf("failed to add reminder: %v", err)
+			}
+
+			moved, err := store.AmendPlan(models.PlanAmendment{
+				Date: date, Revision: 1, Kind: models.AmendmentMove, TaskID: "email",
+				FromStart: "10:00", ToStart: "14:00", ToEnd: "15:00", Reason: "meeting ran over",
+			})
+			if err != nil {
+				t.Fatalf("move failed: %v", err)
+			}
+			if moved.ID == 0 || moved.FromEnd != "11:00" {
+				t.Errorf("expected the amendment's ID and previous end to be filled in, got %+v", moved)
+			}
+			if _, err := store.AmendPlan(models.PlanAmendment{
+				Date: date, Revision: 1, Kind: models.AmendmentAdd, TaskID: "walk", ToStart: "16:00", ToEnd: "16:30",
+			}); err != nil {
+				t.Fatalf("add failed: %v", err)
+			}
+			if _, err := store.AmendPlan(models.PlanAmendment{
+				Date: date, Revision: 1, Kind: models.AmendmentRemove, TaskID: "read", FromStart: "11:00",
+			}); err != nil {
+				t.Fatalf("remove failed: %v", err)
+			}
+
+			got, err := store.GetPlan(date)
+			if err != nil {
+				t.Fatalf("GetPlan failed: %v", err)
+			}
+			if key := planKeys([]models.DayPlan{got})[0]; got.Revision != 1 || key != date+"#1 09:00 14:00 16:00" {
+				t.Fatalf("expected revision 1 amended in place, got %s", key)
+			}
+			write, email, walk := got.Slots[0], got.Slots[1], got.Slots[2]
+			if write.Feedback == nil || write.Feedback.Note != "good" || write.LastNotifiedStart == nil {
+				t.Errorf("untouched slot lost its feedback or notification state: %+v", write)
+			}
+			if email.End != "15:00" || email.LastNotifiedStart != nil {
+				t.Errorf("expected the moved slot to end at 15:00 and be notified again, got %+v", email)
+			}
+			if walk.Status != constants.SlotStatusAccepted {
+				t.Errorf("expected the added slot to be accepted, got %q", walk.Status)
+			}
+			reminders, err := store.GetSlotReminders(date, date)
+			if err != nil {
+				t.Fatalf("GetSlotReminders failed: %v", err)
+			}
+			if len(reminders) != 1 || reminders[0].SlotStart != "14:00" {
+				t.Errorf("expected the reminder to follow the moved slot, got %+v", reminders)
+			}
+
+			amendments, err := store.GetPlanAmendments(date)
+			if err != nil {
+				t.Fatalf("GetPlanAmendments failed: %v", err)
+			}
+			var kinds []models.AmendmentKind
+			for _, a := range amendments {
+				kinds = append(kinds, a.Kind)
+			}
+			if len(kinds) != 3 || kinds[0] != models.AmendmentMove || kinds[1] != models.AmendmentAdd || kinds[2] != models.AmendmentRemove {
+				t.Fatalf("expected move, add and remove in order, got %v", kinds)
+			}
+			if amendments[0].Reason != "meeting ran over" || amendments[2].FromEnd != "12:00" {
+				t.Errorf("unexpected audit trail: %+v", amendments)
+			}
+			if desc := amendments[0].Describe("Email"); desc != "moved Email from 10:00–11:00 to 14:00–15:00" {
+				t.Errorf("Describe = %q", desc)
+			}
+
+			for name, a := range map[string]models.PlanAmendment{
+				"unaccepted revision": {Date: "2025-03-10", Revision: 2, Kind: models.AmendmentRemove, TaskID: "task-1", FromStart: "13:00"},
+				"deleted plan":        {Date: "2025-03-12", Revision: 1, Kind: models.AmendmentRemove, TaskID: "task-1", FromStart: "08:00"},
+				"missing slot":        {Date: date, Revision: 1, Kind: models.AmendmentRemove, TaskID: "read", FromStart: "11:00"},
+				"taken start":         {Date: date, Revision: 1, Kind: models.AmendmentAdd, TaskID: "email", ToStart: "14:00", ToEnd: "14:30"},
+				"bad time":            {Date: date, Revision: 1, Kind: models.AmendmentAdd, TaskID: "email", ToStart: "25:00", ToEnd: "26:00"},
+			} {
+				if _, err := store.AmendPlan(a); err == nil {
+					t.Errorf("%s: expected the amendment to be refused", name)
+				}
+			}
+			if amendments, _ := store.GetPlanAmendments(date); len(amendments) != 3 {
+				t.Errorf("refused amendments were recorded: %d amendments", len(amendments))
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package storage_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+)
+
+const (
+	historyDays  = 3 * 365 // About 1000 plans
+	historyTasks = 20
+	historyStart = "2023-01-01"
+)
+
+// seedHistory fills store with three years of accepted daily plans of about
+// ten slots each, most of them rated, so the benchmarks read a history like
+// a long-time user's
+func seedHistory(b *testing.B, store storage.Provider) {
+	b.Helper()
+
+	for i := range historyTasks {
+		task := models.Task{
+			ID:          fmt.Sprintf("task-%02d", i),
+			Name:        fmt.Sprintf("Task %02d", i),
+			Kind:        constants.TaskKindFlexible,
+			DurationMin: 30,
+			Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+			Priority:    1 + i%5,
+			Active:      true,
+		}
+		if err := store.AddTask(task); err != nil {
+			b.Fatalf("failed to add task: %v", err)
+		}
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	ratings := []models.FeedbackRating{constants.FeedbackOnTrack, constants.FeedbackTooMuch, constants.FeedbackUnnecessary}
+	start, _ := time.Parse(constants.DateFormat, historyStart)
+	for day := range historyDays {
+		date := start.AddDate(0, 0, day)
+		acceptedAt := date.Add(7 * time.Hour).UTC().Format(time.RFC3339)
+		plan := models.DayPlan{Date: date.Format(constants.DateFormat), AcceptedAt: &acceptedAt}
+		for slot := range 9 + rng.IntN(3) {
+			s := models.Slot{
+				Start:  fmt.Sprintf("%02d:00", 8+slot),
+				End:    fmt.Sprintf("%02d:45", 8+slot),
+				TaskID: fmt.Sprintf("task-%02d", rng.IntN(historyTasks)),
+				Status: constants.SlotStatusDone,
+			}
+			if rng.IntN(4) != 0 {
+				s.Feedback = &models.Feedback{Rating: ratings[rng.IntN(len(ratings))], Note: "went fine"}
+			}
+			plan.Slots = append(plan.Slots, s)
+		}
+		if err := store.SavePlan(plan); err != nil {
+			b.Fatalf("failed to save plan %s: %v", plan.Date, err)
+		}
+	}
+}
+
+// historyStores returns the backends to benchmark, each seeded with the same
+// history
+func historyStores(b *testing.B) map[string]storage.Provider {
+	b.Helper()
+
+	sqliteStore := sqlite.NewStore(filepath.Join(b.TempDir(), "bench.db"))
+	if err := sqliteStore.Init(); err != nil {
+		b.Fatalf("failed to init sqlite store: %v", err)
+	}
+	b.Cleanup(func() { sqliteStore.Close() })
+
+	memoryStore := storage.NewMemoryStore()
+	if err := memoryStore.Init(); err != nil {
+		b.Fatalf("failed to init memory store: %v", err)
+	}
+
+	stores := map[string]storage.Provider{"sqlite": sqliteStore, "memory": memoryStore}
+	for _, store := range stores {
+		seedHistory(b, store)
+	}
+	return stores
+}
+
+func BenchmarkHistory(b *testing.B) {
+	stores := historyStores(b)
+	lastDay, _ := time.Parse(constants.DateFormat, historyStart)
+	lastDay = lastDay.AddDate(0, 0, historyDays-1)
+	last := lastDay.Format(constants.DateFormat)
+	monthAgo := lastDay.AddDate(0, -1, 0).Format(constants.DateFormat)
+	yearAgo := lastDay.AddDate(-1, 0, 0).Format(constants.DateFormat)
+
+	ops := []struct {
+		name string
+		run  func(storage.Provider) error
+	}{
+		{"GetAllPlans", func(s storage.Provider) error {
+			plans, err := s.GetAllPlans()
+			if err == nil && len(plans) != historyDays {
+				err = fmt.Errorf("got %d plans, want %d", len(plans), historyDays)
+			}
+			return err
+		}},
+		{"GetPlan", func(s storage.Provider) error {
+			_, err := s.GetPlan(last)
+			return err
+		}},
+		{"GetPlansRange/month", func(s storage.Provider) error {
+			_, err := s.GetPlansRange(monthAgo, last)
+			return err
+		}},
+		{"GetDaySummaries/month", func(s storage.Provider) error {
+			_, err := s.GetDaySummaries(monthAgo, last)
+			return err
+		}},
+		{"GetTaskStats/year", func(s storage.Provider) error {
+			_, err := s.GetTaskStats(yearAgo, last)
+			return err
+		}},
+		{"GetTaskFeedbackHistory", func(s storage.Provider) error {
+			_, err := s.GetTaskFeedbackHistory("task-03", 30)
+			return err
+		}},
+		{"GetTaskSlotHistory", func(s storage.Provider) error {
+			_, err := s.GetTaskSlotHistory("task-03", 30)
+			return err
+		}},
+		{"EachSlot/year", func(s storage.Provider) error {
+			return s.EachSlot(yearAgo, last, func(models.SlotRecord) error { return nil })
+		}},
+		{"Search", func(s storage.Provider) error {
+			_, err := s.Search("went", 50)
+			return err
+		}},
+	}
+
+	for _, op := range ops {
+		for _, backend := range []string{"sqlite", "memory"} {
+			store := stores[backend]
+			b.Run(op.name+"/"+backend, func(b *testing.B) {
+				for b.Loop() {
+					if err := op.run(store); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+// liveSlotTimes returns the start times of the live slots of the latest
+// revision of the plan for date
+func liveSlotTimes(t *testing.T, store storage.Provider, date string) []string {
+	t.Helper()
+	plan, err := store.GetPlan(date)
+	if err != nil {
+		t.Fatalf("failed to get plan %s: %v", date, err)
+	}
+	var starts []string
+	for _, slot := range plan.Slots {
+		starts = append(starts, slot.Start)
+	}
+	return starts
+}
+
+func TestDeleteTaskCascade(t *testing.T) {
+	sqliteStore, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	stores := map[string]storage.Provider{"sqlite": sqliteStore, "memory": setupMemoryStore(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			task := models.Task{ID: "write", Name: "Write", Kind: constants.TaskKindFlexible, DurationMin: 30,
+				Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 3, Active: true}
+			if err := store.AddTask(task); err != nil {
+				t.Fatalf("failed to add task: %v", err)
+			}
+			accepted := "2025-06-01T06:00:00Z"
+			slot := func(start string, status models.SlotStatus) models.Slot {
+				return models.Slot{Start: start, End: start[:3] + "30", TaskID: "write", Status: status}
+			}
+			plans := []models.DayPlan{
+				{Date: "2025-06-01", AcceptedAt: &accepted, Slots: []models.Slot{slot("09:00", constants.SlotStatusAccepted)}},
+				{Date: "2025-06-02", AcceptedAt: &accepted, Slots: []models.Slot{
+					slot("09:00", constants.SlotStatusAccepted), // Already past
+					slot("11:00", constants.SlotStatusAccepted),
+					slot("12:00", constants.SlotStatusDone),
+				}},
+				{Date: "2025-06-03", AcceptedAt: &accepted, Slots: []models.Slot{slot("09:00", constants.SlotStatusAccepted)}},
+				{Date: "2025-06-04", Slots: []models.Slot{slot("09:00", constants.SlotStatusPlanned)}}, // Not accepted
+			}
+			for _, plan := range plans {
+				if err := store.SavePlan(plan); err != nil {
+					t.Fatalf("failed to save plan %s: %v", plan.Date, err)
+				}
+			}
+
+			now := time.Date(2025, 6, 2, 10, 0, 0, 0, time.Local)
+			removed, err := store.DeleteTaskCascade("write", true, now)
+			if err != nil {
+				t.Fatalf("DeleteTaskCascade failed: %v", err)
+			}
+			if removed != 2 {
+				t.Errorf("removed %d slots, want 2", removed)
+			}
+			want := map[string]int{"2025-06-01": 1, "2025-06-02": 2, "2025-06-03": 0, "2025-06-04": 1}
+			for date, n := range want {
+				if got := liveSlotTimes(t, store, date); len(got) != n {
+					t.Errorf("%s has slots %v, want %d", date, got, n)
+				}
+			}
+
+			// Restoring the task brings its slots back
+			if err := store.RestoreTask("write"); err != nil {
+				t.Fatalf("RestoreTask failed: %v", err)
+			}
+			if got := liveSlotTimes(t, store, "2025-06-02"); len(got) != 3 {
+				t.Errorf("after restore, 2025-06-02 has slots %v, want 3", got)
+			}
+			if got := liveSlotTimes(t, store, "2025-06-03"); len(got) != 1 {
+				t.Errorf("after restore, 2025-06-03 has slots %v, want 1", got)
+			}
+
+			// Without removeSlots the plans are left alone
+			if removed, err := store.DeleteTaskCascade("write", false, now); err != nil || removed != 0 {
+				t.Fatalf("DeleteTaskCascade without slots = %d, %v", removed, err)
+			}
+			if got := liveSlotTimes(t, store, "2025-06-03"); len(got) != 1 {
+				t.Errorf("2025-06-03 has slots %v, want 1", got)
+			}
+		})
+	}
+}
+
+func TestDeleteTaskFollowsSetting(t *testing.T) {
+	sqliteStore, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	stores := map[string]storage.Provider{"sqlite": sqliteStore, "memory": setupMemoryStore(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			// DeleteTask works from the current time, so the plan is far ahead
+			accepted := "2025-06-01T06:00:00Z"
+			for _, id := range []string{"kept", "removed"} {
+				task := models.Task{ID: id, Name: id, Kind: constants.TaskKindFlexible, DurationMin: 30,
+					Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 3, Active: true}
+				if err := store.AddTask(task); err != nil {
+					t.Fatalf("failed to add task: %v", err)
+				}
+			}
+			plan := models.DayPlan{Date: "2999-01-01", AcceptedAt: &accepted, Slots: []models.Slot{
+				{Start: "09:00", End: "09:30", TaskID: "kept", Status: constants.SlotStatusAccepted},
+				{Start: "10:00", End: "10:30", TaskID: "removed", Status: constants.SlotStatusAccepted},
+			}}
+			if err := store.SavePlan(plan); err != nil {
+				t.Fatalf("failed to save plan: %v", err)
+			}
+
+			// By default the slots stay
+			if err := store.DeleteTask("kept"); err != nil {
+				t.Fatalf("DeleteTask failed: %v", err)
+			}
+			settings, err := store.GetSettings()
+			if err != nil {
+				t.Fatal(err)
+			}
+			settings.TaskDeleteSlots = constants.TaskDeleteSlotsRemove
+			if err := store.SaveSettings(settings); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.DeleteTask("removed"); err != nil {
+				t.Fatalf("DeleteTask failed: %v", err)
+			}
+
+			if got := liveSlotTimes(t, store, "2999-01-01"); len(got) != 1 || got[0] != "09:00" {
+				t.Errorf("expected only the kept task's slot left, got %v", got)
+			}
+		})
+	}
+}
+
+func TestDeleteHabitCascade(t *testing.T) {
+	sqliteStore, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	stores := map[string]storage.Provider{"sqlite": sqliteStore, "memory": setupMemoryStore(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for _, id := range []string{"run", "floss"} {
+				if err := store.AddHabit(models.Habit{ID: id, Name: id, CreatedAt: time.Now()}); err != nil {
+					t.Fatalf("failed to add habit: %v", err)
+				}
+				for _, day := range []string{"2025-06-01", "2025-06-02"} {
+					if err := store.AddHabitEntry(models.HabitEntry{ID: id + day, HabitID: id, Day: day, CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+						t.Fatalf("failed to add entry: %v", err)
+					}
+				}
+			}
+			entries := func(habitID string) int {
+				t.Helper()
+				list, err := store.GetHabitEntriesForHabit(habitID, "2025-06-01", "2025-06-30")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return len(list)
+			}
+
+			// By default a deleted habit keeps its entries
+			if err := store.DeleteHabit("run"); err != nil {
+				t.Fatalf("DeleteHabit failed: %v", err)
+			}
+			if n := entries("run"); n != 2 {
+				t.Errorf("run has %d entries, want 2", n)
+			}
+
+			settings, err := store.GetSettings()
+			if err != nil {
+				t.Fatal(err)
+			}
+			settings.HabitDeleteEntries = constants.HabitDeleteEntriesDelete
+			if err := store.SaveSettings(settings); err != nil {
+				t.Fatal(err)
+			}
+			// An entry deleted on its own stays deleted when the habit comes back
+			deletedAt := time.Now().Add(-time.Hour)
+			if err := store.UpdateHabitEntry(models.HabitEntry{ID: "floss2025-06-01", HabitID: "floss", Day: "2025-06-01",
+				CreatedAt: time.Now(), UpdatedAt: time.Now(), DeletedAt: &deletedAt}); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.DeleteHabit("floss"); err != nil {
+				t.Fatalf("DeleteHabit failed: %v", err)
+			}
+			if n := entries("floss"); n != 0 {
+				t.Errorf("floss has %d entries after delete, want 0", n)
+			}
+			if err := store.RestoreHabit("floss"); err != nil {
+				t.Fatalf("RestoreHabit failed: %v", err)
+			}
+			if n := entries("floss"); n != 1 {
+				t.Errorf("floss has %d entries after restore, want 1", n)
+			}
+		})
+	}
+}
+
+func TestSQLiteForeignKeys(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+	db := store.GetDB()
+
+	// A slot must belong to a task the database has
+	plan := models.DayPlan{Date: "2025-06-01", Slots: []models.Slot{
+		{Start: "09:00", End: "10:00", TaskID: "missing", Status: constants.SlotStatusPlanned},
+	}}
+	if err := store.SavePlan(plan); err == nil {
+		t.Error("expected saving a slot of a missing task to fail")
+	}
+
+	// and keeps the task from being removed
+	addSlotTasks(t, store, "write")
+	plan.Slots[0].TaskID = "write"
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+	if _, err := db.Exec("DELETE FROM tasks WHERE id = 'write'"); err == nil {
+		t.Error("expected removing a task with slots to fail")
+	}
+	// Saving the task replaces its row, which the slots allow
+	task, err := store.GetTask("write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	task.Priority = 1
+	if err := store.UpdateTask(task); err != nil {
+		t.Errorf("failed to update a task with slots: %v", err)
+	}
+
+	// Entries go with their habit
+	if err := store.AddHabit(models.Habit{ID: "run", Name: "Run", CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddHabitEntry(models.HabitEntry{ID: "e1", HabitID: "run", Day: "2025-06-01", CreatedAt: time.Now(), UpdatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("DELETE FROM habits WHERE id = 'run'"); err != nil {
+		t.Fatalf("failed to remove habit: %v", err)
+	}
+	var entries int
+	if err := db.QueryRow("SELECT COUNT(*) FROM habit_entries").Scan(&entries); err != nil {
+		t.Fatal(err)
+	}
+	if entries != 0 {
+		t.Errorf("%d entries outlived their habit", entries)
+	}
+}
@@ -0,0 +1,12 @@
+package storage
+
+import "github.com/julianstephens/daylit/daylit-cli/internal/models"
+
+// ChangeWatcher is implemented by providers that can report changes made
+// outside the current process
+type ChangeWatcher interface {
+	// WatchChanges sends changes on the returned channel until stop is
+	// called. Bursts of changes may be coalesced into a single Change, and
+	// the process's own writes may be reported as well.
+	WatchChanges() (changes <-chan models.Change, stop func(), err error)
+}
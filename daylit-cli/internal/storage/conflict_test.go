@@ -0,0 +1,137 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestTaskVersionConflict(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	task := models.Task{
+		ID:          "task-1",
+		Name:        "Test Task",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+		Priority:    1,
+		Active:      true,
+	}
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	// Two editors load the same version
+	first, err := store.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	second := first
+	if first.Version != 1 {
+		t.Errorf("expected version 1 after add, got %d", first.Version)
+	}
+
+	first.Name = "First"
+	if err := store.UpdateTask(first); err != nil {
+		t.Fatalf("first update failed: %v", err)
+	}
+
+	second.Name = "Second"
+	err = store.UpdateTask(second)
+	if !errors.Is(err, models.ErrConflict) {
+		t.Fatalf("expected a conflict for the stale update, got %v", err)
+	}
+
+	saved, err := store.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if saved.Name != "First" || saved.Version != 2 {
+		t.Errorf("expected the first update at version 2, got %q at version %d", saved.Name, saved.Version)
+	}
+
+	// Version 0 skips the check
+	second.Version = 0
+	if err := store.UpdateTask(second); err != nil {
+		t.Errorf("unchecked update failed: %v", err)
+	}
+}
+
+func TestPlanVersionConflict(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+	addSlotTasks(t, store, "task-1")
+
+	plan := models.DayPlan{
+		Date: "2024-01-15",
+		Slots: []models.Slot{
+			{Start: "09:00", End: "10:00", TaskID: "task-1", Status: constants.SlotStatusPlanned},
+		},
+	}
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	first, err := store.GetPlan(plan.Date)
+	if err != nil {
+		t.Fatalf("failed to get plan: %v", err)
+	}
+	second, err := store.GetPlan(plan.Date)
+	if err != nil {
+		t.Fatalf("failed to get plan: %v", err)
+	}
+
+	first.Slots[0].Status = constants.SlotStatusDone
+	if err := store.SavePlan(first); err != nil {
+		t.Fatalf("first save failed: %v", err)
+	}
+
+	second.Slots[0].Status = constants.SlotStatusSkipped
+	if err := store.SavePlan(second); !errors.Is(err, models.ErrConflict) {
+		t.Fatalf("expected a conflict for the stale save, got %v", err)
+	}
+
+	saved, err := store.GetPlan(plan.Date)
+	if err != nil {
+		t.Fatalf("failed to get plan: %v", err)
+	}
+	if saved.Slots[0].Status != constants.SlotStatusDone {
+		t.Errorf("expected the first save to be kept, got status %s", saved.Slots[0].Status)
+	}
+}
+
+func TestSettingsVersionConflict(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	first, err := store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	second, err := store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+
+	first.DayStart = "06:00"
+	if err := store.SaveSettings(first); err != nil {
+		t.Fatalf("first save failed: %v", err)
+	}
+
+	second.DayEnd = "23:00"
+	if err := store.SaveSettings(second); !errors.Is(err, models.ErrConflict) {
+		t.Fatalf("expected a conflict for the stale save, got %v", err)
+	}
+
+	saved, err := store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if saved.DayStart != "06:00" || saved.Version != first.Version+1 {
+		t.Errorf("expected the first save at version %d, got day start %s at version %d", first.Version+1, saved.DayStart, saved.Version)
+	}
+}
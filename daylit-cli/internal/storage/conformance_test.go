@@ -0,0 +1,30 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/storagetest"
+)
+
+func TestConformance(t *testing.T) {
+	t.Run("sqlite", func(t *testing.T) {
+		storagetest.Run(t, func(t *testing.T) storage.Provider {
+			store, cleanup := setupTestSQLiteStore(t)
+			t.Cleanup(cleanup)
+			return store
+		})
+	})
+	t.Run("memory", func(t *testing.T) {
+		storagetest.Run(t, func(t *testing.T) storage.Provider {
+			return setupMemoryStore(t)
+		})
+	})
+	// A timed store behaves exactly like the one it wraps
+	t.Run("timed", func(t *testing.T) {
+		storagetest.Run(t, func(t *testing.T) storage.Provider {
+			return storage.Timed(setupMemoryStore(t), func(string, time.Duration) {})
+		})
+	})
+}
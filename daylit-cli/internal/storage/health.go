@@ -0,0 +1,10 @@
+package storage
+
+// HealthChecker is implemented by providers whose backend can become
+// unreachable while the process runs, such as a database server
+type HealthChecker interface {
+	// Ping checks that the backend can be reached. The connection pool
+	// reconnects on its own, so a successful Ping after a failure means the
+	// backend is back.
+	Ping() error
+}
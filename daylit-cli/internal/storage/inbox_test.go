@@ -0,0 +1,53 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestInboxItems(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	now := time.Now()
+	items := []models.InboxItem{
+		{ID: uuid.New().String(), Text: "call the dentist", CreatedAt: now},
+		{ID: uuid.New().String(), Text: "buy stamps", CreatedAt: now.Add(-time.Hour)},
+	}
+	for _, item := range items {
+		if err := store.AddInboxItem(item); err != nil {
+			t.Fatalf("failed to add inbox item: %v", err)
+		}
+	}
+
+	got, err := store.GetInboxItems()
+	if err != nil {
+		t.Fatalf("failed to get inbox items: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 inbox items, got %d", len(got))
+	}
+	// Oldest first
+	if got[0].Text != "buy stamps" || got[1].Text != "call the dentist" {
+		t.Errorf("expected items in capture order, got %q, %q", got[0].Text, got[1].Text)
+	}
+
+	if err := store.DeleteInboxItem(items[1].ID); err != nil {
+		t.Fatalf("failed to delete inbox item: %v", err)
+	}
+	got, err = store.GetInboxItems()
+	if err != nil {
+		t.Fatalf("failed to get inbox items: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != items[0].ID {
+		t.Errorf("expected only %q to remain, got %+v", items[0].Text, got)
+	}
+
+	if err := store.DeleteInboxItem(items[1].ID); err == nil {
+		t.Error("expected an error deleting an item that no longer exists")
+	}
+}
@@ -1,12 +1,20 @@
 package storage
 
-import "github.com/julianstephens/daylit/daylit-cli/internal/models"
+import (
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
 
 type Provider interface {
 	// Lifecycle
 	Init() error
 	Load() error
 	Close() error
+	// WithTx runs fn with a store whose reads and writes all happen in one
+	// transaction, committed when fn returns nil and rolled back when it
+	// returns an error. The store is only good until fn returns.
+	WithTx(fn func(Provider) error) error
 
 	// Settings
 	GetSettings() (Settings, error)
@@ -18,9 +26,36 @@ type Provider interface {
 	GetAllTasks() ([]models.Task, error)
 	GetAllTasksIncludingDeleted() ([]models.Task, error)
 	UpdateTask(models.Task) error
+	// DeleteTask soft-deletes a task, along with its upcoming slots in
+	// accepted plans when task_delete_slots is "remove". DeleteTaskCascade
+	// takes the slots still upcoming at now with it when removeSlots is set,
+	// whatever the setting, and returns how many it removed. Either way it
+	// happens in one transaction, and RestoreTask brings back the task and
+	// the slots deleted with it.
 	DeleteTask(id string) error
+	DeleteTaskCascade(id string, removeSlots bool, now time.Time) (int, error)
 	RestoreTask(id string) error
 
+	// Projects
+	AddProject(models.Project) error
+	GetProjectByName(name string) (models.Project, error)
+	// GetAllProjects returns every project ordered by name
+	GetAllProjects() ([]models.Project, error)
+
+	// Task Pools
+	AddPool(models.TaskPool) error
+	GetPoolByName(name string) (models.TaskPool, error)
+	// GetAllPools returns every pool ordered by name
+	GetAllPools() ([]models.TaskPool, error)
+	// DeletePool deletes a pool and takes its members out of it
+	DeletePool(id string) error
+
+	// Inbox
+	AddInboxItem(models.InboxItem) error
+	// GetInboxItems returns every captured item, oldest first
+	GetInboxItems() ([]models.InboxItem, error)
+	DeleteInboxItem(id string) error
+
 	// Plans
 	SavePlan(models.DayPlan) error
 	GetPlan(date string) (models.DayPlan, error)
@@ -33,10 +68,49 @@ type Provider interface {
 	// for the given date. It returns an error if no such revision exists or the
 	// latest revision cannot be retrieved.
 	GetLatestPlanRevision(date string) (models.DayPlan, error)
+	// GetPlansRange returns the latest non-deleted revision of every plan in
+	// the inclusive date range, ordered by date, with slots as GetPlan returns
+	// them. Days without a plan are omitted.
+	GetPlansRange(startDay, endDay string) ([]models.DayPlan, error)
+	// GetPlansPage returns up to limit plans from the range, as GetPlansRange
+	// does, starting after cursor. Pass an empty cursor for the first page
+	// and the returned NextCursor for each page after it; NextCursor is empty
+	// on the last page. A limit of zero or less returns all remaining plans.
+	GetPlansPage(startDay, endDay, cursor string, limit int) (models.PlanPage, error)
 	DeletePlan(date string) error
 	RestorePlan(date string) error
+	// DeleteSlot soft-deletes the slot of a plan revision that starts at
+	// startTime for taskID, without creating a new revision. RestoreSlot
+	// brings back the most recently deleted such slot. Both fail if the
+	// revision is deleted.
+	DeleteSlot(date string, revision int, startTime string, taskID string) error
+	RestoreSlot(date string, revision int, startTime string, taskID string) error
+
+	// Plan Amendments
+	// AmendPlan applies one change to an accepted, non-deleted plan revision
+	// in place and records it, returning the amendment with its ID and the
+	// slot's previous end filled in. Slots the change doesn't touch keep
+	// their feedback and notification state; a moved slot keeps its feedback
+	// but is notified again at its new time, and its reminders follow it.
+	AmendPlan(models.PlanAmendment) (models.PlanAmendment, error)
+	// GetPlanAmendments returns the amendments made to every revision of the
+	// plan for date, oldest first.
+	GetPlanAmendments(date string) ([]models.PlanAmendment, error)
 	// UpdateSlotNotificationTimestamp updates the notification timestamp for a specific slot
 	UpdateSlotNotificationTimestamp(date string, revision int, startTime string, taskID string, notificationType string, timestamp string) error
+	// EachSlot calls fn for every slot of the latest non-deleted plan revision
+	// in the inclusive date range, ordered by date and start time. Rows are
+	// streamed from the database rather than loaded at once. Iteration stops
+	// at the first error returned by fn, which EachSlot returns.
+	EachSlot(startDay, endDay string, fn func(models.SlotRecord) error) error
+
+	// Day Templates
+	// SaveDayTemplate creates or replaces a template and all of its slots
+	SaveDayTemplate(models.DayTemplate) error
+	GetDayTemplate(name string) (models.DayTemplate, error)
+	// GetAllDayTemplates returns every template with its slots, ordered by name
+	GetAllDayTemplates() ([]models.DayTemplate, error)
+	DeleteDayTemplate(name string) error
 
 	// Habits
 	AddHabit(models.Habit) error
@@ -46,6 +120,9 @@ type Provider interface {
 	UpdateHabit(models.Habit) error
 	ArchiveHabit(id string) error
 	UnarchiveHabit(id string) error
+	// DeleteHabit soft-deletes a habit, along with its entries when
+	// habit_delete_entries is "delete", in one transaction. RestoreHabit
+	// brings back the habit and the entries deleted with it.
 	DeleteHabit(id string) error
 	RestoreHabit(id string) error
 
@@ -77,7 +154,52 @@ type Provider interface {
 	UpdateAlert(models.Alert) error
 	DeleteAlert(id string) error
 
+	// Vacations
+	AddVacation(models.Vacation) error
+	// GetVacations returns the vacations that overlap the inclusive date
+	// range, ordered by start date
+	GetVacations(startDay, endDay string) ([]models.Vacation, error)
+	DeleteVacation(id string) error
+
+	// Slot Reminders
+	AddSlotReminder(models.SlotReminder) error
+	// GetSlotReminders returns the reminders for plans in the inclusive date
+	// range, ordered by date, slot start and earliest reminder first
+	GetSlotReminders(startDay, endDay string) ([]models.SlotReminder, error)
+	MarkSlotReminderSent(id string, sentAt time.Time) error
+	DeleteSlotReminder(id string) error
+
+	// Notification Log
+	AddNotificationLog(models.NotificationLogEntry) error
+	// GetNotificationLog returns the notifications sent at or after since,
+	// newest first. A limit of zero or less returns all entries.
+	GetNotificationLog(since time.Time, limit int) ([]models.NotificationLogEntry, error)
+
+	// Notification Outbox
+	AddOutboxItem(models.OutboxItem) error
+	// GetDueOutboxItems returns up to limit pending items whose next attempt
+	// is due at now, oldest first; a limit of zero or less returns them all
+	GetDueOutboxItems(now time.Time, limit int) ([]models.OutboxItem, error)
+	// UpdateOutboxItem saves an item's attempts, next attempt, last error
+	// and status
+	UpdateOutboxItem(models.OutboxItem) error
+	// DeleteOutboxItem removes an item once it is delivered
+	DeleteOutboxItem(id int64) error
+	// GetOutboxItems returns the items created at or after since, pending or
+	// failed, newest first
+	GetOutboxItems(since time.Time) ([]models.OutboxItem, error)
+
+	// Notification Claims
+	// ClaimNotification records that the notification with the idempotency
+	// key is being sent and reports whether this call claimed it; false means
+	// another notify run already did, even one running at the same time
+	ClaimNotification(key string, at time.Time) (bool, error)
+	// DeleteNotificationClaims removes the claims made before before
+	DeleteNotificationClaims(before time.Time) error
+
 	// Bulk Retrieval for Migration
+	// These return every record, deleted ones included: plans by date and
+	// revision, habit entries by day and habit, OT entries by day.
 	GetAllPlans() ([]models.DayPlan, error)
 	GetAllHabitEntries() ([]models.HabitEntry, error)
 	GetAllOTEntries() ([]models.OTEntry, error)
@@ -86,6 +208,52 @@ type Provider interface {
 	// GetTaskFeedbackHistory retrieves feedback history for a specific task
 	// Returns feedback entries ordered by date (most recent first)
 	GetTaskFeedbackHistory(taskID string, limit int) ([]models.TaskFeedbackEntry, error)
+	// GetTaskSlotHistory returns the task's slots, newest first. Each day's
+	// slots come from the latest non-deleted plan revision that scheduled the
+	// task. A limit of zero or less returns all slots.
+	GetTaskSlotHistory(taskID string, limit int) ([]models.TaskSlotEntry, error)
+
+	// Summaries
+	// GetDaySummaries returns per-day plan, feedback, and habit activity for the
+	// inclusive date range. Days without any recorded activity are omitted.
+	// Results are ordered by date ascending.
+	GetDaySummaries(startDay, endDay string) ([]models.DaySummary, error)
+	// GetTaskStats returns planned and completed slot counts and minutes per
+	// task for the inclusive date range, using the latest non-deleted revision
+	// of each plan. Results are ordered by planned minutes descending.
+	GetTaskStats(startDay, endDay string) ([]models.TaskStats, error)
+	// GetHabitCategoryStats returns the habit days done and due per category
+	// of active habits for the inclusive date range. A habit is due every day
+	// of the range from the day it was created; days it was paused are
+	// neither due nor done. Results are ordered by category, with
+	// uncategorized habits first.
+	GetHabitCategoryStats(startDay, endDay string) ([]models.HabitCategoryStats, error)
+
+	// Metrics
+	// SaveDailyMetrics records the metrics of a day, replacing any recorded
+	// for it before
+	SaveDailyMetrics(models.DailyMetrics) error
+	// GetDailyMetrics returns the recorded metrics of the days in the
+	// inclusive date range, ordered by date
+	GetDailyMetrics(startDay, endDay string) ([]models.DailyMetrics, error)
+
+	// Purge
+	// PurgeDeleted permanently removes the records of the given kinds that
+	// were soft-deleted before the cutoff, in one transaction, and returns
+	// how many rows went. With dryRun set nothing is removed and the summary
+	// counts what would be.
+	PurgeDeleted(before time.Time, kinds []models.PurgeKind, dryRun bool) (models.PurgeSummary, error)
+	// PurgeItem permanently removes one soft-deleted item, whatever its age.
+	// Plans and OT entries are identified by date. A task that a slot or
+	// template still refers to is refused.
+	PurgeItem(kind models.TrashKind, id string) error
+
+	// Search
+	// Search returns task names, habit names, OT entries, and slot feedback
+	// notes matching every word of the query, best matches first. Only notes
+	// from the latest non-deleted plan revision are returned. A limit of zero
+	// or less returns all matches.
+	Search(query string, limit int) ([]models.SearchResult, error)
 
 	// Utils
 	GetConfigPath() string
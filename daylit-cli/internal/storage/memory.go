@@ -0,0 +1,690 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// MemoryStore is a Provider that keeps everything in memory, for tests, the
+// demo and benchmarks. It follows the SQLite store's rules, down to returning
+// sql.ErrNoRows for missing records, but nothing outlives the value.
+type MemoryStore struct {
+	mu  sync.RWMutex
+	seq int64 // Insertion counter standing in for SQLite's rowid
+
+	settings      map[string]string
+	tasks         map[string]record[models.Task]
+	projects      map[string]models.Project
+	pools         map[string]models.TaskPool
+	inbox         map[string]record[models.InboxItem]
+	plans         map[string][]*memPlan // By date, in revision order
+	nextSlotID    int64
+	amendments    []models.PlanAmendment // In the order they were made
+	templates     map[string]models.DayTemplate
+	habits        map[string]record[models.Habit]
+	habitEntries  map[string]record[models.HabitEntry]
+	otEntries     map[string]models.OTEntry // By day
+	alerts        map[string]record[models.Alert]
+	vacations     map[string]record[models.Vacation]
+	reminders     map[string]record[models.SlotReminder]
+	notifications []models.NotificationLogEntry
+	outbox        []models.OutboxItem // In the order they were queued
+	nextOutboxID  int64
+	claims        map[string]time.Time           // Notification claims by key
+	metrics       map[string]models.DailyMetrics // By date
+}
+
+var _ Provider = (*MemoryStore)(nil)
+
+// record is a stored value with its insertion order, which breaks ties the
+// way rowids do in SQLite
+type record[T any] struct {
+	val T
+	seq int64
+}
+
+// NewMemoryStore returns an empty store; call Init before using it
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		settings:     make(map[string]string),
+		tasks:        make(map[string]record[models.Task]),
+		projects:     make(map[string]models.Project),
+		pools:        make(map[string]models.TaskPool),
+		inbox:        make(map[string]record[models.InboxItem]),
+		plans:        make(map[string][]*memPlan),
+		templates:    make(map[string]models.DayTemplate),
+		habits:       make(map[string]record[models.Habit]),
+		habitEntries: make(map[string]record[models.HabitEntry]),
+		otEntries:    make(map[string]models.OTEntry),
+		alerts:       make(map[string]record[models.Alert]),
+		vacations:    make(map[string]record[models.Vacation]),
+		reminders:    make(map[string]record[models.SlotReminder]),
+		claims:       make(map[string]time.Time),
+		metrics:      make(map[string]models.DailyMetrics),
+	}
+}
+
+func (s *MemoryStore) next() int64 {
+	s.seq++
+	return s.seq
+}
+
+// sorted returns the records' values ordered by less, then insertion order.
+// A nil less orders by insertion alone. keep, if set, filters the values.
+func sorted[T any](records map[string]record[T], keep func(T) bool, less func(a, b T) bool) []T {
+	var list []record[T]
+	for _, r := range records {
+		if keep == nil || keep(r.val) {
+			list = append(list, r)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if less != nil {
+			if less(list[i].val, list[j].val) {
+				return true
+			}
+			if less(list[j].val, list[i].val) {
+				return false
+			}
+		}
+		return list[i].seq < list[j].seq
+	})
+
+	var values []T
+	for _, r := range list {
+		values = append(values, r.val)
+	}
+	return values
+}
+
+// stored drops what an RFC 3339 column can't hold, so times read back the
+// same as they would from the database
+func stored(t time.Time) time.Time {
+	parsed, err := time.Parse(time.RFC3339, t.Format(time.RFC3339))
+	if err != nil {
+		return t
+	}
+	return parsed
+}
+
+func storedPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := stored(*t)
+	return &v
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func utcNow() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// Lifecycle
+
+func (s *MemoryStore) Init() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	settings, err := s.getSettings()
+	if err != nil || settings.DayStart == "" {
+		defaultSettings := models.Settings{
+			DayStart:                   constants.DefaultDayStart,
+			DayEnd:                     constants.DefaultDayEnd,
+			DefaultBlockMin:            constants.DefaultBlockMin,
+			NotificationsEnabled:       constants.DefaultNotificationsEnabled,
+			NotifyBlockStart:           constants.DefaultNotifyBlockStart,
+			NotifyBlockEnd:             constants.DefaultNotifyBlockEnd,
+			BlockStartOffsetMin:        constants.DefaultBlockStartOffsetMin,
+			BlockEndOffsetMin:          constants.DefaultBlockEndOffsetMin,
+			NotificationGracePeriodMin: constants.DefaultNotificationGracePeriodMin,
+			Timezone:                   constants.DefaultTimezone,
+			Theme:                      constants.DefaultTheme,
+			MorningPlan:                constants.DefaultMorningPlan,
+		}
+		if err := s.saveSettings(defaultSettings); err != nil {
+			return fmt.Errorf("failed to save default settings: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Load() error {
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// WithTx runs fn on the store itself and, like a rolled back transaction,
+// puts everything back as it was when fn fails
+func (s *MemoryStore) WithTx(fn func(Provider) error) error {
+	saved := s.snapshot()
+	if err := fn(s); err != nil {
+		s.restore(saved)
+		return err
+	}
+	return nil
+}
+
+// memState is everything a MemoryStore holds
+type memState struct {
+	seq           int64
+	settings      map[string]string
+	tasks         map[string]record[models.Task]
+	projects      map[string]models.Project
+	pools         map[string]models.TaskPool
+	inbox         map[string]record[models.InboxItem]
+	plans         map[string][]*memPlan
+	nextSlotID    int64
+	amendments    []models.PlanAmendment
+	templates     map[string]models.DayTemplate
+	habits        map[string]record[models.Habit]
+	habitEntries  map[string]record[models.HabitEntry]
+	otEntries     map[string]models.OTEntry
+	alerts        map[string]record[models.Alert]
+	vacations     map[string]record[models.Vacation]
+	reminders     map[string]record[models.SlotReminder]
+	notifications []models.NotificationLogEntry
+	outbox        []models.OutboxItem
+	nextOutboxID  int64
+	claims        map[string]time.Time
+	metrics       map[string]models.DailyMetrics
+}
+
+// snapshot copies the store's state. Stored values are never changed in
+// place, except plans, so only plans are copied deeply.
+func (s *MemoryStore) snapshot() memState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	plans := make(map[string][]*memPlan, len(s.plans))
+	for date, revisions := range s.plans {
+		for _, p := range revisions {
+			copied := *p
+			copied.slots = slices.Clone(p.slots)
+			plans[date] = append(plans[date], &copied)
+		}
+	}
+	return memState{
+		seq:           s.seq,
+		settings:      maps.Clone(s.settings),
+		tasks:         maps.Clone(s.tasks),
+		projects:      maps.Clone(s.projects),
+		pools:         maps.Clone(s.pools),
+		inbox:         maps.Clone(s.inbox),
+		plans:         plans,
+		nextSlotID:    s.nextSlotID,
+		amendments:    slices.Clone(s.amendments),
+		templates:     maps.Clone(s.templates),
+		habits:        maps.Clone(s.habits),
+		habitEntries:  maps.Clone(s.habitEntries),
+		otEntries:     maps.Clone(s.otEntries),
+		alerts:        maps.Clone(s.alerts),
+		vacations:     maps.Clone(s.vacations),
+		reminders:     maps.Clone(s.reminders),
+		notifications: slices.Clone(s.notifications),
+		outbox:        slices.Clone(s.outbox),
+		nextOutboxID:  s.nextOutboxID,
+		claims:        maps.Clone(s.claims),
+		metrics:       maps.Clone(s.metrics),
+	}
+}
+
+func (s *MemoryStore) restore(m memState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq = m.seq
+	s.settings = m.settings
+	s.tasks = m.tasks
+	s.projects = m.projects
+	s.pools = m.pools
+	s.inbox = m.inbox
+	s.plans = m.plans
+	s.nextSlotID = m.nextSlotID
+	s.amendments = m.amendments
+	s.templates = m.templates
+	s.habits = m.habits
+	s.habitEntries = m.habitEntries
+	s.otEntries = m.otEntries
+	s.alerts = m.alerts
+	s.vacations = m.vacations
+	s.reminders = m.reminders
+	s.notifications = m.notifications
+	s.outbox = m.outbox
+	s.nextOutboxID = m.nextOutboxID
+	s.claims = m.claims
+	s.metrics = m.metrics
+}
+
+// GetConfigPath returns a placeholder, as there is no file behind the store
+func (s *MemoryStore) GetConfigPath() string {
+	return "memory"
+}
+
+// Settings
+
+func (s *MemoryStore) GetSettings() (models.Settings, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.getSettings()
+}
+
+func (s *MemoryStore) getSettings() (models.Settings, error) {
+	if len(s.settings) == 0 {
+		return models.Settings{}, fmt.Errorf("settings not found")
+	}
+	return models.MapToSettings(s.settings)
+}
+
+// storedSettings returns the settings, with defaults for any never saved,
+// for the store's own rules such as what a delete takes with it
+func (s *MemoryStore) storedSettings() (models.Settings, error) {
+	settings, err := models.MapToSettings(s.settings)
+	if err != nil {
+		return models.Settings{}, err
+	}
+	models.ApplyDefaultSettings(&settings)
+	return settings, nil
+}
+
+func (s *MemoryStore) SaveSettings(settings models.Settings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saveSettings(settings)
+}
+
+func (s *MemoryStore) saveSettings(settings models.Settings) error {
+	var version int
+	if value, ok := s.settings[constants.SettingVersion]; ok {
+		if _, err := fmt.Sscanf(value, "%d", &version); err != nil {
+			return fmt.Errorf("parsing settings version: %w", err)
+		}
+	}
+	if settings.Version > 0 && version != settings.Version {
+		return &models.ConflictError{Record: "settings"}
+	}
+
+	for key, value := range models.SettingsToMap(settings) {
+		s.settings[key] = value
+	}
+	s.settings[constants.SettingVersion] = strconv.Itoa(version + 1)
+	return nil
+}
+
+func (s *MemoryStore) GetOTSettings() (models.OTSettings, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	settings := models.OTSettings{
+		PromptOnEmpty:  true,
+		StrictMode:     true,
+		DefaultLogDays: 14,
+	}
+	if value, ok := s.settings[constants.SettingOTPromptOnEmpty]; ok {
+		settings.PromptOnEmpty = value == "true"
+	}
+	if value, ok := s.settings[constants.SettingOTStrictMode]; ok {
+		settings.StrictMode = value == "true"
+	}
+	if value, ok := s.settings[constants.SettingOTDefaultLogDays]; ok {
+		if _, err := fmt.Sscanf(value, "%d", &settings.DefaultLogDays); err != nil {
+			return models.OTSettings{}, fmt.Errorf("parsing ot_default_log_days: %w", err)
+		}
+	}
+	return settings, nil
+}
+
+func (s *MemoryStore) SaveOTSettings(settings models.OTSettings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.settings[constants.SettingOTPromptOnEmpty] = fmt.Sprintf("%v", settings.PromptOnEmpty)
+	s.settings[constants.SettingOTStrictMode] = fmt.Sprintf("%v", settings.StrictMode)
+	s.settings[constants.SettingOTDefaultLogDays] = fmt.Sprintf("%d", settings.DefaultLogDays)
+	return nil
+}
+
+// Tasks
+
+func cloneTask(t models.Task) models.Task {
+	if len(t.Recurrence.WeekdayMask) > 0 {
+		t.Recurrence.WeekdayMask = append([]time.Weekday(nil), t.Recurrence.WeekdayMask...)
+	} else {
+		t.Recurrence.WeekdayMask = nil
+	}
+	t.NotifyOffsetMin = clonePtr(t.NotifyOffsetMin)
+	t.DeletedAt = clonePtr(t.DeletedAt)
+	return t
+}
+
+func (s *MemoryStore) AddTask(task models.Task) error {
+	return s.UpdateTask(task)
+}
+
+func (s *MemoryStore) GetTask(id string) (models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.tasks[id]
+	if !ok || r.val.DeletedAt != nil {
+		return models.Task{}, sql.ErrNoRows
+	}
+	return cloneTask(r.val), nil
+}
+
+func (s *MemoryStore) GetAllTasks() ([]models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := sorted(s.tasks, func(t models.Task) bool { return t.DeletedAt == nil }, nil)
+	for i := range tasks {
+		tasks[i] = cloneTask(tasks[i])
+	}
+	return tasks, nil
+}
+
+func (s *MemoryStore) GetAllTasksIncludingDeleted() ([]models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := sorted(s.tasks, nil, nil)
+	for i := range tasks {
+		tasks[i] = cloneTask(tasks[i])
+	}
+	return tasks, nil
+}
+
+func (s *MemoryStore) UpdateTask(task models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Reject the save if someone else saved the task after it was loaded
+	existing, ok := s.tasks[task.ID]
+	if task.Version > 0 && ok && existing.val.Version != task.Version {
+		return &models.ConflictError{Record: "task", ID: task.ID}
+	}
+
+	task = cloneTask(task)
+	if task.Recurrence.Type != constants.RecurrenceMonthlyDay {
+		task.Recurrence.DayOfWeekInMonth = 0
+	}
+	task.Version = existing.val.Version + 1
+	// Like INSERT OR REPLACE, a saved task moves to the end
+	s.tasks[task.ID] = record[models.Task]{val: task, seq: s.next()}
+	return nil
+}
+
+func (s *MemoryStore) DeleteTask(id string) error {
+	_, err := s.deleteTask(id, nil, time.Now())
+	return err
+}
+
+func (s *MemoryStore) DeleteTaskCascade(id string, removeSlots bool, now time.Time) (int, error) {
+	return s.deleteTask(id, &removeSlots, now)
+}
+
+// deleteTask soft-deletes a task and, when removeSlots says so or is nil
+// and task_delete_slots is "remove", its slots still upcoming at now
+func (s *MemoryStore) deleteTask(id string, removeSlots *bool, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.tasks[id]
+	if !ok {
+		return 0, fmt.Errorf("task with id %s not found", id)
+	}
+	if r.val.DeletedAt != nil {
+		return 0, fmt.Errorf("task with id %s is already deleted", id)
+	}
+	settings, err := s.storedSettings()
+	if err != nil {
+		return 0, err
+	}
+	var window models.DayWindow
+	if removeSlots == nil {
+		remove := settings.TaskDeleteSlots == constants.TaskDeleteSlotsRemove
+		removeSlots = &remove
+	}
+	if *removeSlots {
+		if window, err = models.ParseDayWindow(settings.DayStart, settings.DayEnd); err != nil {
+			return 0, err
+		}
+	}
+
+	// The slots are marked with the task's time so restoring it finds them
+	deletedAt := utcNow()
+	r.val.DeletedAt = &deletedAt
+	r.val.Version++
+	s.tasks[id] = r
+
+	removed := 0
+	if *removeSlots {
+		for _, date := range s.allPlanDates() {
+			p := s.latestPlan(date)
+			if p == nil || p.acceptedAt == nil || date < now.Format(constants.DateFormat) {
+				continue
+			}
+			changed := false
+			for i := range p.slots {
+				slot := &p.slots[i].slot
+				if slot.TaskID == id && window.Upcoming(date, *slot, now) {
+					slot.DeletedAt = clonePtr(&deletedAt)
+					changed = true
+					removed++
+				}
+			}
+			if changed {
+				p.version++
+			}
+		}
+	}
+	return removed, nil
+}
+
+func (s *MemoryStore) RestoreTask(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.tasks[id]
+	if !ok {
+		return fmt.Errorf("task with id %s not found", id)
+	}
+	if r.val.DeletedAt == nil {
+		return fmt.Errorf("cannot restore a task that is not deleted: %s", id)
+	}
+
+	// Bring back the slots deleted with the task
+	for _, revisions := range s.plans {
+		for _, p := range revisions {
+			changed := false
+			for i := range p.slots {
+				slot := &p.slots[i].slot
+				if slot.TaskID == id && slot.DeletedAt != nil && *slot.DeletedAt == *r.val.DeletedAt {
+					slot.DeletedAt = nil
+					changed = true
+				}
+			}
+			if changed {
+				p.version++
+			}
+		}
+	}
+
+	r.val.DeletedAt = nil
+	r.val.Version++
+	s.tasks[id] = r
+	return nil
+}
+
+// Projects
+
+func (s *MemoryStore) AddProject(project models.Project) error {
+	if err := project.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.projects {
+		if p.Name == project.Name && p.ID != project.ID {
+			return fmt.Errorf("a project named %q already exists", project.Name)
+		}
+	}
+	if existing, ok := s.projects[project.ID]; ok {
+		project.CreatedAt = existing.CreatedAt
+	}
+	project.CreatedAt = stored(project.CreatedAt)
+	s.projects[project.ID] = project
+	return nil
+}
+
+func (s *MemoryStore) GetProjectByName(name string) (models.Project, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, p := range s.projects {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return models.Project{}, sql.ErrNoRows
+}
+
+func (s *MemoryStore) GetAllProjects() ([]models.Project, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var projects []models.Project
+	for _, p := range s.projects {
+		projects = append(projects, p)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects, nil
+}
+
+// Task Pools
+
+func (s *MemoryStore) AddPool(pool models.TaskPool) error {
+	if err := pool.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.pools {
+		if p.Name == pool.Name && p.ID != pool.ID {
+			return fmt.Errorf("a pool named %q already exists", pool.Name)
+		}
+	}
+	if existing, ok := s.pools[pool.ID]; ok {
+		pool.CreatedAt = existing.CreatedAt
+	}
+	pool.CreatedAt = stored(pool.CreatedAt)
+	s.pools[pool.ID] = pool
+	return nil
+}
+
+func (s *MemoryStore) GetPoolByName(name string) (models.TaskPool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, p := range s.pools {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return models.TaskPool{}, sql.ErrNoRows
+}
+
+func (s *MemoryStore) GetAllPools() ([]models.TaskPool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var pools []models.TaskPool
+	for _, p := range s.pools {
+		pools = append(pools, p)
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+	return pools, nil
+}
+
+func (s *MemoryStore) DeletePool(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pools[id]; !ok {
+		return fmt.Errorf("pool not found")
+	}
+	delete(s.pools, id)
+
+	// Members go back to being scheduled on their own
+	for taskID, r := range s.tasks {
+		if r.val.PoolID == id {
+			r.val.PoolID = ""
+			r.val.Version++
+			s.tasks[taskID] = r
+		}
+	}
+	return nil
+}
+
+// Inbox
+
+func (s *MemoryStore) AddInboxItem(item models.InboxItem) error {
+	if err := item.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.inbox[item.ID]; ok {
+		existing.val.Text = item.Text
+		s.inbox[item.ID] = existing
+		return nil
+	}
+	item.CreatedAt = stored(item.CreatedAt)
+	s.inbox[item.ID] = record[models.InboxItem]{val: item, seq: s.next()}
+	return nil
+}
+
+func (s *MemoryStore) GetInboxItems() ([]models.InboxItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return sorted(s.inbox, nil, func(a, b models.InboxItem) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}), nil
+}
+
+func (s *MemoryStore) DeleteInboxItem(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.inbox[id]; !ok {
+		return fmt.Errorf("inbox item not found")
+	}
+	delete(s.inbox, id)
+	return nil
+}
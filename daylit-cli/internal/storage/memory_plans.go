@@ -0,0 +1,971 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+// memPlan is one revision of a day's plan
+type memPlan struct {
+	revision    int
+	acceptedAt  *string
+	deletedAt   *string
+	version     int
+	note        string
+	lockedUntil string
+	slots       []memSlot // In the order they were saved, soft-deleted ones included
+}
+
+type memSlot struct {
+	id   int64
+	slot models.Slot
+}
+
+func cloneSlot(slot models.Slot) models.Slot {
+	// Like the database, a slot only has feedback once it's rated
+	if slot.Feedback != nil && slot.Feedback.Rating != "" {
+		slot.Feedback = clonePtr(slot.Feedback)
+	} else {
+		slot.Feedback = nil
+	}
+	slot.DeletedAt = clonePtr(slot.DeletedAt)
+	slot.LastNotifiedStart = clonePtr(slot.LastNotifiedStart)
+	slot.LastNotifiedEnd = clonePtr(slot.LastNotifiedEnd)
+	slot.ActualStart = clonePtr(slot.ActualStart)
+	slot.ActualEnd = clonePtr(slot.ActualEnd)
+	return slot
+}
+
+// planRevision returns the revision of the plan for date, deleted or not
+func (s *MemoryStore) planRevision(date string, revision int) *memPlan {
+	for _, p := range s.plans[date] {
+		if p.revision == revision {
+			return p
+		}
+	}
+	return nil
+}
+
+// latestPlan returns the latest non-deleted revision of the plan for date
+func (s *MemoryStore) latestPlan(date string) *memPlan {
+	var latest *memPlan
+	for _, p := range s.plans[date] {
+		if p.deletedAt == nil && (latest == nil || p.revision > latest.revision) {
+			latest = p
+		}
+	}
+	return latest
+}
+
+// liveSlots returns the plan's slots that aren't deleted, by start time
+func (p *memPlan) liveSlots() []memSlot {
+	var slots []memSlot
+	for _, ms := range p.slots {
+		if ms.slot.DeletedAt == nil {
+			slots = append(slots, ms)
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].slot.Start < slots[j].slot.Start })
+	return slots
+}
+
+// planDates returns the dates in the inclusive range that have plans, in order
+func (s *MemoryStore) planDates(startDay, endDay string) []string {
+	var dates []string
+	for _, date := range s.allPlanDates() {
+		if date >= startDay && date <= endDay {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// allPlanDates returns every date that has plans, in order
+func (s *MemoryStore) allPlanDates() []string {
+	var dates []string
+	for date := range s.plans {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	return dates
+}
+
+func (s *MemoryStore) taskName(id string) string {
+	if r, ok := s.tasks[id]; ok {
+		return r.val.Name
+	}
+	return id
+}
+
+func (s *MemoryStore) SavePlan(plan models.DayPlan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Prevent bypassing the delete/restore workflow by ensuring plans cannot be saved
+	// with DeletedAt manually set. Use DeletePlan/RestorePlan for managing deletion state.
+	if plan.DeletedAt != nil {
+		return fmt.Errorf("cannot save a plan with deleted_at set; use DeletePlan to soft-delete or RestorePlan to restore")
+	}
+
+	var version int
+	if plan.Revision == 0 {
+		latest := s.latestPlan(plan.Date)
+		switch {
+		case latest == nil:
+			plan.Revision = 1
+			version = 1
+		case latest.acceptedAt != nil:
+			// Accepted plans are never changed; this becomes a new revision
+			plan.Revision = latest.revision + 1
+			version = 1
+		default:
+			plan.Revision = latest.revision
+			version = latest.version + 1
+		}
+		// A regenerated plan keeps the day's note
+		if latest != nil && plan.Note == "" {
+			plan.Note = latest.note
+		}
+	} else {
+		existing := s.planRevision(plan.Date, plan.Revision)
+		if existing != nil && existing.deletedAt == nil {
+			if plan.Version > 0 && existing.version != plan.Version {
+				// Someone else saved this revision after it was loaded
+				return &models.ConflictError{Record: "plan", ID: plan.Date}
+			}
+			version = existing.version
+			// Only the same accepted plan may be saved over an accepted revision
+			if existing.acceptedAt != nil && (plan.AcceptedAt == nil || *plan.AcceptedAt != *existing.acceptedAt) {
+				return fmt.Errorf("cannot overwrite accepted plan: %s revision %d", plan.Date, plan.Revision)
+			}
+		}
+		version++
+	}
+
+	p := s.planRevision(plan.Date, plan.Revision)
+	if p != nil && p.deletedAt != nil {
+		return fmt.Errorf("cannot save slots to a deleted plan: %s revision %d", plan.Date, plan.Revision)
+	}
+	if p == nil {
+		p = &memPlan{revision: plan.Revision}
+		s.plans[plan.Date] = append(s.plans[plan.Date], p)
+		sort.Slice(s.plans[plan.Date], func(i, j int) bool {
+			return s.plans[plan.Date][i].revision < s.plans[plan.Date][j].revision
+		})
+	}
+	p.acceptedAt = clonePtr(plan.AcceptedAt)
+	p.version = version
+	p.note = plan.Note
+	p.lockedUntil = plan.LockedUntil
+
+	// The new slots replace the live ones; soft-deleted slots stay for restores
+	var slots []memSlot
+	for _, ms := range p.slots {
+		if ms.slot.DeletedAt != nil {
+			slots = append(slots, ms)
+		}
+	}
+	for _, slot := range plan.Slots {
+		s.nextSlotID++
+		slots = append(slots, memSlot{id: s.nextSlotID, slot: cloneSlot(slot)})
+	}
+	p.slots = slots
+	return nil
+}
+
+func (s *MemoryStore) GetPlan(date string) (models.DayPlan, error) {
+	return s.GetLatestPlanRevision(date)
+}
+
+func (s *MemoryStore) GetLatestPlanRevision(date string) (models.DayPlan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p := s.latestPlan(date)
+	if p == nil {
+		return models.DayPlan{}, fmt.Errorf("no plan found for date: %s", date)
+	}
+	return s.dayPlan(date, p), nil
+}
+
+func (s *MemoryStore) GetPlanRevision(date string, revision int) (models.DayPlan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p := s.planRevision(date, revision)
+	if p == nil {
+		return models.DayPlan{}, fmt.Errorf("no plan found for date: %s revision: %d", date, revision)
+	}
+	if p.deletedAt != nil {
+		return models.DayPlan{}, fmt.Errorf("plan for date %s revision %d has been deleted; use 'daylit restore plan %s' to restore it", date, revision, date)
+	}
+	return s.dayPlan(date, p), nil
+}
+
+func (s *MemoryStore) GetPlansRange(startDay, endDay string) ([]models.DayPlan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.plansAfter(startDay, endDay, "", 0), nil
+}
+
+func (s *MemoryStore) GetPlansPage(startDay, endDay, cursor string, limit int) (models.PlanPage, error) {
+	after, err := models.DecodePlanCursor(cursor)
+	if err != nil {
+		return models.PlanPage{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fetch := 0
+	if limit > 0 {
+		fetch = limit + 1
+	}
+	return models.NewPlanPage(s.plansAfter(startDay, endDay, after, fetch), limit), nil
+}
+
+// plansAfter returns the latest revision of up to limit plans in the range
+// dated after the given date. A limit of zero or less returns them all.
+func (s *MemoryStore) plansAfter(startDay, endDay, after string, limit int) []models.DayPlan {
+	var plans []models.DayPlan
+	for _, date := range s.planDates(startDay, endDay) {
+		if date <= after {
+			continue
+		}
+		if limit > 0 && len(plans) == limit {
+			break
+		}
+		if p := s.latestPlan(date); p != nil {
+			plans = append(plans, s.dayPlan(date, p))
+		}
+	}
+	return plans
+}
+
+// dayPlan returns p with its live slots in day order
+func (s *MemoryStore) dayPlan(date string, p *memPlan) models.DayPlan {
+	plan := models.DayPlan{
+		Date:        date,
+		Revision:    p.revision,
+		Version:     p.version,
+		Note:        p.note,
+		AcceptedAt:  clonePtr(p.acceptedAt),
+		LockedUntil: p.lockedUntil,
+	}
+	for _, ms := range p.liveSlots() {
+		plan.Slots = append(plan.Slots, cloneSlot(ms.slot))
+	}
+
+	// Slots after midnight come last in a day that ends after midnight
+	if settings, err := s.getSettings(); err == nil {
+		if window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd); err == nil {
+			window.SortSlots(plan.Slots)
+		}
+	}
+	return plan
+}
+
+func (s *MemoryStore) DeletePlan(date string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.latestPlan(date) == nil {
+		return fmt.Errorf("no active plans found for date: %s", date)
+	}
+
+	// Every revision and slot deleted now shares the timestamp, so a restore
+	// brings back exactly this deletion
+	deletedAt := utcNow()
+	for _, p := range s.plans[date] {
+		if p.deletedAt == nil {
+			p.deletedAt = clonePtr(&deletedAt)
+		}
+		for i := range p.slots {
+			if p.slots[i].slot.DeletedAt == nil {
+				p.slots[i].slot.DeletedAt = clonePtr(&deletedAt)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) RestorePlan(date string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest string
+	for _, p := range s.plans[date] {
+		if p.deletedAt != nil && *p.deletedAt > latest {
+			latest = *p.deletedAt
+		}
+	}
+	if latest == "" {
+		return fmt.Errorf("no deleted plans found for date: %s", date)
+	}
+
+	for _, p := range s.plans[date] {
+		if p.deletedAt != nil && *p.deletedAt == latest {
+			p.deletedAt = nil
+		}
+		for i := range p.slots {
+			if p.slots[i].slot.DeletedAt != nil && *p.slots[i].slot.DeletedAt == latest {
+				p.slots[i].slot.DeletedAt = nil
+			}
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeleteSlot(date string, revision int, startTime string, taskID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.slotPlan(date, revision)
+	if err != nil {
+		return err
+	}
+	deletedAt := utcNow()
+	found := false
+	for i := range p.slots {
+		slot := &p.slots[i].slot
+		if slot.DeletedAt == nil && slot.Start == startTime && slot.TaskID == taskID {
+			slot.DeletedAt = clonePtr(&deletedAt)
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("no slot at %s for task %s in plan %s revision %d", startTime, taskID, date, revision)
+	}
+	p.version++
+	return nil
+}
+
+func (s *MemoryStore) RestoreSlot(date string, revision int, startTime string, taskID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.slotPlan(date, revision)
+	if err != nil {
+		return err
+	}
+	latest := -1
+	for i, ms := range p.slots {
+		if ms.slot.Start != startTime || ms.slot.TaskID != taskID {
+			continue
+		}
+		if ms.slot.DeletedAt == nil {
+			return fmt.Errorf("plan %s revision %d already has a slot at %s for task %s", date, revision, startTime, taskID)
+		}
+		if latest < 0 || *ms.slot.DeletedAt > *p.slots[latest].slot.DeletedAt {
+			latest = i
+		}
+	}
+	if latest < 0 {
+		return fmt.Errorf("no deleted slot at %s for task %s in plan %s revision %d", startTime, taskID, date, revision)
+	}
+	p.slots[latest].slot.DeletedAt = nil
+	p.version++
+	return nil
+}
+
+// slotPlan returns the plan revision whose slots are being changed, which
+// must exist and not be deleted
+func (s *MemoryStore) slotPlan(date string, revision int) (*memPlan, error) {
+	p := s.planRevision(date, revision)
+	if p == nil {
+		return nil, fmt.Errorf("plan not found: %s revision %d", date, revision)
+	}
+	if p.deletedAt != nil {
+		return nil, fmt.Errorf("plan %s revision %d is deleted; restore the plan first", date, revision)
+	}
+	return p, nil
+}
+
+func (s *MemoryStore) AmendPlan(a models.PlanAmendment) (models.PlanAmendment, error) {
+	if err := a.Validate(); err != nil {
+		return a, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.slotPlan(a.Date, a.Revision)
+	if err != nil {
+		return a, err
+	}
+	if p.acceptedAt == nil {
+		return a, fmt.Errorf("plan %s revision %d is not accepted; change it directly instead", a.Date, a.Revision)
+	}
+
+	find := func(start string) int {
+		for i, ms := range p.slots {
+			if ms.slot.DeletedAt == nil && ms.slot.Start == start && ms.slot.TaskID == a.TaskID {
+				return i
+			}
+		}
+		return -1
+	}
+	if a.Kind != models.AmendmentRemove && a.ToStart != a.FromStart && find(a.ToStart) >= 0 {
+		return a, fmt.Errorf("plan %s revision %d already has a slot at %s for task %s", a.Date, a.Revision, a.ToStart, a.TaskID)
+	}
+	i := -1
+	if a.Kind != models.AmendmentAdd {
+		if i = find(a.FromStart); i < 0 {
+			return a, fmt.Errorf("no slot at %s for task %s in plan %s revision %d", a.FromStart, a.TaskID, a.Date, a.Revision)
+		}
+		a.FromEnd = p.slots[i].slot.End
+	}
+
+	switch a.Kind {
+	case models.AmendmentAdd:
+		s.nextSlotID++
+		p.slots = append(p.slots, memSlot{id: s.nextSlotID, slot: models.Slot{
+			Start:  a.ToStart,
+			End:    a.ToEnd,
+			TaskID: a.TaskID,
+			Status: constants.SlotStatusAccepted,
+		}})
+	case models.AmendmentMove:
+		slot := &p.slots[i].slot
+		slot.Start, slot.End = a.ToStart, a.ToEnd
+		slot.LastNotifiedStart, slot.LastNotifiedEnd = nil, nil
+		for id, r := range s.reminders {
+			if r.val.PlanDate == a.Date && r.val.TaskID == a.TaskID && r.val.SlotStart == a.FromStart {
+				r.val.SlotStart = a.ToStart
+				r.val.SentAt = nil
+				s.reminders[id] = r
+			}
+		}
+	case models.AmendmentRemove:
+		deletedAt := utcNow()
+		p.slots[i].slot.DeletedAt = &deletedAt
+	}
+	p.version++
+
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	a.CreatedAt = stored(a.CreatedAt)
+	a.ID = int64(len(s.amendments) + 1)
+	s.amendments = append(s.amendments, a)
+	return a, nil
+}
+
+func (s *MemoryStore) GetPlanAmendments(date string) ([]models.PlanAmendment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var amendments []models.PlanAmendment
+	for _, a := range s.amendments {
+		if a.Date == date {
+			amendments = append(amendments, a)
+		}
+	}
+	return amendments, nil
+}
+
+func (s *MemoryStore) UpdateSlotNotificationTimestamp(date string, revision int, startTime string, taskID string, notificationType string, timestamp string) error {
+	if notificationType != "start" && notificationType != "end" {
+		return fmt.Errorf("invalid notification type: %s", notificationType)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.planRevision(date, revision)
+	if p == nil {
+		return nil
+	}
+	for i := range p.slots {
+		slot := &p.slots[i].slot
+		if slot.DeletedAt != nil || slot.Start != startTime || slot.TaskID != taskID {
+			continue
+		}
+		if notificationType == "start" {
+			slot.LastNotifiedStart = clonePtr(&timestamp)
+		} else {
+			slot.LastNotifiedEnd = clonePtr(&timestamp)
+		}
+	}
+	return nil
+}
+
+// EachSlot collects the slots first so fn may use the store
+func (s *MemoryStore) EachSlot(startDay, endDay string, fn func(models.SlotRecord) error) error {
+	s.mu.RLock()
+	var records []models.SlotRecord
+	for _, date := range s.planDates(startDay, endDay) {
+		p := s.latestPlan(date)
+		if p == nil {
+			continue
+		}
+		for _, ms := range p.liveSlots() {
+			r := models.SlotRecord{
+				Date:     date,
+				Start:    ms.slot.Start,
+				End:      ms.slot.End,
+				TaskID:   ms.slot.TaskID,
+				TaskName: s.taskName(ms.slot.TaskID),
+				Status:   ms.slot.Status,
+			}
+			if ms.slot.Feedback != nil {
+				r.Rating = ms.slot.Feedback.Rating
+				r.Note = ms.slot.Feedback.Note
+			}
+			records = append(records, r)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, r := range records {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Day Templates
+
+func (s *MemoryStore) SaveDayTemplate(template models.DayTemplate) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.templates {
+		if t.Name == template.Name && t.ID != template.ID {
+			return fmt.Errorf("failed to save template: a template named %q already exists", template.Name)
+		}
+	}
+	if existing, ok := s.templates[template.ID]; ok {
+		template.CreatedAt = existing.CreatedAt
+	}
+	template.CreatedAt = stored(template.CreatedAt)
+	template.Slots = append([]models.TemplateSlot(nil), template.Slots...)
+	sort.SliceStable(template.Slots, func(i, j int) bool { return template.Slots[i].Start < template.Slots[j].Start })
+	s.templates[template.ID] = template
+	return nil
+}
+
+func cloneTemplate(t models.DayTemplate) models.DayTemplate {
+	t.Slots = append([]models.TemplateSlot(nil), t.Slots...)
+	return t
+}
+
+func (s *MemoryStore) GetDayTemplate(name string) (models.DayTemplate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, t := range s.templates {
+		if t.Name == name {
+			return cloneTemplate(t), nil
+		}
+	}
+	return models.DayTemplate{}, sql.ErrNoRows
+}
+
+func (s *MemoryStore) GetAllDayTemplates() ([]models.DayTemplate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var templates []models.DayTemplate
+	for _, t := range s.templates {
+		templates = append(templates, cloneTemplate(t))
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+func (s *MemoryStore) DeleteDayTemplate(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, t := range s.templates {
+		if t.Name == name {
+			delete(s.templates, id)
+			return nil
+		}
+	}
+	return fmt.Errorf("template %s not found", name)
+}
+
+// Bulk Retrieval
+
+func (s *MemoryStore) GetAllPlans() ([]models.DayPlan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var plans []models.DayPlan
+	for _, date := range s.allPlanDates() {
+		for _, p := range s.plans[date] {
+			plan := models.DayPlan{
+				Date:        date,
+				Revision:    p.revision,
+				AcceptedAt:  clonePtr(p.acceptedAt),
+				DeletedAt:   clonePtr(p.deletedAt),
+				Note:        p.note,
+				LockedUntil: p.lockedUntil,
+			}
+			// Deleted slots are included for a complete migration
+			slots := append([]memSlot(nil), p.slots...)
+			sort.SliceStable(slots, func(i, j int) bool { return slots[i].slot.Start < slots[j].slot.Start })
+			for _, ms := range slots {
+				plan.Slots = append(plan.Slots, cloneSlot(ms.slot))
+			}
+			plans = append(plans, plan)
+		}
+	}
+	return plans, nil
+}
+
+// Feedback Analysis
+
+func (s *MemoryStore) GetTaskFeedbackHistory(taskID string, limit int) ([]models.TaskFeedbackEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dates := s.allPlanDates()
+	var entries []models.TaskFeedbackEntry
+	// Newest first
+	for i := len(dates) - 1; i >= 0; i-- {
+		date := dates[i]
+		for _, p := range s.plans[date] {
+			if p.deletedAt != nil {
+				continue
+			}
+			for _, ms := range p.slots {
+				slot := ms.slot
+				if slot.TaskID != taskID || slot.DeletedAt != nil || slot.Feedback == nil {
+					continue
+				}
+				entry := models.TaskFeedbackEntry{
+					Date:        date,
+					TaskID:      taskID,
+					Rating:      slot.Feedback.Rating,
+					Note:        slot.Feedback.Note,
+					ActualStart: slot.Start,
+					ActualEnd:   slot.End,
+				}
+				if slot.ActualStart != nil {
+					entry.ActualStart = *slot.ActualStart
+				}
+				if slot.ActualEnd != nil {
+					entry.ActualEnd = *slot.ActualEnd
+				}
+				startMin, err1 := utils.ParseTimeToMinutes(entry.ActualStart)
+				endMin, err2 := utils.ParseTimeToMinutes(entry.ActualEnd)
+				if err1 == nil && err2 == nil {
+					// Handle slots that span midnight by treating the end time as the next day.
+					if endMin < startMin {
+						endMin += 24 * 60
+					}
+					entry.ActualDuration = endMin - startMin
+				}
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	// As with SQL's LIMIT, only a negative limit returns everything
+	if limit >= 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func (s *MemoryStore) GetTaskSlotHistory(taskID string, limit int) ([]models.TaskSlotEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var entries []models.TaskSlotEntry
+	for _, date := range s.allPlanDates() {
+		// The latest revision that scheduled the task, not the latest overall
+		var latest *memPlan
+		for _, p := range s.plans[date] {
+			if p.deletedAt != nil || (latest != nil && p.revision < latest.revision) {
+				continue
+			}
+			for _, ms := range p.slots {
+				if ms.slot.TaskID == taskID && ms.slot.DeletedAt == nil {
+					latest = p
+					break
+				}
+			}
+		}
+		if latest == nil {
+			continue
+		}
+		for _, ms := range latest.liveSlots() {
+			if ms.slot.TaskID != taskID {
+				continue
+			}
+			e := models.TaskSlotEntry{
+				Date:     date,
+				Revision: latest.revision,
+				Start:    ms.slot.Start,
+				End:      ms.slot.End,
+				Status:   ms.slot.Status,
+			}
+			if ms.slot.Feedback != nil {
+				e.Rating = ms.slot.Feedback.Rating
+				e.Note = ms.slot.Feedback.Note
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	// Newest first
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Date != entries[j].Date {
+			return entries[i].Date > entries[j].Date
+		}
+		return entries[i].Start > entries[j].Start
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// Summaries
+
+func (s *MemoryStore) GetDaySummaries(startDay, endDay string) ([]models.DaySummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summaries := make(map[string]*models.DaySummary)
+	for _, date := range s.planDates(startDay, endDay) {
+		p := s.latestPlan(date)
+		if p == nil {
+			continue
+		}
+		summary := &models.DaySummary{Date: date, HasPlan: true, Accepted: p.acceptedAt != nil}
+		for _, ms := range p.liveSlots() {
+			summary.TotalSlots++
+			if ms.slot.Feedback != nil {
+				summary.SlotsWithFeedback++
+			}
+		}
+		summaries[date] = summary
+	}
+
+	// Count habit entries per day, ignoring deleted entries and habits
+	for _, r := range s.habitEntries {
+		e := r.val
+		if e.Day < startDay || e.Day > endDay || e.DeletedAt != nil {
+			continue
+		}
+		habit, ok := s.habits[e.HabitID]
+		if !ok || habit.val.DeletedAt != nil {
+			continue
+		}
+		summary, ok := summaries[e.Day]
+		if !ok {
+			summary = &models.DaySummary{Date: e.Day}
+			summaries[e.Day] = summary
+		}
+		summary.HabitsCompleted++
+	}
+
+	result := make([]models.DaySummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date < result[j].Date
+	})
+	return result, nil
+}
+
+func (s *MemoryStore) GetTaskStats(startDay, endDay string) ([]models.TaskStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byTask := make(map[string]*models.TaskStats)
+	var order []string
+	for _, date := range s.planDates(startDay, endDay) {
+		p := s.latestPlan(date)
+		if p == nil {
+			continue
+		}
+		for _, ms := range p.liveSlots() {
+			st, ok := byTask[ms.slot.TaskID]
+			if !ok {
+				st = &models.TaskStats{TaskID: ms.slot.TaskID, TaskName: ms.slot.TaskID}
+				if r, ok := s.tasks[ms.slot.TaskID]; ok {
+					st.TaskName = r.val.Name
+					st.Priority = r.val.Priority
+					st.Context = r.val.Context
+				}
+				byTask[ms.slot.TaskID] = st
+				order = append(order, ms.slot.TaskID)
+			}
+
+			minutes := 0
+			start, err1 := utils.ParseTimeToMinutes(ms.slot.Start)
+			end, err2 := utils.ParseTimeToMinutes(ms.slot.End)
+			if err1 == nil && err2 == nil && end > start {
+				minutes = end - start
+			}
+			done := ms.slot.Status == constants.SlotStatusDone
+			st.PlannedSlots++
+			st.PlannedMinutes += minutes
+			if done {
+				st.DoneSlots++
+				st.DoneMinutes += minutes
+			}
+			if p.acceptedAt != nil {
+				st.AcceptedSlots++
+				if done {
+					st.AcceptedDoneSlots++
+				}
+			}
+		}
+	}
+
+	var stats []models.TaskStats
+	for _, id := range order {
+		stats = append(stats, *byTask[id])
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].PlannedMinutes != stats[j].PlannedMinutes {
+			return stats[i].PlannedMinutes > stats[j].PlannedMinutes
+		}
+		return stats[i].TaskName < stats[j].TaskName
+	})
+	return stats, nil
+}
+
+func (s *MemoryStore) GetHabitCategoryStats(startDay, endDay string) ([]models.HabitCategoryStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	end, err := time.Parse(constants.DateFormat, endDay)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end day: %w", err)
+	}
+
+	settings, err := s.storedSettings()
+	if err != nil {
+		return nil, err
+	}
+	keepArchived := settings.ArchivedHabitStats == constants.ArchivedHabitStatsKeep
+
+	byCategory := make(map[string]*models.HabitCategoryStats)
+	for _, r := range s.habits {
+		h := r.val
+		if h.DeletedAt != nil || (h.ArchivedAt != nil && !keepArchived) {
+			continue
+		}
+		// Archived habits are due up to the day they were archived
+		last, lastDay := end, endDay
+		if h.ArchivedAt != nil {
+			if archived := h.ArchivedAt.Format(constants.DateFormat); archived < endDay {
+				lastDay = archived
+				last, _ = time.Parse(constants.DateFormat, archived)
+			}
+		}
+		st, ok := byCategory[h.Category]
+		if !ok {
+			st = &models.HabitCategoryStats{Category: h.Category}
+			byCategory[h.Category] = st
+		}
+		st.Habits++
+
+		// Due from the later of the start day and the day it was created,
+		// except while paused
+		from := max(startDay, h.CreatedAt.Format(constants.DateFormat))
+		for day, err := time.Parse(constants.DateFormat, from); err == nil && !day.After(last); day = day.AddDate(0, 0, 1) {
+			if !h.PausedOn(day.Format(constants.DateFormat)) {
+				st.DueDays++
+			}
+		}
+		for _, e := range s.habitEntries {
+			if e.val.HabitID == h.ID && e.val.DeletedAt == nil && e.val.Day >= startDay && e.val.Day <= lastDay && !h.PausedOn(e.val.Day) {
+				st.DoneDays++
+			}
+		}
+	}
+
+	var stats []models.HabitCategoryStats
+	for _, st := range byCategory {
+		stats = append(stats, *st)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Category < stats[j].Category
+	})
+	return stats, nil
+}
+
+// Search
+
+// Search matches every term against the start of a word, as the database
+// indexes do. Results are ordered newest first rather than by relevance.
+func (s *MemoryStore) Search(query string, limit int) ([]models.SearchResult, error) {
+	terms := models.SearchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var results []models.SearchResult
+	add := func(r models.SearchResult, text string) {
+		if models.MatchesSearchTerms(text, terms) {
+			results = append(results, r)
+		}
+	}
+
+	for _, t := range sorted(s.tasks, func(t models.Task) bool { return t.DeletedAt == nil }, nil) {
+		add(models.SearchResult{Kind: constants.SearchKindTask, ID: t.ID, Title: t.Name}, t.Name)
+	}
+	for _, h := range sorted(s.habits, func(h models.Habit) bool { return h.DeletedAt == nil }, nil) {
+		add(models.SearchResult{Kind: constants.SearchKindHabit, ID: h.ID, Title: h.Name}, h.Name)
+	}
+	for _, e := range s.otEntries {
+		if e.DeletedAt == nil {
+			add(models.SearchResult{Kind: constants.SearchKindOT, ID: e.ID, Date: e.Day, Title: e.Title, Snippet: e.Note}, e.Title+" "+e.Note)
+		}
+	}
+	// Only notes from the latest revision of each plan
+	for _, date := range s.allPlanDates() {
+		p := s.latestPlan(date)
+		if p == nil {
+			continue
+		}
+		for _, ms := range p.liveSlots() {
+			if ms.slot.Feedback == nil || ms.slot.Feedback.Note == "" {
+				continue
+			}
+			title := ""
+			if r, ok := s.tasks[ms.slot.TaskID]; ok {
+				title = r.val.Name
+			}
+			add(models.SearchResult{
+				Kind:    constants.SearchKindNote,
+				ID:      strconv.FormatInt(ms.id, 10),
+				Date:    date,
+				Title:   title,
+				Snippet: ms.slot.Feedback.Note,
+			}, ms.slot.Feedback.Note)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Date > results[j].Date })
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
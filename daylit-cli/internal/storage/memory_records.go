@@ -0,0 +1,977 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// Habits
+
+func (s *MemoryStore) AddHabit(habit models.Habit) error {
+	return s.UpdateHabit(habit)
+}
+
+func (s *MemoryStore) GetHabit(id string) (models.Habit, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.habits[id]
+	if !ok || r.val.DeletedAt != nil {
+		return models.Habit{}, sql.ErrNoRows
+	}
+	return cloneHabit(r.val), nil
+}
+
+func (s *MemoryStore) GetHabitByName(name string) (models.Habit, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, r := range s.habits {
+		if r.val.Name == name && r.val.DeletedAt == nil {
+			return cloneHabit(r.val), nil
+		}
+	}
+	return models.Habit{}, sql.ErrNoRows
+}
+
+func (s *MemoryStore) GetAllHabits(includeArchived, includeDeleted bool) ([]models.Habit, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	habits := sorted(s.habits, func(h models.Habit) bool {
+		return (includeDeleted || h.DeletedAt == nil) && (includeArchived || h.ArchivedAt == nil)
+	}, func(a, b models.Habit) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	for i := range habits {
+		habits[i] = cloneHabit(habits[i])
+	}
+	return habits, nil
+}
+
+func cloneHabit(h models.Habit) models.Habit {
+	h.ArchivedAt = clonePtr(h.ArchivedAt)
+	h.DeletedAt = clonePtr(h.DeletedAt)
+	h.LastReminded = clonePtr(h.LastReminded)
+	h.ReminderDays = append([]time.Weekday(nil), h.ReminderDays...)
+	return h
+}
+
+func (s *MemoryStore) UpdateHabit(habit models.Habit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Names are unique across deleted habits too
+	for id, r := range s.habits {
+		if r.val.Name == habit.Name && id != habit.ID {
+			return fmt.Errorf("a habit named %q already exists", habit.Name)
+		}
+	}
+
+	habit.CreatedAt = stored(habit.CreatedAt)
+	habit.ArchivedAt = storedPtr(habit.ArchivedAt)
+	habit.DeletedAt = storedPtr(habit.DeletedAt)
+	if existing, ok := s.habits[habit.ID]; ok {
+		habit.CreatedAt = existing.val.CreatedAt
+		s.habits[habit.ID] = record[models.Habit]{val: habit, seq: existing.seq}
+		return nil
+	}
+	s.habits[habit.ID] = record[models.Habit]{val: habit, seq: s.next()}
+	return nil
+}
+
+// updateHabit applies change to the habit with id if ok accepts it, and
+// reports whether it did
+func (s *MemoryStore) updateHabit(id string, ok func(models.Habit) bool, change func(*models.Habit)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, found := s.habits[id]
+	if !found || !ok(r.val) {
+		return false
+	}
+	change(&r.val)
+	s.habits[id] = r
+	return true
+}
+
+func (s *MemoryStore) ArchiveHabit(id string) error {
+	archivedAt := stored(time.Now())
+	if !s.updateHabit(id,
+		func(h models.Habit) bool { return h.DeletedAt == nil && h.ArchivedAt == nil },
+		func(h *models.Habit) { h.ArchivedAt = &archivedAt },
+	) {
+		return fmt.Errorf("habit not found or already archived/deleted")
+	}
+	return nil
+}
+
+func (s *MemoryStore) UnarchiveHabit(id string) error {
+	if !s.updateHabit(id,
+		func(h models.Habit) bool { return h.DeletedAt == nil && h.ArchivedAt != nil },
+		func(h *models.Habit) { h.ArchivedAt = nil },
+	) {
+		return fmt.Errorf("habit not found or not archived")
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeleteHabit(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.habits[id]
+	if !ok || r.val.DeletedAt != nil {
+		return fmt.Errorf("habit not found or already deleted")
+	}
+	settings, err := s.storedSettings()
+	if err != nil {
+		return err
+	}
+
+	// The entries are marked with the habit's time so restoring it finds them
+	deletedAt := stored(time.Now())
+	r.val.DeletedAt = &deletedAt
+	s.habits[id] = r
+	if settings.HabitDeleteEntries == constants.HabitDeleteEntriesDelete {
+		for entryID, e := range s.habitEntries {
+			if e.val.HabitID == id && e.val.DeletedAt == nil {
+				e.val.DeletedAt = clonePtr(&deletedAt)
+				s.habitEntries[entryID] = e
+			}
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) RestoreHabit(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.habits[id]
+	if !ok || r.val.DeletedAt == nil {
+		return fmt.Errorf("habit not found or not deleted")
+	}
+
+	// Bring back the entries deleted with the habit
+	for entryID, e := range s.habitEntries {
+		if e.val.HabitID == id && e.val.DeletedAt != nil && e.val.DeletedAt.Equal(*r.val.DeletedAt) {
+			e.val.DeletedAt = nil
+			s.habitEntries[entryID] = e
+		}
+	}
+	r.val.DeletedAt = nil
+	s.habits[id] = r
+	return nil
+}
+
+// Habit Entries
+
+func cloneHabitEntry(e models.HabitEntry) models.HabitEntry {
+	e.DeletedAt = clonePtr(e.DeletedAt)
+	return e
+}
+
+func (s *MemoryStore) AddHabitEntry(entry models.HabitEntry) error {
+	return s.UpdateHabitEntry(entry)
+}
+
+func (s *MemoryStore) GetHabitEntry(habitID, day string) (models.HabitEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, r := range s.habitEntries {
+		if r.val.HabitID == habitID && r.val.Day == day && r.val.DeletedAt == nil {
+			return cloneHabitEntry(r.val), nil
+		}
+	}
+	return models.HabitEntry{}, sql.ErrNoRows
+}
+
+func (s *MemoryStore) GetHabitEntriesForDay(day string) ([]models.HabitEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := sorted(s.habitEntries, func(e models.HabitEntry) bool {
+		return e.Day == day && e.DeletedAt == nil
+	}, func(a, b models.HabitEntry) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	for i := range entries {
+		entries[i] = cloneHabitEntry(entries[i])
+	}
+	return entries, nil
+}
+
+func (s *MemoryStore) GetHabitEntriesForHabit(habitID string, startDay, endDay string) ([]models.HabitEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := sorted(s.habitEntries, func(e models.HabitEntry) bool {
+		return e.HabitID == habitID && e.Day >= startDay && e.Day <= endDay && e.DeletedAt == nil
+	}, func(a, b models.HabitEntry) bool {
+		return a.Day > b.Day
+	})
+	for i := range entries {
+		entries[i] = cloneHabitEntry(entries[i])
+	}
+	return entries, nil
+}
+
+func (s *MemoryStore) UpdateHabitEntry(entry models.HabitEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.CreatedAt = stored(entry.CreatedAt)
+	entry.UpdatedAt = stored(entry.UpdatedAt)
+	entry.DeletedAt = storedPtr(entry.DeletedAt)
+
+	// A habit has one entry a day; saving another updates it
+	for id, r := range s.habitEntries {
+		if r.val.HabitID == entry.HabitID && r.val.Day == entry.Day {
+			r.val.Note = entry.Note
+			r.val.UpdatedAt = entry.UpdatedAt
+			r.val.DeletedAt = entry.DeletedAt
+			s.habitEntries[id] = r
+			return nil
+		}
+	}
+	if _, ok := s.habitEntries[entry.ID]; ok {
+		return fmt.Errorf("habit entry %s already exists", entry.ID)
+	}
+	s.habitEntries[entry.ID] = record[models.HabitEntry]{val: entry, seq: s.next()}
+	return nil
+}
+
+func (s *MemoryStore) DeleteHabitEntry(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.habitEntries[id]
+	if !ok || r.val.DeletedAt != nil {
+		return fmt.Errorf("habit entry not found or already deleted")
+	}
+	deletedAt := stored(time.Now())
+	r.val.DeletedAt = &deletedAt
+	s.habitEntries[id] = r
+	return nil
+}
+
+func (s *MemoryStore) RestoreHabitEntry(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.habitEntries[id]
+	if !ok || r.val.DeletedAt == nil {
+		return fmt.Errorf("habit entry not found or not deleted")
+	}
+	r.val.DeletedAt = nil
+	s.habitEntries[id] = r
+	return nil
+}
+
+func (s *MemoryStore) GetAllHabitEntries() ([]models.HabitEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := sorted(s.habitEntries, nil, func(a, b models.HabitEntry) bool {
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		return a.HabitID < b.HabitID
+	})
+	for i := range entries {
+		entries[i] = cloneHabitEntry(entries[i])
+	}
+	return entries, nil
+}
+
+// OT Entries
+
+func cloneOTEntry(e models.OTEntry) models.OTEntry {
+	e.DeletedAt = clonePtr(e.DeletedAt)
+	return e
+}
+
+func (s *MemoryStore) AddOTEntry(entry models.OTEntry) error {
+	return s.UpdateOTEntry(entry)
+}
+
+func (s *MemoryStore) GetOTEntry(day string) (models.OTEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.otEntries[day]
+	if !ok || e.DeletedAt != nil {
+		return models.OTEntry{}, sql.ErrNoRows
+	}
+	return cloneOTEntry(e), nil
+}
+
+func (s *MemoryStore) GetOTEntries(startDay, endDay string, includeDeleted bool) ([]models.OTEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var entries []models.OTEntry
+	for day, e := range s.otEntries {
+		if day >= startDay && day <= endDay && (includeDeleted || e.DeletedAt == nil) {
+			entries = append(entries, cloneOTEntry(e))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Day > entries[j].Day })
+	return entries, nil
+}
+
+func (s *MemoryStore) UpdateOTEntry(entry models.OTEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.CreatedAt = stored(entry.CreatedAt)
+	entry.UpdatedAt = stored(entry.UpdatedAt)
+	entry.DeletedAt = storedPtr(entry.DeletedAt)
+
+	// A day has one entry; saving another updates it
+	if existing, ok := s.otEntries[entry.Day]; ok {
+		existing.Title = entry.Title
+		existing.Note = entry.Note
+		existing.UpdatedAt = entry.UpdatedAt
+		existing.DeletedAt = entry.DeletedAt
+		s.otEntries[entry.Day] = existing
+		return nil
+	}
+	for _, e := range s.otEntries {
+		if e.ID == entry.ID {
+			return fmt.Errorf("OT entry %s already exists", entry.ID)
+		}
+	}
+	s.otEntries[entry.Day] = entry
+	return nil
+}
+
+func (s *MemoryStore) DeleteOTEntry(day string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.otEntries[day]
+	if !ok || e.DeletedAt != nil {
+		return fmt.Errorf("OT entry not found or already deleted")
+	}
+	deletedAt := stored(time.Now())
+	e.DeletedAt = &deletedAt
+	s.otEntries[day] = e
+	return nil
+}
+
+func (s *MemoryStore) RestoreOTEntry(day string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.otEntries[day]
+	if !ok || e.DeletedAt == nil {
+		return fmt.Errorf("OT entry not found or not deleted")
+	}
+	e.DeletedAt = nil
+	s.otEntries[day] = e
+	return nil
+}
+
+func (s *MemoryStore) GetAllOTEntries() ([]models.OTEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var entries []models.OTEntry
+	for _, e := range s.otEntries {
+		entries = append(entries, cloneOTEntry(e))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Day < entries[j].Day })
+	return entries, nil
+}
+
+// Alerts
+
+func cloneAlert(a models.Alert) models.Alert {
+	if a.Recurrence.WeekdayMask != nil {
+		a.Recurrence.WeekdayMask = append([]time.Weekday{}, a.Recurrence.WeekdayMask...)
+	}
+	a.LastSent = clonePtr(a.LastSent)
+	return a
+}
+
+func (s *MemoryStore) AddAlert(alert models.Alert) error {
+	if err := alert.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.alerts[alert.ID]; ok {
+		return fmt.Errorf("failed to insert alert: alert %s already exists", alert.ID)
+	}
+	alert = cloneAlert(alert)
+	alert.LastSent = storedPtr(alert.LastSent)
+	alert.CreatedAt = stored(alert.CreatedAt)
+	s.alerts[alert.ID] = record[models.Alert]{val: alert, seq: s.next()}
+	return nil
+}
+
+func (s *MemoryStore) GetAlert(id string) (models.Alert, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.alerts[id]
+	if !ok {
+		return models.Alert{}, fmt.Errorf("alert not found")
+	}
+	return cloneAlert(r.val), nil
+}
+
+func (s *MemoryStore) GetAllAlerts() ([]models.Alert, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	alerts := sorted(s.alerts, nil, func(a, b models.Alert) bool { return a.Time < b.Time })
+	for i := range alerts {
+		alerts[i] = cloneAlert(alerts[i])
+	}
+	return alerts, nil
+}
+
+func (s *MemoryStore) UpdateAlert(alert models.Alert) error {
+	if err := alert.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.alerts[alert.ID]
+	if !ok {
+		return fmt.Errorf("alert not found")
+	}
+	alert = cloneAlert(alert)
+	alert.LastSent = storedPtr(alert.LastSent)
+	alert.CreatedAt = existing.val.CreatedAt
+	existing.val = alert
+	s.alerts[alert.ID] = existing
+	return nil
+}
+
+func (s *MemoryStore) DeleteAlert(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.alerts[id]; !ok {
+		return fmt.Errorf("alert not found")
+	}
+	delete(s.alerts, id)
+	return nil
+}
+
+// Vacations
+
+func (s *MemoryStore) AddVacation(vacation models.Vacation) error {
+	if err := vacation.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.vacations[vacation.ID]; ok {
+		return fmt.Errorf("vacation %s already exists", vacation.ID)
+	}
+	vacation.CreatedAt = stored(vacation.CreatedAt)
+	s.vacations[vacation.ID] = record[models.Vacation]{val: vacation, seq: s.next()}
+	return nil
+}
+
+func (s *MemoryStore) GetVacations(startDay, endDay string) ([]models.Vacation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return sorted(s.vacations, func(v models.Vacation) bool {
+		return v.End >= startDay && v.Start <= endDay
+	}, func(a, b models.Vacation) bool {
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		return a.End < b.End
+	}), nil
+}
+
+func (s *MemoryStore) DeleteVacation(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.vacations[id]; !ok {
+		return fmt.Errorf("vacation not found")
+	}
+	delete(s.vacations, id)
+	return nil
+}
+
+// Metrics
+
+func (s *MemoryStore) SaveDailyMetrics(m models.DailyMetrics) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m.ComputedAt = stored(m.ComputedAt)
+	s.metrics[m.Date] = m
+	return nil
+}
+
+func (s *MemoryStore) GetDailyMetrics(startDay, endDay string) ([]models.DailyMetrics, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var metrics []models.DailyMetrics
+	for date, m := range s.metrics {
+		if date >= startDay && date <= endDay {
+			metrics = append(metrics, m)
+		}
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Date < metrics[j].Date })
+	return metrics, nil
+}
+
+// Slot Reminders
+
+func (s *MemoryStore) AddSlotReminder(reminder models.SlotReminder) error {
+	if err := reminder.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.reminders[reminder.ID]; ok {
+		return fmt.Errorf("failed to insert slot reminder: reminder %s already exists", reminder.ID)
+	}
+	reminder.SentAt = storedPtr(reminder.SentAt)
+	reminder.CreatedAt = stored(reminder.CreatedAt)
+	s.reminders[reminder.ID] = record[models.SlotReminder]{val: reminder, seq: s.next()}
+	return nil
+}
+
+func (s *MemoryStore) GetSlotReminders(startDay, endDay string) ([]models.SlotReminder, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reminders := sorted(s.reminders, func(r models.SlotReminder) bool {
+		return r.PlanDate >= startDay && r.PlanDate <= endDay
+	}, func(a, b models.SlotReminder) bool {
+		if a.PlanDate != b.PlanDate {
+			return a.PlanDate < b.PlanDate
+		}
+		if a.SlotStart != b.SlotStart {
+			return a.SlotStart < b.SlotStart
+		}
+		return a.OffsetMin > b.OffsetMin
+	})
+	for i := range reminders {
+		reminders[i].SentAt = clonePtr(reminders[i].SentAt)
+	}
+	return reminders, nil
+}
+
+func (s *MemoryStore) MarkSlotReminderSent(id string, sentAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.reminders[id]
+	if !ok {
+		return fmt.Errorf("slot reminder not found")
+	}
+	r.val.SentAt = storedPtr(&sentAt)
+	s.reminders[id] = r
+	return nil
+}
+
+func (s *MemoryStore) DeleteSlotReminder(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.reminders[id]; !ok {
+		return fmt.Errorf("slot reminder not found")
+	}
+	delete(s.reminders, id)
+	return nil
+}
+
+// Notification Log
+
+func (s *MemoryStore) AddNotificationLog(entry models.NotificationLogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.ID = int64(len(s.notifications) + 1)
+	entry.SentAt = stored(entry.SentAt.UTC())
+	s.notifications = append(s.notifications, entry)
+	return nil
+}
+
+func (s *MemoryStore) GetNotificationLog(since time.Time, limit int) ([]models.NotificationLogEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	since = stored(since.UTC())
+	var entries []models.NotificationLogEntry
+	for _, e := range s.notifications {
+		if !e.SentAt.Before(since) {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].SentAt.Equal(entries[j].SentAt) {
+			return entries[i].SentAt.After(entries[j].SentAt)
+		}
+		return entries[i].ID > entries[j].ID
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// Notification Outbox
+
+func (s *MemoryStore) AddOutboxItem(item models.OutboxItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextOutboxID++
+	item.ID = s.nextOutboxID
+	item.CreatedAt = stored(item.CreatedAt.UTC())
+	item.NextAttemptAt = stored(item.NextAttemptAt.UTC())
+	s.outbox = append(s.outbox, item)
+	return nil
+}
+
+func (s *MemoryStore) GetDueOutboxItems(now time.Time, limit int) ([]models.OutboxItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now = stored(now.UTC())
+	var items []models.OutboxItem
+	for _, item := range s.outbox {
+		if item.Status == models.OutboxPending && !item.NextAttemptAt.After(now) {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.Before(items[j].CreatedAt)
+		}
+		return items[i].ID < items[j].ID
+	})
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (s *MemoryStore) UpdateOutboxItem(item models.OutboxItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.outbox, func(o models.OutboxItem) bool { return o.ID == item.ID })
+	if i < 0 {
+		return fmt.Errorf("outbox item %d not found", item.ID)
+	}
+	updated := s.outbox[i]
+	updated.Attempts = item.Attempts
+	updated.NextAttemptAt = stored(item.NextAttemptAt.UTC())
+	updated.LastError = item.LastError
+	updated.Status = item.Status
+	s.outbox[i] = updated
+	return nil
+}
+
+func (s *MemoryStore) DeleteOutboxItem(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.outbox, func(o models.OutboxItem) bool { return o.ID == id })
+	if i < 0 {
+		return fmt.Errorf("outbox item %d not found", id)
+	}
+	s.outbox = slices.Delete(s.outbox, i, i+1)
+	return nil
+}
+
+func (s *MemoryStore) GetOutboxItems(since time.Time) ([]models.OutboxItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	since = stored(since.UTC())
+	var items []models.OutboxItem
+	for _, item := range s.outbox {
+		if !item.CreatedAt.Before(since) {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.After(items[j].CreatedAt)
+		}
+		return items[i].ID > items[j].ID
+	})
+	return items, nil
+}
+
+// Notification Claims
+
+func (s *MemoryStore) ClaimNotification(key string, at time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.claims[key]; ok {
+		return false, nil
+	}
+	s.claims[key] = stored(at.UTC())
+	return true, nil
+}
+
+func (s *MemoryStore) DeleteNotificationClaims(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before = stored(before.UTC())
+	maps.DeleteFunc(s.claims, func(_ string, at time.Time) bool { return at.Before(before) })
+	return nil
+}
+
+// Purge
+
+func (s *MemoryStore) PurgeDeleted(before time.Time, kinds []models.PurgeKind, dryRun bool) (models.PurgeSummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var summary models.PurgeSummary
+	expired := func(deletedAt *string) bool {
+		return deletedAt != nil && models.DeletedBefore(*deletedAt, before)
+	}
+
+	// Purge into copies, so a dry run leaves the store as it was
+	plans := s.plans
+	reminders := s.reminders
+	if models.HasPurgeKind(kinds, models.PurgePlans) {
+		plans = make(map[string][]*memPlan, len(s.plans))
+		for date, revisions := range s.plans {
+			var kept []*memPlan
+			for _, p := range revisions {
+				if expired(p.deletedAt) {
+					summary.Plans++
+					summary.Slots += len(p.slots)
+					continue
+				}
+				cp := *p
+				cp.slots = nil
+				for _, ms := range p.slots {
+					if expired(ms.slot.DeletedAt) {
+						summary.Slots++
+						continue
+					}
+					cp.slots = append(cp.slots, ms)
+				}
+				kept = append(kept, &cp)
+			}
+			if len(kept) > 0 {
+				plans[date] = kept
+			}
+		}
+
+		// Reminders belong to the day, so they go once no revision is left
+		reminders = make(map[string]record[models.SlotReminder], len(s.reminders))
+		for id, r := range s.reminders {
+			if _, ok := s.plans[r.val.PlanDate]; ok && plans[r.val.PlanDate] == nil {
+				continue
+			}
+			reminders[id] = r
+		}
+	}
+
+	habits := s.habits
+	entries := s.habitEntries
+	if models.HasPurgeKind(kinds, models.PurgeHabits) {
+		habits = make(map[string]record[models.Habit], len(s.habits))
+		for id, r := range s.habits {
+			if r.val.DeletedAt != nil && r.val.DeletedAt.Before(before) {
+				summary.Habits++
+				continue
+			}
+			habits[id] = r
+		}
+		entries = make(map[string]record[models.HabitEntry], len(s.habitEntries))
+		for id, r := range s.habitEntries {
+			_, habitKept := habits[r.val.HabitID]
+			_, habitExisted := s.habits[r.val.HabitID]
+			if (habitExisted && !habitKept) || (r.val.DeletedAt != nil && r.val.DeletedAt.Before(before)) {
+				summary.HabitEntries++
+				continue
+			}
+			entries[id] = r
+		}
+	}
+
+	tasks := s.tasks
+	if models.HasPurgeKind(kinds, models.PurgeTasks) {
+		// Plan history and templates still show a task, so it has to stay
+		referenced := make(map[string]bool)
+		for _, revisions := range plans {
+			for _, p := range revisions {
+				for _, ms := range p.slots {
+					referenced[ms.slot.TaskID] = true
+				}
+			}
+		}
+		for _, t := range s.templates {
+			for _, slot := range t.Slots {
+				referenced[slot.TaskID] = true
+			}
+		}
+
+		tasks = make(map[string]record[models.Task], len(s.tasks))
+		purged := make(map[string]bool)
+		for id, r := range s.tasks {
+			if expired(r.val.DeletedAt) {
+				if !referenced[id] {
+					summary.Tasks++
+					purged[id] = true
+					continue
+				}
+				summary.KeptTasks++
+			}
+			tasks[id] = r
+		}
+		if len(purged) > 0 {
+			kept := make(map[string]record[models.SlotReminder], len(reminders))
+			for id, r := range reminders {
+				if !purged[r.val.TaskID] {
+					kept[id] = r
+				}
+			}
+			reminders = kept
+		}
+	}
+
+	if !dryRun {
+		s.amendments = s.keptAmendments(plans)
+		s.plans, s.reminders = plans, reminders
+		s.habits, s.habitEntries = habits, entries
+		s.tasks = tasks
+	}
+	return summary, nil
+}
+
+func (s *MemoryStore) PurgeItem(kind models.TrashKind, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch kind {
+	case models.TrashTask:
+		r, ok := s.tasks[id]
+		if !ok || r.val.DeletedAt == nil {
+			return fmt.Errorf("task not found or not deleted")
+		}
+		for _, revisions := range s.plans {
+			for _, p := range revisions {
+				for _, ms := range p.slots {
+					if ms.slot.TaskID == id {
+						return fmt.Errorf("task %s is still used by plans or templates", id)
+					}
+				}
+			}
+		}
+		for _, t := range s.templates {
+			for _, slot := range t.Slots {
+				if slot.TaskID == id {
+					return fmt.Errorf("task %s is still used by plans or templates", id)
+				}
+			}
+		}
+		for rid, r := range s.reminders {
+			if r.val.TaskID == id {
+				delete(s.reminders, rid)
+			}
+		}
+		delete(s.tasks, id)
+
+	case models.TrashPlan:
+		var kept []*memPlan
+		for _, p := range s.plans[id] {
+			if p.deletedAt == nil {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) == len(s.plans[id]) {
+			return fmt.Errorf("no deleted plans found for date: %s", id)
+		}
+		if len(kept) > 0 {
+			s.plans[id] = kept
+			s.amendments = s.keptAmendments(s.plans)
+			break
+		}
+		delete(s.plans, id)
+		s.amendments = s.keptAmendments(s.plans)
+		// Reminders belong to the day, so they go once no revision is left
+		for rid, r := range s.reminders {
+			if r.val.PlanDate == id {
+				delete(s.reminders, rid)
+			}
+		}
+
+	case models.TrashHabit:
+		r, ok := s.habits[id]
+		if !ok || r.val.DeletedAt == nil {
+			return fmt.Errorf("habit not found or not deleted")
+		}
+		for eid, e := range s.habitEntries {
+			if e.val.HabitID == id {
+				delete(s.habitEntries, eid)
+			}
+		}
+		delete(s.habits, id)
+
+	case models.TrashHabitEntry:
+		r, ok := s.habitEntries[id]
+		if !ok || r.val.DeletedAt == nil {
+			return fmt.Errorf("habit entry not found or not deleted")
+		}
+		delete(s.habitEntries, id)
+
+	case models.TrashOTEntry:
+		e, ok := s.otEntries[id]
+		if !ok || e.DeletedAt == nil {
+			return fmt.Errorf("OT entry not found or not deleted")
+		}
+		delete(s.otEntries, id)
+
+	default:
+		return fmt.Errorf("unknown kind %q", kind)
+	}
+	return nil
+}
+
+// keptAmendments returns the amendments whose plan revision is still in plans
+func (s *MemoryStore) keptAmendments(plans map[string][]*memPlan) []models.PlanAmendment {
+	var kept []models.PlanAmendment
+	for _, a := range s.amendments {
+		for _, p := range plans[a.Date] {
+			if p.revision == a.Revision {
+				kept = append(kept, a)
+				break
+			}
+		}
+	}
+	return kept
+}
@@ -0,0 +1,163 @@
+package storage_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func setupMemoryStore(t *testing.T) *storage.MemoryStore {
+	t.Helper()
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init memory store: %v", err)
+	}
+	return store
+}
+
+func TestMemoryStoreTasks(t *testing.T) {
+	store := setupMemoryStore(t)
+
+	task := models.Task{
+		ID:          "task-1",
+		Name:        "Read",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceWeekly, WeekdayMask: []time.Weekday{time.Monday}},
+		Active:      true,
+	}
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	got, err := store.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if got.Version != 1 {
+		t.Errorf("expected version 1, got %d", got.Version)
+	}
+
+	// Changing a returned task doesn't change the stored one
+	got.Recurrence.WeekdayMask[0] = time.Friday
+	again, _ := store.GetTask(task.ID)
+	if again.Recurrence.WeekdayMask[0] != time.Monday {
+		t.Errorf("stored task changed through a returned copy: %v", again.Recurrence.WeekdayMask)
+	}
+
+	// A stale version is a conflict
+	got.Name = "Read more"
+	if err := store.UpdateTask(got); err != nil {
+		t.Fatalf("failed to update task: %v", err)
+	}
+	var conflict *models.ConflictError
+	if err := store.UpdateTask(again); !errors.As(err, &conflict) {
+		t.Errorf("expected a conflict for a stale task, got %v", err)
+	}
+
+	if err := store.DeleteTask(task.ID); err != nil {
+		t.Fatalf("failed to delete task: %v", err)
+	}
+	if _, err := store.GetTask(task.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for a deleted task, got %v", err)
+	}
+	all, _ := store.GetAllTasksIncludingDeleted()
+	if len(all) != 1 || all[0].DeletedAt == nil {
+		t.Errorf("expected the deleted task to be kept, got %+v", all)
+	}
+}
+
+func TestMemoryStorePlanRevisions(t *testing.T) {
+	store := setupMemoryStore(t)
+
+	plan := models.DayPlan{
+		Date:  "2025-03-10",
+		Slots: []models.Slot{{Start: "09:00", End: "10:00", TaskID: "task-1", Status: constants.SlotStatusPlanned}},
+	}
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	// An unaccepted plan is replaced in place
+	plan.Slots[0].Start = "08:00"
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan again: %v", err)
+	}
+	got, err := store.GetPlan(plan.Date)
+	if err != nil {
+		t.Fatalf("failed to get plan: %v", err)
+	}
+	if got.Revision != 1 || got.Version != 2 || len(got.Slots) != 1 || got.Slots[0].Start != "08:00" {
+		t.Errorf("expected revision 1 version 2 starting at 08:00, got %+v", got)
+	}
+
+	// Accepting it means the next save is a new revision
+	acceptedAt := time.Now().UTC().Format(time.RFC3339)
+	got.AcceptedAt = &acceptedAt
+	if err := store.SavePlan(got); err != nil {
+		t.Fatalf("failed to accept plan: %v", err)
+	}
+	plan.Revision = 0
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save new revision: %v", err)
+	}
+	latest, _ := store.GetLatestPlanRevision(plan.Date)
+	if latest.Revision != 2 {
+		t.Errorf("expected revision 2, got %d", latest.Revision)
+	}
+
+	// An accepted revision can't be overwritten by a different plan
+	plan.Revision = 1
+	plan.Version = 0
+	if err := store.SavePlan(plan); err == nil {
+		t.Error("expected an error overwriting an accepted revision")
+	}
+
+	if err := store.DeletePlan(plan.Date); err != nil {
+		t.Fatalf("failed to delete plan: %v", err)
+	}
+	if _, err := store.GetPlan(plan.Date); err == nil {
+		t.Error("expected no plan after delete")
+	}
+	if err := store.RestorePlan(plan.Date); err != nil {
+		t.Fatalf("failed to restore plan: %v", err)
+	}
+	if restored, err := store.GetPlan(plan.Date); err != nil || restored.Revision != 2 {
+		t.Errorf("expected revision 2 after restore, got %+v, %v", restored, err)
+	}
+}
+
+func TestMemoryStoreEachSlotCallbackCanUseStore(t *testing.T) {
+	store := setupMemoryStore(t)
+
+	task := models.Task{ID: "task-1", Name: "Write", Kind: constants.TaskKindFlexible, DurationMin: 60, Active: true}
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	plan := models.DayPlan{
+		Date:  "2025-03-10",
+		Slots: []models.Slot{{Start: "09:00", End: "10:00", TaskID: task.ID, Status: constants.SlotStatusDone}},
+	}
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	// Writing from the callback must not deadlock
+	var names []string
+	err := store.EachSlot("2025-03-01", "2025-03-31", func(r models.SlotRecord) error {
+		names = append(names, r.TaskName)
+		task.LastDone = r.Date
+		return store.UpdateTask(task)
+	})
+	if err != nil {
+		t.Fatalf("EachSlot failed: %v", err)
+	}
+	if len(names) != 1 || names[0] != "Write" {
+		t.Errorf("expected one slot for Write, got %v", names)
+	}
+}
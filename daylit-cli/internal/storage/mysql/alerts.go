@@ -0,0 +1,193 @@
+package mysql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) AddAlert(alert models.Alert) error {
+	if err := alert.Validate(); err != nil {
+		return err
+	}
+
+	weekdaysJSON, err := json.Marshal(alert.Recurrence.WeekdayMask)
+	if err != nil {
+		return fmt.Errorf("failed to marshal weekdays: %w", err)
+	}
+
+	_, err = s.conn().Exec(`
+		INSERT INTO alerts (
+			id, message, time, date, 
+			recurrence_type, recurrence_interval, recurrence_weekdays,
+			recurrence_month_day, recurrence_month, recurrence_cron,
+			task_id, travel_min, urgency, sound,
+			active, last_sent, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		alert.ID, alert.Message, alert.Time, alert.Date,
+		string(alert.Recurrence.Type), alert.Recurrence.IntervalDays, string(weekdaysJSON),
+		alert.Recurrence.MonthDay, alert.Recurrence.Month, alert.Cron,
+		alert.TaskID, alert.TravelMin, alert.Urgency, alert.Sound,
+		alert.Active, alert.LastSent, alert.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert alert: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) GetAlert(id string) (models.Alert, error) {
+	var alert models.Alert
+	var weekdaysJSON string
+	var recurrenceType string
+	var lastSent *time.Time
+
+	err := s.conn().QueryRow(`
+		SELECT id, message, time, date,
+			recurrence_type, recurrence_interval, recurrence_weekdays,
+			recurrence_month_day, recurrence_month, recurrence_cron,
+			task_id, travel_min, urgency, sound,
+			active, last_sent, created_at
+		FROM alerts
+		WHERE id = ?
+	`, id).Scan(
+		&alert.ID, &alert.Message, &alert.Time, &alert.Date,
+		&recurrenceType, &alert.Recurrence.IntervalDays, &weekdaysJSON,
+		&alert.Recurrence.MonthDay, &alert.Recurrence.Month, &alert.Cron,
+		&alert.TaskID, &alert.TravelMin, &alert.Urgency, &alert.Sound,
+		&alert.Active, &lastSent, &alert.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return models.Alert{}, fmt.Errorf("alert not found")
+	}
+	if err != nil {
+		return models.Alert{}, fmt.Errorf("failed to get alert: %w", err)
+	}
+
+	alert.Recurrence.Type = constants.RecurrenceType(recurrenceType)
+	alert.LastSent = lastSent
+
+	if err := json.Unmarshal([]byte(weekdaysJSON), &alert.Recurrence.WeekdayMask); err != nil {
+		return models.Alert{}, fmt.Errorf("failed to unmarshal weekdays: %w", err)
+	}
+
+	return alert, nil
+}
+
+func (s *Store) GetAllAlerts() ([]models.Alert, error) {
+	rows, err := s.conn().Query(`
+		SELECT id, message, time, date,
+			recurrence_type, recurrence_interval, recurrence_weekdays,
+			recurrence_month_day, recurrence_month, recurrence_cron,
+			task_id, travel_min, urgency, sound,
+			active, last_sent, created_at
+		FROM alerts
+		ORDER BY time ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []models.Alert
+	for rows.Next() {
+		var alert models.Alert
+		var weekdaysJSON string
+		var recurrenceType string
+		var lastSent *time.Time
+
+		err := rows.Scan(
+			&alert.ID, &alert.Message, &alert.Time, &alert.Date,
+			&recurrenceType, &alert.Recurrence.IntervalDays, &weekdaysJSON,
+			&alert.Recurrence.MonthDay, &alert.Recurrence.Month, &alert.Cron,
+			&alert.TaskID, &alert.TravelMin, &alert.Urgency, &alert.Sound,
+			&alert.Active, &lastSent, &alert.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+
+		alert.Recurrence.Type = constants.RecurrenceType(recurrenceType)
+		alert.LastSent = lastSent
+
+		if err := json.Unmarshal([]byte(weekdaysJSON), &alert.Recurrence.WeekdayMask); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal weekdays: %w", err)
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+func (s *Store) UpdateAlert(alert models.Alert) error {
+	if err := alert.Validate(); err != nil {
+		return err
+	}
+
+	weekdaysJSON, err := json.Marshal(alert.Recurrence.WeekdayMask)
+	if err != nil {
+		return fmt.Errorf("failed to marshal weekdays: %w", err)
+	}
+
+	result, err := s.conn().Exec(`
+		UPDATE alerts SET
+			message = ?, time = ?, date = ?,
+			recurrence_type = ?, recurrence_interval = ?, recurrence_weekdays = ?,
+			recurrence_month_day = ?, recurrence_month = ?, recurrence_cron = ?,
+			task_id = ?, travel_min = ?, urgency = ?, sound = ?,
+			active = ?, last_sent = ?
+		WHERE id = ?
+	`,
+		alert.Message, alert.Time, alert.Date,
+		string(alert.Recurrence.Type), alert.Recurrence.IntervalDays, string(weekdaysJSON),
+		alert.Recurrence.MonthDay, alert.Recurrence.Month, alert.Cron,
+		alert.TaskID, alert.TravelMin, alert.Urgency, alert.Sound,
+		alert.Active, alert.LastSent, alert.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update alert: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("alert not found")
+	}
+
+	return nil
+}
+
+func (s *Store) DeleteAlert(id string) error {
+	result, err := s.conn().Exec(`DELETE FROM alerts WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("alert not found")
+	}
+
+	return nil
+}
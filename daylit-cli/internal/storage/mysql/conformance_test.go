@@ -0,0 +1,44 @@
+package mysql
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/storagetest"
+)
+
+// TestConformance runs the storage conformance suite against MySQL, each
+// test in a database of its own that is dropped when it ends
+// Set MYSQL_TEST_URL environment variable to run this test
+func TestConformance(t *testing.T) {
+	connStr := os.Getenv("MYSQL_TEST_URL")
+	if connStr == "" {
+		t.Skip("MYSQL_TEST_URL not set, skipping MySQL conformance test")
+	}
+	cfg, err := parseConnString(connStr)
+	if err != nil {
+		t.Fatalf("Failed to parse MYSQL_TEST_URL: %v", err)
+	}
+
+	base := cfg.DBName
+	n := 0
+	storagetest.Run(t, func(t *testing.T) storage.Provider {
+		n++
+		cfg.DBName = fmt.Sprintf("%s_conformance_%d_%d", base, os.Getpid(), n)
+		store := New(cfg.FormatDSN())
+		if err := store.Init(); err != nil {
+			t.Fatalf("Failed to initialize store: %v", err)
+		}
+		name := cfg.DBName
+		t.Cleanup(func() {
+			if _, err := store.GetDB().Exec("DROP DATABASE `" + strings.ReplaceAll(name, "`", "``") + "`"); err != nil {
+				t.Errorf("Failed to drop database %s: %v", name, err)
+			}
+			store.Close()
+		})
+		return store
+	})
+}
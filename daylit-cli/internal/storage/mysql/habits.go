@@ -0,0 +1,552 @@
+package mysql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// Habits
+
+func (s *Store) AddHabit(habit models.Habit) error {
+	return s.UpdateHabit(habit)
+}
+
+func (s *Store) GetHabit(id string) (models.Habit, error) {
+	row := s.conn().QueryRow(`
+		SELECT id, name, created_at, archived_at, deleted_at,
+			category, paused_from, paused_until,
+			reminder_time, reminder_days, reminder_enabled, last_reminded
+		FROM habits WHERE id = ? AND deleted_at IS NULL`, id)
+
+	var h models.Habit
+	var createdAt, reminderDays string
+	var archivedAt, deletedAt, lastReminded sql.NullString
+
+	err := row.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt, &h.Category, &h.PausedFrom, &h.PausedUntil,
+		&h.ReminderTime, &reminderDays, &h.ReminderEnabled, &lastReminded)
+	if err != nil {
+		return models.Habit{}, err
+	}
+
+	h.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return models.Habit{}, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if archivedAt.Valid {
+		t, err := time.Parse(time.RFC3339, archivedAt.String)
+		if err != nil {
+			return models.Habit{}, fmt.Errorf("failed to parse archived_at: %w", err)
+		}
+		h.ArchivedAt = &t
+	}
+	if deletedAt.Valid {
+		t, err := time.Parse(time.RFC3339, deletedAt.String)
+		if err != nil {
+			return models.Habit{}, fmt.Errorf("failed to parse deleted_at: %w", err)
+		}
+		h.DeletedAt = &t
+	}
+	if err := parseHabitReminder(&h, reminderDays, lastReminded); err != nil {
+		return models.Habit{}, err
+	}
+
+	return h, nil
+}
+
+func (s *Store) GetHabitByName(name string) (models.Habit, error) {
+	row := s.conn().QueryRow(`
+		SELECT id, name, created_at, archived_at, deleted_at,
+			category, paused_from, paused_until,
+			reminder_time, reminder_days, reminder_enabled, last_reminded
+		FROM habits WHERE name = ? AND deleted_at IS NULL`, name)
+
+	var h models.Habit
+	var createdAt, reminderDays string
+	var archivedAt, deletedAt, lastReminded sql.NullString
+
+	err := row.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt, &h.Category, &h.PausedFrom, &h.PausedUntil,
+		&h.ReminderTime, &reminderDays, &h.ReminderEnabled, &lastReminded)
+	if err != nil {
+		return models.Habit{}, err
+	}
+
+	h.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return models.Habit{}, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if archivedAt.Valid {
+		t, err := time.Parse(time.RFC3339, archivedAt.String)
+		if err != nil {
+			return models.Habit{}, fmt.Errorf("failed to parse archived_at: %w", err)
+		}
+		h.ArchivedAt = &t
+	}
+	if deletedAt.Valid {
+		t, err := time.Parse(time.RFC3339, deletedAt.String)
+		if err != nil {
+			return models.Habit{}, fmt.Errorf("failed to parse deleted_at: %w", err)
+		}
+		h.DeletedAt = &t
+	}
+	if err := parseHabitReminder(&h, reminderDays, lastReminded); err != nil {
+		return models.Habit{}, err
+	}
+
+	return h, nil
+}
+
+func (s *Store) GetAllHabits(includeArchived, includeDeleted bool) ([]models.Habit, error) {
+	query := "SELECT id, name, created_at, archived_at, deleted_at, category, paused_from, paused_until, reminder_time, reminder_days, reminder_enabled, last_reminded FROM habits WHERE 1=1"
+	if !includeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
+	if !includeArchived {
+		query += " AND archived_at IS NULL"
+	}
+	query += " ORDER BY created_at"
+
+	rows, err := s.conn().Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var habits []models.Habit
+	for rows.Next() {
+		var h models.Habit
+		var createdAt, reminderDays string
+		var archivedAt, deletedAt, lastReminded sql.NullString
+
+		err := rows.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt, &h.Category, &h.PausedFrom, &h.PausedUntil,
+			&h.ReminderTime, &reminderDays, &h.ReminderEnabled, &lastReminded)
+		if err != nil {
+			return nil, err
+		}
+
+		h.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at for habit %s: %w", h.ID, err)
+		}
+		if archivedAt.Valid {
+			t, err := time.Parse(time.RFC3339, archivedAt.String)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse archived_at for habit %s: %w", h.ID, err)
+			}
+			h.ArchivedAt = &t
+		}
+		if deletedAt.Valid {
+			t, err := time.Parse(time.RFC3339, deletedAt.String)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse deleted_at for habit %s: %w", h.ID, err)
+			}
+			h.DeletedAt = &t
+		}
+		if err := parseHabitReminder(&h, reminderDays, lastReminded); err != nil {
+			return nil, err
+		}
+
+		habits = append(habits, h)
+	}
+
+	return habits, nil
+}
+
+func (s *Store) UpdateHabit(habit models.Habit) error {
+	var archivedAt, deletedAt sql.NullString
+	if habit.ArchivedAt != nil {
+		archivedAt = sql.NullString{String: habit.ArchivedAt.Format(time.RFC3339), Valid: true}
+	}
+	if habit.DeletedAt != nil {
+		deletedAt = sql.NullString{String: habit.DeletedAt.Format(time.RFC3339), Valid: true}
+	}
+	reminderDays, lastReminded, err := formatHabitReminder(habit)
+	if err != nil {
+		return err
+	}
+
+	return upsert(s.conn(),
+		`UPDATE habits SET name = ?, archived_at = ?, deleted_at = ?, category = ?, paused_from = ?, paused_until = ?,
+			reminder_time = ?, reminder_days = ?, reminder_enabled = ?, last_reminded = ?
+		WHERE id = ?`,
+		[]interface{}{habit.Name, archivedAt, deletedAt, habit.Category, habit.PausedFrom, habit.PausedUntil, habit.ReminderTime, reminderDays, habit.ReminderEnabled, lastReminded, habit.ID},
+		`INSERT INTO habits (id, name, created_at, archived_at, deleted_at, category, paused_from, paused_until,
+			reminder_time, reminder_days, reminder_enabled, last_reminded)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		[]interface{}{habit.ID, habit.Name, habit.CreatedAt.Format(time.RFC3339), archivedAt, deletedAt, habit.Category, habit.PausedFrom, habit.PausedUntil,
+			habit.ReminderTime, reminderDays, habit.ReminderEnabled, lastReminded},
+	)
+}
+
+// parseHabitReminder fills in a habit's reminder from its stored reminder
+// days and last reminded time
+func parseHabitReminder(h *models.Habit, reminderDays string, lastReminded sql.NullString) error {
+	if reminderDays != "" {
+		if err := json.Unmarshal([]byte(reminderDays), &h.ReminderDays); err != nil {
+			return fmt.Errorf("failed to parse reminder_days for habit %s: %w", h.ID, err)
+		}
+	}
+	if lastReminded.Valid {
+		t, err := time.Parse(time.RFC3339, lastReminded.String)
+		if err != nil {
+			return fmt.Errorf("failed to parse last_reminded for habit %s: %w", h.ID, err)
+		}
+		h.LastReminded = &t
+	}
+	return nil
+}
+
+// formatHabitReminder returns a habit's reminder days and last reminded
+// time as stored
+func formatHabitReminder(habit models.Habit) (string, sql.NullString, error) {
+	var reminderDays string
+	if len(habit.ReminderDays) > 0 {
+		data, err := json.Marshal(habit.ReminderDays)
+		if err != nil {
+			return "", sql.NullString{}, fmt.Errorf("failed to marshal reminder days: %w", err)
+		}
+		reminderDays = string(data)
+	}
+	var lastReminded sql.NullString
+	if habit.LastReminded != nil {
+		lastReminded = sql.NullString{String: habit.LastReminded.Format(time.RFC3339), Valid: true}
+	}
+	return reminderDays, lastReminded, nil
+}
+
+func (s *Store) ArchiveHabit(id string) error {
+	result, err := s.conn().Exec(`
+		UPDATE habits SET archived_at = ? WHERE id = ? AND deleted_at IS NULL AND archived_at IS NULL`,
+		time.Now().Format(time.RFC3339), id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("habit not found or already archived/deleted")
+	}
+
+	return nil
+}
+
+func (s *Store) UnarchiveHabit(id string) error {
+	result, err := s.conn().Exec(`
+		UPDATE habits SET archived_at = NULL WHERE id = ? AND deleted_at IS NULL AND archived_at IS NOT NULL`,
+		id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("habit not found or not archived")
+	}
+
+	return nil
+}
+
+func (s *Store) DeleteHabit(id string) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The entries are marked with the habit's time so restoring it finds them
+	stamp := time.Now().Format(time.RFC3339)
+	result, err := tx.Exec(`
+		UPDATE habits SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`,
+		stamp, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("habit not found or already deleted")
+	}
+
+	settings, err := storedSettings(tx)
+	if err != nil {
+		return err
+	}
+	if settings.HabitDeleteEntries == constants.HabitDeleteEntriesDelete {
+		if _, err := tx.Exec(`
+			UPDATE habit_entries SET deleted_at = ? WHERE habit_id = ? AND deleted_at IS NULL`,
+			stamp, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) RestoreHabit(id string) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var deletedAt sql.NullString
+	err = tx.QueryRow("SELECT deleted_at FROM habits WHERE id = ? FOR UPDATE", id).Scan(&deletedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if !deletedAt.Valid {
+		return fmt.Errorf("habit not found or not deleted")
+	}
+
+	if _, err := tx.Exec("UPDATE habits SET deleted_at = NULL WHERE id = ?", id); err != nil {
+		return err
+	}
+	// Bring back the entries deleted with the habit
+	if _, err := tx.Exec(`
+		UPDATE habit_entries SET deleted_at = NULL WHERE habit_id = ? AND deleted_at = ?`,
+		id, deletedAt.String); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Habit Entries
+
+func (s *Store) AddHabitEntry(entry models.HabitEntry) error {
+	return s.UpdateHabitEntry(entry)
+}
+
+func (s *Store) GetHabitEntry(habitID, day string) (models.HabitEntry, error) {
+	row := s.conn().QueryRow(`
+		SELECT id, habit_id, day, note, created_at, updated_at, deleted_at
+		FROM habit_entries WHERE habit_id = ? AND day = ? AND deleted_at IS NULL`,
+		habitID, day)
+
+	var e models.HabitEntry
+	var createdAt, updatedAt string
+	var deletedAt sql.NullString
+
+	err := row.Scan(&e.ID, &e.HabitID, &e.Day, &e.Note, &createdAt, &updatedAt, &deletedAt)
+	if err != nil {
+		return models.HabitEntry{}, err
+	}
+
+	e.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return models.HabitEntry{}, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	e.UpdatedAt, err = time.Parse(time.RFC3339, updatedAt)
+	if err != nil {
+		return models.HabitEntry{}, fmt.Errorf("failed to parse updated_at: %w", err)
+	}
+	if deletedAt.Valid {
+		t, err := time.Parse(time.RFC3339, deletedAt.String)
+		if err != nil {
+			return models.HabitEntry{}, fmt.Errorf("failed to parse deleted_at: %w", err)
+		}
+		e.DeletedAt = &t
+	}
+
+	return e, nil
+}
+
+func (s *Store) GetHabitEntriesForDay(day string) ([]models.HabitEntry, error) {
+	rows, err := s.conn().Query(`
+		SELECT id, habit_id, day, note, created_at, updated_at, deleted_at
+		FROM habit_entries WHERE day = ? AND deleted_at IS NULL
+		ORDER BY created_at`, day)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.HabitEntry
+	for rows.Next() {
+		var e models.HabitEntry
+		var createdAt, updatedAt string
+		var deletedAt sql.NullString
+
+		err := rows.Scan(&e.ID, &e.HabitID, &e.Day, &e.Note, &createdAt, &updatedAt, &deletedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		e.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at for entry %s: %w", e.ID, err)
+		}
+		e.UpdatedAt, err = time.Parse(time.RFC3339, updatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse updated_at for entry %s: %w", e.ID, err)
+		}
+		if deletedAt.Valid {
+			t, err := time.Parse(time.RFC3339, deletedAt.String)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse deleted_at for entry %s: %w", e.ID, err)
+			}
+			e.DeletedAt = &t
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func (s *Store) GetHabitEntriesForHabit(habitID string, startDay, endDay string) ([]models.HabitEntry, error) {
+	rows, err := s.conn().Query(`
+		SELECT id, habit_id, day, note, created_at, updated_at, deleted_at
+		FROM habit_entries
+		WHERE habit_id = ? AND day >= ? AND day <= ? AND deleted_at IS NULL
+		ORDER BY day DESC`, habitID, startDay, endDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.HabitEntry
+	for rows.Next() {
+		var e models.HabitEntry
+		var createdAt, updatedAt string
+		var deletedAt sql.NullString
+
+		err := rows.Scan(&e.ID, &e.HabitID, &e.Day, &e.Note, &createdAt, &updatedAt, &deletedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		e.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at for entry %s: %w", e.ID, err)
+		}
+		e.UpdatedAt, err = time.Parse(time.RFC3339, updatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse updated_at for entry %s: %w", e.ID, err)
+		}
+		if deletedAt.Valid {
+			t, err := time.Parse(time.RFC3339, deletedAt.String)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse deleted_at for entry %s: %w", e.ID, err)
+			}
+			e.DeletedAt = &t
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func (s *Store) GetAllHabitEntries() ([]models.HabitEntry, error) {
+	rows, err := s.conn().Query(`
+		SELECT id, habit_id, day, note, created_at, updated_at, deleted_at
+		FROM habit_entries
+		ORDER BY day, habit_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.HabitEntry
+	for rows.Next() {
+		var e models.HabitEntry
+		var createdAt, updatedAt string
+		var deletedAt sql.NullString
+
+		err := rows.Scan(&e.ID, &e.HabitID, &e.Day, &e.Note, &createdAt, &updatedAt, &deletedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		e.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at for entry %s: %w", e.ID, err)
+		}
+		e.UpdatedAt, err = time.Parse(time.RFC3339, updatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse updated_at for entry %s: %w", e.ID, err)
+		}
+		if deletedAt.Valid {
+			t, err := time.Parse(time.RFC3339, deletedAt.String)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse deleted_at for entry %s: %w", e.ID, err)
+			}
+			e.DeletedAt = &t
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func (s *Store) UpdateHabitEntry(entry models.HabitEntry) error {
+	var deletedAt sql.NullString
+	if entry.DeletedAt != nil {
+		deletedAt = sql.NullString{String: entry.DeletedAt.Format(time.RFC3339), Valid: true}
+	}
+
+	_, err := s.conn().Exec(`
+		INSERT INTO habit_entries (id, habit_id, day, note, created_at, updated_at, deleted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			note = VALUES(note),
+			updated_at = VALUES(updated_at),
+			deleted_at = VALUES(deleted_at)`,
+		entry.ID, entry.HabitID, entry.Day, entry.Note,
+		entry.CreatedAt.Format(time.RFC3339), entry.UpdatedAt.Format(time.RFC3339), deletedAt)
+
+	return err
+}
+
+func (s *Store) DeleteHabitEntry(id string) error {
+	result, err := s.conn().Exec(`
+		UPDATE habit_entries SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`,
+		time.Now().Format(time.RFC3339), id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("habit entry not found or already deleted")
+	}
+
+	return nil
+}
+
+func (s *Store) RestoreHabitEntry(id string) error {
+	result, err := s.conn().Exec(`
+		UPDATE habit_entries SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`,
+		id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("habit entry not found or not deleted")
+	}
+
+	return nil
+}
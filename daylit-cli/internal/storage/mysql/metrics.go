@@ -0,0 +1,52 @@
+package mysql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) SaveDailyMetrics(m models.DailyMetrics) error {
+	computedAt := m.ComputedAt.Format(time.RFC3339)
+	return upsert(s.conn(),
+		`UPDATE metrics SET has_plan = ?, accepted = ?, planned_slots = ?, done_slots = ?, skipped_slots = ?, tracked_slots = ?,
+			drift_minutes = ?, on_track = ?, too_much = ?, unnecessary = ?, computed_at = ?
+		WHERE date = ?`,
+		[]interface{}{m.HasPlan, m.Accepted, m.PlannedSlots, m.DoneSlots, m.SkippedSlots, m.TrackedSlots,
+			m.DriftMinutes, m.OnTrack, m.TooMuch, m.Unnecessary, computedAt, m.Date},
+		`INSERT INTO metrics (date, has_plan, accepted, planned_slots, done_slots, skipped_slots, tracked_slots, drift_minutes, on_track, too_much, unnecessary, computed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		[]interface{}{m.Date, m.HasPlan, m.Accepted, m.PlannedSlots, m.DoneSlots, m.SkippedSlots, m.TrackedSlots,
+			m.DriftMinutes, m.OnTrack, m.TooMuch, m.Unnecessary, computedAt},
+	)
+}
+
+func (s *Store) GetDailyMetrics(startDay, endDay string) ([]models.DailyMetrics, error) {
+	rows, err := s.conn().Query(`
+		SELECT date, has_plan, accepted, planned_slots, done_slots, skipped_slots, tracked_slots, drift_minutes, on_track, too_much, unnecessary, computed_at
+		FROM metrics
+		WHERE date >= ? AND date <= ?
+		ORDER BY date`, startDay, endDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var metrics []models.DailyMetrics
+	for rows.Next() {
+		var m models.DailyMetrics
+		var computedAt string
+		if err := rows.Scan(&m.Date, &m.HasPlan, &m.Accepted, &m.PlannedSlots, &m.DoneSlots, &m.SkippedSlots, &m.TrackedSlots,
+			&m.DriftMinutes, &m.OnTrack, &m.TooMuch, &m.Unnecessary, &computedAt); err != nil {
+			return nil, err
+		}
+		m.ComputedAt, err = time.Parse(time.RFC3339, computedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse computed_at: %w", err)
+		}
+		metrics = append(metrics, m)
+	}
+
+	return metrics, rows.Err()
+}
@@ -0,0 +1,194 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) AddNotificationLog(entry models.NotificationLogEntry) error {
+	_, err := s.conn().Exec(`
+		INSERT INTO notification_log (
+			sent_at, kind, plan_date, slot_start, task_id, alert_id, message, channel, success, error
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.SentAt.UTC().Format(time.RFC3339), entry.Kind, entry.PlanDate, entry.SlotStart,
+		entry.TaskID, entry.AlertID, entry.Message, entry.Channel, entry.Success, entry.Error,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert notification log entry: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetNotificationLog(since time.Time, limit int) ([]models.NotificationLogEntry, error) {
+	query := `
+		SELECT id, sent_at, kind, plan_date, slot_start, task_id, alert_id, message, channel, success, error
+		FROM notification_log
+		WHERE sent_at >= ?
+		ORDER BY sent_at DESC, id DESC`
+	args := []interface{}{since.UTC().Format(time.RFC3339)}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.NotificationLogEntry
+	for rows.Next() {
+		var e models.NotificationLogEntry
+		var sentAt string
+		if err := rows.Scan(
+			&e.ID, &sentAt, &e.Kind, &e.PlanDate, &e.SlotStart, &e.TaskID, &e.AlertID,
+			&e.Message, &e.Channel, &e.Success, &e.Error,
+		); err != nil {
+			return nil, err
+		}
+		e.SentAt, err = time.Parse(time.RFC3339, sentAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sent_at: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (s *Store) AddOutboxItem(item models.OutboxItem) error {
+	_, err := s.conn().Exec(`
+		INSERT INTO notification_outbox (
+			created_at, kind, plan_date, slot_start, task_id, alert_id, message, urgency, sound,
+			attempts, next_attempt_at, last_error, status
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		item.CreatedAt.UTC().Format(time.RFC3339), item.Kind, item.PlanDate, item.SlotStart,
+		item.TaskID, item.AlertID, item.Message, item.Urgency, item.Sound,
+		item.Attempts, item.NextAttemptAt.UTC().Format(time.RFC3339), item.LastError, item.Status,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert outbox item: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetDueOutboxItems(now time.Time, limit int) ([]models.OutboxItem, error) {
+	query := outboxSelect + `
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY created_at, id`
+	args := []interface{}{models.OutboxPending, now.UTC().Format(time.RFC3339)}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox: %w", err)
+	}
+	defer rows.Close()
+	return scanOutboxItems(rows)
+}
+
+func (s *Store) UpdateOutboxItem(item models.OutboxItem) error {
+	result, err := s.conn().Exec(`
+		UPDATE notification_outbox
+		SET attempts = ?, next_attempt_at = ?, last_error = ?, status = ?
+		WHERE id = ?`,
+		item.Attempts, item.NextAttemptAt.UTC().Format(time.RFC3339), item.LastError, item.Status, item.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update outbox item: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("outbox item %d not found", item.ID)
+	}
+	return nil
+}
+
+func (s *Store) DeleteOutboxItem(id int64) error {
+	result, err := s.conn().Exec(`DELETE FROM notification_outbox WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete outbox item: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("outbox item %d not found", id)
+	}
+	return nil
+}
+
+func (s *Store) GetOutboxItems(since time.Time) ([]models.OutboxItem, error) {
+	rows, err := s.conn().Query(outboxSelect+`
+		WHERE created_at >= ?
+		ORDER BY created_at DESC, id DESC`,
+		since.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox: %w", err)
+	}
+	defer rows.Close()
+	return scanOutboxItems(rows)
+}
+
+const outboxSelect = `
+		SELECT id, created_at, kind, plan_date, slot_start, task_id, alert_id, message, urgency, sound,
+			attempts, next_attempt_at, last_error, status
+		FROM notification_outbox`
+
+func scanOutboxItems(rows *sql.Rows) ([]models.OutboxItem, error) {
+	var items []models.OutboxItem
+	for rows.Next() {
+		var item models.OutboxItem
+		var createdAt, nextAttemptAt string
+		if err := rows.Scan(
+			&item.ID, &createdAt, &item.Kind, &item.PlanDate, &item.SlotStart, &item.TaskID, &item.AlertID,
+			&item.Message, &item.Urgency, &item.Sound, &item.Attempts, &nextAttemptAt, &item.LastError, &item.Status,
+		); err != nil {
+			return nil, err
+		}
+		var err error
+		if item.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		if item.NextAttemptAt, err = time.Parse(time.RFC3339, nextAttemptAt); err != nil {
+			return nil, fmt.Errorf("failed to parse next_attempt_at: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (s *Store) ClaimNotification(key string, at time.Time) (bool, error) {
+	result, err := s.conn().Exec(`
+		INSERT IGNORE INTO notification_claims (claim_key, claimed_at)
+		VALUES (?, ?)`,
+		key, at.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim notification: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected == 1, nil
+}
+
+func (s *Store) DeleteNotificationClaims(before time.Time) error {
+	_, err := s.conn().Exec(`DELETE FROM notification_claims WHERE claimed_at < ?`, before.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to delete notification claims: %w", err)
+	}
+	return nil
+}
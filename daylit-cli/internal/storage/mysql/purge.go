@@ -0,0 +1,306 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) PurgeDeleted(before time.Time, kinds []models.PurgeKind, dryRun bool) (models.PurgeSummary, error) {
+	var summary models.PurgeSummary
+
+	tx, err := s.begin()
+	if err != nil {
+		return summary, err
+	}
+	defer tx.Rollback()
+
+	// Plans go first so the slots they free no longer keep tasks alive
+	if models.HasPurgeKind(kinds, models.PurgePlans) {
+		if err := purgePlans(tx, before, &summary); err != nil {
+			return models.PurgeSummary{}, fmt.Errorf("failed to purge plans: %w", err)
+		}
+	}
+	if models.HasPurgeKind(kinds, models.PurgeHabits) {
+		if err := purgeHabits(tx, before, &summary); err != nil {
+			return models.PurgeSummary{}, fmt.Errorf("failed to purge habits: %w", err)
+		}
+	}
+	if models.HasPurgeKind(kinds, models.PurgeTasks) {
+		if err := purgeTasks(tx, before, &summary); err != nil {
+			return models.PurgeSummary{}, fmt.Errorf("failed to purge tasks: %w", err)
+		}
+	}
+
+	if dryRun {
+		return summary, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return models.PurgeSummary{}, err
+	}
+	return summary, nil
+}
+
+// expiredIDs returns the ids from query, which selects id and deleted_at,
+// whose deletion falls before the cutoff
+func expiredIDs(tx *txn, query string, before time.Time) ([]string, error) {
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id, deletedAt string
+		if err := rows.Scan(&id, &deletedAt); err != nil {
+			return nil, err
+		}
+		if models.DeletedBefore(deletedAt, before) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, rows.Err()
+}
+
+func execCount(tx *txn, query string, args ...interface{}) (int, error) {
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
+
+func purgePlans(tx *txn, before time.Time, summary *models.PurgeSummary) error {
+	type planKey struct {
+		date     string
+		revision int
+	}
+
+	rows, err := tx.Query("SELECT date, revision, deleted_at FROM plans WHERE deleted_at IS NOT NULL")
+	if err != nil {
+		return err
+	}
+	var expired []planKey
+	for rows.Next() {
+		var key planKey
+		var deletedAt string
+		if err := rows.Scan(&key.date, &key.revision, &deletedAt); err != nil {
+			rows.Close()
+			return err
+		}
+		if models.DeletedBefore(deletedAt, before) {
+			expired = append(expired, key)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	dates := make(map[string]bool)
+	for _, key := range expired {
+		n, err := execCount(tx, "DELETE FROM slots WHERE plan_date = ? AND plan_revision = ?", key.date, key.revision)
+		if err != nil {
+			return err
+		}
+		summary.Slots += n
+		if _, err := tx.Exec("DELETE FROM plan_amendments WHERE plan_date = ? AND plan_revision = ?", key.date, key.revision); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM plans WHERE date = ? AND revision = ?", key.date, key.revision); err != nil {
+			return err
+		}
+		summary.Plans++
+		dates[key.date] = true
+	}
+
+	// Reminders belong to the day, so they go once no revision is left
+	for date := range dates {
+		if _, err := tx.Exec(
+			"DELETE FROM slot_reminders WHERE plan_date = ? AND NOT EXISTS (SELECT 1 FROM plans WHERE date = ?)",
+			date, date,
+		); err != nil {
+			return err
+		}
+	}
+
+	// Slots deleted on their own from plans that are still around
+	ids, err := expiredIDs(tx, "SELECT CAST(id AS CHAR), deleted_at FROM slots WHERE deleted_at IS NOT NULL", before)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, err := tx.Exec("DELETE FROM slots WHERE id = ?", id); err != nil {
+			return err
+		}
+		summary.Slots++
+	}
+	return nil
+}
+
+func purgeHabits(tx *txn, before time.Time, summary *models.PurgeSummary) error {
+	ids, err := expiredIDs(tx, "SELECT id, deleted_at FROM habits WHERE deleted_at IS NOT NULL", before)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		n, err := execCount(tx, "DELETE FROM habit_entries WHERE habit_id = ?", id)
+		if err != nil {
+			return err
+		}
+		summary.HabitEntries += n
+		if _, err := tx.Exec("DELETE FROM habits WHERE id = ?", id); err != nil {
+			return err
+		}
+		summary.Habits++
+	}
+
+	// Entries deleted on their own from habits that are still around
+	ids, err = expiredIDs(tx, "SELECT id, deleted_at FROM habit_entries WHERE deleted_at IS NOT NULL", before)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, err := tx.Exec("DELETE FROM habit_entries WHERE id = ?", id); err != nil {
+			return err
+		}
+		summary.HabitEntries++
+	}
+	return nil
+}
+
+func purgeTasks(tx *txn, before time.Time, summary *models.PurgeSummary) error {
+	ids, err := expiredIDs(tx, "SELECT id, deleted_at FROM tasks WHERE deleted_at IS NOT NULL", before)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		// Plan history and templates still show the task, so it has to stay
+		var refs int
+		if err := tx.QueryRow(
+			"SELECT (SELECT COUNT(*) FROM slots WHERE task_id = ?) + (SELECT COUNT(*) FROM day_template_slots WHERE task_id = ?)",
+			id, id,
+		).Scan(&refs); err != nil {
+			return err
+		}
+		if refs > 0 {
+			summary.KeptTasks++
+			continue
+		}
+
+		if _, err := tx.Exec("DELETE FROM slot_reminders WHERE task_id = ?", id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM tasks WHERE id = ?", id); err != nil {
+			return err
+		}
+		summary.Tasks++
+	}
+	return nil
+}
+
+func (s *Store) PurgeItem(kind models.TrashKind, id string) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	switch kind {
+	case models.TrashTask:
+		err = purgeTaskItem(tx, id)
+	case models.TrashPlan:
+		err = purgePlanItem(tx, id)
+	case models.TrashHabit:
+		err = purgeHabitItem(tx, id)
+	case models.TrashHabitEntry:
+		err = purgeDeletedRow(tx, "DELETE FROM habit_entries WHERE id = ? AND deleted_at IS NOT NULL", id, "habit entry not found or not deleted")
+	case models.TrashOTEntry:
+		err = purgeDeletedRow(tx, "DELETE FROM ot_entries WHERE day = ? AND deleted_at IS NOT NULL", id, "OT entry not found or not deleted")
+	default:
+		err = fmt.Errorf("unknown kind %q", kind)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// purgeDeletedRow runs a delete guarded by deleted_at and fails with
+// notFound when it matched nothing
+func purgeDeletedRow(tx *txn, query, id, notFound string) error {
+	n, err := execCount(tx, query, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New(notFound)
+	}
+	return nil
+}
+
+func purgeTaskItem(tx *txn, id string) error {
+	var deletedAt sql.NullString
+	err := tx.QueryRow("SELECT deleted_at FROM tasks WHERE id = ?", id).Scan(&deletedAt)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !deletedAt.Valid) {
+		return fmt.Errorf("task not found or not deleted")
+	}
+	if err != nil {
+		return err
+	}
+
+	var refs int
+	if err := tx.QueryRow(
+		"SELECT (SELECT COUNT(*) FROM slots WHERE task_id = ?) + (SELECT COUNT(*) FROM day_template_slots WHERE task_id = ?)",
+		id, id,
+	).Scan(&refs); err != nil {
+		return err
+	}
+	if refs > 0 {
+		return fmt.Errorf("task %s is still used by plans or templates", id)
+	}
+
+	if _, err := tx.Exec("DELETE FROM slot_reminders WHERE task_id = ?", id); err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM tasks WHERE id = ?", id)
+	return err
+}
+
+func purgePlanItem(tx *txn, date string) error {
+	if _, err := tx.Exec(
+		"DELETE FROM slots WHERE plan_date = ? AND plan_revision IN (SELECT revision FROM plans WHERE date = ? AND deleted_at IS NOT NULL)",
+		date, date,
+	); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
+		"DELETE FROM plan_amendments WHERE plan_date = ? AND plan_revision IN (SELECT revision FROM plans WHERE date = ? AND deleted_at IS NOT NULL)",
+		date, date,
+	); err != nil {
+		return err
+	}
+	if err := purgeDeletedRow(tx, "DELETE FROM plans WHERE date = ? AND deleted_at IS NOT NULL", date, "no deleted plans found for date: "+date); err != nil {
+		return err
+	}
+	_, err := tx.Exec(
+		"DELETE FROM slot_reminders WHERE plan_date = ? AND NOT EXISTS (SELECT 1 FROM plans WHERE date = ?)",
+		date, date,
+	)
+	return err
+}
+
+func purgeHabitItem(tx *txn, id string) error {
+	if _, err := tx.Exec(
+		"DELETE FROM habit_entries WHERE habit_id = ? AND EXISTS (SELECT 1 FROM habits WHERE id = ? AND deleted_at IS NOT NULL)",
+		id, id,
+	); err != nil {
+		return err
+	}
+	return purgeDeletedRow(tx, "DELETE FROM habits WHERE id = ? AND deleted_at IS NOT NULL", id, "habit not found or not deleted")
+}
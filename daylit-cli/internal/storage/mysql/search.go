@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// Search looks for the terms in task names, habit names, OT entries and the
+// feedback notes of the latest plan revisions. MySQL's full-text indexes skip
+// short words and stopwords, so rows are narrowed down with LIKE and then
+// matched against the start of each word, as the other stores do. Results
+// are ordered newest first rather than by relevance.
+func (s *Store) Search(query string, limit int) ([]models.SearchResult, error) {
+	terms := models.SearchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	// Terms are only letters and digits, so they need no escaping in LIKE
+	var args []interface{}
+	like := func(column string) string {
+		conds := make([]string, len(terms))
+		for i, term := range terms {
+			conds[i] = "LOWER(" + column + ") LIKE ?"
+			args = append(args, "%"+term+"%")
+		}
+		return strings.Join(conds, " AND ")
+	}
+
+	rows, err := s.conn().Query(`
+		SELECT 'task', id, '', name, '' FROM tasks
+		WHERE deleted_at IS NULL AND `+like("name")+`
+		UNION ALL
+		SELECT 'habit', id, '', name, '' FROM habits
+		WHERE deleted_at IS NULL AND `+like("name")+`
+		UNION ALL
+		SELECT 'ot', id, day, title, note FROM ot_entries
+		WHERE deleted_at IS NULL AND `+like("CONCAT(title, ' ', note)")+`
+		UNION ALL
+		SELECT 'note', CAST(s.id AS CHAR), s.plan_date, COALESCE(t.name, ''), s.feedback_note
+		FROM slots s
+		LEFT JOIN tasks t ON t.id = s.task_id
+		WHERE s.deleted_at IS NULL AND COALESCE(s.feedback_note, '') <> ''
+			AND s.plan_revision = (
+				SELECT MAX(p.revision) FROM plans p WHERE p.date = s.plan_date AND p.deleted_at IS NULL
+			)
+			AND `+like("s.feedback_note")+`
+		ORDER BY 3 DESC`,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query search results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	for rows.Next() {
+		var r models.SearchResult
+		if err := rows.Scan(&r.Kind, &r.ID, &r.Date, &r.Title, &r.Snippet); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		text := r.Title
+		if r.Kind == constants.SearchKindNote {
+			text = r.Snippet
+		} else if r.Snippet != "" {
+			text += " " + r.Snippet
+		}
+		if !models.MatchesSearchTerms(text, terms) {
+			continue
+		}
+		results = append(results, r)
+		if limit > 0 && len(results) == limit {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search results: %w", err)
+	}
+
+	return results, nil
+}
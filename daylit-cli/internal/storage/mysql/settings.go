@@ -0,0 +1,110 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func (s *Store) GetSettings() (storage.Settings, error) {
+	settingsMap, err := readSettings(s.conn())
+	if err != nil {
+		return storage.Settings{}, err
+	}
+
+	if len(settingsMap) == 0 {
+		return storage.Settings{}, fmt.Errorf("settings not found")
+	}
+
+	settings, err := models.MapToSettings(settingsMap)
+	if err != nil {
+		return storage.Settings{}, err
+	}
+
+	return settings, nil
+}
+
+// querier runs queries on the database or in a transaction
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// readSettings loads the stored settings as key-value pairs
+func readSettings(q querier) (map[string]string, error) {
+	rows, err := q.Query("SELECT `key`, value FROM settings")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settingsMap := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		settingsMap[key] = value
+	}
+	return settingsMap, rows.Err()
+}
+
+// storedSettings returns the settings, with defaults for any never saved,
+// for the store's own rules such as what a delete takes with it
+func storedSettings(q querier) (models.Settings, error) {
+	settingsMap, err := readSettings(q)
+	if err != nil {
+		return models.Settings{}, fmt.Errorf("failed to read settings: %w", err)
+	}
+	settings, err := models.MapToSettings(settingsMap)
+	if err != nil {
+		return models.Settings{}, err
+	}
+	models.ApplyDefaultSettings(&settings)
+	return settings, nil
+}
+
+func (s *Store) SaveSettings(settings storage.Settings) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Reject the save if someone else saved the settings after they were loaded
+	var version int
+	var stored string
+	err = tx.QueryRow("SELECT value FROM settings WHERE `key` = ? FOR UPDATE", constants.SettingVersion).Scan(&stored)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check settings version: %w", err)
+	}
+	if err == nil {
+		if _, err := fmt.Sscanf(stored, "%d", &version); err != nil {
+			return fmt.Errorf("parsing settings version: %w", err)
+		}
+	}
+	if settings.Version > 0 && version != settings.Version {
+		return &models.ConflictError{Record: "settings"}
+	}
+
+	// MySQL uses INSERT ... ON DUPLICATE KEY UPDATE for upsert. key is a
+	// reserved word, so it's quoted.
+	stmt, err := tx.Prepare("INSERT INTO settings (`key`, value) VALUES (?, ?) ON DUPLICATE KEY UPDATE value = VALUES(value)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	settingsMap := models.SettingsToMap(settings)
+	settingsMap[constants.SettingVersion] = strconv.Itoa(version + 1)
+	for key, value := range settingsMap {
+		if _, err := stmt.Exec(key, value); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,124 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// GetTaskStats returns per-task slot totals for the inclusive date range
+func (s *Store) GetTaskStats(startDay, endDay string) ([]models.TaskStats, error) {
+	// Slot minutes are derived from the HH:MM start and end times
+	rows, err := s.conn().Query(`
+		SELECT
+			x.task_id,
+			COALESCE(t.name, x.task_id),
+			COALESCE(t.priority, 0),
+			COALESCE(t.context, ''),
+			COUNT(*),
+			COALESCE(SUM(x.minutes), 0),
+			COALESCE(SUM(CASE WHEN x.done THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN x.done THEN x.minutes ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN x.accepted THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN x.accepted AND x.done THEN 1 ELSE 0 END), 0)
+		FROM (
+			SELECT
+				s.task_id,
+				s.status = 'done' AS done,
+				p.accepted_at IS NOT NULL AS accepted,
+				GREATEST(0,
+					(CAST(substr(s.end_time, 1, 2) AS SIGNED) * 60 + CAST(substr(s.end_time, 4, 2) AS SIGNED)) -
+					(CAST(substr(s.start_time, 1, 2) AS SIGNED) * 60 + CAST(substr(s.start_time, 4, 2) AS SIGNED))
+				) AS minutes
+			FROM slots s
+			JOIN plans p ON p.date = s.plan_date AND p.revision = s.plan_revision
+			WHERE s.plan_date BETWEEN ? AND ?
+				AND s.deleted_at IS NULL
+				AND p.deleted_at IS NULL
+				AND p.revision = (
+					SELECT MAX(p2.revision) FROM plans p2 WHERE p2.date = p.date AND p2.deleted_at IS NULL
+				)
+		) x
+		LEFT JOIN tasks t ON t.id = x.task_id
+		GROUP BY x.task_id, t.name, t.priority, t.context
+		ORDER BY 6 DESC, 2`,
+		startDay, endDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.TaskStats
+	for rows.Next() {
+		var st models.TaskStats
+		if err := rows.Scan(
+			&st.TaskID, &st.TaskName, &st.Priority, &st.Context,
+			&st.PlannedSlots, &st.PlannedMinutes,
+			&st.DoneSlots, &st.DoneMinutes,
+			&st.AcceptedSlots, &st.AcceptedDoneSlots,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan task stats: %w", err)
+		}
+		stats = append(stats, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetHabitCategoryStats returns habit days done and due per category for the
+// inclusive date range
+func (s *Store) GetHabitCategoryStats(startDay, endDay string) ([]models.HabitCategoryStats, error) {
+	settings, err := storedSettings(s.conn())
+	if err != nil {
+		return nil, err
+	}
+	keepArchived := settings.ArchivedHabitStats == constants.ArchivedHabitStatsKeep
+
+	// Habits are due from the local date they were created, which is the
+	// date part of created_at, to the end of the range or, for archived
+	// habits kept in the stats, the day they were archived. Paused days are
+	// neither due nor done.
+	rows, err := s.conn().Query(`
+		SELECT x.category, COUNT(*), COALESCE(SUM(x.done), 0), COALESCE(SUM(x.due), 0)
+		FROM (
+			SELECT
+				h.category,
+				(SELECT COUNT(*) FROM habit_entries e
+					WHERE e.habit_id = h.id AND e.deleted_at IS NULL AND e.day BETWEEN ? AND h.last_day
+						AND NOT (e.day BETWEEN h.paused_from AND h.paused_until)) AS done,
+				GREATEST(0, DATEDIFF(h.last_day, GREATEST(?, SUBSTRING(h.created_at, 1, 10))) + 1) -
+				CASE WHEN h.paused_from = '' THEN 0 ELSE
+					GREATEST(0, DATEDIFF(LEAST(h.last_day, h.paused_until), GREATEST(?, SUBSTRING(h.created_at, 1, 10), h.paused_from)) + 1)
+				END AS due
+			FROM (
+				SELECT *, LEAST(?, COALESCE(SUBSTRING(archived_at, 1, 10), ?)) AS last_day
+				FROM habits
+				WHERE deleted_at IS NULL AND (archived_at IS NULL OR ?)
+			) h
+		) x
+		GROUP BY x.category
+		ORDER BY x.category`,
+		startDay, startDay, startDay, endDay, endDay, keepArchived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query habit category stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.HabitCategoryStats
+	for rows.Next() {
+		var st models.HabitCategoryStats
+		if err := rows.Scan(&st.Category, &st.Habits, &st.DoneDays, &st.DueDays); err != nil {
+			return nil, fmt.Errorf("failed to scan habit category stats: %w", err)
+		}
+		stats = append(stats, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating habit category stats: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,307 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/migration"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/migrations"
+)
+
+// defaultPort is the port MySQL and MariaDB listen on unless told otherwise
+const defaultPort = "3306"
+
+type Store struct {
+	connStr string
+	db      *sql.DB
+	// tx is the transaction of a store handed out by WithTx, and savepoints
+	// counts the savepoints begun in it
+	tx         *sql.Tx
+	savepoints int
+}
+
+var (
+	ErrInvalidConnectionString = errors.New("invalid MySQL connection string")
+	ErrEmbeddedCredentials     = errors.New("connection string must not contain a password")
+)
+
+func New(connStr string) *Store {
+	return &Store{
+		connStr: connStr,
+	}
+}
+
+// IsConnString reports whether connStr looks like a MySQL or MariaDB
+// connection string: a mysql:// or mariadb:// URL, or a Go driver DSN such
+// as "user@tcp(host:3306)/daylit"
+func IsConnString(connStr string) bool {
+	return strings.HasPrefix(connStr, "mysql://") ||
+		strings.HasPrefix(connStr, "mariadb://") ||
+		strings.Contains(connStr, "@tcp(") ||
+		strings.Contains(connStr, "@unix(") ||
+		strings.HasPrefix(connStr, "tcp(") ||
+		strings.HasPrefix(connStr, "unix(")
+}
+
+// parseConnString reads a mysql:// or mariadb:// URL or a driver DSN into a
+// driver config. The database defaults to daylit.
+func parseConnString(connStr string) (*mysqldriver.Config, error) {
+	dsn := connStr
+	if strings.HasPrefix(connStr, "mysql://") || strings.HasPrefix(connStr, "mariadb://") {
+		u, err := url.Parse(connStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse connection URL: %v", err)
+		}
+		if u.Host == "" {
+			return nil, errors.New("connection URL is missing a host")
+		}
+		host := u.Host
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), defaultPort)
+		}
+		dsn = fmt.Sprintf("tcp(%s)/%s", host, strings.TrimPrefix(u.Path, "/"))
+		if u.User != nil {
+			dsn = u.User.String() + "@" + dsn
+		}
+		if u.RawQuery != "" {
+			dsn += "?" + u.RawQuery
+		}
+	}
+
+	cfg, err := mysqldriver.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DBName == "" {
+		cfg.DBName = constants.AppName
+	}
+	return cfg, nil
+}
+
+// ValidateConnString checks if a connection string is a valid MySQL
+// connection string (URL or DSN) and ensures it does not contain a
+// password.
+//
+// It returns true if the connection string is valid and contains no password.
+// Otherwise, it returns false and an error describing the issue.
+func ValidateConnString(connStr string) (bool, error) {
+	if strings.TrimSpace(connStr) == "" {
+		return false, fmt.Errorf("%w: connection string cannot be empty", ErrInvalidConnectionString)
+	}
+
+	cfg, err := parseConnString(connStr)
+	if err != nil {
+		return false, fmt.Errorf("%w: invalid connection string format: %v", ErrInvalidConnectionString, err)
+	}
+	if cfg.Passwd != "" {
+		return false, ErrEmbeddedCredentials
+	}
+
+	return true, nil
+}
+
+// driverConfig returns the driver config for the store's connection string.
+// Migration files hold several statements each, and alerts store their
+// times as DATETIME, so both are switched on. Updates report the rows they
+// matched, as in the other stores, not just the rows they changed.
+func (s *Store) driverConfig() (*mysqldriver.Config, error) {
+	cfg, err := parseConnString(s.connStr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConnectionString, err)
+	}
+	cfg.MultiStatements = true
+	cfg.ClientFoundRows = true
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+	return cfg, nil
+}
+
+// open connects to the store's database and configures the connection pool
+func (s *Store) open(cfg *mysqldriver.Config) (*sql.DB, error) {
+	connector, err := mysqldriver.NewConnector(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db := sql.OpenDB(connector)
+
+	// Configure connection pool parameters to avoid connection exhaustion.
+	// MySQL closes idle connections after wait_timeout, so recycle them first.
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(25)
+	db.SetConnMaxLifetime(5 * time.Minute)
+	return db, nil
+}
+
+func (s *Store) Init() error {
+	cfg, err := s.driverConfig()
+	if err != nil {
+		return err
+	}
+
+	// Create the database if it doesn't exist, connected without selecting it
+	serverCfg := cfg.Clone()
+	serverCfg.DBName = ""
+	server, err := s.open(serverCfg)
+	if err != nil {
+		return err
+	}
+	_, err = server.Exec("CREATE DATABASE IF NOT EXISTS `" + strings.ReplaceAll(cfg.DBName, "`", "``") + "` CHARACTER SET utf8mb4")
+	server.Close()
+	if err != nil {
+		return fmt.Errorf("failed to create database: %w", err)
+	}
+
+	db, err := s.open(cfg)
+	if err != nil {
+		return err
+	}
+	s.db = db
+
+	// Test connection
+	if err := s.db.Ping(); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// Run migrations
+	if err := s.runMigrations(); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	// Initialize default settings if not present or incomplete
+	settings, err := s.GetSettings()
+	if err != nil || settings.DayStart == "" {
+		defaultSettings := storage.Settings{
+			DayStart:                   constants.DefaultDayStart,
+			DayEnd:                     constants.DefaultDayEnd,
+			DefaultBlockMin:            constants.DefaultBlockMin,
+			NotificationsEnabled:       constants.DefaultNotificationsEnabled,
+			NotifyBlockStart:           constants.DefaultNotifyBlockStart,
+			NotifyBlockEnd:             constants.DefaultNotifyBlockEnd,
+			BlockStartOffsetMin:        constants.DefaultBlockStartOffsetMin,
+			BlockEndOffsetMin:          constants.DefaultBlockEndOffsetMin,
+			NotificationGracePeriodMin: constants.DefaultNotificationGracePeriodMin,
+			Timezone:                   constants.DefaultTimezone,
+			Theme:                      constants.DefaultTheme,
+			MorningPlan:                constants.DefaultMorningPlan,
+		}
+		if err := s.SaveSettings(defaultSettings); err != nil {
+			return fmt.Errorf("failed to save default settings: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) Load() error {
+	if s.db != nil {
+		return nil
+	}
+
+	cfg, err := s.driverConfig()
+	if err != nil {
+		return err
+	}
+	db, err := s.open(cfg)
+	if err != nil {
+		return err
+	}
+	s.db = db
+
+	// Test connection
+	if err := s.db.Ping(); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// Validate schema version using embedded migrations
+	if err := s.validateSchemaVersion(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Store) Close() error {
+	if s.db != nil {
+		return s.db.Close()
+	}
+	return nil
+}
+
+// Ping checks that the database server can be reached, giving up after
+// constants.HealthCheckTimeout
+func (s *Store) Ping() error {
+	if s.db == nil {
+		return fmt.Errorf("database not loaded")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.HealthCheckTimeout)
+	defer cancel()
+	return s.db.PingContext(ctx)
+}
+
+func (s *Store) runMigrations() error {
+	// Get the embedded MySQL migrations sub-filesystem
+	subFS, err := migrations.Backend("mysql")
+	if err != nil {
+		return err
+	}
+
+	runner := migration.NewRunner(s.db, subFS)
+	_, err = runner.ApplyMigrations(func(msg string) {
+		fmt.Println(msg)
+	})
+	return err
+}
+
+func (s *Store) validateSchemaVersion() error {
+	subFS, err := migrations.Backend("mysql")
+	if err != nil {
+		return err
+	}
+
+	runner := migration.NewRunner(s.db, subFS)
+	return runner.ValidateVersion()
+}
+
+func (s *Store) GetConfigPath() string {
+	// Return a non-sensitive identifier instead of the full connection string
+	return "mysql"
+}
+
+// GetDB returns the underlying database connection.
+// Returns nil if the database has not been initialized or loaded.
+func (s *Store) GetDB() *sql.DB {
+	return s.db
+}
+
+// execer runs statements on a database or inside a transaction
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// upsert runs update and, if it matched no row, insert. INSERT ... ON
+// DUPLICATE KEY UPDATE would also fire on a clash with another unique column,
+// silently updating a different record that has the same name; this way only
+// the record with the same ID is updated and a taken name is still an error.
+func upsert(db execer, update string, updateArgs []interface{}, insert string, insertArgs []interface{}) error {
+	res, err := db.Exec(update, updateArgs...)
+	if err != nil {
+		return err
+	}
+	// The driver reports matched rather than changed rows (ClientFoundRows),
+	// so an update that leaves the row as it was still counts
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	_, err = db.Exec(insert, insertArgs...)
+	return err
+}
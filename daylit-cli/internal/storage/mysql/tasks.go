@@ -0,0 +1,518 @@
+package mysql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) AddTask(task models.Task) error {
+	return s.UpdateTask(task)
+}
+
+func (s *Store) GetTask(id string) (models.Task, error) {
+	row := s.conn().QueryRow(`
+SELECT id, name, kind, duration_min, earliest_start, latest_end, fixed_start, fixed_end,
+       recurrence_type, recurrence_interval, recurrence_weekdays, recurrence_month_day,
+       recurrence_week_occurrence, recurrence_month, recurrence_day_of_week, recurrence_start, recurrence_until, recurrence_count,
+       priority, energy_band, active, last_done, success_streak, avg_actual_duration, project_id, context,
+       notify_start_disabled, notify_offset_min, notify_message, notify_urgency, notify_sound, pool_id, nice_to_have, leisure, prep_min, travel_min, version, deleted_at
+FROM tasks WHERE id = ? AND deleted_at IS NULL`, id)
+
+	var t models.Task
+	var recType, recWeekdays, energyBand string
+	var active bool
+	var deletedAt sql.NullString
+	var recMonthDay, recWeekOccurrence, recMonth, recDayOfWeek, notifyOffset sql.NullInt64
+
+	err := row.Scan(
+		&t.ID, &t.Name, &t.Kind, &t.DurationMin, &t.EarliestStart, &t.LatestEnd, &t.FixedStart, &t.FixedEnd,
+		&recType, &t.Recurrence.IntervalDays, &recWeekdays, &recMonthDay, &recWeekOccurrence, &recMonth, &recDayOfWeek, &t.Recurrence.Start, &t.Recurrence.Until, &t.Recurrence.Count,
+		&t.Priority, &energyBand, &active, &t.LastDone, &t.SuccessStreak, &t.AvgActualDurationMin, &t.ProjectID, &t.Context,
+		&t.NotifyStartDisabled, &notifyOffset, &t.NotifyMessage, &t.NotifyUrgency, &t.NotifySound, &t.PoolID, &t.NiceToHave, &t.Leisure, &t.PrepMin, &t.TravelMin, &t.Version, &deletedAt,
+	)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	t.Recurrence.Type = constants.RecurrenceType(recType)
+	t.EnergyBand = constants.EnergyBand(energyBand)
+	t.Active = active
+
+	if recMonthDay.Valid {
+		t.Recurrence.MonthDay = int(recMonthDay.Int64)
+	}
+	if recWeekOccurrence.Valid {
+		t.Recurrence.WeekOccurrence = int(recWeekOccurrence.Int64)
+	}
+	if recMonth.Valid {
+		t.Recurrence.Month = int(recMonth.Int64)
+	}
+	if recDayOfWeek.Valid {
+		t.Recurrence.DayOfWeekInMonth = time.Weekday(recDayOfWeek.Int64)
+	}
+
+	if notifyOffset.Valid {
+		offset := int(notifyOffset.Int64)
+		t.NotifyOffsetMin = &offset
+	}
+
+	if deletedAt.Valid {
+		t.DeletedAt = &deletedAt.String
+	}
+
+	if recWeekdays != "" {
+		var weekdays []int
+		if err := json.Unmarshal([]byte(recWeekdays), &weekdays); err == nil {
+			for _, w := range weekdays {
+				t.Recurrence.WeekdayMask = append(t.Recurrence.WeekdayMask, time.Weekday(w))
+			}
+		}
+	}
+
+	return t, nil
+}
+
+func (s *Store) GetAllTasks() ([]models.Task, error) {
+	rows, err := s.conn().Query(`
+SELECT id, name, kind, duration_min, earliest_start, latest_end, fixed_start, fixed_end,
+       recurrence_type, recurrence_interval, recurrence_weekdays, recurrence_month_day,
+       recurrence_week_occurrence, recurrence_month, recurrence_day_of_week, recurrence_start, recurrence_until, recurrence_count,
+       priority, energy_band, active, last_done, success_streak, avg_actual_duration, project_id, context,
+       notify_start_disabled, notify_offset_min, notify_message, notify_urgency, notify_sound, pool_id, nice_to_have, leisure, prep_min, travel_min, version, deleted_at
+FROM tasks WHERE deleted_at IS NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []models.Task
+	for rows.Next() {
+		var t models.Task
+		var recType, recWeekdays, energyBand string
+		var active bool
+		var deletedAt sql.NullString
+		var recMonthDay, recWeekOccurrence, recMonth, recDayOfWeek, notifyOffset sql.NullInt64
+
+		err := rows.Scan(
+			&t.ID, &t.Name, &t.Kind, &t.DurationMin, &t.EarliestStart, &t.LatestEnd, &t.FixedStart, &t.FixedEnd,
+			&recType, &t.Recurrence.IntervalDays, &recWeekdays, &recMonthDay, &recWeekOccurrence, &recMonth, &recDayOfWeek, &t.Recurrence.Start, &t.Recurrence.Until, &t.Recurrence.Count,
+			&t.Priority, &energyBand, &active, &t.LastDone, &t.SuccessStreak, &t.AvgActualDurationMin, &t.ProjectID, &t.Context,
+			&t.NotifyStartDisabled, &notifyOffset, &t.NotifyMessage, &t.NotifyUrgency, &t.NotifySound, &t.PoolID, &t.NiceToHave, &t.Leisure, &t.PrepMin, &t.TravelMin, &t.Version, &deletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		t.Recurrence.Type = constants.RecurrenceType(recType)
+		t.EnergyBand = constants.EnergyBand(energyBand)
+		t.Active = active
+
+		if recMonthDay.Valid {
+			t.Recurrence.MonthDay = int(recMonthDay.Int64)
+		}
+		if recWeekOccurrence.Valid {
+			t.Recurrence.WeekOccurrence = int(recWeekOccurrence.Int64)
+		}
+		if recMonth.Valid {
+			t.Recurrence.Month = int(recMonth.Int64)
+		}
+		if recDayOfWeek.Valid {
+			t.Recurrence.DayOfWeekInMonth = time.Weekday(recDayOfWeek.Int64)
+		}
+
+		if notifyOffset.Valid {
+			offset := int(notifyOffset.Int64)
+			t.NotifyOffsetMin = &offset
+		}
+		if deletedAt.Valid {
+			t.DeletedAt = &deletedAt.String
+		}
+
+		if recWeekdays != "" {
+			var weekdays []int
+			if err := json.Unmarshal([]byte(recWeekdays), &weekdays); err == nil {
+				for _, w := range weekdays {
+					t.Recurrence.WeekdayMask = append(t.Recurrence.WeekdayMask, time.Weekday(w))
+				}
+			}
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, nil
+}
+
+func (s *Store) GetAllTasksIncludingDeleted() ([]models.Task, error) {
+	rows, err := s.conn().Query(`
+SELECT id, name, kind, duration_min, earliest_start, latest_end, fixed_start, fixed_end,
+       recurrence_type, recurrence_interval, recurrence_weekdays, recurrence_month_day,
+       recurrence_week_occurrence, recurrence_month, recurrence_day_of_week, recurrence_start, recurrence_until, recurrence_count,
+       priority, energy_band, active, last_done, success_streak, avg_actual_duration, project_id, context,
+       notify_start_disabled, notify_offset_min, notify_message, notify_urgency, notify_sound, pool_id, nice_to_have, leisure, prep_min, travel_min, version, deleted_at
+FROM tasks`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []models.Task
+	for rows.Next() {
+		var t models.Task
+		var recType, recWeekdays, energyBand sql.NullString
+		var earliestStart, latestEnd, fixedStart, fixedEnd, lastDone, projectID, taskContext sql.NullString
+		var durationMin, recurrenceInterval, priority, successStreak sql.NullInt64
+		var recMonthDay, recWeekOccurrence, recMonth, recDayOfWeek, notifyOffset sql.NullInt64
+		var avgActualDuration sql.NullFloat64
+		var active bool
+		var deletedAt sql.NullString
+
+		err := rows.Scan(
+			&t.ID, &t.Name, &t.Kind, &durationMin, &earliestStart, &latestEnd, &fixedStart, &fixedEnd,
+			&recType, &recurrenceInterval, &recWeekdays, &recMonthDay, &recWeekOccurrence, &recMonth, &recDayOfWeek, &t.Recurrence.Start, &t.Recurrence.Until, &t.Recurrence.Count,
+			&priority, &energyBand, &active, &lastDone, &successStreak, &avgActualDuration, &projectID, &taskContext,
+			&t.NotifyStartDisabled, &notifyOffset, &t.NotifyMessage, &t.NotifyUrgency, &t.NotifySound, &t.PoolID, &t.NiceToHave, &t.Leisure, &t.PrepMin, &t.TravelMin, &t.Version, &deletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if durationMin.Valid {
+			t.DurationMin = int(durationMin.Int64)
+		}
+		if recurrenceInterval.Valid {
+			t.Recurrence.IntervalDays = int(recurrenceInterval.Int64)
+		}
+		if recMonthDay.Valid {
+			t.Recurrence.MonthDay = int(recMonthDay.Int64)
+		}
+		if recWeekOccurrence.Valid {
+			t.Recurrence.WeekOccurrence = int(recWeekOccurrence.Int64)
+		}
+		if recMonth.Valid {
+			t.Recurrence.Month = int(recMonth.Int64)
+		}
+		if recDayOfWeek.Valid {
+			t.Recurrence.DayOfWeekInMonth = time.Weekday(recDayOfWeek.Int64)
+		}
+		if priority.Valid {
+			t.Priority = int(priority.Int64)
+		}
+		if successStreak.Valid {
+			t.SuccessStreak = int(successStreak.Int64)
+		}
+		if avgActualDuration.Valid {
+			t.AvgActualDurationMin = avgActualDuration.Float64
+		}
+		if recType.Valid {
+			t.Recurrence.Type = constants.RecurrenceType(recType.String)
+		}
+		if energyBand.Valid {
+			t.EnergyBand = constants.EnergyBand(energyBand.String)
+		}
+		if earliestStart.Valid {
+			t.EarliestStart = earliestStart.String
+		}
+		if latestEnd.Valid {
+			t.LatestEnd = latestEnd.String
+		}
+		if fixedStart.Valid {
+			t.FixedStart = fixedStart.String
+		}
+		if fixedEnd.Valid {
+			t.FixedEnd = fixedEnd.String
+		}
+		if lastDone.Valid {
+			t.LastDone = lastDone.String
+		}
+		if projectID.Valid {
+			t.ProjectID = projectID.String
+		}
+		if taskContext.Valid {
+			t.Context = taskContext.String
+		}
+		t.Active = active
+
+		if notifyOffset.Valid {
+			offset := int(notifyOffset.Int64)
+			t.NotifyOffsetMin = &offset
+		}
+		if deletedAt.Valid {
+			t.DeletedAt = &deletedAt.String
+		}
+
+		if recWeekdays.Valid && recWeekdays.String != "" {
+			var weekdays []int
+			if err := json.Unmarshal([]byte(recWeekdays.String), &weekdays); err == nil {
+				for _, w := range weekdays {
+					t.Recurrence.WeekdayMask = append(t.Recurrence.WeekdayMask, time.Weekday(w))
+				}
+			}
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, nil
+}
+
+func (s *Store) UpdateTask(task models.Task) error {
+	weekdaysJSON, err := json.Marshal(task.Recurrence.WeekdayMask)
+	if err != nil {
+		return fmt.Errorf("failed to marshal recurrence weekday mask: %w", err)
+	}
+
+	var deletedAt sql.NullString
+	if task.DeletedAt != nil {
+		deletedAt = sql.NullString{String: *task.DeletedAt, Valid: true}
+	}
+
+	var recMonthDay, recWeekOccurrence, recMonth, recDayOfWeek, notifyOffset sql.NullInt64
+	if task.Recurrence.MonthDay != 0 {
+		recMonthDay = sql.NullInt64{Int64: int64(task.Recurrence.MonthDay), Valid: true}
+	}
+	if task.Recurrence.WeekOccurrence != 0 || task.Recurrence.Type == constants.RecurrenceMonthlyDay {
+		// For monthly_day, -1 is a valid value (last occurrence), so we need to check the type
+		recWeekOccurrence = sql.NullInt64{Int64: int64(task.Recurrence.WeekOccurrence), Valid: true}
+	}
+	if task.Recurrence.Month != 0 {
+		recMonth = sql.NullInt64{Int64: int64(task.Recurrence.Month), Valid: true}
+	}
+	if task.Recurrence.Type == constants.RecurrenceMonthlyDay {
+		recDayOfWeek = sql.NullInt64{Int64: int64(task.Recurrence.DayOfWeekInMonth), Valid: true}
+	}
+	if task.NotifyOffsetMin != nil {
+		notifyOffset = sql.NullInt64{Int64: int64(*task.NotifyOffsetMin), Valid: true}
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// MySQL's upsert can't skip the update on a condition, so the version is
+	// checked first, with the row locked until the save commits. The save is
+	// rejected if someone else saved the task after it was loaded.
+	var stored int
+	err = tx.QueryRow("SELECT version FROM tasks WHERE id = ? FOR UPDATE", task.ID).Scan(&stored)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check task version: %w", err)
+	}
+	if err == nil && task.Version != 0 && stored != task.Version {
+		return &models.ConflictError{Record: "task", ID: task.ID}
+	}
+
+	_, err = tx.Exec(`
+INSERT INTO tasks (
+id, name, kind, duration_min, earliest_start, latest_end, fixed_start, fixed_end,
+recurrence_type, recurrence_interval, recurrence_weekdays, recurrence_month_day,
+recurrence_week_occurrence, recurrence_month, recurrence_day_of_week, recurrence_start, recurrence_until, recurrence_count,
+priority, energy_band, active, last_done, success_streak, avg_actual_duration, project_id, context,
+       notify_start_disabled, notify_offset_min, notify_message, notify_urgency, notify_sound, pool_id, nice_to_have, leisure, prep_min, travel_min, deleted_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+name = VALUES(name),
+kind = VALUES(kind),
+duration_min = VALUES(duration_min),
+earliest_start = VALUES(earliest_start),
+latest_end = VALUES(latest_end),
+fixed_start = VALUES(fixed_start),
+fixed_end = VALUES(fixed_end),
+recurrence_type = VALUES(recurrence_type),
+recurrence_interval = VALUES(recurrence_interval),
+recurrence_weekdays = VALUES(recurrence_weekdays),
+recurrence_month_day = VALUES(recurrence_month_day),
+recurrence_week_occurrence = VALUES(recurrence_week_occurrence),
+recurrence_month = VALUES(recurrence_month),
+recurrence_day_of_week = VALUES(recurrence_day_of_week),
+recurrence_start = VALUES(recurrence_start),
+recurrence_until = VALUES(recurrence_until),
+recurrence_count = VALUES(recurrence_count),
+priority = VALUES(priority),
+energy_band = VALUES(energy_band),
+active = VALUES(active),
+last_done = VALUES(last_done),
+success_streak = VALUES(success_streak),
+avg_actual_duration = VALUES(avg_actual_duration),
+project_id = VALUES(project_id),
+context = VALUES(context),
+notify_start_disabled = VALUES(notify_start_disabled),
+notify_offset_min = VALUES(notify_offset_min),
+notify_message = VALUES(notify_message),
+notify_urgency = VALUES(notify_urgency),
+notify_sound = VALUES(notify_sound),
+pool_id = VALUES(pool_id),
+nice_to_have = VALUES(nice_to_have),
+leisure = VALUES(leisure),
+prep_min = VALUES(prep_min),
+travel_min = VALUES(travel_min),
+deleted_at = VALUES(deleted_at),
+version = version + 1`,
+		task.ID, task.Name, task.Kind, task.DurationMin, task.EarliestStart, task.LatestEnd, task.FixedStart, task.FixedEnd,
+		task.Recurrence.Type, task.Recurrence.IntervalDays, string(weekdaysJSON), recMonthDay,
+		recWeekOccurrence, recMonth, recDayOfWeek, task.Recurrence.Start, task.Recurrence.Until, task.Recurrence.Count,
+		task.Priority, task.EnergyBand, task.Active, task.LastDone, task.SuccessStreak, task.AvgActualDurationMin, task.ProjectID, task.Context,
+		task.NotifyStartDisabled, notifyOffset, task.NotifyMessage, task.NotifyUrgency, task.NotifySound, task.PoolID, task.NiceToHave, task.Leisure, task.PrepMin, task.TravelMin, deletedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) DeleteTask(id string) error {
+	_, err := s.deleteTask(id, nil, time.Now())
+	return err
+}
+
+func (s *Store) DeleteTaskCascade(id string, removeSlots bool, now time.Time) (int, error) {
+	return s.deleteTask(id, &removeSlots, now)
+}
+
+// deleteTask soft-deletes a task and, when removeSlots says so or is nil
+// and task_delete_slots is "remove", its slots still upcoming at now
+func (s *Store) deleteTask(id string, removeSlots *bool, now time.Time) (int, error) {
+	tx, err := s.begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var deletedAt sql.NullString
+	err = tx.QueryRow("SELECT deleted_at FROM tasks WHERE id = ? FOR UPDATE", id).Scan(&deletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("task with id %s not found", id)
+		}
+		return 0, fmt.Errorf("failed to check task existence: %w", err)
+	}
+
+	if deletedAt.Valid {
+		return 0, fmt.Errorf("task with id %s is already deleted", id)
+	}
+
+	settings, err := storedSettings(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	// The slots are marked with the task's time so restoring it finds them
+	stamp := time.Now().UTC().Format(time.RFC3339)
+	if _, err := tx.Exec("UPDATE tasks SET deleted_at = ?, version = version + 1 WHERE id = ?", stamp, id); err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	if removeSlots == nil {
+		remove := settings.TaskDeleteSlots == constants.TaskDeleteSlotsRemove
+		removeSlots = &remove
+	}
+	if *removeSlots {
+		window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+		if err != nil {
+			return 0, err
+		}
+		if removed, err = deleteUpcomingSlots(tx, id, window, now, stamp); err != nil {
+			return 0, err
+		}
+	}
+	return removed, tx.Commit()
+}
+
+// deleteUpcomingSlots soft-deletes the slots of taskID in the latest
+// revision of accepted plans that are still upcoming at now
+func deleteUpcomingSlots(tx *txn, taskID string, window models.DayWindow, now time.Time, stamp string) (int, error) {
+	rows, err := tx.Query(`
+		SELECT s.id, s.plan_date, s.start_time, s.status
+		FROM slots s
+		JOIN plans p ON p.date = s.plan_date AND p.revision = s.plan_revision
+		WHERE s.task_id = ? AND s.deleted_at IS NULL AND p.date >= ?
+			AND p.accepted_at IS NOT NULL AND p.deleted_at IS NULL
+			AND p.revision = (SELECT MAX(revision) FROM plans WHERE date = p.date AND deleted_at IS NULL)`,
+		taskID, now.Format(constants.DateFormat))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the task's slots: %w", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		var date string
+		var slot models.Slot
+		if err := rows.Scan(&id, &date, &slot.Start, &slot.Status); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan slot: %w", err)
+		}
+		if window.Upcoming(date, slot, now) {
+			ids = append(ids, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating slots: %w", err)
+	}
+
+	for _, id := range ids {
+		if _, err := tx.Exec("UPDATE slots SET deleted_at = ? WHERE id = ?", stamp, id); err != nil {
+			return 0, err
+		}
+	}
+	if len(ids) > 0 {
+		if err := bumpTaskSlotPlans(tx, taskID, stamp); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), nil
+}
+
+// bumpTaskSlotPlans bumps the version of the plans holding slots of taskID
+// deleted at stamp
+func bumpTaskSlotPlans(tx *txn, taskID, stamp string) error {
+	_, err := tx.Exec(`
+		UPDATE plans SET version = version + 1
+		WHERE EXISTS (
+			SELECT 1 FROM slots s
+			WHERE s.plan_date = plans.date AND s.plan_revision = plans.revision
+				AND s.task_id = ? AND s.deleted_at = ?
+		)`,
+		taskID, stamp)
+	return err
+}
+
+func (s *Store) RestoreTask(id string) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var deletedAt sql.NullString
+	err = tx.QueryRow("SELECT deleted_at FROM tasks WHERE id = ? FOR UPDATE", id).Scan(&deletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("task with id %s not found", id)
+		}
+		return fmt.Errorf("failed to check task existence: %w", err)
+	}
+
+	if !deletedAt.Valid {
+		return fmt.Errorf("cannot restore a task that is not deleted: %s", id)
+	}
+
+	if _, err := tx.Exec("UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	// Bring back the slots deleted with the task
+	if err := bumpTaskSlotPlans(tx, id, deletedAt.String); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE slots SET deleted_at = NULL WHERE task_id = ? AND deleted_at = ?", id, deletedAt.String); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
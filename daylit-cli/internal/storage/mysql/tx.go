@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+// dbtx runs statements on the database, or in the transaction of a store
+// handed out by WithTx
+type dbtx interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// txn is a transaction begun by a store method. Inside WithTx it is a
+// savepoint of the enclosing transaction, so the method's writes still
+// succeed or fail together without ending the transaction around them.
+type txn struct {
+	*sql.Tx
+	savepoint string // Empty for a transaction of its own
+	done      bool
+}
+
+// conn is where the store's statements run
+func (s *Store) conn() dbtx {
+	if s.tx != nil {
+		return s.tx
+	}
+	return s.db
+}
+
+// begin starts a transaction, or a savepoint in the one WithTx opened
+func (s *Store) begin() (*txn, error) {
+	if s.tx == nil {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return nil, err
+		}
+		return &txn{Tx: tx}, nil
+	}
+	s.savepoints++
+	name := fmt.Sprintf("daylit_%d", s.savepoints)
+	if _, err := s.tx.Exec("SAVEPOINT " + name); err != nil {
+		return nil, err
+	}
+	return &txn{Tx: s.tx, savepoint: name}, nil
+}
+
+func (t *txn) Commit() error {
+	if t.savepoint == "" {
+		return t.Tx.Commit()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	_, err := t.Exec("RELEASE SAVEPOINT " + t.savepoint)
+	return err
+}
+
+func (t *txn) Rollback() error {
+	if t.savepoint == "" {
+		return t.Tx.Rollback()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if _, err := t.Exec("ROLLBACK TO SAVEPOINT " + t.savepoint); err != nil {
+		return err
+	}
+	_, err := t.Exec("RELEASE SAVEPOINT " + t.savepoint)
+	return err
+}
+
+// WithTx runs fn with a store whose reads and writes all happen in one
+// transaction, committed when fn returns nil and rolled back otherwise.
+// Called on that store, it runs fn in a savepoint instead.
+func (s *Store) WithTx(fn func(storage.Provider) error) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	store := s
+	if s.tx == nil {
+		copied := *s
+		copied.tx = tx.Tx
+		store = &copied
+	}
+	if err := fn(store); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,14 @@
+package storage
+
+// NoteEncrypter is implemented by providers that can encrypt the free-text
+// notes they keep, such as slot feedback and OT notes, with a key held in the
+// OS keyring
+type NoteEncrypter interface {
+	// NotesEncrypted reports whether notes are encrypted
+	NotesEncrypted() bool
+	// EncryptNotes creates a key, stores it in the keyring and encrypts
+	// every note with it. New notes are encrypted from then on.
+	EncryptNotes() error
+	// DecryptNotes decrypts every note and removes the key from the keyring
+	DecryptNotes() error
+}
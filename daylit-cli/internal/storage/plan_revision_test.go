@@ -1,6 +1,7 @@
-package storage
+package storage_test
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -389,3 +390,169 @@ func TestFeedbackAttachesToLatestRevision(t *testing.T) {
 		t.Error("feedback should not be on revision 1")
 	}
 }
+
+// Test that a plan's note is saved and carried over to new revisions
+func TestPlanNoteCarriesOverToNewRevision(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	plan := models.DayPlan{
+		Date:       "2024-03-05",
+		AcceptedAt: &now,
+		Note:       "Slept badly, started late",
+	}
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	retrieved, err := store.GetPlan(plan.Date)
+	if err != nil {
+		t.Fatalf("failed to get plan: %v", err)
+	}
+	if retrieved.Note != plan.Note {
+		t.Errorf("expected note %q, got %q", plan.Note, retrieved.Note)
+	}
+
+	// A new revision generated without a note keeps the day's note
+	later := time.Now().UTC().Add(time.Minute).Format(time.RFC3339)
+	if err := store.SavePlan(models.DayPlan{Date: plan.Date, AcceptedAt: &later}); err != nil {
+		t.Fatalf("failed to save second revision: %v", err)
+	}
+	retrieved, err = store.GetPlan(plan.Date)
+	if err != nil {
+		t.Fatalf("failed to get plan: %v", err)
+	}
+	if retrieved.Revision != 2 || retrieved.Note != plan.Note {
+		t.Errorf("expected revision 2 with the note carried over, got revision %d with note %q", retrieved.Revision, retrieved.Note)
+	}
+
+	// Updating the revision in place replaces the note
+	retrieved.Note = "Better afternoon"
+	if err := store.SavePlan(retrieved); err != nil {
+		t.Fatalf("failed to update note: %v", err)
+	}
+	first, err := store.GetPlanRevision(plan.Date, 1)
+	if err != nil {
+		t.Fatalf("failed to get revision 1: %v", err)
+	}
+	latest, err := store.GetPlan(plan.Date)
+	if err != nil {
+		t.Fatalf("failed to get plan: %v", err)
+	}
+	if latest.Note != "Better afternoon" || first.Note != plan.Note {
+		t.Errorf("expected only the latest revision's note to change, got %q and %q", first.Note, latest.Note)
+	}
+}
+
+func TestPlanLockIsSavedPerRevision(t *testing.T) {
+	for name, store := range rangeStores(t) {
+		t.Run(name, func(t *testing.T) {
+			addSlotTasks(t, store, "task-1")
+			now := time.Now().UTC().Format(time.RFC3339)
+			plan := models.DayPlan{Date: "2024-03-06", AcceptedAt: &now, Slots: []models.Slot{
+				{Start: "09:00", End: "10:00", TaskID: "task-1", Status: constants.SlotStatusAccepted},
+			}}
+			if err := store.SavePlan(plan); err != nil {
+				t.Fatalf("failed to save plan: %v", err)
+			}
+
+			// Locking updates the accepted revision in place
+			locked, err := store.GetPlan(plan.Date)
+			if err != nil {
+				t.Fatalf("failed to get plan: %v", err)
+			}
+			locked.LockedUntil = "12:00"
+			if err := store.SavePlan(locked); err != nil {
+				t.Fatalf("failed to lock plan: %v", err)
+			}
+
+			latest, err := store.GetPlan(plan.Date)
+			if err != nil {
+				t.Fatalf("failed to get plan: %v", err)
+			}
+			inRange, err := store.GetPlansRange(plan.Date, plan.Date)
+			if err != nil {
+				t.Fatalf("failed to get plans: %v", err)
+			}
+			all, err := store.GetAllPlans()
+			if err != nil {
+				t.Fatalf("failed to get all plans: %v", err)
+			}
+			if latest.Revision != 1 || latest.LockedUntil != "12:00" || len(inRange) != 1 || inRange[0].LockedUntil != "12:00" {
+				t.Errorf("expected revision 1 locked until 12:00, got %+v and %+v", latest, inRange)
+			}
+			if len(all) != 1 || all[0].LockedUntil != "12:00" {
+				t.Errorf("expected GetAllPlans to include the lock, got %+v", all)
+			}
+
+			// Unlike the note, a new revision only keeps the lock if it is given
+			later := time.Now().UTC().Add(time.Minute).Format(time.RFC3339)
+			if err := store.SavePlan(models.DayPlan{Date: plan.Date, AcceptedAt: &later}); err != nil {
+				t.Fatalf("failed to save second revision: %v", err)
+			}
+			if latest, err = store.GetPlan(plan.Date); err != nil || latest.Revision != 2 || latest.LockedUntil != "" {
+				t.Errorf("expected an unlocked revision 2, got %+v (%v)", latest, err)
+			}
+		})
+	}
+}
+
+func TestGetAllPlansGroupsSlotsByRevision(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+	addSlotTasks(t, store, "task-1")
+
+	slot := func(start, end string) models.Slot {
+		return models.Slot{Start: start, End: end, TaskID: "task-1", Status: constants.SlotStatusPlanned}
+	}
+	acceptedAt := time.Now().UTC().Format(time.RFC3339)
+	plans := []models.DayPlan{
+		{Date: "2025-03-10", AcceptedAt: &acceptedAt, Slots: []models.Slot{slot("10:00", "11:00"), slot("09:00", "10:00")}},
+		{Date: "2025-03-10", Slots: []models.Slot{slot("13:00", "14:00")}},
+		{Date: "2025-03-11", Note: "Nothing planned"},
+		{Date: "2025-03-12", Slots: []models.Slot{slot("08:00", "09:00")}},
+	}
+	for _, plan := range plans {
+		if err := store.SavePlan(plan); err != nil {
+			t.Fatalf("failed to save plan %s: %v", plan.Date, err)
+		}
+	}
+	if err := store.DeletePlan("2025-03-12"); err != nil {
+		t.Fatalf("failed to delete plan: %v", err)
+	}
+
+	all, err := store.GetAllPlans()
+	if err != nil {
+		t.Fatalf("GetAllPlans failed: %v", err)
+	}
+	type summary struct {
+		date     string
+		revision int
+		starts   []string
+		deleted  bool
+	}
+	var got []summary
+	for _, p := range all {
+		s := summary{date: p.Date, revision: p.Revision, deleted: p.DeletedAt != nil}
+		for _, slot := range p.Slots {
+			s.starts = append(s.starts, slot.Start)
+			if slot.DeletedAt == nil && s.deleted {
+				t.Errorf("slot %s of deleted plan %s is not deleted", slot.Start, p.Date)
+			}
+		}
+		got = append(got, s)
+	}
+	want := []summary{
+		{date: "2025-03-10", revision: 1, starts: []string{"09:00", "10:00"}},
+		{date: "2025-03-10", revision: 2, starts: []string{"13:00"}},
+		{date: "2025-03-11", revision: 1},
+		{date: "2025-03-12", revision: 1, starts: []string{"08:00"}, deleted: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAllPlans:\n got %+v\nwant %+v", got, want)
+	}
+	if all[2].Note != "Nothing planned" {
+		t.Errorf("expected the empty plan's note, got %q", all[2].Note)
+	}
+}
@@ -0,0 +1,164 @@
+package storage_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+// seedPlanRange saves plans for March 10-14, 2025: two revisions on the
+// 10th, an empty plan on the 11th, a deleted plan on the 12th and one-slot
+// plans on the 13th and 14th
+func seedPlanRange(t *testing.T, store storage.Provider) {
+	t.Helper()
+
+	addSlotTasks(t, store, "task-1")
+	slot := func(start, end string) models.Slot {
+		return models.Slot{Start: start, End: end, TaskID: "task-1", Status: constants.SlotStatusPlanned}
+	}
+	acceptedAt := time.Now().UTC().Format(time.RFC3339)
+	plans := []models.DayPlan{
+		{Date: "2025-03-10", AcceptedAt: &acceptedAt, Slots: []models.Slot{slot("09:00", "10:00")}},
+		{Date: "2025-03-10", Slots: []models.Slot{slot("14:00", "15:00"), slot("13:00", "14:00")}},
+		{Date: "2025-03-11", Note: "Nothing planned"},
+		{Date: "2025-03-12", Slots: []models.Slot{slot("08:00", "09:00")}},
+		{Date: "2025-03-13", Slots: []models.Slot{slot("08:00", "09:00")}},
+		{Date: "2025-03-14", Slots: []models.Slot{slot("08:00", "09:00")}},
+	}
+	for _, plan := range plans {
+		if err := store.SavePlan(plan); err != nil {
+			t.Fatalf("failed to save plan %s: %v", plan.Date, err)
+		}
+	}
+	if err := store.DeletePlan("2025-03-12"); err != nil {
+		t.Fatalf("failed to delete plan: %v", err)
+	}
+}
+
+// addSlotTasks adds the tasks that test plans' slots refer to, since the
+// database only takes slots of tasks it has
+func addSlotTasks(t *testing.T, store storage.Provider, ids ...string) {
+	t.Helper()
+
+	for _, id := range ids {
+		task := models.Task{
+			ID:          id,
+			Name:        id,
+			Kind:        constants.TaskKindFlexible,
+			DurationMin: 60,
+			Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+			Priority:    3,
+			Active:      true,
+		}
+		if err := store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task %s: %v", id, err)
+		}
+	}
+}
+
+// planKeys returns each plan's date, revision and slot starts, for comparing
+// plans without their timestamps
+func planKeys(plans []models.DayPlan) []string {
+	var keys []string
+	for _, p := range plans {
+		key := fmt.Sprintf("%s#%d", p.Date, p.Revision)
+		for _, slot := range p.Slots {
+			key += " " + slot.Start
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func rangeStores(t *testing.T) map[string]storage.Provider {
+	sqliteStore, cleanup := setupTestSQLiteStore(t)
+	t.Cleanup(cleanup)
+	return map[string]storage.Provider{"sqlite": sqliteStore, "memory": setupMemoryStore(t)}
+}
+
+func TestGetPlansRange(t *testing.T) {
+	for name, store := range rangeStores(t) {
+		t.Run(name, func(t *testing.T) {
+			seedPlanRange(t, store)
+
+			plans, err := store.GetPlansRange("2025-03-09", "2025-03-13")
+			if err != nil {
+				t.Fatalf("GetPlansRange failed: %v", err)
+			}
+			want := []string{"2025-03-10#2 13:00 14:00", "2025-03-11#1", "2025-03-13#1 08:00"}
+			if got := planKeys(plans); !reflect.DeepEqual(got, want) {
+				t.Errorf("GetPlansRange:\n got %v\nwant %v", got, want)
+			}
+			if plans[1].Note != "Nothing planned" {
+				t.Errorf("expected the empty plan's note, got %q", plans[1].Note)
+			}
+
+			// Each plan matches what GetPlan returns for its day
+			for _, plan := range plans {
+				single, err := store.GetPlan(plan.Date)
+				if err != nil {
+					t.Fatalf("GetPlan(%s) failed: %v", plan.Date, err)
+				}
+				if len(single.Slots) == 0 {
+					single.Slots = nil
+				}
+				if !reflect.DeepEqual(plan, single) {
+					t.Errorf("GetPlansRange and GetPlan differ for %s:\n%+v\n%+v", plan.Date, plan, single)
+				}
+			}
+
+			if plans, err := store.GetPlansRange("2025-04-01", "2025-04-30"); err != nil || len(plans) != 0 {
+				t.Errorf("expected no plans in April, got %v, %v", plans, err)
+			}
+		})
+	}
+}
+
+func TestGetPlansPage(t *testing.T) {
+	for name, store := range rangeStores(t) {
+		t.Run(name, func(t *testing.T) {
+			seedPlanRange(t, store)
+
+			var pages [][]string
+			cursor := ""
+			for {
+				page, err := store.GetPlansPage("2025-03-01", "2025-03-31", cursor, 2)
+				if err != nil {
+					t.Fatalf("GetPlansPage failed: %v", err)
+				}
+				pages = append(pages, planKeys(page.Plans))
+				if page.NextCursor == "" {
+					break
+				}
+				if len(pages) > 5 {
+					t.Fatal("paging did not end")
+				}
+				cursor = page.NextCursor
+			}
+			want := [][]string{
+				{"2025-03-10#2 13:00 14:00", "2025-03-11#1"},
+				{"2025-03-13#1 08:00", "2025-03-14#1 08:00"},
+			}
+			if !reflect.DeepEqual(pages, want) {
+				t.Errorf("pages:\n got %v\nwant %v", pages, want)
+			}
+
+			all, err := store.GetPlansPage("2025-03-01", "2025-03-31", "", 0)
+			if err != nil {
+				t.Fatalf("GetPlansPage without a limit failed: %v", err)
+			}
+			if len(all.Plans) != 4 || all.NextCursor != "" {
+				t.Errorf("expected all 4 plans in one page, got %d and cursor %q", len(all.Plans), all.NextCursor)
+			}
+
+			if _, err := store.GetPlansPage("2025-03-01", "2025-03-31", "2025-03-10", 2); err == nil {
+				t.Error("expected an error for an invalid cursor")
+			}
+		})
+	}
+}
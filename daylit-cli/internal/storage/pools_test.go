@@ -0,0 +1,79 @@
+package storage_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestTaskPools(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	now := time.Now().Truncate(time.Second)
+	pools := []models.TaskPool{
+		{ID: "pool-2", Name: "workout", Strategy: constants.PoolStrategyLeastRecent, CreatedAt: now},
+		{ID: "pool-1", Name: "reading", Strategy: constants.PoolStrategyRoundRobin, CreatedAt: now},
+	}
+	for _, p := range pools {
+		if err := store.AddPool(p); err != nil {
+			t.Fatalf("failed to add pool: %v", err)
+		}
+	}
+	if err := store.AddPool(models.TaskPool{ID: "pool-3", Name: "bad", Strategy: "random", CreatedAt: now}); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+
+	all, err := store.GetAllPools()
+	if err != nil {
+		t.Fatalf("failed to get pools: %v", err)
+	}
+	if len(all) != 2 || all[0].Name != "reading" || all[1].Name != "workout" {
+		t.Fatalf("expected pools ordered by name, got %+v", all)
+	}
+
+	got, err := store.GetPoolByName("workout")
+	if err != nil {
+		t.Fatalf("failed to get pool by name: %v", err)
+	}
+	if got.ID != "pool-2" || got.Strategy != constants.PoolStrategyLeastRecent || !got.CreatedAt.Equal(now) {
+		t.Errorf("unexpected pool: %+v", got)
+	}
+	if _, err := store.GetPoolByName("missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for missing pool, got %v", err)
+	}
+
+	// Tasks keep their pool until it is deleted
+	task := models.Task{
+		ID: "task-run", Name: "Run", Kind: constants.TaskKindFlexible, DurationMin: 30,
+		Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 2, Active: true, PoolID: "pool-2",
+	}
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	saved, err := store.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if saved.PoolID != "pool-2" {
+		t.Errorf("task pool = %q, want %q", saved.PoolID, "pool-2")
+	}
+
+	if err := store.DeletePool("pool-2"); err != nil {
+		t.Fatalf("failed to delete pool: %v", err)
+	}
+	if err := store.DeletePool("pool-2"); err == nil {
+		t.Error("expected error deleting a missing pool")
+	}
+	saved, err = store.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if saved.PoolID != "" {
+		t.Errorf("expected the task to leave the deleted pool, got %q", saved.PoolID)
+	}
+}
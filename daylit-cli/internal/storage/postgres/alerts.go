@@ -20,15 +20,19 @@ func (s *Store) AddAlert(alert models.Alert) error {
 		return fmt.Errorf("failed to marshal weekdays: %w", err)
 	}
 
-	_, err = s.db.Exec(`
+	_, err = s.conn().Exec(`
 		INSERT INTO alerts (
 			id, message, time, date, 
 			recurrence_type, recurrence_interval, recurrence_weekdays,
+			recurrence_month_day, recurrence_month, recurrence_cron,
+			task_id, travel_min, urgency, sound,
 			active, last_sent, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 	`,
 		alert.ID, alert.Message, alert.Time, alert.Date,
 		string(alert.Recurrence.Type), alert.Recurrence.IntervalDays, string(weekdaysJSON),
+		alert.Recurrence.MonthDay, alert.Recurrence.Month, alert.Cron,
+		alert.TaskID, alert.TravelMin, alert.Urgency, alert.Sound,
 		alert.Active, alert.LastSent, alert.CreatedAt,
 	)
 
@@ -45,15 +49,19 @@ func (s *Store) GetAlert(id string) (models.Alert, error) {
 	var recurrenceType string
 	var lastSent *time.Time
 
-	err := s.db.QueryRow(`
+	err := s.conn().QueryRow(`
 		SELECT id, message, time, date,
 			recurrence_type, recurrence_interval, recurrence_weekdays,
+			recurrence_month_day, recurrence_month, recurrence_cron,
+			task_id, travel_min, urgency, sound,
 			active, last_sent, created_at
 		FROM alerts
 		WHERE id = $1
 	`, id).Scan(
 		&alert.ID, &alert.Message, &alert.Time, &alert.Date,
 		&recurrenceType, &alert.Recurrence.IntervalDays, &weekdaysJSON,
+		&alert.Recurrence.MonthDay, &alert.Recurrence.Month, &alert.Cron,
+		&alert.TaskID, &alert.TravelMin, &alert.Urgency, &alert.Sound,
 		&alert.Active, &lastSent, &alert.CreatedAt,
 	)
 
@@ -75,9 +83,11 @@ func (s *Store) GetAlert(id string) (models.Alert, error) {
 }
 
 func (s *Store) GetAllAlerts() ([]models.Alert, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.conn().Query(`
 		SELECT id, message, time, date,
 			recurrence_type, recurrence_interval, recurrence_weekdays,
+			recurrence_month_day, recurrence_month, recurrence_cron,
+			task_id, travel_min, urgency, sound,
 			active, last_sent, created_at
 		FROM alerts
 		ORDER BY time ASC
@@ -97,6 +107,8 @@ func (s *Store) GetAllAlerts() ([]models.Alert, error) {
 		err := rows.Scan(
 			&alert.ID, &alert.Message, &alert.Time, &alert.Date,
 			&recurrenceType, &alert.Recurrence.IntervalDays, &weekdaysJSON,
+			&alert.Recurrence.MonthDay, &alert.Recurrence.Month, &alert.Cron,
+			&alert.TaskID, &alert.TravelMin, &alert.Urgency, &alert.Sound,
 			&alert.Active, &lastSent, &alert.CreatedAt,
 		)
 		if err != nil {
@@ -130,15 +142,19 @@ func (s *Store) UpdateAlert(alert models.Alert) error {
 		return fmt.Errorf("failed to marshal weekdays: %w", err)
 	}
 
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE alerts SET
 			message = $1, time = $2, date = $3,
 			recurrence_type = $4, recurrence_interval = $5, recurrence_weekdays = $6,
-			active = $7, last_sent = $8
-		WHERE id = $9
+			recurrence_month_day = $7, recurrence_month = $8, recurrence_cron = $9,
+			task_id = $10, travel_min = $11, urgency = $12, sound = $13,
+			active = $14, last_sent = $15
+		WHERE id = $16
 	`,
 		alert.Message, alert.Time, alert.Date,
 		string(alert.Recurrence.Type), alert.Recurrence.IntervalDays, string(weekdaysJSON),
+		alert.Recurrence.MonthDay, alert.Recurrence.Month, alert.Cron,
+		alert.TaskID, alert.TravelMin, alert.Urgency, alert.Sound,
 		alert.Active, alert.LastSent, alert.ID,
 	)
 
@@ -159,7 +175,7 @@ func (s *Store) UpdateAlert(alert models.Alert) error {
 }
 
 func (s *Store) DeleteAlert(id string) error {
-	result, err := s.db.Exec(`DELETE FROM alerts WHERE id = $1`, id)
+	result, err := s.conn().Exec(`DELETE FROM alerts WHERE id = $1`, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete alert: %w", err)
 	}
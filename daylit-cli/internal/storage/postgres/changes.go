@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"time"
+
+	pq "github.com/lib/pq"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// WatchChanges listens on the change channel that migration 016's triggers
+// notify. After a reconnect it reports an unknown change, since
+// notifications sent while disconnected are lost.
+func (s *Store) WatchChanges() (<-chan models.Change, func(), error) {
+	listener := pq.NewListener(s.connStr, 10*time.Second, time.Minute,
+		func(ev pq.ListenerEventType, err error) {
+			if err != nil {
+				logger.Warn("Change listener connection problem", "error", err)
+			}
+		})
+	if err := listener.Listen(constants.ChangeChannel); err != nil {
+		listener.Close()
+		return nil, nil, err
+	}
+
+	changes := make(chan models.Change, 1)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case n := <-listener.Notify:
+				if n == nil {
+					// Reconnected; anything may have changed in the meantime
+					sendChange(changes, models.Change{})
+					continue
+				}
+				sendChange(changes, models.Change{Table: n.Extra})
+			}
+		}
+	}()
+
+	stop := func() {
+		close(done)
+		listener.Close()
+	}
+	return changes, stop, nil
+}
+
+// sendChange delivers c without blocking. A change already waiting to be
+// read covers c as well.
+func sendChange(ch chan models.Change, c models.Change) {
+	select {
+	case ch <- c:
+	default:
+	}
+}
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	pq "github.com/lib/pq"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/storagetest"
+)
+
+// TestConformance runs the storage conformance suite against PostgreSQL,
+// each test in a schema of its own that is dropped when it ends
+// Set POSTGRES_TEST_URL environment variable to run this test
+func TestConformance(t *testing.T) {
+	connStr := os.Getenv("POSTGRES_TEST_URL")
+	if connStr == "" {
+		t.Skip("POSTGRES_TEST_URL not set, skipping PostgreSQL conformance test")
+	}
+
+	n := 0
+	storagetest.Run(t, func(t *testing.T) storage.Provider {
+		n++
+		store, err := NewForUser(connStr, fmt.Sprintf("conformance_%d_%d", os.Getpid(), n))
+		if err != nil {
+			t.Fatalf("Failed to create store: %v", err)
+		}
+		if err := store.Init(); err != nil {
+			t.Fatalf("Failed to initialize store: %v", err)
+		}
+		t.Cleanup(func() {
+			if _, err := store.GetDB().Exec("DROP SCHEMA " + pq.QuoteIdentifier(store.schema) + " CASCADE"); err != nil {
+				t.Errorf("Failed to drop schema %s: %v", store.schema, err)
+			}
+			store.Close()
+		})
+		return store
+	})
+}
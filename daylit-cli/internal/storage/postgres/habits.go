@@ -2,9 +2,11 @@ package postgres
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 )
 
@@ -15,15 +17,18 @@ func (s *Store) AddHabit(habit models.Habit) error {
 }
 
 func (s *Store) GetHabit(id string) (models.Habit, error) {
-	row := s.db.QueryRow(`
-		SELECT id, name, created_at, archived_at, deleted_at
+	row := s.conn().QueryRow(`
+		SELECT id, name, created_at, archived_at, deleted_at,
+			category, paused_from, paused_until,
+			reminder_time, reminder_days, reminder_enabled, last_reminded
 		FROM habits WHERE id = $1 AND deleted_at IS NULL`, id)
 
 	var h models.Habit
-	var createdAt string
-	var archivedAt, deletedAt sql.NullString
+	var createdAt, reminderDays string
+	var archivedAt, deletedAt, lastReminded sql.NullString
 
-	err := row.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt)
+	err := row.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt, &h.Category, &h.PausedFrom, &h.PausedUntil,
+		&h.ReminderTime, &reminderDays, &h.ReminderEnabled, &lastReminded)
 	if err != nil {
 		return models.Habit{}, err
 	}
@@ -46,20 +51,26 @@ func (s *Store) GetHabit(id string) (models.Habit, error) {
 		}
 		h.DeletedAt = &t
 	}
+	if err := parseHabitReminder(&h, reminderDays, lastReminded); err != nil {
+		return models.Habit{}, err
+	}
 
 	return h, nil
 }
 
 func (s *Store) GetHabitByName(name string) (models.Habit, error) {
-	row := s.db.QueryRow(`
-		SELECT id, name, created_at, archived_at, deleted_at
+	row := s.conn().QueryRow(`
+		SELECT id, name, created_at, archived_at, deleted_at,
+			category, paused_from, paused_until,
+			reminder_time, reminder_days, reminder_enabled, last_reminded
 		FROM habits WHERE name = $1 AND deleted_at IS NULL`, name)
 
 	var h models.Habit
-	var createdAt string
-	var archivedAt, deletedAt sql.NullString
+	var createdAt, reminderDays string
+	var archivedAt, deletedAt, lastReminded sql.NullString
 
-	err := row.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt)
+	err := row.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt, &h.Category, &h.PausedFrom, &h.PausedUntil,
+		&h.ReminderTime, &reminderDays, &h.ReminderEnabled, &lastReminded)
 	if err != nil {
 		return models.Habit{}, err
 	}
@@ -82,12 +93,15 @@ func (s *Store) GetHabitByName(name string) (models.Habit, error) {
 		}
 		h.DeletedAt = &t
 	}
+	if err := parseHabitReminder(&h, reminderDays, lastReminded); err != nil {
+		return models.Habit{}, err
+	}
 
 	return h, nil
 }
 
 func (s *Store) GetAllHabits(includeArchived, includeDeleted bool) ([]models.Habit, error) {
-	query := "SELECT id, name, created_at, archived_at, deleted_at FROM habits WHERE 1=1"
+	query := "SELECT id, name, created_at, archived_at, deleted_at, category, paused_from, paused_until, reminder_time, reminder_days, reminder_enabled, last_reminded FROM habits WHERE 1=1"
 	if !includeDeleted {
 		query += " AND deleted_at IS NULL"
 	}
@@ -96,7 +110,7 @@ func (s *Store) GetAllHabits(includeArchived, includeDeleted bool) ([]models.Hab
 	}
 	query += " ORDER BY created_at"
 
-	rows, err := s.db.Query(query)
+	rows, err := s.conn().Query(query)
 	if err != nil {
 		return nil, err
 	}
@@ -105,10 +119,11 @@ func (s *Store) GetAllHabits(includeArchived, includeDeleted bool) ([]models.Hab
 	var habits []models.Habit
 	for rows.Next() {
 		var h models.Habit
-		var createdAt string
-		var archivedAt, deletedAt sql.NullString
+		var createdAt, reminderDays string
+		var archivedAt, deletedAt, lastReminded sql.NullString
 
-		err := rows.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt)
+		err := rows.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt, &h.Category, &h.PausedFrom, &h.PausedUntil,
+			&h.ReminderTime, &reminderDays, &h.ReminderEnabled, &lastReminded)
 		if err != nil {
 			return nil, err
 		}
@@ -131,6 +146,9 @@ func (s *Store) GetAllHabits(includeArchived, includeDeleted bool) ([]models.Hab
 			}
 			h.DeletedAt = &t
 		}
+		if err := parseHabitReminder(&h, reminderDays, lastReminded); err != nil {
+			return nil, err
+		}
 
 		habits = append(habits, h)
 	}
@@ -146,21 +164,70 @@ func (s *Store) UpdateHabit(habit models.Habit) error {
 	if habit.DeletedAt != nil {
 		deletedAt = sql.NullString{String: habit.DeletedAt.Format(time.RFC3339), Valid: true}
 	}
+	reminderDays, lastReminded, err := formatHabitReminder(habit)
+	if err != nil {
+		return err
+	}
 
-	_, err := s.db.Exec(`
-		INSERT INTO habits (id, name, created_at, archived_at, deleted_at)
-		VALUES ($1, $2, $3, $4, $5)
+	_, err = s.conn().Exec(`
+		INSERT INTO habits (id, name, created_at, archived_at, deleted_at, category, paused_from, paused_until,
+			reminder_time, reminder_days, reminder_enabled, last_reminded)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		ON CONFLICT(id) DO UPDATE SET
 			name = EXCLUDED.name,
 			archived_at = EXCLUDED.archived_at,
-			deleted_at = EXCLUDED.deleted_at`,
-		habit.ID, habit.Name, habit.CreatedAt.Format(time.RFC3339), archivedAt, deletedAt)
+			deleted_at = EXCLUDED.deleted_at,
+			category = EXCLUDED.category,
+			paused_from = EXCLUDED.paused_from,
+			paused_until = EXCLUDED.paused_until,
+			reminder_time = EXCLUDED.reminder_time,
+			reminder_days = EXCLUDED.reminder_days,
+			reminder_enabled = EXCLUDED.reminder_enabled,
+			last_reminded = EXCLUDED.last_reminded`,
+		habit.ID, habit.Name, habit.CreatedAt.Format(time.RFC3339), archivedAt, deletedAt, habit.Category, habit.PausedFrom, habit.PausedUntil,
+		habit.ReminderTime, reminderDays, habit.ReminderEnabled, lastReminded)
 
 	return err
 }
 
+// parseHabitReminder fills in a habit's reminder from its stored reminder
+// days and last reminded time
+func parseHabitReminder(h *models.Habit, reminderDays string, lastReminded sql.NullString) error {
+	if reminderDays != "" {
+		if err := json.Unmarshal([]byte(reminderDays), &h.ReminderDays); err != nil {
+			return fmt.Errorf("failed to parse reminder_days for habit %s: %w", h.ID, err)
+		}
+	}
+	if lastReminded.Valid {
+		t, err := time.Parse(time.RFC3339, lastReminded.String)
+		if err != nil {
+			return fmt.Errorf("failed to parse last_reminded for habit %s: %w", h.ID, err)
+		}
+		h.LastReminded = &t
+	}
+	return nil
+}
+
+// formatHabitReminder returns a habit's reminder days and last reminded
+// time as stored
+func formatHabitReminder(habit models.Habit) (string, sql.NullString, error) {
+	var reminderDays string
+	if len(habit.ReminderDays) > 0 {
+		data, err := json.Marshal(habit.ReminderDays)
+		if err != nil {
+			return "", sql.NullString{}, fmt.Errorf("failed to marshal reminder days: %w", err)
+		}
+		reminderDays = string(data)
+	}
+	var lastReminded sql.NullString
+	if habit.LastReminded != nil {
+		lastReminded = sql.NullString{String: habit.LastReminded.Format(time.RFC3339), Valid: true}
+	}
+	return reminderDays, lastReminded, nil
+}
+
 func (s *Store) ArchiveHabit(id string) error {
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE habits SET archived_at = $1 WHERE id = $2 AND deleted_at IS NULL AND archived_at IS NULL`,
 		time.Now().Format(time.RFC3339), id)
 	if err != nil {
@@ -179,7 +246,7 @@ func (s *Store) ArchiveHabit(id string) error {
 }
 
 func (s *Store) UnarchiveHabit(id string) error {
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE habits SET archived_at = NULL WHERE id = $1 AND deleted_at IS NULL AND archived_at IS NOT NULL`,
 		id)
 	if err != nil {
@@ -198,9 +265,17 @@ func (s *Store) UnarchiveHabit(id string) error {
 }
 
 func (s *Store) DeleteHabit(id string) error {
-	result, err := s.db.Exec(`
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The entries are marked with the habit's time so restoring it finds them
+	stamp := time.Now().Format(time.RFC3339)
+	result, err := tx.Exec(`
 		UPDATE habits SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`,
-		time.Now().Format(time.RFC3339), id)
+		stamp, id)
 	if err != nil {
 		return err
 	}
@@ -213,26 +288,48 @@ func (s *Store) DeleteHabit(id string) error {
 		return fmt.Errorf("habit not found or already deleted")
 	}
 
-	return nil
+	settings, err := storedSettings(tx)
+	if err != nil {
+		return err
+	}
+	if settings.HabitDeleteEntries == constants.HabitDeleteEntriesDelete {
+		if _, err := tx.Exec(`
+			UPDATE habit_entries SET deleted_at = $1 WHERE habit_id = $2 AND deleted_at IS NULL`,
+			stamp, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (s *Store) RestoreHabit(id string) error {
-	result, err := s.db.Exec(`
-		UPDATE habits SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`,
-		id)
+	tx, err := s.begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rows, err := result.RowsAffected()
-	if err != nil {
+	var deletedAt sql.NullString
+	err = tx.QueryRow("SELECT deleted_at FROM habits WHERE id = $1 FOR UPDATE", id).Scan(&deletedAt)
+	if err != nil && err != sql.ErrNoRows {
 		return err
 	}
-	if rows == 0 {
+	if !deletedAt.Valid {
 		return fmt.Errorf("habit not found or not deleted")
 	}
 
-	return nil
+	if _, err := tx.Exec("UPDATE habits SET deleted_at = NULL WHERE id = $1", id); err != nil {
+		return err
+	}
+	// Bring back the entries deleted with the habit
+	if _, err := tx.Exec(`
+		UPDATE habit_entries SET deleted_at = NULL WHERE habit_id = $1 AND deleted_at = $2`,
+		id, deletedAt.String); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // Habit Entries
@@ -242,7 +339,7 @@ func (s *Store) AddHabitEntry(entry models.HabitEntry) error {
 }
 
 func (s *Store) GetHabitEntry(habitID, day string) (models.HabitEntry, error) {
-	row := s.db.QueryRow(`
+	row := s.conn().QueryRow(`
 		SELECT id, habit_id, day, note, created_at, updated_at, deleted_at
 		FROM habit_entries WHERE habit_id = $1 AND day = $2 AND deleted_at IS NULL`,
 		habitID, day)
@@ -276,7 +373,7 @@ func (s *Store) GetHabitEntry(habitID, day string) (models.HabitEntry, error) {
 }
 
 func (s *Store) GetHabitEntriesForDay(day string) ([]models.HabitEntry, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.conn().Query(`
 		SELECT id, habit_id, day, note, created_at, updated_at, deleted_at
 		FROM habit_entries WHERE day = $1 AND deleted_at IS NULL
 		ORDER BY created_at`, day)
@@ -319,7 +416,7 @@ func (s *Store) GetHabitEntriesForDay(day string) ([]models.HabitEntry, error) {
 }
 
 func (s *Store) GetHabitEntriesForHabit(habitID string, startDay, endDay string) ([]models.HabitEntry, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.conn().Query(`
 		SELECT id, habit_id, day, note, created_at, updated_at, deleted_at
 		FROM habit_entries
 		WHERE habit_id = $1 AND day >= $2 AND day <= $3 AND deleted_at IS NULL
@@ -363,10 +460,10 @@ func (s *Store) GetHabitEntriesForHabit(habitID string, startDay, endDay string)
 }
 
 func (s *Store) GetAllHabitEntries() ([]models.HabitEntry, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.conn().Query(`
 		SELECT id, habit_id, day, note, created_at, updated_at, deleted_at
 		FROM habit_entries
-		ORDER BY day, created_at`)
+		ORDER BY day, habit_id`)
 	if err != nil {
 		return nil, err
 	}
@@ -411,7 +508,7 @@ func (s *Store) UpdateHabitEntry(entry models.HabitEntry) error {
 		deletedAt = sql.NullString{String: entry.DeletedAt.Format(time.RFC3339), Valid: true}
 	}
 
-	_, err := s.db.Exec(`
+	_, err := s.conn().Exec(`
 		INSERT INTO habit_entries (id, habit_id, day, note, created_at, updated_at, deleted_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		ON CONFLICT(habit_id, day) DO UPDATE SET
@@ -425,7 +522,7 @@ func (s *Store) UpdateHabitEntry(entry models.HabitEntry) error {
 }
 
 func (s *Store) DeleteHabitEntry(id string) error {
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE habit_entries SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`,
 		time.Now().Format(time.RFC3339), id)
 	if err != nil {
@@ -444,7 +541,7 @@ func (s *Store) DeleteHabitEntry(id string) error {
 }
 
 func (s *Store) RestoreHabitEntry(id string) error {
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE habit_entries SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`,
 		id)
 	if err != nil {
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) AddInboxItem(item models.InboxItem) error {
+	if err := item.Validate(); err != nil {
+		return err
+	}
+
+	_, err := s.conn().Exec(`
+		INSERT INTO inbox (id, text, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT(id) DO UPDATE SET text = EXCLUDED.text`,
+		item.ID, item.Text, item.CreatedAt.Format(time.RFC3339))
+	return err
+}
+
+func (s *Store) GetInboxItems() ([]models.InboxItem, error) {
+	rows, err := s.conn().Query(`
+		SELECT id, text, created_at
+		FROM inbox ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.InboxItem
+	for rows.Next() {
+		var item models.InboxItem
+		var createdAt string
+		if err := rows.Scan(&item.ID, &item.Text, &createdAt); err != nil {
+			return nil, err
+		}
+		item.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *Store) DeleteInboxItem(id string) error {
+	result, err := s.conn().Exec(`DELETE FROM inbox WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete inbox item: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("inbox item not found")
+	}
+	return nil
+}
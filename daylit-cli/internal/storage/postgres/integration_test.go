@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/migration"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/migrations"
 )
 
 // TestStore_Integration tests PostgreSQL store with a real database
@@ -245,5 +247,59 @@ func TestStore_Integration(t *testing.T) {
 		}
 	})
 
+	// Test change notifications
+	t.Run("WatchChanges", func(t *testing.T) {
+		changes, stop, err := store.WatchChanges()
+		if err != nil {
+			t.Fatalf("Failed to watch changes: %v", err)
+		}
+		defer stop()
+
+		settings, err := store.GetSettings()
+		if err != nil {
+			t.Fatalf("Failed to get settings: %v", err)
+		}
+		if err := store.SaveSettings(settings); err != nil {
+			t.Fatalf("Failed to save settings: %v", err)
+		}
+
+		select {
+		case c := <-changes:
+			if c.Table != "settings" {
+				t.Errorf("Expected change to settings, got %q", c.Table)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected a change notification after saving settings")
+		}
+	})
+
+	// Test schema integrity against a scratch copy of the migrations
+	t.Run("SchemaIntegrity", func(t *testing.T) {
+		subFS, err := migrations.Backend("postgres")
+		if err != nil {
+			t.Fatalf("Failed to access migrations: %v", err)
+		}
+
+		divergences, err := migration.NewRunner(store.GetDB(), subFS).VerifyChecksums()
+		if err != nil {
+			t.Fatalf("Failed to verify checksums: %v", err)
+		}
+		if len(divergences) != 0 {
+			t.Errorf("Expected no checksum divergences, got %v", divergences)
+		}
+
+		want, err := migration.ExpectedPostgresSchema(store.GetDB(), subFS)
+		if err != nil {
+			t.Fatalf("Failed to build expected schema: %v", err)
+		}
+		got, err := migration.PostgresSchema(store.GetDB())
+		if err != nil {
+			t.Fatalf("Failed to introspect schema: %v", err)
+		}
+		if drift := migration.CompareSchemas(want, got); len(drift) != 0 {
+			t.Errorf("Expected no schema drift, got %v", drift)
+		}
+	})
+
 	t.Log("All PostgreSQL integration tests passed!")
 }
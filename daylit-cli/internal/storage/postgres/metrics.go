@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) SaveDailyMetrics(m models.DailyMetrics) error {
+	_, err := s.conn().Exec(`
+		INSERT INTO metrics (date, has_plan, accepted, planned_slots, done_slots, skipped_slots, tracked_slots, drift_minutes, on_track, too_much, unnecessary, computed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT(date) DO UPDATE SET
+			has_plan = EXCLUDED.has_plan,
+			accepted = EXCLUDED.accepted,
+			planned_slots = EXCLUDED.planned_slots,
+			done_slots = EXCLUDED.done_slots,
+			skipped_slots = EXCLUDED.skipped_slots,
+			tracked_slots = EXCLUDED.tracked_slots,
+			drift_minutes = EXCLUDED.drift_minutes,
+			on_track = EXCLUDED.on_track,
+			too_much = EXCLUDED.too_much,
+			unnecessary = EXCLUDED.unnecessary,
+			computed_at = EXCLUDED.computed_at`,
+		m.Date, m.HasPlan, m.Accepted, m.PlannedSlots, m.DoneSlots, m.SkippedSlots, m.TrackedSlots, m.DriftMinutes,
+		m.OnTrack, m.TooMuch, m.Unnecessary, m.ComputedAt.Format(time.RFC3339))
+	return err
+}
+
+func (s *Store) GetDailyMetrics(startDay, endDay string) ([]models.DailyMetrics, error) {
+	rows, err := s.conn().Query(`
+		SELECT date, has_plan, accepted, planned_slots, done_slots, skipped_slots, tracked_slots, drift_minutes, on_track, too_much, unnecessary, computed_at
+		FROM metrics
+		WHERE date >= $1 AND date <= $2
+		ORDER BY date`, startDay, endDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var metrics []models.DailyMetrics
+	for rows.Next() {
+		var m models.DailyMetrics
+		var computedAt string
+		if err := rows.Scan(&m.Date, &m.HasPlan, &m.Accepted, &m.PlannedSlots, &m.DoneSlots, &m.SkippedSlots, &m.TrackedSlots,
+			&m.DriftMinutes, &m.OnTrack, &m.TooMuch, &m.Unnecessary, &computedAt); err != nil {
+			return nil, err
+		}
+		m.ComputedAt, err = time.Parse(time.RFC3339, computedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse computed_at: %w", err)
+		}
+		metrics = append(metrics, m)
+	}
+
+	return metrics, rows.Err()
+}
@@ -12,7 +12,7 @@ import (
 // OT Settings
 
 func (s *Store) GetOTSettings() (models.OTSettings, error) {
-	rows, err := s.db.Query("SELECT key, value FROM settings WHERE key LIKE 'ot_%'")
+	rows, err := s.conn().Query("SELECT key, value FROM settings WHERE key LIKE 'ot_%'")
 	if err != nil {
 		return models.OTSettings{}, err
 	}
@@ -45,7 +45,7 @@ func (s *Store) GetOTSettings() (models.OTSettings, error) {
 }
 
 func (s *Store) SaveOTSettings(settings models.OTSettings) error {
-	tx, err := s.db.Begin()
+	tx, err := s.begin()
 	if err != nil {
 		return err
 	}
@@ -77,7 +77,7 @@ func (s *Store) AddOTEntry(entry models.OTEntry) error {
 }
 
 func (s *Store) GetOTEntry(day string) (models.OTEntry, error) {
-	row := s.db.QueryRow(`
+	row := s.conn().QueryRow(`
 		SELECT id, day, title, note, created_at, updated_at, deleted_at
 		FROM ot_entries WHERE day = $1 AND deleted_at IS NULL`, day)
 
@@ -118,7 +118,7 @@ func (s *Store) GetOTEntries(startDay, endDay string, includeDeleted bool) ([]mo
 	}
 	query += " ORDER BY day DESC"
 
-	rows, err := s.db.Query(query, startDay, endDay)
+	rows, err := s.conn().Query(query, startDay, endDay)
 	if err != nil {
 		return nil, err
 	}
@@ -158,10 +158,10 @@ func (s *Store) GetOTEntries(startDay, endDay string, includeDeleted bool) ([]mo
 }
 
 func (s *Store) GetAllOTEntries() ([]models.OTEntry, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.conn().Query(`
 		SELECT id, day, title, note, created_at, updated_at, deleted_at
 		FROM ot_entries
-		ORDER BY day DESC`)
+		ORDER BY day`)
 	if err != nil {
 		return nil, err
 	}
@@ -206,7 +206,7 @@ func (s *Store) UpdateOTEntry(entry models.OTEntry) error {
 		deletedAt = sql.NullString{String: entry.DeletedAt.Format(time.RFC3339), Valid: true}
 	}
 
-	_, err := s.db.Exec(`
+	_, err := s.conn().Exec(`
 		INSERT INTO ot_entries (id, day, title, note, created_at, updated_at, deleted_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		ON CONFLICT(day) DO UPDATE SET
@@ -221,7 +221,7 @@ func (s *Store) UpdateOTEntry(entry models.OTEntry) error {
 }
 
 func (s *Store) DeleteOTEntry(day string) error {
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE ot_entries SET deleted_at = $1 WHERE day = $2 AND deleted_at IS NULL`,
 		time.Now().Format(time.RFC3339), day)
 	if err != nil {
@@ -240,7 +240,7 @@ func (s *Store) DeleteOTEntry(day string) error {
 }
 
 func (s *Store) RestoreOTEntry(day string) error {
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE ot_entries SET deleted_at = NULL WHERE day = $1 AND deleted_at IS NOT NULL`,
 		day)
 	if err != nil {
@@ -11,7 +11,7 @@ import (
 )
 
 func (s *Store) SavePlan(plan models.DayPlan) error {
-	tx, err := s.db.Begin()
+	tx, err := s.begin()
 	if err != nil {
 		return err
 	}
@@ -23,30 +23,39 @@ func (s *Store) SavePlan(plan models.DayPlan) error {
 		return fmt.Errorf("cannot save a plan with deleted_at set; use DeletePlan to soft-delete or RestorePlan to restore")
 	}
 
-	// Determine the revision number
+	// Determine the revision number and the version to save it with
 	// If plan.Revision is 0, auto-assign it
+	var version int
 	if plan.Revision == 0 {
 		// Check if there's an existing accepted plan for this date
-		var existingRevision int
+		var existingRevision, existingVersion int
 		var acceptedAt sql.NullString
+		var existingNote string
 		err = tx.QueryRow(
-			"SELECT revision, accepted_at FROM plans WHERE date = $1 AND deleted_at IS NULL ORDER BY revision DESC LIMIT 1",
+			"SELECT revision, accepted_at, version, note FROM plans WHERE date = $1 AND deleted_at IS NULL ORDER BY revision DESC LIMIT 1",
 			plan.Date,
-		).Scan(&existingRevision, &acceptedAt)
+		).Scan(&existingRevision, &acceptedAt, &existingVersion, &existingNote)
 
 		if err == sql.ErrNoRows {
 			// No existing plan, start with revision 1
 			plan.Revision = 1
+			version = 1
 		} else if err != nil {
 			return fmt.Errorf("failed to check existing plan: %w", err)
 		} else {
-			// Existing plan found
+			// Existing plan found. A regenerated plan keeps the day's note.
+			if plan.Note == "" {
+				plan.Note = existingNote
+			}
 			if acceptedAt.Valid {
 				// Plan is accepted - must create a new revision
 				plan.Revision = existingRevision + 1
+				version = 1
 			} else {
-				// Plan exists but not accepted - can overwrite
+				// Plan exists but not accepted - can overwrite. Bump the version so
+				// editors holding the old plan see the conflict.
 				plan.Revision = existingRevision
+				version = existingVersion + 1
 				// Delete the old plan and its slots first
 				_, err = tx.Exec("DELETE FROM slots WHERE plan_date = $1 AND plan_revision = $2 AND deleted_at IS NULL", plan.Date, plan.Revision)
 				if err != nil {
@@ -62,7 +71,13 @@ func (s *Store) SavePlan(plan models.DayPlan) error {
 		// If revision is manually set, validate that it doesn't overwrite an accepted plan
 		// unless it's the same plan being updated (same accepted_at timestamp)
 		var existingAcceptedAt sql.NullString
-		err = tx.QueryRow("SELECT accepted_at FROM plans WHERE date = $1 AND revision = $2 AND deleted_at IS NULL", plan.Date, plan.Revision).Scan(&existingAcceptedAt)
+		var existingVersion int
+		err = tx.QueryRow("SELECT accepted_at, version FROM plans WHERE date = $1 AND revision = $2 AND deleted_at IS NULL FOR UPDATE", plan.Date, plan.Revision).Scan(&existingAcceptedAt, &existingVersion)
+		if err == nil && plan.Version > 0 && existingVersion != plan.Version {
+			// Someone else saved this revision after it was loaded
+			return &models.ConflictError{Record: "plan", ID: plan.Date}
+		}
+		version = existingVersion + 1
 		if err == nil && existingAcceptedAt.Valid {
 			// Check if we're updating the same plan (same accepted_at timestamp)
 			planAcceptedAtStr := ""
@@ -91,11 +106,14 @@ func (s *Store) SavePlan(plan models.DayPlan) error {
 
 	// Insert or replace plan
 	_, err = tx.Exec(`
-		INSERT INTO plans (date, revision, accepted_at, deleted_at) VALUES ($1, $2, $3, NULL)
+		INSERT INTO plans (date, revision, accepted_at, deleted_at, version, note, locked_until) VALUES ($1, $2, $3, NULL, $4, $5, $6)
 		ON CONFLICT (date, revision) DO UPDATE SET
 			accepted_at = EXCLUDED.accepted_at,
-			deleted_at = EXCLUDED.deleted_at`,
-		plan.Date, plan.Revision, acceptedAtVal,
+			deleted_at = EXCLUDED.deleted_at,
+			version = EXCLUDED.version,
+			note = EXCLUDED.note,
+			locked_until = EXCLUDED.locked_until`,
+		plan.Date, plan.Revision, acceptedAtVal, version, plan.Note, plan.LockedUntil,
 	)
 	if err != nil {
 		return err
@@ -110,8 +128,8 @@ func (s *Store) SavePlan(plan models.DayPlan) error {
 	// Insert slots
 	stmt, err := tx.Prepare(`
 		INSERT INTO slots (
-			plan_date, plan_revision, start_time, end_time, task_id, status, feedback_rating, feedback_note, deleted_at, last_notified_start, last_notified_end
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`)
+			plan_date, plan_revision, start_time, end_time, task_id, status, feedback_rating, feedback_note, deleted_at, last_notified_start, last_notified_end, actual_start, actual_end
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`)
 	if err != nil {
 		return err
 	}
@@ -134,8 +152,15 @@ func (s *Store) SavePlan(plan models.DayPlan) error {
 		if slot.LastNotifiedEnd != nil {
 			lastNotifiedEnd = sql.NullString{String: *slot.LastNotifiedEnd, Valid: true}
 		}
+		var actualStart, actualEnd sql.NullString
+		if slot.ActualStart != nil {
+			actualStart = sql.NullString{String: *slot.ActualStart, Valid: true}
+		}
+		if slot.ActualEnd != nil {
+			actualEnd = sql.NullString{String: *slot.ActualEnd, Valid: true}
+		}
 		_, err = stmt.Exec(
-			plan.Date, plan.Revision, slot.Start, slot.End, slot.TaskID, slot.Status, rating, note, slotDeletedAt, lastNotifiedStart, lastNotifiedEnd,
+			plan.Date, plan.Revision, slot.Start, slot.End, slot.TaskID, slot.Status, rating, note, slotDeletedAt, lastNotifiedStart, lastNotifiedEnd, actualStart, actualEnd,
 		)
 		if err != nil {
 			return err
@@ -152,12 +177,13 @@ func (s *Store) GetPlan(date string) (models.DayPlan, error) {
 
 func (s *Store) GetLatestPlanRevision(date string) (models.DayPlan, error) {
 	// Get the latest non-deleted revision for this date
-	var revision int
+	var revision, version int
 	var acceptedAt sql.NullString
-	err := s.db.QueryRow(
-		"SELECT revision, accepted_at FROM plans WHERE date = $1 AND deleted_at IS NULL ORDER BY revision DESC LIMIT 1",
+	var note, lockedUntil string
+	err := s.conn().QueryRow(
+		"SELECT revision, accepted_at, version, note, locked_until FROM plans WHERE date = $1 AND deleted_at IS NULL ORDER BY revision DESC LIMIT 1",
 		date,
-	).Scan(&revision, &acceptedAt)
+	).Scan(&revision, &acceptedAt, &version, &note, &lockedUntil)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -166,16 +192,18 @@ func (s *Store) GetLatestPlanRevision(date string) (models.DayPlan, error) {
 		return models.DayPlan{}, err
 	}
 
-	return s.getPlanByRevision(date, revision, acceptedAt)
+	return s.getPlanByRevision(date, revision, version, acceptedAt, note, lockedUntil)
 }
 
 func (s *Store) GetPlanRevision(date string, revision int) (models.DayPlan, error) {
 	// Get a specific revision
 	var acceptedAt, deletedAt sql.NullString
-	err := s.db.QueryRow(
-		"SELECT accepted_at, deleted_at FROM plans WHERE date = $1 AND revision = $2",
+	var version int
+	var note, lockedUntil string
+	err := s.conn().QueryRow(
+		"SELECT accepted_at, deleted_at, version, note, locked_until FROM plans WHERE date = $1 AND revision = $2",
 		date, revision,
-	).Scan(&acceptedAt, &deletedAt)
+	).Scan(&acceptedAt, &deletedAt, &version, &note, &lockedUntil)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -188,13 +216,16 @@ func (s *Store) GetPlanRevision(date string, revision int) (models.DayPlan, erro
 		return models.DayPlan{}, fmt.Errorf("plan for date %s revision %d has been deleted; use 'daylit restore plan %s' to restore it", date, revision, date)
 	}
 
-	return s.getPlanByRevision(date, revision, acceptedAt)
+	return s.getPlanByRevision(date, revision, version, acceptedAt, note, lockedUntil)
 }
 
-func (s *Store) getPlanByRevision(date string, revision int, acceptedAt sql.NullString) (models.DayPlan, error) {
+func (s *Store) getPlanByRevision(date string, revision, version int, acceptedAt sql.NullString, note, lockedUntil string) (models.DayPlan, error) {
 	plan := models.DayPlan{
-		Date:     date,
-		Revision: revision,
+		Date:        date,
+		Revision:    revision,
+		Version:     version,
+		Note:        note,
+		LockedUntil: lockedUntil,
 	}
 
 	if acceptedAt.Valid {
@@ -202,8 +233,8 @@ func (s *Store) getPlanByRevision(date string, revision int, acceptedAt sql.Null
 	}
 
 	// Get slots (exclude soft-deleted slots)
-	rows, err := s.db.Query(`
-		SELECT start_time, end_time, task_id, status, feedback_rating, feedback_note, last_notified_start, last_notified_end
+	rows, err := s.conn().Query(`
+		SELECT start_time, end_time, task_id, status, feedback_rating, feedback_note, last_notified_start, last_notified_end, actual_start, actual_end
 		FROM slots WHERE plan_date = $1 AND plan_revision = $2 AND deleted_at IS NULL ORDER BY start_time`,
 		date, revision)
 	if err != nil {
@@ -214,9 +245,9 @@ func (s *Store) getPlanByRevision(date string, revision int, acceptedAt sql.Null
 	for rows.Next() {
 		var slot models.Slot
 		var rating, note string
-		var lastNotifiedStart, lastNotifiedEnd sql.NullString
+		var lastNotifiedStart, lastNotifiedEnd, actualStart, actualEnd sql.NullString
 		err := rows.Scan(
-			&slot.Start, &slot.End, &slot.TaskID, &slot.Status, &rating, &note, &lastNotifiedStart, &lastNotifiedEnd,
+			&slot.Start, &slot.End, &slot.TaskID, &slot.Status, &rating, &note, &lastNotifiedStart, &lastNotifiedEnd, &actualStart, &actualEnd,
 		)
 		if err != nil {
 			return models.DayPlan{}, err
@@ -234,15 +265,158 @@ func (s *Store) getPlanByRevision(date string, revision int, acceptedAt sql.Null
 		if lastNotifiedEnd.Valid {
 			slot.LastNotifiedEnd = &lastNotifiedEnd.String
 		}
+		if actualStart.Valid {
+			slot.ActualStart = &actualStart.String
+		}
+		if actualEnd.Valid {
+			slot.ActualEnd = &actualEnd.String
+		}
 		plan.Slots = append(plan.Slots, slot)
 	}
 
+	// Slots after midnight come last in a day that ends after midnight
+	if settings, err := s.GetSettings(); err == nil {
+		if window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd); err == nil {
+			window.SortSlots(plan.Slots)
+		}
+	}
+
 	return plan, nil
 }
 
+// GetPlansRange returns the latest non-deleted revision of every plan in the
+// inclusive date range, ordered by date
+func (s *Store) GetPlansRange(startDay, endDay string) ([]models.DayPlan, error) {
+	return s.getPlansAfter(startDay, endDay, "", 0)
+}
+
+// GetPlansPage returns up to limit plans from the range, starting after cursor
+func (s *Store) GetPlansPage(startDay, endDay, cursor string, limit int) (models.PlanPage, error) {
+	after, err := models.DecodePlanCursor(cursor)
+	if err != nil {
+		return models.PlanPage{}, err
+	}
+	fetch := 0
+	if limit > 0 {
+		fetch = limit + 1
+	}
+	plans, err := s.getPlansAfter(startDay, endDay, after, fetch)
+	if err != nil {
+		return models.PlanPage{}, err
+	}
+	return models.NewPlanPage(plans, limit), nil
+}
+
+// getPlansAfter loads the latest revision of up to limit plans in the range
+// dated after the given date, with their live slots, in one query. A limit
+// of zero or less loads them all.
+func (s *Store) getPlansAfter(startDay, endDay, after string, limit int) ([]models.DayPlan, error) {
+	// LIMIT NULL is no limit
+	var rowLimit sql.NullInt64
+	if limit > 0 {
+		rowLimit = sql.NullInt64{Int64: int64(limit), Valid: true}
+	}
+	rows, err := s.conn().Query(`
+		WITH latest AS (
+			SELECT date, MAX(revision) AS revision FROM plans
+			WHERE date >= $1 AND date <= $2 AND date > $3 AND deleted_at IS NULL
+			GROUP BY date ORDER BY date LIMIT $4
+		)
+		SELECT p.date, p.revision, p.version, p.accepted_at, p.note, p.locked_until,
+			s.id, s.start_time, s.end_time, s.task_id, s.status, s.feedback_rating, s.feedback_note,
+			s.last_notified_start, s.last_notified_end, s.actual_start, s.actual_end
+		FROM latest l
+		JOIN plans p ON p.date = l.date AND p.revision = l.revision
+		LEFT JOIN slots s ON s.plan_date = p.date AND s.plan_revision = p.revision AND s.deleted_at IS NULL
+		ORDER BY p.date, s.start_time, s.id`,
+		startDay, endDay, after, rowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans, err := scanPlanRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Slots after midnight come last in a day that ends after midnight
+	if settings, err := s.GetSettings(); err == nil {
+		if window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd); err == nil {
+			for i := range plans {
+				window.SortSlots(plans[i].Slots)
+			}
+		}
+	}
+
+	return plans, nil
+}
+
+// scanPlanRows groups rows of plans joined to their slots, one row per slot
+// or a single row with NULL slot columns for a plan without slots, into plans
+func scanPlanRows(rows *sql.Rows) ([]models.DayPlan, error) {
+	var plans []models.DayPlan
+	for rows.Next() {
+		var date string
+		var revision, version int
+		var acceptedAt sql.NullString
+		var note, lockedUntil string
+		var slotID sql.NullInt64
+		var start, end, taskID, status, rating, feedbackNote sql.NullString
+		var lastNotifiedStart, lastNotifiedEnd, actualStart, actualEnd sql.NullString
+		if err := rows.Scan(
+			&date, &revision, &version, &acceptedAt, &note, &lockedUntil,
+			&slotID, &start, &end, &taskID, &status, &rating, &feedbackNote,
+			&lastNotifiedStart, &lastNotifiedEnd, &actualStart, &actualEnd,
+		); err != nil {
+			return nil, err
+		}
+
+		if n := len(plans); n == 0 || plans[n-1].Date != date {
+			plan := models.DayPlan{Date: date, Revision: revision, Version: version, Note: note, LockedUntil: lockedUntil}
+			if acceptedAt.Valid {
+				plan.AcceptedAt = &acceptedAt.String
+			}
+			plans = append(plans, plan)
+		}
+		if !slotID.Valid {
+			continue
+		}
+
+		slot := models.Slot{
+			Start:  start.String,
+			End:    end.String,
+			TaskID: taskID.String,
+			Status: models.SlotStatus(status.String),
+		}
+		if rating.String != "" {
+			slot.Feedback = &models.Feedback{
+				Rating: models.FeedbackRating(rating.String),
+				Note:   feedbackNote.String,
+			}
+		}
+		if lastNotifiedStart.Valid {
+			slot.LastNotifiedStart = &lastNotifiedStart.String
+		}
+		if lastNotifiedEnd.Valid {
+			slot.LastNotifiedEnd = &lastNotifiedEnd.String
+		}
+		if actualStart.Valid {
+			slot.ActualStart = &actualStart.String
+		}
+		if actualEnd.Valid {
+			slot.ActualEnd = &actualEnd.String
+		}
+
+		plan := &plans[len(plans)-1]
+		plan.Slots = append(plan.Slots, slot)
+	}
+	return plans, rows.Err()
+}
+
 func (s *Store) DeletePlan(date string) error {
 	// Soft delete: set deleted_at timestamp for all revisions of the plan and their slots
-	tx, err := s.db.Begin()
+	tx, err := s.begin()
 	if err != nil {
 		return err
 	}
@@ -276,7 +450,7 @@ func (s *Store) DeletePlan(date string) error {
 
 func (s *Store) RestorePlan(date string) error {
 	// Restore soft-deleted plans (all revisions and their slots) by clearing deleted_at
-	tx, err := s.db.Begin()
+	tx, err := s.begin()
 	if err != nil {
 		return err
 	}
@@ -312,6 +486,102 @@ func (s *Store) RestorePlan(date string) error {
 }
 
 // UpdateSlotNotificationTimestamp updates the notification timestamp for a specific slot
+// DeleteSlot soft-deletes one slot of a plan revision, leaving the rest of
+// the plan as it is
+func (s *Store) DeleteSlot(date string, revision int, startTime string, taskID string) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkSlotPlan(tx, date, revision); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	result, err := tx.Exec(
+		"UPDATE slots SET deleted_at = $1 WHERE plan_date = $2 AND plan_revision = $3 AND start_time = $4 AND task_id = $5 AND deleted_at IS NULL",
+		now, date, revision, startTime, taskID,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("no slot at %s for task %s in plan %s revision %d", startTime, taskID, date, revision)
+	}
+
+	if _, err := tx.Exec("UPDATE plans SET version = version + 1 WHERE date = $1 AND revision = $2", date, revision); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RestoreSlot restores the most recently deleted slot of a plan revision
+// that started at startTime for taskID
+func (s *Store) RestoreSlot(date string, revision int, startTime string, taskID string) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkSlotPlan(tx, date, revision); err != nil {
+		return err
+	}
+
+	var live int
+	if err := tx.QueryRow(
+		"SELECT COUNT(*) FROM slots WHERE plan_date = $1 AND plan_revision = $2 AND start_time = $3 AND task_id = $4 AND deleted_at IS NULL",
+		date, revision, startTime, taskID,
+	).Scan(&live); err != nil {
+		return err
+	}
+	if live > 0 {
+		return fmt.Errorf("plan %s revision %d already has a slot at %s for task %s", date, revision, startTime, taskID)
+	}
+
+	var id int64
+	err = tx.QueryRow(
+		"SELECT id FROM slots WHERE plan_date = $1 AND plan_revision = $2 AND start_time = $3 AND task_id = $4 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT 1",
+		date, revision, startTime, taskID,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("no deleted slot at %s for task %s in plan %s revision %d", startTime, taskID, date, revision)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE slots SET deleted_at = NULL WHERE id = $1", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE plans SET version = version + 1 WHERE date = $1 AND revision = $2", date, revision); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkSlotPlan fails unless the plan revision exists and isn't deleted
+func checkSlotPlan(tx *txn, date string, revision int) error {
+	var deletedAt sql.NullString
+	err := tx.QueryRow("SELECT deleted_at FROM plans WHERE date = $1 AND revision = $2", date, revision).Scan(&deletedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("plan not found: %s revision %d", date, revision)
+	}
+	if err != nil {
+		return err
+	}
+	if deletedAt.Valid {
+		return fmt.Errorf("plan %s revision %d is deleted; restore the plan first", date, revision)
+	}
+	return nil
+}
+
 func (s *Store) UpdateSlotNotificationTimestamp(date string, revision int, startTime string, taskID string, notificationType string, timestamp string) error {
 	var query string
 	switch notificationType {
@@ -323,7 +593,7 @@ func (s *Store) UpdateSlotNotificationTimestamp(date string, revision int, start
 		return fmt.Errorf("invalid notification type: %s", notificationType)
 	}
 
-	result, err := s.db.Exec(query, timestamp, date, revision, startTime, taskID)
+	result, err := s.conn().Exec(query, timestamp, date, revision, startTime, taskID)
 	if err != nil {
 		return fmt.Errorf("failed to update notification timestamp: %w", err)
 	}
@@ -342,10 +612,14 @@ func (s *Store) UpdateSlotNotificationTimestamp(date string, revision int, start
 
 // GetAllPlans retrieves all plans (all dates, all revisions) including deleted ones
 func (s *Store) GetAllPlans() ([]models.DayPlan, error) {
-	rows, err := s.db.Query(`
-SELECT date, revision, accepted_at, deleted_at
-FROM plans
-ORDER BY date, revision`)
+	// One query for every plan and its slots; plans without slots still get a row
+	rows, err := s.conn().Query(`
+SELECT p.date, p.revision, p.accepted_at, p.deleted_at, p.note, p.locked_until,
+	s.id, s.start_time, s.end_time, s.task_id, s.status, s.feedback_rating, s.feedback_note,
+	s.last_notified_start, s.last_notified_end, s.actual_start, s.actual_end, s.deleted_at
+FROM plans p
+LEFT JOIN slots s ON s.plan_date = p.date AND s.plan_revision = p.revision
+ORDER BY p.date, p.revision, s.start_time, s.id`)
 	if err != nil {
 		return nil, err
 	}
@@ -353,64 +627,68 @@ ORDER BY date, revision`)
 
 	var plans []models.DayPlan
 	for rows.Next() {
-		var plan models.DayPlan
+		var date, note, lockedUntil string
+		var revision int
 		var acceptedAt, deletedAt sql.NullString
-		if err := rows.Scan(&plan.Date, &plan.Revision, &acceptedAt, &deletedAt); err != nil {
+		var slotID sql.NullInt64
+		var start, end, taskID, status, rating, feedbackNote sql.NullString
+		var lastNotifiedStart, lastNotifiedEnd, actualStart, actualEnd, slotDeletedAt sql.NullString
+		if err := rows.Scan(
+			&date, &revision, &acceptedAt, &deletedAt, &note, &lockedUntil,
+			&slotID, &start, &end, &taskID, &status, &rating, &feedbackNote,
+			&lastNotifiedStart, &lastNotifiedEnd, &actualStart, &actualEnd, &slotDeletedAt,
+		); err != nil {
 			return nil, err
 		}
 
-		if acceptedAt.Valid {
-			plan.AcceptedAt = &acceptedAt.String
+		// Rows come grouped by plan, so a new date or revision starts the next one
+		if n := len(plans); n == 0 || plans[n-1].Date != date || plans[n-1].Revision != revision {
+			plan := models.DayPlan{Date: date, Revision: revision, Note: note, LockedUntil: lockedUntil}
+			if acceptedAt.Valid {
+				plan.AcceptedAt = &acceptedAt.String
+			}
+			if deletedAt.Valid {
+				plan.DeletedAt = &deletedAt.String
+			}
+			plans = append(plans, plan)
 		}
-		if deletedAt.Valid {
-			plan.DeletedAt = &deletedAt.String
+		if !slotID.Valid {
+			continue
 		}
 
-		// Get slots for this plan
-		// Note: This is N+1 query, but acceptable for this specific admin/debug function
-		// In a real high-load scenario, we would fetch all slots and map them in memory
-		slotsRows, err := s.db.Query(`
-			SELECT start_time, end_time, task_id, status, feedback_rating, feedback_note, last_notified_start, last_notified_end, deleted_at
-			FROM slots WHERE plan_date = $1 AND plan_revision = $2 ORDER BY start_time`,
-			plan.Date, plan.Revision)
-		if err != nil {
-			return nil, err
+		slot := models.Slot{
+			Start:  start.String,
+			End:    end.String,
+			TaskID: taskID.String,
+			Status: models.SlotStatus(status.String),
 		}
-		defer slotsRows.Close()
-
-		for slotsRows.Next() {
-			var slot models.Slot
-			var rating, note string
-			var lastNotifiedStart, lastNotifiedEnd, slotDeletedAt sql.NullString
-			err := slotsRows.Scan(
-				&slot.Start, &slot.End, &slot.TaskID, &slot.Status, &rating, &note, &lastNotifiedStart, &lastNotifiedEnd, &slotDeletedAt,
-			)
-			if err != nil {
-				return nil, err
-			}
-
-			if rating != "" {
-				slot.Feedback = &models.Feedback{
-					Rating: models.FeedbackRating(rating),
-					Note:   note,
-				}
-			}
-			if lastNotifiedStart.Valid {
-				slot.LastNotifiedStart = &lastNotifiedStart.String
-			}
-			if lastNotifiedEnd.Valid {
-				slot.LastNotifiedEnd = &lastNotifiedEnd.String
-			}
-			if slotDeletedAt.Valid {
-				slot.DeletedAt = &slotDeletedAt.String
+		if rating.String != "" {
+			slot.Feedback = &models.Feedback{
+				Rating: models.FeedbackRating(rating.String),
+				Note:   feedbackNote.String,
 			}
-			plan.Slots = append(plan.Slots, slot)
+		}
+		if lastNotifiedStart.Valid {
+			slot.LastNotifiedStart = &lastNotifiedStart.String
+		}
+		if lastNotifiedEnd.Valid {
+			slot.LastNotifiedEnd = &lastNotifiedEnd.String
+		}
+		if actualStart.Valid {
+			slot.ActualStart = &actualStart.String
+		}
+		if actualEnd.Valid {
+			slot.ActualEnd = &actualEnd.String
+		}
+		if slotDeletedAt.Valid {
+			slot.DeletedAt = &slotDeletedAt.String
 		}
 
-		plans = append(plans, plan)
+		plan := &plans[len(plans)-1]
+		plan.Slots = append(plan.Slots, slot)
 	}
 
-	return plans, nil
+	return plans, rows.Err()
 }
 
 // GetTaskFeedbackHistory retrieves feedback history for a specific task
@@ -421,8 +699,8 @@ func (s *Store) GetTaskFeedbackHistory(taskID string, limit int) ([]models.TaskF
 			s.task_id,
 			s.feedback_rating,
 			s.feedback_note,
-			s.start_time,
-			s.end_time
+			COALESCE(s.actual_start, s.start_time),
+			COALESCE(s.actual_end, s.end_time)
 		FROM slots s
 		JOIN plans p ON s.plan_date = p.date AND s.plan_revision = p.revision
 		WHERE s.task_id = $1
@@ -434,7 +712,7 @@ func (s *Store) GetTaskFeedbackHistory(taskID string, limit int) ([]models.TaskF
 		LIMIT $2
 	`
 
-	rows, err := s.db.Query(query, taskID, limit)
+	rows, err := s.conn().Query(query, taskID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query feedback history: %w", err)
 	}
@@ -484,3 +762,86 @@ func (s *Store) GetTaskFeedbackHistory(taskID string, limit int) ([]models.TaskF
 
 	return entries, nil
 }
+
+// GetTaskSlotHistory returns the task's slots from the latest plan revision
+// of each day that scheduled it, newest first
+func (s *Store) GetTaskSlotHistory(taskID string, limit int) ([]models.TaskSlotEntry, error) {
+	query := `
+		SELECT s.plan_date, s.plan_revision, s.start_time, s.end_time,
+			COALESCE(s.status, ''), COALESCE(s.feedback_rating, ''), COALESCE(s.feedback_note, '')
+		FROM slots s
+		JOIN plans p ON p.date = s.plan_date AND p.revision = s.plan_revision
+		WHERE s.task_id = $1
+			AND s.deleted_at IS NULL
+			AND p.deleted_at IS NULL
+			AND s.plan_revision = (
+				SELECT MAX(s2.plan_revision)
+				FROM slots s2
+				JOIN plans p2 ON p2.date = s2.plan_date AND p2.revision = s2.plan_revision
+				WHERE s2.task_id = s.task_id
+					AND s2.plan_date = s.plan_date
+					AND s2.deleted_at IS NULL
+					AND p2.deleted_at IS NULL
+			)
+		ORDER BY s.plan_date DESC, s.start_time DESC`
+	args := []interface{}{taskID}
+	if limit > 0 {
+		query += " LIMIT $2"
+		args = append(args, limit)
+	}
+
+	rows, err := s.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query slot history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.TaskSlotEntry
+	for rows.Next() {
+		var e models.TaskSlotEntry
+		if err := rows.Scan(&e.Date, &e.Revision, &e.Start, &e.End, &e.Status, &e.Rating, &e.Note); err != nil {
+			return nil, fmt.Errorf("failed to scan slot history: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating slot history: %w", err)
+	}
+	return entries, nil
+}
+
+// EachSlot streams the slots of the latest plan revisions in the date range to fn
+func (s *Store) EachSlot(startDay, endDay string, fn func(models.SlotRecord) error) error {
+	rows, err := s.conn().Query(`
+		SELECT s.plan_date, s.start_time, s.end_time, s.task_id, COALESCE(t.name, s.task_id),
+			COALESCE(s.status, ''), COALESCE(s.feedback_rating, ''), COALESCE(s.feedback_note, '')
+		FROM slots s
+		JOIN plans p ON p.date = s.plan_date AND p.revision = s.plan_revision
+		LEFT JOIN tasks t ON t.id = s.task_id
+		WHERE s.plan_date BETWEEN $1 AND $2
+			AND s.deleted_at IS NULL
+			AND p.deleted_at IS NULL
+			AND p.revision = (
+				SELECT MAX(p2.revision) FROM plans p2 WHERE p2.date = p.date AND p2.deleted_at IS NULL
+			)
+		ORDER BY s.plan_date, s.start_time`,
+		startDay, endDay)
+	if err != nil {
+		return fmt.Errorf("failed to query slots: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r models.SlotRecord
+		if err := rows.Scan(&r.Date, &r.Start, &r.End, &r.TaskID, &r.TaskName, &r.Status, &r.Rating, &r.Note); err != nil {
+			return fmt.Errorf("failed to scan slot: %w", err)
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating slots: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) AddPool(pool models.TaskPool) error {
+	if err := pool.Validate(); err != nil {
+		return err
+	}
+
+	_, err := s.conn().Exec(`
+		INSERT INTO task_pools (id, name, strategy, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(id) DO UPDATE SET
+			name = EXCLUDED.name,
+			strategy = EXCLUDED.strategy`,
+		pool.ID, pool.Name, string(pool.Strategy), pool.CreatedAt.Format(time.RFC3339))
+	return err
+}
+
+func (s *Store) GetPoolByName(name string) (models.TaskPool, error) {
+	row := s.conn().QueryRow(`
+		SELECT id, name, strategy, created_at
+		FROM task_pools WHERE name = $1`, name)
+
+	var p models.TaskPool
+	var strategy, createdAt string
+	if err := row.Scan(&p.ID, &p.Name, &strategy, &createdAt); err != nil {
+		return models.TaskPool{}, err
+	}
+	p.Strategy = constants.PoolStrategy(strategy)
+
+	var err error
+	p.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return models.TaskPool{}, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	return p, nil
+}
+
+func (s *Store) GetAllPools() ([]models.TaskPool, error) {
+	rows, err := s.conn().Query(`
+		SELECT id, name, strategy, created_at
+		FROM task_pools ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pools []models.TaskPool
+	for rows.Next() {
+		var p models.TaskPool
+		var strategy, createdAt string
+		if err := rows.Scan(&p.ID, &p.Name, &strategy, &createdAt); err != nil {
+			return nil, err
+		}
+		p.Strategy = constants.PoolStrategy(strategy)
+		p.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		pools = append(pools, p)
+	}
+
+	return pools, rows.Err()
+}
+
+func (s *Store) DeletePool(id string) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM task_pools WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete pool: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("pool not found")
+	}
+
+	// Members go back to being scheduled on their own
+	if _, err := tx.Exec(`UPDATE tasks SET pool_id = '', version = version + 1 WHERE pool_id = $1`, id); err != nil {
+		return fmt.Errorf("failed to remove tasks from pool: %w", err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) AddProject(project models.Project) error {
+	if err := project.Validate(); err != nil {
+		return err
+	}
+
+	_, err := s.conn().Exec(`
+		INSERT INTO projects (id, name, target_hours_per_week, color, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(id) DO UPDATE SET
+			name = EXCLUDED.name,
+			target_hours_per_week = EXCLUDED.target_hours_per_week,
+			color = EXCLUDED.color`,
+		project.ID, project.Name, project.TargetHoursPerWeek, project.Color, project.CreatedAt.Format(time.RFC3339))
+	return err
+}
+
+func (s *Store) GetProjectByName(name string) (models.Project, error) {
+	row := s.conn().QueryRow(`
+		SELECT id, name, target_hours_per_week, color, created_at
+		FROM projects WHERE name = $1`, name)
+
+	var p models.Project
+	var createdAt string
+	if err := row.Scan(&p.ID, &p.Name, &p.TargetHoursPerWeek, &p.Color, &createdAt); err != nil {
+		return models.Project{}, err
+	}
+
+	var err error
+	p.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return models.Project{}, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	return p, nil
+}
+
+func (s *Store) GetAllProjects() ([]models.Project, error) {
+	rows, err := s.conn().Query(`
+		SELECT id, name, target_hours_per_week, color, created_at
+		FROM projects ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []models.Project
+	for rows.Next() {
+		var p models.Project
+		var createdAt string
+		if err := rows.Scan(&p.ID, &p.Name, &p.TargetHoursPerWeek, &p.Color, &createdAt); err != nil {
+			return nil, err
+		}
+		p.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		projects = append(projects, p)
+	}
+
+	return projects, rows.Err()
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// Search queries the tsvector search index maintained by triggers on the source tables
+func (s *Store) Search(query string, limit int) ([]models.SearchResult, error) {
+	terms := models.SearchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	// Match each term as a prefix so partial words still match
+	match := make([]string, len(terms))
+	for i, term := range terms {
+		match[i] = term + ":*"
+	}
+
+	// A NULL limit returns all rows
+	var limitArg sql.NullInt64
+	if limit > 0 {
+		limitArg = sql.NullInt64{Int64: int64(limit), Valid: true}
+	}
+
+	rows, err := s.conn().Query(`
+		SELECT si.kind, si.ref_id, si.day, COALESCE(t.name, si.title), si.body
+		FROM search_index si
+		CROSS JOIN to_tsquery('simple', $1) AS q
+		LEFT JOIN slots s ON si.kind = 'note' AND s.id::text = si.ref_id
+		LEFT JOIN tasks t ON t.id = s.task_id
+		WHERE si.document @@ q
+			AND (si.kind <> 'note' OR s.plan_revision = (
+				SELECT MAX(p.revision) FROM plans p WHERE p.date = s.plan_date AND p.deleted_at IS NULL
+			))
+		ORDER BY ts_rank(si.document, q) DESC, si.day DESC
+		LIMIT $2`,
+		strings.Join(match, " & "), limitArg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query search index: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	for rows.Next() {
+		var r models.SearchResult
+		if err := rows.Scan(&r.Kind, &r.ID, &r.Date, &r.Title, &r.Snippet); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search results: %w", err)
+	}
+
+	return results, nil
+}
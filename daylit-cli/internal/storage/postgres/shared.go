@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	pq "github.com/lib/pq"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) CurrentUser() string {
+	return s.user
+}
+
+// uncopiedTaskColumns are the task columns a member's copy of a shared task
+// doesn't take from the sharer: the copy starts at version 1 and is never
+// deleted
+var uncopiedTaskColumns = []string{"version", "deleted_at"}
+
+// ShareTask shares a task of the current user with the other members. Each
+// member gets a copy of the task with the same ID, without the current
+// user's project, pool and history; members who already have it keep
+// theirs. Every member records the members in turn order, and members
+// dropped by sharing again stop taking turns. Every member's data must be
+// at the same schema version as the current user's.
+func (s *Store) ShareTask(taskID string, members []string, now time.Time) error {
+	st := models.SharedTask{TaskID: taskID}
+	for _, user := range members {
+		st.Members = append(st.Members, models.SharedMember{User: user})
+	}
+	if err := st.Validate(); err != nil {
+		return err
+	}
+	if !slices.Contains(members, s.user) {
+		return fmt.Errorf("%s must be one of the members of tasks they share", models.UserLabel(s.user))
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)", taskID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	// Members dropped from the task stop sharing it
+	previous, err := sharedMembers(tx, taskID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	for _, user := range previous {
+		if slices.Contains(members, user) {
+			continue
+		}
+		if err := deleteSharedTask(tx, user, taskID); err != nil {
+			return err
+		}
+	}
+
+	version, err := schemaVersion(tx, s.schema)
+	if err != nil {
+		return err
+	}
+	columns, err := taskColumns(tx, s.schema)
+	if err != nil {
+		return err
+	}
+	current := pq.QuoteIdentifier(s.schema)
+	joined := strings.Join(members, ",")
+	createdAt := now.UTC().Format(time.RFC3339)
+	for _, user := range members {
+		ok, err := hasTasksTable(tx, userSchema(user))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("user %s has no data yet; they need to run 'daylit --user %s init' first", models.UserLabel(user), models.UserLabel(user))
+		}
+
+		schema := pq.QuoteIdentifier(userSchema(user))
+		if user != s.user {
+			theirs, err := schemaVersion(tx, userSchema(user))
+			if err != nil {
+				return err
+			}
+			if theirs != version {
+				return fmt.Errorf("%s's data is at schema version %d and yours at %d; run 'daylit --user %s migrate' and 'daylit --user %s migrate' with the same daylit version first",
+					models.UserLabel(user), theirs, version, models.UserLabel(user), models.UserLabel(s.user))
+			}
+
+			res, err := tx.Exec("INSERT INTO "+schema+".tasks ("+columns+") SELECT "+columns+" FROM "+current+".tasks WHERE id = $1 ON CONFLICT (id) DO NOTHING", taskID)
+			if err != nil {
+				return fmt.Errorf("failed to copy the task to %s: %w", models.UserLabel(user), err)
+			}
+			// A new copy starts without the sharer's project, pool and
+			// history, which mean nothing in the member's own task list
+			if n, _ := res.RowsAffected(); n == 1 {
+				if _, err := tx.Exec("UPDATE "+schema+".tasks SET last_done = '', success_streak = 0, project_id = '', pool_id = '' WHERE id = $1", taskID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if _, err := tx.Exec(`
+			INSERT INTO `+schema+`.shared_tasks (task_id, members, created_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (task_id) DO UPDATE SET members = EXCLUDED.members`,
+			taskID, joined, createdAt); err != nil {
+			return fmt.Errorf("failed to share the task with %s: %w", models.UserLabel(user), err)
+		}
+	}
+	return tx.Commit()
+}
+
+// UnshareTask stops sharing a task with all its members. Everyone keeps
+// their copy of the task as an ordinary one.
+func (s *Store) UnshareTask(taskID string) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	members, err := sharedMembers(tx, taskID)
+	if err != nil {
+		return err
+	}
+	for _, user := range members {
+		if err := deleteSharedTask(tx, user, taskID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *Store) GetSharedTasks() ([]models.SharedTask, error) {
+	rows, err := s.conn().Query("SELECT task_id, members, created_at FROM shared_tasks ORDER BY created_at, task_id")
+	if err != nil {
+		return nil, err
+	}
+	var shared []models.SharedTask
+	for rows.Next() {
+		var st models.SharedTask
+		var members, createdAt string
+		if err := rows.Scan(&st.TaskID, &members, &createdAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		st.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		for _, user := range strings.Split(members, ",") {
+			st.Members = append(st.Members, models.SharedMember{User: user})
+		}
+		shared = append(shared, st)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Each member's copy knows when they last did the task
+	tables := make(map[string]bool)
+	for i := range shared {
+		for j := range shared[i].Members {
+			m := &shared[i].Members[j]
+			schema := userSchema(m.User)
+			ok, seen := tables[schema]
+			if !seen {
+				if ok, err = hasTasksTable(s.conn(), schema); err != nil {
+					return nil, err
+				}
+				tables[schema] = ok
+			}
+			if !ok {
+				continue
+			}
+			var lastDone sql.NullString
+			err := s.conn().QueryRow("SELECT last_done FROM "+pq.QuoteIdentifier(schema)+".tasks WHERE id = $1 AND deleted_at IS NULL", shared[i].TaskID).Scan(&lastDone)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("failed to get when %s last did a shared task: %w", models.UserLabel(m.User), err)
+			}
+			m.LastDone = lastDone.String
+		}
+	}
+	return shared, nil
+}
+
+// queryRower is the part of *sql.DB and *sql.Tx the schema lookups need
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// hasTasksTable reports whether schema holds a user's tables, which it does
+// once they ran init
+func hasTasksTable(q queryRower, schema string) (bool, error) {
+	var exists bool
+	err := q.QueryRow("SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = $1 AND table_name = 'tasks')", schema).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up schema %s: %w", schema, err)
+	}
+	return exists, nil
+}
+
+// taskColumns returns the quoted columns of the tasks table in schema, less
+// uncopiedTaskColumns, for copying a task to another user. They are named
+// on both sides of the copy so that schemas whose columns were added in a
+// different order still line up, and read from the schema so that columns
+// added by later migrations are copied too.
+func taskColumns(tx *txn, schema string) (string, error) {
+	rows, err := tx.Query(`
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = $1 AND table_name = 'tasks'
+		ORDER BY ordinal_position`, schema)
+	if err != nil {
+		return "", fmt.Errorf("failed to list the task columns of %s: %w", schema, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return "", err
+		}
+		if !slices.Contains(uncopiedTaskColumns, column) {
+			columns = append(columns, pq.QuoteIdentifier(column))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf("schema %s has no tasks table", schema)
+	}
+	return strings.Join(columns, ", "), nil
+}
+
+// schemaVersion returns the migration version of a user's schema
+func schemaVersion(q queryRower, schema string) (int, error) {
+	var version int
+	err := q.QueryRow("SELECT version FROM " + pq.QuoteIdentifier(schema) + ".schema_version").Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the schema version of %s: %w", schema, err)
+	}
+	return version, nil
+}
+
+// sharedMembers returns the members of a task the current user shares
+func sharedMembers(tx *txn, taskID string) ([]string, error) {
+	var members string
+	if err := tx.QueryRow("SELECT members FROM shared_tasks WHERE task_id = $1", taskID).Scan(&members); err != nil {
+		return nil, err
+	}
+	return strings.Split(members, ","), nil
+}
+
+// deleteSharedTask stops user sharing a task, skipping users whose tables
+// are gone
+func deleteSharedTask(tx *txn, user, taskID string) error {
+	ok, err := hasTasksTable(tx, userSchema(user))
+	if err != nil || !ok {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM "+pq.QuoteIdentifier(userSchema(user))+".shared_tasks WHERE task_id = $1", taskID)
+	return err
+}
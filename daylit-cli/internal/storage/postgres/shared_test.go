@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pq "github.com/lib/pq"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// TestShareTask checks that a member's copy of a shared task has every
+// column of the sharer's but their history, and that sharing across schema
+// versions is refused
+// Set POSTGRES_TEST_URL environment variable to run this test
+func TestShareTask(t *testing.T) {
+	connStr := os.Getenv("POSTGRES_TEST_URL")
+	if connStr == "" {
+		t.Skip("POSTGRES_TEST_URL not set, skipping PostgreSQL sharing test")
+	}
+
+	newUser := func(name string) *Store {
+		store, err := NewForUser(connStr, fmt.Sprintf("share_%s_%d", name, os.Getpid()))
+		if err != nil {
+			t.Fatalf("Failed to create store: %v", err)
+		}
+		if err := store.Init(); err != nil {
+			t.Fatalf("Failed to initialize store: %v", err)
+		}
+		t.Cleanup(func() {
+			if _, err := store.GetDB().Exec("DROP SCHEMA " + pq.QuoteIdentifier(store.schema) + " CASCADE"); err != nil {
+				t.Errorf("Failed to drop schema %s: %v", store.schema, err)
+			}
+			store.Close()
+		})
+		return store
+	}
+	alice, bob := newUser("alice"), newUser("bob")
+
+	offset := 5
+	task := models.Task{
+		ID: "share-task-1", Name: "Water the plants", Kind: constants.TaskKindFlexible, DurationMin: 15,
+		EarliestStart: "08:00", LatestEnd: "20:00",
+		Recurrence: models.Recurrence{
+			Type: constants.RecurrenceWeekly, IntervalDays: 1, WeekdayMask: []time.Weekday{time.Monday, time.Thursday},
+			Start: "2025-01-06", Until: "2025-12-31",
+		},
+		Priority: 2, EnergyBand: constants.EnergyLow, Active: true,
+		LastDone: "2025-06-01", SuccessStreak: 3, AvgActualDurationMin: 12.5,
+		NiceToHave: true, Leisure: true, Context: "home",
+		NotifyStartDisabled: true, NotifyOffsetMin: &offset, NotifyMessage: "Plants!", NotifyUrgency: "low", NotifySound: "bell",
+	}
+	if err := alice.AddTask(task); err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	if err := alice.ShareTask(task.ID, []string{alice.user, bob.user}, now); err != nil {
+		t.Fatalf("Failed to share task: %v", err)
+	}
+
+	got, err := bob.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("Member has no copy of the task: %v", err)
+	}
+	// Everything but the sharer's history and version is copied
+	want, err := alice.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	want.LastDone, want.SuccessStreak = "", 0
+	want.Version = got.Version
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Member's copy = %+v, want %+v", got, want)
+	}
+	shared, err := bob.GetSharedTasks()
+	if err != nil {
+		t.Fatalf("Failed to get shared tasks: %v", err)
+	}
+	if len(shared) != 1 || !shared[0].CreatedAt.Equal(now) {
+		t.Errorf("Shared tasks = %+v, want one shared at %v", shared, now)
+	}
+
+	// A member on another schema version can't take a copy
+	carol := newUser("carol")
+	if _, err := carol.GetDB().Exec("UPDATE " + pq.QuoteIdentifier(carol.schema) + ".schema_version SET version = version + 1"); err != nil {
+		t.Fatalf("Failed to change schema version: %v", err)
+	}
+	err = alice.ShareTask(task.ID, []string{alice.user, bob.user, carol.user}, now)
+	if err == nil || !strings.Contains(err.Error(), "schema version") {
+		t.Errorf("Sharing across schema versions = %v, want it refused", err)
+	}
+}
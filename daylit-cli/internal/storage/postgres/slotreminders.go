@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) AddSlotReminder(reminder models.SlotReminder) error {
+	if err := reminder.Validate(); err != nil {
+		return err
+	}
+
+	var sentAt sql.NullString
+	if reminder.SentAt != nil {
+		sentAt = sql.NullString{String: reminder.SentAt.Format(time.RFC3339), Valid: true}
+	}
+
+	_, err := s.conn().Exec(`
+		INSERT INTO slot_reminders (id, plan_date, slot_start, task_id, offset_min, message, sent_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		reminder.ID, reminder.PlanDate, reminder.SlotStart, reminder.TaskID, reminder.OffsetMin,
+		reminder.Message, sentAt, reminder.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to insert slot reminder: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetSlotReminders(startDay, endDay string) ([]models.SlotReminder, error) {
+	rows, err := s.conn().Query(`
+		SELECT id, plan_date, slot_start, task_id, offset_min, message, sent_at, created_at
+		FROM slot_reminders
+		WHERE plan_date >= $1 AND plan_date <= $2
+		ORDER BY plan_date, slot_start, offset_min DESC`, startDay, endDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []models.SlotReminder
+	for rows.Next() {
+		var r models.SlotReminder
+		var sentAt sql.NullString
+		var createdAt string
+		if err := rows.Scan(&r.ID, &r.PlanDate, &r.SlotStart, &r.TaskID, &r.OffsetMin, &r.Message, &sentAt, &createdAt); err != nil {
+			return nil, err
+		}
+		if sentAt.Valid {
+			t, err := time.Parse(time.RFC3339, sentAt.String)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse sent_at: %w", err)
+			}
+			r.SentAt = &t
+		}
+		r.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		reminders = append(reminders, r)
+	}
+
+	return reminders, rows.Err()
+}
+
+func (s *Store) MarkSlotReminderSent(id string, sentAt time.Time) error {
+	result, err := s.conn().Exec(`UPDATE slot_reminders SET sent_at = $1 WHERE id = $2`, sentAt.Format(time.RFC3339), id)
+	if err != nil {
+		return fmt.Errorf("failed to update slot reminder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("slot reminder not found")
+	}
+	return nil
+}
+
+func (s *Store) DeleteSlotReminder(id string) error {
+	result, err := s.conn().Exec(`DELETE FROM slot_reminders WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete slot reminder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("slot reminder not found")
+	}
+	return nil
+}
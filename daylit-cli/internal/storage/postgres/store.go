@@ -1,10 +1,10 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
-	"io/fs"
 	"net/url"
 	"strings"
 	"time"
@@ -14,6 +14,7 @@ import (
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
 	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
 	"github.com/julianstephens/daylit/daylit-cli/internal/migration"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
 	"github.com/julianstephens/daylit/daylit-cli/migrations"
 )
@@ -21,6 +22,13 @@ import (
 type Store struct {
 	connStr string
 	db      *sql.DB
+	// schema holds the tables of the user the store is for
+	schema string
+	user   string
+	// tx is the transaction of a store handed out by WithTx, and savepoints
+	// counts the savepoints begun in it
+	tx         *sql.Tx
+	savepoints int
 }
 
 var (
@@ -31,13 +39,37 @@ var (
 func New(connStr string) *Store {
 	s := &Store{
 		connStr: connStr,
+		schema:  constants.AppName,
 	}
 	s.ensureSearchPath()
 	return s
 }
 
+// NewForUser creates a store for one user's data in a database shared by
+// several people. Each user's tables live in a schema of their own, so the
+// connection string must not set search_path itself. An empty user, or
+// "default", is the default user, the same as New.
+func NewForUser(connStr, user string) (*Store, error) {
+	if user == "" || user == models.DefaultUserName {
+		return New(connStr), nil
+	}
+	if err := models.ValidateUserName(user); err != nil {
+		return nil, err
+	}
+	if hasSearchPathParam(connStr) || urlHasSearchPath(connStr) {
+		return nil, fmt.Errorf("the connection string sets search_path; remove it to choose a user")
+	}
+	s := &Store{
+		connStr: connStr,
+		schema:  userSchema(user),
+		user:    user,
+	}
+	s.ensureSearchPath()
+	return s, nil
+}
+
 func (s *Store) ensureSearchPath() {
-	// Ensure search_path is set to daylit in the connection string
+	// Ensure search_path is set to the store's schema in the connection string
 	if strings.HasPrefix(s.connStr, "postgres://") || strings.HasPrefix(s.connStr, "postgresql://") {
 		u, err := url.Parse(s.connStr)
 		if err != nil {
@@ -47,18 +79,28 @@ func (s *Store) ensureSearchPath() {
 		q := u.Query()
 		// Only set search_path if it's not already present
 		if q.Get("search_path") == "" {
-			q.Set("search_path", constants.AppName)
+			q.Set("search_path", s.schema)
 			u.RawQuery = q.Encode()
 			s.connStr = u.String()
 		}
 	} else {
 		// Assume DSN format - only append if search_path is not already present
 		if !hasSearchPathParam(s.connStr) {
-			s.connStr = strings.TrimSpace(s.connStr) + " search_path=" + constants.AppName
+			s.connStr = strings.TrimSpace(s.connStr) + " search_path=" + s.schema
 		}
 	}
 }
 
+// urlHasSearchPath returns true if the given URL-style connection string
+// has a search_path query parameter
+func urlHasSearchPath(connStr string) bool {
+	if !strings.HasPrefix(connStr, "postgres://") && !strings.HasPrefix(connStr, "postgresql://") {
+		return false
+	}
+	u, err := url.Parse(connStr)
+	return err == nil && u.Query().Get("search_path") != ""
+}
+
 // hasSearchPathParam returns true if the given DSN-style connection string
 // contains a search_path parameter key (case-insensitive).
 func hasSearchPathParam(connStr string) bool {
@@ -159,7 +201,7 @@ func (s *Store) Init() error {
 	db.SetConnMaxLifetime(5 * time.Minute)
 
 	// Create schema if it doesn't exist (before assigning to s.db to maintain consistency)
-	if _, err := db.Exec("CREATE SCHEMA IF NOT EXISTS " + constants.AppName); err != nil {
+	if _, err := db.Exec("CREATE SCHEMA IF NOT EXISTS " + pq.QuoteIdentifier(s.schema)); err != nil {
 		db.Close()
 		return fmt.Errorf("failed to create schema: %w", err)
 	}
@@ -180,8 +222,9 @@ func (s *Store) Init() error {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	// Initialize default settings if not present
-	if _, err := s.GetSettings(); err != nil {
+	// Initialize default settings if not present or incomplete
+	settings, err := s.GetSettings()
+	if err != nil || settings.DayStart == "" {
 		defaultSettings := storage.Settings{
 			DayStart:                   constants.DefaultDayStart,
 			DayEnd:                     constants.DefaultDayEnd,
@@ -193,6 +236,8 @@ func (s *Store) Init() error {
 			BlockEndOffsetMin:          constants.DefaultBlockEndOffsetMin,
 			NotificationGracePeriodMin: constants.DefaultNotificationGracePeriodMin,
 			Timezone:                   constants.DefaultTimezone,
+			Theme:                      constants.DefaultTheme,
+			MorningPlan:                constants.DefaultMorningPlan,
 		}
 		if err := s.SaveSettings(defaultSettings); err != nil {
 			return fmt.Errorf("failed to save default settings: %w", err)
@@ -226,6 +271,17 @@ func (s *Store) Load() error {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// A user's schema only exists once they ran init
+	if s.user != "" {
+		var exists bool
+		if err := s.db.QueryRow("SELECT EXISTS (SELECT 1 FROM information_schema.schemata WHERE schema_name = $1)", s.schema).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to look up user %q: %w", s.user, err)
+		}
+		if !exists {
+			return fmt.Errorf("user %q has no data yet; run 'daylit --user %s init' first", s.user, s.user)
+		}
+	}
+
 	// Validate schema version using embedded migrations
 	if err := s.validateSchemaVersion(); err != nil {
 		return err
@@ -241,11 +297,22 @@ func (s *Store) Close() error {
 	return nil
 }
 
+// Ping checks that the database server can be reached, giving up after
+// constants.HealthCheckTimeout
+func (s *Store) Ping() error {
+	if s.db == nil {
+		return fmt.Errorf("database not loaded")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.HealthCheckTimeout)
+	defer cancel()
+	return s.db.PingContext(ctx)
+}
+
 func (s *Store) runMigrations() error {
 	// Get the embedded PostgreSQL migrations sub-filesystem
-	subFS, err := fs.Sub(migrations.FS, "postgres")
+	subFS, err := migrations.Backend("postgres")
 	if err != nil {
-		return fmt.Errorf("failed to access postgres migrations: %w", err)
+		return err
 	}
 
 	runner := migration.NewRunner(s.db, subFS)
@@ -256,9 +323,9 @@ func (s *Store) runMigrations() error {
 }
 
 func (s *Store) validateSchemaVersion() error {
-	subFS, err := fs.Sub(migrations.FS, "postgres")
+	subFS, err := migrations.Backend("postgres")
 	if err != nil {
-		return fmt.Errorf("failed to access postgres migrations: %w", err)
+		return err
 	}
 
 	runner := migration.NewRunner(s.db, subFS)
@@ -269,3 +336,9 @@ func (s *Store) GetConfigPath() string {
 	// Return a non-sensitive identifier instead of the full connection string
 	return "postgresql"
 }
+
+// GetDB returns the underlying database connection.
+// Returns nil if the database has not been initialized or loaded.
+func (s *Store) GetDB() *sql.DB {
+	return s.db
+}
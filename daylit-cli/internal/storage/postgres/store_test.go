@@ -171,3 +171,75 @@ func TestValidateConnString(t *testing.T) {
 		})
 	}
 }
+
+func TestNewForUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		connStr string
+		user    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "default user",
+			connStr: "host=localhost dbname=daylit",
+			want:    "host=localhost dbname=daylit search_path=daylit",
+		},
+		{
+			name:    "DSN",
+			connStr: "host=localhost dbname=daylit",
+			user:    "sam",
+			want:    "host=localhost dbname=daylit search_path=daylit_sam",
+		},
+		{
+			name:    "URL",
+			connStr: "postgres://sam@localhost:5432/daylit",
+			user:    "sam",
+			want:    "postgres://sam@localhost:5432/daylit?search_path=daylit_sam",
+		},
+		{
+			name:    "search_path already set",
+			connStr: "host=localhost search_path=public",
+			user:    "sam",
+			wantErr: true,
+		},
+		{
+			name:    "search_path already set in URL",
+			connStr: "postgres://localhost/daylit?search_path=public",
+			user:    "sam",
+			wantErr: true,
+		},
+		{
+			name:    "invalid user name",
+			connStr: "host=localhost dbname=daylit",
+			user:    "Sam; DROP",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewForUser(tt.connStr, tt.user)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewForUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && s.connStr != tt.want {
+				t.Errorf("connStr = %q, want %q", s.connStr, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaUser(t *testing.T) {
+	for _, user := range []string{"", "sam", "alex_2"} {
+		got, ok := schemaUser(userSchema(user))
+		if !ok || got != user {
+			t.Errorf("schemaUser(userSchema(%q)) = %q, %v", user, got, ok)
+		}
+	}
+	for _, schema := range []string{"public", "daylit_", "daylit_Sam", "daylitsam"} {
+		if _, ok := schemaUser(schema); ok {
+			t.Errorf("schemaUser(%q) should not be a user", schema)
+		}
+	}
+}
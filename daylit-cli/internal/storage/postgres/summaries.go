@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// GetDaySummaries returns per-day activity summaries for the inclusive date range
+func (s *Store) GetDaySummaries(startDay, endDay string) ([]models.DaySummary, error) {
+	summaries := make(map[string]*models.DaySummary)
+
+	// Aggregate slots of the latest non-deleted revision for each day
+	rows, err := s.conn().Query(`
+		SELECT
+			p.date,
+			p.accepted_at IS NOT NULL,
+			COUNT(s.id),
+			COALESCE(SUM(CASE WHEN s.feedback_rating IS NOT NULL AND s.feedback_rating != '' THEN 1 ELSE 0 END), 0)
+		FROM plans p
+		LEFT JOIN slots s ON s.plan_date = p.date AND s.plan_revision = p.revision AND s.deleted_at IS NULL
+		WHERE p.date BETWEEN $1 AND $2
+			AND p.deleted_at IS NULL
+			AND p.revision = (
+				SELECT MAX(p2.revision) FROM plans p2 WHERE p2.date = p.date AND p2.deleted_at IS NULL
+			)
+		GROUP BY p.date, p.accepted_at`,
+		startDay, endDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query plan summaries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		summary := models.DaySummary{HasPlan: true}
+		if err := rows.Scan(&summary.Date, &summary.Accepted, &summary.TotalSlots, &summary.SlotsWithFeedback); err != nil {
+			return nil, fmt.Errorf("failed to scan plan summary: %w", err)
+		}
+		summaries[summary.Date] = &summary
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating plan summaries: %w", err)
+	}
+
+	// Count habit entries per day, ignoring deleted entries and habits
+	habitRows, err := s.conn().Query(`
+		SELECT e.day, COUNT(*)
+		FROM habit_entries e
+		JOIN habits h ON h.id = e.habit_id
+		WHERE e.day BETWEEN $1 AND $2
+			AND e.deleted_at IS NULL
+			AND h.deleted_at IS NULL
+		GROUP BY e.day`,
+		startDay, endDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query habit summaries: %w", err)
+	}
+	defer habitRows.Close()
+
+	for habitRows.Next() {
+		var day string
+		var count int
+		if err := habitRows.Scan(&day, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan habit summary: %w", err)
+		}
+		summary, ok := summaries[day]
+		if !ok {
+			summary = &models.DaySummary{Date: day}
+			summaries[day] = summary
+		}
+		summary.HabitsCompleted = count
+	}
+	if err := habitRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating habit summaries: %w", err)
+	}
+
+	result := make([]models.DaySummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date < result[j].Date
+	})
+
+	return result, nil
+}
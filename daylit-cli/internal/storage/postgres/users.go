@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	pq "github.com/lib/pq"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// userSchema returns the schema that holds user's tables. The default user
+// keeps the schema daylit always used, so existing databases stay theirs.
+func userSchema(user string) string {
+	if user == "" {
+		return constants.AppName
+	}
+	return constants.AppName + "_" + user
+}
+
+// schemaUser is the reverse of userSchema, reporting false for schemas that
+// don't belong to a user
+func schemaUser(schema string) (string, bool) {
+	if schema == constants.AppName {
+		return "", true
+	}
+	user, ok := strings.CutPrefix(schema, constants.AppName+"_")
+	if !ok || models.ValidateUserName(user) != nil {
+		return "", false
+	}
+	return user, true
+}
+
+// ListUsers returns every user with a schema of daylit tables in the
+// database, with how many tasks they have and the date of their latest plan
+func (s *Store) ListUsers() ([]models.User, error) {
+	rows, err := s.conn().Query(
+		"SELECT table_schema FROM information_schema.tables WHERE table_name = 'tasks' AND (table_schema = $1 OR table_schema LIKE $2) ORDER BY table_schema",
+		constants.AppName, constants.AppName+`\_%`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	var schemas []string
+	for rows.Next() {
+		var schema string
+		if err := rows.Scan(&schema); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var users []models.User
+	for _, schema := range schemas {
+		name, ok := schemaUser(schema)
+		if !ok {
+			continue
+		}
+		user := models.User{Name: name, Current: schema == s.schema}
+		quoted := pq.QuoteIdentifier(schema)
+		if err := s.conn().QueryRow("SELECT COUNT(*) FROM " + quoted + ".tasks WHERE deleted_at IS NULL").Scan(&user.Tasks); err != nil {
+			return nil, fmt.Errorf("failed to count tasks of %s: %w", schema, err)
+		}
+		var lastPlan sql.NullString
+		if err := s.conn().QueryRow("SELECT MAX(date) FROM " + quoted + ".plans WHERE deleted_at IS NULL").Scan(&lastPlan); err != nil {
+			return nil, fmt.Errorf("failed to get plans of %s: %w", schema, err)
+		}
+		user.LastPlan = lastPlan.String
+		users = append(users, user)
+	}
+	return users, nil
+}
@@ -0,0 +1,79 @@
+package storage_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestProjects(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	now := time.Now().Truncate(time.Second)
+	projects := []models.Project{
+		{ID: "project-2", Name: "Writing", TargetHoursPerWeek: 5, Color: "#ff8800", CreatedAt: now},
+		{ID: "project-1", Name: "Fitness", TargetHoursPerWeek: 3.5, CreatedAt: now},
+	}
+	for _, p := range projects {
+		if err := store.AddProject(p); err != nil {
+			t.Fatalf("failed to add project: %v", err)
+		}
+	}
+
+	if err := store.AddProject(models.Project{ID: "project-3", Name: "Bad", Color: "orange", CreatedAt: now}); err == nil {
+		t.Error("expected error for invalid color")
+	}
+
+	all, err := store.GetAllProjects()
+	if err != nil {
+		t.Fatalf("failed to get projects: %v", err)
+	}
+	if len(all) != 2 || all[0].Name != "Fitness" || all[1].Name != "Writing" {
+		t.Fatalf("expected projects ordered by name, got %+v", all)
+	}
+
+	got, err := store.GetProjectByName("Writing")
+	if err != nil {
+		t.Fatalf("failed to get project by name: %v", err)
+	}
+	if got.ID != "project-2" || got.TargetHoursPerWeek != 5 || got.Color != "#ff8800" || !got.CreatedAt.Equal(now) {
+		t.Errorf("unexpected project: %+v", got)
+	}
+
+	if _, err := store.GetProjectByName("Missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for missing project, got %v", err)
+	}
+
+	// Tasks keep their project across updates
+	task := models.Task{
+		ID: "task-project", Name: "Draft chapter", Kind: constants.TaskKindFlexible, DurationMin: 60,
+		Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 2, Active: true, ProjectID: "project-2",
+	}
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	saved, err := store.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if saved.ProjectID != "project-2" {
+		t.Errorf("task project = %q, want %q", saved.ProjectID, "project-2")
+	}
+
+	saved.ProjectID = ""
+	if err := store.UpdateTask(saved); err != nil {
+		t.Fatalf("failed to update task: %v", err)
+	}
+	tasks, err := store.GetAllTasksIncludingDeleted()
+	if err != nil {
+		t.Fatalf("failed to get tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ProjectID != "" {
+		t.Errorf("expected project to be cleared, got %+v", tasks)
+	}
+}
@@ -0,0 +1,115 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+// seedPurge adds to the plan range seed a deleted task the plans use, a
+// deleted task nothing uses and a deleted habit with one entry
+func seedPurge(t *testing.T, store storage.Provider) {
+	t.Helper()
+
+	for _, id := range []string{"task-1", "task-2"} {
+		task := models.Task{
+			ID:          id,
+			Name:        "Task " + id,
+			Kind:        constants.TaskKindFlexible,
+			DurationMin: 30,
+			Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+			Priority:    1,
+			Active:      true,
+		}
+		if err := store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+	}
+	seedPlanRange(t, store)
+	for _, id := range []string{"task-1", "task-2"} {
+		if err := store.DeleteTask(id); err != nil {
+			t.Fatalf("failed to delete task: %v", err)
+		}
+	}
+
+	now := time.Now()
+	if err := store.AddHabit(models.Habit{ID: "habit-1", Name: "Stretch", CreatedAt: now}); err != nil {
+		t.Fatalf("failed to add habit: %v", err)
+	}
+	entry := models.HabitEntry{ID: "entry-1", HabitID: "habit-1", Day: "2025-03-10", CreatedAt: now, UpdatedAt: now}
+	if err := store.AddHabitEntry(entry); err != nil {
+		t.Fatalf("failed to add habit entry: %v", err)
+	}
+	if err := store.DeleteHabit("habit-1"); err != nil {
+		t.Fatalf("failed to delete habit: %v", err)
+	}
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	for name, store := range rangeStores(t) {
+		t.Run(name, func(t *testing.T) {
+			seedPurge(t, store)
+
+			// Nothing was deleted before yesterday
+			summary, err := store.PurgeDeleted(time.Now().Add(-24*time.Hour), models.PurgeKinds, false)
+			if err != nil {
+				t.Fatalf("PurgeDeleted failed: %v", err)
+			}
+			if summary != (models.PurgeSummary{}) {
+				t.Errorf("expected nothing to purge inside the window, got %+v", summary)
+			}
+
+			want := models.PurgeSummary{Tasks: 1, Plans: 1, Slots: 1, Habits: 1, HabitEntries: 1, KeptTasks: 1}
+			cutoff := time.Now().Add(time.Minute)
+
+			summary, err = store.PurgeDeleted(cutoff, models.PurgeKinds, true)
+			if err != nil {
+				t.Fatalf("PurgeDeleted (dry run) failed: %v", err)
+			}
+			if summary != want {
+				t.Errorf("dry run: got %+v, want %+v", summary, want)
+			}
+			if tasks, _ := store.GetAllTasksIncludingDeleted(); len(tasks) != 2 {
+				t.Errorf("dry run removed tasks: %d left", len(tasks))
+			}
+
+			// Habits only
+			summary, err = store.PurgeDeleted(cutoff, []models.PurgeKind{models.PurgeHabits}, false)
+			if err != nil {
+				t.Fatalf("PurgeDeleted (habits) failed: %v", err)
+			}
+			if summary != (models.PurgeSummary{Habits: 1, HabitEntries: 1}) {
+				t.Errorf("habits only: got %+v", summary)
+			}
+			if habits, _ := store.GetAllHabits(true, true); len(habits) != 0 {
+				t.Errorf("expected the habit to be purged, got %+v", habits)
+			}
+
+			summary, err = store.PurgeDeleted(cutoff, models.PurgeKinds, false)
+			if err != nil {
+				t.Fatalf("PurgeDeleted failed: %v", err)
+			}
+			want.Habits, want.HabitEntries = 0, 0
+			if summary != want {
+				t.Errorf("got %+v, want %+v", summary, want)
+			}
+
+			if err := store.RestorePlan("2025-03-12"); err == nil {
+				t.Error("expected a purged plan to be gone for good")
+			}
+			tasks, err := store.GetAllTasksIncludingDeleted()
+			if err != nil {
+				t.Fatalf("failed to get tasks: %v", err)
+			}
+			if len(tasks) != 1 || tasks[0].ID != "task-1" {
+				t.Errorf("expected only the task plans still use to remain, got %+v", tasks)
+			}
+			if plans, _ := store.GetPlansRange("2025-03-10", "2025-03-14"); len(plans) != 4 {
+				t.Errorf("expected the live plans to be untouched, got %d", len(plans))
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestSearch(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	task := models.Task{
+		ID:          "task-search-1",
+		Name:        "Morning Run",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+		Priority:    1,
+		Active:      true,
+	}
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	habit := models.Habit{ID: uuid.New().String(), Name: "Running log", CreatedAt: time.Now()}
+	if err := store.AddHabit(habit); err != nil {
+		t.Fatalf("failed to add habit: %v", err)
+	}
+
+	ot := models.OTEntry{
+		ID:        uuid.New().String(),
+		Day:       "2024-05-01",
+		Title:     "Finish report",
+		Note:      "Then go for a run by the river",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := store.AddOTEntry(ot); err != nil {
+		t.Fatalf("failed to add OT entry: %v", err)
+	}
+
+	plan := models.DayPlan{
+		Date: "2024-05-01",
+		Slots: []models.Slot{
+			{Start: "07:00", End: "07:30", TaskID: task.ID, Status: constants.SlotStatusDone, Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack, Note: "Legs felt heavy on the hills"}},
+			{Start: "08:00", End: "08:30", TaskID: task.ID, Status: constants.SlotStatusPlanned},
+		},
+	}
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+
+	t.Run("prefix matches across kinds", func(t *testing.T) {
+		results, err := store.Search("run", 0)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		kinds := make(map[constants.SearchKind]bool)
+		for _, r := range results {
+			kinds[r.Kind] = true
+		}
+		for _, want := range []constants.SearchKind{constants.SearchKindTask, constants.SearchKindHabit, constants.SearchKindOT} {
+			if !kinds[want] {
+				t.Errorf("expected a %s result for %q, got %+v", want, "run", results)
+			}
+		}
+	})
+
+	t.Run("notes report the slot's task and date", func(t *testing.T) {
+		results, err := store.Search("HILLS!", 0)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("expected 1 result, got %d: %+v", len(results), results)
+		}
+		r := results[0]
+		if r.Kind != constants.SearchKindNote || r.Title != task.Name || r.Date != plan.Date {
+			t.Errorf("unexpected note result: %+v", r)
+		}
+	})
+
+	t.Run("all terms must match", func(t *testing.T) {
+		results, err := store.Search("morning hills", 0)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("expected no results, got %+v", results)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		results, err := store.Search("run", 1)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if len(results) != 1 {
+			t.Errorf("expected 1 result, got %d", len(results))
+		}
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		results, err := store.Search(" \"* ", 0)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("expected no results, got %+v", results)
+		}
+	})
+
+	t.Run("index follows updates and deletes", func(t *testing.T) {
+		task.Name = "Evening Swim"
+		if err := store.UpdateTask(task); err != nil {
+			t.Fatalf("failed to update task: %v", err)
+		}
+		if results, _ := store.Search("morning", 0); len(results) != 0 {
+			t.Errorf("renamed task still matches old name: %+v", results)
+		}
+		if results, _ := store.Search("swim", 0); len(results) != 1 {
+			t.Errorf("renamed task not found by new name: %+v", results)
+		}
+
+		if err := store.DeleteHabit(habit.ID); err != nil {
+			t.Fatalf("failed to delete habit: %v", err)
+		}
+		if err := store.DeleteOTEntry(ot.Day); err != nil {
+			t.Fatalf("failed to delete OT entry: %v", err)
+		}
+		if results, _ := store.Search("run", 0); len(results) != 0 {
+			t.Errorf("deleted habit or OT entry still matches: %+v", results)
+		}
+
+		// Notes from superseded revisions are hidden
+		plan.Revision = 2
+		plan.Slots[0].Feedback.Note = "Flat route today"
+		if err := store.SavePlan(plan); err != nil {
+			t.Fatalf("failed to save revision: %v", err)
+		}
+		if results, _ := store.Search("hills", 0); len(results) != 0 {
+			t.Errorf("note from old revision still matches: %+v", results)
+		}
+		if results, _ := store.Search("flat", 0); len(results) != 1 {
+			t.Errorf("note from latest revision not found: %+v", results)
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestEachSlot(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	task := models.Task{
+		ID:          "task-export-1",
+		Name:        "Write",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+		Priority:    2,
+		Active:      true,
+	}
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	plans := []models.DayPlan{
+		{Date: "2024-05-02", Slots: []models.Slot{
+			{Start: "10:00", End: "10:30", TaskID: task.ID, Status: constants.SlotStatusPlanned},
+			{Start: "09:00", End: "09:30", TaskID: task.ID, Status: constants.SlotStatusDone, Feedback: &models.Feedback{Rating: constants.FeedbackTooMuch, Note: "ran long"}},
+		}},
+		{Date: "2024-05-01", Slots: []models.Slot{
+			{Start: "08:00", End: "08:30", TaskID: task.ID, Status: constants.SlotStatusPlanned},
+		}},
+		{Date: "2024-05-09", Slots: []models.Slot{
+			{Start: "08:00", End: "08:30", TaskID: task.ID, Status: constants.SlotStatusPlanned},
+		}},
+	}
+	for _, plan := range plans {
+		if err := store.SavePlan(plan); err != nil {
+			t.Fatalf("failed to save plan %s: %v", plan.Date, err)
+		}
+	}
+
+	// A newer revision replaces the slots of its day
+	revised := models.DayPlan{Date: "2024-05-01", Revision: 2, Slots: []models.Slot{
+		{Start: "11:00", End: "11:30", TaskID: task.ID, Status: constants.SlotStatusPlanned},
+	}}
+	if err := store.SavePlan(revised); err != nil {
+		t.Fatalf("failed to save revision: %v", err)
+	}
+
+	var records []models.SlotRecord
+	err := store.EachSlot("2024-05-01", "2024-05-07", func(r models.SlotRecord) error {
+		records = append(records, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachSlot failed: %v", err)
+	}
+
+	want := []string{"2024-05-01 11:00", "2024-05-02 09:00", "2024-05-02 10:00"}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %d: %+v", len(want), len(records), records)
+	}
+	for i, r := range records {
+		if got := r.Date + " " + r.Start; got != want[i] {
+			t.Errorf("record %d = %s, want %s", i, got, want[i])
+		}
+		if r.TaskName != task.Name {
+			t.Errorf("record %d task name = %q, want %q", i, r.TaskName, task.Name)
+		}
+	}
+	if r := records[1]; r.Rating != constants.FeedbackTooMuch || r.Note != "ran long" || r.Status != constants.SlotStatusDone {
+		t.Errorf("unexpected feedback record: %+v", r)
+	}
+
+	// Errors from the callback stop iteration
+	stop := errors.New("stop")
+	calls := 0
+	err = store.EachSlot("2024-05-01", "2024-05-07", func(models.SlotRecord) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("expected iteration to stop after first error, got err=%v calls=%d", err, calls)
+	}
+}
+
+func TestGetTaskSlotHistory(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	for _, task := range []models.Task{
+		{ID: "task-history-1", Name: "Write", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 2, Active: true},
+		{ID: "task-history-2", Name: "Read", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 2, Active: true},
+	} {
+		if err := store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+	}
+
+	accepted := "2024-05-01T07:00:00Z"
+	plans := []models.DayPlan{
+		// Revision 1 schedules the task; revision 2 drops it, so the day's
+		// history comes from revision 1
+		{Date: "2024-05-01", AcceptedAt: &accepted, Slots: []models.Slot{
+			{Start: "09:00", End: "09:30", TaskID: "task-history-1", Status: constants.SlotStatusDone, Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack}},
+		}},
+		{Date: "2024-05-01", Slots: []models.Slot{
+			{Start: "09:00", End: "09:30", TaskID: "task-history-2", Status: constants.SlotStatusPlanned},
+		}},
+		// Revision 2 replaces revision 1's slot
+		{Date: "2024-05-02", AcceptedAt: &accepted, Slots: []models.Slot{
+			{Start: "08:00", End: "08:30", TaskID: "task-history-1", Status: constants.SlotStatusAccepted},
+		}},
+		{Date: "2024-05-02", Slots: []models.Slot{
+			{Start: "10:00", End: "10:45", TaskID: "task-history-1", Status: constants.SlotStatusSkipped},
+		}},
+		{Date: "2024-05-03", Slots: []models.Slot{
+			{Start: "09:00", End: "09:30", TaskID: "task-history-1", Status: constants.SlotStatusPlanned},
+		}},
+	}
+	for _, plan := range plans {
+		if err := store.SavePlan(plan); err != nil {
+			t.Fatalf("failed to save plan %s: %v", plan.Date, err)
+		}
+	}
+
+	history, err := store.GetTaskSlotHistory("task-history-1", 0)
+	if err != nil {
+		t.Fatalf("failed to get slot history: %v", err)
+	}
+	want := []models.TaskSlotEntry{
+		{Date: "2024-05-03", Revision: 1, Start: "09:00", End: "09:30", Status: constants.SlotStatusPlanned},
+		{Date: "2024-05-02", Revision: 2, Start: "10:00", End: "10:45", Status: constants.SlotStatusSkipped},
+		{Date: "2024-05-01", Revision: 1, Start: "09:00", End: "09:30", Status: constants.SlotStatusDone, Rating: constants.FeedbackOnTrack},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(history), history)
+	}
+	for i := range want {
+		if history[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, history[i], want[i])
+		}
+	}
+
+	limited, err := store.GetTaskSlotHistory("task-history-1", 2)
+	if err != nil {
+		t.Fatalf("failed to get limited slot history: %v", err)
+	}
+	if len(limited) != 2 || limited[0].Date != "2024-05-03" {
+		t.Errorf("limit 2 = %+v", limited)
+	}
+}
@@ -1,4 +1,4 @@
-package storage
+package storage_test
 
 import (
 	"os"
@@ -642,8 +642,9 @@ func TestRestorePlanTimestampMatching(t *testing.T) {
 		t.Fatalf("failed to save plan: %v", err)
 	}
 
-	// Manually soft-delete one slot individually (simulating a slot being deleted before the plan)
-	// This requires direct database access since there's no API for individual slot deletion yet
+	// Soft-delete one slot on its own before the plan. DeleteSlot stamps the
+	// current second, which the plan's deletion could share, so the earlier
+	// timestamp is set directly.
 	db := store.GetDB()
 	if db == nil {
 		t.Fatal("database connection is nil")
@@ -685,3 +686,52 @@ func TestRestorePlanTimestampMatching(t *testing.T) {
 		}
 	}
 }
+
+func TestDeleteSlot(t *testing.T) {
+	for name, store := range rangeStores(t) {
+		t.Run(name, func(t *testing.T) {
+			seedPlanRange(t, store)
+
+			// Revision 2 of the 10th has slots at 13:00 and 14:00
+			if err := store.DeleteSlot("2025-03-10", 2, "13:00", "task-1"); err != nil {
+				t.Fatalf("DeleteSlot failed: %v", err)
+			}
+			plan, err := store.GetPlan("2025-03-10")
+			if err != nil {
+				t.Fatalf("GetPlan failed: %v", err)
+			}
+			if plan.Revision != 2 || len(plan.Slots) != 1 || plan.Slots[0].Start != "14:00" {
+				t.Errorf("expected revision 2 with only the 14:00 slot, got %v", planKeys([]models.DayPlan{plan}))
+			}
+
+			if err := store.DeleteSlot("2025-03-10", 2, "13:00", "task-1"); err == nil {
+				t.Error("expected deleting a deleted slot to fail")
+			}
+			if err := store.DeleteSlot("2025-03-12", 1, "08:00", "task-1"); err == nil {
+				t.Error("expected deleting a slot of a deleted plan to fail")
+			}
+			if err := store.DeleteSlot("2025-03-10", 7, "13:00", "task-1"); err == nil {
+				t.Error("expected deleting a slot of a missing revision to fail")
+			}
+
+			// Re-saving the revision keeps the deleted slot out
+			if err := store.SavePlan(plan); err != nil {
+				t.Fatalf("SavePlan failed: %v", err)
+			}
+
+			if err := store.RestoreSlot("2025-03-10", 2, "13:00", "task-1"); err != nil {
+				t.Fatalf("RestoreSlot failed: %v", err)
+			}
+			plan, err = store.GetPlan("2025-03-10")
+			if err != nil {
+				t.Fatalf("GetPlan failed: %v", err)
+			}
+			if got := planKeys([]models.DayPlan{plan}); got[0] != "2025-03-10#2 13:00 14:00" {
+				t.Errorf("expected both slots back, got %v", got)
+			}
+			if err := store.RestoreSlot("2025-03-10", 2, "13:00", "task-1"); err == nil {
+				t.Error("expected restoring a live slot to fail")
+			}
+		})
+	}
+}
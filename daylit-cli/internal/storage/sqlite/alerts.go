@@ -28,15 +28,19 @@ func (s *Store) AddAlert(alert models.Alert) error {
 
 	createdAtStr := alert.CreatedAt.Format(time.RFC3339)
 
-	_, err = s.db.Exec(`
+	_, err = s.conn().Exec(`
 		INSERT INTO alerts (
 			id, message, time, date, 
 			recurrence_type, recurrence_interval, recurrence_weekdays,
+			recurrence_month_day, recurrence_month, recurrence_cron,
+			task_id, travel_min, urgency, sound,
 			active, last_sent, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		alert.ID, alert.Message, alert.Time, alert.Date,
 		string(alert.Recurrence.Type), alert.Recurrence.IntervalDays, string(weekdaysJSON),
+		alert.Recurrence.MonthDay, alert.Recurrence.Month, alert.Cron,
+		alert.TaskID, alert.TravelMin, alert.Urgency, alert.Sound,
 		alert.Active, lastSentStr, createdAtStr,
 	)
 
@@ -54,15 +58,19 @@ func (s *Store) GetAlert(id string) (models.Alert, error) {
 	var lastSentStr *string
 	var createdAtStr string
 
-	err := s.db.QueryRow(`
+	err := s.conn().QueryRow(`
 		SELECT id, message, time, date,
 			recurrence_type, recurrence_interval, recurrence_weekdays,
+			recurrence_month_day, recurrence_month, recurrence_cron,
+			task_id, travel_min, urgency, sound,
 			active, last_sent, created_at
 		FROM alerts
 		WHERE id = ?
 	`, id).Scan(
 		&alert.ID, &alert.Message, &alert.Time, &alert.Date,
 		&recurrenceType, &alert.Recurrence.IntervalDays, &weekdaysJSON,
+		&alert.Recurrence.MonthDay, &alert.Recurrence.Month, &alert.Cron,
+		&alert.TaskID, &alert.TravelMin, &alert.Urgency, &alert.Sound,
 		&alert.Active, &lastSentStr, &createdAtStr,
 	)
 
@@ -97,9 +105,11 @@ func (s *Store) GetAlert(id string) (models.Alert, error) {
 }
 
 func (s *Store) GetAllAlerts() ([]models.Alert, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.conn().Query(`
 		SELECT id, message, time, date,
 			recurrence_type, recurrence_interval, recurrence_weekdays,
+			recurrence_month_day, recurrence_month, recurrence_cron,
+			task_id, travel_min, urgency, sound,
 			active, last_sent, created_at
 		FROM alerts
 		ORDER BY time ASC
@@ -120,6 +130,8 @@ func (s *Store) GetAllAlerts() ([]models.Alert, error) {
 		err := rows.Scan(
 			&alert.ID, &alert.Message, &alert.Time, &alert.Date,
 			&recurrenceType, &alert.Recurrence.IntervalDays, &weekdaysJSON,
+			&alert.Recurrence.MonthDay, &alert.Recurrence.Month, &alert.Cron,
+			&alert.TaskID, &alert.TravelMin, &alert.Urgency, &alert.Sound,
 			&alert.Active, &lastSentStr, &createdAtStr,
 		)
 		if err != nil {
@@ -172,15 +184,19 @@ func (s *Store) UpdateAlert(alert models.Alert) error {
 		lastSentStr = &str
 	}
 
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE alerts SET
 			message = ?, time = ?, date = ?,
 			recurrence_type = ?, recurrence_interval = ?, recurrence_weekdays = ?,
+			recurrence_month_day = ?, recurrence_month = ?, recurrence_cron = ?,
+			task_id = ?, travel_min = ?, urgency = ?, sound = ?,
 			active = ?, last_sent = ?
 		WHERE id = ?
 	`,
 		alert.Message, alert.Time, alert.Date,
 		string(alert.Recurrence.Type), alert.Recurrence.IntervalDays, string(weekdaysJSON),
+		alert.Recurrence.MonthDay, alert.Recurrence.Month, alert.Cron,
+		alert.TaskID, alert.TravelMin, alert.Urgency, alert.Sound,
 		alert.Active, lastSentStr, alert.ID,
 	)
 
@@ -201,7 +217,7 @@ func (s *Store) UpdateAlert(alert models.Alert) error {
 }
 
 func (s *Store) DeleteAlert(id string) error {
-	result, err := s.db.Exec(`DELETE FROM alerts WHERE id = ?`, id)
+	result, err := s.conn().Exec(`DELETE FROM alerts WHERE id = ?`, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete alert: %w", err)
 	}
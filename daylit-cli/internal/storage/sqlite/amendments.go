@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) AmendPlan(a models.PlanAmendment) (models.PlanAmendment, error) {
+	if err := a.Validate(); err != nil {
+		return a, err
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return a, err
+	}
+	defer tx.Rollback()
+
+	var acceptedAt, deletedAt sql.NullString
+	err = tx.QueryRow("SELECT accepted_at, deleted_at FROM plans WHERE date = ? AND revision = ?", a.Date, a.Revision).Scan(&acceptedAt, &deletedAt)
+	if err == sql.ErrNoRows {
+		return a, fmt.Errorf("plan not found: %s revision %d", a.Date, a.Revision)
+	}
+	if err != nil {
+		return a, err
+	}
+	if deletedAt.Valid {
+		return a, fmt.Errorf("plan %s revision %d is deleted; restore the plan first", a.Date, a.Revision)
+	}
+	if !acceptedAt.Valid {
+		return a, fmt.Errorf("plan %s revision %d is not accepted; change it directly instead", a.Date, a.Revision)
+	}
+
+	// A task has at most one live slot per start time, so amendments can find it
+	if a.Kind != models.AmendmentRemove && a.ToStart != a.FromStart {
+		var taken int
+		if err := tx.QueryRow(
+			"SELECT COUNT(*) FROM slots WHERE plan_date = ? AND plan_revision = ? AND start_time = ? AND task_id = ? AND deleted_at IS NULL",
+			a.Date, a.Revision, a.ToStart, a.TaskID,
+		).Scan(&taken); err != nil {
+			return a, err
+		}
+		if taken > 0 {
+			return a, fmt.Errorf("plan %s revision %d already has a slot at %s for task %s", a.Date, a.Revision, a.ToStart, a.TaskID)
+		}
+	}
+
+	var slotID int64
+	if a.Kind != models.AmendmentAdd {
+		err := tx.QueryRow(
+			"SELECT id, end_time FROM slots WHERE plan_date = ? AND plan_revision = ? AND start_time = ? AND task_id = ? AND deleted_at IS NULL ORDER BY id LIMIT 1",
+			a.Date, a.Revision, a.FromStart, a.TaskID,
+		).Scan(&slotID, &a.FromEnd)
+		if err == sql.ErrNoRows {
+			return a, fmt.Errorf("no slot at %s for task %s in plan %s revision %d", a.FromStart, a.TaskID, a.Date, a.Revision)
+		}
+		if err != nil {
+			return a, err
+		}
+	}
+
+	switch a.Kind {
+	case models.AmendmentAdd:
+		_, err = tx.Exec(
+			"INSERT INTO slots (plan_date, plan_revision, start_time, end_time, task_id, status, feedback_rating, feedback_note) VALUES (?, ?, ?, ?, ?, ?, '', '')",
+			a.Date, a.Revision, a.ToStart, a.ToEnd, a.TaskID, constants.SlotStatusAccepted,
+		)
+	case models.AmendmentMove:
+		// The slot keeps its feedback but is notified again at its new time,
+		// and its reminders follow it
+		if _, err = tx.Exec(
+			"UPDATE slots SET start_time = ?, end_time = ?, last_notified_start = NULL, last_notified_end = NULL WHERE id = ?",
+			a.ToStart, a.ToEnd, slotID,
+		); err == nil {
+			_, err = tx.Exec(
+				"UPDATE slot_reminders SET slot_start = ?, sent_at = NULL WHERE plan_date = ? AND task_id = ? AND slot_start = ?",
+				a.ToStart, a.Date, a.TaskID, a.FromStart,
+			)
+		}
+	case models.AmendmentRemove:
+		_, err = tx.Exec("UPDATE slots SET deleted_at = ? WHERE id = ?", time.Now().UTC().Format(time.RFC3339), slotID)
+	}
+	if err != nil {
+		return a, fmt.Errorf("failed to amend plan: %w", err)
+	}
+
+	if _, err := tx.Exec("UPDATE plans SET version = version + 1 WHERE date = ? AND revision = ?", a.Date, a.Revision); err != nil {
+		return a, err
+	}
+
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	result, err := tx.Exec(`
+		INSERT INTO plan_amendments (plan_date, plan_revision, kind, task_id, from_start, from_end, to_start, to_end, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.Date, a.Revision, a.Kind, a.TaskID, a.FromStart, a.FromEnd, a.ToStart, a.ToEnd, a.Reason, a.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return a, fmt.Errorf("failed to record amendment: %w", err)
+	}
+	if a.ID, err = result.LastInsertId(); err != nil {
+		return a, err
+	}
+
+	return a, tx.Commit()
+}
+
+func (s *Store) GetPlanAmendments(date string) ([]models.PlanAmendment, error) {
+	rows, err := s.conn().Query(`
+		SELECT id, plan_date, plan_revision, kind, task_id, from_start, from_end, to_start, to_end, reason, created_at
+		FROM plan_amendments WHERE plan_date = ? ORDER BY id`, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var amendments []models.PlanAmendment
+	for rows.Next() {
+		var a models.PlanAmendment
+		var createdAt string
+		if err := rows.Scan(&a.ID, &a.Date, &a.Revision, &a.Kind, &a.TaskID, &a.FromStart, &a.FromEnd, &a.ToStart, &a.ToEnd, &a.Reason, &createdAt); err != nil {
+			return nil, err
+		}
+		a.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		amendments = append(amendments, a)
+	}
+	return amendments, rows.Err()
+}
@@ -12,7 +12,7 @@ import (
 func (s *Store) GetAllPlans() ([]models.DayPlan, error) {
 	// Check if notification columns exist (for backward compatibility with older DBs during migration)
 	var hasNotificationCols bool
-	checkRows, err := s.db.Query("SELECT count(*) FROM pragma_table_info('slots') WHERE name='last_notified_start'")
+	checkRows, err := s.conn().Query("SELECT count(*) FROM pragma_table_info('slots') WHERE name='last_notified_start'")
 	if err == nil {
 		defer checkRows.Close()
 		var count int
@@ -23,10 +23,54 @@ func (s *Store) GetAllPlans() ([]models.DayPlan, error) {
 		}
 	}
 
-	rows, err := s.db.Query(`
-		SELECT date, revision, accepted_at, deleted_at
-		FROM plans
-		ORDER BY date, revision`)
+	var hasActualEndCol bool
+	var actualEndCount int
+	if err := s.conn().QueryRow("SELECT count(*) FROM pragma_table_info('slots') WHERE name='actual_end'").Scan(&actualEndCount); err == nil {
+		hasActualEndCol = actualEndCount > 0
+	}
+
+	var hasActualStartCol bool
+	var actualStartCount int
+	if err := s.conn().QueryRow("SELECT count(*) FROM pragma_table_info('slots') WHERE name='actual_start'").Scan(&actualStartCount); err == nil {
+		hasActualStartCol = actualStartCount > 0
+	}
+
+	var hasNoteCol bool
+	var noteCount int
+	if err := s.conn().QueryRow("SELECT count(*) FROM pragma_table_info('plans') WHERE name='note'").Scan(&noteCount); err == nil {
+		hasNoteCol = noteCount > 0
+	}
+
+	var hasLockCol bool
+	var lockCount int
+	if err := s.conn().QueryRow("SELECT count(*) FROM pragma_table_info('plans') WHERE name='locked_until'").Scan(&lockCount); err == nil {
+		hasLockCol = lockCount > 0
+	}
+
+	// One query for every plan and its slots, including deleted slots for a
+	// complete migration; plans without slots still get a row
+	query := `SELECT p.date, p.revision, p.accepted_at, p.deleted_at`
+	if hasNoteCol {
+		query += `, p.note`
+	}
+	if hasLockCol {
+		query += `, p.locked_until`
+	}
+	query += `, s.id, s.start_time, s.end_time, s.task_id, s.status, s.feedback_rating, s.feedback_note, s.deleted_at`
+	if hasNotificationCols {
+		query += `, s.last_notified_start, s.last_notified_end`
+	}
+	if hasActualStartCol {
+		query += `, s.actual_start`
+	}
+	if hasActualEndCol {
+		query += `, s.actual_end`
+	}
+	query += ` FROM plans p
+		LEFT JOIN slots s ON s.plan_date = p.date AND s.plan_revision = p.revision
+		ORDER BY p.date, p.revision, s.start_time, s.id`
+
+	rows, err := s.conn().Query(query)
 	if err != nil {
 		return nil, err
 	}
@@ -34,72 +78,87 @@ func (s *Store) GetAllPlans() ([]models.DayPlan, error) {
 
 	var plans []models.DayPlan
 	for rows.Next() {
-		var plan models.DayPlan
-		var acceptedAt, deletedAt sql.NullString
-		if err := rows.Scan(&plan.Date, &plan.Revision, &acceptedAt, &deletedAt); err != nil {
-			return nil, err
-		}
+		var date string
+		var revision int
+		var acceptedAt, deletedAt, note, lockedUntil sql.NullString
+		var slotID sql.NullInt64
+		var start, end, taskID, status, rating, feedbackNote sql.NullString
+		var slotDeletedAt, lastNotifiedStart, lastNotifiedEnd, actualStart, actualEnd sql.NullString
 
-		if acceptedAt.Valid {
-			plan.AcceptedAt = &acceptedAt.String
+		dest := []interface{}{&date, &revision, &acceptedAt, &deletedAt}
+		if hasNoteCol {
+			dest = append(dest, &note)
 		}
-		if deletedAt.Valid {
-			plan.DeletedAt = &deletedAt.String
+		if hasLockCol {
+			dest = append(dest, &lockedUntil)
 		}
-
-		// Get slots for this plan (including deleted slots for complete migration)
-		query := `SELECT start_time, end_time, task_id, status, feedback_rating, feedback_note, deleted_at`
+		dest = append(dest, &slotID, &start, &end, &taskID, &status, &rating, &feedbackNote, &slotDeletedAt)
 		if hasNotificationCols {
-			query += `, last_notified_start, last_notified_end`
+			dest = append(dest, &lastNotifiedStart, &lastNotifiedEnd)
 		}
-		query += ` FROM slots WHERE plan_date = ? AND plan_revision = ? ORDER BY start_time`
-
-		slotRows, err := s.db.Query(query, plan.Date, plan.Revision)
-		if err != nil {
+		if hasActualStartCol {
+			dest = append(dest, &actualStart)
+		}
+		if hasActualEndCol {
+			dest = append(dest, &actualEnd)
+		}
+		if err := rows.Scan(dest...); err != nil {
 			return nil, err
 		}
 
-		for slotRows.Next() {
-			var slot models.Slot
-			var rating, note string
-			var slotDeletedAt, lastNotifiedStart, lastNotifiedEnd sql.NullString
-
-			dest := []interface{}{
-				&slot.Start, &slot.End, &slot.TaskID, &slot.Status,
-				&rating, &note, &slotDeletedAt,
+		// Rows come grouped by plan, so a new date or revision starts the next one
+		if n := len(plans); n == 0 || plans[n-1].Date != date || plans[n-1].Revision != revision {
+			note, err := s.open(note.String)
+			if err != nil {
+				return nil, err
 			}
-			if hasNotificationCols {
-				dest = append(dest, &lastNotifiedStart, &lastNotifiedEnd)
+			plan := models.DayPlan{Date: date, Revision: revision, Note: note, LockedUntil: lockedUntil.String}
+			if acceptedAt.Valid {
+				plan.AcceptedAt = &acceptedAt.String
 			}
-
-			if err := slotRows.Scan(dest...); err != nil {
-				slotRows.Close()
-				return nil, err
+			if deletedAt.Valid {
+				plan.DeletedAt = &deletedAt.String
 			}
+			plans = append(plans, plan)
+		}
+		if !slotID.Valid {
+			continue
+		}
 
-			if rating != "" {
-				slot.Feedback = &models.Feedback{
-					Rating: models.FeedbackRating(rating),
-					Note:   note,
-				}
-			}
-			if slotDeletedAt.Valid {
-				slot.DeletedAt = &slotDeletedAt.String
+		slot := models.Slot{
+			Start:  start.String,
+			End:    end.String,
+			TaskID: taskID.String,
+			Status: models.SlotStatus(status.String),
+		}
+		if rating.String != "" {
+			note, err := s.open(feedbackNote.String)
+			if err != nil {
+				return nil, err
 			}
-			if hasNotificationCols {
-				if lastNotifiedStart.Valid {
-					slot.LastNotifiedStart = &lastNotifiedStart.String
-				}
-				if lastNotifiedEnd.Valid {
-					slot.LastNotifiedEnd = &lastNotifiedEnd.String
-				}
+			slot.Feedback = &models.Feedback{
+				Rating: models.FeedbackRating(rating.String),
+				Note:   note,
 			}
-
-			plan.Slots = append(plan.Slots, slot)
 		}
-		slotRows.Close()
+		if slotDeletedAt.Valid {
+			slot.DeletedAt = &slotDeletedAt.String
+		}
+		if lastNotifiedStart.Valid {
+			slot.LastNotifiedStart = &lastNotifiedStart.String
+		}
+		if lastNotifiedEnd.Valid {
+			slot.LastNotifiedEnd = &lastNotifiedEnd.String
+		}
+		if actualStart.Valid {
+			slot.ActualStart = &actualStart.String
+		}
+		if actualEnd.Valid {
+			slot.ActualEnd = &actualEnd.String
+		}
 
-		plans = append(plans, plan)
+		plan := &plans[len(plans)-1]
+		plan.Slots = append(plan.Slots, slot)
 	}
 
 	return plans, rows.Err()
@@ -115,7 +174,7 @@ func (s *Store) GetAllHabitEntries() ([]models.HabitEntry, error) {
 		return []models.HabitEntry{}, nil
 	}
 
-	rows, err := s.db.Query(`
+	rows, err := s.conn().Query(`
 		SELECT id, habit_id, day, note, created_at, updated_at, deleted_at
 		FROM habit_entries
 		ORDER BY day, habit_id`)
@@ -136,6 +195,9 @@ func (s *Store) GetAllHabitEntries() ([]models.HabitEntry, error) {
 		}
 
 		var err error
+		if entry.Note, err = s.open(entry.Note); err != nil {
+			return nil, err
+		}
 		entry.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse created_at for habit entry %s: %w", entry.ID, err)
@@ -168,7 +230,7 @@ func (s *Store) GetAllOTEntries() ([]models.OTEntry, error) {
 		return []models.OTEntry{}, nil
 	}
 
-	rows, err := s.db.Query(`
+	rows, err := s.conn().Query(`
 		SELECT id, day, title, note, created_at, updated_at, deleted_at
 		FROM ot_entries
 		ORDER BY day`)
@@ -189,6 +251,9 @@ func (s *Store) GetAllOTEntries() ([]models.OTEntry, error) {
 		}
 
 		var err error
+		if entry.Note, err = s.open(entry.Note); err != nil {
+			return nil, err
+		}
 		entry.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse created_at for OT entry %s: %w", entry.ID, err)
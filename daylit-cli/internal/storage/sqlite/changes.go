@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// WatchChanges polls PRAGMA data_version, which SQLite bumps whenever
+// another connection commits to the database file. The check runs on a
+// dedicated connection, because the value is tracked per connection.
+func (s *Store) WatchChanges() (<-chan models.Change, func(), error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	var last int64
+	if err := conn.QueryRowContext(ctx, "PRAGMA data_version").Scan(&last); err != nil {
+		conn.Close()
+		cancel()
+		return nil, nil, err
+	}
+
+	changes := make(chan models.Change, 1)
+	go func() {
+		defer conn.Close()
+		ticker := time.NewTicker(constants.ChangePollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				var version int64
+				if err := conn.QueryRowContext(ctx, "PRAGMA data_version").Scan(&version); err != nil {
+					if ctx.Err() == nil {
+						logger.Warn("Failed to check for database changes", "error", err)
+					}
+					continue
+				}
+				if version != last {
+					last = version
+					sendChange(changes, models.Change{})
+				}
+			}
+		}
+	}()
+
+	return changes, cancel, nil
+}
+
+// sendChange delivers c without blocking. A change already waiting to be
+// read covers c as well.
+func sendChange(ch chan models.Change, c models.Change) {
+	select {
+	case ch <- c:
+	default:
+	}
+}
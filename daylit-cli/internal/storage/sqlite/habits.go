@@ -2,9 +2,11 @@ package sqlite
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 )
 
@@ -13,15 +15,18 @@ func (s *Store) AddHabit(habit models.Habit) error {
 }
 
 func (s *Store) GetHabit(id string) (models.Habit, error) {
-	row := s.db.QueryRow(`
-		SELECT id, name, created_at, archived_at, deleted_at
+	row := s.conn().QueryRow(`
+		SELECT id, name, created_at, archived_at, deleted_at,
+			category, paused_from, paused_until,
+			reminder_time, reminder_days, reminder_enabled, last_reminded
 		FROM habits WHERE id = ? AND deleted_at IS NULL`, id)
 
 	var h models.Habit
-	var createdAt string
-	var archivedAt, deletedAt sql.NullString
+	var createdAt, reminderDays string
+	var archivedAt, deletedAt, lastReminded sql.NullString
 
-	err := row.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt)
+	err := row.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt, &h.Category, &h.PausedFrom, &h.PausedUntil,
+		&h.ReminderTime, &reminderDays, &h.ReminderEnabled, &lastReminded)
 	if err != nil {
 		return models.Habit{}, err
 	}
@@ -44,20 +49,26 @@ func (s *Store) GetHabit(id string) (models.Habit, error) {
 		}
 		h.DeletedAt = &t
 	}
+	if err := parseHabitReminder(&h, reminderDays, lastReminded); err != nil {
+		return models.Habit{}, err
+	}
 
 	return h, nil
 }
 
 func (s *Store) GetHabitByName(name string) (models.Habit, error) {
-	row := s.db.QueryRow(`
-		SELECT id, name, created_at, archived_at, deleted_at
+	row := s.conn().QueryRow(`
+		SELECT id, name, created_at, archived_at, deleted_at,
+			category, paused_from, paused_until,
+			reminder_time, reminder_days, reminder_enabled, last_reminded
 		FROM habits WHERE name = ? AND deleted_at IS NULL`, name)
 
 	var h models.Habit
-	var createdAt string
-	var archivedAt, deletedAt sql.NullString
+	var createdAt, reminderDays string
+	var archivedAt, deletedAt, lastReminded sql.NullString
 
-	err := row.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt)
+	err := row.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt, &h.Category, &h.PausedFrom, &h.PausedUntil,
+		&h.ReminderTime, &reminderDays, &h.ReminderEnabled, &lastReminded)
 	if err != nil {
 		return models.Habit{}, err
 	}
@@ -80,6 +91,9 @@ func (s *Store) GetHabitByName(name string) (models.Habit, error) {
 		}
 		h.DeletedAt = &t
 	}
+	if err := parseHabitReminder(&h, reminderDays, lastReminded); err != nil {
+		return models.Habit{}, err
+	}
 
 	return h, nil
 }
@@ -93,7 +107,7 @@ func (s *Store) GetAllHabits(includeArchived, includeDeleted bool) ([]models.Hab
 		return []models.Habit{}, nil
 	}
 
-	query := "SELECT id, name, created_at, archived_at, deleted_at FROM habits WHERE 1=1"
+	query := "SELECT id, name, created_at, archived_at, deleted_at, category, paused_from, paused_until, reminder_time, reminder_days, reminder_enabled, last_reminded FROM habits WHERE 1=1"
 	if !includeDeleted {
 		query += " AND deleted_at IS NULL"
 	}
@@ -102,7 +116,7 @@ func (s *Store) GetAllHabits(includeArchived, includeDeleted bool) ([]models.Hab
 	}
 	query += " ORDER BY created_at"
 
-	rows, err := s.db.Query(query)
+	rows, err := s.conn().Query(query)
 	if err != nil {
 		return nil, err
 	}
@@ -111,10 +125,11 @@ func (s *Store) GetAllHabits(includeArchived, includeDeleted bool) ([]models.Hab
 	var habits []models.Habit
 	for rows.Next() {
 		var h models.Habit
-		var createdAt string
-		var archivedAt, deletedAt sql.NullString
+		var createdAt, reminderDays string
+		var archivedAt, deletedAt, lastReminded sql.NullString
 
-		err := rows.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt)
+		err := rows.Scan(&h.ID, &h.Name, &createdAt, &archivedAt, &deletedAt, &h.Category, &h.PausedFrom, &h.PausedUntil,
+			&h.ReminderTime, &reminderDays, &h.ReminderEnabled, &lastReminded)
 		if err != nil {
 			return nil, err
 		}
@@ -137,6 +152,9 @@ func (s *Store) GetAllHabits(includeArchived, includeDeleted bool) ([]models.Hab
 			}
 			h.DeletedAt = &t
 		}
+		if err := parseHabitReminder(&h, reminderDays, lastReminded); err != nil {
+			return nil, err
+		}
 
 		habits = append(habits, h)
 	}
@@ -152,21 +170,70 @@ func (s *Store) UpdateHabit(habit models.Habit) error {
 	if habit.DeletedAt != nil {
 		deletedAt = sql.NullString{String: habit.DeletedAt.Format(time.RFC3339), Valid: true}
 	}
+	reminderDays, lastReminded, err := formatHabitReminder(habit)
+	if err != nil {
+		return err
+	}
 
-	_, err := s.db.Exec(`
-		INSERT INTO habits (id, name, created_at, archived_at, deleted_at)
-		VALUES (?, ?, ?, ?, ?)
+	_, err = s.conn().Exec(`
+		INSERT INTO habits (id, name, created_at, archived_at, deleted_at, category, paused_from, paused_until,
+			reminder_time, reminder_days, reminder_enabled, last_reminded)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			name = excluded.name,
 			archived_at = excluded.archived_at,
-			deleted_at = excluded.deleted_at`,
-		habit.ID, habit.Name, habit.CreatedAt.Format(time.RFC3339), archivedAt, deletedAt)
+			deleted_at = excluded.deleted_at,
+			category = excluded.category,
+			paused_from = excluded.paused_from,
+			paused_until = excluded.paused_until,
+			reminder_time = excluded.reminder_time,
+			reminder_days = excluded.reminder_days,
+			reminder_enabled = excluded.reminder_enabled,
+			last_reminded = excluded.last_reminded`,
+		habit.ID, habit.Name, habit.CreatedAt.Format(time.RFC3339), archivedAt, deletedAt, habit.Category, habit.PausedFrom, habit.PausedUntil,
+		habit.ReminderTime, reminderDays, habit.ReminderEnabled, lastReminded)
 
 	return err
 }
 
+// parseHabitReminder fills in a habit's reminder from its stored reminder
+// days and last reminded time
+func parseHabitReminder(h *models.Habit, reminderDays string, lastReminded sql.NullString) error {
+	if reminderDays != "" {
+		if err := json.Unmarshal([]byte(reminderDays), &h.ReminderDays); err != nil {
+			return fmt.Errorf("failed to parse reminder_days for habit %s: %w", h.ID, err)
+		}
+	}
+	if lastReminded.Valid {
+		t, err := time.Parse(time.RFC3339, lastReminded.String)
+		if err != nil {
+			return fmt.Errorf("failed to parse last_reminded for habit %s: %w", h.ID, err)
+		}
+		h.LastReminded = &t
+	}
+	return nil
+}
+
+// formatHabitReminder returns a habit's reminder days and last reminded
+// time as stored
+func formatHabitReminder(habit models.Habit) (string, sql.NullString, error) {
+	var reminderDays string
+	if len(habit.ReminderDays) > 0 {
+		data, err := json.Marshal(habit.ReminderDays)
+		if err != nil {
+			return "", sql.NullString{}, fmt.Errorf("failed to marshal reminder days: %w", err)
+		}
+		reminderDays = string(data)
+	}
+	var lastReminded sql.NullString
+	if habit.LastReminded != nil {
+		lastReminded = sql.NullString{String: habit.LastReminded.Format(time.RFC3339), Valid: true}
+	}
+	return reminderDays, lastReminded, nil
+}
+
 func (s *Store) ArchiveHabit(id string) error {
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE habits SET archived_at = ? WHERE id = ? AND deleted_at IS NULL AND archived_at IS NULL`,
 		time.Now().Format(time.RFC3339), id)
 	if err != nil {
@@ -185,7 +252,7 @@ func (s *Store) ArchiveHabit(id string) error {
 }
 
 func (s *Store) UnarchiveHabit(id string) error {
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE habits SET archived_at = NULL WHERE id = ? AND deleted_at IS NULL AND archived_at IS NOT NULL`,
 		id)
 	if err != nil {
@@ -204,9 +271,17 @@ func (s *Store) UnarchiveHabit(id string) error {
 }
 
 func (s *Store) DeleteHabit(id string) error {
-	result, err := s.db.Exec(`
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The entries are marked with the habit's time so restoring it finds them
+	stamp := time.Now().Format(time.RFC3339)
+	result, err := tx.Exec(`
 		UPDATE habits SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`,
-		time.Now().Format(time.RFC3339), id)
+		stamp, id)
 	if err != nil {
 		return err
 	}
@@ -219,26 +294,48 @@ func (s *Store) DeleteHabit(id string) error {
 		return fmt.Errorf("habit not found or already deleted")
 	}
 
-	return nil
+	settings, err := storedSettings(tx)
+	if err != nil {
+		return err
+	}
+	if settings.HabitDeleteEntries == constants.HabitDeleteEntriesDelete {
+		if _, err := tx.Exec(`
+			UPDATE habit_entries SET deleted_at = ? WHERE habit_id = ? AND deleted_at IS NULL`,
+			stamp, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (s *Store) RestoreHabit(id string) error {
-	result, err := s.db.Exec(`
-		UPDATE habits SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`,
-		id)
+	tx, err := s.begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rows, err := result.RowsAffected()
-	if err != nil {
+	var deletedAt sql.NullString
+	err = tx.QueryRow("SELECT deleted_at FROM habits WHERE id = ?", id).Scan(&deletedAt)
+	if err != nil && err != sql.ErrNoRows {
 		return err
 	}
-	if rows == 0 {
+	if !deletedAt.Valid {
 		return fmt.Errorf("habit not found or not deleted")
 	}
 
-	return nil
+	if _, err := tx.Exec("UPDATE habits SET deleted_at = NULL WHERE id = ?", id); err != nil {
+		return err
+	}
+	// Bring back the entries deleted with the habit
+	if _, err := tx.Exec(`
+		UPDATE habit_entries SET deleted_at = NULL WHERE habit_id = ? AND deleted_at = ?`,
+		id, deletedAt.String); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // Habit Entries
@@ -248,7 +345,7 @@ func (s *Store) AddHabitEntry(entry models.HabitEntry) error {
 }
 
 func (s *Store) GetHabitEntry(habitID, day string) (models.HabitEntry, error) {
-	row := s.db.QueryRow(`
+	row := s.conn().QueryRow(`
 		SELECT id, habit_id, day, note, created_at, updated_at, deleted_at
 		FROM habit_entries WHERE habit_id = ? AND day = ? AND deleted_at IS NULL`,
 		habitID, day)
@@ -261,6 +358,9 @@ func (s *Store) GetHabitEntry(habitID, day string) (models.HabitEntry, error) {
 	if err != nil {
 		return models.HabitEntry{}, err
 	}
+	if e.Note, err = s.open(e.Note); err != nil {
+		return models.HabitEntry{}, err
+	}
 
 	e.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
 	if err != nil {
@@ -282,7 +382,7 @@ func (s *Store) GetHabitEntry(habitID, day string) (models.HabitEntry, error) {
 }
 
 func (s *Store) GetHabitEntriesForDay(day string) ([]models.HabitEntry, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.conn().Query(`
 		SELECT id, habit_id, day, note, created_at, updated_at, deleted_at
 		FROM habit_entries WHERE day = ? AND deleted_at IS NULL
 		ORDER BY created_at`, day)
@@ -301,6 +401,9 @@ func (s *Store) GetHabitEntriesForDay(day string) ([]models.HabitEntry, error) {
 		if err != nil {
 			return nil, err
 		}
+		if e.Note, err = s.open(e.Note); err != nil {
+			return nil, err
+		}
 
 		e.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
 		if err != nil {
@@ -325,7 +428,7 @@ func (s *Store) GetHabitEntriesForDay(day string) ([]models.HabitEntry, error) {
 }
 
 func (s *Store) GetHabitEntriesForHabit(habitID string, startDay, endDay string) ([]models.HabitEntry, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.conn().Query(`
 		SELECT id, habit_id, day, note, created_at, updated_at, deleted_at
 		FROM habit_entries
 		WHERE habit_id = ? AND day >= ? AND day <= ? AND deleted_at IS NULL
@@ -345,6 +448,9 @@ func (s *Store) GetHabitEntriesForHabit(habitID string, startDay, endDay string)
 		if err != nil {
 			return nil, err
 		}
+		if e.Note, err = s.open(e.Note); err != nil {
+			return nil, err
+		}
 
 		e.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
 		if err != nil {
@@ -374,21 +480,21 @@ func (s *Store) UpdateHabitEntry(entry models.HabitEntry) error {
 		deletedAt = sql.NullString{String: entry.DeletedAt.Format(time.RFC3339), Valid: true}
 	}
 
-	_, err := s.db.Exec(`
+	_, err := s.conn().Exec(`
 		INSERT INTO habit_entries (id, habit_id, day, note, created_at, updated_at, deleted_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(habit_id, day) DO UPDATE SET
 			note = excluded.note,
 			updated_at = excluded.updated_at,
 			deleted_at = excluded.deleted_at`,
-		entry.ID, entry.HabitID, entry.Day, entry.Note,
+		entry.ID, entry.HabitID, entry.Day, s.seal(entry.Note),
 		entry.CreatedAt.Format(time.RFC3339), entry.UpdatedAt.Format(time.RFC3339), deletedAt)
 
 	return err
 }
 
 func (s *Store) DeleteHabitEntry(id string) error {
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE habit_entries SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`,
 		time.Now().Format(time.RFC3339), id)
 	if err != nil {
@@ -407,7 +513,7 @@ func (s *Store) DeleteHabitEntry(id string) error {
 }
 
 func (s *Store) RestoreHabitEntry(id string) error {
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE habit_entries SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`,
 		id)
 	if err != nil {
@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) SaveDailyMetrics(m models.DailyMetrics) error {
+	_, err := s.conn().Exec(`
+		INSERT INTO metrics (date, has_plan, accepted, planned_slots, done_slots, skipped_slots, tracked_slots, drift_minutes, on_track, too_much, unnecessary, computed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(date) DO UPDATE SET
+			has_plan = excluded.has_plan,
+			accepted = excluded.accepted,
+			planned_slots = excluded.planned_slots,
+			done_slots = excluded.done_slots,
+			skipped_slots = excluded.skipped_slots,
+			tracked_slots = excluded.tracked_slots,
+			drift_minutes = excluded.drift_minutes,
+			on_track = excluded.on_track,
+			too_much = excluded.too_much,
+			unnecessary = excluded.unnecessary,
+			computed_at = excluded.computed_at`,
+		m.Date, m.HasPlan, m.Accepted, m.PlannedSlots, m.DoneSlots, m.SkippedSlots, m.TrackedSlots, m.DriftMinutes,
+		m.OnTrack, m.TooMuch, m.Unnecessary, m.ComputedAt.Format(time.RFC3339))
+	return err
+}
+
+func (s *Store) GetDailyMetrics(startDay, endDay string) ([]models.DailyMetrics, error) {
+	rows, err := s.conn().Query(`
+		SELECT date, has_plan, accepted, planned_slots, done_slots, skipped_slots, tracked_slots, drift_minutes, on_track, too_much, unnecessary, computed_at
+		FROM metrics
+		WHERE date >= ? AND date <= ?
+		ORDER BY date`, startDay, endDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var metrics []models.DailyMetrics
+	for rows.Next() {
+		var m models.DailyMetrics
+		var computedAt string
+		if err := rows.Scan(&m.Date, &m.HasPlan, &m.Accepted, &m.PlannedSlots, &m.DoneSlots, &m.SkippedSlots, &m.TrackedSlots,
+			&m.DriftMinutes, &m.OnTrack, &m.TooMuch, &m.Unnecessary, &computedAt); err != nil {
+			return nil, err
+		}
+		m.ComputedAt, err = time.Parse(time.RFC3339, computedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse computed_at: %w", err)
+		}
+		metrics = append(metrics, m)
+	}
+
+	return metrics, rows.Err()
+}
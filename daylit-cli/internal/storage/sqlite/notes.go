@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/keyring"
+	"github.com/julianstephens/daylit/daylit-cli/internal/notecrypt"
+)
+
+// noteCheck is sealed with the key and kept with the database, so a wrong
+// key is caught before it garbles any note
+const noteCheck = "daylit"
+
+// noteColumns are the free-text columns encrypted when notes are
+var noteColumns = []struct{ table, column string }{
+	{"slots", "feedback_note"},
+	{"plans", "note"},
+	{"ot_entries", "note"},
+	{"habit_entries", "note"},
+}
+
+// loadNotesKey gets the key of a database with encrypted notes from the
+// keyring, failing when it's missing or doesn't match the database
+func (s *Store) loadNotesKey() error {
+	var keyID, check string
+	err := s.conn().QueryRow("SELECT value FROM settings WHERE key = ?", constants.SettingNotesKeyID).Scan(&keyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check for encrypted notes: %w", err)
+	}
+	if err := s.conn().QueryRow("SELECT value FROM settings WHERE key = ?", constants.SettingNotesKeyCheck).Scan(&check); err != nil {
+		return fmt.Errorf("failed to check for encrypted notes: %w", err)
+	}
+
+	key, err := keyring.GetNotesKey(keyID)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("notes in %s are encrypted, but their key is not in this machine's OS keyring", s.path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get the key of the encrypted notes: %w", err)
+	}
+	c, err := notecrypt.New(key)
+	if err != nil {
+		return err
+	}
+	if plain, err := c.Open(check); err != nil || plain != noteCheck {
+		return fmt.Errorf("the key in the OS keyring doesn't match the encrypted notes in %s", s.path)
+	}
+	s.notes = c
+	return nil
+}
+
+// seal encrypts a note before it's written, when notes are encrypted
+func (s *Store) seal(note string) string {
+	if s.notes == nil {
+		return note
+	}
+	return s.notes.Seal(note)
+}
+
+// open decrypts a note read from the database. Notes written before
+// encryption was turned on are returned as they are.
+func (s *Store) open(note string) (string, error) {
+	if !notecrypt.IsSealed(note) {
+		return note, nil
+	}
+	if s.notes == nil {
+		return "", fmt.Errorf("note is encrypted, but notes in %s are not", s.path)
+	}
+	return s.notes.Open(note)
+}
+
+func (s *Store) NotesEncrypted() bool {
+	return s.notes != nil
+}
+
+// EncryptNotes turns on note encryption with a new key, which is stored in
+// the keyring before any note is encrypted with it
+func (s *Store) EncryptNotes() error {
+	if s.notes != nil {
+		return fmt.Errorf("notes are already encrypted")
+	}
+	keyID := uuid.New().String()
+	key := notecrypt.GenerateKey()
+	c, err := notecrypt.New(key)
+	if err != nil {
+		return err
+	}
+	if err := keyring.SetNotesKey(keyID, key); err != nil {
+		return err
+	}
+
+	seal := func(note string) (string, error) { return c.Seal(note), nil }
+	err = s.rewriteNotes(seal, map[string]string{
+		constants.SettingNotesKeyID:    keyID,
+		constants.SettingNotesKeyCheck: c.Seal(noteCheck),
+	})
+	if err != nil {
+		// Nothing was encrypted with the key
+		_ = keyring.DeleteNotesKey(keyID)
+		return err
+	}
+	s.notes = c
+	return nil
+}
+
+// DecryptNotes turns note encryption off, deleting the key once every note
+// is decrypted
+func (s *Store) DecryptNotes() error {
+	if s.notes == nil {
+		return fmt.Errorf("notes are not encrypted")
+	}
+	var keyID string
+	if err := s.conn().QueryRow("SELECT value FROM settings WHERE key = ?", constants.SettingNotesKeyID).Scan(&keyID); err != nil {
+		return fmt.Errorf("failed to get the key of the encrypted notes: %w", err)
+	}
+
+	if err := s.rewriteNotes(s.notes.Open, nil); err != nil {
+		return err
+	}
+	s.notes = nil
+	if err := keyring.DeleteNotesKey(keyID); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("notes were decrypted, but their key is still in the keyring: %w", err)
+	}
+	return nil
+}
+
+// rewriteNotes rewrites every note with rewrite in one transaction and sets
+// the note encryption markers to markers, removing them when nil
+func (s *Store) rewriteNotes(rewrite func(string) (string, error), markers map[string]string) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, nc := range noteColumns {
+		rows, err := tx.Query(fmt.Sprintf("SELECT rowid, %s FROM %s WHERE COALESCE(%s, '') != ''", nc.column, nc.table, nc.column))
+		if err != nil {
+			return fmt.Errorf("failed to read %s.%s: %w", nc.table, nc.column, err)
+		}
+		notes := make(map[int64]string)
+		for rows.Next() {
+			var id int64
+			var note string
+			if err := rows.Scan(&id, &note); err != nil {
+				rows.Close()
+				return err
+			}
+			notes[id] = note
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		update := fmt.Sprintf("UPDATE %s SET %s = ? WHERE rowid = ?", nc.table, nc.column)
+		for id, note := range notes {
+			note, err := rewrite(note)
+			if err != nil {
+				return fmt.Errorf("failed to rewrite %s.%s: %w", nc.table, nc.column, err)
+			}
+			if _, err := tx.Exec(update, note, id); err != nil {
+				return fmt.Errorf("failed to rewrite %s.%s: %w", nc.table, nc.column, err)
+			}
+		}
+	}
+
+	if markers == nil {
+		if _, err := tx.Exec("DELETE FROM settings WHERE key IN (?, ?)", constants.SettingNotesKeyID, constants.SettingNotesKeyCheck); err != nil {
+			return err
+		}
+	}
+	for key, value := range markers {
+		if _, err := tx.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES (?, ?)", key, value); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
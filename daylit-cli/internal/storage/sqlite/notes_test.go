@@ -0,0 +1,120 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gokeyring "github.com/zalando/go-keyring"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestEncryptNotes(t *testing.T) {
+	gokeyring.MockInit()
+
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	store := NewStore(dbPath)
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+
+	task := models.Task{
+		ID: "task-1", Name: "Therapy", Kind: constants.TaskKindFlexible, DurationMin: 60,
+		Recurrence: models.Recurrence{Type: constants.RecurrenceAdHoc}, Priority: 3, Active: true,
+	}
+	if err := store.AddTask(task); err != nil {
+		t.Fatal(err)
+	}
+	plan := models.DayPlan{Date: "2026-11-02", Note: "rough morning", Slots: []models.Slot{{
+		Start: "09:00", End: "10:00", TaskID: task.ID, Status: constants.SlotStatusDone,
+		Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack, Note: "talked about work"},
+	}}}
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	ot := models.OTEntry{ID: "ot-1", Day: "2026-11-02", Title: "Rest", Note: "tired", CreatedAt: now, UpdatedAt: now}
+	if err := store.AddOTEntry(ot); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.EncryptNotes(); err != nil {
+		t.Fatalf("EncryptNotes() failed: %v", err)
+	}
+	if !store.NotesEncrypted() {
+		t.Error("NotesEncrypted() = false after EncryptNotes()")
+	}
+
+	// The file only holds ciphertext, new notes included
+	ot.Note = "slept badly"
+	if err := store.UpdateOTEntry(ot); err != nil {
+		t.Fatal(err)
+	}
+	for _, query := range []string{
+		"SELECT feedback_note FROM slots",
+		"SELECT note FROM plans",
+		"SELECT note FROM ot_entries",
+	} {
+		var raw string
+		if err := store.db.QueryRow(query).Scan(&raw); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(raw, "enc:v1:") {
+			t.Errorf("%s = %q, want ciphertext", query, raw)
+		}
+	}
+
+	// Another process finds the key in the keyring
+	other := NewStore(dbPath)
+	if err := other.Load(); err != nil {
+		t.Fatalf("Load() of encrypted database failed: %v", err)
+	}
+	defer other.Close()
+	got, err := other.GetPlan(plan.Date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Note != "rough morning" || got.Slots[0].Feedback.Note != "talked about work" {
+		t.Errorf("plan notes = %q, %q", got.Note, got.Slots[0].Feedback.Note)
+	}
+	if entry, err := other.GetOTEntry(ot.Day); err != nil || entry.Note != "slept badly" {
+		t.Errorf("GetOTEntry() = %q, %v", entry.Note, err)
+	}
+	if results, err := other.Search("work", 10); err != nil || len(results) != 0 {
+		t.Errorf("Search() should not match encrypted notes, got %v, %v", results, err)
+	}
+
+	if err := store.DecryptNotes(); err != nil {
+		t.Fatalf("DecryptNotes() failed: %v", err)
+	}
+	var raw string
+	if err := store.db.QueryRow("SELECT note FROM ot_entries").Scan(&raw); err != nil || raw != "slept badly" {
+		t.Errorf("note after DecryptNotes() = %q, %v", raw, err)
+	}
+}
+
+func TestLoadWithoutNotesKey(t *testing.T) {
+	gokeyring.MockInit()
+
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	store := NewStore(dbPath)
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+	if err := store.EncryptNotes(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The key stays behind on the machine the database came from
+	gokeyring.MockInit()
+	other := NewStore(dbPath)
+	defer other.Close()
+	if err := other.Load(); err == nil || !strings.Contains(err.Error(), "keyring") {
+		t.Errorf("Load() error = %v, want a missing key error", err)
+	}
+}
@@ -18,7 +18,7 @@ func (s *Store) GetOTSettings() (models.OTSettings, error) {
 		DefaultLogDays: 14,
 	}
 
-	rows, err := s.db.Query("SELECT key, value FROM settings WHERE key LIKE 'ot_%'")
+	rows, err := s.conn().Query("SELECT key, value FROM settings WHERE key LIKE 'ot_%'")
 	if err != nil {
 		return models.OTSettings{}, err
 	}
@@ -45,7 +45,7 @@ func (s *Store) GetOTSettings() (models.OTSettings, error) {
 }
 
 func (s *Store) SaveOTSettings(settings models.OTSettings) error {
-	tx, err := s.db.Begin()
+	tx, err := s.begin()
 	if err != nil {
 		return err
 	}
@@ -77,7 +77,7 @@ func (s *Store) AddOTEntry(entry models.OTEntry) error {
 }
 
 func (s *Store) GetOTEntry(day string) (models.OTEntry, error) {
-	row := s.db.QueryRow(`
+	row := s.conn().QueryRow(`
 		SELECT id, day, title, note, created_at, updated_at, deleted_at
 		FROM ot_entries WHERE day = ? AND deleted_at IS NULL`, day)
 
@@ -89,6 +89,9 @@ func (s *Store) GetOTEntry(day string) (models.OTEntry, error) {
 	if err != nil {
 		return models.OTEntry{}, err
 	}
+	if e.Note, err = s.open(e.Note); err != nil {
+		return models.OTEntry{}, err
+	}
 
 	e.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
 	if err != nil {
@@ -118,7 +121,7 @@ func (s *Store) GetOTEntries(startDay, endDay string, includeDeleted bool) ([]mo
 	}
 	query += " ORDER BY day DESC"
 
-	rows, err := s.db.Query(query, startDay, endDay)
+	rows, err := s.conn().Query(query, startDay, endDay)
 	if err != nil {
 		return nil, err
 	}
@@ -134,6 +137,9 @@ func (s *Store) GetOTEntries(startDay, endDay string, includeDeleted bool) ([]mo
 		if err != nil {
 			return nil, err
 		}
+		if e.Note, err = s.open(e.Note); err != nil {
+			return nil, err
+		}
 
 		e.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
 		if err != nil {
@@ -163,7 +169,7 @@ func (s *Store) UpdateOTEntry(entry models.OTEntry) error {
 		deletedAt = sql.NullString{String: entry.DeletedAt.Format(time.RFC3339), Valid: true}
 	}
 
-	_, err := s.db.Exec(`
+	_, err := s.conn().Exec(`
 		INSERT INTO ot_entries (id, day, title, note, created_at, updated_at, deleted_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(day) DO UPDATE SET
@@ -171,14 +177,14 @@ func (s *Store) UpdateOTEntry(entry models.OTEntry) error {
 			note = excluded.note,
 			updated_at = excluded.updated_at,
 			deleted_at = excluded.deleted_at`,
-		entry.ID, entry.Day, entry.Title, entry.Note,
+		entry.ID, entry.Day, entry.Title, s.seal(entry.Note),
 		entry.CreatedAt.Format(time.RFC3339), entry.UpdatedAt.Format(time.RFC3339), deletedAt)
 
 	return err
 }
 
 func (s *Store) DeleteOTEntry(day string) error {
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE ot_entries SET deleted_at = ? WHERE day = ? AND deleted_at IS NULL`,
 		time.Now().Format(time.RFC3339), day)
 	if err != nil {
@@ -197,7 +203,7 @@ func (s *Store) DeleteOTEntry(day string) error {
 }
 
 func (s *Store) RestoreOTEntry(day string) error {
-	result, err := s.db.Exec(`
+	result, err := s.conn().Exec(`
 		UPDATE ot_entries SET deleted_at = NULL WHERE day = ? AND deleted_at IS NOT NULL`,
 		day)
 	if err != nil {
@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/notecrypt"
+)
+
+// Search queries the FTS5 search index maintained by triggers on the source tables
+func (s *Store) Search(query string, limit int) ([]models.SearchResult, error) {
+	terms := models.SearchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	// Quote each term and match it as a prefix so partial words still match
+	match := make([]string, len(terms))
+	for i, term := range terms {
+		match[i] = `"` + term + `"*`
+	}
+
+	if limit <= 0 {
+		limit = -1
+	}
+	sealed := notecrypt.Prefix + "%"
+
+	// Encrypted notes are indexed as ciphertext, which is neither shown nor
+	// matched; an OT entry can still be found by its title
+	rows, err := s.conn().Query(`
+		SELECT m.kind, m.ref_id, m.day, COALESCE(t.name, m.title), m.snip
+		FROM (
+			SELECT kind, ref_id, day, title,
+				CASE WHEN body LIKE ? THEN '' ELSE snippet(search_index, 4, '', '', '…', 12) END AS snip, rank
+			FROM search_index
+			WHERE search_index MATCH ? AND NOT (kind = 'note' AND body LIKE ?)
+		) m
+		LEFT JOIN slots s ON m.kind = 'note' AND s.id = CAST(m.ref_id AS INTEGER)
+		LEFT JOIN tasks t ON t.id = s.task_id
+		WHERE m.kind != 'note' OR s.plan_revision = (
+			SELECT MAX(p.revision) FROM plans p WHERE p.date = s.plan_date AND p.deleted_at IS NULL
+		)
+		ORDER BY m.rank, m.day DESC
+		LIMIT ?`,
+		sealed, strings.Join(match, " "), sealed, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query search index: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	for rows.Next() {
+		var r models.SearchResult
+		if err := rows.Scan(&r.Kind, &r.ID, &r.Date, &r.Title, &r.Snippet); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search results: %w", err)
+	}
+
+	return results, nil
+}
@@ -3,7 +3,6 @@ package sqlite
 import (
 	"database/sql"
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -12,12 +11,18 @@ import (
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
 	"github.com/julianstephens/daylit/daylit-cli/internal/migration"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/notecrypt"
 	"github.com/julianstephens/daylit/daylit-cli/migrations"
 )
 
 type Store struct {
-	path string
-	db   *sql.DB
+	path  string
+	db    *sql.DB
+	notes *notecrypt.Cipher // Encrypts notes; nil when they aren't
+	// tx is the transaction of a store handed out by WithTx, and savepoints
+	// counts the savepoints begun in it
+	tx         *sql.Tx
+	savepoints int
 }
 
 func NewStore(path string) *Store {
@@ -34,7 +39,7 @@ func (s *Store) Init() error {
 	}
 
 	// Open database
-	db, err := sql.Open("sqlite", s.path)
+	db, err := openDB(s.path)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -59,13 +64,15 @@ func (s *Store) Init() error {
 			BlockEndOffsetMin:          constants.DefaultBlockEndOffsetMin,
 			NotificationGracePeriodMin: constants.DefaultNotificationGracePeriodMin,
 			Timezone:                   constants.DefaultTimezone,
+			Theme:                      constants.DefaultTheme,
+			MorningPlan:                constants.DefaultMorningPlan,
 		}
 		if err := s.SaveSettings(defaultSettings); err != nil {
 			return fmt.Errorf("failed to save default settings: %w", err)
 		}
 	}
 
-	return nil
+	return s.loadNotesKey()
 }
 
 func (s *Store) Load() error {
@@ -74,10 +81,10 @@ func (s *Store) Load() error {
 	}
 
 	if _, err := os.Stat(s.path); os.IsNotExist(err) {
-		return fmt.Errorf("storage not initialized, run 'daylit init' first")
+		return fmt.Errorf("storage not initialized, run 'daylit setup' (or 'daylit init') first")
 	}
 
-	db, err := sql.Open("sqlite", s.path)
+	db, err := openDB(s.path)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -88,7 +95,15 @@ func (s *Store) Load() error {
 		return err
 	}
 
-	return nil
+	return s.loadNotesKey()
+}
+
+// openDB opens the database at path. SQLite leaves foreign keys unchecked
+// unless each connection turns them on, so the pool's connections all do.
+// A busy timeout makes a write wait for another one, such as a notify run
+// from cron racing the daemon, instead of failing at once.
+func openDB(path string) (*sql.DB, error) {
+	return sql.Open("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
 }
 
 func (s *Store) Close() error {
@@ -112,9 +127,9 @@ func (s *Store) tableExists(tableName string) (bool, error) {
 
 func (s *Store) runMigrations() error {
 	// Get the embedded SQLite migrations sub-filesystem
-	subFS, err := fs.Sub(migrations.FS, "sqlite")
+	subFS, err := migrations.Backend("sqlite")
 	if err != nil {
-		return fmt.Errorf("failed to access sqlite migrations: %w", err)
+		return err
 	}
 
 	// Create migration runner
@@ -128,9 +143,9 @@ func (s *Store) runMigrations() error {
 }
 
 func (s *Store) validateSchemaVersion() error {
-	subFS, err := fs.Sub(migrations.FS, "sqlite")
+	subFS, err := migrations.Backend("sqlite")
 	if err != nil {
-		return fmt.Errorf("failed to access sqlite migrations: %w", err)
+		return err
 	}
 
 	runner := migration.NewRunner(s.db, subFS)
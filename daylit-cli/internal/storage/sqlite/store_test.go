@@ -4,6 +4,10 @@ import (
 	"database/sql"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 )
 
 // setupMinimalTestStore creates a SQLite store without running migrations
@@ -188,3 +192,44 @@ func TestTableExists(t *testing.T) {
 		}
 	})
 }
+
+func TestWatchChanges(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	store := NewStore(dbPath)
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+
+	changes, stop, err := store.WatchChanges()
+	if err != nil {
+		t.Fatalf("WatchChanges() returned unexpected error: %v", err)
+	}
+	defer stop()
+
+	// A second store stands in for another process writing to the same file
+	other := NewStore(dbPath)
+	if err := other.Load(); err != nil {
+		t.Fatalf("failed to open second store: %v", err)
+	}
+	defer other.Close()
+
+	task := models.Task{
+		ID:          "task-watch",
+		Name:        "Watched",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+		Priority:    1,
+		Active:      true,
+	}
+	if err := other.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	select {
+	case <-changes:
+	case <-time.After(3 * constants.ChangePollInterval):
+		t.Fatal("expected a change after another store wrote to the database")
+	}
+}
@@ -15,23 +15,25 @@ func (s *Store) AddTask(task models.Task) error {
 }
 
 func (s *Store) GetTask(id string) (models.Task, error) {
-	row := s.db.QueryRow(`
+	row := s.conn().QueryRow(`
 		SELECT id, name, kind, duration_min, earliest_start, latest_end, fixed_start, fixed_end,
 		       recurrence_type, recurrence_interval, recurrence_weekdays, recurrence_month_day,
-		       recurrence_week_occurrence, recurrence_month, recurrence_day_of_week,
-		       priority, energy_band, active, last_done, success_streak, avg_actual_duration, deleted_at
+		       recurrence_week_occurrence, recurrence_month, recurrence_day_of_week, recurrence_start, recurrence_until, recurrence_count,
+		       priority, energy_band, active, last_done, success_streak, avg_actual_duration, project_id, context,
+		       notify_start_disabled, notify_offset_min, notify_message, notify_urgency, notify_sound, pool_id, nice_to_have, leisure, prep_min, travel_min, version, deleted_at
 		FROM tasks WHERE id = ? AND deleted_at IS NULL`, id)
 
 	var t models.Task
 	var recType, recWeekdays, energyBand string
 	var active bool
 	var deletedAt sql.NullString
-	var recMonthDay, recWeekOccurrence, recMonth, recDayOfWeek sql.NullInt64
+	var recMonthDay, recWeekOccurrence, recMonth, recDayOfWeek, notifyOffset sql.NullInt64
 
 	err := row.Scan(
 		&t.ID, &t.Name, &t.Kind, &t.DurationMin, &t.EarliestStart, &t.LatestEnd, &t.FixedStart, &t.FixedEnd,
-		&recType, &t.Recurrence.IntervalDays, &recWeekdays, &recMonthDay, &recWeekOccurrence, &recMonth, &recDayOfWeek,
-		&t.Priority, &energyBand, &active, &t.LastDone, &t.SuccessStreak, &t.AvgActualDurationMin, &deletedAt,
+		&recType, &t.Recurrence.IntervalDays, &recWeekdays, &recMonthDay, &recWeekOccurrence, &recMonth, &recDayOfWeek, &t.Recurrence.Start, &t.Recurrence.Until, &t.Recurrence.Count,
+		&t.Priority, &energyBand, &active, &t.LastDone, &t.SuccessStreak, &t.AvgActualDurationMin, &t.ProjectID, &t.Context,
+		&t.NotifyStartDisabled, &notifyOffset, &t.NotifyMessage, &t.NotifyUrgency, &t.NotifySound, &t.PoolID, &t.NiceToHave, &t.Leisure, &t.PrepMin, &t.TravelMin, &t.Version, &deletedAt,
 	)
 	if err != nil {
 		return models.Task{}, err
@@ -54,6 +56,11 @@ func (s *Store) GetTask(id string) (models.Task, error) {
 		t.Recurrence.DayOfWeekInMonth = time.Weekday(recDayOfWeek.Int64)
 	}
 
+	if notifyOffset.Valid {
+		offset := int(notifyOffset.Int64)
+		t.NotifyOffsetMin = &offset
+	}
+
 	if deletedAt.Valid {
 		t.DeletedAt = &deletedAt.String
 	}
@@ -71,11 +78,12 @@ func (s *Store) GetTask(id string) (models.Task, error) {
 }
 
 func (s *Store) GetAllTasks() ([]models.Task, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.conn().Query(`
 		SELECT id, name, kind, duration_min, earliest_start, latest_end, fixed_start, fixed_end,
 		       recurrence_type, recurrence_interval, recurrence_weekdays, recurrence_month_day,
-		       recurrence_week_occurrence, recurrence_month, recurrence_day_of_week,
-		       priority, energy_band, active, last_done, success_streak, avg_actual_duration, deleted_at
+		       recurrence_week_occurrence, recurrence_month, recurrence_day_of_week, recurrence_start, recurrence_until, recurrence_count,
+		       priority, energy_band, active, last_done, success_streak, avg_actual_duration, project_id, context,
+		       notify_start_disabled, notify_offset_min, notify_message, notify_urgency, notify_sound, pool_id, nice_to_have, leisure, prep_min, travel_min, version, deleted_at
 		FROM tasks WHERE deleted_at IS NULL`)
 	if err != nil {
 		return nil, err
@@ -88,12 +96,13 @@ func (s *Store) GetAllTasks() ([]models.Task, error) {
 		var recType, recWeekdays, energyBand string
 		var active bool
 		var deletedAt sql.NullString
-		var recMonthDay, recWeekOccurrence, recMonth, recDayOfWeek sql.NullInt64
+		var recMonthDay, recWeekOccurrence, recMonth, recDayOfWeek, notifyOffset sql.NullInt64
 
 		err := rows.Scan(
 			&t.ID, &t.Name, &t.Kind, &t.DurationMin, &t.EarliestStart, &t.LatestEnd, &t.FixedStart, &t.FixedEnd,
-			&recType, &t.Recurrence.IntervalDays, &recWeekdays, &recMonthDay, &recWeekOccurrence, &recMonth, &recDayOfWeek,
-			&t.Priority, &energyBand, &active, &t.LastDone, &t.SuccessStreak, &t.AvgActualDurationMin, &deletedAt,
+			&recType, &t.Recurrence.IntervalDays, &recWeekdays, &recMonthDay, &recWeekOccurrence, &recMonth, &recDayOfWeek, &t.Recurrence.Start, &t.Recurrence.Until, &t.Recurrence.Count,
+			&t.Priority, &energyBand, &active, &t.LastDone, &t.SuccessStreak, &t.AvgActualDurationMin, &t.ProjectID, &t.Context,
+			&t.NotifyStartDisabled, &notifyOffset, &t.NotifyMessage, &t.NotifyUrgency, &t.NotifySound, &t.PoolID, &t.NiceToHave, &t.Leisure, &t.PrepMin, &t.TravelMin, &t.Version, &deletedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -116,6 +125,10 @@ func (s *Store) GetAllTasks() ([]models.Task, error) {
 			t.Recurrence.DayOfWeekInMonth = time.Weekday(recDayOfWeek.Int64)
 		}
 
+		if notifyOffset.Valid {
+			offset := int(notifyOffset.Int64)
+			t.NotifyOffsetMin = &offset
+		}
 		if deletedAt.Valid {
 			t.DeletedAt = &deletedAt.String
 		}
@@ -135,11 +148,12 @@ func (s *Store) GetAllTasks() ([]models.Task, error) {
 }
 
 func (s *Store) GetAllTasksIncludingDeleted() ([]models.Task, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.conn().Query(`
 		SELECT id, name, kind, duration_min, earliest_start, latest_end, fixed_start, fixed_end,
 		       recurrence_type, recurrence_interval, recurrence_weekdays, recurrence_month_day,
-		       recurrence_week_occurrence, recurrence_month, recurrence_day_of_week,
-		       priority, energy_band, active, last_done, success_streak, avg_actual_duration, deleted_at
+		       recurrence_week_occurrence, recurrence_month, recurrence_day_of_week, recurrence_start, recurrence_until, recurrence_count,
+		       priority, energy_band, active, last_done, success_streak, avg_actual_duration, project_id, context,
+		       notify_start_disabled, notify_offset_min, notify_message, notify_urgency, notify_sound, pool_id, nice_to_have, leisure, prep_min, travel_min, version, deleted_at
 		FROM tasks`)
 	if err != nil {
 		return nil, err
@@ -150,17 +164,18 @@ func (s *Store) GetAllTasksIncludingDeleted() ([]models.Task, error) {
 	for rows.Next() {
 		var t models.Task
 		var recType, recWeekdays, energyBand sql.NullString
-		var earliestStart, latestEnd, fixedStart, fixedEnd, lastDone sql.NullString
+		var earliestStart, latestEnd, fixedStart, fixedEnd, lastDone, projectID, taskContext sql.NullString
 		var durationMin, recurrenceInterval, priority, successStreak sql.NullInt64
-		var recMonthDay, recWeekOccurrence, recMonth, recDayOfWeek sql.NullInt64
+		var recMonthDay, recWeekOccurrence, recMonth, recDayOfWeek, notifyOffset sql.NullInt64
 		var avgActualDuration sql.NullFloat64
 		var active bool
 		var deletedAt sql.NullString
 
 		err := rows.Scan(
 			&t.ID, &t.Name, &t.Kind, &durationMin, &earliestStart, &latestEnd, &fixedStart, &fixedEnd,
-			&recType, &recurrenceInterval, &recWeekdays, &recMonthDay, &recWeekOccurrence, &recMonth, &recDayOfWeek,
-			&priority, &energyBand, &active, &lastDone, &successStreak, &avgActualDuration, &deletedAt,
+			&recType, &recurrenceInterval, &recWeekdays, &recMonthDay, &recWeekOccurrence, &recMonth, &recDayOfWeek, &t.Recurrence.Start, &t.Recurrence.Until, &t.Recurrence.Count,
+			&priority, &energyBand, &active, &lastDone, &successStreak, &avgActualDuration, &projectID, &taskContext,
+			&t.NotifyStartDisabled, &notifyOffset, &t.NotifyMessage, &t.NotifyUrgency, &t.NotifySound, &t.PoolID, &t.NiceToHave, &t.Leisure, &t.PrepMin, &t.TravelMin, &t.Version, &deletedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -214,8 +229,18 @@ func (s *Store) GetAllTasksIncludingDeleted() ([]models.Task, error) {
 		if lastDone.Valid {
 			t.LastDone = lastDone.String
 		}
+		if projectID.Valid {
+			t.ProjectID = projectID.String
+		}
+		if taskContext.Valid {
+			t.Context = taskContext.String
+		}
 		t.Active = active
 
+		if notifyOffset.Valid {
+			offset := int(notifyOffset.Int64)
+			t.NotifyOffsetMin = &offset
+		}
 		if deletedAt.Valid {
 			t.DeletedAt = &deletedAt.String
 		}
@@ -245,7 +270,7 @@ func (s *Store) UpdateTask(task models.Task) error {
 		deletedAt = sql.NullString{String: *task.DeletedAt, Valid: true}
 	}
 
-	var recMonthDay, recWeekOccurrence, recMonth, recDayOfWeek sql.NullInt64
+	var recMonthDay, recWeekOccurrence, recMonth, recDayOfWeek, notifyOffset sql.NullInt64
 	if task.Recurrence.MonthDay != 0 {
 		recMonthDay = sql.NullInt64{Int64: int64(task.Recurrence.MonthDay), Valid: true}
 	}
@@ -259,46 +284,176 @@ func (s *Store) UpdateTask(task models.Task) error {
 	if task.Recurrence.Type == constants.RecurrenceMonthlyDay {
 		recDayOfWeek = sql.NullInt64{Int64: int64(task.Recurrence.DayOfWeekInMonth), Valid: true}
 	}
+	if task.NotifyOffsetMin != nil {
+		notifyOffset = sql.NullInt64{Int64: int64(*task.NotifyOffsetMin), Valid: true}
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Reject the save if someone else saved the task after it was loaded
+	var version int
+	err = tx.QueryRow("SELECT version FROM tasks WHERE id = ?", task.ID).Scan(&version)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check task version: %w", err)
+	}
+	if task.Version > 0 && err == nil && version != task.Version {
+		return &models.ConflictError{Record: "task", ID: task.ID}
+	}
 
-	_, err = s.db.Exec(`
+	_, err = tx.Exec(`
 		INSERT OR REPLACE INTO tasks (
 			id, name, kind, duration_min, earliest_start, latest_end, fixed_start, fixed_end,
 			recurrence_type, recurrence_interval, recurrence_weekdays, recurrence_month_day,
-			recurrence_week_occurrence, recurrence_month, recurrence_day_of_week,
-			priority, energy_band, active, last_done, success_streak, avg_actual_duration, deleted_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			recurrence_week_occurrence, recurrence_month, recurrence_day_of_week, recurrence_start, recurrence_until, recurrence_count,
+			priority, energy_band, active, last_done, success_streak, avg_actual_duration, project_id, context,
+		       notify_start_disabled, notify_offset_min, notify_message, notify_urgency, notify_sound, pool_id, nice_to_have, leisure, prep_min, travel_min, version, deleted_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		task.ID, task.Name, task.Kind, task.DurationMin, task.EarliestStart, task.LatestEnd, task.FixedStart, task.FixedEnd,
 		task.Recurrence.Type, task.Recurrence.IntervalDays, string(weekdaysJSON), recMonthDay,
-		recWeekOccurrence, recMonth, recDayOfWeek,
-		task.Priority, task.EnergyBand, task.Active, task.LastDone, task.SuccessStreak, task.AvgActualDurationMin, deletedAt,
+		recWeekOccurrence, recMonth, recDayOfWeek, task.Recurrence.Start, task.Recurrence.Until, task.Recurrence.Count,
+		task.Priority, task.EnergyBand, task.Active, task.LastDone, task.SuccessStreak, task.AvgActualDurationMin, task.ProjectID, task.Context,
+		task.NotifyStartDisabled, notifyOffset, task.NotifyMessage, task.NotifyUrgency, task.NotifySound, task.PoolID, task.NiceToHave, task.Leisure, task.PrepMin, task.TravelMin, version+1, deletedAt,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (s *Store) DeleteTask(id string) error {
+	_, err := s.deleteTask(id, nil, time.Now())
+	return err
+}
+
+func (s *Store) DeleteTaskCascade(id string, removeSlots bool, now time.Time) (int, error) {
+	return s.deleteTask(id, &removeSlots, now)
+}
+
+// deleteTask soft-deletes a task and, when removeSlots says so or is nil
+// and task_delete_slots is "remove", its slots still upcoming at now
+func (s *Store) deleteTask(id string, removeSlots *bool, now time.Time) (int, error) {
+	tx, err := s.begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
 	// Soft delete: set deleted_at timestamp instead of removing the record
 	var deletedAt sql.NullString
-	err := s.db.QueryRow("SELECT deleted_at FROM tasks WHERE id = ?", id).Scan(&deletedAt)
+	err = tx.QueryRow("SELECT deleted_at FROM tasks WHERE id = ?", id).Scan(&deletedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("task with id %s not found", id)
+			return 0, fmt.Errorf("task with id %s not found", id)
 		}
-		return fmt.Errorf("failed to check task existence: %w", err)
+		return 0, fmt.Errorf("failed to check task existence: %w", err)
 	}
 
 	if deletedAt.Valid {
-		return fmt.Errorf("task with id %s is already deleted", id)
+		return 0, fmt.Errorf("task with id %s is already deleted", id)
 	}
 
-	now := time.Now().UTC().Format(time.RFC3339)
-	_, err = s.db.Exec("UPDATE tasks SET deleted_at = ? WHERE id = ?", now, id)
+	settings, err := storedSettings(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	// The slots are marked with the task's time so restoring it finds them
+	stamp := time.Now().UTC().Format(time.RFC3339)
+	if _, err := tx.Exec("UPDATE tasks SET deleted_at = ?, version = version + 1 WHERE id = ?", stamp, id); err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	if removeSlots == nil {
+		remove := settings.TaskDeleteSlots == constants.TaskDeleteSlotsRemove
+		removeSlots = &remove
+	}
+	if *removeSlots {
+		window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+		if err != nil {
+			return 0, err
+		}
+		if removed, err = deleteUpcomingSlots(tx, id, window, now, stamp); err != nil {
+			return 0, err
+		}
+	}
+	return removed, tx.Commit()
+}
+
+// deleteUpcomingSlots soft-deletes the slots of taskID in the latest
+// revision of accepted plans that are still upcoming at now
+func deleteUpcomingSlots(tx *txn, taskID string, window models.DayWindow, now time.Time, stamp string) (int, error) {
+	rows, err := tx.Query(`
+		SELECT s.id, s.plan_date, s.start_time, s.status
+		FROM slots s
+		JOIN plans p ON p.date = s.plan_date AND p.revision = s.plan_revision
+		WHERE s.task_id = ? AND s.deleted_at IS NULL AND p.date >= ?
+			AND p.accepted_at IS NOT NULL AND p.deleted_at IS NULL
+			AND p.revision = (SELECT MAX(revision) FROM plans WHERE date = p.date AND deleted_at IS NULL)`,
+		taskID, now.Format(constants.DateFormat))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the task's slots: %w", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		var date string
+		var slot models.Slot
+		if err := rows.Scan(&id, &date, &slot.Start, &slot.Status); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan slot: %w", err)
+		}
+		if window.Upcoming(date, slot, now) {
+			ids = append(ids, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating slots: %w", err)
+	}
+
+	for _, id := range ids {
+		if _, err := tx.Exec("UPDATE slots SET deleted_at = ? WHERE id = ?", stamp, id); err != nil {
+			return 0, err
+		}
+	}
+	if len(ids) > 0 {
+		if err := bumpTaskSlotPlans(tx, taskID, stamp); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), nil
+}
+
+// bumpTaskSlotPlans bumps the version of the plans holding slots of taskID
+// deleted at stamp
+func bumpTaskSlotPlans(tx *txn, taskID, stamp string) error {
+	_, err := tx.Exec(`
+		UPDATE plans SET version = version + 1
+		WHERE EXISTS (
+			SELECT 1 FROM slots s
+			WHERE s.plan_date = plans.date AND s.plan_revision = plans.revision
+				AND s.task_id = ? AND s.deleted_at = ?
+		)`,
+		taskID, stamp)
 	return err
 }
 
 func (s *Store) RestoreTask(id string) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	// Restore a soft-deleted task by clearing deleted_at
 	var deletedAt sql.NullString
-	err := s.db.QueryRow("SELECT deleted_at FROM tasks WHERE id = ?", id).Scan(&deletedAt)
+	err = tx.QueryRow("SELECT deleted_at FROM tasks WHERE id = ?", id).Scan(&deletedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return fmt.Errorf("task with id %s not found", id)
@@ -310,6 +465,16 @@ func (s *Store) RestoreTask(id string) error {
 		return fmt.Errorf("cannot restore a task that is not deleted: %s", id)
 	}
 
-	_, err = s.db.Exec("UPDATE tasks SET deleted_at = NULL WHERE id = ?", id)
-	return err
+	if _, err := tx.Exec("UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	// Bring back the slots deleted with the task
+	if err := bumpTaskSlotPlans(tx, id, deletedAt.String); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE slots SET deleted_at = NULL WHERE task_id = ? AND deleted_at = ?", id, deletedAt.String); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
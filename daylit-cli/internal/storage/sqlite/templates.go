@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) SaveDayTemplate(template models.DayTemplate) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO day_templates (id, name, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name`,
+		template.ID, template.Name, template.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to save template: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM day_template_slots WHERE template_id = ?", template.ID); err != nil {
+		return fmt.Errorf("failed to clear template slots: %w", err)
+	}
+	for _, slot := range template.Slots {
+		_, err := tx.Exec(`
+			INSERT INTO day_template_slots (template_id, start_time, end_time, task_id)
+			VALUES (?, ?, ?, ?)`,
+			template.ID, slot.Start, slot.End, slot.TaskID)
+		if err != nil {
+			return fmt.Errorf("failed to save template slot: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) GetDayTemplate(name string) (models.DayTemplate, error) {
+	var t models.DayTemplate
+	var createdAt string
+	err := s.conn().QueryRow(`
+		SELECT id, name, created_at FROM day_templates WHERE name = ?`, name).
+		Scan(&t.ID, &t.Name, &createdAt)
+	if err != nil {
+		return models.DayTemplate{}, err
+	}
+
+	t.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return models.DayTemplate{}, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+
+	t.Slots, err = s.getDayTemplateSlots(t.ID)
+	if err != nil {
+		return models.DayTemplate{}, err
+	}
+	return t, nil
+}
+
+func (s *Store) GetAllDayTemplates() ([]models.DayTemplate, error) {
+	rows, err := s.conn().Query("SELECT id, name, created_at FROM day_templates ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []models.DayTemplate
+	for rows.Next() {
+		var t models.DayTemplate
+		var createdAt string
+		if err := rows.Scan(&t.ID, &t.Name, &createdAt); err != nil {
+			return nil, err
+		}
+		t.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range templates {
+		if templates[i].Slots, err = s.getDayTemplateSlots(templates[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return templates, nil
+}
+
+func (s *Store) DeleteDayTemplate(name string) error {
+	tx, err := s.begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRow("SELECT id FROM day_templates WHERE name = ?", name).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("template %s not found", name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find template: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM day_template_slots WHERE template_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete template slots: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM day_templates WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (s *Store) getDayTemplateSlots(templateID string) ([]models.TemplateSlot, error) {
+	rows, err := s.conn().Query(`
+		SELECT start_time, end_time, task_id FROM day_template_slots
+		WHERE template_id = ? ORDER BY start_time`, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template slots: %w", err)
+	}
+	defer rows.Close()
+
+	var slots []models.TemplateSlot
+	for rows.Next() {
+		var slot models.TemplateSlot
+		if err := rows.Scan(&slot.Start, &slot.End, &slot.TaskID); err != nil {
+			return nil, err
+		}
+		slots = append(slots, slot)
+	}
+	return slots, rows.Err()
+}
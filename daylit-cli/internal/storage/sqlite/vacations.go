@@ -0,0 +1,66 @@
+package sqlite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func (s *Store) AddVacation(vacation models.Vacation) error {
+	if err := vacation.Validate(); err != nil {
+		return err
+	}
+
+	_, err := s.conn().Exec(`
+		INSERT INTO vacations (id, start_date, end_date, note, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		vacation.ID, vacation.Start, vacation.End, vacation.Note, vacation.CreatedAt.Format(time.RFC3339))
+	return err
+}
+
+func (s *Store) GetVacations(startDay, endDay string) ([]models.Vacation, error) {
+	rows, err := s.conn().Query(`
+		SELECT id, start_date, end_date, note, created_at
+		FROM vacations
+		WHERE end_date >= ? AND start_date <= ?
+		ORDER BY start_date, end_date`, startDay, endDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vacations []models.Vacation
+	for rows.Next() {
+		var v models.Vacation
+		var createdAt string
+		if err := rows.Scan(&v.ID, &v.Start, &v.End, &v.Note, &createdAt); err != nil {
+			return nil, err
+		}
+		v.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		vacations = append(vacations, v)
+	}
+
+	return vacations, rows.Err()
+}
+
+func (s *Store) DeleteVacation(id string) error {
+	result, err := s.conn().Exec(`DELETE FROM vacations WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete vacation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("vacation not found")
+	}
+
+	return nil
+}
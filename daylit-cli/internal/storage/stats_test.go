@@ -0,0 +1,204 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func TestGetTaskStats(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	tasks := []models.Task{
+		{ID: "task-stats-1", Name: "Deep Work", Kind: constants.TaskKindFlexible, DurationMin: 60, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 1, Active: true},
+		{ID: "task-stats-2", Name: "Email", Kind: constants.TaskKindFlexible, DurationMin: 30, Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Priority: 4, Active: true},
+	}
+	for _, task := range tasks {
+		if err := store.AddTask(task); err != nil {
+			t.Fatalf("failed to add task: %v", err)
+		}
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	accepted := models.DayPlan{
+		Date:       "2024-05-01",
+		AcceptedAt: &now,
+		Slots: []models.Slot{
+			{Start: "09:00", End: "10:00", TaskID: "task-stats-1", Status: constants.SlotStatusDone},
+			{Start: "10:00", End: "10:30", TaskID: "task-stats-2", Status: constants.SlotStatusSkipped},
+		},
+	}
+	draft := models.DayPlan{
+		Date: "2024-05-02",
+		Slots: []models.Slot{
+			{Start: "09:00", End: "10:30", TaskID: "task-stats-1", Status: constants.SlotStatusPlanned},
+		},
+	}
+	outside := models.DayPlan{
+		Date:  "2024-06-01",
+		Slots: []models.Slot{{Start: "09:00", End: "10:00", TaskID: "task-stats-1", Status: constants.SlotStatusDone}},
+	}
+	for _, plan := range []models.DayPlan{accepted, draft, outside} {
+		if err := store.SavePlan(plan); err != nil {
+			t.Fatalf("failed to save plan %s: %v", plan.Date, err)
+		}
+	}
+
+	stats, err := store.GetTaskStats("2024-05-01", "2024-05-31")
+	if err != nil {
+		t.Fatalf("failed to get task stats: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 tasks, got %d: %+v", len(stats), stats)
+	}
+
+	deep := stats[0]
+	want := models.TaskStats{
+		TaskID:            "task-stats-1",
+		TaskName:          "Deep Work",
+		Priority:          1,
+		PlannedSlots:      2,
+		PlannedMinutes:    150,
+		DoneSlots:         1,
+		DoneMinutes:       60,
+		AcceptedSlots:     1,
+		AcceptedDoneSlots: 1,
+	}
+	if deep != want {
+		t.Errorf("deep work stats = %+v, want %+v", deep, want)
+	}
+	if deep.Adherence() != 100 {
+		t.Errorf("deep work adherence = %d, want 100", deep.Adherence())
+	}
+
+	email := stats[1]
+	if email.PlannedMinutes != 30 || email.DoneMinutes != 0 || email.AcceptedSlots != 1 || email.Adherence() != 0 {
+		t.Errorf("unexpected email stats: %+v", email)
+	}
+}
+
+func TestGetHabitCategoryStats(t *testing.T) {
+	sqliteStore, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	stores := map[string]storage.Provider{"sqlite": sqliteStore, "memory": setupMemoryStore(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			loc := time.FixedZone("test", -5*60*60)
+			archived := time.Date(2024, 5, 3, 0, 0, 0, 0, loc)
+			habits := []models.Habit{
+				{ID: "run", Name: "Run", Category: "health", CreatedAt: time.Date(2024, 4, 1, 8, 0, 0, 0, loc)},
+				// Created late in the evening: due from its local date
+				{ID: "floss", Name: "Floss", Category: "health", CreatedAt: time.Date(2024, 5, 5, 22, 0, 0, 0, loc)},
+				{ID: "dishes", Name: "Dishes", Category: "chores", CreatedAt: time.Date(2024, 5, 1, 8, 0, 0, 0, loc)},
+				{ID: "plants", Name: "Plants", Category: "chores", CreatedAt: time.Date(2024, 5, 1, 8, 0, 0, 0, loc), ArchivedAt: &archived},
+				{ID: "read", Name: "Read", CreatedAt: time.Date(2024, 5, 8, 8, 0, 0, 0, loc)},
+				// Paused days are neither due nor done
+				{ID: "yoga", Name: "Yoga", Category: "health", CreatedAt: time.Date(2024, 4, 1, 8, 0, 0, 0, loc), PausedFrom: "2024-05-03", PausedUntil: "2024-05-05"},
+			}
+			for _, h := range habits {
+				if err := store.AddHabit(h); err != nil {
+					t.Fatalf("failed to add habit %s: %v", h.Name, err)
+				}
+			}
+			entries := []struct{ habit, day string }{
+				{"run", "2024-05-01"}, {"run", "2024-05-02"}, {"run", "2024-04-30"},
+				{"floss", "2024-05-06"},
+				{"dishes", "2024-05-07"},
+				{"plants", "2024-05-02"},
+				{"yoga", "2024-05-01"}, {"yoga", "2024-05-04"},
+			}
+			for _, e := range entries {
+				if err := store.AddHabitEntry(models.HabitEntry{
+					ID: e.habit + e.day, HabitID: e.habit, Day: e.day,
+					CreatedAt: time.Now(), UpdatedAt: time.Now(),
+				}); err != nil {
+					t.Fatalf("failed to add entry: %v", err)
+				}
+			}
+
+			stats, err := store.GetHabitCategoryStats("2024-05-01", "2024-05-07")
+			if err != nil {
+				t.Fatalf("failed to get habit category stats: %v", err)
+			}
+			want := []models.HabitCategoryStats{
+				{Category: "chores", Habits: 1, DoneDays: 1, DueDays: 7},
+				{Category: "health", Habits: 3, DoneDays: 4, DueDays: 14},
+			}
+			// Read is created after the range and has nothing due
+			if len(stats) != 3 || stats[0] != (models.HabitCategoryStats{Habits: 1}) {
+				t.Fatalf("expected uncategorized first with nothing due, got %+v", stats)
+			}
+			for i, w := range want {
+				if stats[i+1] != w {
+					t.Errorf("stats[%d] = %+v, want %+v", i+1, stats[i+1], w)
+				}
+			}
+			if got := stats[2].Completion(); got != 28 {
+				t.Errorf("health completion = %d%%, want 28%%", got)
+			}
+
+			// Kept in the stats, Plants is due up to the day it was archived
+			settings, err := store.GetSettings()
+			if err != nil {
+				t.Fatal(err)
+			}
+			settings.ArchivedHabitStats = constants.ArchivedHabitStatsKeep
+			if err := store.SaveSettings(settings); err != nil {
+				t.Fatal(err)
+			}
+			stats, err = store.GetHabitCategoryStats("2024-05-01", "2024-05-07")
+			if err != nil {
+				t.Fatalf("failed to get habit category stats: %v", err)
+			}
+			if want := (models.HabitCategoryStats{Category: "chores", Habits: 2, DoneDays: 2, DueDays: 10}); len(stats) != 3 || stats[1] != want {
+				t.Errorf("with archived habits kept, chores = %+v, want %+v", stats, want)
+			}
+		})
+	}
+}
+
+func TestDailyMetrics(t *testing.T) {
+	sqliteStore, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	stores := map[string]storage.Provider{"sqlite": sqliteStore, "memory": setupMemoryStore(t)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			computedAt := time.Date(2024, 5, 8, 7, 0, 0, 0, time.UTC)
+			for _, m := range []models.DailyMetrics{
+				{Date: "2024-05-02", HasPlan: true, PlannedSlots: 4, DoneSlots: 1, ComputedAt: computedAt},
+				{Date: "2024-05-01", HasPlan: true, Accepted: true, PlannedSlots: 5, DoneSlots: 4, SkippedSlots: 1,
+					TrackedSlots: 2, DriftMinutes: 25, OnTrack: 2, TooMuch: 1, Unnecessary: 1, ComputedAt: computedAt},
+				{Date: "2024-05-09", ComputedAt: computedAt},
+			} {
+				if err := store.SaveDailyMetrics(m); err != nil {
+					t.Fatalf("failed to save metrics: %v", err)
+				}
+			}
+			// Saving a day again replaces it
+			replaced := models.DailyMetrics{Date: "2024-05-02", HasPlan: true, Accepted: true, PlannedSlots: 4, DoneSlots: 3, ComputedAt: computedAt}
+			if err := store.SaveDailyMetrics(replaced); err != nil {
+				t.Fatalf("failed to replace metrics: %v", err)
+			}
+
+			daily, err := store.GetDailyMetrics("2024-05-01", "2024-05-07")
+			if err != nil {
+				t.Fatalf("failed to get metrics: %v", err)
+			}
+			if len(daily) != 2 || daily[0].Date != "2024-05-01" {
+				t.Fatalf("expected two days in date order, got %+v", daily)
+			}
+			if daily[0].DriftMinutes != 25 || daily[0].Unnecessary != 1 || !daily[0].Accepted || !daily[0].ComputedAt.Equal(computedAt) {
+				t.Errorf("metrics did not round-trip: %+v", daily[0])
+			}
+			if daily[1].DoneSlots != 3 || !daily[1].Accepted {
+				t.Errorf("expected the replaced metrics, got %+v", daily[1])
+			}
+		})
+	}
+}
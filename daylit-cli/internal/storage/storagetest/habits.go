@@ -0,0 +1,356 @@
+package storagetest
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func testHabits(t *testing.T, store storage.Provider) {
+	base := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+	stretch := models.Habit{ID: "h1", Name: "Stretch", CreatedAt: base, Category: "health",
+		ReminderTime: "07:30", ReminderDays: []time.Weekday{time.Monday, time.Thursday}, ReminderEnabled: true}
+	for _, h := range []models.Habit{
+		stretch,
+		{ID: "h2", Name: "Read", CreatedAt: base.Add(time.Minute)},
+		{ID: "h3", Name: "Journal", CreatedAt: base.Add(2 * time.Minute)},
+	} {
+		must(t, store.AddHabit(h), "add habit")
+	}
+
+	got, err := store.GetHabit("h1")
+	must(t, err, "get habit")
+	if got.Name != "Stretch" || got.Category != "health" || !got.CreatedAt.Equal(base) || got.ReminderTime != "07:30" ||
+		len(got.ReminderDays) != 2 || got.ReminderDays[1] != time.Thursday || !got.ReminderEnabled ||
+		got.ArchivedAt != nil || got.DeletedAt != nil {
+		t.Errorf("habit not saved as given: %+v", got)
+	}
+	byName, err := store.GetHabitByName("Read")
+	must(t, err, "get habit by name")
+	if byName.ID != "h2" {
+		t.Errorf("habit by name = %s, want h2", byName.ID)
+	}
+	if _, err := store.GetHabit("missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("missing habit: got %v, want sql.ErrNoRows", err)
+	}
+
+	got.Name = "Stretch well"
+	got.PausedFrom, got.PausedUntil = "2025-07-01", "2025-07-14"
+	must(t, store.UpdateHabit(got), "update habit")
+	got, err = store.GetHabit("h1")
+	must(t, err, "get habit")
+	if got.Name != "Stretch well" || got.PausedFrom != "2025-07-01" || got.PausedUntil != "2025-07-14" {
+		t.Errorf("update not saved: %+v", got)
+	}
+
+	must(t, store.ArchiveHabit("h2"), "archive habit")
+	if err := store.ArchiveHabit("h2"); err == nil {
+		t.Error("archiving an archived habit succeeded")
+	}
+	must(t, store.DeleteHabit("h3"), "delete habit")
+	if err := store.DeleteHabit("h3"); err == nil {
+		t.Error("deleting a deleted habit succeeded")
+	}
+	if _, err := store.GetHabit("h3"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted habit: got %v, want sql.ErrNoRows", err)
+	}
+	if archived, err := store.GetHabit("h2"); err != nil || archived.ArchivedAt == nil {
+		t.Errorf("archived habit = %+v, %v, want it with its archive time", archived, err)
+	}
+
+	lists := []struct {
+		archived, deleted bool
+		want              []string
+	}{
+		{false, false, []string{"h1"}},
+		{true, false, []string{"h1", "h2"}},
+		{false, true, []string{"h1", "h3"}},
+		{true, true, []string{"h1", "h2", "h3"}},
+	}
+	for _, l := range lists {
+		habits, err := store.GetAllHabits(l.archived, l.deleted)
+		must(t, err, "get habits")
+		var ids []string
+		for _, h := range habits {
+			ids = append(ids, h.ID)
+		}
+		// Oldest first
+		if !slices.Equal(ids, l.want) {
+			t.Errorf("habits (archived %v, deleted %v) = %v, want %v", l.archived, l.deleted, ids, l.want)
+		}
+	}
+
+	must(t, store.UnarchiveHabit("h2"), "unarchive habit")
+	if err := store.UnarchiveHabit("h2"); err == nil {
+		t.Error("unarchiving a live habit succeeded")
+	}
+	must(t, store.RestoreHabit("h3"), "restore habit")
+	if err := store.RestoreHabit("h3"); err == nil {
+		t.Error("restoring a live habit succeeded")
+	}
+	habits, err := store.GetAllHabits(false, false)
+	must(t, err, "get habits")
+	if len(habits) != 3 {
+		t.Errorf("got %d live habits, want all 3 back", len(habits))
+	}
+}
+
+func testHabitEntries(t *testing.T, store storage.Provider) {
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, h := range []models.Habit{{ID: "h1", Name: "Stretch", CreatedAt: now}, {ID: "h2", Name: "Read", CreatedAt: now}} {
+		must(t, store.AddHabit(h), "add habit")
+	}
+	entries := []models.HabitEntry{
+		{ID: "e1", HabitID: "h1", Day: "2025-07-01", Note: "ten minutes", CreatedAt: now, UpdatedAt: now},
+		{ID: "e2", HabitID: "h1", Day: "2025-07-02", CreatedAt: now.Add(time.Second), UpdatedAt: now},
+		{ID: "e3", HabitID: "h2", Day: "2025-07-02", CreatedAt: now.Add(2 * time.Second), UpdatedAt: now},
+		{ID: "e4", HabitID: "h1", Day: "2025-07-04", CreatedAt: now.Add(3 * time.Second), UpdatedAt: now},
+	}
+	for _, e := range entries {
+		must(t, store.AddHabitEntry(e), "add habit entry")
+	}
+
+	got, err := store.GetHabitEntry("h1", "2025-07-01")
+	must(t, err, "get habit entry")
+	if got.ID != "e1" || got.Note != "ten minutes" || got.DeletedAt != nil {
+		t.Errorf("entry not saved as given: %+v", got)
+	}
+	if _, err := store.GetHabitEntry("h2", "2025-07-01"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("missing entry: got %v, want sql.ErrNoRows", err)
+	}
+
+	// A habit has one entry a day; adding another updates it
+	must(t, store.AddHabitEntry(models.HabitEntry{ID: "e5", HabitID: "h1", Day: "2025-07-01", Note: "twenty", CreatedAt: now, UpdatedAt: now}), "add habit entry")
+	got, err = store.GetHabitEntry("h1", "2025-07-01")
+	must(t, err, "get habit entry")
+	if got.ID != "e1" || got.Note != "twenty" {
+		t.Errorf("second entry of the day = %+v, want e1 with the new note", got)
+	}
+	got.Note = "thirty"
+	must(t, store.UpdateHabitEntry(got), "update habit entry")
+	if got, err := store.GetHabitEntry("h1", "2025-07-01"); err != nil || got.Note != "thirty" {
+		t.Errorf("updated entry = %+v, %v", got, err)
+	}
+
+	day, err := store.GetHabitEntriesForDay("2025-07-02")
+	must(t, err, "get entries for day")
+	if len(day) != 2 || day[0].ID != "e2" || day[1].ID != "e3" {
+		t.Errorf("day's entries = %+v, want e2 and e3 in the order added", day)
+	}
+	forHabit, err := store.GetHabitEntriesForHabit("h1", "2025-07-01", "2025-07-03")
+	must(t, err, "get entries for habit")
+	if len(forHabit) != 2 || forHabit[0].Day != "2025-07-02" || forHabit[1].Day != "2025-07-01" {
+		t.Errorf("habit's entries = %+v, want the two in range, newest first", forHabit)
+	}
+
+	must(t, store.DeleteHabitEntry("e2"), "delete habit entry")
+	if err := store.DeleteHabitEntry("e2"); err == nil {
+		t.Error("deleting a deleted entry succeeded")
+	}
+	if _, err := store.GetHabitEntry("h1", "2025-07-02"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted entry: got %v, want sql.ErrNoRows", err)
+	}
+	if day, _ := store.GetHabitEntriesForDay("2025-07-02"); len(day) != 1 {
+		t.Errorf("day's entries after delete = %+v, want only e3", day)
+	}
+	must(t, store.RestoreHabitEntry("e2"), "restore habit entry")
+	if err := store.RestoreHabitEntry("e2"); err == nil {
+		t.Error("restoring a live entry succeeded")
+	}
+
+	// With habit_delete_entries "delete" a habit's entries go with it, and
+	// come back with it
+	settings, err := store.GetSettings()
+	must(t, err, "get settings")
+	settings.HabitDeleteEntries = constants.HabitDeleteEntriesDelete
+	must(t, store.SaveSettings(settings), "save settings")
+	must(t, store.DeleteHabit("h1"), "delete habit")
+	all, err := store.GetAllHabitEntries()
+	must(t, err, "get all entries")
+	var deleted []string
+	for _, e := range all {
+		if e.DeletedAt != nil {
+			deleted = append(deleted, e.ID)
+		}
+	}
+	if !slices.Equal(deleted, []string{"e1", "e2", "e4"}) {
+		t.Errorf("deleted entries = %v, want all of h1's", deleted)
+	}
+	must(t, store.RestoreHabit("h1"), "restore habit")
+	if entries, _ := store.GetHabitEntriesForHabit("h1", "2025-07-01", "2025-07-31"); len(entries) != 3 {
+		t.Errorf("after restore h1 has %d entries, want the 3 deleted with it", len(entries))
+	}
+
+	// Every entry, deleted or not, by day and habit
+	all, err = store.GetAllHabitEntries()
+	must(t, err, "get all entries")
+	var ids []string
+	for _, e := range all {
+		ids = append(ids, e.ID)
+	}
+	if !slices.Equal(ids, []string{"e1", "e2", "e3", "e4"}) {
+		t.Errorf("all entries = %v, want [e1 e2 e3 e4]", ids)
+	}
+}
+
+func testOTEntries(t *testing.T, store storage.Provider) {
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, e := range []models.OTEntry{
+		{ID: "o2", Day: "2025-08-02", Title: "Finish the draft", CreatedAt: now, UpdatedAt: now},
+		{ID: "o1", Day: "2025-08-01", Title: "Call the plumber", Note: "before noon", CreatedAt: now, UpdatedAt: now},
+		{ID: "o3", Day: "2025-08-03", Title: "Rest", CreatedAt: now, UpdatedAt: now},
+	} {
+		must(t, store.AddOTEntry(e), "add OT entry")
+	}
+
+	got, err := store.GetOTEntry("2025-08-01")
+	must(t, err, "get OT entry")
+	if got.ID != "o1" || got.Title != "Call the plumber" || got.Note != "before noon" || !got.CreatedAt.Equal(now) {
+		t.Errorf("OT entry not saved as given: %+v", got)
+	}
+	if _, err := store.GetOTEntry("2025-08-09"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("missing OT entry: got %v, want sql.ErrNoRows", err)
+	}
+	got.Title = "Call the electrician"
+	must(t, store.UpdateOTEntry(got), "update OT entry")
+	if got, err := store.GetOTEntry("2025-08-01"); err != nil || got.Title != "Call the electrician" {
+		t.Errorf("updated OT entry = %+v, %v", got, err)
+	}
+
+	must(t, store.DeleteOTEntry("2025-08-02"), "delete OT entry")
+	if err := store.DeleteOTEntry("2025-08-02"); err == nil {
+		t.Error("deleting a deleted OT entry succeeded")
+	}
+	if _, err := store.GetOTEntry("2025-08-02"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted OT entry: got %v, want sql.ErrNoRows", err)
+	}
+	days := func(includeDeleted bool) []string {
+		entries, err := store.GetOTEntries("2025-08-01", "2025-08-02", includeDeleted)
+		must(t, err, "get OT entries")
+		var days []string
+		for _, e := range entries {
+			days = append(days, e.Day)
+		}
+		return days
+	}
+	// Newest first
+	if got := days(false); !slices.Equal(got, []string{"2025-08-01"}) {
+		t.Errorf("live OT entries = %v", got)
+	}
+	if got := days(true); !slices.Equal(got, []string{"2025-08-02", "2025-08-01"}) {
+		t.Errorf("OT entries including deleted = %v", got)
+	}
+
+	// Every entry, deleted or not, oldest first
+	all, err := store.GetAllOTEntries()
+	must(t, err, "get all OT entries")
+	var all3 []string
+	for _, e := range all {
+		all3 = append(all3, e.ID)
+	}
+	if !slices.Equal(all3, []string{"o1", "o2", "o3"}) {
+		t.Errorf("all OT entries = %v, want [o1 o2 o3]", all3)
+	}
+
+	must(t, store.RestoreOTEntry("2025-08-02"), "restore OT entry")
+	if err := store.RestoreOTEntry("2025-08-02"); err == nil {
+		t.Error("restoring a live OT entry succeeded")
+	}
+}
+
+func testAlerts(t *testing.T, store storage.Provider) {
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, a := range []models.Alert{
+		{ID: "a2", Message: "Stand up", Time: "15:00", Recurrence: models.Recurrence{Type: constants.RecurrenceDaily}, Active: true, CreatedAt: now},
+		{ID: "a1", Message: "Pay rent", Time: "09:00", Date: "2025-09-01", Urgency: "critical", Active: true, CreatedAt: now},
+	} {
+		must(t, store.AddAlert(a), "add alert")
+	}
+	if err := store.AddAlert(models.Alert{ID: "a3", Message: "Bad", Time: "25:00", Active: true, CreatedAt: now}); err == nil {
+		t.Error("adding an invalid alert succeeded")
+	}
+
+	got, err := store.GetAlert("a1")
+	must(t, err, "get alert")
+	if got.Message != "Pay rent" || got.Time != "09:00" || got.Date != "2025-09-01" || got.Urgency != "critical" ||
+		!got.Active || got.LastSent != nil {
+		t.Errorf("alert not saved as given: %+v", got)
+	}
+	if _, err := store.GetAlert("missing"); err == nil {
+		t.Error("getting a missing alert succeeded")
+	}
+
+	sent := now.Add(-time.Minute)
+	got.LastSent = &sent
+	got.Active = false
+	must(t, store.UpdateAlert(got), "update alert")
+	got, err = store.GetAlert("a1")
+	must(t, err, "get alert")
+	if got.Active || got.LastSent == nil || !got.LastSent.Equal(sent) {
+		t.Errorf("update not saved: %+v", got)
+	}
+
+	all, err := store.GetAllAlerts()
+	must(t, err, "get alerts")
+	if len(all) != 2 || all[0].ID != "a1" || all[1].ID != "a2" {
+		t.Errorf("alerts = %+v, want them ordered by time", all)
+	}
+
+	must(t, store.DeleteAlert("a1"), "delete alert")
+	if _, err := store.GetAlert("a1"); err == nil {
+		t.Error("getting a deleted alert succeeded")
+	}
+	if err := store.DeleteAlert("a1"); err == nil {
+		t.Error("deleting a missing alert succeeded")
+	}
+}
+
+func testVacations(t *testing.T, store storage.Provider) {
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, v := range []models.Vacation{
+		{ID: "v2", Start: "2025-12-20", End: "2026-01-02", Note: "holidays", CreatedAt: now},
+		{ID: "v1", Start: "2025-08-04", End: "2025-08-15", CreatedAt: now},
+		{ID: "v3", Start: "2026-03-01", End: "2026-03-01", CreatedAt: now},
+	} {
+		must(t, store.AddVacation(v), "add vacation")
+	}
+	if err := store.AddVacation(models.Vacation{ID: "v4", Start: "2025-08-10", End: "2025-08-01", CreatedAt: now}); err == nil {
+		t.Error("adding a vacation that ends before it starts succeeded")
+	}
+
+	ids := func(start, end string) []string {
+		vacations, err := store.GetVacations(start, end)
+		must(t, err, "get vacations")
+		var ids []string
+		for _, v := range vacations {
+			ids = append(ids, v.ID)
+		}
+		return ids
+	}
+	// Overlapping the range, by start
+	if got := ids("2025-08-15", "2025-12-20"); !slices.Equal(got, []string{"v1", "v2"}) {
+		t.Errorf("vacations = %v, want [v1 v2]", got)
+	}
+	if got := ids("2025-08-16", "2025-12-19"); len(got) != 0 {
+		t.Errorf("vacations between = %v, want none", got)
+	}
+	vacations, err := store.GetVacations("2026-01-01", "2026-01-01")
+	must(t, err, "get vacations")
+	if len(vacations) != 1 || vacations[0].Note != "holidays" || vacations[0].End != "2026-01-02" {
+		t.Errorf("vacation not saved as given: %+v", vacations)
+	}
+
+	must(t, store.DeleteVacation("v2"), "delete vacation")
+	if got := ids("2025-01-01", "2026-12-31"); !slices.Equal(got, []string{"v1", "v3"}) {
+		t.Errorf("vacations after delete = %v, want [v1 v3]", got)
+	}
+	if err := store.DeleteVacation("v2"); err == nil {
+		t.Error("deleting a deleted vacation succeeded")
+	}
+}
@@ -0,0 +1,606 @@
+package storagetest
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func testPlans(t *testing.T, store storage.Provider) {
+	addTasks(t, store, "write", "read")
+	const date = "2025-03-10"
+	if _, err := store.GetPlan(date); err == nil {
+		t.Error("getting a missing plan succeeded")
+	}
+
+	actualStart := "09:05"
+	plan := models.DayPlan{Date: date, Note: "short day", Slots: []models.Slot{
+		// Saved out of order; plans come back ordered by start
+		{Start: "10:00", End: "11:00", TaskID: "read", Status: constants.SlotStatusPlanned},
+		{Start: "09:00", End: "10:00", TaskID: "write", Status: constants.SlotStatusDone, ActualStart: &actualStart,
+			Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack, Note: "flowed"}},
+	}}
+	must(t, store.SavePlan(plan), "save plan")
+	got, err := store.GetPlan(date)
+	must(t, err, "get plan")
+	if got.Revision != 1 || got.AcceptedAt != nil || got.Note != "short day" || got.DeletedAt != nil {
+		t.Errorf("plan not saved as given: %+v", got)
+	}
+	if s := starts(got.Slots); !slices.Equal(s, []string{"09:00", "10:00"}) {
+		t.Fatalf("slots = %v, want them ordered by start", s)
+	}
+	write := got.Slots[0]
+	if write.TaskID != "write" || write.End != "10:00" || write.Status != constants.SlotStatusDone ||
+		write.Feedback == nil || write.Feedback.Rating != constants.FeedbackOnTrack || write.Feedback.Note != "flowed" ||
+		write.ActualStart == nil || *write.ActualStart != "09:05" || write.ActualEnd != nil {
+		t.Errorf("slot not saved as given: %+v", write)
+	}
+	if got.Slots[1].Feedback != nil {
+		t.Errorf("slot without feedback came back with %+v", got.Slots[1].Feedback)
+	}
+
+	// An unaccepted plan is replaced in place
+	got.Slots = got.Slots[:1]
+	must(t, store.SavePlan(got), "save plan")
+	replaced, err := store.GetPlan(date)
+	must(t, err, "get plan")
+	if replaced.Revision != 1 || len(replaced.Slots) != 1 {
+		t.Errorf("expected revision 1 with one slot, got revision %d with %v", replaced.Revision, starts(replaced.Slots))
+	}
+	if err := store.SavePlan(got); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("stale save: got %v, want a conflict", err)
+	}
+
+	must(t, store.DeletePlan(date), "delete plan")
+	if _, err := store.GetPlan(date); err == nil {
+		t.Error("getting a deleted plan succeeded")
+	}
+	if err := store.DeletePlan(date); err == nil {
+		t.Error("deleting a deleted plan succeeded")
+	}
+	must(t, store.RestorePlan(date), "restore plan")
+	restored, err := store.GetPlan(date)
+	must(t, err, "get plan")
+	if len(restored.Slots) != 1 || restored.DeletedAt != nil {
+		t.Errorf("restored plan = %+v", restored)
+	}
+	if err := store.RestorePlan(date); err == nil {
+		t.Error("restoring a live plan succeeded")
+	}
+}
+
+func testPlanRevisions(t *testing.T, store storage.Provider) {
+	addTasks(t, store, "write", "read")
+	const date = "2025-03-11"
+	savePlan(t, store, date, accepted(), slot("09:00", "10:00", "write", constants.SlotStatusAccepted))
+	must(t, store.SavePlan(models.DayPlan{Date: date, Note: "rainy", AcceptedAt: accepted(), Slots: []models.Slot{
+		slot("09:00", "10:00", "read", constants.SlotStatusAccepted),
+	}}), "save plan")
+	// A new revision of an accepted plan keeps the day's note
+	savePlan(t, store, date, nil, slot("11:00", "12:00", "write", constants.SlotStatusPlanned))
+
+	latest, err := store.GetPlan(date)
+	must(t, err, "get plan")
+	if latest.Revision != 3 || latest.Note != "rainy" {
+		t.Errorf("latest plan is revision %d with note %q, want revision 3 with note rainy", latest.Revision, latest.Note)
+	}
+	first, err := store.GetPlanRevision(date, 1)
+	must(t, err, "get revision 1")
+	if first.Revision != 1 || first.AcceptedAt == nil || len(first.Slots) != 1 || first.Slots[0].TaskID != "write" {
+		t.Errorf("revision 1 changed: %+v", first)
+	}
+	if _, err := store.GetPlanRevision(date, 9); err == nil {
+		t.Error("getting a missing revision succeeded")
+	}
+	got, err := store.GetLatestPlanRevision(date)
+	must(t, err, "get latest revision")
+	if got.Revision != 3 {
+		t.Errorf("latest revision = %d, want 3", got.Revision)
+	}
+	if _, err := store.GetLatestPlanRevision("2025-03-12"); err == nil {
+		t.Error("getting the latest revision of a missing plan succeeded")
+	}
+
+	all, err := store.GetAllPlans()
+	must(t, err, "get all plans")
+	var revisions []string
+	for _, p := range all {
+		revisions = append(revisions, fmt.Sprintf("%s#%d", p.Date, p.Revision))
+	}
+	if !slices.Equal(revisions, []string{date + "#1", date + "#2", date + "#3"}) {
+		t.Errorf("all plans = %v, want every revision", revisions)
+	}
+}
+
+// seedRange saves accepted plans for the first days of April 2025, the
+// third one deleted
+func seedRange(t *testing.T, store storage.Provider) {
+	t.Helper()
+	addTasks(t, store, "write", "read")
+	for _, date := range []string{"2025-04-01", "2025-04-02", "2025-04-03", "2025-04-05"} {
+		savePlan(t, store, date, accepted(),
+			slot("13:00", "14:00", "read", constants.SlotStatusAccepted),
+			slot("09:00", "10:00", "write", constants.SlotStatusDone))
+	}
+	must(t, store.DeletePlan("2025-04-03"), "delete plan")
+}
+
+func testPlansRange(t *testing.T, store storage.Provider) {
+	seedRange(t, store)
+	// A later revision replaces the day's slots
+	savePlan(t, store, "2025-04-02", accepted(), slot("10:00", "11:00", "read", constants.SlotStatusAccepted))
+
+	plans, err := store.GetPlansRange("2025-04-01", "2025-04-04")
+	must(t, err, "get plans")
+	var keys []string
+	for _, p := range plans {
+		keys = append(keys, fmt.Sprintf("%s#%d %v", p.Date, p.Revision, starts(p.Slots)))
+	}
+	want := []string{"2025-04-01#1 [09:00 13:00]", "2025-04-02#2 [10:00]"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("plans = %v, want %v", keys, want)
+	}
+
+	var pages [][]string
+	cursor := ""
+	for range 5 {
+		page, err := store.GetPlansPage("2025-04-01", "2025-04-30", cursor, 2)
+		must(t, err, "get plans page")
+		var dates []string
+		for _, p := range page.Plans {
+			dates = append(dates, p.Date)
+		}
+		pages = append(pages, dates)
+		if cursor = page.NextCursor; cursor == "" {
+			break
+		}
+	}
+	if got := fmt.Sprint(pages); got != "[[2025-04-01 2025-04-02] [2025-04-05]]" {
+		t.Errorf("pages = %s, want two pages of the live plans", got)
+	}
+	page, err := store.GetPlansPage("2025-04-01", "2025-04-30", "", 0)
+	must(t, err, "get plans page")
+	if len(page.Plans) != 3 || page.NextCursor != "" {
+		t.Errorf("unlimited page has %d plans and cursor %q, want 3 and none", len(page.Plans), page.NextCursor)
+	}
+
+	var records []string
+	err = store.EachSlot("2025-04-01", "2025-04-05", func(r models.SlotRecord) error {
+		records = append(records, r.Date+" "+r.Start+" "+r.TaskName)
+		return nil
+	})
+	must(t, err, "iterate slots")
+	want = []string{
+		"2025-04-01 09:00 write", "2025-04-01 13:00 read",
+		"2025-04-02 10:00 read",
+		"2025-04-05 09:00 write", "2025-04-05 13:00 read",
+	}
+	if !slices.Equal(records, want) {
+		t.Errorf("slots = %v, want %v", records, want)
+	}
+	stop := errors.New("stop")
+	calls := 0
+	err = store.EachSlot("2025-04-01", "2025-04-05", func(models.SlotRecord) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("EachSlot returned %v after %d calls, want fn's error after one", err, calls)
+	}
+}
+
+func testSlots(t *testing.T, store storage.Provider) {
+	addTasks(t, store, "write", "read")
+	const date = "2025-05-01"
+	savePlan(t, store, date, accepted(),
+		slot("09:00", "10:00", "write", constants.SlotStatusAccepted),
+		slot("10:00", "11:00", "read", constants.SlotStatusAccepted))
+
+	must(t, store.DeleteSlot(date, 1, "09:00", "write"), "delete slot")
+	plan, err := store.GetPlan(date)
+	must(t, err, "get plan")
+	if got := starts(plan.Slots); plan.Revision != 1 || !slices.Equal(got, []string{"10:00"}) {
+		t.Errorf("after delete, revision %d has slots %v, want revision 1 with [10:00]", plan.Revision, got)
+	}
+	if err := store.DeleteSlot(date, 1, "09:00", "write"); err == nil {
+		t.Error("deleting a deleted slot succeeded")
+	}
+	must(t, store.RestoreSlot(date, 1, "09:00", "write"), "restore slot")
+	plan, err = store.GetPlan(date)
+	must(t, err, "get plan")
+	if got := starts(plan.Slots); !slices.Equal(got, []string{"09:00", "10:00"}) {
+		t.Errorf("after restore, slots = %v", got)
+	}
+
+	const notified = "2025-05-01T08:55:00Z"
+	must(t, store.UpdateSlotNotificationTimestamp(date, 1, "09:00", "write", "start", notified), "mark slot notified")
+	must(t, store.UpdateSlotNotificationTimestamp(date, 1, "10:00", "read", "end", notified), "mark slot notified")
+	plan, err = store.GetPlan(date)
+	must(t, err, "get plan")
+	write, read := plan.Slots[0], plan.Slots[1]
+	if write.LastNotifiedStart == nil || *write.LastNotifiedStart != notified || write.LastNotifiedEnd != nil {
+		t.Errorf("write notified at %v/%v, want the start at %s", write.LastNotifiedStart, write.LastNotifiedEnd, notified)
+	}
+	if read.LastNotifiedEnd == nil || *read.LastNotifiedEnd != notified || read.LastNotifiedStart != nil {
+		t.Errorf("read notified at %v/%v, want the end at %s", read.LastNotifiedStart, read.LastNotifiedEnd, notified)
+	}
+
+	// Slots of a deleted revision are left alone
+	must(t, store.DeletePlan(date), "delete plan")
+	if err := store.DeleteSlot(date, 1, "10:00", "read"); err == nil {
+		t.Error("deleting a slot of a deleted plan succeeded")
+	}
+	if err := store.RestoreSlot(date, 1, "09:00", "write"); err == nil {
+		t.Error("restoring a slot of a deleted plan succeeded")
+	}
+}
+
+func testAmendments(t *testing.T, store storage.Provider) {
+	addTasks(t, store, "write", "email", "walk")
+	const date = "2025-05-02"
+	savePlan(t, store, date, accepted(),
+		slot("09:00", "10:00", "write", constants.SlotStatusAccepted),
+		slot("10:00", "11:00", "email", constants.SlotStatusAccepted))
+
+	moved, err := store.AmendPlan(models.PlanAmendment{
+		Date: date, Revision: 1, Kind: models.AmendmentMove, TaskID: "email",
+		FromStart: "10:00", ToStart: "14:00", ToEnd: "14:30", Reason: "call ran over",
+	})
+	must(t, err, "move slot")
+	if moved.ID == 0 || moved.FromEnd != "11:00" {
+		t.Errorf("expected the amendment's ID and previous end to be filled in, got %+v", moved)
+	}
+	_, err = store.AmendPlan(models.PlanAmendment{Date: date, Revision: 1, Kind: models.AmendmentAdd, TaskID: "walk", ToStart: "12:00", ToEnd: "12:30"})
+	must(t, err, "add slot")
+	_, err = store.AmendPlan(models.PlanAmendment{Date: date, Revision: 1, Kind: models.AmendmentRemove, TaskID: "write", FromStart: "09:00"})
+	must(t, err, "remove slot")
+
+	plan, err := store.GetPlan(date)
+	must(t, err, "get plan")
+	if got := starts(plan.Slots); plan.Revision != 1 || !slices.Equal(got, []string{"12:00", "14:00"}) {
+		t.Errorf("amended plan is revision %d with slots %v, want revision 1 with [12:00 14:00]", plan.Revision, got)
+	}
+	amendments, err := store.GetPlanAmendments(date)
+	must(t, err, "get amendments")
+	var kinds []string
+	for _, a := range amendments {
+		kinds = append(kinds, string(a.Kind))
+	}
+	if !slices.Equal(kinds, []string{"move", "add", "remove"}) {
+		t.Errorf("amendments = %v, want them oldest first", kinds)
+	}
+
+	// Only accepted plans are amended
+	savePlan(t, store, "2025-05-03", nil, slot("09:00", "10:00", "write", constants.SlotStatusPlanned))
+	if _, err := store.AmendPlan(models.PlanAmendment{Date: "2025-05-03", Revision: 1, Kind: models.AmendmentRemove, TaskID: "write", FromStart: "09:00"}); err == nil {
+		t.Error("amending an unaccepted plan succeeded")
+	}
+}
+
+func testDayTemplates(t *testing.T, store storage.Provider) {
+	addTasks(t, store, "write", "read", "walk")
+	now := time.Now().Truncate(time.Second)
+	workday := models.DayTemplate{ID: "t1", Name: "workday", CreatedAt: now, Slots: []models.TemplateSlot{
+		{Start: "09:00", End: "10:30", TaskID: "write"},
+		{Start: "13:00", End: "14:00", TaskID: "read"},
+	}}
+	must(t, store.SaveDayTemplate(workday), "save template")
+	must(t, store.SaveDayTemplate(models.DayTemplate{ID: "t2", Name: "sunday", CreatedAt: now, Slots: []models.TemplateSlot{
+		{Start: "10:00", End: "11:00", TaskID: "walk"},
+	}}), "save template")
+
+	got, err := store.GetDayTemplate("workday")
+	must(t, err, "get template")
+	if got.ID != "t1" || len(got.Slots) != 2 || got.Slots[0] != workday.Slots[0] || got.Slots[1] != workday.Slots[1] {
+		t.Errorf("template not saved as given: %+v", got)
+	}
+	if _, err := store.GetDayTemplate("missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("missing template: got %v, want sql.ErrNoRows", err)
+	}
+
+	// Saving again replaces the slots
+	workday.Slots = workday.Slots[1:]
+	must(t, store.SaveDayTemplate(workday), "save template")
+	got, err = store.GetDayTemplate("workday")
+	must(t, err, "get template")
+	if len(got.Slots) != 1 || got.Slots[0].TaskID != "read" {
+		t.Errorf("template slots = %+v, want only read", got.Slots)
+	}
+
+	all, err := store.GetAllDayTemplates()
+	must(t, err, "get templates")
+	if len(all) != 2 || all[0].Name != "sunday" || all[1].Name != "workday" || len(all[0].Slots) != 1 {
+		t.Errorf("templates = %+v, want both, ordered by name, with their slots", all)
+	}
+
+	must(t, store.DeleteDayTemplate("sunday"), "delete template")
+	if _, err := store.GetDayTemplate("sunday"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted template: got %v, want sql.ErrNoRows", err)
+	}
+	if err := store.DeleteDayTemplate("sunday"); err == nil {
+		t.Error("deleting a missing template succeeded")
+	}
+}
+
+func testSlotReminders(t *testing.T, store storage.Provider) {
+	addTasks(t, store, "write", "read")
+	now := time.Now().Truncate(time.Second)
+	reminders := []models.SlotReminder{
+		{ID: "r3", PlanDate: "2025-05-06", SlotStart: "09:00", TaskID: "write", OffsetMin: 5, CreatedAt: now},
+		{ID: "r2", PlanDate: "2025-05-05", SlotStart: "13:00", TaskID: "read", OffsetMin: 0, CreatedAt: now},
+		{ID: "r1", PlanDate: "2025-05-05", SlotStart: "09:00", TaskID: "write", OffsetMin: 5, Message: "notes", CreatedAt: now},
+		{ID: "r0", PlanDate: "2025-05-05", SlotStart: "09:00", TaskID: "write", OffsetMin: 15, CreatedAt: now},
+		{ID: "r9", PlanDate: "2025-05-09", SlotStart: "09:00", TaskID: "write", CreatedAt: now},
+	}
+	for _, r := range reminders {
+		must(t, store.AddSlotReminder(r), "add reminder")
+	}
+
+	got, err := store.GetSlotReminders("2025-05-05", "2025-05-06")
+	must(t, err, "get reminders")
+	var ids []string
+	for _, r := range got {
+		ids = append(ids, r.ID)
+	}
+	// By date, slot start, and the earliest reminder first
+	if !slices.Equal(ids, []string{"r0", "r1", "r2", "r3"}) {
+		t.Errorf("reminders = %v, want [r0 r1 r2 r3]", ids)
+	}
+	if got[1].Message != "notes" || got[1].OffsetMin != 5 || got[1].SentAt != nil {
+		t.Errorf("reminder not saved as given: %+v", got[1])
+	}
+
+	sent := now.Add(-time.Minute).UTC()
+	must(t, store.MarkSlotReminderSent("r1", sent), "mark reminder sent")
+	must(t, store.DeleteSlotReminder("r2"), "delete reminder")
+	got, err = store.GetSlotReminders("2025-05-05", "2025-05-05")
+	must(t, err, "get reminders")
+	if len(got) != 2 || got[1].SentAt == nil || !got[1].SentAt.Equal(sent) {
+		t.Errorf("reminders = %+v, want r0 and r1 sent at %v", got, sent)
+	}
+	if err := store.MarkSlotReminderSent("missing", sent); err == nil {
+		t.Error("marking a missing reminder sent succeeded")
+	}
+	if err := store.DeleteSlotReminder("r2"); err == nil {
+		t.Error("deleting a missing reminder succeeded")
+	}
+}
+
+func testNotificationLog(t *testing.T, store storage.Provider) {
+	base := time.Now().UTC().Truncate(time.Second)
+	entries := []models.NotificationLogEntry{
+		{SentAt: base.Add(-2 * time.Hour), Kind: "alert", AlertID: "a1", Message: "old", Channel: "tray", Success: true},
+		{SentAt: base.Add(-time.Hour), Kind: "block_start", PlanDate: "2025-05-07", SlotStart: "09:00", TaskID: "write", Message: "Write", Channel: "tray", Success: true},
+		{SentAt: base, Kind: "block_end", PlanDate: "2025-05-07", SlotStart: "09:00", TaskID: "write", Message: "Write done", Channel: "tray", Error: "no tray"},
+	}
+	for _, e := range entries {
+		must(t, store.AddNotificationLog(e), "log notification")
+	}
+
+	got, err := store.GetNotificationLog(base.Add(-90*time.Minute), 0)
+	must(t, err, "get notification log")
+	if len(got) != 2 || got[0].Message != "Write done" || got[1].Message != "Write" {
+		t.Fatalf("log = %+v, want the last two, newest first", got)
+	}
+	if got[0].ID == 0 || got[0].Success || got[0].Error != "no tray" || !got[0].SentAt.Equal(base) ||
+		got[1].PlanDate != "2025-05-07" || got[1].SlotStart != "09:00" || got[1].TaskID != "write" {
+		t.Errorf("log entries not saved as given: %+v", got)
+	}
+	got, err = store.GetNotificationLog(time.Time{}, 1)
+	must(t, err, "get notification log")
+	if len(got) != 1 || got[0].Message != "Write done" {
+		t.Errorf("limited log = %+v, want the newest entry", got)
+	}
+}
+
+func testOutbox(t *testing.T, store storage.Provider) {
+	base := time.Now().UTC().Truncate(time.Second)
+	items := []models.OutboxItem{
+		{CreatedAt: base.Add(-2 * time.Hour), Kind: "alert", AlertID: "a1", Message: "old", Attempts: 6, NextAttemptAt: base, LastError: "no tray", Status: models.OutboxFailed},
+		{CreatedAt: base.Add(-time.Hour), Kind: "block_start", PlanDate: "2025-05-07", SlotStart: "09:00", TaskID: "write", Message: "Write", Urgency: "critical", Sound: "bell", Attempts: 1, NextAttemptAt: base.Add(-time.Minute), LastError: "no tray", Status: models.OutboxPending},
+		{CreatedAt: base, Kind: "block_end", Message: "Write done", Attempts: 1, NextAttemptAt: base.Add(time.Minute), Status: models.OutboxPending},
+	}
+	for _, item := range items {
+		must(t, store.AddOutboxItem(item), "queue notification")
+	}
+
+	due, err := store.GetDueOutboxItems(base, 0)
+	must(t, err, "get due outbox items")
+	if len(due) != 1 || due[0].Message != "Write" {
+		t.Fatalf("due = %+v, want only the pending item whose attempt is due", due)
+	}
+	item := due[0]
+	if item.ID == 0 || item.PlanDate != "2025-05-07" || item.SlotStart != "09:00" || item.TaskID != "write" ||
+		item.Urgency != "critical" || item.Sound != "bell" || item.LastError != "no tray" || !item.CreatedAt.Equal(base.Add(-time.Hour)) {
+		t.Errorf("outbox item not saved as given: %+v", item)
+	}
+
+	item.Attempts, item.NextAttemptAt, item.LastError = 2, base.Add(2*time.Minute), "still no tray"
+	must(t, store.UpdateOutboxItem(item), "update outbox item")
+	if due, err := store.GetDueOutboxItems(base.Add(2*time.Minute), 1); err != nil || len(due) != 1 || due[0].Message != "Write" ||
+		due[0].Attempts != 2 || due[0].LastError != "still no tray" {
+		t.Errorf("due after update = %+v, %v, want the updated item first", due, err)
+	}
+
+	all, err := store.GetOutboxItems(base.Add(-90 * time.Minute))
+	must(t, err, "get outbox items")
+	if len(all) != 2 || all[0].Message != "Write done" || all[1].Message != "Write" {
+		t.Errorf("outbox = %+v, want the last two, newest first", all)
+	}
+
+	must(t, store.DeleteOutboxItem(item.ID), "delete outbox item")
+	if err := store.DeleteOutboxItem(item.ID); err == nil {
+		t.Error("deleting a missing outbox item succeeded")
+	}
+	if err := store.UpdateOutboxItem(item); err == nil {
+		t.Error("updating a missing outbox item succeeded")
+	}
+	if all, err := store.GetOutboxItems(time.Time{}); err != nil || len(all) != 2 || all[1].Status != models.OutboxFailed {
+		t.Errorf("outbox after delete = %+v, %v, want the other two", all, err)
+	}
+}
+
+func testNotificationClaims(t *testing.T, store storage.Provider) {
+	base := time.Now().UTC().Truncate(time.Second)
+	key := models.SlotNotificationKey(constants.NotificationKindBlockStart, "2025-05-07", "09:00", "write")
+
+	// Of several runs claiming the same notification at once, only one wins
+	var wg sync.WaitGroup
+	var claimed atomic.Int32
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, err := store.ClaimNotification(key, base); err != nil {
+				t.Errorf("failed to claim notification: %v", err)
+			} else if ok {
+				claimed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := claimed.Load(); n != 1 {
+		t.Fatalf("notification claimed %d times, want once", n)
+	}
+
+	other := models.AlertNotificationKey("a1", base)
+	if ok, err := store.ClaimNotification(other, base.Add(-time.Hour)); err != nil || !ok {
+		t.Fatalf("claiming another notification = %v, %v, want claimed", ok, err)
+	}
+	must(t, store.DeleteNotificationClaims(base.Add(-time.Minute)), "delete notification claims")
+	if ok, err := store.ClaimNotification(other, base); err != nil || !ok {
+		t.Errorf("claiming a deleted claim again = %v, %v, want claimed", ok, err)
+	}
+	if ok, err := store.ClaimNotification(key, base); err != nil || ok {
+		t.Errorf("claiming a kept claim again = %v, %v, want not claimed", ok, err)
+	}
+}
+
+func testHistory(t *testing.T, store storage.Provider) {
+	addTasks(t, store, "write", "read")
+	const first, second = "2025-06-01", "2025-06-02"
+	start, end := "09:00", "09:50"
+	savePlan(t, store, first, accepted(), models.Slot{Start: "09:00", End: "10:00", TaskID: "write", Status: constants.SlotStatusDone,
+		ActualStart: &start, ActualEnd: &end, Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack, Note: "good"}})
+	savePlan(t, store, second, accepted(),
+		models.Slot{Start: "09:00", End: "10:00", TaskID: "write", Status: constants.SlotStatusSkipped,
+			Feedback: &models.Feedback{Rating: constants.FeedbackUnnecessary}},
+		slot("10:00", "11:00", "read", constants.SlotStatusAccepted))
+
+	feedback, err := store.GetTaskFeedbackHistory("write", 10)
+	must(t, err, "get feedback history")
+	if len(feedback) != 2 || feedback[0].Date != second || feedback[1].Date != first {
+		t.Fatalf("feedback = %+v, want both days, most recent first", feedback)
+	}
+	if f := feedback[1]; f.Rating != constants.FeedbackOnTrack || f.Note != "good" || f.ActualStart != "09:00" ||
+		f.ActualEnd != "09:50" || f.ActualDuration != 50 {
+		t.Errorf("feedback entry = %+v", f)
+	}
+	if feedback, err := store.GetTaskFeedbackHistory("write", 1); err != nil || len(feedback) != 1 {
+		t.Errorf("limited feedback = %+v, %v, want one entry", feedback, err)
+	}
+
+	slots, err := store.GetTaskSlotHistory("write", 0)
+	must(t, err, "get slot history")
+	if len(slots) != 2 || slots[0].Date != second || slots[0].Status != constants.SlotStatusSkipped ||
+		slots[1].Rating != constants.FeedbackOnTrack || slots[1].Note != "good" {
+		t.Errorf("slot history = %+v, want both days, newest first", slots)
+	}
+	// A newer revision takes the day's slots with it
+	savePlan(t, store, second, accepted(), slot("10:00", "11:00", "read", constants.SlotStatusAccepted))
+	slots, err = store.GetTaskSlotHistory("write", 0)
+	must(t, err, "get slot history")
+	if len(slots) != 2 || slots[0].Revision != 1 {
+		t.Errorf("slot history = %+v, want the day's slot from revision 1, the last to schedule it", slots)
+	}
+}
+
+func testSummaries(t *testing.T, store storage.Provider) {
+	addTasks(t, store, "write")
+	reading := newTask("read", "read")
+	reading.Context = "home"
+	must(t, store.AddTask(reading), "add task read")
+	const day = "2025-06-10"
+	savePlan(t, store, day, accepted(),
+		models.Slot{Start: "09:00", End: "10:00", TaskID: "write", Status: constants.SlotStatusDone,
+			Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack}},
+		slot("10:00", "10:30", "read", constants.SlotStatusAccepted),
+		slot("11:00", "11:30", "write", constants.SlotStatusAccepted))
+	savePlan(t, store, "2025-06-11", nil, slot("09:00", "09:30", "read", constants.SlotStatusPlanned))
+	habit := models.Habit{ID: "h1", Name: "Stretch", CreatedAt: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)}
+	must(t, store.AddHabit(habit), "add habit")
+	must(t, store.AddHabitEntry(models.HabitEntry{ID: "e1", HabitID: "h1", Day: day, CreatedAt: time.Now(), UpdatedAt: time.Now()}), "add habit entry")
+
+	summaries, err := store.GetDaySummaries("2025-06-09", "2025-06-12")
+	must(t, err, "get day summaries")
+	want := []models.DaySummary{
+		{Date: day, HasPlan: true, Accepted: true, TotalSlots: 3, SlotsWithFeedback: 1, HabitsCompleted: 1},
+		{Date: "2025-06-11", HasPlan: true, TotalSlots: 1},
+	}
+	if fmt.Sprint(summaries) != fmt.Sprint(want) {
+		t.Errorf("summaries = %+v, want %+v", summaries, want)
+	}
+
+	stats, err := store.GetTaskStats(day, "2025-06-11")
+	must(t, err, "get task stats")
+	if len(stats) != 2 {
+		t.Fatalf("stats = %+v, want write and read", stats)
+	}
+	write, read := stats[0], stats[1]
+	if write.TaskID != "write" || write.TaskName != "write" || write.PlannedSlots != 2 || write.PlannedMinutes != 90 ||
+		write.DoneSlots != 1 || write.DoneMinutes != 60 || write.AcceptedSlots != 2 || write.AcceptedDoneSlots != 1 {
+		t.Errorf("write stats = %+v", write)
+	}
+	if read.TaskID != "read" || read.Context != "home" || read.PlannedSlots != 2 || read.PlannedMinutes != 60 || read.AcceptedSlots != 1 {
+		t.Errorf("read stats = %+v", read)
+	}
+
+	categories, err := store.GetHabitCategoryStats(day, "2025-06-11")
+	must(t, err, "get habit stats")
+	if len(categories) != 1 || categories[0].Habits != 1 || categories[0].DoneDays != 1 || categories[0].DueDays != 2 {
+		t.Errorf("habit stats = %+v, want one habit done 1 of 2 days", categories)
+	}
+}
+
+func testMetrics(t *testing.T, store storage.Provider) {
+	computed := time.Now().UTC().Truncate(time.Second)
+	metrics := []models.DailyMetrics{
+		{Date: "2025-06-21", HasPlan: true, PlannedSlots: 2, ComputedAt: computed},
+		{Date: "2025-06-20", HasPlan: true, Accepted: true, PlannedSlots: 4, DoneSlots: 3, SkippedSlots: 1,
+			TrackedSlots: 2, DriftMinutes: 15, OnTrack: 2, TooMuch: 1, ComputedAt: computed},
+	}
+	for _, m := range metrics {
+		must(t, store.SaveDailyMetrics(m), "save metrics")
+	}
+	got, err := store.GetDailyMetrics("2025-06-01", "2025-06-30")
+	must(t, err, "get metrics")
+	if len(got) != 2 || got[0].Date != "2025-06-20" || got[1].Date != "2025-06-21" {
+		t.Fatalf("metrics = %+v, want both days in date order", got)
+	}
+	g := got[0]
+	if !g.ComputedAt.Equal(computed) {
+		t.Errorf("computed at %v, want %v", g.ComputedAt, computed)
+	}
+	g.ComputedAt = computed
+	if g != metrics[1] {
+		t.Errorf("metrics not saved as given: %+v", g)
+	}
+
+	// Saving a day again replaces its metrics
+	metrics[0].DoneSlots = 2
+	must(t, store.SaveDailyMetrics(metrics[0]), "save metrics")
+	got, err = store.GetDailyMetrics("2025-06-21", "2025-06-21")
+	must(t, err, "get metrics")
+	if len(got) != 1 || got[0].DoneSlots != 2 {
+		t.Errorf("metrics = %+v, want the day replaced", got)
+	}
+}
@@ -0,0 +1,183 @@
+package storagetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func testPurge(t *testing.T, store storage.Provider) {
+	addTasks(t, store, "used", "unused")
+	savePlan(t, store, "2025-10-01", nil, slot("09:00", "10:00", "used", constants.SlotStatusPlanned))
+	savePlan(t, store, "2025-10-02", nil, slot("09:00", "10:00", "used", constants.SlotStatusPlanned))
+	must(t, store.DeletePlan("2025-10-02"), "delete plan")
+	for _, id := range []string{"used", "unused"} {
+		must(t, store.DeleteTask(id), "delete task")
+	}
+	now := time.Now()
+	must(t, store.AddHabit(models.Habit{ID: "h1", Name: "Stretch", CreatedAt: now}), "add habit")
+	must(t, store.AddHabitEntry(models.HabitEntry{ID: "e1", HabitID: "h1", Day: "2025-10-01", CreatedAt: now, UpdatedAt: now}), "add habit entry")
+	must(t, store.DeleteHabit("h1"), "delete habit")
+
+	summary, err := store.PurgeDeleted(now.Add(-24*time.Hour), models.PurgeKinds, false)
+	must(t, err, "purge")
+	if summary != (models.PurgeSummary{}) {
+		t.Errorf("purged %+v from before anything was deleted, want nothing", summary)
+	}
+
+	cutoff := now.Add(time.Minute)
+	want := models.PurgeSummary{Tasks: 1, Plans: 1, Slots: 1, Habits: 1, HabitEntries: 1, KeptTasks: 1}
+	summary, err = store.PurgeDeleted(cutoff, models.PurgeKinds, true)
+	must(t, err, "purge")
+	if summary != want {
+		t.Errorf("dry run = %+v, want %+v", summary, want)
+	}
+	if tasks, _ := store.GetAllTasksIncludingDeleted(); len(tasks) != 2 {
+		t.Errorf("dry run removed tasks; %d left", len(tasks))
+	}
+
+	summary, err = store.PurgeDeleted(cutoff, []models.PurgeKind{models.PurgeHabits}, false)
+	must(t, err, "purge")
+	if summary != (models.PurgeSummary{Habits: 1, HabitEntries: 1}) {
+		t.Errorf("purging habits = %+v, want the habit and its entry", summary)
+	}
+	summary, err = store.PurgeDeleted(cutoff, models.PurgeKinds, false)
+	must(t, err, "purge")
+	want.Habits, want.HabitEntries = 0, 0
+	if summary != want {
+		t.Errorf("purge = %+v, want %+v", summary, want)
+	}
+	if err := store.RestorePlan("2025-10-02"); err == nil {
+		t.Error("restoring a purged plan succeeded")
+	}
+	tasks, err := store.GetAllTasksIncludingDeleted()
+	must(t, err, "get tasks")
+	if len(tasks) != 1 || tasks[0].ID != "used" {
+		t.Errorf("tasks = %+v, want only the one a plan still uses", tasks)
+	}
+
+	// Purging one item ignores its age, but not what refers to it
+	if err := store.PurgeItem(models.TrashTask, "used"); err == nil {
+		t.Error("purging a task a slot refers to succeeded")
+	}
+	must(t, store.DeletePlan("2025-10-01"), "delete plan")
+	must(t, store.PurgeItem(models.TrashPlan, "2025-10-01"), "purge plan")
+	must(t, store.PurgeItem(models.TrashTask, "used"), "purge task")
+	if tasks, _ := store.GetAllTasksIncludingDeleted(); len(tasks) != 0 {
+		t.Errorf("tasks = %+v, want none", tasks)
+	}
+	if err := store.PurgeItem(models.TrashTask, "used"); err == nil {
+		t.Error("purging a purged task succeeded")
+	}
+	must(t, store.AddOTEntry(models.OTEntry{ID: "o1", Day: "2025-10-03", Title: "Rest", CreatedAt: now, UpdatedAt: now}), "add OT entry")
+	if err := store.PurgeItem(models.TrashOTEntry, "2025-10-03"); err == nil {
+		t.Error("purging a live OT entry succeeded")
+	}
+}
+
+func testSearch(t *testing.T, store storage.Provider) {
+	addTasks(t, store, "run")
+	task, err := store.GetTask("run")
+	must(t, err, "get task")
+	task.Name = "Morning Run"
+	must(t, store.UpdateTask(task), "update task")
+	now := time.Now()
+	must(t, store.AddHabit(models.Habit{ID: "h1", Name: "Running log", CreatedAt: now}), "add habit")
+	must(t, store.AddOTEntry(models.OTEntry{ID: "o1", Day: "2025-11-01", Title: "Finish report",
+		Note: "then a run by the river", CreatedAt: now, UpdatedAt: now}), "add OT entry")
+	savePlan(t, store, "2025-11-01", nil, models.Slot{Start: "07:00", End: "07:30", TaskID: "run", Status: constants.SlotStatusDone,
+		Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack, Note: "Legs felt heavy on the hills"}})
+
+	results, err := store.Search("run", 0)
+	must(t, err, "search")
+	kinds := make(map[constants.SearchKind]string)
+	for _, r := range results {
+		kinds[r.Kind] = r.ID
+	}
+	want := map[constants.SearchKind]string{constants.SearchKindTask: "run", constants.SearchKindHabit: "h1", constants.SearchKindOT: "o1"}
+	if len(kinds) != len(want) {
+		t.Errorf("results for run = %+v, want a task, a habit and an OT entry", results)
+	}
+	for kind, id := range want {
+		if kinds[kind] != id {
+			t.Errorf("%s result = %q, want %q", kind, kinds[kind], id)
+		}
+	}
+
+	results, err = store.Search("HILLS!", 0)
+	must(t, err, "search")
+	if len(results) != 1 || results[0].Kind != constants.SearchKindNote || results[0].Title != "Morning Run" || results[0].Date != "2025-11-01" {
+		t.Errorf("results for hills = %+v, want the slot's note", results)
+	}
+	if results, err := store.Search("morning hills", 0); err != nil || len(results) != 0 {
+		t.Errorf("results for two words never together = %+v, %v, want none", results, err)
+	}
+	if results, err := store.Search("run", 1); err != nil || len(results) != 1 {
+		t.Errorf("limited results = %+v, %v, want one", results, err)
+	}
+	if results, err := store.Search(` "* `, 0); err != nil || len(results) != 0 {
+		t.Errorf("results for an empty query = %+v, %v, want none", results, err)
+	}
+
+	// Deleted records drop out
+	must(t, store.DeleteTask("run"), "delete task")
+	must(t, store.DeleteHabit("h1"), "delete habit")
+	must(t, store.DeleteOTEntry("2025-11-01"), "delete OT entry")
+	results, err = store.Search("run", 0)
+	must(t, err, "search")
+	if len(results) != 0 {
+		t.Errorf("results after delete = %+v, want none", results)
+	}
+}
+
+func testWithTx(t *testing.T, store storage.Provider) {
+	failed := errors.New("failed")
+	err := store.WithTx(func(tx storage.Provider) error {
+		must(t, tx.AddTask(newTask("write", "Write")), "add task")
+		if _, err := tx.GetTask("write"); err != nil {
+			t.Errorf("the transaction doesn't see its own write: %v", err)
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("WithTx returned %v, want fn's error", err)
+	}
+	if _, err := store.GetTask("write"); err == nil {
+		t.Error("the task of a rolled back transaction was saved")
+	}
+
+	// A failed nested transaction only undoes its own writes
+	err = store.WithTx(func(tx storage.Provider) error {
+		if err := tx.AddTask(newTask("read", "Read")); err != nil {
+			return err
+		}
+		if err := tx.WithTx(func(inner storage.Provider) error {
+			must(t, inner.AddTask(newTask("walk", "Walk")), "add task")
+			return failed
+		}); !errors.Is(err, failed) {
+			t.Errorf("nested WithTx returned %v, want fn's error", err)
+		}
+		// The transaction is still good after a failed write
+		stale := newTask("read", "Read")
+		stale.Version = 9
+		if err := tx.UpdateTask(stale); !errors.Is(err, models.ErrConflict) {
+			t.Errorf("stale update: got %v, want a conflict", err)
+		}
+		savePlan(t, tx, "2025-12-01", accepted(), slot("09:00", "10:00", "read", constants.SlotStatusAccepted))
+		return nil
+	})
+	must(t, err, "run transaction")
+	if _, err := store.GetTask("read"); err != nil {
+		t.Errorf("the committed task is missing: %v", err)
+	}
+	if _, err := store.GetTask("walk"); err == nil {
+		t.Error("the task of a rolled back nested transaction was saved")
+	}
+	if plan, err := store.GetPlan("2025-12-01"); err != nil || len(plan.Slots) != 1 {
+		t.Errorf("the committed plan = %+v, %v", plan, err)
+	}
+}
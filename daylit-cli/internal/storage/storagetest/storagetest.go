@@ -0,0 +1,113 @@
+// Package storagetest holds the conformance suite every storage.Provider
+// runs, so that the backends keep to the same semantics: the same defaults,
+// the same ordering, the same errors and the same soft-delete rules.
+package storagetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+// Run runs the suite against the stores newStore returns. Each test gets a
+// store of its own, initialized and holding nothing but the default
+// settings; newStore is responsible for closing it when the test ends.
+func Run(t *testing.T, newStore func(t *testing.T) storage.Provider) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, store storage.Provider)
+	}{
+		{"Settings", testSettings},
+		{"OTSettings", testOTSettings},
+		{"Tasks", testTasks},
+		{"DeleteTaskCascade", testDeleteTaskCascade},
+		{"Projects", testProjects},
+		{"Pools", testPools},
+		{"Inbox", testInbox},
+		{"Plans", testPlans},
+		{"PlanRevisions", testPlanRevisions},
+		{"PlansRange", testPlansRange},
+		{"Slots", testSlots},
+		{"Amendments", testAmendments},
+		{"DayTemplates", testDayTemplates},
+		{"Habits", testHabits},
+		{"HabitEntries", testHabitEntries},
+		{"OTEntries", testOTEntries},
+		{"Alerts", testAlerts},
+		{"Vacations", testVacations},
+		{"SlotReminders", testSlotReminders},
+		{"NotificationLog", testNotificationLog},
+		{"Outbox", testOutbox},
+		{"NotificationClaims", testNotificationClaims},
+		{"History", testHistory},
+		{"Summaries", testSummaries},
+		{"Metrics", testMetrics},
+		{"Purge", testPurge},
+		{"Search", testSearch},
+		{"WithTx", testWithTx},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStore(t))
+		})
+	}
+}
+
+// must fails the test when err is set, saying what was being done
+func must(t *testing.T, err error, action string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("failed to %s: %v", action, err)
+	}
+}
+
+// newTask returns an active daily flexible task
+func newTask(id, name string) models.Task {
+	return models.Task{
+		ID:          id,
+		Name:        name,
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+		Priority:    3,
+		Active:      true,
+	}
+}
+
+// addTasks adds a task for each ID, named after it
+func addTasks(t *testing.T, store storage.Provider, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		must(t, store.AddTask(newTask(id, id)), "add task "+id)
+	}
+}
+
+// slot returns a slot of taskID from start to end
+func slot(start, end, taskID string, status models.SlotStatus) models.Slot {
+	return models.Slot{Start: start, End: end, TaskID: taskID, Status: status}
+}
+
+// accepted returns an acceptance timestamp
+func accepted() *string {
+	at := time.Now().UTC().Format(time.RFC3339)
+	return &at
+}
+
+// savePlan saves a plan for date with the slots, accepted when acceptedAt is
+// set
+func savePlan(t *testing.T, store storage.Provider, date string, acceptedAt *string, slots ...models.Slot) {
+	t.Helper()
+	must(t, store.SavePlan(models.DayPlan{Date: date, AcceptedAt: acceptedAt, Slots: slots}), "save plan "+date)
+}
+
+// starts returns the start times of the slots
+func starts(slots []models.Slot) []string {
+	out := make([]string, 0, len(slots))
+	for _, s := range slots {
+		out = append(out, s.Start)
+	}
+	return out
+}
@@ -0,0 +1,344 @@
+package storagetest
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func testSettings(t *testing.T, store storage.Provider) {
+	settings, err := store.GetSettings()
+	must(t, err, "get settings")
+	// Init saves the defaults of the settings it knows
+	defaults := []struct {
+		name      string
+		got, want any
+	}{
+		{"day start", settings.DayStart, constants.DefaultDayStart},
+		{"day end", settings.DayEnd, constants.DefaultDayEnd},
+		{"default block", settings.DefaultBlockMin, constants.DefaultBlockMin},
+		{"notifications", settings.NotificationsEnabled, constants.DefaultNotificationsEnabled},
+		{"notify block start", settings.NotifyBlockStart, constants.DefaultNotifyBlockStart},
+		{"notify block end", settings.NotifyBlockEnd, constants.DefaultNotifyBlockEnd},
+		{"block start offset", settings.BlockStartOffsetMin, constants.DefaultBlockStartOffsetMin},
+		{"block end offset", settings.BlockEndOffsetMin, constants.DefaultBlockEndOffsetMin},
+		{"grace period", settings.NotificationGracePeriodMin, constants.DefaultNotificationGracePeriodMin},
+		{"timezone", settings.Timezone, constants.DefaultTimezone},
+		{"theme", settings.Theme, constants.DefaultTheme},
+		{"morning plan", settings.MorningPlan, constants.DefaultMorningPlan},
+	}
+	for _, d := range defaults {
+		if d.got != d.want {
+			t.Errorf("default %s = %v, want %v", d.name, d.got, d.want)
+		}
+	}
+
+	settings.DayStart = "06:30"
+	settings.DayEnd = "21:00"
+	settings.DefaultBlockMin = 45
+	settings.NotificationsEnabled = !settings.NotificationsEnabled
+	settings.Timezone = "Europe/Berlin"
+	must(t, store.SaveSettings(settings), "save settings")
+	saved, err := store.GetSettings()
+	must(t, err, "get settings")
+	if saved.DayStart != "06:30" || saved.DayEnd != "21:00" || saved.DefaultBlockMin != 45 ||
+		saved.NotificationsEnabled != settings.NotificationsEnabled || saved.Timezone != "Europe/Berlin" {
+		t.Errorf("settings not saved: %+v", saved)
+	}
+
+	// A stale save is a conflict
+	stale := settings
+	saved.DayStart = "07:00"
+	must(t, store.SaveSettings(saved), "save settings")
+	stale.DayStart = "08:00"
+	if err := store.SaveSettings(stale); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("stale save: got %v, want a conflict", err)
+	}
+}
+
+func testOTSettings(t *testing.T, store storage.Provider) {
+	settings, err := store.GetOTSettings()
+	must(t, err, "get OT settings")
+	want := models.OTSettings{PromptOnEmpty: true, StrictMode: true, DefaultLogDays: 14}
+	if settings != want {
+		t.Errorf("default OT settings = %+v, want %+v", settings, want)
+	}
+
+	want = models.OTSettings{PromptOnEmpty: false, StrictMode: false, DefaultLogDays: 30}
+	must(t, store.SaveOTSettings(want), "save OT settings")
+	settings, err = store.GetOTSettings()
+	must(t, err, "get OT settings")
+	if settings != want {
+		t.Errorf("OT settings = %+v, want %+v", settings, want)
+	}
+
+	// OT settings live apart from the others
+	general, err := store.GetSettings()
+	must(t, err, "get settings")
+	if general.DayStart != constants.DefaultDayStart {
+		t.Errorf("saving OT settings changed day start to %q", general.DayStart)
+	}
+}
+
+func testTasks(t *testing.T, store storage.Provider) {
+	offset := 10
+	task := models.Task{
+		ID:            "write",
+		Name:          "Write",
+		Kind:          constants.TaskKindFlexible,
+		DurationMin:   90,
+		EarliestStart: "08:00",
+		LatestEnd:     "12:00",
+		Recurrence: models.Recurrence{
+			Type:        constants.RecurrenceWeekly,
+			WeekdayMask: []time.Weekday{time.Monday, time.Wednesday, time.Friday},
+			Start:       "2025-01-01",
+			Until:       "2025-12-31",
+		},
+		Priority:        2,
+		EnergyBand:      constants.EnergyHigh,
+		Active:          true,
+		Context:         "office",
+		NiceToHave:      true,
+		NotifyOffsetMin: &offset,
+		NotifyMessage:   "Time to write",
+	}
+	must(t, store.AddTask(task), "add task")
+	appointment := models.Task{
+		ID: "dentist", Name: "Dentist", Kind: constants.TaskKindAppointment, DurationMin: 60,
+		FixedStart: "14:00", FixedEnd: "15:00", PrepMin: 10, TravelMin: 20,
+		Recurrence: models.Recurrence{Type: constants.RecurrenceAdHoc}, Priority: 1, Active: true,
+	}
+	must(t, store.AddTask(appointment), "add task")
+
+	got, err := store.GetTask("write")
+	must(t, err, "get task")
+	if got.Name != task.Name || got.Kind != task.Kind || got.DurationMin != 90 ||
+		got.EarliestStart != "08:00" || got.LatestEnd != "12:00" || got.Priority != 2 ||
+		got.EnergyBand != constants.EnergyHigh || !got.Active || got.Context != "office" || !got.NiceToHave ||
+		got.NotifyMessage != "Time to write" || got.DeletedAt != nil {
+		t.Errorf("task not saved as given: %+v", got)
+	}
+	if got.NotifyOffsetMin == nil || *got.NotifyOffsetMin != 10 {
+		t.Errorf("notify offset = %v, want 10", got.NotifyOffsetMin)
+	}
+	rec := got.Recurrence
+	if rec.Type != constants.RecurrenceWeekly || len(rec.WeekdayMask) != 3 || rec.WeekdayMask[1] != time.Wednesday ||
+		rec.Start != "2025-01-01" || rec.Until != "2025-12-31" {
+		t.Errorf("recurrence not saved as given: %+v", rec)
+	}
+	if got.Version != 1 {
+		t.Errorf("version after add = %d, want 1", got.Version)
+	}
+	dentist, err := store.GetTask("dentist")
+	must(t, err, "get task")
+	if dentist.FixedStart != "14:00" || dentist.FixedEnd != "15:00" || dentist.PrepMin != 10 || dentist.TravelMin != 20 ||
+		dentist.NotifyOffsetMin != nil {
+		t.Errorf("appointment not saved as given: %+v", dentist)
+	}
+	if _, err := store.GetTask("missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("missing task: got %v, want sql.ErrNoRows", err)
+	}
+
+	got.DurationMin = 60
+	got.NotifyOffsetMin = nil
+	must(t, store.UpdateTask(got), "update task")
+	updated, err := store.GetTask("write")
+	must(t, err, "get task")
+	if updated.DurationMin != 60 || updated.NotifyOffsetMin != nil || updated.Version != 2 {
+		t.Errorf("update not saved: duration %d, offset %v, version %d", updated.DurationMin, updated.NotifyOffsetMin, updated.Version)
+	}
+	if err := store.UpdateTask(got); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("stale update: got %v, want a conflict", err)
+	}
+
+	must(t, store.DeleteTask("dentist"), "delete task")
+	if _, err := store.GetTask("dentist"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted task: got %v, want sql.ErrNoRows", err)
+	}
+	live, err := store.GetAllTasks()
+	must(t, err, "get tasks")
+	if len(live) != 1 || live[0].ID != "write" {
+		t.Errorf("live tasks = %+v, want only write", live)
+	}
+	all, err := store.GetAllTasksIncludingDeleted()
+	must(t, err, "get tasks")
+	if len(all) != 2 {
+		t.Fatalf("got %d tasks including deleted, want 2", len(all))
+	}
+	for _, task := range all {
+		if (task.DeletedAt != nil) != (task.ID == "dentist") {
+			t.Errorf("task %s deleted at %v", task.ID, task.DeletedAt)
+		}
+	}
+	if err := store.DeleteTask("dentist"); err == nil {
+		t.Error("deleting a deleted task succeeded")
+	}
+
+	must(t, store.RestoreTask("dentist"), "restore task")
+	if _, err := store.GetTask("dentist"); err != nil {
+		t.Errorf("restored task: %v", err)
+	}
+	if err := store.RestoreTask("dentist"); err == nil {
+		t.Error("restoring a live task succeeded")
+	}
+}
+
+func testDeleteTaskCascade(t *testing.T, store storage.Provider) {
+	addTasks(t, store, "write", "read")
+	settings, err := store.GetSettings()
+	must(t, err, "get settings")
+
+	// Days are relative to now so that DeleteTask's own clock sees them ahead
+	now := time.Now()
+	past := now.AddDate(0, 0, -1).Format(constants.DateFormat)
+	future := now.AddDate(0, 0, 1).Format(constants.DateFormat)
+	later := now.AddDate(0, 0, 2).Format(constants.DateFormat)
+	savePlan(t, store, past, accepted(), slot("09:00", "10:00", "write", constants.SlotStatusAccepted))
+	savePlan(t, store, future, accepted(),
+		slot("09:00", "10:00", "write", constants.SlotStatusAccepted),
+		slot("10:00", "11:00", "read", constants.SlotStatusAccepted))
+	savePlan(t, store, later, nil, slot("09:00", "10:00", "write", constants.SlotStatusPlanned))
+
+	// With task_delete_slots "keep" the slots stay
+	settings.TaskDeleteSlots = constants.TaskDeleteSlotsKeep
+	must(t, store.SaveSettings(settings), "save settings")
+	must(t, store.DeleteTask("write"), "delete task")
+	if plan, err := store.GetPlan(future); err != nil || len(plan.Slots) != 2 {
+		t.Errorf("task_delete_slots keep removed slots: %v %v", starts(plan.Slots), err)
+	}
+	must(t, store.RestoreTask("write"), "restore task")
+
+	removed, err := store.DeleteTaskCascade("write", true, now)
+	must(t, err, "delete task")
+	if removed != 1 {
+		t.Errorf("removed %d slots, want the one upcoming in an accepted plan", removed)
+	}
+	for date, want := range map[string][]string{past: {"09:00"}, future: {"10:00"}, later: {"09:00"}} {
+		plan, err := store.GetPlan(date)
+		must(t, err, "get plan")
+		if got := starts(plan.Slots); !slices.Equal(got, want) {
+			t.Errorf("%s slots = %v, want %v", date, got, want)
+		}
+	}
+
+	must(t, store.RestoreTask("write"), "restore task")
+	plan, err := store.GetPlan(future)
+	must(t, err, "get plan")
+	if got := starts(plan.Slots); !slices.Equal(got, []string{"09:00", "10:00"}) {
+		t.Errorf("after restore, slots = %v, want both back", got)
+	}
+
+	// task_delete_slots "remove" makes DeleteTask cascade too
+	settings, err = store.GetSettings()
+	must(t, err, "get settings")
+	settings.TaskDeleteSlots = constants.TaskDeleteSlotsRemove
+	must(t, store.SaveSettings(settings), "save settings")
+	must(t, store.DeleteTask("read"), "delete task")
+	plan, err = store.GetPlan(future)
+	must(t, err, "get plan")
+	if got := starts(plan.Slots); !slices.Equal(got, []string{"09:00"}) {
+		t.Errorf("after delete with task_delete_slots remove, slots = %v, want [09:00]", got)
+	}
+}
+
+func testProjects(t *testing.T, store storage.Provider) {
+	now := time.Now().Truncate(time.Second)
+	for _, p := range []models.Project{
+		{ID: "p2", Name: "thesis", TargetHoursPerWeek: 10.5, Color: "#336699", CreatedAt: now},
+		{ID: "p1", Name: "garden", TargetHoursPerWeek: 2, CreatedAt: now},
+	} {
+		must(t, store.AddProject(p), "add project")
+	}
+	if err := store.AddProject(models.Project{ID: "p3", Name: "thesis", CreatedAt: now}); err == nil {
+		t.Error("adding a second project of the same name succeeded")
+	}
+
+	got, err := store.GetProjectByName("thesis")
+	must(t, err, "get project")
+	if got.ID != "p2" || got.TargetHoursPerWeek != 10.5 || got.Color != "#336699" || !got.CreatedAt.Equal(now) {
+		t.Errorf("project not saved as given: %+v", got)
+	}
+	if _, err := store.GetProjectByName("missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("missing project: got %v, want sql.ErrNoRows", err)
+	}
+
+	all, err := store.GetAllProjects()
+	must(t, err, "get projects")
+	if len(all) != 2 || all[0].Name != "garden" || all[1].Name != "thesis" {
+		t.Errorf("projects = %+v, want them ordered by name", all)
+	}
+}
+
+func testPools(t *testing.T, store storage.Provider) {
+	now := time.Now().Truncate(time.Second)
+	for _, p := range []models.TaskPool{
+		{ID: "pool-2", Name: "workout", Strategy: constants.PoolStrategyLeastRecent, CreatedAt: now},
+		{ID: "pool-1", Name: "reading", Strategy: constants.PoolStrategyRoundRobin, CreatedAt: now},
+	} {
+		must(t, store.AddPool(p), "add pool")
+	}
+	if err := store.AddPool(models.TaskPool{ID: "pool-3", Name: "dice", Strategy: "random", CreatedAt: now}); err == nil {
+		t.Error("adding a pool with an unknown strategy succeeded")
+	}
+
+	got, err := store.GetPoolByName("workout")
+	must(t, err, "get pool")
+	if got.ID != "pool-2" || got.Strategy != constants.PoolStrategyLeastRecent || !got.CreatedAt.Equal(now) {
+		t.Errorf("pool not saved as given: %+v", got)
+	}
+	if _, err := store.GetPoolByName("missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("missing pool: got %v, want sql.ErrNoRows", err)
+	}
+	all, err := store.GetAllPools()
+	must(t, err, "get pools")
+	if len(all) != 2 || all[0].Name != "reading" || all[1].Name != "workout" {
+		t.Errorf("pools = %+v, want them ordered by name", all)
+	}
+
+	run := newTask("run", "Run")
+	run.PoolID = "pool-2"
+	must(t, store.AddTask(run), "add task")
+	must(t, store.DeletePool("pool-2"), "delete pool")
+	saved, err := store.GetTask("run")
+	must(t, err, "get task")
+	if saved.PoolID != "" {
+		t.Errorf("task still in deleted pool %q", saved.PoolID)
+	}
+	if err := store.DeletePool("pool-2"); err == nil {
+		t.Error("deleting a missing pool succeeded")
+	}
+}
+
+func testInbox(t *testing.T, store storage.Provider) {
+	base := time.Now().Truncate(time.Second)
+	for i, text := range []string{"call the bank", "buy stamps", "book flights"} {
+		item := models.InboxItem{ID: text, Text: text, CreatedAt: base.Add(time.Duration(i) * time.Minute)}
+		must(t, store.AddInboxItem(item), "add inbox item")
+	}
+	items, err := store.GetInboxItems()
+	must(t, err, "get inbox")
+	var texts []string
+	for _, item := range items {
+		texts = append(texts, item.Text)
+	}
+	if !slices.Equal(texts, []string{"call the bank", "buy stamps", "book flights"}) {
+		t.Errorf("inbox = %v, want oldest first", texts)
+	}
+
+	must(t, store.DeleteInboxItem("buy stamps"), "delete inbox item")
+	items, err = store.GetInboxItems()
+	must(t, err, "get inbox")
+	if len(items) != 2 {
+		t.Errorf("got %d items after delete, want 2", len(items))
+	}
+	if err := store.DeleteInboxItem("buy stamps"); err == nil {
+		t.Error("deleting a missing inbox item succeeded")
+	}
+}
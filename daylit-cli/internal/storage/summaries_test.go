@@ -0,0 +1,105 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestGetDaySummaries(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	task := models.Task{
+		ID:          "task-summary-1",
+		Name:        "Summary Task",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence: models.Recurrence{
+			Type: constants.RecurrenceDaily,
+		},
+		Priority: 1,
+		Active:   true,
+	}
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	// Accepted plan with one of two slots rated
+	now := time.Now().UTC().Format(time.RFC3339)
+	accepted := models.DayPlan{
+		Date:       "2024-05-01",
+		AcceptedAt: &now,
+		Slots: []models.Slot{
+			{Start: "09:00", End: "09:30", TaskID: task.ID, Status: constants.SlotStatusDone, Feedback: &models.Feedback{Rating: constants.FeedbackOnTrack}},
+			{Start: "10:00", End: "10:30", TaskID: task.ID, Status: constants.SlotStatusAccepted},
+		},
+	}
+	if err := store.SavePlan(accepted); err != nil {
+		t.Fatalf("failed to save accepted plan: %v", err)
+	}
+
+	// Unaccepted plan on another day
+	draft := models.DayPlan{
+		Date: "2024-05-02",
+		Slots: []models.Slot{
+			{Start: "09:00", End: "09:30", TaskID: task.ID, Status: constants.SlotStatusPlanned},
+		},
+	}
+	if err := store.SavePlan(draft); err != nil {
+		t.Fatalf("failed to save draft plan: %v", err)
+	}
+
+	// Plan outside the requested range
+	outside := models.DayPlan{Date: "2024-06-01", Slots: []models.Slot{{Start: "09:00", End: "09:30", TaskID: task.ID, Status: constants.SlotStatusPlanned}}}
+	if err := store.SavePlan(outside); err != nil {
+		t.Fatalf("failed to save outside plan: %v", err)
+	}
+
+	// Habit entry on a day without a plan
+	habit := models.Habit{ID: uuid.New().String(), Name: "Stretch", CreatedAt: time.Now()}
+	if err := store.AddHabit(habit); err != nil {
+		t.Fatalf("failed to add habit: %v", err)
+	}
+	entry := models.HabitEntry{
+		ID:        uuid.New().String(),
+		HabitID:   habit.ID,
+		Day:       "2024-05-03",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := store.AddHabitEntry(entry); err != nil {
+		t.Fatalf("failed to add habit entry: %v", err)
+	}
+
+	summaries, err := store.GetDaySummaries("2024-05-01", "2024-05-31")
+	if err != nil {
+		t.Fatalf("failed to get day summaries: %v", err)
+	}
+
+	if len(summaries) != 3 {
+		t.Fatalf("expected 3 summaries, got %d: %+v", len(summaries), summaries)
+	}
+
+	first := summaries[0]
+	if first.Date != "2024-05-01" || !first.HasPlan || !first.Accepted {
+		t.Errorf("unexpected summary for accepted day: %+v", first)
+	}
+	if first.TotalSlots != 2 || first.SlotsWithFeedback != 1 || first.FeedbackPercent() != 50 {
+		t.Errorf("expected 1/2 slots rated (50%%), got %+v", first)
+	}
+
+	second := summaries[1]
+	if second.Date != "2024-05-02" || !second.HasPlan || second.Accepted {
+		t.Errorf("unexpected summary for draft day: %+v", second)
+	}
+
+	third := summaries[2]
+	if third.Date != "2024-05-03" || third.HasPlan || third.HabitsCompleted != 1 {
+		t.Errorf("unexpected summary for habit-only day: %+v", third)
+	}
+}
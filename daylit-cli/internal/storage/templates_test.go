@@ -0,0 +1,77 @@
+package storage_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestDayTemplates(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	now := time.Now().Truncate(time.Second)
+	template := models.DayTemplate{
+		ID:   "template-1",
+		Name: "deep-work",
+		Slots: []models.TemplateSlot{
+			{Start: "13:00", End: "14:00", TaskID: "task-2"},
+			{Start: "09:00", End: "12:00", TaskID: "task-1"},
+		},
+		CreatedAt: now,
+	}
+	if err := store.SaveDayTemplate(template); err != nil {
+		t.Fatalf("failed to save template: %v", err)
+	}
+
+	overlapping := models.DayTemplate{
+		ID:   "template-2",
+		Name: "broken",
+		Slots: []models.TemplateSlot{
+			{Start: "09:00", End: "11:00", TaskID: "task-1"},
+			{Start: "10:00", End: "12:00", TaskID: "task-2"},
+		},
+		CreatedAt: now,
+	}
+	if err := store.SaveDayTemplate(overlapping); err == nil {
+		t.Error("expected error for overlapping slots")
+	}
+
+	got, err := store.GetDayTemplate("deep-work")
+	if err != nil {
+		t.Fatalf("failed to get template: %v", err)
+	}
+	if len(got.Slots) != 2 || got.Slots[0].Start != "09:00" || got.Slots[1].TaskID != "task-2" {
+		t.Errorf("expected slots ordered by start time, got %+v", got.Slots)
+	}
+	if !got.CreatedAt.Equal(now) {
+		t.Errorf("created_at = %v, want %v", got.CreatedAt, now)
+	}
+
+	// Saving again replaces the slots
+	got.Name = "focus"
+	got.Slots = []models.TemplateSlot{{Start: "08:00", End: "10:00", TaskID: "task-3"}}
+	if err := store.SaveDayTemplate(got); err != nil {
+		t.Fatalf("failed to update template: %v", err)
+	}
+	all, err := store.GetAllDayTemplates()
+	if err != nil {
+		t.Fatalf("failed to get templates: %v", err)
+	}
+	if len(all) != 1 || all[0].Name != "focus" || len(all[0].Slots) != 1 || all[0].Slots[0].TaskID != "task-3" {
+		t.Errorf("unexpected templates after update: %+v", all)
+	}
+
+	if err := store.DeleteDayTemplate("focus"); err != nil {
+		t.Fatalf("failed to delete template: %v", err)
+	}
+	if _, err := store.GetDayTemplate("focus"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows after delete, got %v", err)
+	}
+	if err := store.DeleteDayTemplate("focus"); err == nil {
+		t.Error("expected error deleting a missing template")
+	}
+}
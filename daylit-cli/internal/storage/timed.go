@@ -0,0 +1,544 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// Timed returns a provider that calls observe with the name and duration of
+// every call it forwards to p. A call that takes a callback, such as
+// EachSlot or WithTx, is timed with the callback's work included.
+func Timed(p Provider, observe func(op string, elapsed time.Duration)) Provider {
+	return &timedStore{p: p, observe: observe}
+}
+
+// Unwrap returns the provider a Timed provider wraps, or p itself. Code that
+// asserts a provider's concrete type or optional interfaces, such as
+// HealthChecker, asserts on the unwrapped one.
+func Unwrap(p Provider) Provider {
+	for {
+		t, ok := p.(*timedStore)
+		if !ok {
+			return p
+		}
+		p = t.p
+	}
+}
+
+type timedStore struct {
+	p       Provider
+	observe func(op string, elapsed time.Duration)
+}
+
+// track starts timing op; call the returned func when it's done
+func (s *timedStore) track(op string) func() {
+	start := time.Now()
+	return func() { s.observe(op, time.Since(start)) }
+}
+
+func (s *timedStore) WithTx(fn func(Provider) error) error {
+	defer s.track("WithTx")()
+	return s.p.WithTx(func(tx Provider) error {
+		return fn(&timedStore{p: tx, observe: s.observe})
+	})
+}
+
+func (s *timedStore) GetConfigPath() string {
+	return s.p.GetConfigPath()
+}
+
+func (s *timedStore) Init() error {
+	defer s.track("Init")()
+	return s.p.Init()
+}
+
+func (s *timedStore) Load() error {
+	defer s.track("Load")()
+	return s.p.Load()
+}
+
+func (s *timedStore) Close() error {
+	defer s.track("Close")()
+	return s.p.Close()
+}
+
+func (s *timedStore) GetSettings() (Settings, error) {
+	defer s.track("GetSettings")()
+	return s.p.GetSettings()
+}
+
+func (s *timedStore) SaveSettings(settings Settings) error {
+	defer s.track("SaveSettings")()
+	return s.p.SaveSettings(settings)
+}
+
+func (s *timedStore) AddTask(task models.Task) error {
+	defer s.track("AddTask")()
+	return s.p.AddTask(task)
+}
+
+func (s *timedStore) GetTask(id string) (models.Task, error) {
+	defer s.track("GetTask")()
+	return s.p.GetTask(id)
+}
+
+func (s *timedStore) GetAllTasks() ([]models.Task, error) {
+	defer s.track("GetAllTasks")()
+	return s.p.GetAllTasks()
+}
+
+func (s *timedStore) GetAllTasksIncludingDeleted() ([]models.Task, error) {
+	defer s.track("GetAllTasksIncludingDeleted")()
+	return s.p.GetAllTasksIncludingDeleted()
+}
+
+func (s *timedStore) UpdateTask(task models.Task) error {
+	defer s.track("UpdateTask")()
+	return s.p.UpdateTask(task)
+}
+
+func (s *timedStore) DeleteTask(id string) error {
+	defer s.track("DeleteTask")()
+	return s.p.DeleteTask(id)
+}
+
+func (s *timedStore) DeleteTaskCascade(id string, removeSlots bool, now time.Time) (int, error) {
+	defer s.track("DeleteTaskCascade")()
+	return s.p.DeleteTaskCascade(id, removeSlots, now)
+}
+
+func (s *timedStore) RestoreTask(id string) error {
+	defer s.track("RestoreTask")()
+	return s.p.RestoreTask(id)
+}
+
+func (s *timedStore) AddProject(project models.Project) error {
+	defer s.track("AddProject")()
+	return s.p.AddProject(project)
+}
+
+func (s *timedStore) GetProjectByName(name string) (models.Project, error) {
+	defer s.track("GetProjectByName")()
+	return s.p.GetProjectByName(name)
+}
+
+func (s *timedStore) GetAllProjects() ([]models.Project, error) {
+	defer s.track("GetAllProjects")()
+	return s.p.GetAllProjects()
+}
+
+func (s *timedStore) AddPool(pool models.TaskPool) error {
+	defer s.track("AddPool")()
+	return s.p.AddPool(pool)
+}
+
+func (s *timedStore) GetPoolByName(name string) (models.TaskPool, error) {
+	defer s.track("GetPoolByName")()
+	return s.p.GetPoolByName(name)
+}
+
+func (s *timedStore) GetAllPools() ([]models.TaskPool, error) {
+	defer s.track("GetAllPools")()
+	return s.p.GetAllPools()
+}
+
+func (s *timedStore) DeletePool(id string) error {
+	defer s.track("DeletePool")()
+	return s.p.DeletePool(id)
+}
+
+func (s *timedStore) AddInboxItem(item models.InboxItem) error {
+	defer s.track("AddInboxItem")()
+	return s.p.AddInboxItem(item)
+}
+
+func (s *timedStore) GetInboxItems() ([]models.InboxItem, error) {
+	defer s.track("GetInboxItems")()
+	return s.p.GetInboxItems()
+}
+
+func (s *timedStore) DeleteInboxItem(id string) error {
+	defer s.track("DeleteInboxItem")()
+	return s.p.DeleteInboxItem(id)
+}
+
+func (s *timedStore) SavePlan(plan models.DayPlan) error {
+	defer s.track("SavePlan")()
+	return s.p.SavePlan(plan)
+}
+
+func (s *timedStore) GetPlan(date string) (models.DayPlan, error) {
+	defer s.track("GetPlan")()
+	return s.p.GetPlan(date)
+}
+
+func (s *timedStore) GetPlanRevision(date string, revision int) (models.DayPlan, error) {
+	defer s.track("GetPlanRevision")()
+	return s.p.GetPlanRevision(date, revision)
+}
+
+func (s *timedStore) GetLatestPlanRevision(date string) (models.DayPlan, error) {
+	defer s.track("GetLatestPlanRevision")()
+	return s.p.GetLatestPlanRevision(date)
+}
+
+func (s *timedStore) GetPlansRange(startDay, endDay string) ([]models.DayPlan, error) {
+	defer s.track("GetPlansRange")()
+	return s.p.GetPlansRange(startDay, endDay)
+}
+
+func (s *timedStore) GetPlansPage(startDay, endDay, cursor string, limit int) (models.PlanPage, error) {
+	defer s.track("GetPlansPage")()
+	return s.p.GetPlansPage(startDay, endDay, cursor, limit)
+}
+
+func (s *timedStore) DeletePlan(date string) error {
+	defer s.track("DeletePlan")()
+	return s.p.DeletePlan(date)
+}
+
+func (s *timedStore) RestorePlan(date string) error {
+	defer s.track("RestorePlan")()
+	return s.p.RestorePlan(date)
+}
+
+func (s *timedStore) DeleteSlot(date string, revision int, startTime string, taskID string) error {
+	defer s.track("DeleteSlot")()
+	return s.p.DeleteSlot(date, revision, startTime, taskID)
+}
+
+func (s *timedStore) RestoreSlot(date string, revision int, startTime string, taskID string) error {
+	defer s.track("RestoreSlot")()
+	return s.p.RestoreSlot(date, revision, startTime, taskID)
+}
+
+func (s *timedStore) AmendPlan(amendment models.PlanAmendment) (models.PlanAmendment, error) {
+	defer s.track("AmendPlan")()
+	return s.p.AmendPlan(amendment)
+}
+
+func (s *timedStore) GetPlanAmendments(date string) ([]models.PlanAmendment, error) {
+	defer s.track("GetPlanAmendments")()
+	return s.p.GetPlanAmendments(date)
+}
+
+func (s *timedStore) UpdateSlotNotificationTimestamp(date string, revision int, startTime string, taskID string, notificationType string, timestamp string) error {
+	defer s.track("UpdateSlotNotificationTimestamp")()
+	return s.p.UpdateSlotNotificationTimestamp(date, revision, startTime, taskID, notificationType, timestamp)
+}
+
+func (s *timedStore) EachSlot(startDay, endDay string, fn func(models.SlotRecord) error) error {
+	defer s.track("EachSlot")()
+	return s.p.EachSlot(startDay, endDay, fn)
+}
+
+func (s *timedStore) SaveDayTemplate(template models.DayTemplate) error {
+	defer s.track("SaveDayTemplate")()
+	return s.p.SaveDayTemplate(template)
+}
+
+func (s *timedStore) GetDayTemplate(name string) (models.DayTemplate, error) {
+	defer s.track("GetDayTemplate")()
+	return s.p.GetDayTemplate(name)
+}
+
+func (s *timedStore) GetAllDayTemplates() ([]models.DayTemplate, error) {
+	defer s.track("GetAllDayTemplates")()
+	return s.p.GetAllDayTemplates()
+}
+
+func (s *timedStore) DeleteDayTemplate(name string) error {
+	defer s.track("DeleteDayTemplate")()
+	return s.p.DeleteDayTemplate(name)
+}
+
+func (s *timedStore) AddHabit(habit models.Habit) error {
+	defer s.track("AddHabit")()
+	return s.p.AddHabit(habit)
+}
+
+func (s *timedStore) GetHabit(id string) (models.Habit, error) {
+	defer s.track("GetHabit")()
+	return s.p.GetHabit(id)
+}
+
+func (s *timedStore) GetHabitByName(name string) (models.Habit, error) {
+	defer s.track("GetHabitByName")()
+	return s.p.GetHabitByName(name)
+}
+
+func (s *timedStore) GetAllHabits(includeArchived, includeDeleted bool) ([]models.Habit, error) {
+	defer s.track("GetAllHabits")()
+	return s.p.GetAllHabits(includeArchived, includeDeleted)
+}
+
+func (s *timedStore) UpdateHabit(habit models.Habit) error {
+	defer s.track("UpdateHabit")()
+	return s.p.UpdateHabit(habit)
+}
+
+func (s *timedStore) ArchiveHabit(id string) error {
+	defer s.track("ArchiveHabit")()
+	return s.p.ArchiveHabit(id)
+}
+
+func (s *timedStore) UnarchiveHabit(id string) error {
+	defer s.track("UnarchiveHabit")()
+	return s.p.UnarchiveHabit(id)
+}
+
+func (s *timedStore) DeleteHabit(id string) error {
+	defer s.track("DeleteHabit")()
+	return s.p.DeleteHabit(id)
+}
+
+func (s *timedStore) RestoreHabit(id string) error {
+	defer s.track("RestoreHabit")()
+	return s.p.RestoreHabit(id)
+}
+
+func (s *timedStore) AddHabitEntry(entry models.HabitEntry) error {
+	defer s.track("AddHabitEntry")()
+	return s.p.AddHabitEntry(entry)
+}
+
+func (s *timedStore) GetHabitEntry(habitID, day string) (models.HabitEntry, error) {
+	defer s.track("GetHabitEntry")()
+	return s.p.GetHabitEntry(habitID, day)
+}
+
+func (s *timedStore) GetHabitEntriesForDay(day string) ([]models.HabitEntry, error) {
+	defer s.track("GetHabitEntriesForDay")()
+	return s.p.GetHabitEntriesForDay(day)
+}
+
+func (s *timedStore) GetHabitEntriesForHabit(habitID string, startDay, endDay string) ([]models.HabitEntry, error) {
+	defer s.track("GetHabitEntriesForHabit")()
+	return s.p.GetHabitEntriesForHabit(habitID, startDay, endDay)
+}
+
+func (s *timedStore) UpdateHabitEntry(entry models.HabitEntry) error {
+	defer s.track("UpdateHabitEntry")()
+	return s.p.UpdateHabitEntry(entry)
+}
+
+func (s *timedStore) DeleteHabitEntry(id string) error {
+	defer s.track("DeleteHabitEntry")()
+	return s.p.DeleteHabitEntry(id)
+}
+
+func (s *timedStore) RestoreHabitEntry(id string) error {
+	defer s.track("RestoreHabitEntry")()
+	return s.p.RestoreHabitEntry(id)
+}
+
+func (s *timedStore) GetOTSettings() (models.OTSettings, error) {
+	defer s.track("GetOTSettings")()
+	return s.p.GetOTSettings()
+}
+
+func (s *timedStore) SaveOTSettings(settings models.OTSettings) error {
+	defer s.track("SaveOTSettings")()
+	return s.p.SaveOTSettings(settings)
+}
+
+func (s *timedStore) AddOTEntry(entry models.OTEntry) error {
+	defer s.track("AddOTEntry")()
+	return s.p.AddOTEntry(entry)
+}
+
+func (s *timedStore) GetOTEntry(day string) (models.OTEntry, error) {
+	defer s.track("GetOTEntry")()
+	return s.p.GetOTEntry(day)
+}
+
+func (s *timedStore) GetOTEntries(startDay, endDay string, includeDeleted bool) ([]models.OTEntry, error) {
+	defer s.track("GetOTEntries")()
+	return s.p.GetOTEntries(startDay, endDay, includeDeleted)
+}
+
+func (s *timedStore) UpdateOTEntry(entry models.OTEntry) error {
+	defer s.track("UpdateOTEntry")()
+	return s.p.UpdateOTEntry(entry)
+}
+
+func (s *timedStore) DeleteOTEntry(day string) error {
+	defer s.track("DeleteOTEntry")()
+	return s.p.DeleteOTEntry(day)
+}
+
+func (s *timedStore) RestoreOTEntry(day string) error {
+	defer s.track("RestoreOTEntry")()
+	return s.p.RestoreOTEntry(day)
+}
+
+func (s *timedStore) AddAlert(alert models.Alert) error {
+	defer s.track("AddAlert")()
+	return s.p.AddAlert(alert)
+}
+
+func (s *timedStore) GetAlert(id string) (models.Alert, error) {
+	defer s.track("GetAlert")()
+	return s.p.GetAlert(id)
+}
+
+func (s *timedStore) GetAllAlerts() ([]models.Alert, error) {
+	defer s.track("GetAllAlerts")()
+	return s.p.GetAllAlerts()
+}
+
+func (s *timedStore) UpdateAlert(alert models.Alert) error {
+	defer s.track("UpdateAlert")()
+	return s.p.UpdateAlert(alert)
+}
+
+func (s *timedStore) DeleteAlert(id string) error {
+	defer s.track("DeleteAlert")()
+	return s.p.DeleteAlert(id)
+}
+
+func (s *timedStore) AddVacation(vacation models.Vacation) error {
+	defer s.track("AddVacation")()
+	return s.p.AddVacation(vacation)
+}
+
+func (s *timedStore) GetVacations(startDay, endDay string) ([]models.Vacation, error) {
+	defer s.track("GetVacations")()
+	return s.p.GetVacations(startDay, endDay)
+}
+
+func (s *timedStore) DeleteVacation(id string) error {
+	defer s.track("DeleteVacation")()
+	return s.p.DeleteVacation(id)
+}
+
+func (s *timedStore) AddSlotReminder(reminder models.SlotReminder) error {
+	defer s.track("AddSlotReminder")()
+	return s.p.AddSlotReminder(reminder)
+}
+
+func (s *timedStore) GetSlotReminders(startDay, endDay string) ([]models.SlotReminder, error) {
+	defer s.track("GetSlotReminders")()
+	return s.p.GetSlotReminders(startDay, endDay)
+}
+
+func (s *timedStore) MarkSlotReminderSent(id string, sentAt time.Time) error {
+	defer s.track("MarkSlotReminderSent")()
+	return s.p.MarkSlotReminderSent(id, sentAt)
+}
+
+func (s *timedStore) DeleteSlotReminder(id string) error {
+	defer s.track("DeleteSlotReminder")()
+	return s.p.DeleteSlotReminder(id)
+}
+
+func (s *timedStore) AddNotificationLog(entry models.NotificationLogEntry) error {
+	defer s.track("AddNotificationLog")()
+	return s.p.AddNotificationLog(entry)
+}
+
+func (s *timedStore) GetNotificationLog(since time.Time, limit int) ([]models.NotificationLogEntry, error) {
+	defer s.track("GetNotificationLog")()
+	return s.p.GetNotificationLog(since, limit)
+}
+
+func (s *timedStore) AddOutboxItem(item models.OutboxItem) error {
+	defer s.track("AddOutboxItem")()
+	return s.p.AddOutboxItem(item)
+}
+
+func (s *timedStore) GetDueOutboxItems(now time.Time, limit int) ([]models.OutboxItem, error) {
+	defer s.track("GetDueOutboxItems")()
+	return s.p.GetDueOutboxItems(now, limit)
+}
+
+func (s *timedStore) UpdateOutboxItem(item models.OutboxItem) error {
+	defer s.track("UpdateOutboxItem")()
+	return s.p.UpdateOutboxItem(item)
+}
+
+func (s *timedStore) DeleteOutboxItem(id int64) error {
+	defer s.track("DeleteOutboxItem")()
+	return s.p.DeleteOutboxItem(id)
+}
+
+func (s *timedStore) GetOutboxItems(since time.Time) ([]models.OutboxItem, error) {
+	defer s.track("GetOutboxItems")()
+	return s.p.GetOutboxItems(since)
+}
+
+func (s *timedStore) ClaimNotification(key string, at time.Time) (bool, error) {
+	defer s.track("ClaimNotification")()
+	return s.p.ClaimNotification(key, at)
+}
+
+func (s *timedStore) DeleteNotificationClaims(before time.Time) error {
+	defer s.track("DeleteNotificationClaims")()
+	return s.p.DeleteNotificationClaims(before)
+}
+
+func (s *timedStore) GetAllPlans() ([]models.DayPlan, error) {
+	defer s.track("GetAllPlans")()
+	return s.p.GetAllPlans()
+}
+
+func (s *timedStore) GetAllHabitEntries() ([]models.HabitEntry, error) {
+	defer s.track("GetAllHabitEntries")()
+	return s.p.GetAllHabitEntries()
+}
+
+func (s *timedStore) GetAllOTEntries() ([]models.OTEntry, error) {
+	defer s.track("GetAllOTEntries")()
+	return s.p.GetAllOTEntries()
+}
+
+func (s *timedStore) GetTaskFeedbackHistory(taskID string, limit int) ([]models.TaskFeedbackEntry, error) {
+	defer s.track("GetTaskFeedbackHistory")()
+	return s.p.GetTaskFeedbackHistory(taskID, limit)
+}
+
+func (s *timedStore) GetTaskSlotHistory(taskID string, limit int) ([]models.TaskSlotEntry, error) {
+	defer s.track("GetTaskSlotHistory")()
+	return s.p.GetTaskSlotHistory(taskID, limit)
+}
+
+func (s *timedStore) GetDaySummaries(startDay, endDay string) ([]models.DaySummary, error) {
+	defer s.track("GetDaySummaries")()
+	return s.p.GetDaySummaries(startDay, endDay)
+}
+
+func (s *timedStore) GetTaskStats(startDay, endDay string) ([]models.TaskStats, error) {
+	defer s.track("GetTaskStats")()
+	return s.p.GetTaskStats(startDay, endDay)
+}
+
+func (s *timedStore) GetHabitCategoryStats(startDay, endDay string) ([]models.HabitCategoryStats, error) {
+	defer s.track("GetHabitCategoryStats")()
+	return s.p.GetHabitCategoryStats(startDay, endDay)
+}
+
+func (s *timedStore) SaveDailyMetrics(metrics models.DailyMetrics) error {
+	defer s.track("SaveDailyMetrics")()
+	return s.p.SaveDailyMetrics(metrics)
+}
+
+func (s *timedStore) GetDailyMetrics(startDay, endDay string) ([]models.DailyMetrics, error) {
+	defer s.track("GetDailyMetrics")()
+	return s.p.GetDailyMetrics(startDay, endDay)
+}
+
+func (s *timedStore) PurgeDeleted(before time.Time, kinds []models.PurgeKind, dryRun bool) (models.PurgeSummary, error) {
+	defer s.track("PurgeDeleted")()
+	return s.p.PurgeDeleted(before, kinds, dryRun)
+}
+
+func (s *timedStore) PurgeItem(kind models.TrashKind, id string) error {
+	defer s.track("PurgeItem")()
+	return s.p.PurgeItem(kind, id)
+}
+
+func (s *timedStore) Search(query string, limit int) ([]models.SearchResult, error) {
+	defer s.track("Search")()
+	return s.p.Search(query, limit)
+}
@@ -0,0 +1,39 @@
+package storage_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func TestTimed(t *testing.T) {
+	inner := setupMemoryStore(t)
+	var ops []string
+	store := storage.Timed(inner, func(op string, elapsed time.Duration) {
+		if elapsed < 0 {
+			t.Errorf("%s took %v", op, elapsed)
+		}
+		ops = append(ops, op)
+	})
+
+	addSlotTasks(t, store, "write")
+	if err := store.WithTx(func(tx storage.Provider) error {
+		_, err := tx.GetTask("write")
+		return err
+	}); err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+
+	// Calls inside a transaction are timed too, and finish before it does
+	if want := []string{"AddTask", "GetTask", "WithTx"}; !slices.Equal(ops, want) {
+		t.Errorf("observed %v, want %v", ops, want)
+	}
+	if got := storage.Unwrap(store); got != storage.Provider(inner) {
+		t.Errorf("Unwrap returned %T, want the wrapped store", got)
+	}
+	if got := storage.Unwrap(inner); got != storage.Provider(inner) {
+		t.Errorf("Unwrap of an unwrapped store returned %T", got)
+	}
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// ListTrash returns every soft-deleted task, plan, habit, habit entry and OT
+// entry, most recently deleted first. A plan is listed once per date, however
+// many of its revisions were deleted.
+func ListTrash(p Provider) ([]models.TrashItem, error) {
+	var items []models.TrashItem
+
+	tasks, err := p.GetAllTasksIncludingDeleted()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	for _, t := range tasks {
+		if t.DeletedAt != nil {
+			items = append(items, models.TrashItem{Kind: models.TrashTask, ID: t.ID, Label: t.Name, DeletedAt: parseDeletedAt(*t.DeletedAt)})
+		}
+	}
+
+	plans, err := p.GetAllPlans()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plans: %w", err)
+	}
+	deletedPlans := make(map[string]models.TrashItem)
+	for _, plan := range plans {
+		if plan.DeletedAt == nil {
+			continue
+		}
+		deletedAt := parseDeletedAt(*plan.DeletedAt)
+		if existing, ok := deletedPlans[plan.Date]; ok && !deletedAt.After(existing.DeletedAt) {
+			continue
+		}
+		deletedPlans[plan.Date] = models.TrashItem{
+			Kind:      models.TrashPlan,
+			ID:        plan.Date,
+			Label:     fmt.Sprintf("Plan for %s (%d slots)", plan.Date, len(plan.Slots)),
+			DeletedAt: deletedAt,
+		}
+	}
+	for _, item := range deletedPlans {
+		items = append(items, item)
+	}
+
+	habits, err := p.GetAllHabits(true, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habits: %w", err)
+	}
+	habitNames := make(map[string]string, len(habits))
+	for _, h := range habits {
+		habitNames[h.ID] = h.Name
+		if h.DeletedAt != nil {
+			items = append(items, models.TrashItem{Kind: models.TrashHabit, ID: h.ID, Label: h.Name, DeletedAt: *h.DeletedAt})
+		}
+	}
+
+	entries, err := p.GetAllHabitEntries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habit entries: %w", err)
+	}
+	for _, e := range entries {
+		if e.DeletedAt != nil {
+			label := fmt.Sprintf("%s on %s", habitNames[e.HabitID], e.Day)
+			items = append(items, models.TrashItem{Kind: models.TrashHabitEntry, ID: e.ID, Label: label, DeletedAt: *e.DeletedAt})
+		}
+	}
+
+	otEntries, err := p.GetAllOTEntries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OT entries: %w", err)
+	}
+	for _, e := range otEntries {
+		if e.DeletedAt != nil {
+			items = append(items, models.TrashItem{Kind: models.TrashOTEntry, ID: e.Day, Label: e.Title, DeletedAt: *e.DeletedAt})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].DeletedAt.Equal(items[j].DeletedAt) {
+			return items[i].DeletedAt.After(items[j].DeletedAt)
+		}
+		if items[i].Kind != items[j].Kind {
+			return items[i].Kind < items[j].Kind
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items, nil
+}
+
+// RestoreTrashItem restores one soft-deleted item
+func RestoreTrashItem(p Provider, kind models.TrashKind, id string) error {
+	switch kind {
+	case models.TrashTask:
+		return p.RestoreTask(id)
+	case models.TrashPlan:
+		return p.RestorePlan(id)
+	case models.TrashHabit:
+		return p.RestoreHabit(id)
+	case models.TrashHabitEntry:
+		return p.RestoreHabitEntry(id)
+	case models.TrashOTEntry:
+		return p.RestoreOTEntry(id)
+	}
+	return fmt.Errorf("unknown kind %q", kind)
+}
+
+// parseDeletedAt parses a soft-delete timestamp stored as text. Timestamps
+// that don't parse sort last.
+func parseDeletedAt(deletedAt string) time.Time {
+	t, err := time.Parse(time.RFC3339, deletedAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,87 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func TestTrash(t *testing.T) {
+	for name, store := range rangeStores(t) {
+		t.Run(name, func(t *testing.T) {
+			seedPurge(t, store)
+
+			now := time.Now()
+			ot := models.OTEntry{ID: "ot-1", Day: "2025-03-10", Title: "Ship it", CreatedAt: now, UpdatedAt: now}
+			if err := store.AddOTEntry(ot); err != nil {
+				t.Fatalf("failed to add OT entry: %v", err)
+			}
+			if err := store.DeleteOTEntry(ot.Day); err != nil {
+				t.Fatalf("failed to delete OT entry: %v", err)
+			}
+
+			items, err := storage.ListTrash(store)
+			if err != nil {
+				t.Fatalf("ListTrash failed: %v", err)
+			}
+			got := make(map[models.TrashKind][]string)
+			for _, item := range items {
+				if item.DeletedAt.IsZero() {
+					t.Errorf("%s %s has no deletion time", item.Kind, item.ID)
+				}
+				got[item.Kind] = append(got[item.Kind], item.ID)
+			}
+			if len(items) != 5 || len(got[models.TrashTask]) != 2 || got[models.TrashPlan][0] != "2025-03-12" ||
+				got[models.TrashHabit][0] != "habit-1" || got[models.TrashOTEntry][0] != ot.Day {
+				t.Fatalf("unexpected trash: %+v", items)
+			}
+
+			if err := storage.RestoreTrashItem(store, models.TrashOTEntry, ot.Day); err != nil {
+				t.Fatalf("failed to restore OT entry: %v", err)
+			}
+			if entry, err := store.GetOTEntry(ot.Day); err != nil || entry.Title != ot.Title {
+				t.Errorf("expected the OT entry back, got %+v (%v)", entry, err)
+			}
+
+			// Tasks the plans still use can't be purged
+			if err := store.PurgeItem(models.TrashTask, "task-1"); err == nil {
+				t.Error("expected purging a task plans still use to fail")
+			}
+			for _, item := range []struct {
+				kind models.TrashKind
+				id   string
+			}{
+				{models.TrashTask, "task-2"},
+				{models.TrashPlan, "2025-03-12"},
+				{models.TrashHabit, "habit-1"},
+			} {
+				if err := store.PurgeItem(item.kind, item.id); err != nil {
+					t.Fatalf("PurgeItem(%s, %s) failed: %v", item.kind, item.id, err)
+				}
+				if err := store.PurgeItem(item.kind, item.id); err == nil {
+					t.Errorf("expected purging %s %s twice to fail", item.kind, item.id)
+				}
+			}
+			// Only deleted items can be purged
+			if err := store.PurgeItem(models.TrashOTEntry, ot.Day); err == nil {
+				t.Error("expected purging a live OT entry to fail")
+			}
+
+			items, err = storage.ListTrash(store)
+			if err != nil {
+				t.Fatalf("ListTrash failed: %v", err)
+			}
+			if len(items) != 1 || items[0].ID != "task-1" {
+				t.Errorf("expected only task-1 left in the trash, got %+v", items)
+			}
+			if entries, _ := store.GetAllHabitEntries(); len(entries) != 0 {
+				t.Errorf("expected the habit's entries to go with it, got %d", len(entries))
+			}
+			if plans, _ := store.GetPlansRange("2025-03-10", "2025-03-14"); len(plans) != 4 {
+				t.Errorf("expected the live plans to be untouched, got %d", len(plans))
+			}
+		})
+	}
+}
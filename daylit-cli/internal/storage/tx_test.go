@@ -0,0 +1,92 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+func TestWithTx(t *testing.T) {
+	for name, store := range rangeStores(t) {
+		t.Run(name, func(t *testing.T) {
+			addSlotTasks(t, store, "write")
+			plan := models.DayPlan{Date: "2025-06-01", Slots: []models.Slot{
+				{Start: "09:00", End: "10:00", TaskID: "write", Status: constants.SlotStatusPlanned},
+			}}
+			rename := func(tx storage.Provider, name string) error {
+				task, err := tx.GetTask("write")
+				if err != nil {
+					return err
+				}
+				task.Name = name
+				return tx.UpdateTask(task)
+			}
+			taskName := func() string {
+				t.Helper()
+				task, err := store.GetTask("write")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return task.Name
+			}
+
+			// A failure undoes every write made in the transaction
+			failed := errors.New("failed")
+			err := store.WithTx(func(tx storage.Provider) error {
+				if err := rename(tx, "Draft"); err != nil {
+					return err
+				}
+				if err := tx.SavePlan(plan); err != nil {
+					return err
+				}
+				return failed
+			})
+			if !errors.Is(err, failed) {
+				t.Fatalf("WithTx returned %v, want fn's error", err)
+			}
+			if got := taskName(); got != "write" {
+				t.Errorf("task name = %q after rollback, want it unchanged", got)
+			}
+			if _, err := store.GetPlan(plan.Date); err == nil {
+				t.Error("expected the plan saved in the failed transaction to be gone")
+			}
+
+			// A nested failure only undoes its own writes
+			err = store.WithTx(func(tx storage.Provider) error {
+				if err := rename(tx, "Draft"); err != nil {
+					return err
+				}
+				if err := tx.WithTx(func(inner storage.Provider) error {
+					if err := inner.SavePlan(plan); err != nil {
+						return err
+					}
+					return failed
+				}); !errors.Is(err, failed) {
+					t.Errorf("nested WithTx returned %v, want fn's error", err)
+				}
+				// A store method that fails leaves the transaction usable
+				stale, err := tx.GetTask("write")
+				if err != nil {
+					return err
+				}
+				stale.Version--
+				if err := tx.UpdateTask(stale); err == nil {
+					t.Error("expected a stale update to conflict")
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("WithTx failed: %v", err)
+			}
+			if got := taskName(); got != "Draft" {
+				t.Errorf("task name = %q, want the committed rename", got)
+			}
+			if _, err := store.GetPlan(plan.Date); err == nil {
+				t.Error("expected the plan saved in the failed nested transaction to be gone")
+			}
+		})
+	}
+}
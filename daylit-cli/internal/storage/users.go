@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// UserLister is implemented by providers that keep several users' data
+// apart in one shared database
+type UserLister interface {
+	// ListUsers returns every user with data in the database, the default
+	// user first
+	ListUsers() ([]models.User, error)
+}
+
+// Household is implemented by providers where users on a shared database
+// can share tasks and take turns at them
+type Household interface {
+	// CurrentUser returns the user this provider works for, empty for the
+	// default user
+	CurrentUser() string
+	// ShareTask shares the current user's task with the other members,
+	// copying it into their task lists, as of now. Sharing a task again
+	// changes its members.
+	ShareTask(taskID string, members []string, now time.Time) error
+	// UnshareTask stops sharing a task; every member keeps their copy
+	UnshareTask(taskID string) error
+	// GetSharedTasks returns the current user's shared tasks with when
+	// each member last did them
+	GetSharedTasks() ([]models.SharedTask, error)
+}
@@ -0,0 +1,63 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestVacations(t *testing.T) {
+	store, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+
+	now := time.Now().Truncate(time.Second)
+	vacations := []models.Vacation{
+		{ID: "vacation-2", Start: "2025-07-01", End: "2025-07-14", Note: "Lisbon", CreatedAt: now},
+		{ID: "vacation-1", Start: "2025-03-10", End: "2025-03-10", CreatedAt: now},
+	}
+	for _, v := range vacations {
+		if err := store.AddVacation(v); err != nil {
+			t.Fatalf("failed to add vacation: %v", err)
+		}
+	}
+
+	if err := store.AddVacation(models.Vacation{ID: "bad", Start: "2025-08-02", End: "2025-08-01", CreatedAt: now}); err == nil {
+		t.Error("expected error for a vacation that ends before it starts")
+	}
+
+	all, err := store.GetVacations("0000-01-01", "9999-12-31")
+	if err != nil {
+		t.Fatalf("failed to get vacations: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "vacation-1" || all[1].ID != "vacation-2" {
+		t.Fatalf("expected vacations ordered by start, got %+v", all)
+	}
+	if all[1].Note != "Lisbon" || !all[1].CreatedAt.Equal(now) {
+		t.Errorf("unexpected vacation: %+v", all[1])
+	}
+
+	// Ranges that only touch the vacation's first or last day overlap it
+	for _, r := range [][2]string{{"2025-06-20", "2025-07-01"}, {"2025-07-14", "2025-07-20"}, {"2025-07-05", "2025-07-05"}} {
+		got, err := store.GetVacations(r[0], r[1])
+		if err != nil {
+			t.Fatalf("failed to get vacations: %v", err)
+		}
+		if len(got) != 1 || got[0].ID != "vacation-2" {
+			t.Errorf("GetVacations(%s, %s) = %+v, want vacation-2", r[0], r[1], got)
+		}
+	}
+	if got, _ := store.GetVacations("2025-07-15", "2025-07-31"); len(got) != 0 {
+		t.Errorf("expected no vacations after the end, got %+v", got)
+	}
+
+	if err := store.DeleteVacation("vacation-2"); err != nil {
+		t.Fatalf("failed to delete vacation: %v", err)
+	}
+	if err := store.DeleteVacation("vacation-2"); err == nil {
+		t.Error("expected error deleting a missing vacation")
+	}
+	if got, _ := store.GetVacations("2025-07-01", "2025-07-14"); len(got) != 0 {
+		t.Errorf("expected the vacation to be deleted, got %+v", got)
+	}
+}
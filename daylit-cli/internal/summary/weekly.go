@@ -0,0 +1,300 @@
+// Package summary builds the weekly summary sent by the notify daemon and
+// shown by 'daylit summary', reusing the stats and day summary aggregations.
+package summary
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+// streakLookbackDays bounds how far back habit streaks are counted
+const streakLookbackDays = 365
+
+// HabitWeek is one habit's progress over the week
+type HabitWeek struct {
+	Name   string `json:"name"`
+	Done   int    `json:"done"`    // Days marked this week
+	Target int    `json:"target"`  // Days in the week since the habit was created, less paused days
+	Streak int    `json:"streak"`  // Consecutive days marked, up to today or yesterday
+	AtRisk bool   `json:"at_risk"` // The streak ends unless the habit is marked today
+}
+
+// Weekly is the summary of the week from From to To
+type Weekly struct {
+	From       string                      `json:"from"`
+	To         string                      `json:"to"`
+	Habits     []HabitWeek                 `json:"habits"`
+	Categories []models.HabitCategoryStats `json:"habit_categories"`
+	Tasks      models.TaskStats            `json:"tasks"`     // Slot totals across all tasks
+	Adherence  int                         `json:"adherence"` // Percentage of slots in accepted plans that were done
+	Tomorrow   models.DaySummary           `json:"tomorrow"`
+}
+
+// Build summarizes the latest week, of weeks starting on first, that ends
+// on or before now's day. Streaks and tomorrow's plan are as of now.
+func Build(store storage.Provider, now time.Time, first time.Weekday) (Weekly, error) {
+	today := now.Format(constants.DateFormat)
+	end := lastWeekEnd(now, first)
+	w := Weekly{
+		From:   end.AddDate(0, 0, -6).Format(constants.DateFormat),
+		To:     end.Format(constants.DateFormat),
+		Habits: []HabitWeek{},
+	}
+
+	habits, err := store.GetAllHabits(false, false)
+	if err != nil {
+		return Weekly{}, fmt.Errorf("failed to get habits: %w", err)
+	}
+	lookback := now.AddDate(0, 0, -streakLookbackDays).Format(constants.DateFormat)
+	for _, habit := range habits {
+		entries, err := store.GetHabitEntriesForHabit(habit.ID, lookback, today)
+		if err != nil {
+			return Weekly{}, fmt.Errorf("failed to get entries for habit %s: %w", habit.Name, err)
+		}
+		w.Habits = append(w.Habits, habitWeek(habit, entries, end, now))
+	}
+	w.Categories, err = store.GetHabitCategoryStats(w.From, w.To)
+	if err != nil {
+		return Weekly{}, fmt.Errorf("failed to get habit category stats: %w", err)
+	}
+	if w.Categories == nil {
+		w.Categories = []models.HabitCategoryStats{}
+	}
+
+	tasks, err := store.GetTaskStats(w.From, w.To)
+	if err != nil {
+		return Weekly{}, fmt.Errorf("failed to get task stats: %w", err)
+	}
+	for _, t := range tasks {
+		w.Tasks.Add(t)
+	}
+	w.Adherence = w.Tasks.Adherence()
+
+	tomorrow := now.AddDate(0, 0, 1).Format(constants.DateFormat)
+	w.Tomorrow = models.DaySummary{Date: tomorrow}
+	days, err := store.GetDaySummaries(tomorrow, tomorrow)
+	if err != nil {
+		return Weekly{}, fmt.Errorf("failed to get tomorrow's plan: %w", err)
+	}
+	if len(days) > 0 {
+		w.Tomorrow = days[0]
+	}
+	return w, nil
+}
+
+// lastWeekEnd returns the last day of the latest week, of weeks starting on
+// first, that ends on or before t's day
+func lastWeekEnd(t time.Time, first time.Weekday) time.Time {
+	last := (first + 6) % 7
+	return t.AddDate(0, 0, -((int(t.Weekday()) - int(last) + 7) % 7))
+}
+
+// habitWeek counts the days habit was marked in the week ending on end's
+// day, and its streak as of now
+func habitWeek(habit models.Habit, entries []models.HabitEntry, end, now time.Time) HabitWeek {
+	marked := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		marked[e.Day] = true
+	}
+
+	hw := HabitWeek{Name: habit.Name}
+	created := habit.CreatedAt.In(now.Location()).Format(constants.DateFormat)
+	for i := 0; i < 7; i++ {
+		day := end.AddDate(0, 0, -i).Format(constants.DateFormat)
+		if day < created {
+			break
+		}
+		// Paused days are neutral
+		if habit.PausedOn(day) {
+			continue
+		}
+		hw.Target++
+		if marked[day] {
+			hw.Done++
+		}
+	}
+
+	// A streak still counts from yesterday until today is over, and carries
+	// on across paused days without growing
+	today := now.Format(constants.DateFormat)
+	day := now
+	if !marked[today] && !habit.PausedOn(today) {
+		day = now.AddDate(0, 0, -1)
+	}
+	for {
+		date := day.Format(constants.DateFormat)
+		if habit.PausedOn(date) {
+			day = day.AddDate(0, 0, -1)
+			continue
+		}
+		if !marked[date] {
+			break
+		}
+		hw.Streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	hw.AtRisk = !marked[today] && !habit.PausedOn(today) && hw.Streak > 1
+	return hw
+}
+
+// Message returns the summary as a short notification text
+func (w Weekly) Message() string {
+	var parts []string
+	if len(w.Habits) > 0 {
+		done, target := 0, 0
+		for _, h := range w.Habits {
+			done += h.Done
+			target += h.Target
+		}
+		parts = append(parts, fmt.Sprintf("habits %d/%d", done, target))
+	}
+	if w.Tasks.AcceptedSlots > 0 {
+		parts = append(parts, fmt.Sprintf("adherence %d%%", w.Adherence))
+	}
+	if risk := w.atRisk(); len(risk) > 0 {
+		parts = append(parts, "streaks at risk: "+strings.Join(risk, ", "))
+	}
+	parts = append(parts, "tomorrow: "+w.tomorrowStatus())
+	return "Weekly summary: " + strings.Join(parts, "; ")
+}
+
+// Markdown returns the summary as a Markdown report
+func (w Weekly) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Week of %s to %s\n\n", w.From, w.To)
+
+	b.WriteString("## Habits\n\n")
+	if len(w.Habits) == 0 {
+		b.WriteString("No habits.\n")
+	} else {
+		b.WriteString("| Habit | Done | Streak |\n|---|---|---|\n")
+		for _, h := range w.Habits {
+			streak := fmt.Sprintf("%d", h.Streak)
+			if h.AtRisk {
+				streak += " (at risk)"
+			}
+			fmt.Fprintf(&b, "| %s | %d/%d | %s |\n", h.Name, h.Done, h.Target, streak)
+		}
+	}
+
+	if w.hasCategories() {
+		b.WriteString("\n| Category | Done | Completion |\n|---|---|---|\n")
+		for _, c := range w.Categories {
+			name := c.Category
+			if name == "" {
+				name = "uncategorized"
+			}
+			fmt.Fprintf(&b, "| %s | %d/%d | %d%% |\n", name, c.DoneDays, c.DueDays, c.Completion())
+		}
+	}
+
+	b.WriteString("\n## Plans\n\n")
+	fmt.Fprintf(&b, "- Planned: %d slots (%s)\n", w.Tasks.PlannedSlots, utils.FormatMinutes(w.Tasks.PlannedMinutes))
+	fmt.Fprintf(&b, "- Done: %d slots (%s)\n", w.Tasks.DoneSlots, utils.FormatMinutes(w.Tasks.DoneMinutes))
+	if w.Tasks.AcceptedSlots == 0 {
+		b.WriteString("- Adherence: - (no accepted plans)\n")
+	} else {
+		fmt.Fprintf(&b, "- Adherence: %d%% (%d of %d accepted slots done)\n", w.Adherence, w.Tasks.AcceptedDoneSlots, w.Tasks.AcceptedSlots)
+	}
+
+	fmt.Fprintf(&b, "\n## Tomorrow (%s)\n\n%s\n", w.Tomorrow.Date, w.tomorrowStatus())
+	return b.String()
+}
+
+// WriteReport writes the Markdown report to dir as week-<To>.md and returns its path
+func (w Weekly) WriteReport(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+	path := filepath.Join(dir, "week-"+w.To+".md")
+	if err := os.WriteFile(path, []byte(w.Markdown()), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// hasCategories reports whether any habit has a category
+func (w Weekly) hasCategories() bool {
+	for _, c := range w.Categories {
+		if c.Category != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// atRisk returns the names of the habits whose streaks end unless they are marked today
+func (w Weekly) atRisk() []string {
+	var names []string
+	for _, h := range w.Habits {
+		if h.AtRisk {
+			names = append(names, h.Name)
+		}
+	}
+	return names
+}
+
+func (w Weekly) tomorrowStatus() string {
+	switch {
+	case !w.Tomorrow.HasPlan:
+		return "no plan yet"
+	case w.Tomorrow.Accepted:
+		return fmt.Sprintf("plan accepted (%d blocks)", w.Tomorrow.TotalSlots)
+	default:
+		return fmt.Sprintf("plan not accepted yet (%d blocks)", w.Tomorrow.TotalSlots)
+	}
+}
+
+// ValidMode reports whether mode is a known weekly summary mode
+func ValidMode(mode string) bool {
+	switch mode {
+	case constants.WeeklySummaryOff, constants.WeeklySummaryNotify, constants.WeeklySummaryReport, constants.WeeklySummaryBoth:
+		return true
+	}
+	return false
+}
+
+// ParseSchedule parses a schedule such as "sun 18:00" or "Sunday 18:00" into
+// a weekday and minutes since midnight
+func ParseSchedule(raw string) (time.Weekday, int, error) {
+	fields := strings.Fields(strings.ToLower(raw))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("invalid schedule %q (expected a weekday and time, e.g. 'sun 18:00')", raw)
+	}
+	minutes, err := utils.ParseTimeToMinutes(fields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid schedule %q: %w", raw, err)
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if fields[0] == name || fields[0] == name[:3] {
+			return d, minutes, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid schedule %q: unknown weekday %q", raw, fields[0])
+}
+
+// Due reports whether the weekly summary should go out at now: the setting
+// is on, it's the scheduled weekday at or after the scheduled time, and the
+// summary hasn't gone out today
+func Due(settings models.Settings, now time.Time) bool {
+	if settings.WeeklySummary == "" || settings.WeeklySummary == constants.WeeklySummaryOff {
+		return false
+	}
+	if settings.WeeklySummarySentOn == now.Format(constants.DateFormat) {
+		return false
+	}
+	weekday, at, err := ParseSchedule(settings.WeeklySummaryAt)
+	if err != nil {
+		return false
+	}
+	return now.Weekday() == weekday && now.Hour()*60+now.Minute() >= at
+}
@@ -0,0 +1,244 @@
+package summary
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+// weeklyTestNow is a Sunday evening
+var weeklyTestNow = time.Date(2025, 3, 9, 18, 0, 0, 0, time.Local)
+
+func TestBuild(t *testing.T) {
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	habits := []models.Habit{
+		{ID: "read", Name: "Read", CreatedAt: weeklyTestNow.AddDate(0, 0, -30)},
+		{ID: "run", Name: "Run", CreatedAt: weeklyTestNow.AddDate(0, 0, -30)},
+		{ID: "stretch", Name: "Stretch", CreatedAt: weeklyTestNow.AddDate(0, 0, -2)},
+	}
+	for _, h := range habits {
+		if err := store.AddHabit(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mark := func(habitID string, daysAgo ...int) {
+		for _, d := range daysAgo {
+			day := weeklyTestNow.AddDate(0, 0, -d).Format(constants.DateFormat)
+			if err := store.AddHabitEntry(models.HabitEntry{ID: habitID + day, HabitID: habitID, Day: day}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// Read is marked today and on the 9 days before; Run was marked up to
+	// yesterday, so its streak is at risk; Stretch only exists since Friday
+	mark("read", 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	mark("run", 1, 2, 3, 10)
+	mark("stretch", 2)
+
+	tomorrow := weeklyTestNow.AddDate(0, 0, 1).Format(constants.DateFormat)
+	accepted := weeklyTestNow.UTC().Format(time.RFC3339)
+	if err := store.SavePlan(models.DayPlan{
+		Date:       tomorrow,
+		AcceptedAt: &accepted,
+		Slots:      []models.Slot{{Start: "09:00", End: "10:00", TaskID: "x", Status: constants.SlotStatusAccepted}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := Build(store, weeklyTestNow, time.Monday)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if w.From != "2025-03-03" || w.To != "2025-03-09" {
+		t.Errorf("week = %s to %s, want 2025-03-03 to 2025-03-09", w.From, w.To)
+	}
+
+	want := map[string]HabitWeek{
+		"Read":    {Name: "Read", Done: 7, Target: 7, Streak: 10},
+		"Run":     {Name: "Run", Done: 3, Target: 7, Streak: 3, AtRisk: true},
+		"Stretch": {Name: "Stretch", Done: 1, Target: 3, Streak: 0},
+	}
+	if len(w.Habits) != len(want) {
+		t.Fatalf("got %d habits, want %d", len(w.Habits), len(want))
+	}
+	for _, h := range w.Habits {
+		if h != want[h.Name] {
+			t.Errorf("habit %s = %+v, want %+v", h.Name, h, want[h.Name])
+		}
+	}
+
+	if !w.Tomorrow.Accepted || w.Tomorrow.TotalSlots != 1 {
+		t.Errorf("tomorrow = %+v, want an accepted plan with 1 slot", w.Tomorrow)
+	}
+
+	msg := w.Message()
+	for _, part := range []string{"habits 11/17", "streaks at risk: Run", "tomorrow: plan accepted (1 blocks)"} {
+		if !strings.Contains(msg, part) {
+			t.Errorf("message %q doesn't mention %q", msg, part)
+		}
+	}
+
+	path, err := w.WriteReport(t.TempDir())
+	if err != nil {
+		t.Fatalf("WriteReport failed: %v", err)
+	}
+	if filepath.Base(path) != "week-2025-03-09.md" {
+		t.Errorf("report written to %s", path)
+	}
+	report, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(report), "| Run | 3/7 | 3 (at risk) |") {
+		t.Errorf("report is missing Run's row:\n%s", report)
+	}
+}
+
+func TestBuild_PausedHabit(t *testing.T) {
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	day := func(daysAgo int) string {
+		return weeklyTestNow.AddDate(0, 0, -daysAgo).Format(constants.DateFormat)
+	}
+	// Swim was paused from 4 to 2 days ago; the pause neither breaks its
+	// streak nor counts against it
+	habit := models.Habit{ID: "swim", Name: "Swim", CreatedAt: weeklyTestNow.AddDate(0, 0, -30), PausedFrom: day(4), PausedUntil: day(2)}
+	if err := store.AddHabit(habit); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []int{1, 5, 6, 7} {
+		if err := store.AddHabitEntry(models.HabitEntry{ID: "swim" + day(d), HabitID: "swim", Day: day(d)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w, err := Build(store, weeklyTestNow, time.Monday)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	want := HabitWeek{Name: "Swim", Done: 3, Target: 4, Streak: 4, AtRisk: true}
+	if len(w.Habits) != 1 || w.Habits[0] != want {
+		t.Errorf("habits = %+v, want [%+v]", w.Habits, want)
+	}
+	if len(w.Categories) != 1 || w.Categories[0].DueDays != 4 || w.Categories[0].DoneDays != 3 {
+		t.Errorf("categories = %+v, want 3 of 4 days done", w.Categories)
+	}
+
+	// Not at risk while paused
+	w, err = Build(store, weeklyTestNow.AddDate(0, 0, -3), time.Monday)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if w.Habits[0].AtRisk || w.Habits[0].Streak != 3 {
+		t.Errorf("paused habit = %+v, want a streak of 3 not at risk", w.Habits[0])
+	}
+}
+
+func TestBuild_WeekStart(t *testing.T) {
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		t.Fatal(err)
+	}
+	habit := models.Habit{ID: "read", Name: "Read", CreatedAt: weeklyTestNow.AddDate(0, 0, -30)}
+	if err := store.AddHabit(habit); err != nil {
+		t.Fatal(err)
+	}
+	// Marked today and on Monday, which is only in a week starting on Monday
+	for _, d := range []int{0, 6} {
+		day := weeklyTestNow.AddDate(0, 0, -d).Format(constants.DateFormat)
+		if err := store.AddHabitEntry(models.HabitEntry{ID: "read" + day, HabitID: "read", Day: day}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		first    time.Weekday
+		from, to string
+		done     int
+	}{
+		// Sunday starts a new week, so the latest one to end is the last
+		{"sunday start on sunday", weeklyTestNow, time.Sunday, "2025-03-02", "2025-03-08", 1},
+		{"sunday start on saturday", weeklyTestNow.AddDate(0, 0, -1), time.Sunday, "2025-03-02", "2025-03-08", 1},
+		{"monday start on sunday", weeklyTestNow, time.Monday, "2025-03-03", "2025-03-09", 2},
+		{"monday start midweek", weeklyTestNow.AddDate(0, 0, -3), time.Monday, "2025-02-24", "2025-03-02", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := Build(store, tt.now, tt.first)
+			if err != nil {
+				t.Fatalf("Build failed: %v", err)
+			}
+			if w.From != tt.from || w.To != tt.to {
+				t.Errorf("week = %s to %s, want %s to %s", w.From, w.To, tt.from, tt.to)
+			}
+			if len(w.Habits) != 1 || w.Habits[0].Done != tt.done || w.Habits[0].Target != 7 {
+				t.Errorf("habits = %+v, want %d of 7 days done", w.Habits, tt.done)
+			}
+		})
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		input   string
+		day     time.Weekday
+		minutes int
+		wantErr bool
+	}{
+		{"sun 18:00", time.Sunday, 18 * 60, false},
+		{"Friday 07:30", time.Friday, 7*60 + 30, false},
+		{"  SAT   09:05 ", time.Saturday, 9*60 + 5, false},
+		{"sun", 0, 0, true},
+		{"someday 18:00", 0, 0, true},
+		{"sun 25:00", 0, 0, true},
+	}
+	for _, tt := range tests {
+		day, minutes, err := ParseSchedule(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSchedule(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (day != tt.day || minutes != tt.minutes) {
+			t.Errorf("ParseSchedule(%q) = %v %d, want %v %d", tt.input, day, minutes, tt.day, tt.minutes)
+		}
+	}
+}
+
+func TestDue(t *testing.T) {
+	settings := models.Settings{WeeklySummary: constants.WeeklySummaryNotify, WeeklySummaryAt: "sun 18:00"}
+
+	if !Due(settings, weeklyTestNow) {
+		t.Error("expected the summary to be due at the scheduled time")
+	}
+	if Due(settings, weeklyTestNow.Add(-time.Minute)) {
+		t.Error("expected the summary not to be due before the scheduled time")
+	}
+	if Due(settings, weeklyTestNow.AddDate(0, 0, 1)) {
+		t.Error("expected the summary not to be due on another weekday")
+	}
+
+	settings.WeeklySummarySentOn = weeklyTestNow.Format(constants.DateFormat)
+	if Due(settings, weeklyTestNow.Add(time.Hour)) {
+		t.Error("expected the summary to go out once a day")
+	}
+
+	settings = models.Settings{WeeklySummary: constants.WeeklySummaryOff, WeeklySummaryAt: "sun 18:00"}
+	if Due(settings, weeklyTestNow) {
+		t.Error("expected no summary when the setting is off")
+	}
+}
@@ -0,0 +1,216 @@
+// Package telemetry times the storage calls and scheduler phases of a
+// command run with --debug. Each call is logged as it finishes, and the
+// run's totals are appended to a profile file next to the log, which
+// 'daylit debug profile' summarizes. Nothing leaves the machine.
+package telemetry
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
+)
+
+const (
+	KindStorage   = "storage"
+	KindScheduler = "scheduler"
+
+	// MaxRuns is how many command runs the profile file keeps
+	MaxRuns = 200
+)
+
+// Op is the timing of one storage call or scheduler phase over a run, or
+// over several runs once summarized
+type Op struct {
+	Kind  string        `json:"kind"`
+	Name  string        `json:"name"`
+	Calls int           `json:"calls"`
+	Total time.Duration `json:"total_ns"`
+	Max   time.Duration `json:"max_ns"`
+}
+
+// Avg returns the mean duration of a call
+func (o Op) Avg() time.Duration {
+	if o.Calls == 0 {
+		return 0
+	}
+	return o.Total / time.Duration(o.Calls)
+}
+
+// Run is the profile of one command run
+type Run struct {
+	Command  string        `json:"command"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration_ns"`
+	Ops      []Op          `json:"ops"`
+}
+
+// Recorder collects the timings of one command run. It is safe for
+// concurrent use, as notify serve and the TUI call the store from several
+// goroutines.
+type Recorder struct {
+	mu      sync.Mutex
+	command string
+	started time.Time
+	ops     map[string]*Op
+}
+
+// New starts recording a run of command
+func New(command string) *Recorder {
+	return &Recorder{command: command, started: time.Now(), ops: make(map[string]*Op)}
+}
+
+// Storage records a storage call; pass it to storage.Timed
+func (r *Recorder) Storage(op string, elapsed time.Duration) {
+	r.record(KindStorage, op, elapsed)
+}
+
+// Scheduler records a scheduler phase; pass it to Scheduler.Observe
+func (r *Recorder) Scheduler(phase string, elapsed time.Duration) {
+	r.record(KindScheduler, phase, elapsed)
+}
+
+func (r *Recorder) record(kind, name string, elapsed time.Duration) {
+	logger.Debug("Timing", "kind", kind, "op", name, "elapsed", elapsed)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := kind + "/" + name
+	op, ok := r.ops[key]
+	if !ok {
+		op = &Op{Kind: kind, Name: name}
+		r.ops[key] = op
+	}
+	op.Calls++
+	op.Total += elapsed
+	op.Max = max(op.Max, elapsed)
+}
+
+// Finish ends the run and returns its profile, slowest operations first
+func (r *Recorder) Finish() Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run := Run{Command: r.command, Started: r.started, Duration: time.Since(r.started)}
+	var storageTotal time.Duration
+	for _, op := range r.ops {
+		run.Ops = append(run.Ops, *op)
+		if op.Kind == KindStorage {
+			storageTotal += op.Total
+		}
+	}
+	sortOps(run.Ops)
+	logger.Debug("Command timing", "command", run.Command, "elapsed", run.Duration, "storage", storageTotal)
+	return run
+}
+
+// Path returns the profile file, next to the log file, or "" before the
+// logger is initialized
+func Path() string {
+	if logger.Path() == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(logger.Path()), "profile.jsonl")
+}
+
+// Load returns the runs in the profile file at path, oldest first. A
+// missing file holds no runs.
+func Load(path string) ([]Run, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var runs []Run
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			// A line cut short by a crash loses that run only
+			continue
+		}
+		runs = append(runs, run)
+	}
+	return runs, scanner.Err()
+}
+
+// Append adds run to the profile file at path, dropping the oldest runs
+// beyond MaxRuns
+func Append(path string, run Run) error {
+	runs, err := Load(path)
+	if err != nil {
+		return err
+	}
+	runs = append(runs, run)
+	if len(runs) > MaxRuns {
+		runs = runs[len(runs)-MaxRuns:]
+	}
+
+	var b strings.Builder
+	for _, r := range runs {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace profile file: %w", err)
+	}
+	return nil
+}
+
+// Summarize merges the operations of runs, slowest total first
+func Summarize(runs []Run) []Op {
+	merged := make(map[string]*Op)
+	for _, run := range runs {
+		for _, op := range run.Ops {
+			key := op.Kind + "/" + op.Name
+			m, ok := merged[key]
+			if !ok {
+				m = &Op{Kind: op.Kind, Name: op.Name}
+				merged[key] = m
+			}
+			m.Calls += op.Calls
+			m.Total += op.Total
+			m.Max = max(m.Max, op.Max)
+		}
+	}
+	ops := make([]Op, 0, len(merged))
+	for _, op := range merged {
+		ops = append(ops, *op)
+	}
+	sortOps(ops)
+	return ops
+}
+
+func sortOps(ops []Op) {
+	slices.SortFunc(ops, func(a, b Op) int {
+		if a.Total != b.Total {
+			return cmp.Compare(b.Total, a.Total)
+		}
+		return strings.Compare(a.Kind+a.Name, b.Kind+b.Name)
+	})
+}
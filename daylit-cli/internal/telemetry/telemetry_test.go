@@ -0,0 +1,99 @@
+package telemetry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	r := New("plan <date>")
+	r.Storage("GetTask", 2*time.Millisecond)
+	r.Storage("GetTask", 4*time.Millisecond)
+	r.Storage("SavePlan", 20*time.Millisecond)
+	r.Scheduler("place flexible tasks", time.Millisecond)
+
+	run := r.Finish()
+	if run.Command != "plan <date>" || run.Duration <= 0 {
+		t.Errorf("run = %+v, want the command and its elapsed time", run)
+	}
+	want := []Op{
+		{Kind: KindStorage, Name: "SavePlan", Calls: 1, Total: 20 * time.Millisecond, Max: 20 * time.Millisecond},
+		{Kind: KindStorage, Name: "GetTask", Calls: 2, Total: 6 * time.Millisecond, Max: 4 * time.Millisecond},
+		{Kind: KindScheduler, Name: "place flexible tasks", Calls: 1, Total: time.Millisecond, Max: time.Millisecond},
+	}
+	if len(run.Ops) != len(want) {
+		t.Fatalf("ops = %+v, want %+v", run.Ops, want)
+	}
+	for i := range want {
+		if run.Ops[i] != want[i] {
+			t.Errorf("op %d = %+v, want %+v", i, run.Ops[i], want[i])
+		}
+	}
+	if avg := run.Ops[1].Avg(); avg != 3*time.Millisecond {
+		t.Errorf("GetTask average = %v, want 3ms", avg)
+	}
+}
+
+func TestAppend_KeepsLatestRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "profile.jsonl")
+	if runs, err := Load(path); err != nil || len(runs) != 0 {
+		t.Fatalf("loading a missing file = %v, %v, want no runs", runs, err)
+	}
+
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	for i := range MaxRuns + 5 {
+		run := Run{Command: "today", Started: start.Add(time.Duration(i) * time.Minute), Duration: time.Second}
+		if err := Append(path, run); err != nil {
+			t.Fatalf("failed to append run %d: %v", i, err)
+		}
+	}
+
+	runs, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if len(runs) != MaxRuns {
+		t.Fatalf("kept %d runs, want %d", len(runs), MaxRuns)
+	}
+	if !runs[0].Started.Equal(start.Add(5 * time.Minute)) {
+		t.Errorf("oldest run kept started at %v, want the sixth", runs[0].Started)
+	}
+}
+
+func TestLoad_SkipsTruncatedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profile.jsonl")
+	data := `{"command":"today","duration_ns":1000}` + "\n" + `{"command":"pla`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	runs, err := Load(path)
+	if err != nil || len(runs) != 1 || runs[0].Command != "today" {
+		t.Errorf("runs = %+v, %v, want the complete run only", runs, err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	runs := []Run{
+		{Command: "today", Ops: []Op{
+			{Kind: KindStorage, Name: "GetPlan", Calls: 1, Total: 5 * time.Millisecond, Max: 5 * time.Millisecond},
+			{Kind: KindStorage, Name: "Load", Calls: 1, Total: 30 * time.Millisecond, Max: 30 * time.Millisecond},
+		}},
+		{Command: "plan <date>", Ops: []Op{
+			{Kind: KindStorage, Name: "GetPlan", Calls: 3, Total: 40 * time.Millisecond, Max: 25 * time.Millisecond},
+		}},
+	}
+
+	ops := Summarize(runs)
+	if len(ops) != 2 {
+		t.Fatalf("ops = %+v, want GetPlan and Load", ops)
+	}
+	want := Op{Kind: KindStorage, Name: "GetPlan", Calls: 4, Total: 45 * time.Millisecond, Max: 25 * time.Millisecond}
+	if ops[0] != want {
+		t.Errorf("slowest op = %+v, want %+v", ops[0], want)
+	}
+	if ops[1].Name != "Load" {
+		t.Errorf("second op = %+v, want Load", ops[1])
+	}
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/cheatsheet"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/i18n"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
+)
+
+// tabCommands are the top-level CLI commands the help overlay shows on each
+// tab, for doing from the terminal what the tab does
+var tabCommands = map[constants.SessionState][]string{
+	constants.StateNow:      {"now", "start", "stop", "done", "feedback"},
+	constants.StatePlan:     {"plan", "plans", "reflow", "remind", "day"},
+	constants.StateCalendar: {"day", "export"},
+	constants.StateWeek:     {"summary", "stats"},
+	constants.StateTasks:    {"task", "project", "pool", "import"},
+	constants.StateHabits:   {"habit"},
+	constants.StateOT:       {"ot"},
+	constants.StateAlerts:   {"alert"},
+	constants.StateInbox:    {"capture", "inbox"},
+	constants.StateTrash:    {"restore", "purge"},
+	constants.StateSettings: {"settings", "config", "keys", "context"},
+}
+
+// maxCheatsheetEntries keeps the overlay within a small terminal
+const maxCheatsheetEntries = 12
+
+// viewCheatsheet shows the CLI commands for the current tab while the full
+// help is open, or "" when there are none
+func (m Model) viewCheatsheet() string {
+	var entries []cheatsheet.Entry
+	for _, word := range tabCommands[m.State] {
+		entries = append(entries, cheatsheet.Filter(m.Cheatsheet, word)...)
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+
+	more := 0
+	if len(entries) > maxCheatsheetEntries {
+		more = len(entries) - maxCheatsheetEntries
+		entries = entries[:maxCheatsheetEntries]
+	}
+
+	width := 0
+	for _, e := range entries {
+		width = max(width, len(e.Example))
+	}
+	muted := lipgloss.NewStyle().Foreground(theme.Current().Muted)
+	line := lipgloss.NewStyle().MaxWidth(max(m.Width-4, 20))
+
+	lines := []string{
+		lipgloss.NewStyle().Foreground(theme.Current().Accent).Bold(true).Render(i18n.T("cheatsheet.title")),
+		"",
+	}
+	for _, e := range entries {
+		lines = append(lines, line.Render(fmt.Sprintf("%-*s  %s", width, e.Example, muted.Render(e.Help))))
+	}
+	if more > 0 {
+		lines = append(lines, muted.Render(i18n.T("cheatsheet.more", more)))
+	}
+	lines = append(lines, "",
+		muted.Render(i18n.T("cheatsheet.hint", tabCommands[m.State][0])),
+	)
+
+	return lipgloss.Place(m.Width, m.Height-4,
+		lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Left, lines...),
+	)
+}
@@ -8,6 +8,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
 )
 
 type AddAlertMsg struct{}
@@ -22,6 +23,9 @@ type Item struct {
 
 func (i Item) Title() string {
 	title := fmt.Sprintf("⏰ %s at %s", i.Alert.Message, i.Alert.Time)
+	if i.Alert.IsCron() || i.Alert.IsLeaveBy() {
+		title = fmt.Sprintf("⏰ %s", i.Alert.Message)
+	}
 	if !i.Alert.Active {
 		title = "[INACTIVE] " + title
 	}
@@ -66,18 +70,13 @@ func New(alerts []models.Alert, width, height int) Model {
 		items[i] = Item{Alert: a}
 	}
 
-	l := list.New(items, list.NewDefaultDelegate(), width, height)
+	l := list.New(items, theme.ListDelegate(), width, height)
 	l.Title = "Alerts"
 	l.SetShowTitle(false)
 	l.SetShowHelp(false)
 
 	keys := DefaultKeyMap()
-	l.AdditionalShortHelpKeys = func() []key.Binding {
-		return []key.Binding{keys.Add, keys.Delete}
-	}
-	l.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{keys.Add, keys.Delete}
-	}
+	setHelpKeys(&l, keys)
 
 	return Model{
 		list: l,
@@ -130,3 +129,30 @@ func (m Model) View() string {
 func (m *Model) SetSize(width, height int) {
 	m.list.SetSize(width, height)
 }
+
+// SetKeyMap replaces the action key bindings
+func (m *Model) SetKeyMap(keys KeyMap) {
+	m.keys = keys
+	setHelpKeys(&m.list, keys)
+}
+
+// SetCursorKeys replaces the keys that move the list cursor
+func (m *Model) SetCursorKeys(up, down key.Binding) {
+	m.list.KeyMap.CursorUp = up
+	m.list.KeyMap.CursorDown = down
+}
+
+// setHelpKeys registers the action bindings with the list's help views
+func setHelpKeys(l *list.Model, keys KeyMap) {
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Add, keys.Delete}
+	}
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Add, keys.Delete}
+	}
+}
+
+// RefreshStyles rebuilds the list delegate from the active theme
+func (m *Model) RefreshStyles() {
+	m.list.SetDelegate(theme.ListDelegate())
+}
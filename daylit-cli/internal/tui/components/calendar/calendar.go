@@ -0,0 +1,368 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/i18n"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+const cellWidth = 11
+
+func titleStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Current().Accent).
+		MarginBottom(1)
+}
+
+func headerStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Bold(true).
+		Width(cellWidth)
+}
+
+func cellStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Text).
+		Width(cellWidth)
+}
+
+func outsideStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Faint).
+		Width(cellWidth)
+}
+
+func todayStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Accent).
+		Bold(true).
+		Width(cellWidth)
+}
+
+func selectedStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().OnHighlight).
+		Background(theme.Current().Highlight).
+		Width(cellWidth)
+}
+
+func legendStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Italic(true).
+		MarginTop(1)
+}
+
+// SelectDayMsg is emitted when the user chooses a day to open in the Plan tab
+type SelectDayMsg struct {
+	Date string
+}
+
+// MonthChangedMsg is emitted when the visible month changes and summaries must be reloaded
+type MonthChangedMsg struct {
+	Start string
+	End   string
+}
+
+type KeyMap struct {
+	Left      key.Binding
+	Right     key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	PrevMonth key.Binding
+	NextMonth key.Binding
+	Today     key.Binding
+	Select    key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Left: key.NewBinding(
+			key.WithKeys("left"),
+			key.WithHelp("←", "prev day"),
+		),
+		Right: key.NewBinding(
+			key.WithKeys("right"),
+			key.WithHelp("→", "next day"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "prev week"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "next week"),
+		),
+		PrevMonth: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "prev month"),
+		),
+		NextMonth: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next month"),
+		),
+		Today: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "today"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "open plan"),
+		),
+	}
+}
+
+type Model struct {
+	keys      KeyMap
+	clock     clock.Clock // The system clock when nil
+	cursor    time.Time
+	summaries map[string]models.DaySummary
+	vacations []models.Vacation
+	first     time.Weekday // Day the weeks of the grid start on
+	width     int
+	height    int
+}
+
+func New(today time.Time, summaries []models.DaySummary, width, height int) Model {
+	m := Model{
+		keys:   DefaultKeyMap(),
+		cursor: time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local),
+		width:  width,
+		height: height,
+	}
+	m.SetSummaries(summaries)
+	return m
+}
+
+// SetSummaries replaces the day summaries used to render markers
+func (m *Model) SetSummaries(summaries []models.DaySummary) {
+	m.summaries = make(map[string]models.DaySummary, len(summaries))
+	for _, s := range summaries {
+		m.summaries[s.Date] = s
+	}
+}
+
+// SetVacations replaces the vacations marked on the calendar
+func (m *Model) SetVacations(vacations []models.Vacation) {
+	m.vacations = vacations
+}
+
+// SetClock sets the clock that says which day is today
+func (m *Model) SetClock(clk clock.Clock) {
+	m.clock = clk
+}
+
+// SetWeekStart sets the day the weeks of the grid start on
+func (m *Model) SetWeekStart(first time.Weekday) {
+	m.first = first
+}
+
+// SetKeyMap replaces the key bindings
+func (m *Model) SetKeyMap(keys KeyMap) {
+	m.keys = keys
+}
+
+// Keys returns the calendar key bindings for help display
+func (m Model) Keys() KeyMap {
+	return m.keys
+}
+
+// Cursor returns the day under the cursor
+func (m Model) Cursor() time.Time {
+	return m.cursor
+}
+
+// SelectedDate returns the date under the cursor in YYYY-MM-DD format
+func (m Model) SelectedDate() string {
+	return m.cursor.Format(constants.DateFormat)
+}
+
+// MonthRange returns the first and last dates of the visible month in YYYY-MM-DD format
+func MonthRange(t time.Time) (string, string) {
+	first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+	last := first.AddDate(0, 1, -1)
+	return first.Format(constants.DateFormat), last.Format(constants.DateFormat)
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	prev := m.cursor
+	switch {
+	case key.Matches(keyMsg, m.keys.Left):
+		m.cursor = m.cursor.AddDate(0, 0, -1)
+	case key.Matches(keyMsg, m.keys.Right):
+		m.cursor = m.cursor.AddDate(0, 0, 1)
+	case key.Matches(keyMsg, m.keys.Up):
+		m.cursor = m.cursor.AddDate(0, 0, -7)
+	case key.Matches(keyMsg, m.keys.Down):
+		m.cursor = m.cursor.AddDate(0, 0, 7)
+	case key.Matches(keyMsg, m.keys.PrevMonth):
+		m.cursor = addMonthsClamped(m.cursor, -1)
+	case key.Matches(keyMsg, m.keys.NextMonth):
+		m.cursor = addMonthsClamped(m.cursor, 1)
+	case key.Matches(keyMsg, m.keys.Today):
+		now := clock.Or(m.clock).Now()
+		m.cursor = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	case key.Matches(keyMsg, m.keys.Select):
+		date := m.SelectedDate()
+		return m, func() tea.Msg { return SelectDayMsg{Date: date} }
+	default:
+		return m, nil
+	}
+
+	if prev.Year() != m.cursor.Year() || prev.Month() != m.cursor.Month() {
+		start, end := MonthRange(m.cursor)
+		return m, func() tea.Msg { return MonthChangedMsg{Start: start, End: end} }
+	}
+	return m, nil
+}
+
+// addMonthsClamped moves t by the given number of months, clamping the day
+// to the last day of the target month instead of overflowing into the next one
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, months, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.Local)
+}
+
+func (m Model) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle().Render(i18n.Format(m.cursor, i18n.LayoutMonthYear)))
+	b.WriteString("\n")
+
+	var headers []string
+	for i := 0; i < 7; i++ {
+		wd := (m.first + time.Weekday(i)) % 7
+		headers = append(headers, headerStyle().Render(i18n.WeekdayShort(wd)))
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, headers...))
+	b.WriteString("\n")
+
+	today := clock.Or(m.clock).Now().Format(constants.DateFormat)
+	first := time.Date(m.cursor.Year(), m.cursor.Month(), 1, 0, 0, 0, 0, time.Local)
+	day := utils.StartOfWeek(first, m.first)
+
+	for week := 0; week < 6; week++ {
+		// Stop once the grid has moved past the visible month
+		if week > 0 && day.Month() != m.cursor.Month() {
+			break
+		}
+		var cells []string
+		for i := 0; i < 7; i++ {
+			cells = append(cells, m.renderCell(day, today))
+			day = day.AddDate(0, 0, 1)
+		}
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(legendStyle().Render(i18n.T("calendar.legend")))
+	b.WriteString("\n")
+	b.WriteString(m.renderSelectedDetail())
+
+	return b.String()
+}
+
+func (m Model) renderCell(day time.Time, today string) string {
+	date := day.Format(constants.DateFormat)
+
+	marker := " "
+	detail := ""
+	if s, ok := m.summaries[date]; ok {
+		if s.HasPlan {
+			if s.Accepted {
+				marker = "●"
+			} else {
+				marker = "○"
+			}
+			if s.TotalSlots > 0 {
+				detail = fmt.Sprintf("%d%%", s.FeedbackPercent())
+			}
+		}
+		if s.HabitsCompleted > 0 {
+			if detail != "" {
+				detail += " "
+			}
+			detail += fmt.Sprintf("✓%d", s.HabitsCompleted)
+		}
+	}
+	if _, away := models.VacationOn(m.vacations, date); away && marker == " " {
+		marker = "✈"
+	}
+
+	content := fmt.Sprintf("%2d %s\n%s", day.Day(), marker, detail)
+
+	style := cellStyle()
+	switch {
+	case date == m.SelectedDate():
+		style = selectedStyle()
+	case day.Month() != m.cursor.Month():
+		style = outsideStyle()
+	case date == today:
+		style = todayStyle()
+	}
+	return style.Render(content)
+}
+
+func (m Model) renderSelectedDetail() string {
+	date := m.SelectedDate()
+	var parts []string
+	if v, away := models.VacationOn(m.vacations, date); away {
+		vacation := fmt.Sprintf("vacation %s..%s", v.Start, v.End)
+		if v.Note != "" {
+			vacation += " (" + v.Note + ")"
+		}
+		parts = append(parts, vacation)
+	}
+
+	s, ok := m.summaries[date]
+	if !ok {
+		if len(parts) > 0 {
+			return fmt.Sprintf("%s: %s", date, strings.Join(parts, ", "))
+		}
+		return fmt.Sprintf("%s: no activity", date)
+	}
+
+	if s.HasPlan {
+		status := "planned"
+		if s.Accepted {
+			status = "accepted"
+		}
+		parts = append(parts, fmt.Sprintf("plan %s, %d/%d slots rated", status, s.SlotsWithFeedback, s.TotalSlots))
+	} else {
+		parts = append(parts, "no plan")
+	}
+	parts = append(parts, fmt.Sprintf("%d habit(s) done", s.HabitsCompleted))
+	return fmt.Sprintf("%s: %s", date, strings.Join(parts, ", "))
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
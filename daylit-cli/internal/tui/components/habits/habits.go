@@ -1,14 +1,14 @@
 package habits
 
 import (
-	"time"
+	"fmt"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 
-	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
 )
 
 type AddHabitMsg struct{}
@@ -37,6 +37,7 @@ type Item struct {
 	Habit     models.Habit
 	IsMarked  bool
 	IsDeleted bool
+	IsPaused  bool
 }
 
 func (i Item) Title() string {
@@ -47,6 +48,8 @@ func (i Item) Title() string {
 		title = "[ARCHIVED] " + title
 	} else if i.IsMarked {
 		title = "✓ " + title
+	} else if i.IsPaused {
+		title = "‖ " + title
 	} else {
 		title = "○ " + title
 	}
@@ -63,11 +66,35 @@ func (i Item) Description() string {
 	if i.IsMarked {
 		return "completed today"
 	}
+	if i.IsPaused {
+		return "paused until " + i.Habit.PausedUntil
+	}
 	return "not completed today"
 }
 
 func (i Item) FilterValue() string { return i.Habit.Name }
 
+// GroupItem heads the habits of a category. It is shown when any habit has
+// a category and can't be selected.
+type GroupItem struct {
+	Category string
+	Done     int // Active habits in the category marked today
+	Total    int // Active habits in the category
+}
+
+func (g GroupItem) Title() string {
+	name := g.Category
+	if name == "" {
+		name = "uncategorized"
+	}
+	return fmt.Sprintf("── %s · %d/%d today", name, g.Done, g.Total)
+}
+
+func (g GroupItem) Description() string { return "" }
+
+// FilterValue is empty so headings drop out while filtering
+func (g GroupItem) FilterValue() string { return "" }
+
 type KeyMap struct {
 	Add     key.Binding
 	Mark    key.Binding
@@ -113,63 +140,107 @@ type Model struct {
 	today        string
 }
 
-func New(habits []models.Habit, entries []models.HabitEntry, width, height int) Model {
-	today := time.Now().Format(constants.DateFormat)
+// New creates the habits list for today (YYYY-MM-DD), with entries being
+// the habits done that day
+func New(habits []models.Habit, entries []models.HabitEntry, today string, width, height int) Model {
 	markedHabits := make(map[string]bool)
 	for _, entry := range entries {
 		markedHabits[entry.HabitID] = true
 	}
 
-	items := make([]list.Item, len(habits))
-	for i, h := range habits {
-		isDeleted := h.DeletedAt != nil
-		isMarked := markedHabits[h.ID] && !isDeleted && h.ArchivedAt == nil
-		items[i] = Item{
-			Habit:     h,
-			IsMarked:  isMarked,
-			IsDeleted: isDeleted,
-		}
-	}
-
-	l := list.New(items, list.NewDefaultDelegate(), width, height)
+	l := list.New(buildItems(habits, markedHabits, today), theme.ListDelegate(), width, height)
 	l.Title = "Habits"
 	l.SetShowTitle(false)
 	l.SetShowHelp(false)
 
 	keys := DefaultKeyMap()
-	l.AdditionalShortHelpKeys = func() []key.Binding {
-		return []key.Binding{keys.Add, keys.Mark, keys.Unmark, keys.Archive, keys.Delete, keys.Restore}
-	}
-	l.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{keys.Add, keys.Mark, keys.Unmark, keys.Archive, keys.Delete, keys.Restore}
-	}
+	setHelpKeys(&l, keys)
 
-	return Model{
+	m := Model{
 		list:         l,
 		keys:         keys,
 		markedHabits: markedHabits,
 		today:        today,
 	}
+	m.skipGroup(0)
+	return m
 }
 
-func (m *Model) SetHabits(habits []models.Habit, entries []models.HabitEntry) {
-	m.today = time.Now().Format(constants.DateFormat)
+// SetHabits replaces the habits and the entries of today (YYYY-MM-DD)
+func (m *Model) SetHabits(habits []models.Habit, entries []models.HabitEntry, today string) {
+	m.today = today
 	m.markedHabits = make(map[string]bool)
 	for _, entry := range entries {
 		m.markedHabits[entry.HabitID] = true
 	}
 
-	items := make([]list.Item, len(habits))
-	for i, h := range habits {
+	m.list.SetItems(buildItems(habits, m.markedHabits, m.today))
+	m.skipGroup(m.list.Index())
+}
+
+// buildItems lists the habits, grouped under a heading per category when
+// any habit has one
+func buildItems(habits []models.Habit, marked map[string]bool, today string) []list.Item {
+	item := func(h models.Habit) Item {
 		isDeleted := h.DeletedAt != nil
-		isMarked := m.markedHabits[h.ID] && !isDeleted && h.ArchivedAt == nil
-		items[i] = Item{
+		return Item{
 			Habit:     h,
-			IsMarked:  isMarked,
+			IsMarked:  marked[h.ID] && !isDeleted && h.ArchivedAt == nil,
 			IsDeleted: isDeleted,
+			IsPaused:  h.PausedOn(today),
+		}
+	}
+
+	groups := models.GroupHabits(habits)
+	if len(groups) <= 1 && (len(groups) == 0 || groups[0].Category == "") {
+		items := make([]list.Item, len(habits))
+		for i, h := range habits {
+			items[i] = item(h)
+		}
+		return items
+	}
+
+	items := make([]list.Item, 0, len(habits)+len(groups))
+	for _, group := range groups {
+		header := GroupItem{Category: group.Category}
+		for _, h := range group.Habits {
+			if h.DeletedAt == nil && h.ArchivedAt == nil && !h.PausedOn(today) {
+				header.Total++
+				if marked[h.ID] {
+					header.Done++
+				}
+			}
+		}
+		items = append(items, header)
+		for _, h := range group.Habits {
+			items = append(items, item(h))
+		}
+	}
+	return items
+}
+
+// skipGroup moves the cursor off a group heading, in the direction it was
+// moving from the index before
+func (m *Model) skipGroup(before int) {
+	if _, ok := m.list.SelectedItem().(GroupItem); !ok {
+		return
+	}
+	if m.list.Index() < before && m.list.Index() > 0 {
+		m.list.CursorUp()
+	} else {
+		m.list.CursorDown()
+	}
+}
+
+// SelectHabit moves the cursor to the habit with the given ID, if it is listed
+func (m *Model) SelectHabit(id string) {
+	m.list.ResetFilter()
+	for i, item := range m.list.Items() {
+		if it, ok := item.(Item); ok && it.Habit.ID == id {
+			m.list.Select(i)
+			return
 		}
 	}
-	m.list.SetItems(items)
 }
 
 func (m Model) Init() tea.Cmd {
@@ -220,7 +291,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 	}
 
+	before := m.list.Index()
 	m.list, cmd = m.list.Update(msg)
+	m.skipGroup(before)
 	return m, cmd
 }
 
@@ -234,3 +307,30 @@ func (m Model) View() string {
 func (m *Model) SetSize(width, height int) {
 	m.list.SetSize(width, height)
 }
+
+// SetKeyMap replaces the action key bindings
+func (m *Model) SetKeyMap(keys KeyMap) {
+	m.keys = keys
+	setHelpKeys(&m.list, keys)
+}
+
+// SetCursorKeys replaces the keys that move the list cursor
+func (m *Model) SetCursorKeys(up, down key.Binding) {
+	m.list.KeyMap.CursorUp = up
+	m.list.KeyMap.CursorDown = down
+}
+
+// setHelpKeys registers the action bindings with the list's help views
+func setHelpKeys(l *list.Model, keys KeyMap) {
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Add, keys.Mark, keys.Unmark, keys.Archive, keys.Delete, keys.Restore}
+	}
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Add, keys.Mark, keys.Unmark, keys.Archive, keys.Delete, keys.Restore}
+	}
+}
+
+// RefreshStyles rebuilds the list delegate from the active theme
+func (m *Model) RefreshStyles() {
+	m.list.SetDelegate(theme.ListDelegate())
+}
@@ -0,0 +1,64 @@
+package habits
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestGroupedHabits(t *testing.T) {
+	habits := []models.Habit{
+		{ID: "run", Name: "Run", Category: "health"},
+		{ID: "dishes", Name: "Dishes", Category: "chores"},
+		{ID: "floss", Name: "Floss", Category: "health"},
+	}
+	entries := []models.HabitEntry{{HabitID: "run"}}
+	m := New(habits, entries, "2025-06-02", 80, 40)
+
+	var titles []string
+	for _, item := range m.list.Items() {
+		titles = append(titles, item.(interface{ Title() string }).Title())
+	}
+	want := []string{"── chores · 0/1 today", "○ Dishes", "── health · 1/2 today", "✓ Run", "○ Floss"}
+	if len(titles) != len(want) {
+		t.Fatalf("items = %q, want %q", titles, want)
+	}
+	for i := range want {
+		if titles[i] != want[i] {
+			t.Errorf("item %d = %q, want %q", i, titles[i], want[i])
+		}
+	}
+
+	// The cursor starts on the first habit and steps over headings
+	selected := func() string {
+		item, ok := m.list.SelectedItem().(Item)
+		if !ok {
+			t.Fatalf("a heading is selected at %d", m.list.Index())
+		}
+		return item.Habit.Name
+	}
+	if got := selected(); got != "Dishes" {
+		t.Errorf("initial selection = %s, want Dishes", got)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := selected(); got != "Run" {
+		t.Errorf("after down = %s, want Run", got)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := selected(); got != "Dishes" {
+		t.Errorf("after up = %s, want Dishes", got)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := selected(); got != "Dishes" {
+		t.Errorf("up from the first habit = %s, want Dishes", got)
+	}
+}
+
+func TestUngroupedHabits(t *testing.T) {
+	m := New([]models.Habit{{ID: "run", Name: "Run"}, {ID: "read", Name: "Read"}}, nil, "2025-06-02", 80, 40)
+	if n := len(m.list.Items()); n != 2 {
+		t.Errorf("expected no headings without categories, got %d items", n)
+	}
+}
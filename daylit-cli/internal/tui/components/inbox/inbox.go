@@ -0,0 +1,193 @@
+package inbox
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/i18n"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
+)
+
+// ToTaskMsg, ToOTMsg and ToAlertMsg turn an item into a task, today's One
+// Thing or an alert
+type ToTaskMsg struct {
+	Item models.InboxItem
+}
+
+type ToOTMsg struct {
+	Item models.InboxItem
+}
+
+type ToAlertMsg struct {
+	Item models.InboxItem
+}
+
+type DismissMsg struct {
+	ID string
+}
+
+type Item struct {
+	InboxItem models.InboxItem
+	today     string // Today's date, so items captured today show the time only
+}
+
+func (i Item) Title() string { return "📥 " + i.InboxItem.Text }
+
+func (i Item) Description() string {
+	created := i.InboxItem.CreatedAt.Local()
+	if created.Format(constants.DateFormat) == i.today {
+		return "Captured today at " + created.Format("15:04")
+	}
+	return i18n.T("inbox.captured", i18n.Format(created, i18n.LayoutTimestamp))
+}
+
+func (i Item) FilterValue() string { return i.InboxItem.Text }
+
+type KeyMap struct {
+	Task    key.Binding
+	OT      key.Binding
+	Alert   key.Binding
+	Dismiss key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Task: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "to task"),
+		),
+		OT: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "to one thing"),
+		),
+		Alert: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "to alert"),
+		),
+		Dismiss: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "dismiss"),
+		),
+	}
+}
+
+type Model struct {
+	list  list.Model
+	keys  KeyMap
+	clock clock.Clock // The system clock when nil
+	items []models.InboxItem
+}
+
+func New(items []models.InboxItem, width, height int) Model {
+	l := list.New(listItems(items, nil), theme.ListDelegate(), width, height)
+	l.Title = "Inbox"
+	l.SetShowTitle(false)
+	l.SetShowHelp(false)
+
+	keys := DefaultKeyMap()
+	setHelpKeys(&l, keys)
+
+	return Model{
+		list:  l,
+		keys:  keys,
+		items: items,
+	}
+}
+
+func listItems(items []models.InboxItem, clk clock.Clock) []list.Item {
+	today := clock.Or(clk).Now().Format(constants.DateFormat)
+	listed := make([]list.Item, len(items))
+	for i, item := range items {
+		listed[i] = Item{InboxItem: item, today: today}
+	}
+	return listed
+}
+
+// SetClock sets the clock that says which day is today
+func (m *Model) SetClock(clk clock.Clock) {
+	m.clock = clk
+	m.list.SetItems(listItems(m.items, clk))
+}
+
+func (m *Model) SetItems(items []models.InboxItem) {
+	m.items = items
+	m.list.SetItems(listItems(items, m.clock))
+}
+
+// Len returns the number of items in the inbox
+func (m Model) Len() int {
+	return len(m.list.Items())
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// Don't match if we're filtering
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+
+		item, ok := m.list.SelectedItem().(Item)
+		if !ok {
+			break
+		}
+		switch {
+		case key.Matches(msg, m.keys.Task):
+			return m, func() tea.Msg { return ToTaskMsg{Item: item.InboxItem} }
+		case key.Matches(msg, m.keys.OT):
+			return m, func() tea.Msg { return ToOTMsg{Item: item.InboxItem} }
+		case key.Matches(msg, m.keys.Alert):
+			return m, func() tea.Msg { return ToAlertMsg{Item: item.InboxItem} }
+		case key.Matches(msg, m.keys.Dismiss):
+			return m, func() tea.Msg { return DismissMsg{ID: item.InboxItem.ID} }
+		}
+	}
+
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m Model) View() string {
+	return m.list.View()
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.list.SetSize(width, height)
+}
+
+// SetKeyMap replaces the action key bindings
+func (m *Model) SetKeyMap(keys KeyMap) {
+	m.keys = keys
+	setHelpKeys(&m.list, keys)
+}
+
+// SetCursorKeys replaces the keys that move the list cursor
+func (m *Model) SetCursorKeys(up, down key.Binding) {
+	m.list.KeyMap.CursorUp = up
+	m.list.KeyMap.CursorDown = down
+}
+
+// setHelpKeys registers the action bindings with the list's help views
+func setHelpKeys(l *list.Model, keys KeyMap) {
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Task, keys.OT, keys.Alert, keys.Dismiss}
+	}
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Task, keys.OT, keys.Alert, keys.Dismiss}
+	}
+}
+
+// RefreshStyles rebuilds the list delegate from the active theme
+func (m *Model) RefreshStyles() {
+	m.list.SetDelegate(theme.ListDelegate())
+}
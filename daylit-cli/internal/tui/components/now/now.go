@@ -6,43 +6,65 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/julianstephens/daylit/daylit-cli/internal/i18n"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
 	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
 )
 
-var (
-	titleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("205")).
-			Bold(true).
-			Padding(1, 2).
-			Align(lipgloss.Center)
-
-	timeStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
-			Padding(0, 1)
-
-	taskNameStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252")).
-			Bold(true).
-			Padding(1, 0).
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("62")).
-			Width(40).
-			Align(lipgloss.Center)
-)
+// progressBarWidth matches the width of the task name box
+const progressBarWidth = 40
+
+func titleStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Accent).
+		Bold(true).
+		Padding(1, 2).
+		Align(lipgloss.Center)
+}
+
+func timeStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Padding(0, 1)
+}
+
+func mutedStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted)
+}
+
+func progressStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Accent)
+}
+
+func taskNameStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Text).
+		Bold(true).
+		Padding(1, 0).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Current().Highlight).
+		Width(40).
+		Align(lipgloss.Center)
+}
 
 type Model struct {
-	Plan   *models.DayPlan
-	Tasks  map[string]models.Task
-	Time   time.Time
-	width  int
-	height int
+	Plan     *models.DayPlan
+	Previous *models.DayPlan  // yesterday's plan, still running after midnight when the day ends late
+	Window   models.DayWindow // day boundaries used to place slots that cross midnight
+	Tasks    map[string]models.Task
+	Time     time.Time
+	width    int
+	height   int
 }
 
-func New() Model {
+// New creates the Now view showing the time t
+func New(t time.Time) Model {
 	return Model{
 		Tasks: make(map[string]models.Task),
-		Time:  time.Now(),
+		Time:  t,
 	}
 }
 
@@ -73,31 +95,36 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 }
 
 func (m Model) View() string {
-	if m.Plan == nil {
-		return titleStyle.Render("No plan for today.")
+	slots, countdown := m.countdown()
+	if m.Plan == nil && countdown.Current < 0 {
+		return titleStyle().Render(i18n.T("now.empty"))
 	}
 
-	currentSlot := m.getCurrentSlot()
-
 	var content string
-	if currentSlot == nil {
-		content = "Free time"
+	if countdown.Current < 0 {
+		content = i18n.T("now.free_time")
 	} else {
-		taskName := "Unknown Task"
-		if t, ok := m.Tasks[currentSlot.TaskID]; ok {
-			taskName = t.Name
-		}
-
+		currentSlot := slots[countdown.Current]
 		content = lipgloss.JoinVertical(lipgloss.Center,
-			timeStyle.Render(fmt.Sprintf("%s - %s", currentSlot.Start, currentSlot.End)),
-			taskNameStyle.Render(taskName),
-			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(string(currentSlot.Status)),
+			timeStyle().Render(fmt.Sprintf("%s - %s", currentSlot.Start, currentSlot.End)),
+			taskNameStyle().Render(m.taskName(currentSlot.TaskID)),
+			mutedStyle().Render(string(currentSlot.Status)),
+			"",
+			progressStyle().Render(countdown.ProgressBar(progressBarWidth)),
+			mutedStyle().Render(i18n.T("now.remaining", utils.FormatMinutes(countdown.Remaining))),
 		)
 	}
 
+	next := i18n.T("now.next_none")
+	if countdown.Next >= 0 {
+		next = i18n.T("now.next", m.taskName(slots[countdown.Next].TaskID), utils.FormatMinutes(countdown.UntilNext))
+	}
+
 	content = lipgloss.JoinVertical(lipgloss.Center,
-		titleStyle.Render(fmt.Sprintf("Now: %02d:%02d", m.Time.Hour(), m.Time.Minute())),
+		titleStyle().Render(i18n.T("now.title", m.Time.Hour(), m.Time.Minute())),
 		content,
+		"",
+		mutedStyle().Render(next),
 	)
 
 	if m.width > 0 && m.height > 0 {
@@ -106,35 +133,62 @@ func (m Model) View() string {
 	return content
 }
 
-func (m *Model) SetPlan(plan models.DayPlan, tasks []models.Task) {
-	m.Plan = &plan
+func (m Model) taskName(id string) string {
+	if t, ok := m.Tasks[id]; ok {
+		return t.Name
+	}
+	return i18n.T("now.unknown_task")
+}
+
+// ClearPlan shows the empty state when today has no plan
+func (m *Model) ClearPlan() {
+	m.Plan = nil
+}
+
+// SetPrevious sets yesterday's plan, or nil if there is none
+func (m *Model) SetPrevious(plan *models.DayPlan, tasks []models.Task) {
+	m.Previous = plan
 	for _, t := range tasks {
 		m.Tasks[t.ID] = t
 	}
 }
 
-func (m Model) getCurrentSlot() *models.Slot {
-	if m.Plan == nil {
-		return nil
+// SetWindow sets the day boundaries
+func (m *Model) SetWindow(window models.DayWindow) {
+	m.Window = window
+}
+
+func (m *Model) SetPlan(plan models.DayPlan, tasks []models.Task) {
+	m.Plan = &plan
+	for _, t := range tasks {
+		m.Tasks[t.ID] = t
 	}
+}
 
+// countdown places the current time among the slots of today's plan, or of
+// yesterday's while a late day runs past midnight, and returns the slots it
+// was placed in
+func (m Model) countdown() ([]models.Slot, models.Countdown) {
 	currentMinutes := m.Time.Hour()*60 + m.Time.Minute()
 
-	for i := range m.Plan.Slots {
-		slot := &m.Plan.Slots[i]
-
-		startMinutes, err := utils.ParseTimeToMinutes(slot.Start)
-		if err != nil {
-			continue
-		}
-		endMinutes, err := utils.ParseTimeToMinutes(slot.End)
-		if err != nil {
-			continue
+	var today models.Countdown
+	if m.Plan != nil {
+		today = m.Window.CountdownAt(m.Plan.Slots, currentMinutes, nil)
+		if today.Current >= 0 {
+			return m.Plan.Slots, today
 		}
+	}
 
-		if startMinutes <= currentMinutes && currentMinutes < endMinutes {
-			return slot
+	// After midnight, yesterday's plan is still running if the day ends late
+	if m.Previous != nil && m.Window.CrossesMidnight() && currentMinutes < m.Window.Start {
+		prev := m.Window.CountdownAt(m.Previous.Slots, currentMinutes+models.MinutesPerDay, nil)
+		if prev.Current >= 0 {
+			return m.Previous.Slots, prev
 		}
 	}
-	return nil
+
+	if m.Plan == nil {
+		return nil, models.Countdown{Current: -1, Next: -1}
+	}
+	return m.Plan.Slots, today
 }
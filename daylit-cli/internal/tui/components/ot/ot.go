@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
 )
 
 type EditOTMsg struct{}
@@ -19,30 +20,36 @@ type Model struct {
 	viewport viewport.Model
 }
 
-var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("205")).
-			MarginBottom(1)
-
-	otTitleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252")).
-			Bold(true).
-			MarginBottom(1)
-
-	noteStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
-			Italic(true).
-			MarginTop(1)
-
-	emptyStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
-			Italic(true)
-
-	sectionStyle = lipgloss.NewStyle().
-			MarginTop(1).
-			MarginBottom(1)
-)
+func titleStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Current().Accent).
+		MarginBottom(1)
+}
+
+func otTitleStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Text).
+		Bold(true).
+		MarginBottom(1)
+}
+
+func noteStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Italic(true).
+		MarginTop(1)
+}
+
+func emptyStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Italic(true)
+}
+
+var sectionStyle = lipgloss.NewStyle().
+	MarginTop(1).
+	MarginBottom(1)
 
 func New(entry *models.OTEntry, width, height int) Model {
 	m := Model{
@@ -92,30 +99,35 @@ func (m *Model) SetSize(width, height int) {
 	m.updateViewportContent()
 }
 
+// RefreshStyles re-renders the view content with the active theme
+func (m *Model) RefreshStyles() {
+	m.updateViewportContent()
+}
+
 func (m *Model) updateViewportContent() {
 	var sections []string
 
 	// Title
-	headerTitle := titleStyle.Render("One Thing (OT)")
+	headerTitle := titleStyle().Render("One Thing (OT)")
 	sections = append(sections, headerTitle)
 
 	// OT Content
 	if m.entry == nil {
-		emptyMessage := emptyStyle.Render("No One Thing set for today.")
+		emptyMessage := emptyStyle().Render("No One Thing set for today.")
 		sections = append(sections, sectionStyle.Render(emptyMessage))
 	} else {
-		otTitle := otTitleStyle.Render(m.entry.Title)
+		otTitle := otTitleStyle().Render(m.entry.Title)
 		sections = append(sections, sectionStyle.Render(otTitle))
 
 		if m.entry.Note != "" {
-			note := noteStyle.Render(fmt.Sprintf("Note: %s", m.entry.Note))
+			note := noteStyle().Render(fmt.Sprintf("Note: %s", m.entry.Note))
 			sections = append(sections, note)
 		}
 	}
 
 	// Help text
 	helpText := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(theme.Current().Muted).
 		Italic(true).
 		MarginTop(2).
 		Render("Press 'e' or 's' to set/edit your One Thing")
@@ -4,35 +4,104 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
 )
 
-var (
-	timeStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
-			Width(12)
+func timeStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Width(12)
+}
 
-	taskStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252")).
-			Bold(true)
+func taskStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Text).
+		Bold(true)
+}
 
-	statusStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
-			Italic(true)
+func statusStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Italic(true)
+}
 
-	warningStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("214")).
-			Bold(true)
-)
+func warningStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Warning).
+		Bold(true)
+}
+
+func driftStyle(minutes int) lipgloss.Style {
+	style := lipgloss.NewStyle().Foreground(theme.Current().Muted)
+	switch {
+	case minutes >= actualsDangerDrift:
+		style = style.Foreground(theme.Current().Danger).Bold(true)
+	case minutes > 0:
+		style = style.Foreground(theme.Current().Warning)
+	}
+	return style
+}
+
+// actualsDangerDrift is the drift in minutes from which a slot stands out
+// in the actuals view
+const actualsDangerDrift = 15
+
+func notesTitleStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Accent).
+		Bold(true)
+}
+
+func notesStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Text)
+}
+
+func emptyNotesStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Italic(true)
+}
+
+// EditNoteMsg is emitted when the user wants to edit the notes of the plan being viewed
+type EditNoteMsg struct {
+	Date string
+}
+
+type KeyMap struct {
+	Note    key.Binding
+	Actuals key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Note: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "edit notes"),
+		),
+		Actuals: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "planned vs actual"),
+		),
+	}
+}
 
 type Model struct {
+	keys           KeyMap
+	clock          clock.Clock // The system clock when nil
 	viewport       viewport.Model
 	Plan           *models.DayPlan
+	Date           string // Date being viewed (YYYY-MM-DD); empty means today
 	Tasks          map[string]models.Task
-	LatestRevision int // Track the latest revision number for warning display
+	LatestRevision int  // Track the latest revision number for warning display
+	ShowActuals    bool // Show planned times next to the tracked ones instead of the plan
 	width          int
 	height         int
 }
@@ -40,16 +109,37 @@ type Model struct {
 func New(width, height int) Model {
 	vp := viewport.New(width, height)
 	return Model{
+		keys:     DefaultKeyMap(),
 		viewport: vp,
 		Tasks:    make(map[string]models.Task),
 	}
 }
 
+// SetKeyMap replaces the key bindings
+func (m *Model) SetKeyMap(keys KeyMap) {
+	m.keys = keys
+}
+
+// Keys returns the plan view key bindings for help display
+func (m Model) Keys() KeyMap {
+	return m.keys
+}
+
 func (m Model) Init() tea.Cmd {
 	return nil
 }
 
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.Note) && m.Plan != nil {
+		date := m.Plan.Date
+		return m, func() tea.Msg { return EditNoteMsg{Date: date} }
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.Actuals) && m.Plan != nil {
+		m.ShowActuals = !m.ShowActuals
+		m.Render()
+		return m, nil
+	}
+
 	var cmd tea.Cmd
 	m.viewport, cmd = m.viewport.Update(msg)
 	return m, cmd
@@ -57,11 +147,19 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 func (m Model) View() string {
 	if m.Plan == nil {
+		if m.Date != "" && m.Date != clock.Or(m.clock).Now().Format(constants.DateFormat) {
+			return fmt.Sprintf("No plan for %s.", m.Date)
+		}
 		return "No plan for today. Press 'g' to generate."
 	}
 	return m.viewport.View()
 }
 
+// SetClock sets the clock that says which day is today
+func (m *Model) SetClock(clk clock.Clock) {
+	m.clock = clk
+}
+
 func (m *Model) SetSize(width, height int) {
 	m.width = width
 	m.height = height
@@ -72,6 +170,7 @@ func (m *Model) SetSize(width, height int) {
 
 func (m *Model) SetPlan(plan models.DayPlan, tasks []models.Task) {
 	m.Plan = &plan
+	m.Date = plan.Date
 	// By default, assume the current plan's revision is the latest known for this view.
 	// Callers can override this via SetLatestRevision when they know of a newer revision.
 	m.LatestRevision = plan.Revision
@@ -81,6 +180,14 @@ func (m *Model) SetPlan(plan models.DayPlan, tasks []models.Task) {
 	m.Render()
 }
 
+// ClearPlan shows the empty state for a date that has no plan
+func (m *Model) ClearPlan(date string) {
+	m.Plan = nil
+	m.Date = date
+	m.LatestRevision = 0
+	m.Render()
+}
+
 // SetLatestRevision updates the latest revision number for warning display
 func (m *Model) SetLatestRevision(latestRev int) {
 	m.LatestRevision = latestRev
@@ -96,13 +203,19 @@ func (m *Model) Render() {
 	var b strings.Builder
 
 	// Add revision badge at the top
-	revisionText := fmt.Sprintf("Revision %d", m.Plan.Revision)
+	revisionText := fmt.Sprintf("%s · Revision %d", m.Plan.Date, m.Plan.Revision)
 	if m.LatestRevision > 0 && m.Plan.Revision < m.LatestRevision {
 		// Viewing an older revision - show warning
-		revisionText += warningStyle.Render(fmt.Sprintf(" ⚠ Not latest (Rev %d available)", m.LatestRevision))
+		revisionText += warningStyle().Render(fmt.Sprintf(" ⚠ Not latest (Rev %d available)", m.LatestRevision))
 	}
 	b.WriteString(revisionText + "\n\n")
 
+	if m.ShowActuals {
+		m.renderActuals(&b)
+		m.viewport.SetContent(b.String())
+		return
+	}
+
 	for _, slot := range m.Plan.Slots {
 		taskName := "Unknown Task"
 		taskDeleted := false
@@ -122,11 +235,77 @@ func (m *Model) Render() {
 		}
 
 		line := fmt.Sprintf("%s %s %s\n",
-			timeStyle.Render(timeStr),
-			taskStyle.Render(displayName),
-			statusStyle.Render(string(slot.Status)),
+			timeStyle().Render(timeStr),
+			taskStyle().Render(displayName),
+			statusStyle().Render(string(slot.Status)),
 		)
 		b.WriteString(line)
 	}
+
+	b.WriteString("\n" + notesTitleStyle().Render("Notes") + "\n")
+	if m.Plan.Note == "" {
+		b.WriteString(emptyNotesStyle().Render(fmt.Sprintf("No notes. Press '%s' to add some.", m.keys.Note.Help().Key)) + "\n")
+	} else {
+		b.WriteString(notesStyle().Render(m.Plan.Note) + "\n")
+	}
 	m.viewport.SetContent(b.String())
 }
+
+// renderActuals writes the slots with their planned and tracked times side by
+// side, highlighting the ones that drifted, and the day's total drift
+func (m *Model) renderActuals(b *strings.Builder) {
+	b.WriteString(notesTitleStyle().Render("Planned vs actual") + "\n\n")
+
+	// Drift is measured the short way around the clock, so it doesn't need
+	// the day window
+	var window models.DayWindow
+	tracked := 0
+	for _, slot := range m.Plan.Slots {
+		taskName := "Unknown Task"
+		if t, ok := m.Tasks[slot.TaskID]; ok {
+			taskName = t.Name
+		}
+
+		actual := "-"
+		if slot.ActualStart != nil || slot.ActualEnd != nil {
+			start, end := "?", "…"
+			if slot.ActualStart != nil {
+				start = *slot.ActualStart
+			}
+			if slot.ActualEnd != nil {
+				end = *slot.ActualEnd
+			}
+			actual = start + " - " + end
+		}
+
+		drift := window.Drift(slot)
+		driftText := "not tracked"
+		if drift.Tracked() {
+			tracked++
+			driftText = fmt.Sprintf("%+dm", signedDrift(drift))
+		}
+
+		fmt.Fprintf(b, "%s %s %s %s\n",
+			timeStyle().Render(slot.Start+" - "+slot.End),
+			timeStyle().Width(14).Render(actual),
+			taskStyle().Render(taskName),
+			driftStyle(drift.Minutes()).Render(driftText),
+		)
+	}
+
+	if tracked == 0 {
+		b.WriteString("\n" + emptyNotesStyle().Render("No slots tracked yet. Use 'daylit start' and 'daylit stop' to record actual times.") + "\n")
+		return
+	}
+	total := window.TotalDrift(m.Plan.Slots)
+	fmt.Fprintf(b, "\n%s\n", driftStyle(total).Render(fmt.Sprintf("Total drift: %dm across %d tracked slot(s)", total, tracked)))
+}
+
+// signedDrift returns the drift of a slot with its sign: its end drift once it
+// has ended, else its start drift
+func signedDrift(d models.SlotDrift) int {
+	if d.HasEnd {
+		return d.End
+	}
+	return d.Start
+}
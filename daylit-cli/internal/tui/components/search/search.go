@@ -0,0 +1,273 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
+)
+
+func titleStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Current().Accent).
+		MarginBottom(1)
+}
+
+func kindStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Accent).
+		Width(7)
+}
+
+func dateStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Width(11)
+}
+
+func snippetStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Italic(true)
+}
+
+func selectedStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().OnHighlight).
+		Background(theme.Current().Highlight)
+}
+
+func emptyStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Faint).
+		Italic(true)
+}
+
+// QueryMsg is emitted when the query text changes and results must be reloaded
+type QueryMsg struct {
+	Query string
+}
+
+// SelectResultMsg is emitted when the user opens a result
+type SelectResultMsg struct {
+	Result models.SearchResult
+}
+
+// CloseMsg is emitted when the user dismisses the overlay
+type CloseMsg struct{}
+
+type KeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Select key.Binding
+	Close  key.Binding
+}
+
+// DefaultKeyMap avoids letter keys, which are typed into the query
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("↑", "prev result"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("↓", "next result"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "open"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "close"),
+		),
+	}
+}
+
+// Model is a search box with the matching results listed beneath it
+type Model struct {
+	keys    KeyMap
+	input   textinput.Model
+	query   string // Query the current results belong to
+	results []models.SearchResult
+	cursor  int
+	offset  int
+	width   int
+	height  int
+}
+
+func New() Model {
+	ti := textinput.New()
+	ti.Placeholder = "tasks, habits, OT, notes…"
+	ti.Prompt = "/ "
+	return Model{
+		keys:  DefaultKeyMap(),
+		input: ti,
+	}
+}
+
+// Open focuses the query input, keeping the previous query and results
+func (m *Model) Open() tea.Cmd {
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+// SetKeyMap replaces the key bindings
+func (m *Model) SetKeyMap(keys KeyMap) {
+	m.keys = keys
+}
+
+// Keys returns the active key bindings
+func (m Model) Keys() KeyMap {
+	return m.keys
+}
+
+// Query returns the text currently in the search box
+func (m Model) Query() string {
+	return m.input.Value()
+}
+
+// SetResults replaces the results for query. Results for a query that no
+// longer matches the search box are ignored.
+func (m *Model) SetResults(query string, results []models.SearchResult) {
+	if query != m.input.Value() {
+		return
+	}
+	m.query = query
+	m.results = results
+	m.cursor = 0
+	m.offset = 0
+}
+
+// Selected returns the highlighted result, if any
+func (m Model) Selected() (models.SearchResult, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.results) {
+		return models.SearchResult{}, false
+	}
+	return m.results[m.cursor], true
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.Close):
+			m.input.Blur()
+			return m, func() tea.Msg { return CloseMsg{} }
+		case key.Matches(msg, m.keys.Select):
+			if r, ok := m.Selected(); ok {
+				m.input.Blur()
+				return m, func() tea.Msg { return SelectResultMsg{Result: r} }
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			m.scrollToCursor()
+			return m, nil
+		case key.Matches(msg, m.keys.Down):
+			if m.cursor < len(m.results)-1 {
+				m.cursor++
+			}
+			m.scrollToCursor()
+			return m, nil
+		}
+	}
+
+	before := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if query := m.input.Value(); query != before {
+		return m, tea.Batch(cmd, func() tea.Msg { return QueryMsg{Query: query} })
+	}
+	return m, cmd
+}
+
+// visibleRows is the number of result rows that fit below the title and input
+func (m Model) visibleRows() int {
+	if m.height <= 0 {
+		return 10
+	}
+	return max(m.height-5, 1)
+}
+
+func (m *Model) scrollToCursor() {
+	rows := m.visibleRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+func (m Model) View() string {
+	var b strings.Builder
+	b.WriteString(titleStyle().Render("Search"))
+	b.WriteString("\n")
+	b.WriteString(m.input.View())
+	b.WriteString("\n\n")
+
+	switch {
+	case strings.TrimSpace(m.query) == "":
+		b.WriteString(emptyStyle().Render("Type to search task names, habits, OT entries, and feedback notes."))
+	case len(m.results) == 0:
+		b.WriteString(emptyStyle().Render(fmt.Sprintf("No matches for %q.", m.query)))
+	default:
+		end := min(m.offset+m.visibleRows(), len(m.results))
+		for i := m.offset; i < end; i++ {
+			b.WriteString(m.renderResult(i))
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+func (m Model) renderResult(i int) string {
+	r := m.results[i]
+	line := kindStyle().Render(kindLabel(r.Kind)) + dateStyle().Render(r.Date) + r.Title
+	if r.Snippet != "" {
+		line += snippetStyle().Render("  " + r.Snippet)
+	}
+	if m.width > 2 {
+		line = lipgloss.NewStyle().MaxWidth(m.width - 2).Render(line)
+	}
+	if i == m.cursor {
+		return selectedStyle().Render("> ") + line
+	}
+	return "  " + line
+}
+
+func kindLabel(kind constants.SearchKind) string {
+	switch kind {
+	case constants.SearchKindTask:
+		return "task"
+	case constants.SearchKindHabit:
+		return "habit"
+	case constants.SearchKindOT:
+		return "OT"
+	case constants.SearchKindNote:
+		return "note"
+	}
+	return string(kind)
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.input.Width = max(width-4, 10)
+	m.scrollToCursor()
+}
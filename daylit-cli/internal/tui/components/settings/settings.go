@@ -9,6 +9,7 @@ import (
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
 )
 
 type EditSettingsMsg struct{}
@@ -21,24 +22,28 @@ type Model struct {
 	viewport   viewport.Model
 }
 
-var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("205")).
-			MarginBottom(1)
+func titleStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Current().Accent).
+		MarginBottom(1)
+}
 
-	labelStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
-			Width(25)
+func labelStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Width(25)
+}
 
-	valueStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("255")).
-			Bold(true)
+func valueStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Bright).
+		Bold(true)
+}
 
-	sectionStyle = lipgloss.NewStyle().
-			MarginTop(1).
-			MarginBottom(1)
-)
+var sectionStyle = lipgloss.NewStyle().
+	MarginTop(1).
+	MarginBottom(1)
 
 func New(settings storage.Settings, otSettings models.OTSettings, width, height int) Model {
 	m := Model{
@@ -94,41 +99,43 @@ func (m *Model) updateViewportContent() {
 	var sections []string
 
 	// General Settings
-	generalTitle := titleStyle.Render("General Settings")
+	generalTitle := titleStyle().Render("General Settings")
 	generalContent := lipgloss.JoinVertical(
 		lipgloss.Left,
-		fmt.Sprintf("%s %s", labelStyle.Render("Day Start:"), valueStyle.Render(m.settings.DayStart)),
-		fmt.Sprintf("%s %s", labelStyle.Render("Day End:"), valueStyle.Render(m.settings.DayEnd)),
-		fmt.Sprintf("%s %s", labelStyle.Render("Default Block (min):"), valueStyle.Render(fmt.Sprintf("%d", m.settings.DefaultBlockMin))),
-		fmt.Sprintf("%s %s", labelStyle.Render("Timezone:"), valueStyle.Render(m.settings.Timezone)),
+		fmt.Sprintf("%s %s", labelStyle().Render("Day Start:"), valueStyle().Render(m.settings.DayStart)),
+		fmt.Sprintf("%s %s", labelStyle().Render("Day End:"), valueStyle().Render(m.settings.DayEnd)),
+		fmt.Sprintf("%s %s", labelStyle().Render("Default Block (min):"), valueStyle().Render(fmt.Sprintf("%d", m.settings.DefaultBlockMin))),
+		fmt.Sprintf("%s %s", labelStyle().Render("Timezone:"), valueStyle().Render(m.settings.Timezone)),
+		fmt.Sprintf("%s %s", labelStyle().Render("Theme:"), valueStyle().Render(m.settings.Theme)),
+		fmt.Sprintf("%s %s", labelStyle().Render("Week Starts On:"), valueStyle().Render(m.settings.WeekStart)),
 	)
 	sections = append(sections, sectionStyle.Render(generalTitle+"\n"+generalContent))
 
 	// Once Today Settings
-	otTitle := titleStyle.Render("Once Today Settings")
+	otTitle := titleStyle().Render("Once Today Settings")
 	otContent := lipgloss.JoinVertical(
 		lipgloss.Left,
-		fmt.Sprintf("%s %s", labelStyle.Render("Prompt On Empty:"), valueStyle.Render(fmt.Sprintf("%t", m.otSettings.PromptOnEmpty))),
-		fmt.Sprintf("%s %s", labelStyle.Render("Strict Mode:"), valueStyle.Render(fmt.Sprintf("%t", m.otSettings.StrictMode))),
-		fmt.Sprintf("%s %s", labelStyle.Render("Default Log Days:"), valueStyle.Render(fmt.Sprintf("%d", m.otSettings.DefaultLogDays))),
+		fmt.Sprintf("%s %s", labelStyle().Render("Prompt On Empty:"), valueStyle().Render(fmt.Sprintf("%t", m.otSettings.PromptOnEmpty))),
+		fmt.Sprintf("%s %s", labelStyle().Render("Strict Mode:"), valueStyle().Render(fmt.Sprintf("%t", m.otSettings.StrictMode))),
+		fmt.Sprintf("%s %s", labelStyle().Render("Default Log Days:"), valueStyle().Render(fmt.Sprintf("%d", m.otSettings.DefaultLogDays))),
 	)
 	sections = append(sections, sectionStyle.Render(otTitle+"\n"+otContent))
 
 	// Notification Settings
-	notifTitle := titleStyle.Render("Notification Settings")
+	notifTitle := titleStyle().Render("Notification Settings")
 	notifContent := lipgloss.JoinVertical(
 		lipgloss.Left,
-		fmt.Sprintf("%s %s", labelStyle.Render("Enabled:"), valueStyle.Render(fmt.Sprintf("%t", m.settings.NotificationsEnabled))),
-		fmt.Sprintf("%s %s", labelStyle.Render("Notify Block Start:"), valueStyle.Render(fmt.Sprintf("%t", m.settings.NotifyBlockStart))),
-		fmt.Sprintf("%s %s", labelStyle.Render("Start Offset (min):"), valueStyle.Render(fmt.Sprintf("%d", m.settings.BlockStartOffsetMin))),
-		fmt.Sprintf("%s %s", labelStyle.Render("Notify Block End:"), valueStyle.Render(fmt.Sprintf("%t", m.settings.NotifyBlockEnd))),
-		fmt.Sprintf("%s %s", labelStyle.Render("End Offset (min):"), valueStyle.Render(fmt.Sprintf("%d", m.settings.BlockEndOffsetMin))),
+		fmt.Sprintf("%s %s", labelStyle().Render("Enabled:"), valueStyle().Render(fmt.Sprintf("%t", m.settings.NotificationsEnabled))),
+		fmt.Sprintf("%s %s", labelStyle().Render("Notify Block Start:"), valueStyle().Render(fmt.Sprintf("%t", m.settings.NotifyBlockStart))),
+		fmt.Sprintf("%s %s", labelStyle().Render("Start Offset (min):"), valueStyle().Render(fmt.Sprintf("%d", m.settings.BlockStartOffsetMin))),
+		fmt.Sprintf("%s %s", labelStyle().Render("Notify Block End:"), valueStyle().Render(fmt.Sprintf("%t", m.settings.NotifyBlockEnd))),
+		fmt.Sprintf("%s %s", labelStyle().Render("End Offset (min):"), valueStyle().Render(fmt.Sprintf("%d", m.settings.BlockEndOffsetMin))),
 	)
 	sections = append(sections, sectionStyle.Render(notifTitle+"\n"+notifContent))
 
 	// Help text
 	helpText := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(theme.Current().Muted).
 		Italic(true).
 		MarginTop(2).
 		Render("Press 'e' to edit settings")
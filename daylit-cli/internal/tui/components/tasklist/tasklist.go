@@ -8,6 +8,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
 )
 
 type AddTaskMsg struct{}
@@ -82,19 +83,14 @@ func New(tasks []models.Task, width, height int) Model {
 		items[i] = Item{Task: t}
 	}
 
-	l := list.New(items, list.NewDefaultDelegate(), width, height)
+	l := list.New(items, theme.ListDelegate(), width, height)
 	l.Title = "Tasks"
 	l.SetShowTitle(false)
 	l.SetShowHelp(false) // We handle help globally in the main model
 
 	// Add custom keys to list additional short help
 	keys := DefaultKeyMap()
-	l.AdditionalShortHelpKeys = func() []key.Binding {
-		return []key.Binding{keys.Add, keys.Edit, keys.Delete, keys.Restore}
-	}
-	l.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{keys.Add, keys.Edit, keys.Delete, keys.Restore}
-	}
+	setHelpKeys(&l, keys)
 
 	return Model{list: l, keys: keys}
 }
@@ -107,6 +103,17 @@ func (m *Model) SetTasks(tasks []models.Task) {
 	m.list.SetItems(items)
 }
 
+// SelectTask moves the cursor to the task with the given ID, if it is listed
+func (m *Model) SelectTask(id string) {
+	m.list.ResetFilter()
+	for i, item := range m.list.Items() {
+		if it, ok := item.(Item); ok && it.Task.ID == id {
+			m.list.Select(i)
+			return
+		}
+	}
+}
+
 func (m Model) Init() tea.Cmd {
 	return nil
 }
@@ -153,3 +160,30 @@ func (m Model) View() string {
 func (m *Model) SetSize(width, height int) {
 	m.list.SetSize(width, height)
 }
+
+// SetKeyMap replaces the action key bindings
+func (m *Model) SetKeyMap(keys KeyMap) {
+	m.keys = keys
+	setHelpKeys(&m.list, keys)
+}
+
+// SetCursorKeys replaces the keys that move the list cursor
+func (m *Model) SetCursorKeys(up, down key.Binding) {
+	m.list.KeyMap.CursorUp = up
+	m.list.KeyMap.CursorDown = down
+}
+
+// setHelpKeys registers the action bindings with the list's help views
+func setHelpKeys(l *list.Model, keys KeyMap) {
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Add, keys.Edit, keys.Delete, keys.Restore}
+	}
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Add, keys.Edit, keys.Delete, keys.Restore}
+	}
+}
+
+// RefreshStyles rebuilds the list delegate from the active theme
+func (m *Model) RefreshStyles() {
+	m.list.SetDelegate(theme.ListDelegate())
+}
@@ -0,0 +1,166 @@
+package toast
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
+)
+
+const (
+	// successDuration and errorDuration control how long a toast stays visible;
+	// errors linger longer so they can be read
+	successDuration = 3 * time.Second
+	errorDuration   = 6 * time.Second
+
+	// maxQueued caps the number of toasts waiting behind the visible one
+	maxQueued = 5
+)
+
+type Level int
+
+const (
+	LevelInfo Level = iota
+	LevelSuccess
+	LevelError
+)
+
+type Toast struct {
+	ID    int
+	Level Level
+	Text  string
+}
+
+// DismissMsg is emitted when the visible toast's display time has elapsed
+type DismissMsg struct {
+	ID int
+}
+
+func successStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().OnHighlight).
+		Background(theme.Current().Highlight).
+		Padding(0, 1)
+}
+
+func errorStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Bright).
+		Background(theme.Current().Danger).
+		Bold(true).
+		Padding(0, 1)
+}
+
+func infoStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Text).
+		Background(theme.Current().Surface).
+		Padding(0, 1)
+}
+
+func pendingStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Italic(true).
+		PaddingLeft(1)
+}
+
+// Model is a queue of status messages shown one at a time, each dismissed
+// automatically after a delay
+type Model struct {
+	queue  []Toast
+	nextID int
+	width  int
+}
+
+func New() Model {
+	return Model{}
+}
+
+// Push queues a toast and returns the command that schedules its dismissal
+// if it is shown immediately
+func (m *Model) Push(level Level, text string) tea.Cmd {
+	m.nextID++
+	t := Toast{ID: m.nextID, Level: level, Text: text}
+
+	if len(m.queue) > maxQueued {
+		// Drop the oldest waiting toast, never the visible one
+		m.queue = append(m.queue[:1], m.queue[2:]...)
+	}
+	m.queue = append(m.queue, t)
+
+	if len(m.queue) == 1 {
+		return dismissAfter(t)
+	}
+	return nil
+}
+
+// Current returns the visible toast, if any
+func (m Model) Current() (Toast, bool) {
+	if len(m.queue) == 0 {
+		return Toast{}, false
+	}
+	return m.queue[0], true
+}
+
+func dismissAfter(t Toast) tea.Cmd {
+	d := successDuration
+	if t.Level == LevelError {
+		d = errorDuration
+	}
+	id := t.ID
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return DismissMsg{ID: id}
+	})
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case DismissMsg:
+		if len(m.queue) == 0 || m.queue[0].ID != msg.ID {
+			return m, nil
+		}
+		m.queue = m.queue[1:]
+		if len(m.queue) > 0 {
+			return m, dismissAfter(m.queue[0])
+		}
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	t, ok := m.Current()
+	if !ok {
+		return ""
+	}
+
+	var text string
+	switch t.Level {
+	case LevelSuccess:
+		text = successStyle().Render("✓ " + t.Text)
+	case LevelError:
+		text = errorStyle().Render("✗ " + t.Text)
+	default:
+		text = infoStyle().Render(t.Text)
+	}
+
+	if pending := len(m.queue) - 1; pending > 0 {
+		text += pendingStyle().Render(fmt.Sprintf("+%d more", pending))
+	}
+
+	if m.width > 0 {
+		return lipgloss.NewStyle().MaxWidth(m.width).Render(text)
+	}
+	return text
+}
+
+func (m *Model) SetSize(width int) {
+	m.width = width
+}
@@ -0,0 +1,64 @@
+package toast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPushQueuesAndDismisses(t *testing.T) {
+	m := New()
+
+	if cmd := m.Push(LevelSuccess, "first"); cmd == nil {
+		t.Fatal("expected dismiss command for the first toast")
+	}
+	if cmd := m.Push(LevelError, "second"); cmd != nil {
+		t.Error("queued toast should not schedule dismissal until it is visible")
+	}
+
+	cur, ok := m.Current()
+	if !ok || cur.Text != "first" {
+		t.Fatalf("Current() = %q, want %q", cur.Text, "first")
+	}
+	if !strings.Contains(m.View(), "+1 more") {
+		t.Errorf("View() should mention pending toasts, got %q", m.View())
+	}
+
+	// A stale dismissal for an unknown toast is ignored
+	m, _ = m.Update(DismissMsg{ID: 99})
+	if cur, _ := m.Current(); cur.Text != "first" {
+		t.Errorf("stale dismissal removed the visible toast")
+	}
+
+	m, cmd := m.Update(DismissMsg{ID: cur.ID})
+	if cmd == nil {
+		t.Error("expected dismiss command for the next toast")
+	}
+	if cur, ok := m.Current(); !ok || cur.Text != "second" {
+		t.Fatalf("Current() = %q, want %q", cur.Text, "second")
+	}
+
+	m, _ = m.Update(DismissMsg{ID: m.queue[0].ID})
+	if _, ok := m.Current(); ok {
+		t.Error("queue should be empty after all toasts are dismissed")
+	}
+	if m.View() != "" {
+		t.Errorf("View() = %q, want empty", m.View())
+	}
+}
+
+func TestPushDropsOldestWaiting(t *testing.T) {
+	m := New()
+	for i := 0; i < maxQueued+3; i++ {
+		m.Push(LevelInfo, string(rune('a'+i)))
+	}
+
+	if got := len(m.queue); got != maxQueued+1 {
+		t.Fatalf("queue length = %d, want %d", got, maxQueued+1)
+	}
+	if cur, _ := m.Current(); cur.Text != "a" {
+		t.Errorf("visible toast = %q, want %q", cur.Text, "a")
+	}
+	if last := m.queue[len(m.queue)-1].Text; last != string(rune('a'+maxQueued+2)) {
+		t.Errorf("newest toast = %q was dropped", last)
+	}
+}
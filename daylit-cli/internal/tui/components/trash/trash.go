@@ -0,0 +1,165 @@
+package trash
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/i18n"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
+)
+
+type RestoreMsg struct {
+	Item models.TrashItem
+}
+
+type PurgeMsg struct {
+	Item models.TrashItem
+}
+
+var kindIcons = map[models.TrashKind]string{
+	models.TrashTask:       "📋",
+	models.TrashPlan:       "📅",
+	models.TrashHabit:      "🔁",
+	models.TrashHabitEntry: "✔",
+	models.TrashOTEntry:    "🎯",
+}
+
+type Item struct {
+	TrashItem models.TrashItem
+}
+
+func (i Item) Title() string {
+	return kindIcons[i.TrashItem.Kind] + " " + i.TrashItem.Label
+}
+
+func (i Item) Description() string {
+	desc := string(i.TrashItem.Kind) + " " + i.TrashItem.ID
+	if !i.TrashItem.DeletedAt.IsZero() {
+		desc += " · " + i18n.T("trash.deleted", i18n.Format(i.TrashItem.DeletedAt.Local(), i18n.LayoutTimestamp))
+	}
+	return desc
+}
+
+func (i Item) FilterValue() string { return i.TrashItem.Label }
+
+type KeyMap struct {
+	Restore key.Binding
+	Purge   key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Restore: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "restore"),
+		),
+		Purge: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "purge"),
+		),
+	}
+}
+
+type Model struct {
+	list list.Model
+	keys KeyMap
+}
+
+func New(items []models.TrashItem, width, height int) Model {
+	l := list.New(listItems(items), theme.ListDelegate(), width, height)
+	l.Title = "Trash"
+	l.SetShowTitle(false)
+	l.SetShowHelp(false)
+
+	keys := DefaultKeyMap()
+	setHelpKeys(&l, keys)
+
+	return Model{
+		list: l,
+		keys: keys,
+	}
+}
+
+func listItems(items []models.TrashItem) []list.Item {
+	listed := make([]list.Item, len(items))
+	for i, item := range items {
+		listed[i] = Item{TrashItem: item}
+	}
+	return listed
+}
+
+func (m *Model) SetItems(items []models.TrashItem) {
+	m.list.SetItems(listItems(items))
+}
+
+// Len returns the number of items in the trash
+func (m Model) Len() int {
+	return len(m.list.Items())
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// Don't match if we're filtering
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+
+		item, ok := m.list.SelectedItem().(Item)
+		if !ok {
+			break
+		}
+		switch {
+		case key.Matches(msg, m.keys.Restore):
+			return m, func() tea.Msg { return RestoreMsg{Item: item.TrashItem} }
+		case key.Matches(msg, m.keys.Purge):
+			return m, func() tea.Msg { return PurgeMsg{Item: item.TrashItem} }
+		}
+	}
+
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m Model) View() string {
+	return m.list.View()
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.list.SetSize(width, height)
+}
+
+// SetKeyMap replaces the action key bindings
+func (m *Model) SetKeyMap(keys KeyMap) {
+	m.keys = keys
+	setHelpKeys(&m.list, keys)
+}
+
+// SetCursorKeys replaces the keys that move the list cursor
+func (m *Model) SetCursorKeys(up, down key.Binding) {
+	m.list.KeyMap.CursorUp = up
+	m.list.KeyMap.CursorDown = down
+}
+
+// setHelpKeys registers the action bindings with the list's help views
+func setHelpKeys(l *list.Model, keys KeyMap) {
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Restore, keys.Purge}
+	}
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Restore, keys.Purge}
+	}
+}
+
+// RefreshStyles rebuilds the list delegate from the active theme
+func (m *Model) RefreshStyles() {
+	m.list.SetDelegate(theme.ListDelegate())
+}
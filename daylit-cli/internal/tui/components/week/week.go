@@ -0,0 +1,423 @@
+package week
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/i18n"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
+	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
+)
+
+const (
+	minColumnWidth = 14
+
+	// busyThreshold and overloadThreshold are the fractions of the waking
+	// window at which a day is highlighted as busy or overloaded
+	busyThreshold     = 0.85
+	overloadThreshold = 1.0
+)
+
+func titleStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Current().Accent).
+		MarginBottom(1)
+}
+
+func headerStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Text).
+		Bold(true)
+}
+
+func selectedHeaderStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().OnHighlight).
+		Background(theme.Current().Highlight).
+		Bold(true)
+}
+
+func loadStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted)
+}
+
+func busyStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Warning).
+		Bold(true)
+}
+
+func overloadStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Danger).
+		Bold(true)
+}
+
+func slotStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Text)
+}
+
+func emptyStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Italic(true)
+}
+
+func hintStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Italic(true).
+		MarginTop(1)
+}
+
+// WeekChangedMsg is emitted when the visible week changes and plans must be reloaded
+type WeekChangedMsg struct {
+	Start time.Time
+}
+
+// SelectDayMsg is emitted when the user opens a day from the week view in the Plan tab
+type SelectDayMsg struct {
+	Date string
+}
+
+// GenerateWeekMsg is emitted when the user requests plans for the unplanned days of the visible week
+type GenerateWeekMsg struct {
+	Dates []string
+}
+
+type KeyMap struct {
+	Left     key.Binding
+	Right    key.Binding
+	PrevWeek key.Binding
+	NextWeek key.Binding
+	Today    key.Binding
+	Select   key.Binding
+	Generate key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Left: key.NewBinding(
+			key.WithKeys("left"),
+			key.WithHelp("←", "prev day"),
+		),
+		Right: key.NewBinding(
+			key.WithKeys("right"),
+			key.WithHelp("→", "next day"),
+		),
+		PrevWeek: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "prev week"),
+		),
+		NextWeek: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next week"),
+		),
+		Today: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "this week"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "open plan"),
+		),
+		Generate: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "plan week"),
+		),
+	}
+}
+
+type Model struct {
+	keys     KeyMap
+	clock    clock.Clock // The system clock when nil
+	start    time.Time
+	first    time.Weekday // Day the week starts on
+	selected int
+	plans    map[string]models.DayPlan
+	tasks    map[string]models.Task
+	dayStart string
+	dayEnd   string
+	width    int
+	height   int
+}
+
+// New creates a week view showing the week of today, with weeks starting on
+// first
+func New(today time.Time, first time.Weekday, width, height int) Model {
+	return Model{
+		keys:     DefaultKeyMap(),
+		start:    utils.StartOfWeek(today, first),
+		first:    first,
+		selected: dayIndex(today, first),
+		plans:    make(map[string]models.DayPlan),
+		tasks:    make(map[string]models.Task),
+		width:    width,
+		height:   height,
+	}
+}
+
+// dayIndex returns the column of t in a week starting on first
+func dayIndex(t time.Time, first time.Weekday) int {
+	return (int(t.Weekday()) - int(first) + 7) % 7
+}
+
+// SetClock sets the clock that says which day is today
+func (m *Model) SetClock(clk clock.Clock) {
+	m.clock = clk
+}
+
+// SetWeekStart changes the day weeks start on, keeping the selected day in
+// view. The caller reloads the plans of the new Start.
+func (m *Model) SetWeekStart(first time.Weekday) {
+	if first == m.first {
+		return
+	}
+	selected := m.start.AddDate(0, 0, m.selected)
+	m.first = first
+	m.start = utils.StartOfWeek(selected, first)
+	m.selected = dayIndex(selected, first)
+}
+
+// Start returns the first day of the visible week
+func (m Model) Start() time.Time {
+	return m.start
+}
+
+// Dates returns the seven dates of the visible week in YYYY-MM-DD format
+func (m Model) Dates() []string {
+	dates := make([]string, 7)
+	for i := range dates {
+		dates[i] = m.start.AddDate(0, 0, i).Format(constants.DateFormat)
+	}
+	return dates
+}
+
+// SetKeyMap replaces the key bindings
+func (m *Model) SetKeyMap(keys KeyMap) {
+	m.keys = keys
+}
+
+// Keys returns the week view key bindings for help display
+func (m Model) Keys() KeyMap {
+	return m.keys
+}
+
+// SetWeek replaces the plans, tasks, and day window used to render the week
+func (m *Model) SetWeek(plans []models.DayPlan, tasks []models.Task, dayStart, dayEnd string) {
+	m.plans = make(map[string]models.DayPlan, len(plans))
+	for _, p := range plans {
+		m.plans[p.Date] = p
+	}
+	m.tasks = make(map[string]models.Task, len(tasks))
+	for _, t := range tasks {
+		m.tasks[t.ID] = t
+	}
+	m.dayStart = dayStart
+	m.dayEnd = dayEnd
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.keys.Left):
+		if m.selected > 0 {
+			m.selected--
+			return m, nil
+		}
+		m.selected = 6
+		return m.shiftWeek(-1)
+	case key.Matches(keyMsg, m.keys.Right):
+		if m.selected < 6 {
+			m.selected++
+			return m, nil
+		}
+		m.selected = 0
+		return m.shiftWeek(1)
+	case key.Matches(keyMsg, m.keys.PrevWeek):
+		return m.shiftWeek(-1)
+	case key.Matches(keyMsg, m.keys.NextWeek):
+		return m.shiftWeek(1)
+	case key.Matches(keyMsg, m.keys.Today):
+		now := clock.Or(m.clock).Now()
+		m.start = utils.StartOfWeek(now, m.first)
+		m.selected = dayIndex(now, m.first)
+		start := m.start
+		return m, func() tea.Msg { return WeekChangedMsg{Start: start} }
+	case key.Matches(keyMsg, m.keys.Select):
+		date := m.start.AddDate(0, 0, m.selected).Format(constants.DateFormat)
+		return m, func() tea.Msg { return SelectDayMsg{Date: date} }
+	case key.Matches(keyMsg, m.keys.Generate):
+		dates := m.unplannedDates()
+		if len(dates) == 0 {
+			return m, nil
+		}
+		return m, func() tea.Msg { return GenerateWeekMsg{Dates: dates} }
+	}
+	return m, nil
+}
+
+func (m Model) shiftWeek(weeks int) (Model, tea.Cmd) {
+	m.start = m.start.AddDate(0, 0, 7*weeks)
+	start := m.start
+	return m, func() tea.Msg { return WeekChangedMsg{Start: start} }
+}
+
+// unplannedDates returns the dates from today onward in the visible week that have no plan
+func (m Model) unplannedDates() []string {
+	today := clock.Or(m.clock).Now().Format(constants.DateFormat)
+	var dates []string
+	for _, date := range m.Dates() {
+		if date < today {
+			continue
+		}
+		if _, ok := m.plans[date]; !ok {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+func (m Model) View() string {
+	end := m.start.AddDate(0, 0, 6)
+	// Numbered by its last day, so the week holding January 1 is week 1
+	number := utils.WeekNumber(end, m.first)
+	title := titleStyle().Render(i18n.T("week.title", number, i18n.Format(m.start, i18n.LayoutDayMonth), i18n.Format(end, i18n.LayoutDayMonthYear)))
+
+	colWidth := minColumnWidth
+	if m.width/7 > colWidth {
+		colWidth = m.width / 7
+	}
+
+	// Reserve lines for the title, column header, load line, and hint
+	maxSlots := m.height - 7
+	if maxSlots < 3 {
+		maxSlots = 3
+	}
+
+	columns := make([]string, 7)
+	for i, date := range m.Dates() {
+		columns[i] = m.renderColumn(i, date, colWidth, maxSlots)
+	}
+
+	grid := lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+	hint := hintStyle().Render(i18n.T("week.hint"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, grid, hint)
+}
+
+func (m Model) renderColumn(index int, date string, width, maxSlots int) string {
+	day := m.start.AddDate(0, 0, index)
+	col := lipgloss.NewStyle().Width(width).PaddingRight(1)
+
+	header := i18n.Format(day, i18n.LayoutWeekdayDate)
+	if date == clock.Or(m.clock).Now().Format(constants.DateFormat) {
+		header += " *"
+	}
+	if index == m.selected {
+		header = selectedHeaderStyle().Render(header)
+	} else {
+		header = headerStyle().Render(header)
+	}
+
+	plan, ok := m.plans[date]
+	if !ok {
+		return col.Render(lipgloss.JoinVertical(lipgloss.Left, header, emptyStyle().Render(i18n.T("week.no_plan"))))
+	}
+
+	marker := "○"
+	if plan.AcceptedAt != nil {
+		marker = "●"
+	}
+
+	lines := []string{header, m.renderLoad(plan, marker)}
+	for i, slot := range plan.Slots {
+		if i >= maxSlots {
+			lines = append(lines, emptyStyle().Render(fmt.Sprintf("+%d more", len(plan.Slots)-maxSlots)))
+			break
+		}
+		name := "Unknown"
+		if t, ok := m.tasks[slot.TaskID]; ok {
+			name = t.Name
+		}
+		line := truncate(fmt.Sprintf("%s %s", slot.Start, name), width-1)
+		lines = append(lines, slotStyle().Render(line))
+	}
+	if len(plan.Slots) == 0 {
+		lines = append(lines, emptyStyle().Render("empty"))
+	}
+
+	return col.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// renderLoad formats the scheduled minutes for a plan and colors it by how
+// much of the waking window it consumes
+func (m Model) renderLoad(plan models.DayPlan, marker string) string {
+	dayWindow, _ := models.ParseDayWindow(m.dayStart, m.dayEnd)
+	scheduled := 0
+	for _, slot := range plan.Slots {
+		// Slots may cross midnight
+		start, end, err := dayWindow.Range(slot.Start, slot.End)
+		if err != nil {
+			continue
+		}
+		scheduled += end - start
+	}
+
+	text := fmt.Sprintf("%s %dh%02dm", marker, scheduled/60, scheduled%60)
+
+	window := m.windowMinutes()
+	if window <= 0 {
+		return loadStyle().Render(text)
+	}
+
+	ratio := float64(scheduled) / float64(window)
+	text += fmt.Sprintf(" %d%%", int(ratio*100))
+	switch {
+	case ratio > overloadThreshold:
+		return overloadStyle().Render(text)
+	case ratio >= busyThreshold:
+		return busyStyle().Render(text)
+	default:
+		return loadStyle().Render(text)
+	}
+}
+
+func (m Model) windowMinutes() int {
+	window, err := models.ParseDayWindow(m.dayStart, m.dayEnd)
+	if err != nil {
+		return 0
+	}
+	return window.End - window.Start
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 1 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
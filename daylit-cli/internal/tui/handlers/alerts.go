@@ -3,7 +3,7 @@ package handlers
 import (
 	"fmt"
 	"strconv"
-	"time"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
@@ -21,8 +21,8 @@ func HandleAddAlertState(m *state.Model, msg tea.Msg) tea.Cmd {
 	var cmds []tea.Cmd
 
 	if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyEsc {
-		m.State = constants.StateAlerts
-		return nil
+		m.FormError = ""
+		return leaveForm(m, constants.StateAlerts, false)
 	}
 
 	form, cmd := m.Form.Update(msg)
@@ -40,12 +40,19 @@ func HandleAddAlertState(m *state.Model, msg tea.Msg) tea.Cmd {
 			Time:      m.AlertForm.Time,
 			Date:      m.AlertForm.Date,
 			Active:    true,
-			CreatedAt: time.Now(),
+			CreatedAt: m.Now(),
 		}
 
 		// Set recurrence if not one-time
 		if alert.Date == "" {
 			alert.Recurrence.Type = m.AlertForm.Recurrence
+			alert.Cron = strings.TrimSpace(m.AlertForm.Cron)
+			// The form checked both are numbers in range, if given
+			alert.Recurrence.MonthDay, _ = strconv.Atoi(strings.TrimSpace(m.AlertForm.MonthDay))
+			alert.Recurrence.Month, _ = strconv.Atoi(strings.TrimSpace(m.AlertForm.Month))
+			if alert.IsCron() {
+				alert.Time = ""
+			}
 			if m.AlertForm.Interval != "" {
 				interval, err := strconv.Atoi(m.AlertForm.Interval)
 				if err != nil || interval < 1 {
@@ -75,14 +82,16 @@ func HandleAddAlertState(m *state.Model, msg tea.Msg) tea.Cmd {
 			alertsList, _ := m.Store.GetAllAlerts()
 			m.AlertsModel.SetAlerts(alertsList)
 			m.FormError = "" // Clear any previous errors
-			m.State = constants.StateAlerts
+			cmds = append(cmds, leaveForm(m, constants.StateAlerts, true))
+			cmds = append(cmds, m.NotifySuccess("Alert added"))
 		} else {
 			// Store error and stay in form state to allow retry
 			m.FormError = fmt.Sprintf("Failed to add alert: %v", err)
 			m.Form.State = huh.StateNormal
 		}
 	case huh.StateAborted:
-		m.State = constants.StateAlerts
+		m.FormError = ""
+		cmds = append(cmds, leaveForm(m, constants.StateAlerts, false))
 	}
 	return tea.Batch(cmds...)
 }
@@ -91,24 +100,33 @@ func HandleAddAlertState(m *state.Model, msg tea.Msg) tea.Cmd {
 func HandleAlertMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
 	switch msg := msg.(type) {
 	case alerts.AddAlertMsg:
-		m.AlertForm = &state.AlertFormModel{
-			Message:    "",
-			Time:       "",
-			Date:       "",
-			Recurrence: constants.RecurrenceDaily,
-			Interval:   "1",
-			Weekdays:   "",
-		}
-		m.Form = NewAlertForm(m.AlertForm)
-		m.State = constants.StateAddAlert
-		return true, m.Form.Init()
+		return true, openAlertForm(m, "")
 
 	case alerts.DeleteAlertMsg:
-		if err := m.Store.DeleteAlert(msg.ID); err == nil {
-			alertsList, _ := m.Store.GetAllAlerts()
-			m.AlertsModel.SetAlerts(alertsList)
+		if err := m.Store.DeleteAlert(msg.ID); err != nil {
+			return true, m.NotifyError("Failed to delete alert", err)
 		}
-		return true, nil
+		alertsList, _ := m.Store.GetAllAlerts()
+		m.AlertsModel.SetAlerts(alertsList)
+		return true, m.NotifySuccess("Alert deleted")
 	}
 	return false, nil
 }
+
+// openAlertForm opens the form for a new daily alert with message
+func openAlertForm(m *state.Model, message string) tea.Cmd {
+	m.AlertForm = &state.AlertFormModel{
+		Message:    message,
+		Time:       "",
+		Date:       "",
+		Recurrence: constants.RecurrenceDaily,
+		Interval:   "1",
+		Weekdays:   "",
+		MonthDay:   "",
+		Month:      "",
+		Cron:       "",
+	}
+	m.Form = NewAlertForm(m.AlertForm)
+	m.State = constants.StateAddAlert
+	return m.Form.Init()
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/autoplan"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/calendar"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// Plan generation and the larger store reads run in background commands so
+// the TUI keeps responding on slow connections. The commands only use the
+// store and scheduler; their results come back as the messages below and are
+// applied to the model in HandleAsyncMessages.
+
+// PlanGeneratedMsg carries a plan generated and saved in the background
+type PlanGeneratedMsg struct {
+	Plan    models.DayPlan
+	Tasks   []models.Task // All tasks, including deleted ones, to show the plan with
+	Success string        // Status message on success
+	Failure string        // Action reported in the status bar when Err is set
+	Err     error
+}
+
+// WeekGeneratedMsg carries the result of generating draft plans for the
+// days of a week that had none
+type WeekGeneratedMsg struct {
+	Generated int
+	Today     *models.DayPlan // Today's new plan, if today was one of the days
+	Tasks     []models.Task
+	Failures  []DayFailure
+	Err       error
+}
+
+// DayFailure is a day whose plan could not be generated
+type DayFailure struct {
+	Date string
+	Err  error
+}
+
+// CalendarLoadedMsg carries the day summaries and vacations of a month
+type CalendarLoadedMsg struct {
+	Start, End string
+	Summaries  []models.DaySummary
+	Vacations  []models.Vacation
+	Failure    string
+	Err        error
+}
+
+// WeekLoadedMsg carries the plans of a week
+type WeekLoadedMsg struct {
+	Start time.Time
+	Data  state.WeekData
+	Err   error
+}
+
+// HandleAsyncMessages applies the results of background commands and keeps
+// the spinner turning while any are running. Results arrive in every state.
+func HandleAsyncMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
+	switch msg := msg.(type) {
+	case spinner.TickMsg:
+		if len(m.Busy) == 0 {
+			// Let the spinner stop until the next operation starts
+			return true, nil
+		}
+		var cmd tea.Cmd
+		m.Spinner, cmd = m.Spinner.Update(msg)
+		return true, cmd
+
+	case state.ValidationMsg:
+		m.ApplyValidation(msg)
+		return true, nil
+
+	case PlanGeneratedMsg:
+		m.EndBusy(state.BusyGenerating)
+		if msg.Err != nil {
+			return true, m.NotifyError(msg.Failure, msg.Err)
+		}
+		m.PlanModel.SetPlan(msg.Plan, msg.Tasks)
+		if msg.Plan.Date == m.Today {
+			m.NowModel.SetPlan(msg.Plan, msg.Tasks)
+		}
+		return true, tea.Batch(m.Validate(), m.NotifySuccess(msg.Success))
+
+	case WeekGeneratedMsg:
+		m.EndBusy(state.BusyGenerating)
+		if msg.Err != nil {
+			return true, m.NotifyError("Failed to get settings", msg.Err)
+		}
+		var cmds []tea.Cmd
+		for _, failure := range msg.Failures {
+			cmds = append(cmds, m.NotifyError("Failed to generate plan for "+failure.Date, failure.Err))
+		}
+		if msg.Today != nil {
+			m.PlanModel.SetPlan(*msg.Today, msg.Tasks)
+			m.NowModel.SetPlan(*msg.Today, msg.Tasks)
+			cmds = append(cmds, m.Validate())
+		}
+		if msg.Generated > 0 {
+			cmds = append(cmds, m.NotifySuccess(fmt.Sprintf("Generated %d draft plan(s)", msg.Generated)))
+		}
+		cmds = append(cmds, refreshWeek(m, m.WeekModel.Start()))
+		return true, tea.Batch(cmds...)
+
+	case CalendarLoadedMsg:
+		m.EndBusy(state.BusyLoading)
+		if msg.Err != nil {
+			return true, m.NotifyError(msg.Failure, msg.Err)
+		}
+		// The user may have moved on to another month in the meantime
+		if start, _ := calendar.MonthRange(m.CalendarModel.Cursor()); start != msg.Start {
+			return true, nil
+		}
+		m.CalendarModel.SetSummaries(msg.Summaries)
+		m.CalendarModel.SetVacations(msg.Vacations)
+		return true, nil
+
+	case WeekLoadedMsg:
+		m.EndBusy(state.BusyLoading)
+		if msg.Err != nil {
+			return true, m.NotifyError("Failed to load week", msg.Err)
+		}
+		if !msg.Start.Equal(m.WeekModel.Start()) {
+			return true, nil
+		}
+		m.SetWeek(msg.Data)
+		return true, nil
+	}
+	return false, nil
+}
+
+// GeneratePlan generates a draft plan for date in the background, as the
+// Plan tab's generate key does when date has no plan yet
+func GeneratePlan(m *state.Model, date string) tea.Cmd {
+	return generatePlan(m, date, false, "Plan generated")
+}
+
+// generatePlan generates and saves the plan for date in the background,
+// keeping the locked part of an existing plan. accept marks the new plan
+// accepted.
+func generatePlan(m *state.Model, date string, accept bool, success string) tea.Cmd {
+	if m.IsBusy(state.BusyGenerating) {
+		return m.NotifyInfo("A plan is already being generated")
+	}
+	store, sched := m.Store, m.Scheduler
+	now := m.Now()
+	return tea.Batch(m.StartBusy(state.BusyGenerating), func() tea.Msg {
+		settings, err := store.GetSettings()
+		if err != nil {
+			return PlanGeneratedMsg{Failure: "Failed to get settings", Err: err}
+		}
+		plan, err := autoplan.Generate(store, sched, settings, date, accept, now)
+		if err != nil {
+			return PlanGeneratedMsg{Failure: "Failed to generate plan", Err: err}
+		}
+		tasks, _ := store.GetAllTasksIncludingDeleted()
+		return PlanGeneratedMsg{Plan: plan, Tasks: tasks, Success: success}
+	})
+}
+
+// generateWeek generates draft plans in the background for the dates that
+// have no plan yet
+func generateWeek(m *state.Model, dates []string) tea.Cmd {
+	if m.IsBusy(state.BusyGenerating) {
+		return m.NotifyInfo("A plan is already being generated")
+	}
+	store, sched := m.Store, m.Scheduler
+	now := m.Now()
+	today := now.Format(constants.DateFormat)
+	return tea.Batch(m.StartBusy(state.BusyGenerating), func() tea.Msg {
+		settings, err := store.GetSettings()
+		if err != nil {
+			return WeekGeneratedMsg{Err: err}
+		}
+
+		var result WeekGeneratedMsg
+		for _, date := range dates {
+			// Never replace a plan that was created after the week was loaded
+			if _, err := store.GetPlan(date); err == nil {
+				continue
+			}
+			plan, err := autoplan.Generate(store, sched, settings, date, false, now)
+			if err != nil {
+				result.Failures = append(result.Failures, DayFailure{Date: date, Err: err})
+				continue
+			}
+			result.Generated++
+			if date == today {
+				result.Today = &plan
+			}
+		}
+		result.Tasks, _ = store.GetAllTasksIncludingDeleted()
+		return result
+	})
+}
+
+// loadCalendar loads the day summaries and vacations between start and end
+// in the background
+func loadCalendar(m *state.Model, start, end string) tea.Cmd {
+	store := m.Store
+	return tea.Batch(m.StartBusy(state.BusyLoading), func() tea.Msg {
+		summaries, err := store.GetDaySummaries(start, end)
+		if err != nil {
+			return CalendarLoadedMsg{Failure: "Failed to load calendar", Err: err}
+		}
+		vacations, err := store.GetVacations(start, end)
+		if err != nil {
+			return CalendarLoadedMsg{Failure: "Failed to load vacations", Err: err}
+		}
+		return CalendarLoadedMsg{Start: start, End: end, Summaries: summaries, Vacations: vacations}
+	})
+}
+
+// refreshWeek reloads the week view starting at start in the background
+func refreshWeek(m *state.Model, start time.Time) tea.Cmd {
+	store := m.Store
+	return tea.Batch(m.StartBusy(state.BusyLoading), func() tea.Msg {
+		data, err := state.LoadWeek(store, start)
+		return WeekLoadedMsg{Start: start, Data: data, Err: err}
+	})
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// runAsync runs cmd and the commands it leads to, as the TUI would, leaving
+// out the spinner, and returns the messages that reached HandleAsyncMessages
+func runAsync(t *testing.T, m *state.Model, cmd tea.Cmd) []tea.Msg {
+	t.Helper()
+	var handled []tea.Msg
+	queue := []tea.Cmd{cmd}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if next == nil {
+			continue
+		}
+		switch msg := next().(type) {
+		case tea.BatchMsg:
+			queue = append(queue, msg...)
+		case spinner.TickMsg:
+			// The spinner keeps ticking while anything is busy
+		default:
+			if ok, follow := HandleAsyncMessages(m, msg); ok {
+				handled = append(handled, msg)
+				queue = append(queue, follow)
+			}
+		}
+		if len(handled) > 100 {
+			t.Fatal("background commands did not settle")
+		}
+	}
+	return handled
+}
+
+func TestGeneratePlanInBackground(t *testing.T) {
+	store := sqlite.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+
+	task := models.Task{
+		ID:          "task-async",
+		Name:        "Write",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+		Priority:    1,
+		Active:      true,
+	}
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	m := state.New(store, scheduler.New(), clock.System)
+	cmd := GeneratePlan(&m, m.Today)
+	if !m.IsBusy(state.BusyGenerating) {
+		t.Fatal("expected the model to be busy while the plan is generated")
+	}
+	if m.PlanModel.Plan != nil {
+		t.Fatal("the plan should only be shown once generated")
+	}
+
+	// A second request doesn't start another generation
+	GeneratePlan(&m, m.Today)
+	if len(m.Busy) != 1 {
+		t.Fatalf("expected one operation running, got %v", m.Busy)
+	}
+
+	runAsync(t, &m, cmd)
+
+	if len(m.Busy) != 0 {
+		t.Errorf("expected every operation to have finished, got %v", m.Busy)
+	}
+	if m.PlanModel.Plan == nil || len(m.PlanModel.Plan.Slots) != 1 {
+		t.Fatal("expected the generated plan in the Plan tab")
+	}
+	if m.NowModel.Plan == nil {
+		t.Error("expected today's plan in the Now tab")
+	}
+	if _, err := store.GetPlan(m.Today); err != nil {
+		t.Errorf("expected the plan to be saved: %v", err)
+	}
+}
+
+func TestValidationKeepsLatestResult(t *testing.T) {
+	store := sqlite.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+
+	m := state.New(store, scheduler.New(), clock.System)
+	first := m.Validate()
+	second := m.Validate()
+
+	// The newer run finishes first; the older result must not replace it
+	runAsync(t, &m, second)
+	m.ValidationWarning = "latest"
+	runAsync(t, &m, first)
+
+	if m.ValidationWarning != "latest" {
+		t.Errorf("a stale validation replaced the latest result: %q", m.ValidationWarning)
+	}
+	if len(m.Busy) != 0 {
+		t.Errorf("expected every operation to have finished, got %v", m.Busy)
+	}
+}
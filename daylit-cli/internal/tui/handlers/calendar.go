@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/calendar"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// HandleCalendarMessages handles messages from the calendar component
+func HandleCalendarMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
+	switch msg := msg.(type) {
+	case calendar.MonthChangedMsg:
+		return true, loadCalendar(m, msg.Start, msg.End)
+
+	case calendar.SelectDayMsg:
+		openPlanForDate(m, msg.Date)
+		return true, nil
+	}
+	return false, nil
+}
+
+// openPlanForDate loads the plan for the given date into the Plan tab and switches to it
+func openPlanForDate(m *state.Model, date string) {
+	tasks, _ := m.Store.GetAllTasksIncludingDeleted()
+	if plan, err := m.Store.GetPlan(date); err == nil {
+		m.PlanModel.SetPlan(plan, tasks)
+	} else {
+		m.PlanModel.ClearPlan(date)
+	}
+	m.State = constants.StatePlan
+}
+
+// RefreshCalendar reloads summaries for the month currently shown in the calendar
+func RefreshCalendar(m *state.Model) tea.Cmd {
+	start, end := calendar.MonthRange(m.CalendarModel.Cursor())
+	return loadCalendar(m, start, end)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// ChangeMsg reports that the database changed, possibly on another machine
+type ChangeMsg models.Change
+
+// WaitForChange returns a command that waits for the next change on ch. It
+// returns nil when ch is nil, so stores without change support cost nothing.
+func WaitForChange(ch <-chan models.Change) tea.Cmd {
+	if ch == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		c, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return ChangeMsg(c)
+	}
+}
+
+// RefreshMsg asks for the views to be reloaded once no form or dialog is open
+type RefreshMsg struct{}
+
+// HandleChange reloads the views after the database changed, unless a
+// refresh is already waiting
+func HandleChange(m *state.Model) tea.Cmd {
+	if m.RefreshPending {
+		return nil
+	}
+	return HandleRefresh(m)
+}
+
+// HandleRefresh reloads the views. Reloading while a form or dialog is open
+// could discard what the user is editing, so the refresh is retried until
+// they are back on a tab.
+func HandleRefresh(m *state.Model) tea.Cmd {
+	if !isTabState(m.State) {
+		m.RefreshPending = true
+		return tea.Tick(time.Second, func(time.Time) tea.Msg { return RefreshMsg{} })
+	}
+	m.RefreshPending = false
+	return RefreshAll(m)
+}
+
+// RefreshAll reloads every view from the store
+func RefreshAll(m *state.Model) tea.Cmd {
+	// Settings first, so the views below render with the current theme
+	if settings, err := m.Store.GetSettings(); err == nil {
+		otSettings, _ := m.Store.GetOTSettings()
+		if window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd); err == nil {
+			m.NowModel.SetWindow(window)
+		}
+		m.ApplyTheme(settings.Theme)
+		m.ApplyWeekStart(settings.FirstWeekday())
+		m.SettingsModel.SetSettings(settings, otSettings)
+	}
+
+	tasks, _ := m.Store.GetAllTasksIncludingDeleted()
+	m.TaskList.SetTasks(tasks)
+
+	// Plan tab, keeping the date being viewed
+	date := m.PlanModel.Date
+	if date == "" {
+		date = m.Today
+	}
+	if plan, err := m.Store.GetPlan(date); err == nil {
+		m.PlanModel.SetPlan(plan, tasks)
+	} else {
+		m.PlanModel.ClearPlan(date)
+	}
+
+	// Now tab
+	if plan, err := m.Store.GetPlan(m.Today); err == nil {
+		m.NowModel.SetPlan(plan, tasks)
+	} else {
+		m.NowModel.ClearPlan()
+	}
+	yesterday := m.Now().AddDate(0, 0, -1).Format(constants.DateFormat)
+	if prevPlan, err := m.Store.GetPlan(yesterday); err == nil {
+		m.NowModel.SetPrevious(&prevPlan, tasks)
+	} else {
+		m.NowModel.SetPrevious(nil, nil)
+	}
+
+	refreshHabits(m)
+	if entry, err := m.Store.GetOTEntry(m.Today); err == nil && entry.ID != "" {
+		m.OTModel.SetEntry(&entry)
+	} else {
+		m.OTModel.SetEntry(nil)
+	}
+
+	alertsList, _ := m.Store.GetAllAlerts()
+	m.AlertsModel.SetAlerts(alertsList)
+	refreshInbox(m)
+	refreshTrash(m)
+
+	return tea.Batch(m.Validate(), RefreshCalendar(m), refreshWeek(m, m.WeekModel.Start()))
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+func TestHandleChange(t *testing.T) {
+	store := sqlite.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+
+	m := state.New(store, scheduler.New(), clock.System)
+
+	// Another machine saves today's plan while a form is open
+	task := models.Task{
+		ID:          "task-remote",
+		Name:        "Remote",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+		Priority:    1,
+		Active:      true,
+	}
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	plan := models.DayPlan{
+		Date:  m.Today,
+		Slots: []models.Slot{{Start: "09:00", End: "09:30", TaskID: task.ID, Status: constants.SlotStatusPlanned}},
+	}
+	if err := store.SavePlan(plan); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+	m.State = constants.StateEditing
+
+	if cmd := HandleChange(&m); cmd == nil {
+		t.Fatal("expected a retry while the form is open")
+	}
+	if !m.RefreshPending {
+		t.Fatal("expected the refresh to be pending")
+	}
+	if m.PlanModel.Plan != nil {
+		t.Fatal("the plan should not reload while the form is open")
+	}
+	if cmd := HandleChange(&m); cmd != nil {
+		t.Error("a second change should not start another retry")
+	}
+
+	m.State = constants.StatePlan
+	HandleRefresh(&m)
+	if m.RefreshPending {
+		t.Error("the refresh should no longer be pending")
+	}
+	if m.PlanModel.Plan == nil || len(m.PlanModel.Plan.Slots) != 1 {
+		t.Error("expected today's plan after the refresh")
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/week"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// TestFixedClock checks that the tabs go by the model's clock rather than
+// the system's, as they do under --now
+func TestFixedClock(t *testing.T) {
+	store := sqlite.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+
+	// A Wednesday long past, in a week that starts on Monday
+	now := time.Date(2025, 3, 12, 9, 0, 0, 0, time.Local)
+	settings, err := store.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	settings.WeekStart = "monday"
+	if err := store.SaveSettings(settings); err != nil {
+		t.Fatalf("failed to save settings: %v", err)
+	}
+	if err := store.SavePlan(models.DayPlan{Date: "2025-03-13", Slots: []models.Slot{}}); err != nil {
+		t.Fatalf("failed to save plan: %v", err)
+	}
+	if err := store.AddHabit(models.Habit{
+		ID: "run", Name: "Run", CreatedAt: now.AddDate(0, -1, 0),
+		PausedFrom: "2025-03-12", PausedUntil: "2025-03-12",
+	}); err != nil {
+		t.Fatalf("failed to add habit: %v", err)
+	}
+
+	m := state.New(store, scheduler.New(), clock.Fixed(now))
+
+	// Planning the week skips the days before the frozen today and the day
+	// already planned
+	_, cmd := m.WeekModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if cmd == nil {
+		t.Fatal("planning the week did nothing")
+	}
+	msg, ok := cmd().(week.GenerateWeekMsg)
+	if !ok {
+		t.Fatalf("planning the week sent %T", cmd())
+	}
+	if want := []string{"2025-03-12", "2025-03-14", "2025-03-15", "2025-03-16"}; !slices.Equal(msg.Dates, want) {
+		t.Errorf("unplanned dates = %v, want %v", msg.Dates, want)
+	}
+
+	// The habit is paused on the frozen today, both at startup and after a
+	// refresh
+	m.HabitsModel.SetSize(80, 20)
+	if view := m.HabitsModel.View(); !strings.Contains(view, "paused until 2025-03-12") {
+		t.Errorf("habits view doesn't show the pause:\n%s", view)
+	}
+	refreshHabits(&m)
+	if view := m.HabitsModel.View(); !strings.Contains(view, "paused until 2025-03-12") {
+		t.Errorf("habits view doesn't show the pause after a refresh:\n%s", view)
+	}
+}
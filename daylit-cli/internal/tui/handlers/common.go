@@ -11,6 +11,7 @@ import (
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
 	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
 )
 
@@ -73,6 +74,34 @@ func NewEditForm(fm *state.TaskFormModel) *huh.Form {
 					}
 					return nil
 				}),
+			huh.NewInput().
+				Title("Until (YYYY-MM-DD)").
+				Description("Last date the task recurs on; empty for no end").
+				Value(&fm.Until).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return nil
+					}
+					_, err := time.Parse(constants.DateFormat, strings.TrimSpace(s))
+					return err
+				}),
+			huh.NewInput().
+				Title("Occurrences").
+				Description("Stop recurring after this many, counted from today; empty for no limit").
+				Value(&fm.Count).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return nil
+					}
+					i, err := strconv.Atoi(strings.TrimSpace(s))
+					if err != nil {
+						return err
+					}
+					if i < 0 {
+						return fmt.Errorf("occurrences cannot be negative")
+					}
+					return nil
+				}),
 			huh.NewInput().
 				Title("Priority (1-5)").
 				Value(&fm.Priority).
@@ -90,7 +119,7 @@ func NewEditForm(fm *state.TaskFormModel) *huh.Form {
 				Title("Active").
 				Value(&fm.Active),
 		),
-	).WithTheme(huh.ThemeDracula())
+	).WithTheme(theme.Form())
 }
 
 // NewHabitForm creates a new form for adding habits
@@ -106,8 +135,12 @@ func NewHabitForm(fm *state.HabitFormModel) *huh.Form {
 					}
 					return nil
 				}),
+			huh.NewInput().
+				Title("Category (optional)").
+				Description("e.g. health, chores, learning").
+				Value(&fm.Category),
 		),
-	).WithTheme(huh.ThemeDracula())
+	).WithTheme(theme.Form())
 }
 
 // NewAlertForm creates a new form for adding alerts
@@ -125,8 +158,13 @@ func NewAlertForm(fm *state.AlertFormModel) *huh.Form {
 				}),
 			huh.NewInput().
 				Title("Time (HH:MM)").
+				Description("Leave empty for cron alerts").
 				Value(&fm.Time).
 				Validate(func(s string) error {
+					// Cron alerts take their times from the expression
+					if strings.TrimSpace(s) == "" {
+						return nil
+					}
 					_, err := time.Parse(constants.TimeFormat, s)
 					if err != nil {
 						return fmt.Errorf("invalid time format, use HH:MM")
@@ -154,6 +192,9 @@ func NewAlertForm(fm *state.AlertFormModel) *huh.Form {
 					huh.NewOption("Daily", constants.RecurrenceDaily),
 					huh.NewOption("Weekly", constants.RecurrenceWeekly),
 					huh.NewOption("Every N Days", constants.RecurrenceNDays),
+					huh.NewOption("Monthly", constants.RecurrenceMonthlyDate),
+					huh.NewOption("Yearly", constants.RecurrenceYearly),
+					huh.NewOption("Cron expression", constants.RecurrenceCron),
 				).
 				Value(&fm.Recurrence).
 				Validate(func(r constants.RecurrenceType) error {
@@ -184,8 +225,43 @@ func NewAlertForm(fm *state.AlertFormModel) *huh.Form {
 				Title("Weekdays").
 				Description("For weekly: comma-separated (mon,wed,fri)").
 				Value(&fm.Weekdays),
+			huh.NewInput().
+				Title("Day of month (1-31)").
+				Description("For monthly and yearly recurrence").
+				Value(&fm.MonthDay).
+				Validate(optionalIntInRange(1, 31)),
+			huh.NewInput().
+				Title("Month (1-12)").
+				Description("For yearly recurrence").
+				Value(&fm.Month).
+				Validate(optionalIntInRange(1, 12)),
+			huh.NewInput().
+				Title("Cron expression").
+				Description("For cron recurrence: minute hour day month weekday, e.g. 0 9 * * 1-5").
+				Value(&fm.Cron).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return nil
+					}
+					_, err := models.ParseCron(s)
+					return err
+				}),
 		),
-	).WithTheme(huh.ThemeDracula())
+	).WithTheme(theme.Form())
+}
+
+// optionalIntInRange validates an optional whole number between min and max
+func optionalIntInRange(min, max int) func(string) error {
+	return func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return nil
+		}
+		i, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || i < min || i > max {
+			return fmt.Errorf("must be a number from %d to %d", min, max)
+		}
+		return nil
+	}
 }
 
 // NewSettingsForm creates a new form for editing settings
@@ -203,17 +279,18 @@ func NewSettingsForm(fm *state.SettingsFormModel) *huh.Form {
 					return nil
 				}),
 			huh.NewInput().
-				Title("Day End (HH:MM)").
+				Title("Day End (HH:MM, may be after midnight)").
 				Value(&fm.DayEnd).
 				Validate(func(s string) error {
 					endTime, err := time.Parse(constants.TimeFormat, s)
 					if err != nil {
 						return fmt.Errorf("invalid time format, use HH:MM")
 					}
-					// Cross-field validation: ensure Day End is after Day Start
+					// Cross-field validation: a Day End before Day Start is after
+					// midnight, but they can't be equal
 					startTime, err := time.Parse(constants.TimeFormat, fm.DayStart)
-					if err == nil && !endTime.After(startTime) {
-						return fmt.Errorf("day end must be after day start")
+					if err == nil && endTime.Equal(startTime) {
+						return fmt.Errorf("day end must differ from day start")
 					}
 					return nil
 				}),
@@ -240,6 +317,19 @@ func NewSettingsForm(fm *state.SettingsFormModel) *huh.Form {
 					}
 					return nil
 				}),
+			huh.NewSelect[string]().
+				Title("Theme").
+				Description("Colors used by the TUI; NO_COLOR overrides this").
+				Options(huh.NewOptions(theme.Names()...)...).
+				Value(&fm.Theme),
+			huh.NewSelect[string]().
+				Title("Week Starts On").
+				Description("First day of the week and calendar views, reports and week numbers").
+				Options(
+					huh.NewOption("Sunday", constants.WeekStartSunday),
+					huh.NewOption("Monday", constants.WeekStartMonday),
+				).
+				Value(&fm.WeekStart),
 			huh.NewConfirm().
 				Title("Prompt On Empty").
 				Value(&fm.PromptOnEmpty),
@@ -285,7 +375,7 @@ func NewSettingsForm(fm *state.SettingsFormModel) *huh.Form {
 					return err
 				}),
 		),
-	).WithTheme(huh.ThemeDracula())
+	).WithTheme(theme.Form())
 }
 
 // NewOTForm creates a new form for editing One Thing
@@ -305,5 +395,17 @@ func NewOTForm(fm *state.OTFormModel) *huh.Form {
 				Title("Note (optional)").
 				Value(&fm.Note),
 		),
-	).WithTheme(huh.ThemeDracula())
+	).WithTheme(theme.Form())
+}
+
+// NewNoteForm creates a new form for a plan's notes
+func NewNoteForm(fm *state.NoteFormModel) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title(fmt.Sprintf("Notes for %s", fm.Date)).
+				Description("Context about the day, such as why it went sideways").
+				Value(&fm.Note),
+		),
+	).WithTheme(theme.Form())
 }
@@ -1,16 +1,16 @@
 package handlers
 
 import (
-	"time"
-
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/julianstephens/daylit/daylit-cli/internal/autoplan"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
 )
 
 // HandleConfirmDeleteState handles the delete confirmation state
 func HandleConfirmDeleteState(m *state.Model, msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
 	if msg, ok := msg.(tea.KeyMsg); ok {
 		switch msg.String() {
 		case "y", "Y":
@@ -18,7 +18,9 @@ func HandleConfirmDeleteState(m *state.Model, msg tea.Msg) tea.Cmd {
 				if err := m.Store.DeleteTask(m.TaskToDeleteID); err == nil {
 					tasks, _ := m.Store.GetAllTasksIncludingDeleted()
 					m.TaskList.SetTasks(tasks)
-					m.UpdateValidationStatus()
+					cmd = tea.Batch(m.Validate(), m.NotifySuccess("Task deleted"))
+				} else {
+					cmd = m.NotifyError("Failed to delete task", err)
 				}
 				m.TaskToDeleteID = ""
 			}
@@ -28,11 +30,12 @@ func HandleConfirmDeleteState(m *state.Model, msg tea.Msg) tea.Cmd {
 			m.State = constants.StateTasks
 		}
 	}
-	return nil
+	return cmd
 }
 
 // HandleConfirmRestoreState handles the restore confirmation state
 func HandleConfirmRestoreState(m *state.Model, msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
 	if msg, ok := msg.(tea.KeyMsg); ok {
 		switch msg.String() {
 		case "y", "Y":
@@ -40,21 +43,25 @@ func HandleConfirmRestoreState(m *state.Model, msg tea.Msg) tea.Cmd {
 				if err := m.Store.RestoreTask(m.TaskToRestoreID); err == nil {
 					tasks, _ := m.Store.GetAllTasksIncludingDeleted()
 					m.TaskList.SetTasks(tasks)
-					m.UpdateValidationStatus()
+					cmd = tea.Batch(m.Validate(), m.NotifySuccess("Task restored"))
+				} else {
+					cmd = m.NotifyError("Failed to restore task", err)
 				}
 				m.TaskToRestoreID = ""
 				m.State = constants.StateTasks
 			} else if m.PlanToRestoreDate != "" {
 				if err := m.Store.RestorePlan(m.PlanToRestoreDate); err == nil {
 					// Restore succeeded - refresh plan
-					today := time.Now().Format(constants.DateFormat)
+					today := m.Now().Format(constants.DateFormat)
 					plan, err := m.Store.GetPlan(today)
 					tasks, _ := m.Store.GetAllTasksIncludingDeleted()
 					if err == nil {
 						m.PlanModel.SetPlan(plan, tasks)
 						m.NowModel.SetPlan(plan, tasks)
 					}
-					m.UpdateValidationStatus()
+					cmd = tea.Batch(m.Validate(), m.NotifySuccess("Plan restored for "+m.PlanToRestoreDate))
+				} else {
+					cmd = m.NotifyError("Failed to restore plan", err)
 				}
 				m.PlanToRestoreDate = ""
 				m.State = constants.StatePlan
@@ -69,33 +76,18 @@ func HandleConfirmRestoreState(m *state.Model, msg tea.Msg) tea.Cmd {
 			}
 		}
 	}
-	return nil
+	return cmd
 }
 
 // HandleConfirmOverwriteState handles the overwrite confirmation state
 func HandleConfirmOverwriteState(m *state.Model, msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
 	if msg, ok := msg.(tea.KeyMsg); ok {
 		switch msg.String() {
 		case "y", "Y":
 			if m.PlanToOverwriteDate != "" {
-				settings, _ := m.Store.GetSettings()
-				dayStart := settings.DayStart
-				if dayStart == "" {
-					dayStart = "08:00"
-				}
-				dayEnd := settings.DayEnd
-				if dayEnd == "" {
-					dayEnd = "18:00"
-				}
-
-				tasks, _ := m.Store.GetAllTasks()
-				plan, err := m.Scheduler.GeneratePlan(m.PlanToOverwriteDate, tasks, dayStart, dayEnd)
-				if err == nil {
-					m.Store.SavePlan(plan)
-					m.PlanModel.SetPlan(plan, tasks)
-					m.NowModel.SetPlan(plan, tasks)
-					m.UpdateValidationStatus()
-				}
+				// The locked part of the day stays as it is
+				cmd = generatePlan(m, m.PlanToOverwriteDate, false, "Plan regenerated for "+m.PlanToOverwriteDate)
 				m.PlanToOverwriteDate = ""
 			}
 			m.State = constants.StatePlan
@@ -104,21 +96,21 @@ func HandleConfirmOverwriteState(m *state.Model, msg tea.Msg) tea.Cmd {
 			m.State = constants.StatePlan
 		}
 	}
-	return nil
+	return cmd
 }
 
 // HandleConfirmArchiveState handles the archive confirmation state
 func HandleConfirmArchiveState(m *state.Model, msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
 	if msg, ok := msg.(tea.KeyMsg); ok {
 		switch msg.String() {
 		case "y", "Y":
 			if m.HabitToArchiveID != "" {
 				if err := m.Store.ArchiveHabit(m.HabitToArchiveID); err == nil {
-					// Refresh habits list
-					today := time.Now().Format(constants.DateFormat)
-					habitsList, _ := m.Store.GetAllHabits(false, true)
-					habitEntries, _ := m.Store.GetHabitEntriesForDay(today)
-					m.HabitsModel.SetHabits(habitsList, habitEntries)
+					refreshHabits(m)
+					cmd = m.NotifySuccess("Habit archived")
+				} else {
+					cmd = m.NotifyError("Failed to archive habit", err)
 				}
 				m.HabitToArchiveID = ""
 			}
@@ -128,5 +120,53 @@ func HandleConfirmArchiveState(m *state.Model, msg tea.Msg) tea.Cmd {
 			m.State = constants.StateHabits
 		}
 	}
+	return cmd
+}
+
+// CheckMorningPlan runs the morning plan check when the TUI starts. Prompt
+// mode asks whether to generate today's plan; hands-free mode generates and
+// accepts it.
+func CheckMorningPlan(m *state.Model) tea.Cmd {
+	settings, err := m.Store.GetSettings()
+	if err != nil {
+		return nil
+	}
+	today := m.Now().Format(constants.DateFormat)
+	if settings.MorningPlanDismissedOn == today || !autoplan.Due(m.Store, settings, m.Now()) {
+		return nil
+	}
+
+	if settings.MorningPlan == constants.MorningPlanHandsFree {
+		return generateMorningPlan(m, "Today's plan was generated and accepted")
+	}
+	m.State = constants.StateConfirmMorningPlan
 	return nil
 }
+
+// HandleConfirmMorningPlanState handles the morning plan prompt
+func HandleConfirmMorningPlanState(m *state.Model, msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "y", "Y":
+			cmd = generateMorningPlan(m, "Today's plan accepted")
+			m.State = constants.StatePlan
+		case "n", "N", "esc":
+			// Don't ask again today
+			if settings, err := m.Store.GetSettings(); err == nil {
+				settings.MorningPlanDismissedOn = m.Now().Format(constants.DateFormat)
+				if err := m.Store.SaveSettings(settings); err != nil {
+					cmd = m.NotifyError("Failed to save settings", err)
+				}
+			}
+			m.State = constants.StateNow
+		}
+	}
+	return cmd
+}
+
+// generateMorningPlan generates and accepts today's plan in the background
+// and shows it
+func generateMorningPlan(m *state.Model, success string) tea.Cmd {
+	return generatePlan(m, m.Now().Format(constants.DateFormat), true, success)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// newTaskConflict merges the edits from base to mine into the latest stored
+// version of the task
+func newTaskConflict(store storage.Provider, base, mine models.Task) (*state.SaveConflict, error) {
+	latest, err := store.GetTask(mine.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the latest version: %w", err)
+	}
+	merged, overlapping := models.MergeChanges(base, mine, latest)
+	return &state.SaveConflict{
+		Record:      fmt.Sprintf("task %q", mine.Name),
+		Overlapping: overlapping,
+		Keep:        func() error { return store.UpdateTask(merged) },
+		ReturnState: constants.StateTasks,
+	}, nil
+}
+
+// newSettingsConflict merges the edits from base to mine into the latest
+// stored settings
+func newSettingsConflict(store storage.Provider, base, mine models.Settings) (*state.SaveConflict, error) {
+	latest, err := store.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the latest settings: %w", err)
+	}
+	merged, overlapping := models.MergeChanges(base, mine, latest)
+	return &state.SaveConflict{
+		Record:      "settings",
+		Overlapping: overlapping,
+		Keep:        func() error { return store.SaveSettings(merged) },
+		ReturnState: constants.StateSettings,
+	}, nil
+}
+
+// handleSaveConflict saves a rejected edit again on top of the latest
+// record. Edits to fields nobody else changed are merged without asking; if
+// both sides changed the same field, the user picks which version to keep.
+func handleSaveConflict(m *state.Model, c *state.SaveConflict) tea.Cmd {
+	if len(c.Overlapping) == 0 {
+		return resolveConflict(m, c, true)
+	}
+	m.Conflict = c
+	m.State = constants.StateConfirmConflict
+	return nil
+}
+
+// HandleConfirmConflictState handles the prompt to keep or discard changes
+// that conflict with changes made elsewhere
+func HandleConfirmConflictState(m *state.Model, msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok && m.Conflict != nil {
+		switch msg.String() {
+		case "k", "K":
+			return resolveConflict(m, m.Conflict, true)
+		case "d", "D", "esc":
+			return resolveConflict(m, m.Conflict, false)
+		}
+	}
+	return nil
+}
+
+// resolveConflict saves or drops the user's changes and reloads the views
+func resolveConflict(m *state.Model, c *state.SaveConflict, keep bool) tea.Cmd {
+	m.Conflict = nil
+	m.State = c.ReturnState
+
+	var cmd tea.Cmd
+	if !keep {
+		cmd = m.NotifyInfo("Discarded your changes to " + c.Record)
+	} else if err := c.Keep(); err != nil {
+		cmd = m.NotifyError("Failed to save "+c.Record, err)
+	} else {
+		cmd = m.NotifySuccess("Saved " + c.Record + " with changes made elsewhere")
+	}
+	return tea.Batch(cmd, RefreshAll(m))
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+func TestTaskSaveConflict(t *testing.T) {
+	store := sqlite.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.AddTask(models.Task{
+		ID:          "task-conflict",
+		Name:        "Read",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+		Priority:    3,
+		Active:      true,
+	}); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	base, err := store.GetTask("task-conflict")
+	if err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+
+	// Another machine changes the duration while the form is open
+	theirs := base
+	theirs.DurationMin = 45
+	if err := store.UpdateTask(theirs); err != nil {
+		t.Fatalf("failed to update task: %v", err)
+	}
+
+	m := state.New(store, scheduler.New(), clock.System)
+	m.State = constants.StateEditing
+
+	t.Run("different fields merge", func(t *testing.T) {
+		mine := base
+		mine.Priority = 1
+		c, err := newTaskConflict(store, base, mine)
+		if err != nil {
+			t.Fatalf("newTaskConflict() returned unexpected error: %v", err)
+		}
+		handleSaveConflict(&m, c)
+		if m.State != constants.StateTasks || m.Conflict != nil {
+			t.Fatalf("expected the merge to be saved without asking, got state %v", m.State)
+		}
+		saved, _ := store.GetTask("task-conflict")
+		if saved.DurationMin != 45 || saved.Priority != 1 {
+			t.Errorf("expected both changes, got duration %d and priority %d", saved.DurationMin, saved.Priority)
+		}
+	})
+
+	t.Run("same field asks", func(t *testing.T) {
+		latest, _ := store.GetTask("task-conflict")
+		mine := base
+		mine.DurationMin = 20
+		m.State = constants.StateEditing
+		c, err := newTaskConflict(store, base, mine)
+		if err != nil {
+			t.Fatalf("newTaskConflict() returned unexpected error: %v", err)
+		}
+		handleSaveConflict(&m, c)
+		if m.State != constants.StateConfirmConflict || m.Conflict == nil {
+			t.Fatalf("expected the conflict prompt, got state %v", m.State)
+		}
+
+		// Discarding keeps the stored task
+		resolveConflict(&m, m.Conflict, false)
+		saved, _ := store.GetTask("task-conflict")
+		if saved.DurationMin != latest.DurationMin || saved.Version != latest.Version {
+			t.Errorf("discarding should keep the stored task, got duration %d", saved.DurationMin)
+		}
+	})
+}
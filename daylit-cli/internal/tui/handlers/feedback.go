@@ -1,19 +1,19 @@
 package handlers
 
 import (
-	"time"
-
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/hooks"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
-	"github.com/julianstephens/daylit/daylit-cli/internal/utils"
 )
 
 // HandleFeedbackState handles the feedback state using key-based rating system
 func HandleFeedbackState(m *state.Model, msg tea.Msg) tea.Cmd {
 	if msg, ok := msg.(tea.KeyMsg); ok {
+		var cmd tea.Cmd
 		var rating models.FeedbackRating
 		switch msg.String() {
 		case "1":
@@ -30,10 +30,15 @@ func HandleFeedbackState(m *state.Model, msg tea.Msg) tea.Cmd {
 		}
 
 		// Apply feedback
-		today := time.Now().Format(constants.DateFormat)
+		today := m.Now().Format(constants.DateFormat)
 		plan, err := m.Store.GetPlan(today)
-		if err == nil && m.FeedbackSlotID >= 0 && m.FeedbackSlotID < len(plan.Slots) {
+		if err != nil {
+			m.State = m.PreviousState
+			return m.NotifyError("Failed to load today's plan", err)
+		}
+		if m.FeedbackSlotID >= 0 && m.FeedbackSlotID < len(plan.Slots) {
 			slot := &plan.Slots[m.FeedbackSlotID]
+			wasDone := slot.Status == constants.SlotStatusDone
 			slot.Feedback = &models.Feedback{
 				Rating: rating,
 			}
@@ -43,7 +48,7 @@ func HandleFeedbackState(m *state.Model, msg tea.Msg) tea.Cmd {
 			if err := m.Store.SavePlan(plan); err != nil {
 				// On error, revert to previous state
 				m.State = m.PreviousState
-				return nil
+				return m.NotifyError("Failed to save feedback", err)
 			}
 
 			// Update task stats only after plan is saved
@@ -71,8 +76,10 @@ func HandleFeedbackState(m *state.Model, msg tea.Msg) tea.Cmd {
 				}
 				task.LastDone = today
 				task.SuccessStreak++
-				// Ignore task update errors to avoid inconsistency if it fails after plan save
-				m.Store.UpdateTask(task)
+				// Task stats are best-effort once the plan is saved, but surface the failure
+				if err := m.Store.UpdateTask(task); err != nil {
+					cmd = m.NotifyError("Feedback saved but task stats were not updated", err)
+				}
 			}
 
 			// Refresh views
@@ -80,17 +87,20 @@ func HandleFeedbackState(m *state.Model, msg tea.Msg) tea.Cmd {
 			if err != nil {
 				// On error, revert to previous state
 				m.State = m.PreviousState
-				return nil
+				return tea.Batch(cmd, m.NotifyError("Failed to reload tasks", err))
 			}
 			tasksIncludingDeleted, _ := m.Store.GetAllTasksIncludingDeleted()
 			m.PlanModel.SetPlan(plan, tasks)
 			m.NowModel.SetPlan(plan, tasks)
 			m.TaskList.SetTasks(tasksIncludingDeleted)
-			m.UpdateValidationStatus()
+			if cmd == nil {
+				cmd = m.NotifySuccess("Feedback recorded")
+			}
+			cmd = tea.Batch(cmd, m.Validate(), fireFeedbackHooks(hooks.Slot(m.Store, today, *slot), wasDone))
 		}
 
 		m.State = m.PreviousState
-		return nil
+		return cmd
 	}
 	return nil
 }
@@ -98,20 +108,27 @@ func HandleFeedbackState(m *state.Model, msg tea.Msg) tea.Cmd {
 // HandleFeedbackMessages handles messages related to feedback
 func HandleFeedbackMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
 	if msg, ok := msg.(tea.KeyMsg); ok {
-		if msg.String() == "f" {
+		if key.Matches(msg, m.Keys.Feedback) {
 			// Find slot for feedback
-			today := time.Now().Format(constants.DateFormat)
+			today := m.Now().Format(constants.DateFormat)
 			plan, err := m.Store.GetPlan(today)
 			if err == nil {
-				now := time.Now()
+				now := m.Now()
 				currentMinutes := now.Hour()*60 + now.Minute()
 				targetSlotIdx := -1
 
+				var window models.DayWindow
+				if settings, err := m.Store.GetSettings(); err == nil {
+					if w, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd); err == nil {
+						window = w
+					}
+				}
+
 				for i := len(plan.Slots) - 1; i >= 0; i-- {
 					slot := &plan.Slots[i]
 					if (slot.Status == constants.SlotStatusAccepted || slot.Status == constants.SlotStatusDone) &&
 						slot.Feedback == nil {
-						endMinutes, err := utils.ParseTimeToMinutes(slot.End)
+						_, endMinutes, err := window.Range(slot.Start, slot.End)
 						if err == nil && endMinutes <= currentMinutes {
 							targetSlotIdx = i
 							break
@@ -130,3 +147,15 @@ func HandleFeedbackMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
 	}
 	return false, nil
 }
+
+// fireFeedbackHooks runs the hooks for a rated slot in the background, so
+// slow hooks don't hold up the TUI
+func fireFeedbackHooks(data hooks.SlotData, wasDone bool) tea.Cmd {
+	return func() tea.Msg {
+		if !wasDone {
+			hooks.Fire(hooks.SlotDone, data)
+		}
+		hooks.Fire(hooks.FeedbackRecorded, data)
+		return nil
+	}
+}
@@ -1,13 +1,14 @@
 package handlers
 
 import (
-	"time"
+	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/google/uuid"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/hooks"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/habits"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
@@ -18,6 +19,7 @@ func HandleAddHabitState(m *state.Model, msg tea.Msg) tea.Cmd {
 	var cmds []tea.Cmd
 
 	if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyEsc {
+		m.FormError = ""
 		m.State = constants.StateHabits
 		return nil
 	}
@@ -34,21 +36,23 @@ func HandleAddHabitState(m *state.Model, msg tea.Msg) tea.Cmd {
 		habit := models.Habit{
 			ID:        uuid.New().String(),
 			Name:      m.HabitForm.Name,
-			CreatedAt: time.Now(),
+			Category:  models.NormalizeHabitCategory(m.HabitForm.Category),
+			CreatedAt: m.Now(),
 		}
 		if err := m.Store.AddHabit(habit); err == nil {
 			// Refresh habits list only if add succeeded
-			today := time.Now().Format(constants.DateFormat)
-			habitsList, _ := m.Store.GetAllHabits(false, true)
-			habitEntries, _ := m.Store.GetHabitEntriesForDay(today)
-			m.HabitsModel.SetHabits(habitsList, habitEntries)
+			refreshHabits(m)
+			m.FormError = ""
 			m.State = constants.StateHabits
+			cmds = append(cmds, m.NotifySuccess("Habit added: "+habit.Name))
 		} else {
 			// Stay in form state on error to allow retry
 			// The form will display, user can cancel with ESC or retry
+			m.FormError = fmt.Sprintf("Failed to add habit: %v", err)
 			m.Form.State = huh.StateNormal
 		}
 	case huh.StateAborted:
+		m.FormError = ""
 		m.State = constants.StateHabits
 	}
 	return tea.Batch(cmds...)
@@ -66,32 +70,39 @@ func HandleHabitMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
 		return true, m.Form.Init()
 
 	case habits.MarkHabitMsg:
-		today := time.Now().Format(constants.DateFormat)
+		today := m.Now().Format(constants.DateFormat)
 		entry := models.HabitEntry{
 			ID:        uuid.New().String(),
 			HabitID:   msg.ID,
 			Day:       today,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+			CreatedAt: m.Now(),
+			UpdatedAt: m.Now(),
 		}
-		if err := m.Store.AddHabitEntry(entry); err == nil {
-			habitsList, _ := m.Store.GetAllHabits(false, true)
-			habitEntries, _ := m.Store.GetHabitEntriesForDay(today)
-			m.HabitsModel.SetHabits(habitsList, habitEntries)
+		if err := m.Store.AddHabitEntry(entry); err != nil {
+			return true, m.NotifyError("Failed to mark habit", err)
 		}
-		return true, nil
+		refreshHabits(m)
+		data := hooks.HabitData{HabitID: msg.ID, Day: today}
+		if habit, err := m.Store.GetHabit(msg.ID); err == nil {
+			data.Name = habit.Name
+		}
+		return true, tea.Batch(m.NotifySuccess("Habit marked done"), func() tea.Msg {
+			// Hooks run in the background so slow ones don't hold up the TUI
+			hooks.Fire(hooks.HabitMarked, data)
+			return nil
+		})
 
 	case habits.UnmarkHabitMsg:
-		today := time.Now().Format(constants.DateFormat)
+		today := m.Now().Format(constants.DateFormat)
 		entry, err := m.Store.GetHabitEntry(msg.ID, today)
-		if err == nil {
-			if err := m.Store.DeleteHabitEntry(entry.ID); err == nil {
-				habitsList, _ := m.Store.GetAllHabits(false, true)
-				habitEntries, _ := m.Store.GetHabitEntriesForDay(today)
-				m.HabitsModel.SetHabits(habitsList, habitEntries)
-			}
+		if err != nil {
+			return true, m.NotifyError("Failed to unmark habit", err)
 		}
-		return true, nil
+		if err := m.Store.DeleteHabitEntry(entry.ID); err != nil {
+			return true, m.NotifyError("Failed to unmark habit", err)
+		}
+		refreshHabits(m)
+		return true, m.NotifySuccess("Habit unmarked")
 
 	case habits.ArchiveHabitMsg:
 		m.HabitToArchiveID = msg.ID
@@ -99,22 +110,26 @@ func HandleHabitMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
 		return true, nil
 
 	case habits.DeleteHabitMsg:
-		if err := m.Store.DeleteHabit(msg.ID); err == nil {
-			today := time.Now().Format(constants.DateFormat)
-			habitsList, _ := m.Store.GetAllHabits(false, true)
-			habitEntries, _ := m.Store.GetHabitEntriesForDay(today)
-			m.HabitsModel.SetHabits(habitsList, habitEntries)
+		if err := m.Store.DeleteHabit(msg.ID); err != nil {
+			return true, m.NotifyError("Failed to delete habit", err)
 		}
-		return true, nil
+		refreshHabits(m)
+		return true, m.NotifySuccess("Habit deleted")
 
 	case habits.RestoreHabitMsg:
-		if err := m.Store.RestoreHabit(msg.ID); err == nil {
-			today := time.Now().Format(constants.DateFormat)
-			habitsList, _ := m.Store.GetAllHabits(false, true)
-			habitEntries, _ := m.Store.GetHabitEntriesForDay(today)
-			m.HabitsModel.SetHabits(habitsList, habitEntries)
+		if err := m.Store.RestoreHabit(msg.ID); err != nil {
+			return true, m.NotifyError("Failed to restore habit", err)
 		}
-		return true, nil
+		refreshHabits(m)
+		return true, m.NotifySuccess("Habit restored")
 	}
 	return false, nil
 }
+
+// refreshHabits reloads the habits list with today's entries
+func refreshHabits(m *state.Model) {
+	today := m.Now().Format(constants.DateFormat)
+	habitsList, _ := m.Store.GetAllHabits(false, true)
+	habitEntries, _ := m.Store.GetHabitEntriesForDay(today)
+	m.HabitsModel.SetHabits(habitsList, habitEntries, today)
+}
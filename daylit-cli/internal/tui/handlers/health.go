@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// healthTickMsg asks for the next periodic storage health check
+type healthTickMsg struct{}
+
+// StartHealthChecks starts checking the storage backend periodically. It
+// returns nil for backends that can't become unreachable, such as SQLite.
+func StartHealthChecks(m *state.Model) tea.Cmd {
+	if _, ok := storage.Unwrap(m.Store).(storage.HealthChecker); !ok {
+		return nil
+	}
+	return scheduleHealthCheck(constants.HealthCheckInterval)
+}
+
+func scheduleHealthCheck(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg { return healthTickMsg{} })
+}
+
+// reconnectDelay is the wait before the next reconnection attempt. It
+// doubles with every failed attempt, up to constants.ReconnectMaxDelay.
+func reconnectDelay(attempts int) time.Duration {
+	delay := constants.ReconnectMinDelay
+	for i := 0; i < attempts && delay < constants.ReconnectMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, constants.ReconnectMaxDelay)
+}
+
+// HandleHealthMessages runs the storage health checks. When the backend
+// becomes unreachable the TUI explains that it is read-only and retries with
+// backoff; once it is back, the views are reloaded.
+func HandleHealthMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
+	switch msg := msg.(type) {
+	case healthTickMsg:
+		return true, m.PingStore(true)
+
+	case state.HealthMsg:
+		var cmds []tea.Cmd
+		switch {
+		case msg.Err != nil && !m.Health.Down:
+			m.Health = state.StoreHealth{Down: true, Err: msg.Err, Since: m.Now(), ReturnState: m.State}
+			m.State = constants.StateStorageDown
+		case msg.Err != nil:
+			m.Health.Err = msg.Err
+			if msg.Loop {
+				m.Health.Attempts++
+			}
+		case m.Health.Down:
+			if m.State == constants.StateStorageDown {
+				m.State = m.Health.ReturnState
+			}
+			m.Health = state.StoreHealth{}
+			cmds = append(cmds, m.NotifySuccess("Storage reconnected"), HandleRefresh(m))
+		}
+
+		if msg.Loop {
+			delay := constants.HealthCheckInterval
+			if m.Health.Down {
+				delay = reconnectDelay(m.Health.Attempts)
+			}
+			cmds = append(cmds, scheduleHealthCheck(delay))
+		}
+		return true, tea.Batch(cmds...)
+	}
+	return false, nil
+}
+
+// HandleStorageDownState handles the notice shown when the storage backend
+// becomes unreachable
+func HandleStorageDownState(m *state.Model, msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.Keys.Quit):
+			m.Quitting = true
+			return tea.Quit
+		case msg.String() == "esc" || key.Matches(msg, m.Keys.Enter):
+			m.State = m.Health.ReturnState
+		}
+	}
+	return nil
+}
+
+// HandleReadOnlyKeys keeps the tabs read-only while the storage backend is
+// unreachable: only moving around, help and quitting are allowed. Forms stay
+// usable so nothing typed is lost; saving them fails until the backend is back.
+func HandleReadOnlyKeys(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !m.Health.Down || !isTabState(m.State) {
+		return false, nil
+	}
+	if key.Matches(keyMsg, m.Keys.Quit, m.Keys.Tab, m.Keys.ShiftTab, m.Keys.Left, m.Keys.Right, m.Keys.Up, m.Keys.Down, m.Keys.Help) {
+		return false, nil
+	}
+	switch keyMsg.String() {
+	case "up", "down", "left", "right", "pgup", "pgdown", "home", "end":
+		return false, nil
+	}
+	return true, m.NotifyInfo("Storage is unavailable; daylit is read-only until it reconnects")
+}
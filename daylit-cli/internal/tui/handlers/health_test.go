@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// flakyStore is a store whose server can be made unreachable
+type flakyStore struct {
+	*sqlite.Store
+	pingErr error
+}
+
+func (s *flakyStore) Ping() error {
+	return s.pingErr
+}
+
+func TestStorageHealth(t *testing.T) {
+	store := &flakyStore{Store: sqlite.NewStore(filepath.Join(t.TempDir(), "test.db"))}
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+
+	m := state.New(store, scheduler.New(), clock.System)
+	m.State = constants.StateTasks
+	if StartHealthChecks(&m) == nil {
+		t.Fatal("expected health checks for a store that can become unreachable")
+	}
+
+	// The connection drops
+	store.pingErr = errors.New("connection refused")
+	HandleHealthMessages(&m, state.HealthMsg{Err: store.Ping(), Loop: true})
+	if !m.Health.Down || m.State != constants.StateStorageDown {
+		t.Fatalf("expected the outage notice, got state %v and health %+v", m.State, m.Health)
+	}
+
+	// Closing the notice leaves the tabs read-only
+	HandleStorageDownState(&m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.State != constants.StateTasks {
+		t.Fatalf("expected to return to the Tasks tab, got state %v", m.State)
+	}
+	if handled, _ := HandleReadOnlyKeys(&m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}); !handled {
+		t.Error("adding a task should be blocked while storage is down")
+	}
+	if handled, _ := HandleReadOnlyKeys(&m, tea.KeyMsg{Type: tea.KeyTab}); handled {
+		t.Error("switching tabs should still work while storage is down")
+	}
+
+	// Failed reconnection attempts back off
+	for i := 0; i < 3; i++ {
+		HandleHealthMessages(&m, state.HealthMsg{Err: store.Ping(), Loop: true})
+	}
+	if m.Health.Attempts != 3 || reconnectDelay(m.Health.Attempts) != 8*time.Second {
+		t.Errorf("expected 3 attempts and an 8s delay, got %d and %v", m.Health.Attempts, reconnectDelay(m.Health.Attempts))
+	}
+	if reconnectDelay(20) != constants.ReconnectMaxDelay {
+		t.Errorf("reconnectDelay(20) = %v, want %v", reconnectDelay(20), constants.ReconnectMaxDelay)
+	}
+
+	// The server comes back
+	store.pingErr = nil
+	HandleHealthMessages(&m, state.HealthMsg{Err: store.Ping(), Loop: true})
+	if m.Health.Down {
+		t.Error("expected the storage to be healthy again")
+	}
+	if handled, _ := HandleReadOnlyKeys(&m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}); handled {
+		t.Error("keys should no longer be blocked once storage is back")
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/inbox"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// HandleInboxMessages handles messages from the inbox component. Triaging
+// opens the matching form filled in with the item's text; the item leaves
+// the inbox once the form is saved.
+func HandleInboxMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
+	switch msg := msg.(type) {
+	case inbox.ToTaskMsg:
+		task := newTask()
+		task.Name = msg.Item.Text
+		m.TriageInboxID = msg.Item.ID
+		return true, openTaskForm(m, task)
+
+	case inbox.ToOTMsg:
+		var warning tea.Cmd
+		if existing := todaysOTEntry(m); existing.Title != "" {
+			warning = m.NotifyInfo("Saving replaces today's One Thing: " + existing.Title)
+		}
+		m.TriageInboxID = msg.Item.ID
+		return true, tea.Batch(openOTForm(m, models.OTEntry{Title: msg.Item.Text}), warning)
+
+	case inbox.ToAlertMsg:
+		m.TriageInboxID = msg.Item.ID
+		return true, openAlertForm(m, msg.Item.Text)
+
+	case inbox.DismissMsg:
+		if err := m.Store.DeleteInboxItem(msg.ID); err != nil {
+			return true, m.NotifyError("Failed to dismiss item", err)
+		}
+		refreshInbox(m)
+		return true, m.NotifySuccess("Item dismissed")
+	}
+	return false, nil
+}
+
+// leaveForm returns from a task, One Thing or alert form to tab, or to the
+// inbox when the form was opened to triage an item. A saved triage removes
+// the item from the inbox.
+func leaveForm(m *state.Model, tab constants.SessionState, saved bool) tea.Cmd {
+	id := m.TriageInboxID
+	if id == "" {
+		m.State = tab
+		return nil
+	}
+
+	m.TriageInboxID = ""
+	m.State = constants.StateInbox
+	if !saved {
+		return nil
+	}
+	if err := m.Store.DeleteInboxItem(id); err != nil {
+		return m.NotifyError("Failed to remove item from inbox", err)
+	}
+	refreshInbox(m)
+	return nil
+}
+
+func refreshInbox(m *state.Model) {
+	items, _ := m.Store.GetInboxItems()
+	m.InboxModel.SetItems(items)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/inbox"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+func TestInboxTriage(t *testing.T) {
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+
+	item := models.InboxItem{ID: "item-1", Text: "buy stamps", CreatedAt: time.Now()}
+	if err := store.AddInboxItem(item); err != nil {
+		t.Fatalf("failed to add inbox item: %v", err)
+	}
+
+	m := state.New(store, scheduler.New(), clock.System)
+	m.State = constants.StateInbox
+
+	HandleInboxMessages(&m, inbox.ToAlertMsg{Item: item})
+	if m.State != constants.StateAddAlert {
+		t.Fatalf("expected the alert form to open, got state %v", m.State)
+	}
+	if m.AlertForm.Message != item.Text {
+		t.Errorf("expected the alert message %q, got %q", item.Text, m.AlertForm.Message)
+	}
+
+	// Cancelling keeps the item
+	leaveForm(&m, constants.StateAlerts, false)
+	if m.State != constants.StateInbox {
+		t.Errorf("expected to return to the inbox, got state %v", m.State)
+	}
+	if items, _ := store.GetInboxItems(); len(items) != 1 {
+		t.Errorf("expected the item to stay in the inbox, got %d items", len(items))
+	}
+
+	HandleInboxMessages(&m, inbox.ToTaskMsg{Item: item})
+	if m.State != constants.StateEditing || m.TaskForm.Name != item.Text {
+		t.Fatalf("expected the task form to open for %q, got state %v and name %q", item.Text, m.State, m.TaskForm.Name)
+	}
+
+	// Saving removes it
+	leaveForm(&m, constants.StateTasks, true)
+	if m.State != constants.StateInbox {
+		t.Errorf("expected to return to the inbox, got state %v", m.State)
+	}
+	if items, _ := store.GetInboxItems(); len(items) != 0 {
+		t.Errorf("expected the item to leave the inbox, got %d items", len(items))
+	}
+	if m.InboxModel.Len() != 0 {
+		t.Errorf("expected the inbox tab to be empty, got %d items", m.InboxModel.Len())
+	}
+
+	// Forms opened from their own tabs return there
+	leaveForm(&m, constants.StateTasks, true)
+	if m.State != constants.StateTasks {
+		t.Errorf("expected to return to the tasks tab, got state %v", m.State)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
@@ -9,22 +10,37 @@ import (
 
 // HandleGlobalKeys handles global key presses
 func HandleGlobalKeys(m *state.Model, msg tea.KeyMsg) (bool, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c", "q":
+	switch {
+	case key.Matches(msg, m.Keys.Quit):
 		m.Quitting = true
 		return true, tea.Quit
-	case "tab", "l":
-		// Cycle through main views - all 7 tabs
+	case key.Matches(msg, m.Keys.Tab, m.Keys.Right):
+		// Cycle through main views
 		m.State = (m.State + 1) % constants.NumMainTabs
-		return true, nil
-	case "shift+tab", "h":
+		return true, onTabEnter(m)
+	case key.Matches(msg, m.Keys.ShiftTab, m.Keys.Left):
 		// Cycle backwards through main views
 		m.State = (m.State - 1 + constants.NumMainTabs) % constants.NumMainTabs
-		return true, nil
-	case "?":
+		return true, onTabEnter(m)
+	case key.Matches(msg, m.Keys.Search):
+		return true, openSearch(m)
+	case key.Matches(msg, m.Keys.Help):
 		// Toggle help
 		m.Help.ShowAll = !m.Help.ShowAll
 		return true, nil
 	}
 	return false, nil
 }
+
+// onTabEnter refreshes views whose data may have changed while another tab was active
+func onTabEnter(m *state.Model) tea.Cmd {
+	switch m.State {
+	case constants.StateCalendar:
+		return RefreshCalendar(m)
+	case constants.StateWeek:
+		return refreshWeek(m, m.WeekModel.Start())
+	case constants.StateTrash:
+		refreshTrash(m)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/plan"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// HandleEditNoteState handles the edit note state
+func HandleEditNoteState(m *state.Model, msg tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyEsc {
+		m.FormError = ""
+		m.State = constants.StatePlan
+		return nil
+	}
+
+	form, cmd := m.Form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.Form = f
+	}
+	cmds = append(cmds, cmd)
+
+	switch m.Form.State {
+	case huh.StateCompleted:
+		// Save on the latest revision so notes never land on an old one
+		dayPlan, err := m.Store.GetPlan(m.NoteForm.Date)
+		if err != nil {
+			m.FormError = fmt.Sprintf("Failed to load plan: %v", err)
+			m.Form.State = huh.StateNormal
+			return tea.Batch(cmds...)
+		}
+		dayPlan.Note = strings.TrimSpace(m.NoteForm.Note)
+		if err := m.Store.SavePlan(dayPlan); err != nil {
+			m.FormError = fmt.Sprintf("Failed to save notes: %v", err)
+			m.Form.State = huh.StateNormal
+			return tea.Batch(cmds...)
+		}
+
+		if saved, err := m.Store.GetPlan(dayPlan.Date); err == nil {
+			dayPlan = saved
+		}
+		tasks, _ := m.Store.GetAllTasksIncludingDeleted()
+		m.PlanModel.SetPlan(dayPlan, tasks)
+		m.FormError = ""
+		m.State = constants.StatePlan
+		cmds = append(cmds, m.NotifySuccess("Notes saved"))
+	case huh.StateAborted:
+		m.FormError = ""
+		m.State = constants.StatePlan
+	}
+	return tea.Batch(cmds...)
+}
+
+// HandlePlanMessages handles messages from the plan component
+func HandlePlanMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
+	switch msg := msg.(type) {
+	case plan.EditNoteMsg:
+		dayPlan, err := m.Store.GetPlan(msg.Date)
+		if err != nil {
+			return true, m.NotifyError("Failed to load plan", err)
+		}
+		m.FormError = ""
+		m.NoteForm = &state.NoteFormModel{Date: msg.Date, Note: dayPlan.Note}
+		m.Form = NewNoteForm(m.NoteForm)
+		m.State = constants.StateEditNote
+		return true, m.Form.Init()
+	}
+	return false, nil
+}
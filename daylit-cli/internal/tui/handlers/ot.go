@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
-	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
@@ -22,8 +21,7 @@ func HandleEditOTState(m *state.Model, msg tea.Msg) tea.Cmd {
 
 	if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyEsc {
 		m.FormError = "" // Clear error on cancel
-		m.State = constants.StateOT
-		return nil
+		return leaveForm(m, constants.StateOT, false)
 	}
 
 	form, cmd := m.Form.Update(msg)
@@ -35,7 +33,7 @@ func HandleEditOTState(m *state.Model, msg tea.Msg) tea.Cmd {
 	switch m.Form.State {
 	case huh.StateCompleted:
 		// Save or update OT entry
-		today := time.Now().Format(constants.DateFormat)
+		today := m.Now().Format(constants.DateFormat)
 
 		// Trim whitespace from title and note
 		title := strings.TrimSpace(m.OTForm.Title)
@@ -47,7 +45,7 @@ func HandleEditOTState(m *state.Model, msg tea.Msg) tea.Cmd {
 			// Update existing entry
 			existingEntry.Title = title
 			existingEntry.Note = note
-			existingEntry.UpdatedAt = time.Now()
+			existingEntry.UpdatedAt = m.Now()
 			if err := m.Store.UpdateOTEntry(existingEntry); err != nil {
 				// Store error and stay in form state to allow retry
 				m.FormError = fmt.Sprintf("Failed to update OT: %v", err)
@@ -69,8 +67,8 @@ func HandleEditOTState(m *state.Model, msg tea.Msg) tea.Cmd {
 				Day:       today,
 				Title:     title,
 				Note:      note,
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
+				CreatedAt: m.Now(),
+				UpdatedAt: m.Now(),
 			}
 			if err := m.Store.AddOTEntry(newEntry); err != nil {
 				// Store error and stay in form state to allow retry
@@ -88,10 +86,11 @@ func HandleEditOTState(m *state.Model, msg tea.Msg) tea.Cmd {
 			}
 		}
 		m.FormError = "" // Clear any previous errors
-		m.State = constants.StateOT
+		cmds = append(cmds, leaveForm(m, constants.StateOT, true))
+		cmds = append(cmds, m.NotifySuccess("One Thing saved"))
 	case huh.StateAborted:
 		m.FormError = "" // Clear error on abort
-		m.State = constants.StateOT
+		cmds = append(cmds, leaveForm(m, constants.StateOT, false))
 	}
 	return tea.Batch(cmds...)
 }
@@ -100,33 +99,43 @@ func HandleEditOTState(m *state.Model, msg tea.Msg) tea.Cmd {
 func HandleOTMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
 	switch msg.(type) {
 	case ot.EditOTMsg:
-		today := time.Now().Format(constants.DateFormat)
-		existingEntry, err := m.Store.GetOTEntry(today)
+		return true, openOTForm(m, todaysOTEntry(m))
+	}
+	return false, nil
+}
 
-		// Handle database errors differently from "not found"
-		if err != nil {
-			// Check if it's a "not found" error (sql.ErrNoRows)
-			if err == sql.ErrNoRows {
-				// Entry not found - initialize with empty values
-				existingEntry = models.OTEntry{}
-			} else {
-				// Actual database error - show error to user
-				m.FormError = fmt.Sprintf("Error loading OT: %v", err)
-				// Still allow editing with empty form
-				existingEntry = models.OTEntry{}
-			}
+// todaysOTEntry returns today's One Thing, or an empty entry when there is
+// none yet
+func todaysOTEntry(m *state.Model) models.OTEntry {
+	today := m.Now().Format(constants.DateFormat)
+	existingEntry, err := m.Store.GetOTEntry(today)
+
+	// Handle database errors differently from "not found"
+	if err != nil {
+		// Check if it's a "not found" error (sql.ErrNoRows)
+		if err == sql.ErrNoRows {
+			// Entry not found - initialize with empty values
+			existingEntry = models.OTEntry{}
 		} else {
-			// Clear any previous form errors only if no error occurred
-			m.FormError = ""
+			// Actual database error - show error to user
+			m.FormError = fmt.Sprintf("Error loading OT: %v", err)
+			// Still allow editing with empty form
+			existingEntry = models.OTEntry{}
 		}
+	} else {
+		// Clear any previous form errors only if no error occurred
+		m.FormError = ""
+	}
+	return existingEntry
+}
 
-		m.OTForm = &state.OTFormModel{
-			Title: existingEntry.Title,
-			Note:  existingEntry.Note,
-		}
-		m.Form = NewOTForm(m.OTForm)
-		m.State = constants.StateEditOT
-		return true, m.Form.Init()
+// openOTForm opens the One Thing form filled in from entry
+func openOTForm(m *state.Model, entry models.OTEntry) tea.Cmd {
+	m.OTForm = &state.OTFormModel{
+		Title: entry.Title,
+		Note:  entry.Note,
 	}
-	return false, nil
+	m.Form = NewOTForm(m.OTForm)
+	m.State = constants.StateEditOT
+	return m.Form.Init()
 }
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// HandleDayRollover reloads the views tied to today when the date changes
+// while the TUI is open, and offers to review the previous day if some of its
+// blocks have no feedback
+func HandleDayRollover(m *state.Model, now time.Time) tea.Cmd {
+	today := now.Format(constants.DateFormat)
+	if today == m.Today {
+		return nil
+	}
+	previous := m.Today
+	m.Today = today
+
+	// Plan and Now tabs
+	tasks, _ := m.Store.GetAllTasksIncludingDeleted()
+	if plan, err := m.Store.GetPlan(today); err == nil {
+		m.PlanModel.SetPlan(plan, tasks)
+		m.NowModel.SetPlan(plan, tasks)
+	} else {
+		m.PlanModel.ClearPlan(today)
+		m.NowModel.ClearPlan()
+	}
+	if prevPlan, err := m.Store.GetPlan(previous); err == nil {
+		m.NowModel.SetPrevious(&prevPlan, tasks)
+	} else {
+		m.NowModel.SetPrevious(nil, nil)
+	}
+
+	// Habit checklist and OT
+	refreshHabits(m)
+	if entry, err := m.Store.GetOTEntry(today); err == nil && entry.ID != "" {
+		m.OTModel.SetEntry(&entry)
+	} else {
+		m.OTModel.SetEntry(nil)
+	}
+
+	cmds := []tea.Cmd{m.Validate(), RefreshCalendar(m), m.NotifyInfo("New day: " + today)}
+
+	// Don't interrupt a form or another dialog
+	if isTabState(m.State) && pendingFeedback(m, previous) > 0 {
+		m.PlanToReviewDate = previous
+		m.PreviousState = m.State
+		m.State = constants.StateConfirmReview
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// HandleConfirmReviewState handles the prompt to review the previous day
+func HandleConfirmReviewState(m *state.Model, msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "y", "Y":
+			openPlanForDate(m, m.PlanToReviewDate)
+			m.PlanToReviewDate = ""
+		case "n", "N", "esc":
+			m.PlanToReviewDate = ""
+			m.State = m.PreviousState
+		}
+	}
+	return nil
+}
+
+// pendingFeedback counts the accepted or done blocks of a day's plan that
+// have no feedback
+func pendingFeedback(m *state.Model, date string) int {
+	if date == "" {
+		return 0
+	}
+	plan, err := m.Store.GetPlan(date)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, slot := range plan.Slots {
+		if (slot.Status == constants.SlotStatusAccepted || slot.Status == constants.SlotStatusDone) &&
+			slot.Feedback == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// isTabState reports whether state is one of the main tabs rather than a
+// form or dialog
+func isTabState(s constants.SessionState) bool {
+	switch s {
+	case constants.StateNow, constants.StatePlan, constants.StateCalendar, constants.StateWeek,
+		constants.StateTasks, constants.StateHabits, constants.StateOT, constants.StateAlerts,
+		constants.StateInbox, constants.StateTrash, constants.StateSettings:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+func TestHandleDayRollover(t *testing.T) {
+	store := sqlite.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+
+	task := models.Task{
+		ID:          "task-rollover",
+		Name:        "Review",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence:  models.Recurrence{Type: constants.RecurrenceDaily},
+		Priority:    1,
+		Active:      true,
+	}
+	if err := store.AddTask(task); err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+
+	now := time.Date(2025, 3, 11, 0, 5, 0, 0, time.Local)
+	today := now.Format(constants.DateFormat)
+	yesterday := now.AddDate(0, 0, -1).Format(constants.DateFormat)
+	acceptedAt := now.UTC().Format(time.RFC3339)
+	for _, date := range []string{yesterday, today} {
+		plan := models.DayPlan{
+			Date:       date,
+			AcceptedAt: &acceptedAt,
+			Slots: []models.Slot{
+				{Start: "09:00", End: "09:30", TaskID: task.ID, Status: constants.SlotStatusAccepted},
+			},
+		}
+		if err := store.SavePlan(plan); err != nil {
+			t.Fatalf("failed to save plan for %s: %v", date, err)
+		}
+	}
+
+	// The TUI was opened yesterday evening and is still open after midnight
+	m := state.New(store, scheduler.New(), clock.Fixed(now.Add(-time.Hour)))
+	if m.Today != yesterday {
+		t.Fatalf("Today = %q, want %q", m.Today, yesterday)
+	}
+	m.SetClock(clock.Fixed(now))
+
+	HandleDayRollover(&m, now)
+
+	if m.Today != today {
+		t.Errorf("Today = %q, want %q", m.Today, today)
+	}
+	if m.NowModel.Plan == nil || m.NowModel.Plan.Date != today {
+		t.Error("Now tab should show today's plan after the rollover")
+	}
+	if m.State != constants.StateConfirmReview || m.PlanToReviewDate != yesterday {
+		t.Fatalf("expected review prompt for %s, got state %v and date %q", yesterday, m.State, m.PlanToReviewDate)
+	}
+
+	HandleConfirmReviewState(&m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if m.State != constants.StatePlan || m.PlanModel.Date != yesterday {
+		t.Errorf("accepting the review should open %s in the Plan tab, got state %v and date %q", yesterday, m.State, m.PlanModel.Date)
+	}
+
+	// Further ticks on the same day change nothing
+	m.State = constants.StateNow
+	if cmd := HandleDayRollover(&m, now); cmd != nil || m.State != constants.StateNow {
+		t.Error("rollover should only run once per day")
+	}
+}
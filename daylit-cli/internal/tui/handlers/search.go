@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/search"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// searchLimit caps the number of results shown in the search overlay
+const searchLimit = 50
+
+// openSearch shows the search overlay over the current tab
+func openSearch(m *state.Model) tea.Cmd {
+	m.PreviousState = m.State
+	m.State = constants.StateSearch
+	return m.SearchModel.Open()
+}
+
+// HandleSearchState handles input and results while the search overlay is open
+func HandleSearchState(m *state.Model, msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case search.QueryMsg:
+		results, err := m.Store.Search(msg.Query, searchLimit)
+		if err != nil {
+			return m.NotifyError("Search failed", err)
+		}
+		m.SearchModel.SetResults(msg.Query, results)
+		return nil
+
+	case search.CloseMsg:
+		m.State = m.PreviousState
+		return nil
+
+	case search.SelectResultMsg:
+		return openSearchResult(m, msg.Result)
+	}
+
+	var cmd tea.Cmd
+	m.SearchModel, cmd = m.SearchModel.Update(msg)
+	return cmd
+}
+
+// openSearchResult switches to the view that shows the result: the Tasks or
+// Habits tab for names, today's OT tab, or the Plan tab for the result's date
+func openSearchResult(m *state.Model, r models.SearchResult) tea.Cmd {
+	today := m.Now().Format(constants.DateFormat)
+
+	switch {
+	case r.Kind == constants.SearchKindTask:
+		m.TaskList.SelectTask(r.ID)
+		m.State = constants.StateTasks
+	case r.Kind == constants.SearchKindHabit:
+		m.HabitsModel.SelectHabit(r.ID)
+		m.State = constants.StateHabits
+	case r.Kind == constants.SearchKindOT && r.Date == today:
+		m.State = constants.StateOT
+	default:
+		openPlanForDate(m, r.Date)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -30,15 +31,27 @@ func HandleEditSettingsState(m *state.Model, msg tea.Msg) tea.Cmd {
 
 	switch m.Form.State {
 	case huh.StateCompleted:
-		// Save general settings
-		newSettings := models.Settings{
-			DayStart:             m.SettingsForm.DayStart,
-			DayEnd:               m.SettingsForm.DayEnd,
-			Timezone:             m.SettingsForm.Timezone,
-			NotificationsEnabled: m.SettingsForm.NotificationsEnabled,
-			NotifyBlockStart:     m.SettingsForm.NotifyBlockStart,
-			NotifyBlockEnd:       m.SettingsForm.NotifyBlockEnd,
+		// Save general settings, starting from the values the form was opened
+		// with so fields without a form input are preserved
+		base := m.EditingSettings
+		if base == nil {
+			loaded, err := m.Store.GetSettings()
+			if err != nil {
+				m.FormError = "Failed to load settings: " + err.Error()
+				m.Form.State = huh.StateNormal
+				return tea.Batch(cmds...)
+			}
+			base = &loaded
 		}
+		newSettings := *base
+		newSettings.DayStart = m.SettingsForm.DayStart
+		newSettings.DayEnd = m.SettingsForm.DayEnd
+		newSettings.Timezone = m.SettingsForm.Timezone
+		newSettings.Theme = m.SettingsForm.Theme
+		newSettings.WeekStart = m.SettingsForm.WeekStart
+		newSettings.NotificationsEnabled = m.SettingsForm.NotificationsEnabled
+		newSettings.NotifyBlockStart = m.SettingsForm.NotifyBlockStart
+		newSettings.NotifyBlockEnd = m.SettingsForm.NotifyBlockEnd
 
 		if val, err := strconv.Atoi(m.SettingsForm.DefaultBlockMin); err == nil {
 			newSettings.DefaultBlockMin = val
@@ -50,7 +63,13 @@ func HandleEditSettingsState(m *state.Model, msg tea.Msg) tea.Cmd {
 			newSettings.BlockEndOffsetMin = val
 		}
 
-		if err := m.Store.SaveSettings(newSettings); err != nil {
+		// Someone else may have saved the settings while the form was open
+		var conflict *state.SaveConflict
+		err := m.Store.SaveSettings(newSettings)
+		if errors.Is(err, models.ErrConflict) {
+			conflict, err = newSettingsConflict(m.Store, *base, newSettings)
+		}
+		if err != nil {
 			// Store error and stay in form state to allow retry
 			m.FormError = "Failed to update settings: " + err.Error()
 			m.Form.State = huh.StateNormal
@@ -74,11 +93,24 @@ func HandleEditSettingsState(m *state.Model, msg tea.Msg) tea.Cmd {
 			return tea.Batch(cmds...)
 		}
 
+		m.FormError = "" // Clear any previous errors
+		if conflict != nil {
+			return tea.Batch(append(cmds, handleSaveConflict(m, conflict))...)
+		}
+
+		if window, err := models.ParseDayWindow(newSettings.DayStart, newSettings.DayEnd); err == nil {
+			m.NowModel.SetWindow(window)
+		}
+
+		// Apply the theme before refreshing views so they render with the new colors
+		m.ApplyTheme(newSettings.Theme)
+		m.ApplyWeekStart(newSettings.FirstWeekday())
+
 		// Refresh settings view
 		m.SettingsModel.SetSettings(newSettings, otSettings)
 
-		m.FormError = "" // Clear any previous errors
 		m.State = constants.StateSettings
+		cmds = append(cmds, m.NotifySuccess("Settings saved"), refreshWeek(m, m.WeekModel.Start()))
 	case huh.StateAborted:
 		m.FormError = "" // Clear error on abort
 		m.State = constants.StateSettings
@@ -104,9 +136,13 @@ func HandleSettingsMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
 				BlockStartOffsetMin:  5,
 				BlockEndOffsetMin:    0,
 				Timezone:             "Local",
+				Theme:                constants.DefaultTheme,
+				WeekStart:            constants.DefaultWeekStart,
 			}
+			m.EditingSettings = nil
 		} else {
 			m.FormError = ""
+			m.EditingSettings = &currentSettings
 		}
 
 		// Load OT settings
@@ -125,6 +161,8 @@ func HandleSettingsMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
 			DayEnd:               currentSettings.DayEnd,
 			DefaultBlockMin:      strconv.Itoa(currentSettings.DefaultBlockMin),
 			Timezone:             currentSettings.Timezone,
+			Theme:                currentSettings.Theme,
+			WeekStart:            currentSettings.WeekStart,
 			PromptOnEmpty:        currentOTSettings.PromptOnEmpty,
 			StrictMode:           currentOTSettings.StrictMode,
 			DefaultLogDays:       strconv.Itoa(currentOTSettings.DefaultLogDays),
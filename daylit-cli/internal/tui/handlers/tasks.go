@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -19,8 +21,8 @@ func HandleEditingState(m *state.Model, msg tea.Msg) tea.Cmd {
 	var cmds []tea.Cmd
 
 	if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyEsc {
-		m.State = constants.StateTasks
-		return nil
+		m.FormError = ""
+		return leaveForm(m, constants.StateTasks, false)
 	}
 
 	form, cmd := m.Form.Update(msg)
@@ -48,11 +50,23 @@ func HandleEditingState(m *state.Model, msg tea.Msg) tea.Cmd {
 			m.EditingTask.Priority = prio
 		}
 		m.EditingTask.Active = m.TaskForm.Active
+		m.EditingTask.Recurrence.Until = strings.TrimSpace(m.TaskForm.Until)
+		// A new count starts over from today
+		count, _ := strconv.Atoi(strings.TrimSpace(m.TaskForm.Count))
+		if count != m.EditingTask.Recurrence.Count {
+			m.EditingTask.Recurrence.Count = count
+			m.EditingTask.Recurrence.Start = ""
+			if count > 0 {
+				m.EditingTask.Recurrence.Start = m.Now().Format(constants.DateFormat)
+			}
+		}
 
 		// Check if task exists to decide Add vs Update
 		_, err = m.Store.GetTask(m.EditingTask.ID)
 		var saveErr error
-		if err != nil {
+		if boundsErr := m.EditingTask.Recurrence.ValidateBounds(); boundsErr != nil {
+			saveErr = boundsErr
+		} else if err != nil {
 			// Task doesn't exist, add it
 			saveErr = m.Store.AddTask(*m.EditingTask)
 		} else {
@@ -60,17 +74,35 @@ func HandleEditingState(m *state.Model, msg tea.Msg) tea.Cmd {
 			saveErr = m.Store.UpdateTask(*m.EditingTask)
 		}
 
-		// Only update task list if save was successful
-		if saveErr == nil {
-			tasks, err := m.Store.GetAllTasksIncludingDeleted()
+		// Someone else saved the task while the form was open
+		if errors.Is(saveErr, models.ErrConflict) {
+			conflict, err := newTaskConflict(m.Store, m.EditingTaskBase, *m.EditingTask)
 			if err == nil {
-				m.TaskList.SetTasks(tasks)
+				m.FormError = ""
+				return tea.Batch(append(cmds, handleSaveConflict(m, conflict))...)
 			}
-			m.UpdateValidationStatus()
+			saveErr = err
 		}
-		m.State = constants.StateTasks
+
+		if saveErr != nil {
+			// Store error and stay in form state to allow retry
+			m.FormError = fmt.Sprintf("Failed to save task: %v", saveErr)
+			m.Form.State = huh.StateNormal
+			return tea.Batch(cmds...)
+		}
+
+		tasks, err := m.Store.GetAllTasksIncludingDeleted()
+		if err == nil {
+			m.TaskList.SetTasks(tasks)
+		} else {
+			cmds = append(cmds, m.NotifyError("Failed to reload tasks", err))
+		}
+		m.FormError = ""
+		cmds = append(cmds, m.Validate(), leaveForm(m, constants.StateTasks, true))
+		cmds = append(cmds, m.NotifySuccess("Task saved: "+m.EditingTask.Name))
 	case huh.StateAborted:
-		m.State = constants.StateTasks
+		m.FormError = ""
+		cmds = append(cmds, leaveForm(m, constants.StateTasks, false))
 	}
 	return tea.Batch(cmds...)
 }
@@ -89,43 +121,47 @@ func HandleTaskMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
 		return true, nil
 
 	case tasklist.AddTaskMsg:
-		task := models.Task{
-			ID:          uuid.New().String(),
-			Name:        "New Task",
-			Kind:        constants.TaskKindFlexible,
-			DurationMin: 30,
-			Recurrence: models.Recurrence{
-				Type: constants.RecurrenceAdHoc,
-			},
-			Priority: 3,
-			Active:   true,
-		}
-		m.EditingTask = &task
-		m.TaskForm = &state.TaskFormModel{
-			Name:       task.Name,
-			Duration:   strconv.Itoa(task.DurationMin),
-			Recurrence: task.Recurrence.Type,
-			Interval:   strconv.Itoa(task.Recurrence.IntervalDays),
-			Priority:   strconv.Itoa(task.Priority),
-			Active:     task.Active,
-		}
-		m.Form = NewEditForm(m.TaskForm)
-		m.State = constants.StateEditing
-		return true, m.Form.Init()
+		return true, openTaskForm(m, newTask())
 
 	case tasklist.EditTaskMsg:
-		m.EditingTask = &msg.Task
-		m.TaskForm = &state.TaskFormModel{
-			Name:       msg.Task.Name,
-			Duration:   strconv.Itoa(msg.Task.DurationMin),
-			Recurrence: msg.Task.Recurrence.Type,
-			Interval:   strconv.Itoa(msg.Task.Recurrence.IntervalDays),
-			Priority:   strconv.Itoa(msg.Task.Priority),
-			Active:     msg.Task.Active,
-		}
-		m.Form = NewEditForm(m.TaskForm)
-		m.State = constants.StateEditing
-		return true, m.Form.Init()
+		return true, openTaskForm(m, msg.Task)
 	}
 	return false, nil
 }
+
+// newTask returns the defaults for a task added from the TUI
+func newTask() models.Task {
+	return models.Task{
+		ID:          uuid.New().String(),
+		Name:        "New Task",
+		Kind:        constants.TaskKindFlexible,
+		DurationMin: 30,
+		Recurrence: models.Recurrence{
+			Type: constants.RecurrenceAdHoc,
+		},
+		Priority: 3,
+		Active:   true,
+	}
+}
+
+// openTaskForm opens the task form on task, which is added on save if it
+// isn't stored yet
+func openTaskForm(m *state.Model, task models.Task) tea.Cmd {
+	m.EditingTask = &task
+	m.EditingTaskBase = task
+	m.TaskForm = &state.TaskFormModel{
+		Name:       task.Name,
+		Duration:   strconv.Itoa(task.DurationMin),
+		Recurrence: task.Recurrence.Type,
+		Interval:   strconv.Itoa(task.Recurrence.IntervalDays),
+		Until:      task.Recurrence.Until,
+		Priority:   strconv.Itoa(task.Priority),
+		Active:     task.Active,
+	}
+	if task.Recurrence.Count > 0 {
+		m.TaskForm.Count = strconv.Itoa(task.Recurrence.Count)
+	}
+	m.Form = NewEditForm(m.TaskForm)
+	m.State = constants.StateEditing
+	return m.Form.Init()
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/trash"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// HandleTrashMessages handles messages from the trash component. Restoring
+// brings the item back in every view; purging asks for confirmation first.
+func HandleTrashMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
+	switch msg := msg.(type) {
+	case trash.RestoreMsg:
+		if err := storage.RestoreTrashItem(m.Store, msg.Item.Kind, msg.Item.ID); err != nil {
+			return true, m.NotifyError("Failed to restore "+string(msg.Item.Kind), err)
+		}
+		return true, tea.Batch(RefreshAll(m), m.NotifySuccess("Restored "+msg.Item.Label))
+
+	case trash.PurgeMsg:
+		item := msg.Item
+		m.TrashToPurge = &item
+		m.State = constants.StateConfirmPurge
+		return true, nil
+	}
+	return false, nil
+}
+
+// HandleConfirmPurgeState handles the purge confirmation state
+func HandleConfirmPurgeState(m *state.Model, msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "y", "Y":
+			if item := m.TrashToPurge; item != nil {
+				if err := m.Store.PurgeItem(item.Kind, item.ID); err == nil {
+					refreshTrash(m)
+					cmd = m.NotifySuccess("Purged " + item.Label)
+				} else {
+					cmd = m.NotifyError("Failed to purge "+string(item.Kind), err)
+				}
+				m.TrashToPurge = nil
+			}
+			m.State = constants.StateTrash
+		case "n", "N", "esc":
+			m.TrashToPurge = nil
+			m.State = constants.StateTrash
+		}
+	}
+	return cmd
+}
+
+func refreshTrash(m *state.Model) {
+	items, _ := storage.ListTrash(m.Store)
+	m.TrashModel.SetItems(items)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/trash"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+func TestTrashRestoreAndPurge(t *testing.T) {
+	store := storage.NewMemoryStore()
+	if err := store.Init(); err != nil {
+		t.Fatalf("failed to init store: %v", err)
+	}
+	defer store.Close()
+
+	now := time.Now()
+	for _, h := range []models.Habit{{ID: "habit-1", Name: "Stretch", CreatedAt: now}, {ID: "habit-2", Name: "Read", CreatedAt: now}} {
+		if err := store.AddHabit(h); err != nil {
+			t.Fatalf("failed to add habit: %v", err)
+		}
+		if err := store.DeleteHabit(h.ID); err != nil {
+			t.Fatalf("failed to delete habit: %v", err)
+		}
+	}
+
+	m := state.New(store, scheduler.New(), clock.System)
+	m.State = constants.StateTrash
+	if m.TrashModel.Len() != 2 {
+		t.Fatalf("expected both habits in the trash, got %d", m.TrashModel.Len())
+	}
+
+	HandleTrashMessages(&m, trash.RestoreMsg{Item: models.TrashItem{Kind: models.TrashHabit, ID: "habit-1", Label: "Stretch"}})
+	if habits, _ := store.GetAllHabits(false, false); len(habits) != 1 || habits[0].ID != "habit-1" {
+		t.Errorf("expected habit-1 to be restored, got %+v", habits)
+	}
+	if m.TrashModel.Len() != 1 {
+		t.Errorf("expected one item left in the trash, got %d", m.TrashModel.Len())
+	}
+
+	purge := models.TrashItem{Kind: models.TrashHabit, ID: "habit-2", Label: "Read"}
+	HandleTrashMessages(&m, trash.PurgeMsg{Item: purge})
+	if m.State != constants.StateConfirmPurge {
+		t.Fatalf("expected the purge to ask for confirmation, got state %v", m.State)
+	}
+
+	// Declining keeps the item
+	HandleConfirmPurgeState(&m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.State != constants.StateTrash || m.TrashModel.Len() != 1 {
+		t.Fatalf("expected to return to the trash with the item kept, got state %v and %d items", m.State, m.TrashModel.Len())
+	}
+
+	HandleTrashMessages(&m, trash.PurgeMsg{Item: purge})
+	HandleConfirmPurgeState(&m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if m.State != constants.StateTrash || m.TrashModel.Len() != 0 {
+		t.Errorf("expected an empty trash after the purge, got state %v and %d items", m.State, m.TrashModel.Len())
+	}
+	if habits, _ := store.GetAllHabits(true, true); len(habits) != 1 {
+		t.Errorf("expected the purged habit to be gone for good, got %+v", habits)
+	}
+}
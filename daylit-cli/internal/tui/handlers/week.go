@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/week"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
+)
+
+// HandleWeekMessages handles messages from the week agenda component
+func HandleWeekMessages(m *state.Model, msg tea.Msg) (bool, tea.Cmd) {
+	switch msg := msg.(type) {
+	case week.WeekChangedMsg:
+		return true, refreshWeek(m, msg.Start)
+
+	case week.SelectDayMsg:
+		openPlanForDate(m, msg.Date)
+		return true, nil
+
+	case week.GenerateWeekMsg:
+		return true, generateWeek(m, msg.Dates)
+	}
+	return false, nil
+}
@@ -4,37 +4,76 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/handlers"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/state"
 )
 
 // Model wraps the state.Model and adds TUI-specific methods
 type Model struct {
 	state.Model
+
+	initCmd      tea.Cmd              // command from the startup checks, run by Init
+	changes      <-chan models.Change // changes made outside this TUI, or nil
+	stopWatching func()
 }
 
-// NewModel creates a new TUI Model
-func NewModel(store storage.Provider, sched *scheduler.Scheduler) Model {
+// NewModel creates a new TUI Model that tells the time by clk, or by the
+// system clock if clk is nil
+func NewModel(store storage.Provider, sched *scheduler.Scheduler, clk clock.Clock) Model {
 	m := Model{
-		Model: state.New(store, sched),
+		Model: state.New(store, sched, clk),
 	}
 
-	// Run validation on initialization
-	m.UpdateValidationStatus()
+	// Validate in the background, and offer or generate today's plan,
+	// depending on the morning plan setting
+	m.initCmd = tea.Batch(m.Validate(), handlers.CheckMorningPlan(&m.Model))
+
+	// Refresh the views when another process or machine edits the data
+	if watcher, ok := storage.Unwrap(store).(storage.ChangeWatcher); ok {
+		changes, stop, err := watcher.WatchChanges()
+		if err != nil {
+			logger.Warn("Failed to watch for database changes", "error", err)
+		} else {
+			m.changes = changes
+			m.stopWatching = stop
+		}
+	}
 
 	return m
 }
 
+// Close stops watching for database changes
+func (m Model) Close() {
+	if m.stopWatching != nil {
+		m.stopWatching()
+	}
+}
+
 // ShortHelp returns the short help key bindings
 func (m Model) ShortHelp() []key.Binding {
-	keys := []key.Binding{m.Keys.Tab, m.Keys.Quit, m.Keys.Help}
+	if m.State == constants.StateSearch {
+		searchKeys := m.SearchModel.Keys()
+		return []key.Binding{searchKeys.Up, searchKeys.Down, searchKeys.Select, searchKeys.Close}
+	}
+
+	keys := []key.Binding{m.Keys.Tab, m.Keys.Search, m.Keys.Quit, m.Keys.Help}
 	switch m.State {
 	case constants.StateTasks:
 		keys = append(keys, m.Keys.Add, m.Keys.Edit, m.Keys.Delete)
 	case constants.StatePlan:
-		keys = append(keys, m.Keys.Generate)
+		keys = append(keys, m.Keys.Generate, m.PlanModel.Keys().Note, m.PlanModel.Keys().Actuals)
+	case constants.StateCalendar:
+		calKeys := m.CalendarModel.Keys()
+		keys = append(keys, calKeys.Select, calKeys.PrevMonth, calKeys.NextMonth)
+	case constants.StateWeek:
+		weekKeys := m.WeekModel.Keys()
+		keys = append(keys, weekKeys.Select, weekKeys.Generate, weekKeys.PrevWeek, weekKeys.NextWeek)
 	case constants.StateHabits:
 		keys = append(keys, m.Keys.Add)
 	}
@@ -44,7 +83,11 @@ func (m Model) ShortHelp() []key.Binding {
 
 // FullHelp returns the full help key bindings
 func (m Model) FullHelp() [][]key.Binding {
-	global := []key.Binding{m.Keys.Tab, m.Keys.ShiftTab, m.Keys.Quit, m.Keys.Help, m.Keys.Feedback}
+	if m.State == constants.StateSearch {
+		return [][]key.Binding{m.ShortHelp()}
+	}
+
+	global := []key.Binding{m.Keys.Tab, m.Keys.ShiftTab, m.Keys.Search, m.Keys.Quit, m.Keys.Help, m.Keys.Feedback}
 	navigation := []key.Binding{m.Keys.Up, m.Keys.Down, m.Keys.Left, m.Keys.Right, m.Keys.Enter}
 
 	var actions []key.Binding
@@ -52,7 +95,13 @@ func (m Model) FullHelp() [][]key.Binding {
 	case constants.StateTasks:
 		actions = []key.Binding{m.Keys.Add, m.Keys.Edit, m.Keys.Delete}
 	case constants.StatePlan:
-		actions = []key.Binding{m.Keys.Generate}
+		actions = []key.Binding{m.Keys.Generate, m.PlanModel.Keys().Note, m.PlanModel.Keys().Actuals}
+	case constants.StateCalendar:
+		calKeys := m.CalendarModel.Keys()
+		actions = []key.Binding{calKeys.Left, calKeys.Right, calKeys.Up, calKeys.Down, calKeys.PrevMonth, calKeys.NextMonth, calKeys.Today, calKeys.Select}
+	case constants.StateWeek:
+		weekKeys := m.WeekModel.Keys()
+		actions = []key.Binding{weekKeys.Left, weekKeys.Right, weekKeys.PrevWeek, weekKeys.NextWeek, weekKeys.Today, weekKeys.Select, weekKeys.Generate}
 	case constants.StateHabits:
 		actions = []key.Binding{m.Keys.Add}
 	}
@@ -62,5 +111,5 @@ func (m Model) FullHelp() [][]key.Binding {
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
-	return m.NowModel.Init()
+	return tea.Batch(m.NowModel.Init(), m.initCmd, handlers.WaitForChange(m.changes), handlers.StartHealthChecks(&m.Model))
 }
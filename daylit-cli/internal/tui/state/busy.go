@@ -0,0 +1,39 @@
+package state
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Labels of the background operations shown next to the spinner
+const (
+	BusyGenerating = "Generating plan"
+	BusyValidating = "Validating"
+	BusyLoading    = "Loading"
+)
+
+// StartBusy records that a background operation is running and returns the
+// command that starts the spinner
+func (m *Model) StartBusy(label string) tea.Cmd {
+	m.Busy = append(m.Busy, label)
+	return m.Spinner.Tick
+}
+
+// EndBusy records that a background operation started with label finished
+func (m *Model) EndBusy(label string) {
+	for i, l := range m.Busy {
+		if l == label {
+			m.Busy = append(m.Busy[:i], m.Busy[i+1:]...)
+			return
+		}
+	}
+}
+
+// IsBusy reports whether a background operation with label is running
+func (m *Model) IsBusy(label string) bool {
+	for _, l := range m.Busy {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
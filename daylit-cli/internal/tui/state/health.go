@@ -0,0 +1,43 @@
+package state
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+// StoreHealth tracks whether the storage backend can be reached
+type StoreHealth struct {
+	Down        bool
+	Err         error                  // Why the last check failed
+	Since       time.Time              // When the backend became unreachable
+	Attempts    int                    // Reconnection attempts since then
+	ReturnState constants.SessionState // Where to go when the outage notice is closed
+}
+
+// HealthMsg carries the result of a storage health check
+type HealthMsg struct {
+	Err  error
+	Loop bool // Sent by the periodic check, which schedules the next one
+}
+
+// CheckHealth checks the storage backend in the background. It returns nil
+// for backends that can't become unreachable, such as SQLite.
+func (m *Model) CheckHealth() tea.Cmd {
+	return m.PingStore(false)
+}
+
+// PingStore pings the storage backend in the background, reporting the
+// result as a HealthMsg
+func (m *Model) PingStore(loop bool) tea.Cmd {
+	checker, ok := storage.Unwrap(m.Store).(storage.HealthChecker)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		return HealthMsg{Err: checker.Ping(), Loop: loop}
+	}
+}
@@ -1,6 +1,21 @@
 package state
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/i18n"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/alerts"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/calendar"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/habits"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/inbox"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/search"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/tasklist"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/trash"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/week"
+)
 
 // KeyMap defines the key bindings for the TUI
 type KeyMap struct {
@@ -18,79 +33,174 @@ type KeyMap struct {
 	Add      key.Binding
 	Edit     key.Binding
 	Delete   key.Binding
+	Search   key.Binding
 }
 
 // ShortHelp returns the short help key bindings
 func (k KeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Tab, k.Quit, k.Help}
+	return []key.Binding{k.Tab, k.Search, k.Quit, k.Help}
 }
 
 // FullHelp returns the full help key bindings
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Tab, k.ShiftTab, k.Quit},
+		{k.Tab, k.ShiftTab, k.Search, k.Quit},
 		{k.Up, k.Down, k.Left, k.Right, k.Enter, k.Help, k.Generate, k.Feedback, k.Add, k.Edit, k.Delete},
 	}
 }
 
+// KeyAction describes a remappable TUI action and its default keys. Its help
+// text is the catalog message "key.<name>".
+type KeyAction struct {
+	Name    string
+	Keys    []string
+	HelpKey string
+	binding func(*KeyMap) *key.Binding
+}
+
+// KeyActions lists every remappable action in the order shown by `daylit keys list`
+var KeyActions = []KeyAction{
+	{"tab", []string{"tab"}, "tab", func(k *KeyMap) *key.Binding { return &k.Tab }},
+	{"shift_tab", []string{"shift+tab"}, "shift+tab", func(k *KeyMap) *key.Binding { return &k.ShiftTab }},
+	{"left", []string{"h"}, "h", func(k *KeyMap) *key.Binding { return &k.Left }},
+	{"right", []string{"l"}, "l", func(k *KeyMap) *key.Binding { return &k.Right }},
+	{"quit", []string{"q", "ctrl+c"}, "q", func(k *KeyMap) *key.Binding { return &k.Quit }},
+	{"up", []string{"up", "k"}, "↑/k", func(k *KeyMap) *key.Binding { return &k.Up }},
+	{"down", []string{"down", "j"}, "↓/j", func(k *KeyMap) *key.Binding { return &k.Down }},
+	{"enter", []string{"enter"}, "enter", func(k *KeyMap) *key.Binding { return &k.Enter }},
+	{"help", []string{"?"}, "?", func(k *KeyMap) *key.Binding { return &k.Help }},
+	{"generate", []string{"g"}, "g", func(k *KeyMap) *key.Binding { return &k.Generate }},
+	{"feedback", []string{"f"}, "f", func(k *KeyMap) *key.Binding { return &k.Feedback }},
+	{"add", []string{"a"}, "a", func(k *KeyMap) *key.Binding { return &k.Add }},
+	{"edit", []string{"e"}, "e", func(k *KeyMap) *key.Binding { return &k.Edit }},
+	{"delete", []string{"d"}, "d", func(k *KeyMap) *key.Binding { return &k.Delete }},
+	{"search", []string{"/"}, "/", func(k *KeyMap) *key.Binding { return &k.Search }},
+}
+
+// LookupKeyAction returns the remappable action with the given name
+func LookupKeyAction(name string) (KeyAction, bool) {
+	for _, a := range KeyActions {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return KeyAction{}, false
+}
+
+// ParseKeys splits a comma-separated key list such as "q,ctrl+c"
+func ParseKeys(value string) []string {
+	var keys []string
+	for _, k := range strings.Split(value, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
 // DefaultKeyMap returns the default key bindings
 func DefaultKeyMap() KeyMap {
-	return KeyMap{
-		Tab: key.NewBinding(
-			key.WithKeys("tab"),
-			key.WithHelp("tab", "next tab"),
-		),
-		ShiftTab: key.NewBinding(
-			key.WithKeys("shift+tab"),
-			key.WithHelp("shift+tab", "prev tab"),
-		),
-		Quit: key.NewBinding(
-			key.WithKeys("q", "ctrl+c"),
-			key.WithHelp("q", "quit"),
-		),
-		Up: key.NewBinding(
-			key.WithKeys("up", "k"),
-			key.WithHelp("↑/k", "up"),
-		),
-		Down: key.NewBinding(
-			key.WithKeys("down", "j"),
-			key.WithHelp("↓/j", "down"),
-		),
-		Left: key.NewBinding(
-			key.WithKeys("h"),
-			key.WithHelp("h", "prev tab"),
-		),
-		Right: key.NewBinding(
-			key.WithKeys("l"),
-			key.WithHelp("l", "next tab"),
-		),
-		Enter: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "select"),
-		),
-		Help: key.NewBinding(
-			key.WithKeys("?"),
-			key.WithHelp("?", "toggle help"),
-		),
-		Generate: key.NewBinding(
-			key.WithKeys("g"),
-			key.WithHelp("g", "generate plan"),
-		),
-		Feedback: key.NewBinding(
-			key.WithKeys("f"),
-			key.WithHelp("f", "feedback"),
-		),
-		Add: key.NewBinding(
-			key.WithKeys("a"),
-			key.WithHelp("a", "add task"),
-		),
-		Edit: key.NewBinding(
-			key.WithKeys("e"),
-			key.WithHelp("e", "edit task"),
-		),
-		Delete: key.NewBinding(
-			key.WithKeys("d"),
-			key.WithHelp("d", "delete task"),
-		),
+	km, _ := NewKeyMap(nil)
+	return km
+}
+
+// NewKeyMap builds the key bindings from the defaults and the given overrides,
+// keyed by action name with comma-separated keys as values. Empty overrides
+// keep the default keys. It returns an error for unknown actions and for keys
+// bound to more than one action.
+func NewKeyMap(overrides map[string]string) (KeyMap, error) {
+	for name := range overrides {
+		if _, ok := LookupKeyAction(name); !ok {
+			return KeyMap{}, fmt.Errorf("unknown key action: %s", name)
+		}
 	}
+
+	var km KeyMap
+	owners := make(map[string]string)
+	for _, a := range KeyActions {
+		keys := a.Keys
+		helpKey := a.HelpKey
+		if custom := ParseKeys(overrides[a.Name]); len(custom) > 0 {
+			keys = custom
+			helpKey = strings.Join(custom, "/")
+		}
+
+		for _, k := range keys {
+			if owner, ok := owners[k]; ok {
+				return KeyMap{}, fmt.Errorf("key %q is bound to both %s and %s", k, owner, a.Name)
+			}
+			owners[k] = a.Name
+		}
+
+		*a.binding(&km) = key.NewBinding(
+			key.WithKeys(keys...),
+			key.WithHelp(helpKey, i18n.T("key."+a.Name)),
+		)
+	}
+	return km, nil
+}
+
+// Binding returns the binding for the named action
+func (k KeyMap) Binding(name string) (key.Binding, bool) {
+	a, ok := LookupKeyAction(name)
+	if !ok {
+		return key.Binding{}, false
+	}
+	return *a.binding(&k), true
+}
+
+// ApplyKeyMap installs the key bindings and rebinds the component actions
+// that share them
+func (m *Model) ApplyKeyMap(keys KeyMap) {
+	m.Keys = keys
+
+	taskKeys := tasklist.DefaultKeyMap()
+	taskKeys.Add = rebind(taskKeys.Add, keys.Add)
+	taskKeys.Edit = rebind(taskKeys.Edit, keys.Edit)
+	taskKeys.Delete = rebind(taskKeys.Delete, keys.Delete)
+	m.TaskList.SetKeyMap(taskKeys)
+	m.TaskList.SetCursorKeys(keys.Up, keys.Down)
+
+	habitKeys := habits.DefaultKeyMap()
+	habitKeys.Add = rebind(habitKeys.Add, keys.Add)
+	habitKeys.Delete = rebind(habitKeys.Delete, keys.Delete)
+	m.HabitsModel.SetKeyMap(habitKeys)
+	m.HabitsModel.SetCursorKeys(keys.Up, keys.Down)
+
+	alertKeys := alerts.DefaultKeyMap()
+	alertKeys.Add = rebind(alertKeys.Add, keys.Add)
+	alertKeys.Delete = rebind(alertKeys.Delete, keys.Delete)
+	m.AlertsModel.SetKeyMap(alertKeys)
+	m.AlertsModel.SetCursorKeys(keys.Up, keys.Down)
+
+	inboxKeys := inbox.DefaultKeyMap()
+	inboxKeys.Dismiss = rebind(inboxKeys.Dismiss, keys.Delete)
+	m.InboxModel.SetKeyMap(inboxKeys)
+	m.InboxModel.SetCursorKeys(keys.Up, keys.Down)
+
+	trashKeys := trash.DefaultKeyMap()
+	trashKeys.Purge = rebind(trashKeys.Purge, keys.Delete)
+	m.TrashModel.SetKeyMap(trashKeys)
+	m.TrashModel.SetCursorKeys(keys.Up, keys.Down)
+
+	calendarKeys := calendar.DefaultKeyMap()
+	calendarKeys.Select = rebind(calendarKeys.Select, keys.Enter)
+	m.CalendarModel.SetKeyMap(calendarKeys)
+
+	weekKeys := week.DefaultKeyMap()
+	weekKeys.Select = rebind(weekKeys.Select, keys.Enter)
+	weekKeys.Generate = rebind(weekKeys.Generate, keys.Generate)
+	m.WeekModel.SetKeyMap(weekKeys)
+
+	searchKeys := search.DefaultKeyMap()
+	searchKeys.Select = rebind(searchKeys.Select, keys.Enter)
+	m.SearchModel.SetKeyMap(searchKeys)
+}
+
+// rebind returns b with the keys of from, keeping b's help description
+func rebind(b, from key.Binding) key.Binding {
+	return key.NewBinding(
+		key.WithKeys(from.Keys()...),
+		key.WithHelp(from.Help().Key, b.Help().Desc),
+	)
 }
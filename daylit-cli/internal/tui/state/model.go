@@ -4,19 +4,30 @@ import (
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/huh"
 
+	"github.com/julianstephens/daylit/daylit-cli/internal/cheatsheet"
+	"github.com/julianstephens/daylit/daylit-cli/internal/clock"
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/logger"
 	"github.com/julianstephens/daylit/daylit-cli/internal/models"
 	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
 	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/alerts"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/calendar"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/habits"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/inbox"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/now"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/ot"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/plan"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/search"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/settings"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/tasklist"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/toast"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/trash"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/week"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
 	"github.com/julianstephens/daylit/daylit-cli/internal/validation"
 )
 
@@ -26,13 +37,16 @@ type TaskFormModel struct {
 	Duration   string
 	Recurrence constants.RecurrenceType
 	Interval   string
+	Until      string // Last date the task recurs on; empty for no end
+	Count      string // Occurrences to stop after; empty or 0 for no limit
 	Priority   string
 	Active     bool
 }
 
 // HabitFormModel represents the form model for habit creation
 type HabitFormModel struct {
-	Name string
+	Name     string
+	Category string
 }
 
 // SettingsFormModel represents the form model for settings
@@ -41,6 +55,8 @@ type SettingsFormModel struct {
 	DayEnd               string
 	DefaultBlockMin      string
 	Timezone             string
+	Theme                string
+	WeekStart            string
 	PromptOnEmpty        bool
 	StrictMode           bool
 	DefaultLogDays       string
@@ -57,6 +73,12 @@ type OTFormModel struct {
 	Note  string
 }
 
+// NoteFormModel represents the form model for a plan's notes
+type NoteFormModel struct {
+	Date string
+	Note string
+}
+
 // AlertFormModel represents the form model for alerts
 type AlertFormModel struct {
 	Message    string
@@ -65,30 +87,58 @@ type AlertFormModel struct {
 	Recurrence constants.RecurrenceType
 	Interval   string
 	Weekdays   string
+	MonthDay   string
+	Month      string
+	Cron       string
+}
+
+// SaveConflict is a save that was rejected because someone else changed the
+// record after it was loaded
+type SaveConflict struct {
+	Record      string                 // What was being saved, e.g. `task "Read"`
+	Overlapping []string               // Fields that both sides changed
+	Keep        func() error           // Saves the user's changes on top of the latest record
+	ReturnState constants.SessionState // Where to go once the conflict is resolved
 }
 
 // Model represents the shared state for the TUI
 type Model struct {
 	Store               storage.Provider
 	Scheduler           *scheduler.Scheduler
+	Clock               clock.Clock // The system clock when nil
 	State               constants.SessionState
 	PreviousState       constants.SessionState
 	Keys                KeyMap
 	Help                help.Model
+	Cheatsheet          []cheatsheet.Entry // CLI commands shown in the full help, built from the command tree
 	TaskList            tasklist.Model
 	PlanModel           plan.Model
+	CalendarModel       calendar.Model
+	WeekModel           week.Model
 	NowModel            now.Model
 	HabitsModel         habits.Model
 	OTModel             ot.Model
 	AlertsModel         alerts.Model
+	InboxModel          inbox.Model
+	TrashModel          trash.Model
 	SettingsModel       settings.Model
+	SearchModel         search.Model
+	Toast               toast.Model
+	Spinner             spinner.Model
+	Busy                []string // Labels of the background operations still running
+	Health              StoreHealth
 	Form                *huh.Form
 	TaskForm            *TaskFormModel
 	HabitForm           *HabitFormModel
 	OTForm              *OTFormModel
+	NoteForm            *NoteFormModel
 	AlertForm           *AlertFormModel
 	SettingsForm        *SettingsFormModel
 	EditingTask         *models.Task
+	EditingTaskBase     models.Task      // EditingTask as loaded, to merge with changes made elsewhere
+	EditingSettings     *models.Settings // Settings as loaded when the settings form opened
+	Conflict            *SaveConflict    // Save waiting for the user to keep or discard their changes
+	TriageInboxID       string           // Inbox item being turned into a task, One Thing or alert
 	Quitting            bool
 	Width               int
 	Height              int
@@ -96,20 +146,32 @@ type Model struct {
 	TaskToDeleteID      string
 	TaskToRestoreID     string
 	HabitToArchiveID    string
+	TrashToPurge        *models.TrashItem     // Deleted item waiting for the user to confirm its purge
 	ValidationWarning   string                // Validation warning message to display
 	ValidationConflicts []validation.Conflict // Detailed conflict information
 	PlanToDeleteDate    string
 	PlanToRestoreDate   string
 	PlanToOverwriteDate string
+	PlanToReviewDate    string // Previous day offered for review after the date rolls over
+	Today               string // Date the day views were loaded for, in YYYY-MM-DD format
+	RefreshPending      bool   // A database change is waiting for the user to leave a form
+	ThemeOverride       string // Theme from config.toml, used instead of the theme setting
 	FormError           string // Error message to display for form operations
+
+	validationSeq int // Number of the latest background validation
 }
 
-// New creates a new state Model
-func New(store storage.Provider, sched *scheduler.Scheduler) Model {
-	today := time.Now().Format(constants.DateFormat)
+// New creates a new state Model. clk tells the time; nil means the system clock.
+func New(store storage.Provider, sched *scheduler.Scheduler, clk clock.Clock) Model {
+	// Activate the configured theme before any component renders
+	currentSettings, _ := store.GetSettings()
+	theme.Set(theme.Resolve(currentSettings.Theme))
+
+	current := clock.Or(clk).Now()
+	today := current.Format(constants.DateFormat)
 	planData, planErr := store.GetPlan(today)
 	pm := plan.New(0, 0)
-	nm := now.New()
+	nm := now.New(current)
 	tasks, taskErr := store.GetAllTasksIncludingDeleted()
 	if taskErr != nil {
 		// Initialize with empty task list on error
@@ -120,10 +182,19 @@ func New(store storage.Provider, sched *scheduler.Scheduler) Model {
 		nm.SetPlan(planData, tasks)
 	}
 
+	// Late days run past midnight, so the Now tab also looks at yesterday's plan
+	if window, err := models.ParseDayWindow(currentSettings.DayStart, currentSettings.DayEnd); err == nil {
+		nm.SetWindow(window)
+	}
+	yesterday := current.AddDate(0, 0, -1).Format(constants.DateFormat)
+	if prevPlan, err := store.GetPlan(yesterday); err == nil {
+		nm.SetPrevious(&prevPlan, tasks)
+	}
+
 	// Initialize habits
 	habitsList, _ := store.GetAllHabits(false, true) // includeArchived=false, includeDeleted=true
 	habitEntries, _ := store.GetHabitEntriesForDay(today)
-	hm := habits.New(habitsList, habitEntries, 0, 0)
+	hm := habits.New(habitsList, habitEntries, today, 0, 0)
 
 	// Initialize OT
 	otEntry, _ := store.GetOTEntry(today)
@@ -133,26 +204,73 @@ func New(store storage.Provider, sched *scheduler.Scheduler) Model {
 	}
 
 	// Initialize settings
-	currentSettings, _ := store.GetSettings()
 	otSettings, _ := store.GetOTSettings()
 	sm := settings.New(currentSettings, otSettings, 0, 0)
 
+	// Initialize calendar with the current month's summaries
+	monthStart, monthEnd := calendar.MonthRange(current)
+	summaries, _ := store.GetDaySummaries(monthStart, monthEnd)
+	cm := calendar.New(current, summaries, 0, 0)
+	vacations, _ := store.GetVacations(monthStart, monthEnd)
+	cm.SetVacations(vacations)
+	cm.SetWeekStart(currentSettings.FirstWeekday())
+
 	// Initialize alerts
 	alertsList, _ := store.GetAllAlerts()
 	am := alerts.New(alertsList, 0, 0)
 
-	return Model{
+	// Initialize inbox
+	inboxItems, _ := store.GetInboxItems()
+
+	// Initialize trash
+	trashItems, _ := storage.ListTrash(store)
+
+	m := Model{
 		Store:         store,
 		Scheduler:     sched,
 		State:         constants.StateNow,
-		Keys:          DefaultKeyMap(),
 		Help:          help.New(),
 		TaskList:      tasklist.New(tasks, 0, 0),
 		PlanModel:     pm,
+		CalendarModel: cm,
+		WeekModel:     week.New(current, currentSettings.FirstWeekday(), 0, 0),
 		NowModel:      nm,
 		HabitsModel:   hm,
 		OTModel:       om,
 		AlertsModel:   am,
+		InboxModel:    inbox.New(inboxItems, 0, 0),
+		TrashModel:    trash.New(trashItems, 0, 0),
 		SettingsModel: sm,
+		SearchModel:   search.New(),
+		Toast:         toast.New(),
+		Spinner:       spinner.New(spinner.WithSpinner(spinner.MiniDot)),
+		Today:         today,
+	}
+	m.SetClock(clk)
+	_ = m.RefreshWeek(m.WeekModel.Start()) // Reloaded again when the Week tab is entered
+
+	// Invalid key overrides fall back to the defaults rather than blocking startup;
+	// `daylit keys list` reports the error
+	keys, err := NewKeyMap(currentSettings.Keys)
+	if err != nil {
+		logger.Warn("Ignoring invalid key bindings", "error", err)
+		keys = DefaultKeyMap()
 	}
+	m.ApplyKeyMap(keys)
+
+	return m
+}
+
+// SetClock replaces the model's clock and the one the tabs tell today by
+func (m *Model) SetClock(clk clock.Clock) {
+	m.Clock = clk
+	m.PlanModel.SetClock(clk)
+	m.CalendarModel.SetClock(clk)
+	m.WeekModel.SetClock(clk)
+	m.InboxModel.SetClock(clk)
+}
+
+// Now returns the current time by the model's clock
+func (m *Model) Now() time.Time {
+	return clock.Or(m.Clock).Now()
 }
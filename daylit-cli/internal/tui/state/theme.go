@@ -0,0 +1,19 @@
+package state
+
+import "github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
+
+// ApplyTheme activates the named theme and re-renders components that cache
+// styled content. ThemeOverride, when set, wins over name.
+func (m *Model) ApplyTheme(name string) {
+	if m.ThemeOverride != "" {
+		name = m.ThemeOverride
+	}
+	theme.Set(theme.Resolve(name))
+	m.TaskList.RefreshStyles()
+	m.HabitsModel.RefreshStyles()
+	m.AlertsModel.RefreshStyles()
+	m.InboxModel.RefreshStyles()
+	m.TrashModel.RefreshStyles()
+	m.OTModel.RefreshStyles()
+	m.PlanModel.Render()
+}
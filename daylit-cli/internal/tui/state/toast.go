@@ -0,0 +1,25 @@
+package state
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/toast"
+)
+
+// NotifySuccess queues a success message in the status bar
+func (m *Model) NotifySuccess(text string) tea.Cmd {
+	return m.Toast.Push(toast.LevelSuccess, text)
+}
+
+// NotifyInfo queues a neutral message in the status bar
+func (m *Model) NotifyInfo(text string) tea.Cmd {
+	return m.Toast.Push(toast.LevelInfo, text)
+}
+
+// NotifyError queues an error message in the status bar, prefixed with the
+// action that failed, and checks whether the storage backend is still there
+func (m *Model) NotifyError(action string, err error) tea.Cmd {
+	return tea.Batch(m.Toast.Push(toast.LevelError, fmt.Sprintf("%s: %v", action, err)), m.CheckHealth())
+}
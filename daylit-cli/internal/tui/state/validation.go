@@ -4,36 +4,67 @@ import (
 	"fmt"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
+
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
 	"github.com/julianstephens/daylit/daylit-cli/internal/validation"
 )
 
-// UpdateValidationStatus runs validation and updates the warning message
-func (m *Model) UpdateValidationStatus() {
+// ValidationMsg carries the result of a validation run in the background
+type ValidationMsg struct {
+	Seq       int // Which run the result belongs to; only the latest is shown
+	Warning   string
+	Conflicts []validation.Conflict
+}
+
+// Validate runs validation in the background; the result arrives as a
+// ValidationMsg to pass to ApplyValidation
+func (m *Model) Validate() tea.Cmd {
+	m.validationSeq++
+	seq, store, now := m.validationSeq, m.Store, m.Now()
+	return tea.Batch(m.StartBusy(BusyValidating), func() tea.Msg {
+		warning, conflicts := validate(store, now)
+		return ValidationMsg{Seq: seq, Warning: warning, Conflicts: conflicts}
+	})
+}
+
+// ApplyValidation shows the result of a background validation, unless a
+// newer one has started since
+func (m *Model) ApplyValidation(msg ValidationMsg) {
+	m.EndBusy(BusyValidating)
+	if msg.Seq != m.validationSeq {
+		return
+	}
+	m.ValidationWarning = msg.Warning
+	m.ValidationConflicts = msg.Conflicts
+}
+
+// validate checks the tasks and today's plan and returns the warning message
+// and conflicts to display. It only reads from store, so it is safe to run in
+// a background command.
+func validate(store storage.Provider, todayDate time.Time) (string, []validation.Conflict) {
 	// Get all tasks
-	tasks, err := m.Store.GetAllTasks()
+	tasks, err := store.GetAllTasks()
 	if err != nil {
 		// Store errors prevent validation - show generic message
-		m.ValidationWarning = "⚠ Validation unavailable"
-		m.ValidationConflicts = nil
-		return
+		return "⚠ Validation unavailable", nil
 	}
 
 	// Get settings
-	settings, err := m.Store.GetSettings()
+	settings, err := store.GetSettings()
 	if err != nil {
 		// Store errors prevent validation - show generic message
-		m.ValidationWarning = "⚠ Validation unavailable"
-		m.ValidationConflicts = nil
-		return
+		return "⚠ Validation unavailable", nil
 	}
 
 	// Get today's plan
-	today := time.Now().Format(constants.DateFormat)
-	todayDate := time.Now()
-	plan, err := m.Store.GetPlan(today)
+	today := todayDate.Format(constants.DateFormat)
+	plan, err := store.GetPlan(today)
 
 	validator := validation.New()
+	validator.MinFreeMinutes = settings.MinFreeMinutes
+	validator.Recovery = settings.Recovery()
 
 	// Validate tasks first - scoped to today's date
 	taskResult := validator.ValidateTasksForDate(tasks, &todayDate)
@@ -46,12 +77,9 @@ func (m *Model) UpdateValidationStatus() {
 
 	// Combine conflicts
 	allConflicts := append(taskResult.Conflicts, planResult.Conflicts...)
-	m.ValidationConflicts = allConflicts
-
 	if len(allConflicts) > 0 {
 		// Show count of conflicts
-		m.ValidationWarning = fmt.Sprintf("⚠ %d validation warning(s)", len(allConflicts))
-	} else {
-		m.ValidationWarning = ""
+		return fmt.Sprintf("⚠ %d validation warning(s)", len(allConflicts)), allConflicts
 	}
+	return "", allConflicts
 }
@@ -0,0 +1,62 @@
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+)
+
+// WeekData is what the week view shows for the week starting on Start
+type WeekData struct {
+	Start            time.Time
+	Plans            []models.DayPlan
+	Tasks            []models.Task
+	DayStart, DayEnd string
+}
+
+// LoadWeek reads the plans of the week starting at the given day. It only
+// reads from store, so it is safe to run in a background command. Days
+// without a plan are expected and are not reported as errors.
+func LoadWeek(store storage.Provider, start time.Time) (WeekData, error) {
+	plans, err := store.GetPlansRange(
+		start.Format(constants.DateFormat),
+		start.AddDate(0, 0, 6).Format(constants.DateFormat),
+	)
+	if err != nil {
+		return WeekData{}, fmt.Errorf("loading plans: %w", err)
+	}
+	tasks, err := store.GetAllTasksIncludingDeleted()
+	if err != nil {
+		return WeekData{}, fmt.Errorf("loading tasks: %w", err)
+	}
+	settings, err := store.GetSettings()
+	if err != nil {
+		return WeekData{}, fmt.Errorf("loading settings: %w", err)
+	}
+	return WeekData{Start: start, Plans: plans, Tasks: tasks, DayStart: settings.DayStart, DayEnd: settings.DayEnd}, nil
+}
+
+// SetWeek shows data in the week view
+func (m *Model) SetWeek(data WeekData) {
+	m.WeekModel.SetWeek(data.Plans, data.Tasks, data.DayStart, data.DayEnd)
+}
+
+// RefreshWeek reloads the plans shown in the week view starting at the given day
+func (m *Model) RefreshWeek(start time.Time) error {
+	data, err := LoadWeek(m.Store, start)
+	if err != nil {
+		return err
+	}
+	m.SetWeek(data)
+	return nil
+}
+
+// ApplyWeekStart makes the week and calendar views start their weeks on
+// first. The caller reloads the week view from WeekModel.Start.
+func (m *Model) ApplyWeekStart(first time.Weekday) {
+	m.WeekModel.SetWeekStart(first)
+	m.CalendarModel.SetWeekStart(first)
+}
@@ -1,25 +1,35 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
 
-var (
-	activeTabStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("205")).
-			Background(lipgloss.Color("236")).
-			Padding(0, 1).
-			Bold(true)
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
+)
 
-	inactiveTabStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("240")).
-				Padding(0, 1)
+func activeTabStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Accent).
+		Background(theme.Current().Surface).
+		Padding(0, 1).
+		Bold(true)
+}
 
-	dangerStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
-			Bold(true)
+func inactiveTabStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Padding(0, 1)
+}
 
-	warningStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("214")).
-			Italic(true)
+func dangerStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Danger).
+		Bold(true)
+}
 
-	docStyle = lipgloss.NewStyle().Padding(1, 2)
-)
+func warningStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Warning).
+		Italic(true)
+}
+
+var docStyle = lipgloss.NewStyle().Padding(1, 2)
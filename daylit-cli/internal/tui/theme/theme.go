@@ -0,0 +1,171 @@
+package theme
+
+import (
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+// Palette holds the semantic colors used across the TUI
+type Palette struct {
+	Accent      lipgloss.TerminalColor // titles and the active tab
+	Text        lipgloss.TerminalColor // primary foreground text
+	Bright      lipgloss.TerminalColor // emphasized values
+	Muted       lipgloss.TerminalColor // secondary text, labels, and hints
+	Faint       lipgloss.TerminalColor // de-emphasized content such as days outside the month
+	Surface     lipgloss.TerminalColor // background of the active tab
+	Highlight   lipgloss.TerminalColor // borders and selection backgrounds
+	OnHighlight lipgloss.TerminalColor // text drawn on top of Highlight
+	Warning     lipgloss.TerminalColor
+	Danger      lipgloss.TerminalColor
+}
+
+// Theme describes the colors and form style used by the TUI
+type Theme struct {
+	Name string
+	Palette
+	// DraculaForms selects the Dracula huh theme for forms instead of the base theme
+	DraculaForms bool
+}
+
+var builtins = map[string]Theme{
+	constants.ThemeDark: {
+		Name: constants.ThemeDark,
+		Palette: Palette{
+			Accent:      lipgloss.Color("205"),
+			Text:        lipgloss.Color("252"),
+			Bright:      lipgloss.Color("255"),
+			Muted:       lipgloss.Color("240"),
+			Faint:       lipgloss.Color("238"),
+			Surface:     lipgloss.Color("236"),
+			Highlight:   lipgloss.Color("62"),
+			OnHighlight: lipgloss.Color("0"),
+			Warning:     lipgloss.Color("214"),
+			Danger:      lipgloss.Color("196"),
+		},
+		DraculaForms: true,
+	},
+	constants.ThemeLight: {
+		Name: constants.ThemeLight,
+		Palette: Palette{
+			Accent:      lipgloss.Color("125"),
+			Text:        lipgloss.Color("235"),
+			Bright:      lipgloss.Color("232"),
+			Muted:       lipgloss.Color("243"),
+			Faint:       lipgloss.Color("250"),
+			Surface:     lipgloss.Color("254"),
+			Highlight:   lipgloss.Color("25"),
+			OnHighlight: lipgloss.Color("231"),
+			Warning:     lipgloss.Color("130"),
+			Danger:      lipgloss.Color("160"),
+		},
+		DraculaForms: false,
+	},
+	constants.ThemeHighContrast: {
+		Name: constants.ThemeHighContrast,
+		Palette: Palette{
+			Accent:      lipgloss.Color("15"),
+			Text:        lipgloss.Color("15"),
+			Bright:      lipgloss.Color("15"),
+			Muted:       lipgloss.Color("7"),
+			Faint:       lipgloss.Color("7"),
+			Surface:     lipgloss.Color("4"),
+			Highlight:   lipgloss.Color("11"),
+			OnHighlight: lipgloss.Color("0"),
+			Warning:     lipgloss.Color("11"),
+			Danger:      lipgloss.Color("9"),
+		},
+		DraculaForms: false,
+	},
+	constants.ThemeNoColor: {
+		Name: constants.ThemeNoColor,
+		Palette: Palette{
+			Accent:      lipgloss.NoColor{},
+			Text:        lipgloss.NoColor{},
+			Bright:      lipgloss.NoColor{},
+			Muted:       lipgloss.NoColor{},
+			Faint:       lipgloss.NoColor{},
+			Surface:     lipgloss.NoColor{},
+			Highlight:   lipgloss.NoColor{},
+			OnHighlight: lipgloss.NoColor{},
+			Warning:     lipgloss.NoColor{},
+			Danger:      lipgloss.NoColor{},
+		},
+		DraculaForms: false,
+	},
+}
+
+var (
+	mu      sync.RWMutex
+	current = builtins[constants.ThemeDark]
+)
+
+// Lookup returns the built-in theme with the given name
+func Lookup(name string) (Theme, bool) {
+	t, ok := builtins[name]
+	return t, ok
+}
+
+// Names returns the names of all built-in themes in sorted order
+func Names() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the theme for the given setting value. The NO_COLOR
+// environment variable always selects the no-color theme, and unknown
+// names fall back to the default theme.
+func Resolve(name string) Theme {
+	if os.Getenv("NO_COLOR") != "" {
+		return builtins[constants.ThemeNoColor]
+	}
+	if t, ok := builtins[name]; ok {
+		return t
+	}
+	return builtins[constants.DefaultTheme]
+}
+
+// Set makes t the active theme
+func Set(t Theme) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = t
+}
+
+// Current returns the active theme
+func Current() Theme {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Form returns the huh theme matching the active theme
+func Form() *huh.Theme {
+	if Current().DraculaForms {
+		return huh.ThemeDracula()
+	}
+	return huh.ThemeBase()
+}
+
+// ListDelegate returns a list delegate styled with the active theme
+func ListDelegate() list.DefaultDelegate {
+	t := Current()
+	d := list.NewDefaultDelegate()
+	d.Styles.NormalTitle = d.Styles.NormalTitle.Foreground(t.Text)
+	d.Styles.NormalDesc = d.Styles.NormalDesc.Foreground(t.Muted)
+	d.Styles.SelectedTitle = d.Styles.SelectedTitle.Foreground(t.Accent).BorderForeground(t.Accent)
+	d.Styles.SelectedDesc = d.Styles.SelectedDesc.Foreground(t.Accent).BorderForeground(t.Accent)
+	d.Styles.DimmedTitle = d.Styles.DimmedTitle.Foreground(t.Faint)
+	d.Styles.DimmedDesc = d.Styles.DimmedDesc.Foreground(t.Faint)
+	return d
+}
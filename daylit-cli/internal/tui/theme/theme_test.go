@@ -0,0 +1,75 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting string
+		noColor string
+		want    string
+	}{
+		{
+			name:    "built-in theme",
+			setting: constants.ThemeLight,
+			want:    constants.ThemeLight,
+		},
+		{
+			name:    "unknown theme falls back to default",
+			setting: "solarized",
+			want:    constants.DefaultTheme,
+		},
+		{
+			name:    "empty setting falls back to default",
+			setting: "",
+			want:    constants.DefaultTheme,
+		},
+		{
+			name:    "NO_COLOR overrides setting",
+			setting: constants.ThemeHighContrast,
+			noColor: "1",
+			want:    constants.ThemeNoColor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			if got := Resolve(tt.setting); got.Name != tt.want {
+				t.Errorf("Resolve(%q) = %q, want %q", tt.setting, got.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupBuiltins(t *testing.T) {
+	for _, name := range []string{constants.ThemeDark, constants.ThemeLight, constants.ThemeHighContrast, constants.ThemeNoColor} {
+		if _, ok := Lookup(name); !ok {
+			t.Errorf("Lookup(%q) not found", name)
+		}
+	}
+	if _, ok := Lookup("solarized"); ok {
+		t.Error("Lookup(\"solarized\") should not be found")
+	}
+	if got := len(Names()); got != 4 {
+		t.Errorf("Names() returned %d themes, want 4", got)
+	}
+}
+
+func TestSetCurrent(t *testing.T) {
+	prev := Current()
+	defer Set(prev)
+
+	light, _ := Lookup(constants.ThemeLight)
+	Set(light)
+	if got := Current().Name; got != constants.ThemeLight {
+		t.Errorf("Current() = %q, want %q", got, constants.ThemeLight)
+	}
+	if Form() == nil {
+		t.Error("Form() returned nil")
+	}
+}
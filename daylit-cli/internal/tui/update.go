@@ -1,18 +1,56 @@
 package tui
 
 import (
-	"time"
-
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/now"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/components/toast"
 	"github.com/julianstephens/daylit/daylit-cli/internal/tui/handlers"
 )
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	// Toast dismissals arrive on a timer and must be handled in every state
+	if msg, ok := msg.(toast.DismissMsg); ok {
+		var cmd tea.Cmd
+		m.Toast, cmd = m.Toast.Update(msg)
+		return m, cmd
+	}
+
+	// Clock ticks keep running in every state and detect the date rolling
+	// over. They carry the system time, so they're read by the model's clock.
+	if _, ok := msg.(now.TickMsg); ok {
+		t := m.Now()
+		var cmd tea.Cmd
+		m.NowModel, cmd = m.NowModel.Update(now.TickMsg(t))
+		return m, tea.Batch(cmd, handlers.HandleDayRollover(&m.Model, t))
+	}
+
+	// Database changes arrive in every state; the refresh waits for a tab
+	switch msg.(type) {
+	case handlers.ChangeMsg:
+		return m, tea.Batch(handlers.HandleChange(&m.Model), handlers.WaitForChange(m.changes))
+	case handlers.RefreshMsg:
+		return m, handlers.HandleRefresh(&m.Model)
+	}
+
+	// Background work finishes in every state
+	if handled, cmd := handlers.HandleAsyncMessages(&m.Model, msg); handled {
+		return m, cmd
+	}
+	if handled, cmd := handlers.HandleHealthMessages(&m.Model, msg); handled {
+		return m, cmd
+	}
+
+	// Handle Storage Down State
+	if m.State == constants.StateStorageDown {
+		cmd := handlers.HandleStorageDownState(&m.Model, msg)
+		return m, cmd
+	}
+
 	// Handle Editing State
 	if m.State == constants.StateEditing {
 		cmd := handlers.HandleEditingState(&m.Model, msg)
@@ -37,12 +75,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	// Handle Edit Note State
+	if m.State == constants.StateEditNote {
+		cmd := handlers.HandleEditNoteState(&m.Model, msg)
+		return m, cmd
+	}
+
 	// Handle Edit Settings State
 	if m.State == constants.StateEditSettings {
 		cmd := handlers.HandleEditSettingsState(&m.Model, msg)
 		return m, cmd
 	}
 
+	// Handle Search State
+	if m.State == constants.StateSearch {
+		cmd := handlers.HandleSearchState(&m.Model, msg)
+		return m, cmd
+	}
+
 	// Handle Feedback State
 	if m.State == constants.StateFeedback {
 		cmd := handlers.HandleFeedbackState(&m.Model, msg)
@@ -73,22 +123,52 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	// Handle Confirm Purge State
+	if m.State == constants.StateConfirmPurge {
+		cmd := handlers.HandleConfirmPurgeState(&m.Model, msg)
+		return m, cmd
+	}
+
+	// Handle Confirm Morning Plan State
+	if m.State == constants.StateConfirmMorningPlan {
+		cmd := handlers.HandleConfirmMorningPlanState(&m.Model, msg)
+		return m, cmd
+	}
+
+	// Handle Confirm Review State
+	if m.State == constants.StateConfirmReview {
+		cmd := handlers.HandleConfirmReviewState(&m.Model, msg)
+		return m, cmd
+	}
+
+	// Handle Confirm Conflict State
+	if m.State == constants.StateConfirmConflict {
+		cmd := handlers.HandleConfirmConflictState(&m.Model, msg)
+		return m, cmd
+	}
+
 	// Handle Window Size
 	if msg, ok := msg.(tea.WindowSizeMsg); ok {
 		m.Width = msg.Width
 		m.Height = msg.Height
 		m.Help.Width = msg.Width
-		// Adjust height for tabs and help
-		listHeight := msg.Height - 4 // Approximate height for tabs + help
+		// Adjust height for tabs, status bar, and help
+		listHeight := msg.Height - 5 // Approximate height for tabs + status bar + help
 
 		h, v := docStyle.GetFrameSize()
 		m.TaskList.SetSize(msg.Width-h, listHeight-v)
 		m.PlanModel.SetSize(msg.Width-h, listHeight-v)
+		m.CalendarModel.SetSize(msg.Width-h, listHeight-v)
+		m.WeekModel.SetSize(msg.Width-h, listHeight-v)
 		m.NowModel.SetSize(msg.Width, listHeight)
 		m.HabitsModel.SetSize(msg.Width-h, listHeight-v)
 		m.OTModel.SetSize(msg.Width-h, listHeight-v)
 		m.AlertsModel.SetSize(msg.Width-h, listHeight-v)
+		m.InboxModel.SetSize(msg.Width-h, listHeight-v)
+		m.TrashModel.SetSize(msg.Width-h, listHeight-v)
 		m.SettingsModel.SetSize(msg.Width-h, listHeight-v)
+		m.SearchModel.SetSize(msg.Width-h, listHeight-v)
+		m.Toast.SetSize(msg.Width)
 		return m, nil
 	}
 
@@ -105,6 +185,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	if handled, cmd := handlers.HandleInboxMessages(&m.Model, msg); handled {
+		return m, cmd
+	}
+
+	if handled, cmd := handlers.HandleTrashMessages(&m.Model, msg); handled {
+		return m, cmd
+	}
+
 	if handled, cmd := handlers.HandleSettingsMessages(&m.Model, msg); handled {
 		return m, cmd
 	}
@@ -113,10 +201,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	if handled, cmd := handlers.HandlePlanMessages(&m.Model, msg); handled {
+		return m, cmd
+	}
+
+	if handled, cmd := handlers.HandleCalendarMessages(&m.Model, msg); handled {
+		return m, cmd
+	}
+
+	if handled, cmd := handlers.HandleWeekMessages(&m.Model, msg); handled {
+		return m, cmd
+	}
+
 	if handled, cmd := handlers.HandleFeedbackMessages(&m.Model, msg); handled {
 		return m, cmd
 	}
 
+	// Tabs are read-only while the storage backend is unreachable
+	if handled, cmd := handlers.HandleReadOnlyKeys(&m.Model, msg); handled {
+		return m, cmd
+	}
+
 	// Global Keys
 	if msg, ok := msg.(tea.KeyMsg); ok {
 		if handled, cmd := handlers.HandleGlobalKeys(&m.Model, msg); handled {
@@ -127,11 +232,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	// Always update nowModel for time ticks
 	var cmd tea.Cmd
-	m.NowModel, cmd = m.NowModel.Update(msg)
-	cmds = append(cmds, cmd)
-
 	switch m.State {
 	case constants.StateTasks:
 		m.TaskList, cmd = m.TaskList.Update(msg)
@@ -139,7 +240,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case constants.StatePlan:
 		if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.Keys.Generate) {
 			// Generate plan
-			today := time.Now().Format(constants.DateFormat)
+			today := m.Now().Format(constants.DateFormat)
 
 			// Check if plan already exists
 			_, err := m.Store.GetPlan(today)
@@ -150,29 +251,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
-			settings, _ := m.Store.GetSettings()
-
-			// Default settings if not set
-			dayStart := settings.DayStart
-			if dayStart == "" {
-				dayStart = "08:00"
-			}
-			dayEnd := settings.DayEnd
-			if dayEnd == "" {
-				dayEnd = "18:00"
-			}
-
-			tasks, _ := m.Store.GetAllTasks()
-			plan, err := m.Scheduler.GeneratePlan(today, tasks, dayStart, dayEnd)
-			if err == nil {
-				m.Store.SavePlan(plan)
-				m.PlanModel.SetPlan(plan, tasks)
-				m.NowModel.SetPlan(plan, tasks)
-				m.UpdateValidationStatus()
-			}
+			cmds = append(cmds, handlers.GeneratePlan(&m.Model, today))
 		}
 		m.PlanModel, cmd = m.PlanModel.Update(msg)
 		cmds = append(cmds, cmd)
+	case constants.StateCalendar:
+		m.CalendarModel, cmd = m.CalendarModel.Update(msg)
+		cmds = append(cmds, cmd)
+	case constants.StateWeek:
+		m.WeekModel, cmd = m.WeekModel.Update(msg)
+		cmds = append(cmds, cmd)
 	case constants.StateHabits:
 		m.HabitsModel, cmd = m.HabitsModel.Update(msg)
 		cmds = append(cmds, cmd)
@@ -182,11 +270,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case constants.StateAlerts:
 		m.AlertsModel, cmd = m.AlertsModel.Update(msg)
 		cmds = append(cmds, cmd)
+	case constants.StateInbox:
+		m.InboxModel, cmd = m.InboxModel.Update(msg)
+		cmds = append(cmds, cmd)
+	case constants.StateTrash:
+		m.TrashModel, cmd = m.TrashModel.Update(msg)
+		cmds = append(cmds, cmd)
 	case constants.StateSettings:
 		m.SettingsModel, cmd = m.SettingsModel.Update(msg)
 		cmds = append(cmds, cmd)
 	case constants.StateNow:
-		// nowModel is already updated above
+		// nowModel only handles clock ticks, which are handled above
 	}
 
 	return m, tea.Batch(cmds...)
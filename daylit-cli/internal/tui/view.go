@@ -2,10 +2,14 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/i18n"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/tui/theme"
 )
 
 func (m Model) View() string {
@@ -20,6 +24,10 @@ func (m Model) View() string {
 		content = m.viewNow()
 	case constants.StatePlan:
 		content = m.viewPlan()
+	case constants.StateCalendar:
+		content = m.viewCalendar()
+	case constants.StateWeek:
+		content = m.viewWeek()
 	case constants.StateTasks:
 		content = m.viewTasks()
 	case constants.StateHabits:
@@ -28,15 +36,21 @@ func (m Model) View() string {
 		content = m.viewOT()
 	case constants.StateAlerts:
 		content = m.viewAlerts()
+	case constants.StateInbox:
+		content = m.viewInbox()
+	case constants.StateTrash:
+		content = m.viewTrash()
 	case constants.StateSettings:
 		content = m.viewSettings()
 	case constants.StateFeedback:
 		content = m.viewFeedback()
-	case constants.StateEditing, constants.StateAddHabit, constants.StateAddAlert, constants.StateEditOT, constants.StateEditSettings:
+	case constants.StateSearch:
+		content = m.viewSearch()
+	case constants.StateEditing, constants.StateAddHabit, constants.StateAddAlert, constants.StateEditOT, constants.StateEditNote, constants.StateEditSettings:
 		formContent := m.Form.View()
 		if m.FormError != "" {
 			errorStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("196")).
+				Foreground(theme.Current().Danger).
 				Bold(true).
 				Padding(1, 0)
 			formContent = lipgloss.JoinVertical(lipgloss.Left,
@@ -53,6 +67,23 @@ func (m Model) View() string {
 		content = m.viewConfirmOverwrite()
 	case constants.StateConfirmArchive:
 		content = m.viewConfirmArchive()
+	case constants.StateConfirmPurge:
+		content = m.viewConfirmPurge()
+	case constants.StateConfirmMorningPlan:
+		content = m.viewConfirmMorningPlan()
+	case constants.StateConfirmReview:
+		content = m.viewConfirmReview()
+	case constants.StateConfirmConflict:
+		content = m.viewConfirmConflict()
+	case constants.StateStorageDown:
+		content = m.viewStorageDown()
+	}
+
+	// The full help replaces the tab with the matching CLI commands
+	if m.Help.ShowAll {
+		if sheet := m.viewCheatsheet(); sheet != "" {
+			content = sheet
+		}
 	}
 
 	var banner string
@@ -65,6 +96,7 @@ func (m Model) View() string {
 		m.viewTabs(),
 		banner,
 		content,
+		m.viewStatus(),
 		m.Help.View(m),
 	)
 
@@ -75,19 +107,49 @@ func (m Model) View() string {
 	return ui
 }
 
+// viewStatus shows the spinner while background operations run, next to
+// the current toast
+func (m Model) viewStatus() string {
+	if len(m.Busy) == 0 {
+		return m.Toast.View()
+	}
+	busy := lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		PaddingRight(1).
+		Render(m.Spinner.View() + " " + m.Busy[len(m.Busy)-1] + "…")
+	return lipgloss.JoinHorizontal(lipgloss.Top, busy, m.Toast.View())
+}
+
 func (m Model) viewTabs() string {
 	var tabs []string
-	tabTitles := []string{"Now", "Plan", "Tasks", "Habits", "OT", "Alerts", "Settings"}
-	for i, title := range tabTitles {
+	tabTitles := []string{"now", "plan", "calendar", "week", "tasks", "habits", "ot", "alerts", "inbox", "trash", "settings"}
+	for i, name := range tabTitles {
+		title := i18n.T("tab." + name)
 		if m.State == constants.SessionState(i) {
-			tabs = append(tabs, activeTabStyle.Render(title))
+			tabs = append(tabs, activeTabStyle().Render(title))
 		} else {
-			tabs = append(tabs, inactiveTabStyle.Render(title))
+			tabs = append(tabs, inactiveTabStyle().Render(title))
 		}
 	}
+	if indicator := m.viewHealth(); indicator != "" {
+		tabs = append(tabs, indicator)
+	}
 	return lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
 }
 
+// viewHealth shows whether the storage backend can be reached, for backends
+// that can become unreachable
+func (m Model) viewHealth() string {
+	if _, ok := storage.Unwrap(m.Store).(storage.HealthChecker); !ok {
+		return ""
+	}
+	style := lipgloss.NewStyle().Padding(0, 1)
+	if m.Health.Down {
+		return style.Foreground(theme.Current().Danger).Bold(true).Render(i18n.T("health.offline"))
+	}
+	return style.Foreground(theme.Current().Muted).Render(i18n.T("health.connected"))
+}
+
 func (m Model) viewNow() string {
 	return m.NowModel.View()
 }
@@ -96,6 +158,14 @@ func (m Model) viewPlan() string {
 	return docStyle.Render(m.PlanModel.View())
 }
 
+func (m Model) viewCalendar() string {
+	return docStyle.Render(m.CalendarModel.View())
+}
+
+func (m Model) viewWeek() string {
+	return docStyle.Render(m.WeekModel.View())
+}
+
 func (m Model) viewTasks() string {
 	return docStyle.Render(m.TaskList.View())
 }
@@ -112,21 +182,33 @@ func (m Model) viewAlerts() string {
 	return docStyle.Render(m.AlertsModel.View())
 }
 
+func (m Model) viewInbox() string {
+	return docStyle.Render(m.InboxModel.View())
+}
+
+func (m Model) viewTrash() string {
+	return docStyle.Render(m.TrashModel.View())
+}
+
 func (m Model) viewSettings() string {
 	return docStyle.Render(m.SettingsModel.View())
 }
 
+func (m Model) viewSearch() string {
+	return docStyle.Render(m.SearchModel.View())
+}
+
 func (m Model) viewFeedback() string {
 	return lipgloss.Place(m.Width, m.Height-4,
 		lipgloss.Center, lipgloss.Center,
 		lipgloss.JoinVertical(lipgloss.Center,
-			"Rate the last completed task:",
+			i18n.T("feedback.title"),
 			"",
-			"[1] On Track",
-			"[2] Too Much",
-			"[3] Unnecessary",
+			i18n.T("feedback.on_track"),
+			i18n.T("feedback.too_much"),
+			i18n.T("feedback.unnecessary"),
 			"",
-			"[q] Cancel",
+			i18n.T("feedback.cancel"),
 		),
 	)
 }
@@ -135,10 +217,10 @@ func (m Model) viewConfirmDelete() string {
 	return lipgloss.Place(m.Width, m.Height-4,
 		lipgloss.Center, lipgloss.Center,
 		lipgloss.JoinVertical(lipgloss.Center,
-			dangerStyle.Render("Are you sure you want to delete this task?"),
+			dangerStyle().Render(i18n.T("confirm.delete_task")),
 			"",
-			"[y] Yes",
-			"[n] No",
+			i18n.T("confirm.yes"),
+			i18n.T("confirm.no"),
 		),
 	)
 }
@@ -153,10 +235,10 @@ func (m Model) viewConfirmRestore() string {
 	return lipgloss.Place(m.Width, m.Height-4,
 		lipgloss.Center, lipgloss.Center,
 		lipgloss.JoinVertical(lipgloss.Center,
-			warningStyle.Render(fmt.Sprintf("Restore deleted %s: %s?", itemType, itemID)),
+			warningStyle().Render(fmt.Sprintf("Restore deleted %s: %s?", itemType, itemID)),
 			"",
-			"[y] Yes",
-			"[n] No",
+			i18n.T("confirm.yes"),
+			i18n.T("confirm.no"),
 		),
 	)
 }
@@ -165,11 +247,11 @@ func (m Model) viewConfirmOverwrite() string {
 	return lipgloss.Place(m.Width, m.Height-4,
 		lipgloss.Center, lipgloss.Center,
 		lipgloss.JoinVertical(lipgloss.Center,
-			dangerStyle.Render(fmt.Sprintf("Overwrite existing plan for %s?", m.PlanToOverwriteDate)),
+			dangerStyle().Render(fmt.Sprintf("Overwrite existing plan for %s?", m.PlanToOverwriteDate)),
 			"This will create a new revision.",
 			"",
-			"[y] Yes",
-			"[n] No",
+			i18n.T("confirm.yes"),
+			i18n.T("confirm.no"),
 		),
 	)
 }
@@ -180,8 +262,8 @@ func (m Model) viewConflictBanner() string {
 	}
 
 	var bannerStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("0")).
-		Background(lipgloss.Color("214")).
+		Foreground(theme.Current().OnHighlight).
+		Background(theme.Current().Warning).
 		Bold(true).
 		Padding(0, 1)
 
@@ -193,10 +275,89 @@ func (m Model) viewConfirmArchive() string {
 	return lipgloss.Place(m.Width, m.Height-4,
 		lipgloss.Center, lipgloss.Center,
 		lipgloss.JoinVertical(lipgloss.Center,
-			warningStyle.Render("Are you sure you want to archive this habit?"),
+			warningStyle().Render(i18n.T("confirm.archive_habit")),
+			"",
+			i18n.T("confirm.yes"),
+			i18n.T("confirm.no"),
+		),
+	)
+}
+
+func (m Model) viewConfirmPurge() string {
+	question := "Permanently remove this item?"
+	if m.TrashToPurge != nil {
+		question = fmt.Sprintf("Permanently remove deleted %s: %s?", m.TrashToPurge.Kind, m.TrashToPurge.Label)
+	}
+	return lipgloss.Place(m.Width, m.Height-4,
+		lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center,
+			dangerStyle().Render(question),
+			"This cannot be undone.",
+			"",
+			i18n.T("confirm.yes"),
+			i18n.T("confirm.no"),
+		),
+	)
+}
+
+func (m Model) viewConfirmMorningPlan() string {
+	return lipgloss.Place(m.Width, m.Height-4,
+		lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center,
+			warningStyle().Render("No plan for today yet. Generate one?"),
+			"The plan will be accepted and can be changed with a new revision.",
+			"",
+			i18n.T("confirm.yes"),
+			i18n.T("confirm.not_today"),
+		),
+	)
+}
+
+func (m Model) viewConfirmReview() string {
+	return lipgloss.Place(m.Width, m.Height-4,
+		lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center,
+			warningStyle().Render("A new day has started."),
+			fmt.Sprintf("Some blocks from %s have no feedback. Review that plan?", m.PlanToReviewDate),
+			"",
+			i18n.T("confirm.yes"),
+			i18n.T("confirm.no"),
+		),
+	)
+}
+
+func (m Model) viewConfirmConflict() string {
+	if m.Conflict == nil {
+		return ""
+	}
+	return lipgloss.Place(m.Width, m.Height-4,
+		lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center,
+			warningStyle().Render(fmt.Sprintf("The %s changed elsewhere while you were editing.", m.Conflict.Record)),
+			"Both versions changed: "+strings.Join(m.Conflict.Overlapping, ", "),
+			"",
+			"[k] Keep mine (other changes are kept too)",
+			"[d] Discard mine",
+		),
+	)
+}
+
+func (m Model) viewStorageDown() string {
+	reason := "The storage backend can't be reached."
+	if m.Health.Err != nil {
+		reason = fmt.Sprintf("The storage backend can't be reached: %v", m.Health.Err)
+	}
+	return lipgloss.Place(m.Width, m.Height-4,
+		lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center,
+			dangerStyle().Render("Storage unavailable"),
+			lipgloss.NewStyle().MaxWidth(max(m.Width-4, 20)).Render(reason),
+			"",
+			"daylit keeps reconnecting in the background. Until then the views",
+			"show what was already loaded and changes can't be saved.",
 			"",
-			"[y] Yes",
-			"[n] No",
+			"[esc] Continue read-only",
+			"[q] Quit",
 		),
 	)
 }
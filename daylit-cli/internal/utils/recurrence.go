@@ -12,6 +12,71 @@ import (
 // based on its recurrence pattern. This logic is shared between validation and
 // scheduling to ensure consistency.
 func ShouldScheduleTask(task models.Task, date time.Time) bool {
+	return withinBounds(task, date) && matchesRule(task, date)
+}
+
+// maxOccurrenceSearchDays caps how far occurrences are searched for, so a
+// rule that never matches (e.g. yearly on Feb 30) can't loop forever
+const maxOccurrenceSearchDays = 100 * 366
+
+// withinBounds reports whether date falls in the span a bounded rule applies
+// to: not before its start or after its until date, and not past its count
+// of occurrences
+func withinBounds(task models.Task, date time.Time) bool {
+	rec := task.Recurrence
+	day := date.Format(constants.DateFormat)
+	if rec.Start != "" && day < rec.Start {
+		return false
+	}
+	if rec.Until != "" && day > rec.Until {
+		return false
+	}
+	if rec.Count > 0 {
+		last, ok := countEnd(task, date.Location())
+		return ok && day <= last.Format(constants.DateFormat)
+	}
+	return true
+}
+
+// countEnd returns the date of the last of the rule's counted occurrences,
+// in loc. It reports false when the rule runs out of search room first.
+func countEnd(task models.Task, loc *time.Location) (time.Time, bool) {
+	start, err := time.Parse(constants.DateFormat, task.Recurrence.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	seen := 0
+	for i := 0; i < maxOccurrenceSearchDays; i++ {
+		if matchesRule(task, day) {
+			seen++
+			if seen == task.Recurrence.Count {
+				return day, true
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}, false
+}
+
+// RecurrenceEnd returns the last day a bounded rule applies to: its until
+// date or the day of its last counted occurrence, whichever comes first. It
+// reports false for a rule without an end.
+func RecurrenceEnd(task models.Task) (string, bool) {
+	rec := task.Recurrence
+	end := rec.Until
+	if rec.Count > 0 {
+		last, ok := countEnd(task, time.UTC)
+		if ok && (end == "" || last.Format(constants.DateFormat) < end) {
+			end = last.Format(constants.DateFormat)
+		}
+	}
+	return end, end != ""
+}
+
+// matchesRule reports whether the recurrence pattern falls on date, ignoring
+// its bounds
+func matchesRule(task models.Task, date time.Time) bool {
 	switch task.Recurrence.Type {
 	case constants.RecurrenceDaily:
 		return true
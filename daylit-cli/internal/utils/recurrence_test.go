@@ -465,3 +465,78 @@ func TestIsNthWeekdayOfMonth_EdgeCases(t *testing.T) {
 		t.Error("Expected Jan 25 to be the last Sunday")
 	}
 }
+
+func TestShouldScheduleTask_Bounds(t *testing.T) {
+	firstMonday := models.Recurrence{
+		Type:             constants.RecurrenceMonthlyDay,
+		WeekOccurrence:   1,
+		DayOfWeekInMonth: time.Monday,
+	}
+
+	tests := []struct {
+		name  string
+		rec   func(models.Recurrence) models.Recurrence
+		dates map[string]bool
+	}{
+		{
+			name: "until",
+			rec: func(r models.Recurrence) models.Recurrence {
+				r.Until = "2026-12-07"
+				return r
+			},
+			dates: map[string]bool{"2026-11-02": true, "2026-12-07": true, "2027-01-04": false},
+		},
+		{
+			name: "count from start",
+			rec: func(r models.Recurrence) models.Recurrence {
+				r.Start, r.Count = "2026-10-17", 3
+				return r
+			},
+			// October's first Monday is before the start
+			dates: map[string]bool{"2026-10-05": false, "2026-11-02": true, "2027-01-04": true, "2027-02-01": false},
+		},
+		{
+			name: "until before the count runs out",
+			rec: func(r models.Recurrence) models.Recurrence {
+				r.Start, r.Count, r.Until = "2026-10-17", 3, "2026-11-30"
+				return r
+			},
+			dates: map[string]bool{"2026-11-02": true, "2026-12-07": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := models.Task{Recurrence: tt.rec(firstMonday)}
+			for date, want := range tt.dates {
+				day, _ := time.Parse(constants.DateFormat, date)
+				if got := ShouldScheduleTask(task, day); got != want {
+					t.Errorf("ShouldScheduleTask(%s) = %v, want %v", date, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceEnd(t *testing.T) {
+	tests := []struct {
+		name string
+		rec  models.Recurrence
+		want string
+	}{
+		{name: "unbounded", rec: models.Recurrence{Type: constants.RecurrenceDaily}},
+		{name: "until", rec: models.Recurrence{Type: constants.RecurrenceDaily, Until: "2026-11-30"}, want: "2026-11-30"},
+		{name: "count", rec: models.Recurrence{Type: constants.RecurrenceWeekdays, Start: "2026-10-16", Count: 3}, want: "2026-10-20"},
+		{name: "earlier until", rec: models.Recurrence{Type: constants.RecurrenceDaily, Start: "2026-10-16", Count: 30, Until: "2026-10-20"}, want: "2026-10-20"},
+		{name: "never matches", rec: models.Recurrence{Type: constants.RecurrenceYearly, Month: 2, MonthDay: 30, Start: "2026-10-16", Count: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := RecurrenceEnd(models.Task{Recurrence: tt.rec})
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("RecurrenceEnd() = %q, %v; want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
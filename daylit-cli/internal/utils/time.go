@@ -66,6 +66,20 @@ func ParseTimeToMinutes(timeStr string) (int, error) {
 	return t.Hour()*60 + t.Minute(), nil
 }
 
+// FormatTimeOfDay formats minutes from midnight as a time string (HH:MM),
+// the inverse of ParseTimeToMinutes.
+func FormatTimeOfDay(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// FormatMinutes formats a duration in minutes as e.g. "45m" or "2h05m".
+func FormatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
 // ParseDateInLocation parses a date string (YYYY-MM-DD) in the specified timezone.
 func ParseDateInLocation(dateStr string, loc *time.Location) (time.Time, error) {
 	t, err := time.Parse(constants.DateFormat, dateStr)
@@ -110,3 +124,24 @@ func ValidateTimezone(timezone string) bool {
 	_, err := time.LoadLocation(timezone)
 	return err == nil
 }
+
+// StartOfWeek returns midnight on the first day of the week containing t,
+// for weeks starting on first
+func StartOfWeek(t time.Time, first time.Weekday) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) - int(first) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// WeekNumber returns the week of the year t falls in. Weeks starting on
+// Monday are numbered as in ISO 8601, where week 1 holds the first Thursday
+// of the year; other weeks are numbered from the one holding January 1.
+func WeekNumber(t time.Time, first time.Weekday) int {
+	if first == time.Monday {
+		_, week := t.ISOWeek()
+		return week
+	}
+	jan1 := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	offset := (int(jan1.Weekday()) - int(first) + 7) % 7
+	return (t.YearDay()-1+offset)/7 + 1
+}
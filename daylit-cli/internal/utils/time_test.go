@@ -474,6 +474,44 @@ func TestCombineDateAndTime(t *testing.T) {
 	}
 }
 
+func TestFormatMinutes(t *testing.T) {
+	tests := []struct {
+		input int
+		want  string
+	}{
+		{0, "0m"},
+		{45, "45m"},
+		{60, "1h00m"},
+		{125, "2h05m"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatMinutes(tt.input); got != tt.want {
+			t.Errorf("FormatMinutes(%d) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTimeOfDay(t *testing.T) {
+	tests := []struct {
+		input int
+		want  string
+	}{
+		{0, "00:00"},
+		{7*60 + 5, "07:05"},
+		{23*60 + 59, "23:59"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatTimeOfDay(tt.input); got != tt.want {
+			t.Errorf("FormatTimeOfDay(%d) = %q, want %q", tt.input, got, tt.want)
+		}
+		if back, err := ParseTimeToMinutes(tt.want); err != nil || back != tt.input {
+			t.Errorf("ParseTimeToMinutes(%q) = %d, %v, want %d", tt.want, back, err, tt.input)
+		}
+	}
+}
+
 func TestValidateTimezone(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -530,3 +568,57 @@ func TestValidateTimezone(t *testing.T) {
 		})
 	}
 }
+
+func TestStartOfWeek(t *testing.T) {
+	// 2026-10-15 is a Thursday
+	thursday := time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		t     time.Time
+		first time.Weekday
+		want  string
+	}{
+		{"sunday start", thursday, time.Sunday, "2026-10-11"},
+		{"monday start", thursday, time.Monday, "2026-10-12"},
+		{"on the first day", time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), time.Monday, "2026-10-12"},
+		{"sunday in a monday week", time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC), time.Monday, "2026-10-12"},
+		{"across a month", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), time.Monday, "2026-10-26"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StartOfWeek(tt.t, tt.first)
+			if got.Format(constants.DateFormat) != tt.want || got.Hour() != 0 {
+				t.Errorf("StartOfWeek() = %v, want midnight on %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeekNumber(t *testing.T) {
+	tests := []struct {
+		date  string
+		first time.Weekday
+		want  int
+	}{
+		// 2026 starts on a Thursday
+		{"2026-01-01", time.Sunday, 1},
+		{"2026-01-03", time.Sunday, 1},
+		{"2026-01-04", time.Sunday, 2},
+		{"2026-01-01", time.Monday, 1},
+		{"2026-01-05", time.Monday, 2},
+		{"2026-10-17", time.Monday, 42},
+		// 2027 starts on a Friday, which is still in ISO week 53 of 2026
+		{"2027-01-01", time.Monday, 53},
+		{"2027-01-01", time.Sunday, 1},
+		{"2027-01-03", time.Sunday, 2},
+	}
+	for _, tt := range tests {
+		date, err := time.Parse(constants.DateFormat, tt.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := WeekNumber(date, tt.first); got != tt.want {
+			t.Errorf("WeekNumber(%s, %v) = %d, want %d", tt.date, tt.first, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+// Overlap is a pair of live appointment slots in a plan that overlap, given
+// as indexes into the plan's slots. First starts no later than Second.
+type Overlap struct {
+	First  int
+	Second int
+}
+
+// Resolution is one way to fix an overlap, with the plan's slots as they
+// are after the fix
+type Resolution struct {
+	Description string
+	Slots       []models.Slot
+}
+
+// AppointmentOverlaps finds the pairs of live slots for appointments that
+// overlap in plan, in the order they start
+func AppointmentOverlaps(plan models.DayPlan, tasks []models.Task, window models.DayWindow) []Overlap {
+	kinds := make(map[string]constants.TaskKind, len(tasks))
+	for _, task := range tasks {
+		kinds[task.ID] = task.Kind
+	}
+
+	var idx []int
+	for i, slot := range plan.Slots {
+		if slot.DeletedAt == nil && kinds[slot.TaskID] == constants.TaskKindAppointment {
+			idx = append(idx, i)
+		}
+	}
+	starts := make(map[int]int, len(idx))
+	for _, i := range idx {
+		starts[i], _ = window.Minutes(plan.Slots[i].Start)
+	}
+
+	var overlaps []Overlap
+	for a := 0; a < len(idx); a++ {
+		for b := a + 1; b < len(idx); b++ {
+			first, second := idx[a], idx[b]
+			if starts[second] < starts[first] {
+				first, second = second, first
+			}
+			s1, s2 := plan.Slots[first], plan.Slots[second]
+			if rangesOverlap(window, s1.Start, s1.End, s2.Start, s2.End) {
+				overlaps = append(overlaps, Overlap{First: first, Second: second})
+			}
+		}
+	}
+	return overlaps
+}
+
+// Resolutions proposes the fixes for an overlap in plan: shifting either
+// appointment clear of the other, shortening either to end where the other
+// begins, or leaving either out of the day. Shifts that would push an
+// appointment out of the day window aren't offered. name gives the task
+// name to describe each fix with.
+func Resolutions(plan models.DayPlan, o Overlap, window models.DayWindow, name func(taskID string) string) []Resolution {
+	first, second := plan.Slots[o.First], plan.Slots[o.Second]
+	s1, e1, err := window.Range(first.Start, first.End)
+	if err != nil {
+		return nil
+	}
+	s2, e2, err := window.Range(second.Start, second.End)
+	if err != nil {
+		return nil
+	}
+
+	var res []Resolution
+	change := func(i, start, end int, format string) {
+		slots := append([]models.Slot(nil), plan.Slots...)
+		slots[i].Start, slots[i].End = clock(start), clock(end)
+		res = append(res, Resolution{
+			Description: fmt.Sprintf(format, name(slots[i].TaskID), slots[i].Start, slots[i].End),
+			Slots:       slots,
+		})
+	}
+
+	if end := e1 + e2 - s2; end <= window.End {
+		change(o.Second, e1, end, "Shift %s to %s–%s")
+	}
+	if start := s2 - (e1 - s1); start >= window.Start {
+		change(o.First, start, s2, "Shift %s to %s–%s")
+	}
+	if s2 > s1 {
+		change(o.First, s1, s2, "Shorten %s to %s–%s")
+	}
+	if e1 < e2 {
+		change(o.Second, e1, e2, "Shorten %s to %s–%s")
+	}
+	for _, i := range []int{o.First, o.Second} {
+		slots := append([]models.Slot(nil), plan.Slots[:i]...)
+		slots = append(slots, plan.Slots[i+1:]...)
+		slot := plan.Slots[i]
+		res = append(res, Resolution{
+			Description: fmt.Sprintf("Skip %s (%s–%s) for the day", name(slot.TaskID), slot.Start, slot.End),
+			Slots:       slots,
+		})
+	}
+	return res
+}
+
+// clock formats minutes on the plan day as HH:MM
+func clock(minutes int) string {
+	minutes %= models.MinutesPerDay
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
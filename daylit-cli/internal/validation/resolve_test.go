@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+)
+
+func TestAppointmentOverlaps(t *testing.T) {
+	tasks := []models.Task{
+		{ID: "dentist", Name: "Dentist", Kind: constants.TaskKindAppointment},
+		{ID: "call", Name: "Call", Kind: constants.TaskKindAppointment},
+		{ID: "read", Name: "Read", Kind: constants.TaskKindFlexible},
+	}
+	deleted := "2026-11-02T08:00:00Z"
+	plan := models.DayPlan{Date: "2026-11-02", Slots: []models.Slot{
+		{TaskID: "call", Start: "09:30", End: "10:30"},
+		{TaskID: "dentist", Start: "09:00", End: "10:00"},
+		{TaskID: "read", Start: "09:00", End: "09:30"},
+		{TaskID: "call", Start: "09:45", End: "10:15", DeletedAt: &deleted},
+	}}
+	window, _ := models.ParseDayWindow("07:00", "22:00")
+
+	got := AppointmentOverlaps(plan, tasks, window)
+	want := []Overlap{{First: 1, Second: 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AppointmentOverlaps() = %v, want %v", got, want)
+	}
+}
+
+func TestResolutions(t *testing.T) {
+	names := map[string]string{"dentist": "Dentist", "call": "Call"}
+	name := func(id string) string { return names[id] }
+	plan := models.DayPlan{Date: "2026-11-02", Slots: []models.Slot{
+		{TaskID: "dentist", Start: "09:00", End: "10:00"},
+		{TaskID: "call", Start: "09:30", End: "10:30"},
+	}}
+
+	tests := []struct {
+		name     string
+		dayStart string
+		dayEnd   string
+		want     []string
+	}{
+		{
+			name:     "all fixes",
+			dayStart: "07:00",
+			dayEnd:   "22:00",
+			want: []string{
+				"Shift Call to 10:00–11:00",
+				"Shift Dentist to 08:30–09:30",
+				"Shorten Dentist to 09:00–09:30",
+				"Shorten Call to 10:00–10:30",
+				"Skip Dentist (09:00–10:00) for the day",
+				"Skip Call (09:30–10:30) for the day",
+			},
+		},
+		{
+			name:     "shifts outside the day window left out",
+			dayStart: "09:00",
+			dayEnd:   "10:30",
+			want: []string{
+				"Shorten Dentist to 09:00–09:30",
+				"Shorten Call to 10:00–10:30",
+				"Skip Dentist (09:00–10:00) for the day",
+				"Skip Call (09:30–10:30) for the day",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := models.ParseDayWindow(tt.dayStart, tt.dayEnd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res := Resolutions(plan, Overlap{First: 0, Second: 1}, window, name)
+			var got []string
+			for _, r := range res {
+				got = append(got, r.Description)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Resolutions() = %q, want %q", got, tt.want)
+			}
+			for _, r := range res {
+				p := models.DayPlan{Date: plan.Date, Slots: r.Slots}
+				tasks := []models.Task{
+					{ID: "dentist", Kind: constants.TaskKindAppointment},
+					{ID: "call", Kind: constants.TaskKindAppointment},
+				}
+				if overlaps := AppointmentOverlaps(p, tasks, window); len(overlaps) > 0 {
+					t.Errorf("%q leaves overlaps %v", r.Description, overlaps)
+				}
+			}
+			if plan.Slots[0].End != "10:00" || plan.Slots[1].Start != "09:30" {
+				t.Errorf("Resolutions changed the plan's slots: %v", plan.Slots)
+			}
+		})
+	}
+}
@@ -50,7 +50,14 @@ func (vr *ValidationResult) FormatReport() string {
 }
 
 // Validator validates tasks and plans for conflicts
-type Validator struct{}
+type Validator struct {
+	// MinFreeMinutes is the free time a plan must leave in the day, with
+	// leisure tasks counting as free. Zero doesn't check.
+	MinFreeMinutes int
+	// Recovery is the rest demanding slots must leave before the next
+	// demanding slot. A zero gap doesn't check.
+	Recovery models.Recovery
+}
 
 // New creates a new Validator
 func New() *Validator {
@@ -138,11 +145,11 @@ func (v *Validator) ValidateTasksForDate(tasks []models.Task, planDate *time.Tim
 			}
 		}
 
-		// Check for negative duration in fixed appointments
-		if task.FixedStart != "" && task.FixedEnd != "" {
-			startMin, err1 := parseTimeToMinutes(task.FixedStart)
-			endMin, err2 := parseTimeToMinutes(task.FixedEnd)
-			if err1 == nil && err2 == nil && endMin < startMin {
+		// Check for negative duration in fixed appointments; appointments may
+		// cross midnight, e.g. 23:00-00:30
+		if task.FixedStart != "" && task.FixedEnd != "" &&
+			isValidTimeFormat(task.FixedStart) && isValidTimeFormat(task.FixedEnd) {
+			if _, _, err := (models.DayWindow{}).Range(task.FixedStart, task.FixedEnd); err != nil {
 				result.Conflicts = append(result.Conflicts, Conflict{
 					Type:        constants.ConflictInvalidDateTime,
 					Description: fmt.Sprintf("Task \"%s\" has end time (%s) before start time (%s)", task.Name, task.FixedEnd, task.FixedStart),
@@ -229,30 +236,34 @@ func (v *Validator) ValidatePlan(plan models.DayPlan, tasks []models.Task, daySt
 	}
 
 	// Parse day boundaries
-	dayStartMinutes, err := parseTimeToMinutes(dayStart)
-	if err != nil {
+	dayStartValid := isValidTimeFormat(dayStart)
+	if !dayStartValid {
 		result.Conflicts = append(result.Conflicts, Conflict{
 			Type:        constants.ConflictInvalidDateTime,
 			Description: fmt.Sprintf("Invalid day start time: %s", dayStart),
 		})
 	}
 
-	dayEndMinutes, err := parseTimeToMinutes(dayEnd)
-	if err != nil {
+	dayEndValid := isValidTimeFormat(dayEnd)
+	if !dayEndValid {
 		result.Conflicts = append(result.Conflicts, Conflict{
 			Type:        constants.ConflictInvalidDateTime,
 			Description: fmt.Sprintf("Invalid day end time: %s", dayEnd),
 		})
 	}
 
-	wakingWindowMinutes := dayEndMinutes - dayStartMinutes
-	if wakingWindowMinutes <= 0 {
-		result.Conflicts = append(result.Conflicts, Conflict{
-			Type:        constants.ConflictInvalidDateTime,
-			Description: fmt.Sprintf("Invalid waking window: day_start (%s) must be before day_end (%s)", dayStart, dayEnd),
-		})
+	// A day_end before day_start ends the day after midnight
+	window, err := models.ParseDayWindow(dayStart, dayEnd)
+	if err != nil {
+		if dayStartValid && dayEndValid {
+			result.Conflicts = append(result.Conflicts, Conflict{
+				Type:        constants.ConflictInvalidDateTime,
+				Description: fmt.Sprintf("Invalid waking window: day_start (%s) must differ from day_end (%s)", dayStart, dayEnd),
+			})
+		}
 		return result // Can't continue validation
 	}
+	wakingWindowMinutes := window.End - window.Start
 
 	// Check each slot
 	totalPlannedMinutes := 0
@@ -287,18 +298,14 @@ func (v *Validator) ValidatePlan(plan models.DayPlan, tasks []models.Task, daySt
 			})
 		}
 
-		// Calculate slot duration
-		slotStart, err := parseTimeToMinutes(slot.Start)
-		if err != nil {
-			continue // Already reported as invalid time
-		}
-		slotEnd, err := parseTimeToMinutes(slot.End)
-		if err != nil {
+		if !isValidTimeFormat(slot.Start) || !isValidTimeFormat(slot.End) {
 			continue // Already reported as invalid time
 		}
 
-		// Validate that the slot end time is not before the start time
-		if slotEnd < slotStart {
+		// Calculate slot duration. Slots may cross midnight, but an end
+		// too far before the start is a mistake.
+		slotStart, slotEnd, err := window.Range(slot.Start, slot.End)
+		if err != nil {
 			result.Conflicts = append(result.Conflicts, Conflict{
 				Type:        constants.ConflictInvalidDateTime,
 				Description: fmt.Sprintf("%s: Slot end time '%s' is before start time '%s'", formatDate(planDate), slot.End, slot.Start),
@@ -320,16 +327,14 @@ func (v *Validator) ValidatePlan(plan models.DayPlan, tasks []models.Task, daySt
 		}
 	}
 
-	sort.Slice(nonDeletedSlots, func(i, j int) bool {
-		return nonDeletedSlots[i].Start < nonDeletedSlots[j].Start
-	})
+	window.SortSlots(nonDeletedSlots)
 
 	for i := 0; i < len(nonDeletedSlots); i++ {
 		for j := i + 1; j < len(nonDeletedSlots); j++ {
 			slot1 := nonDeletedSlots[i]
 			slot2 := nonDeletedSlots[j]
 
-			if timesOverlap(slot1.Start, slot1.End, slot2.Start, slot2.End) {
+			if rangesOverlap(window, slot1.Start, slot1.End, slot2.Start, slot2.End) {
 				task1Name := "Unknown"
 				task2Name := "Unknown"
 				if t, ok := taskMap[slot1.TaskID]; ok {
@@ -351,6 +356,61 @@ func (v *Validator) ValidatePlan(plan models.DayPlan, tasks []models.Task, daySt
 		}
 	}
 
+	// Check that no other slot takes an appointment's prep and travel time
+	for _, appt := range nonDeletedSlots {
+		task := taskMap[appt.TaskID]
+		before, after := task.Padding()
+		if before == 0 && after == 0 {
+			continue
+		}
+		start, end, err := window.Range(appt.Start, appt.End)
+		if err != nil {
+			continue
+		}
+		padStart, padEnd := max(start-before, min(start, window.Start)), min(end+after, max(end, window.End))
+		for _, slot := range nonDeletedSlots {
+			s, e, err := window.Range(slot.Start, slot.End)
+			// Overlaps with the appointment itself are reported above
+			if err != nil || (s < end && start < e) || !(s < padEnd && padStart < e) {
+				continue
+			}
+			result.Conflicts = append(result.Conflicts, Conflict{
+				Type: constants.ConflictPaddingOverlap,
+				Description: fmt.Sprintf("%s: %s-%s \"%s\" falls in the prep and travel time of \"%s\"",
+					formatDate(planDate), slot.Start, slot.End, taskName(taskMap, slot.TaskID), task.Name),
+				Date:      plan.Date,
+				Items:     []string{taskName(taskMap, slot.TaskID), task.Name},
+				TimeRange: fmt.Sprintf("%s-%s", clock(padStart), clock(padEnd)),
+			})
+		}
+	}
+
+	// Check that demanding slots leave the recovery gap before the next one
+	if v.Recovery.Enabled() {
+		prev := -1
+		prevEnd := 0
+		for i, slot := range nonDeletedSlots {
+			start, end, err := window.Range(slot.Start, slot.End)
+			if err != nil || !v.Recovery.Demanding(taskMap[slot.TaskID], end-start) {
+				continue
+			}
+			// Overlapping slots are reported above
+			if prev >= 0 && start >= prevEnd && start-prevEnd < v.Recovery.GapMinutes {
+				first := nonDeletedSlots[prev]
+				result.Conflicts = append(result.Conflicts, Conflict{
+					Type: constants.ConflictNoRecovery,
+					Description: fmt.Sprintf("%s: %s-%s \"%s\" leaves %dm before \"%s\", less than the %dm recovery",
+						formatDate(planDate), first.Start, first.End, taskName(taskMap, first.TaskID), start-prevEnd,
+						taskName(taskMap, slot.TaskID), v.Recovery.GapMinutes),
+					Date:      plan.Date,
+					Items:     []string{taskName(taskMap, first.TaskID), taskName(taskMap, slot.TaskID)},
+					TimeRange: fmt.Sprintf("%s-%s", first.End, slot.Start),
+				})
+			}
+			prev, prevEnd = i, end
+		}
+	}
+
 	// Check if plan exceeds waking window
 	if totalPlannedMinutes > wakingWindowMinutes {
 		hoursScheduled := float64(totalPlannedMinutes) / 60.0
@@ -376,40 +436,50 @@ func (v *Validator) ValidatePlan(plan models.DayPlan, tasks []models.Task, daySt
 		})
 	}
 
+	// Check that the plan leaves the minimum free time
+	if v.MinFreeMinutes > 0 {
+		free := models.PlanFreeTime(plan, window, func(taskID string) bool { return taskMap[taskID].Leisure })
+		if free.Free < v.MinFreeMinutes {
+			result.Conflicts = append(result.Conflicts, Conflict{
+				Type: constants.ConflictNotEnoughFreeTime,
+				Description: fmt.Sprintf("%s: %.1fh free time is below the %.1fh minimum",
+					formatDate(planDate), float64(free.Free)/60.0, float64(v.MinFreeMinutes)/60.0),
+				Date: plan.Date,
+			})
+		}
+	}
+
 	return result
 }
 
 // Helper functions
 
+// taskName is the name of the task with id, or "Unknown" if it is missing
+func taskName(taskMap map[string]models.Task, id string) string {
+	if t, ok := taskMap[id]; ok {
+		return t.Name
+	}
+	return "Unknown"
+}
+
 func isValidTimeFormat(timeStr string) bool {
 	_, err := time.Parse(constants.TimeFormat, timeStr)
 	return err == nil
 }
 
-func parseTimeToMinutes(timeStr string) (int, error) {
-	t, err := time.Parse("15:04", timeStr)
-	if err != nil {
-		return 0, err
-	}
-	return t.Hour()*60 + t.Minute(), nil
-}
-
 // timesOverlap checks if two time ranges overlap
 // Assumes all times are in HH:MM format
 func timesOverlap(start1, end1, start2, end2 string) bool {
-	s1, err := parseTimeToMinutes(start1)
-	if err != nil {
-		return false
-	}
-	e1, err := parseTimeToMinutes(end1)
-	if err != nil {
-		return false
-	}
-	s2, err := parseTimeToMinutes(start2)
+	return rangesOverlap(models.DayWindow{}, start1, end1, start2, end2)
+}
+
+// rangesOverlap checks if two time ranges overlap on the day described by window
+func rangesOverlap(window models.DayWindow, start1, end1, start2, end2 string) bool {
+	s1, e1, err := window.Range(start1, end1)
 	if err != nil {
 		return false
 	}
-	e2, err := parseTimeToMinutes(end2)
+	s2, e2, err := window.Range(start2, end2)
 	if err != nil {
 		return false
 	}
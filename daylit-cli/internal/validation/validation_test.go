@@ -295,6 +295,124 @@ func TestValidatePlan_Overcommitted(t *testing.T) {
 	}
 }
 
+func TestValidatePlan_NotEnoughFreeTime(t *testing.T) {
+	tasks := []models.Task{
+		{ID: "work", Name: "Work", Active: true},
+		{ID: "walk", Name: "Walk", Active: true, Leisure: true},
+	}
+
+	// Waking window is 08:00-18:00 (10 hours); work takes 7.5h and the
+	// one-hour walk counts as free time, leaving 2.5h
+	plan := models.DayPlan{
+		Date: "2025-01-15",
+		Slots: []models.Slot{
+			{Start: "08:00", End: "12:00", TaskID: "work", Status: constants.SlotStatusPlanned},
+			{Start: "12:00", End: "13:00", TaskID: "walk", Status: constants.SlotStatusPlanned},
+			{Start: "13:00", End: "16:30", TaskID: "work", Status: constants.SlotStatusPlanned},
+		},
+	}
+
+	tests := []struct {
+		minFree int
+		want    bool
+	}{
+		{0, false},
+		{150, false},
+		{180, true},
+	}
+	for _, tt := range tests {
+		validator := New()
+		validator.MinFreeMinutes = tt.minFree
+		result := validator.ValidatePlan(plan, tasks, "08:00", "18:00")
+
+		found := false
+		for _, conflict := range result.Conflicts {
+			if conflict.Type == constants.ConflictNotEnoughFreeTime {
+				found = true
+			}
+		}
+		if found != tt.want {
+			t.Errorf("min free %dm: found free time conflict = %v, want %v (%v)", tt.minFree, found, tt.want, result.Conflicts)
+		}
+	}
+}
+
+func TestValidatePlan_Recovery(t *testing.T) {
+	tasks := []models.Task{
+		{ID: "gym", Name: "Gym", Active: true, EnergyBand: constants.EnergyHigh},
+		{ID: "code", Name: "Code", Active: true},
+		{ID: "email", Name: "Email", Active: true},
+	}
+	plan := models.DayPlan{
+		Date: "2025-01-15",
+		Slots: []models.Slot{
+			{Start: "09:00", End: "10:00", TaskID: "gym", Status: constants.SlotStatusPlanned},
+			{Start: "10:00", End: "10:20", TaskID: "email", Status: constants.SlotStatusPlanned},
+			{Start: "10:20", End: "12:20", TaskID: "code", Status: constants.SlotStatusPlanned},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		recovery models.Recovery
+		want     bool
+	}{
+		{"off", models.Recovery{}, false},
+		{"only high-energy tasks are demanding", models.Recovery{GapMinutes: 30}, false},
+		{"gap filled by a lighter task is too short", models.Recovery{GapMinutes: 30, LongerThan: 90}, true},
+		{"gap long enough", models.Recovery{GapMinutes: 20, LongerThan: 90}, false},
+	}
+	for _, tt := range tests {
+		validator := New()
+		validator.Recovery = tt.recovery
+		result := validator.ValidatePlan(plan, tasks, "08:00", "18:00")
+
+		var found []Conflict
+		for _, conflict := range result.Conflicts {
+			if conflict.Type == constants.ConflictNoRecovery {
+				found = append(found, conflict)
+			}
+		}
+		if (len(found) > 0) != tt.want {
+			t.Errorf("%s: found recovery conflict = %v, want %v (%v)", tt.name, found, tt.want, result.Conflicts)
+		}
+		if len(found) > 0 && found[0].TimeRange != "10:00-10:20" {
+			t.Errorf("%s: time range = %q, want the gap", tt.name, found[0].TimeRange)
+		}
+	}
+}
+
+func TestValidatePlan_PaddingOverlap(t *testing.T) {
+	tasks := []models.Task{
+		{ID: "dentist", Name: "Dentist", Kind: constants.TaskKindAppointment, Active: true, PrepMin: 15, TravelMin: 30},
+		{ID: "email", Name: "Email", Active: true},
+		{ID: "read", Name: "Read", Active: true},
+	}
+	plan := models.DayPlan{
+		Date: "2025-01-15",
+		Slots: []models.Slot{
+			{Start: "09:00", End: "09:30", TaskID: "email", Status: constants.SlotStatusPlanned},
+			{Start: "10:00", End: "11:00", TaskID: "dentist", Status: constants.SlotStatusPlanned},
+			{Start: "11:30", End: "12:00", TaskID: "read", Status: constants.SlotStatusPlanned},
+		},
+	}
+
+	// Email runs into the prep time; Read starts once the way back is done
+	result := New().ValidatePlan(plan, tasks, "08:00", "18:00")
+	var found []Conflict
+	for _, conflict := range result.Conflicts {
+		if conflict.Type == constants.ConflictPaddingOverlap {
+			found = append(found, conflict)
+		}
+	}
+	if len(found) != 1 {
+		t.Fatalf("expected 1 padding conflict, got %+v", result.Conflicts)
+	}
+	if found[0].Items[0] != "Email" || found[0].TimeRange != "09:15-11:30" {
+		t.Errorf("unexpected conflict %+v", found[0])
+	}
+}
+
 func TestValidatePlan_InvalidDate(t *testing.T) {
 	validator := New()
 
@@ -1,6 +1,27 @@
+// Package migrations holds the schema migrations of every storage backend,
+// compiled into the binary so a daylit build never depends on files next to
+// it.
 package migrations
 
-import "embed"
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
 
-//go:embed sqlite/*.sql postgres/*.sql
+//go:embed sqlite/*.sql postgres/*.sql mysql/*.sql
 var FS embed.FS
+
+// Backend returns the migrations of one backend ("sqlite", "postgres" or
+// "mysql"), with the files at its root as the migration runner expects
+func Backend(name string) (fs.FS, error) {
+	sub, err := fs.Sub(FS, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access %s migrations: %w", name, err)
+	}
+	files, err := fs.Glob(sub, "*.sql")
+	if err != nil || len(files) == 0 {
+		return nil, fmt.Errorf("no %s migrations are embedded in this build", name)
+	}
+	return sub, nil
+}
@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"io/fs"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// latestVersion returns the highest migration number of a backend
+func latestVersion(t *testing.T, backend string) int {
+	t.Helper()
+
+	sub, err := Backend(backend)
+	if err != nil {
+		t.Fatalf("Backend(%q) failed: %v", backend, err)
+	}
+	files, err := fs.Glob(sub, "*.sql")
+	if err != nil {
+		t.Fatalf("failed to list %s migrations: %v", backend, err)
+	}
+
+	latest := 0
+	for _, name := range files {
+		version, err := strconv.Atoi(strings.SplitN(name, "_", 2)[0])
+		if err != nil {
+			t.Fatalf("invalid migration filename %s/%s", backend, name)
+		}
+		latest = max(latest, version)
+	}
+	return latest
+}
+
+// TestBackendsInSync guards against a migration added to one backend only:
+// every backend must be embedded and end at the same schema version
+func TestBackendsInSync(t *testing.T) {
+	want := latestVersion(t, "sqlite")
+	for _, backend := range []string{"postgres", "mysql"} {
+		if got := latestVersion(t, backend); got != want {
+			t.Errorf("%s migrations end at version %d, sqlite at %d", backend, got, want)
+		}
+	}
+}
+
+func TestBackendUnknown(t *testing.T) {
+	if _, err := Backend("oracle"); err == nil {
+		t.Error("Backend should fail for a backend without migrations")
+	}
+}
@@ -0,0 +1,206 @@
+-- Migration 028: Initial schema
+-- The MySQL/MariaDB backend was added when the SQLite and PostgreSQL schemas
+-- were at version 28, so it starts there with the same tables in one step.
+-- Later migrations are numbered to match the other backends.
+--
+-- Identifiers, dates and times are VARCHAR so they can be keys and indexed;
+-- free text is TEXT. Tables use a binary collation so names, ordering and
+-- uniqueness compare exactly as they do in the other backends. Full-text
+-- search reads the source tables directly, so there is no search index.
+
+CREATE TABLE IF NOT EXISTS settings (
+    `key` VARCHAR(191) PRIMARY KEY,
+    value TEXT
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id                         VARCHAR(191) PRIMARY KEY,
+    name                       TEXT,
+    kind                       VARCHAR(32),
+    duration_min               INTEGER,
+    earliest_start             VARCHAR(16),
+    latest_end                 VARCHAR(16),
+    fixed_start                VARCHAR(16),
+    fixed_end                  VARCHAR(16),
+    recurrence_type            VARCHAR(32),
+    recurrence_interval        INTEGER,
+    recurrence_weekdays        TEXT,
+    recurrence_month_day       INTEGER DEFAULT NULL,
+    recurrence_week_occurrence INTEGER DEFAULT NULL,
+    recurrence_month           INTEGER DEFAULT NULL,
+    recurrence_day_of_week     INTEGER DEFAULT NULL,
+    priority                   INTEGER,
+    energy_band                VARCHAR(32),
+    active                     BOOLEAN,
+    last_done                  VARCHAR(32),
+    success_streak             INTEGER,
+    avg_actual_duration        DOUBLE,
+    project_id                 VARCHAR(191) NOT NULL DEFAULT '',
+    context                    VARCHAR(191) NOT NULL DEFAULT '',
+    notify_start_disabled      BOOLEAN NOT NULL DEFAULT FALSE,
+    notify_offset_min          INTEGER NULL, -- NULL uses the global setting
+    notify_message             TEXT NOT NULL,
+    pool_id                    VARCHAR(191) NOT NULL DEFAULT '',
+    nice_to_have               BOOLEAN NOT NULL DEFAULT FALSE,
+    version                    INTEGER NOT NULL DEFAULT 1,
+    deleted_at                 VARCHAR(64) NULL
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS plans (
+    date        VARCHAR(10) NOT NULL,   -- YYYY-MM-DD
+    revision    INTEGER NOT NULL DEFAULT 1,
+    accepted_at VARCHAR(64) NULL,
+    deleted_at  VARCHAR(64) NULL,
+    version     INTEGER NOT NULL DEFAULT 1,
+    note        TEXT NOT NULL,
+    PRIMARY KEY (date, revision)
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS slots (
+    id                  BIGINT AUTO_INCREMENT PRIMARY KEY,
+    plan_date           VARCHAR(10) NOT NULL,
+    plan_revision       INTEGER NOT NULL DEFAULT 1,
+    start_time          VARCHAR(16),
+    end_time            VARCHAR(16),
+    task_id             VARCHAR(191),
+    status              VARCHAR(32),
+    feedback_rating     VARCHAR(32),
+    feedback_note       TEXT,
+    deleted_at          VARCHAR(64) NULL,
+    last_notified_start VARCHAR(64) NULL,
+    last_notified_end   VARCHAR(64) NULL,
+    actual_start        VARCHAR(16) NULL,
+    actual_end          VARCHAR(16) NULL,
+    FOREIGN KEY (plan_date, plan_revision) REFERENCES plans(date, revision),
+    FOREIGN KEY (task_id) REFERENCES tasks(id)
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE INDEX idx_slots_plan ON slots(plan_date, plan_revision, start_time);
+CREATE INDEX idx_slots_task ON slots(task_id);
+
+CREATE TABLE IF NOT EXISTS habits (
+    id          VARCHAR(191) PRIMARY KEY,   -- UUID
+    name        VARCHAR(191) NOT NULL UNIQUE,
+    created_at  VARCHAR(64) NOT NULL,       -- ISO8601
+    archived_at VARCHAR(64) NULL,           -- archived but not deleted
+    deleted_at  VARCHAR(64) NULL            -- soft-delete marker
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS habit_entries (
+    id         VARCHAR(191) PRIMARY KEY,
+    habit_id   VARCHAR(191) NOT NULL,
+    day        VARCHAR(10) NOT NULL,        -- YYYY-MM-DD
+    note       TEXT NOT NULL,
+    created_at VARCHAR(64) NOT NULL,
+    updated_at VARCHAR(64) NOT NULL,
+    deleted_at VARCHAR(64) NULL,
+    UNIQUE (habit_id, day),
+    FOREIGN KEY (habit_id) REFERENCES habits(id)
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE INDEX idx_habit_entries_day ON habit_entries(day);
+
+CREATE TABLE IF NOT EXISTS ot_entries (
+    id         VARCHAR(191) PRIMARY KEY,
+    day        VARCHAR(10) NOT NULL UNIQUE,
+    title      TEXT NOT NULL,
+    note       TEXT NOT NULL,
+    created_at VARCHAR(64) NOT NULL,
+    updated_at VARCHAR(64) NOT NULL,
+    deleted_at VARCHAR(64) NULL
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS alerts (
+    id                   VARCHAR(191) PRIMARY KEY,
+    message              TEXT NOT NULL,
+    time                 VARCHAR(16) NOT NULL,
+    date                 VARCHAR(10),
+    recurrence_type      VARCHAR(32),
+    recurrence_interval  INTEGER,
+    recurrence_weekdays  TEXT,
+    active               BOOLEAN DEFAULT TRUE,
+    last_sent            DATETIME(6) NULL,
+    created_at           DATETIME(6) NOT NULL,
+    recurrence_month_day INTEGER NOT NULL DEFAULT 0,
+    recurrence_month     INTEGER NOT NULL DEFAULT 0,
+    recurrence_cron      VARCHAR(191) NOT NULL DEFAULT '',
+    task_id              VARCHAR(191) NOT NULL DEFAULT '',
+    travel_min           INTEGER NOT NULL DEFAULT 0
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS projects (
+    id                    VARCHAR(191) PRIMARY KEY,         -- UUID
+    name                  VARCHAR(191) NOT NULL UNIQUE,
+    target_hours_per_week DOUBLE NOT NULL DEFAULT 0,
+    color                 VARCHAR(16) NOT NULL DEFAULT '', -- #RRGGBB, empty for none
+    created_at            VARCHAR(64) NOT NULL              -- ISO8601
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS day_templates (
+    id         VARCHAR(191) PRIMARY KEY,    -- UUID
+    name       VARCHAR(191) NOT NULL UNIQUE,
+    created_at VARCHAR(64) NOT NULL         -- ISO8601
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS day_template_slots (
+    id          BIGINT AUTO_INCREMENT PRIMARY KEY,
+    template_id VARCHAR(191) NOT NULL,
+    start_time  VARCHAR(16) NOT NULL,       -- HH:MM
+    end_time    VARCHAR(16) NOT NULL,       -- HH:MM
+    task_id     VARCHAR(191) NOT NULL,
+    FOREIGN KEY (template_id) REFERENCES day_templates(id) ON DELETE CASCADE
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS notification_log (
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    sent_at    VARCHAR(64) NOT NULL,             -- ISO8601, UTC
+    kind       VARCHAR(32) NOT NULL,             -- block_start, block_end, alert, morning_plan
+    plan_date  VARCHAR(10) NOT NULL DEFAULT '',  -- YYYY-MM-DD
+    slot_start VARCHAR(16) NOT NULL DEFAULT '',  -- HH:MM
+    task_id    VARCHAR(191) NOT NULL DEFAULT '',
+    alert_id   VARCHAR(191) NOT NULL DEFAULT '',
+    message    TEXT NOT NULL,
+    channel    VARCHAR(32) NOT NULL,             -- tray, dry_run
+    success    BOOLEAN NOT NULL,
+    error      TEXT NOT NULL
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE INDEX idx_notification_log_sent_at ON notification_log(sent_at);
+
+CREATE TABLE IF NOT EXISTS vacations (
+    id         VARCHAR(191) PRIMARY KEY,    -- UUID
+    start_date VARCHAR(10) NOT NULL,        -- YYYY-MM-DD
+    end_date   VARCHAR(10) NOT NULL,        -- YYYY-MM-DD, inclusive
+    note       TEXT NOT NULL,
+    created_at VARCHAR(64) NOT NULL         -- ISO8601
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE INDEX idx_vacations_dates ON vacations(start_date, end_date);
+
+CREATE TABLE IF NOT EXISTS slot_reminders (
+    id         VARCHAR(191) PRIMARY KEY,    -- UUID
+    plan_date  VARCHAR(10) NOT NULL,        -- YYYY-MM-DD
+    slot_start VARCHAR(16) NOT NULL,        -- HH:MM
+    task_id    VARCHAR(191) NOT NULL,
+    offset_min INTEGER NOT NULL DEFAULT 0,
+    message    TEXT NOT NULL,
+    sent_at    VARCHAR(64) NULL,            -- ISO8601, NULL until sent
+    created_at VARCHAR(64) NOT NULL         -- ISO8601
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE INDEX idx_slot_reminders_plan_date ON slot_reminders(plan_date);
+
+CREATE TABLE IF NOT EXISTS task_pools (
+    id         VARCHAR(191) PRIMARY KEY,    -- UUID
+    name       VARCHAR(191) NOT NULL UNIQUE,
+    strategy   VARCHAR(32) NOT NULL DEFAULT 'round_robin',
+    created_at VARCHAR(64) NOT NULL         -- ISO8601
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS inbox (
+    id         VARCHAR(191) PRIMARY KEY,    -- UUID
+    text       TEXT NOT NULL,
+    created_at VARCHAR(64) NOT NULL         -- ISO8601
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE INDEX idx_inbox_created_at ON inbox(created_at);
@@ -0,0 +1,20 @@
+-- Migration 029: Add plan amendments
+-- Targeted changes made to an accepted plan revision in place: a slot added,
+-- moved or removed. Each row is one change, kept as an audit trail of how the
+-- day diverged from the plan that was accepted.
+
+CREATE TABLE IF NOT EXISTS plan_amendments (
+    id            BIGINT AUTO_INCREMENT PRIMARY KEY,
+    plan_date     VARCHAR(10) NOT NULL,         -- YYYY-MM-DD
+    plan_revision INTEGER NOT NULL,
+    kind          VARCHAR(16) NOT NULL,         -- add, move or remove
+    task_id       VARCHAR(191) NOT NULL,
+    from_start    VARCHAR(16) NOT NULL DEFAULT '', -- HH:MM, empty for add
+    from_end      VARCHAR(16) NOT NULL DEFAULT '',
+    to_start      VARCHAR(16) NOT NULL DEFAULT '', -- HH:MM, empty for remove
+    to_end        VARCHAR(16) NOT NULL DEFAULT '',
+    reason        TEXT NOT NULL,
+    created_at    VARCHAR(64) NOT NULL          -- ISO8601
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE INDEX idx_plan_amendments_plan ON plan_amendments(plan_date, plan_revision);
@@ -0,0 +1,5 @@
+-- Migration 030: Add plan locks
+-- A locked plan's slots that start before locked_until (HH:MM) are committed:
+-- regenerating or reflowing the day keeps them in place. Empty means unlocked.
+
+ALTER TABLE plans ADD COLUMN locked_until VARCHAR(5) NOT NULL DEFAULT '';
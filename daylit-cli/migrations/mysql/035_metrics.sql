@@ -0,0 +1,20 @@
+-- Migration 035: Add scheduling metrics
+-- When metrics are turned on, the notify daemon records each day's scheduling
+-- quality indicators here every night: whether the plan was accepted, how
+-- many slots were done, how far tracked slots drifted and the feedback mix.
+-- They stay in the local database and feed 'daylit stats' and 'daylit optimize'.
+
+CREATE TABLE IF NOT EXISTS metrics (
+    date          VARCHAR(10) PRIMARY KEY,  -- YYYY-MM-DD
+    has_plan      BOOLEAN NOT NULL DEFAULT FALSE,
+    accepted      BOOLEAN NOT NULL DEFAULT FALSE,
+    planned_slots INTEGER NOT NULL DEFAULT 0,
+    done_slots    INTEGER NOT NULL DEFAULT 0,
+    skipped_slots INTEGER NOT NULL DEFAULT 0,
+    tracked_slots INTEGER NOT NULL DEFAULT 0,
+    drift_minutes INTEGER NOT NULL DEFAULT 0,
+    on_track      INTEGER NOT NULL DEFAULT 0,
+    too_much      INTEGER NOT NULL DEFAULT 0,
+    unnecessary   INTEGER NOT NULL DEFAULT 0,
+    computed_at   VARCHAR(64) NOT NULL      -- ISO8601
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
@@ -0,0 +1,8 @@
+-- Migration 037: Add recurrence bounds
+-- A recurring task can stop after a last date (until) or after a number of
+-- occurrences (count), counted from the date the rule starts. Dates are
+-- YYYY-MM-DD; empty and 0 leave the rule unbounded.
+
+ALTER TABLE tasks ADD COLUMN recurrence_start VARCHAR(10) NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN recurrence_until VARCHAR(10) NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN recurrence_count INTEGER NOT NULL DEFAULT 0;
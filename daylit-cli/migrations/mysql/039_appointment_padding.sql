@@ -0,0 +1,6 @@
+-- Migration 039: Add prep and travel time to appointments
+-- Prep minutes are spent getting ready before an appointment and travel
+-- minutes getting there and back; plans keep both free around the block.
+
+ALTER TABLE tasks ADD COLUMN prep_min INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN travel_min INTEGER NOT NULL DEFAULT 0;
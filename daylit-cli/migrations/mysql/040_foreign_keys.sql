@@ -0,0 +1,9 @@
+-- Migration 040: Remove habit entries with their habit
+-- Slots already can't outlive their task: the key on slots.task_id rejects
+-- removing a task while slots refer to it. Habit entries now go with their
+-- habit instead of blocking its removal.
+
+ALTER TABLE habit_entries DROP FOREIGN KEY habit_entries_ibfk_1;
+ALTER TABLE habit_entries
+    ADD CONSTRAINT habit_entries_habit_id_fkey
+    FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE;
@@ -0,0 +1,23 @@
+-- Migration 041: Add notification outbox
+-- Notifications whose delivery failed, e.g. because the tray wasn't running,
+-- wait here to be retried by later `daylit notify` runs with exponential
+-- backoff. Items given up on stay, marked failed, for `daylit notify history`.
+
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id              BIGINT AUTO_INCREMENT PRIMARY KEY,
+    created_at      VARCHAR(64) NOT NULL,                  -- ISO8601, UTC
+    kind            VARCHAR(32) NOT NULL,
+    plan_date       VARCHAR(10) NOT NULL DEFAULT '',       -- YYYY-MM-DD
+    slot_start      VARCHAR(16) NOT NULL DEFAULT '',       -- HH:MM
+    task_id         VARCHAR(191) NOT NULL DEFAULT '',
+    alert_id        VARCHAR(191) NOT NULL DEFAULT '',
+    message         TEXT NOT NULL,
+    urgency         VARCHAR(16) NOT NULL DEFAULT '',
+    sound           VARCHAR(191) NOT NULL DEFAULT '',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at VARCHAR(64) NOT NULL,                  -- ISO8601, UTC
+    last_error      TEXT NOT NULL,
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending' -- pending, failed
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE INDEX idx_notification_outbox_due ON notification_outbox(status, next_attempt_at);
@@ -0,0 +1,12 @@
+-- Migration 042: Add notification claims
+-- Every slot and alert notification has an idempotency key naming its
+-- trigger and the day or time it fired for. `daylit notify` claims the key
+-- before sending, and the primary key lets only one of several runs that see
+-- the notification due at once, such as cron and the daemon, claim it.
+
+CREATE TABLE IF NOT EXISTS notification_claims (
+    claim_key  VARCHAR(255) PRIMARY KEY,
+    claimed_at VARCHAR(64) NOT NULL  -- ISO8601, UTC
+) DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE INDEX idx_notification_claims_claimed_at ON notification_claims(claimed_at);
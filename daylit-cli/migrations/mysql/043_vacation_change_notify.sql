@@ -0,0 +1,5 @@
+-- Migration 043: Notify listeners of vacation changes
+-- Only Postgres notifies listeners of changes (see its migration 016); this
+-- migration changes nothing here and keeps the backends at the same version.
+
+SELECT 1;
@@ -0,0 +1,114 @@
+-- Migration 010: Add full-text search index
+-- Indexes task names, habit names, OT titles/notes, and slot feedback notes
+-- in a tsvector column kept in sync by triggers on the source tables.
+
+CREATE TABLE IF NOT EXISTS search_index (
+    kind     TEXT NOT NULL,
+    ref_id   TEXT NOT NULL,
+    day      TEXT NOT NULL DEFAULT '',
+    title    TEXT NOT NULL DEFAULT '',
+    body     TEXT NOT NULL DEFAULT '',
+    document tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', title), 'A') ||
+        setweight(to_tsvector('simple', body), 'B')
+    ) STORED,
+    PRIMARY KEY (kind, ref_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_index_document ON search_index USING GIN (document);
+
+-- Tasks
+CREATE OR REPLACE FUNCTION search_index_tasks() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        DELETE FROM search_index WHERE kind = 'task' AND ref_id = OLD.id;
+    END IF;
+    IF TG_OP <> 'DELETE' AND NEW.deleted_at IS NULL THEN
+        INSERT INTO search_index (kind, ref_id, day, title, body)
+        VALUES ('task', NEW.id, '', COALESCE(NEW.name, ''), '')
+        ON CONFLICT (kind, ref_id) DO UPDATE SET title = EXCLUDED.title;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS search_index_tasks ON tasks;
+CREATE TRIGGER search_index_tasks AFTER INSERT OR UPDATE OR DELETE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION search_index_tasks();
+
+-- Habits
+CREATE OR REPLACE FUNCTION search_index_habits() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        DELETE FROM search_index WHERE kind = 'habit' AND ref_id = OLD.id;
+    END IF;
+    IF TG_OP <> 'DELETE' AND NEW.deleted_at IS NULL THEN
+        INSERT INTO search_index (kind, ref_id, day, title, body)
+        VALUES ('habit', NEW.id, '', NEW.name, '')
+        ON CONFLICT (kind, ref_id) DO UPDATE SET title = EXCLUDED.title;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS search_index_habits ON habits;
+CREATE TRIGGER search_index_habits AFTER INSERT OR UPDATE OR DELETE ON habits
+    FOR EACH ROW EXECUTE FUNCTION search_index_habits();
+
+-- OT entries
+CREATE OR REPLACE FUNCTION search_index_ot() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        DELETE FROM search_index WHERE kind = 'ot' AND ref_id = OLD.id;
+    END IF;
+    IF TG_OP <> 'DELETE' AND NEW.deleted_at IS NULL THEN
+        INSERT INTO search_index (kind, ref_id, day, title, body)
+        VALUES ('ot', NEW.id, NEW.day, NEW.title, NEW.note)
+        ON CONFLICT (kind, ref_id) DO UPDATE
+            SET day = EXCLUDED.day, title = EXCLUDED.title, body = EXCLUDED.body;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS search_index_ot ON ot_entries;
+CREATE TRIGGER search_index_ot AFTER INSERT OR UPDATE OR DELETE ON ot_entries
+    FOR EACH ROW EXECUTE FUNCTION search_index_ot();
+
+-- Slot feedback notes
+CREATE OR REPLACE FUNCTION search_index_slots() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        DELETE FROM search_index WHERE kind = 'note' AND ref_id = OLD.id::text;
+    END IF;
+    IF TG_OP <> 'DELETE' AND NEW.deleted_at IS NULL AND COALESCE(NEW.feedback_note, '') <> '' THEN
+        INSERT INTO search_index (kind, ref_id, day, title, body)
+        VALUES ('note', NEW.id::text, NEW.plan_date, '', NEW.feedback_note)
+        ON CONFLICT (kind, ref_id) DO UPDATE
+            SET day = EXCLUDED.day, body = EXCLUDED.body;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS search_index_slots ON slots;
+CREATE TRIGGER search_index_slots AFTER INSERT OR UPDATE OR DELETE ON slots
+    FOR EACH ROW EXECUTE FUNCTION search_index_slots();
+
+-- Backfill existing rows
+INSERT INTO search_index (kind, ref_id, day, title, body)
+SELECT 'task', id, '', COALESCE(name, ''), '' FROM tasks WHERE deleted_at IS NULL
+ON CONFLICT (kind, ref_id) DO NOTHING;
+
+INSERT INTO search_index (kind, ref_id, day, title, body)
+SELECT 'habit', id, '', name, '' FROM habits WHERE deleted_at IS NULL
+ON CONFLICT (kind, ref_id) DO NOTHING;
+
+INSERT INTO search_index (kind, ref_id, day, title, body)
+SELECT 'ot', id, day, title, note FROM ot_entries WHERE deleted_at IS NULL
+ON CONFLICT (kind, ref_id) DO NOTHING;
+
+INSERT INTO search_index (kind, ref_id, day, title, body)
+SELECT 'note', id::text, plan_date, '', feedback_note FROM slots
+WHERE deleted_at IS NULL AND COALESCE(feedback_note, '') <> ''
+ON CONFLICT (kind, ref_id) DO NOTHING;
@@ -0,0 +1,12 @@
+-- Migration 011: Add projects
+-- Projects group tasks under a larger goal with a weekly time target
+
+CREATE TABLE IF NOT EXISTS projects (
+    id                    TEXT PRIMARY KEY,        -- UUID
+    name                  TEXT NOT NULL UNIQUE,
+    target_hours_per_week DOUBLE PRECISION NOT NULL DEFAULT 0,
+    color                 TEXT NOT NULL DEFAULT '', -- #RRGGBB, empty for none
+    created_at            TEXT NOT NULL            -- ISO8601
+);
+
+ALTER TABLE tasks ADD COLUMN project_id TEXT NOT NULL DEFAULT '';
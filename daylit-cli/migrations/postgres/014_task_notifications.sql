@@ -0,0 +1,7 @@
+-- Migration 014: Add per-task notification preferences
+-- Tasks can skip the block start notification, use their own lead time
+-- instead of block_start_offset_min, or replace the notification text
+
+ALTER TABLE tasks ADD COLUMN notify_start_disabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE tasks ADD COLUMN notify_offset_min INTEGER NULL; -- NULL uses the global setting
+ALTER TABLE tasks ADD COLUMN notify_message TEXT NOT NULL DEFAULT '';
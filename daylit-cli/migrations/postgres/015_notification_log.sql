@@ -0,0 +1,19 @@
+-- Migration 015: Add notification log
+-- Every notification `daylit notify` emits is recorded with its delivery
+-- result so missing or duplicate notifications can be traced
+
+CREATE TABLE IF NOT EXISTS notification_log (
+    id         SERIAL PRIMARY KEY,
+    sent_at    TEXT NOT NULL,              -- ISO8601, UTC
+    kind       TEXT NOT NULL,              -- block_start, block_end, alert, morning_plan
+    plan_date  TEXT NOT NULL DEFAULT '',   -- YYYY-MM-DD
+    slot_start TEXT NOT NULL DEFAULT '',   -- HH:MM
+    task_id    TEXT NOT NULL DEFAULT '',
+    alert_id   TEXT NOT NULL DEFAULT '',
+    message    TEXT NOT NULL,
+    channel    TEXT NOT NULL,              -- tray, dry_run
+    success    BOOLEAN NOT NULL,
+    error      TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_log_sent_at ON notification_log(sent_at);
@@ -0,0 +1,27 @@
+-- Migration 016: Notify listeners of data changes
+-- Writes to user data send the table name on the daylit_changes channel so
+-- TUIs on other machines sharing this database can refresh their views.
+-- SQLite has no equivalent; its clients poll PRAGMA data_version instead.
+
+CREATE OR REPLACE FUNCTION daylit_notify_change() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('daylit_changes', TG_TABLE_NAME);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'settings', 'tasks', 'plans', 'slots', 'habits', 'habit_entries',
+        'ot_settings', 'ot_entries', 'alerts', 'projects', 'day_templates', 'day_template_slots'
+    ] LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS daylit_notify_change ON %I', t);
+        EXECUTE format(
+            'CREATE TRIGGER daylit_notify_change AFTER INSERT OR UPDATE OR DELETE ON %I '
+            'FOR EACH STATEMENT EXECUTE FUNCTION daylit_notify_change()', t);
+    END LOOP;
+END;
+$$;
@@ -0,0 +1,5 @@
+-- Migration 018: Record when a slot was actually finished
+-- actual_end is the HH:MM time 'daylit done' marked the slot done; NULL means
+-- the slot ran to its planned end_time
+
+ALTER TABLE slots ADD COLUMN actual_end TEXT NULL;
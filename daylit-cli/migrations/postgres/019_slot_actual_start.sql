@@ -0,0 +1,5 @@
+-- Migration 019: Record when a slot was actually started
+-- actual_start is the HH:MM time 'daylit start' started the slot; NULL means
+-- the slot was not tracked and is taken to have started at start_time
+
+ALTER TABLE slots ADD COLUMN actual_start TEXT NULL;
@@ -0,0 +1,13 @@
+-- Migration 020: Add vacations
+-- A vacation is an inclusive range of days away, during which recurring tasks
+-- aren't scheduled, habits aren't expected and notifications are muted
+
+CREATE TABLE IF NOT EXISTS vacations (
+    id         TEXT PRIMARY KEY,        -- UUID
+    start_date TEXT NOT NULL,           -- YYYY-MM-DD
+    end_date   TEXT NOT NULL,           -- YYYY-MM-DD, inclusive
+    note       TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL            -- ISO8601
+);
+
+CREATE INDEX IF NOT EXISTS idx_vacations_dates ON vacations(start_date, end_date);
@@ -0,0 +1,5 @@
+-- Migration 021: Add a free-text note to plans
+-- The note records context about the day, such as why it went sideways. New
+-- revisions of a day's plan start with the previous revision's note.
+
+ALTER TABLE plans ADD COLUMN note TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- Migration 023: Add monthly, yearly and cron schedules to alerts
+-- Monthly and yearly alerts use the day of month (and month) like tasks do;
+-- cron alerts take their times from recurrence_cron instead of time.
+
+ALTER TABLE alerts ADD COLUMN recurrence_month_day INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE alerts ADD COLUMN recurrence_month INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE alerts ADD COLUMN recurrence_cron TEXT NOT NULL DEFAULT '';
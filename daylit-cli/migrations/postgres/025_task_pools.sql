@@ -0,0 +1,12 @@
+-- Migration 025: Add task pools
+-- A pool groups interchangeable tasks of which the scheduler picks one per
+-- day, taking turns (round_robin) or the one done longest ago (least_recent).
+
+CREATE TABLE IF NOT EXISTS task_pools (
+    id         TEXT PRIMARY KEY,        -- UUID
+    name       TEXT NOT NULL UNIQUE,
+    strategy   TEXT NOT NULL DEFAULT 'round_robin',
+    created_at TEXT NOT NULL            -- ISO8601
+);
+
+ALTER TABLE tasks ADD COLUMN pool_id TEXT NOT NULL DEFAULT '';
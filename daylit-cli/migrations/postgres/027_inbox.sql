@@ -0,0 +1,16 @@
+-- Migration 027: Add the capture inbox
+-- Text captured with 'daylit capture' waits here until it is triaged into a
+-- task, a One Thing or an alert, or dismissed.
+
+CREATE TABLE IF NOT EXISTS inbox (
+    id         TEXT PRIMARY KEY,        -- UUID
+    text       TEXT NOT NULL,
+    created_at TEXT NOT NULL            -- ISO8601
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbox_created_at ON inbox(created_at);
+
+-- Let open TUIs see items captured elsewhere (see migration 016)
+DROP TRIGGER IF EXISTS daylit_notify_change ON inbox;
+CREATE TRIGGER daylit_notify_change AFTER INSERT OR UPDATE OR DELETE ON inbox
+    FOR EACH STATEMENT EXECUTE FUNCTION daylit_notify_change();
@@ -0,0 +1,20 @@
+-- Migration 029: Add plan amendments
+-- Targeted changes made to an accepted plan revision in place: a slot added,
+-- moved or removed. Each row is one change, kept as an audit trail of how the
+-- day diverged from the plan that was accepted.
+
+CREATE TABLE IF NOT EXISTS plan_amendments (
+    id            SERIAL PRIMARY KEY,
+    plan_date     TEXT NOT NULL,           -- YYYY-MM-DD
+    plan_revision INTEGER NOT NULL,
+    kind          TEXT NOT NULL,           -- add, move or remove
+    task_id       TEXT NOT NULL,
+    from_start    TEXT NOT NULL DEFAULT '', -- HH:MM, empty for add
+    from_end      TEXT NOT NULL DEFAULT '',
+    to_start      TEXT NOT NULL DEFAULT '', -- HH:MM, empty for remove
+    to_end        TEXT NOT NULL DEFAULT '',
+    reason        TEXT NOT NULL DEFAULT '',
+    created_at    TEXT NOT NULL            -- ISO8601
+);
+
+CREATE INDEX IF NOT EXISTS idx_plan_amendments_plan ON plan_amendments(plan_date, plan_revision);
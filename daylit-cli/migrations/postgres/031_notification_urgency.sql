@@ -0,0 +1,9 @@
+-- Migration 031: Add notification urgency and sound
+-- Alerts and tasks can mark their notifications low, normal or critical and
+-- pick a sound, so urgent reminders stand out from routine block nudges.
+-- Empty values use the notifier's defaults.
+
+ALTER TABLE alerts ADD COLUMN urgency TEXT NOT NULL DEFAULT '';
+ALTER TABLE alerts ADD COLUMN sound TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN notify_urgency TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN notify_sound TEXT NOT NULL DEFAULT '';
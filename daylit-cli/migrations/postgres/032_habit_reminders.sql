@@ -0,0 +1,10 @@
+-- Migration 032: Add habit reminders
+-- A habit can have a reminder time and the weekdays it applies on, so the
+-- notify daemon nags about it while it is unmarked. Reminder days are a JSON
+-- array of weekday numbers; an empty value means every day. last_reminded
+-- keeps a reminder from being sent twice on the same day.
+
+ALTER TABLE habits ADD COLUMN reminder_time TEXT NOT NULL DEFAULT '';
+ALTER TABLE habits ADD COLUMN reminder_days TEXT NOT NULL DEFAULT '';
+ALTER TABLE habits ADD COLUMN reminder_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE habits ADD COLUMN last_reminded TEXT;
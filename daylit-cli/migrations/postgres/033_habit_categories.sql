@@ -0,0 +1,6 @@
+-- Migration 033: Add habit categories
+-- Habits can be grouped under a category such as health, chores or learning.
+-- The habits tab groups them by category and reports show completion per
+-- category. Habits without a category have an empty value.
+
+ALTER TABLE habits ADD COLUMN category TEXT NOT NULL DEFAULT '';
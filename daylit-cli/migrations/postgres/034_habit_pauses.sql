@@ -0,0 +1,7 @@
+-- Migration 034: Add habit pauses
+-- A habit can be paused until a date. Paused days are neutral: they don't
+-- break streaks or count against completion, unlike archiving, which hides
+-- the habit. Dates are YYYY-MM-DD; empty when the habit was never paused.
+
+ALTER TABLE habits ADD COLUMN paused_from TEXT NOT NULL DEFAULT '';
+ALTER TABLE habits ADD COLUMN paused_until TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+-- Migration 038: Add shared tasks
+-- A shared task is a household task that several users of a shared database
+-- take turns at. Every member has a copy of the task with the same ID, and a
+-- row here in their schema listing the members in turn order, separated by
+-- commas; the default user is the empty name.
+
+CREATE TABLE IF NOT EXISTS shared_tasks (
+    task_id TEXT PRIMARY KEY,
+    members TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
@@ -0,0 +1,23 @@
+-- Migration 041: Add notification outbox
+-- Notifications whose delivery failed, e.g. because the tray wasn't running,
+-- wait here to be retried by later `daylit notify` runs with exponential
+-- backoff. Items given up on stay, marked failed, for `daylit notify history`.
+
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id              SERIAL PRIMARY KEY,
+    created_at      TEXT NOT NULL,                  -- ISO8601, UTC
+    kind            TEXT NOT NULL,
+    plan_date       TEXT NOT NULL DEFAULT '',       -- YYYY-MM-DD
+    slot_start      TEXT NOT NULL DEFAULT '',       -- HH:MM
+    task_id         TEXT NOT NULL DEFAULT '',
+    alert_id        TEXT NOT NULL DEFAULT '',
+    message         TEXT NOT NULL,
+    urgency         TEXT NOT NULL DEFAULT '',
+    sound           TEXT NOT NULL DEFAULT '',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TEXT NOT NULL,                  -- ISO8601, UTC
+    last_error      TEXT NOT NULL DEFAULT '',
+    status          TEXT NOT NULL DEFAULT 'pending' -- pending, failed
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_due ON notification_outbox(status, next_attempt_at);
@@ -0,0 +1,12 @@
+-- Migration 042: Add notification claims
+-- Every slot and alert notification has an idempotency key naming its
+-- trigger and the day or time it fired for. `daylit notify` claims the key
+-- before sending, and the primary key lets only one of several runs that see
+-- the notification due at once, such as cron and the daemon, claim it.
+
+CREATE TABLE IF NOT EXISTS notification_claims (
+    claim_key  TEXT PRIMARY KEY,
+    claimed_at TEXT NOT NULL  -- ISO8601, UTC
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_claims_claimed_at ON notification_claims(claimed_at);
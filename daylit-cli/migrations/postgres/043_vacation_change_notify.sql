@@ -0,0 +1,7 @@
+-- Migration 043: Notify listeners of vacation changes
+-- Let open TUIs see vacations added or removed elsewhere, so their calendars
+-- don't show stale ranges (see migration 016)
+
+DROP TRIGGER IF EXISTS daylit_notify_change ON vacations;
+CREATE TRIGGER daylit_notify_change AFTER INSERT OR UPDATE OR DELETE ON vacations
+    FOR EACH STATEMENT EXECUTE FUNCTION daylit_notify_change();
@@ -0,0 +1,104 @@
+-- Migration 010: Add full-text search index
+-- Indexes task names, habit names, OT titles/notes, and slot feedback notes
+-- in an FTS5 table kept in sync by triggers on the source tables.
+-- Insert triggers clear any existing row first because INSERT OR REPLACE does
+-- not fire delete triggers.
+
+CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+    kind UNINDEXED,
+    ref_id UNINDEXED,
+    day UNINDEXED,
+    title,
+    body,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+-- Tasks
+CREATE TRIGGER IF NOT EXISTS search_tasks_ai AFTER INSERT ON tasks BEGIN
+    DELETE FROM search_index WHERE kind = 'task' AND ref_id = NEW.id;
+    INSERT INTO search_index (kind, ref_id, day, title, body)
+    SELECT 'task', NEW.id, '', COALESCE(NEW.name, ''), ''
+    WHERE NEW.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_tasks_au AFTER UPDATE ON tasks BEGIN
+    DELETE FROM search_index WHERE kind = 'task' AND ref_id = OLD.id;
+    INSERT INTO search_index (kind, ref_id, day, title, body)
+    SELECT 'task', NEW.id, '', COALESCE(NEW.name, ''), ''
+    WHERE NEW.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_tasks_ad AFTER DELETE ON tasks BEGIN
+    DELETE FROM search_index WHERE kind = 'task' AND ref_id = OLD.id;
+END;
+
+-- Habits
+CREATE TRIGGER IF NOT EXISTS search_habits_ai AFTER INSERT ON habits BEGIN
+    DELETE FROM search_index WHERE kind = 'habit' AND ref_id = NEW.id;
+    INSERT INTO search_index (kind, ref_id, day, title, body)
+    SELECT 'habit', NEW.id, '', NEW.name, ''
+    WHERE NEW.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_habits_au AFTER UPDATE ON habits BEGIN
+    DELETE FROM search_index WHERE kind = 'habit' AND ref_id = OLD.id;
+    INSERT INTO search_index (kind, ref_id, day, title, body)
+    SELECT 'habit', NEW.id, '', NEW.name, ''
+    WHERE NEW.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_habits_ad AFTER DELETE ON habits BEGIN
+    DELETE FROM search_index WHERE kind = 'habit' AND ref_id = OLD.id;
+END;
+
+-- OT entries
+CREATE TRIGGER IF NOT EXISTS search_ot_ai AFTER INSERT ON ot_entries BEGIN
+    DELETE FROM search_index WHERE kind = 'ot' AND ref_id = NEW.id;
+    INSERT INTO search_index (kind, ref_id, day, title, body)
+    SELECT 'ot', NEW.id, NEW.day, NEW.title, NEW.note
+    WHERE NEW.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_ot_au AFTER UPDATE ON ot_entries BEGIN
+    DELETE FROM search_index WHERE kind = 'ot' AND ref_id = OLD.id;
+    INSERT INTO search_index (kind, ref_id, day, title, body)
+    SELECT 'ot', NEW.id, NEW.day, NEW.title, NEW.note
+    WHERE NEW.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_ot_ad AFTER DELETE ON ot_entries BEGIN
+    DELETE FROM search_index WHERE kind = 'ot' AND ref_id = OLD.id;
+END;
+
+-- Slot feedback notes
+CREATE TRIGGER IF NOT EXISTS search_slots_ai AFTER INSERT ON slots BEGIN
+    DELETE FROM search_index WHERE kind = 'note' AND ref_id = CAST(NEW.id AS TEXT);
+    INSERT INTO search_index (kind, ref_id, day, title, body)
+    SELECT 'note', CAST(NEW.id AS TEXT), NEW.plan_date, '', NEW.feedback_note
+    WHERE NEW.deleted_at IS NULL AND COALESCE(NEW.feedback_note, '') != '';
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_slots_au AFTER UPDATE ON slots BEGIN
+    DELETE FROM search_index WHERE kind = 'note' AND ref_id = CAST(OLD.id AS TEXT);
+    INSERT INTO search_index (kind, ref_id, day, title, body)
+    SELECT 'note', CAST(NEW.id AS TEXT), NEW.plan_date, '', NEW.feedback_note
+    WHERE NEW.deleted_at IS NULL AND COALESCE(NEW.feedback_note, '') != '';
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_slots_ad AFTER DELETE ON slots BEGIN
+    DELETE FROM search_index WHERE kind = 'note' AND ref_id = CAST(OLD.id AS TEXT);
+END;
+
+-- Backfill existing rows
+INSERT INTO search_index (kind, ref_id, day, title, body)
+SELECT 'task', id, '', COALESCE(name, ''), '' FROM tasks WHERE deleted_at IS NULL;
+
+INSERT INTO search_index (kind, ref_id, day, title, body)
+SELECT 'habit', id, '', name, '' FROM habits WHERE deleted_at IS NULL;
+
+INSERT INTO search_index (kind, ref_id, day, title, body)
+SELECT 'ot', id, day, title, note FROM ot_entries WHERE deleted_at IS NULL;
+
+INSERT INTO search_index (kind, ref_id, day, title, body)
+SELECT 'note', CAST(id AS TEXT), plan_date, '', feedback_note FROM slots
+WHERE deleted_at IS NULL AND COALESCE(feedback_note, '') != '';
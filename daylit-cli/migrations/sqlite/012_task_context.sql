@@ -0,0 +1,5 @@
+-- Migration 012: Add task contexts
+-- A task's context (e.g. "home", "office") limits plan generation to days
+-- when that context is active; empty means the task fits any context
+
+ALTER TABLE tasks ADD COLUMN context TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,19 @@
+-- Migration 013: Add day templates
+-- A day template is a reusable skeleton of slots ("deep work day") that
+-- plan generation keeps in place before filling the gaps
+
+CREATE TABLE IF NOT EXISTS day_templates (
+    id         TEXT PRIMARY KEY,        -- UUID
+    name       TEXT NOT NULL UNIQUE,
+    created_at TEXT NOT NULL            -- ISO8601
+);
+
+CREATE TABLE IF NOT EXISTS day_template_slots (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    template_id TEXT NOT NULL REFERENCES day_templates(id) ON DELETE CASCADE,
+    start_time  TEXT NOT NULL,          -- HH:MM
+    end_time    TEXT NOT NULL,          -- HH:MM
+    task_id     TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_day_template_slots_template ON day_template_slots(template_id);
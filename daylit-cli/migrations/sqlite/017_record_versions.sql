@@ -0,0 +1,7 @@
+-- Migration 017: Add record versions for conflict detection
+-- Every save bumps the version, and a save that starts from an older version
+-- is rejected instead of silently overwriting the newer data. Settings keep
+-- their version under the "version" key.
+
+ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE plans ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,17 @@
+-- Migration 022: Add slot reminders
+-- Extra notifications attached to one slot of a day's plan, sent offset_min
+-- minutes before the slot starts. The slot is found by its task and its start
+-- when the reminder was added, or the task's only slot that day if it moved.
+
+CREATE TABLE IF NOT EXISTS slot_reminders (
+    id         TEXT PRIMARY KEY,        -- UUID
+    plan_date  TEXT NOT NULL,           -- YYYY-MM-DD
+    slot_start TEXT NOT NULL,           -- HH:MM
+    task_id    TEXT NOT NULL,
+    offset_min INTEGER NOT NULL DEFAULT 0,
+    message    TEXT NOT NULL DEFAULT '',
+    sent_at    TEXT NULL,               -- ISO8601, NULL until sent
+    created_at TEXT NOT NULL            -- ISO8601
+);
+
+CREATE INDEX IF NOT EXISTS idx_slot_reminders_plan_date ON slot_reminders(plan_date);
@@ -0,0 +1,7 @@
+-- Migration 024: Add "leave by" alerts
+-- An alert bound to an appointment task goes off travel_min minutes before
+-- the appointment's fixed start, on the days the appointment takes place,
+-- instead of at a clock time.
+
+ALTER TABLE alerts ADD COLUMN task_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE alerts ADD COLUMN travel_min INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- Migration 026: Add nice-to-have tasks
+-- The scheduler places the other tasks first and fits nice-to-have tasks into
+-- the time left over; those that don't fit are listed as overflow.
+
+ALTER TABLE tasks ADD COLUMN nice_to_have INTEGER NOT NULL DEFAULT 0;
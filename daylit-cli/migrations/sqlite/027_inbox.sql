@@ -0,0 +1,11 @@
+-- Migration 027: Add the capture inbox
+-- Text captured with 'daylit capture' waits here until it is triaged into a
+-- task, a One Thing or an alert, or dismissed.
+
+CREATE TABLE IF NOT EXISTS inbox (
+    id         TEXT PRIMARY KEY,        -- UUID
+    text       TEXT NOT NULL,
+    created_at TEXT NOT NULL            -- ISO8601
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbox_created_at ON inbox(created_at);
@@ -0,0 +1,8 @@
+-- Migration 028: Index plan history lookups
+-- Slots are read by plan and by task far more often than they are written;
+-- without these every plan load, feedback history and day summary scanned the
+-- whole slots table, which grows by a plan a day.
+
+CREATE INDEX IF NOT EXISTS idx_slots_plan ON slots(plan_date, plan_revision, start_time);
+CREATE INDEX IF NOT EXISTS idx_slots_task ON slots(task_id);
+CREATE INDEX IF NOT EXISTS idx_habit_entries_day ON habit_entries(day);
@@ -0,0 +1,5 @@
+-- Migration 036: Add leisure tasks
+-- Leisure tasks are scheduled like any other task, but their blocks count as
+-- free time when plans are checked against the minimum free time setting.
+
+ALTER TABLE tasks ADD COLUMN leisure INTEGER NOT NULL DEFAULT 0;
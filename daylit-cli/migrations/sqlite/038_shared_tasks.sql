@@ -0,0 +1,10 @@
+-- Migration 038: Add shared tasks
+-- A shared task is a household task that several users of a shared PostgreSQL
+-- database take turns at. This backend holds a single user, so the table stays
+-- empty; it keeps the schema version in step with PostgreSQL.
+
+CREATE TABLE IF NOT EXISTS shared_tasks (
+    task_id TEXT PRIMARY KEY,
+    members TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
@@ -0,0 +1,92 @@
+-- Migration 040: Enforce foreign keys on slots and habit entries
+-- Slots must belong to a plan revision and a task, and a task can't be
+-- removed while slots refer to it; habit entries go with their habit. The
+-- store turns foreign keys on for every connection. SQLite can't change a
+-- table's constraints, so both tables are rebuilt.
+--
+-- The task key is checked at the end of each statement (NO ACTION) rather
+-- than at once (RESTRICT) because saving a task replaces its row.
+--
+-- Rows the keys would reject are removed first: slots of plans or tasks that
+-- no longer exist and entries of habits that no longer exist. 'daylit doctor'
+-- lists them before migrating.
+
+DELETE FROM slots
+WHERE NOT EXISTS (SELECT 1 FROM plans p WHERE p.date = slots.plan_date AND p.revision = slots.plan_revision)
+   OR (task_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM tasks t WHERE t.id = slots.task_id));
+
+DELETE FROM habit_entries
+WHERE NOT EXISTS (SELECT 1 FROM habits h WHERE h.id = habit_entries.habit_id);
+
+CREATE TABLE slots_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    plan_date TEXT NOT NULL,
+    plan_revision INTEGER NOT NULL DEFAULT 1,
+    start_time TEXT,
+    end_time TEXT,
+    task_id TEXT,
+    status TEXT,
+    feedback_rating TEXT,
+    feedback_note TEXT,
+    deleted_at TEXT NULL,
+    last_notified_start TEXT NULL,
+    last_notified_end TEXT NULL,
+    actual_end TEXT NULL,
+    actual_start TEXT NULL,
+    FOREIGN KEY(plan_date, plan_revision) REFERENCES plans(date, revision),
+    FOREIGN KEY(task_id) REFERENCES tasks(id) ON DELETE NO ACTION
+);
+
+INSERT INTO slots_new (id, plan_date, plan_revision, start_time, end_time, task_id, status, feedback_rating, feedback_note,
+                       deleted_at, last_notified_start, last_notified_end, actual_end, actual_start)
+SELECT id, plan_date, plan_revision, start_time, end_time, task_id, status, feedback_rating, feedback_note,
+       deleted_at, last_notified_start, last_notified_end, actual_end, actual_start
+FROM slots;
+
+-- Keep handing out IDs after the highest one ever used, as the old table did
+DELETE FROM sqlite_sequence WHERE name = 'slots_new';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'slots_new', seq FROM sqlite_sequence WHERE name = 'slots';
+
+DROP TABLE slots;
+ALTER TABLE slots_new RENAME TO slots;
+
+CREATE INDEX IF NOT EXISTS idx_slots_plan ON slots(plan_date, plan_revision, start_time);
+CREATE INDEX IF NOT EXISTS idx_slots_task ON slots(task_id);
+
+-- Dropping the old table dropped its search triggers
+CREATE TRIGGER IF NOT EXISTS search_slots_ai AFTER INSERT ON slots BEGIN
+    DELETE FROM search_index WHERE kind = 'note' AND ref_id = CAST(NEW.id AS TEXT);
+    INSERT INTO search_index (kind, ref_id, day, title, body)
+    SELECT 'note', CAST(NEW.id AS TEXT), NEW.plan_date, '', NEW.feedback_note
+    WHERE NEW.deleted_at IS NULL AND COALESCE(NEW.feedback_note, '') != '';
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_slots_au AFTER UPDATE ON slots BEGIN
+    DELETE FROM search_index WHERE kind = 'note' AND ref_id = CAST(OLD.id AS TEXT);
+    INSERT INTO search_index (kind, ref_id, day, title, body)
+    SELECT 'note', CAST(NEW.id AS TEXT), NEW.plan_date, '', NEW.feedback_note
+    WHERE NEW.deleted_at IS NULL AND COALESCE(NEW.feedback_note, '') != '';
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_slots_ad AFTER DELETE ON slots BEGIN
+    DELETE FROM search_index WHERE kind = 'note' AND ref_id = CAST(OLD.id AS TEXT);
+END;
+
+CREATE TABLE habit_entries_new (
+    id         TEXT PRIMARY KEY,
+    habit_id   TEXT NOT NULL REFERENCES habits(id) ON DELETE CASCADE,
+    day        TEXT NOT NULL,           -- YYYY-MM-DD (aligned to daylit's timezone)
+    note       TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    deleted_at TEXT NULL,
+    UNIQUE(habit_id, day)
+);
+
+INSERT INTO habit_entries_new (id, habit_id, day, note, created_at, updated_at, deleted_at)
+SELECT id, habit_id, day, note, created_at, updated_at, deleted_at FROM habit_entries;
+
+DROP TABLE habit_entries;
+ALTER TABLE habit_entries_new RENAME TO habit_entries;
+
+CREATE INDEX IF NOT EXISTS idx_habit_entries_day ON habit_entries(day);
@@ -0,0 +1,293 @@
+// Package daylit lets other Go programs, such as bots, dashboards and the
+// tray backend, plan and query a daylit database without running the CLI.
+//
+// A Client opens the same database the CLI uses, a SQLite file or a
+// PostgreSQL or MySQL connection string, and plans days with the same
+// scheduler, so a plan generated here is the plan `daylit plan` would have
+// made. The types are the ones daylit stores, under names that are part of
+// this package's API.
+package daylit
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julianstephens/daylit/daylit-cli/internal/autoplan"
+	"github.com/julianstephens/daylit/daylit-cli/internal/constants"
+	"github.com/julianstephens/daylit/daylit-cli/internal/models"
+	"github.com/julianstephens/daylit/daylit-cli/internal/scheduler"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/mysql"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/postgres"
+	"github.com/julianstephens/daylit/daylit-cli/internal/storage/sqlite"
+)
+
+// DateFormat is the layout of the dates daylit keys days by (YYYY-MM-DD)
+const DateFormat = constants.DateFormat
+
+type (
+	// Task is something to schedule: a flexible task placed wherever it
+	// fits, or an appointment at a fixed time
+	Task = models.Task
+	// Recurrence is how often a task comes round
+	Recurrence = models.Recurrence
+	// TaskKind is whether a task is flexible or an appointment
+	TaskKind = constants.TaskKind
+	// RecurrenceType is the kind of rule a Recurrence follows
+	RecurrenceType = constants.RecurrenceType
+	// EnergyBand is how much energy a task takes
+	EnergyBand = constants.EnergyBand
+	// Plan is the latest revision of a day's plan
+	Plan = models.DayPlan
+	// Slot is a block of a plan, from Start to End (HH:MM), for one task
+	Slot = models.Slot
+	// SlotStatus is how far along a slot is
+	SlotStatus = models.SlotStatus
+	// Feedback is the rating given to a slot once it's over
+	Feedback = models.Feedback
+	// Settings are the user's settings, such as the day's start and end
+	Settings = models.Settings
+	// Habit is a habit tracked day by day
+	Habit = models.Habit
+	// HabitEntry records a habit done on a day
+	HabitEntry = models.HabitEntry
+	// OTEntry is a day's Once-Today intention
+	OTEntry = models.OTEntry
+)
+
+const (
+	TaskKindFlexible    = constants.TaskKindFlexible
+	TaskKindAppointment = constants.TaskKindAppointment
+
+	RecurrenceDaily       = constants.RecurrenceDaily
+	RecurrenceWeekly      = constants.RecurrenceWeekly
+	RecurrenceWeekdays    = constants.RecurrenceWeekdays
+	RecurrenceNDays       = constants.RecurrenceNDays
+	RecurrenceMonthlyDate = constants.RecurrenceMonthlyDate
+	RecurrenceMonthlyDay  = constants.RecurrenceMonthlyDay
+	RecurrenceYearly      = constants.RecurrenceYearly
+	RecurrenceAdHoc       = constants.RecurrenceAdHoc
+
+	EnergyLow    = constants.EnergyLow
+	EnergyMedium = constants.EnergyMedium
+	EnergyHigh   = constants.EnergyHigh
+
+	SlotPlanned  SlotStatus = constants.SlotStatusPlanned
+	SlotAccepted SlotStatus = constants.SlotStatusAccepted
+	SlotDone     SlotStatus = constants.SlotStatusDone
+	SlotSkipped  SlotStatus = constants.SlotStatusSkipped
+)
+
+var (
+	// ErrNotFound is returned for a task, plan or OT entry that doesn't
+	// exist or was deleted
+	ErrNotFound = errors.New("daylit: not found")
+	// ErrConflict is returned when a record changed since it was read; read
+	// it again and reapply the change
+	ErrConflict = models.ErrConflict
+)
+
+// Options configure how Open opens a database
+type Options struct {
+	// User picks whose tasks and plans to use on a PostgreSQL database
+	// several people share; empty for the default user
+	User string
+	// Create sets up the database, with the default settings, when it
+	// doesn't exist yet, and migrates an older one to the current schema.
+	// Without it Open fails on a database the CLI hasn't set up.
+	Create bool
+}
+
+// Client plans and queries one daylit database. It is not safe for use by
+// several goroutines at once.
+type Client struct {
+	store storage.Provider
+	sched *scheduler.Scheduler
+	now   func() time.Time
+}
+
+// Open opens the daylit database at config: a SQLite file path, or a
+// PostgreSQL or MySQL connection string, as the CLI's --config takes
+func Open(config string, opts Options) (*Client, error) {
+	var store storage.Provider
+	switch {
+	case mysql.IsConnString(config):
+		store = mysql.New(config)
+	case strings.HasPrefix(config, "postgres://") || strings.HasPrefix(config, "postgresql://"):
+		pgStore, err := postgres.NewForUser(config, opts.User)
+		if err != nil {
+			return nil, err
+		}
+		store = pgStore
+	default:
+		store = sqlite.NewStore(config)
+	}
+
+	open := store.Load
+	if opts.Create {
+		open = store.Init
+	}
+	if err := open(); err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", store.GetConfigPath(), err)
+	}
+	return &Client{store: store, sched: scheduler.New(), now: time.Now}, nil
+}
+
+// Close closes the database
+func (c *Client) Close() error {
+	return c.store.Close()
+}
+
+// Settings returns the user's settings
+func (c *Client) Settings() (Settings, error) {
+	return c.store.GetSettings()
+}
+
+// Tasks returns the tasks that aren't deleted, active or not
+func (c *Client) Tasks() ([]Task, error) {
+	return c.store.GetAllTasks()
+}
+
+// Task returns the task with id
+func (c *Client) Task(id string) (Task, error) {
+	task, err := c.store.GetTask(id)
+	return task, notFound(err)
+}
+
+// AddTask validates and saves a new task, giving it an ID when it has none,
+// and returns it as saved
+func (c *Client) AddTask(task Task) (Task, error) {
+	if task.ID == "" {
+		task.ID = uuid.New().String()
+	}
+	if task.Kind == "" {
+		task.Kind = TaskKindFlexible
+	}
+	if err := task.Validate(); err != nil {
+		return Task{}, err
+	}
+	if err := c.store.AddTask(task); err != nil {
+		return Task{}, err
+	}
+	return c.Task(task.ID)
+}
+
+// UpdateTask saves changes to a task read with Task or Tasks. It returns
+// ErrConflict when the task changed since it was read.
+func (c *Client) UpdateTask(task Task) error {
+	if err := task.Validate(); err != nil {
+		return err
+	}
+	if _, err := c.Task(task.ID); err != nil {
+		return err
+	}
+	return c.store.UpdateTask(task)
+}
+
+// DeleteTask deletes the task with id; `daylit restore` can bring it back
+func (c *Client) DeleteTask(id string) error {
+	if _, err := c.Task(id); err != nil {
+		return err
+	}
+	return c.store.DeleteTask(id)
+}
+
+// Plan returns the latest revision of the plan for date (YYYY-MM-DD)
+func (c *Client) Plan(date string) (Plan, error) {
+	// The backends word a missing plan differently, while an empty range
+	// is the same everywhere
+	plans, err := c.store.GetPlansRange(date, date)
+	if err != nil {
+		return Plan{}, err
+	}
+	if len(plans) == 0 {
+		return Plan{}, ErrNotFound
+	}
+	return plans[0], nil
+}
+
+// Plans returns the plans of the days from start to end, both included, in
+// date order. Days without a plan are left out.
+func (c *Client) Plans(start, end string) ([]Plan, error) {
+	return c.store.GetPlansRange(start, end)
+}
+
+// GeneratePlan schedules the day's tasks for date as `daylit plan` does and
+// saves the plan as a new revision, keeping the locked part of the day in
+// place. With accept set the plan is accepted too, so it's what the day
+// follows and notifications fire for it.
+func (c *Client) GeneratePlan(date string, accept bool) (Plan, error) {
+	if _, err := time.Parse(DateFormat, date); err != nil {
+		return Plan{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+	}
+	settings, err := c.store.GetSettings()
+	if err != nil {
+		return Plan{}, err
+	}
+	return autoplan.Generate(c.store, c.sched, settings, date, accept, c.now())
+}
+
+// SlotAt returns the accepted or done slot in progress at t and the plan
+// holding it. After midnight the previous day's plan is still running when
+// the day ends late. ok is false when no slot covers t.
+func (c *Client) SlotAt(t time.Time) (plan Plan, slot Slot, ok bool, err error) {
+	settings, err := c.store.GetSettings()
+	if err != nil {
+		return Plan{}, Slot{}, false, err
+	}
+	window, err := models.ParseDayWindow(settings.DayStart, settings.DayEnd)
+	if err != nil {
+		return Plan{}, Slot{}, false, err
+	}
+	active := func(s Slot) bool { return s.Status == SlotAccepted || s.Status == SlotDone }
+
+	minutes := t.Hour()*60 + t.Minute()
+	days := []time.Time{t}
+	if window.CrossesMidnight() && minutes < window.Start {
+		days = append(days, t.AddDate(0, 0, -1))
+	}
+	for i, day := range days {
+		plan, err := c.store.GetPlan(day.Format(DateFormat))
+		if err != nil {
+			continue
+		}
+		if j := window.SlotAt(plan.Slots, minutes+i*models.MinutesPerDay, active); j >= 0 {
+			return plan, plan.Slots[j], true, nil
+		}
+	}
+	return Plan{}, Slot{}, false, nil
+}
+
+// Now returns the slot in progress now, as SlotAt does
+func (c *Client) Now() (plan Plan, slot Slot, ok bool, err error) {
+	return c.SlotAt(c.now())
+}
+
+// Habits returns the habits that aren't archived or deleted
+func (c *Client) Habits() ([]Habit, error) {
+	return c.store.GetAllHabits(false, false)
+}
+
+// HabitEntries returns the habits recorded as done on day
+func (c *Client) HabitEntries(day string) ([]HabitEntry, error) {
+	return c.store.GetHabitEntriesForDay(day)
+}
+
+// OTEntry returns the Once-Today intention of day
+func (c *Client) OTEntry(day string) (OTEntry, error) {
+	entry, err := c.store.GetOTEntry(day)
+	return entry, notFound(err)
+}
+
+// notFound turns the storage layer's missing row into ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
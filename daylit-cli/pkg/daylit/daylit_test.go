@@ -0,0 +1,111 @@
+package daylit_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/daylit/daylit-cli/pkg/daylit"
+)
+
+func openTestClient(t *testing.T) *daylit.Client {
+	t.Helper()
+	client, err := daylit.Open(filepath.Join(t.TempDir(), "daylit.db"), daylit.Options{Create: true})
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestOpen_WithoutCreate(t *testing.T) {
+	if _, err := daylit.Open(filepath.Join(t.TempDir(), "missing.db"), daylit.Options{}); err == nil {
+		t.Error("opening a database that was never set up succeeded")
+	}
+}
+
+func TestClient_Tasks(t *testing.T) {
+	client := openTestClient(t)
+
+	if _, err := client.AddTask(daylit.Task{Name: "No duration", Priority: 3}); err == nil {
+		t.Error("adding an invalid task succeeded")
+	}
+	task, err := client.AddTask(daylit.Task{
+		Name:        "Write",
+		DurationMin: 60,
+		Recurrence:  daylit.Recurrence{Type: daylit.RecurrenceDaily},
+		Priority:    2,
+		Active:      true,
+	})
+	if err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	if task.ID == "" || task.Kind != daylit.TaskKindFlexible {
+		t.Errorf("added task = %+v, want an ID and the flexible kind", task)
+	}
+
+	task.DurationMin = 45
+	if err := client.UpdateTask(task); err != nil {
+		t.Fatalf("failed to update task: %v", err)
+	}
+	if err := client.UpdateTask(task); !errors.Is(err, daylit.ErrConflict) {
+		t.Errorf("updating a stale task: got %v, want ErrConflict", err)
+	}
+	if tasks, err := client.Tasks(); err != nil || len(tasks) != 1 || tasks[0].DurationMin != 45 {
+		t.Errorf("tasks = %+v, %v, want the updated task", tasks, err)
+	}
+
+	if err := client.DeleteTask(task.ID); err != nil {
+		t.Fatalf("failed to delete task: %v", err)
+	}
+	if _, err := client.Task(task.ID); !errors.Is(err, daylit.ErrNotFound) {
+		t.Errorf("getting a deleted task: got %v, want ErrNotFound", err)
+	}
+	if err := client.DeleteTask("missing"); !errors.Is(err, daylit.ErrNotFound) {
+		t.Errorf("deleting a missing task: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestClient_GeneratePlan(t *testing.T) {
+	client := openTestClient(t)
+	if _, err := client.Plan("2025-06-02"); !errors.Is(err, daylit.ErrNotFound) {
+		t.Errorf("getting a missing plan: got %v, want ErrNotFound", err)
+	}
+	if _, err := client.GeneratePlan("June 2nd", false); err == nil {
+		t.Error("generating a plan for a malformed date succeeded")
+	}
+
+	task, err := client.AddTask(daylit.Task{
+		Name:        "Standup",
+		Kind:        daylit.TaskKindAppointment,
+		DurationMin: 15,
+		FixedStart:  "10:00",
+		FixedEnd:    "10:15",
+		Recurrence:  daylit.Recurrence{Type: daylit.RecurrenceDaily},
+		Priority:    1,
+		Active:      true,
+	})
+	if err != nil {
+		t.Fatalf("failed to add task: %v", err)
+	}
+	plan, err := client.GeneratePlan("2025-06-02", true)
+	if err != nil {
+		t.Fatalf("failed to generate plan: %v", err)
+	}
+	if plan.AcceptedAt == nil || len(plan.Slots) != 1 || plan.Slots[0].Status != daylit.SlotAccepted {
+		t.Fatalf("plan = %+v, want the accepted standup", plan)
+	}
+	if saved, err := client.Plan("2025-06-02"); err != nil || saved.Revision != plan.Revision {
+		t.Errorf("saved plan = %+v, %v, want revision %d", saved, err, plan.Revision)
+	}
+
+	at := time.Date(2025, 6, 2, 10, 5, 0, 0, time.Local)
+	_, slot, ok, err := client.SlotAt(at)
+	if err != nil || !ok || slot.TaskID != task.ID {
+		t.Errorf("slot at 10:05 = %+v, %v, %v, want the standup", slot, ok, err)
+	}
+	if _, _, ok, err := client.SlotAt(at.Add(time.Hour)); err != nil || ok {
+		t.Errorf("slot at 11:05 = %v, %v, want none", ok, err)
+	}
+}
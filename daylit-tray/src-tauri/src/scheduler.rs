@@ -1,4 +1,4 @@
-use crate::state::{AppState, Settings};
+use crate::state::{AppState, Settings, WebhookPayload};
 use std::time::Duration;
 use std::{process::Command, thread};
 use tauri::AppHandle;
@@ -60,10 +60,82 @@ fn get_scheduler_interval() -> u64 {
         .unwrap_or(60000)
 }
 
+// Where `daylit notify serve` listens for the tray, unless DAYLIT_SOCKET says
+// otherwise
+#[cfg(unix)]
+fn daemon_socket_path() -> Option<std::path::PathBuf> {
+    if let Ok(path) = std::env::var("DAYLIT_SOCKET") {
+        if !path.is_empty() {
+            return Some(path.into());
+        }
+    }
+    std::env::var_os("HOME")
+        .map(|home| std::path::Path::new(&home).join(".config/daylit/daylit.sock"))
+}
+
+// Reads a line from the daemon's socket, returning the notification to show
+// if it is a notification event
+#[cfg_attr(not(unix), allow(dead_code))]
+fn parse_daemon_event(line: &str) -> Option<WebhookPayload> {
+    let msg: serde_json::Value = serde_json::from_str(line).ok()?;
+    if msg.get("method")?.as_str()? != "event" {
+        return None;
+    }
+    let params = msg.get("params")?;
+    if params.get("type")?.as_str()? != "notification" {
+        return None;
+    }
+    serde_json::from_value(params.get("data")?.clone()).ok()
+}
+
+// Subscribes to a running `daylit notify serve` and shows its notifications
+// until it goes away. The daemon runs the notification checks itself, so
+// nothing is spawned meanwhile. Returns false when no daemon is listening.
+#[cfg(unix)]
+fn follow_daemon(app_handle: &AppHandle) -> bool {
+    use std::io::{BufRead, BufReader, Write};
+    use std::os::unix::net::UnixStream;
+
+    let Some(path) = daemon_socket_path() else {
+        return false;
+    };
+    let Ok(mut stream) = UnixStream::connect(&path) else {
+        return false;
+    };
+    if let Err(e) = stream.write_all(b"{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"subscribe\"}\n") {
+        error!("Failed to subscribe to the daylit daemon: {}", e);
+        return false;
+    }
+    info!("Subscribed to the daylit daemon at {}", path.display());
+
+    for line in BufReader::new(stream).lines() {
+        match line {
+            Ok(line) => {
+                if let Some(payload) = parse_daemon_event(&line) {
+                    crate::server::show_notification(app_handle, payload);
+                }
+            }
+            Err(e) => {
+                error!("Lost the daylit daemon connection: {}", e);
+                break;
+            }
+        }
+    }
+    info!("daylit daemon went away; running daylit notify again");
+    true
+}
+
 pub fn start_scheduler_thread(app_handle: AppHandle) {
     thread::spawn(move || {
         let runner = RealCommandRunner;
         loop {
+            // While a daemon is running, it sends the notifications
+            #[cfg(unix)]
+            if follow_daemon(&app_handle) {
+                thread::sleep(Duration::from_secs(1));
+                continue;
+            }
+
             // Determine sleep interval from env var or default to 60 seconds
             let interval_ms = get_scheduler_interval();
 
@@ -173,6 +245,28 @@ mod tests {
         assert!(*runner.called.borrow());
     }
 
+    #[test]
+    fn test_parse_daemon_event_notification() {
+        let line = r#"{"jsonrpc":"2.0","method":"event","params":{"type":"notification","data":{"text":"Write starts now","duration_ms":5000,"urgency":"critical"}}}"#;
+        let payload = parse_daemon_event(line).expect("expected a notification");
+
+        assert_eq!(payload.text, "Write starts now");
+        assert_eq!(payload.duration_ms, 5000);
+        assert_eq!(payload.urgency, "critical");
+        assert_eq!(payload.sound, "");
+    }
+
+    #[test]
+    fn test_parse_daemon_event_ignores_other_messages() {
+        // The reply to subscribe, other events and garbage show nothing
+        assert!(parse_daemon_event(r#"{"jsonrpc":"2.0","id":1,"result":true}"#).is_none());
+        assert!(
+            parse_daemon_event(r#"{"jsonrpc":"2.0","method":"event","params":{"type":"other"}}"#)
+                .is_none()
+        );
+        assert!(parse_daemon_event("not json").is_none());
+    }
+
     #[test]
     #[serial]
     fn test_get_scheduler_interval_default() {
@@ -151,88 +151,7 @@ pub fn start_webhook_server(app_handle: AppHandle) {
             }
 
             if let Ok(payload) = serde_json::from_str::<WebhookPayload>(&content) {
-                let state: State<AppState> = app_handle.state();
-                *state
-                    .payload
-                    .lock()
-                    .expect("Failed to acquire payload lock") = Some(payload.clone());
-
-                // Check if we should use native notifications
-                let settings = Settings::load(&state.settings);
-                
-                if settings.use_native_notifications {
-                    // Use native system notifications
-                    // Note: The duration_ms field from the payload is not used here as
-                    // native notification duration is controlled by the operating system.
-                    // Custom notifications (else branch) do respect the duration_ms setting.
-                    info!("Using native notification");
-                    if let Err(e) = app_handle
-                        .notification()
-                        .builder()
-                        .title("Daylit")
-                        .body(&payload.text)
-                        .show()
-                    {
-                        error!("Failed to show native notification: {}", e);
-                    }
-                } else {
-                    // Use custom window notification (existing behavior)
-                    info!("Received webhook payload. Scheduling on main thread.");
-                    let app_handle_clone = app_handle.clone();
-                    if let Err(e) = app_handle.run_on_main_thread(move || {
-                        info!("Running on main thread.");
-                        // --- Re-use or Create Window Logic ---
-                        if let Some(existing_window) =
-                            app_handle_clone.get_webview_window("notification_dialog")
-                        {
-                            info!("Dialog exists. Re-using and sending new data.");
-                            if let Err(e) = existing_window.set_focus() {
-                                error!("Failed to set window focus: {}", e);
-                            }
-                            if let Err(e) = existing_window.emit(
-                                "update_notification",
-                                &UpdatePayload {
-                                    text: payload.text,
-                                    duration_ms: payload.duration_ms,
-                                },
-                            ) {
-                                error!("Failed to emit update notification: {}", e);
-                            }
-                        } else {
-                            info!("Dialog does not exist. Creating a new one.");
-                            if let Some(main_window) = app_handle_clone.get_webview_window("main") {
-                                if let Ok(Some(monitor)) = main_window.primary_monitor() {
-                                    let monitor_size = monitor.size();
-                                    let dialog_width = 1000.0;
-                                    let dialog_height = 100.0;
-                                    let pos_x = (monitor_size.width as f64 - dialog_width) / 2.0;
-                                    let pos_y = 60.0;
-
-                                    if let Err(e) = tauri::WebviewWindowBuilder::new(
-                                        &app_handle_clone,
-                                        "notification_dialog",
-                                        tauri::WebviewUrl::App("/notification".into()),
-                                    )
-                                    .inner_size(dialog_width, dialog_height)
-                                    .position(pos_x, pos_y)
-                                    .always_on_top(true)
-                                    .decorations(false)
-                                    .transparent(true)
-                                    .build()
-                                    {
-                                        error!("Failed to build notification dialog: {}", e);
-                                    }
-                                } else {
-                                    error!("Failed to get primary monitor");
-                                }
-                            } else {
-                                error!("Main window not found");
-                            }
-                        }
-                    }) {
-                        error!("Failed to run on main thread: {}", e);
-                    }
-                }
+                show_notification(&app_handle, payload);
 
                 let response = Response::from_string("Notification triggered");
                 if let Err(e) = request.respond(response) {
@@ -249,6 +168,101 @@ pub fn start_webhook_server(app_handle: AppHandle) {
     });
 }
 
+/// Shows a notification from the CLI, as a native notification or in the
+/// notification dialog, and keeps its payload for the dialog to read
+pub fn show_notification(app_handle: &AppHandle, payload: WebhookPayload) {
+    let state: State<AppState> = app_handle.state();
+    *state
+        .payload
+        .lock()
+        .expect("Failed to acquire payload lock") = Some(payload.clone());
+
+    // Check if we should use native notifications
+    let settings = Settings::load(&state.settings);
+
+    if settings.use_native_notifications {
+        // Use native system notifications
+        // Note: The duration_ms field from the payload is not used here as
+        // native notification duration is controlled by the operating system.
+        // Custom notifications (else branch) do respect the duration_ms setting.
+        info!("Using native notification");
+        let title = if payload.urgency == "critical" {
+            "Daylit: Urgent"
+        } else {
+            "Daylit"
+        };
+        let mut builder = app_handle
+            .notification()
+            .builder()
+            .title(title)
+            .body(&payload.text);
+        if !payload.sound.is_empty() {
+            builder = builder.sound(&payload.sound);
+        }
+        if let Err(e) = builder.show() {
+            error!("Failed to show native notification: {}", e);
+        }
+    } else {
+        // Use custom window notification (existing behavior)
+        info!("Received notification. Scheduling on main thread.");
+        let app_handle_clone = app_handle.clone();
+        if let Err(e) = app_handle.run_on_main_thread(move || {
+            info!("Running on main thread.");
+            // --- Re-use or Create Window Logic ---
+            if let Some(existing_window) =
+                app_handle_clone.get_webview_window("notification_dialog")
+            {
+                info!("Dialog exists. Re-using and sending new data.");
+                if let Err(e) = existing_window.set_focus() {
+                    error!("Failed to set window focus: {}", e);
+                }
+                if let Err(e) = existing_window.emit(
+                    "update_notification",
+                    &UpdatePayload {
+                        text: payload.text,
+                        duration_ms: payload.duration_ms,
+                        urgency: payload.urgency,
+                    },
+                ) {
+                    error!("Failed to emit update notification: {}", e);
+                }
+            } else {
+                info!("Dialog does not exist. Creating a new one.");
+                if let Some(main_window) = app_handle_clone.get_webview_window("main") {
+                    if let Ok(Some(monitor)) = main_window.primary_monitor() {
+                        let monitor_size = monitor.size();
+                        let dialog_width = 1000.0;
+                        let dialog_height = 100.0;
+                        let pos_x = (monitor_size.width as f64 - dialog_width) / 2.0;
+                        let pos_y = 60.0;
+
+                        if let Err(e) = tauri::WebviewWindowBuilder::new(
+                            &app_handle_clone,
+                            "notification_dialog",
+                            tauri::WebviewUrl::App("/notification".into()),
+                        )
+                        .inner_size(dialog_width, dialog_height)
+                        .position(pos_x, pos_y)
+                        .always_on_top(true)
+                        .decorations(false)
+                        .transparent(true)
+                        .build()
+                        {
+                            error!("Failed to build notification dialog: {}", e);
+                        }
+                    } else {
+                        error!("Failed to get primary monitor");
+                    }
+                } else {
+                    error!("Main window not found");
+                }
+            }
+        }) {
+            error!("Failed to run on main thread: {}", e);
+        }
+    }
+}
+
 #[cfg(test)]
 mod tests {
     use super::*;
@@ -332,6 +346,8 @@ mod tests {
         let payload = WebhookPayload {
             text: "Test notification".to_string(),
             duration_ms: 5000,
+            urgency: "critical".to_string(),
+            sound: "Glass".to_string(),
         };
 
         let json = serde_json::to_string(&payload).unwrap();
@@ -339,6 +355,18 @@ mod tests {
 
         assert_eq!(deserialized.text, "Test notification");
         assert_eq!(deserialized.duration_ms, 5000);
+        assert_eq!(deserialized.urgency, "critical");
+        assert_eq!(deserialized.sound, "Glass");
+    }
+
+    #[test]
+    fn test_webhook_payload_without_urgency() {
+        // Older CLIs send only the text and duration
+        let json = r#"{"text": "Starting now", "duration_ms": 5000}"#;
+        let payload: WebhookPayload = serde_json::from_str(json).unwrap();
+
+        assert_eq!(payload.urgency, "");
+        assert_eq!(payload.sound, "");
     }
 
     // Note: Integration tests for the actual notification delivery would require
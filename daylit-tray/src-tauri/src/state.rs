@@ -40,6 +40,12 @@ impl Settings {
 pub struct WebhookPayload {
     pub text: String,
     pub duration_ms: u32,
+    // low, normal or critical; empty is normal
+    #[serde(default)]
+    pub urgency: String,
+    // Sound for native notifications; empty uses the system default
+    #[serde(default)]
+    pub sound: String,
 }
 
 // Event payload for when we re-use an existing window
@@ -47,6 +53,7 @@ pub struct WebhookPayload {
 pub struct UpdatePayload {
     pub text: String,
     pub duration_ms: u32,
+    pub urgency: String,
 }
 
 // Main application state, holds settings store and last payload
@@ -6,6 +6,7 @@ import "./NotificationPage.css";
 interface WebhookPayload {
   text: string;
   duration_ms: number;
+  urgency?: string;
 }
 
 function NotificationPage() {
@@ -23,9 +24,15 @@ function NotificationPage() {
 
     setNotification(payload);
 
+    // Critical notifications stay until clicked; low ones close sooner
+    if (payload.urgency === "critical") {
+      timerRef.current = null;
+      return;
+    }
+    const duration = payload.duration_ms || 7000;
     timerRef.current = setTimeout(
       handleClose,
-      payload.duration_ms || 7000,
+      payload.urgency === "low" ? duration / 2 : duration,
     ) as unknown as number;
   };
 
@@ -87,7 +94,10 @@ function NotificationPage() {
   }
 
   return (
-    <div className="notification-bar" onClick={handleClose}>
+    <div
+      className={`notification-bar ${notification.urgency ? `urgency-${notification.urgency}` : ""}`}
+      onClick={handleClose}
+    >
       <p className="notification-text">{notification.text}</p>
     </div>
   );